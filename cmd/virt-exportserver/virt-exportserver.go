@@ -0,0 +1,78 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"kubevirt.io/client-go/log"
+	exportserver "kubevirt.io/kubevirt/pkg/virt-exportserver"
+)
+
+// virt-exportserver serves the volumes of a VirtualMachineExport. It is
+// started by virt-controller in a pod which mounts the exported volumes.
+func main() {
+	listen := flag.String("listen", ":8443", "Address to serve the exported volumes on")
+	certFile := flag.String("cert-file", "", "TLS certificate of the server")
+	keyFile := flag.String("key-file", "", "TLS key of the server")
+	baseURL := flag.String("base-url", "", "URL the server is reachable at, the import URLs in the manifest are resolved against it")
+	tokenFile := flag.String("token-file", "", "File holding the token clients have to present")
+	manifestFile := flag.String("manifest-file", "", "File holding the VirtualMachine manifest which imports the volumes")
+	scratchDir := flag.String("scratch-dir", "/scratch", "Directory the qcow2 conversions are stored in")
+	volumeArgs := flag.StringArray("volume", nil, "An exported volume in the form name=path, can be repeated")
+	flag.Parse()
+
+	log.InitializeLogging("virt-exportserver")
+
+	if *baseURL == "" {
+		fmt.Fprintln(os.Stderr, "--base-url is required")
+		os.Exit(1)
+	}
+
+	volumes := map[string]string{}
+	for _, volume := range *volumeArgs {
+		parts := strings.SplitN(volume, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			fmt.Fprintf(os.Stderr, "invalid volume %q, expected name=path\n", volume)
+			os.Exit(1)
+		}
+		volumes[parts[0]] = parts[1]
+	}
+
+	token, err := ioutil.ReadFile(*tokenFile)
+	if err != nil {
+		log.Log.Reason(err).Critical("failed to read the token")
+	}
+	manifest, err := ioutil.ReadFile(*manifestFile)
+	if err != nil {
+		log.Log.Reason(err).Critical("failed to read the manifest")
+	}
+
+	server := exportserver.NewServer(strings.TrimSpace(string(token)), *baseURL, volumes, manifest, *scratchDir)
+	log.Log.Infof("serving %d volumes on %s", len(volumes), *listen)
+	err = http.ListenAndServeTLS(*listen, *certFile, *keyFile, server)
+	log.Log.Reason(err).Critical("export server stopped")
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/emicklei/go-restful"
 	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
 	flag "github.com/spf13/pflag"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -56,13 +57,18 @@ import (
 	_ "kubevirt.io/kubevirt/pkg/monitoring/workqueue/prometheus" // import for prometheus metrics
 	"kubevirt.io/kubevirt/pkg/service"
 	"kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/util/cgroup"
+	"kubevirt.io/kubevirt/pkg/util/utilization"
 	"kubevirt.io/kubevirt/pkg/util/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 	virthandler "kubevirt.io/kubevirt/pkg/virt-handler"
 	virtcache "kubevirt.io/kubevirt/pkg/virt-handler/cache"
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
 	"kubevirt.io/kubevirt/pkg/virt-handler/isolation"
+	"kubevirt.io/kubevirt/pkg/virt-handler/ksm"
+	nodelabeller "kubevirt.io/kubevirt/pkg/virt-handler/node-labeller"
 	"kubevirt.io/kubevirt/pkg/virt-handler/rest"
+	"kubevirt.io/kubevirt/pkg/virt-handler/seccomp"
 	"kubevirt.io/kubevirt/pkg/virt-handler/selinux"
 	virt_api "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 	"kubevirt.io/kubevirt/pkg/watchdog"
@@ -100,6 +106,7 @@ type virtHandlerApp struct {
 	VirtPrivateDir            string
 	VirtLibDir                string
 	KubeletPodsDir            string
+	KubeletSeccompDir         string
 	WatchdogTimeoutDuration   time.Duration
 	MaxDevices                int
 	MaxRequestsInFlight       int
@@ -189,6 +196,18 @@ func (app *virtHandlerApp) Run() {
 		cache.Indexers{},
 	)
 
+	// The launcher pods of the node, the metrics map the VMIs to them
+	launcherPodLabel, err := labels.Parse(fmt.Sprintf("%s=virt-launcher", v1.AppLabel))
+	if err != nil {
+		panic(err)
+	}
+	launcherPodSharedInformer := cache.NewSharedIndexInformer(
+		controller.NewListWatchFromClient(app.virtCli.CoreV1().RESTClient(), "pods", k8sv1.NamespaceAll, fields.OneTermEqualSelector("spec.nodeName", app.HostOverride), launcherPodLabel),
+		&k8sv1.Pod{},
+		0,
+		cache.Indexers{},
+	)
+
 	// Wire Domain controller
 	domainSharedInformer, err := virtcache.NewSharedInformer(app.VirtShareDir, int(app.WatchdogTimeoutDuration.Seconds()), recorder, vmSourceSharedInformer.GetStore(), time.Duration(app.domainResyncPeriodSeconds)*time.Second)
 	if err != nil {
@@ -267,6 +286,7 @@ func (app *virtHandlerApp) Run() {
 		vmTargetSharedInformer,
 		domainSharedInformer,
 		gracefulShutdownInformer,
+		factory.PersistentVolumeClaim(),
 		int(app.WatchdogTimeoutDuration.Seconds()),
 		app.MaxDevices,
 		app.clusterConfig,
@@ -275,6 +295,18 @@ func (app *virtHandlerApp) Run() {
 		podIsolationDetector,
 	)
 
+	backupController := virthandler.NewBackupController(
+		recorder,
+		app.virtCli,
+		app.HostOverride,
+		factory.VirtualMachineBackup(),
+		vmSourceSharedInformer,
+		app.clusterConfig,
+	)
+
+	nodeLabeller := nodelabeller.NewNodeLabeller(app.clusterConfig, app.virtCli, app.HostOverride)
+	ksmHandler := ksm.NewKSMHandler(app.clusterConfig, app.HostOverride)
+
 	consoleHandler := rest.NewConsoleHandler(
 		podIsolationDetector,
 		vmiInformer,
@@ -285,7 +317,17 @@ func (app *virtHandlerApp) Run() {
 		app.VirtShareDir,
 	)
 
-	promvm.SetupCollector(app.virtCli, app.VirtShareDir, app.HostOverride, app.MaxRequestsInFlight)
+	// the metrics handler collects the VMI collector for every scrape, not through the default registry,
+	// the registry it is registered on serves the consumers within virt-handler
+	collectorRegistry := prometheus.NewRegistry()
+	collector, err := promvm.SetupCollector(collectorRegistry, app.virtCli, app.VirtShareDir, app.HostOverride, launcherPodSharedInformer.GetStore(), app.clusterConfig.GetMetricsNamingMode)
+	if err != nil {
+		glog.Fatalf("Error setting up the metrics collector: %v", err)
+	}
+	if err := promvm.SetupDomainStateCollector(prometheus.DefaultRegisterer, app.HostOverride, domainSharedInformer); err != nil {
+		glog.Fatalf("Error setting up the domain state metrics collector: %v", err)
+	}
+	utilizationReporter := utilization.NewNodeReporter(app.clusterConfig, app.virtCli, app.HostOverride, collectorRegistry)
 
 	go app.clientcertmanager.Start()
 	go app.servercertmanager.Start()
@@ -308,27 +350,48 @@ func (app *virtHandlerApp) Run() {
 		panic(fmt.Errorf("failed to detect the presence of selinux: %v", err))
 	}
 
+	// The node is only marked as schedulable by the heartbeat of the vm controller, so the
+	// virt-launcher pods never land on a node without the profile they may run with
+	err = seccomp.InstallProfile(filepath.Join(util.HostRootMount, app.KubeletSeccompDir))
+	if err != nil {
+		panic(fmt.Errorf("failed to install the virt-launcher seccomp profile: %v", err))
+	}
+
+	// The cgroup version is detected again for every launcher pod, this only reports it
+	if version, err := cgroup.DetectVersion(cgroup.Root); err != nil {
+		log.DefaultLogger().Reason(err).Warning("Failed to detect the cgroup version of the node")
+	} else {
+		log.DefaultLogger().Infof("The node uses cgroup v%d", version)
+	}
+
+	go launcherPodSharedInformer.Run(stop)
+
 	cache.WaitForCacheSync(stop, factory.ConfigMap().HasSynced, vmiInformer.HasSynced, factory.CRD().HasSynced)
 
 	go vmController.Run(10, stop)
+	go backupController.Run(3, stop)
+	go nodeLabeller.Run(3*time.Minute, stop)
+	go ksmHandler.Run(time.Minute, stop)
+	go utilizationReporter.Run(time.Minute, stop)
 
 	errCh := make(chan error)
 	promErrCh := make(chan error)
-	go app.runPrometheusServer(promErrCh)
+	go app.runPrometheusServer(promErrCh, collector)
 	go app.runServer(errCh, consoleHandler, lifecycleHandler)
 
 	// wait for one of the servers to exit
 	fmt.Println(<-errCh)
 }
 
-func (app *virtHandlerApp) runPrometheusServer(errCh chan error) {
+func (app *virtHandlerApp) runPrometheusServer(errCh chan error, collector *promvm.Collector) {
 	mux := restful.NewContainer()
 	webService := new(restful.WebService)
 	webService.Path("/").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
 	webService.Route(webService.GET("/healthz").To(healthz.KubeConnectionHealthzFuncFactory(app.clusterConfig)).Doc("Health endpoint"))
 	mux.Add(webService)
 	log.Log.V(1).Infof("metrics: max concurrent requests=%d", app.MaxRequestsInFlight)
-	mux.Handle("/metrics", promvm.Handler(app.MaxRequestsInFlight))
+	mux.Handle("/metrics", promvm.Handler(app.MaxRequestsInFlight, collector))
+	mux.Handle("/metrics/deprecated", collector.DeprecatedMetricsHandler())
 	server := http.Server{
 		Addr:      app.ServiceListen.Address(),
 		Handler:   mux,
@@ -341,11 +404,17 @@ func (app *virtHandlerApp) runServer(errCh chan error, consoleHandler *rest.Cons
 	ws := new(restful.WebService)
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/console").To(consoleHandler.SerialHandler))
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/vnc").To(consoleHandler.VNCHandler))
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/portforward/{port}").To(consoleHandler.PortForwardHandler))
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/usbredir").To(consoleHandler.USBRedirHandler))
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/backup").To(consoleHandler.BackupHandler))
 	ws.Route(ws.PUT("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/pause").To(lifecycleHandler.PauseHandler))
 	ws.Route(ws.PUT("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/unpause").To(lifecycleHandler.UnpauseHandler))
+	ws.Route(ws.PUT("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/freeze").To(lifecycleHandler.FreezeHandler))
+	ws.Route(ws.PUT("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/thaw").To(lifecycleHandler.ThawHandler))
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/guestosinfo").To(lifecycleHandler.GetGuestInfo).Produces(restful.MIME_JSON).Consumes(restful.MIME_JSON).Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceGuestAgentInfo{}))
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/userlist").To(lifecycleHandler.GetUsers).Produces(restful.MIME_JSON).Consumes(restful.MIME_JSON).Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceGuestOSUserList{}))
 	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/filesystemlist").To(lifecycleHandler.GetFilesystems).Produces(restful.MIME_JSON).Consumes(restful.MIME_JSON).Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceFileSystemList{}))
+	ws.Route(ws.GET("/v1/namespaces/{namespace}/virtualmachineinstances/{name}/stats").To(lifecycleHandler.GetStats).Produces(restful.MIME_JSON).Consumes(restful.MIME_JSON).Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceStats{}))
 	restful.DefaultContainer.Add(ws)
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", app.ServiceListen.BindAddress, app.consoleServerPort),
@@ -382,6 +451,9 @@ func (app *virtHandlerApp) AddFlags() {
 	flag.StringVar(&app.KubeletPodsDir, "kubelet-pods-dir", util.KubeletPodsDir,
 		"Path for pod directory (matching host's path for kubelet root)")
 
+	flag.StringVar(&app.KubeletSeccompDir, "kubelet-seccomp-dir", util.KubeletSeccompDir,
+		"Path of the seccomp directory of the kubelet on the host")
+
 	flag.DurationVar(&app.WatchdogTimeoutDuration, "watchdog-timeout", defaultWatchdogTimeout,
 		"Watchdog file timeout")
 
@@ -107,8 +107,8 @@ func startCmdServer(socketPath string,
 	return done
 }
 
-func createLibvirtConnection() virtcli.Connection {
-	libvirtUri := "qemu:///system"
+func createLibvirtConnection(nonRoot bool) virtcli.Connection {
+	libvirtUri := util.LibvirtURI(nonRoot)
 	domainConn, err := virtcli.NewConnection(libvirtUri, "", "", 10*time.Second)
 	if err != nil {
 		panic(fmt.Sprintf("failed to connect to libvirtd: %v", err))
@@ -199,6 +199,11 @@ func initializeDirs(virtShareDir string,
 	if err != nil {
 		panic(err)
 	}
+
+	err = virtlauncher.InitializeDisksDirectories(config.SysprepDisksDir)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func waitForDomainUUID(timeout time.Duration, events chan watch.Event, stop chan struct{}, domainManager virtwrap.DomainManager) *api.Domain {
@@ -310,6 +315,10 @@ func main() {
 	qemuAgentFileInterval := pflag.Duration("qemu-agent-file-interval", 300, "Interval in seconds between consecutive qemu agent calls for file command")
 	qemuAgentUserInterval := pflag.Duration("qemu-agent-user-interval", 10, "Interval in seconds between consecutive qemu agent calls for user command")
 	qemuAgentVersionInterval := pflag.Duration("qemu-agent-version-interval", 300, "Interval in seconds between consecutive qemu agent calls for version command")
+	serialConsoleLogMaxSize := pflag.Int64("serial-console-log-max-size", 0, "Size in bytes the serial console log may grow to before it is rotated, keeps the virtlogd defaults if 0")
+	serialConsoleLogMaxFiles := pflag.Int("serial-console-log-max-files", 3, "Number of rotated serial console logs to keep")
+	serialConsoleLogStdout := pflag.Bool("serial-console-log-stdout", false, "Write the serial console log to the virt-launcher logs")
+	runAsNonRoot := pflag.Bool("run-as-nonroot", false, "Run the libvirt session daemon as the unprivileged user of virt-launcher")
 	// set new default verbosity, was set to 0 by glog
 	goflag.Set("v", "2")
 
@@ -342,16 +351,25 @@ func main() {
 	// Start libvirtd, virtlogd, and establish libvirt connection
 	stopChan := make(chan struct{})
 
-	err = util.SetupLibvirt()
+	err = util.SetupLibvirt(*runAsNonRoot)
 	if err != nil {
 		panic(err)
 	}
+	if *serialConsoleLogMaxSize > 0 {
+		err = util.ConfigureVirtlogRotation(*serialConsoleLogMaxSize, *serialConsoleLogMaxFiles, *runAsNonRoot)
+		if err != nil {
+			panic(err)
+		}
+	}
 	util.StartLibvirt(stopChan)
 	// only single domain should be present
 	domainName := api.VMINamespaceKeyFunc(vm)
-	util.StartVirtlog(stopChan, domainName)
+	util.StartVirtlog(stopChan, domainName, *runAsNonRoot)
+	if *serialConsoleLogStdout {
+		util.FollowSerialConsoleLog(stopChan, log.Log, api.GetSerialConsoleLogPath(types.UID(*uid), ""))
+	}
 
-	domainConn := createLibvirtConnection()
+	domainConn := createLibvirtConnection(*runAsNonRoot)
 	defer domainConn.Close()
 
 	var agentStore = agentpoller.NewAsyncAgentStore()
@@ -0,0 +1,69 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/util"
+	virtprobe "kubevirt.io/kubevirt/pkg/virt-probe"
+)
+
+const pollInterval = 100 * time.Millisecond
+
+// virt-probe runs a probe command inside the guest through the guest agent.
+// It is executed by kubelet in the compute container and exits with the exit
+// code of the guest command.
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	namespace := flag.String("namespace", "", "Namespace of the VirtualMachineInstance")
+	name := flag.String("name", "", "Name of the VirtualMachineInstance")
+	timeoutSeconds := flag.Int("timeoutSeconds", 1, "Number of seconds after which the probe times out")
+	runAsNonRoot := flag.Bool("run-as-nonroot", false, "Connect to the libvirt session daemon of a non-root virt-launcher")
+	flag.Parse()
+
+	if *namespace == "" || *name == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: virt-probe --namespace <namespace> --name <name> [--timeoutSeconds <seconds>] [--run-as-nonroot] -- <command> [args...]")
+		return 1
+	}
+	timeout := time.Duration(*timeoutSeconds) * time.Second
+
+	conn, err := cli.NewConnection(util.LibvirtURI(*runAsNonRoot), "", "", timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to libvirt: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	exitCode, err := virtprobe.GuestExec(conn, util.DomainFromNamespaceName(*namespace, *name), flag.Args(), timeout, pollInterval)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return exitCode
+}
@@ -0,0 +1,69 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	extender "kubevirt.io/kubevirt/pkg/virt-scheduler-extender"
+)
+
+// virt-scheduler-extender is an optional extender of kube-scheduler, which scores
+// nodes for VMIs by the utilization virt-handler reports on them. It ships in the
+// virt-controller image.
+func main() {
+	flag.CommandLine.AddGoFlagSet(kubecli.FlagSet())
+	listen := flag.String("listen", ":8888", "Address to serve the scheduler extender on")
+	certFile := flag.String("cert-file", "", "TLS certificate of the server, serves plain HTTP if not set")
+	keyFile := flag.String("key-file", "", "TLS key of the server")
+	staleAfter := flag.Duration("stale-after", 5*time.Minute, "How old the utilization of a node may be before it is ignored")
+	flag.Parse()
+
+	log.InitializeLogging("virt-scheduler-extender")
+
+	virtCli, err := kubecli.GetKubevirtClient()
+	if err != nil {
+		log.Log.Reason(err).Critical("failed to create the client")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	lw := cache.NewListWatchFromClient(virtCli.CoreV1().RESTClient(), "nodes", k8sv1.NamespaceAll, fields.Everything())
+	nodeStore, nodeController := cache.NewInformer(lw, &k8sv1.Node{}, 0, cache.ResourceEventHandlerFuncs{})
+	go nodeController.Run(stop)
+	cache.WaitForCacheSync(stop, nodeController.HasSynced)
+
+	server := extender.NewExtender(nodeStore, *staleAfter)
+	log.Log.Infof("serving the scheduler extender on %s", *listen)
+	if *certFile != "" {
+		err = http.ListenAndServeTLS(*listen, *certFile, *keyFile, server)
+	} else {
+		err = http.ListenAndServe(*listen, server)
+	}
+	log.Log.Reason(err).Critical("scheduler extender stopped")
+}
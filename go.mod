@@ -51,11 +51,13 @@ require (
 	github.com/operator-framework/operator-marketplace v0.0.0-20190508022032-93d436f211c1
 	github.com/pborman/uuid v1.2.0
 	github.com/prometheus/client_golang v1.1.0
+	github.com/prometheus/procfs v0.0.3
 	github.com/smartystreets/goconvey v0.0.0-20190731233626-505e41936337 // indirect
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.5
 	github.com/subgraph/libmacouflage v0.0.1
 	github.com/vishvananda/netlink v0.0.0-20181108222139-023a6dafdcdf
+	github.com/vishvananda/netns v0.0.0-20180720170159-13995c7128cc
 	golang.org/x/crypto v0.0.0-20190927123631-a832865fa7ad
 	golang.org/x/net v0.0.0-20191209160850-c0dbc17a3553
 	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae
@@ -66,6 +68,7 @@ require (
 	k8s.io/api v0.17.0
 	k8s.io/apiextensions-apiserver v0.16.4
 	k8s.io/apimachinery v0.17.1-beta.0
+	k8s.io/apiserver v0.16.4
 	k8s.io/client-go v12.0.0+incompatible
 	k8s.io/kube-aggregator v0.16.4
 	k8s.io/kube-openapi v0.0.0-20191107075043-30be4d16710a
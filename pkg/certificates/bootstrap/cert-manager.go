@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/util/certificate"
 
 	"kubevirt.io/kubevirt/pkg/certificates/triple"
@@ -23,6 +24,20 @@ const (
 	KeyBytesValue  = "tls.key"
 )
 
+var (
+	loadedCertificateExpirationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_loaded_certificate_expiration_timestamp_seconds",
+			Help: "Expiration time of the certificate currently served from a certificate directory, in seconds since the epoch",
+		},
+		[]string{"directory"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(loadedCertificateExpirationGauge)
+}
+
 type FileCertificateManager struct {
 	stopCh             chan struct{}
 	certAccessLock     sync.Mutex
@@ -177,7 +192,10 @@ func (f *FileCertificateManager) rotateCerts() error {
 	f.certAccessLock.Lock()
 	defer f.certAccessLock.Unlock()
 	// update after the callback, to ensure that the reconfiguration succeeded
+	// TLS configs look the certificate up on every handshake, so established
+	// connections are not affected by the rotation
 	f.cert = crt
+	loadedCertificateExpirationGauge.WithLabelValues(f.certDir).Set(float64(crt.Leaf.NotAfter.Unix()))
 
 	log.DefaultLogger().Infof("certificate from %s with common name '%s' retrieved.", f.certDir, crt.Leaf.Subject.CommonName)
 	return nil
@@ -9,6 +9,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	io_prometheus_client "github.com/prometheus/client_model/go"
 	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
@@ -58,6 +59,26 @@ var _ = Describe("cert-manager", func() {
 		}, 3*time.Second).Should(Not(BeNil()))
 	})
 
+	It("should load rotated certificates and export their expiration time", func() {
+		certManager := NewFileCertificateManager(certDir)
+		writeCertsToDir(certDir)
+		go certManager.Start()
+		defer certManager.Stop()
+		Eventually(func() *tls.Certificate {
+			return certManager.Current()
+		}, time.Second).Should(Not(BeNil()))
+		oldCrt := certManager.Current()
+
+		writeCertsToDir(certDir)
+		Eventually(func() bool {
+			return certManager.Current().Leaf.SerialNumber.Cmp(oldCrt.Leaf.SerialNumber) != 0
+		}, 3*time.Second).Should(BeTrue())
+
+		dto := &io_prometheus_client.Metric{}
+		Expect(loadedCertificateExpirationGauge.WithLabelValues(certDir).Write(dto)).To(Succeed())
+		Expect(dto.GetGauge().GetValue()).To(Equal(float64(certManager.Current().Leaf.NotAfter.Unix())))
+	})
+
 	It("should keep the latest certificate if it can't load new certs", func() {
 		certManager := NewFileCertificateManager(certDir)
 		writeCertsToDir(certDir)
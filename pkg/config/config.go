@@ -42,6 +42,9 @@ const (
 	// ServiceAccount represents a secret type,
 	// https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/
 	ServiceAccount Type = "serviceaccount"
+	// Sysprep represents a Sysprep answer file type,
+	// https://docs.microsoft.com/en-us/windows-hardware/manufacture/desktop/windows-setup-automation-overview
+	Sysprep Type = "sysprep"
 
 	mountBaseDir = "/var/run/kubevirt-private"
 )
@@ -51,6 +54,8 @@ var (
 	ConfigMapSourceDir = mountBaseDir + "/config-map"
 	// SecretSourceDir represents a location where Secrets is attached to the pod
 	SecretSourceDir = mountBaseDir + "/secret"
+	// SysprepSourceDir represents a location where the Sysprep ConfigMap or Secret is attached to the pod
+	SysprepSourceDir = mountBaseDir + "/sysprep"
 	// ServiceAccountSourceDir represents the location where the ServiceAccount token is attached to the pod
 	ServiceAccountSourceDir = "/var/run/secrets/kubernetes.io/serviceaccount/"
 
@@ -58,6 +63,8 @@ var (
 	ConfigMapDisksDir = mountBaseDir + "/config-map-disks"
 	// SecretDisksDir represents a path to Secrets iso images
 	SecretDisksDir = mountBaseDir + "/secret-disks"
+	// SysprepDisksDir represents a path to Sysprep iso images
+	SysprepDisksDir = mountBaseDir + "/sysprep-disks"
 	// ServiceAccountDisksDir represents a path to the ServiceAccount iso image
 	ServiceAccountDiskDir = mountBaseDir + "/service-account-disk"
 	// ServiceAccountDisksName represents the name of the ServiceAccount iso image
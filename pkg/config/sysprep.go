@@ -0,0 +1,85 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	ephemeraldiskutils "kubevirt.io/kubevirt/pkg/ephemeral-disk-utils"
+)
+
+const sysprepVolumeLabel = "unattendCD"
+
+// sysprepAnswerFiles are the names of the answer files Windows Setup looks for on removable media
+var sysprepAnswerFiles = []string{"autounattend.xml", "unattend.xml"}
+
+// GetSysprepSourcePath returns a path to the Sysprep ConfigMap or Secret mounted on a pod
+func GetSysprepSourcePath(volumeName string) string {
+	return filepath.Join(SysprepSourceDir, volumeName)
+}
+
+// GetSysprepDiskPath returns a path to Sysprep iso image created based on a volume name
+func GetSysprepDiskPath(volumeName string) string {
+	return filepath.Join(SysprepDisksDir, volumeName+".iso")
+}
+
+// CreateSysprepDisks creates Sysprep iso disks which are attached to vmis
+func CreateSysprepDisks(vmi *v1.VirtualMachineInstance) error {
+	for _, volume := range vmi.Spec.Volumes {
+		if volume.Sysprep != nil {
+			answerFile, err := findSysprepAnswerFile(GetSysprepSourcePath(volume.Name))
+			if err != nil {
+				return err
+			}
+
+			disk := GetSysprepDiskPath(volume.Name)
+			if err := createIsoConfigImage(disk, sysprepVolumeLabel, []string{answerFile}); err != nil {
+				return err
+			}
+
+			if err := ephemeraldiskutils.DefaultOwnershipManager.SetFileOwnership(disk); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// findSysprepAnswerFile returns the graft point of the answer file in the given directory.
+// Windows ignores the case of file names, so any spelling of the answer file names is accepted.
+func findSysprepAnswerFile(dirPath string) (string, error) {
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return "", err
+	}
+	for _, answerFile := range sysprepAnswerFiles {
+		for _, file := range files {
+			if strings.EqualFold(file.Name(), answerFile) {
+				return file.Name() + "=" + filepath.Join(dirPath, file.Name()), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Sysprep answer file found in %s, expected one of %s", dirPath, strings.Join(sysprepAnswerFiles, ", "))
+}
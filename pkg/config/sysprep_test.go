@@ -0,0 +1,102 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Sysprep", func() {
+	var isoFiles []string
+	var isoVolID string
+
+	newSysprepVMI := func() *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("fake-vmi")
+		vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+			Name: "sysprep-volume",
+			VolumeSource: v1.VolumeSource{
+				Sysprep: &v1.SysprepSource{
+					ConfigMap: &k8sv1.LocalObjectReference{Name: "test-sysprep"},
+				},
+			},
+		})
+		return vmi
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		SysprepSourceDir, err = ioutil.TempDir("", "sysprep")
+		Expect(err).NotTo(HaveOccurred())
+		os.MkdirAll(filepath.Join(SysprepSourceDir, "sysprep-volume"), 0755)
+
+		SysprepDisksDir, err = ioutil.TempDir("", "sysprep-disks")
+		Expect(err).NotTo(HaveOccurred())
+
+		isoFiles = nil
+		setIsoCreationFunction(func(output string, volID string, files []string) error {
+			isoFiles = files
+			isoVolID = volID
+			return mockCreateISOImage(output, volID, files)
+		})
+	})
+
+	AfterEach(func() {
+		setIsoCreationFunction(mockCreateISOImage)
+		os.RemoveAll(SysprepSourceDir)
+		os.RemoveAll(SysprepDisksDir)
+	})
+
+	It("Should create a new sysprep iso disk with only the answer file", func() {
+		answerFile := filepath.Join(SysprepSourceDir, "sysprep-volume", "Autounattend.xml")
+		os.OpenFile(answerFile, os.O_RDONLY|os.O_CREATE, 0666)
+		os.OpenFile(filepath.Join(SysprepSourceDir, "sysprep-volume", "other-file"), os.O_RDONLY|os.O_CREATE, 0666)
+
+		err := CreateSysprepDisks(newSysprepVMI())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = os.Stat(filepath.Join(SysprepDisksDir, "sysprep-volume.iso"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isoFiles).To(Equal([]string{"Autounattend.xml=" + answerFile}))
+		Expect(isoVolID).To(Equal("unattendCD"))
+	})
+
+	It("Should accept an unattend.xml answer file", func() {
+		answerFile := filepath.Join(SysprepSourceDir, "sysprep-volume", "unattend.xml")
+		os.OpenFile(answerFile, os.O_RDONLY|os.O_CREATE, 0666)
+
+		Expect(CreateSysprepDisks(newSysprepVMI())).To(Succeed())
+		Expect(isoFiles).To(Equal([]string{"unattend.xml=" + answerFile}))
+	})
+
+	It("Should fail without an answer file", func() {
+		os.OpenFile(filepath.Join(SysprepSourceDir, "sysprep-volume", "other-file"), os.O_RDONLY|os.O_CREATE, 0666)
+
+		err := CreateSysprepDisks(newSysprepVMI())
+		Expect(err).To(MatchError(ContainSubstring("no Sysprep answer file found")))
+	})
+})
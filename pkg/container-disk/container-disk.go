@@ -28,6 +28,7 @@ import (
 
 	kubev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
 
 	ephemeraldisk "kubevirt.io/kubevirt/pkg/ephemeral-disk"
 
@@ -42,6 +43,23 @@ var podsBaseDir = util.KubeletPodsDir
 
 var mountBaseDir = filepath.Join(util.VirtShareDir, "/container-disks")
 
+// firmwareName is the name of the container and of the mount target of the EFI roms
+const firmwareName = "firmware"
+
+// memoryDumpName is the name of the mount target of the claim a memory dump is written to
+const memoryDumpName = "memory-dump"
+
+const (
+	// MemoryDumpClaimDir is where the memory dump attach pod mounts the claim
+	MemoryDumpClaimDir = "/memory-dump"
+	// MemoryDumpSocketVolumeName is the emptyDir holding the socket of the memory dump attach pod
+	MemoryDumpSocketVolumeName = "memory-dump-socket"
+	// MemoryDumpSocketDir is where the memory dump attach pod mounts the socket volume
+	MemoryDumpSocketDir = "/var/run/kubevirt-memory-dump"
+	// MemoryDumpCopyPath is passed to the container-disk binary of the attach pod, which listens on it with a .sock suffix
+	MemoryDumpCopyPath = MemoryDumpSocketDir + "/attach"
+)
+
 func GetLegacyVolumeMountDirOnHost(vmi *v1.VirtualMachineInstance) string {
 	return filepath.Join(mountBaseDir, string(vmi.UID))
 }
@@ -78,6 +96,63 @@ func GetDiskTargetPathFromLauncherView(volumeIndex int) string {
 	return filepath.Join(mountBaseDir, fmt.Sprintf("disk_%d.img", volumeIndex))
 }
 
+// GetFirmwareContainerDisk returns the container disk which ships the EFI roms of the vmi, if any
+func GetFirmwareContainerDisk(vmi *v1.VirtualMachineInstance) *v1.ContainerDiskSource {
+	firmware := vmi.Spec.Domain.Firmware
+	if firmware == nil || firmware.Bootloader == nil || firmware.Bootloader.EFI == nil {
+		return nil
+	}
+	return firmware.Bootloader.EFI.ContainerDisk
+}
+
+func GetFirmwareTargetPathFromHostView(vmi *v1.VirtualMachineInstance) (string, error) {
+	basepath, found, err := GetVolumeMountDirOnHost(vmi)
+	if err != nil {
+		return "", err
+	} else if !found {
+		return "", fmt.Errorf("container disk volume for vmi not found")
+	}
+
+	return filepath.Join(basepath, firmwareName), nil
+}
+
+func GetFirmwareTargetPathFromLauncherView() string {
+	return filepath.Join(mountBaseDir, firmwareName)
+}
+
+// GetMemoryDumpTargetPathFromHostView returns where virt-handler mounts the claim of a memory dump
+func GetMemoryDumpTargetPathFromHostView(vmi *v1.VirtualMachineInstance) (string, error) {
+	basepath, found, err := GetVolumeMountDirOnHost(vmi)
+	if err != nil {
+		return "", err
+	} else if !found {
+		return "", fmt.Errorf("container disk volume for vmi not found")
+	}
+
+	return filepath.Join(basepath, memoryDumpName), nil
+}
+
+// GetMemoryDumpTargetPathFromLauncherView returns where the claim of a memory dump shows up in virt-launcher
+func GetMemoryDumpTargetPathFromLauncherView() string {
+	return filepath.Join(mountBaseDir, memoryDumpName)
+}
+
+// GetMemoryDumpSocketPathFromHostView returns the socket of the memory dump attach pod with the given UID
+func GetMemoryDumpSocketPathFromHostView(podUID types.UID) string {
+	return filepath.Join(podsBaseDir, string(podUID), "volumes/kubernetes.io~empty-dir", MemoryDumpSocketVolumeName, filepath.Base(MemoryDumpCopyPath)+".sock")
+}
+
+func GetFirmwareSocketPathFromHostView(vmi *v1.VirtualMachineInstance) (string, error) {
+	for podUID, _ := range vmi.Status.ActivePods {
+		basepath := fmt.Sprintf("/pods/%s/volumes/kubernetes.io~empty-dir/container-disks", string(podUID))
+		exists, _ := diskutils.FileExists(basepath)
+		if exists {
+			return filepath.Join(basepath, firmwareName+".sock"), nil
+		}
+	}
+	return "", fmt.Errorf("container disk socket path not found for vmi")
+}
+
 func SetLocalDirectory(dir string) error {
 	mountBaseDir = dir
 	return os.MkdirAll(dir, 0755)
@@ -147,81 +222,109 @@ func GetImage(root string, imagePath string) (string, error) {
 	return imagePath, nil
 }
 
+// GetFirmwareDir returns the directory containing the EFI roms below root
+func GetFirmwareDir(root string, dirPath string) (string, error) {
+	if dirPath == "" {
+		dirPath = DiskSourceFallbackPath
+	}
+	dirPath = filepath.Join(root, dirPath)
+	info, err := os.Stat(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("No firmware directory on path %s", dirPath)
+		}
+		return "", fmt.Errorf("Failed to check if a firmware directory exists at %s", dirPath)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("Firmware path %s is not a directory", dirPath)
+	}
+	return dirPath, nil
+}
+
 // The controller uses this function to generate the container
 // specs for hosting the container registry disks.
 func GenerateContainers(vmi *v1.VirtualMachineInstance, podVolumeName string, binVolumeName string) []kubev1.Container {
 	var containers []kubev1.Container
 
+	volumeMountDir := GetVolumeMountDirOnGuest(vmi)
+
+	// Make VirtualMachineInstance Image Wrapper Containers
+	for index, volume := range vmi.Spec.Volumes {
+		if volume.ContainerDisk != nil {
+			diskContainerName := fmt.Sprintf("volume%s", volume.Name)
+			copyPath := volumeMountDir + "/disk_" + strconv.Itoa(index)
+			containers = append(containers, generateContainer(vmi, diskContainerName, volume.ContainerDisk, copyPath, podVolumeName, binVolumeName))
+		}
+	}
+
+	// The EFI roms are served the same way as disks
+	if firmware := GetFirmwareContainerDisk(vmi); firmware != nil {
+		copyPath := filepath.Join(volumeMountDir, firmwareName)
+		containers = append(containers, generateContainer(vmi, firmwareName, firmware, copyPath, podVolumeName, binVolumeName))
+	}
+	return containers
+}
+
+func generateContainer(vmi *v1.VirtualMachineInstance, name string, source *v1.ContainerDiskSource, copyPath string, podVolumeName string, binVolumeName string) kubev1.Container {
 	initialDelaySeconds := 1
 	timeoutSeconds := 1
 	periodSeconds := 1
 	successThreshold := 1
 	failureThreshold := 5
 
-	// Make VirtualMachineInstance Image Wrapper Containers
-	for index, volume := range vmi.Spec.Volumes {
-		if volume.ContainerDisk != nil {
-
-			volumeMountDir := GetVolumeMountDirOnGuest(vmi)
-			diskContainerName := fmt.Sprintf("volume%s", volume.Name)
-			diskContainerImage := volume.ContainerDisk.Image
-			resources := kubev1.ResourceRequirements{}
-			if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
-				resources.Limits = make(kubev1.ResourceList)
-				resources.Limits[kubev1.ResourceCPU] = resource.MustParse("10m")
-				resources.Limits[kubev1.ResourceMemory] = resource.MustParse("40M")
-				resources.Requests = make(kubev1.ResourceList)
-				resources.Requests[kubev1.ResourceCPU] = resource.MustParse("10m")
-				resources.Requests[kubev1.ResourceMemory] = resource.MustParse("40M")
-			} else {
-				resources.Limits = make(kubev1.ResourceList)
-				resources.Limits[kubev1.ResourceCPU] = resource.MustParse("100m")
-				resources.Limits[kubev1.ResourceMemory] = resource.MustParse("40M")
-				resources.Requests = make(kubev1.ResourceList)
-				resources.Requests[kubev1.ResourceCPU] = resource.MustParse("10m")
-				resources.Requests[kubev1.ResourceMemory] = resource.MustParse("1M")
-			}
-			container := kubev1.Container{
-				Name:            diskContainerName,
-				Image:           diskContainerImage,
-				ImagePullPolicy: volume.ContainerDisk.ImagePullPolicy,
-				Command:         []string{"/usr/bin/container-disk"},
-				Args:            []string{"--copy-path", volumeMountDir + "/disk_" + strconv.Itoa(index)},
-				VolumeMounts: []kubev1.VolumeMount{
-					{
-						Name:      podVolumeName,
-						MountPath: volumeMountDir,
-					},
-					{
-						Name:      binVolumeName,
-						MountPath: "/usr/bin",
-					},
-				},
-				Resources: resources,
-
-				// The readiness probes ensure the volume coversion and copy finished
-				// before the container is marked as "Ready: True"
-				ReadinessProbe: &kubev1.Probe{
-					Handler: kubev1.Handler{
-						Exec: &kubev1.ExecAction{
-							Command: []string{
-								"/usr/bin/container-disk",
-								"--health-check",
-							},
-						},
+	volumeMountDir := GetVolumeMountDirOnGuest(vmi)
+	resources := kubev1.ResourceRequirements{}
+	if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
+		resources.Limits = make(kubev1.ResourceList)
+		resources.Limits[kubev1.ResourceCPU] = resource.MustParse("10m")
+		resources.Limits[kubev1.ResourceMemory] = resource.MustParse("40M")
+		resources.Requests = make(kubev1.ResourceList)
+		resources.Requests[kubev1.ResourceCPU] = resource.MustParse("10m")
+		resources.Requests[kubev1.ResourceMemory] = resource.MustParse("40M")
+	} else {
+		resources.Limits = make(kubev1.ResourceList)
+		resources.Limits[kubev1.ResourceCPU] = resource.MustParse("100m")
+		resources.Limits[kubev1.ResourceMemory] = resource.MustParse("40M")
+		resources.Requests = make(kubev1.ResourceList)
+		resources.Requests[kubev1.ResourceCPU] = resource.MustParse("10m")
+		resources.Requests[kubev1.ResourceMemory] = resource.MustParse("1M")
+	}
+	return kubev1.Container{
+		Name:            name,
+		Image:           source.Image,
+		ImagePullPolicy: source.ImagePullPolicy,
+		Command:         []string{"/usr/bin/container-disk"},
+		Args:            []string{"--copy-path", copyPath},
+		VolumeMounts: []kubev1.VolumeMount{
+			{
+				Name:      podVolumeName,
+				MountPath: volumeMountDir,
+			},
+			{
+				Name:      binVolumeName,
+				MountPath: "/usr/bin",
+			},
+		},
+		Resources: resources,
+
+		// The readiness probes ensure the volume coversion and copy finished
+		// before the container is marked as "Ready: True"
+		ReadinessProbe: &kubev1.Probe{
+			Handler: kubev1.Handler{
+				Exec: &kubev1.ExecAction{
+					Command: []string{
+						"/usr/bin/container-disk",
+						"--health-check",
 					},
-					InitialDelaySeconds: int32(initialDelaySeconds),
-					PeriodSeconds:       int32(periodSeconds),
-					TimeoutSeconds:      int32(timeoutSeconds),
-					SuccessThreshold:    int32(successThreshold),
-					FailureThreshold:    int32(failureThreshold),
 				},
-			}
-
-			containers = append(containers, container)
-		}
+			},
+			InitialDelaySeconds: int32(initialDelaySeconds),
+			PeriodSeconds:       int32(periodSeconds),
+			TimeoutSeconds:      int32(timeoutSeconds),
+			SuccessThreshold:    int32(successThreshold),
+			FailureThreshold:    int32(failureThreshold),
+		},
 	}
-	return containers
 }
 
 func CreateEphemeralImages(vmi *v1.VirtualMachineInstance) error {
@@ -167,6 +167,59 @@ var _ = Describe("ContainerDisk", func() {
 				Expect(containers[0].ImagePullPolicy).To(Equal(k8sv1.PullAlways))
 				Expect(containers[1].ImagePullPolicy).To(Equal(k8sv1.PullAlways))
 			})
+			It("by verifying firmware container generation", func() {
+				vmi := v1.NewMinimalVMI("fake-vmi")
+				vmi.UID = "6789"
+				appendContainerDisk(vmi, "r0")
+				vmi.Spec.Domain.Firmware = &v1.Firmware{
+					Bootloader: &v1.Bootloader{
+						EFI: &v1.EFI{
+							ContainerDisk: &v1.ContainerDiskSource{
+								Image:           "ovmf:custom",
+								ImagePullPolicy: k8sv1.PullIfNotPresent,
+							},
+						},
+					},
+				}
+				containers := GenerateContainers(vmi, "libvirt-runtime", "bin-volume")
+
+				Expect(containers).To(HaveLen(2))
+				Expect(containers[1].Name).To(Equal("firmware"))
+				Expect(containers[1].Image).To(Equal("ovmf:custom"))
+				Expect(containers[1].ImagePullPolicy).To(Equal(k8sv1.PullIfNotPresent))
+				Expect(containers[1].Args).To(Equal([]string{"--copy-path", filepath.Join(tmpDir, "6789", "firmware")}))
+			})
+			It("by verifying firmware directory locations", func() {
+				Expect(GetFirmwareTargetPathFromLauncherView()).To(Equal(filepath.Join(tmpDir, "firmware")))
+
+				_, err := GetFirmwareDir(tmpDir, "")
+				Expect(err).To(HaveOccurred())
+
+				Expect(os.MkdirAll(filepath.Join(tmpDir, "disk"), 0755)).To(Succeed())
+				dir, err := GetFirmwareDir(tmpDir, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dir).To(Equal(filepath.Join(tmpDir, "disk")))
+
+				_, err = os.Create(filepath.Join(tmpDir, "disk", "OVMF_CODE.fd"))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = GetFirmwareDir(tmpDir, "/disk/OVMF_CODE.fd")
+				Expect(err).To(HaveOccurred())
+			})
+			It("by verifying memory dump target locations", func() {
+				vmi := v1.NewMinimalVMI("fake-vmi")
+				vmi.Status.ActivePods = map[types.UID]string{"abcd": "node01"}
+
+				_, err := GetMemoryDumpTargetPathFromHostView(vmi)
+				Expect(err).To(HaveOccurred())
+
+				hostDir := filepath.Join(tmpDir, "abcd", "volumes", "kubernetes.io~empty-dir", "container-disks")
+				Expect(os.MkdirAll(hostDir, 0755)).To(Succeed())
+				path, err := GetMemoryDumpTargetPathFromHostView(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(path).To(Equal(filepath.Join(hostDir, "memory-dump")))
+				Expect(GetMemoryDumpTargetPathFromLauncherView()).To(Equal(filepath.Join(tmpDir, "memory-dump")))
+				Expect(GetMemoryDumpSocketPathFromHostView("efgh")).To(Equal(filepath.Join(tmpDir, "efgh", "volumes", "kubernetes.io~empty-dir", "memory-dump-socket", "attach.sock")))
+			})
 		})
 	})
 })
@@ -193,3 +193,20 @@ func SetLatestApiVersionAnnotation(object metav1.Object) {
 	annotations[v1.ControllerAPIStorageVersionObservedAnnotation] = v1.ApiStorageVersion
 	object.SetAnnotations(annotations)
 }
+
+// SetVMIPhaseTransitionTimestamp records when the VMI entered its phase, if the phase of newStatus differs
+// from the one of oldStatus and was not recorded yet
+func SetVMIPhaseTransitionTimestamp(oldStatus *v1.VirtualMachineInstanceStatus, newStatus *v1.VirtualMachineInstanceStatus) {
+	if oldStatus.Phase == newStatus.Phase || newStatus.Phase == v1.VmPhaseUnset {
+		return
+	}
+	for _, transition := range newStatus.PhaseTransitionTimestamps {
+		if transition.Phase == newStatus.Phase {
+			return
+		}
+	}
+	newStatus.PhaseTransitionTimestamps = append(newStatus.PhaseTransitionTimestamps, v1.VirtualMachineInstancePhaseTransitionTimestamp{
+		Phase:                    newStatus.Phase,
+		PhaseTransitionTimestamp: metav1.Now(),
+	})
+}
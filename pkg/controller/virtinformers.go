@@ -33,6 +33,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -41,6 +42,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/rest"
@@ -53,7 +55,6 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
-	"kubevirt.io/kubevirt/pkg/testutils"
 )
 
 const (
@@ -85,9 +86,21 @@ type KubeInformerFactory interface {
 	// VirtualMachine handles the VMIs that are stopped or not running
 	VirtualMachine() cache.SharedIndexInformer
 
+	// Watches for ControllerRevisions holding the template history of VirtualMachines
+	VirtualMachineTemplateRevision() cache.SharedIndexInformer
+
 	// Watches VirtualMachineInstanceMigration objects
 	VirtualMachineInstanceMigration() cache.SharedIndexInformer
 
+	// Watches ConformanceRun objects
+	ConformanceRun() cache.SharedIndexInformer
+
+	// Watches VirtualMachineExport objects
+	VirtualMachineExport() cache.SharedIndexInformer
+
+	// Watches VirtualMachineBackup objects
+	VirtualMachineBackup() cache.SharedIndexInformer
+
 	// Watches VirtualMachineSnapshot objects
 	VirtualMachineSnapshot() cache.SharedIndexInformer
 
@@ -112,6 +125,9 @@ type KubeInformerFactory interface {
 	// Watches for LimitRange objects
 	LimitRanges() cache.SharedIndexInformer
 
+	// Watches for ResourceQuota objects
+	ResourceQuotas() cache.SharedIndexInformer
+
 	// Watches for CDI DataVolume objects
 	DataVolume() cache.SharedIndexInformer
 
@@ -199,6 +215,9 @@ type KubeInformerFactory interface {
 	// PVC StorageClasses
 	StorageClass() cache.SharedIndexInformer
 
+	// NetworkPolicies restricting the traffic of the virt-launcher pods
+	NetworkPolicy() cache.SharedIndexInformer
+
 	K8SInformerFactory() informers.SharedInformerFactory
 }
 
@@ -315,6 +334,27 @@ func (f *kubeInformerFactory) VirtualMachineInstanceMigration() cache.SharedInde
 	})
 }
 
+func (f *kubeInformerFactory) ConformanceRun() cache.SharedIndexInformer {
+	return f.getInformer("conformanceRunInformer", func() cache.SharedIndexInformer {
+		lw := cache.NewListWatchFromClient(f.restClient, "conformanceruns", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &kubev1.ConformanceRun{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
+func (f *kubeInformerFactory) VirtualMachineExport() cache.SharedIndexInformer {
+	return f.getInformer("vmExportInformer", func() cache.SharedIndexInformer {
+		lw := cache.NewListWatchFromClient(f.restClient, "virtualmachineexports", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &kubev1.VirtualMachineExport{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
+func (f *kubeInformerFactory) VirtualMachineBackup() cache.SharedIndexInformer {
+	return f.getInformer("vmBackupInformer", func() cache.SharedIndexInformer {
+		lw := cache.NewListWatchFromClient(f.restClient, "virtualmachinebackups", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &kubev1.VirtualMachineBackup{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
 func (f *kubeInformerFactory) KubeVirtPod() cache.SharedIndexInformer {
 	return f.getInformer("kubeVirtPodInformer", func() cache.SharedIndexInformer {
 		// Watch all pods with the kubevirt app label
@@ -342,6 +382,18 @@ func (f *kubeInformerFactory) VirtualMachine() cache.SharedIndexInformer {
 	})
 }
 
+func (f *kubeInformerFactory) VirtualMachineTemplateRevision() cache.SharedIndexInformer {
+	return f.getInformer("vmTemplateRevisionInformer", func() cache.SharedIndexInformer {
+		labelSelector, err := labels.Parse(kubev1.VirtualMachineTemplateRevisionLabel)
+		if err != nil {
+			panic(err)
+		}
+
+		lw := NewListWatchFromClient(f.clientSet.AppsV1().RESTClient(), "controllerrevisions", k8sv1.NamespaceAll, fields.Everything(), labelSelector)
+		return cache.NewSharedIndexInformer(lw, &appsv1.ControllerRevision{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
 func (f *kubeInformerFactory) VirtualMachineSnapshot() cache.SharedIndexInformer {
 	return f.getInformer("vmSnapshotInformer", func() cache.SharedIndexInformer {
 		lw := cache.NewListWatchFromClient(f.clientSet.GeneratedKubeVirtClient().SnapshotV1alpha1().RESTClient(), "virtualmachinesnapshots", k8sv1.NamespaceAll, fields.Everything())
@@ -398,10 +450,54 @@ func (f *kubeInformerFactory) DataVolume() cache.SharedIndexInformer {
 	})
 }
 
+// newDummyInformer returns an informer which stays empty, for APIs which are not installed in the
+// cluster. It has no source, so nothing is listed or watched.
+func newDummyInformer(obj runtime.Object) cache.SharedIndexInformer {
+	return &dummyInformer{
+		indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+	}
+}
+
+type dummyInformer struct {
+	indexer cache.Indexer
+}
+
+func (i *dummyInformer) AddEventHandler(handler cache.ResourceEventHandler) {}
+
+func (i *dummyInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+}
+
+func (i *dummyInformer) GetStore() cache.Store {
+	return i.indexer
+}
+
+func (i *dummyInformer) GetController() cache.Controller {
+	return nil
+}
+
+func (i *dummyInformer) Run(stopCh <-chan struct{}) {
+	<-stopCh
+}
+
+func (i *dummyInformer) HasSynced() bool {
+	return true
+}
+
+func (i *dummyInformer) LastSyncResourceVersion() string {
+	return ""
+}
+
+func (i *dummyInformer) AddIndexers(indexers cache.Indexers) error {
+	return i.indexer.AddIndexers(indexers)
+}
+
+func (i *dummyInformer) GetIndexer() cache.Indexer {
+	return i.indexer
+}
+
 func (f *kubeInformerFactory) DummyDataVolume() cache.SharedIndexInformer {
 	return f.getInformer("fakeDataVolumeInformer", func() cache.SharedIndexInformer {
-		informer, _ := testutils.NewFakeInformerFor(&cdiv1.DataVolume{})
-		return informer
+		return newDummyInformer(&cdiv1.DataVolume{})
 	})
 }
 
@@ -448,6 +544,14 @@ func (f *kubeInformerFactory) LimitRanges() cache.SharedIndexInformer {
 	})
 }
 
+func (f *kubeInformerFactory) ResourceQuotas() cache.SharedIndexInformer {
+	return f.getInformer("resourceQuotaInformer", func() cache.SharedIndexInformer {
+		restClient := f.clientSet.CoreV1().RESTClient()
+		lw := cache.NewListWatchFromClient(restClient, "resourcequotas", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &k8sv1.ResourceQuota{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
 func (f *kubeInformerFactory) KubeVirt() cache.SharedIndexInformer {
 	return f.getInformer("kubeVirtInformer", func() cache.SharedIndexInformer {
 		lw := cache.NewListWatchFromClient(f.restClient, "kubevirts", k8sv1.NamespaceAll, fields.Everything())
@@ -594,8 +698,7 @@ func (f *kubeInformerFactory) OperatorSCC() cache.SharedIndexInformer {
 
 func (f *kubeInformerFactory) DummyOperatorSCC() cache.SharedIndexInformer {
 	return f.getInformer("FakeOperatorSCC", func() cache.SharedIndexInformer {
-		informer, _ := testutils.NewFakeInformerFor(&secv1.SecurityContextConstraints{})
-		return informer
+		return newDummyInformer(&secv1.SecurityContextConstraints{})
 	})
 }
 
@@ -711,8 +814,7 @@ func (f *kubeInformerFactory) OperatorServiceMonitor() cache.SharedIndexInformer
 
 func (f *kubeInformerFactory) DummyOperatorServiceMonitor() cache.SharedIndexInformer {
 	return f.getInformer("FakeOperatorServiceMonitor", func() cache.SharedIndexInformer {
-		informer, _ := testutils.NewFakeInformerFor(&promv1.ServiceMonitor{})
-		return informer
+		return newDummyInformer(&promv1.ServiceMonitor{})
 	})
 }
 
@@ -750,8 +852,7 @@ func (f *kubeInformerFactory) OperatorPrometheusRule() cache.SharedIndexInformer
 
 func (f *kubeInformerFactory) DummyOperatorPrometheusRule() cache.SharedIndexInformer {
 	return f.getInformer("FakeOperatorPrometheusRuleInformer", func() cache.SharedIndexInformer {
-		informer, _ := testutils.NewFakeInformerFor(&promv1.PrometheusRule{})
-		return informer
+		return newDummyInformer(&promv1.PrometheusRule{})
 	})
 }
 
@@ -763,6 +864,14 @@ func (f *kubeInformerFactory) StorageClass() cache.SharedIndexInformer {
 	})
 }
 
+func (f *kubeInformerFactory) NetworkPolicy() cache.SharedIndexInformer {
+	return f.getInformer("networkPolicyInformer", func() cache.SharedIndexInformer {
+		restClient := f.clientSet.NetworkingV1().RESTClient()
+		lw := cache.NewListWatchFromClient(restClient, "networkpolicies", k8sv1.NamespaceAll, fields.Everything())
+		return cache.NewSharedIndexInformer(lw, &networkingv1.NetworkPolicy{}, f.defaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+}
+
 // VolumeSnapshotInformer returns an informer for VolumeSnapshots
 func VolumeSnapshotInformer(clientSet kubecli.KubevirtClient, resyncPeriod time.Duration) cache.SharedIndexInformer {
 	restClient := clientSet.KubernetesSnapshotClient().SnapshotV1beta1().RESTClient()
@@ -31,6 +31,7 @@ import (
 
 var mountBaseDir = "/var/run/libvirt/kubevirt-ephemeral-disk"
 var pvcBaseDir = "/var/run/kubevirt-private/vmi-disks"
+var blockDeviceBaseDir = "/dev"
 
 func generateBaseDir() string {
 	return fmt.Sprintf("%s", mountBaseDir)
@@ -44,6 +45,16 @@ func getBackingFilePath(volumeName string) string {
 	return filepath.Join(pvcBaseDir, volumeName, "disk.img")
 }
 
+// getGoldenImageBackingPath returns the device of a block golden image, or the
+// image file on a filesystem golden image
+func getGoldenImageBackingPath(volumeName string) string {
+	devicePath := filepath.Join(blockDeviceBaseDir, volumeName)
+	if _, err := os.Stat(devicePath); err == nil {
+		return devicePath
+	}
+	return getBackingFilePath(volumeName)
+}
+
 func SetLocalDirectory(dir string) error {
 	mountBaseDir = dir
 	return os.MkdirAll(dir, 0755)
@@ -121,6 +132,11 @@ func CreateEphemeralImages(vmi *v1.VirtualMachineInstance) error {
 				return err
 			}
 		}
+		if volume.VolumeSource.GoldenImage != nil {
+			if err := CreateBackedImageForVolume(volume, getGoldenImageBackingPath(volume.Name)); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
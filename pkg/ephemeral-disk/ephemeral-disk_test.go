@@ -149,4 +149,38 @@ var _ = Describe("ContainerDisk", func() {
 			})
 		})
 	})
+
+	Describe("golden image", func() {
+		It("Should create an overlay for the golden image", func() {
+			vmi := v1.NewMinimalVMI("fake-vmi")
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "golden-disk",
+				VolumeSource: v1.VolumeSource{
+					GoldenImage: &v1.GoldenImageVolumeSource{
+						ClaimName: "golden-pvc",
+					},
+				},
+			})
+			createBackingImageForPVC("golden-disk")
+
+			err := CreateEphemeralImages(vmi)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = os.Stat(filepath.Join(mountBaseDir, "golden-disk", "disk.qcow2"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should prefer the block device of a golden image", func() {
+			devTempDirPath, err := ioutil.TempDir("", "ephemeraldisk-dev")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(devTempDirPath)
+			blockDeviceBaseDir = devTempDirPath
+			defer func() { blockDeviceBaseDir = "/dev" }()
+
+			Expect(getGoldenImageBackingPath("golden-disk")).To(Equal(getBackingFilePath("golden-disk")))
+
+			Expect(ioutil.WriteFile(filepath.Join(devTempDirPath, "golden-disk"), []byte{}, 0644)).To(Succeed())
+			Expect(getGoldenImageBackingPath("golden-disk")).To(Equal(filepath.Join(devTempDirPath, "golden-disk")))
+		})
+	})
 })
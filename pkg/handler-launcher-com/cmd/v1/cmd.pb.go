@@ -20,6 +20,10 @@ It has these top-level messages:
 	GuestInfoResponse
 	GuestUserListResponse
 	GuestFilesystemsResponse
+	BackupRequest
+	BackupResponse
+	MemoryDumpRequest
+	DomainStatsStreamRequest
 */
 package v1
 
@@ -334,6 +338,94 @@ func (m *GuestFilesystemsResponse) GetGuestFilesystemsResponse() string {
 	return ""
 }
 
+type BackupRequest struct {
+	Vmi     *VMI   `protobuf:"bytes,1,opt,name=vmi" json:"vmi,omitempty"`
+	Options []byte `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *BackupRequest) Reset()                    { *m = BackupRequest{} }
+func (m *BackupRequest) String() string            { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()               {}
+func (*BackupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *BackupRequest) GetVmi() *VMI {
+	if m != nil {
+		return m.Vmi
+	}
+	return nil
+}
+
+func (m *BackupRequest) GetOptions() []byte {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type BackupResponse struct {
+	Response      *Response `protobuf:"bytes,1,opt,name=response" json:"response,omitempty"`
+	BackupVolumes string    `protobuf:"bytes,2,opt,name=backupVolumes" json:"backupVolumes,omitempty"`
+}
+
+func (m *BackupResponse) Reset()                    { *m = BackupResponse{} }
+func (m *BackupResponse) String() string            { return proto.CompactTextString(m) }
+func (*BackupResponse) ProtoMessage()               {}
+func (*BackupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *BackupResponse) GetResponse() *Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func (m *BackupResponse) GetBackupVolumes() string {
+	if m != nil {
+		return m.BackupVolumes
+	}
+	return ""
+}
+
+type MemoryDumpRequest struct {
+	Vmi      *VMI   `protobuf:"bytes,1,opt,name=vmi" json:"vmi,omitempty"`
+	FileName string `protobuf:"bytes,2,opt,name=fileName" json:"fileName,omitempty"`
+}
+
+func (m *MemoryDumpRequest) Reset()                    { *m = MemoryDumpRequest{} }
+func (m *MemoryDumpRequest) String() string            { return proto.CompactTextString(m) }
+func (*MemoryDumpRequest) ProtoMessage()               {}
+func (*MemoryDumpRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *MemoryDumpRequest) GetVmi() *VMI {
+	if m != nil {
+		return m.Vmi
+	}
+	return nil
+}
+
+func (m *MemoryDumpRequest) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+type DomainStatsStreamRequest struct {
+	IntervalSeconds uint32 `protobuf:"varint,1,opt,name=intervalSeconds" json:"intervalSeconds,omitempty"`
+}
+
+func (m *DomainStatsStreamRequest) Reset()                    { *m = DomainStatsStreamRequest{} }
+func (m *DomainStatsStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*DomainStatsStreamRequest) ProtoMessage()               {}
+func (*DomainStatsStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *DomainStatsStreamRequest) GetIntervalSeconds() uint32 {
+	if m != nil {
+		return m.IntervalSeconds
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*VMI)(nil), "kubevirt.cmd.v1.VMI")
 	proto.RegisterType((*SMBios)(nil), "kubevirt.cmd.v1.SMBios")
@@ -347,6 +439,10 @@ func init() {
 	proto.RegisterType((*GuestInfoResponse)(nil), "kubevirt.cmd.v1.GuestInfoResponse")
 	proto.RegisterType((*GuestUserListResponse)(nil), "kubevirt.cmd.v1.GuestUserListResponse")
 	proto.RegisterType((*GuestFilesystemsResponse)(nil), "kubevirt.cmd.v1.GuestFilesystemsResponse")
+	proto.RegisterType((*BackupRequest)(nil), "kubevirt.cmd.v1.BackupRequest")
+	proto.RegisterType((*BackupResponse)(nil), "kubevirt.cmd.v1.BackupResponse")
+	proto.RegisterType((*MemoryDumpRequest)(nil), "kubevirt.cmd.v1.MemoryDumpRequest")
+	proto.RegisterType((*DomainStatsStreamRequest)(nil), "kubevirt.cmd.v1.DomainStatsStreamRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -376,6 +472,12 @@ type CmdClient interface {
 	GetUsers(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GuestUserListResponse, error)
 	GetFilesystems(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GuestFilesystemsResponse, error)
 	Ping(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Response, error)
+	BackupVirtualMachine(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
+	AbortVirtualMachineBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*Response, error)
+	MemoryDumpVirtualMachine(ctx context.Context, in *MemoryDumpRequest, opts ...grpc.CallOption) (*Response, error)
+	FreezeVirtualMachine(ctx context.Context, in *VMIRequest, opts ...grpc.CallOption) (*Response, error)
+	UnfreezeVirtualMachine(ctx context.Context, in *VMIRequest, opts ...grpc.CallOption) (*Response, error)
+	StreamDomainStats(ctx context.Context, in *DomainStatsStreamRequest, opts ...grpc.CallOption) (Cmd_StreamDomainStatsClient, error)
 }
 
 type cmdClient struct {
@@ -530,6 +632,83 @@ func (c *cmdClient) Ping(ctx context.Context, in *EmptyRequest, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *cmdClient) BackupVirtualMachine(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*BackupResponse, error) {
+	out := new(BackupResponse)
+	err := grpc.Invoke(ctx, "/kubevirt.cmd.v1.Cmd/BackupVirtualMachine", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdClient) AbortVirtualMachineBackup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := grpc.Invoke(ctx, "/kubevirt.cmd.v1.Cmd/AbortVirtualMachineBackup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdClient) MemoryDumpVirtualMachine(ctx context.Context, in *MemoryDumpRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := grpc.Invoke(ctx, "/kubevirt.cmd.v1.Cmd/MemoryDumpVirtualMachine", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdClient) FreezeVirtualMachine(ctx context.Context, in *VMIRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := grpc.Invoke(ctx, "/kubevirt.cmd.v1.Cmd/FreezeVirtualMachine", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdClient) UnfreezeVirtualMachine(ctx context.Context, in *VMIRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := grpc.Invoke(ctx, "/kubevirt.cmd.v1.Cmd/UnfreezeVirtualMachine", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cmdClient) StreamDomainStats(ctx context.Context, in *DomainStatsStreamRequest, opts ...grpc.CallOption) (Cmd_StreamDomainStatsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Cmd_serviceDesc.Streams[0], c.cc, "/kubevirt.cmd.v1.Cmd/StreamDomainStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cmdStreamDomainStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Cmd_StreamDomainStatsClient interface {
+	Recv() (*DomainStatsResponse, error)
+	grpc.ClientStream
+}
+
+type cmdStreamDomainStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cmdStreamDomainStatsClient) Recv() (*DomainStatsResponse, error) {
+	m := new(DomainStatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Cmd service
 
 type CmdServer interface {
@@ -549,6 +728,12 @@ type CmdServer interface {
 	GetUsers(context.Context, *EmptyRequest) (*GuestUserListResponse, error)
 	GetFilesystems(context.Context, *EmptyRequest) (*GuestFilesystemsResponse, error)
 	Ping(context.Context, *EmptyRequest) (*Response, error)
+	BackupVirtualMachine(context.Context, *BackupRequest) (*BackupResponse, error)
+	AbortVirtualMachineBackup(context.Context, *BackupRequest) (*Response, error)
+	MemoryDumpVirtualMachine(context.Context, *MemoryDumpRequest) (*Response, error)
+	FreezeVirtualMachine(context.Context, *VMIRequest) (*Response, error)
+	UnfreezeVirtualMachine(context.Context, *VMIRequest) (*Response, error)
+	StreamDomainStats(*DomainStatsStreamRequest, Cmd_StreamDomainStatsServer) error
 }
 
 func RegisterCmdServer(s *grpc.Server, srv CmdServer) {
@@ -843,6 +1028,117 @@ func _Cmd_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cmd_BackupVirtualMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdServer).BackupVirtualMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kubevirt.cmd.v1.Cmd/BackupVirtualMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdServer).BackupVirtualMachine(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cmd_AbortVirtualMachineBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdServer).AbortVirtualMachineBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kubevirt.cmd.v1.Cmd/AbortVirtualMachineBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdServer).AbortVirtualMachineBackup(ctx, req.(*BackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cmd_MemoryDumpVirtualMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MemoryDumpRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdServer).MemoryDumpVirtualMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kubevirt.cmd.v1.Cmd/MemoryDumpVirtualMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdServer).MemoryDumpVirtualMachine(ctx, req.(*MemoryDumpRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cmd_FreezeVirtualMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdServer).FreezeVirtualMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kubevirt.cmd.v1.Cmd/FreezeVirtualMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdServer).FreezeVirtualMachine(ctx, req.(*VMIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cmd_UnfreezeVirtualMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CmdServer).UnfreezeVirtualMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kubevirt.cmd.v1.Cmd/UnfreezeVirtualMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CmdServer).UnfreezeVirtualMachine(ctx, req.(*VMIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cmd_StreamDomainStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DomainStatsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CmdServer).StreamDomainStats(m, &cmdStreamDomainStatsServer{stream})
+}
+
+type Cmd_StreamDomainStatsServer interface {
+	Send(*DomainStatsResponse) error
+	grpc.ServerStream
+}
+
+type cmdStreamDomainStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cmdStreamDomainStatsServer) Send(m *DomainStatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Cmd_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "kubevirt.cmd.v1.Cmd",
 	HandlerType: (*CmdServer)(nil),
@@ -911,60 +1207,96 @@ var _Cmd_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _Cmd_Ping_Handler,
 		},
+		{
+			MethodName: "BackupVirtualMachine",
+			Handler:    _Cmd_BackupVirtualMachine_Handler,
+		},
+		{
+			MethodName: "AbortVirtualMachineBackup",
+			Handler:    _Cmd_AbortVirtualMachineBackup_Handler,
+		},
+		{
+			MethodName: "MemoryDumpVirtualMachine",
+			Handler:    _Cmd_MemoryDumpVirtualMachine_Handler,
+		},
+		{
+			MethodName: "FreezeVirtualMachine",
+			Handler:    _Cmd_FreezeVirtualMachine_Handler,
+		},
+		{
+			MethodName: "UnfreezeVirtualMachine",
+			Handler:    _Cmd_UnfreezeVirtualMachine_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDomainStats",
+			Handler:       _Cmd_StreamDomainStats_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/handler-launcher-com/cmd/v1/cmd.proto",
 }
 
 func init() { proto.RegisterFile("pkg/handler-launcher-com/cmd/v1/cmd.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 738 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x96, 0x61, 0x4f, 0xd3, 0x40,
-	0x18, 0xc7, 0x37, 0x86, 0x30, 0x1e, 0x26, 0xc2, 0xc1, 0xb0, 0x62, 0x08, 0x78, 0x31, 0x44, 0x12,
-	0x19, 0x01, 0xf1, 0x8d, 0x2f, 0x8c, 0x19, 0xe8, 0x82, 0x38, 0x98, 0x1d, 0xcc, 0x68, 0x4c, 0xcc,
-	0xd1, 0x1e, 0xdd, 0x85, 0xf6, 0x6e, 0xf6, 0xae, 0xd3, 0xbd, 0xf7, 0x95, 0x89, 0x5f, 0xc0, 0x4f,
-	0xe3, 0x47, 0x33, 0xbd, 0x76, 0x83, 0xad, 0x9d, 0x8b, 0xd9, 0x5e, 0xd1, 0xe7, 0x9e, 0xe7, 0x7e,
-	0xff, 0xe7, 0x9e, 0x6b, 0xff, 0x0c, 0xb6, 0x5b, 0xd7, 0xce, 0x6e, 0x93, 0x70, 0xdb, 0xa5, 0xfe,
-	0x8e, 0x4b, 0x02, 0x6e, 0x35, 0xa9, 0xbf, 0x63, 0x09, 0x6f, 0xd7, 0xf2, 0xec, 0xdd, 0xf6, 0x5e,
-	0xf8, 0xa7, 0xd4, 0xf2, 0x85, 0x12, 0xe8, 0xde, 0x75, 0x70, 0x49, 0xdb, 0xcc, 0x57, 0xa5, 0x70,
-	0xad, 0xbd, 0x87, 0x37, 0x20, 0xd7, 0xa8, 0x1e, 0x23, 0x03, 0x66, 0xdb, 0x1e, 0x7b, 0x2b, 0x05,
-	0x37, 0xb2, 0x9b, 0xd9, 0x27, 0x05, 0xb3, 0x1b, 0xe2, 0x9f, 0x59, 0x98, 0xa9, 0x57, 0xcb, 0x4c,
-	0x48, 0x84, 0xa1, 0xe0, 0x11, 0x1e, 0x5c, 0x11, 0x4b, 0x05, 0x3e, 0xf5, 0x75, 0xe5, 0x9c, 0xd9,
-	0xb7, 0x16, 0x82, 0x5a, 0xbe, 0xb0, 0x03, 0x4b, 0x19, 0x53, 0x3a, 0xdd, 0x0d, 0xb5, 0x04, 0xf5,
-	0x25, 0x13, 0xdc, 0xc8, 0x45, 0x99, 0x38, 0x44, 0x8b, 0x90, 0x93, 0xd7, 0x81, 0x31, 0xad, 0x57,
-	0xc3, 0x47, 0xb4, 0x0a, 0x33, 0x57, 0xc4, 0x63, 0x6e, 0xc7, 0xb8, 0xa3, 0x17, 0xe3, 0x08, 0xff,
-	0xce, 0x42, 0xb1, 0xc1, 0x7c, 0x15, 0x10, 0xb7, 0x4a, 0xac, 0x26, 0xe3, 0xf4, 0xac, 0xa5, 0x98,
-	0xe0, 0x12, 0x9d, 0xc0, 0x4a, 0x7f, 0x22, 0xea, 0x59, 0xf7, 0x38, 0xbf, 0x7f, 0xbf, 0x34, 0x70,
-	0xee, 0x52, 0x94, 0x36, 0x53, 0x37, 0xa1, 0x03, 0x28, 0x56, 0xa9, 0x57, 0x26, 0xae, 0x2b, 0x04,
-	0xaf, 0x2b, 0xa2, 0x64, 0x8d, 0xfa, 0x4c, 0xd8, 0xfa, 0x48, 0x77, 0xcd, 0xf4, 0x24, 0x6e, 0x03,
-	0x34, 0xaa, 0xc7, 0x26, 0xfd, 0x1a, 0x50, 0xa9, 0xd0, 0x16, 0xe4, 0xda, 0x1e, 0x8b, 0xf5, 0x57,
-	0x12, 0xfa, 0x61, 0x65, 0x58, 0x80, 0x5e, 0xc1, 0xac, 0x88, 0xce, 0xa0, 0xe9, 0xf3, 0xfb, 0x5b,
-	0xc9, 0xda, 0xb4, 0x13, 0x9b, 0xdd, 0x6d, 0xf8, 0x1c, 0x16, 0xab, 0xcc, 0xf1, 0x49, 0x18, 0xfd,
-	0xaf, 0xba, 0xd1, 0xaf, 0x5e, 0xb8, 0xa1, 0x2e, 0x40, 0xe1, 0xb5, 0xd7, 0x52, 0x9d, 0x98, 0x88,
-	0x5f, 0x42, 0xde, 0xa4, 0xb2, 0x25, 0xb8, 0xa4, 0xe1, 0x2e, 0x19, 0x58, 0x16, 0x95, 0xd1, 0x7c,
-	0xf3, 0x66, 0x37, 0x0c, 0x33, 0x1e, 0x95, 0x92, 0x38, 0xb4, 0x7b, 0xfd, 0x71, 0x88, 0xbf, 0xc0,
-	0xc2, 0x91, 0xf0, 0x08, 0xe3, 0x3d, 0xca, 0x73, 0xc8, 0xfb, 0xf1, 0x73, 0xdc, 0xe8, 0x83, 0x44,
-	0xa3, 0xdd, 0x62, 0xb3, 0x57, 0x1a, 0xbe, 0x1b, 0xb6, 0x06, 0xc5, 0x0a, 0x71, 0x84, 0x39, 0x2c,
-	0x47, 0x02, 0xfa, 0x4e, 0xc6, 0x55, 0xd9, 0x84, 0x79, 0xfb, 0x86, 0x16, 0x4b, 0xdd, 0x5e, 0xc2,
-	0xdf, 0x61, 0xa9, 0x12, 0x4e, 0xe6, 0x98, 0x5f, 0x89, 0x71, 0xd5, 0x9e, 0xc2, 0x92, 0x33, 0xc8,
-	0x8a, 0x35, 0x93, 0x09, 0xfc, 0x23, 0x0b, 0x45, 0x2d, 0x7d, 0x21, 0xa9, 0xff, 0x8e, 0x49, 0x35,
-	0xae, 0xfc, 0x01, 0x14, 0x9d, 0x34, 0x5e, 0xdc, 0x42, 0x7a, 0x12, 0xff, 0xca, 0x82, 0xa1, 0xdb,
-	0x78, 0xc3, 0x5c, 0x2a, 0x3b, 0x52, 0x51, 0x6f, 0xec, 0xb1, 0xbf, 0x00, 0xc3, 0x19, 0x82, 0x8c,
-	0x9b, 0x19, 0x9a, 0xdf, 0xff, 0x03, 0x90, 0x3b, 0xf4, 0x6c, 0x74, 0x0a, 0xa8, 0xde, 0xe1, 0x56,
-	0xff, 0x57, 0x83, 0x1e, 0xa6, 0x7e, 0x04, 0xd1, 0xcb, 0xbd, 0x36, 0xbc, 0x37, 0x9c, 0x41, 0x67,
-	0xb0, 0x5c, 0x23, 0x81, 0xa4, 0x13, 0x03, 0xbe, 0x87, 0xe2, 0x05, 0x6f, 0x4d, 0x14, 0x69, 0xc2,
-	0x6a, 0xbd, 0x19, 0x28, 0x5b, 0x7c, 0xe3, 0x13, 0x63, 0x9e, 0x02, 0x3a, 0x61, 0xae, 0x3b, 0x31,
-	0x5e, 0x0d, 0x56, 0x8e, 0xa8, 0x4b, 0xd5, 0xe4, 0x4e, 0xfd, 0x01, 0x8a, 0x91, 0xf3, 0x0d, 0x22,
-	0x1f, 0x25, 0x76, 0x0d, 0x3a, 0xe4, 0xc8, 0x2b, 0x0f, 0x5f, 0xa1, 0xde, 0xa6, 0x73, 0xe2, 0x3b,
-	0x54, 0x8d, 0xd1, 0xe9, 0x47, 0x58, 0x3f, 0x24, 0xdc, 0xa2, 0x03, 0xd3, 0xec, 0x09, 0x8c, 0x81,
-	0x6e, 0xc0, 0x5a, 0x9d, 0xaa, 0x7e, 0xae, 0xfe, 0x2c, 0xcf, 0x99, 0x37, 0xce, 0x70, 0xab, 0x30,
-	0x57, 0xa1, 0x2a, 0xb2, 0x54, 0xb4, 0x9e, 0xa8, 0xbc, 0xfd, 0xcf, 0x61, 0x6d, 0x23, 0x91, 0xee,
-	0xf7, 0x7a, 0x7d, 0x57, 0x0b, 0x3d, 0x9c, 0x36, 0xd0, 0x51, 0xcc, 0xc7, 0x43, 0x98, 0x7d, 0xf6,
-	0x8e, 0x33, 0xa8, 0x0e, 0x85, 0x0a, 0x55, 0x3d, 0x2b, 0x1e, 0x85, 0xc5, 0x89, 0x74, 0xc2, 0xc5,
-	0x35, 0x34, 0x5f, 0xa1, 0xda, 0xf2, 0x46, 0xf6, 0xb9, 0x95, 0x0e, 0x4c, 0xd8, 0x65, 0x06, 0x7d,
-	0xd6, 0x23, 0xb8, 0x65, 0x5d, 0xa3, 0xd0, 0xdb, 0xe9, 0xe8, 0x14, 0xf3, 0xc3, 0x19, 0x54, 0x86,
-	0xe9, 0x1a, 0xe3, 0xce, 0x28, 0xe6, 0xbf, 0xee, 0xbc, 0x3c, 0xfd, 0x69, 0xaa, 0xbd, 0x77, 0x39,
-	0xa3, 0x7f, 0x2b, 0x3e, 0xfb, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x5c, 0xa3, 0x1e, 0x96, 0x58, 0x0a,
-	0x00, 0x00,
+	// 906 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x97, 0xdf, 0x6f, 0xdb, 0x36,
+	0x10, 0xc7, 0xe3, 0x3a, 0x4b, 0xdd, 0x8b, 0xe3, 0x36, 0x6c, 0xdc, 0xa9, 0x1e, 0xba, 0x74, 0x44,
+	0x11, 0x34, 0xc0, 0x9a, 0x2c, 0x59, 0xf7, 0xb2, 0x87, 0x61, 0x73, 0xb3, 0x06, 0x59, 0xe7, 0x34,
+	0x95, 0x13, 0x77, 0xbf, 0x80, 0x81, 0x91, 0x69, 0x9b, 0x88, 0x48, 0x7a, 0x24, 0xe5, 0xcd, 0x7b,
+	0xde, 0xd3, 0x80, 0xfd, 0x03, 0xfb, 0xf3, 0xf6, 0x97, 0x0c, 0xa2, 0x24, 0xc7, 0xb2, 0xe4, 0xaa,
+	0x81, 0xfd, 0x14, 0x1f, 0xef, 0xf8, 0xb9, 0xe3, 0x91, 0xa7, 0x2f, 0x02, 0xbb, 0xc3, 0xab, 0xfe,
+	0xfe, 0x80, 0x88, 0xae, 0x4f, 0xd5, 0x33, 0x9f, 0x04, 0xc2, 0x1b, 0x50, 0xf5, 0xcc, 0x93, 0x7c,
+	0xdf, 0xe3, 0xdd, 0xfd, 0xd1, 0x41, 0xf8, 0x67, 0x6f, 0xa8, 0xa4, 0x91, 0xe8, 0xee, 0x55, 0x70,
+	0x49, 0x47, 0x4c, 0x99, 0xbd, 0x70, 0x6d, 0x74, 0x80, 0xb7, 0xa1, 0xdc, 0x69, 0x9d, 0x20, 0x07,
+	0x6e, 0x8f, 0x38, 0xfb, 0x4e, 0x4b, 0xe1, 0x94, 0x1e, 0x97, 0x9e, 0x56, 0xdd, 0xc4, 0xc4, 0x7f,
+	0x97, 0x60, 0xad, 0xdd, 0x6a, 0x32, 0xa9, 0x11, 0x86, 0x2a, 0x27, 0x22, 0xe8, 0x11, 0xcf, 0x04,
+	0x8a, 0x2a, 0x1b, 0x79, 0xc7, 0x4d, 0xad, 0x85, 0xa0, 0xa1, 0x92, 0xdd, 0xc0, 0x33, 0xce, 0x2d,
+	0xeb, 0x4e, 0x4c, 0x9b, 0x82, 0x2a, 0xcd, 0xa4, 0x70, 0xca, 0x91, 0x27, 0x36, 0xd1, 0x3d, 0x28,
+	0xeb, 0xab, 0xc0, 0x59, 0xb5, 0xab, 0xe1, 0x4f, 0xf4, 0x00, 0xd6, 0x7a, 0x84, 0x33, 0x7f, 0xec,
+	0x7c, 0x60, 0x17, 0x63, 0x0b, 0xff, 0x5b, 0x82, 0x7a, 0x87, 0x29, 0x13, 0x10, 0xbf, 0x45, 0xbc,
+	0x01, 0x13, 0xf4, 0xf5, 0xd0, 0x30, 0x29, 0x34, 0x7a, 0x05, 0x5b, 0x69, 0x47, 0x54, 0xb3, 0xad,
+	0x71, 0xfd, 0xf0, 0xc3, 0xbd, 0x99, 0x73, 0xef, 0x45, 0x6e, 0x37, 0x77, 0x13, 0x7a, 0x0e, 0xf5,
+	0x16, 0xe5, 0x4d, 0xe2, 0xfb, 0x52, 0x8a, 0xb6, 0x21, 0x46, 0x9f, 0x51, 0xc5, 0x64, 0xd7, 0x1e,
+	0x69, 0xc3, 0xcd, 0x77, 0xe2, 0x11, 0x40, 0xa7, 0x75, 0xe2, 0xd2, 0xdf, 0x02, 0xaa, 0x0d, 0xda,
+	0x81, 0xf2, 0x88, 0xb3, 0x38, 0xff, 0x56, 0x26, 0x7f, 0x18, 0x19, 0x06, 0xa0, 0xaf, 0xe1, 0xb6,
+	0x8c, 0xce, 0x60, 0xe9, 0xeb, 0x87, 0x3b, 0xd9, 0xd8, 0xbc, 0x13, 0xbb, 0xc9, 0x36, 0x7c, 0x0e,
+	0xf7, 0x5a, 0xac, 0xaf, 0x48, 0x68, 0xdd, 0x34, 0xbb, 0x93, 0xce, 0x5e, 0xbd, 0xa6, 0xd6, 0xa0,
+	0xfa, 0x2d, 0x1f, 0x9a, 0x71, 0x4c, 0xc4, 0x5f, 0x41, 0xc5, 0xa5, 0x7a, 0x28, 0x85, 0xa6, 0xe1,
+	0x2e, 0x1d, 0x78, 0x1e, 0xd5, 0x51, 0x7f, 0x2b, 0x6e, 0x62, 0x86, 0x1e, 0x4e, 0xb5, 0x26, 0x7d,
+	0x9a, 0x5c, 0x7f, 0x6c, 0xe2, 0x5f, 0xa1, 0x76, 0x24, 0x39, 0x61, 0x62, 0x42, 0xf9, 0x02, 0x2a,
+	0x2a, 0xfe, 0x1d, 0x17, 0xfa, 0x30, 0x53, 0x68, 0x12, 0xec, 0x4e, 0x42, 0xc3, 0xb7, 0xd1, 0xb5,
+	0xa0, 0x38, 0x43, 0x6c, 0x61, 0x01, 0xf7, 0xa3, 0x04, 0xf6, 0x4e, 0x16, 0xcd, 0xf2, 0x18, 0xd6,
+	0xbb, 0xd7, 0xb4, 0x38, 0xd5, 0xf4, 0x12, 0xfe, 0x03, 0x36, 0x8f, 0xc3, 0xce, 0x9c, 0x88, 0x9e,
+	0x5c, 0x34, 0xdb, 0xa7, 0xb0, 0xd9, 0x9f, 0x65, 0xc5, 0x39, 0xb3, 0x0e, 0xfc, 0x57, 0x09, 0xea,
+	0x36, 0xf5, 0x85, 0xa6, 0xea, 0x7b, 0xa6, 0xcd, 0xa2, 0xe9, 0x9f, 0x43, 0xbd, 0x9f, 0xc7, 0x8b,
+	0x4b, 0xc8, 0x77, 0xe2, 0x7f, 0x4a, 0xe0, 0xd8, 0x32, 0x5e, 0x32, 0x9f, 0xea, 0xb1, 0x36, 0x94,
+	0x2f, 0xdc, 0xf6, 0x2f, 0xc1, 0xe9, 0xcf, 0x41, 0xc6, 0xc5, 0xcc, 0xf5, 0xe3, 0x37, 0xb0, 0xd1,
+	0x24, 0xde, 0x55, 0x30, 0x5c, 0xde, 0x10, 0x70, 0xa8, 0x25, 0xc8, 0xc5, 0xce, 0xf5, 0x04, 0x36,
+	0x2e, 0x2d, 0xa8, 0x23, 0xfd, 0x80, 0xd3, 0xe4, 0x41, 0xa5, 0x17, 0xf1, 0x5b, 0xd8, 0x6c, 0x51,
+	0x2e, 0xd5, 0xf8, 0x28, 0xe0, 0x37, 0x3e, 0x45, 0x03, 0x2a, 0x3d, 0xe6, 0xd3, 0x53, 0xc2, 0x93,
+	0x56, 0x4d, 0x6c, 0x7c, 0x04, 0xce, 0xd4, 0x6c, 0xb4, 0x8d, 0xa2, 0x84, 0x27, 0xfc, 0xa7, 0x70,
+	0x97, 0x09, 0x43, 0xd5, 0x88, 0xf8, 0x6d, 0xea, 0x49, 0xd1, 0x8d, 0x86, 0x7a, 0xc3, 0x9d, 0x5d,
+	0x3e, 0xfc, 0xaf, 0x06, 0xe5, 0x17, 0xbc, 0x8b, 0x4e, 0x01, 0xb5, 0xc7, 0xc2, 0x4b, 0x7f, 0x96,
+	0xd0, 0x47, 0xb9, 0xa5, 0x45, 0x49, 0x1a, 0xf3, 0x9b, 0x84, 0x57, 0xd0, 0x6b, 0xb8, 0x7f, 0x46,
+	0x02, 0x4d, 0x97, 0x06, 0x7c, 0x03, 0xf5, 0x0b, 0x31, 0x5c, 0x2a, 0xd2, 0x85, 0x07, 0xed, 0x41,
+	0x60, 0xba, 0xf2, 0x77, 0xb1, 0x34, 0xe6, 0x29, 0xa0, 0x57, 0xcc, 0xf7, 0x97, 0xc6, 0x3b, 0x83,
+	0xad, 0x23, 0xea, 0x53, 0xb3, 0xbc, 0x53, 0xbf, 0x85, 0x7a, 0x24, 0x2d, 0xb3, 0xc8, 0x4f, 0x32,
+	0xbb, 0x66, 0x25, 0xa8, 0xf0, 0xca, 0xc3, 0x27, 0x34, 0xd9, 0x74, 0x4e, 0x54, 0x9f, 0x9a, 0x05,
+	0x2a, 0xfd, 0x11, 0x1e, 0xbd, 0x20, 0xc2, 0xa3, 0x33, 0xdd, 0x9c, 0x24, 0x58, 0x00, 0xdd, 0x81,
+	0x46, 0x9b, 0x9a, 0x34, 0xd7, 0x7e, 0xf7, 0xce, 0x19, 0x5f, 0xa4, 0xb9, 0x2d, 0xb8, 0x73, 0x4c,
+	0x4d, 0x34, 0x97, 0xe8, 0x51, 0x26, 0x72, 0x5a, 0x7d, 0x1b, 0xdb, 0x19, 0x77, 0x5a, 0x4c, 0xed,
+	0x5d, 0xd5, 0x26, 0x38, 0x3b, 0xe6, 0x45, 0xcc, 0x27, 0x73, 0x98, 0x29, 0xfd, 0xc4, 0x2b, 0xa8,
+	0x0d, 0xd5, 0x63, 0x6a, 0x26, 0x5a, 0x57, 0x84, 0xc5, 0x19, 0x77, 0x46, 0x26, 0x2d, 0xb4, 0x72,
+	0x4c, 0xad, 0xa6, 0x14, 0xd6, 0xb9, 0x93, 0x0f, 0xcc, 0xe8, 0xd1, 0x0a, 0xfa, 0xc5, 0xb6, 0x60,
+	0x4a, 0x1b, 0x8a, 0xd0, 0xbb, 0xf9, 0xe8, 0x3c, 0x75, 0x59, 0x41, 0x4d, 0x58, 0x3d, 0x63, 0xa2,
+	0x5f, 0xc4, 0x2c, 0x78, 0xa6, 0x5b, 0x91, 0xa0, 0xcc, 0xcc, 0xd3, 0xc7, 0x99, 0x4d, 0x29, 0x29,
+	0x6b, 0x6c, 0xcf, 0xf5, 0x4f, 0xd0, 0x3f, 0xc0, 0xc3, 0x6f, 0x2e, 0xa5, 0x9a, 0x79, 0xa8, 0x51,
+	0x58, 0x21, 0xff, 0x9d, 0x45, 0xff, 0x0c, 0xce, 0xb5, 0x2c, 0xcd, 0x14, 0x9e, 0xbd, 0xed, 0x8c,
+	0x82, 0x15, 0x7e, 0xb4, 0x5e, 0x2a, 0x4a, 0xff, 0x5c, 0xea, 0xa7, 0xfa, 0x42, 0xf4, 0x96, 0xcb,
+	0x1c, 0xc0, 0x66, 0xa4, 0x9a, 0xd3, 0xf3, 0xb5, 0xfb, 0xae, 0x01, 0x4a, 0x89, 0xec, 0xfb, 0xce,
+	0xda, 0x67, 0xa5, 0xe6, 0xea, 0x4f, 0xb7, 0x46, 0x07, 0x97, 0x6b, 0xf6, 0xdf, 0xb5, 0xcf, 0xff,
+	0x1f, 0x00, 0x4d, 0xba, 0x05, 0x63, 0xdb, 0x0d, 0x00, 0x00,
 }
@@ -36,6 +36,7 @@ import (
 	hooksInfo "kubevirt.io/kubevirt/pkg/hooks/info"
 	hooksV1alpha1 "kubevirt.io/kubevirt/pkg/hooks/v1alpha1"
 	hooksV1alpha2 "kubevirt.io/kubevirt/pkg/hooks/v1alpha2"
+	hooksV1alpha3 "kubevirt.io/kubevirt/pkg/hooks/v1alpha3"
 	grpcutil "kubevirt.io/kubevirt/pkg/util/net/grpc"
 	virtwrapApi "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 )
@@ -141,7 +142,13 @@ func processSideCarSocket(socketPath string) (*callBackClient, bool, error) {
 		versionsSet[version] = true
 	}
 
-	if _, found := versionsSet[hooksV1alpha2.Version]; found {
+	if _, found := versionsSet[hooksV1alpha3.Version]; found {
+		return &callBackClient{
+			SocketPath:           socketPath,
+			Version:              hooksV1alpha3.Version,
+			subscribedHookPoints: info.GetHookPoints(),
+		}, false, nil
+	} else if _, found := versionsSet[hooksV1alpha2.Version]; found {
 		return &callBackClient{
 			SocketPath:           socketPath,
 			Version:              hooksV1alpha2.Version,
@@ -156,7 +163,7 @@ func processSideCarSocket(socketPath string) (*callBackClient, bool, error) {
 	} else {
 		return nil, false,
 			fmt.Errorf("Hook sidecar does not expose a supported version. Exposed versions: %v, supported versions: %v",
-				info.GetVersions(), []string{hooksV1alpha1.Version, hooksV1alpha2.Version})
+				info.GetVersions(), []string{hooksV1alpha1.Version, hooksV1alpha2.Version, hooksV1alpha3.Version})
 	}
 }
 
@@ -181,7 +188,7 @@ func (m *Manager) OnDefineDomain(domainSpec *virtwrapApi.DomainSpec, vmi *v1.Vir
 	}
 	if callbacks, found := m.CallbacksPerHookPoint[hooksInfo.OnDefineDomainHookPointName]; found {
 		for _, callback := range callbacks {
-			if callback.Version == hooksV1alpha1.Version || callback.Version == hooksV1alpha2.Version {
+			if callback.Version == hooksV1alpha1.Version || callback.Version == hooksV1alpha2.Version || callback.Version == hooksV1alpha3.Version {
 				vmiJSON, err := json.Marshal(vmi)
 				if err != nil {
 					return "", fmt.Errorf("Failed to marshal VMI spec: %v", vmi)
@@ -218,6 +225,20 @@ func (m *Manager) OnDefineDomain(domainSpec *virtwrapApi.DomainSpec, vmi *v1.Vir
 						return "", err
 					}
 					domainSpecXML = result.GetDomainXML()
+				case hooksV1alpha3.Version:
+					client := hooksV1alpha3.NewCallbacksClient(conn)
+					result, err := client.OnDefineDomain(ctx, &hooksV1alpha3.OnDefineDomainParams{
+						DomainXML:         domainSpecXML,
+						Vmi:               vmiJSON,
+						ProtectedElements: ProtectedDomainElementPaths(),
+					})
+					if err != nil {
+						return "", err
+					}
+					if err := ValidateDomainXML(domainSpecXML, result.GetDomainXML()); err != nil {
+						return "", fmt.Errorf("hook sidecar %s returned an invalid domain specification: %v", callback.SocketPath, err)
+					}
+					domainSpecXML = result.GetDomainXML()
 				default:
 					panic("Should never happen, version compatibility check is done during Info call")
 				}
@@ -230,7 +251,9 @@ func (m *Manager) OnDefineDomain(domainSpec *virtwrapApi.DomainSpec, vmi *v1.Vir
 func (m *Manager) PreCloudInitIso(vmi *v1.VirtualMachineInstance, cloudInitData *cloudinit.CloudInitData) (*cloudinit.CloudInitData, error) {
 	if callbacks, found := m.CallbacksPerHookPoint[hooksInfo.PreCloudInitIsoHookPointName]; found {
 		for _, callback := range callbacks {
-			if callback.Version == hooksV1alpha2.Version {
+			if callback.Version == hooksV1alpha3.Version {
+				return preCloudInitIsoV1alpha3(callback, vmi, cloudInitData)
+			} else if callback.Version == hooksV1alpha2.Version {
 				var resultData *cloudinit.CloudInitData
 				vmiJSON, err := json.Marshal(vmi)
 				if err != nil {
@@ -299,3 +322,40 @@ func (m *Manager) PreCloudInitIso(vmi *v1.VirtualMachineInstance, cloudInitData
 	}
 	return cloudInitData, nil
 }
+
+func preCloudInitIsoV1alpha3(callback *callBackClient, vmi *v1.VirtualMachineInstance, cloudInitData *cloudinit.CloudInitData) (*cloudinit.CloudInitData, error) {
+	vmiJSON, err := json.Marshal(vmi)
+	if err != nil {
+		return cloudInitData, fmt.Errorf("Failed to marshal VMI spec: %v", vmi)
+	}
+	cloudInitDataJSON, err := json.Marshal(cloudInitData)
+	if err != nil {
+		return cloudInitData, fmt.Errorf("Failed to marshal CloudInitData: %v", cloudInitData)
+	}
+
+	conn, err := grpcutil.DialSocketWithTimeout(callback.SocketPath, 1)
+	if err != nil {
+		log.Log.Reason(err).Infof("Failed to Dial hook socket: %s", callback.SocketPath)
+		return cloudInitData, err
+	}
+	defer conn.Close()
+
+	client := hooksV1alpha3.NewCallbacksClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := client.PreCloudInitIso(ctx, &hooksV1alpha3.PreCloudInitIsoParams{
+		CloudInitData: cloudInitDataJSON,
+		Vmi:           vmiJSON,
+	})
+	if err != nil {
+		return cloudInitData, err
+	}
+
+	var resultData *cloudinit.CloudInitData
+	err = json.Unmarshal(result.GetCloudInitData(), &resultData)
+	if err != nil {
+		log.Log.Reason(err).Infof("Failed to unmarshal CloudInitData result")
+		return cloudInitData, err
+	}
+	return resultData, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hooks
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+
+	virtwrapApi "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+type protectedDomainElement struct {
+	path string
+	get  func(spec *virtwrapApi.DomainSpec) interface{}
+}
+
+// protectedDomainElements are the elements of the domain XML which hook sidecars must not change.
+// virt-launcher identifies the domain by its name and uuid and keeps its own state in the metadata,
+// host devices can not be migrated.
+var protectedDomainElements = []protectedDomainElement{
+	{path: "name", get: func(spec *virtwrapApi.DomainSpec) interface{} { return spec.Name }},
+	{path: "uuid", get: func(spec *virtwrapApi.DomainSpec) interface{} { return spec.UUID }},
+	{path: "metadata", get: func(spec *virtwrapApi.DomainSpec) interface{} { return spec.Metadata }},
+	{path: "devices/hostdev", get: func(spec *virtwrapApi.DomainSpec) interface{} { return spec.Devices.HostDevices }},
+}
+
+// ProtectedDomainElementPaths returns the paths of the domain XML elements which hook sidecars must not change
+func ProtectedDomainElementPaths() []string {
+	paths := make([]string, 0, len(protectedDomainElements))
+	for _, element := range protectedDomainElements {
+		paths = append(paths, element.path)
+	}
+	return paths
+}
+
+// ValidateDomainXML verifies that the domain XML returned by a hook sidecar is a valid domain
+// specification and that it does not change any protected element of the original domain XML
+func ValidateDomainXML(originalXML []byte, domainXML []byte) error {
+	original := &virtwrapApi.DomainSpec{}
+	if err := xml.Unmarshal(originalXML, original); err != nil {
+		return fmt.Errorf("failed to parse the original domain specification: %v", err)
+	}
+	mutated := &virtwrapApi.DomainSpec{}
+	if err := xml.Unmarshal(domainXML, mutated); err != nil {
+		return fmt.Errorf("the domain specification is not valid: %v", err)
+	}
+
+	if mutated.Type == "" {
+		return fmt.Errorf("the domain specification is not valid: the domain type is missing")
+	}
+	if mutated.Memory.Value == 0 {
+		return fmt.Errorf("the domain specification is not valid: the domain memory is missing")
+	}
+
+	for _, element := range protectedDomainElements {
+		if !reflect.DeepEqual(element.get(original), element.get(mutated)) {
+			return fmt.Errorf("the domain element %s must not be changed", element.path)
+		}
+	}
+	return nil
+}
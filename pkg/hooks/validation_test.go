@@ -0,0 +1,170 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hooks_test
+
+import (
+	"context"
+	"encoding/xml"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/hooks"
+	hooksInfo "kubevirt.io/kubevirt/pkg/hooks/info"
+	hooksV1alpha3 "kubevirt.io/kubevirt/pkg/hooks/v1alpha3"
+	virtwrapApi "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+type v1alpha3Server struct {
+	mutate            func(spec *virtwrapApi.DomainSpec)
+	protectedElements []string
+}
+
+func (s *v1alpha3Server) Info(ctx context.Context, params *hooksInfo.InfoParams) (*hooksInfo.InfoResult, error) {
+	return &hooksInfo.InfoResult{
+		Name:     "v1alpha3",
+		Versions: []string{hooksV1alpha3.Version},
+		HookPoints: []*hooksInfo.HookPoint{
+			{Name: hooksInfo.OnDefineDomainHookPointName},
+		},
+	}, nil
+}
+
+func (s *v1alpha3Server) OnDefineDomain(ctx context.Context, params *hooksV1alpha3.OnDefineDomainParams) (*hooksV1alpha3.OnDefineDomainResult, error) {
+	s.protectedElements = params.GetProtectedElements()
+	spec := &virtwrapApi.DomainSpec{}
+	if err := xml.Unmarshal(params.GetDomainXML(), spec); err != nil {
+		return nil, err
+	}
+	s.mutate(spec)
+	domainXML, err := xml.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &hooksV1alpha3.OnDefineDomainResult{DomainXML: domainXML}, nil
+}
+
+func (s *v1alpha3Server) PreCloudInitIso(ctx context.Context, params *hooksV1alpha3.PreCloudInitIsoParams) (*hooksV1alpha3.PreCloudInitIsoResult, error) {
+	return &hooksV1alpha3.PreCloudInitIsoResult{CloudInitData: params.GetCloudInitData()}, nil
+}
+
+var _ = Describe("Domain XML validation", func() {
+
+	newDomainSpec := func() *virtwrapApi.DomainSpec {
+		return &virtwrapApi.DomainSpec{
+			Type:   "kvm",
+			Name:   "default_testvmi",
+			UUID:   "c7c4a5ad-4f4d-4b0e-9e7a-3d4b9bc3a4f2",
+			Memory: virtwrapApi.Memory{Value: 64, Unit: "MiB"},
+			Metadata: virtwrapApi.Metadata{
+				KubeVirt: virtwrapApi.KubeVirtMetadata{UID: "1234"},
+			},
+		}
+	}
+
+	marshal := func(spec *virtwrapApi.DomainSpec) []byte {
+		domainXML, err := xml.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+		return domainXML
+	}
+
+	It("should accept changes of unprotected elements", func() {
+		spec := newDomainSpec()
+		mutated := newDomainSpec()
+		mutated.Devices.Serials = []virtwrapApi.Serial{{Type: "pty"}}
+
+		Expect(hooks.ValidateDomainXML(marshal(spec), marshal(mutated))).To(Succeed())
+	})
+
+	table.DescribeTable("should reject", func(mutate func(spec *virtwrapApi.DomainSpec), message string) {
+		spec := newDomainSpec()
+		mutated := newDomainSpec()
+		mutate(mutated)
+
+		err := hooks.ValidateDomainXML(marshal(spec), marshal(mutated))
+		Expect(err).To(MatchError(ContainSubstring(message)))
+	},
+		table.Entry("a changed name", func(spec *virtwrapApi.DomainSpec) { spec.Name = "other" }, "name must not be changed"),
+		table.Entry("a changed uuid", func(spec *virtwrapApi.DomainSpec) { spec.UUID = "" }, "uuid must not be changed"),
+		table.Entry("changed metadata", func(spec *virtwrapApi.DomainSpec) { spec.Metadata.KubeVirt.UID = "4321" }, "metadata must not be changed"),
+		table.Entry("added host devices", func(spec *virtwrapApi.DomainSpec) {
+			spec.Devices.HostDevices = []virtwrapApi.HostDevice{{Type: "pci", Managed: "yes"}}
+		}, "devices/hostdev must not be changed"),
+		table.Entry("a missing domain type", func(spec *virtwrapApi.DomainSpec) { spec.Type = "" }, "domain type is missing"),
+	)
+
+	It("should reject a domain XML which can not be parsed", func() {
+		err := hooks.ValidateDomainXML(marshal(newDomainSpec()), []byte("<domain>"))
+		Expect(err).To(MatchError(ContainSubstring("not valid")))
+	})
+
+	Context("with a v1alpha3 sidecar", func() {
+		var server *v1alpha3Server
+		var socket net.Listener
+		socketPath := filepath.Join(hooks.HookSocketsSharedDirectory, "v1alpha3.sock")
+
+		BeforeEach(func() {
+			os.MkdirAll(hooks.HookSocketsSharedDirectory, os.ModePerm)
+			var err error
+			socket, err = net.Listen("unix", socketPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			server = &v1alpha3Server{}
+			grpcServer := grpc.NewServer()
+			hooksInfo.RegisterInfoServer(grpcServer, server)
+			hooksV1alpha3.RegisterCallbacksServer(grpcServer, server)
+			go grpcServer.Serve(socket)
+
+			Expect(hooks.GetManager().Collect(1, 10*time.Second)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			socket.Close()
+			os.Remove(socketPath)
+		})
+
+		It("should pass the protected elements and accept valid changes", func() {
+			server.mutate = func(spec *virtwrapApi.DomainSpec) {
+				spec.Devices.Serials = []virtwrapApi.Serial{{Type: "pty"}}
+			}
+
+			domainXML, err := hooks.GetManager().OnDefineDomain(newDomainSpec(), v1.NewMinimalVMI("testvmi"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(domainXML).To(ContainSubstring("pty"))
+			Expect(server.protectedElements).To(Equal(hooks.ProtectedDomainElementPaths()))
+		})
+
+		It("should reject changes of protected elements", func() {
+			server.mutate = func(spec *virtwrapApi.DomainSpec) {
+				spec.Devices.HostDevices = []virtwrapApi.HostDevice{{Type: "pci", Managed: "yes"}}
+			}
+
+			_, err := hooks.GetManager().OnDefineDomain(newDomainSpec(), v1.NewMinimalVMI("testvmi"))
+			Expect(err).To(MatchError(ContainSubstring("devices/hostdev must not be changed")))
+		})
+	})
+})
@@ -52,8 +52,16 @@ func setDiskDirectory(dir string) error {
 func ReplacePVCByHostDisk(vmi *v1.VirtualMachineInstance, clientset kubecli.KubevirtClient) error {
 	// If PVC is defined and it's not a BlockMode PVC, then it is replaced by HostDisk
 	// Filesystem PersistenVolumeClaim is mounted into pod as directory from node filesystem
+	sharedFilesystems := map[string]bool{}
+	for _, fs := range vmi.Spec.Domain.Devices.Filesystems {
+		sharedFilesystems[fs.Name] = true
+	}
 	for i := range vmi.Spec.Volumes {
 		if volumeSource := &vmi.Spec.Volumes[i].VolumeSource; volumeSource.PersistentVolumeClaim != nil {
+			// A PVC shared through virtio-fs is passed to the guest as a directory
+			if sharedFilesystems[vmi.Spec.Volumes[i].Name] {
+				continue
+			}
 
 			pvc, exists, isBlockVolumePVC, err := types.IsPVCBlockFromClient(clientset, vmi.Namespace, volumeSource.PersistentVolumeClaim.ClaimName)
 			if err != nil {
@@ -383,6 +383,21 @@ var _ = Describe("HostDisk", func() {
 			table.Entry("filemode", k8sv1.PersistentVolumeFilesystem),
 			table.Entry("blockmode", k8sv1.PersistentVolumeBlock),
 		)
+
+		It("should keep a PVC which is shared through virtio-fs", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Volumes = []v1.Volume{{
+				Name: "shared",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "claim"},
+				},
+			}}
+			vmi.Spec.Domain.Devices.Filesystems = []v1.Filesystem{{Name: "shared", Virtiofs: &v1.FilesystemVirtiofs{}}}
+
+			Expect(ReplacePVCByHostDisk(vmi, virtClient)).To(Succeed())
+			Expect(vmi.Spec.Volumes[0].HostDisk).To(BeNil())
+			Expect(vmi.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("claim"))
+		})
 	})
 
 })
@@ -24,6 +24,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -33,13 +35,78 @@ import (
 
 var ignitionLocalDir = "/var/run/libvirt/ignition-dir"
 
-const IgnitionFile = "data.ign"
+const (
+	IgnitionFile = "data.ign"
+	// ConfigKey is the key of the Ignition config in the referenced secrets and config maps
+	ConfigKey = "config.ign"
+	// MaxConfigSize is the size limit of Ignition configs read from secrets and config maps
+	MaxConfigSize = 1024 * 1024
+)
 
+// GetIgnitionSource returns the Ignition config of an Ignition volume, or of the Ignition annotation
+// if the vmi has no such volume. Configs referenced by a volume are only available after
+// ResolveIgnitionVolume was called.
 func GetIgnitionSource(vmi *v1.VirtualMachineInstance) string {
 	precond.MustNotBeNil(vmi)
+	if volume := GetIgnitionVolume(vmi); volume != nil {
+		return volume.Ignition.Data
+	}
 	return vmi.Annotations[v1.IgnitionAnnotation]
 }
 
+// HasIgnitionSource tells whether an Ignition config is passed to the guest
+func HasIgnitionSource(vmi *v1.VirtualMachineInstance) bool {
+	if GetIgnitionVolume(vmi) != nil {
+		return true
+	}
+	ignitionData := vmi.Annotations[v1.IgnitionAnnotation]
+	return ignitionData != "" && strings.Contains(ignitionData, "ignition")
+}
+
+// GetIgnitionVolume returns the first volume with an Ignition source
+func GetIgnitionVolume(vmi *v1.VirtualMachineInstance) *v1.Volume {
+	for i := range vmi.Spec.Volumes {
+		if vmi.Spec.Volumes[i].Ignition != nil {
+			return &vmi.Spec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+// ResolveIgnitionVolume reads the Ignition config referenced by the Ignition volume from
+// the mounted secret or config map and sets the Data field on that volume.
+//
+// Note: when using this function, make sure that your code can access the secret and config map volumes.
+func ResolveIgnitionVolume(vmi *v1.VirtualMachineInstance, secretSourceDir string, configMapSourceDir string) error {
+	volume := GetIgnitionVolume(vmi)
+	if volume == nil {
+		return nil
+	}
+
+	var configFile string
+	if volume.Ignition.SecretRef != nil {
+		configFile = filepath.Join(secretSourceDir, volume.Name, ConfigKey)
+	} else if volume.Ignition.ConfigMapRef != nil {
+		configFile = filepath.Join(configMapSourceDir, volume.Name, ConfigKey)
+	} else {
+		return nil
+	}
+
+	info, err := os.Stat(configFile)
+	if err != nil {
+		return fmt.Errorf("no Ignition config found at volume %s: %v", volume.Name, err)
+	}
+	if info.Size() > MaxConfigSize {
+		return fmt.Errorf("the Ignition config of volume %s exceeds the %d byte limit", volume.Name, MaxConfigSize)
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the Ignition config of volume %s: %v", volume.Name, err)
+	}
+	volume.Ignition.Data = string(data)
+	return nil
+}
+
 func SetLocalDirectory(dir string) error {
 	err := os.MkdirAll(dir, 0755)
 	if err != nil {
@@ -63,7 +130,6 @@ func GetDomainBasePath(domain string, namespace string) string {
 
 func GenerateIgnitionLocalData(vmi *v1.VirtualMachineInstance, namespace string) error {
 	precond.MustNotBeEmpty(vmi.Name)
-	precond.MustNotBeNil(vmi.Annotations[v1.IgnitionAnnotation])
 
 	domainBasePath := GetDomainBasePath(vmi.Name, namespace)
 	err := os.MkdirAll(domainBasePath, 0755)
@@ -73,7 +139,7 @@ func GenerateIgnitionLocalData(vmi *v1.VirtualMachineInstance, namespace string)
 	}
 
 	ignitionFile := fmt.Sprintf("%s/%s", domainBasePath, IgnitionFile)
-	ignitionData := []byte(vmi.Annotations[v1.IgnitionAnnotation])
+	ignitionData := []byte(GetIgnitionSource(vmi))
 	err = ioutil.WriteFile(ignitionFile, ignitionData, 0644)
 	if err != nil {
 		return err
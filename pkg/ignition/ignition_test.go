@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
 
 	v1 "kubevirt.io/client-go/api/v1"
 )
@@ -61,5 +63,78 @@ var _ = Describe("Ignition", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+
+		Context("with an ignition volume", func() {
+			var secretSourceDir string
+			var configMapSourceDir string
+
+			newIgnitionVMI := func(source *v1.IgnitionSource) *v1.VirtualMachineInstance {
+				vmi := v1.NewMinimalVMI(vmName)
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+					Name:         "ignition",
+					VolumeSource: v1.VolumeSource{Ignition: source},
+				})
+				return vmi
+			}
+
+			writeConfig := func(baseDir string, data []byte) {
+				Expect(os.MkdirAll(filepath.Join(baseDir, "ignition"), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(baseDir, "ignition", ConfigKey), data, 0644)).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				var err error
+				secretSourceDir, err = ioutil.TempDir("", "ignition-secret")
+				Expect(err).ToNot(HaveOccurred())
+				configMapSourceDir, err = ioutil.TempDir("", "ignition-configmap")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(secretSourceDir)
+				os.RemoveAll(configMapSourceDir)
+			})
+
+			It("should prefer the volume over the annotation", func() {
+				vmi := newIgnitionVMI(&v1.IgnitionSource{Data: "volume-data"})
+				vmi.Annotations = map[string]string{v1.IgnitionAnnotation: "annotation-data"}
+				Expect(HasIgnitionSource(vmi)).To(BeTrue())
+				Expect(GetIgnitionSource(vmi)).To(Equal("volume-data"))
+			})
+
+			It("should resolve the config from a secret", func() {
+				writeConfig(secretSourceDir, []byte("secret-data"))
+				vmi := newIgnitionVMI(&v1.IgnitionSource{SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"}})
+
+				Expect(ResolveIgnitionVolume(vmi, secretSourceDir, configMapSourceDir)).To(Succeed())
+				Expect(GetIgnitionSource(vmi)).To(Equal("secret-data"))
+
+				Expect(GenerateIgnitionLocalData(vmi, namespace)).To(Succeed())
+				data, err := ioutil.ReadFile(filepath.Join(GetDomainBasePath(vmName, namespace), IgnitionFile))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(Equal("secret-data"))
+			})
+
+			It("should resolve the config from a config map", func() {
+				writeConfig(configMapSourceDir, []byte("configmap-data"))
+				vmi := newIgnitionVMI(&v1.IgnitionSource{ConfigMapRef: &k8sv1.LocalObjectReference{Name: "ignition-config"}})
+
+				Expect(ResolveIgnitionVolume(vmi, secretSourceDir, configMapSourceDir)).To(Succeed())
+				Expect(GetIgnitionSource(vmi)).To(Equal("configmap-data"))
+			})
+
+			It("should fail if the config is missing", func() {
+				vmi := newIgnitionVMI(&v1.IgnitionSource{SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"}})
+				err := ResolveIgnitionVolume(vmi, secretSourceDir, configMapSourceDir)
+				Expect(err).To(MatchError(ContainSubstring("no Ignition config found at volume ignition")))
+			})
+
+			It("should fail if the config exceeds the size limit", func() {
+				writeConfig(secretSourceDir, make([]byte, MaxConfigSize+1))
+				vmi := newIgnitionVMI(&v1.IgnitionSource{SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"}})
+				err := ResolveIgnitionVolume(vmi, secretSourceDir, configMapSourceDir)
+				Expect(err).To(MatchError(ContainSubstring("exceeds the 1048576 byte limit")))
+			})
+		})
 	})
 })
@@ -0,0 +1,295 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package instancetype
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// Conflicts are the fields of a VirtualMachineInstance which are set
+// although the VirtualMachineInstancetype owns them
+type Conflicts []*k8sfield.Path
+
+func (c Conflicts) String() string {
+	paths := make([]string, 0, len(c))
+	for _, path := range c {
+		paths = append(paths, path.String())
+	}
+	return strings.Join(paths, ", ")
+}
+
+// FindInstancetypeSpec returns the spec of the VirtualMachineInstancetype referenced by the VirtualMachine.
+// Once the VirtualMachine is pinned to a ControllerRevision, the spec is taken from the revision.
+func FindInstancetypeSpec(clientset kubecli.KubevirtClient, vm *v1.VirtualMachine) (*v1.VirtualMachineInstancetypeSpec, error) {
+	if vm.Spec.Instancetype == nil {
+		return nil, nil
+	}
+
+	instancetype := &v1.VirtualMachineInstancetype{}
+	if vm.Spec.Instancetype.RevisionName != "" {
+		if err := getFromControllerRevision(clientset, vm.Namespace, vm.Spec.Instancetype.RevisionName, instancetype); err != nil {
+			return nil, err
+		}
+		return &instancetype.Spec, nil
+	}
+
+	instancetype, err := clientset.VirtualMachineInstancetype(vm.Namespace).Get(vm.Spec.Instancetype.Name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &instancetype.Spec, nil
+}
+
+// FindPreferenceSpec returns the spec of the VirtualMachinePreference referenced by the VirtualMachine.
+// Once the VirtualMachine is pinned to a ControllerRevision, the spec is taken from the revision.
+func FindPreferenceSpec(clientset kubecli.KubevirtClient, vm *v1.VirtualMachine) (*v1.VirtualMachinePreferenceSpec, error) {
+	if vm.Spec.Preference == nil {
+		return nil, nil
+	}
+
+	preference := &v1.VirtualMachinePreference{}
+	if vm.Spec.Preference.RevisionName != "" {
+		if err := getFromControllerRevision(clientset, vm.Namespace, vm.Spec.Preference.RevisionName, preference); err != nil {
+			return nil, err
+		}
+		return &preference.Spec, nil
+	}
+
+	preference, err := clientset.VirtualMachinePreference(vm.Namespace).Get(vm.Spec.Preference.Name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &preference.Spec, nil
+}
+
+func getFromControllerRevision(clientset kubecli.KubevirtClient, namespace string, name string, obj interface{}) error {
+	revision, err := clientset.AppsV1().ControllerRevisions(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(revision.Data.Raw, obj); err != nil {
+		return fmt.Errorf("failed to decode ControllerRevision %s: %v", name, err)
+	}
+	return nil
+}
+
+// StoreControllerRevisions copies the VirtualMachineInstancetype and VirtualMachinePreference referenced by
+// the VirtualMachine into ControllerRevisions and pins the VirtualMachine to them, so that later changes of
+// either don't affect the VirtualMachine. It returns the updated VirtualMachine.
+func StoreControllerRevisions(clientset kubecli.KubevirtClient, vm *v1.VirtualMachine) (*v1.VirtualMachine, error) {
+	spec := map[string]interface{}{}
+
+	if vm.Spec.Instancetype != nil && vm.Spec.Instancetype.RevisionName == "" {
+		instancetype, err := clientset.VirtualMachineInstancetype(vm.Namespace).Get(vm.Spec.Instancetype.Name, &metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		instancetype.TypeMeta = metav1.TypeMeta{
+			APIVersion: v1.VirtualMachineInstancetypeGroupVersionKind.GroupVersion().String(),
+			Kind:       v1.VirtualMachineInstancetypeGroupVersionKind.Kind,
+		}
+		revisionName, err := storeControllerRevision(clientset, vm, "instancetype", &instancetype.ObjectMeta, instancetype)
+		if err != nil {
+			return nil, err
+		}
+		spec["instancetype"] = map[string]interface{}{"revisionName": revisionName}
+	}
+
+	if vm.Spec.Preference != nil && vm.Spec.Preference.RevisionName == "" {
+		preference, err := clientset.VirtualMachinePreference(vm.Namespace).Get(vm.Spec.Preference.Name, &metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		preference.TypeMeta = metav1.TypeMeta{
+			APIVersion: v1.VirtualMachinePreferenceGroupVersionKind.GroupVersion().String(),
+			Kind:       v1.VirtualMachinePreferenceGroupVersionKind.Kind,
+		}
+		revisionName, err := storeControllerRevision(clientset, vm, "preference", &preference.ObjectMeta, preference)
+		if err != nil {
+			return nil, err
+		}
+		spec["preference"] = map[string]interface{}{"revisionName": revisionName}
+	}
+
+	if len(spec) == 0 {
+		return vm, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return nil, err
+	}
+	return clientset.VirtualMachine(vm.Namespace).Patch(vm.Name, types.MergePatchType, patch)
+}
+
+func storeControllerRevision(clientset kubecli.KubevirtClient, vm *v1.VirtualMachine, kind string, objMeta *metav1.ObjectMeta, obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-%s-%d", vm.Name, kind, objMeta.Name, objMeta.Generation),
+			Namespace: vm.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(vm, v1.VirtualMachineGroupVersionKind),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: objMeta.Generation,
+	}
+
+	_, err = clientset.AppsV1().ControllerRevisions(vm.Namespace).Create(revision)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create ControllerRevision for %s %s: %v", kind, objMeta.Name, err)
+	}
+	return revision.Name, nil
+}
+
+// ApplyToVmi expands the VirtualMachineInstancetype and the VirtualMachinePreference into the spec of a
+// VirtualMachineInstance. Nothing is applied if the spec sets fields owned by the VirtualMachineInstancetype,
+// these fields are returned as conflicts below the given path instead.
+func ApplyToVmi(field *k8sfield.Path, instancetypeSpec *v1.VirtualMachineInstancetypeSpec, preferenceSpec *v1.VirtualMachinePreferenceSpec, vmiSpec *v1.VirtualMachineInstanceSpec) Conflicts {
+	if instancetypeSpec != nil {
+		if conflicts := findConflicts(field, vmiSpec); len(conflicts) > 0 {
+			return conflicts
+		}
+		applyCPU(instancetypeSpec, preferenceSpec, vmiSpec)
+		applyMemory(instancetypeSpec, vmiSpec)
+	}
+
+	if preferenceSpec != nil {
+		applyDevicePreferences(preferenceSpec, vmiSpec)
+		applyMachinePreferences(preferenceSpec, vmiSpec)
+	}
+
+	return nil
+}
+
+func findConflicts(field *k8sfield.Path, vmiSpec *v1.VirtualMachineInstanceSpec) Conflicts {
+	var conflicts Conflicts
+	domain := field.Child("domain")
+
+	if vmiSpec.Domain.CPU != nil {
+		conflicts = append(conflicts, domain.Child("cpu"))
+	}
+	if vmiSpec.Domain.Memory != nil {
+		conflicts = append(conflicts, domain.Child("memory"))
+	}
+	for _, name := range []k8sv1.ResourceName{k8sv1.ResourceCPU, k8sv1.ResourceMemory} {
+		if _, exists := vmiSpec.Domain.Resources.Requests[name]; exists {
+			conflicts = append(conflicts, domain.Child("resources", "requests", string(name)))
+		}
+		if _, exists := vmiSpec.Domain.Resources.Limits[name]; exists {
+			conflicts = append(conflicts, domain.Child("resources", "limits", string(name)))
+		}
+	}
+
+	return conflicts
+}
+
+func applyCPU(instancetypeSpec *v1.VirtualMachineInstancetypeSpec, preferenceSpec *v1.VirtualMachinePreferenceSpec, vmiSpec *v1.VirtualMachineInstanceSpec) {
+	vmiSpec.Domain.CPU = &v1.CPU{
+		Sockets:               1,
+		Cores:                 1,
+		Threads:               1,
+		Model:                 instancetypeSpec.CPU.Model,
+		DedicatedCPUPlacement: instancetypeSpec.CPU.DedicatedCPUPlacement,
+	}
+
+	topology := v1.PreferSockets
+	if preferenceSpec != nil && preferenceSpec.CPU != nil && preferenceSpec.CPU.PreferredCPUTopology != "" {
+		topology = preferenceSpec.CPU.PreferredCPUTopology
+	}
+
+	switch topology {
+	case v1.PreferCores:
+		vmiSpec.Domain.CPU.Cores = instancetypeSpec.CPU.Guest
+	case v1.PreferThreads:
+		vmiSpec.Domain.CPU.Threads = instancetypeSpec.CPU.Guest
+	default:
+		vmiSpec.Domain.CPU.Sockets = instancetypeSpec.CPU.Guest
+	}
+}
+
+func applyMemory(instancetypeSpec *v1.VirtualMachineInstancetypeSpec, vmiSpec *v1.VirtualMachineInstanceSpec) {
+	guest := instancetypeSpec.Memory.Guest.DeepCopy()
+	vmiSpec.Domain.Memory = &v1.Memory{
+		Guest: &guest,
+	}
+	if instancetypeSpec.Memory.Hugepages != nil {
+		vmiSpec.Domain.Memory.Hugepages = instancetypeSpec.Memory.Hugepages.DeepCopy()
+	}
+}
+
+func applyDevicePreferences(preferenceSpec *v1.VirtualMachinePreferenceSpec, vmiSpec *v1.VirtualMachineInstanceSpec) {
+	if preferenceSpec.Devices == nil {
+		return
+	}
+	devices := &vmiSpec.Domain.Devices
+
+	if preferenceSpec.Devices.PreferredAutoattachGraphicsDevice != nil && devices.AutoattachGraphicsDevice == nil {
+		autoattach := *preferenceSpec.Devices.PreferredAutoattachGraphicsDevice
+		devices.AutoattachGraphicsDevice = &autoattach
+	}
+
+	if preferenceSpec.Devices.PreferredDiskBus != "" {
+		for i := range devices.Disks {
+			disk := &devices.Disks[i]
+			if disk.Disk == nil && disk.LUN == nil && disk.Floppy == nil && disk.CDRom == nil {
+				disk.Disk = &v1.DiskTarget{}
+			}
+			if disk.Disk != nil && disk.Disk.Bus == "" {
+				disk.Disk.Bus = preferenceSpec.Devices.PreferredDiskBus
+			}
+		}
+	}
+
+	if preferenceSpec.Devices.PreferredInterfaceModel != "" {
+		for i := range devices.Interfaces {
+			if devices.Interfaces[i].Model == "" {
+				devices.Interfaces[i].Model = preferenceSpec.Devices.PreferredInterfaceModel
+			}
+		}
+	}
+}
+
+func applyMachinePreferences(preferenceSpec *v1.VirtualMachinePreferenceSpec, vmiSpec *v1.VirtualMachineInstanceSpec) {
+	if preferenceSpec.Machine == nil {
+		return
+	}
+	if vmiSpec.Domain.Machine.Type == "" {
+		vmiSpec.Domain.Machine.Type = preferenceSpec.Machine.PreferredMachineType
+	}
+}
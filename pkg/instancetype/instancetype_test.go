@@ -0,0 +1,220 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package instancetype
+
+import (
+	"encoding/json"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+var _ = Describe("Instancetype", func() {
+
+	var ctrl *gomock.Controller
+	var virtClient *kubecli.MockKubevirtClient
+	var instancetypeInterface *kubecli.MockVirtualMachineInstancetypeInterface
+	var preferenceInterface *kubecli.MockVirtualMachinePreferenceInterface
+	var vmInterface *kubecli.MockVirtualMachineInterface
+	var kubeClient *fake.Clientset
+	var vm *v1.VirtualMachine
+	var instancetypeObj *v1.VirtualMachineInstancetype
+	var preferenceObj *v1.VirtualMachinePreference
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		instancetypeInterface = kubecli.NewMockVirtualMachineInstancetypeInterface(ctrl)
+		preferenceInterface = kubecli.NewMockVirtualMachinePreferenceInterface(ctrl)
+		vmInterface = kubecli.NewMockVirtualMachineInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+
+		virtClient.EXPECT().VirtualMachineInstancetype(k8sv1.NamespaceDefault).Return(instancetypeInterface).AnyTimes()
+		virtClient.EXPECT().VirtualMachinePreference(k8sv1.NamespaceDefault).Return(preferenceInterface).AnyTimes()
+		virtClient.EXPECT().VirtualMachine(k8sv1.NamespaceDefault).Return(vmInterface).AnyTimes()
+		virtClient.EXPECT().AppsV1().Return(kubeClient.AppsV1()).AnyTimes()
+
+		instancetypeObj = &v1.VirtualMachineInstancetype{
+			ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: k8sv1.NamespaceDefault, Generation: 2},
+			Spec: v1.VirtualMachineInstancetypeSpec{
+				CPU:    v1.CPUInstancetype{Guest: 2},
+				Memory: v1.MemoryInstancetype{Guest: resource.MustParse("1Gi")},
+			},
+		}
+		preferenceObj = &v1.VirtualMachinePreference{
+			ObjectMeta: metav1.ObjectMeta{Name: "linux", Namespace: k8sv1.NamespaceDefault, Generation: 1},
+			Spec: v1.VirtualMachinePreferenceSpec{
+				Machine: &v1.MachinePreferences{PreferredMachineType: "q35"},
+			},
+		}
+
+		vm = &v1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: k8sv1.NamespaceDefault, UID: "vm-uid"},
+			Spec: v1.VirtualMachineSpec{
+				Instancetype: &v1.InstancetypeMatcher{Name: "small"},
+				Preference:   &v1.PreferenceMatcher{Name: "linux"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("ControllerRevisions", func() {
+
+		It("should store the instancetype and preference and pin the VirtualMachine to them", func() {
+			instancetypeInterface.EXPECT().Get("small", gomock.Any()).Return(instancetypeObj, nil)
+			preferenceInterface.EXPECT().Get("linux", gomock.Any()).Return(preferenceObj, nil)
+			vmInterface.EXPECT().Patch("testvm", types.MergePatchType, gomock.Any()).DoAndReturn(
+				func(name string, pt types.PatchType, data []byte, subresources ...string) (*v1.VirtualMachine, error) {
+					Expect(string(data)).To(Equal(`{"spec":{"instancetype":{"revisionName":"testvm-instancetype-small-2"},"preference":{"revisionName":"testvm-preference-linux-1"}}}`))
+					return vm, nil
+				})
+
+			_, err := StoreControllerRevisions(virtClient, vm)
+			Expect(err).ToNot(HaveOccurred())
+
+			revision, err := kubeClient.AppsV1().ControllerRevisions(k8sv1.NamespaceDefault).Get("testvm-instancetype-small-2", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revision.OwnerReferences).To(HaveLen(1))
+			Expect(revision.OwnerReferences[0].Name).To(Equal("testvm"))
+			Expect(revision.Revision).To(Equal(int64(2)))
+
+			_, err = kubeClient.AppsV1().ControllerRevisions(k8sv1.NamespaceDefault).Get("testvm-preference-linux-1", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not touch a VirtualMachine which is already pinned", func() {
+			vm.Spec.Instancetype.RevisionName = "testvm-instancetype-small-2"
+			vm.Spec.Preference.RevisionName = "testvm-preference-linux-1"
+
+			updated, err := StoreControllerRevisions(virtClient, vm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(Equal(vm))
+		})
+
+		It("should read the instancetype from the pinned ControllerRevision", func() {
+			data, err := json.Marshal(instancetypeObj)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = kubeClient.AppsV1().ControllerRevisions(k8sv1.NamespaceDefault).Create(&appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{Name: "testvm-instancetype-small-2", Namespace: k8sv1.NamespaceDefault},
+				Data:       runtime.RawExtension{Raw: data},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			vm.Spec.Instancetype.RevisionName = "testvm-instancetype-small-2"
+
+			spec, err := FindInstancetypeSpec(virtClient, vm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.CPU.Guest).To(Equal(uint32(2)))
+			Expect(spec.Memory.Guest.String()).To(Equal("1Gi"))
+		})
+
+		It("should read the preference from the cluster if the VirtualMachine is not pinned yet", func() {
+			preferenceInterface.EXPECT().Get("linux", gomock.Any()).Return(preferenceObj, nil)
+
+			spec, err := FindPreferenceSpec(virtClient, vm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.Machine.PreferredMachineType).To(Equal("q35"))
+		})
+	})
+
+	Context("ApplyToVmi", func() {
+
+		var field *k8sfield.Path
+		var vmiSpec *v1.VirtualMachineInstanceSpec
+
+		BeforeEach(func() {
+			field = k8sfield.NewPath("spec", "template", "spec")
+			vmiSpec = &v1.VirtualMachineInstanceSpec{}
+			vmiSpec.Domain.Devices.Disks = []v1.Disk{
+				{Name: "disk0"},
+				{Name: "disk1", DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "sata"}}},
+				{Name: "cdrom", DiskDevice: v1.DiskDevice{CDRom: &v1.CDRomTarget{}}},
+			}
+			vmiSpec.Domain.Devices.Interfaces = []v1.Interface{{Name: "default"}, {Name: "other", Model: "e1000"}}
+		})
+
+		DescribeTable("should arrange the vCPUs by the preferred topology", func(topology v1.PreferredCPUTopology, sockets, cores, threads uint32) {
+			preferenceObj.Spec.CPU = &v1.CPUPreferences{PreferredCPUTopology: topology}
+
+			Expect(ApplyToVmi(field, &instancetypeObj.Spec, &preferenceObj.Spec, vmiSpec)).To(BeEmpty())
+			Expect(vmiSpec.Domain.CPU.Sockets).To(Equal(sockets))
+			Expect(vmiSpec.Domain.CPU.Cores).To(Equal(cores))
+			Expect(vmiSpec.Domain.CPU.Threads).To(Equal(threads))
+		},
+			Entry("by default", v1.PreferredCPUTopology(""), uint32(2), uint32(1), uint32(1)),
+			Entry("as sockets", v1.PreferSockets, uint32(2), uint32(1), uint32(1)),
+			Entry("as cores", v1.PreferCores, uint32(1), uint32(2), uint32(1)),
+			Entry("as threads", v1.PreferThreads, uint32(1), uint32(1), uint32(2)),
+		)
+
+		It("should apply the memory of the instancetype", func() {
+			instancetypeObj.Spec.Memory.Hugepages = &v1.Hugepages{PageSize: "2Mi"}
+
+			Expect(ApplyToVmi(field, &instancetypeObj.Spec, nil, vmiSpec)).To(BeEmpty())
+			Expect(vmiSpec.Domain.Memory.Guest.String()).To(Equal("1Gi"))
+			Expect(vmiSpec.Domain.Memory.Hugepages.PageSize).To(Equal("2Mi"))
+		})
+
+		It("should only apply preferences to fields which are unset", func() {
+			preferenceObj.Spec.Devices = &v1.DevicePreferences{
+				PreferredAutoattachGraphicsDevice: &[]bool{false}[0],
+				PreferredDiskBus:                  "virtio",
+				PreferredInterfaceModel:           "virtio",
+			}
+
+			Expect(ApplyToVmi(field, nil, &preferenceObj.Spec, vmiSpec)).To(BeEmpty())
+			Expect(*vmiSpec.Domain.Devices.AutoattachGraphicsDevice).To(BeFalse())
+			Expect(vmiSpec.Domain.Devices.Disks[0].Disk.Bus).To(Equal("virtio"))
+			Expect(vmiSpec.Domain.Devices.Disks[1].Disk.Bus).To(Equal("sata"))
+			Expect(vmiSpec.Domain.Devices.Disks[2].Disk).To(BeNil())
+			Expect(vmiSpec.Domain.Devices.Interfaces[0].Model).To(Equal("virtio"))
+			Expect(vmiSpec.Domain.Devices.Interfaces[1].Model).To(Equal("e1000"))
+			Expect(vmiSpec.Domain.Machine.Type).To(Equal("q35"))
+			Expect(vmiSpec.Domain.CPU).To(BeNil())
+		})
+
+		It("should report fields which override the instancetype", func() {
+			vmiSpec.Domain.CPU = &v1.CPU{Cores: 4}
+			vmiSpec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("2Gi"),
+			}
+
+			conflicts := ApplyToVmi(field, &instancetypeObj.Spec, &preferenceObj.Spec, vmiSpec)
+			Expect(conflicts.String()).To(Equal("spec.template.spec.domain.cpu, spec.template.spec.domain.resources.requests.memory"))
+			Expect(vmiSpec.Domain.CPU.Cores).To(Equal(uint32(4)))
+			Expect(vmiSpec.Domain.Memory).To(BeNil())
+		})
+	})
+})
@@ -23,90 +23,131 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/net/context"
+
 	k6tv1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
+	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
 )
 
-const collectionTimeout = 10 * time.Second // "long enough", crude heuristic
+// statsStreamInterval is how often the launchers push the stats of their domain
+const statsStreamInterval = 10 * time.Second
+
+// statsStaleAfter is how old a sample gets when the launcher misses two pushes in a row, e.g. because libvirt hangs
+const statsStaleAfter = 3 * statsStreamInterval
 
 type vmiSocketMap map[string]*k6tv1.VirtualMachineInstance
 
-type metricsScraper interface {
-	Scrape(key string, vmi *k6tv1.VirtualMachineInstance)
+type launcherClientFactory func(socketFile string) (cmdclient.LauncherClient, error)
+
+// domainStatsCache keeps a stream of domain stats open to every launcher, and the latest sample of each
+type domainStatsCache struct {
+	lock      sync.Mutex
+	streams   map[string]*domainStatsStream
+	interval  time.Duration
+	newClient launcherClientFactory
+	now       func() time.Time
 }
 
-type concurrentCollector struct {
-	lock             sync.Mutex
-	clientsPerKey    map[string]int
-	maxClientsPerKey int
+type domainStatsStream struct {
+	cancel     context.CancelFunc
+	stats      *stats.DomainStats
+	receivedAt time.Time
 }
 
-func NewConcurrentCollector(MaxRequestsPerKey int) *concurrentCollector {
-	return &concurrentCollector{
-		clientsPerKey:    make(map[string]int),
-		maxClientsPerKey: MaxRequestsPerKey,
+func newDomainStatsCache(interval time.Duration, newClient launcherClientFactory) *domainStatsCache {
+	return &domainStatsCache{
+		streams:   make(map[string]*domainStatsStream),
+		interval:  interval,
+		newClient: newClient,
+		now:       time.Now,
 	}
 }
 
-func (cc *concurrentCollector) Collect(socketToVMIs vmiSocketMap, scraper metricsScraper, timeout time.Duration) ([]string, bool) {
-	log.Log.V(3).Infof("Collecting VM metrics from %d sources", len(socketToVMIs))
-	var busyScrapers sync.WaitGroup
+// Sync opens the streams to the new sources and closes the streams to the sources which are gone
+func (c *domainStatsCache) Sync(socketToVMIs vmiSocketMap) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	skipped := []string{}
-	for key, vmi := range socketToVMIs {
-		reserved := cc.reserveKey(key)
-		if !reserved {
-			log.Log.Warningf("Source %s busy from a previous collection, skipped", key)
-			skipped = append(skipped, key)
-			continue
+	for socketFile, s := range c.streams {
+		if _, exists := socketToVMIs[socketFile]; !exists {
+			log.Log.V(4).Infof("Source %s gone, closing its stream", socketFile)
+			s.cancel()
+			delete(c.streams, socketFile)
 		}
-
-		log.Log.V(4).Infof("Source %s responsive, scraping", key)
-		busyScrapers.Add(1)
-		go cc.collectFromSource(scraper, &busyScrapers, key, vmi)
 	}
 
-	completed := true
-	c := make(chan struct{})
-	go func() {
-		busyScrapers.Wait()
-		c <- struct{}{}
-	}()
-	select {
-	case <-c:
-		log.Log.V(3).Infof("Collection successful")
-	case <-time.After(timeout):
-		log.Log.Warning("Collection timeout")
-		completed = false
+	for socketFile := range socketToVMIs {
+		if _, exists := c.streams[socketFile]; exists {
+			continue
+		}
+		log.Log.V(4).Infof("Opening a stream to source %s", socketFile)
+		ctx, cancel := context.WithCancel(context.Background())
+		s := &domainStatsStream{cancel: cancel}
+		c.streams[socketFile] = s
+		go c.stream(ctx, socketFile, s)
 	}
+}
 
-	log.Log.V(4).Infof("Collection completed")
+// Get returns the latest sample pushed by a source, unless it is stale
+func (c *domainStatsCache) Get(socketFile string) (*stats.DomainStats, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	return skipped, completed
+	s, exists := c.streams[socketFile]
+	if !exists || s.stats == nil {
+		return nil, false
+	}
+	if age := c.now().Sub(s.receivedAt); age > statsStaleAfter {
+		log.Log.V(2).Infof("stats from %s are %v old, ignored", socketFile, age)
+		return nil, false
+	}
+	return s.stats, true
 }
 
-func (cc *concurrentCollector) collectFromSource(scraper metricsScraper, wg *sync.WaitGroup, key string, vmi *k6tv1.VirtualMachineInstance) {
-	defer wg.Done()
-	defer cc.releaseKey(key)
+func (c *domainStatsCache) stream(ctx context.Context, socketFile string, s *domainStatsStream) {
+	defer c.remove(socketFile, s)
+
+	cli, err := c.newClient(socketFile)
+	if err != nil {
+		log.Log.Reason(err).Error("failed to connect to cmd client socket")
+		// Ignore failure to connect to client.
+		// These are all local connections via unix socket.
+		// A failure to connect means there's nothing on the other
+		// end listening.
+		return
+	}
+	defer cli.Close()
 
-	log.Log.V(4).Infof("Getting stats from source %s", key)
-	scraper.Scrape(key, vmi)
-	log.Log.V(4).Infof("Updated stats from source %s", key)
+	err = cli.StreamDomainStats(ctx, c.interval, func(vmStats *stats.DomainStats, exists bool) {
+		c.update(socketFile, s, vmStats, exists)
+	})
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to stream stats from socket %s", socketFile)
+	}
 }
 
-func (cc *concurrentCollector) reserveKey(key string) bool {
-	cc.lock.Lock()
-	defer cc.lock.Unlock()
-	count := cc.clientsPerKey[key]
-	if count >= cc.maxClientsPerKey {
-		return false
+func (c *domainStatsCache) update(socketFile string, s *domainStatsStream, vmStats *stats.DomainStats, exists bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !exists || vmStats.Name == "" {
+		log.Log.V(2).Infof("disappearing VM on %s, ignored", socketFile) // VM may be shutting down
+		s.stats = nil
+		return
 	}
-	cc.clientsPerKey[key] += 1
-	return true
+	s.stats = vmStats
+	s.receivedAt = c.now()
 }
 
-func (cc *concurrentCollector) releaseKey(key string) {
-	cc.lock.Lock()
-	defer cc.lock.Unlock()
-	cc.clientsPerKey[key] -= 1
+// remove forgets a stream which ended, so that the next Sync opens it again
+func (c *domainStatsCache) remove(socketFile string, s *domainStatsStream) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s.cancel()
+	if c.streams[socketFile] == s {
+		delete(c.streams, socketFile)
+	}
 }
@@ -20,142 +20,293 @@
 package prometheus
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+	"k8s.io/client-go/tools/cache"
 
 	k6tv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
 )
 
 var _ = Describe("Collector", func() {
+	var ctrl *gomock.Controller
+	var client *cmdclient.MockLauncherClient
+	var cache *domainStatsCache
+	var clients int32
 	var socketToVMI vmiSocketMap
 
+	type streamFunc func(ctx context.Context, interval time.Duration, handler func(*stats.DomainStats, bool)) error
+
+	// pushing pushes the samples, then keeps the stream open until it is closed
+	pushing := func(closed chan struct{}, samples ...*stats.DomainStats) streamFunc {
+		return func(ctx context.Context, interval time.Duration, handler func(*stats.DomainStats, bool)) error {
+			for _, sample := range samples {
+				handler(sample, sample != nil)
+			}
+			<-ctx.Done()
+			close(closed)
+			return nil
+		}
+	}
+
 	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = cmdclient.NewMockLauncherClient(ctrl)
+		client.EXPECT().Close().AnyTimes()
+
+		clients = 0
+		cache = newDomainStatsCache(statsStreamInterval, func(socketFile string) (cmdclient.LauncherClient, error) {
+			atomic.AddInt32(&clients, 1)
+			return client, nil
+		})
+
 		socketToVMI = make(vmiSocketMap)
 		socketToVMI["a"] = &k6tv1.VirtualMachineInstance{}
-		socketToVMI["b"] = &k6tv1.VirtualMachineInstance{}
-		socketToVMI["c"] = &k6tv1.VirtualMachineInstance{}
+	})
 
+	AfterEach(func() {
+		cache.Sync(nil)
+		ctrl.Finish()
 	})
 
-	Context("on running source", func() {
-		It("should scrape all the sources", func() {
-			fs := newFakeScraper(len(socketToVMI))
-			cc := NewConcurrentCollector(1)
+	It("should report the latest stats pushed by the source", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), statsStreamInterval, gomock.Any()).DoAndReturn(
+			pushing(closed, &stats.DomainStats{Name: "first"}, &stats.DomainStats{Name: "second"}),
+		)
 
-			skipped, completed := cc.Collect(socketToVMI, fs, 1*time.Second)
+		cache.Sync(socketToVMI)
+		Eventually(func() string {
+			vmStats, _ := cache.Get("a")
+			if vmStats == nil {
+				return ""
+			}
+			return vmStats.Name
+		}).Should(Equal("second"))
+	})
 
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeTrue())
-		})
+	It("should not report stats before the first push", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(pushing(closed))
+
+		cache.Sync(socketToVMI)
+		Consistently(func() bool {
+			_, exists := cache.Get("a")
+			return exists
+		}, 100*time.Millisecond).Should(BeFalse())
 	})
 
-	Context("on blocked source", func() {
-		It("should gather the available data", func() {
-			fs := newFakeScraper(len(socketToVMI))
-			fs.Block("a")
-			cc := NewConcurrentCollector(1)
+	It("should not report the stats of a disappearing VM", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			pushing(closed, &stats.DomainStats{Name: "first"}, nil),
+		)
 
-			skipped, completed := cc.Collect(socketToVMI, fs, 1*time.Second)
+		cache.Sync(socketToVMI)
+		Consistently(func() bool {
+			_, exists := cache.Get("a")
+			return exists
+		}, 100*time.Millisecond).Should(BeFalse())
+	})
 
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeFalse())
-		})
+	It("should not report stale stats", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			pushing(closed, &stats.DomainStats{Name: "first"}),
+		)
 
-		It("should skip it on later collections", func() {
-			fs := newFakeScraper(len(socketToVMI))
-			fs.Block("a")
-			cc := NewConcurrentCollector(2)
-
-			By("Doing a first collection")
-			skipped, completed := cc.Collect(socketToVMI, fs, 1*time.Second)
-			// first collection is not aware of the blocked source
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeFalse())
-
-			By("Doing a second collection")
-			skipped, completed = cc.Collect(socketToVMI, fs, 1*time.Second)
-			// second collection is not aware of the blocked source
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeFalse())
-
-			By("Collecting again with a blocked source")
-			skipped, completed = cc.Collect(socketToVMI, fs, 1*time.Second)
-			// second collection is aware of the blocked source
-			Expect(len(skipped)).To(Equal(1))
-			Expect(skipped[0]).To(Equal("a"))
-			Expect(completed).To(BeTrue())
+		cache.Sync(socketToVMI)
+		Eventually(func() bool {
+			_, exists := cache.Get("a")
+			return exists
+		}).Should(BeTrue())
 
-		})
+		cache.now = func() time.Time {
+			return time.Now().Add(statsStaleAfter + time.Second)
+		}
+		_, exists := cache.Get("a")
+		Expect(exists).To(BeFalse())
+	})
 
-		It("should resume scraping when unblocks", func() {
-			fs := newFakeScraper(len(socketToVMI))
-			fs.Block("b")
-			cc := NewConcurrentCollector(1)
-
-			By("Doing a first collection")
-			skipped, completed := cc.Collect(socketToVMI, fs, 1*time.Second)
-			// first collection is not aware of the blocked source
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeFalse())
-
-			By("Collecting again with a blocked source")
-			skipped, completed = cc.Collect(socketToVMI, fs, 1*time.Second)
-			// second collection is aware of the blocked source
-			Expect(len(skipped)).To(Equal(1))
-			Expect(skipped[0]).To(Equal("b"))
-			Expect(completed).To(BeTrue())
-
-			By("Unblocking the stuck source")
-			ready := fs.Wakeup("b")
-			<-ready
-			fs.Unblock("b")
-
-			By("Restored a clean state")
-			skipped, completed = cc.Collect(socketToVMI, fs, 1*time.Second)
-			Expect(len(skipped)).To(Equal(0))
-			Expect(completed).To(BeTrue())
-		})
+	It("should close the streams of the sources which are gone", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			pushing(closed, &stats.DomainStats{Name: "first"}),
+		)
+
+		cache.Sync(socketToVMI)
+		cache.Sync(vmiSocketMap{})
+		Eventually(closed).Should(BeClosed())
+
+		_, exists := cache.Get("a")
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should keep a single stream per source", func() {
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(pushing(closed))
+
+		cache.Sync(socketToVMI)
+		cache.Sync(socketToVMI)
+		Eventually(func() int32 {
+			return atomic.LoadInt32(&clients)
+		}).Should(Equal(int32(1)))
+		Consistently(func() int32 {
+			return atomic.LoadInt32(&clients)
+		}, 100*time.Millisecond).Should(Equal(int32(1)))
+	})
+
+	It("should open a broken stream again on the next sync", func() {
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("broken"))
+		closed := make(chan struct{})
+		client.EXPECT().StreamDomainStats(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			pushing(closed, &stats.DomainStats{Name: "first"}),
+		)
+
+		cache.Sync(socketToVMI)
+		Eventually(func() int {
+			cache.lock.Lock()
+			defer cache.lock.Unlock()
+			return len(cache.streams)
+		}).Should(Equal(0))
+
+		cache.Sync(socketToVMI)
+		Eventually(func() bool {
+			_, exists := cache.Get("a")
+			return exists
+		}).Should(BeTrue())
 	})
 })
 
-type fakeScraper struct {
-	ready   map[string]chan bool
-	blocked map[string]chan bool
-}
+var _ = Describe("Handler", func() {
+	var server *ghttp.Server
+	var release chan struct{}
+	var co *Collector
 
-func newFakeScraper(maxKeys int) *fakeScraper {
-	return &fakeScraper{
-		blocked: make(map[string]chan bool),
-		ready:   make(map[string]chan bool),
-	}
-}
+	BeforeEach(func() {
+		release = make(chan struct{})
+		server = ghttp.NewServer()
+		// the apiserver does not answer until the test ends
+		server.RouteToHandler(http.MethodGet, regexp.MustCompile(".*"), func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		})
+		virtClient, err := kubecli.GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+		co, err = SetupCollector(prometheus.NewRegistry(), virtClient, "/var/run/kubevirt", "testnode", cache.NewStore(cache.MetaNamespaceKeyFunc), namingMode(k6tv1.MetricsNamingBoth))
+		Expect(err).ToNot(HaveOccurred())
+	})
 
-// Unblock makes sure Scrape() WILL block. Call it when no goroutines are running (e.g. when concurrentCollector is known idle.)
-func (fs *fakeScraper) Block(key string) {
-	fs.blocked[key] = make(chan bool)
-	fs.ready[key] = make(chan bool)
-}
+	AfterEach(func() {
+		close(release)
+		server.Close()
+	})
+
+	It("should bound the VMI lookup by the scrape timeout", func() {
+		request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		request.Header.Set(scrapeTimeoutHeader, "0.2")
+		recorder := httptest.NewRecorder()
+		start := time.Now()
+		Handler(3, co).ServeHTTP(recorder, request)
+
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("kubevirt_info"))
+	})
 
-// Unblock makes sure Scrape() won't block. Call it when no goroutines are running (e.g. when concurrentCollector is known idle.)
-func (fs *fakeScraper) Unblock(key string) {
-	delete(fs.blocked, key)
-	delete(fs.ready, key)
+	It("should cancel the VMI lookup once the scrape timed out", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := virtualMachinesOnNode(ctx, co.virtCli, "testnode")
+		Expect(err).To(HaveOccurred())
+		Expect(ctx.Err()).To(Equal(context.DeadlineExceeded))
+	})
+
+	table.DescribeTable("should read the scrape timeout from the request", func(header string, expected time.Duration) {
+		request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if header != "" {
+			request.Header.Set(scrapeTimeoutHeader, header)
+		}
+		Expect(scrapeTimeout(request)).To(Equal(expected))
+	},
+		table.Entry("with a timeout in seconds", "4.5", 4*time.Second),
+		table.Entry("without the header", "", defaultScrapeTimeout-scrapeTimeoutOffset),
+		table.Entry("with an invalid header", "soon", defaultScrapeTimeout-scrapeTimeoutOffset),
+		table.Entry("with a negative timeout", "-1", defaultScrapeTimeout-scrapeTimeoutOffset),
+		table.Entry("with a timeout too short for the offset", "0.5", 500*time.Millisecond),
+	)
+})
+
+type failingRegisterer struct {
+	prometheus.Registerer
 }
 
-// Wakeup awakens a blocked Scrape(). Can be called when concurrentCollector is running.
-func (fs *fakeScraper) Wakeup(key string) chan bool {
-	if c, ok := fs.blocked[key]; ok {
-		c <- true
-		return fs.ready[key]
-	}
-	return nil
+func (failingRegisterer) Register(prometheus.Collector) error {
+	return fmt.Errorf("registration refused")
 }
 
-func (fs *fakeScraper) Scrape(key string, vmi *k6tv1.VirtualMachineInstance) {
-	if c, ok := fs.blocked[key]; ok {
-		<-c
-		fs.ready[key] <- true
+func namingMode(mode k6tv1.MetricsNamingMode) func() k6tv1.MetricsNamingMode {
+	return func() k6tv1.MetricsNamingMode {
+		return mode
 	}
 }
+
+var _ = Describe("SetupCollector", func() {
+	var server *ghttp.Server
+	var virtClient kubecli.KubevirtClient
+	var registry *prometheus.Registry
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		server.AllowUnhandledRequests = true
+		var err error
+		virtClient, err = kubecli.GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+		registry = testutils.NewMetricsRegistry()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should register the collector on the given registry", func() {
+		_, err := SetupCollector(registry, virtClient, "/var/run/kubevirt", "testnode", cache.NewStore(cache.MetaNamespaceKeyFunc), namingMode(k6tv1.MetricsNamingBoth))
+		Expect(err).ToNot(HaveOccurred())
+
+		families, err := testutils.GatherMetricFamilies(registry)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveKeyWithValue("kubevirt_info", 1))
+	})
+
+	It("should gather the metrics for virt-handler without counting them as scrapes", func() {
+		co, err := SetupCollector(registry, virtClient, "/var/run/kubevirt", "testnode", cache.NewStore(cache.MetaNamespaceKeyFunc), namingMode(k6tv1.MetricsNamingBoth))
+		Expect(err).ToNot(HaveOccurred())
+
+		families, err := testutils.GatherMetricFamilies(registry)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveKeyWithValue("kubevirt_info", 1))
+		Expect(co.deprecated.list()).To(BeEmpty())
+	})
+
+	It("should fail instead of panicking if the collector can not be registered", func() {
+		_, err := SetupCollector(failingRegisterer{}, virtClient, "/var/run/kubevirt", "testnode", cache.NewStore(cache.MetaNamespaceKeyFunc), namingMode(k6tv1.MetricsNamingBoth))
+		Expect(err).To(MatchError(ContainSubstring("registration refused")))
+	})
+})
@@ -0,0 +1,149 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package prometheus
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	k6tv1 "kubevirt.io/client-go/api/v1"
+)
+
+// deprecatedMetricNames maps the metrics violating the Prometheus naming conventions to the names replacing them
+var deprecatedMetricNames = map[string]string{
+	"kubevirt_vmi_vcpu_seconds":           "kubevirt_vmi_vcpu_seconds_total",
+	"kubevirt_vmi_vcpu_wait_seconds":      "kubevirt_vmi_vcpu_wait_seconds_total",
+	"kubevirt_vmi_storage_times_ms_total": "kubevirt_vmi_storage_times_seconds_total",
+}
+
+// metricNaming selects the names of the renamed metrics during a scrape and records the deprecated names exposed
+type metricNaming struct {
+	mode k6tv1.MetricsNamingMode
+
+	lock              sync.Mutex
+	scrapedDeprecated map[string]struct{}
+}
+
+func newMetricNaming(mode k6tv1.MetricsNamingMode) *metricNaming {
+	return &metricNaming{
+		mode:              mode,
+		scrapedDeprecated: map[string]struct{}{},
+	}
+}
+
+func (n *metricNaming) exposesCurrentNames() bool {
+	return n == nil || n.mode != k6tv1.MetricsNamingLegacy
+}
+
+func (n *metricNaming) exposesDeprecatedName(name string) bool {
+	if n == nil || (n.mode != k6tv1.MetricsNamingLegacy && n.mode != k6tv1.MetricsNamingBoth) {
+		return false
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.scrapedDeprecated[name] = struct{}{}
+	return true
+}
+
+func (n *metricNaming) scrapedDeprecatedNames() []string {
+	if n == nil {
+		return nil
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	names := make([]string, 0, len(n.scrapedDeprecated))
+	for name := range n.scrapedDeprecated {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (metrics *vmiMetrics) exposesCurrentNames() bool {
+	return metrics.naming.exposesCurrentNames()
+}
+
+func (metrics *vmiMetrics) exposesDeprecatedName(name string) bool {
+	return metrics.naming.exposesDeprecatedName(name)
+}
+
+// DeprecatedMetric is a deprecated metric name which is still exposed
+type DeprecatedMetric struct {
+	Name        string    `json:"name"`
+	Replacement string    `json:"replacement"`
+	LastScraped time.Time `json:"lastScraped"`
+	Scrapes     uint64    `json:"scrapes"`
+}
+
+type deprecatedMetricsTracker struct {
+	lock    sync.Mutex
+	metrics map[string]*DeprecatedMetric
+	now     func() time.Time
+}
+
+func newDeprecatedMetricsTracker() *deprecatedMetricsTracker {
+	return &deprecatedMetricsTracker{
+		metrics: map[string]*DeprecatedMetric{},
+		now:     time.Now,
+	}
+}
+
+func (t *deprecatedMetricsTracker) observe(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	now := t.now()
+	for _, name := range names {
+		metric, exists := t.metrics[name]
+		if !exists {
+			metric = &DeprecatedMetric{Name: name, Replacement: deprecatedMetricNames[name]}
+			t.metrics[name] = metric
+		}
+		metric.LastScraped = now
+		metric.Scrapes++
+	}
+}
+
+func (t *deprecatedMetricsTracker) list() []DeprecatedMetric {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	metrics := make([]DeprecatedMetric, 0, len(t.metrics))
+	for _, metric := range t.metrics {
+		metrics = append(metrics, *metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+// DeprecatedMetricsHandler lists the deprecated metric names which were scraped from this node, with their replacements
+func (co *Collector) DeprecatedMetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(co.deprecated.list()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
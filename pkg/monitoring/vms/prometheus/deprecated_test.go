@@ -0,0 +1,75 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package prometheus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	k6tv1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Deprecated metrics", func() {
+
+	It("should not record anything in the current naming mode", func() {
+		naming := newMetricNaming(k6tv1.MetricsNamingCurrent)
+		Expect(naming.exposesCurrentNames()).To(BeTrue())
+		Expect(naming.exposesDeprecatedName("kubevirt_vmi_vcpu_seconds")).To(BeFalse())
+		Expect(naming.scrapedDeprecatedNames()).To(BeEmpty())
+	})
+
+	It("should list the scraped deprecated names with their replacements", func() {
+		first := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+		second := first.Add(time.Minute)
+
+		tracker := newDeprecatedMetricsTracker()
+		tracker.now = func() time.Time { return first }
+		tracker.observe([]string{"kubevirt_vmi_vcpu_seconds", "kubevirt_vmi_storage_times_ms_total"})
+		tracker.now = func() time.Time { return second }
+		tracker.observe([]string{"kubevirt_vmi_vcpu_seconds"})
+		co := &Collector{deprecated: tracker}
+
+		recorder := httptest.NewRecorder()
+		co.DeprecatedMetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics/deprecated", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var metrics []DeprecatedMetric
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &metrics)).To(Succeed())
+		Expect(metrics).To(Equal([]DeprecatedMetric{
+			{
+				Name:        "kubevirt_vmi_storage_times_ms_total",
+				Replacement: "kubevirt_vmi_storage_times_seconds_total",
+				LastScraped: first,
+				Scrapes:     1,
+			},
+			{
+				Name:        "kubevirt_vmi_vcpu_seconds",
+				Replacement: "kubevirt_vmi_vcpu_seconds_total",
+				LastScraped: second,
+				Scrapes:     2,
+			},
+		}))
+	})
+})
@@ -0,0 +1,72 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/cache"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+var domainStateDesc = prometheus.NewDesc(
+	"kubevirt_vmi_domain_state",
+	"The state of the domain of a VMI and the reason of the state, as the last virt-launcher notification reported them.",
+	[]string{"node", "namespace", "name", "state", "reason"},
+	nil,
+)
+
+// domainStateCollector reports the state of the domains on the node from the domain informer of
+// virt-handler, which the virt-launcher domain notifications keep up to date
+type domainStateCollector struct {
+	nodeName       string
+	domainInformer cache.SharedInformer
+}
+
+// SetupDomainStateCollector registers the collector of the state of the domains on the node on the registerer
+func SetupDomainStateCollector(registerer prometheus.Registerer, nodeName string, domainInformer cache.SharedInformer) error {
+	return registerer.Register(&domainStateCollector{nodeName: nodeName, domainInformer: domainInformer})
+}
+
+func (c *domainStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- domainStateDesc
+}
+
+func (c *domainStateCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, obj := range c.domainInformer.GetStore().List() {
+		domain := obj.(*api.Domain)
+		// the domains of vanished or unresponsive virt-launchers are marked as deleted, their state is not known
+		if domain.ObjectMeta.DeletionTimestamp != nil {
+			continue
+		}
+
+		mv, err := prometheus.NewConstMetric(
+			domainStateDesc, prometheus.GaugeValue, 1,
+			c.nodeName, domain.ObjectMeta.Namespace, domain.ObjectMeta.Name,
+			string(domain.Status.Status), string(domain.Status.Reason),
+		)
+		if err != nil {
+			log.Log.Object(domain).Reason(err).Error("Failed to create the domain state metric")
+			continue
+		}
+		ch <- mv
+	}
+}
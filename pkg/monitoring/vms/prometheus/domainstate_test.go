@@ -0,0 +1,69 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package prometheus
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+var _ = Describe("Domain state collector", func() {
+
+	It("should report the state and reason of the domains which are not deleted", func() {
+		informer, _ := testutils.NewFakeInformerFor(&api.Domain{})
+
+		domain := api.NewMinimalDomainWithNS("default", "testvmi")
+		domain.Status.Status = api.Paused
+		domain.Status.Reason = api.ReasonPausedIOError
+		Expect(informer.GetStore().Add(domain)).To(Succeed())
+
+		deleted := api.NewMinimalDomainWithNS("default", "deleted")
+		now := metav1.Now()
+		deleted.ObjectMeta.DeletionTimestamp = &now
+		Expect(informer.GetStore().Add(deleted)).To(Succeed())
+
+		registry := prometheus.NewPedanticRegistry()
+		Expect(SetupDomainStateCollector(registry, "testnode", informer)).To(Succeed())
+
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(families).To(HaveLen(1))
+		Expect(families[0].GetName()).To(Equal("kubevirt_vmi_domain_state"))
+		Expect(families[0].GetMetric()).To(HaveLen(1))
+
+		labels := map[string]string{}
+		for _, label := range families[0].GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		Expect(labels).To(Equal(map[string]string{
+			"node":      "testnode",
+			"namespace": "default",
+			"name":      "testvmi",
+			"state":     "Paused",
+			"reason":    "IOError",
+		}))
+		Expect(families[0].GetMetric()[0].GetGauge().GetValue()).To(Equal(float64(1)))
+	})
+})
@@ -22,22 +22,38 @@ package prometheus
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/net/context"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
 
 	k6tv1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/client-go/version"
-	"kubevirt.io/kubevirt/pkg/util/lookup"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
 )
 
-const statsMaxAge time.Duration = collectionTimeout + 2*time.Second // "a bit more" than timeout, heuristic again
+const (
+	scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+	// defaultScrapeTimeout is the default scrape timeout of Prometheus, used
+	// if a scrape does not tell its own
+	defaultScrapeTimeout = 10 * time.Second
+	// scrapeTimeoutOffset leaves time to send the metrics before the scrape times out
+	scrapeTimeoutOffset = 500 * time.Millisecond
+)
 
 var (
 
@@ -64,6 +80,66 @@ var (
 		},
 		nil,
 	)
+
+	// joins the VMI metrics with the pod metrics, e.g. of cAdvisor
+	vmiPodInfoDesc = prometheus.NewDesc(
+		"kubevirt_vmi_pod_info",
+		"Launcher pod of the VMI.",
+		[]string{
+			"namespace", "vmi", "pod", "node",
+		},
+		nil,
+	)
+
+	// inventory of the mediated devices, e.g. vGPUs, of the node
+	mdevCapacityDesc = prometheus.NewDesc(
+		"kubevirt_node_mdev_capacity",
+		"Number of mediated devices of a type which the node supports.",
+		[]string{
+			"node", "mdev_type", "name",
+		},
+		nil,
+	)
+	mdevAllocatedDesc = prometheus.NewDesc(
+		"kubevirt_node_mdev_allocated",
+		"Number of mediated devices of a type which are created on the node.",
+		[]string{
+			"node", "mdev_type", "name",
+		},
+		nil,
+	)
+
+	// kernel samepage merging of the node
+	ksmRunningDesc = prometheus.NewDesc(
+		"kubevirt_node_ksm_running",
+		"Whether the KSM daemon of the node merges pages.",
+		[]string{"node"},
+		nil,
+	)
+	ksmPagesSharedDesc = prometheus.NewDesc(
+		"kubevirt_node_ksm_pages_shared",
+		"Number of shared pages which KSM keeps in use on the node.",
+		[]string{"node"},
+		nil,
+	)
+	ksmPagesSharingDesc = prometheus.NewDesc(
+		"kubevirt_node_ksm_pages_sharing",
+		"Number of page mappings which point to a page shared by KSM on the node.",
+		[]string{"node"},
+		nil,
+	)
+	ksmPagesUnsharedDesc = prometheus.NewDesc(
+		"kubevirt_node_ksm_pages_unshared",
+		"Number of unique pages which KSM repeatedly checks for merging on the node.",
+		[]string{"node"},
+		nil,
+	)
+	ksmFullScansDesc = prometheus.NewDesc(
+		"kubevirt_node_ksm_full_scans_total",
+		"Number of times KSM scanned all mergeable memory areas of the node.",
+		[]string{"node"},
+		nil,
+	)
 )
 
 func tryToPushMetric(desc *prometheus.Desc, mv prometheus.Metric, err error, ch chan<- prometheus.Metric) {
@@ -128,13 +204,18 @@ func (metrics *vmiMetrics) updateMemory(vmi *k6tv1.VirtualMachineInstance, vmSta
 			swapTrafficLabels,
 			nil,
 		)
+		// the traffic only grows, it was reported as a gauge before the naming conventions were applied
+		swapTrafficType := prometheus.CounterValue
+		if !metrics.exposesCurrentNames() {
+			swapTrafficType = prometheus.GaugeValue
+		}
 
 		if vmStats.Memory.SwapInSet {
 			var swapTrafficInLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, "in"}
 			swapTrafficInLabelValues = append(swapTrafficInLabelValues, k8sLabelValues...)
 
 			mv, err := prometheus.NewConstMetric(
-				metrics.swapTrafficDesc, prometheus.GaugeValue,
+				metrics.swapTrafficDesc, swapTrafficType,
 				// the libvirt value is in KiB
 				float64(vmStats.Memory.SwapIn)*1024,
 				swapTrafficInLabelValues...,
@@ -146,7 +227,7 @@ func (metrics *vmiMetrics) updateMemory(vmi *k6tv1.VirtualMachineInstance, vmSta
 			swapTrafficOutLabelValues = append(swapTrafficOutLabelValues, k8sLabelValues...)
 
 			mv, err := prometheus.NewConstMetric(
-				metrics.swapTrafficDesc, prometheus.GaugeValue,
+				metrics.swapTrafficDesc, swapTrafficType,
 				// the libvirt value is in KiB
 				float64(vmStats.Memory.SwapOut)*1024,
 				swapTrafficOutLabelValues...,
@@ -154,6 +235,91 @@ func (metrics *vmiMetrics) updateMemory(vmi *k6tv1.VirtualMachineInstance, vmSta
 			tryToPushMetric(metrics.swapTrafficDesc, mv, err, ch)
 		}
 	}
+
+	// the hugepages charged to the launcher pod confirm that they back the guest memory
+	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Hugepages != nil && len(vmStats.Hugepages) > 0 {
+		var memoryHugepagesLabels = []string{"node", "namespace", "name", "domain", "page_size"}
+		memoryHugepagesLabels = append(memoryHugepagesLabels, k8sLabels...)
+		metrics.memoryHugepagesDesc = prometheus.NewDesc(
+			"kubevirt_vmi_memory_hugepages_bytes",
+			"hugepages in use by the domain per page size.",
+			memoryHugepagesLabels,
+			nil,
+		)
+
+		for _, hugepagesStats := range vmStats.Hugepages {
+			pageSize := resource.NewQuantity(int64(hugepagesStats.PageSize), resource.BinarySI).String()
+			var memoryHugepagesLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, pageSize}
+			memoryHugepagesLabelValues = append(memoryHugepagesLabelValues, k8sLabelValues...)
+
+			mv, err := prometheus.NewConstMetric(
+				metrics.memoryHugepagesDesc, prometheus.GaugeValue,
+				float64(hugepagesStats.Usage),
+				memoryHugepagesLabelValues...,
+			)
+			tryToPushMetric(metrics.memoryHugepagesDesc, mv, err, ch)
+		}
+	}
+
+	if vmStats.DirtyRate != nil {
+		var memoryDirtyRateLabels = []string{"node", "namespace", "name", "domain"}
+		memoryDirtyRateLabels = append(memoryDirtyRateLabels, k8sLabels...)
+		metrics.memoryDirtyRateDesc = prometheus.NewDesc(
+			"kubevirt_vmi_dirty_rate_bytes_per_second",
+			"rate at which the guest dirtied its memory in the last measurement.",
+			memoryDirtyRateLabels,
+			nil,
+		)
+
+		var memoryDirtyRateLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name}
+		memoryDirtyRateLabelValues = append(memoryDirtyRateLabelValues, k8sLabelValues...)
+
+		mv, err := prometheus.NewConstMetric(
+			metrics.memoryDirtyRateDesc, prometheus.GaugeValue,
+			float64(vmStats.DirtyRate.BytesPerSecond),
+			memoryDirtyRateLabelValues...,
+		)
+		tryToPushMetric(metrics.memoryDirtyRateDesc, mv, err, ch)
+	}
+}
+
+func (metrics *vmiMetrics) updateCPUPressure(vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats, ch chan<- prometheus.Metric, k8sLabels []string, k8sLabelValues []string) {
+	if vmStats.CPUPressure == nil {
+		return
+	}
+
+	var cpuPressureLabels = []string{"node", "namespace", "name", "domain"}
+	cpuPressureLabels = append(cpuPressureLabels, k8sLabels...)
+	var cpuPressureLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name}
+	cpuPressureLabelValues = append(cpuPressureLabelValues, k8sLabelValues...)
+
+	metrics.cpuPressureSomeDesc = prometheus.NewDesc(
+		"kubevirt_vmi_cpu_pressure_some_seconds_total",
+		"time in which at least one process of the virt-launcher pod stalled waiting for a cpu.",
+		cpuPressureLabels,
+		nil,
+	)
+	mv, err := prometheus.NewConstMetric(
+		metrics.cpuPressureSomeDesc, prometheus.CounterValue,
+		vmStats.CPUPressure.Some,
+		cpuPressureLabelValues...,
+	)
+	tryToPushMetric(metrics.cpuPressureSomeDesc, mv, err, ch)
+
+	if vmStats.CPUPressure.FullSet {
+		metrics.cpuPressureFullDesc = prometheus.NewDesc(
+			"kubevirt_vmi_cpu_pressure_full_seconds_total",
+			"time in which all non-idle processes of the virt-launcher pod stalled waiting for a cpu.",
+			cpuPressureLabels,
+			nil,
+		)
+		mv, err := prometheus.NewConstMetric(
+			metrics.cpuPressureFullDesc, prometheus.CounterValue,
+			vmStats.CPUPressure.Full,
+			cpuPressureLabelValues...,
+		)
+		tryToPushMetric(metrics.cpuPressureFullDesc, mv, err, ch)
+	}
 }
 
 func (metrics *vmiMetrics) updateVcpu(vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats, ch chan<- prometheus.Metric, k8sLabels []string, k8sLabelValues []string) {
@@ -164,22 +330,38 @@ func (metrics *vmiMetrics) updateVcpu(vmi *k6tv1.VirtualMachineInstance, vmStats
 		} else {
 			var vcpuUsageLabels = []string{"node", "namespace", "name", "domain", "id", "state"}
 			vcpuUsageLabels = append(vcpuUsageLabels, k8sLabels...)
-			metrics.vcpuUsageDesc = prometheus.NewDesc(
-				"kubevirt_vmi_vcpu_seconds",
-				"Vcpu elapsed time.",
-				vcpuUsageLabels,
-				nil,
-			)
-
 			var vcpuUsageLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, fmt.Sprintf("%v", vcpuId), fmt.Sprintf("%v", vcpu.State)}
 			vcpuUsageLabelValues = append(vcpuUsageLabelValues, k8sLabelValues...)
-			mv, err := prometheus.NewConstMetric(
-				metrics.vcpuUsageDesc, prometheus.GaugeValue,
-				float64(vcpu.Time/1000000000),
-				vcpuUsageLabelValues...,
-			)
-			tryToPushMetric(metrics.vcpuUsageDesc, mv, err, ch)
 
+			if metrics.exposesCurrentNames() {
+				metrics.vcpuUsageDesc = prometheus.NewDesc(
+					"kubevirt_vmi_vcpu_seconds_total",
+					"Vcpu elapsed time.",
+					vcpuUsageLabels,
+					nil,
+				)
+				mv, err := prometheus.NewConstMetric(
+					metrics.vcpuUsageDesc, prometheus.CounterValue,
+					float64(vcpu.Time)/1000000000,
+					vcpuUsageLabelValues...,
+				)
+				tryToPushMetric(metrics.vcpuUsageDesc, mv, err, ch)
+			}
+
+			if metrics.exposesDeprecatedName("kubevirt_vmi_vcpu_seconds") {
+				metrics.legacyVcpuUsageDesc = prometheus.NewDesc(
+					"kubevirt_vmi_vcpu_seconds",
+					"Vcpu elapsed time. Deprecated in favor of kubevirt_vmi_vcpu_seconds_total.",
+					vcpuUsageLabels,
+					nil,
+				)
+				mv, err := prometheus.NewConstMetric(
+					metrics.legacyVcpuUsageDesc, prometheus.GaugeValue,
+					float64(vcpu.Time/1000000000),
+					vcpuUsageLabelValues...,
+				)
+				tryToPushMetric(metrics.legacyVcpuUsageDesc, mv, err, ch)
+			}
 		}
 
 		if !vcpu.WaitSet {
@@ -195,20 +377,38 @@ func (metrics *vmiMetrics) updateVcpu(vmi *k6tv1.VirtualMachineInstance, vmStats
 		}
 		vcpuWaitLabelsValues = append(vcpuWaitLabelsValues, k8sLabelValues...)
 
-		vcpuWaitDesc := prometheus.NewDesc(
-			"kubevirt_vmi_vcpu_wait_seconds",
-			"vcpu time spent by waiting on I/O",
-			vcpuWaitLabels,
-			nil,
-		)
+		if metrics.exposesCurrentNames() {
+			vcpuWaitDesc := prometheus.NewDesc(
+				"kubevirt_vmi_vcpu_wait_seconds_total",
+				"vcpu time spent by waiting on I/O",
+				vcpuWaitLabels,
+				nil,
+			)
 
-		mv, err := prometheus.NewConstMetric(
-			vcpuWaitDesc, prometheus.GaugeValue,
-			float64(vcpu.Wait/1000000),
-			vcpuWaitLabelsValues...,
-		)
-		tryToPushMetric(vcpuWaitDesc, mv, err, ch)
+			mv, err := prometheus.NewConstMetric(
+				vcpuWaitDesc, prometheus.CounterValue,
+				// the libvirt value is in nanoseconds
+				float64(vcpu.Wait)/1000000000,
+				vcpuWaitLabelsValues...,
+			)
+			tryToPushMetric(vcpuWaitDesc, mv, err, ch)
+		}
+
+		if metrics.exposesDeprecatedName("kubevirt_vmi_vcpu_wait_seconds") {
+			vcpuWaitDesc := prometheus.NewDesc(
+				"kubevirt_vmi_vcpu_wait_seconds",
+				"vcpu time spent by waiting on I/O, in milliseconds. Deprecated in favor of kubevirt_vmi_vcpu_wait_seconds_total.",
+				vcpuWaitLabels,
+				nil,
+			)
 
+			mv, err := prometheus.NewConstMetric(
+				vcpuWaitDesc, prometheus.GaugeValue,
+				float64(vcpu.Wait/1000000),
+				vcpuWaitLabelsValues...,
+			)
+			tryToPushMetric(vcpuWaitDesc, mv, err, ch)
+		}
 	}
 }
 
@@ -292,36 +492,107 @@ func (metrics *vmiMetrics) updateBlock(vmi *k6tv1.VirtualMachineInstance, vmStat
 		if block.RdTimesSet || block.WrTimesSet {
 			var storageTimesLabels = []string{"node", "namespace", "name", "domain", "drive", "type"}
 			storageTimesLabels = append(storageTimesLabels, k8sLabels...)
-			metrics.storageTimesDesc = prometheus.NewDesc(
-				"kubevirt_vmi_storage_times_ms_total",
-				"storage operation time.",
-				storageTimesLabels,
-				nil,
-			)
-
-			if block.RdTimesSet {
-				var storageTimesReadLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, block.Name, "read"}
-				storageTimesReadLabelValues = append(storageTimesReadLabelValues, k8sLabelValues...)
 
-				mv, err := prometheus.NewConstMetric(
-					metrics.storageTimesDesc, prometheus.CounterValue,
-					float64(block.RdTimes),
-					storageTimesReadLabelValues...,
+			var storageTimesReadLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, block.Name, "read"}
+			storageTimesReadLabelValues = append(storageTimesReadLabelValues, k8sLabelValues...)
+			var storageTimesWriteLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, block.Name, "write"}
+			storageTimesWriteLabelValues = append(storageTimesWriteLabelValues, k8sLabelValues...)
+
+			if metrics.exposesCurrentNames() {
+				metrics.storageTimesDesc = prometheus.NewDesc(
+					"kubevirt_vmi_storage_times_seconds_total",
+					"storage operation time.",
+					storageTimesLabels,
+					nil,
 				)
-				tryToPushMetric(metrics.storageTimesDesc, mv, err, ch)
+				// the libvirt values are in nanoseconds
+				if block.RdTimesSet {
+					mv, err := prometheus.NewConstMetric(
+						metrics.storageTimesDesc, prometheus.CounterValue,
+						float64(block.RdTimes)/1000000000,
+						storageTimesReadLabelValues...,
+					)
+					tryToPushMetric(metrics.storageTimesDesc, mv, err, ch)
+				}
+				if block.WrTimesSet {
+					mv, err := prometheus.NewConstMetric(
+						metrics.storageTimesDesc, prometheus.CounterValue,
+						float64(block.WrTimes)/1000000000,
+						storageTimesWriteLabelValues...,
+					)
+					tryToPushMetric(metrics.storageTimesDesc, mv, err, ch)
+				}
 			}
-			if block.WrTimesSet {
-				var storageTimesWriteLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, block.Name, "write"}
-				storageTimesWriteLabelValues = append(storageTimesWriteLabelValues, k8sLabelValues...)
 
-				mv, err := prometheus.NewConstMetric(
-					metrics.storageTimesDesc, prometheus.CounterValue,
-					float64(block.WrTimes),
-					storageTimesWriteLabelValues...,
+			if metrics.exposesDeprecatedName("kubevirt_vmi_storage_times_ms_total") {
+				metrics.legacyStorageTimesDesc = prometheus.NewDesc(
+					"kubevirt_vmi_storage_times_ms_total",
+					"storage operation time, in nanoseconds despite its name. Deprecated in favor of kubevirt_vmi_storage_times_seconds_total.",
+					storageTimesLabels,
+					nil,
 				)
-				tryToPushMetric(metrics.storageTimesDesc, mv, err, ch)
+				if block.RdTimesSet {
+					mv, err := prometheus.NewConstMetric(
+						metrics.legacyStorageTimesDesc, prometheus.CounterValue,
+						float64(block.RdTimes),
+						storageTimesReadLabelValues...,
+					)
+					tryToPushMetric(metrics.legacyStorageTimesDesc, mv, err, ch)
+				}
+				if block.WrTimesSet {
+					mv, err := prometheus.NewConstMetric(
+						metrics.legacyStorageTimesDesc, prometheus.CounterValue,
+						float64(block.WrTimes),
+						storageTimesWriteLabelValues...,
+					)
+					tryToPushMetric(metrics.legacyStorageTimesDesc, mv, err, ch)
+				}
 			}
 		}
+
+		if block.CapacitySet {
+			var storageCapacityLabels = []string{"node", "namespace", "name", "domain", "drive"}
+			storageCapacityLabels = append(storageCapacityLabels, k8sLabels...)
+			metrics.storageCapacityDesc = prometheus.NewDesc(
+				"kubevirt_vmi_storage_capacity_bytes",
+				"size of the drive as the guest sees it.",
+				storageCapacityLabels,
+				nil,
+			)
+
+			var storageCapacityLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, block.Name}
+			storageCapacityLabelValues = append(storageCapacityLabelValues, k8sLabelValues...)
+
+			mv, err := prometheus.NewConstMetric(
+				metrics.storageCapacityDesc, prometheus.GaugeValue,
+				float64(block.Capacity),
+				storageCapacityLabelValues...,
+			)
+			tryToPushMetric(metrics.storageCapacityDesc, mv, err, ch)
+		}
+	}
+
+	if len(vmStats.BlockThrottle) > 0 {
+		var storageThrottledLabels = []string{"node", "namespace", "name", "domain", "drive", "limit"}
+		storageThrottledLabels = append(storageThrottledLabels, k8sLabels...)
+		metrics.storageThrottledDesc = prometheus.NewDesc(
+			"kubevirt_vmi_storage_throttled_total",
+			"number of samples in which the drive ran at one of its I/O limits.",
+			storageThrottledLabels,
+			nil,
+		)
+
+		for _, throttle := range vmStats.BlockThrottle {
+			var storageThrottledLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, throttle.Name, throttle.Limit}
+			storageThrottledLabelValues = append(storageThrottledLabelValues, k8sLabelValues...)
+
+			mv, err := prometheus.NewConstMetric(
+				metrics.storageThrottledDesc, prometheus.CounterValue,
+				float64(throttle.Hits),
+				storageThrottledLabelValues...,
+			)
+			tryToPushMetric(metrics.storageThrottledDesc, mv, err, ch)
+		}
 	}
 }
 
@@ -435,6 +706,153 @@ func (metrics *vmiMetrics) updateNetwork(vmi *k6tv1.VirtualMachineInstance, vmSt
 			}
 		}
 	}
+
+	if len(vmStats.NetThrottle) > 0 {
+		var networkThrottledLabels = []string{"node", "namespace", "name", "domain", "interface", "type"}
+		networkThrottledLabels = append(networkThrottledLabels, k8sLabels...)
+		metrics.networkThrottledDesc = prometheus.NewDesc(
+			"kubevirt_vmi_network_throttled_packets_total",
+			"packets which exceeded the bandwidth limits of the interface.",
+			networkThrottledLabels,
+			nil,
+		)
+
+		for _, throttle := range vmStats.NetThrottle {
+			var networkThrottledLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, throttle.Name, throttle.Type}
+			networkThrottledLabelValues = append(networkThrottledLabelValues, k8sLabelValues...)
+
+			mv, err := prometheus.NewConstMetric(
+				metrics.networkThrottledDesc, prometheus.CounterValue,
+				float64(throttle.Throttled),
+				networkThrottledLabelValues...,
+			)
+			tryToPushMetric(metrics.networkThrottledDesc, mv, err, ch)
+		}
+	}
+}
+
+func (metrics *vmiMetrics) updateConnLimit(vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats, ch chan<- prometheus.Metric, k8sLabels []string, k8sLabelValues []string) {
+	if len(vmStats.ConnLimit) == 0 {
+		return
+	}
+
+	var connLimitDroppedLabels = []string{"node", "namespace", "name", "domain", "interface", "reason"}
+	connLimitDroppedLabels = append(connLimitDroppedLabels, k8sLabels...)
+	metrics.connLimitDroppedDesc = prometheus.NewDesc(
+		"kubevirt_vmi_network_connection_limit_dropped_total",
+		"new connections dropped by the connection limits.",
+		connLimitDroppedLabels,
+		nil,
+	)
+
+	for _, connLimit := range vmStats.ConnLimit {
+		var connLimitDroppedLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, connLimit.Name, connLimit.Reason}
+		connLimitDroppedLabelValues = append(connLimitDroppedLabelValues, k8sLabelValues...)
+
+		mv, err := prometheus.NewConstMetric(
+			metrics.connLimitDroppedDesc, prometheus.CounterValue,
+			float64(connLimit.Dropped),
+			connLimitDroppedLabelValues...,
+		)
+		tryToPushMetric(metrics.connLimitDroppedDesc, mv, err, ch)
+	}
+}
+
+func (metrics *vmiMetrics) updateProcesses(vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats, ch chan<- prometheus.Metric, k8sLabels []string, k8sLabelValues []string) {
+	if len(vmStats.Processes) == 0 {
+		return
+	}
+
+	var launcherCPULabels = []string{"node", "namespace", "name", "domain", "process"}
+	launcherCPULabels = append(launcherCPULabels, k8sLabels...)
+	metrics.launcherCPUDesc = prometheus.NewDesc(
+		"kubevirt_vmi_launcher_cpu_seconds_total",
+		"cpu time consumed by the processes of the launcher pod.",
+		launcherCPULabels,
+		nil,
+	)
+
+	var launcherMemoryLabels = []string{"node", "namespace", "name", "domain", "process"}
+	launcherMemoryLabels = append(launcherMemoryLabels, k8sLabels...)
+	metrics.launcherMemoryDesc = prometheus.NewDesc(
+		"kubevirt_vmi_launcher_memory_resident_bytes",
+		"resident set size of the processes of the launcher pod.",
+		launcherMemoryLabels,
+		nil,
+	)
+
+	var launcherStorageLabels = []string{"node", "namespace", "name", "domain", "process", "type"}
+	launcherStorageLabels = append(launcherStorageLabels, k8sLabels...)
+	metrics.launcherStorageDesc = prometheus.NewDesc(
+		"kubevirt_vmi_launcher_storage_traffic_bytes_total",
+		"storage traffic of the processes of the launcher pod.",
+		launcherStorageLabels,
+		nil,
+	)
+
+	for _, processStats := range vmStats.Processes {
+		var launcherLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, processStats.Type}
+		launcherLabelValues = append(launcherLabelValues, k8sLabelValues...)
+
+		mv, err := prometheus.NewConstMetric(
+			metrics.launcherCPUDesc, prometheus.CounterValue,
+			processStats.CPUTime,
+			launcherLabelValues...,
+		)
+		tryToPushMetric(metrics.launcherCPUDesc, mv, err, ch)
+
+		mv, err = prometheus.NewConstMetric(
+			metrics.launcherMemoryDesc, prometheus.GaugeValue,
+			float64(processStats.ResidentMemory),
+			launcherLabelValues...,
+		)
+		tryToPushMetric(metrics.launcherMemoryDesc, mv, err, ch)
+
+		var launcherStorageReadLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, processStats.Type, "read"}
+		launcherStorageReadLabelValues = append(launcherStorageReadLabelValues, k8sLabelValues...)
+		mv, err = prometheus.NewConstMetric(
+			metrics.launcherStorageDesc, prometheus.CounterValue,
+			float64(processStats.ReadBytes),
+			launcherStorageReadLabelValues...,
+		)
+		tryToPushMetric(metrics.launcherStorageDesc, mv, err, ch)
+
+		var launcherStorageWriteLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, processStats.Type, "write"}
+		launcherStorageWriteLabelValues = append(launcherStorageWriteLabelValues, k8sLabelValues...)
+		mv, err = prometheus.NewConstMetric(
+			metrics.launcherStorageDesc, prometheus.CounterValue,
+			float64(processStats.WriteBytes),
+			launcherStorageWriteLabelValues...,
+		)
+		tryToPushMetric(metrics.launcherStorageDesc, mv, err, ch)
+	}
+}
+
+func (metrics *vmiMetrics) updateIOThreads(vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats, ch chan<- prometheus.Metric, k8sLabels []string, k8sLabelValues []string) {
+	if len(vmStats.IOThreads) == 0 {
+		return
+	}
+
+	var ioThreadCPULabels = []string{"node", "namespace", "name", "domain", "iothread"}
+	ioThreadCPULabels = append(ioThreadCPULabels, k8sLabels...)
+	metrics.ioThreadCPUDesc = prometheus.NewDesc(
+		"kubevirt_vmi_storage_iothread_cpu_seconds_total",
+		"cpu time consumed by the storage iothreads of the vmi.",
+		ioThreadCPULabels,
+		nil,
+	)
+
+	for _, ioThreadStats := range vmStats.IOThreads {
+		var ioThreadLabelValues = []string{vmi.Status.NodeName, vmi.Namespace, vmi.Name, vmStats.Name, fmt.Sprintf("%d", ioThreadStats.ID)}
+		ioThreadLabelValues = append(ioThreadLabelValues, k8sLabelValues...)
+
+		mv, err := prometheus.NewConstMetric(
+			metrics.ioThreadCPUDesc, prometheus.CounterValue,
+			ioThreadStats.CPUTime,
+			ioThreadLabelValues...,
+		)
+		tryToPushMetric(metrics.ioThreadCPUDesc, mv, err, ch)
+	}
 }
 
 func makeVMIsPhasesMap(vmis []*k6tv1.VirtualMachineInstance) map[string]uint64 {
@@ -463,6 +881,124 @@ func updateVMIsPhase(nodeName string, vmis []*k6tv1.VirtualMachineInstance, ch c
 	}
 }
 
+func updateVMIsPodInfo(nodeName string, vmis []*k6tv1.VirtualMachineInstance, pods []*k8sv1.Pod, ch chan<- prometheus.Metric) {
+	podsByUID := make(map[types.UID]*k8sv1.Pod)
+	for _, pod := range pods {
+		podsByUID[pod.UID] = pod
+	}
+
+	for _, vmi := range vmis {
+		// During a migration the source and the target pod are both active,
+		// only report the one on this node
+		for podUID, podNodeName := range vmi.Status.ActivePods {
+			pod, exists := podsByUID[podUID]
+			if !exists || podNodeName != nodeName {
+				continue
+			}
+			mv, err := prometheus.NewConstMetric(
+				vmiPodInfoDesc, prometheus.GaugeValue,
+				1.0,
+				vmi.Namespace, vmi.Name, pod.Name, nodeName,
+			)
+			tryToPushMetric(vmiPodInfoDesc, mv, err, ch)
+		}
+	}
+}
+
+// updateMediatedDevices reports the capacity and the allocated mediated devices
+// per type, summed up over all parent devices of the node
+func updateMediatedDevices(nodeName string, mdevTypes []hardware.MediatedDeviceType, ch chan<- prometheus.Metric) {
+	type mdevCount struct {
+		name      string
+		capacity  int
+		allocated int
+	}
+	counts := map[string]*mdevCount{}
+	for _, mdevType := range mdevTypes {
+		count, exists := counts[mdevType.ID]
+		if !exists {
+			count = &mdevCount{name: mdevType.Name}
+			counts[mdevType.ID] = count
+		}
+		count.capacity += mdevType.AvailableInstances + len(mdevType.Devices)
+		count.allocated += len(mdevType.Devices)
+	}
+
+	for typeID, count := range counts {
+		mv, err := prometheus.NewConstMetric(
+			mdevCapacityDesc, prometheus.GaugeValue,
+			float64(count.capacity),
+			nodeName, typeID, count.name,
+		)
+		tryToPushMetric(mdevCapacityDesc, mv, err, ch)
+
+		mv, err = prometheus.NewConstMetric(
+			mdevAllocatedDesc, prometheus.GaugeValue,
+			float64(count.allocated),
+			nodeName, typeID, count.name,
+		)
+		tryToPushMetric(mdevAllocatedDesc, mv, err, ch)
+	}
+}
+
+// updateKSM reports the state of kernel samepage merging on the node
+func updateKSM(nodeName string, ksmStats *hardware.KSMStats, ch chan<- prometheus.Metric) {
+	running := 0.0
+	if ksmStats.Running {
+		running = 1.0
+	}
+	mv, err := prometheus.NewConstMetric(ksmRunningDesc, prometheus.GaugeValue, running, nodeName)
+	tryToPushMetric(ksmRunningDesc, mv, err, ch)
+
+	mv, err = prometheus.NewConstMetric(ksmPagesSharedDesc, prometheus.GaugeValue, float64(ksmStats.PagesShared), nodeName)
+	tryToPushMetric(ksmPagesSharedDesc, mv, err, ch)
+
+	mv, err = prometheus.NewConstMetric(ksmPagesSharingDesc, prometheus.GaugeValue, float64(ksmStats.PagesSharing), nodeName)
+	tryToPushMetric(ksmPagesSharingDesc, mv, err, ch)
+
+	mv, err = prometheus.NewConstMetric(ksmPagesUnsharedDesc, prometheus.GaugeValue, float64(ksmStats.PagesUnshared), nodeName)
+	tryToPushMetric(ksmPagesUnsharedDesc, mv, err, ch)
+
+	mv, err = prometheus.NewConstMetric(ksmFullScansDesc, prometheus.CounterValue, float64(ksmStats.FullScans), nodeName)
+	tryToPushMetric(ksmFullScansDesc, mv, err, ch)
+}
+
+// launcherPods returns the launcher pods of the node known to the informer
+func launcherPods(podStore cache.Store) []*k8sv1.Pod {
+	var pods []*k8sv1.Pod
+	for _, obj := range podStore.List() {
+		if pod, ok := obj.(*k8sv1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// virtualMachinesOnNode lists the VMIs of the node, the request is cancelled
+// once the context is done
+func virtualMachinesOnNode(ctx context.Context, virtCli kubecli.KubevirtClient, nodeName string) ([]*k6tv1.VirtualMachineInstance, error) {
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s in (%s)", k6tv1.NodeNameLabel, nodeName))
+	if err != nil {
+		return nil, err
+	}
+	list := &k6tv1.VirtualMachineInstanceList{}
+	err = virtCli.RestClient().Get().
+		Resource("virtualmachineinstances").
+		VersionedParams(&metav1.ListOptions{LabelSelector: labelSelector.String()}, scheme.ParameterCodec).
+		Context(ctx).
+		Do().
+		Into(list)
+	if err != nil {
+		return nil, err
+	}
+
+	vmis := []*k6tv1.VirtualMachineInstance{}
+	for i := range list.Items {
+		vmis = append(vmis, &list.Items[i])
+	}
+	return vmis, nil
+}
+
 func updateVersion(ch chan<- prometheus.Metric) {
 	verinfo := version.Get()
 	ch <- prometheus.MustNewConstMetric(
@@ -476,13 +1012,29 @@ type vmiMetrics struct {
 	storageIopsDesc         *prometheus.Desc
 	storageTrafficDesc      *prometheus.Desc
 	storageTimesDesc        *prometheus.Desc
+	storageThrottledDesc    *prometheus.Desc
+	storageCapacityDesc     *prometheus.Desc
 	vcpuUsageDesc           *prometheus.Desc
+	legacyVcpuUsageDesc     *prometheus.Desc
+	legacyStorageTimesDesc  *prometheus.Desc
+	cpuPressureSomeDesc     *prometheus.Desc
+	cpuPressureFullDesc     *prometheus.Desc
 	networkTrafficBytesDesc *prometheus.Desc
 	networkTrafficPktsDesc  *prometheus.Desc
 	networkErrorsDesc       *prometheus.Desc
+	networkThrottledDesc    *prometheus.Desc
+	connLimitDroppedDesc    *prometheus.Desc
+	launcherCPUDesc         *prometheus.Desc
+	launcherMemoryDesc      *prometheus.Desc
+	launcherStorageDesc     *prometheus.Desc
+	ioThreadCPUDesc         *prometheus.Desc
 	memoryAvailableDesc     *prometheus.Desc
 	memoryResidentDesc      *prometheus.Desc
+	memoryHugepagesDesc     *prometheus.Desc
+	memoryDirtyRateDesc     *prometheus.Desc
 	swapTrafficDesc         *prometheus.Desc
+
+	naming *metricNaming
 }
 
 func newVmiMetrics() *vmiMetrics {
@@ -490,22 +1042,39 @@ func newVmiMetrics() *vmiMetrics {
 }
 
 type Collector struct {
-	virtCli       kubecli.KubevirtClient
-	virtShareDir  string
-	nodeName      string
-	concCollector *concurrentCollector
+	virtCli      kubecli.KubevirtClient
+	virtShareDir string
+	nodeName     string
+	podStore     cache.Store
+	mdevBusPath  string
+	ksmPath      string
+	statsCache   *domainStatsCache
+
+	namingMode func() k6tv1.MetricsNamingMode
+	deprecated *deprecatedMetricsTracker
 }
 
-func SetupCollector(virtCli kubecli.KubevirtClient, virtShareDir, nodeName string, MaxRequestsInFlight int) *Collector {
+// SetupCollector registers the collector of the metrics of the node and its VMIs on the registerer.
+// Handler collects it for every scrape itself, so it must not be registered on the default registry
+// if Handler serves it. podStore holds the launcher pods of the node. namingMode tells whether the renamed metrics are exposed under their
+// deprecated names, their new names or both when Handler serves them.
+func SetupCollector(registerer prometheus.Registerer, virtCli kubecli.KubevirtClient, virtShareDir, nodeName string, podStore cache.Store, namingMode func() k6tv1.MetricsNamingMode) (*Collector, error) {
 	log.Log.Infof("Starting collector: node name=%v", nodeName)
 	co := &Collector{
-		virtCli:       virtCli,
-		virtShareDir:  virtShareDir,
-		nodeName:      nodeName,
-		concCollector: NewConcurrentCollector(MaxRequestsInFlight),
+		virtCli:      virtCli,
+		virtShareDir: virtShareDir,
+		nodeName:     nodeName,
+		podStore:     podStore,
+		mdevBusPath:  hardware.MdevBusPath,
+		ksmPath:      hardware.KSMPath,
+		statsCache:   newDomainStatsCache(statsStreamInterval, cmdclient.NewClient),
+		namingMode:   namingMode,
+		deprecated:   newDeprecatedMetricsTracker(),
+	}
+	if err := registerer.Register(co); err != nil {
+		return nil, fmt.Errorf("failed to register the collector: %v", err)
 	}
-	prometheus.MustRegister(co)
-	return co
+	return co, nil
 }
 
 func (co *Collector) Describe(ch chan<- *prometheus.Desc) {
@@ -532,30 +1101,64 @@ func newvmiSocketMapFromVMIs(baseDir string, vmis []*k6tv1.VirtualMachineInstanc
 }
 
 // Note that Collect could be called concurrently
+// Collect collects the metrics for gatherers which do not tell a timeout, it is bounded by the
+// default scrape timeout and only exposes the new names of the renamed metrics
 func (co *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultScrapeTimeout-scrapeTimeoutOffset)
+	defer cancel()
+	co.collect(ctx, ch, nil)
+}
+
+// collect collects the metrics of the node and its VMIs, the requests to the apiserver are
+// cancelled once the context is done. naming selects the names of the renamed metrics.
+func (co *Collector) collect(ctx context.Context, ch chan<- prometheus.Metric, naming *metricNaming) {
 	updateVersion(ch)
 
-	vmis, err := lookup.VirtualMachinesOnNode(co.virtCli, co.nodeName)
+	mdevTypes, err := hardware.LookupMediatedDeviceTypes(co.mdevBusPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to look up the mediated devices in '%s': %s", co.nodeName, err)
+	} else {
+		updateMediatedDevices(co.nodeName, mdevTypes, ch)
+	}
+
+	ksmStats, err := hardware.LookupKSMStats(co.ksmPath)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to look up the KSM state in '%s': %s", co.nodeName, err)
+	} else if ksmStats != nil {
+		updateKSM(co.nodeName, ksmStats, ch)
+	}
+
+	vmis, err := virtualMachinesOnNode(ctx, co.virtCli, co.nodeName)
 	if err != nil {
 		log.Log.Reason(err).Errorf("failed to list all VMIs in '%s': %s", co.nodeName, err)
 		return
 	}
 
+	// the launchers push their stats on streams, which live as long as the VMIs
+	socketToVMIs := newvmiSocketMapFromVMIs(co.virtShareDir, vmis)
+	co.statsCache.Sync(socketToVMIs)
+
 	if len(vmis) == 0 {
 		log.Log.V(4).Infof("No VMIs detected")
 		return
 	}
 
-	socketToVMIs := newvmiSocketMapFromVMIs(co.virtShareDir, vmis)
-	scraper := &prometheusScraper{ch: ch}
-	co.concCollector.Collect(socketToVMIs, scraper, collectionTimeout)
+	scraper := &prometheusScraper{ch: ch, naming: naming}
+	for socketFile, vmi := range socketToVMIs {
+		if vmStats, exists := co.statsCache.Get(socketFile); exists {
+			scraper.Report(socketFile, vmi, vmStats)
+		}
+	}
 
 	updateVMIsPhase(co.nodeName, vmis, ch)
-	return
+
+	updateVMIsPodInfo(co.nodeName, vmis, launcherPods(co.podStore), ch)
 }
 
 type prometheusScraper struct {
 	ch chan<- prometheus.Metric
+	// naming selects the names of the renamed metrics, only the new names are exposed without it
+	naming *metricNaming
 }
 
 type vmiStatsInfo struct {
@@ -563,72 +1166,76 @@ type vmiStatsInfo struct {
 	vmiStats *stats.DomainStats
 }
 
-func (ps *prometheusScraper) Scrape(socketFile string, vmi *k6tv1.VirtualMachineInstance) {
-	ts := time.Now()
-	cli, err := cmdclient.NewClient(socketFile)
-	if err != nil {
-		log.Log.Reason(err).Error("failed to connect to cmd client socket")
-		// Ignore failure to connect to client.
-		// These are all local connections via unix socket.
-		// A failure to connect means there's nothing on the other
-		// end listening.
-		return
-	}
-	defer cli.Close()
-
-	vmStats, exists, err := cli.GetDomainStats()
-	if err != nil {
-		log.Log.Reason(err).Errorf("failed to update stats from socket %s", socketFile)
-		return
-	}
-	if !exists || vmStats.Name == "" {
-		log.Log.V(2).Infof("disappearing VM on %s, ignored", socketFile) // VM may be shutting down
-		return
-	}
-
-	// GetDomainStats() may hang for a long time.
-	// If it wakes up past the timeout, there is no point in send back any metric.
-	// In the best case the information is stale, in the worst case the information is stale *and*
-	// the reporting channel is already closed, leading to a possible panic - see below
-	elapsed := time.Now().Sub(ts)
-	if elapsed > statsMaxAge {
-		log.Log.Infof("took too long (%v) to collect stats from %s: ignored", elapsed, socketFile)
-		return
-	}
-
-	ps.Report(socketFile, vmi, vmStats)
-}
-
 func (ps *prometheusScraper) Report(socketFile string, vmi *k6tv1.VirtualMachineInstance, vmStats *stats.DomainStats) {
-	// statsMaxAge is an estimation - and there is not better way to do that. So it is possible that
-	// GetDomainStats() takes enough time to lag behind, but not enough to trigger the statsMaxAge check.
-	// In this case the next functions will end up writing on a closed channel. This will panic.
-	// It is actually OK in this case to abort the goroutine that panicked -that's what we want anyway,
-	// and the very reason we collect in throwaway goroutines. We need however to avoid dump stacktraces in the logs.
-	// Since this is a known failure condition, let's handle it explicitely.
-	defer func() {
-		if err := recover(); err != nil {
-			log.Log.V(2).Warningf("collector goroutine panicked for VM %s: %s", socketFile, err)
-		}
-	}()
-
 	vmiMetrics := newVmiMetrics()
+	vmiMetrics.naming = ps.naming
 	k8sLabels, k8sLabelValues := updateKubernetesLabels(vmi)
 
 	vmiMetrics.updateMemory(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
 	vmiMetrics.updateVcpu(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
+	vmiMetrics.updateCPUPressure(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
 	vmiMetrics.updateBlock(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
 	vmiMetrics.updateNetwork(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
+	vmiMetrics.updateConnLimit(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
+	vmiMetrics.updateProcesses(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
+	vmiMetrics.updateIOThreads(vmi, vmStats, ps.ch, k8sLabels, k8sLabelValues)
+}
+
+// scrapeCollector collects the metrics of a collector for a single scrape
+type scrapeCollector struct {
+	co  *Collector
+	ctx context.Context
+}
+
+func (c *scrapeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.co.Describe(ch)
+}
+
+func (c *scrapeCollector) Collect(ch chan<- prometheus.Metric) {
+	naming := newMetricNaming(c.co.namingMode())
+	c.co.collect(c.ctx, ch, naming)
+	c.co.deprecated.observe(naming.scrapedDeprecatedNames())
 }
 
-func Handler(MaxRequestsInFlight int) http.Handler {
+// scrapeTimeout returns how long a scrape may take, from the timeout
+// Prometheus sends along with it
+func scrapeTimeout(r *http.Request) time.Duration {
+	timeout := defaultScrapeTimeout
+	if seconds, err := strconv.ParseFloat(r.Header.Get(scrapeTimeoutHeader), 64); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if timeout > 2*scrapeTimeoutOffset {
+		timeout -= scrapeTimeoutOffset
+	}
+	return timeout
+}
+
+// Handler serves the metrics of the default gatherer and of the collector. The collector is
+// collected with the context of each scrape, which ends with the timeout of the scrape.
+func Handler(maxRequestsInFlight int, co *Collector) http.Handler {
+	var inFlight chan struct{}
+	if maxRequestsInFlight > 0 {
+		inFlight = make(chan struct{}, maxRequestsInFlight)
+	}
 	return promhttp.InstrumentMetricHandler(
 		prometheus.DefaultRegisterer,
-		promhttp.HandlerFor(
-			prometheus.DefaultGatherer,
-			promhttp.HandlerOpts{
-				MaxRequestsInFlight: MaxRequestsInFlight,
-			}),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if inFlight != nil {
+				select {
+				case inFlight <- struct{}{}:
+					defer func() { <-inFlight }()
+				default:
+					http.Error(w, fmt.Sprintf("Limit of concurrent requests reached (%d), try again later.", maxRequestsInFlight), http.StatusServiceUnavailable)
+					return
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout(r))
+			defer cancel()
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(&scrapeCollector{co: co, ctx: ctx})
+			promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, registry}, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+		}),
 	)
 }
 
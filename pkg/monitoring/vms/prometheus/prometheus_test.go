@@ -20,15 +20,22 @@
 package prometheus
 
 import (
+	"strings"
+
+	k8sv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	k6tv1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
 )
 
@@ -37,29 +44,6 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = Describe("Prometheus", func() {
-	Context("on blocked source", func() {
-		It("should handle closed reporting socket", func() {
-			ch := make(chan prometheus.Metric)
-			close(ch)
-
-			ps := prometheusScraper{ch: ch}
-
-			testReportPanic := func() {
-				vmStats := &stats.DomainStats{
-					Cpu: &stats.DomainStatsCPU{},
-					Memory: &stats.DomainStatsMemory{
-						// trigger write on a socket. We need a value set - any value
-						RSS:    1024,
-						RSSSet: true,
-					},
-				}
-				vmi := k6tv1.VirtualMachineInstance{}
-				ps.Report("test", &vmi, vmStats)
-			}
-			Expect(testReportPanic).ToNot(Panic())
-		})
-	})
-
 	Context("on handling push", func() {
 		It("should send rss", func() {
 			ch := make(chan prometheus.Metric, 1)
@@ -170,7 +154,100 @@ var _ = Describe("Prometheus", func() {
 
 			result := <-ch
 			Expect(result).ToNot(BeNil())
-			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_seconds"))
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_seconds_total"))
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(float64(0.000002)))
+		})
+
+		It("should expose the deprecated vcpu seconds gauge next to the counter in the both naming mode", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch, naming: newMetricNaming(k6tv1.MetricsNamingBoth)}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Vcpu: []stats.DomainStatsVcpu{
+					{
+						StateSet: true,
+						State:    1,
+						TimeSet:  true,
+						Time:     3000000000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_seconds_total"))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring(`"kubevirt_vmi_vcpu_seconds"`))
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(3)))
+		})
+
+		It("should only expose the deprecated vcpu seconds gauge in the legacy naming mode", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			naming := newMetricNaming(k6tv1.MetricsNamingLegacy)
+			ps := prometheusScraper{ch: ch, naming: naming}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Vcpu: []stats.DomainStatsVcpu{
+					{
+						StateSet: true,
+						State:    1,
+						TimeSet:  true,
+						Time:     3500000000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			Expect(ch).To(HaveLen(1))
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring(`"kubevirt_vmi_vcpu_seconds"`))
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(3)))
+			Expect(naming.scrapedDeprecatedNames()).To(ConsistOf("kubevirt_vmi_vcpu_seconds"))
+		})
+
+		It("should not expose the deprecated vcpu seconds gauge in the current naming mode", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch, naming: newMetricNaming(k6tv1.MetricsNamingCurrent)}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Vcpu: []stats.DomainStatsVcpu{
+					{
+						StateSet: true,
+						State:    1,
+						TimeSet:  true,
+						Time:     3500000000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			Expect(ch).To(HaveLen(1))
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_seconds_total"))
 		})
 
 		It("should not expose vcpu metrics for invalid DomainStats", func() {
@@ -330,7 +407,7 @@ var _ = Describe("Prometheus", func() {
 
 			result := <-ch
 			Expect(result).ToNot(BeNil())
-			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_times_ms_total"))
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_times_seconds_total"))
 		})
 
 		It("should handle block write time metrics", func() {
@@ -357,7 +434,72 @@ var _ = Describe("Prometheus", func() {
 
 			result := <-ch
 			Expect(result).ToNot(BeNil())
-			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_times_ms_total"))
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_times_seconds_total"))
+		})
+
+		It("should handle block capacity metrics", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Block: []stats.DomainStatsBlock{
+					{
+						NameSet:     true,
+						Name:        "vda",
+						CapacitySet: true,
+						Capacity:    2147483648,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_capacity_bytes"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(2147483648)))
+		})
+
+		It("should handle block throttling metrics", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				BlockThrottle: []stats.DomainStatsBlockThrottle{
+					{
+						Name:  "vda",
+						Limit: stats.IOTuneTotalIopsSec,
+						Hits:  3,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_throttled_total"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(float64(3)))
+			labels := map[string]string{}
+			for _, label := range dto.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels).To(HaveKeyWithValue("drive", "vda"))
+			Expect(labels).To(HaveKeyWithValue("limit", "total_iops_sec"))
 		})
 
 		It("should not expose nameless block metrics", func() {
@@ -545,6 +687,238 @@ var _ = Describe("Prometheus", func() {
 			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_network_errors_total"))
 		})
 
+		It("should handle connection limit drop metrics", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				ConnLimit: []stats.DomainStatsConnLimit{
+					{
+						Name:    "default",
+						Reason:  "max-connections",
+						Dropped: 1000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result).ToNot(BeNil())
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_network_connection_limit_dropped_total"))
+		})
+
+		It("should handle launcher process metrics", func() {
+			ch := make(chan prometheus.Metric, 4)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Processes: []stats.DomainStatsProcesses{
+					{
+						Type:           stats.ProcessTypeQemu,
+						CPUTime:        10,
+						ResidentMemory: 1024,
+						ReadBytes:      2048,
+						WriteBytes:     4096,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_launcher_cpu_seconds_total"))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_launcher_memory_resident_bytes"))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_launcher_storage_traffic_bytes_total"))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_launcher_storage_traffic_bytes_total"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(float64(4096)))
+		})
+
+		It("should handle iothread metrics", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				IOThreads: []stats.DomainStatsIOThread{
+					{ID: 1, CPUTime: 10},
+					{ID: 2, CPUTime: 20},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_iothread_cpu_seconds_total"))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_iothread_cpu_seconds_total"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(float64(20)))
+		})
+
+		It("should handle hugepages metrics of VMIs with hugepages", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Hugepages: []stats.DomainStatsHugepages{
+					{PageSize: 2 << 20, Usage: 64 << 20},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			vmi.Spec.Domain.Memory = &k6tv1.Memory{Hugepages: &k6tv1.Hugepages{PageSize: "2Mi"}}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_memory_hugepages_bytes"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(64 << 20)))
+			labels := map[string]string{}
+			for _, label := range dto.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels).To(HaveKeyWithValue("page_size", "2Mi"))
+		})
+
+		It("should handle the dirty rate metrics", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:       &stats.DomainStatsCPU{},
+				Memory:    &stats.DomainStatsMemory{},
+				DirtyRate: &stats.DomainStatsDirtyRate{BytesPerSecond: 64 << 20},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_dirty_rate_bytes_per_second"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(64 << 20)))
+		})
+
+		It("should handle the cpu pressure metrics", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				CPUPressure: &stats.DomainStatsPressure{
+					Some:    1.5,
+					FullSet: true,
+					Full:    0.25,
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_cpu_pressure_some_seconds_total"))
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(1.5))
+
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_cpu_pressure_full_seconds_total"))
+			dto = &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(0.25))
+		})
+
+		It("should not report the full cpu pressure where the kernel does not track it", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:         &stats.DomainStatsCPU{},
+				Memory:      &stats.DomainStatsMemory{},
+				CPUPressure: &stats.DomainStatsPressure{Some: 1.5},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_cpu_pressure_some_seconds_total"))
+			Expect(ch).To(BeEmpty())
+		})
+
+		It("should handle network throttling metrics", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			ps := prometheusScraper{ch: ch}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				NetThrottle: []stats.DomainStatsNetThrottle{
+					{
+						Name:      "vnet0",
+						Type:      stats.NetTypeTx,
+						Throttled: 7,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_network_throttled_packets_total"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetCounter().GetValue()).To(Equal(float64(7)))
+			labels := map[string]string{}
+			for _, label := range dto.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels).To(HaveKeyWithValue("interface", "vnet0"))
+			Expect(labels).To(HaveKeyWithValue("type", "tx"))
+		})
+
 		It("should not expose nameless network interface metrics", func() {
 			ch := make(chan prometheus.Metric, 1)
 			defer close(ch)
@@ -619,7 +993,92 @@ var _ = Describe("Prometheus", func() {
 
 			result := <-ch
 			Expect(result).ToNot(BeNil())
-			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_wait_seconds"))
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_vcpu_wait_seconds_total"))
+		})
+
+		It("should only expose the deprecated names in the legacy naming mode", func() {
+			ch := make(chan prometheus.Metric, 10)
+			defer close(ch)
+
+			naming := newMetricNaming(k6tv1.MetricsNamingLegacy)
+			ps := prometheusScraper{ch: ch, naming: naming}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{},
+				Vcpu: []stats.DomainStatsVcpu{
+					{
+						WaitSet: true,
+						Wait:    6000000,
+					},
+				},
+				Block: []stats.DomainStatsBlock{
+					{
+						NameSet:    true,
+						Name:       "vda",
+						RdTimesSet: true,
+						RdTimes:    1000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+
+			Expect(ch).To(HaveLen(2))
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring(`"kubevirt_vmi_vcpu_wait_seconds"`))
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			Expect(dto.GetGauge().GetValue()).To(Equal(float64(6)))
+			result = <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_storage_times_ms_total"))
+			Expect(naming.scrapedDeprecatedNames()).To(ConsistOf("kubevirt_vmi_vcpu_wait_seconds", "kubevirt_vmi_storage_times_ms_total"))
+		})
+
+		It("should follow the naming conventions in the current naming mode", func() {
+			ch := make(chan prometheus.Metric, 20)
+
+			ps := prometheusScraper{ch: ch, naming: newMetricNaming(k6tv1.MetricsNamingCurrent)}
+
+			vmStats := &stats.DomainStats{
+				Cpu:    &stats.DomainStatsCPU{},
+				Memory: &stats.DomainStatsMemory{SwapInSet: true, SwapIn: 1024},
+				Vcpu: []stats.DomainStatsVcpu{
+					{
+						StateSet: true,
+						State:    1,
+						TimeSet:  true,
+						Time:     2000,
+						WaitSet:  true,
+						Wait:     6,
+					},
+				},
+				Block: []stats.DomainStatsBlock{
+					{
+						NameSet:    true,
+						Name:       "vda",
+						RdTimesSet: true,
+						RdTimes:    1000,
+					},
+				},
+			}
+
+			vmi := k6tv1.VirtualMachineInstance{}
+			ps.Report("test", &vmi, vmStats)
+			close(ch)
+
+			Expect(ch).ToNot(BeEmpty())
+			for result := range ch {
+				dto := &io_prometheus_client.Metric{}
+				Expect(result.Write(dto)).To(Succeed())
+				name := result.Desc().String()
+				if dto.Counter != nil {
+					Expect(name).To(MatchRegexp(`fqName: "[a-z_]+_total"`))
+				} else {
+					Expect(name).ToNot(MatchRegexp(`fqName: "[a-z_]+_total"`))
+				}
+			}
 		})
 	})
 })
@@ -684,4 +1143,154 @@ var _ = Describe("Utility functions", func() {
 			Expect(phasesMap["bogus"]).To(Equal(uint64(0))) // intentionally bogus key
 		})
 	})
+
+	Context("VMI pod info reporting", func() {
+		It("should report the launcher pod on this node", func() {
+			ch := make(chan prometheus.Metric, 2)
+			defer close(ch)
+
+			vmis := []*k6tv1.VirtualMachineInstance{
+				&k6tv1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+					},
+					Status: k6tv1.VirtualMachineInstanceStatus{
+						ActivePods: map[types.UID]string{
+							"source-uid": "node01",
+							"target-uid": "node02",
+						},
+					},
+				},
+			}
+			podStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			Expect(podStore.Add(&k8sv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "virt-launcher-testvmi-abcde",
+					Namespace: "default",
+					UID:       "source-uid",
+				},
+			})).To(Succeed())
+			pods := launcherPods(podStore)
+
+			updateVMIsPodInfo("node01", vmis, pods, ch)
+
+			Expect(ch).To(HaveLen(1))
+			result := <-ch
+			Expect(result.Desc().String()).To(ContainSubstring("kubevirt_vmi_pod_info"))
+
+			dto := &io_prometheus_client.Metric{}
+			Expect(result.Write(dto)).To(Succeed())
+			labels := map[string]string{}
+			for _, label := range dto.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels).To(Equal(map[string]string{
+				"namespace": "default",
+				"vmi":       "testvmi",
+				"pod":       "virt-launcher-testvmi-abcde",
+				"node":      "node01",
+			}))
+			Expect(dto.GetGauge().GetValue()).To(Equal(1.0))
+		})
+
+		It("should not report VMIs without a known launcher pod", func() {
+			ch := make(chan prometheus.Metric, 1)
+			defer close(ch)
+
+			vmis := []*k6tv1.VirtualMachineInstance{
+				&k6tv1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+					},
+					Status: k6tv1.VirtualMachineInstanceStatus{
+						ActivePods: map[types.UID]string{
+							"gone-uid": "node01",
+						},
+					},
+				},
+			}
+
+			updateVMIsPodInfo("node01", vmis, nil, ch)
+			Expect(ch).To(BeEmpty())
+		})
+	})
+
+	Context("mediated device reporting", func() {
+		It("should report the capacity and the allocated devices per type", func() {
+			ch := make(chan prometheus.Metric, 4)
+			defer close(ch)
+
+			mdevTypes := []hardware.MediatedDeviceType{
+				{ID: "nvidia-222", Name: "GRID T4-1Q", ParentAddress: "0000:65:00.0", AvailableInstances: 14, Devices: []string{"4b20d080-1b54-4048-85b3-a6a62d165c01", "1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11"}},
+				{ID: "nvidia-222", Name: "GRID T4-1Q", ParentAddress: "0000:66:00.0", AvailableInstances: 16},
+			}
+
+			updateMediatedDevices("node01", mdevTypes, ch)
+
+			Expect(ch).To(HaveLen(2))
+			values := map[string]float64{}
+			for i := 0; i < 2; i++ {
+				result := <-ch
+				dto := &io_prometheus_client.Metric{}
+				Expect(result.Write(dto)).To(Succeed())
+				labels := map[string]string{}
+				for _, label := range dto.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				Expect(labels).To(Equal(map[string]string{
+					"node":      "node01",
+					"mdev_type": "nvidia-222",
+					"name":      "GRID T4-1Q",
+				}))
+				if strings.Contains(result.Desc().String(), "kubevirt_node_mdev_capacity") {
+					values["capacity"] = dto.GetGauge().GetValue()
+				} else {
+					values["allocated"] = dto.GetGauge().GetValue()
+				}
+			}
+			Expect(values).To(Equal(map[string]float64{"capacity": 32, "allocated": 2}))
+		})
+	})
+
+	Context("KSM reporting", func() {
+		It("should report the state of KSM on the node", func() {
+			ch := make(chan prometheus.Metric, 5)
+			defer close(ch)
+
+			ksmStats := &hardware.KSMStats{
+				Running:       true,
+				PagesShared:   1024,
+				PagesSharing:  8192,
+				PagesUnshared: 512,
+				FullScans:     42,
+			}
+
+			updateKSM("node01", ksmStats, ch)
+
+			Expect(ch).To(HaveLen(5))
+			values := map[string]float64{}
+			for i := 0; i < 5; i++ {
+				result := <-ch
+				dto := &io_prometheus_client.Metric{}
+				Expect(result.Write(dto)).To(Succeed())
+				Expect(dto.GetLabel()).To(HaveLen(1))
+				Expect(dto.GetLabel()[0].GetValue()).To(Equal("node01"))
+				name := strings.Split(result.Desc().String(), "\"")[1]
+				if dto.GetCounter() != nil {
+					values[name] = dto.GetCounter().GetValue()
+				} else {
+					values[name] = dto.GetGauge().GetValue()
+				}
+			}
+			Expect(values).To(Equal(map[string]float64{
+				"kubevirt_node_ksm_running":          1,
+				"kubevirt_node_ksm_pages_shared":     1024,
+				"kubevirt_node_ksm_pages_sharing":    8192,
+				"kubevirt_node_ksm_pages_unshared":   512,
+				"kubevirt_node_ksm_full_scans_total": 42,
+			}))
+		})
+	})
 })
@@ -0,0 +1,25 @@
+package testutils
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewMetricsRegistry returns an empty registry for the unit tests of collectors, instead of
+// the default registry of the process. It checks the consistency of the collected metrics.
+func NewMetricsRegistry() *prometheus.Registry {
+	return prometheus.NewPedanticRegistry()
+}
+
+// GatherMetricFamilies gathers the metrics of the registry and returns how many metrics
+// of each family it got, by the name of the family
+func GatherMetricFamilies(registry prometheus.Gatherer) (map[string]int, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, family := range families {
+		counts[family.GetName()] = len(family.Metric)
+	}
+	return counts, nil
+}
@@ -3,6 +3,7 @@ package testutils
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
@@ -37,6 +38,8 @@ type MockWorkQueue struct {
 	addWG            *sync.WaitGroup
 	rateLimitedEnque int32
 	wgLock           sync.Mutex
+	// the delays of the delayed enqueues, by item
+	addAfterDelays map[interface{}]time.Duration
 }
 
 func (q *MockWorkQueue) Add(obj interface{}) {
@@ -57,6 +60,21 @@ func (q *MockWorkQueue) GetRateLimitedEnqueueCount() int {
 	return int(atomic.LoadInt32(&q.rateLimitedEnque))
 }
 
+func (q *MockWorkQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.RateLimitingInterface.AddAfter(item, duration)
+	q.wgLock.Lock()
+	defer q.wgLock.Unlock()
+	q.addAfterDelays[item] = duration
+}
+
+// GetAddAfterDelay returns the delay of the last delayed enqueue of the item
+func (q *MockWorkQueue) GetAddAfterDelay(item interface{}) (time.Duration, bool) {
+	q.wgLock.Lock()
+	defer q.wgLock.Unlock()
+	duration, exists := q.addAfterDelays[item]
+	return duration, exists
+}
+
 // ExpectAdds allows setting the amount of expected enqueues.
 func (q *MockWorkQueue) ExpectAdds(diff int) {
 	q.wgLock.Lock()
@@ -75,7 +93,7 @@ func (q *MockWorkQueue) Wait() {
 }
 
 func NewMockWorkQueue(queue workqueue.RateLimitingInterface) *MockWorkQueue {
-	return &MockWorkQueue{queue, nil, 0, sync.Mutex{}}
+	return &MockWorkQueue{queue, nil, 0, sync.Mutex{}, map[interface{}]time.Duration{}}
 }
 
 func NewFakeInformerFor(obj runtime.Object) (cache.SharedIndexInformer, *framework.FakeControllerSource) {
@@ -0,0 +1,91 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package tpm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+const (
+	// StateVolumeName is the name of the pod volume which carries the TPM state claim
+	StateVolumeName = "virt-tpm-state"
+)
+
+var (
+	// StateSourceDir is where the TPM state claim is mounted in the virt-launcher pod
+	StateSourceDir = "/var/run/kubevirt-private/tpm-state"
+
+	// swtpmStorageDir is where libvirt keeps the swtpm state of its domains
+	swtpmStorageDir = "/var/lib/libvirt/swtpm"
+)
+
+// stateLinkName is the directory swtpm uses for the state of TPM 2.0 devices
+const stateLinkName = "tpm2"
+
+// HasPersistentState returns true if the TPM state of the vmi has to be kept on a claim
+func HasPersistentState(vmi *v1.VirtualMachineInstance) bool {
+	tpm := vmi.Spec.Domain.Devices.TPM
+	return tpm != nil && tpm.PersistentStateClaimName != ""
+}
+
+// PrepareStorage points the swtpm state directory of the domain at the TPM state claim.
+// libvirt derives the state directory from the domain UUID, so a UUID is assigned to
+// the domain here if it does not have one yet.
+//
+// The state directory is replaced with a symlink: libvirt removes the swtpm storage of
+// a domain when it is undefined, but its tree removal only unlinks symlinks instead of
+// following them, which keeps the state on the claim intact.
+func PrepareStorage(vmi *v1.VirtualMachineInstance, domain *api.Domain) error {
+	if !HasPersistentState(vmi) {
+		return nil
+	}
+
+	if domain.Spec.UUID == "" {
+		if vmi.Spec.Domain.Firmware != nil && vmi.Spec.Domain.Firmware.UUID != "" {
+			domain.Spec.UUID = string(vmi.Spec.Domain.Firmware.UUID)
+		} else {
+			domain.Spec.UUID = string(uuid.NewUUID())
+		}
+	}
+
+	domainDir := filepath.Join(swtpmStorageDir, domain.Spec.UUID)
+	if err := os.MkdirAll(domainDir, 0711); err != nil {
+		return fmt.Errorf("failed to create the swtpm storage directory: %v", err)
+	}
+
+	link := filepath.Join(domainDir, stateLinkName)
+	if target, err := os.Readlink(link); err == nil && target == StateSourceDir {
+		return nil
+	}
+	if err := os.RemoveAll(link); err != nil {
+		return fmt.Errorf("failed to remove the swtpm state directory: %v", err)
+	}
+	if err := os.Symlink(StateSourceDir, link); err != nil {
+		return fmt.Errorf("failed to link the TPM state claim: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package tpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+var _ = Describe("TPM", func() {
+	var tmpDir string
+	var origStateSourceDir string
+	var origSwtpmStorageDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "tpm")
+		Expect(err).ToNot(HaveOccurred())
+
+		origStateSourceDir = StateSourceDir
+		origSwtpmStorageDir = swtpmStorageDir
+		StateSourceDir = filepath.Join(tmpDir, "tpm-state")
+		swtpmStorageDir = filepath.Join(tmpDir, "swtpm")
+		Expect(os.MkdirAll(StateSourceDir, 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		StateSourceDir = origStateSourceDir
+		swtpmStorageDir = origSwtpmStorageDir
+		os.RemoveAll(tmpDir)
+	})
+
+	newVMI := func(claimName string) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Spec.Domain.Devices.TPM = &v1.TPMDevice{PersistentStateClaimName: claimName}
+		return vmi
+	}
+
+	It("should only report persistent state if a claim is set", func() {
+		Expect(HasPersistentState(v1.NewMinimalVMI("testvmi"))).To(BeFalse())
+		Expect(HasPersistentState(newVMI(""))).To(BeFalse())
+		Expect(HasPersistentState(newVMI("tpm-claim"))).To(BeTrue())
+	})
+
+	It("should leave the domain untouched without persistent state", func() {
+		domain := &api.Domain{}
+		Expect(PrepareStorage(newVMI(""), domain)).To(Succeed())
+		Expect(domain.Spec.UUID).To(BeEmpty())
+		_, err := os.Stat(swtpmStorageDir)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should use the firmware UUID as domain UUID", func() {
+		vmi := newVMI("tpm-claim")
+		vmi.Spec.Domain.Firmware = &v1.Firmware{UUID: types.UID("5d307ca9-b3ef-428c-8861-06e72d69f223")}
+		domain := &api.Domain{}
+
+		Expect(PrepareStorage(vmi, domain)).To(Succeed())
+		Expect(domain.Spec.UUID).To(Equal("5d307ca9-b3ef-428c-8861-06e72d69f223"))
+	})
+
+	It("should link the swtpm state directory to the claim", func() {
+		domain := &api.Domain{}
+		Expect(PrepareStorage(newVMI("tpm-claim"), domain)).To(Succeed())
+		Expect(domain.Spec.UUID).ToNot(BeEmpty())
+
+		link := filepath.Join(swtpmStorageDir, domain.Spec.UUID, "tpm2")
+		target, err := os.Readlink(link)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal(StateSourceDir))
+
+		// the link has to survive a second preparation
+		Expect(PrepareStorage(newVMI("tpm-claim"), domain)).To(Succeed())
+		target, err = os.Readlink(link)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal(StateSourceDir))
+	})
+
+	It("should replace a state directory created by libvirt", func() {
+		domain := &api.Domain{}
+		domain.Spec.UUID = "5d307ca9-b3ef-428c-8861-06e72d69f223"
+		stateDir := filepath.Join(swtpmStorageDir, domain.Spec.UUID, "tpm2")
+		Expect(os.MkdirAll(stateDir, 0755)).To(Succeed())
+
+		Expect(PrepareStorage(newVMI("tpm-claim"), domain)).To(Succeed())
+		target, err := os.Readlink(stateDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal(StateSourceDir))
+	})
+})
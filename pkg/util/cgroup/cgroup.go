@@ -0,0 +1,221 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package cgroup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"kubevirt.io/kubevirt/pkg/util/hardware"
+)
+
+// Root is where the cgroup hierarchy is mounted, on the nodes and in the pods
+const Root = "/sys/fs/cgroup"
+
+type Version int
+
+const (
+	// V1 are the per controller hierarchies, including the hybrid mode
+	V1 Version = 1
+	// V2 is the unified hierarchy
+	V2 Version = 2
+)
+
+// UnsupportedError is returned for operations the cgroup of a pod does not support
+type UnsupportedError struct {
+	Operation string
+	Reason    string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported: %s", e.Operation, e.Reason)
+}
+
+func IsUnsupported(err error) bool {
+	_, ok := err.(*UnsupportedError)
+	return ok
+}
+
+// DetectVersion detects the version of the cgroup hierarchy mounted at root
+func DetectVersion(root string) (Version, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(root, &stat); err != nil {
+		return 0, fmt.Errorf("failed to detect the cgroup version of %s: %v", root, err)
+	}
+	if stat.Type == unix.CGROUP2_SUPER_MAGIC {
+		return V2, nil
+	}
+	return V1, nil
+}
+
+// Pressure is the pressure stall information of a resource of a cgroup
+type Pressure struct {
+	// Some is the time in microseconds in which at least one task stalled on the resource
+	Some uint64
+	// Full is the time in microseconds in which all non-idle tasks stalled at once,
+	// older kernels do not report it for the CPU
+	FullSet bool
+	Full    uint64
+}
+
+// Manager reads and changes the cgroup of a pod, in the same way on both versions
+type Manager interface {
+	Version() Version
+	// GetCpuSet returns the CPUs the processes of the cgroup may run on
+	GetCpuSet() ([]int, error)
+	// GetCPUPressure returns the time the processes of the cgroup stalled waiting for a CPU
+	GetCPUPressure() (*Pressure, error)
+}
+
+type manager struct {
+	root    string
+	version Version
+}
+
+// NewManager returns a manager of the cgroup mounted at root, as the pod sees it
+func NewManager(root string) (Manager, error) {
+	version, err := DetectVersion(root)
+	if err != nil {
+		return nil, err
+	}
+	return newManager(root, version), nil
+}
+
+func newManager(root string, version Version) Manager {
+	return &manager{root: root, version: version}
+}
+
+func (m *manager) Version() Version {
+	return m.version
+}
+
+func (m *manager) GetCpuSet() ([]int, error) {
+	path := filepath.Join(m.root, "cpuset", "cpuset.cpus")
+	if m.version == V2 {
+		// the unified hierarchy only has the files of the controllers enabled for the cgroup
+		enabled, err := m.isControllerEnabled("cpuset")
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, &UnsupportedError{Operation: "reading the cpuset", Reason: "the cpuset controller is not enabled in the cgroup v2 hierarchy of the pod"}
+		}
+		path = filepath.Join(m.root, "cpuset.cpus.effective")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cpuset: %v", err)
+	}
+	cpus, err := hardware.ParseCPUSetLine(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the cpuset: %v", err)
+	}
+	return cpus, nil
+}
+
+func (m *manager) GetCPUPressure() (*Pressure, error) {
+	operation := "reading the CPU pressure"
+	if m.version != V2 {
+		return nil, &UnsupportedError{Operation: operation, Reason: "the pressure stall information is only tracked per cgroup on cgroup v2"}
+	}
+
+	f, err := os.Open(filepath.Join(m.root, "cpu.pressure"))
+	if os.IsNotExist(err) {
+		return nil, &UnsupportedError{Operation: operation, Reason: "the kernel does not track the pressure stall information"}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the CPU pressure: %v", err)
+	}
+	defer f.Close()
+	return parsePressure(f)
+}
+
+// parsePressure parses the content of a pressure file, like
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=1234
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(r io.Reader) (*Pressure, error) {
+	pressure := &Pressure{}
+	someSet := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var total *uint64
+		switch fields[0] {
+		case "some":
+			total = &pressure.Some
+			someSet = true
+		case "full":
+			total = &pressure.Full
+			pressure.FullSet = true
+		default:
+			continue
+		}
+
+		totalSet := false
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "total=") {
+				continue
+			}
+			value, err := strconv.ParseUint(strings.TrimPrefix(field, "total="), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the pressure: %v", err)
+			}
+			*total = value
+			totalSet = true
+		}
+		if !totalSet {
+			return nil, fmt.Errorf("failed to parse the pressure: no total in %q", scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the pressure: %v", err)
+	}
+	if !someSet {
+		return nil, fmt.Errorf("failed to parse the pressure: no stall time of some tasks")
+	}
+	return pressure, nil
+}
+
+func (m *manager) isControllerEnabled(controller string) (bool, error) {
+	content, err := ioutil.ReadFile(filepath.Join(m.root, "cgroup.controllers"))
+	if err != nil {
+		return false, fmt.Errorf("failed to read the cgroup controllers: %v", err)
+	}
+	for _, c := range strings.Fields(string(content)) {
+		if c == controller {
+			return true, nil
+		}
+	}
+	return false, nil
+}
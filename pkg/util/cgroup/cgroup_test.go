@@ -0,0 +1,122 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cgroup", func() {
+
+	var root string
+
+	writeFile := func(path string, content string) {
+		path = filepath.Join(root, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "cgroup")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("should detect other file systems than cgroup2 as v1", func() {
+		Expect(DetectVersion(root)).To(Equal(V1))
+	})
+
+	It("should fail to detect the version of a missing hierarchy", func() {
+		_, err := DetectVersion(filepath.Join(root, "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("on cgroup v1", func() {
+		It("should read the cpuset of the cpuset controller", func() {
+			writeFile("cpuset/cpuset.cpus", "2-3,6\n")
+			Expect(newManager(root, V1).GetCpuSet()).To(Equal([]int{2, 3, 6}))
+		})
+
+		It("should fail without the cpuset controller", func() {
+			_, err := newManager(root, V1).GetCpuSet()
+			Expect(err).To(HaveOccurred())
+			Expect(IsUnsupported(err)).To(BeFalse())
+		})
+
+		It("should not support reading the CPU pressure", func() {
+			writeFile("cpu,cpuacct/cpu.pressure", "some avg10=0.00 avg60=0.00 avg300=0.00 total=1234\n")
+			_, err := newManager(root, V1).GetCPUPressure()
+			Expect(IsUnsupported(err)).To(BeTrue())
+		})
+	})
+
+	Context("on cgroup v2", func() {
+		It("should read the effective cpuset", func() {
+			writeFile("cgroup.controllers", "cpuset cpu io memory pids\n")
+			writeFile("cpuset.cpus.effective", "4-5\n")
+			Expect(newManager(root, V2).GetCpuSet()).To(Equal([]int{4, 5}))
+		})
+
+		It("should not support reading the cpuset without the cpuset controller", func() {
+			writeFile("cgroup.controllers", "cpu io memory pids\n")
+			_, err := newManager(root, V2).GetCpuSet()
+			Expect(IsUnsupported(err)).To(BeTrue())
+			Expect(err).To(MatchError("reading the cpuset is not supported: the cpuset controller is not enabled in the cgroup v2 hierarchy of the pod"))
+		})
+
+		It("should read the CPU pressure", func() {
+			writeFile("cpu.pressure", "some avg10=1.50 avg60=0.75 avg300=0.20 total=1234\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=56\n")
+			Expect(newManager(root, V2).GetCPUPressure()).To(Equal(&Pressure{Some: 1234, FullSet: true, Full: 56}))
+		})
+
+		It("should not support reading the CPU pressure on kernels without pressure stall information", func() {
+			_, err := newManager(root, V2).GetCPUPressure()
+			Expect(IsUnsupported(err)).To(BeTrue())
+		})
+	})
+
+	Context("parsing the pressure", func() {
+		It("should accept kernels which do not report the full stall time of the CPU", func() {
+			pressure, err := parsePressure(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=1234\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pressure).To(Equal(&Pressure{Some: 1234}))
+		})
+
+		It("should fail without the stall time of some tasks", func() {
+			_, err := parsePressure(strings.NewReader("full avg10=0.00 avg60=0.00 avg300=0.00 total=56\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on a malformed total", func() {
+			_, err := parsePressure(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=x\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,82 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// KSMPath is where the kernel exposes the controls and statistics of kernel samepage merging
+const KSMPath = "/sys/kernel/mm/ksm"
+
+// KSMStats holds the state of kernel samepage merging on the host
+type KSMStats struct {
+	// Running tells if the KSM daemon merges pages
+	Running bool
+	// PagesShared is the number of shared pages which are in use
+	PagesShared int64
+	// PagesSharing is the number of page mappings which point to a shared page
+	PagesSharing int64
+	// PagesUnshared is the number of pages which are unique, but repeatedly checked for merging
+	PagesUnshared int64
+	// FullScans is the number of times all mergeable memory areas have been scanned
+	FullScans int64
+}
+
+// LookupKSMStats reads the state of KSM below ksmPath. It returns nil if the kernel does not support KSM.
+func LookupKSMStats(ksmPath string) (*KSMStats, error) {
+	if _, err := os.Stat(ksmPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	values := map[string]int64{}
+	for _, name := range []string{"run", "pages_shared", "pages_sharing", "pages_unshared", "full_scans"} {
+		content, err := ioutil.ReadFile(filepath.Join(ksmPath, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read KSM %s: %v", name, err)
+		}
+		values[name], err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KSM %s: %v", name, err)
+		}
+	}
+
+	return &KSMStats{
+		Running:       values["run"] == 1,
+		PagesShared:   values["pages_shared"],
+		PagesSharing:  values["pages_sharing"],
+		PagesUnshared: values["pages_unshared"],
+		FullScans:     values["full_scans"],
+	}, nil
+}
+
+// SetKSMRunning starts or stops the KSM daemon. Stopping it keeps the pages which are already merged.
+func SetKSMRunning(ksmPath string, running bool) error {
+	run := "0"
+	if running {
+		run = "1"
+	}
+	return ioutil.WriteFile(filepath.Join(ksmPath, "run"), []byte(run), 0644)
+}
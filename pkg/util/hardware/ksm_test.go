@@ -0,0 +1,83 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KSM", func() {
+	var ksmPath string
+
+	writeFile := func(name string, content string) {
+		Expect(ioutil.WriteFile(filepath.Join(ksmPath, name), []byte(content+"\n"), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		ksmPath, err = ioutil.TempDir("", "ksm")
+		Expect(err).ToNot(HaveOccurred())
+
+		writeFile("run", "1")
+		writeFile("pages_shared", "1024")
+		writeFile("pages_sharing", "8192")
+		writeFile("pages_unshared", "512")
+		writeFile("full_scans", "42")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(ksmPath)
+	})
+
+	It("should read the KSM statistics", func() {
+		stats, err := LookupKSMStats(ksmPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats).To(Equal(&KSMStats{
+			Running:       true,
+			PagesShared:   1024,
+			PagesSharing:  8192,
+			PagesUnshared: 512,
+			FullScans:     42,
+		}))
+	})
+
+	It("should report nothing on hosts without KSM", func() {
+		stats, err := LookupKSMStats(filepath.Join(ksmPath, "missing"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats).To(BeNil())
+	})
+
+	It("should start and stop the KSM daemon", func() {
+		Expect(SetKSMRunning(ksmPath, false)).To(Succeed())
+		stats, err := LookupKSMStats(ksmPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats.Running).To(BeFalse())
+
+		Expect(SetKSMRunning(ksmPath, true)).To(Succeed())
+		stats, err = LookupKSMStats(ksmPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stats.Running).To(BeTrue())
+	})
+})
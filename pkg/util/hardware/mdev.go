@@ -0,0 +1,87 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MdevBusPath is where the kernel lists the host devices which support mediated devices
+const MdevBusPath = "/sys/class/mdev_bus"
+
+// MediatedDeviceType is a mediated device type which a parent device of the host supports
+type MediatedDeviceType struct {
+	// ID of the type, e.g. "nvidia-222"
+	ID string
+	// Name of the type, e.g. "GRID T4-1Q"
+	Name string
+	// ParentAddress is the PCI address of the parent device
+	ParentAddress string
+	// AvailableInstances is the number of mediated devices which can still be created
+	AvailableInstances int
+	// Devices are the UUIDs of the created mediated devices
+	Devices []string
+}
+
+// LookupMediatedDeviceTypes reads the mediated device types of all parent devices below mdevBusPath
+func LookupMediatedDeviceTypes(mdevBusPath string) ([]MediatedDeviceType, error) {
+	typeDirs, err := filepath.Glob(filepath.Join(mdevBusPath, "*", "mdev_supported_types", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var mdevTypes []MediatedDeviceType
+	for _, typeDir := range typeDirs {
+		mdevType := MediatedDeviceType{
+			ID:            filepath.Base(typeDir),
+			ParentAddress: filepath.Base(filepath.Dir(filepath.Dir(typeDir))),
+		}
+
+		// the name of a type is optional
+		mdevType.Name = mdevType.ID
+		if content, err := ioutil.ReadFile(filepath.Join(typeDir, "name")); err == nil {
+			mdevType.Name = strings.TrimSpace(string(content))
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(typeDir, "available_instances"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the available instances of mdev type %s: %v", mdevType.ID, err)
+		}
+		mdevType.AvailableInstances, err = strconv.Atoi(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the available instances of mdev type %s: %v", mdevType.ID, err)
+		}
+
+		devices, err := ioutil.ReadDir(filepath.Join(typeDir, "devices"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the devices of mdev type %s: %v", mdevType.ID, err)
+		}
+		for _, device := range devices {
+			mdevType.Devices = append(mdevType.Devices, device.Name())
+		}
+
+		mdevTypes = append(mdevTypes, mdevType)
+	}
+	return mdevTypes, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mediated devices", func() {
+	var mdevBusPath string
+
+	addType := func(parent string, id string, name string, available string, devices ...string) {
+		dir := filepath.Join(mdevBusPath, parent, "mdev_supported_types", id)
+		Expect(os.MkdirAll(filepath.Join(dir, "devices"), 0755)).To(Succeed())
+		if name != "" {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "name"), []byte(name+"\n"), 0644)).To(Succeed())
+		}
+		Expect(ioutil.WriteFile(filepath.Join(dir, "available_instances"), []byte(available+"\n"), 0644)).To(Succeed())
+		for _, device := range devices {
+			Expect(os.MkdirAll(filepath.Join(dir, "devices", device), 0755)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		mdevBusPath, err = ioutil.TempDir("", "mdev_bus")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(mdevBusPath)
+	})
+
+	It("should read the mediated device types of all parent devices", func() {
+		addType("0000:65:00.0", "nvidia-222", "GRID T4-1B", "14", "4b20d080-1b54-4048-85b3-a6a62d165c01", "1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11")
+		addType("0000:65:00.0", "nvidia-223", "GRID T4-2B", "8")
+		addType("0000:00:02.0", "i915-GVTg_V5_4", "", "1")
+
+		mdevTypes, err := LookupMediatedDeviceTypes(mdevBusPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mdevTypes).To(ConsistOf(
+			MediatedDeviceType{
+				ID: "nvidia-222", Name: "GRID T4-1B", ParentAddress: "0000:65:00.0", AvailableInstances: 14,
+				Devices: []string{"1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11", "4b20d080-1b54-4048-85b3-a6a62d165c01"},
+			},
+			MediatedDeviceType{ID: "nvidia-223", Name: "GRID T4-2B", ParentAddress: "0000:65:00.0", AvailableInstances: 8},
+			MediatedDeviceType{ID: "i915-GVTg_V5_4", Name: "i915-GVTg_V5_4", ParentAddress: "0000:00:02.0", AvailableInstances: 1},
+		))
+	})
+
+	It("should report no types on hosts without mediated devices", func() {
+		mdevTypes, err := LookupMediatedDeviceTypes(filepath.Join(mdevBusPath, "missing"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mdevTypes).To(BeEmpty())
+	})
+
+	It("should fail on unparsable available instances", func() {
+		addType("0000:65:00.0", "nvidia-222", "GRID T4-1B", "many")
+		_, err := LookupMediatedDeviceTypes(mdevBusPath)
+		Expect(err).To(HaveOccurred())
+	})
+})
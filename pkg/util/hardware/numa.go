@@ -0,0 +1,149 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANodesPath is where the kernel exposes the NUMA nodes of the host
+const NUMANodesPath = "/sys/devices/system/node"
+
+// NUMANode is a NUMA node of the host
+type NUMANode struct {
+	ID   int
+	CPUs []int
+}
+
+// NUMACell groups the vCPUs of a VMI whose pCPUs belong to the same host NUMA node
+type NUMACell struct {
+	// HostNode is the ID of the host NUMA node
+	HostNode int
+	// VCPUs are the indices of the vCPUs
+	VCPUs []int
+	// Memory of the cell in bytes
+	Memory int64
+}
+
+// LookupNUMATopology reads the NUMA nodes of the host below nodesPath,
+// sorted by their ID
+func LookupNUMATopology(nodesPath string) ([]NUMANode, error) {
+	dirs, err := filepath.Glob(filepath.Join(nodesPath, "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CPUs of NUMA node %d: %v", id, err)
+		}
+		node := NUMANode{ID: id}
+		// memory-only nodes have no CPUs
+		if cpuList := strings.TrimSpace(string(content)); cpuList != "" {
+			if node.CPUs, err = ParseCPUSetLine(cpuList); err != nil {
+				return nil, fmt.Errorf("failed to parse the CPUs of NUMA node %d: %v", id, err)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no NUMA nodes found in %s", nodesPath)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes, nil
+}
+
+// FreeHugepages returns the number of free hugepages of pageSize bytes on a
+// NUMA node of the host
+func FreeHugepages(nodesPath string, node int, pageSize int64) (int64, error) {
+	path := filepath.Join(nodesPath, fmt.Sprintf("node%d", node), "hugepages", fmt.Sprintf("hugepages-%dkB", pageSize/1024), "free_hugepages")
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// GetNUMACells groups the vCPUs of a VMI by the host NUMA node of their pCPU,
+// vCPU i runs on cpus[i], and splits memory evenly between the groups in
+// multiples of pageSize. The cells are sorted by the ID of the host NUMA node.
+func GetNUMACells(nodes []NUMANode, cpus []int, memory int64, pageSize int64) ([]NUMACell, error) {
+	nodeOfCPU := map[int]int{}
+	for _, node := range nodes {
+		for _, cpu := range node.CPUs {
+			nodeOfCPU[cpu] = node.ID
+		}
+	}
+
+	cellOfNode := map[int]*NUMACell{}
+	for vcpu, cpu := range cpus {
+		node, exists := nodeOfCPU[cpu]
+		if !exists {
+			return nil, fmt.Errorf("CPU %d does not belong to any NUMA node", cpu)
+		}
+		cell, exists := cellOfNode[node]
+		if !exists {
+			cell = &NUMACell{HostNode: node}
+			cellOfNode[node] = cell
+		}
+		cell.VCPUs = append(cell.VCPUs, vcpu)
+	}
+	if len(cellOfNode) == 0 {
+		return nil, fmt.Errorf("no CPUs to place on NUMA nodes")
+	}
+
+	if pageSize <= 0 || memory%pageSize != 0 {
+		return nil, fmt.Errorf("memory of %d bytes is not a multiple of the page size %d", memory, pageSize)
+	}
+	pages := memory / pageSize
+	count := int64(len(cellOfNode))
+	if pages < count {
+		return nil, fmt.Errorf("memory of %d bytes can not be split into %d NUMA cells", memory, count)
+	}
+
+	cells := make([]NUMACell, 0, len(cellOfNode))
+	for _, node := range nodes {
+		if cell, exists := cellOfNode[node.ID]; exists {
+			cells = append(cells, *cell)
+		}
+	}
+	for i := range cells {
+		cellPages := pages / count
+		if int64(i) < pages%count {
+			cellPages++
+		}
+		cells[i].Memory = cellPages * pageSize
+	}
+	return cells, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NUMA topology", func() {
+	var nodesPath string
+
+	addNode := func(id string, cpuList string, freeHugepages string) {
+		dir := filepath.Join(nodesPath, "node"+id)
+		Expect(os.MkdirAll(filepath.Join(dir, "hugepages", "hugepages-2048kB"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "cpulist"), []byte(cpuList+"\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "hugepages", "hugepages-2048kB", "free_hugepages"), []byte(freeHugepages+"\n"), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		nodesPath, err = ioutil.TempDir("", "node")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(nodesPath)
+	})
+
+	It("should read the NUMA nodes sorted by their ID", func() {
+		addNode("1", "4-7", "0")
+		addNode("0", "0-3", "0")
+		addNode("2", "", "0")
+		Expect(os.MkdirAll(filepath.Join(nodesPath, "power"), 0755)).To(Succeed())
+
+		nodes, err := LookupNUMATopology(nodesPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodes).To(Equal([]NUMANode{
+			{ID: 0, CPUs: []int{0, 1, 2, 3}},
+			{ID: 1, CPUs: []int{4, 5, 6, 7}},
+			{ID: 2},
+		}))
+	})
+
+	It("should fail without NUMA nodes", func() {
+		_, err := LookupNUMATopology(nodesPath)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the free hugepages of a NUMA node", func() {
+		addNode("1", "4-7", "12")
+
+		free, err := FreeHugepages(nodesPath, 1, 2*1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(free).To(Equal(int64(12)))
+
+		_, err = FreeHugepages(nodesPath, 1, 1024*1024*1024)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("NUMA cells", func() {
+		nodes := []NUMANode{
+			{ID: 0, CPUs: []int{0, 1, 2, 3}},
+			{ID: 1, CPUs: []int{4, 5, 6, 7}},
+		}
+		pageSize := int64(2 * 1024 * 1024)
+
+		It("should group the vCPUs by host NUMA node and split the memory", func() {
+			cells, err := GetNUMACells(nodes, []int{5, 1, 6, 2, 7}, 5*pageSize, pageSize)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cells).To(Equal([]NUMACell{
+				{HostNode: 0, VCPUs: []int{1, 3}, Memory: 3 * pageSize},
+				{HostNode: 1, VCPUs: []int{0, 2, 4}, Memory: 2 * pageSize},
+			}))
+		})
+
+		It("should create a single cell if all CPUs are on one host NUMA node", func() {
+			cells, err := GetNUMACells(nodes, []int{4, 5}, 4*pageSize, pageSize)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cells).To(Equal([]NUMACell{
+				{HostNode: 1, VCPUs: []int{0, 1}, Memory: 4 * pageSize},
+			}))
+		})
+
+		It("should reject CPUs outside of the NUMA nodes", func() {
+			_, err := GetNUMACells(nodes, []int{1, 8}, 2*pageSize, pageSize)
+			Expect(err).To(MatchError("CPU 8 does not belong to any NUMA node"))
+		})
+
+		It("should reject memory which is not a multiple of the page size", func() {
+			_, err := GetNUMACells(nodes, []int{1}, pageSize+1, pageSize)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject memory which is too small for the cells", func() {
+			_, err := GetNUMACells(nodes, []int{1, 5}, pageSize, pageSize)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
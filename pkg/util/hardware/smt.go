@@ -0,0 +1,157 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+// CPUPath is where the kernel exposes the CPUs of the host
+const CPUPath = "/sys/devices/system/cpu"
+
+// LookupThreadSiblings reads the hyperthreads sharing a core with each of the
+// given CPUs below cpuPath. The siblings of a CPU include the CPU itself.
+func LookupThreadSiblings(cpuPath string, cpus []int) (map[int][]int, error) {
+	siblings := map[int][]int{}
+	for _, cpu := range cpus {
+		content, err := ioutil.ReadFile(filepath.Join(cpuPath, fmt.Sprintf("cpu%d", cpu), "topology", "thread_siblings_list"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the thread siblings of CPU %d: %v", cpu, err)
+		}
+		if siblings[cpu], err = ParseCPUSetLine(strings.TrimSpace(string(content))); err != nil {
+			return nil, fmt.Errorf("failed to parse the thread siblings of CPU %d: %v", cpu, err)
+		}
+	}
+	return siblings, nil
+}
+
+// ThreadsPerCore returns the number of hyperthreads of the core of the first
+// CPU below cpuPath
+func ThreadsPerCore(cpuPath string) (int, error) {
+	siblings, err := LookupThreadSiblings(cpuPath, []int{0})
+	if err != nil {
+		return 0, err
+	}
+	return len(siblings[0]), nil
+}
+
+// PlaceDedicatedCPUs picks the pCPUs for vcpus vCPUs out of cpus, following the
+// thread policy, vCPU i is pinned to the returned pCPU i. Only cores whose
+// hyperthreads all belong to cpus are used. With fullCores the siblings of a
+// core are handed out one after the other, with isolate only the first sibling
+// of every core is used. The emulator thread CPU is picked among the remaining
+// CPUs, preferring the ones which do not share a core with a vCPU.
+func PlaceDedicatedCPUs(cpus []int, siblings map[int][]int, policy v1.CPUThreadPolicy, vcpus int, emulatorThread bool) ([]int, *int, error) {
+	owned := map[int]bool{}
+	for _, cpu := range cpus {
+		owned[cpu] = true
+	}
+
+	var placed []int
+	var cores [][]int
+	seen := map[int]bool{}
+	for _, cpu := range cpus {
+		if seen[cpu] {
+			continue
+		}
+		seen[cpu] = true
+		core := siblings[cpu]
+		complete := len(core) > 0
+		for _, sibling := range core {
+			seen[sibling] = true
+			if !owned[sibling] {
+				complete = false
+			}
+		}
+		if complete && len(placed) < vcpus {
+			switch policy {
+			case v1.CPUThreadPolicyFullCores:
+				placed = append(placed, core...)
+			case v1.CPUThreadPolicyIsolate:
+				placed = append(placed, core[0])
+			default:
+				return nil, nil, fmt.Errorf("unknown thread policy %s", policy)
+			}
+			cores = append(cores, core)
+		}
+	}
+	if len(placed) < vcpus {
+		return nil, nil, fmt.Errorf("the CPUs %v do not contain enough full cores for %d vCPUs with the %s thread policy", cpus, vcpus, policy)
+	}
+	placed = placed[:vcpus]
+
+	if !emulatorThread {
+		return placed, nil, nil
+	}
+	used := map[int]bool{}
+	for _, core := range cores {
+		for _, cpu := range core {
+			used[cpu] = true
+		}
+	}
+	var fallback *int
+	for i, cpu := range cpus {
+		if !used[cpu] {
+			return placed, &cpus[i], nil
+		}
+		if fallback == nil && !contains(placed, cpu) {
+			fallback = &cpus[i]
+		}
+	}
+	if fallback == nil {
+		return nil, nil, fmt.Errorf("no CPU left for the emulator thread")
+	}
+	return placed, fallback, nil
+}
+
+// DedicatedCPUs splits the cpuset of the launcher pod of a VMI with dedicated
+// CPUs into the pCPUs of the vCPUs, in the order of the vCPUs, and the pCPU of
+// the emulator thread if it is isolated. Without a thread policy the last CPU
+// is reserved for the emulator thread.
+func DedicatedCPUs(cpuPath string, vmi *v1.VirtualMachineInstance, cpus []int) ([]int, *int, error) {
+	isolateEmulatorThread := vmi.GetEmulatorThreadPolicy() == v1.EmulatorThreadPolicyIsolate
+
+	if cpu := vmi.Spec.Domain.CPU; cpu != nil && cpu.ThreadPolicy != nil {
+		siblings, err := LookupThreadSiblings(cpuPath, cpus)
+		if err != nil {
+			return nil, nil, err
+		}
+		return PlaceDedicatedCPUs(cpus, siblings, *cpu.ThreadPolicy, int(GetNumberOfVCPUs(cpu)), isolateEmulatorThread)
+	}
+
+	if isolateEmulatorThread && len(cpus) > 0 {
+		return cpus[:len(cpus)-1], &cpus[len(cpus)-1], nil
+	}
+	return cpus, nil, nil
+}
+
+func contains(cpus []int, cpu int) bool {
+	for _, c := range cpus {
+		if c == cpu {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package hardware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("SMT topology", func() {
+	// two threads per core, CPU n and n+4 share a core
+	siblings := map[int][]int{
+		0: {0, 4}, 1: {1, 5}, 2: {2, 6}, 3: {3, 7},
+		4: {0, 4}, 5: {1, 5}, 6: {2, 6}, 7: {3, 7},
+	}
+
+	It("should read the thread siblings of the CPUs", func() {
+		cpuPath, err := ioutil.TempDir("", "cpu")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(cpuPath)
+		for _, cpu := range []int{0, 4} {
+			dir := filepath.Join(cpuPath, fmt.Sprintf("cpu%d", cpu), "topology")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "thread_siblings_list"), []byte("0,4\n"), 0644)).To(Succeed())
+		}
+
+		threads, err := ThreadsPerCore(cpuPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(threads).To(Equal(2))
+
+		_, err = LookupThreadSiblings(cpuPath, []int{1})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pin the vCPUs to the siblings of full cores", func() {
+		placed, emulatorThreadCpu, err := PlaceDedicatedCPUs([]int{0, 1, 4, 5, 6}, siblings, v1.CPUThreadPolicyFullCores, 4, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placed).To(Equal([]int{0, 4, 1, 5}))
+		Expect(*emulatorThreadCpu).To(Equal(6))
+	})
+
+	It("should pin every vCPU to a core of its own", func() {
+		placed, emulatorThreadCpu, err := PlaceDedicatedCPUs([]int{0, 1, 2, 4, 5, 6}, siblings, v1.CPUThreadPolicyIsolate, 2, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placed).To(Equal([]int{0, 1}))
+		Expect(emulatorThreadCpu).To(BeNil())
+	})
+
+	It("should reserve the last CPU for an isolated emulator thread without a thread policy", func() {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Spec.Domain.CPU = &v1.CPU{Cores: 2, DedicatedCPUPlacement: true, IsolateEmulatorThread: true}
+
+		vcpus, emulatorThreadCpu, err := DedicatedCPUs("/nonexistent", vmi, []int{2, 3, 4})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vcpus).To(Equal([]int{2, 3}))
+		Expect(*emulatorThreadCpu).To(Equal(4))
+	})
+
+	It("should fail if there are not enough full cores", func() {
+		_, _, err := PlaceDedicatedCPUs([]int{0, 1, 2, 4}, siblings, v1.CPUThreadPolicyIsolate, 2, false)
+		Expect(err).To(HaveOccurred())
+	})
+})
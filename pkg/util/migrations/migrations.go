@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
@@ -27,3 +28,34 @@ func FilterRunningMigrations(migrations []v1.VirtualMachineInstanceMigration) []
 	}
 	return runningMigrations
 }
+
+// VMIEvictionStrategy returns the eviction strategy of the VMI, VMIs without
+// one are shut off when their pod is evicted
+func VMIEvictionStrategy(vmi *v1.VirtualMachineInstance) v1.EvictionStrategy {
+	if vmi.Spec.EvictionStrategy == nil {
+		return v1.EvictionStrategyNone
+	}
+	return *vmi.Spec.EvictionStrategy
+}
+
+// IsMigratable returns true if virt-handler reported that the VMI can be live migrated
+func IsMigratable(vmi *v1.VirtualMachineInstance) bool {
+	for _, c := range vmi.Status.Conditions {
+		if c.Type == v1.VirtualMachineInstanceIsMigratable {
+			return c.Status == k8sv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// MigrateOnEviction returns true if the VMI should be migrated away instead of
+// being shut off when its pod is evicted
+func MigrateOnEviction(vmi *v1.VirtualMachineInstance) bool {
+	switch VMIEvictionStrategy(vmi) {
+	case v1.EvictionStrategyLiveMigrate:
+		return true
+	case v1.EvictionStrategyLiveMigrateIfPossible:
+		return IsMigratable(vmi)
+	}
+	return false
+}
@@ -68,6 +68,17 @@ func IsPVCShared(pvc *k8sv1.PersistentVolumeClaim) bool {
 	return false
 }
 
+// IsPVCMultiAttachable returns true if the PVC can be attached to pods on many nodes at
+// the same time, at least read-only
+func IsPVCMultiAttachable(pvc *k8sv1.PersistentVolumeClaim) bool {
+	for _, accessMode := range pvc.Spec.AccessModes {
+		if accessMode == k8sv1.ReadWriteMany || accessMode == k8sv1.ReadOnlyMany {
+			return true
+		}
+	}
+	return false
+}
+
 func IsSharedPVCFromClient(client kubecli.KubevirtClient, namespace string, claimName string) (pvc *k8sv1.PersistentVolumeClaim, isShared bool, err error) {
 	pvc, err = client.CoreV1().PersistentVolumeClaims(namespace).Get(claimName, v1.GetOptions{})
 	if err == nil {
@@ -154,4 +154,21 @@ var _ = Describe("PVC utils test", func() {
 		})
 	})
 
+	Context("PVC multi attach test", func() {
+
+		It("should detect multi attachable PVCs", func() {
+			pvc := kubev1.PersistentVolumeClaim{}
+			Expect(IsPVCMultiAttachable(&pvc)).To(BeFalse())
+
+			pvc.Spec.AccessModes = []kubev1.PersistentVolumeAccessMode{kubev1.ReadWriteOnce}
+			Expect(IsPVCMultiAttachable(&pvc)).To(BeFalse())
+
+			pvc.Spec.AccessModes = []kubev1.PersistentVolumeAccessMode{kubev1.ReadWriteOnce, kubev1.ReadOnlyMany}
+			Expect(IsPVCMultiAttachable(&pvc)).To(BeTrue())
+
+			pvc.Spec.AccessModes = []kubev1.PersistentVolumeAccessMode{kubev1.ReadWriteMany}
+			Expect(IsPVCMultiAttachable(&pvc)).To(BeTrue())
+		})
+	})
+
 })
@@ -1,6 +1,9 @@
 package util
 
 import (
+	"fmt"
+	"strings"
+
 	v1 "kubevirt.io/client-go/api/v1"
 )
 
@@ -11,10 +14,22 @@ const VirtPrivateDir = "/var/run/kubevirt-private"
 const NetworkInfoDir = VirtPrivateDir + "/network-info-cache"
 const VirtLibDir = "/var/lib/kubevirt"
 const KubeletPodsDir = "/var/lib/kubelet/pods"
+const KubeletSeccompDir = "/var/lib/kubelet/seccomp"
 const HostRootMount = "/proc/1/root/"
+const PCIResourcePrefix = "PCI_RESOURCE"
+const MDEVResourcePrefix = "MDEV_PCI_RESOURCE"
+const MacvtapResourcePrefix = "MACVTAP_RESOURCE"
 const CPUManagerOS3Path = HostRootMount + "var/lib/origin/openshift.local.volumes/cpu_manager_state"
 const CPUManagerPath = HostRootMount + "var/lib/kubelet/cpu_manager_state"
 
+// KubeVirtSeccompProfile is the path of the kubevirt/default seccomp profile virt-handler
+// installs, relative to the seccomp directory of the kubelet
+const KubeVirtSeccompProfile = "kubevirt/default.json"
+
+// NonRootUID is the uid of the qemu user of the virt-launcher image, non-root
+// virt-launchers run qemu and libvirt as this user
+const NonRootUID = 107
+
 var VMIInterfaceDir = NetworkInfoDir + "/%s"
 var VMIInterfacepath = NetworkInfoDir + "/%s/%s"
 
@@ -42,3 +57,41 @@ func IsQATVMI(vmi *v1.VirtualMachineInstance) bool {
 	}
 	return false
 }
+
+// Check if a VMI spec requests host devices
+func IsHostDevVMI(vmi *v1.VirtualMachineInstance) bool {
+	if vmi.Spec.Domain.Devices.HostDevices != nil && len(vmi.Spec.Domain.Devices.HostDevices) != 0 {
+		return true
+	}
+	return false
+}
+
+// Check if a VMI spec requests memory encryption with AMD SEV
+func IsSEVVMI(vmi *v1.VirtualMachineInstance) bool {
+	return vmi.Spec.Domain.LaunchSecurity != nil && vmi.Spec.Domain.LaunchSecurity.SEV != nil
+}
+
+// Check if a VMI spec requests filesystems shared through virtio-fs
+func IsVMIVirtiofsEnabled(vmi *v1.VirtualMachineInstance) bool {
+	for _, fs := range vmi.Spec.Domain.Devices.Filesystems {
+		if fs.Virtiofs != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if qemu and libvirt of the VMI run as an unprivileged user
+func IsNonRootVMI(vmi *v1.VirtualMachineInstance) bool {
+	return vmi.Status.RuntimeUser != 0
+}
+
+// ResourceNameToEnvVar returns the name of the environment variable which
+// holds the allocated devices of a device plugin resource, e.g.
+// "PCI_RESOURCE_INTEL_COM_NVME" for the prefix "PCI_RESOURCE" and "intel.com/nvme"
+func ResourceNameToEnvVar(prefix string, resourceName string) string {
+	varName := strings.ToUpper(resourceName)
+	varName = strings.Replace(varName, "/", "_", -1)
+	varName = strings.Replace(varName, ".", "_", -1)
+	return fmt.Sprintf("%s_%s", prefix, varName)
+}
@@ -0,0 +1,177 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package utilization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	cpuPressureMetric    = "kubevirt_vmi_cpu_pressure_some_seconds_total"
+	memoryResidentMetric = "kubevirt_vmi_launcher_memory_resident_bytes"
+)
+
+// sample are the metrics of the VMIs of the node at a point in time
+type sample struct {
+	time time.Time
+	// cpuStalled is the total time the tasks of a VMI waited for a CPU in seconds, by VMI
+	cpuStalled map[string]float64
+	// memoryResident is the memory all virt-launcher pods keep resident
+	memoryResident float64
+}
+
+// NodeReporter samples the metrics virt-handler collects of the VMIs on the node and
+// reports how contended they were since the previous sample on the node, while the
+// UtilizationScheduling feature gate is enabled
+type NodeReporter struct {
+	clusterConfig *virtconfig.ClusterConfig
+	clientset     kubecli.KubevirtClient
+	host          string
+	gatherer      prometheus.Gatherer
+	now           func() time.Time
+	last          *sample
+}
+
+func NewNodeReporter(clusterConfig *virtconfig.ClusterConfig, clientset kubecli.KubevirtClient, host string, gatherer prometheus.Gatherer) *NodeReporter {
+	return &NodeReporter{
+		clusterConfig: clusterConfig,
+		clientset:     clientset,
+		host:          host,
+		gatherer:      gatherer,
+		now:           time.Now,
+	}
+}
+
+// Run samples the node in the given interval
+func (r *NodeReporter) Run(interval time.Duration, stopCh chan struct{}) {
+	wait.JitterUntil(func() {
+		if err := r.reconcile(); err != nil {
+			log.DefaultLogger().Reason(err).Errorf("Failed to report the utilization of node %s", r.host)
+		}
+	}, interval, 1.2, true, stopCh)
+}
+
+func (r *NodeReporter) reconcile() error {
+	if !r.clusterConfig.UtilizationSchedulingEnabled() {
+		// a sample from before the gate was disabled would average over the pause
+		r.last = nil
+		return nil
+	}
+
+	current, err := r.sample()
+	if err != nil {
+		return err
+	}
+
+	last := r.last
+	r.last = current
+	if last == nil {
+		return nil
+	}
+	elapsed := current.time.Sub(last.time)
+	if elapsed <= 0 {
+		return nil
+	}
+
+	utilization := &NodeUtilization{
+		MemoryResidentBytes: int64(current.memoryResident),
+		Timestamp:           metav1.NewTime(current.time),
+	}
+	for vmi, stalled := range current.cpuStalled {
+		// VMIs which started since the previous sample are left to the next one
+		if previous, exists := last.cpuStalled[vmi]; exists && stalled >= previous {
+			utilization.CPUPressure = math.Max(utilization.CPUPressure, share(stalled-previous, elapsed))
+		}
+	}
+	return r.annotate(utilization)
+}
+
+func (r *NodeReporter) sample() (*sample, error) {
+	now := r.now()
+	families, err := r.gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather the metrics of the VMIs: %v", err)
+	}
+
+	current := &sample{time: now, cpuStalled: map[string]float64{}}
+	for _, family := range families {
+		switch family.GetName() {
+		case cpuPressureMetric:
+			for _, metric := range family.GetMetric() {
+				var namespace, name string
+				for _, label := range metric.GetLabel() {
+					switch label.GetName() {
+					case "namespace":
+						namespace = label.GetValue()
+					case "name":
+						name = label.GetValue()
+					}
+				}
+				current.cpuStalled[namespace+"/"+name] = metric.GetCounter().GetValue()
+			}
+		case memoryResidentMetric:
+			for _, metric := range family.GetMetric() {
+				current.memoryResident += metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return current, nil
+}
+
+// share returns the stall time in seconds as a percentage of elapsed, rounded to
+// a tenth of a percent
+func share(stalled float64, elapsed time.Duration) float64 {
+	percent := stalled * 100 / elapsed.Seconds()
+	return math.Min(math.Round(percent*10)/10, 100)
+}
+
+func (r *NodeReporter) annotate(utilization *NodeUtilization) error {
+	value, err := json.Marshal(utilization)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				v1.NodeUtilizationAnnotation: string(value),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := r.clientset.CoreV1().Nodes().Patch(r.host, types.StrategicMergePatchType, data); err != nil {
+		return fmt.Errorf("failed to patch the utilization of node %s: %v", r.host, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package utilization
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Node utilization reporter", func() {
+	var ctrl *gomock.Controller
+	var kubeClient *fake.Clientset
+	var registry *prometheus.Registry
+	var cpuStalled *prometheus.CounterVec
+	var memoryResident *prometheus.GaugeVec
+	var now time.Time
+
+	newReporter := func(featureGates string) *NodeReporter {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: featureGates},
+		})
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+		reporter := NewNodeReporter(clusterConfig, virtClient, "testnode", registry)
+		reporter.now = func() time.Time {
+			return now
+		}
+		return reporter
+	}
+
+	reportedUtilization := func() *NodeUtilization {
+		node, err := kubeClient.CoreV1().Nodes().Get("testnode", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		utilization, err := FromNode(node)
+		Expect(err).ToNot(HaveOccurred())
+		return utilization
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = fake.NewSimpleClientset(&k8sv1.Node{ObjectMeta: metav1.ObjectMeta{Name: "testnode"}})
+		now = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		registry = prometheus.NewRegistry()
+		cpuStalled = prometheus.NewCounterVec(prometheus.CounterOpts{Name: cpuPressureMetric}, []string{"namespace", "name"})
+		memoryResident = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: memoryResidentMetric}, []string{"namespace", "name"})
+		registry.MustRegister(cpuStalled, memoryResident)
+		cpuStalled.WithLabelValues("default", "a").Add(1)
+		cpuStalled.WithLabelValues("default", "b").Add(0)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should report the highest share of time a VMI stalled and the resident memory since the previous sample", func() {
+		reporter := newReporter(virtconfig.UtilizationSchedulingGate)
+		Expect(reporter.reconcile()).To(Succeed())
+		Expect(reportedUtilization()).To(BeNil())
+
+		// 15s and 3s of CPU stalls within a minute, a VMI which just started is left out
+		now = now.Add(time.Minute)
+		cpuStalled.WithLabelValues("default", "a").Add(15)
+		cpuStalled.WithLabelValues("default", "b").Add(3)
+		cpuStalled.WithLabelValues("default", "c").Add(60)
+		memoryResident.WithLabelValues("default", "a").Set(1024 * 1024 * 1024)
+		memoryResident.WithLabelValues("default", "b").Set(2 * 1024 * 1024 * 1024)
+		Expect(reporter.reconcile()).To(Succeed())
+
+		utilization := reportedUtilization()
+		Expect(utilization).ToNot(BeNil())
+		Expect(utilization.CPUPressure).To(Equal(25.0))
+		Expect(utilization.MemoryResidentBytes).To(Equal(int64(3 * 1024 * 1024 * 1024)))
+		Expect(utilization.Timestamp.Time.Equal(now)).To(BeTrue())
+	})
+
+	It("should not report without the UtilizationScheduling feature gate", func() {
+		reporter := newReporter("")
+		Expect(reporter.reconcile()).To(Succeed())
+		now = now.Add(time.Minute)
+		Expect(reporter.reconcile()).To(Succeed())
+
+		Expect(reportedUtilization()).To(BeNil())
+		Expect(reporter.last).To(BeNil())
+	})
+
+	It("should load a node by the higher of the CPU pressure and the share of resident memory", func() {
+		node := &k8sv1.Node{Status: k8sv1.NodeStatus{
+			Allocatable: k8sv1.ResourceList{k8sv1.ResourceMemory: resource.MustParse("4Gi")},
+		}}
+		utilization := &NodeUtilization{CPUPressure: 25, MemoryResidentBytes: 3 * 1024 * 1024 * 1024}
+		Expect(utilization.Load(node)).To(Equal(75.0))
+
+		utilization.CPUPressure = 90
+		Expect(utilization.Load(node)).To(Equal(90.0))
+		Expect(utilization.Load(&k8sv1.Node{})).To(Equal(90.0))
+	})
+
+	It("should fail to read a malformed utilization", func() {
+		node := &k8sv1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "testnode",
+			Annotations: map[string]string{v1.NodeUtilizationAnnotation: "{"},
+		}}
+		_, err := FromNode(node)
+		Expect(err).To(HaveOccurred())
+	})
+})
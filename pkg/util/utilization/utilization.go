@@ -0,0 +1,69 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package utilization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+// NodeUtilization is how contended the VMIs on a node actually were since the
+// previous sample. virt-handler reports it on the node in the
+// NodeUtilizationAnnotation.
+type NodeUtilization struct {
+	// CPUPressure is the highest share of time, in percent, in which a task of a
+	// VMI on the node waited for a CPU. For the vCPU threads this is their steal time.
+	CPUPressure float64 `json:"cpuPressure"`
+	// MemoryResidentBytes is the memory the virt-launcher pods on the node keep resident
+	MemoryResidentBytes int64 `json:"memoryResidentBytes"`
+	// Timestamp is when the utilization was sampled
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// Load is the higher of the CPU pressure and of the share of the allocatable
+// memory of the node resident in virt-launcher pods, in percent
+func (u *NodeUtilization) Load(node *k8sv1.Node) float64 {
+	load := u.CPUPressure
+	if allocatable, exists := node.Status.Allocatable[k8sv1.ResourceMemory]; exists && allocatable.Value() > 0 {
+		memory := math.Min(float64(u.MemoryResidentBytes)*100/float64(allocatable.Value()), 100)
+		load = math.Max(load, memory)
+	}
+	return load
+}
+
+// FromNode returns the utilization virt-handler reported on the node, or nil if it
+// did not report any
+func FromNode(node *k8sv1.Node) (*NodeUtilization, error) {
+	annotation, exists := node.Annotations[v1.NodeUtilizationAnnotation]
+	if !exists {
+		return nil, nil
+	}
+	utilization := &NodeUtilization{}
+	if err := json.Unmarshal([]byte(annotation), utilization); err != nil {
+		return nil, fmt.Errorf("failed to parse the utilization of node %s: %v", node.Name, err)
+	}
+	return utilization, nil
+}
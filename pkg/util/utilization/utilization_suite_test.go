@@ -0,0 +1,16 @@
+package utilization_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/log"
+)
+
+func TestUtilization(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.Log.SetIOWriter(GinkgoWriter)
+	RunSpecs(t, "Utilization Suite")
+}
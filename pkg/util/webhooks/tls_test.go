@@ -58,6 +58,7 @@ var _ = Describe("TLS", func() {
 
 	var caManager webhooks.ClientCAManager
 	var certmanagers map[string]certificate.Manager
+	var caCert *tls.Certificate
 
 	BeforeEach(func() {
 		// Bootstrap TLS for kubevirt
@@ -65,7 +66,8 @@ var _ = Describe("TLS", func() {
 		caSecret := components.NewCACertSecret("whatever")
 		secrets := components.NewCertSecrets("install_namespace", "operator_namespace")
 		Expect(components.PopulateSecretWithCertificate(caSecret, nil, &v1.Duration{Duration: 1 * time.Hour})).To(Succeed())
-		caCert, err := components.LoadCertificates(caSecret)
+		var err error
+		caCert, err = components.LoadCertificates(caSecret)
 		Expect(err).ToNot(HaveOccurred())
 		for _, secret := range secrets {
 			Expect(components.PopulateSecretWithCertificate(secret, caCert, &v1.Duration{Duration: 1 * time.Hour})).To(Succeed())
@@ -120,6 +122,48 @@ var _ = Describe("TLS", func() {
 		),
 	)
 
+	It("should serve rotated certificates to new connections without closing established ones", func() {
+		serverCertManager := certmanagers[components.VirtHandlerServerCertSecretName].(*mockCertManager)
+		serverTLSConfig := webhooks.SetupTLSForVirtHandlerServer(caManager, serverCertManager)
+		clientTLSConfig := webhooks.SetupTLSForVirtHandlerClients(caManager, certmanagers[components.VirtHandlerCertSecretName])
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "hello")
+		}))
+		srv.TLS = serverTLSConfig
+		srv.StartTLS()
+		defer srv.Close()
+
+		get := func(client *http.Client) *tls.ConnectionState {
+			resp, err := client.Get(srv.URL)
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+			_, err = ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			return resp.TLS
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSConfig}}
+		oldCrt := serverCertManager.crt
+		Expect(get(client).PeerCertificates[0].SerialNumber).To(Equal(oldCrt.Leaf.SerialNumber))
+
+		var newCrt *tls.Certificate
+		for _, secret := range components.NewCertSecrets("install_namespace", "operator_namespace") {
+			if secret.Name == components.VirtHandlerServerCertSecretName {
+				Expect(components.PopulateSecretWithCertificate(secret, caCert, &v1.Duration{Duration: 1 * time.Hour})).To(Succeed())
+				var err error
+				newCrt, err = components.LoadCertificates(secret)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+		serverCertManager.crt = newCrt
+
+		// the established connection is reused and keeps the old certificate
+		Expect(get(client).PeerCertificates[0].SerialNumber).To(Equal(oldCrt.Leaf.SerialNumber))
+
+		newClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSConfig}}
+		Expect(get(newClient).PeerCertificates[0].SerialNumber).To(Equal(newCrt.Leaf.SerialNumber))
+	})
+
 	It("should allow anonymous TLS connections to prometheus endpoints", func() {
 		serverTLSConfig := webhooks.SetupPromTLS(certmanagers[components.VirtHandlerServerCertSecretName])
 		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
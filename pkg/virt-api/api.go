@@ -31,9 +31,13 @@ import (
 	"github.com/go-openapi/spec"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	flag "github.com/spf13/pflag"
+	k8sv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8coresv1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	certificate2 "k8s.io/client-go/util/certificate"
 	aggregatorclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 
@@ -66,6 +70,9 @@ const (
 	defaultHost = "0.0.0.0"
 
 	defaultConsoleServerPort = 8186
+
+	// The key VNC tokens are signed with, mounted from a secret which virt-operator creates
+	vncTokenKeyFile = "/etc/virt-api/vnc-token-key/key"
 )
 
 type VirtApi interface {
@@ -83,6 +90,8 @@ type virtAPIApp struct {
 	virtCli          kubecli.KubevirtClient
 	aggregatorClient *aggregatorclient.Clientset
 	authorizor       rest.VirtApiAuthorizor
+	auditor          *rest.Auditor
+	vncTokens        *rest.VNCTokens
 	certsDirectory   string
 	clusterConfig    *virtconfig.ClusterConfig
 
@@ -93,6 +102,7 @@ type virtAPIApp struct {
 	certmanager             certificate2.Manager
 	handlerTLSConfiguration *tls.Config
 	handlerCertManager      certificate2.Manager
+	recorder                record.EventRecorder
 }
 
 var _ service.Service = &virtAPIApp{}
@@ -126,7 +136,11 @@ func (app *virtAPIApp) Execute() {
 
 	app.authorizor = authorizor
 
+	app.vncTokens = rest.NewVNCTokens(rest.VNCTokenKeyFromFile(vncTokenKeyFile), app.authorizor.GetUserHeaders)
+	app.authorizor.SetVNCTokens(app.vncTokens)
+
 	app.virtCli = virtCli
+	app.recorder = app.getNewRecorder(k8sv1.NamespaceAll, "virt-api")
 
 	app.certsDirectory, err = ioutil.TempDir("", "certsdir")
 	if err != nil {
@@ -141,6 +155,12 @@ func (app *virtAPIApp) Execute() {
 	app.Run()
 }
 
+func (app *virtAPIApp) getNewRecorder(namespace string, componentName string) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&k8coresv1.EventSinkImpl{Interface: app.virtCli.CoreV1().Events(namespace)})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, k8sv1.EventSource{Component: componentName})
+}
+
 func subresourceAPIGroup() metav1.APIGroup {
 	apiGroup := metav1.APIGroup{
 		Name: "subresource.kubevirt.io",
@@ -176,7 +196,7 @@ func (app *virtAPIApp) composeSubresources() {
 		subws.Doc(fmt.Sprintf("KubeVirt \"%s\" Subresource API.", version.Version))
 		subws.Path(rest.GroupVersionBasePath(version))
 
-		subresourceApp := rest.NewSubresourceAPIApp(app.virtCli, app.consoleServerPort, app.handlerTLSConfiguration)
+		subresourceApp := rest.NewSubresourceAPIApp(app.virtCli, app.consoleServerPort, app.handlerTLSConfiguration, app.vncTokens, app.clusterConfig)
 
 		restartRouteBuilder := subws.PUT(rest.ResourcePath(subresourcesvmGVR)+rest.SubResourcePath("restart")).
 			To(subresourceApp.RestartVMRequestHandler).
@@ -208,14 +228,17 @@ func (app *virtAPIApp) composeSubresources() {
 			Returns(http.StatusNotFound, "Not Found", "").
 			Returns(http.StatusBadRequest, "Bad Request", ""))
 
-		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmGVR)+rest.SubResourcePath("stop")).
+		stopRouteBuilder := subws.PUT(rest.ResourcePath(subresourcesvmGVR)+rest.SubResourcePath("stop")).
 			To(subresourceApp.StopVMRequestHandler).
+			Reads(v1.StopOptions{}).
 			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
 			Operation("stop").
 			Doc("Stop a VirtualMachine object.").
 			Returns(http.StatusOK, "OK", "").
 			Returns(http.StatusNotFound, "Not Found", "").
-			Returns(http.StatusBadRequest, "Bad Request", ""))
+			Returns(http.StatusBadRequest, "Bad Request", "")
+		stopRouteBuilder.ParameterNamed("body").Required(false)
+		subws.Route(stopRouteBuilder)
 
 		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("pause")).
 			To(subresourceApp.PauseVMIRequestHandler).
@@ -235,6 +258,34 @@ func (app *virtAPIApp) composeSubresources() {
 			Returns(http.StatusNotFound, "Not Found", "").
 			Returns(http.StatusBadRequest, "Bad Request", ""))
 
+		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("freeze")).
+			To(subresourceApp.FreezeVMIRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("freeze").
+			Doc("Freeze the guest filesystems of a VirtualMachineInstance object.").
+			Returns(http.StatusOK, "OK", "").
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
+		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("thaw")).
+			To(subresourceApp.ThawVMIRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("thaw").
+			Doc("Thaw the guest filesystems of a VirtualMachineInstance object.").
+			Returns(http.StatusOK, "OK", "").
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
+		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("memorydump")).
+			To(subresourceApp.MemoryDumpVMIRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("memorydump").
+			Doc("Dump the guest memory of a running VirtualMachineInstance to a PersistentVolumeClaim.").
+			Returns(http.StatusAccepted, "Accepted", "").
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusConflict, "Conflict", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
 		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR) + rest.SubResourcePath("console")).
 			To(subresourceApp.ConsoleRequestHandler).
 			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
@@ -247,6 +298,37 @@ func (app *virtAPIApp) composeSubresources() {
 			Operation("vnc").
 			Doc("Open a websocket connection to connect to VNC on the specified VirtualMachineInstance."))
 
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("vnc-token")).
+			To(subresourceApp.VNCTokenRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Param(subws.QueryParameter(rest.VNCTokenDurationParam, "The lifetime of the token, at most 1m").DataType("string")).
+			Produces(restful.MIME_JSON).
+			Operation("vnc-token").
+			Doc("Issue a short-lived token granting the requesting user a single connection to VNC on the specified VirtualMachineInstance.").
+			Writes(v1.VNCToken{}).
+			Returns(http.StatusOK, "OK", v1.VNCToken{}).
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR) + rest.SubResourcePath("portforward/{port}")).
+			To(subresourceApp.PortForwardRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Param(rest.PortParam(subws)).
+			Operation("portforward").
+			Doc("Open a websocket connection forwarding a TCP port of the specified VirtualMachineInstance."))
+
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR) + rest.SubResourcePath("usbredir")).
+			To(subresourceApp.USBRedirRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("usbredir").
+			Doc("Open a websocket connection redirecting a USB device of the client to the specified VirtualMachineInstance."))
+
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR) + rest.SubResourcePath("backup")).
+			To(subresourceApp.BackupRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("backup").
+			Doc("Open a websocket connection to the NBD server exporting the disks of a ready VirtualMachineBackup of the specified VirtualMachineInstance."))
+
 		// An empty handler function would respond with HTTP OK by default
 		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR) + rest.SubResourcePath("test")).
 			To(func(request *restful.Request, response *restful.Response) {}).
@@ -266,6 +348,23 @@ func (app *virtAPIApp) composeSubresources() {
 			Doc("Health endpoint").
 			Returns(http.StatusOK, "OK", "").
 			Returns(http.StatusInternalServerError, "Unhealthy", ""))
+		subws.Route(subws.GET(rest.SubResourcePath("capabilities")).
+			To(subresourceApp.CapabilitiesRequestHandler).
+			Produces(restful.MIME_JSON).
+			Operation("capabilities").
+			Doc("Get the feature gates, machine types and effective configuration of the cluster").
+			Writes(v1.ClusterCapabilities{}).
+			Returns(http.StatusOK, "OK", v1.ClusterCapabilities{}))
+		subws.Route(subws.PUT(rest.SubResourcePath("launcher-resources")).
+			To(subresourceApp.LauncherResourcesRequestHandler).
+			Reads(v1.VirtualMachineInstance{}).
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON).
+			Operation("launcherResources").
+			Doc("Calculate the resources the virt-launcher pod of a VirtualMachineInstance object would request, including the overhead added to the resources of the guest.").
+			Writes(v1.LauncherResources{}).
+			Returns(http.StatusOK, "OK", v1.LauncherResources{}).
+			Returns(http.StatusBadRequest, "Bad Request", ""))
 		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("guestosinfo")).
 			To(subresourceApp.GuestOSInfo).
 			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
@@ -286,6 +385,42 @@ func (app *virtAPIApp) composeSubresources() {
 			Returns(http.StatusNotFound, "Not Found", "").
 			Returns(http.StatusBadRequest, "Bad Request", ""))
 
+		subws.Route(subws.PUT(rest.ResourcePath(subresourcesvmGVR)+rest.SubResourcePath("rollback")).
+			To(subresourceApp.RollbackVMRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Operation("rollback").
+			Doc("Restore the template of a VirtualMachine object from one of its template revisions.").
+			Returns(http.StatusOK, "OK", "").
+			Returns(http.StatusAccepted, "Accepted", "").
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmGVR)+rest.SubResourcePath("expand-spec")).
+			To(subresourceApp.ExpandVMSpecRequestHandler).
+			Param(rest.NamespaceParam(subws)).Param(rest.NameParam(subws)).
+			Produces(restful.MIME_JSON).
+			Operation("expandVMSpec").
+			Doc("Get a VirtualMachine object with its instancetype, preference and defaults applied to the template.").
+			Writes(v1.VirtualMachine{}).
+			Returns(http.StatusOK, "OK", v1.VirtualMachine{}).
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusUnprocessableEntity, "Unprocessable Entity", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
+		subws.Route(subws.PUT(rest.NamespaceBasePath()+rest.SubResourcePath("expand-spec")).
+			To(subresourceApp.ExpandSpecRequestHandler).
+			Param(rest.NamespaceParam(subws)).
+			Reads(v1.VirtualMachine{}).
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON).
+			Operation("expandSpec").
+			Doc("Apply the instancetype, preference and defaults to the template of a VirtualMachine object without creating it.").
+			Writes(v1.VirtualMachine{}).
+			Returns(http.StatusOK, "OK", v1.VirtualMachine{}).
+			Returns(http.StatusNotFound, "Not Found", "").
+			Returns(http.StatusUnprocessableEntity, "Unprocessable Entity", "").
+			Returns(http.StatusBadRequest, "Bad Request", ""))
+
 		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("userlist")).
 			To(subresourceApp.UserList).
 			Consumes(restful.MIME_JSON).
@@ -304,6 +439,15 @@ func (app *virtAPIApp) composeSubresources() {
 			Writes(v1.VirtualMachineInstanceFileSystemList{}).
 			Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceFileSystemList{}))
 
+		subws.Route(subws.GET(rest.ResourcePath(subresourcesvmiGVR)+rest.SubResourcePath("stats")).
+			To(subresourceApp.Stats).
+			Consumes(restful.MIME_JSON).
+			Produces(restful.MIME_JSON).
+			Operation("stats").
+			Doc("Get a sample of the resource usage of a running VMI").
+			Writes(v1.VirtualMachineInstanceStats{}).
+			Returns(http.StatusOK, "OK", v1.VirtualMachineInstanceStats{}))
+
 		// Return empty api resource list.
 		// K8s expects to be able to retrieve a resource list for each aggregated
 		// app in order to discover what resources it provides. Without returning
@@ -322,6 +466,10 @@ func (app *virtAPIApp) composeSubresources() {
 						Name:       "virtualmachineinstances/vnc",
 						Namespaced: true,
 					},
+					{
+						Name:       "virtualmachineinstances/vnc-token",
+						Namespaced: true,
+					},
 					{
 						Name:       "virtualmachineinstances/console",
 						Namespaced: true,
@@ -334,6 +482,14 @@ func (app *virtAPIApp) composeSubresources() {
 						Name:       "virtualmachineinstances/unpause",
 						Namespaced: true,
 					},
+					{
+						Name:       "virtualmachineinstances/freeze",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachineinstances/thaw",
+						Namespaced: true,
+					},
 					{
 						Name:       "virtualmachines/start",
 						Namespaced: true,
@@ -358,6 +514,10 @@ func (app *virtAPIApp) composeSubresources() {
 						Name:       "virtualmachines/rename",
 						Namespaced: true,
 					},
+					{
+						Name:       "virtualmachines/rollback",
+						Namespaced: true,
+					},
 					{
 						Name:       "virtualmachineinstances/userlist",
 						Namespaced: true,
@@ -366,6 +526,34 @@ func (app *virtAPIApp) composeSubresources() {
 						Name:       "virtualmachineinstances/filesystemlist",
 						Namespaced: true,
 					},
+					{
+						Name:       "virtualmachineinstances/stats",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachineinstances/portforward",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachineinstances/usbredir",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachineinstances/backup",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachineinstances/memorydump",
+						Namespaced: true,
+					},
+					{
+						Name:       "virtualmachines/expand-spec",
+						Namespaced: true,
+					},
+					{
+						Name:       "expand-spec",
+						Namespaced: true,
+					},
 				}
 
 				response.WriteAsJson(list)
@@ -379,6 +567,44 @@ func (app *virtAPIApp) composeSubresources() {
 
 		subwss = append(subwss, subws)
 	}
+
+	// Serve the guest metrics of VMIs through the custom metrics API. The API
+	// is only reachable once an APIService for custom.metrics.k8s.io points to
+	// virt-api, which is left to the cluster admin to not replace an existing
+	// metrics adapter.
+	metricsApp := rest.NewCustomMetricsAPIApp(app.virtCli, app.consoleServerPort, app.handlerTLSConfiguration)
+	metricsws := new(restful.WebService)
+	metricsws.Doc("The KubeVirt Custom Metrics API")
+	metricsws.Path("/apis/" + rest.CustomMetricsGroup + "/" + rest.CustomMetricsVersion)
+
+	metricsws.Route(metricsws.GET("/").
+		Produces(restful.MIME_JSON).Writes(metav1.APIResourceList{}).
+		To(func(request *restful.Request, response *restful.Response) {
+			list := &metav1.APIResourceList{}
+			list.Kind = "APIResourceList"
+			list.GroupVersion = rest.CustomMetricsGroup + "/" + rest.CustomMetricsVersion
+			list.APIVersion = "v1"
+			list.APIResources = rest.CustomMetricsResources()
+			response.WriteAsJson(list)
+		}).
+		Operation("getCustomMetricsResources").
+		Doc("Get the custom metrics of VMIs").
+		Returns(http.StatusOK, "OK", metav1.APIResourceList{}))
+
+	metricsws.Route(metricsws.GET("/namespaces/{namespace}/{resource}/{name}/{metric}").
+		To(metricsApp.MetricsRequestHandler).
+		Param(rest.NamespaceParam(metricsws)).
+		Param(metricsws.PathParameter("resource", "pods or virtualmachineinstances.kubevirt.io").Required(true)).
+		Param(metricsws.PathParameter("name", "Name of the object, * for all objects matching the labelSelector").Required(true)).
+		Param(metricsws.PathParameter("metric", "Name of the metric").Required(true)).
+		Param(metricsws.QueryParameter("labelSelector", "Selects the objects if the name is *")).
+		Produces(restful.MIME_JSON).
+		Operation("getCustomMetric").
+		Doc("Get a custom metric of VMIs or of their launcher pods").
+		Returns(http.StatusOK, "OK", "").
+		Returns(http.StatusNotFound, "Not Found", ""))
+
+	restful.Add(metricsws)
 	ws := new(restful.WebService)
 
 	// K8s needs the ability to query the root paths
@@ -450,8 +676,11 @@ func (app *virtAPIApp) Compose() {
 
 	app.composeSubresources()
 
+	app.auditor = rest.NewAuditor(app.authorizor, app.clusterConfig)
+
 	restful.Filter(filter.RequestLoggingFilter())
 	restful.Filter(restful.OPTIONSFilter())
+	restful.Filter(app.auditor.Filter)
 	restful.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 		allowed, reason, err := app.authorizor.Authorize(req)
 		if err != nil {
@@ -572,6 +801,9 @@ func (app *virtAPIApp) registerValidatingWebhooks() {
 	http.HandleFunc(components.StatusValidatePath, func(w http.ResponseWriter, r *http.Request) {
 		validating_webhook.ServeStatusValidation(w, r)
 	})
+	http.HandleFunc(components.LauncherEvictionValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		validating_webhook.ServePodEvictionInterceptor(w, r, app.clusterConfig, app.virtCli, app.recorder)
+	})
 }
 
 func (app *virtAPIApp) registerMutatingWebhook() {
@@ -621,6 +853,7 @@ func (app *virtAPIApp) startTLS(informerFactory controller.KubeInformerFactory,
 	app.setupTLS(k8sCAManager, kubevirtCAInformer)
 
 	app.Compose()
+	go app.auditor.Run(stopCh)
 
 	// start TLS server
 	go func() {
@@ -666,6 +899,8 @@ func (app *virtAPIApp) Run() {
 	go webhookInformers.VMIInformer.Run(stopChan)
 	go webhookInformers.VMIPresetInformer.Run(stopChan)
 	go webhookInformers.NamespaceLimitsInformer.Run(stopChan)
+	go webhookInformers.NodeInformer.Run(stopChan)
+	go webhookInformers.ResourceQuotaInformer.Run(stopChan)
 	go kubeVirtInformer.Run(stopChan)
 	go configMapInformer.Run(stopChan)
 	go crdInformer.Run(stopChan)
@@ -679,6 +914,8 @@ func (app *virtAPIApp) Run() {
 		webhookInformers.VMIInformer.HasSynced,
 		webhookInformers.VMIPresetInformer.HasSynced,
 		webhookInformers.NamespaceLimitsInformer.HasSynced,
+		webhookInformers.NodeInformer.HasSynced,
+		webhookInformers.ResourceQuotaInformer.HasSynced,
 		configMapInformer.HasSynced)
 
 	app.clusterConfig = virtconfig.NewClusterConfig(configMapInformer, crdInformer, kubeVirtInformer, app.namespace)
@@ -0,0 +1,333 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	auditQueueSize      = 1000
+	auditBatchSize      = 100
+	auditWebhookTimeout = 10 * time.Second
+
+	// the annotations follow the ones kube-apiserver adds to its audit events
+	auditDecisionAnnotation = "authorization.k8s.io/decision"
+	auditVNCTokenAnnotation = "subresources.kubevirt.io/vnc-token"
+)
+
+// auditedSubresources are the privileged operations on vmis which are audited
+var auditedSubresources = map[string]bool{
+	"console":    true,
+	"vnc":        true,
+	"vnc-token":  true,
+	"pause":      true,
+	"unpause":    true,
+	"migrate":    true,
+	"memorydump": true,
+}
+
+// Auditor emits audit events for the privileged operations on vmis. The events
+// have the audit.k8s.io/v1 format of the kube-apiserver audit events, are logged
+// and, if the cluster config has an audit webhook, posted to it.
+type Auditor struct {
+	authorizor    VirtApiAuthorizor
+	clusterConfig *virtconfig.ClusterConfig
+	events        chan *auditv1.Event
+
+	webhookConfig v1.AuditConfiguration
+	webhookClient *http.Client
+}
+
+func NewAuditor(authorizor VirtApiAuthorizor, clusterConfig *virtconfig.ClusterConfig) *Auditor {
+	return &Auditor{
+		authorizor:    authorizor,
+		clusterConfig: clusterConfig,
+		events:        make(chan *auditv1.Event, auditQueueSize),
+	}
+}
+
+// Filter audits the requests to the audited subresources. It has to run before
+// the authorization, so that denied requests are audited as well.
+func (a *Auditor) Filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	objectRef, ok := getAuditObjectRef(req)
+	if !ok {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	event := a.newEvent(req, objectRef)
+
+	// console and VNC connections are audited when the websocket is established,
+	// not only once it is closed again
+	hijacked := false
+	writer := resp.ResponseWriter
+	resp.ResponseWriter = &auditResponseWriter{
+		ResponseWriter: writer,
+		hijacked: func() {
+			hijacked = true
+			started := event.DeepCopy()
+			started.Stage = auditv1.StageResponseStarted
+			started.StageTimestamp = metav1.NowMicro()
+			started.ResponseStatus = &metav1.Status{Code: http.StatusSwitchingProtocols}
+			started.Annotations[auditDecisionAnnotation] = "allow"
+			a.emit(started)
+		},
+	}
+	defer func() {
+		resp.ResponseWriter = writer
+	}()
+
+	chain.ProcessFilter(req, resp)
+
+	code := resp.StatusCode()
+	if hijacked {
+		code = http.StatusSwitchingProtocols
+	}
+	event.Stage = auditv1.StageResponseComplete
+	event.StageTimestamp = metav1.NowMicro()
+	event.ResponseStatus = &metav1.Status{Code: int32(code)}
+	if code == http.StatusUnauthorized || code == http.StatusForbidden {
+		event.Annotations[auditDecisionAnnotation] = "forbid"
+	} else {
+		event.Annotations[auditDecisionAnnotation] = "allow"
+	}
+	a.emit(event)
+}
+
+func (a *Auditor) newEvent(req *restful.Request, objectRef *auditv1.ObjectReference) *auditv1.Event {
+	event := &auditv1.Event{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: auditv1.SchemeGroupVersion.String(),
+			Kind:       "Event",
+		},
+		Level:                    auditv1.LevelMetadata,
+		AuditID:                  uuid.NewUUID(),
+		RequestURI:               req.Request.URL.RequestURI(),
+		UserAgent:                req.Request.UserAgent(),
+		ObjectRef:                objectRef,
+		RequestReceivedTimestamp: metav1.NowMicro(),
+		Annotations:              map[string]string{},
+	}
+	if verb, err := mapHttpVerbToRbacVerb(req.Request.Method, objectRef.Name); err == nil {
+		event.Verb = verb
+	} else {
+		event.Verb = strings.ToLower(req.Request.Method)
+	}
+	for _, ip := range utilnet.SourceIPs(req.Request) {
+		event.SourceIPs = append(event.SourceIPs, ip.String())
+	}
+
+	// the user headers are only set by the aggregation layer of kube-apiserver
+	// if the request is authenticated by its client certificate
+	if isAuthenticated(req) {
+		event.User = a.getUserInfo(req.Request.Header)
+	}
+	if _, _, _, ok := getVNCToken(req); ok {
+		event.Annotations[auditVNCTokenAnnotation] = "true"
+	}
+	return event
+}
+
+func (a *Auditor) getUserInfo(header http.Header) authnv1.UserInfo {
+	userInfo := authnv1.UserInfo{}
+	for _, key := range a.authorizor.GetUserHeaders() {
+		if user, ok := header[key]; ok {
+			userInfo.Username = user[0]
+			break
+		}
+	}
+	for _, key := range a.authorizor.GetGroupHeaders() {
+		if groups, ok := header[key]; ok {
+			userInfo.Groups = groups
+			break
+		}
+	}
+	return userInfo
+}
+
+func (a *Auditor) emit(event *auditv1.Event) {
+	log.Log.Level(log.INFO).
+		With("auditID", event.AuditID).
+		With("stage", event.Stage).
+		With("username", event.User.Username).
+		With("verb", event.Verb).
+		With("namespace", event.ObjectRef.Namespace).
+		With("name", event.ObjectRef.Name).
+		With("resource", event.ObjectRef.Resource).
+		With("subresource", event.ObjectRef.Subresource).
+		With("decision", event.Annotations[auditDecisionAnnotation]).
+		Log("statusCode", event.ResponseStatus.Code)
+
+	select {
+	case a.events <- event:
+	default:
+		log.Log.Warningf("Dropping audit event %s, the audit queue is full", event.AuditID)
+	}
+}
+
+// Run posts the audit events to the audit webhook of the cluster config
+func (a *Auditor) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event := <-a.events:
+			batch := []auditv1.Event{*event}
+			for len(batch) < auditBatchSize && len(a.events) > 0 {
+				batch = append(batch, *<-a.events)
+			}
+			if err := a.send(batch); err != nil {
+				log.Log.Reason(err).Errorf("Failed to send %d audit events to the audit webhook", len(batch))
+			}
+		}
+	}
+}
+
+func (a *Auditor) send(events []auditv1.Event) error {
+	if a.clusterConfig == nil {
+		return nil
+	}
+	config := a.clusterConfig.GetAuditConfiguration()
+	if config == nil || config.WebhookURL == "" {
+		return nil
+	}
+
+	client, err := a.getWebhookClient(config)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(&auditv1.EventList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: auditv1.SchemeGroupVersion.String(),
+			Kind:       "EventList",
+		},
+		Items: events,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("the audit webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// getWebhookClient returns the client for the audit webhook, which is only
+// created again once the audit configuration changed
+func (a *Auditor) getWebhookClient(config *v1.AuditConfiguration) (*http.Client, error) {
+	if a.webhookClient != nil && a.webhookConfig == *config {
+		return a.webhookClient, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if config.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CABundle)) {
+			return nil, fmt.Errorf("no PEM encoded certificate found in the CA bundle of the audit webhook")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	a.webhookConfig = *config
+	a.webhookClient = &http.Client{
+		Timeout:   auditWebhookTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	return a.webhookClient, nil
+}
+
+// getAuditObjectRef returns the vmi or vm of a request to an audited subresource
+func getAuditObjectRef(req *restful.Request) (*auditv1.ObjectReference, bool) {
+	httpRequest := req.Request
+	if httpRequest == nil || httpRequest.URL == nil {
+		return nil, false
+	}
+
+	// URL example
+	// /apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/console
+	pathSplit := strings.Split(httpRequest.URL.Path, "/")
+	if len(pathSplit) != 9 || pathSplit[4] != "namespaces" || !auditedSubresources[pathSplit[8]] {
+		return nil, false
+	}
+	if pathSplit[6] != "virtualmachineinstances" && pathSplit[6] != "virtualmachines" {
+		return nil, false
+	}
+	return &auditv1.ObjectReference{
+		APIGroup:    pathSplit[2],
+		APIVersion:  pathSplit[3],
+		Namespace:   pathSplit[5],
+		Resource:    pathSplit[6],
+		Name:        pathSplit[7],
+		Subresource: pathSplit[8],
+	}, true
+}
+
+// auditResponseWriter notifies the auditor when the connection is taken over
+// for a websocket
+type auditResponseWriter struct {
+	http.ResponseWriter
+	hijacked func()
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		w.hijacked()
+	}
+	return conn, rw, err
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	restful "github.com/emicklei/go-restful"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+var _ = Describe("Audit", func() {
+
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var auditor *Auditor
+
+	newRequest := func(method string, path string) *restful.Request {
+		req := restful.NewRequest(&http.Request{
+			Method: method,
+			URL:    &url.URL{Path: path},
+			Header: http.Header{
+				userHeader:        []string{"user"},
+				groupHeader:       []string{"group1", "group2"},
+				"X-Forwarded-For": []string{"192.168.1.1"},
+			},
+			TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}},
+		})
+		return req
+	}
+
+	process := func(req *restful.Request, target restful.RouteFunction) {
+		resp := restful.NewResponse(httptest.NewRecorder())
+		chain := &restful.FilterChain{Target: target}
+		auditor.Filter(req, resp, chain)
+	}
+
+	respond := func(code int) restful.RouteFunction {
+		return func(req *restful.Request, resp *restful.Response) {
+			resp.WriteHeader(code)
+		}
+	}
+
+	nextEvent := func() *auditv1.Event {
+		var event *auditv1.Event
+		Expect(auditor.events).To(Receive(&event))
+		return event
+	}
+
+	BeforeEach(func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+		auditor = NewAuditor(&authorizor{
+			userHeaders:  []string{userHeader},
+			groupHeaders: []string{groupHeader},
+		}, clusterConfig)
+	})
+
+	It("should audit privileged operations on vmis", func() {
+		process(newRequest(http.MethodPut, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/pause"), respond(http.StatusAccepted))
+
+		event := nextEvent()
+		Expect(event.Kind).To(Equal("Event"))
+		Expect(event.APIVersion).To(Equal("audit.k8s.io/v1"))
+		Expect(event.AuditID).ToNot(BeEmpty())
+		Expect(event.Stage).To(BeEquivalentTo(auditv1.StageResponseComplete))
+		Expect(event.Verb).To(Equal("update"))
+		Expect(event.User.Username).To(Equal("user"))
+		Expect(event.User.Groups).To(Equal([]string{"group1", "group2"}))
+		Expect(event.SourceIPs).To(ContainElement("192.168.1.1"))
+		Expect(*event.ObjectRef).To(Equal(auditv1.ObjectReference{
+			APIGroup:    "subresources.kubevirt.io",
+			APIVersion:  "v1alpha3",
+			Namespace:   "default",
+			Resource:    "virtualmachineinstances",
+			Name:        "testvmi",
+			Subresource: "pause",
+		}))
+		Expect(event.ResponseStatus.Code).To(Equal(int32(http.StatusAccepted)))
+		Expect(event.Annotations).To(HaveKeyWithValue(auditDecisionAnnotation, "allow"))
+		Expect(auditor.events).To(BeEmpty())
+	})
+
+	It("should audit denied requests", func() {
+		process(newRequest(http.MethodPut, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachines/testvm/migrate"), respond(http.StatusUnauthorized))
+
+		event := nextEvent()
+		Expect(event.ObjectRef.Resource).To(Equal("virtualmachines"))
+		Expect(event.ObjectRef.Subresource).To(Equal("migrate"))
+		Expect(event.Annotations).To(HaveKeyWithValue(auditDecisionAnnotation, "forbid"))
+	})
+
+	It("should not trust the user headers of unauthenticated requests", func() {
+		req := newRequest(http.MethodGet, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc")
+		req.Request.TLS = nil
+		req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{"token"}}.Encode()
+		process(req, respond(http.StatusOK))
+
+		event := nextEvent()
+		Expect(event.User.Username).To(BeEmpty())
+		Expect(event.Annotations).To(HaveKeyWithValue(auditVNCTokenAnnotation, "true"))
+	})
+
+	It("should audit established websocket connections right away", func() {
+		req := newRequest(http.MethodGet, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/console")
+		resp := restful.NewResponse(&hijackableRecorder{httptest.NewRecorder()})
+		chain := &restful.FilterChain{Target: func(req *restful.Request, resp *restful.Response) {
+			_, _, err := resp.ResponseWriter.(http.Hijacker).Hijack()
+			Expect(err).ToNot(HaveOccurred())
+
+			started := nextEvent()
+			Expect(started.Stage).To(BeEquivalentTo(auditv1.StageResponseStarted))
+			Expect(started.ResponseStatus.Code).To(Equal(int32(http.StatusSwitchingProtocols)))
+		}}
+		auditor.Filter(req, resp, chain)
+
+		completed := nextEvent()
+		Expect(completed.Stage).To(BeEquivalentTo(auditv1.StageResponseComplete))
+		Expect(completed.ResponseStatus.Code).To(Equal(int32(http.StatusSwitchingProtocols)))
+	})
+
+	It("should not audit other requests", func() {
+		process(newRequest(http.MethodGet, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/guestosinfo"), respond(http.StatusOK))
+		process(newRequest(http.MethodGet, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/portforward/22"), respond(http.StatusOK))
+		process(newRequest(http.MethodGet, "/apis/subresources.kubevirt.io/v1alpha3/healthz"), respond(http.StatusOK))
+		Expect(auditor.events).To(BeEmpty())
+	})
+
+	It("should post the audit events to the audit webhook", func() {
+		received := make(chan auditv1.EventList, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			list := auditv1.EventList{}
+			Expect(json.NewDecoder(r.Body).Decode(&list)).To(Succeed())
+			received <- list
+		}))
+		defer server.Close()
+
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.AuditConfigurationKey: "webhookURL: " + server.URL},
+		})
+		auditor.clusterConfig = clusterConfig
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+
+		process(newRequest(http.MethodPut, "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/memorydump"), respond(http.StatusAccepted))
+		go auditor.Run(stopCh)
+
+		var list auditv1.EventList
+		Eventually(received).Should(Receive(&list))
+		Expect(list.Kind).To(Equal("EventList"))
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].ObjectRef.Subresource).To(Equal("memorydump"))
+	})
+})
@@ -50,6 +50,7 @@ type VirtApiAuthorizor interface {
 	GetGroupHeaders() []string
 	AddExtraPrefixHeaders(header []string)
 	GetExtraPrefixHeaders() []string
+	SetVNCTokens(tokens *VNCTokens)
 }
 
 type authorizor struct {
@@ -58,6 +59,7 @@ type authorizor struct {
 	userExtraHeaderPrefixes []string
 
 	subjectAccessReview authorizationclient.SubjectAccessReviewInterface
+	vncTokens           *VNCTokens
 }
 
 func (a *authorizor) getUserGroups(header http.Header) ([]string, error) {
@@ -123,6 +125,10 @@ func (a *authorizor) GetExtraPrefixHeaders() []string {
 	return a.userExtraHeaderPrefixes
 }
 
+func (a *authorizor) SetVNCTokens(tokens *VNCTokens) {
+	a.vncTokens = tokens
+}
+
 func (a *authorizor) generateAccessReview(req *restful.Request) (*authorization.SubjectAccessReview, error) {
 
 	httpRequest := req.Request
@@ -137,10 +143,11 @@ func (a *authorizor) generateAccessReview(req *restful.Request) (*authorization.
 		return nil, fmt.Errorf("no URL in http request")
 	}
 
-	// URL example
+	// URL examples
 	// /apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/console
+	// /apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/portforward/22
 	pathSplit := strings.Split(url.Path, "/")
-	if len(pathSplit) != 9 {
+	if len(pathSplit) != 9 && !(len(pathSplit) == 10 && pathSplit[8] == "portforward") {
 		return nil, fmt.Errorf("unknown api endpoint %s", url.Path)
 	}
 
@@ -152,7 +159,10 @@ func (a *authorizor) generateAccessReview(req *restful.Request) (*authorization.
 	subresource := pathSplit[8]
 	userExtras := a.getUserExtras(headers)
 
-	if resource != "virtualmachineinstances" && resource != "virtualmachines" {
+	// the custom metrics are authorized like with other metrics adapters, e.g.
+	// /apis/custom.metrics.k8s.io/v1beta1/namespaces/default/pods/*/kubevirt_vmi_memory_resident_bytes
+	// needs get on the pods resource of the custom.metrics.k8s.io group
+	if group != CustomMetricsGroup && resource != "virtualmachineinstances" && resource != "virtualmachines" {
 		return nil, fmt.Errorf("unknown resource type %s", resource)
 	}
 
@@ -235,6 +245,45 @@ func isInfoOrHealthEndpoint(req *restful.Request) bool {
 	return false
 }
 
+// authenticatedEndpoints are open to all authenticated users, since they
+// neither reveal nor change any objects of the cluster
+var authenticatedEndpoints = map[string]bool{
+	"capabilities":       true,
+	"launcher-resources": true,
+}
+
+// URL examples
+// /apis/subresources.kubevirt.io/v1/capabilities
+// /apis/subresources.kubevirt.io/v1/launcher-resources
+func isAuthenticatedEndpoint(req *restful.Request) bool {
+	if req.Request == nil || req.Request.URL == nil {
+		return false
+	}
+	pathSplit := strings.Split(req.Request.URL.Path, "/")
+	return len(pathSplit) == 5 && authenticatedEndpoints[pathSplit[4]]
+}
+
+// getVNCToken returns the namespace and name of the VMI and the token of a
+// request to the vnc subresource, if it carries one
+func getVNCToken(req *restful.Request) (namespace string, name string, token string, ok bool) {
+	httpRequest := req.Request
+	if httpRequest == nil || httpRequest.URL == nil {
+		return "", "", "", false
+	}
+	token = httpRequest.URL.Query().Get(VNCTokenParam)
+	if token == "" {
+		return "", "", "", false
+	}
+
+	// URL example
+	// /apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc
+	pathSplit := strings.Split(httpRequest.URL.Path, "/")
+	if len(pathSplit) != 9 || pathSplit[6] != "virtualmachineinstances" || pathSplit[8] != "vnc" {
+		return "", "", "", false
+	}
+	return pathSplit[5], pathSplit[7], token, true
+}
+
 func isAuthenticated(req *restful.Request) bool {
 	// Peer cert is required for authentication.
 	// If the peer's cert is provided, we are guaranteed
@@ -258,6 +307,22 @@ func (a *authorizor) Authorize(req *restful.Request) (bool, string, error) {
 		return false, "request is not authenticated", nil
 	}
 
+	// A VNC token grants a single connection to the user who requested it, so that
+	// web UIs don't need access to the vnc subresource. The user headers can only
+	// be trusted on authenticated requests. The vnc handler consumes the token.
+	if namespace, name, token, ok := getVNCToken(req); ok && a.vncTokens != nil {
+		if err := a.vncTokens.Validate(token, namespace, name, req.Request.Header); err != nil {
+			return false, fmt.Sprintf("invalid VNC token: %v", err), nil
+		}
+		return true, "", nil
+	}
+
+	// The capabilities of the cluster are readable by all authenticated users,
+	// like the discovery of the APIs, and so are the launcher resources of vmis
+	if isAuthenticatedEndpoint(req) {
+		return true, "", nil
+	}
+
 	r, err := a.generateAccessReview(req)
 	if err != nil {
 		// only internal service errors are returned
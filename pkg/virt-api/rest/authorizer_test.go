@@ -128,6 +128,111 @@ var _ = Describe("Authorizer", func() {
 				close(done)
 			}, 5)
 
+			It("should allow VNC access with a valid token", func(done Done) {
+				req.Request.TLS = &tls.ConnectionState{}
+				req.Request.TLS.PeerCertificates = append(req.Request.TLS.PeerCertificates, fakecert)
+				app.vncTokens = newFakeVNCTokens()
+				token, _, err := app.vncTokens.Issue(newTokenVMI("testvmi"), "user", DefaultVNCTokenDuration)
+				Expect(err).ToNot(HaveOccurred())
+
+				req.Request.URL.Path = "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc"
+				req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+
+				allowed, _, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+
+				close(done)
+			}, 5)
+
+			It("should reject VNC access with a token for another VMI", func(done Done) {
+				req.Request.TLS = &tls.ConnectionState{}
+				req.Request.TLS.PeerCertificates = append(req.Request.TLS.PeerCertificates, fakecert)
+				app.vncTokens = newFakeVNCTokens()
+				token, _, err := app.vncTokens.Issue(newTokenVMI("othervmi"), "user", DefaultVNCTokenDuration)
+				Expect(err).ToNot(HaveOccurred())
+
+				req.Request.URL.Path = "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc"
+				req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+
+				allowed, reason, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+				Expect(reason).To(ContainSubstring("invalid VNC token"))
+
+				close(done)
+			}, 5)
+
+			It("should reject VNC access with a token of another user", func(done Done) {
+				req.Request.TLS = &tls.ConnectionState{}
+				req.Request.TLS.PeerCertificates = append(req.Request.TLS.PeerCertificates, fakecert)
+				app.vncTokens = newFakeVNCTokens()
+				token, _, err := app.vncTokens.Issue(newTokenVMI("testvmi"), "otheruser", DefaultVNCTokenDuration)
+				Expect(err).ToNot(HaveOccurred())
+
+				req.Request.URL.Path = "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc"
+				req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+
+				allowed, reason, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+				Expect(reason).To(ContainSubstring("token was issued for another user"))
+
+				close(done)
+			}, 5)
+
+			It("should require authentication for a VNC token", func(done Done) {
+				app.vncTokens = newFakeVNCTokens()
+				token, _, err := app.vncTokens.Issue(newTokenVMI("testvmi"), "user", DefaultVNCTokenDuration)
+				Expect(err).ToNot(HaveOccurred())
+
+				req.Request.URL.Path = "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/vnc"
+				req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+
+				allowed, reason, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+				Expect(reason).To(Equal("request is not authenticated"))
+
+				close(done)
+			}, 5)
+
+			It("should require authentication for a token on other subresources", func(done Done) {
+				app.vncTokens = newFakeVNCTokens()
+				token, _, err := app.vncTokens.Issue(newTokenVMI("testvmi"), "user", DefaultVNCTokenDuration)
+				Expect(err).ToNot(HaveOccurred())
+
+				req.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+
+				allowed, reason, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+				Expect(reason).To(Equal("request is not authenticated"))
+
+				close(done)
+			}, 5)
+
+			It("should review the portforward subresource without the port", func() {
+				req.Request.URL.Path = "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/portforward/22"
+
+				result, err := app.generateAccessReview(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Spec.ResourceAttributes.Resource).To(Equal("virtualmachineinstances"))
+				Expect(result.Spec.ResourceAttributes.Name).To(Equal("testvmi"))
+				Expect(result.Spec.ResourceAttributes.Subresource).To(Equal("portforward"))
+			})
+
+			It("should review the custom metrics like other metrics adapters", func() {
+				req.Request.URL.Path = "/apis/custom.metrics.k8s.io/v1beta1/namespaces/default/pods/*/" + VMIMemoryResidentMetric
+
+				result, err := app.generateAccessReview(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Spec.ResourceAttributes.Group).To(Equal(CustomMetricsGroup))
+				Expect(result.Spec.ResourceAttributes.Resource).To(Equal("pods"))
+				Expect(result.Spec.ResourceAttributes.Name).To(Equal("*"))
+				Expect(result.Spec.ResourceAttributes.Subresource).To(Equal(VMIMemoryResidentMetric))
+			})
+
 			It("should not allow user if auth check fails", func(done Done) {
 
 				req.Request.TLS = &tls.ConnectionState{}
@@ -147,6 +252,22 @@ var _ = Describe("Authorizer", func() {
 				close(done)
 			}, 5)
 
+			table.DescribeTable("should allow all authenticated users", func(path string) {
+				req.Request.URL.Path = path
+				allowed, _, err := app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeFalse())
+
+				req.Request.TLS = &tls.ConnectionState{}
+				req.Request.TLS.PeerCertificates = append(req.Request.TLS.PeerCertificates, fakecert)
+				allowed, _, err = app.Authorize(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+			},
+				table.Entry("to read the capabilities", "/apis/subresources.kubevirt.io/v1/capabilities"),
+				table.Entry("to calculate the launcher resources", "/apis/subresources.kubevirt.io/v1/launcher-resources"),
+			)
+
 			table.DescribeTable("should allow all users for info endpoints", func(path string) {
 				req.Request.URL.Path = path
 				allowed, _, err := app.Authorize(req)
@@ -173,6 +294,7 @@ var _ = Describe("Authorizer", func() {
 				table.Entry("random2", "/1/2/3/4/5/6/7/8/9/0/1/2/3/4/5/6/7/8/9"),
 				table.Entry("no subresource provided", "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"),
 				table.Entry("invalid resource type", "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/madeupresource/testvmi/console"),
+				table.Entry("extra path on a subresource other than portforward", "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi/console/22"),
 			)
 		})
 
@@ -0,0 +1,48 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"runtime"
+
+	"github.com/emicklei/go-restful"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+// CapabilitiesRequestHandler returns the enabled feature gates, the machine types
+// and the effective configuration of the cluster, so that clients don't need to
+// read the KubeVirt CR
+func (app *SubresourceAPIApp) CapabilitiesRequestHandler(_ *restful.Request, response *restful.Response) {
+	config := app.clusterConfig.GetConfig()
+
+	capabilities := v1.ClusterCapabilities{
+		MachineType:      config.MachineType,
+		EmulatedMachines: config.EmulatedMachines,
+		// the default machine types are chosen for the architecture of the control plane
+		Architecture:  runtime.GOARCH,
+		Configuration: config,
+	}
+	if config.DeveloperConfiguration != nil {
+		capabilities.FeatureGates = config.DeveloperConfiguration.FeatureGates
+	}
+
+	response.WriteEntity(capabilities)
+}
@@ -0,0 +1,369 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+)
+
+const (
+	CustomMetricsGroup   = "custom.metrics.k8s.io"
+	CustomMetricsVersion = "v1beta1"
+
+	// VMIVCPUUtilizationMetric is the CPU time the guest consumed, in percent of its vCPUs
+	VMIVCPUUtilizationMetric = "kubevirt_vmi_vcpu_utilization_percent"
+	// VMIMemoryResidentMetric is the resident set size of the domain
+	VMIMemoryResidentMetric = "kubevirt_vmi_memory_resident_bytes"
+
+	// the resources the metrics can be requested for
+	customMetricsPodsResource = "pods"
+	customMetricsVMIsResource = "virtualmachineinstances.kubevirt.io"
+
+	// statsCacheTTL bounds how often the stats of a VMI are requested from
+	// virt-handler, the horizontal pod autoscaler polls every 15 seconds
+	statsCacheTTL = 10 * time.Second
+	// statsMaxAge is how long the stats of VMIs which are not asked for anymore are kept
+	statsMaxAge = 10 * time.Minute
+	// cpuSampleInterval is the time between the two samples the first CPU
+	// utilization of a VMI is computed from
+	cpuSampleInterval = time.Second
+)
+
+var customMetrics = []string{VMIVCPUUtilizationMetric, VMIMemoryResidentMetric}
+
+// MetricValueList and MetricValue mirror the wire format of the
+// custom.metrics.k8s.io/v1beta1 API, which the horizontal pod autoscaler reads
+type MetricValueList struct {
+	k8smetav1.TypeMeta `json:",inline"`
+	k8smetav1.ListMeta `json:"metadata,omitempty"`
+	Items              []MetricValue `json:"items"`
+}
+
+type MetricValue struct {
+	k8smetav1.TypeMeta `json:",inline"`
+	DescribedObject    k8sv1.ObjectReference    `json:"describedObject"`
+	MetricName         string                   `json:"metricName"`
+	Timestamp          k8smetav1.Time           `json:"timestamp"`
+	WindowSeconds      *int64                   `json:"window,omitempty"`
+	Value              resource.Quantity        `json:"value"`
+	Selector           *k8smetav1.LabelSelector `json:"selector"`
+}
+
+// statsSamples are the last two stats samples of a VMI, the CPU utilization
+// is computed from the CPU time consumed between them
+type statsSamples struct {
+	previous  *v1.VirtualMachineInstanceStats
+	latest    *v1.VirtualMachineInstanceStats
+	fetchedAt time.Time
+}
+
+// CustomMetricsAPIApp serves the guest metrics of VMIs through the custom
+// metrics API, so that horizontal pod autoscalers can scale e.g.
+// VirtualMachineInstanceReplicaSets on the guest load instead of the load of
+// the virt-launcher pods. The stats are fetched from virt-handler and cached.
+type CustomMetricsAPIApp struct {
+	virtCli                 kubecli.KubevirtClient
+	consoleServerPort       int
+	handlerTLSConfiguration *tls.Config
+
+	lock       sync.Mutex
+	samples    map[types.UID]statsSamples
+	fetchStats func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstanceStats, error)
+	now        func() time.Time
+	sleep      func(time.Duration)
+}
+
+func NewCustomMetricsAPIApp(virtCli kubecli.KubevirtClient, consoleServerPort int, tlsConfiguration *tls.Config) *CustomMetricsAPIApp {
+	app := &CustomMetricsAPIApp{
+		virtCli:                 virtCli,
+		consoleServerPort:       consoleServerPort,
+		handlerTLSConfiguration: tlsConfiguration,
+		samples:                 map[types.UID]statsSamples{},
+		now:                     time.Now,
+		sleep:                   time.Sleep,
+	}
+	app.fetchStats = app.fetchStatsFromHandler
+	return app
+}
+
+// CustomMetricsResources lists the metrics for the discovery of the custom metrics API
+func CustomMetricsResources() []k8smetav1.APIResource {
+	var resources []k8smetav1.APIResource
+	for _, resource := range []string{customMetricsPodsResource, customMetricsVMIsResource} {
+		for _, metric := range customMetrics {
+			resources = append(resources, k8smetav1.APIResource{
+				Name:       resource + "/" + metric,
+				Namespaced: true,
+				Kind:       "MetricValueList",
+				Verbs:      []string{"get"},
+			})
+		}
+	}
+	return resources
+}
+
+// MetricsRequestHandler returns the value of a metric for a VMI or a launcher
+// pod, or for all the ones matching the label selector if the name is *
+func (app *CustomMetricsAPIApp) MetricsRequestHandler(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	resourceName := request.PathParameter("resource")
+	name := request.PathParameter("name")
+	metric := request.PathParameter("metric")
+	selector := request.QueryParameter("labelSelector")
+
+	if metric != VMIVCPUUtilizationMetric && metric != VMIMemoryResidentMetric {
+		writeError(errors.NewNotFound(metricsGroupResource(resourceName), metric), response)
+		return
+	}
+
+	var objects map[*v1.VirtualMachineInstance]k8sv1.ObjectReference
+	var statusErr *errors.StatusError
+	switch resourceName {
+	case customMetricsPodsResource:
+		objects, statusErr = app.vmisForPods(namespace, name, selector)
+	case customMetricsVMIsResource:
+		objects, statusErr = app.vmis(namespace, name, selector)
+	default:
+		statusErr = errors.NewNotFound(metricsGroupResource(resourceName), name)
+	}
+	if statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
+	list := MetricValueList{
+		TypeMeta: k8smetav1.TypeMeta{
+			Kind:       "MetricValueList",
+			APIVersion: CustomMetricsGroup + "/" + CustomMetricsVersion,
+		},
+		Items: []MetricValue{},
+	}
+	values := make(chan *MetricValue, len(objects))
+	wg := sync.WaitGroup{}
+	for vmi, object := range objects {
+		wg.Add(1)
+		go func(vmi *v1.VirtualMachineInstance, object k8sv1.ObjectReference) {
+			defer wg.Done()
+			values <- app.metricValue(vmi, object, metric)
+		}(vmi, object)
+	}
+	wg.Wait()
+	close(values)
+	for value := range values {
+		if value != nil {
+			list.Items = append(list.Items, *value)
+		}
+	}
+
+	if name != "*" && len(list.Items) == 0 {
+		writeError(errors.NewNotFound(metricsGroupResource(resourceName), name), response)
+		return
+	}
+	response.WriteEntity(list)
+}
+
+// metricValue computes the metric of a VMI from its stats samples, nil if it is not available
+func (app *CustomMetricsAPIApp) metricValue(vmi *v1.VirtualMachineInstance, object k8sv1.ObjectReference, metric string) *MetricValue {
+	samples, err := app.getSamples(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to fetch the stats for the custom metrics")
+		return nil
+	}
+
+	value := &MetricValue{
+		DescribedObject: object,
+		MetricName:      metric,
+		Timestamp:       k8smetav1.NewTime(samples.latest.Timestamp.Time),
+	}
+	switch metric {
+	case VMIVCPUUtilizationMetric:
+		previous, latest := samples.previous, samples.latest
+		elapsed := latest.Timestamp.Sub(previous.Timestamp.Time)
+		// the counters restart with the domain, e.g. after a migration
+		if elapsed <= 0 || latest.VCPUs == 0 || latest.CPUTimeNanoseconds < previous.CPUTimeNanoseconds {
+			return nil
+		}
+		consumed := float64(latest.CPUTimeNanoseconds - previous.CPUTimeNanoseconds)
+		utilization := consumed / float64(elapsed.Nanoseconds()*int64(latest.VCPUs)) * 100
+		value.Value = *resource.NewMilliQuantity(int64(utilization*1000), resource.DecimalSI)
+		window := int64(elapsed.Seconds())
+		value.WindowSeconds = &window
+	case VMIMemoryResidentMetric:
+		value.Value = *resource.NewQuantity(int64(samples.latest.MemoryResidentBytes), resource.BinarySI)
+	}
+	return value
+}
+
+// getSamples returns the cached stats samples of the VMI, they are refreshed
+// once they are older than statsCacheTTL
+func (app *CustomMetricsAPIApp) getSamples(vmi *v1.VirtualMachineInstance) (statsSamples, error) {
+	app.lock.Lock()
+	samples, exists := app.samples[vmi.UID]
+	app.lock.Unlock()
+	if exists && app.now().Sub(samples.fetchedAt) < statsCacheTTL {
+		return samples, nil
+	}
+
+	if !exists {
+		first, err := app.fetchStats(vmi)
+		if err != nil {
+			return samples, err
+		}
+		samples.latest = first
+		app.sleep(cpuSampleInterval)
+	}
+	latest, err := app.fetchStats(vmi)
+	if err != nil {
+		return samples, err
+	}
+	samples = statsSamples{
+		previous:  samples.latest,
+		latest:    latest,
+		fetchedAt: app.now(),
+	}
+
+	app.lock.Lock()
+	defer app.lock.Unlock()
+	app.samples[vmi.UID] = samples
+	for uid, cached := range app.samples {
+		if app.now().Sub(cached.fetchedAt) > statsMaxAge {
+			delete(app.samples, uid)
+		}
+	}
+	return samples, nil
+}
+
+func (app *CustomMetricsAPIApp) fetchStatsFromHandler(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstanceStats, error) {
+	conn := kubecli.NewVirtHandlerClient(app.virtCli).Port(app.consoleServerPort).ForNode(vmi.Status.NodeName)
+	url, err := conn.StatsURI(vmi)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := conn.Get(url, app.handlerTLSConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	vmiStats := &v1.VirtualMachineInstanceStats{}
+	if err := json.Unmarshal([]byte(resp), vmiStats); err != nil {
+		return nil, fmt.Errorf("error unmarshalling stats response: %v", err)
+	}
+	return vmiStats, nil
+}
+
+// vmis returns the running VMIs with the name, or matching the selector if the name is *
+func (app *CustomMetricsAPIApp) vmis(namespace, name, selector string) (map[*v1.VirtualMachineInstance]k8sv1.ObjectReference, *errors.StatusError) {
+	var vmis []v1.VirtualMachineInstance
+	if name == "*" {
+		list, err := app.virtCli.VirtualMachineInstance(namespace).List(&k8smetav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		vmis = list.Items
+	} else {
+		vmi, err := app.virtCli.VirtualMachineInstance(namespace).Get(name, &k8smetav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound(v1.Resource("virtualmachineinstance"), name)
+		} else if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		vmis = append(vmis, *vmi)
+	}
+
+	objects := map[*v1.VirtualMachineInstance]k8sv1.ObjectReference{}
+	for i := range vmis {
+		vmi := &vmis[i]
+		if !vmi.IsRunning() {
+			continue
+		}
+		objects[vmi] = k8sv1.ObjectReference{
+			Kind:       v1.VirtualMachineInstanceGroupVersionKind.Kind,
+			APIVersion: v1.GroupVersion.String(),
+			Namespace:  vmi.Namespace,
+			Name:       vmi.Name,
+		}
+	}
+	return objects, nil
+}
+
+// vmisForPods returns the running VMIs of the launcher pods with the name, or
+// matching the selector if the name is *
+func (app *CustomMetricsAPIApp) vmisForPods(namespace, name, selector string) (map[*v1.VirtualMachineInstance]k8sv1.ObjectReference, *errors.StatusError) {
+	var pods []k8sv1.Pod
+	if name == "*" {
+		list, err := app.virtCli.CoreV1().Pods(namespace).List(k8smetav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		pods = list.Items
+	} else {
+		pod, err := app.virtCli.CoreV1().Pods(namespace).Get(name, k8smetav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound(k8sv1.Resource("pods"), name)
+		} else if err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		pods = append(pods, *pod)
+	}
+
+	vmis, statusErr := app.vmis(namespace, "*", "")
+	if statusErr != nil {
+		return nil, statusErr
+	}
+	vmisByUID := map[string]*v1.VirtualMachineInstance{}
+	for vmi := range vmis {
+		vmisByUID[string(vmi.UID)] = vmi
+	}
+
+	objects := map[*v1.VirtualMachineInstance]k8sv1.ObjectReference{}
+	for _, pod := range pods {
+		vmi, exists := vmisByUID[pod.Labels[v1.CreatedByLabel]]
+		// only the pod running the domain reports its metrics during a migration
+		if !exists || pod.Labels[v1.AppLabel] != "virt-launcher" || pod.Spec.NodeName != vmi.Status.NodeName {
+			continue
+		}
+		objects[vmi] = k8sv1.ObjectReference{
+			Kind:       "Pod",
+			APIVersion: "v1",
+			Namespace:  pod.Namespace,
+			Name:       pod.Name,
+		}
+	}
+	return objects, nil
+}
+
+func metricsGroupResource(resource string) schema.GroupResource {
+	return schema.GroupResource{Group: CustomMetricsGroup, Resource: resource}
+}
@@ -0,0 +1,192 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+var _ = Describe("Custom metrics", func() {
+
+	var ctrl *gomock.Controller
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var kubeClient *fake.Clientset
+	var app *CustomMetricsAPIApp
+	var now time.Time
+	var lock sync.Mutex
+	var samples map[string]int
+	var cpuTime map[string]uint64
+	var fetches int
+
+	newVMI := func(name string) v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI(name)
+		vmi.UID = types.UID(name + "-uid")
+		vmi.Status.Phase = v1.Running
+		vmi.Status.NodeName = "node01"
+		return *vmi
+	}
+
+	request := func(resource, name, metric, selector string) *httptest.ResponseRecorder {
+		req := restful.NewRequest(&http.Request{URL: &url.URL{RawQuery: url.Values{"labelSelector": []string{selector}}.Encode()}})
+		req.PathParameters()["namespace"] = k8sv1.NamespaceDefault
+		req.PathParameters()["resource"] = resource
+		req.PathParameters()["name"] = name
+		req.PathParameters()["metric"] = metric
+		recorder := httptest.NewRecorder()
+		response := restful.NewResponse(recorder)
+		response.SetRequestAccepts(restful.MIME_JSON)
+		app.MetricsRequestHandler(req, response)
+		return recorder
+	}
+
+	decode := func(recorder *httptest.ResponseRecorder) MetricValueList {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		list := MetricValueList{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &list)).To(Succeed())
+		return list
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+		virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		now = time.Unix(1600000000, 0)
+		samples = map[string]int{}
+		cpuTime = map[string]uint64{}
+		fetches = 0
+		app = NewCustomMetricsAPIApp(virtClient, 0, nil)
+		app.now = func() time.Time { return now }
+		app.sleep = func(time.Duration) {}
+		// every sample of a VMI is taken a second after the previous one and
+		// consumed half of its two vCPUs in between
+		app.fetchStats = func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstanceStats, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			fetches++
+			stats := &v1.VirtualMachineInstanceStats{
+				Timestamp:           k8smetav1.NewMicroTime(time.Unix(1600000000+int64(samples[vmi.Name]), 0)),
+				VCPUs:               2,
+				CPUTimeNanoseconds:  cpuTime[vmi.Name],
+				MemoryResidentBytes: 1024 * 1024 * 1024,
+			}
+			samples[vmi.Name]++
+			cpuTime[vmi.Name] += uint64(time.Second.Nanoseconds())
+			return stats, nil
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should list the metrics for the discovery", func() {
+		Expect(CustomMetricsResources()).To(ContainElement(k8smetav1.APIResource{
+			Name:       "pods/" + VMIVCPUUtilizationMetric,
+			Namespaced: true,
+			Kind:       "MetricValueList",
+			Verbs:      []string{"get"},
+		}))
+	})
+
+	It("should report the vCPU utilization of the VMIs matching the selector", func() {
+		vmiInterface.EXPECT().List(&k8smetav1.ListOptions{LabelSelector: "app=test"}).Return(&v1.VirtualMachineInstanceList{
+			Items: []v1.VirtualMachineInstance{newVMI("testvmi1"), newVMI("testvmi2")},
+		}, nil)
+
+		list := decode(request(customMetricsVMIsResource, "*", VMIVCPUUtilizationMetric, "app=test"))
+		Expect(list.Items).To(HaveLen(2))
+		for _, item := range list.Items {
+			Expect(item.DescribedObject.Kind).To(Equal("VirtualMachineInstance"))
+			Expect(item.MetricName).To(Equal(VMIVCPUUtilizationMetric))
+			Expect(item.Value.String()).To(Equal("50"))
+			Expect(*item.WindowSeconds).To(Equal(int64(1)))
+		}
+	})
+
+	It("should report the memory of the VMIs of launcher pods", func() {
+		vmi := newVMI("testvmi")
+		pod := &k8sv1.Pod{
+			ObjectMeta: k8smetav1.ObjectMeta{
+				Name:      "virt-launcher-testvmi-abcde",
+				Namespace: k8sv1.NamespaceDefault,
+				Labels:    map[string]string{v1.AppLabel: "virt-launcher", v1.CreatedByLabel: string(vmi.UID)},
+			},
+			Spec: k8sv1.PodSpec{NodeName: "node01"},
+		}
+		_, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Create(pod)
+		Expect(err).ToNot(HaveOccurred())
+		vmiInterface.EXPECT().List(gomock.Any()).Return(&v1.VirtualMachineInstanceList{Items: []v1.VirtualMachineInstance{vmi}}, nil)
+
+		list := decode(request(customMetricsPodsResource, pod.Name, VMIMemoryResidentMetric, ""))
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].DescribedObject.Kind).To(Equal("Pod"))
+		Expect(list.Items[0].DescribedObject.Name).To(Equal(pod.Name))
+		Expect(list.Items[0].Value.String()).To(Equal("1Gi"))
+	})
+
+	It("should cache the stats of the VMIs", func() {
+		vmi := newVMI("testvmi")
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(&vmi, nil).Times(3)
+
+		decode(request(customMetricsVMIsResource, "testvmi", VMIVCPUUtilizationMetric, ""))
+		Expect(fetches).To(Equal(2))
+		decode(request(customMetricsVMIsResource, "testvmi", VMIMemoryResidentMetric, ""))
+		Expect(fetches).To(Equal(2))
+
+		now = now.Add(statsCacheTTL)
+		decode(request(customMetricsVMIsResource, "testvmi", VMIVCPUUtilizationMetric, ""))
+		Expect(fetches).To(Equal(3))
+	})
+
+	It("should not report the vCPU utilization after the domain restarted", func() {
+		vmi := newVMI("testvmi")
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(&vmi, nil).Times(2)
+		decode(request(customMetricsVMIsResource, "testvmi", VMIVCPUUtilizationMetric, ""))
+
+		cpuTime["testvmi"] = 0
+		now = now.Add(statsCacheTTL)
+		Expect(request(customMetricsVMIsResource, "testvmi", VMIVCPUUtilizationMetric, "").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject unknown metrics", func() {
+		Expect(request(customMetricsVMIsResource, "testvmi", "madeup", "").Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -43,6 +43,11 @@ func ComposeAPIDefinitions() []*restful.WebService {
 	vmGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachines"}
 	migrationGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachineinstancemigrations"}
 	kubeVirtGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "kubevirt"}
+	conformanceRunGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "conformanceruns"}
+	vmExportGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachineexports"}
+	vmBackupGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachinebackups"}
+	vmInstancetypeGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachineinstancetypes"}
+	vmPreferenceGVR := schema.GroupVersionResource{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version, Resource: "virtualmachinepreferences"}
 
 	vmsGVR := snapshotv1.SchemeGroupVersion.WithResource("virtualmachinesnapshots")
 	vmscGVR := snapshotv1.SchemeGroupVersion.WithResource("virtualmachinesnapshotcontents")
@@ -81,6 +86,31 @@ func ComposeAPIDefinitions() []*restful.WebService {
 		panic(err)
 	}
 
+	ws, err = GenericResourceProxy(ws, conformanceRunGVR, &v1.ConformanceRun{}, v1.ConformanceRunGroupVersionKind.Kind, &v1.ConformanceRunList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = GenericResourceProxy(ws, vmExportGVR, &v1.VirtualMachineExport{}, v1.VirtualMachineExportGroupVersionKind.Kind, &v1.VirtualMachineExportList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = GenericResourceProxy(ws, vmBackupGVR, &v1.VirtualMachineBackup{}, v1.VirtualMachineBackupGroupVersionKind.Kind, &v1.VirtualMachineBackupList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = GenericResourceProxy(ws, vmInstancetypeGVR, &v1.VirtualMachineInstancetype{}, v1.VirtualMachineInstancetypeGroupVersionKind.Kind, &v1.VirtualMachineInstancetypeList{})
+	if err != nil {
+		panic(err)
+	}
+
+	ws, err = GenericResourceProxy(ws, vmPreferenceGVR, &v1.VirtualMachinePreference{}, v1.VirtualMachinePreferenceGroupVersionKind.Kind, &v1.VirtualMachinePreferenceList{})
+	if err != nil {
+		panic(err)
+	}
+
 	ws1, err := ResourceProxyAutodiscovery(vmiGVR)
 	if err != nil {
 		panic(err)
@@ -323,6 +353,10 @@ func NamespaceParam(ws *restful.WebService) *restful.Parameter {
 	return ws.PathParameter("namespace", "Object name and auth scope, such as for teams and projects").Required(true)
 }
 
+func PortParam(ws *restful.WebService) *restful.Parameter {
+	return ws.PathParameter("port", "The target port for portforward on the VirtualMachineInstance.").Required(true)
+}
+
 func labelSelectorParam(ws *restful.WebService) *restful.Parameter {
 	return ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels. Defaults to everything")
 }
@@ -383,6 +417,10 @@ func GroupVersionBasePath(gvr schema.GroupVersion) string {
 	return fmt.Sprintf("/apis/%s/%s", gvr.Group, gvr.Version)
 }
 
+func NamespaceBasePath() string {
+	return "/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}"
+}
+
 func ResourceBasePath(gvr schema.GroupVersionResource) string {
 	return fmt.Sprintf("/namespaces/{namespace:[a-z0-9][a-z0-9\\-]*}/%s", gvr.Resource)
 }
@@ -0,0 +1,145 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/instancetype"
+)
+
+// ExpandSpecRequestHandler returns the VirtualMachine of the request body with its instancetype,
+// preference and defaults applied to the template, without creating it
+func (app *SubresourceAPIApp) ExpandSpecRequestHandler(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+
+	if request.Request.Body == nil {
+		writeError(errors.NewBadRequest("Request with no body, a VirtualMachine is expected as the request body"), response)
+		return
+	}
+	defer request.Request.Body.Close()
+
+	vm := &v1.VirtualMachine{}
+	err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(vm)
+	switch err {
+	case nil:
+		break
+	case io.EOF:
+		writeError(errors.NewBadRequest("Request with no body, a VirtualMachine is expected as the request body"), response)
+		return
+	default:
+		writeError(errors.NewBadRequest(fmt.Sprintf("Can not unmarshal Request body to struct, error: %s", err)), response)
+		return
+	}
+
+	if vm.Namespace == "" {
+		vm.Namespace = namespace
+	} else if vm.Namespace != namespace {
+		writeError(errors.NewBadRequest(fmt.Sprintf("VirtualMachine namespace %s does not match the request namespace %s", vm.Namespace, namespace)), response)
+		return
+	}
+
+	app.writeExpandedSpec(vm, response)
+}
+
+// ExpandVMSpecRequestHandler returns an existing VirtualMachine with its instancetype,
+// preference and defaults applied to the template
+func (app *SubresourceAPIApp) ExpandVMSpecRequestHandler(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+	namespace := request.PathParameter("namespace")
+
+	vm, statusErr := app.fetchVirtualMachine(name, namespace)
+	if statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
+	app.writeExpandedSpec(vm, response)
+}
+
+func (app *SubresourceAPIApp) writeExpandedSpec(vm *v1.VirtualMachine, response *restful.Response) {
+	if statusErr := app.expandSpec(vm); statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
+	if err := response.WriteHeaderAndJson(http.StatusOK, vm, restful.MIME_JSON); err != nil {
+		log.Log.Object(vm).Reason(err).Error("Failed to write http response.")
+	}
+}
+
+// expandSpec applies the instancetype and preference of the VirtualMachine to its template, the same
+// way they are applied when the VirtualMachine gets started, followed by the defaults of the mutating
+// webhook. The references are dropped, since the returned template already contains everything.
+func (app *SubresourceAPIApp) expandSpec(vm *v1.VirtualMachine) *errors.StatusError {
+	if vm.Spec.Template == nil {
+		return errors.NewBadRequest("VirtualMachine has no template to expand")
+	}
+
+	if vm.Spec.Instancetype != nil || vm.Spec.Preference != nil {
+		if !app.clusterConfig.InstancetypeEnabled() {
+			return errors.NewBadRequest("Instancetype feature gate is not enabled in kubevirt-config")
+		}
+
+		instancetypeSpec, err := instancetype.FindInstancetypeSpec(app.virtCli, vm)
+		if err != nil {
+			return toStatusError(err)
+		}
+		preferenceSpec, err := instancetype.FindPreferenceSpec(app.virtCli, vm)
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		conflicts := instancetype.ApplyToVmi(k8sfield.NewPath("spec", "template", "spec"), instancetypeSpec, preferenceSpec, &vm.Spec.Template.Spec)
+		if len(conflicts) > 0 {
+			var fieldErrors k8sfield.ErrorList
+			for _, conflict := range conflicts {
+				fieldErrors = append(fieldErrors, k8sfield.Forbidden(conflict, "conflicts with selected instancetype"))
+			}
+			return errors.NewInvalid(v1.VirtualMachineGroupVersionKind.GroupKind(), vm.Name, fieldErrors)
+		}
+
+		vm.Spec.Instancetype = nil
+		vm.Spec.Preference = nil
+	}
+
+	// like the mutating webhook of VirtualMachines
+	if vm.Spec.Template.Spec.Domain.Machine.Type == "" {
+		vm.Spec.Template.Spec.Domain.Machine.Type = app.clusterConfig.GetMachineType()
+	}
+
+	return nil
+}
+
+func toStatusError(err error) *errors.StatusError {
+	if statusErr, ok := err.(*errors.StatusError); ok {
+		return statusErr
+	}
+	return errors.NewInternalError(err)
+}
@@ -94,3 +94,11 @@ func (_m *MockVirtApiAuthorizor) GetExtraPrefixHeaders() []string {
 func (_mr *_MockVirtApiAuthorizorRecorder) GetExtraPrefixHeaders() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExtraPrefixHeaders")
 }
+
+func (_m *MockVirtApiAuthorizor) SetVNCTokens(tokens *VNCTokens) {
+	_m.ctrl.Call(_m, "SetVNCTokens", tokens)
+}
+
+func (_mr *_MockVirtApiAuthorizorRecorder) SetVNCTokens(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetVNCTokens", arg0)
+}
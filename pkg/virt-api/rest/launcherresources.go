@@ -0,0 +1,74 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-controller/services"
+)
+
+// LauncherResourcesRequestHandler returns the resources the virt-launcher pod of the
+// VirtualMachineInstance of the request body would request, including the overhead
+// KubeVirt adds to the resources of the guest. The defaults of the mutating webhook
+// are not applied, the VirtualMachineInstance has to request its memory explicitly.
+func (app *SubresourceAPIApp) LauncherResourcesRequestHandler(request *restful.Request, response *restful.Response) {
+	if request.Request.Body == nil {
+		writeError(errors.NewBadRequest("Request with no body, a VirtualMachineInstance is expected as the request body"), response)
+		return
+	}
+	defer request.Request.Body.Close()
+
+	vmi := &v1.VirtualMachineInstance{}
+	err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(vmi)
+	switch err {
+	case nil:
+		break
+	case io.EOF:
+		writeError(errors.NewBadRequest("Request with no body, a VirtualMachineInstance is expected as the request body"), response)
+		return
+	default:
+		writeError(errors.NewBadRequest(fmt.Sprintf("Can not unmarshal Request body to struct, error: %s", err)), response)
+		return
+	}
+
+	if vmi.Spec.Domain.Resources.Requests.Memory().IsZero() {
+		writeError(errors.NewBadRequest("VirtualMachineInstance requests no memory, spec.domain.resources.requests.memory is required"), response)
+		return
+	}
+
+	resources, err := services.CalculateLauncherResources(vmi)
+	if err != nil {
+		writeError(errors.NewBadRequest(fmt.Sprintf("Can not calculate the virt-launcher resources, error: %v", err)), response)
+		return
+	}
+
+	if err := response.WriteHeaderAndJson(http.StatusOK, resources, restful.MIME_JSON); err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to write http response.")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+)
+
+// RollbackVMRequestHandler restores the template of a VirtualMachine from one of its template revisions.
+// The VirtualMachine gets annotated with the revision, which lets the admission webhook reject the
+// rollback while the VirtualMachineInstance is migrating.
+func (app *SubresourceAPIApp) RollbackVMRequestHandler(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+	namespace := request.PathParameter("namespace")
+
+	opts := &v1.RollbackOptions{}
+
+	if request.Request.Body != nil {
+		defer request.Request.Body.Close()
+		err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(opts)
+		switch err {
+		case io.EOF, nil:
+			break
+		default:
+			writeError(errors.NewBadRequest(fmt.Sprintf("Can not unmarshal Request body to struct, error: %s",
+				err)), response)
+			return
+		}
+	} else {
+		writeError(errors.NewBadRequest("Request with no body, a revision name is expected as the request body"),
+			response)
+		return
+	}
+
+	if opts.RevisionName == "" {
+		writeError(errors.NewBadRequest("Please provide the revision to roll back to"), response)
+		return
+	}
+
+	vm, statusErr := app.fetchVirtualMachine(name, namespace)
+	if statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
+	revision, err := app.virtCli.AppsV1().ControllerRevisions(namespace).Get(opts.RevisionName, k8smetav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			writeError(errors.NewBadRequest(fmt.Sprintf("ControllerRevision %s does not exist", opts.RevisionName)), response)
+			return
+		}
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
+	if revision.Labels[v1.VirtualMachineTemplateRevisionLabel] != string(vm.UID) {
+		writeError(errors.NewBadRequest(fmt.Sprintf("ControllerRevision %s is not a template revision of VirtualMachine %s", opts.RevisionName, name)), response)
+		return
+	}
+
+	template := &v1.VirtualMachineInstanceTemplateSpec{}
+	if err := json.Unmarshal(revision.Data.Raw, template); err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
+	rollbackJson, err := getRollbackJson(vm, template, revision.Name)
+	if err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
+	log.Log.Object(vm).V(4).Infof("Patching VM: %s", rollbackJson)
+	if _, err := app.virtCli.VirtualMachine(namespace).Patch(vm.Name, types.JSONPatchType, []byte(rollbackJson)); err != nil {
+		writeError(toStatusError(err), response)
+		return
+	}
+
+	response.WriteHeader(http.StatusAccepted)
+}
+
+func getRollbackJson(vm *v1.VirtualMachine, template *v1.VirtualMachineInstanceTemplateSpec, revisionName string) (string, error) {
+	templateJson, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+
+	patch := []string{
+		fmt.Sprintf(`{ "op": "test", "path": "/metadata/resourceVersion", "value": "%s" }`, vm.ResourceVersion),
+		fmt.Sprintf(`{ "op": "replace", "path": "/spec/template", "value": %s }`, string(templateJson)),
+	}
+	if vm.Annotations == nil {
+		patch = append(patch, fmt.Sprintf(`{ "op": "add", "path": "/metadata/annotations", "value": { "%s": "%s" } }`, v1.RollbackRevisionAnnotation, revisionName))
+	} else {
+		path := "/metadata/annotations/" + strings.ReplaceAll(v1.RollbackRevisionAnnotation, "/", "~1")
+		patch = append(patch, fmt.Sprintf(`{ "op": "add", "path": "%s", "value": "%s" }`, path, revisionName))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(patch, ", ")), nil
+}
@@ -26,12 +26,15 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/emicklei/go-restful"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -45,23 +48,32 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/controller"
+	pvcutils "kubevirt.io/kubevirt/pkg/util/types"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
+// memoryDumpOverhead is the space the headers of a memory dump take on top of the guest memory
+const memoryDumpOverhead = "100Mi"
+
 type SubresourceAPIApp struct {
 	virtCli                 kubecli.KubevirtClient
 	consoleServerPort       int
 	handlerTLSConfiguration *tls.Config
 	credentialsLock         *sync.Mutex
 	statusUpdater           *status.VMStatusUpdater
+	vncTokens               *VNCTokens
+	clusterConfig           *virtconfig.ClusterConfig
 }
 
-func NewSubresourceAPIApp(virtCli kubecli.KubevirtClient, consoleServerPort int, tlsConfiguration *tls.Config) *SubresourceAPIApp {
+func NewSubresourceAPIApp(virtCli kubecli.KubevirtClient, consoleServerPort int, tlsConfiguration *tls.Config, vncTokens *VNCTokens, clusterConfig *virtconfig.ClusterConfig) *SubresourceAPIApp {
 	return &SubresourceAPIApp{
 		virtCli:                 virtCli,
 		consoleServerPort:       consoleServerPort,
 		credentialsLock:         &sync.Mutex{},
 		handlerTLSConfiguration: tlsConfiguration,
 		statusUpdater:           status.NewVMStatusUpdater(virtCli),
+		vncTokens:               vncTokens,
+		clusterConfig:           clusterConfig,
 	}
 }
 
@@ -161,6 +173,7 @@ func (app *SubresourceAPIApp) putRequestHandler(request *restful.Request, respon
 }
 
 func (app *SubresourceAPIApp) VNCRequestHandler(request *restful.Request, response *restful.Response) {
+	token := request.QueryParameter(VNCTokenParam)
 	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
 		// If there are no graphics devices present, we can't proceed
 		if vmi.Spec.Domain.Devices.AutoattachGraphicsDevice != nil && *vmi.Spec.Domain.Devices.AutoattachGraphicsDevice == false {
@@ -172,6 +185,13 @@ func (app *SubresourceAPIApp) VNCRequestHandler(request *restful.Request, respon
 		if condManager.HasCondition(vmi, v1.VirtualMachineInstancePaused) {
 			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is paused"))
 		}
+		// A token opens a single connection to the VMI it was issued for
+		if token != "" && app.vncTokens != nil {
+			if err := app.vncTokens.Consume(token, vmi); err != nil {
+				log.Log.Object(vmi).Reason(err).Warning("Rejecting a VNC token")
+				return errors.NewUnauthorized(fmt.Sprintf("invalid VNC token: %v", err))
+			}
+		}
 		return nil
 	}
 	getConsoleURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
@@ -180,6 +200,49 @@ func (app *SubresourceAPIApp) VNCRequestHandler(request *restful.Request, respon
 	app.streamRequestHandler(request, response, validate, getConsoleURL)
 }
 
+// VNCTokenRequestHandler issues a token for the vnc subresource of the VMI
+func (app *SubresourceAPIApp) VNCTokenRequestHandler(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+	namespace := request.PathParameter("namespace")
+
+	duration := DefaultVNCTokenDuration
+	if param := request.QueryParameter(VNCTokenDurationParam); param != "" {
+		var err error
+		duration, err = time.ParseDuration(param)
+		if err != nil || duration <= 0 || duration > MaxVNCTokenDuration {
+			writeError(errors.NewBadRequest(fmt.Sprintf("invalid duration %s, must be positive and at most %s", param, MaxVNCTokenDuration)), response)
+			return
+		}
+	}
+
+	vmi, statusError := app.fetchVirtualMachineInstance(name, namespace)
+	if statusError != nil {
+		writeError(statusError, response)
+		return
+	}
+	if vmi.Spec.Domain.Devices.AutoattachGraphicsDevice != nil && *vmi.Spec.Domain.Devices.AutoattachGraphicsDevice == false {
+		writeError(errors.NewBadRequest("No graphics devices are present."), response)
+		return
+	}
+
+	user := app.vncTokens.userName(request.Request.Header)
+	if user == "" {
+		writeError(errors.NewUnauthorized("the user requesting the token is unknown"), response)
+		return
+	}
+	token, expires, err := app.vncTokens.Issue(vmi, user, duration)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to issue a VNC token")
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
+	response.WriteEntity(v1.VNCToken{
+		Token:               token,
+		ExpirationTimestamp: k8smetav1.NewTime(expires),
+	})
+}
+
 func (app *SubresourceAPIApp) getVirtHandlerConnForVMI(vmi *v1.VirtualMachineInstance) (kubecli.VirtHandlerConn, error) {
 	if !vmi.IsRunning() {
 		return nil, goerror.New(fmt.Sprintf("Unable to connect to VirtualMachineInstance because phase is %s instead of %s", vmi.Status.Phase, v1.Running))
@@ -206,6 +269,94 @@ func (app *SubresourceAPIApp) ConsoleRequestHandler(request *restful.Request, re
 	app.streamRequestHandler(request, response, validate, getConsoleURL)
 }
 
+// PortForwardRequestHandler tunnels a single TCP connection to a port of the guest
+func (app *SubresourceAPIApp) PortForwardRequestHandler(request *restful.Request, response *restful.Response) {
+	port, err := strconv.Atoi(request.PathParameter("port"))
+	if err != nil || port < 1 || port > 65535 {
+		writeError(errors.NewBadRequest(fmt.Sprintf("invalid port %q", request.PathParameter("port"))), response)
+		return
+	}
+
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		condManager := controller.NewVirtualMachineInstanceConditionManager()
+		if condManager.HasCondition(vmi, v1.VirtualMachineInstancePaused) {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is paused"))
+		}
+		if err := validatePortForwardPort(vmi, port); err != nil {
+			log.Log.Object(vmi).Reason(err).Error("Can't forward a port to the VMI.")
+			return errors.NewBadRequest(err.Error())
+		}
+		return nil
+	}
+	getPortForwardURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.PortForwardURI(vmi, port)
+	}
+	app.streamRequestHandler(request, response, validate, getPortForwardURL)
+}
+
+// validatePortForwardPort makes sure that the port can be reached from the
+// loopback device of the virt-launcher pod, which is only the case for the
+// declared TCP ports of a masquerade interface on the pod network.
+func validatePortForwardPort(vmi *v1.VirtualMachineInstance, port int) error {
+	podNetworkName := ""
+	for _, network := range vmi.Spec.Networks {
+		if network.Pod != nil {
+			podNetworkName = network.Name
+		}
+	}
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		if iface.Name != podNetworkName || iface.Masquerade == nil {
+			continue
+		}
+		for _, p := range iface.Ports {
+			if int(p.Port) == port && (p.Protocol == "" || strings.EqualFold(p.Protocol, "TCP")) {
+				return nil
+			}
+		}
+		return fmt.Errorf("port %d is not declared as a TCP port of interface %s", port, iface.Name)
+	}
+	return fmt.Errorf("port forwarding requires an interface with the masquerade binding on the pod network")
+}
+
+// USBRedirRequestHandler tunnels the usbredir protocol of a single USB device to the guest
+func (app *SubresourceAPIApp) USBRedirRequestHandler(request *restful.Request, response *restful.Response) {
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		if vmi.Spec.Domain.Devices.ClientPassthrough == nil {
+			err := fmt.Errorf("No USB redirection devices are present.")
+			log.Log.Object(vmi).Reason(err).Error("Can't redirect a USB device to the VMI.")
+			return errors.NewBadRequest(err.Error())
+		}
+		return nil
+	}
+	getUSBRedirURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.USBRedirURI(vmi)
+	}
+	app.streamRequestHandler(request, response, validate, getUSBRedirURL)
+}
+
+// BackupRequestHandler tunnels the NBD protocol to the disks exported by a ready VirtualMachineBackup
+func (app *SubresourceAPIApp) BackupRequestHandler(request *restful.Request, response *restful.Response) {
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		backups, err := app.virtCli.VirtualMachineBackup(vmi.Namespace).List(&k8smetav1.ListOptions{})
+		if err != nil {
+			log.Log.Object(vmi).Reason(err).Error("Failed to list the backups of the VMI.")
+			return errors.NewInternalError(err)
+		}
+		for _, backup := range backups.Items {
+			if backup.Spec.Source == vmi.Name && backup.Status.Phase == v1.VirtualMachineBackupReady {
+				return nil
+			}
+		}
+		err = fmt.Errorf("No backup of the VMI is ready.")
+		log.Log.Object(vmi).Reason(err).Error("Can't connect to the backup of the VMI.")
+		return errors.NewBadRequest(err.Error())
+	}
+	getBackupURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.BackupURI(vmi)
+	}
+	app.streamRequestHandler(request, response, validate, getBackupURL)
+}
+
 func getChangeRequestJson(vm *v1.VirtualMachine, changes ...v1.VirtualMachineStateChangeRequest) (string, error) {
 	verb := "add"
 	// Special case: if there's no status field at all, add one.
@@ -334,14 +485,9 @@ func (app *SubresourceAPIApp) RestartVMRequestHandler(request *restful.Request,
 			return
 		}
 	}
-	if bodyStruct.GracePeriodSeconds != nil {
-		if *bodyStruct.GracePeriodSeconds > 0 {
-			writeError(errors.NewBadRequest(fmt.Sprintf("For force restart, only gracePeriod=0 is supported for now")), response)
-			return
-		} else if *bodyStruct.GracePeriodSeconds < 0 {
-			writeError(errors.NewBadRequest(fmt.Sprintf("gracePeriod has to be greater or equal to 0")), response)
-			return
-		}
+	if statusErr := validateGracePeriod(bodyStruct.GracePeriodSeconds, "restart"); statusErr != nil {
+		writeError(statusErr, response)
+		return
 	}
 
 	vm, statusErr := app.fetchVirtualMachine(name, namespace)
@@ -396,33 +542,51 @@ func (app *SubresourceAPIApp) RestartVMRequestHandler(request *restful.Request,
 		return
 	}
 
-	// Only force restart with GracePeriodSeconds=0 is supported for now
-	// Here we are deleting the Pod because CRDs don't support gracePeriodSeconds at the moment
-	if bodyStruct.GracePeriodSeconds != nil {
-		if *bodyStruct.GracePeriodSeconds == 0 {
-			vmiPodname, err := app.findPod(namespace, vmi)
-			if err != nil {
-				writeError(errors.NewInternalError(err), response)
-				return
-			}
-			if vmiPodname == "" {
-				response.WriteHeader(http.StatusAccepted)
-				return
-			}
-			// set termincationGracePeriod and delete the VMI pod to trigger a forced restart
-			err = app.virtCli.CoreV1().Pods(namespace).Delete(vmiPodname, &k8smetav1.DeleteOptions{GracePeriodSeconds: bodyStruct.GracePeriodSeconds})
-			if err != nil {
-				if !errors.IsNotFound(err) {
-					writeError(errors.NewInternalError(err), response)
-					return
-				}
-			}
-		}
+	if err := app.forceDeleteVMIPod(namespace, vmi, bodyStruct.GracePeriodSeconds); err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
 	}
 
 	response.WriteHeader(http.StatusAccepted)
 }
 
+// validateGracePeriod checks the grace period of a forced restart or stop
+func validateGracePeriod(gracePeriod *int64, operation string) *errors.StatusError {
+	if gracePeriod == nil {
+		return nil
+	}
+	if *gracePeriod > 0 {
+		return errors.NewBadRequest(fmt.Sprintf("For force %s, only gracePeriod=0 is supported for now", operation))
+	} else if *gracePeriod < 0 {
+		return errors.NewBadRequest(fmt.Sprintf("gracePeriod has to be greater or equal to 0"))
+	}
+	return nil
+}
+
+// forceDeleteVMIPod deletes the pod of the VMI with the given grace period. Only force restart and stop with
+// GracePeriodSeconds=0 are supported for now.
+// Here we are deleting the Pod because CRDs don't support gracePeriodSeconds at the moment
+func (app *SubresourceAPIApp) forceDeleteVMIPod(namespace string, vmi *v1.VirtualMachineInstance, gracePeriod *int64) error {
+	if gracePeriod == nil || *gracePeriod != 0 {
+		return nil
+	}
+
+	vmiPodname, err := app.findPod(namespace, vmi)
+	if err != nil {
+		return err
+	}
+	if vmiPodname == "" {
+		return nil
+	}
+
+	// set termincationGracePeriod and delete the VMI pod to trigger a forced restart or stop
+	err = app.virtCli.CoreV1().Pods(namespace).Delete(vmiPodname, &k8smetav1.DeleteOptions{GracePeriodSeconds: gracePeriod})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 func (app *SubresourceAPIApp) RenameVMRequestHandler(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
 	namespace := request.PathParameter("namespace")
@@ -644,6 +808,23 @@ func (app *SubresourceAPIApp) StopVMRequestHandler(request *restful.Request, res
 	name := request.PathParameter("name")
 	namespace := request.PathParameter("namespace")
 
+	bodyStruct := &v1.StopOptions{}
+
+	if request.Request.Body != nil {
+		err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(&bodyStruct)
+		switch err {
+		case io.EOF, nil:
+			break
+		default:
+			writeError(errors.NewBadRequest(fmt.Sprintf("Can not unmarshal Request body to struct, error: %s", err)), response)
+			return
+		}
+	}
+	if statusErr := validateGracePeriod(bodyStruct.GracePeriodSeconds, "stop"); statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
 	vm, statusErr := app.fetchVirtualMachine(name, namespace)
 	if statusErr != nil {
 		writeError(statusErr, response)
@@ -703,6 +884,11 @@ func (app *SubresourceAPIApp) StopVMRequestHandler(request *restful.Request, res
 		return
 	}
 
+	if err := app.forceDeleteVMIPod(namespace, vmi, bodyStruct.GracePeriodSeconds); err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
 	response.WriteHeader(http.StatusAccepted)
 }
 
@@ -748,6 +934,170 @@ func (app *SubresourceAPIApp) UnpauseVMIRequestHandler(request *restful.Request,
 
 }
 
+// FreezeVMIRequestHandler quiesces the guest filesystems of a running VMI through the guest agent
+func (app *SubresourceAPIApp) FreezeVMIRequestHandler(request *restful.Request, response *restful.Response) {
+
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		if vmi.Status.Phase != v1.Running {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is not running"))
+		}
+		condManager := controller.NewVirtualMachineInstanceConditionManager()
+		if condManager.HasCondition(vmi, v1.VirtualMachineInstancePaused) {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is paused"))
+		}
+		if !condManager.HasCondition(vmi, v1.VirtualMachineInstanceAgentConnected) {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI does not have guest agent connected"))
+		}
+		if condManager.HasCondition(vmi, v1.VirtualMachineInstanceFrozen) {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is already frozen"))
+		}
+		return nil
+	}
+
+	getURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.FreezeURI(vmi)
+	}
+
+	app.putRequestHandler(request, response, validate, getURL)
+}
+
+// ThawVMIRequestHandler resumes the I/O of the guest filesystems of a frozen VMI
+func (app *SubresourceAPIApp) ThawVMIRequestHandler(request *restful.Request, response *restful.Response) {
+
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		if vmi.Status.Phase != v1.Running {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is not frozen"))
+		}
+		condManager := controller.NewVirtualMachineInstanceConditionManager()
+		if !condManager.HasCondition(vmi, v1.VirtualMachineInstanceFrozen) {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is not frozen"))
+		}
+		return nil
+	}
+
+	getURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.ThawURI(vmi)
+	}
+
+	app.putRequestHandler(request, response, validate, getURL)
+}
+
+// MemoryDumpVMIRequestHandler requests a dump of the guest memory of a running VMI to a PersistentVolumeClaim
+func (app *SubresourceAPIApp) MemoryDumpVMIRequestHandler(request *restful.Request, response *restful.Response) {
+	name := request.PathParameter("name")
+	namespace := request.PathParameter("namespace")
+
+	opts := &v1.MemoryDumpOptions{}
+
+	if request.Request.Body != nil {
+		defer request.Request.Body.Close()
+		err := yaml.NewYAMLOrJSONDecoder(request.Request.Body, 1024).Decode(opts)
+		switch err {
+		case io.EOF, nil:
+			break
+		default:
+			writeError(errors.NewBadRequest(fmt.Sprintf("Can not unmarshal Request body to struct, error: %s",
+				err)), response)
+			return
+		}
+	} else {
+		writeError(errors.NewBadRequest("Request with no body, a claim name is expected as the request body"),
+			response)
+		return
+	}
+
+	if opts.ClaimName == "" {
+		writeError(errors.NewBadRequest("Please provide a claim for the memory dump"), response)
+		return
+	}
+
+	vmi, statusErr := app.fetchVirtualMachineInstance(name, namespace)
+	if statusErr != nil {
+		writeError(statusErr, response)
+		return
+	}
+
+	if !vmi.IsRunning() {
+		writeError(errors.NewConflict(v1.Resource("virtualmachineinstance"), name, fmt.Errorf("VMI is not running")), response)
+		return
+	}
+
+	// the claim is only mounted on the node the VMI runs on
+	if migration := vmi.Status.MigrationState; migration != nil && !migration.Completed && !migration.Failed {
+		writeError(errors.NewConflict(v1.Resource("virtualmachineinstance"), name, fmt.Errorf("VMI is migrating")), response)
+		return
+	}
+
+	if dump := vmi.Status.MemoryDump; dump != nil && (dump.Phase == v1.MemoryDumpPending || dump.Phase == v1.MemoryDumpInProgress) {
+		writeError(errors.NewConflict(v1.Resource("virtualmachineinstance"), name, fmt.Errorf("a memory dump to %s is already in progress", dump.ClaimName)), response)
+		return
+	}
+
+	pvc, exists, isBlock, err := pvcutils.IsPVCBlockFromClient(app.virtCli, namespace, opts.ClaimName)
+	if err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	} else if !exists {
+		writeError(errors.NewBadRequest(fmt.Sprintf("PersistentVolumeClaim %s does not exist", opts.ClaimName)), response)
+		return
+	} else if isBlock {
+		writeError(errors.NewBadRequest(fmt.Sprintf("PersistentVolumeClaim %s is a block volume, the memory is dumped to a file", opts.ClaimName)), response)
+		return
+	}
+
+	if err := validateMemoryDumpClaim(vmi, pvc); err != nil {
+		writeError(errors.NewBadRequest(err.Error()), response)
+		return
+	}
+
+	dump, err := json.Marshal(&v1.VirtualMachineInstanceMemoryDumpStatus{
+		ClaimName: opts.ClaimName,
+		Phase:     v1.MemoryDumpPending,
+	})
+	if err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+	patch := fmt.Sprintf(`[{ "op": "test", "path": "/metadata/resourceVersion", "value": "%s"}, { "op": "add", "path": "/status/memoryDump", "value": %s}]`, vmi.ResourceVersion, string(dump))
+
+	_, err = app.virtCli.VirtualMachineInstance(namespace).Patch(name, types.JSONPatchType, []byte(patch))
+	if err != nil {
+		writeError(errors.NewInternalError(err), response)
+		return
+	}
+
+	response.WriteHeader(http.StatusAccepted)
+}
+
+// validateMemoryDumpClaim makes sure the claim can hold the whole guest memory
+// and the headers of the dump
+func validateMemoryDumpClaim(vmi *v1.VirtualMachineInstance, pvc *v12.PersistentVolumeClaim) error {
+	capacity, ok := pvc.Status.Capacity[v12.ResourceStorage]
+	if !ok {
+		capacity = pvc.Spec.Resources.Requests[v12.ResourceStorage]
+	}
+
+	required := memoryDumpSize(vmi)
+	if capacity.Cmp(required) < 0 {
+		return fmt.Errorf("PersistentVolumeClaim %s is too small for the memory dump, it has %s but %s are required", pvc.Name, capacity.String(), required.String())
+	}
+	return nil
+}
+
+// memoryDumpSize returns the space a memory dump of the guest takes at most
+func memoryDumpSize(vmi *v1.VirtualMachineInstance) resource.Quantity {
+	var size resource.Quantity
+	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
+		size = vmi.Spec.Domain.Memory.Guest.DeepCopy()
+	} else if memory, ok := vmi.Spec.Domain.Resources.Requests[v12.ResourceMemory]; ok {
+		size = memory.DeepCopy()
+	} else {
+		size = vmi.Spec.Domain.Resources.Limits.Memory().DeepCopy()
+	}
+	size.Add(resource.MustParse(memoryDumpOverhead))
+	return size
+}
+
 func (app *SubresourceAPIApp) fetchVirtualMachine(name string, namespace string) (*v1.VirtualMachine, *errors.StatusError) {
 
 	vm, err := app.virtCli.VirtualMachine(namespace).Get(name, &k8smetav1.GetOptions{})
@@ -901,3 +1251,39 @@ func (app *SubresourceAPIApp) FilesystemList(request *restful.Request, response
 
 	response.WriteEntity(filesystemList)
 }
+
+// Stats handles the subresource for providing a sample of the VMI resource usage
+func (app *SubresourceAPIApp) Stats(request *restful.Request, response *restful.Response) {
+	validate := func(vmi *v1.VirtualMachineInstance) *errors.StatusError {
+		if vmi == nil || vmi.Status.Phase != v1.Running {
+			return errors.NewConflict(v1.Resource("virtualmachineinstance"), vmi.Name, fmt.Errorf("VMI is not running"))
+		}
+		return nil
+	}
+	getURL := func(vmi *v1.VirtualMachineInstance, conn kubecli.VirtHandlerConn) (string, error) {
+		return conn.StatsURI(vmi)
+	}
+
+	_, url, conn, err := app.prepareConnection(request, validate, getURL)
+	if err != nil {
+		log.Log.Errorf("Cannot prepare connection %s", err.Error())
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, conErr := conn.Get(url, app.handlerTLSConfiguration)
+	if conErr != nil {
+		log.Log.Errorf("Cannot GET request %s", conErr.Error())
+		response.WriteError(http.StatusInternalServerError, conErr)
+		return
+	}
+
+	vmiStats := v1.VirtualMachineInstanceStats{}
+	if err := json.Unmarshal([]byte(resp), &vmiStats); err != nil {
+		log.Log.Reason(err).Error("error unmarshalling stats response")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response.WriteEntity(vmiStats)
+}
@@ -29,9 +29,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	rt "runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/emicklei/go-restful"
 	. "github.com/onsi/ginkgo"
@@ -41,14 +44,21 @@ import (
 
 	"kubevirt.io/kubevirt/pkg/util/status"
 
+	appsv1 "k8s.io/api/apps/v1"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 )
 
 const vmPathFormat = "/apis/kubevirt.io/%s/namespaces/%s/virtualmachines/%s"
@@ -117,7 +127,7 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		)
 	}
 
-	expectVMI := func(running, paused bool) {
+	expectVMIWithConditions := func(running bool, conditions ...v1.VirtualMachineInstanceConditionType) {
 		request.PathParameters()["name"] = "testvmi"
 		request.PathParameters()["namespace"] = "default"
 
@@ -136,13 +146,11 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 			},
 		}
 
-		if paused {
-			vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
-				{
-					Type:   v1.VirtualMachineInstancePaused,
-					Status: k8sv1.ConditionTrue,
-				},
-			}
+		for _, condition := range conditions {
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:   condition,
+				Status: k8sv1.ConditionTrue,
+			})
 		}
 
 		server.AppendHandlers(
@@ -155,6 +163,14 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		expectHandlerPod()
 	}
 
+	expectVMI := func(running, paused bool) {
+		if paused {
+			expectVMIWithConditions(running, v1.VirtualMachineInstancePaused)
+		} else {
+			expectVMIWithConditions(running)
+		}
+	}
+
 	getVMPath := func(version, namespace, vmName string) string {
 		return fmt.Sprintf(vmPathFormat, version, namespace, vmName)
 	}
@@ -292,6 +308,203 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 			close(done)
 		}, 5)
 
+		Context("VNC tokens", func() {
+			BeforeEach(func() {
+				app.vncTokens = newFakeVNCTokens()
+				request.Request.URL = &url.URL{}
+				request.Request.Header = http.Header{userHeader: []string{"user"}}
+				request.PathParameters()["name"] = "testvmi"
+				request.PathParameters()["namespace"] = "default"
+			})
+
+			It("should issue a token for the VNC of the VMI", func(done Done) {
+				request.Request.URL.RawQuery = VNCTokenDurationParam + "=45s"
+				response.SetRequestAccepts(restful.MIME_JSON)
+				expectVMI(true, false)
+
+				app.VNCTokenRequestHandler(request, response)
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+
+				token := v1.VNCToken{}
+				Expect(json.Unmarshal(recorder.Body.Bytes(), &token)).To(Succeed())
+				Expect(app.vncTokens.Validate(token.Token, "default", "testvmi", request.Request.Header)).To(Succeed())
+				Expect(token.ExpirationTimestamp.Time).To(BeTemporally("~", time.Now().Add(45*time.Second), 2*time.Second))
+				close(done)
+			}, 5)
+
+			It("should not issue a token without a user", func(done Done) {
+				request.Request.Header = http.Header{}
+				expectVMI(true, false)
+
+				app.VNCTokenRequestHandler(request, response)
+				ExpectStatusErrorWithCode(recorder, http.StatusUnauthorized)
+				close(done)
+			}, 5)
+
+			It("should reject a token for a VMI which was recreated", func(done Done) {
+				vmi := newTokenVMI("testvmi")
+				token, _, err := app.vncTokens.Issue(vmi, "user", time.Minute)
+				Expect(err).ToNot(HaveOccurred())
+				request.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+				expectVMI(true, false)
+
+				app.VNCRequestHandler(request, response)
+				ExpectStatusErrorWithCode(recorder, http.StatusUnauthorized)
+				close(done)
+			}, 5)
+
+			It("should reject a token which was used already", func(done Done) {
+				vmi := newTokenVMI("testvmi")
+				vmi.UID = ""
+				token, _, err := app.vncTokens.Issue(vmi, "user", time.Minute)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(app.vncTokens.Consume(token, vmi)).To(Succeed())
+				request.Request.URL.RawQuery = url.Values{VNCTokenParam: []string{token}}.Encode()
+				expectVMI(true, false)
+
+				app.VNCRequestHandler(request, response)
+				ExpectStatusErrorWithCode(recorder, http.StatusUnauthorized)
+				close(done)
+			}, 5)
+
+			table.DescribeTable("should reject an invalid duration", func(duration string) {
+				request.Request.URL.RawQuery = VNCTokenDurationParam + "=" + duration
+
+				app.VNCTokenRequestHandler(request, response)
+				ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			},
+				table.Entry("which can not be parsed", "forever"),
+				table.Entry("which is negative", "-1m"),
+				table.Entry("which exceeds the maximum", "5m"),
+			)
+
+			It("should fail if the VMI has no graphics device", func(done Done) {
+				flag := false
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Spec.Domain.Devices.AutoattachGraphicsDevice = &flag
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+					),
+				)
+
+				app.VNCTokenRequestHandler(request, response)
+				ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+				close(done)
+			}, 5)
+		})
+
+		It("should fail to forward an invalid port", func(done Done) {
+
+			request.PathParameters()["name"] = "testvmi"
+			request.PathParameters()["namespace"] = "default"
+			request.PathParameters()["port"] = "70000"
+
+			app.PortForwardRequestHandler(request, response)
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			close(done)
+		}, 5)
+
+		It("should fail to forward a port which is not declared on the masquerade interface", func(done Done) {
+
+			request.PathParameters()["name"] = "testvmi"
+			request.PathParameters()["namespace"] = "default"
+			request.PathParameters()["port"] = "22"
+
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Status.Phase = v1.Running
+			vmi.ObjectMeta.SetUID(uuid.NewUUID())
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}
+			vmi.Spec.Domain.Devices.Interfaces[0].Ports = []v1.Port{{Port: 80}}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+			)
+			app.PortForwardRequestHandler(request, response)
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			close(done)
+		}, 5)
+
+		table.DescribeTable("should validate the forwarded port", func(iface *v1.Interface, port int, expectedErr string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*iface}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+
+			err := validatePortForwardPort(vmi, port)
+			if expectedErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+			table.Entry("with a declared TCP port",
+				&v1.Interface{Name: "default", InterfaceBindingMethod: v1.InterfaceBindingMethod{Masquerade: &v1.InterfaceMasquerade{}}, Ports: []v1.Port{{Port: 22, Protocol: "TCP"}}},
+				22, ""),
+			table.Entry("with a declared port without protocol",
+				&v1.Interface{Name: "default", InterfaceBindingMethod: v1.InterfaceBindingMethod{Masquerade: &v1.InterfaceMasquerade{}}, Ports: []v1.Port{{Port: 22}}},
+				22, ""),
+			table.Entry("with a declared UDP port",
+				&v1.Interface{Name: "default", InterfaceBindingMethod: v1.InterfaceBindingMethod{Masquerade: &v1.InterfaceMasquerade{}}, Ports: []v1.Port{{Port: 53, Protocol: "UDP"}}},
+				53, "port 53 is not declared as a TCP port of interface default"),
+			table.Entry("with a bridge interface",
+				&v1.Interface{Name: "default", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				22, "port forwarding requires an interface with the masquerade binding on the pod network"),
+		)
+
+		It("should fail to redirect USB devices if the VMI has no client passthrough devices", func(done Done) {
+
+			request.PathParameters()["name"] = "testvmi"
+			request.PathParameters()["namespace"] = "default"
+
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Status.Phase = v1.Running
+			vmi.ObjectMeta.SetUID(uuid.NewUUID())
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+			)
+			app.USBRedirRequestHandler(request, response)
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			close(done)
+		}, 5)
+
+		It("should fail to connect to the backup if no backup of the VMI is ready", func(done Done) {
+
+			request.PathParameters()["name"] = "testvmi"
+			request.PathParameters()["namespace"] = "default"
+
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Status.Phase = v1.Running
+			vmi.ObjectMeta.SetUID(uuid.NewUUID())
+
+			backup := kubecli.NewMinimalVirtualMachineBackup("testbackup")
+			backup.Spec.Source = "testvmi"
+			backup.Status.Phase = v1.VirtualMachineBackupPending
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachinebackups"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, kubecli.NewVirtualMachineBackupList(*backup)),
+				),
+			)
+			app.BackupRequestHandler(request, response)
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			close(done)
+		}, 5)
+
 		It("should fail with no serial console at console connections", func(done Done) {
 
 			request.PathParameters()["name"] = "testvmi"
@@ -809,6 +1022,336 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		})
 	})
 
+	Context("Subresource api - expand-spec", func() {
+		const instancetypePath = "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstancetypes/small"
+
+		var vm *v1.VirtualMachine
+		var configMapInformer cache.SharedIndexInformer
+
+		newVMBody := func(vm *v1.VirtualMachine) io.ReadCloser {
+			vmJson, _ := json.Marshal(vm)
+			return &readCloserWrapper{bytes.NewReader(vmJson)}
+		}
+
+		expectInstancetype := func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", instancetypePath),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &v1.VirtualMachineInstancetype{
+						ObjectMeta: k8smetav1.ObjectMeta{Name: "small", Namespace: k8sv1.NamespaceDefault},
+						Spec: v1.VirtualMachineInstancetypeSpec{
+							CPU:    v1.CPUInstancetype{Guest: 2},
+							Memory: v1.MemoryInstancetype{Guest: resource.MustParse("128Mi")},
+						},
+					}),
+				),
+			)
+		}
+
+		BeforeEach(func() {
+			app.clusterConfig, configMapInformer, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.InstancetypeGate},
+			})
+
+			vm = newMinimalVM("testvm")
+			vm.Spec.Instancetype = &v1.InstancetypeMatcher{Name: "small"}
+			vm.Spec.Template = &v1.VirtualMachineInstanceTemplateSpec{}
+
+			request.PathParameters()["namespace"] = k8sv1.NamespaceDefault
+		})
+
+		It("should return the VirtualMachine with the instancetype and defaults applied", func() {
+			expectInstancetype()
+			request.Request.Body = newVMBody(vm)
+
+			app.ExpandSpecRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+			expanded := &v1.VirtualMachine{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), expanded)).To(Succeed())
+			Expect(expanded.Spec.Instancetype).To(BeNil())
+			Expect(expanded.Spec.Template.Spec.Domain.CPU.Sockets).To(Equal(uint32(2)))
+			Expect(expanded.Spec.Template.Spec.Domain.Memory.Guest.String()).To(Equal("128Mi"))
+			Expect(expanded.Spec.Template.Spec.Domain.Machine.Type).To(Equal(virtconfig.DefaultMachineType))
+		})
+
+		It("should report fields conflicting with the instancetype", func() {
+			expectInstancetype()
+			vm.Spec.Template.Spec.Domain.CPU = &v1.CPU{Cores: 4}
+			request.Request.Body = newVMBody(vm)
+
+			app.ExpandSpecRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusUnprocessableEntity)
+			Expect(status.ErrStatus.Details.Causes).To(HaveLen(1))
+			Expect(status.ErrStatus.Details.Causes[0].Field).To(Equal("spec.template.spec.domain.cpu"))
+		})
+
+		It("should fail if the instancetype does not exist", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", instancetypePath),
+					ghttp.RespondWithJSONEncoded(http.StatusNotFound, errors.NewNotFound(v1.Resource("virtualmachineinstancetypes"), "small")),
+				),
+			)
+			request.Request.Body = newVMBody(vm)
+
+			app.ExpandSpecRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusNotFound)
+		})
+
+		It("should fail if the feature gate is not enabled", func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{})
+			request.Request.Body = newVMBody(vm)
+
+			app.ExpandSpecRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			Expect(status.Error()).To(ContainSubstring("feature gate"))
+		})
+
+		It("should fail if the namespace of the VirtualMachine does not match", func() {
+			vm.Namespace = "other"
+			request.Request.Body = newVMBody(vm)
+
+			app.ExpandSpecRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+		})
+
+		It("should expand an existing VirtualMachine", func() {
+			vm.Namespace = k8sv1.NamespaceDefault
+			request.PathParameters()["name"] = vm.Name
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", getVMPath(v1.GroupVersion.Version, k8sv1.NamespaceDefault, vm.Name)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vm),
+				),
+			)
+			expectInstancetype()
+
+			app.ExpandVMSpecRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+			expanded := &v1.VirtualMachine{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), expanded)).To(Succeed())
+			Expect(expanded.Spec.Template.Spec.Domain.CPU.Sockets).To(Equal(uint32(2)))
+		})
+	})
+
+	Context("Subresource api - rollback", func() {
+		const revisionPath = "/apis/apps/v1/namespaces/default/controllerrevisions/testvm-template-1"
+
+		var vm *v1.VirtualMachine
+		var revision *appsv1.ControllerRevision
+
+		newRollbackBody := func(opts *v1.RollbackOptions) io.ReadCloser {
+			optsJson, _ := json.Marshal(opts)
+			return &readCloserWrapper{bytes.NewReader(optsJson)}
+		}
+
+		expectVMAndRevision := func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", getVMPath(v1.GroupVersion.Version, k8sv1.NamespaceDefault, vm.Name)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vm),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", revisionPath),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, revision),
+				),
+			)
+		}
+
+		BeforeEach(func() {
+			vm = newMinimalVM("testvm")
+			vm.Namespace = k8sv1.NamespaceDefault
+			vm.UID = "vm-uid"
+			vm.ResourceVersion = "2"
+			vm.Spec.Template = &v1.VirtualMachineInstanceTemplateSpec{}
+
+			template, err := json.Marshal(&v1.VirtualMachineInstanceTemplateSpec{
+				Spec: v1.VirtualMachineInstanceSpec{Hostname: "previous"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			revision = &appsv1.ControllerRevision{
+				ObjectMeta: k8smetav1.ObjectMeta{
+					Name:      "testvm-template-1",
+					Namespace: k8sv1.NamespaceDefault,
+					Labels:    map[string]string{v1.VirtualMachineTemplateRevisionLabel: "vm-uid"},
+				},
+				Data:     runtime.RawExtension{Raw: template},
+				Revision: 1,
+			}
+
+			request.PathParameters()["name"] = vm.Name
+			request.PathParameters()["namespace"] = k8sv1.NamespaceDefault
+		})
+
+		It("should restore the template of the revision and annotate the VirtualMachine", func() {
+			expectVMAndRevision()
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", getVMPath(v1.GroupVersion.Version, k8sv1.NamespaceDefault, vm.Name)),
+					func(w http.ResponseWriter, r *http.Request) {
+						body, err := ioutil.ReadAll(r.Body)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(body)).To(ContainSubstring(`{ "op": "test", "path": "/metadata/resourceVersion", "value": "2" }`))
+						Expect(string(body)).To(ContainSubstring(`"hostname":"previous"`))
+						Expect(string(body)).To(ContainSubstring(`{ "op": "add", "path": "/metadata/annotations", "value": { "kubevirt.io/rollback-revision": "testvm-template-1" } }`))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vm),
+				),
+			)
+			request.Request.Body = newRollbackBody(&v1.RollbackOptions{RevisionName: "testvm-template-1"})
+
+			app.RollbackVMRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusAccepted))
+		})
+
+		It("should fail without a revision name", func() {
+			request.Request.Body = newRollbackBody(&v1.RollbackOptions{})
+
+			app.RollbackVMRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+		})
+
+		It("should fail if the revision does not belong to the VirtualMachine", func() {
+			revision.Labels[v1.VirtualMachineTemplateRevisionLabel] = "other-uid"
+			expectVMAndRevision()
+			request.Request.Body = newRollbackBody(&v1.RollbackOptions{RevisionName: "testvm-template-1"})
+
+			app.RollbackVMRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			Expect(status.Error()).To(ContainSubstring("is not a template revision"))
+		})
+
+		It("should pass on the rejection of the rollback", func() {
+			expectVMAndRevision()
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", getVMPath(v1.GroupVersion.Version, k8sv1.NamespaceDefault, vm.Name)),
+					ghttp.RespondWithJSONEncoded(http.StatusUnprocessableEntity, errors.NewInvalid(v1.VirtualMachineGroupVersionKind.GroupKind(), vm.Name, nil)),
+				),
+			)
+			request.Request.Body = newRollbackBody(&v1.RollbackOptions{RevisionName: "testvm-template-1"})
+
+			app.RollbackVMRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusUnprocessableEntity)
+		})
+	})
+
+	Context("Subresource api with memory dump", func() {
+		const vmiPath = "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvmi"
+		const pvcPath = "/api/v1/namespaces/default/persistentvolumeclaims/dump"
+
+		var vmi *v1.VirtualMachineInstance
+
+		newMemoryDumpBody := func(claimName string) io.ReadCloser {
+			body, err := json.Marshal(&v1.MemoryDumpOptions{ClaimName: claimName})
+			Expect(err).ToNot(HaveOccurred())
+			return &readCloserWrapper{bytes.NewReader(body)}
+		}
+
+		expectGetVMI := func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", vmiPath),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+			)
+		}
+
+		expectGetPVC := func(size string) {
+			pvc := &k8sv1.PersistentVolumeClaim{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "dump", Namespace: "default"},
+				Status: k8sv1.PersistentVolumeClaimStatus{
+					Capacity: k8sv1.ResourceList{k8sv1.ResourceStorage: resource.MustParse(size)},
+				},
+			}
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", pvcPath),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pvc),
+				),
+			)
+		}
+
+		BeforeEach(func() {
+			request.PathParameters()["name"] = "testvmi"
+			request.PathParameters()["namespace"] = "default"
+
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{k8sv1.ResourceMemory: resource.MustParse("1Gi")}
+		})
+
+		It("should fail if no claim is provided", func() {
+			request.Request.Body = newMemoryDumpBody("")
+
+			app.MemoryDumpVMIRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			Expect(status.Error()).To(ContainSubstring("provide a claim"))
+		})
+
+		It("should fail if the VMI is not running", func() {
+			request.Request.Body = newMemoryDumpBody("dump")
+			vmi.Status.Phase = v1.Scheduled
+			expectGetVMI()
+
+			app.MemoryDumpVMIRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusConflict)
+		})
+
+		It("should fail if a memory dump is already in progress", func() {
+			request.Request.Body = newMemoryDumpBody("dump")
+			vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{ClaimName: "other", Phase: v1.MemoryDumpInProgress}
+			expectGetVMI()
+
+			app.MemoryDumpVMIRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusConflict)
+			Expect(status.Error()).To(ContainSubstring("already in progress"))
+		})
+
+		It("should fail if the claim is too small for the guest memory", func() {
+			request.Request.Body = newMemoryDumpBody("dump")
+			expectGetVMI()
+			expectGetPVC("1Gi")
+
+			app.MemoryDumpVMIRequestHandler(request, response)
+
+			status := ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			Expect(status.Error()).To(ContainSubstring("too small"))
+		})
+
+		It("should request the memory dump", func() {
+			request.Request.Body = newMemoryDumpBody("dump")
+			vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{ClaimName: "dump", Phase: v1.MemoryDumpCompleted}
+			expectGetVMI()
+			expectGetPVC("2Gi")
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", vmiPath),
+					ghttp.VerifyBody([]byte(`[{ "op": "test", "path": "/metadata/resourceVersion", "value": "1"}, { "op": "add", "path": "/status/memoryDump", "value": {"claimName":"dump","phase":"Pending"}}]`)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+			)
+
+			app.MemoryDumpVMIRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusAccepted))
+		})
+	})
+
 	Context("Subresource api - error handling for RestartVMRequestHandler", func() {
 		BeforeEach(func() {
 			request.PathParameters()["name"] = "testvm"
@@ -1124,6 +1667,58 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 			table.Entry("RerunOnFailure", v1.RunStrategyRerunOnFailure),
 			table.Entry("Manual", v1.RunStrategyManual),
 		)
+
+		It("should force stop VirtualMachine", func() {
+			bytesRepresentation, _ := json.Marshal(&v1.StopOptions{GracePeriodSeconds: &[]int64{0}[0]})
+			request.Request.Body = ioutil.NopCloser(bytes.NewReader(bytesRepresentation))
+
+			vm := newVirtualMachineWithRunStrategy(v1.RunStrategyAlways)
+			vmi := newVirtualMachineInstanceInPhase(v1.Running)
+
+			pod := k8sv1.Pod{}
+			pod.ObjectMeta.Name = "virt-launcher-testvm"
+			pod.Labels = map[string]string{v1.AppLabel: "virt-launcher", v1.CreatedByLabel: string(vmi.UID)}
+			pod.Status.Phase = k8sv1.PodRunning
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachines/testvm"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vm),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvm"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vmi),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachines/testvm"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, vm),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/namespaces/default/pods"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, k8sv1.PodList{Items: []k8sv1.Pod{pod}}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/api/v1/namespaces/default/pods/virt-launcher-testvm"),
+					ghttp.VerifyJSON(`{"kind":"DeleteOptions","apiVersion":"v1","gracePeriodSeconds":0}`),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pod),
+				),
+			)
+
+			app.StopVMRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusAccepted))
+			Expect(server.ReceivedRequests()).To(HaveLen(5))
+		})
+
+		It("should only support a grace period of 0", func() {
+			bytesRepresentation, _ := json.Marshal(&v1.StopOptions{GracePeriodSeconds: &[]int64{30}[0]})
+			request.Request.Body = ioutil.NopCloser(bytes.NewReader(bytesRepresentation))
+
+			app.StopVMRequestHandler(request, response)
+
+			statusErr := ExpectStatusErrorWithCode(recorder, http.StatusBadRequest)
+			Expect(statusErr.Error()).To(ContainSubstring("For force stop, only gracePeriod=0 is supported for now"))
+		})
 	})
 
 	Context("Subresource api - MigrateVMRequestHandler", func() {
@@ -1251,6 +1846,7 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 			table.Entry("for GuestOSInfo", app.GuestOSInfo),
 			table.Entry("for UserList", app.UserList),
 			table.Entry("for Filesystem", app.FilesystemList),
+			table.Entry("for Stats", app.Stats),
 		)
 
 		table.DescribeTable("should fail when the VMI is not running", func(fn subRes) {
@@ -1275,6 +1871,7 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 			table.Entry("for GuestOSInfo", app.GuestOSInfo),
 			table.Entry("for UserList", app.UserList),
 			table.Entry("for FilesystemList", app.FilesystemList),
+			table.Entry("for Stats", app.Stats),
 		)
 
 		table.DescribeTable("should fail when VMI does not have agent connected", func(fn subRes) {
@@ -1307,6 +1904,78 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		)
 	})
 
+	Context("Subresource api - capabilities", func() {
+		It("should return the feature gates, machine types and configuration of the cluster", func() {
+			app.clusterConfig, _, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+				Data: map[string]string{
+					virtconfig.FeatureGatesKey:     virtconfig.SnapshotGate + "," + virtconfig.InstancetypeGate,
+					virtconfig.EmulatedMachinesKey: "q35*",
+				},
+			})
+			response.SetRequestAccepts(restful.MIME_JSON)
+
+			app.CapabilitiesRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+			capabilities := &v1.ClusterCapabilities{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), capabilities)).To(Succeed())
+			Expect(capabilities.FeatureGates).To(ConsistOf(virtconfig.SnapshotGate, virtconfig.InstancetypeGate))
+			Expect(capabilities.MachineType).To(Equal(virtconfig.DefaultMachineType))
+			Expect(capabilities.EmulatedMachines).To(ConsistOf("q35*"))
+			Expect(capabilities.Architecture).To(Equal(rt.GOARCH))
+			Expect(capabilities.Configuration.EmulatedMachines).To(ConsistOf("q35*"))
+		})
+	})
+
+	Context("Subresource api - launcher-resources", func() {
+		newLauncherResourcesRequest := func(vmi *v1.VirtualMachineInstance) {
+			body, err := json.Marshal(vmi)
+			Expect(err).ToNot(HaveOccurred())
+			request.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+			response.SetRequestAccepts(restful.MIME_JSON)
+		}
+
+		It("should return the resources of the virt-launcher pod", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("1Gi"),
+			}
+			newLauncherResourcesRequest(vmi)
+
+			app.LauncherResourcesRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+			resources := &v1.LauncherResources{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), resources)).To(Succeed())
+
+			expected, err := services.CalculateLauncherResources(vmi)
+			Expect(err).ToNot(HaveOccurred())
+			memory := resources.Requests[k8sv1.ResourceMemory]
+			Expect(memory.Cmp(expected.Requests[k8sv1.ResourceMemory])).To(BeZero())
+			overhead := resources.Overhead[k8sv1.ResourceMemory]
+			Expect(overhead.Cmp(expected.Overhead[k8sv1.ResourceMemory])).To(BeZero())
+			Expect(overhead.Sign()).To(Equal(1))
+		})
+
+		It("should fail if the vmi requests no memory", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests = nil
+			newLauncherResourcesRequest(vmi)
+
+			app.LauncherResourcesRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should fail without a vmi in the request body", func() {
+			request.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte("not a vmi")))
+
+			app.LauncherResourcesRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusBadRequest))
+		})
+	})
+
 	Context("StateChange JSON", func() {
 		It("should create a stop request if status exists", func() {
 			uid := uuid.NewUUID()
@@ -1524,6 +2193,57 @@ var _ = Describe("VirtualMachineInstance Subresources", func() {
 		})
 	})
 
+	Context("Freezing", func() {
+		It("Should freeze a running VMI with a connected agent", func() {
+			backend.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/v1/namespaces/default/virtualmachineinstances/testvmi/freeze"),
+					ghttp.RespondWith(http.StatusOK, ""),
+				),
+			)
+			expectVMIWithConditions(true, v1.VirtualMachineInstanceAgentConnected)
+
+			app.FreezeVMIRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+		})
+
+		table.DescribeTable("Should fail freezing", func(running bool, conditions ...v1.VirtualMachineInstanceConditionType) {
+			expectVMIWithConditions(running, conditions...)
+
+			app.FreezeVMIRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusConflict)
+		},
+			table.Entry("a not running VMI", false, v1.VirtualMachineInstanceAgentConnected),
+			table.Entry("a VMI without a connected agent", true),
+			table.Entry("a paused VMI", true, v1.VirtualMachineInstanceAgentConnected, v1.VirtualMachineInstancePaused),
+			table.Entry("an already frozen VMI", true, v1.VirtualMachineInstanceAgentConnected, v1.VirtualMachineInstanceFrozen),
+		)
+
+		It("Should fail thawing a VMI which is not frozen", func() {
+			expectVMIWithConditions(true, v1.VirtualMachineInstanceAgentConnected)
+
+			app.ThawVMIRequestHandler(request, response)
+
+			ExpectStatusErrorWithCode(recorder, http.StatusConflict)
+		})
+
+		It("Should thaw a frozen VMI", func() {
+			backend.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/v1/namespaces/default/virtualmachineinstances/testvmi/thaw"),
+					ghttp.RespondWith(http.StatusOK, ""),
+				),
+			)
+			expectVMIWithConditions(true, v1.VirtualMachineInstanceAgentConnected, v1.VirtualMachineInstanceFrozen)
+
+			app.ThawVMIRequestHandler(request, response)
+
+			Expect(response.StatusCode()).To(Equal(http.StatusOK))
+		})
+	})
+
 	AfterEach(func() {
 		server.Close()
 		backend.Close()
@@ -0,0 +1,226 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+const (
+	// VNCTokenParam is the query parameter of the vnc subresource carrying a token
+	VNCTokenParam = "token"
+
+	// VNCTokenDurationParam is the query parameter of the vnc-token subresource for the lifetime of the token
+	VNCTokenDurationParam = "duration"
+
+	// The lifetime of the tokens is short, as each virt-api replica only knows the tokens it consumed
+	DefaultVNCTokenDuration = 20 * time.Second
+	MaxVNCTokenDuration     = 1 * time.Minute
+)
+
+// vncTokenClaims is the signed content of a token
+type vncTokenClaims struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	User      string    `json:"user"`
+	Expires   int64     `json:"expires"`
+}
+
+// VNCTokens issues and validates short-lived tokens, which grant a single connection to the
+// VNC of a VMI to the user who requested them. A token is bound to that user and to the UID of
+// the VMI. The tokens are signed with a dedicated key, which all virt-api replicas share.
+//
+// The replicas don't share which tokens were used, so that a token can open one connection
+// on each replica until it expires. The short lifetime of the tokens bounds this.
+type VNCTokens struct {
+	getKey      func() ([]byte, error)
+	userHeaders func() []string
+	now         func() time.Time
+
+	// the IDs of the tokens which were used already on this replica, with their expiration
+	consumed     map[string]int64
+	consumedLock sync.Mutex
+}
+
+func NewVNCTokens(getKey func() ([]byte, error), userHeaders func() []string) *VNCTokens {
+	return &VNCTokens{
+		getKey:      getKey,
+		userHeaders: userHeaders,
+		now:         time.Now,
+		consumed:    map[string]int64{},
+	}
+}
+
+// VNCTokenKeyFromFile returns the signing key of the tokens stored in the file. The file is read
+// whenever the key is needed, so that an update of the mounted secret applies without a restart.
+func VNCTokenKeyFromFile(path string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return ioutil.ReadFile(path)
+	}
+}
+
+// userName returns the user the aggregator authenticated the request for, if any
+func (t *VNCTokens) userName(header http.Header) string {
+	for _, key := range t.userHeaders() {
+		if user, ok := header[key]; ok && len(user) > 0 {
+			return user[0]
+		}
+	}
+	return ""
+}
+
+// Issue returns a token for the VNC of the VMI, which the user can use once until it
+// expires after the given duration
+func (t *VNCTokens) Issue(vmi *v1.VirtualMachineInstance, user string, duration time.Duration) (string, time.Time, error) {
+	if user == "" {
+		return "", time.Time{}, fmt.Errorf("the user requesting the token is unknown")
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", time.Time{}, err
+	}
+	expires := t.now().Add(duration).Truncate(time.Second)
+	claims, err := json.Marshal(vncTokenClaims{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Namespace: vmi.Namespace,
+		Name:      vmi.Name,
+		UID:       vmi.UID,
+		User:      user,
+		Expires:   expires.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	signature, err := t.sign(payload)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature), expires, nil
+}
+
+// Validate checks that the token was issued for the VNC of the VMI, did not expire and was not
+// used yet. The request must be authenticated for the user who requested the token, so the
+// headers must only be passed once the request was authenticated.
+func (t *VNCTokens) Validate(token, namespace, name string, header http.Header) error {
+	claims, err := t.validate(token, namespace, name)
+	if err != nil {
+		return err
+	}
+	if user := t.userName(header); user == "" || user != claims.User {
+		return fmt.Errorf("token was issued for another user")
+	}
+	return nil
+}
+
+// Consume validates the token for the VMI which is connected to and marks it as used, so that
+// it can not open another connection
+func (t *VNCTokens) Consume(token string, vmi *v1.VirtualMachineInstance) error {
+	claims, err := t.validate(token, vmi.Namespace, vmi.Name)
+	if err != nil {
+		return err
+	}
+	if claims.UID != vmi.UID {
+		return fmt.Errorf("token was issued for another VMI")
+	}
+
+	t.consumedLock.Lock()
+	defer t.consumedLock.Unlock()
+	now := t.now().Unix()
+	for id, expires := range t.consumed {
+		if now >= expires {
+			delete(t.consumed, id)
+		}
+	}
+	if _, used := t.consumed[claims.ID]; used {
+		return fmt.Errorf("token was used already")
+	}
+	t.consumed[claims.ID] = claims.Expires
+	return nil
+}
+
+func (t *VNCTokens) validate(token, namespace, name string) (*vncTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	expectedSignature, err := t.sign(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, expectedSignature) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	claims := &vncTokenClaims{}
+	if err := json.Unmarshal(rawClaims, claims); err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	if claims.Namespace != namespace || claims.Name != name {
+		return nil, fmt.Errorf("token was issued for another VMI")
+	}
+	if t.now().Unix() >= claims.Expires {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	t.consumedLock.Lock()
+	_, used := t.consumed[claims.ID]
+	t.consumedLock.Unlock()
+	if used {
+		return nil, fmt.Errorf("token was used already")
+	}
+	return claims, nil
+}
+
+func (t *VNCTokens) sign(payload string) ([]byte, error) {
+	key, err := t.getKey()
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("no signing key available")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,176 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package rest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+func newFakeVNCTokens() *VNCTokens {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	Expect(err).ToNot(HaveOccurred())
+	return NewVNCTokens(func() ([]byte, error) { return key, nil }, func() []string { return []string{userHeader} })
+}
+
+func newTokenVMI(name string) *v1.VirtualMachineInstance {
+	vmi := v1.NewMinimalVMI(name)
+	vmi.Namespace = "default"
+	vmi.UID = types.UID(name + "-uid")
+	return vmi
+}
+
+var _ = Describe("VNC tokens", func() {
+
+	var tokens *VNCTokens
+	var now time.Time
+	var vmi *v1.VirtualMachineInstance
+	var userHeaders http.Header
+
+	BeforeEach(func() {
+		tokens = newFakeVNCTokens()
+		now = time.Unix(1600000000, 0)
+		tokens.now = func() time.Time { return now }
+		vmi = newTokenVMI("testvmi")
+		userHeaders = http.Header{userHeader: []string{"alice"}}
+	})
+
+	It("should accept a token for the VMI it was issued for", func() {
+		token, expires, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expires).To(Equal(now.Add(time.Minute)))
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(Succeed())
+	})
+
+	It("should reject a token for another VMI", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Validate(token, "default", "othervmi", userHeaders)).To(MatchError("token was issued for another VMI"))
+		Expect(tokens.Validate(token, "other", "testvmi", userHeaders)).To(MatchError("token was issued for another VMI"))
+	})
+
+	It("should reject a token of another user", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Validate(token, "default", "testvmi", http.Header{userHeader: []string{"bob"}})).To(MatchError("token was issued for another user"))
+	})
+
+	It("should reject a token without a known user", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Validate(token, "default", "testvmi", http.Header{})).To(MatchError("token was issued for another user"))
+	})
+
+	It("should reject an expired token", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		now = now.Add(time.Minute)
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(MatchError("token expired"))
+	})
+
+	It("should reject a token signed with another key", func() {
+		token, _, err := newFakeVNCTokens().Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(MatchError("invalid token signature"))
+	})
+
+	It("should reject a token with modified claims", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		otherToken, _, err := tokens.Issue(newTokenVMI("othervmi"), "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+
+		// combine the claims of one token with the signature of the other
+		tampered := otherToken[:strings.Index(otherToken, ".")] + token[strings.Index(token, "."):]
+		Expect(tokens.Validate(tampered, "default", "othervmi", userHeaders)).To(MatchError("invalid token signature"))
+	})
+
+	It("should reject a malformed token", func() {
+		Expect(tokens.Validate("garbage", "default", "testvmi", userHeaders)).To(MatchError("malformed token"))
+	})
+
+	It("should fail without a signing key", func() {
+		tokens = NewVNCTokens(func() ([]byte, error) { return nil, fmt.Errorf("not mounted") }, func() []string { return nil })
+		_, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).To(MatchError("no signing key available"))
+	})
+
+	It("should sign with the key of the file", func() {
+		dir, err := ioutil.TempDir("", "vnc-token-key")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		keyFile := filepath.Join(dir, "key")
+		Expect(ioutil.WriteFile(keyFile, []byte("first-key"), 0600)).To(Succeed())
+
+		tokens = NewVNCTokens(VNCTokenKeyFromFile(keyFile), func() []string { return []string{userHeader} })
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(Succeed())
+
+		// the key is read again, so that a rotated key applies
+		Expect(ioutil.WriteFile(keyFile, []byte("second-key"), 0600)).To(Succeed())
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(MatchError("invalid token signature"))
+	})
+
+	It("should not issue tokens without a user", func() {
+		_, _, err := tokens.Issue(vmi, "", time.Minute)
+		Expect(err).To(MatchError("the user requesting the token is unknown"))
+	})
+
+	It("should accept a token only once", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Consume(token, vmi)).To(Succeed())
+		Expect(tokens.Consume(token, vmi)).To(MatchError("token was used already"))
+		Expect(tokens.Validate(token, "default", "testvmi", userHeaders)).To(MatchError("token was used already"))
+	})
+
+	It("should reject a token for a VMI which was recreated", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		vmi.UID = "recreated-uid"
+		Expect(tokens.Consume(token, vmi)).To(MatchError("token was issued for another VMI"))
+	})
+
+	It("should forget used tokens once they expired", func() {
+		token, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Consume(token, vmi)).To(Succeed())
+
+		now = now.Add(time.Minute)
+		otherToken, _, err := tokens.Issue(vmi, "alice", time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.Consume(otherToken, vmi)).To(Succeed())
+		Expect(tokens.consumed).To(HaveLen(1))
+	})
+})
@@ -20,11 +20,16 @@ package mutators
 
 import (
 	"encoding/json"
+	"math"
+	"strconv"
 
 	"k8s.io/api/admission/v1beta1"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
@@ -64,7 +69,7 @@ func (mutator *VMsMutator) Mutate(ar *v1beta1.AdmissionReview) *v1beta1.Admissio
 
 	// Set VM defaults
 	log.Log.Object(&vm).V(4).Info("Apply defaults")
-	mutator.setDefaultMachineType(&vm)
+	mutator.SetDefaults(&vm)
 
 	var patch []patchOperation
 	var value interface{}
@@ -96,6 +101,12 @@ func (mutator *VMsMutator) Mutate(ar *v1beta1.AdmissionReview) *v1beta1.Admissio
 	}
 }
 
+// SetDefaults applies the defaults which are set on the admission of a VM
+func (mutator *VMsMutator) SetDefaults(vm *v1.VirtualMachine) {
+	mutator.setDefaultMachineType(vm)
+	mutator.setDataVolumeFilesystemOverhead(vm)
+}
+
 func (mutator *VMsMutator) setDefaultMachineType(vm *v1.VirtualMachine) {
 	if vm.Spec.Template == nil {
 		// nothing to do, let's the validating webhook fail later
@@ -105,3 +116,62 @@ func (mutator *VMsMutator) setDefaultMachineType(vm *v1.VirtualMachine) {
 		vm.Spec.Template.Spec.Domain.Machine.Type = mutator.ClusterConfig.GetMachineType()
 	}
 }
+
+// setDataVolumeFilesystemOverhead increases the storage requested for the DataVolume templates on filesystem
+// volumes by the overhead of their filesystem, so that a disk image of the requested size fits in the volume.
+// The requested size and the overhead are kept in annotations, the size is not increased again on updates.
+func (mutator *VMsMutator) setDataVolumeFilesystemOverhead(vm *v1.VirtualMachine) {
+	for i := range vm.Spec.DataVolumeTemplates {
+		dataVolume := &vm.Spec.DataVolumeTemplates[i]
+		pvc := dataVolume.Spec.PVC
+		if pvc == nil || (pvc.VolumeMode != nil && *pvc.VolumeMode == k8sv1.PersistentVolumeBlock) {
+			continue
+		}
+		size, exists := pvc.Resources.Requests[k8sv1.ResourceStorage]
+		if !exists {
+			continue
+		}
+
+		requested := requestedStorageSize(dataVolume, size)
+		overhead := mutator.ClusterConfig.GetFilesystemOverhead(pvc.StorageClassName)
+		if overhead == 0 {
+			pvc.Resources.Requests[k8sv1.ResourceStorage] = requested
+			delete(dataVolume.Annotations, v1.RequestedStorageSizeAnnotation)
+			delete(dataVolume.Annotations, v1.FilesystemOverheadAnnotation)
+			continue
+		}
+
+		pvc.Resources.Requests[k8sv1.ResourceStorage] = addFilesystemOverhead(requested, overhead)
+		if dataVolume.Annotations == nil {
+			dataVolume.Annotations = map[string]string{}
+		}
+		dataVolume.Annotations[v1.RequestedStorageSizeAnnotation] = requested.String()
+		dataVolume.Annotations[v1.FilesystemOverheadAnnotation] = strconv.FormatFloat(overhead, 'f', -1, 64)
+	}
+}
+
+// requestedStorageSize returns the size requested for a DataVolume template before the overhead in its
+// annotations was added, a size changed since then is the new requested size
+func requestedStorageSize(dataVolume *cdiv1.DataVolume, size resource.Quantity) resource.Quantity {
+	requested, err := resource.ParseQuantity(dataVolume.Annotations[v1.RequestedStorageSizeAnnotation])
+	if err != nil {
+		return size
+	}
+	overhead, err := strconv.ParseFloat(dataVolume.Annotations[v1.FilesystemOverheadAnnotation], 64)
+	if err != nil {
+		return size
+	}
+	if adjusted := addFilesystemOverhead(requested, overhead); adjusted.Cmp(size) != 0 {
+		return size
+	}
+	return requested
+}
+
+// addFilesystemOverhead returns the size of a volume whose filesystem takes the overhead, in percent, and leaves
+// the given size available, rounded up to whole mebibytes
+func addFilesystemOverhead(size resource.Quantity, overhead float64) resource.Quantity {
+	const mebibyte = 1024 * 1024
+	bytes := int64(math.Ceil(float64(size.Value()) / (1 - overhead/100)))
+	bytes = (bytes + mebibyte - 1) / mebibyte * mebibyte
+	return *resource.NewQuantity(bytes, resource.BinarySI)
+}
@@ -26,11 +26,14 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/api/admission/v1beta1"
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
 
 	v1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	"kubevirt.io/kubevirt/pkg/testutils"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
@@ -115,4 +118,94 @@ var _ = Describe("VirtualMachine Mutator", func() {
 		vmSpec, _ := getVMSpecMetaFromResponse()
 		Expect(vmSpec.Template.Spec.Domain.Machine.Type).To(Equal(vm.Spec.Template.Spec.Domain.Machine.Type))
 	})
+
+	Context("with a filesystem overhead", func() {
+		dataVolumeTemplate := func(size string, storageClass *string, volumeMode *k8sv1.PersistentVolumeMode) cdiv1.DataVolume {
+			return cdiv1.DataVolume{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "disk"},
+				Spec: cdiv1.DataVolumeSpec{
+					PVC: &k8sv1.PersistentVolumeClaimSpec{
+						StorageClassName: storageClass,
+						VolumeMode:       volumeMode,
+						Resources: k8sv1.ResourceRequirements{
+							Requests: k8sv1.ResourceList{k8sv1.ResourceStorage: resource.MustParse(size)},
+						},
+					},
+				},
+			}
+		}
+
+		requestedSize := func(dataVolume cdiv1.DataVolume) resource.Quantity {
+			return dataVolume.Spec.PVC.Resources.Requests[k8sv1.ResourceStorage]
+		}
+
+		BeforeEach(func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{
+					virtconfig.FilesystemOverheadKey: "global: \"5.5\"\nstorageClass:\n  local: \"10\"\n  raw: \"0\"",
+				},
+			})
+		})
+
+		It("should increase the size of DataVolume templates on filesystem volumes", func() {
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("10Gi", nil, nil)}
+
+			vmSpec, _ := getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("10836Mi")))
+			Expect(dataVolume.Annotations).To(HaveKeyWithValue(v1.RequestedStorageSizeAnnotation, "10Gi"))
+			Expect(dataVolume.Annotations).To(HaveKeyWithValue(v1.FilesystemOverheadAnnotation, "5.5"))
+		})
+
+		It("should use the overhead of the storage class", func() {
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("9Gi", pointer.StringPtr("local"), nil)}
+
+			vmSpec, _ := getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("10Gi")))
+			Expect(dataVolume.Annotations).To(HaveKeyWithValue(v1.FilesystemOverheadAnnotation, "10"))
+		})
+
+		It("should not change the size of DataVolume templates on block volumes", func() {
+			block := k8sv1.PersistentVolumeBlock
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("10Gi", nil, &block)}
+
+			vmSpec, _ := getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("10Gi")))
+			Expect(dataVolume.Annotations).To(BeEmpty())
+		})
+
+		It("should not change the size of DataVolume templates of storage classes without an overhead", func() {
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("10Gi", pointer.StringPtr("raw"), nil)}
+
+			vmSpec, _ := getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("10Gi")))
+			Expect(dataVolume.Annotations).To(BeEmpty())
+		})
+
+		It("should not increase the size again on updates", func() {
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("10Gi", nil, nil)}
+			vmSpec, _ := getVMSpecMetaFromResponse()
+
+			vm.Spec = *vmSpec
+			vmSpec, _ = getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("10836Mi")))
+			Expect(dataVolume.Annotations).To(HaveKeyWithValue(v1.RequestedStorageSizeAnnotation, "10Gi"))
+		})
+
+		It("should increase a size changed since the last admission", func() {
+			vm.Spec.DataVolumeTemplates = []cdiv1.DataVolume{dataVolumeTemplate("10Gi", nil, nil)}
+			vmSpec, _ := getVMSpecMetaFromResponse()
+
+			vm.Spec = *vmSpec
+			vm.Spec.DataVolumeTemplates[0].Spec.PVC.Resources.Requests[k8sv1.ResourceStorage] = resource.MustParse("20Gi")
+			vmSpec, _ = getVMSpecMetaFromResponse()
+			dataVolume := vmSpec.DataVolumeTemplates[0]
+			Expect(requestedSize(dataVolume)).To(Equal(resource.MustParse("21672Mi")))
+			Expect(dataVolume.Annotations).To(HaveKeyWithValue(v1.RequestedStorageSizeAnnotation, "20Gi"))
+		})
+	})
 })
@@ -33,6 +33,8 @@ import (
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
@@ -83,11 +85,14 @@ func (mutator *VMIsMutator) Mutate(ar *v1beta1.AdmissionReview) *v1beta1.Admissi
 		mutator.setDefaultMachineType(newVMI)
 		mutator.setDefaultResourceRequests(newVMI)
 		mutator.setDefaultPullPoliciesOnContainerDisks(newVMI)
+		mutator.setDefaultFreePageReporting(newVMI)
 		err = mutator.setDefaultNetworkInterface(newVMI)
 		if err != nil {
 			return webhookutils.ToAdmissionResponseError(err)
 		}
 		v1.SetObjectDefaults_VirtualMachineInstance(newVMI)
+		mutator.setDefaultMultiQueue(newVMI)
+		mutator.setNodeLabellerNodeSelectors(newVMI)
 
 		// In a future, yet undecided, release either libvirt or QEMU are going to check the hyperv dependencies, so we can get rid of this code.
 		// Until that time, we need to handle the hyperv deps to avoid obscure rejections from QEMU later on
@@ -105,6 +110,8 @@ func (mutator *VMIsMutator) Mutate(ar *v1beta1.AdmissionReview) *v1beta1.Admissi
 		// Add foreground finalizer
 		newVMI.Finalizers = append(newVMI.Finalizers, v1.VirtualMachineInstanceFinalizer)
 
+		mutator.setRuntimeUser(newVMI)
+
 		var value interface{}
 		value = newVMI.Spec
 		patch = append(patch, patchOperation{
@@ -119,6 +126,13 @@ func (mutator *VMIsMutator) Mutate(ar *v1beta1.AdmissionReview) *v1beta1.Admissi
 			Path:  "/metadata",
 			Value: value,
 		})
+
+		value = newVMI.Status
+		patch = append(patch, patchOperation{
+			Op:    "replace",
+			Path:  "/status",
+			Value: value,
+		})
 	} else if ar.Request.Operation == v1beta1.Update {
 		// Ignore status updates if they are not coming from our service accounts
 		// TODO: As soon as CRDs support field selectors we can remove this and just enable
@@ -179,6 +193,16 @@ func (mutator *VMIsMutator) setDefaultNetworkInterface(obj *v1.VirtualMachineIns
 	return nil
 }
 
+// setRuntimeUser decides if qemu and libvirt of the VMI run as root, the user
+// is fixed for the lifetime of the VMI and also used by its migration targets
+func (mutator *VMIsMutator) setRuntimeUser(vmi *v1.VirtualMachineInstance) {
+	if mutator.ClusterConfig.NonRootEnabled() {
+		vmi.Status.RuntimeUser = util.NonRootUID
+	} else {
+		vmi.Status.RuntimeUser = 0
+	}
+}
+
 func (mutator *VMIsMutator) setDefaultCPUModel(vmi *v1.VirtualMachineInstance) {
 	//if vmi doesn't have cpu topology or cpu model set
 	if vmi.Spec.Domain.CPU == nil || vmi.Spec.Domain.CPU.Model == "" {
@@ -193,20 +217,153 @@ func (mutator *VMIsMutator) setDefaultCPUModel(vmi *v1.VirtualMachineInstance) {
 	}
 }
 
+// setNodeLabellerNodeSelectors makes the VMI require nodes whose node-labeller labels
+// show that they provide the CPU model, CPU features and hugepages the VMI asks for
+func (mutator *VMIsMutator) setNodeLabellerNodeSelectors(vmi *v1.VirtualMachineInstance) {
+	if !mutator.ClusterConfig.CPUNodeDiscoveryEnabled() {
+		return
+	}
+
+	nodeSelectors := map[string]string{}
+	if cpu := vmi.Spec.Domain.CPU; cpu != nil {
+		if cpu.Model != "" && cpu.Model != v1.CPUModeHostModel && cpu.Model != v1.CPUModeHostPassthrough {
+			nodeSelectors[v1.CPUModelLabel+cpu.Model] = "true"
+		}
+		for _, feature := range cpu.Features {
+			if feature.Policy == "" || feature.Policy == "require" {
+				nodeSelectors[v1.CPUFeatureLabel+feature.Name] = "true"
+			}
+		}
+	}
+	if memory := vmi.Spec.Domain.Memory; memory != nil && memory.Hugepages != nil {
+		if pageSize, err := resource.ParseQuantity(memory.Hugepages.PageSize); err == nil {
+			nodeSelectors[v1.HugepagesLabel+pageSize.String()] = "true"
+		}
+	}
+
+	if len(nodeSelectors) == 0 {
+		return
+	}
+	if vmi.Spec.NodeSelector == nil {
+		vmi.Spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range nodeSelectors {
+		if _, exists := vmi.Spec.NodeSelector[key]; !exists {
+			vmi.Spec.NodeSelector[key] = value
+		}
+	}
+}
+
 func (mutator *VMIsMutator) setDefaultMachineType(vmi *v1.VirtualMachineInstance) {
 	if vmi.Spec.Domain.Machine.Type == "" {
 		vmi.Spec.Domain.Machine.Type = mutator.ClusterConfig.GetMachineType()
 	}
 }
 
+func (mutator *VMIsMutator) setDefaultFreePageReporting(vmi *v1.VirtualMachineInstance) {
+	devices := &vmi.Spec.Domain.Devices
+	if devices.AutoattachMemBalloon != nil && *devices.AutoattachMemBalloon == false {
+		return
+	}
+	if devices.MemBalloon == nil {
+		devices.MemBalloon = &v1.MemBalloon{}
+	}
+	if devices.MemBalloon.FreePageReporting == nil {
+		freePageReporting := mutator.ClusterConfig.IsMemBalloonFreePageReportingEnabled()
+		devices.MemBalloon.FreePageReporting = &freePageReporting
+	}
+}
+
+// setDefaultMultiQueue enables multi-queue on the virtio network interfaces and disks of
+// VMIs with more than one vCPU if the auto tuning is enabled, with one queue per vCPU up
+// to the configured maximum. Explicit settings of the VMI are kept.
+func (mutator *VMIsMutator) setDefaultMultiQueue(vmi *v1.VirtualMachineInstance) {
+	config := mutator.ClusterConfig.GetMultiQueueConfiguration()
+	if config == nil || !config.AutoTuning {
+		return
+	}
+
+	vcpus := getNumberOfVCPUs(vmi)
+	if vcpus < 2 {
+		return
+	}
+	maxQueues := virtconfig.DefaultMultiQueueMaxQueues
+	if config.MaxQueues != nil {
+		maxQueues = *config.MaxQueues
+	}
+	queues := uint32(vcpus)
+	if queues > maxQueues {
+		queues = maxQueues
+	}
+
+	devices := &vmi.Spec.Domain.Devices
+	if devices.NetworkInterfaceMultiQueue == nil {
+		for _, iface := range devices.Interfaces {
+			if iface.Model == "" || iface.Model == "virtio" {
+				multiQueue := true
+				devices.NetworkInterfaceMultiQueue = &multiQueue
+				break
+			}
+		}
+	}
+	if devices.NetworkInterfaceMultiQueue != nil && *devices.NetworkInterfaceMultiQueue && devices.NetworkInterfaceQueues == nil {
+		netQueues := queues
+		devices.NetworkInterfaceQueues = &netQueues
+	}
+
+	if devices.BlockMultiQueue != nil && !*devices.BlockMultiQueue {
+		return
+	}
+	for i := range devices.Disks {
+		disk := &devices.Disks[i]
+		if disk.Disk == nil || disk.Disk.Bus != "virtio" {
+			continue
+		}
+		if disk.Queues == nil {
+			disk.Queues = &v1.DiskQueues{}
+		}
+		if disk.Queues.Count == nil {
+			blkQueues := queues
+			disk.Queues.Count = &blkQueues
+		}
+	}
+}
+
+// getNumberOfVCPUs counts the vCPUs of the VMI the same way as the domain is built, from the
+// CPU topology or else from the CPU limit or request
+func getNumberOfVCPUs(vmi *v1.VirtualMachineInstance) int64 {
+	if cpu := vmi.Spec.Domain.CPU; cpu != nil {
+		if vcpus := hardware.GetNumberOfVCPUs(cpu); vcpus > 0 {
+			return vcpus
+		}
+	}
+	resources := vmi.Spec.Domain.Resources
+	if cpuLimit, ok := resources.Limits[k8sv1.ResourceCPU]; ok {
+		return cpuLimit.Value()
+	} else if cpuRequest, ok := resources.Requests[k8sv1.ResourceCPU]; ok {
+		return cpuRequest.Value()
+	}
+	return 1
+}
+
 func (mutator *VMIsMutator) setDefaultPullPoliciesOnContainerDisks(vmi *v1.VirtualMachineInstance) {
 	for _, volume := range vmi.Spec.Volumes {
-		if volume.ContainerDisk != nil && volume.ContainerDisk.ImagePullPolicy == "" {
-			if strings.HasSuffix(volume.ContainerDisk.Image, ":latest") || !strings.ContainsAny(volume.ContainerDisk.Image, ":@") {
-				volume.ContainerDisk.ImagePullPolicy = k8sv1.PullAlways
-			} else {
-				volume.ContainerDisk.ImagePullPolicy = k8sv1.PullIfNotPresent
-			}
+		if volume.ContainerDisk != nil {
+			setDefaultPullPolicy(volume.ContainerDisk)
+		}
+	}
+	if firmware := vmi.Spec.Domain.Firmware; firmware != nil && firmware.Bootloader != nil &&
+		firmware.Bootloader.EFI != nil && firmware.Bootloader.EFI.ContainerDisk != nil {
+		setDefaultPullPolicy(firmware.Bootloader.EFI.ContainerDisk)
+	}
+}
+
+func setDefaultPullPolicy(containerDisk *v1.ContainerDiskSource) {
+	if containerDisk.ImagePullPolicy == "" {
+		if strings.HasSuffix(containerDisk.Image, ":latest") || !strings.ContainsAny(containerDisk.Image, ":@") {
+			containerDisk.ImagePullPolicy = k8sv1.PullAlways
+		} else {
+			containerDisk.ImagePullPolicy = k8sv1.PullIfNotPresent
 		}
 	}
 }
@@ -38,6 +38,7 @@ import (
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/util"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 	"kubevirt.io/kubevirt/pkg/virt-operator/creation/rbac"
@@ -61,7 +62,7 @@ var _ = Describe("VirtualMachineInstance Mutator", func() {
 	machineTypeFromConfig := "pc-q35-3.0"
 	cpuRequestFromConfig := "800m"
 
-	getVMISpecMetaFromResponse := func() (*v1.VirtualMachineInstanceSpec, *k8smetav1.ObjectMeta) {
+	mutateVMICreate := func() []byte {
 		vmiBytes, err := json.Marshal(vmi)
 		Expect(err).ToNot(HaveOccurred())
 		By("Creating the test admissions review from the VMI")
@@ -77,6 +78,11 @@ var _ = Describe("VirtualMachineInstance Mutator", func() {
 		By("Mutating the VMI")
 		resp := mutator.Mutate(ar)
 		Expect(resp.Allowed).To(BeTrue())
+		return resp.Patch
+	}
+
+	getVMISpecMetaFromResponse := func() (*v1.VirtualMachineInstanceSpec, *k8smetav1.ObjectMeta) {
+		patchBytes := mutateVMICreate()
 
 		By("Getting the VMI spec from the response")
 		vmiSpec := &v1.VirtualMachineInstanceSpec{}
@@ -85,13 +91,30 @@ var _ = Describe("VirtualMachineInstance Mutator", func() {
 			{Value: vmiSpec},
 			{Value: vmiMeta},
 		}
-		err = json.Unmarshal(resp.Patch, &patch)
+		err := json.Unmarshal(patchBytes, &patch)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(patch).NotTo(BeEmpty())
 
 		return vmiSpec, vmiMeta
 	}
 
+	getVMIStatusFromCreateResponse := func() *v1.VirtualMachineInstanceStatus {
+		patchBytes := mutateVMICreate()
+
+		By("Getting the VMI status from the response")
+		vmiStatus := &v1.VirtualMachineInstanceStatus{}
+		patch := []patchOperation{
+			{Value: &v1.VirtualMachineInstanceSpec{}},
+			{Value: &k8smetav1.ObjectMeta{}},
+			{Value: vmiStatus},
+		}
+		err := json.Unmarshal(patchBytes, &patch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patch[2].Path).To(Equal("/status"))
+
+		return vmiStatus
+	}
+
 	getVMIStatusFromResponse := func(oldVMI *v1.VirtualMachineInstance, newVMI *v1.VirtualMachineInstance, user string) *v1.VirtualMachineInstanceStatus {
 		oldVMIBytes, err := json.Marshal(oldVMI)
 		Expect(err).ToNot(HaveOccurred())
@@ -407,6 +430,18 @@ var _ = Describe("VirtualMachineInstance Mutator", func() {
 		),
 	)
 
+	It("should set the ImagePullPolicy on the EFI firmware container disk", func() {
+		vmi.Spec.Domain.Firmware = &v1.Firmware{
+			Bootloader: &v1.Bootloader{
+				EFI: &v1.EFI{
+					ContainerDisk: &v1.ContainerDiskSource{Image: "ovmf:custom"},
+				},
+			},
+		}
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(vmiSpec.Domain.Firmware.Bootloader.EFI.ContainerDisk.ImagePullPolicy).To(Equal(k8sv1.PullIfNotPresent))
+	})
+
 	table.DescribeTable("should add the default network interface",
 		func(iface string) {
 			expectedIface := "bridge"
@@ -529,6 +564,142 @@ var _ = Describe("VirtualMachineInstance Mutator", func() {
 		Expect(vmiSpec.Domain.Memory.Guest.String()).To(Equal("4096M"))
 	})
 
+	Context("with the CPUNodeDiscovery feature gate", func() {
+		BeforeEach(func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.CPUNodeDiscoveryGate},
+			})
+		})
+
+		It("should require nodes providing the CPU and hugepages of the VMI", func() {
+			vmi.Spec.NodeSelector = map[string]string{"custom": "label"}
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Model:    "Haswell",
+				Features: []v1.CPUFeature{{Name: "vmx"}, {Name: "pcid", Policy: "optional"}},
+			}
+			vmi.Spec.Domain.Memory = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "1Gi"}}
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(vmiSpec.NodeSelector).To(Equal(map[string]string{
+				"custom":                     "label",
+				v1.CPUModelLabel + "Haswell": "true",
+				v1.CPUFeatureLabel + "vmx":   "true",
+				v1.HugepagesLabel + "1Gi":    "true",
+			}))
+		})
+
+		It("should not require a CPU model for host-model VMIs", func() {
+			vmi.Spec.Domain.CPU = &v1.CPU{Model: v1.CPUModeHostModel}
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(vmiSpec.NodeSelector).To(BeEmpty())
+		})
+	})
+
+	It("should not add node-labeller node selectors without the CPUNodeDiscovery feature gate", func() {
+		vmi.Spec.Domain.CPU = &v1.CPU{Model: "Haswell"}
+
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(vmiSpec.NodeSelector).To(BeEmpty())
+	})
+
+	It("should disable free page reporting by default", func() {
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(*vmiSpec.Domain.Devices.MemBalloon.FreePageReporting).To(BeFalse())
+	})
+
+	It("should default free page reporting to the cluster config", func() {
+		testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.MemBalloonFreePageReportingKey: "true"},
+		})
+
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(*vmiSpec.Domain.Devices.MemBalloon.FreePageReporting).To(BeTrue())
+	})
+
+	It("should not override the free page reporting of the VMI", func() {
+		testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.MemBalloonFreePageReportingKey: "true"},
+		})
+		freePageReporting := false
+		vmi.Spec.Domain.Devices.MemBalloon = &v1.MemBalloon{FreePageReporting: &freePageReporting}
+
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(*vmiSpec.Domain.Devices.MemBalloon.FreePageReporting).To(BeFalse())
+	})
+
+	It("should run qemu and libvirt as root by default", func() {
+		vmi.Status.RuntimeUser = util.NonRootUID
+		Expect(getVMIStatusFromCreateResponse().RuntimeUser).To(BeZero())
+	})
+
+	It("should run qemu and libvirt as an unprivileged user with the NonRoot feature gate", func() {
+		testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.NonRootGate},
+		})
+
+		Expect(getVMIStatusFromCreateResponse().RuntimeUser).To(Equal(uint64(util.NonRootUID)))
+	})
+
+	Context("with multi-queue auto tuning", func() {
+		BeforeEach(func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.MultiQueueConfigurationKey: "autoTuning: true\nmaxQueues: 4"},
+			})
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+				{Name: "virtiodisk", DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "virtio"}}},
+				{Name: "satadisk", DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "sata"}}},
+			}
+		})
+
+		It("should use one queue per vCPU", func() {
+			vmi.Spec.Domain.CPU = &v1.CPU{Cores: 2}
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(*vmiSpec.Domain.Devices.NetworkInterfaceMultiQueue).To(BeTrue())
+			Expect(*vmiSpec.Domain.Devices.NetworkInterfaceQueues).To(Equal(uint32(2)))
+			Expect(*vmiSpec.Domain.Devices.Disks[0].Queues.Count).To(Equal(uint32(2)))
+			Expect(vmiSpec.Domain.Devices.Disks[1].Queues).To(BeNil())
+		})
+
+		It("should limit the queues to the configured maximum", func() {
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{k8sv1.ResourceCPU: resource.MustParse("6")}
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(*vmiSpec.Domain.Devices.NetworkInterfaceQueues).To(Equal(uint32(4)))
+			Expect(*vmiSpec.Domain.Devices.Disks[0].Queues.Count).To(Equal(uint32(4)))
+		})
+
+		It("should not tune the queues of a VMI with a single vCPU", func() {
+			vmi.Spec.Domain.CPU = &v1.CPU{Cores: 1}
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(vmiSpec.Domain.Devices.NetworkInterfaceMultiQueue).To(BeNil())
+			Expect(vmiSpec.Domain.Devices.Disks[0].Queues).To(BeNil())
+		})
+
+		It("should keep the explicit multi-queue settings of the VMI", func() {
+			vmi.Spec.Domain.CPU = &v1.CPU{Cores: 4}
+			multiQueue := false
+			vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue = &multiQueue
+			vmi.Spec.Domain.Devices.BlockMultiQueue = &multiQueue
+
+			vmiSpec, _ := getVMISpecMetaFromResponse()
+			Expect(*vmiSpec.Domain.Devices.NetworkInterfaceMultiQueue).To(BeFalse())
+			Expect(vmiSpec.Domain.Devices.NetworkInterfaceQueues).To(BeNil())
+			Expect(vmiSpec.Domain.Devices.Disks[0].Queues).To(BeNil())
+		})
+	})
+
+	It("should not configure the memory balloon if it is not attached", func() {
+		autoattach := false
+		vmi.Spec.Domain.Devices.AutoattachMemBalloon = &autoattach
+
+		vmiSpec, _ := getVMISpecMetaFromResponse()
+		Expect(vmiSpec.Domain.Devices.MemBalloon).To(BeNil())
+	})
+
 	It("should apply foreground finalizer on VMI create", func() {
 		_, vmiMeta := getVMISpecMetaFromResponse()
 		Expect(vmiMeta.Finalizers).To(ContainElement(v1.VirtualMachineInstanceFinalizer))
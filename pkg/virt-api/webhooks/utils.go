@@ -77,6 +77,8 @@ type Informers struct {
 	VMIPresetInformer       cache.SharedIndexInformer
 	NamespaceLimitsInformer cache.SharedIndexInformer
 	VMIInformer             cache.SharedIndexInformer
+	NodeInformer            cache.SharedIndexInformer
+	ResourceQuotaInformer   cache.SharedIndexInformer
 }
 
 // XXX fix this, this is a huge mess. Move informers to Admitter and Mutator structs.
@@ -112,6 +114,8 @@ func newInformers() *Informers {
 		VMIInformer:             kubeInformerFactory.VMI(),
 		VMIPresetInformer:       kubeInformerFactory.VirtualMachinePreset(),
 		NamespaceLimitsInformer: kubeInformerFactory.LimitRanges(),
+		NodeInformer:            kubeInformerFactory.KubeVirtNode(),
+		ResourceQuotaInformer:   kubeInformerFactory.ResourceQuotas(),
 	}
 }
 
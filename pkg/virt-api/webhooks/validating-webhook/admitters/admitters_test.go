@@ -21,6 +21,7 @@ package admitters
 
 import (
 	. "github.com/onsi/ginkgo"
+	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
@@ -33,8 +34,12 @@ var _ = Describe("Validating Webhook", func() {
 
 	BeforeSuite(func() {
 		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		nodeInformer, _ := testutils.NewFakeInformerFor(&k8sv1.Node{})
+		resourceQuotaInformer, _ := testutils.NewFakeInformerFor(&k8sv1.ResourceQuota{})
 		webhooks.SetInformers(&webhooks.Informers{
-			VMIInformer: vmiInformer,
+			VMIInformer:           vmiInformer,
+			NodeInformer:          nodeInformer,
+			ResourceQuotaInformer: resourceQuotaInformer,
 		})
 	})
 })
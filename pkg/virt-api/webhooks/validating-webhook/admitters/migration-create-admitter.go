@@ -29,6 +29,7 @@ import (
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
 	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/util"
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
@@ -75,6 +76,10 @@ func (admitter *MigrationCreateAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1b
 		return webhookutils.ToAdmissionResponseError(fmt.Errorf("Cannot migrated VMI in finalized state."))
 	}
 
+	if util.IsSEVVMI(vmi) && !admitter.ClusterConfig.SEVLiveMigrationEnabled() {
+		return webhookutils.ToAdmissionResponseError(fmt.Errorf("Cannot migrate VMI %s with SEV memory encryption, the %s feature gate is not enabled", vmi.Name, virtconfig.SEVLiveMigrationGate))
+	}
+
 	// Reject migration jobs for non-migratable VMIs
 	for _, c := range vmi.Status.Conditions {
 		if c.Type == v1.VirtualMachineInstanceIsMigratable &&
@@ -83,6 +88,11 @@ func (admitter *MigrationCreateAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1b
 				c.Reason, c.Message)
 			return webhookutils.ToAdmissionResponseError(errMsg)
 		}
+		// The guest filesystems are quiesced for a snapshot, keep the VMI where it is until they are thawed
+		if c.Type == v1.VirtualMachineInstanceFrozen &&
+			c.Status == k8sv1.ConditionTrue {
+			return webhookutils.ToAdmissionResponseError(fmt.Errorf("Cannot migrate VMI %s while its guest filesystems are frozen", vmi.Name))
+		}
 	}
 
 	// Don't allow new migration jobs to be introduced when previous migration jobs
@@ -292,6 +292,84 @@ var _ = Describe("Validating MigrationCreate Admitter", func() {
 		Expect(resp.Result.Message).To(ContainSubstring("DisksNotLiveMigratable"))
 	})
 
+	It("should reject Migration spec for VMIs with frozen guest filesystems", func() {
+		vmi := v1.NewMinimalVMI("testmigratevmi4")
+		vmi.Status.Phase = v1.Running
+		vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+			{
+				Type:   v1.VirtualMachineInstanceFrozen,
+				Status: k8sv1.ConditionTrue,
+			},
+		}
+
+		informers := webhooks.GetInformers()
+		informers.VMIInformer.GetIndexer().Add(vmi)
+
+		migration := v1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+			},
+			Spec: v1.VirtualMachineInstanceMigrationSpec{
+				VMIName: "testmigratevmi4",
+			},
+		}
+		migrationBytes, _ := json.Marshal(&migration)
+
+		enableFeatureGate(virtconfig.LiveMigrationGate)
+
+		ar := &v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Resource: webhooks.MigrationGroupVersionResource,
+				Object: runtime.RawExtension{
+					Raw: migrationBytes,
+				},
+			},
+		}
+
+		resp := migrationCreateAdmitter.Admit(ar)
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("frozen"))
+	})
+
+	table.DescribeTable("should handle Migration spec for SEV VMIs", func(featureGates string, allowed bool) {
+		vmi := v1.NewMinimalVMI("testmigratevmi5")
+		vmi.Status.Phase = v1.Running
+		vmi.Spec.Domain.LaunchSecurity = &v1.LaunchSecurity{SEV: &v1.SEV{}}
+
+		informers := webhooks.GetInformers()
+		informers.VMIInformer.GetIndexer().Add(vmi)
+
+		migration := v1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+			},
+			Spec: v1.VirtualMachineInstanceMigrationSpec{
+				VMIName: "testmigratevmi5",
+			},
+		}
+		migrationBytes, _ := json.Marshal(&migration)
+
+		enableFeatureGate(featureGates)
+
+		ar := &v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Resource: webhooks.MigrationGroupVersionResource,
+				Object: runtime.RawExtension{
+					Raw: migrationBytes,
+				},
+			},
+		}
+
+		resp := migrationCreateAdmitter.Admit(ar)
+		Expect(resp.Allowed).To(Equal(allowed))
+		if !allowed {
+			Expect(resp.Result.Message).To(ContainSubstring("SEV memory encryption"))
+		}
+	},
+		table.Entry("reject without the SEVLiveMigration feature gate", virtconfig.LiveMigrationGate, false),
+		table.Entry("accept with the SEVLiveMigration feature gate", virtconfig.LiveMigrationGate+","+virtconfig.SEVLiveMigrationGate, true),
+	)
+
 	table.DescribeTable("should reject documents containing unknown or missing fields for", func(data string, validationResult string, gvr metav1.GroupVersionResource, review func(ar *v1beta1.AdmissionReview) *v1beta1.AdmissionResponse) {
 		input := map[string]interface{}{}
 		json.Unmarshal([]byte(data), &input)
@@ -0,0 +1,162 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package admitters
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/api/admission/v1beta1"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	migrationutils "kubevirt.io/kubevirt/pkg/util/migrations"
+	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// EvictionBlockedReason is the event reason used when the eviction of a virt-launcher pod is denied
+	EvictionBlockedReason = "EvictionBlocked"
+
+	evictionBlockedNotMigratable = "NotMigratable"
+	evictionBlockedMigrating     = "Migrating"
+	evictionBlockedExternal      = "External"
+)
+
+var evictionBlockedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kubevirt_vmi_eviction_blocked_total",
+		Help: "Number of virt-launcher pod evictions which were blocked, by reason",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	prometheus.MustRegister(evictionBlockedCounter)
+}
+
+// PodEvictionAdmitter intercepts evictions of virt-launcher pods and
+// enforces the eviction strategy of the VirtualMachineInstance
+type PodEvictionAdmitter struct {
+	ClusterConfig *virtconfig.ClusterConfig
+	Client        kubecli.KubevirtClient
+	Recorder      record.EventRecorder
+}
+
+// NewPodEvictionAdmitter creates a PodEvictionAdmitter
+func NewPodEvictionAdmitter(clusterConfig *virtconfig.ClusterConfig, client kubecli.KubevirtClient, recorder record.EventRecorder) *PodEvictionAdmitter {
+	return &PodEvictionAdmitter{
+		ClusterConfig: clusterConfig,
+		Client:        client,
+		Recorder:      recorder,
+	}
+}
+
+// Admit validates an AdmissionReview
+func (admitter *PodEvictionAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+	pod, err := admitter.Client.CoreV1().Pods(ar.Request.Namespace).Get(ar.Request.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return allowEviction()
+	} else if err != nil {
+		return webhookutils.ToAdmissionResponseError(err)
+	}
+
+	if pod.Labels[v1.AppLabel] != "virt-launcher" {
+		return allowEviction()
+	}
+	domainName, exists := pod.Annotations[v1.DomainAnnotation]
+	if !exists {
+		return allowEviction()
+	}
+
+	vmi, err := admitter.Client.VirtualMachineInstance(pod.Namespace).Get(domainName, &metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return allowEviction()
+	} else if err != nil {
+		return webhookutils.ToAdmissionResponseError(err)
+	}
+
+	if vmi.IsFinal() {
+		return allowEviction()
+	}
+
+	dryRun := ar.Request.DryRun != nil && *ar.Request.DryRun
+
+	switch migrationutils.VMIEvictionStrategy(vmi) {
+	case v1.EvictionStrategyLiveMigrate:
+		if !migrationutils.IsMigratable(vmi) {
+			return admitter.denyEviction(vmi, evictionBlockedNotMigratable,
+				fmt.Sprintf("VMI %s has eviction strategy %s but is not migratable", vmi.Name, v1.EvictionStrategyLiveMigrate))
+		}
+		return admitter.evacuate(vmi, pod, dryRun, evictionBlockedMigrating,
+			fmt.Sprintf("VMI %s is migrated away from node %s before its pod can be evicted", vmi.Name, pod.Spec.NodeName))
+	case v1.EvictionStrategyLiveMigrateIfPossible:
+		if !migrationutils.IsMigratable(vmi) {
+			return allowEviction()
+		}
+		return admitter.evacuate(vmi, pod, dryRun, evictionBlockedMigrating,
+			fmt.Sprintf("VMI %s is migrated away from node %s before its pod can be evicted", vmi.Name, pod.Spec.NodeName))
+	case v1.EvictionStrategyExternal:
+		return admitter.evacuate(vmi, pod, dryRun, evictionBlockedExternal,
+			fmt.Sprintf("VMI %s has to be moved away from node %s by an external controller", vmi.Name, pod.Spec.NodeName))
+	}
+
+	return allowEviction()
+}
+
+// evacuate marks the VMI as having to leave the node of the pod and denies the eviction
+func (admitter *PodEvictionAdmitter) evacuate(vmi *v1.VirtualMachineInstance, pod *k8sv1.Pod, dryRun bool, reason string, message string) *v1beta1.AdmissionResponse {
+	if !dryRun && vmi.Status.EvacuationNodeName != pod.Spec.NodeName {
+		patch := fmt.Sprintf(`[{ "op": "add", "path": "/status/evacuationNodeName", "value": "%s" }]`, pod.Spec.NodeName)
+		if _, err := admitter.Client.VirtualMachineInstance(vmi.Namespace).Patch(vmi.Name, types.JSONPatchType, []byte(patch)); err != nil {
+			return webhookutils.ToAdmissionResponseError(err)
+		}
+	}
+	return admitter.denyEviction(vmi, reason, message)
+}
+
+func (admitter *PodEvictionAdmitter) denyEviction(vmi *v1.VirtualMachineInstance, reason string, message string) *v1beta1.AdmissionResponse {
+	log.Log.Object(vmi).Infof("Blocked eviction of virt-launcher pod: %s", message)
+	evictionBlockedCounter.WithLabelValues(reason).Inc()
+	admitter.Recorder.Event(vmi, k8sv1.EventTypeWarning, EvictionBlockedReason, message)
+
+	// TooManyRequests makes kubectl drain retry the eviction until the VMI is gone
+	return &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Message: message,
+			Code:    http.StatusTooManyRequests,
+		},
+	}
+}
+
+func allowEviction() *v1beta1.AdmissionResponse {
+	return &v1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
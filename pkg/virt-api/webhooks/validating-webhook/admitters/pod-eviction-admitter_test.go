@@ -0,0 +1,169 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package admitters
+
+import (
+	"net/http"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/admission/v1beta1"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("Validating Pod Eviction Admitter", func() {
+	var ctrl *gomock.Controller
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var kubeClient *fake.Clientset
+	var recorder *record.FakeRecorder
+	var admitter *PodEvictionAdmitter
+
+	newLauncherPod := func() *k8sv1.Pod {
+		return &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virt-launcher-testvmi",
+				Namespace: k8sv1.NamespaceDefault,
+				Labels: map[string]string{
+					v1.AppLabel: "virt-launcher",
+				},
+				Annotations: map[string]string{
+					v1.DomainAnnotation: "testvmi",
+				},
+			},
+			Spec: k8sv1.PodSpec{
+				NodeName: "testnode",
+			},
+		}
+	}
+
+	newVMI := func(strategy v1.EvictionStrategy, migratable bool) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = v1.Running
+		vmi.Status.NodeName = "testnode"
+		vmi.Spec.EvictionStrategy = &strategy
+		status := k8sv1.ConditionFalse
+		if migratable {
+			status = k8sv1.ConditionTrue
+		}
+		vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{{Type: v1.VirtualMachineInstanceIsMigratable, Status: status}}
+		return vmi
+	}
+
+	newEvictionReview := func(dryRun bool) *v1beta1.AdmissionReview {
+		return &v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Name:      "virt-launcher-testvmi",
+				Namespace: k8sv1.NamespaceDefault,
+				Operation: v1beta1.Create,
+				DryRun:    &dryRun,
+			},
+		}
+	}
+
+	addPod := func(pod *k8sv1.Pod) {
+		_, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(pod)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+		virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+		recorder = record.NewFakeRecorder(10)
+		admitter = NewPodEvictionAdmitter(nil, virtClient, recorder)
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	It("should allow the eviction of unknown pods", func() {
+		resp := admitter.Admit(newEvictionReview(false))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should allow the eviction of pods which are not virt-launcher pods", func() {
+		pod := newLauncherPod()
+		pod.Labels = nil
+		addPod(pod)
+
+		resp := admitter.Admit(newEvictionReview(false))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	table.DescribeTable("should allow the eviction", func(strategy v1.EvictionStrategy, migratable bool) {
+		addPod(newLauncherPod())
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(newVMI(strategy, migratable), nil)
+
+		resp := admitter.Admit(newEvictionReview(false))
+		Expect(resp.Allowed).To(BeTrue())
+	},
+		table.Entry("with the None strategy", v1.EvictionStrategyNone, true),
+		table.Entry("of non-migratable VMIs with the LiveMigrateIfPossible strategy", v1.EvictionStrategyLiveMigrateIfPossible, false),
+	)
+
+	table.DescribeTable("should block the eviction and mark the VMI for evacuation", func(strategy v1.EvictionStrategy) {
+		addPod(newLauncherPod())
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(newVMI(strategy, true), nil)
+		vmiInterface.EXPECT().Patch("testvmi", types.JSONPatchType, []byte(`[{ "op": "add", "path": "/status/evacuationNodeName", "value": "testnode" }]`)).Return(nil, nil)
+
+		resp := admitter.Admit(newEvictionReview(false))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusTooManyRequests)))
+		testutils.ExpectEvent(recorder, EvictionBlockedReason)
+	},
+		table.Entry("with the LiveMigrate strategy", v1.EvictionStrategyLiveMigrate),
+		table.Entry("with the LiveMigrateIfPossible strategy", v1.EvictionStrategyLiveMigrateIfPossible),
+		table.Entry("with the External strategy", v1.EvictionStrategyExternal),
+	)
+
+	It("should not mark the VMI on dry runs", func() {
+		addPod(newLauncherPod())
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(newVMI(v1.EvictionStrategyLiveMigrate, true), nil)
+
+		resp := admitter.Admit(newEvictionReview(true))
+		Expect(resp.Allowed).To(BeFalse())
+		testutils.ExpectEvent(recorder, EvictionBlockedReason)
+	})
+
+	It("should block the eviction of non-migratable VMIs with the LiveMigrate strategy", func() {
+		addPod(newLauncherPod())
+		vmiInterface.EXPECT().Get("testvmi", gomock.Any()).Return(newVMI(v1.EvictionStrategyLiveMigrate, false), nil)
+
+		resp := admitter.Admit(newEvictionReview(false))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("is not migratable"))
+		testutils.ExpectEvent(recorder, EvictionBlockedReason)
+	})
+})
@@ -25,13 +25,17 @@ import (
 	"net"
 	"regexp"
 	"strings"
+	"time"
 
 	"k8s.io/api/admission/v1beta1"
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/kubevirt/pkg/hooks"
@@ -40,6 +44,7 @@ import (
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 )
 
 const (
@@ -53,14 +58,29 @@ const (
 	cloudInitUserMaxLen    = 2048
 	cloudInitNetworkMaxLen = 2048
 
+	// ignitionDataMaxLen limits inline Ignition configs for the same reason,
+	// larger configs should be referenced with SecretRef or ConfigMapRef
+	ignitionDataMaxLen = 2048
+
 	// Copied from kubernetes/pkg/apis/core/validation/validation.go
 	maxDNSNameservers     = 3
 	maxDNSSearchPaths     = 6
 	maxDNSSearchListChars = 256
+
+	// Limits of the virtio block devices of QEMU
+	maxDiskQueues    = 256
+	maxDiskQueueSize = 1024
+
+	// Limit of the virtio network devices of QEMU
+	maxNetworkInterfaceQueues = 256
+
+	// Trimming the guest is too expensive to run it more often
+	minDiskCompactionInterval = time.Hour
 )
 
 var validInterfaceModels = map[string]*struct{}{"e1000": nil, "e1000e": nil, "ne2k_pci": nil, "pcnet": nil, "rtl8139": nil, "virtio": nil}
-var validIOThreadsPolicies = []v1.IOThreadsPolicy{v1.IOThreadsPolicyShared, v1.IOThreadsPolicyAuto}
+var validIOThreadsPolicies = []v1.IOThreadsPolicy{v1.IOThreadsPolicyShared, v1.IOThreadsPolicyAuto, v1.IOThreadsPolicyManual}
+var validEmulatorThreadPolicies = []v1.EmulatorThreadPolicy{v1.EmulatorThreadPolicyIsolate, v1.EmulatorThreadPolicyVCPU0, v1.EmulatorThreadPolicyFloat}
 var validCPUFeaturePolicies = map[string]*struct{}{"": nil, "force": nil, "require": nil, "optional": nil, "disable": nil, "forbid": nil}
 
 var restriectedVmiLabels = map[string]bool{
@@ -92,16 +112,165 @@ func (admitter *VMICreateAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1beta1.A
 	causes = append(causes, ValidateVirtualMachineInstanceMetadata(k8sfield.NewPath("metadata"), &vmi.ObjectMeta, admitter.ClusterConfig, accountName)...)
 	// In a future, yet undecided, release either libvirt or QEMU are going to check the hyperv dependencies, so we can get rid of this code.
 	causes = append(causes, webhooks.ValidateVirtualMachineInstanceHypervFeatureDependencies(k8sfield.NewPath("spec"), &vmi.Spec)...)
+	// the node-labeller only labels the hugepages of the nodes with the CPUNodeDiscovery feature gate
+	if admitter.ClusterConfig.CPUNodeDiscoveryEnabled() {
+		causes = append(causes, validateHugepagesNodeCapacity(k8sfield.NewPath("spec"), &vmi.Spec, webhooks.GetInformers().NodeInformer.GetStore())...)
+	}
+	if util.IsNonRootVMI(vmi) {
+		causes = append(causes, validateNonRootVMI(k8sfield.NewPath("spec"), &vmi.Spec)...)
+	}
 
 	if len(causes) > 0 {
 		return webhookutils.ToAdmissionResponse(causes)
 	}
 
+	if resp := validateResourceQuotas(vmi, ar.Request.Namespace, webhooks.GetInformers().ResourceQuotaInformer.GetIndexer()); resp != nil {
+		return resp
+	}
+
 	reviewResponse := v1beta1.AdmissionResponse{}
 	reviewResponse.Allowed = true
 	return &reviewResponse
 }
 
+// validateResourceQuotas refuses VMIs whose virt-launcher pod would be refused by
+// the resource quotas of the namespace. Otherwise the VMI would stay pending until
+// the quota allows the pod, while the guest resources alone may seem to fit.
+func validateResourceQuotas(vmi *v1.VirtualMachineInstance, namespace string, quotaIndexer cache.Indexer) *v1beta1.AdmissionResponse {
+	objs, err := quotaIndexer.ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return webhookutils.ToAdmissionResponseError(err)
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+	quotas := make([]*k8sv1.ResourceQuota, 0, len(objs))
+	for _, obj := range objs {
+		quotas = append(quotas, obj.(*k8sv1.ResourceQuota))
+	}
+
+	pod, err := services.RenderLauncherResources(vmi)
+	if err != nil {
+		return webhookutils.ToAdmissionResponseError(err)
+	}
+	if err := services.CheckResourceQuotas(vmi, pod, quotas); err != nil {
+		status := errors.NewForbidden(v1.Resource("virtualmachineinstances"), vmi.Name, err).Status()
+		return &v1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		}
+	}
+	return nil
+}
+
+// validateHugepagesNodeCapacity verifies with the labels of the node-labeller that
+// a node provides hugepages of the requested size, and that one of these nodes has
+// enough allocatable hugepages of that size for the guest memory
+func validateHugepagesNodeCapacity(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, nodeStore cache.Store) []metav1.StatusCause {
+	if spec.Domain.Memory == nil || spec.Domain.Memory.Hugepages == nil {
+		return nil
+	}
+	pageSize, err := resource.ParseQuantity(spec.Domain.Memory.Hugepages.PageSize)
+	if err != nil {
+		// reported by the spec validation
+		return nil
+	}
+
+	// the pod requests hugepages for the guest memory if it is smaller than the requested memory
+	memory := spec.Domain.Resources.Requests.Memory()
+	if spec.Domain.Memory.Guest != nil && spec.Domain.Memory.Guest.Cmp(*memory) < 0 {
+		memory = spec.Domain.Memory.Guest
+	}
+
+	label := v1.HugepagesLabel + pageSize.String()
+	resourceName := k8sv1.ResourceName(k8sv1.ResourceHugePagesPrefix + pageSize.String())
+	labelled := false
+	for _, obj := range nodeStore.List() {
+		node := obj.(*k8sv1.Node)
+		if node.Labels[label] != "true" {
+			continue
+		}
+		labelled = true
+		if allocatable, exists := node.Status.Allocatable[resourceName]; exists && allocatable.Cmp(*memory) >= 0 {
+			return nil
+		}
+	}
+
+	if !labelled {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s '%s' is not provided by any node", field.Child("domain", "memory", "hugepages", "pageSize").String(), spec.Domain.Memory.Hugepages.PageSize),
+			Field:   field.Child("domain", "memory", "hugepages", "pageSize").String(),
+		}}
+	}
+	return []metav1.StatusCause{{
+		Type: metav1.CauseTypeFieldValueInvalid,
+		Message: fmt.Sprintf("no node has %s of allocatable hugepages of size %s '%s'",
+			memory.String(),
+			field.Child("domain", "memory", "hugepages", "pageSize").String(),
+			spec.Domain.Memory.Hugepages.PageSize,
+		),
+		Field: field.Child("domain", "memory", "hugepages", "pageSize").String(),
+	}}
+}
+
+// validateNonRootVMI refuses the features which need privileges a non-root
+// virt-launcher lacks. Only passt connects the guest to the pod network without
+// rewiring it, virtiofsd needs to preserve the ownership of the shared files,
+// realtime vCPUs need CAP_SYS_NICE and host disks are created on the node.
+func validateNonRootVMI(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	reason := "is not supported with the NonRoot feature gate"
+
+	interfaces := spec.Domain.Devices.Interfaces
+	autoattach := spec.Domain.Devices.AutoattachPodInterface
+	if len(interfaces) == 0 && (autoattach == nil || *autoattach) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("the default pod network interface %s, use a passt interface or disable %s", reason, field.Child("domain", "devices", "autoattachPodInterface").String()),
+			Field:   field.Child("domain", "devices", "autoattachPodInterface").String(),
+		})
+	}
+	for idx, iface := range interfaces {
+		if iface.Passt == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("%s %s, only passt interfaces are", field.Child("domain", "devices", "interfaces").Index(idx).String(), reason),
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).String(),
+			})
+		}
+	}
+
+	for idx, fs := range spec.Domain.Devices.Filesystems {
+		if fs.Virtiofs != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("%s %s", field.Child("domain", "devices", "filesystems").Index(idx).Child("virtiofs").String(), reason),
+				Field:   field.Child("domain", "devices", "filesystems").Index(idx).Child("virtiofs").String(),
+			})
+		}
+	}
+
+	if spec.Domain.CPU != nil && spec.Domain.CPU.Realtime != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s %s", field.Child("domain", "cpu", "realtime").String(), reason),
+			Field:   field.Child("domain", "cpu", "realtime").String(),
+		})
+	}
+
+	for idx, volume := range spec.Volumes {
+		if volume.HostDisk != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("%s %s", field.Child("volumes").Index(idx).Child("hostDisk").String(), reason),
+				Field:   field.Child("volumes").Index(idx).Child("hostDisk").String(),
+			})
+		}
+	}
+	return causes
+}
+
 func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
 	var causes []metav1.StatusCause
 	volumeNameMap := make(map[string]*v1.Volume)
@@ -420,6 +589,37 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 			Field:   field.Child("domain", "cpu", "isolateEmulatorThread").String(),
 		})
 	}
+	if spec.Domain.CPU != nil && spec.Domain.CPU.EmulatorThreadPolicy != nil {
+		policy := *spec.Domain.CPU.EmulatorThreadPolicy
+		isValidPolicy := false
+		for _, p := range validEmulatorThreadPolicies {
+			if policy == p {
+				isValidPolicy = true
+			}
+		}
+		if !isValidPolicy {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("Invalid EmulatorThreadPolicy (%s)", policy),
+				Field:   field.Child("domain", "cpu", "emulatorThreadPolicy").String(),
+			})
+		} else if policy != v1.EmulatorThreadPolicyFloat && !spec.Domain.CPU.DedicatedCPUPlacement {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("EmulatorThreadPolicy %s should be only set in combination with DedicatedCPUPlacement", policy),
+				Field:   field.Child("domain", "cpu", "emulatorThreadPolicy").String(),
+			})
+		} else if policy != v1.EmulatorThreadPolicyIsolate && spec.Domain.CPU.IsolateEmulatorThread {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("EmulatorThreadPolicy %s conflicts with IsolateEmulatorThread", policy),
+				Field:   field.Child("domain", "cpu", "emulatorThreadPolicy").String(),
+			})
+		}
+	}
+	if spec.Domain.CPU != nil && spec.Domain.CPU.ThreadPolicy != nil {
+		causes = append(causes, validateCPUThreadPolicy(field, spec.Domain.CPU)...)
+	}
 	// Validate CPU Feature Policies
 	if spec.Domain.CPU != nil && spec.Domain.CPU.Features != nil {
 		for idx, feature := range spec.Domain.CPU.Features {
@@ -461,8 +661,8 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 		}
 	}
 
-	for _, volume := range spec.Volumes {
-		volumeNameMap[volume.Name] = &volume
+	for i := range spec.Volumes {
+		volumeNameMap[spec.Volumes[i].Name] = &spec.Volumes[i]
 	}
 
 	// used to validate uniqueness of boot orders among disks and interfaces
@@ -482,6 +682,24 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 			})
 		}
 
+		// Verify ignition volumes are not mapped to disks, they are passed through the firmware configuration
+		if volumeExists && matchingVolume.Ignition != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s can not be mapped to an ignition volume.", field.Child("domain", "devices", "disks").Index(idx).String()),
+				Field:   field.Child("domain", "devices", "disks").Index(idx).Child("name").String(),
+			})
+		}
+
+		// Verify sysprep volumes are only mapped to cdroms, where Windows Setup looks for the answer file
+		if volumeExists && matchingVolume.Sysprep != nil && disk.CDRom == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be a cdrom to be mapped to a sysprep volume.", field.Child("domain", "devices", "disks").Index(idx).String()),
+				Field:   field.Child("domain", "devices", "disks").Index(idx).String(),
+			})
+		}
+
 		// Verify Lun disks are only mapped to network/block devices.
 		if disk.LUN != nil && volumeExists && matchingVolume.PersistentVolumeClaim == nil {
 			causes = append(causes, metav1.StatusCause{
@@ -525,6 +743,9 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 			if network.NetworkSource.Multus.Default {
 				multusDefaultCount++
 			}
+			if network.NetworkSource.Multus.OVN != nil {
+				causes = append(causes, validateOVNNetwork(field.Child("networks").Index(idx).Child("multus"), network.NetworkSource.Multus)...)
+			}
 		}
 
 		if cniTypesCount == 0 {
@@ -612,6 +833,36 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 				Message: "Bridge on pod network configuration is not enabled under kubevirt-config",
 				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("name").String(),
 			})
+		} else if iface.Passt != nil && networkData.Pod == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Passt interface only implemented with pod network",
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("name").String(),
+			})
+		} else if iface.Passt != nil && !config.PasstEnabled() {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Passt feature gate is not enabled in kubevirt-config",
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("passt").String(),
+			})
+		} else if iface.Macvtap != nil && !config.MacvtapEnabled() {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Macvtap feature gate is not enabled in kubevirt-config",
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("macvtap").String(),
+			})
+		} else if iface.Macvtap != nil && (networkData.Multus == nil || networkData.Multus.Default) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Macvtap interface only implemented with multus secondary networks",
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("name").String(),
+			})
+		} else if iface.InterfaceBindingMethod.Bridge == nil && networkData.Multus != nil && networkData.Multus.OVN != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "OVN-Kubernetes secondary networks are only supported with the bridge binding",
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("name").String(),
+			})
 		}
 
 		// Check if the interface name is unique
@@ -778,6 +1029,57 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 					})
 				}
 			}
+			causes = append(causes, validateDHCPOverrides(field.Child("domain", "devices", "interfaces").Index(idx).Child("dhcpOptions"), iface.DHCPOptions)...)
+		}
+
+		// verify that connection limits are only set where they can be enforced
+		if limits := iface.ConnectionLimits; limits != nil {
+			if iface.Masquerade == nil {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("interface %s: connection limits are only supported with the masquerade binding", field.Child("domain", "devices", "interfaces").Index(idx).Child("name").String()),
+					Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("connectionLimits").String(),
+				})
+			}
+			if limits.MaxConnections != nil && *limits.MaxConnections == 0 {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: "maxConnections must be greater than 0, if supplied",
+					Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("connectionLimits", "maxConnections").String(),
+				})
+			}
+			if limits.MaxNewConnectionsPerSecond != nil && *limits.MaxNewConnectionsPerSecond == 0 {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: "maxNewConnectionsPerSecond must be greater than 0, if supplied",
+					Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("connectionLimits", "maxNewConnectionsPerSecond").String(),
+				})
+			}
+		}
+
+		if iface.AllowedAddresses != nil {
+			causes = append(causes, validateAllowedAddresses(field.Child("domain", "devices", "interfaces").Index(idx), &iface)...)
+		}
+
+		if iface.Bandwidth != nil {
+			causes = append(causes, validateInterfaceBandwidth(field.Child("domain", "devices", "interfaces").Index(idx), &iface)...)
+		}
+
+		// Only the IPAM of multus networks can be asked for the claimed addresses
+		if iface.PersistentIPs && (iface.Bridge == nil || (networkExists && networkData.Multus == nil)) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("interface %s: persistent IPs are only supported with the bridge binding on multus networks", iface.Name),
+				Field:   field.Child("domain", "devices", "interfaces").Index(idx).Child("persistentIPs").String(),
+			})
+		}
+
+		if iface.Passt != nil {
+			causes = append(causes, validatePasstPorts(field.Child("domain", "devices", "interfaces").Index(idx), &iface)...)
+		}
+
+		if iface.SRIOV != nil && iface.SRIOV.FailoverStandby != "" {
+			causes = append(causes, validateSRIOVFailoverStandby(field.Child("domain", "devices", "interfaces").Index(idx), &iface, spec.Domain.Devices.Interfaces)...)
 		}
 	}
 	// Network interface multiqueue can only be set for a virtio driver
@@ -790,6 +1092,22 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 		})
 
 	}
+	if queues := spec.Domain.Devices.NetworkInterfaceQueues; queues != nil {
+		if vifMQ == nil || !*vifMQ {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "virtio-net queues are set, but networkInterfaceMultiqueue is not enabled",
+				Field:   field.Child("domain", "devices", "networkInterfaceQueues").String(),
+			})
+		}
+		if *queues < 1 || *queues > maxNetworkInterfaceQueues {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("virtio-net queues must be between 1 and %d", maxNetworkInterfaceQueues),
+				Field:   field.Child("domain", "devices", "networkInterfaceQueues").String(),
+			})
+		}
+	}
 
 	// Validate that every network was assign to an interface
 	networkDuplicates := map[string]struct{}{}
@@ -845,56 +1163,20 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 			})
 		}
 	}
+	causes = append(causes, validateIOThreads(field, spec)...)
 
-	if spec.ReadinessProbe != nil {
-		if spec.ReadinessProbe.HTTPGet != nil && spec.ReadinessProbe.TCPSocket != nil {
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("%s must have exactly one probe type set", field.Child("readinessProbe").String()),
-				Field:   field.Child("readinessProbe").String(),
-			})
-		} else if spec.ReadinessProbe.HTTPGet == nil && spec.ReadinessProbe.TCPSocket == nil {
-			causes = append(causes, metav1.StatusCause{
-				Type: metav1.CauseTypeFieldValueRequired,
-				Message: fmt.Sprintf("either %s or %s must be set if a %s is specified",
-					field.Child("readinessProbe", "tcpSocket").String(),
-					field.Child("readinessProbe", "httpGet").String(),
-					field.Child("readinessProbe").String(),
-				),
-				Field: field.Child("readinessProbe").String(),
-			})
-		}
-	}
-
-	if spec.LivenessProbe != nil {
-		if spec.LivenessProbe.HTTPGet != nil && spec.LivenessProbe.TCPSocket != nil {
-			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("%s must have exactly one probe type set", field.Child("livenessProbe").String()),
-				Field:   field.Child("livenessProbe").String(),
-			})
-		} else if spec.LivenessProbe.HTTPGet == nil && spec.LivenessProbe.TCPSocket == nil {
-			causes = append(causes, metav1.StatusCause{
-				Type: metav1.CauseTypeFieldValueRequired,
-				Message: fmt.Sprintf("either %s or %s must be set if a %s is specified",
-					field.Child("livenessProbe", "tcpSocket").String(),
-					field.Child("livenessProbe", "httpGet").String(),
-					field.Child("livenessProbe").String(),
-				),
-				Field: field.Child("livenessProbe").String(),
-			})
-		}
-	}
+	causes = append(causes, validateProbe(field.Child("readinessProbe"), spec.ReadinessProbe)...)
+	causes = append(causes, validateProbe(field.Child("livenessProbe"), spec.LivenessProbe)...)
 
 	if !podNetworkInterfacePresent {
-		if spec.LivenessProbe != nil {
+		if probeNeedsPodNetwork(spec.LivenessProbe) {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
 				Message: fmt.Sprintf("%s is only allowed if the Pod Network is attached", field.Child("livenessProbe").String()),
 				Field:   field.Child("livenessProbe").String(),
 			})
 		}
-		if spec.ReadinessProbe != nil {
+		if probeNeedsPodNetwork(spec.ReadinessProbe) {
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
 				Message: fmt.Sprintf("%s is only allowed if the Pod Network is attached", field.Child("readinessProbe").String()),
@@ -909,22 +1191,32 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 		causes = append(causes, validateDNSPolicy(&spec.DNSPolicy, field.Child("dnsPolicy"))...)
 	}
 	causes = append(causes, validatePodDNSConfig(spec.DNSConfig, &spec.DNSPolicy, field.Child("dnsConfig"))...)
+	causes = append(causes, validateDiskCompaction(field.Child("diskCompaction"), spec.DiskCompaction)...)
+	if spec.Lifecycle != nil {
+		causes = append(causes, validateLifecycleHandler(field.Child("lifecycle", "postStart"), spec.Lifecycle.PostStart)...)
+		causes = append(causes, validateLifecycleHandler(field.Child("lifecycle", "preStop"), spec.Lifecycle.PreStop)...)
+	}
+	causes = append(causes, validateIOErrorRecovery(field.Child("ioErrorRecovery"), spec.IOErrorRecovery)...)
+	causes = append(causes, validateSerialConsoleLog(field.Child("domain", "devices", "serialConsoleLog"), spec)...)
 
-	if !config.LiveMigrationEnabled() && spec.EvictionStrategy != nil {
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: "LiveMigration feature gate is not enabled",
-			Field:   field.Child("evictionStrategy").String(),
-		})
-	} else if spec.EvictionStrategy != nil {
-		if *spec.EvictionStrategy != v1.EvictionStrategyLiveMigrate {
+	if spec.EvictionStrategy != nil {
+		switch *spec.EvictionStrategy {
+		case v1.EvictionStrategyLiveMigrate, v1.EvictionStrategyLiveMigrateIfPossible:
+			if !config.LiveMigrationEnabled() {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: "LiveMigration feature gate is not enabled",
+					Field:   field.Child("evictionStrategy").String(),
+				})
+			}
+		case v1.EvictionStrategyExternal, v1.EvictionStrategyNone:
+		default:
 			causes = append(causes, metav1.StatusCause{
 				Type:    metav1.CauseTypeFieldValueInvalid,
 				Message: fmt.Sprintf("%s is set with an unrecognized option: %s", field.Child("evictionStrategy").String(), *spec.EvictionStrategy),
 				Field:   field.Child("evictionStrategy").String(),
 			})
 		}
-
 	}
 
 	if spec.Domain.Devices.GPUs != nil && !config.GPUPassthroughEnabled() {
@@ -943,6 +1235,42 @@ func ValidateVirtualMachineInstanceSpec(field *k8sfield.Path, spec *v1.VirtualMa
 		})
 	}
 
+	if spec.Domain.Devices.HostDevices != nil {
+		causes = append(causes, validateHostDevices(field, spec, config)...)
+	}
+
+	if spec.Domain.Devices.TPM != nil {
+		causes = append(causes, validateTPM(field, spec, config)...)
+	}
+
+	if len(spec.Domain.Devices.Filesystems) > 0 {
+		causes = append(causes, validateFilesystems(field, spec, config)...)
+	}
+
+	if spec.Domain.LaunchSecurity != nil {
+		causes = append(causes, validateLaunchSecurity(field, spec, config)...)
+	}
+
+	if spec.Domain.Devices.Sound != nil {
+		causes = append(causes, validateSound(field, spec.Domain.Devices.Sound)...)
+	}
+
+	if spec.Domain.Devices.Panic != nil {
+		causes = append(causes, validatePanic(field, spec)...)
+	}
+
+	if len(spec.TopologySpreadConstraints) > 0 {
+		causes = append(causes, validateTopologySpreadConstraints(field, spec.TopologySpreadConstraints)...)
+	}
+
+	if spec.Domain.CPU != nil && spec.Domain.CPU.NUMA != nil && spec.Domain.CPU.NUMA.GuestMappingPassthrough != nil {
+		causes = append(causes, validateNUMAPassthrough(field, spec, config)...)
+	}
+
+	if spec.Domain.CPU != nil && spec.Domain.CPU.Realtime != nil {
+		causes = append(causes, validateRealtime(field, spec)...)
+	}
+
 	return causes
 }
 
@@ -1005,6 +1333,18 @@ func ValidateVirtualMachineInstanceMetadata(field *k8sfield.Path, metadata *meta
 		})
 	}
 
+	// Only the workload classes the SELinux types are configured for can be selected
+	if class, exists := annotations[v1.SELinuxWorkloadClassAnnotation]; exists {
+		if _, known := config.GetSELinuxLauncherTypeForClass(class); !known {
+			causes = append(causes, metav1.StatusCause{
+				Type: metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("unknown SELinux workload class '%s' in %s, the class has no selinuxLauncherTypes entry",
+					class, field.Child("annotations", v1.SELinuxWorkloadClassAnnotation).String()),
+				Field: field.Child("annotations").String(),
+			})
+		}
+	}
+
 	return causes
 }
 
@@ -1100,58 +1440,301 @@ func validatePodDNSConfig(dnsConfig *k8sv1.PodDNSConfig, dnsPolicy *k8sv1.DNSPol
 	return causes
 }
 
-// Copied from kubernetes/pkg/apis/core/validation/validation.go
-func validateDNSPolicy(dnsPolicy *k8sv1.DNSPolicy, field *k8sfield.Path) []metav1.StatusCause {
+func validateProbe(field *k8sfield.Path, probe *v1.Probe) []metav1.StatusCause {
 	var causes []metav1.StatusCause
-	switch *dnsPolicy {
-	case k8sv1.DNSClusterFirstWithHostNet, k8sv1.DNSClusterFirst, k8sv1.DNSDefault, k8sv1.DNSNone, "":
-	default:
-		validValues := []string{string(k8sv1.DNSClusterFirstWithHostNet), string(k8sv1.DNSClusterFirst), string(k8sv1.DNSDefault), string(k8sv1.DNSNone), ""}
-		causes = append(causes, metav1.StatusCause{
-			Type:    metav1.CauseTypeFieldValueNotSupported,
-			Message: fmt.Sprintf("DNSPolicy: %s is not supported, valid values: %s", *dnsPolicy, validValues),
-			Field:   field.String(),
-		})
+	if probe == nil {
+		return causes
 	}
-	return causes
-}
 
-func validateBootloader(field *k8sfield.Path, bootloader *v1.Bootloader) []metav1.StatusCause {
-	var causes []metav1.StatusCause
+	actions := 0
+	if probe.HTTPGet != nil {
+		actions++
+	}
+	if probe.TCPSocket != nil {
+		actions++
+	}
+	if probe.GuestAgentExec != nil {
+		actions++
+	}
 
-	if bootloader != nil && bootloader.EFI != nil && bootloader.BIOS != nil {
+	if actions > 1 {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("%s has both EFI and BIOS configured, but they are mutually exclusive.", field.String()),
+			Message: fmt.Sprintf("%s must have exactly one probe type set", field.String()),
 			Field:   field.String(),
 		})
+	} else if actions == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("either %s, %s or %s must be set if a %s is specified",
+				field.Child("tcpSocket").String(),
+				field.Child("httpGet").String(),
+				field.Child("guestAgentExec").String(),
+				field.String(),
+			),
+			Field: field.String(),
+		})
+	} else if probe.GuestAgentExec != nil && len(probe.GuestAgentExec.Command) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must not be empty", field.Child("guestAgentExec", "command").String()),
+			Field:   field.Child("guestAgentExec", "command").String(),
+		})
 	}
-
 	return causes
 }
 
-func validateFirmware(field *k8sfield.Path, firmware *v1.Firmware) []metav1.StatusCause {
+func validateLifecycleHandler(field *k8sfield.Path, handler *v1.LifecycleHandler) []metav1.StatusCause {
 	var causes []metav1.StatusCause
-
-	if firmware != nil {
-		causes = append(causes, validateBootloader(field.Child("bootloader"), firmware.Bootloader)...)
+	if handler == nil {
+		return causes
 	}
 
-	return causes
-}
-
-func validateDomainSpec(field *k8sfield.Path, spec *v1.DomainSpec) []metav1.StatusCause {
-	var causes []metav1.StatusCause
-
-	causes = append(causes, validateDevices(field.Child("devices"), &spec.Devices)...)
-	causes = append(causes, validateFirmware(field.Child("firmware"), spec.Firmware)...)
-
-	if spec.Firmware != nil && spec.Firmware.Bootloader != nil && spec.Firmware.Bootloader.EFI != nil &&
-		(spec.Firmware.Bootloader.EFI.SecureBoot == nil || *spec.Firmware.Bootloader.EFI.SecureBoot) &&
-		(spec.Features == nil || spec.Features.SMM == nil || !*spec.Features.SMM.Enabled) {
+	if handler.Exec != nil && handler.HTTPGet != nil {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("%s has EFI SecureBoot enabled. SecureBoot requires SMM, which is currently disabled.", field.String()),
+			Message: fmt.Sprintf("%s must have exactly one handler type set", field.String()),
+			Field:   field.String(),
+		})
+	} else if handler.Exec == nil && handler.HTTPGet == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("either %s or %s must be set if a %s is specified",
+				field.Child("exec").String(),
+				field.Child("httpGet").String(),
+				field.String(),
+			),
+			Field: field.String(),
+		})
+	} else if handler.Exec != nil && len(handler.Exec.Command) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must not be empty", field.Child("exec", "command").String()),
+			Field:   field.Child("exec", "command").String(),
+		})
+	} else if handler.HTTPGet != nil && (handler.HTTPGet.Port.Type != intstr.Int || handler.HTTPGet.Port.IntVal < 1 || handler.HTTPGet.Port.IntVal > 65535) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be a port number between 1 and 65535", field.Child("httpGet", "port").String()),
+			Field:   field.Child("httpGet", "port").String(),
+		})
+	}
+
+	if handler.TimeoutSeconds < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must not be negative", field.Child("timeoutSeconds").String()),
+			Field:   field.Child("timeoutSeconds").String(),
+		})
+	}
+	return causes
+}
+
+// probeNeedsPodNetwork returns true for probes which connect to the VMI over the pod network
+func probeNeedsPodNetwork(probe *v1.Probe) bool {
+	return probe != nil && (probe.HTTPGet != nil || probe.TCPSocket != nil)
+}
+
+// Copied from kubernetes/pkg/apis/core/validation/validation.go
+func validateDNSPolicy(dnsPolicy *k8sv1.DNSPolicy, field *k8sfield.Path) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	switch *dnsPolicy {
+	case k8sv1.DNSClusterFirstWithHostNet, k8sv1.DNSClusterFirst, k8sv1.DNSDefault, k8sv1.DNSNone, "":
+	default:
+		validValues := []string{string(k8sv1.DNSClusterFirstWithHostNet), string(k8sv1.DNSClusterFirst), string(k8sv1.DNSDefault), string(k8sv1.DNSNone), ""}
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("DNSPolicy: %s is not supported, valid values: %s", *dnsPolicy, validValues),
+			Field:   field.String(),
+		})
+	}
+	return causes
+}
+
+func validateOVNNetwork(field *k8sfield.Path, multus *v1.MultusNetwork) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	ovn := multus.OVN
+
+	if multus.Default {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "OVN-Kubernetes secondary networks can't be used as the Multus default network",
+			Field:   field.Child("ovn").String(),
+		})
+	}
+
+	if ovn.Topology != v1.OVNTopologyLocalnet && ovn.Topology != v1.OVNTopologyLayer2 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s must be one of %s, %s", field.Child("ovn", "topology").String(), v1.OVNTopologyLocalnet, v1.OVNTopologyLayer2),
+			Field:   field.Child("ovn", "topology").String(),
+		})
+	}
+
+	if ovn.VLAN != nil {
+		if ovn.Topology != v1.OVNTopologyLocalnet {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is only supported with the %s topology", field.Child("ovn", "vlan").String(), v1.OVNTopologyLocalnet),
+				Field:   field.Child("ovn", "vlan").String(),
+			})
+		} else if *ovn.VLAN < 1 || *ovn.VLAN > 4094 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be in range 1 to 4094", field.Child("ovn", "vlan").String()),
+				Field:   field.Child("ovn", "vlan").String(),
+			})
+		}
+	}
+
+	return causes
+}
+
+func validateBootloader(field *k8sfield.Path, bootloader *v1.Bootloader) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	if bootloader != nil && bootloader.EFI != nil && bootloader.BIOS != nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s has both EFI and BIOS configured, but they are mutually exclusive.", field.String()),
+			Field:   field.String(),
+		})
+	}
+
+	if bootloader != nil && bootloader.EFI != nil {
+		causes = append(causes, validateEFI(field.Child("efi"), bootloader.EFI)...)
+	}
+
+	return causes
+}
+
+func validateEFI(field *k8sfield.Path, efi *v1.EFI) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	if efi.EnrolledKeys != nil && efi.SecureBoot != nil && !*efi.SecureBoot {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is only supported with SecureBoot enabled.", field.Child("enrolledKeys").String()),
+			Field:   field.Child("enrolledKeys").String(),
+		})
+	}
+
+	if efi.ContainerDisk != nil && efi.ContainerDisk.Image == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must be set", field.Child("containerDisk", "image").String()),
+			Field:   field.Child("containerDisk", "image").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateFirmware(field *k8sfield.Path, firmware *v1.Firmware) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	if firmware != nil {
+		causes = append(causes, validateBootloader(field.Child("bootloader"), firmware.Bootloader)...)
+	}
+
+	return causes
+}
+
+func validateDiskCompaction(field *k8sfield.Path, diskCompaction *v1.DiskCompaction) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	if diskCompaction != nil && diskCompaction.Interval.Duration < minDiskCompactionInterval {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be at least %s", field.Child("interval").String(), minDiskCompactionInterval),
+			Field:   field.Child("interval").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateIOErrorRecovery(field *k8sfield.Path, recovery *v1.IOErrorRecovery) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	if recovery != nil && recovery.RetryIntervalSeconds < 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must not be negative", field.Child("retryIntervalSeconds").String()),
+			Field:   field.Child("retryIntervalSeconds").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateSerialConsoleLog(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	serialConsoleLog := spec.Domain.Devices.SerialConsoleLog
+	if serialConsoleLog == nil {
+		return causes
+	}
+
+	if spec.Domain.Devices.AutoattachSerialConsole != nil && !*spec.Domain.Devices.AutoattachSerialConsole {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires the serial console to be attached", field.String()),
+			Field:   field.String(),
+		})
+	}
+
+	if serialConsoleLog.VolumeName != "" {
+		var logVolume *v1.Volume
+		for i := range spec.Volumes {
+			if spec.Volumes[i].Name == serialConsoleLog.VolumeName {
+				logVolume = &spec.Volumes[i]
+			}
+		}
+		usedByDisk := false
+		for _, disk := range spec.Domain.Devices.Disks {
+			if disk.Name == serialConsoleLog.VolumeName {
+				usedByDisk = true
+			}
+		}
+		if logVolume == nil || logVolume.PersistentVolumeClaim == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must reference a persistentVolumeClaim volume", field.Child("volumeName").String()),
+				Field:   field.Child("volumeName").String(),
+			})
+		} else if usedByDisk {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must reference a volume which is not used by a disk", field.Child("volumeName").String()),
+				Field:   field.Child("volumeName").String(),
+			})
+		}
+	}
+
+	if serialConsoleLog.MaxSize != nil && serialConsoleLog.MaxSize.Value() <= 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be greater than 0", field.Child("maxSize").String()),
+			Field:   field.Child("maxSize").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateDomainSpec(field *k8sfield.Path, spec *v1.DomainSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	causes = append(causes, validateDevices(field.Child("devices"), &spec.Devices)...)
+	causes = append(causes, validateFirmware(field.Child("firmware"), spec.Firmware)...)
+
+	if spec.Firmware != nil && spec.Firmware.Bootloader != nil && spec.Firmware.Bootloader.EFI != nil &&
+		(spec.Firmware.Bootloader.EFI.SecureBoot == nil || *spec.Firmware.Bootloader.EFI.SecureBoot) &&
+		(spec.Features == nil || spec.Features.SMM == nil || (spec.Features.SMM.Enabled != nil && !*spec.Features.SMM.Enabled)) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s has EFI SecureBoot enabled. SecureBoot requires SMM, which is currently disabled.", field.String()),
 			Field:   field.String(),
 		})
 	}
@@ -1175,6 +1758,11 @@ func validateVolumes(field *k8sfield.Path, volumes []v1.Volume, config *virtconf
 
 	// check that we have max 1 serviceAccount volume
 	serviceAccountVolumeCount := 0
+	// check that we have max 1 ignition volume
+	ignitionVolumeCount := 0
+	// check that we have max 1 sysprep volume, which is not used together with cloud-init
+	sysprepVolumeCount := 0
+	cloudInitVolumeCount := 0
 
 	for idx, volume := range volumes {
 		// verify name is unique
@@ -1206,6 +1794,16 @@ func validateVolumes(field *k8sfield.Path, volumes []v1.Volume, config *virtconf
 		if volume.Ephemeral != nil {
 			volumeSourceSetCount++
 		}
+		if volume.GoldenImage != nil {
+			if volume.GoldenImage.ClaimName == "" {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueRequired,
+					Message: "GoldenImage 'claimName' must be set",
+					Field:   field.Index(idx).Child("goldenImage", "claimName").String(),
+				})
+			}
+			volumeSourceSetCount++
+		}
 		if volume.EmptyDisk != nil {
 			volumeSourceSetCount++
 		}
@@ -1240,6 +1838,17 @@ func validateVolumes(field *k8sfield.Path, volumes []v1.Volume, config *virtconf
 			volumeSourceSetCount++
 			serviceAccountVolumeCount++
 		}
+		if volume.Ignition != nil {
+			volumeSourceSetCount++
+			ignitionVolumeCount++
+		}
+		if volume.Sysprep != nil {
+			volumeSourceSetCount++
+			sysprepVolumeCount++
+		}
+		if volume.CloudInitNoCloud != nil || volume.CloudInitConfigDrive != nil {
+			cloudInitVolumeCount++
+		}
 
 		if volumeSourceSetCount != 1 {
 			causes = append(causes, metav1.StatusCause{
@@ -1413,6 +2022,14 @@ func validateVolumes(field *k8sfield.Path, volumes []v1.Volume, config *virtconf
 				})
 			}
 		}
+
+		if volume.Ignition != nil {
+			causes = append(causes, validateIgnitionSource(field.Index(idx).Child("ignition"), volume.Ignition, config)...)
+		}
+
+		if volume.Sysprep != nil {
+			causes = append(causes, validateSysprepSource(field.Index(idx).Child("sysprep"), volume.Sysprep)...)
+		}
 	}
 
 	if serviceAccountVolumeCount > 1 {
@@ -1423,89 +2040,729 @@ func validateVolumes(field *k8sfield.Path, volumes []v1.Volume, config *virtconf
 		})
 	}
 
-	return causes
-}
+	if ignitionVolumeCount > 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must have max one ignition volume set", field.String()),
+			Field:   field.String(),
+		})
+	}
 
-func validateDevices(field *k8sfield.Path, devices *v1.Devices) []metav1.StatusCause {
-	var causes []metav1.StatusCause
-	causes = append(causes, validateDisks(field.Child("disks"), devices.Disks)...)
-	return causes
-}
+	if sysprepVolumeCount > 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must have max one sysprep volume set", field.String()),
+			Field:   field.String(),
+		})
+	}
 
-func getNumberOfPodInterfaces(spec *v1.VirtualMachineInstanceSpec) int {
-	nPodInterfaces := 0
-	for _, net := range spec.Networks {
-		if net.Pod != nil {
-			for _, iface := range spec.Domain.Devices.Interfaces {
-				if iface.Name == net.Name {
-					nPodInterfaces++
-					break // we maintain 1-to-1 relationship between networks and interfaces
-				}
-			}
-		}
+	if sysprepVolumeCount > 0 && cloudInitVolumeCount > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must not have a sysprep volume together with a cloud-init volume", field.String()),
+			Field:   field.String(),
+		})
 	}
-	return nPodInterfaces
+
+	return causes
 }
 
-func validateDisks(field *k8sfield.Path, disks []v1.Disk) []metav1.StatusCause {
+func validateIgnitionSource(field *k8sfield.Path, source *v1.IgnitionSource, config *virtconfig.ClusterConfig) []metav1.StatusCause {
 	var causes []metav1.StatusCause
-	nameMap := make(map[string]int)
 
-	if len(disks) > arrayLenMax {
+	if !config.IgnitionEnabled() {
 		causes = append(causes, metav1.StatusCause{
 			Type:    metav1.CauseTypeFieldValueInvalid,
-			Message: fmt.Sprintf("%s list exceeds the %d element limit in length", field.String(), arrayLenMax),
+			Message: fmt.Sprintf("ExperimentalIgnitionSupport feature gate is not enabled in kubevirt-config, invalid entry %s", field.String()),
 			Field:   field.String(),
 		})
-		// We won't process anything over the limit
-		return causes
 	}
 
-	for idx, disk := range disks {
-		// verify name is unique
-		otherIdx, ok := nameMap[disk.Name]
-		if !ok {
-			nameMap[disk.Name] = idx
-		} else {
+	sourceCount := 0
+	if source.SecretRef != nil {
+		sourceCount++
+		if source.SecretRef.Name == "" {
 			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueInvalid,
-				Message: fmt.Sprintf("%s and %s must not have the same Name.", field.Index(idx).String(), field.Index(otherIdx).String()),
-				Field:   field.Index(idx).Child("name").String(),
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s is a required field", field.Child("secretRef", "name").String()),
+				Field:   field.Child("secretRef", "name").String(),
 			})
 		}
-
-		// Reject Floppy disks
-		if disk.Floppy != nil {
+	}
+	if source.ConfigMapRef != nil {
+		sourceCount++
+		if source.ConfigMapRef.Name == "" {
 			causes = append(causes, metav1.StatusCause{
-				Type:    metav1.CauseTypeFieldValueNotSupported,
-				Message: "Floppy disks are deprecated and will be removed from the API soon.",
-				Field:   field.Index(idx).Child("name").String(),
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s is a required field", field.Child("configMapRef", "name").String()),
+				Field:   field.Child("configMapRef", "name").String(),
 			})
 		}
+	}
+	if source.Data != "" {
+		sourceCount++
+	}
 
-		// Verify only a single device type is set.
-		deviceTargetSetCount := 0
-		var diskType, bus string
-		if disk.Disk != nil {
-			deviceTargetSetCount++
-			diskType = "disk"
-			bus = disk.Disk.Bus
-		}
-		if disk.LUN != nil {
-			deviceTargetSetCount++
-			diskType = "lun"
-			bus = disk.LUN.Bus
-		}
-		if disk.Floppy != nil {
-			deviceTargetSetCount++
-		}
-		if disk.CDRom != nil {
-			deviceTargetSetCount++
-			diskType = "cdrom"
-			bus = disk.CDRom.Bus
-		}
+	if sourceCount != 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must have exactly one ignition config source set.", field.String()),
+			Field:   field.String(),
+		})
+	}
 
-		// NOTE: not setting a device target is okay. We default to Disk.
+	if len(source.Data) > ignitionDataMaxLen {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s exceeds %d byte limit. Should use SecretRef or ConfigMapRef for larger data.", field.Child("data").String(), ignitionDataMaxLen),
+			Field:   field.Child("data").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateSysprepSource(field *k8sfield.Path, source *v1.SysprepSource) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	sourceCount := 0
+	if source.ConfigMap != nil {
+		sourceCount++
+		if source.ConfigMap.Name == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s is a required field", field.Child("configMap", "name").String()),
+				Field:   field.Child("configMap", "name").String(),
+			})
+		}
+	}
+	if source.Secret != nil {
+		sourceCount++
+		if source.Secret.Name == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s is a required field", field.Child("secret", "name").String()),
+				Field:   field.Child("secret", "name").String(),
+			})
+		}
+	}
+
+	if sourceCount != 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must have exactly one answer file source set.", field.String()),
+			Field:   field.String(),
+		})
+	}
+
+	return causes
+}
+
+func validateIOThreads(field *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+
+	manual := spec.Domain.IOThreadsPolicy != nil && *spec.Domain.IOThreadsPolicy == v1.IOThreadsPolicyManual
+	ioThreadsField := field.Child("domain", "ioThreads")
+
+	if !manual && len(spec.Domain.IOThreads) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires the %s IOThreadsPolicy", ioThreadsField.String(), v1.IOThreadsPolicyManual),
+			Field:   ioThreadsField.String(),
+		})
+	} else if manual && len(spec.Domain.IOThreads) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must be set with the %s IOThreadsPolicy", ioThreadsField.String(), v1.IOThreadsPolicyManual),
+			Field:   ioThreadsField.String(),
+		})
+	}
+
+	// the vCPUs an IOThread can be pinned to
+	vCPUs := int64(0)
+	if spec.Domain.CPU != nil {
+		vCPUs = hardware.GetNumberOfVCPUs(spec.Domain.CPU)
+	}
+	if vCPUs == 0 {
+		vCPUs = spec.Domain.Resources.Requests.Cpu().Value()
+	}
+	if vCPUs == 0 {
+		vCPUs = spec.Domain.Resources.Limits.Cpu().Value()
+	}
+
+	ioThreadIDs := map[uint32]bool{}
+	for idx, ioThread := range spec.Domain.IOThreads {
+		if ioThread.ID < 1 || int(ioThread.ID) > len(spec.Domain.IOThreads) || ioThreadIDs[ioThread.ID] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be unique and between 1 and %d", ioThreadsField.Index(idx).Child("id").String(), len(spec.Domain.IOThreads)),
+				Field:   ioThreadsField.Index(idx).Child("id").String(),
+			})
+		}
+		ioThreadIDs[ioThread.ID] = true
+
+		if ioThread.CPUs == "" {
+			continue
+		}
+		cpusField := ioThreadsField.Index(idx).Child("cpus")
+		if spec.Domain.CPU == nil || !spec.Domain.CPU.DedicatedCPUPlacement {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s requires DedicatedCPUPlacement", cpusField.String()),
+				Field:   cpusField.String(),
+			})
+			continue
+		}
+		cpus, err := hardware.ParseCPUSetLine(ioThread.CPUs)
+		if err != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not a valid CPU set: %v", cpusField.String(), err),
+				Field:   cpusField.String(),
+			})
+			continue
+		}
+		for _, cpu := range cpus {
+			if cpu < 0 || (vCPUs > 0 && int64(cpu) >= vCPUs) {
+				causes = append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s references vCPU %d, but the vmi has %d vCPUs", cpusField.String(), cpu, vCPUs),
+					Field:   cpusField.String(),
+				})
+				break
+			}
+		}
+	}
+
+	for idx, disk := range spec.Domain.Devices.Disks {
+		if disk.IOThread == nil {
+			continue
+		}
+		diskField := field.Child("domain", "devices", "disks").Index(idx)
+		if !manual {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s requires the %s IOThreadsPolicy", diskField.Child("ioThread").String(), v1.IOThreadsPolicyManual),
+				Field:   diskField.Child("ioThread").String(),
+			})
+			continue
+		}
+		if !ioThreadIDs[*disk.IOThread] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s references the unknown IOThread %d", diskField.Child("ioThread").String(), *disk.IOThread),
+				Field:   diskField.Child("ioThread").String(),
+			})
+		}
+		if disk.DedicatedIOThread != nil && *disk.DedicatedIOThread {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s and %s are mutually exclusive", diskField.Child("ioThread").String(), diskField.Child("dedicatedIOThread").String()),
+				Field:   diskField.Child("dedicatedIOThread").String(),
+			})
+		}
+	}
+
+	return causes
+}
+
+func validateRealtime(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "cpu", "realtime")
+	realtime := spec.Domain.CPU.Realtime
+
+	if !spec.Domain.CPU.DedicatedCPUPlacement {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires DedicatedCPUPlacement", field.String()),
+			Field:   field.String(),
+		})
+	}
+	if spec.Domain.Memory == nil || spec.Domain.Memory.Hugepages == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires hugepages", field.String()),
+			Field:   field.String(),
+		})
+	}
+	if realtime.Priority != nil && (*realtime.Priority < 1 || *realtime.Priority > 99) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be between 1 and 99", field.Child("priority").String()),
+			Field:   field.Child("priority").String(),
+		})
+	}
+
+	vCPUs := hardware.GetNumberOfVCPUs(spec.Domain.CPU)
+	if vCPUs == 0 {
+		vCPUs = spec.Domain.Resources.Requests.Cpu().Value()
+	}
+	if vCPUs == 0 {
+		vCPUs = spec.Domain.Resources.Limits.Cpu().Value()
+	}
+
+	realtimeVCPUs := vCPUs
+	if realtime.Mask != "" {
+		maskField := field.Child("mask")
+		cpus, err := hardware.ParseCPUSetLine(realtime.Mask)
+		if err != nil {
+			return append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not a valid CPU set: %v", maskField.String(), err),
+				Field:   maskField.String(),
+			})
+		}
+		for _, cpu := range cpus {
+			if cpu < 0 || (vCPUs > 0 && int64(cpu) >= vCPUs) {
+				return append(causes, metav1.StatusCause{
+					Type:    metav1.CauseTypeFieldValueInvalid,
+					Message: fmt.Sprintf("%s references vCPU %d, but the vmi has %d vCPUs", maskField.String(), cpu, vCPUs),
+					Field:   maskField.String(),
+				})
+			}
+		}
+		realtimeVCPUs = int64(len(cpus))
+	}
+
+	// the emulator thread needs a pCPU which is not used by a realtime vCPU
+	isolated := spec.Domain.CPU.IsolateEmulatorThread ||
+		(spec.Domain.CPU.EmulatorThreadPolicy != nil && *spec.Domain.CPU.EmulatorThreadPolicy == v1.EmulatorThreadPolicyIsolate)
+	if !isolated && realtimeVCPUs >= vCPUs {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s covers all vCPUs, which requires the %s emulatorThreadPolicy", field.String(), v1.EmulatorThreadPolicyIsolate),
+			Field:   field.String(),
+		})
+	}
+
+	return causes
+}
+
+func validateNUMAPassthrough(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "cpu", "numa", "guestMappingPassthrough")
+
+	if !config.NUMAEnabled() {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s feature gate is not enabled in kubevirt-config", virtconfig.NUMAGate),
+			Field:   field.String(),
+		})
+	}
+	if !spec.Domain.CPU.DedicatedCPUPlacement {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires DedicatedCPUPlacement", field.String()),
+			Field:   field.String(),
+		})
+	}
+	if spec.Domain.Memory == nil || spec.Domain.Memory.Hugepages == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires hugepages", field.String()),
+			Field:   field.String(),
+		})
+		return causes
+	}
+
+	// the memory of every guest NUMA cell is made of hugepages
+	if guest := spec.Domain.Memory.Guest; guest != nil {
+		pageSize, err := resource.ParseQuantity(spec.Domain.Memory.Hugepages.PageSize)
+		if err == nil && guest.Value()%pageSize.Value() != 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type: metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s '%s' is not a multiple of the page size %s '%s'",
+					specField.Child("domain", "memory", "guest").String(),
+					guest.String(),
+					specField.Child("domain", "hugepages", "size").String(),
+					spec.Domain.Memory.Hugepages.PageSize,
+				),
+				Field: specField.Child("domain", "memory", "guest").String(),
+			})
+		}
+	}
+	return causes
+}
+
+func validateHostDevices(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "devices", "hostDevices")
+
+	if !config.HostDevicesPassthroughEnabled() {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s feature gate is not enabled in kubevirt-config", virtconfig.HostDevicesGate),
+			Field:   field.String(),
+		})
+	}
+
+	permitted := map[string]bool{}
+	if permittedHostDevices := config.GetPermittedHostDevices(); permittedHostDevices != nil {
+		for _, pciDev := range permittedHostDevices.PciHostDevices {
+			permitted[pciDev.ResourceName] = true
+		}
+		for _, mdev := range permittedHostDevices.MediatedDevices {
+			permitted[mdev.ResourceName] = true
+		}
+	}
+
+	names := map[string]bool{}
+	for idx, hostDev := range spec.Domain.Devices.HostDevices {
+		if names[hostDev.Name] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("%s has a duplicate name %s", field.Index(idx).String(), hostDev.Name),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+		names[hostDev.Name] = true
+
+		if !permitted[hostDev.DeviceName] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is not a permitted host device in kubevirt-config", hostDev.DeviceName),
+				Field:   field.Index(idx).Child("deviceName").String(),
+			})
+		}
+	}
+	return causes
+}
+
+func validateTPM(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "devices", "tpm")
+
+	if !config.TPMEnabled() {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s feature gate is not enabled in kubevirt-config", virtconfig.TPMGate),
+			Field:   field.String(),
+		})
+	}
+
+	claimName := spec.Domain.Devices.TPM.PersistentStateClaimName
+	if claimName == "" {
+		return causes
+	}
+
+	// the TPM state must not be exposed to the guest as a disk
+	for idx, volume := range spec.Volumes {
+		if (volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName) ||
+			(volume.DataVolume != nil && volume.DataVolume.Name == claimName) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s claim %s is already used by %s", field.Child("persistentStateClaimName").String(), claimName, specField.Child("volumes").Index(idx).String()),
+				Field:   field.Child("persistentStateClaimName").String(),
+			})
+		}
+	}
+
+	return causes
+}
+
+func validateLaunchSecurity(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "launchSecurity")
+
+	if spec.Domain.LaunchSecurity.SEV == nil {
+		return causes
+	}
+
+	if !config.WorkloadEncryptionSEVEnabled() {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s feature gate is not enabled in kubevirt-config", virtconfig.SEVGate),
+			Field:   field.Child("sev").String(),
+		})
+	}
+
+	// OVMF with SecureBoot relies on SMM, which is not available to SEV guests
+	firmware := spec.Domain.Firmware
+	if firmware == nil || firmware.Bootloader == nil || firmware.Bootloader.EFI == nil ||
+		firmware.Bootloader.EFI.SecureBoot == nil || *firmware.Bootloader.EFI.SecureBoot {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires EFI with SecureBoot disabled", field.Child("sev").String()),
+			Field:   specField.Child("domain", "firmware", "bootloader").String(),
+		})
+	}
+
+	if spec.EvictionStrategy != nil && *spec.EvictionStrategy == v1.EvictionStrategyLiveMigrate && !config.SEVLiveMigrationEnabled() {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s can not be live migrated without the %s feature gate", field.Child("sev").String(), virtconfig.SEVLiveMigrationGate),
+			Field:   specField.Child("evictionStrategy").String(),
+		})
+	}
+
+	return causes
+}
+
+func validateFilesystems(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec, config *virtconfig.ClusterConfig) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "devices", "filesystems")
+
+	if !config.VirtiofsEnabled() {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s feature gate is not enabled in kubevirt-config", virtconfig.VirtIOFSGate),
+			Field:   field.String(),
+		})
+	}
+
+	volumes := map[string]*v1.Volume{}
+	for i := range spec.Volumes {
+		volumes[spec.Volumes[i].Name] = &spec.Volumes[i]
+	}
+	disks := map[string]bool{}
+	for _, disk := range spec.Domain.Devices.Disks {
+		disks[disk.Name] = true
+	}
+
+	names := map[string]bool{}
+	for idx, fs := range spec.Domain.Devices.Filesystems {
+		if names[fs.Name] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("%s has a duplicate name %s", field.Index(idx).String(), fs.Name),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+		names[fs.Name] = true
+
+		if fs.Virtiofs == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s must be set, virtio-fs is the only supported filesystem", field.Index(idx).Child("virtiofs").String()),
+				Field:   field.Index(idx).Child("virtiofs").String(),
+			})
+		}
+
+		volume, exists := volumes[fs.Name]
+		if !exists {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s '%s' not found.", field.Index(idx).Child("name").String(), fs.Name),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+			continue
+		}
+		if volume.ConfigMap == nil && volume.Secret == nil && volume.PersistentVolumeClaim == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s can only be mapped to a ConfigMap, Secret or PersistentVolumeClaim volume.", field.Index(idx).String()),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+		if disks[fs.Name] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s shares a volume which is already used by a disk.", field.Index(idx).String()),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+	}
+	return causes
+}
+
+func validateSound(specField *k8sfield.Path, sound *v1.SoundDevice) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "devices", "sound")
+
+	if sound.Name == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must not be empty", field.Child("name").String()),
+			Field:   field.Child("name").String(),
+		})
+	}
+	if sound.Model != "" && sound.Model != "ich9" && sound.Model != "ac97" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s '%s' is not supported, use ich9 or ac97", field.Child("model").String(), sound.Model),
+			Field:   field.Child("model").String(),
+		})
+	}
+	return causes
+}
+
+func validatePanic(specField *k8sfield.Path, spec *v1.VirtualMachineInstanceSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "devices", "panic", "crashDumpClaimName")
+
+	claimName := spec.Domain.Devices.Panic.CrashDumpClaimName
+	if claimName == "" {
+		return causes
+	}
+
+	// the crash dump must not end up on a disk of the guest
+	for idx, volume := range spec.Volumes {
+		if (volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName) ||
+			(volume.DataVolume != nil && volume.DataVolume.Name == claimName) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s claim %s is already used by %s", field.String(), claimName, specField.Child("volumes").Index(idx).String()),
+				Field:   field.String(),
+			})
+		}
+	}
+	return causes
+}
+
+func validateCPUThreadPolicy(specField *k8sfield.Path, cpu *v1.CPU) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	field := specField.Child("domain", "cpu", "threadPolicy")
+	policy := *cpu.ThreadPolicy
+
+	if policy != v1.CPUThreadPolicyFullCores && policy != v1.CPUThreadPolicyIsolate {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s '%s' is not supported, use %s or %s", field.String(), policy, v1.CPUThreadPolicyFullCores, v1.CPUThreadPolicyIsolate),
+			Field:   field.String(),
+		})
+	}
+	if !cpu.DedicatedCPUPlacement {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires DedicatedCPUPlacement", field.String()),
+			Field:   field.String(),
+		})
+	}
+	if hardware.GetNumberOfVCPUs(cpu) == 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires the CPU topology of the guest", field.String()),
+			Field:   field.String(),
+		})
+	}
+	// the guest sees the same hyperthreads as the node with full cores and none with isolated threads
+	if policy == v1.CPUThreadPolicyFullCores && cpu.Threads < 2 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s %s requires at least 2 %s", field.String(), policy, specField.Child("domain", "cpu", "threads").String()),
+			Field:   specField.Child("domain", "cpu", "threads").String(),
+		})
+	}
+	if policy == v1.CPUThreadPolicyIsolate && cpu.Threads > 1 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s %s does not support more than 1 %s", field.String(), policy, specField.Child("domain", "cpu", "threads").String()),
+			Field:   specField.Child("domain", "cpu", "threads").String(),
+		})
+	}
+	return causes
+}
+
+func validateTopologySpreadConstraints(specField *k8sfield.Path, constraints []k8sv1.TopologySpreadConstraint) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	for idx, constraint := range constraints {
+		field := specField.Child("topologySpreadConstraints").Index(idx)
+
+		if constraint.MaxSkew <= 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be greater than zero", field.Child("maxSkew").String()),
+				Field:   field.Child("maxSkew").String(),
+			})
+		}
+		if constraint.TopologyKey == "" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueRequired,
+				Message: fmt.Sprintf("%s must not be empty", field.Child("topologyKey").String()),
+				Field:   field.Child("topologyKey").String(),
+			})
+		}
+		if constraint.WhenUnsatisfiable != k8sv1.DoNotSchedule && constraint.WhenUnsatisfiable != k8sv1.ScheduleAnyway {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("%s '%s' is not supported, use %s or %s", field.Child("whenUnsatisfiable").String(), constraint.WhenUnsatisfiable, k8sv1.DoNotSchedule, k8sv1.ScheduleAnyway),
+				Field:   field.Child("whenUnsatisfiable").String(),
+			})
+		}
+	}
+	return causes
+}
+
+func validateDevices(field *k8sfield.Path, devices *v1.Devices) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	causes = append(causes, validateDisks(field.Child("disks"), devices.Disks)...)
+	return causes
+}
+
+func getNumberOfPodInterfaces(spec *v1.VirtualMachineInstanceSpec) int {
+	nPodInterfaces := 0
+	for _, net := range spec.Networks {
+		if net.Pod != nil {
+			for _, iface := range spec.Domain.Devices.Interfaces {
+				if iface.Name == net.Name {
+					nPodInterfaces++
+					break // we maintain 1-to-1 relationship between networks and interfaces
+				}
+			}
+		}
+	}
+	return nPodInterfaces
+}
+
+func validateDisks(field *k8sfield.Path, disks []v1.Disk) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	nameMap := make(map[string]int)
+
+	if len(disks) > arrayLenMax {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s list exceeds the %d element limit in length", field.String(), arrayLenMax),
+			Field:   field.String(),
+		})
+		// We won't process anything over the limit
+		return causes
+	}
+
+	for idx, disk := range disks {
+		// verify name is unique
+		otherIdx, ok := nameMap[disk.Name]
+		if !ok {
+			nameMap[disk.Name] = idx
+		} else {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s and %s must not have the same Name.", field.Index(idx).String(), field.Index(otherIdx).String()),
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+
+		// Reject Floppy disks
+		if disk.Floppy != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: "Floppy disks are deprecated and will be removed from the API soon.",
+				Field:   field.Index(idx).Child("name").String(),
+			})
+		}
+
+		// Verify only a single device type is set.
+		deviceTargetSetCount := 0
+		var diskType, bus string
+		if disk.Disk != nil {
+			deviceTargetSetCount++
+			diskType = "disk"
+			bus = disk.Disk.Bus
+		}
+		if disk.LUN != nil {
+			deviceTargetSetCount++
+			diskType = "lun"
+			bus = disk.LUN.Bus
+		}
+		if disk.Floppy != nil {
+			deviceTargetSetCount++
+		}
+		if disk.CDRom != nil {
+			deviceTargetSetCount++
+			diskType = "cdrom"
+			bus = disk.CDRom.Bus
+		}
+
+		// NOTE: not setting a device target is okay. We default to Disk.
 		// However, only a single device target is allowed to be set at a time.
 		if deviceTargetSetCount > 1 {
 			causes = append(causes, metav1.StatusCause{
@@ -1535,6 +2792,15 @@ func validateDisks(field *k8sfield.Path, disks []v1.Disk) []metav1.StatusCause {
 			}
 		}
 
+		// Verify the queues can be tuned on the bus of the disk
+		if disk.Queues != nil {
+			causes = append(causes, validateDiskQueues(field.Index(idx), diskType, bus, disk.Queues)...)
+		}
+
+		if disk.IOTune != nil {
+			causes = append(causes, validateDiskIOTune(field.Index(idx).Child("ioTune"), disk.IOTune)...)
+		}
+
 		// Verify boot order is greater than 0, if provided
 		if disk.BootOrder != nil && *disk.BootOrder < 1 {
 			causes = append(causes, metav1.StatusCause{
@@ -1623,3 +2889,316 @@ func validateDisks(field *k8sfield.Path, disks []v1.Disk) []metav1.StatusCause {
 
 	return causes
 }
+
+// validateAllowedAddresses verifies the additional addresses a guest may
+// send from, e.g. the virtual IPs and MACs of a VRRP cluster.
+func validateAllowedAddresses(field *k8sfield.Path, iface *v1.Interface) (causes []metav1.StatusCause) {
+	if iface.Bridge == nil && iface.SRIOV == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: allowed addresses are only supported with the bridge and SR-IOV bindings", iface.Name),
+			Field:   field.Child("allowedAddresses").String(),
+		})
+	}
+
+	seenMACs := map[string]bool{}
+	for i, mac := range iface.AllowedAddresses.MACAddresses {
+		hwAddr, err := net.ParseMAC(mac)
+		if err != nil || len(hwAddr) != 6 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("allowed MAC address %s is not a valid MAC-48 address", mac),
+				Field:   field.Child("allowedAddresses", "macAddresses").Index(i).String(),
+			})
+			continue
+		}
+		if hwAddr[0]&1 == 1 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("allowed MAC address %s must be a unicast address", mac),
+				Field:   field.Child("allowedAddresses", "macAddresses").Index(i).String(),
+			})
+		}
+		if seenMACs[hwAddr.String()] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("allowed MAC address %s is listed more than once", mac),
+				Field:   field.Child("allowedAddresses", "macAddresses").Index(i).String(),
+			})
+		}
+		seenMACs[hwAddr.String()] = true
+	}
+
+	if len(iface.AllowedAddresses.IPAddresses) > 0 && iface.Bridge == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: allowed IP addresses are only supported with the bridge binding", iface.Name),
+			Field:   field.Child("allowedAddresses", "ipAddresses").String(),
+		})
+	}
+	for i, ip := range iface.AllowedAddresses.IPAddresses {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("allowed IP address %s is neither a valid IP address nor a CIDR", ip),
+				Field:   field.Child("allowedAddresses", "ipAddresses").Index(i).String(),
+			})
+		}
+	}
+
+	return causes
+}
+
+// validatePasstPorts verifies that every port of a passt interface is
+// declared once per protocol, passt forwards each of them to the guest
+func validatePasstPorts(field *k8sfield.Path, iface *v1.Interface) (causes []metav1.StatusCause) {
+	seen := map[string]bool{}
+	for i, port := range iface.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		key := fmt.Sprintf("%d/%s", port.Port, protocol)
+		if seen[key] {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("interface %s: port %s is declared more than once", iface.Name, key),
+				Field:   field.Child("ports").Index(i).String(),
+			})
+		}
+		seen[key] = true
+	}
+	return causes
+}
+
+// validateDHCPOverrides verifies the options which replace or extend what the
+// DHCP server of virt-launcher learned from the pod interface
+func validateDHCPOverrides(field *k8sfield.Path, options *v1.DHCPOptions) (causes []metav1.StatusCause) {
+	if options.MTU != nil && (*options.MTU < 68 || *options.MTU > 65535) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "MTU must be in range 68 to 65535",
+			Field:   field.Child("mtu").String(),
+		})
+	}
+
+	for i, route := range options.Routes {
+		_, dst, err := net.ParseCIDR(route.Destination)
+		if err != nil || dst.IP.To4() == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("route destination %s must be an IPv4 network in CIDR notation", route.Destination),
+				Field:   field.Child("routes").Index(i).Child("destination").String(),
+			})
+		}
+		if net.ParseIP(route.Gateway).To4() == nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("route gateway %s must be an IPv4 address", route.Gateway),
+				Field:   field.Child("routes").Index(i).Child("gateway").String(),
+			})
+		}
+	}
+
+	// each domain is encoded with a length byte per label and a terminating byte,
+	// all of them have to fit into a single DHCP option
+	encodedLen := 0
+	for i, domain := range options.SearchDomains {
+		for _, msg := range validation.IsDNS1123Subdomain(domain) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("search domain %s is invalid: %s", domain, msg),
+				Field:   field.Child("searchDomains").Index(i).String(),
+			})
+		}
+		encodedLen += len(domain) + 2
+	}
+	if encodedLen > 255 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "search domains exceed the maximum length of a DHCP option",
+			Field:   field.Child("searchDomains").String(),
+		})
+	}
+	return causes
+}
+
+// validateInterfaceBandwidth verifies the rate limits of an interface, which are applied
+// to its tap device and therefore only work with the bridge and masquerade bindings
+func validateInterfaceBandwidth(field *k8sfield.Path, iface *v1.Interface) (causes []metav1.StatusCause) {
+	if iface.Bridge == nil && iface.Masquerade == nil {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: bandwidth limits are only supported with the bridge and masquerade bindings", iface.Name),
+			Field:   field.Child("bandwidth").String(),
+		})
+	}
+
+	validateLimit := func(field *k8sfield.Path, limit *v1.BandwidthLimit) {
+		if limit.Average == 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "average must be greater than 0",
+				Field:   field.Child("average").String(),
+			})
+		}
+		if limit.Peak != nil && *limit.Peak < limit.Average {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "peak must not be lower than average, if supplied",
+				Field:   field.Child("peak").String(),
+			})
+		}
+		if limit.Burst != nil && *limit.Burst == 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "burst must be greater than 0, if supplied",
+				Field:   field.Child("burst").String(),
+			})
+		}
+	}
+
+	if limit := iface.Bandwidth.Ingress; limit != nil {
+		validateLimit(field.Child("bandwidth", "ingress"), limit)
+	}
+	if limit := iface.Bandwidth.Egress; limit != nil {
+		validateLimit(field.Child("bandwidth", "egress"), limit)
+		// the sent traffic is policed, which knows no peak rate
+		if limit.Peak != nil {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: "a peak rate is not supported for the egress traffic",
+				Field:   field.Child("bandwidth", "egress", "peak").String(),
+			})
+		}
+	}
+	return causes
+}
+
+// validateSRIOVFailoverStandby verifies that the standby of an SR-IOV interface is a virtio
+// interface with the bridge binding and the same MAC address, which net_failover needs to
+// bond both interfaces in the guest
+func validateSRIOVFailoverStandby(field *k8sfield.Path, iface *v1.Interface, ifaces []v1.Interface) (causes []metav1.StatusCause) {
+	standbyField := field.Child("sriov", "failoverStandby")
+
+	var standby *v1.Interface
+	for i := range ifaces {
+		if ifaces[i].Name == iface.SRIOV.FailoverStandby {
+			standby = &ifaces[i]
+			break
+		}
+	}
+	if standby == nil {
+		return append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: failover standby interface %s not found", iface.Name, iface.SRIOV.FailoverStandby),
+			Field:   standbyField.String(),
+		})
+	}
+
+	if standby.Bridge == nil || (standby.Model != "" && standby.Model != "virtio") {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: the failover standby %s must be a virtio interface with the bridge binding", iface.Name, standby.Name),
+			Field:   standbyField.String(),
+		})
+	}
+	for _, other := range ifaces {
+		if other.Name != iface.Name && other.SRIOV != nil && other.SRIOV.FailoverStandby == standby.Name {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueDuplicate,
+				Message: fmt.Sprintf("interface %s: the failover standby %s is already used by interface %s", iface.Name, standby.Name, other.Name),
+				Field:   standbyField.String(),
+			})
+			break
+		}
+	}
+	if iface.MacAddress == "" || !strings.EqualFold(iface.MacAddress, standby.MacAddress) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("interface %s: the failover standby %s must use the same MAC address", iface.Name, standby.Name),
+			Field:   field.Child("macAddress").String(),
+		})
+	}
+	return causes
+}
+
+// validateDiskIOTune rejects limits of zero, which libvirt treats as unlimited, and the
+// combination of a total limit with the read and write limits of the same kind
+func validateDiskIOTune(field *k8sfield.Path, ioTune *v1.DiskIOTune) (causes []metav1.StatusCause) {
+	limits := []struct {
+		name  string
+		value *uint64
+	}{
+		{"totalBytesSec", ioTune.TotalBytesSec},
+		{"readBytesSec", ioTune.ReadBytesSec},
+		{"writeBytesSec", ioTune.WriteBytesSec},
+		{"totalIopsSec", ioTune.TotalIopsSec},
+		{"readIopsSec", ioTune.ReadIopsSec},
+		{"writeIopsSec", ioTune.WriteIopsSec},
+	}
+	for _, limit := range limits {
+		if limit.value != nil && *limit.value == 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be greater than 0", field.Child(limit.name).String()),
+				Field:   field.Child(limit.name).String(),
+			})
+		}
+	}
+
+	if ioTune.TotalBytesSec != nil && (ioTune.ReadBytesSec != nil || ioTune.WriteBytesSec != nil) {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s can not be combined with %s and %s", field.Child("totalBytesSec").String(),
+				field.Child("readBytesSec").String(), field.Child("writeBytesSec").String()),
+			Field: field.Child("totalBytesSec").String(),
+		})
+	}
+	if ioTune.TotalIopsSec != nil && (ioTune.ReadIopsSec != nil || ioTune.WriteIopsSec != nil) {
+		causes = append(causes, metav1.StatusCause{
+			Type: metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s can not be combined with %s and %s", field.Child("totalIopsSec").String(),
+				field.Child("readIopsSec").String(), field.Child("writeIopsSec").String()),
+			Field: field.Child("totalIopsSec").String(),
+		})
+	}
+	return causes
+}
+
+func validateDiskQueues(field *k8sfield.Path, diskType string, bus string, queues *v1.DiskQueues) (causes []metav1.StatusCause) {
+	if bus != "" && bus != "virtio" && bus != "scsi" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s sets queues, which are only supported with the virtio and scsi buses", field.String()),
+			Field:   field.Child("queues").String(),
+		})
+		return causes
+	}
+	if queues.Count != nil && (*queues.Count < 1 || *queues.Count > maxDiskQueues) {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s must be between 1 and %d", field.Child("queues", "count").String(), maxDiskQueues),
+			Field:   field.Child("queues", "count").String(),
+		})
+	}
+	if queues.Size != nil {
+		if bus == "scsi" {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s is only supported with the virtio bus", field.Child("queues", "size").String()),
+				Field:   field.Child("queues", "size").String(),
+			})
+		} else if size := *queues.Size; size < 2 || size > maxDiskQueueSize || size&(size-1) != 0 {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: fmt.Sprintf("%s must be a power of 2 between 2 and %d", field.Child("queues", "size").String(), maxDiskQueueSize),
+				Field:   field.Child("queues", "size").String(),
+			})
+		}
+	}
+	return causes
+}
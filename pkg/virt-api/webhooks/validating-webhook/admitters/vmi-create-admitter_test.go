@@ -22,9 +22,11 @@ package admitters
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	rt "runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"kubevirt.io/kubevirt/pkg/virt-operator/creation/rbac"
 
@@ -40,10 +42,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/kubevirt/pkg/hooks"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/util"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
@@ -117,6 +121,120 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 		Expect(resp.Result.Message).To(ContainSubstring("no memory requested"))
 	})
 
+	Context("with resource quotas", func() {
+		var quota *k8sv1.ResourceQuota
+
+		admit := func(vmi *v1.VirtualMachineInstance) *v1beta1.AdmissionResponse {
+			vmiBytes, _ := json.Marshal(vmi)
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Namespace: k8sv1.NamespaceDefault,
+					Resource:  webhooks.VirtualMachineInstanceGroupVersionResource,
+					Object: runtime.RawExtension{
+						Raw: vmiBytes,
+					},
+				},
+			}
+			return vmiCreateAdmitter.Admit(ar)
+		}
+
+		BeforeEach(func() {
+			quota = &k8sv1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "memory", Namespace: k8sv1.NamespaceDefault},
+				Status: k8sv1.ResourceQuotaStatus{
+					Hard: k8sv1.ResourceList{k8sv1.ResourceRequestsMemory: resource.MustParse("2Gi")},
+					Used: k8sv1.ResourceList{k8sv1.ResourceRequestsMemory: resource.MustParse("1Gi")},
+				},
+			}
+			Expect(webhooks.GetInformers().ResourceQuotaInformer.GetStore().Add(quota)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(webhooks.GetInformers().ResourceQuotaInformer.GetStore().Delete(quota)).To(Succeed())
+		})
+
+		It("should accept VMIs whose virt-launcher pod fits into the quota", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("512Mi")
+
+			resp := admit(vmi)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject VMIs whose virt-launcher pod overhead exceeds the quota", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("1Gi")
+
+			resp := admit(vmi)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(Equal(int32(http.StatusForbidden)))
+			Expect(resp.Result.Message).To(ContainSubstring("exceeded quota: memory"))
+			Expect(resp.Result.Message).To(ContainSubstring("(guest: 1Gi, overhead: "))
+		})
+
+		It("should ignore the quotas of other namespaces", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("1Gi")
+			vmi.Namespace = "other"
+
+			vmiBytes, _ := json.Marshal(vmi)
+			resp := vmiCreateAdmitter.Admit(&v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Namespace: "other",
+					Resource:  webhooks.VirtualMachineInstanceGroupVersionResource,
+					Object:    runtime.RawExtension{Raw: vmiBytes},
+				},
+			})
+			Expect(resp.Allowed).To(BeTrue())
+		})
+	})
+
+	Context("with a non-root VMI", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		admit := func(vmi *v1.VirtualMachineInstance) *v1beta1.AdmissionResponse {
+			vmiBytes, _ := json.Marshal(vmi)
+			return vmiCreateAdmitter.Admit(&v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Resource: webhooks.VirtualMachineInstanceGroupVersionResource,
+					Object:   runtime.RawExtension{Raw: vmiBytes},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			enableFeatureGate(virtconfig.PasstGate)
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Status.RuntimeUser = util.NonRootUID
+		})
+
+		It("should accept a passt interface", func() {
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+				Name:                   "default",
+				InterfaceBindingMethod: v1.InterfaceBindingMethod{Passt: &v1.InterfacePasst{}},
+			}}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+
+			resp := admit(vmi)
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject interfaces which rewire the pod network", func() {
+			resp := admit(vmi)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.domain.devices.autoattachPodInterface"))
+
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			resp = admit(vmi)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.domain.devices.interfaces[0]"))
+			Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("is not supported with the NonRoot feature gate"))
+		})
+	})
+
 	Context("tolerations with eviction policies given", func() {
 		var vmi *v1.VirtualMachineInstance
 		var policy = v1.EvictionStrategyLiveMigrate
@@ -132,6 +250,9 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(resp).To(BeEmpty())
 		},
 			table.Entry("migration policy to be set", v1.EvictionStrategyLiveMigrate),
+			table.Entry("migration if possible policy to be set", v1.EvictionStrategyLiveMigrateIfPossible),
+			table.Entry("external policy to be set", v1.EvictionStrategyExternal),
+			table.Entry("none policy to be set", v1.EvictionStrategyNone),
 		)
 
 		It("should block setting eviction policies if the feature gate is disabled", func() {
@@ -141,6 +262,16 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(resp[0].Message).To(ContainSubstring("LiveMigration feature gate is not enabled"))
 		})
 
+		table.DescribeTable("should allow eviction policies which do not migrate if the feature gate is disabled", func(policy v1.EvictionStrategy) {
+			disableFeatureGates()
+			vmi.Spec.EvictionStrategy = &policy
+			resp := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(resp).To(BeEmpty())
+		},
+			table.Entry("external policy", v1.EvictionStrategyExternal),
+			table.Entry("none policy", v1.EvictionStrategyNone),
+		)
+
 		It("should allow no eviction policy to be set", func() {
 			vmi.Spec.EvictionStrategy = nil
 			resp := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
@@ -176,7 +307,7 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			}
 			resp := vmiCreateAdmitter.Admit(ar)
 			Expect(resp.Allowed).To(BeFalse())
-			Expect(resp.Result.Message).To(Equal(`either spec.readinessProbe.tcpSocket or spec.readinessProbe.httpGet must be set if a spec.readinessProbe is specified, either spec.livenessProbe.tcpSocket or spec.livenessProbe.httpGet must be set if a spec.livenessProbe is specified`))
+			Expect(resp.Result.Message).To(Equal(`either spec.readinessProbe.tcpSocket, spec.readinessProbe.httpGet or spec.readinessProbe.guestAgentExec must be set if a spec.readinessProbe is specified, either spec.livenessProbe.tcpSocket, spec.livenessProbe.httpGet or spec.livenessProbe.guestAgentExec must be set if a spec.livenessProbe is specified`))
 		})
 		It("should reject probes with more than one action per probe configured", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
@@ -270,6 +401,116 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(resp.Allowed).To(BeFalse())
 			Expect(resp.Result.Message).To(Equal(`spec.livenessProbe is only allowed if the Pod Network is attached, spec.readinessProbe is only allowed if the Pod Network is attached`))
 		})
+		It("should accept guest agent exec probes without a Pod Network", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.ReadinessProbe = &v1.Probe{
+				Handler: v1.Handler{
+					GuestAgentExec: &v1.GuestAgentExecAction{Command: []string{"/usr/bin/systemctl", "is-active", "httpd"}},
+				},
+			}
+			vmi.Spec.LivenessProbe = &v1.Probe{
+				Handler: v1.Handler{
+					GuestAgentExec: &v1.GuestAgentExecAction{Command: []string{"/bin/true"}},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+		table.DescribeTable("should reject invalid guest agent exec probes", func(handler v1.Handler, message string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.ReadinessProbe = &v1.Probe{Handler: handler}
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal(message))
+		},
+			table.Entry("without a command",
+				v1.Handler{GuestAgentExec: &v1.GuestAgentExecAction{}},
+				"spec.readinessProbe.guestAgentExec.command must not be empty",
+			),
+			table.Entry("combined with another action",
+				v1.Handler{
+					GuestAgentExec: &v1.GuestAgentExecAction{Command: []string{"/bin/true"}},
+					HTTPGet:        &k8sv1.HTTPGetAction{Host: "test", Port: intstr.Parse("80")},
+				},
+				"spec.readinessProbe must have exactly one probe type set",
+			),
+		)
+	})
+
+	Context("with lifecycle hooks", func() {
+		It("should accept exec and http hooks", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Lifecycle = &v1.VirtualMachineInstanceLifecycle{
+				PostStart: &v1.LifecycleHandler{
+					Exec: &k8sv1.ExecAction{Command: []string{"/usr/bin/register"}},
+				},
+				PreStop: &v1.LifecycleHandler{
+					HTTPGet:        &k8sv1.HTTPGetAction{Path: "/drain", Port: intstr.FromInt(8080)},
+					TimeoutSeconds: 60,
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		table.DescribeTable("should reject invalid hooks", func(handler *v1.LifecycleHandler, message string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Lifecycle = &v1.VirtualMachineInstanceLifecycle{PreStop: handler}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal(message))
+		},
+			table.Entry("without an action",
+				&v1.LifecycleHandler{},
+				"either spec.lifecycle.preStop.exec or spec.lifecycle.preStop.httpGet must be set if a spec.lifecycle.preStop is specified",
+			),
+			table.Entry("with two actions",
+				&v1.LifecycleHandler{
+					Exec:    &k8sv1.ExecAction{Command: []string{"/bin/true"}},
+					HTTPGet: &k8sv1.HTTPGetAction{Port: intstr.FromInt(8080)},
+				},
+				"spec.lifecycle.preStop must have exactly one handler type set",
+			),
+			table.Entry("with an empty command",
+				&v1.LifecycleHandler{Exec: &k8sv1.ExecAction{}},
+				"spec.lifecycle.preStop.exec.command must not be empty",
+			),
+			table.Entry("with a named port",
+				&v1.LifecycleHandler{HTTPGet: &k8sv1.HTTPGetAction{Port: intstr.FromString("http")}},
+				"spec.lifecycle.preStop.httpGet.port must be a port number between 1 and 65535",
+			),
+			table.Entry("with a negative timeout",
+				&v1.LifecycleHandler{Exec: &k8sv1.ExecAction{Command: []string{"/bin/true"}}, TimeoutSeconds: -1},
+				"spec.lifecycle.preStop.timeoutSeconds must not be negative",
+			),
+		)
+	})
+
+	Context("with I/O error recovery", func() {
+		It("should accept automatic resumes", func() {
+			_true := true
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.IOErrorRecovery = &v1.IOErrorRecovery{AutoResume: &_true, RetryIntervalSeconds: 30}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should reject a negative retry interval", func() {
+			_true := true
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.IOErrorRecovery = &v1.IOErrorRecovery{AutoResume: &_true, RetryIntervalSeconds: -1}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("spec"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("spec.ioErrorRecovery.retryIntervalSeconds"))
+		})
 	})
 
 	It("should accept valid vmi spec on create", func() {
@@ -426,6 +667,27 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 				virtconfig.SidecarGate,
 			),
 		)
+
+		table.DescribeTable("should validate the SELinux workload class", func(class string, valid bool) {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.SELinuxLauncherTypesKey: "database: virt_launcher_db_t"},
+			})
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.ObjectMeta = metav1.ObjectMeta{
+				Annotations: map[string]string{v1.SELinuxWorkloadClassAnnotation: class},
+			}
+			causes := ValidateVirtualMachineInstanceMetadata(k8sfield.NewPath("metadata"), &vmi.ObjectMeta, config, "fake-account")
+			if valid {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Message).To(ContainSubstring("unknown SELinux workload class 'unknown'"))
+			}
+		},
+			table.Entry("accept a configured class", "database", true),
+			table.Entry("accept a built-in class", v1.SELinuxWorkloadClassVFIO, true),
+			table.Entry("reject an unknown class", "unknown", false),
+		)
 	})
 
 	Context("with VirtualMachineInstance spec", func() {
@@ -1291,6 +1553,158 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(causes[0].Field).To(Equal("fake.networks"))
 			Expect(causes[0].Message).To(Equal("Pod network cannot be defined when Multus default network is defined"))
 		})
+		Context("with an OVN-Kubernetes secondary network", func() {
+			newOVNVMI := func(ovn *v1.OVNNetwork) *v1.VirtualMachineInstance {
+				vm := v1.NewMinimalVMI("testvm")
+				vm.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+				vm.Spec.Domain.Devices.Interfaces[0].Name = "ovn"
+				vm.Spec.Networks = []v1.Network{
+					v1.Network{
+						Name: "ovn",
+						NetworkSource: v1.NetworkSource{
+							Multus: &v1.MultusNetwork{NetworkName: "localnet", OVN: ovn},
+						},
+					},
+				}
+				return vm
+			}
+			vlan := func(id int32) *int32 {
+				return &id
+			}
+
+			It("should accept a localnet network with a VLAN", func() {
+				vm := newOVNVMI(&v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet, VLAN: vlan(100)})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(BeEmpty())
+			})
+
+			table.DescribeTable("should reject", func(ovn *v1.OVNNetwork, field string, message string) {
+				vm := newOVNVMI(ovn)
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(field))
+				Expect(causes[0].Message).To(ContainSubstring(message))
+			},
+				table.Entry("an unknown topology", &v1.OVNNetwork{Topology: "layer3"},
+					"fake.networks[0].multus.ovn.topology", "must be one of localnet, layer2"),
+				table.Entry("a VLAN on a layer2 network", &v1.OVNNetwork{Topology: v1.OVNTopologyLayer2, VLAN: vlan(100)},
+					"fake.networks[0].multus.ovn.vlan", "only supported with the localnet topology"),
+				table.Entry("a VLAN out of range", &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet, VLAN: vlan(4095)},
+					"fake.networks[0].multus.ovn.vlan", "must be in range 1 to 4094"),
+			)
+
+			It("should reject the network as the multus default", func() {
+				vm := newOVNVMI(&v1.OVNNetwork{Topology: v1.OVNTopologyLayer2})
+				vm.Spec.Networks[0].Multus.Default = true
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.networks[0].multus.ovn"))
+			})
+
+			It("should reject bindings other than bridge", func() {
+				vm := newOVNVMI(&v1.OVNNetwork{Topology: v1.OVNTopologyLayer2})
+				vm.Spec.Domain.Devices.Interfaces[0].InterfaceBindingMethod = v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}}
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].name"))
+				Expect(causes[0].Message).To(Equal("OVN-Kubernetes secondary networks are only supported with the bridge binding"))
+			})
+		})
+		Context("with a passt interface", func() {
+			newPasstVMI := func(network v1.NetworkSource, ports ...v1.Port) *v1.VirtualMachineInstance {
+				vm := v1.NewMinimalVMI("testvm")
+				vm.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+					Name:                   "default",
+					InterfaceBindingMethod: v1.InterfaceBindingMethod{Passt: &v1.InterfacePasst{}},
+					Ports:                  ports,
+				}}
+				vm.Spec.Networks = []v1.Network{{Name: "default", NetworkSource: network}}
+				return vm
+			}
+
+			It("should accept TCP and UDP ports on the pod network", func() {
+				enableFeatureGate(virtconfig.PasstGate)
+				vm := newPasstVMI(v1.NetworkSource{Pod: &v1.PodNetwork{}},
+					v1.Port{Port: 53}, v1.Port{Port: 53, Protocol: "UDP"})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(BeEmpty())
+			})
+
+			It("should reject the interface if the feature gate is disabled", func() {
+				vm := newPasstVMI(v1.NetworkSource{Pod: &v1.PodNetwork{}})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].passt"))
+			})
+
+			It("should reject multus networks", func() {
+				enableFeatureGate(virtconfig.PasstGate)
+				vm := newPasstVMI(v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "net"}})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Message).To(Equal("Passt interface only implemented with pod network"))
+			})
+
+			It("should reject a port declared twice for the same protocol", func() {
+				enableFeatureGate(virtconfig.PasstGate)
+				vm := newPasstVMI(v1.NetworkSource{Pod: &v1.PodNetwork{}},
+					v1.Port{Port: 80}, v1.Port{Port: 80, Protocol: "TCP"})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Type).To(Equal(metav1.CauseTypeFieldValueDuplicate))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].ports[1]"))
+			})
+		})
+
+		Context("with a macvtap interface", func() {
+			newMacvtapVMI := func(network v1.NetworkSource) *v1.VirtualMachineInstance {
+				vm := v1.NewMinimalVMI("testvm")
+				vm.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+					Name:                   "macvtap",
+					InterfaceBindingMethod: v1.InterfaceBindingMethod{Macvtap: &v1.InterfaceMacvtap{}},
+				}}
+				vm.Spec.Networks = []v1.Network{{Name: "macvtap", NetworkSource: network}}
+				return vm
+			}
+
+			It("should accept a multus secondary network", func() {
+				enableFeatureGate(virtconfig.MacvtapGate)
+				vm := newMacvtapVMI(v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "macvtap"}})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(BeEmpty())
+			})
+
+			It("should reject the interface if the feature gate is disabled", func() {
+				vm := newMacvtapVMI(v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "macvtap"}})
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].macvtap"))
+				Expect(causes[0].Message).To(Equal("Macvtap feature gate is not enabled in kubevirt-config"))
+			})
+
+			table.DescribeTable("should reject", func(network v1.NetworkSource) {
+				enableFeatureGate(virtconfig.MacvtapGate)
+				vm := newMacvtapVMI(network)
+
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vm.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].name"))
+				Expect(causes[0].Message).To(Equal("Macvtap interface only implemented with multus secondary networks"))
+			},
+				table.Entry("the pod network", v1.NetworkSource{Pod: &v1.PodNetwork{}}),
+				table.Entry("the multus default network", v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "macvtap", Default: true}}),
+			)
+		})
 		It("should reject multus network source without networkName", func() {
 			vm := v1.NewMinimalVMI("testvm")
 			vm.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
@@ -1662,6 +2076,168 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(len(causes)).To(Equal(2))
 		})
 
+		It("should accept valid DHCP overrides", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			mtu := uint32(1400)
+			vmi.Spec.Domain.Devices.Interfaces[0].DHCPOptions = &v1.DHCPOptions{
+				MTU:           &mtu,
+				Routes:        []v1.DHCPRoute{{Destination: "10.10.0.0/16", Gateway: "10.0.2.254"}},
+				SearchDomains: []string{"example.com", "svc.cluster.local"},
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		table.DescribeTable("should reject invalid DHCP overrides", func(options v1.DHCPOptions, expectedField string) {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.Interfaces[0].DHCPOptions = &options
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal(expectedField))
+		},
+			table.Entry("with a too small MTU", v1.DHCPOptions{MTU: &[]uint32{67}[0]},
+				"fake.domain.devices.interfaces[0].dhcpOptions.mtu"),
+			table.Entry("with a too large MTU", v1.DHCPOptions{MTU: &[]uint32{65536}[0]},
+				"fake.domain.devices.interfaces[0].dhcpOptions.mtu"),
+			table.Entry("with an IPv6 route destination", v1.DHCPOptions{Routes: []v1.DHCPRoute{{Destination: "fd10::/64", Gateway: "10.0.2.254"}}},
+				"fake.domain.devices.interfaces[0].dhcpOptions.routes[0].destination"),
+			table.Entry("with a route destination without prefix length", v1.DHCPOptions{Routes: []v1.DHCPRoute{{Destination: "10.10.0.0", Gateway: "10.0.2.254"}}},
+				"fake.domain.devices.interfaces[0].dhcpOptions.routes[0].destination"),
+			table.Entry("with an invalid route gateway", v1.DHCPOptions{Routes: []v1.DHCPRoute{{Destination: "10.10.0.0/16", Gateway: "gateway"}}},
+				"fake.domain.devices.interfaces[0].dhcpOptions.routes[0].gateway"),
+			table.Entry("with an invalid search domain", v1.DHCPOptions{SearchDomains: []string{"example.com", "-example.com"}},
+				"fake.domain.devices.interfaces[0].dhcpOptions.searchDomains[1]"),
+			table.Entry("with too many search domains", v1.DHCPOptions{SearchDomains: []string{
+				strings.Repeat("a", 60) + ".com", strings.Repeat("b", 60) + ".com", strings.Repeat("c", 60) + ".com", strings.Repeat("d", 60) + ".com"}},
+				"fake.domain.devices.interfaces[0].dhcpOptions.searchDomains"),
+		)
+
+		It("should accept connection limits on a masquerade interface", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			maxConnections := uint32(1000)
+			maxRate := uint32(50)
+			vmi.Spec.Domain.Devices.Interfaces[0].ConnectionLimits = &v1.ConnectionLimits{
+				MaxConnections:             &maxConnections,
+				MaxNewConnectionsPerSecond: &maxRate,
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(0))
+		})
+
+		It("should reject connection limits on a bridge interface", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			maxConnections := uint32(1000)
+			vmi.Spec.Domain.Devices.Interfaces[0].ConnectionLimits = &v1.ConnectionLimits{
+				MaxConnections: &maxConnections,
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].connectionLimits"))
+		})
+
+		It("should reject zero connection limits", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			zero := uint32(0)
+			vmi.Spec.Domain.Devices.Interfaces[0].ConnectionLimits = &v1.ConnectionLimits{
+				MaxConnections:             &zero,
+				MaxNewConnectionsPerSecond: &zero,
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(2))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].connectionLimits.maxConnections"))
+			Expect(causes[1].Field).To(Equal("fake.domain.devices.interfaces[0].connectionLimits.maxNewConnectionsPerSecond"))
+		})
+
+		It("should accept allowed addresses on a bridge interface", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.Interfaces[0].AllowedAddresses = &v1.AllowedAddresses{
+				MACAddresses: []string{"00:00:5e:00:01:0a"},
+				IPAddresses:  []string{"10.0.0.100", "fd00::100", "192.168.0.0/24"},
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(0))
+		})
+
+		It("should reject allowed IP addresses on an SR-IOV interface", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+				Name:                   "sriov",
+				InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}},
+				AllowedAddresses: &v1.AllowedAddresses{
+					MACAddresses: []string{"00:00:5e:00:01:0a"},
+					IPAddresses:  []string{"10.0.0.100"},
+				},
+			}}
+			causes := validateAllowedAddresses(k8sfield.NewPath("fake"), &vmi.Spec.Domain.Devices.Interfaces[0])
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.allowedAddresses.ipAddresses"))
+		})
+
+		It("should reject allowed addresses on a masquerade interface", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.Interfaces[0].AllowedAddresses = &v1.AllowedAddresses{
+				MACAddresses: []string{"00:00:5e:00:01:0a"},
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.interfaces[0].allowedAddresses"))
+		})
+
+		table.DescribeTable("should reject invalid allowed addresses", func(allowed v1.AllowedAddresses, expectedField string) {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.Interfaces[0].AllowedAddresses = &allowed
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal(expectedField))
+		},
+			table.Entry("with a malformed MAC address", v1.AllowedAddresses{MACAddresses: []string{"00:00:5e:00:01"}}, "fake.domain.devices.interfaces[0].allowedAddresses.macAddresses[0]"),
+			table.Entry("with an EUI-64 MAC address", v1.AllowedAddresses{MACAddresses: []string{"00:00:5e:00:01:0a:00:01"}}, "fake.domain.devices.interfaces[0].allowedAddresses.macAddresses[0]"),
+			table.Entry("with a multicast MAC address", v1.AllowedAddresses{MACAddresses: []string{"01:00:5e:00:00:12"}}, "fake.domain.devices.interfaces[0].allowedAddresses.macAddresses[0]"),
+			table.Entry("with a duplicate MAC address", v1.AllowedAddresses{MACAddresses: []string{"00:00:5e:00:01:0a", "00:00:5E:00:01:0A"}}, "fake.domain.devices.interfaces[0].allowedAddresses.macAddresses[1]"),
+			table.Entry("with a malformed IP address", v1.AllowedAddresses{IPAddresses: []string{"10.0.0.100", "10.0.0"}}, "fake.domain.devices.interfaces[0].allowedAddresses.ipAddresses[1]"),
+			table.Entry("with a malformed CIDR", v1.AllowedAddresses{IPAddresses: []string{"10.0.0.0/33"}}, "fake.domain.devices.interfaces[0].allowedAddresses.ipAddresses[0]"),
+		)
+
+		table.DescribeTable("should validate interface bandwidth limits", func(iface *v1.Interface, bandwidth v1.InterfaceBandwidth, expectedFields ...string) {
+			iface.Bandwidth = &bandwidth
+			causes := validateInterfaceBandwidth(k8sfield.NewPath("fake"), iface)
+			Expect(causes).To(HaveLen(len(expectedFields)))
+			for i, field := range expectedFields {
+				Expect(causes[i].Field).To(Equal(field))
+			}
+		},
+			table.Entry("accept limits on a bridge interface", v1.DefaultBridgeNetworkInterface(),
+				v1.InterfaceBandwidth{Ingress: &v1.BandwidthLimit{Average: 1024, Peak: &[]uint32{2048}[0], Burst: &[]uint32{256}[0]}, Egress: &v1.BandwidthLimit{Average: 512}}),
+			table.Entry("accept limits on a masquerade interface", v1.DefaultMasqueradeNetworkInterface(),
+				v1.InterfaceBandwidth{Egress: &v1.BandwidthLimit{Average: 512, Burst: &[]uint32{64}[0]}}),
+			table.Entry("reject limits on a slirp interface", v1.DefaultSlirpNetworkInterface(),
+				v1.InterfaceBandwidth{Ingress: &v1.BandwidthLimit{Average: 1024}}, "fake.bandwidth"),
+			table.Entry("reject an average of zero", v1.DefaultBridgeNetworkInterface(),
+				v1.InterfaceBandwidth{Ingress: &v1.BandwidthLimit{}}, "fake.bandwidth.ingress.average"),
+			table.Entry("reject a peak below the average", v1.DefaultBridgeNetworkInterface(),
+				v1.InterfaceBandwidth{Ingress: &v1.BandwidthLimit{Average: 1024, Peak: &[]uint32{512}[0]}}, "fake.bandwidth.ingress.peak"),
+			table.Entry("reject a burst of zero", v1.DefaultBridgeNetworkInterface(),
+				v1.InterfaceBandwidth{Egress: &v1.BandwidthLimit{Average: 1024, Burst: &[]uint32{0}[0]}}, "fake.bandwidth.egress.burst"),
+			table.Entry("reject an egress peak", v1.DefaultBridgeNetworkInterface(),
+				v1.InterfaceBandwidth{Egress: &v1.BandwidthLimit{Average: 1024, Peak: &[]uint32{2048}[0]}}, "fake.bandwidth.egress.peak"),
+		)
+
 		It("should accept valid DHCPPrivateOptions", func() {
 			vmi := v1.NewMinimalVMI("testvm")
 			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
@@ -1741,17 +2317,195 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(causes[0].Field).To(Equal("fake.domain.devices.networkInterfaceMultiqueue"))
 		})
 
-		It("should allow BlockMultiQueue with CPU settings", func() {
-			_true := true
+		table.DescribeTable("should validate persistent IPs", func(iface v1.Interface, network v1.Network, expectedCauses int) {
 			vmi := v1.NewMinimalVMI("testvm")
-			vmi.Spec.Domain.Devices.BlockMultiQueue = &_true
-			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{}
-			vmi.Spec.Domain.Resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("5")
-
+			iface.PersistentIPs = true
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{iface}
+			vmi.Spec.Networks = []v1.Network{network}
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-			Expect(len(causes)).To(Equal(0))
-		})
-
+			Expect(causes).To(HaveLen(expectedCauses))
+			for _, cause := range causes {
+				Expect(cause.Field).To(Equal("fake.domain.devices.interfaces[0].persistentIPs"))
+			}
+		},
+			table.Entry("with a bridge on a multus network",
+				v1.Interface{Name: "multus", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				v1.Network{Name: "multus", NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "net"}}}, 0),
+			table.Entry("with a bridge on the pod network", *v1.DefaultBridgeNetworkInterface(), *v1.DefaultPodNetwork(), 1),
+			table.Entry("with masquerade", *v1.DefaultMasqueradeNetworkInterface(), *v1.DefaultPodNetwork(), 1),
+		)
+
+		table.DescribeTable("should validate the failover standby of SR-IOV interfaces", func(standby v1.Interface, sriovMac string, expectedField string) {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+				{Name: "sriov", MacAddress: sriovMac, InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{FailoverStandby: "standby"}}},
+				standby,
+			}
+			vmi.Spec.Networks = []v1.Network{
+				{Name: "sriov", NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "sriov-net"}}},
+				{Name: standby.Name, NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "bridge-net"}}},
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			if expectedField == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(expectedField))
+			}
+		},
+			table.Entry("with a virtio bridge standby",
+				v1.Interface{Name: "standby", MacAddress: "de:ad:00:00:be:ef", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				"DE:AD:00:00:BE:EF", ""),
+			table.Entry("with a missing standby",
+				v1.Interface{Name: "other", MacAddress: "de:ad:00:00:be:ef", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				"de:ad:00:00:be:ef", "fake.domain.devices.interfaces[0].sriov.failoverStandby"),
+			table.Entry("with an e1000 standby",
+				v1.Interface{Name: "standby", Model: "e1000", MacAddress: "de:ad:00:00:be:ef", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				"de:ad:00:00:be:ef", "fake.domain.devices.interfaces[0].sriov.failoverStandby"),
+			table.Entry("with a different MAC address",
+				v1.Interface{Name: "standby", MacAddress: "de:ad:00:00:be:ee", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				"de:ad:00:00:be:ef", "fake.domain.devices.interfaces[0].macAddress"),
+			table.Entry("without a MAC address",
+				v1.Interface{Name: "standby", InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}}},
+				"", "fake.domain.devices.interfaces[0].macAddress"),
+		)
+
+		table.DescribeTable("should validate network interface queues", func(multiQueue bool, queues uint32, expectedCauses int) {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultBridgeNetworkInterface()}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.NetworkInterfaceMultiQueue = &multiQueue
+			vmi.Spec.Domain.Devices.NetworkInterfaceQueues = &queues
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(expectedCauses))
+			for _, cause := range causes {
+				Expect(cause.Field).To(Equal("fake.domain.devices.networkInterfaceQueues"))
+			}
+		},
+			table.Entry("with a supported queue count", true, uint32(4), 0),
+			table.Entry("without queues", true, uint32(0), 1),
+			table.Entry("with too many queues", true, uint32(257), 1),
+			table.Entry("without multiqueue", false, uint32(4), 1),
+		)
+
+		table.DescribeTable("should validate disk queues", func(bus string, count, size uint32, expectedFields ...string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			queues := &v1.DiskQueues{}
+			if count != 0 {
+				queues.Count = &count
+			}
+			if size != 0 {
+				queues.Size = &size
+			}
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name:       "testdisk",
+				DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: bus}},
+				Queues:     queues,
+			})
+
+			causes := validateDisks(k8sfield.NewPath("fake"), vmi.Spec.Domain.Devices.Disks)
+			Expect(causes).To(HaveLen(len(expectedFields)))
+			for i, field := range expectedFields {
+				Expect(causes[i].Field).To(Equal(field))
+			}
+		},
+			table.Entry("accept count and size on virtio", "virtio", uint32(4), uint32(512)),
+			table.Entry("accept count on scsi", "scsi", uint32(4), uint32(0)),
+			table.Entry("reject queues on sata", "sata", uint32(4), uint32(0), "fake[0].queues"),
+			table.Entry("reject a too large count", "virtio", uint32(257), uint32(0), "fake[0].queues.count"),
+			table.Entry("reject a size which is not a power of 2", "virtio", uint32(0), uint32(300), "fake[0].queues.size"),
+			table.Entry("reject a too large size", "virtio", uint32(0), uint32(2048), "fake[0].queues.size"),
+			table.Entry("reject a size on scsi", "scsi", uint32(0), uint32(256), "fake[0].queues.size"),
+		)
+
+		table.DescribeTable("should validate the disk compaction interval", func(interval time.Duration, valid bool) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.DiskCompaction = &v1.DiskCompaction{Interval: metav1.Duration{Duration: interval}}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			if valid {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.diskCompaction.interval"))
+			}
+		},
+			table.Entry("accept a daily compaction", 24*time.Hour, true),
+			table.Entry("accept an hourly compaction", time.Hour, true),
+			table.Entry("reject a compaction every minute", time.Minute, false),
+		)
+
+		table.DescribeTable("should validate the serial console log", func(serialConsoleLog *v1.SerialConsoleLog, autoattachSerialConsole *bool, expectedFields ...string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.SerialConsoleLog = serialConsoleLog
+			vmi.Spec.Domain.Devices.AutoattachSerialConsole = autoattachSerialConsole
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{{Name: "disk"}}
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "disk",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "disk"},
+					},
+				},
+				{
+					Name: "logs",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "logs"},
+					},
+				},
+				{
+					Name:         "emptydisk",
+					VolumeSource: v1.VolumeSource{EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")}},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(len(expectedFields)))
+			for i, field := range expectedFields {
+				Expect(causes[i].Field).To(Equal(field))
+			}
+		},
+			table.Entry("accept logging to the pod logs", &v1.SerialConsoleLog{}, nil),
+			table.Entry("accept logging to a volume", &v1.SerialConsoleLog{VolumeName: "logs", MaxSize: resource.NewQuantity(1024, resource.BinarySI)}, nil),
+			table.Entry("reject logging without serial console", &v1.SerialConsoleLog{}, &[]bool{false}[0], "fake.domain.devices.serialConsoleLog"),
+			table.Entry("reject a missing volume", &v1.SerialConsoleLog{VolumeName: "missing"}, nil, "fake.domain.devices.serialConsoleLog.volumeName"),
+			table.Entry("reject a volume which is not a persistentVolumeClaim", &v1.SerialConsoleLog{VolumeName: "emptydisk"}, nil, "fake.domain.devices.serialConsoleLog.volumeName"),
+			table.Entry("reject a volume used by a disk", &v1.SerialConsoleLog{VolumeName: "disk"}, nil, "fake.domain.devices.serialConsoleLog.volumeName"),
+			table.Entry("reject a zero max size", &v1.SerialConsoleLog{MaxSize: resource.NewQuantity(0, resource.BinarySI)}, nil, "fake.domain.devices.serialConsoleLog.maxSize"),
+		)
+
+		table.DescribeTable("should validate disk I/O limits", func(ioTune *v1.DiskIOTune, expectedFields ...string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name:       "testdisk",
+				DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "virtio"}},
+				IOTune:     ioTune,
+			})
+
+			causes := validateDisks(k8sfield.NewPath("fake"), vmi.Spec.Domain.Devices.Disks)
+			Expect(causes).To(HaveLen(len(expectedFields)))
+			for i, field := range expectedFields {
+				Expect(causes[i].Field).To(Equal(field))
+			}
+		},
+			table.Entry("accept total limits", &v1.DiskIOTune{TotalBytesSec: &[]uint64{1 << 20}[0], TotalIopsSec: &[]uint64{100}[0]}),
+			table.Entry("accept read and write limits", &v1.DiskIOTune{ReadBytesSec: &[]uint64{1 << 20}[0], WriteIopsSec: &[]uint64{100}[0]}),
+			table.Entry("reject a limit of zero", &v1.DiskIOTune{ReadIopsSec: &[]uint64{0}[0]}, "fake[0].ioTune.readIopsSec"),
+			table.Entry("reject a total limit with a read limit", &v1.DiskIOTune{TotalBytesSec: &[]uint64{1 << 20}[0], ReadBytesSec: &[]uint64{1 << 20}[0]}, "fake[0].ioTune.totalBytesSec"),
+			table.Entry("reject a total limit with a write limit", &v1.DiskIOTune{TotalIopsSec: &[]uint64{100}[0], WriteIopsSec: &[]uint64{100}[0]}, "fake[0].ioTune.totalIopsSec"),
+		)
+
+		It("should allow BlockMultiQueue with CPU settings", func() {
+			_true := true
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.Devices.BlockMultiQueue = &_true
+			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{}
+			vmi.Spec.Domain.Resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("5")
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(0))
+		})
+
 		It("should ignore CPU settings for explicitly rejected BlockMultiQueue", func() {
 			_false := false
 			vmi := v1.NewMinimalVMI("testvm")
@@ -1794,6 +2548,80 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(causes[0].Message).To(Equal(fmt.Sprintf("Invalid IOThreadsPolicy (%s)", ioThreadPolicy)))
 		})
 
+		Context("with the manual ioThreadsPolicy", func() {
+			var vmi *v1.VirtualMachineInstance
+
+			BeforeEach(func() {
+				vmi = v1.NewMinimalVMI("testvm")
+				policy := v1.IOThreadsPolicyManual
+				vmi.Spec.Domain.IOThreadsPolicy = &policy
+				vmi.Spec.Domain.CPU = &v1.CPU{Cores: 4, DedicatedCPUPlacement: true}
+				vmi.Spec.Domain.IOThreads = []v1.IOThread{{ID: 1, CPUs: "0-1"}, {ID: 2}}
+				ioThread := uint32(2)
+				vmi.Spec.Domain.Devices.Disks = []v1.Disk{{Name: "disk0", IOThread: &ioThread}}
+				vmi.Spec.Volumes = []v1.Volume{{
+					Name: "disk0",
+					VolumeSource: v1.VolumeSource{
+						ContainerDisk: &v1.ContainerDiskSource{Image: "fake"},
+					},
+				}}
+			})
+
+			It("should accept a valid iothread mapping", func() {
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+				Expect(causes).To(BeEmpty())
+			})
+
+			It("should require ioThreads", func() {
+				vmi.Spec.Domain.IOThreads = nil
+				vmi.Spec.Domain.Devices.Disks = nil
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.ioThreads"))
+			})
+
+			It("should reject ioThreads with another policy", func() {
+				policy := v1.IOThreadsPolicyAuto
+				vmi.Spec.Domain.IOThreadsPolicy = &policy
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+				Expect(causes).To(HaveLen(2))
+				Expect(causes[0].Field).To(Equal("fake.domain.ioThreads"))
+				Expect(causes[1].Field).To(Equal("fake.domain.devices.disks[0].ioThread"))
+			})
+
+			table.DescribeTable("should reject", func(mutate func(vmi *v1.VirtualMachineInstance), field string) {
+				mutate(vmi)
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(field))
+			},
+				table.Entry("duplicate ids", func(vmi *v1.VirtualMachineInstance) {
+					vmi.Spec.Domain.IOThreads[1].ID = 1
+					vmi.Spec.Domain.Devices.Disks = nil
+				}, "fake.domain.ioThreads[1].id"),
+				table.Entry("out of range ids", func(vmi *v1.VirtualMachineInstance) {
+					vmi.Spec.Domain.IOThreads[0].ID = 3
+				}, "fake.domain.ioThreads[0].id"),
+				table.Entry("an invalid cpu set", func(vmi *v1.VirtualMachineInstance) {
+					vmi.Spec.Domain.IOThreads[0].CPUs = "a-b"
+				}, "fake.domain.ioThreads[0].cpus"),
+				table.Entry("cpus beyond the vCPU count", func(vmi *v1.VirtualMachineInstance) {
+					vmi.Spec.Domain.IOThreads[0].CPUs = "3-4"
+				}, "fake.domain.ioThreads[0].cpus"),
+				table.Entry("cpus without dedicated cpu placement", func(vmi *v1.VirtualMachineInstance) {
+					vmi.Spec.Domain.CPU.DedicatedCPUPlacement = false
+				}, "fake.domain.ioThreads[0].cpus"),
+				table.Entry("a disk referencing an unknown iothread", func(vmi *v1.VirtualMachineInstance) {
+					ioThread := uint32(5)
+					vmi.Spec.Domain.Devices.Disks[0].IOThread = &ioThread
+				}, "fake.domain.devices.disks[0].ioThread"),
+				table.Entry("a disk with a dedicated iothread", func(vmi *v1.VirtualMachineInstance) {
+					dedicated := true
+					vmi.Spec.Domain.Devices.Disks[0].DedicatedIOThread = &dedicated
+				}, "fake.domain.devices.disks[0].dedicatedIOThread"),
+			)
+		})
+
 		It("should reject GPU devices when feature gate is disabled", func() {
 			vmi := v1.NewMinimalVMI("testvm")
 			vmi.Spec.Domain.Devices.GPUs = []v1.GPU{
@@ -1895,6 +2723,33 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(len(causes)).To(Equal(1))
 			Expect(causes[0].Field).To(Equal("fake.domain.cpu.isolateEmulatorThread"))
 		})
+		table.DescribeTable("should validate the emulator thread policy", func(policy v1.EmulatorThreadPolicy, dedicated bool, isolate bool, valid bool) {
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				DedicatedCPUPlacement: dedicated,
+				IsolateEmulatorThread: isolate,
+				EmulatorThreadPolicy:  &policy,
+			}
+			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
+				k8sv1.ResourceCPU: resource.MustParse("2"),
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			if valid {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.cpu.emulatorThreadPolicy"))
+			}
+		},
+			table.Entry("accept isolate with dedicated CPUs", v1.EmulatorThreadPolicyIsolate, true, false, true),
+			table.Entry("accept vcpu0 with dedicated CPUs", v1.EmulatorThreadPolicyVCPU0, true, false, true),
+			table.Entry("accept float with dedicated CPUs", v1.EmulatorThreadPolicyFloat, true, false, true),
+			table.Entry("accept float without dedicated CPUs", v1.EmulatorThreadPolicyFloat, false, false, true),
+			table.Entry("accept isolate together with IsolateEmulatorThread", v1.EmulatorThreadPolicyIsolate, true, true, true),
+			table.Entry("reject vcpu0 without dedicated CPUs", v1.EmulatorThreadPolicyVCPU0, false, false, false),
+			table.Entry("reject isolate without dedicated CPUs", v1.EmulatorThreadPolicyIsolate, false, false, false),
+			table.Entry("reject vcpu0 together with IsolateEmulatorThread", v1.EmulatorThreadPolicyVCPU0, true, true, false),
+			table.Entry("reject an unknown policy", v1.EmulatorThreadPolicy("somewhere"), true, false, false),
+		)
 		It("should reject specs without inconsistent cpu reqirements", func() {
 			vmi.Spec.Domain.CPU.Cores = 4
 			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
@@ -1931,66 +2786,594 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 				k8sv1.ResourceMemory: resource.MustParse("8Mi"),
 			}
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-			Expect(len(causes)).To(Equal(1))
-			Expect(causes[0].Field).To(Equal("fake.domain.cpu.dedicatedCpuPlacement"))
-		})
-		It("should reject specs without a memory specification", func() {
-			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
-				k8sv1.ResourceCPU: resource.MustParse("4"),
-			}
-			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
-				k8sv1.ResourceCPU: resource.MustParse("4"),
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.cpu.dedicatedCpuPlacement"))
+		})
+		It("should reject specs without a memory specification", func() {
+			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
+				k8sv1.ResourceCPU: resource.MustParse("4"),
+			}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceCPU: resource.MustParse("4"),
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.resources.limits.memory"))
+		})
+		It("should reject specs with inconsistent memory specification", func() {
+			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
+				k8sv1.ResourceCPU:    resource.MustParse("1"),
+				k8sv1.ResourceMemory: resource.MustParse("8Mi"),
+			}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceCPU:    resource.MustParse("1"),
+				k8sv1.ResourceMemory: resource.MustParse("4Mi"),
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.resources.requests.memory"))
+		})
+	})
+
+	Context("with CPU features", func() {
+		It("should accept valid CPU feature policies", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Features: []v1.CPUFeature{
+					{
+						Name: "lahf_lm",
+					},
+				},
+			}
+
+			for policy, _ := range validCPUFeaturePolicies {
+				vmi.Spec.Domain.CPU.Features[0].Policy = policy
+				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+				Expect(len(causes)).To(Equal(0))
+			}
+		})
+
+		It("should reject invalid CPU feature policy", func() {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Features: []v1.CPUFeature{
+					{
+						Name:   "lahf_lm",
+						Policy: "invalid_policy",
+					},
+				},
+			}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(len(causes)).To(Equal(1))
+		})
+	})
+
+	Context("with hugepages on the nodes", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		newNode := func(name string, pageSize string, allocatable string) *k8sv1.Node {
+			return &k8sv1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{v1.HugepagesLabel + pageSize: "true"},
+				},
+				Status: k8sv1.NodeStatus{
+					Allocatable: k8sv1.ResourceList{
+						k8sv1.ResourceName(k8sv1.ResourceHugePagesPrefix + pageSize): resource.MustParse(allocatable),
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Memory = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "2048Ki"}}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("64Mi"),
+			}
+		})
+
+		table.DescribeTable("should validate the page size against the nodes", func(nodes []*k8sv1.Node, expectedMessage string) {
+			nodeStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			for _, node := range nodes {
+				Expect(nodeStore.Add(node)).To(Succeed())
+			}
+
+			causes := validateHugepagesNodeCapacity(k8sfield.NewPath("fake"), &vmi.Spec, nodeStore)
+			if expectedMessage == "" {
+				Expect(causes).To(BeEmpty())
+				return
+			}
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.memory.hugepages.pageSize"))
+			Expect(causes[0].Message).To(ContainSubstring(expectedMessage))
+		},
+			table.Entry("and accept a node with enough hugepages of the size",
+				[]*k8sv1.Node{newNode("node01", "1Gi", "4Gi"), newNode("node02", "2Mi", "128Mi")}, ""),
+			table.Entry("and reject the size if no node provides it",
+				[]*k8sv1.Node{newNode("node01", "1Gi", "4Gi")}, "is not provided by any node"),
+			table.Entry("and reject the size if no node has enough hugepages of it",
+				[]*k8sv1.Node{newNode("node01", "2Mi", "32Mi")}, "no node has 64Mi of allocatable hugepages"),
+		)
+
+		It("should only validate the page size against the nodes with the CPUNodeDiscovery feature gate", func() {
+			vmiBytes, _ := json.Marshal(vmi)
+			ar := &v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Resource: webhooks.VirtualMachineInstanceGroupVersionResource,
+					Object: runtime.RawExtension{
+						Raw: vmiBytes,
+					},
+				},
+			}
+
+			resp := vmiCreateAdmitter.Admit(ar)
+			Expect(resp.Allowed).To(BeTrue())
+
+			enableFeatureGate(virtconfig.CPUNodeDiscoveryGate)
+			resp = vmiCreateAdmitter.Admit(ar)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.domain.memory.hugepages.pageSize"))
+		})
+	})
+
+	Context("with NUMA passthrough", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Cores:                 2,
+				DedicatedCPUPlacement: true,
+				NUMA:                  &v1.NUMA{GuestMappingPassthrough: &v1.NUMAGuestMappingPassthrough{}},
+			}
+			vmi.Spec.Domain.Memory = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "2Mi"}}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("64Mi"),
+			}
+		})
+
+		It("should reject NUMA passthrough if the feature gate is not enabled", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.cpu.numa.guestMappingPassthrough"))
+			Expect(causes[0].Message).To(ContainSubstring("NUMA feature gate is not enabled"))
+		})
+
+		It("should accept NUMA passthrough with dedicated cpus and hugepages", func() {
+			enableFeatureGate(virtconfig.NUMAGate)
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should reject NUMA passthrough without dedicated cpus", func() {
+			enableFeatureGate(virtconfig.NUMAGate)
+			vmi.Spec.Domain.CPU.DedicatedCPUPlacement = false
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(ContainSubstring("requires DedicatedCPUPlacement"))
+		})
+
+		It("should reject NUMA passthrough without hugepages", func() {
+			enableFeatureGate(virtconfig.NUMAGate)
+			vmi.Spec.Domain.Memory = nil
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(ContainSubstring("requires hugepages"))
+		})
+
+		It("should reject guest memory which is not a multiple of the page size", func() {
+			enableFeatureGate(virtconfig.NUMAGate)
+			guest := resource.MustParse("63M")
+			vmi.Spec.Domain.Memory.Guest = &guest
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.memory.guest"))
+		})
+	})
+
+	Context("with realtime", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Cores:                 2,
+				DedicatedCPUPlacement: true,
+				Realtime:              &v1.Realtime{Mask: "1"},
+			}
+			vmi.Spec.Domain.Memory = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "2Mi"}}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("64Mi"),
+			}
+		})
+
+		It("should accept realtime with dedicated cpus and hugepages", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should reject realtime without dedicated cpus", func() {
+			vmi.Spec.Domain.CPU.DedicatedCPUPlacement = false
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.cpu.realtime"))
+			Expect(causes[0].Message).To(ContainSubstring("requires DedicatedCPUPlacement"))
+		})
+
+		It("should reject realtime without hugepages", func() {
+			vmi.Spec.Domain.Memory = nil
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(ContainSubstring("requires hugepages"))
+		})
+
+		table.DescribeTable("should reject an invalid", func(realtime *v1.Realtime, field string, message string) {
+			vmi.Spec.Domain.CPU.Realtime = realtime
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal(field))
+			Expect(causes[0].Message).To(ContainSubstring(message))
+		},
+			table.Entry("mask", &v1.Realtime{Mask: "a-b"}, "fake.domain.cpu.realtime.mask", "is not a valid CPU set"),
+			table.Entry("mask with too many vCPUs", &v1.Realtime{Mask: "1-2"}, "fake.domain.cpu.realtime.mask", "references vCPU 2"),
+			table.Entry("priority", &v1.Realtime{Mask: "1", Priority: uint32Ptr(100)}, "fake.domain.cpu.realtime.priority", "must be between 1 and 99"),
+			table.Entry("mask covering all vCPUs without an isolated emulator thread", &v1.Realtime{}, "fake.domain.cpu.realtime", "requires the isolate emulatorThreadPolicy"),
+		)
+
+		It("should accept realtime for all vCPUs with an isolated emulator thread", func() {
+			vmi.Spec.Domain.CPU.Realtime = &v1.Realtime{}
+			vmi.Spec.Domain.CPU.IsolateEmulatorThread = true
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+	})
+
+	Context("with host devices", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		enableHostDevices := func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{
+					virtconfig.FeatureGatesKey: virtconfig.HostDevicesGate,
+					virtconfig.PermittedHostDevicesKey: `
+pciHostDevices:
+- pciVendorSelector: "8086:6f54"
+  resourceName: "intel.com/nvme"
+mediatedDevices:
+- mdevNameSelector: "GRID T4-1Q"
+  resourceName: "nvidia.com/GRID_T4-1Q"
+`,
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.HostDevices = []v1.HostDevice{
+				{Name: "nvme1", DeviceName: "intel.com/nvme"},
+			}
+		})
+
+		It("should reject host devices if the feature gate is not enabled", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.hostDevices"))
+			Expect(causes[0].Message).To(ContainSubstring("HostDevices feature gate is not enabled"))
+		})
+
+		It("should accept permitted host devices", func() {
+			enableHostDevices()
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should accept permitted mediated devices", func() {
+			enableHostDevices()
+			vmi.Spec.Domain.Devices.HostDevices = append(vmi.Spec.Domain.Devices.HostDevices, v1.HostDevice{
+				Name: "vgpu1", DeviceName: "nvidia.com/GRID_T4-1Q",
+			})
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should reject host devices which are not permitted", func() {
+			enableHostDevices()
+			vmi.Spec.Domain.Devices.HostDevices[0].DeviceName = "vendor.com/fpga"
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.hostDevices[0].deviceName"))
+			Expect(causes[0].Message).To(ContainSubstring("vendor.com/fpga is not a permitted host device"))
+		})
+
+		It("should reject host devices with duplicate names", func() {
+			enableHostDevices()
+			vmi.Spec.Domain.Devices.HostDevices = append(vmi.Spec.Domain.Devices.HostDevices, vmi.Spec.Domain.Devices.HostDevices[0])
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.hostDevices[1].name"))
+		})
+	})
+
+	Context("with TPM", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.TPM = &v1.TPMDevice{}
+		})
+
+		It("should reject a TPM if the feature gate is not enabled", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.tpm"))
+			Expect(causes[0].Message).To(ContainSubstring("VirtualTPM feature gate is not enabled"))
+		})
+
+		It("should accept a TPM with persistent state", func() {
+			enableFeatureGate(virtconfig.TPMGate)
+			vmi.Spec.Domain.Devices.TPM.PersistentStateClaimName = "tpm-state"
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		table.DescribeTable("should reject a state claim which is also used by a volume", func(volumeSource v1.VolumeSource) {
+			enableFeatureGate(virtconfig.TPMGate)
+			vmi.Spec.Domain.Devices.TPM.PersistentStateClaimName = "tpm-state"
+			vmi.Spec.Volumes = []v1.Volume{{Name: "disk", VolumeSource: volumeSource}}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.tpm.persistentStateClaimName"))
+			Expect(causes[0].Message).To(ContainSubstring("is already used by fake.volumes[0]"))
+		},
+			table.Entry("persistentVolumeClaim", v1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "tpm-state"},
+			}),
+			table.Entry("dataVolume", v1.VolumeSource{
+				DataVolume: &v1.DataVolumeSource{Name: "tpm-state"},
+			}),
+		)
+	})
+
+	Context("with SEV", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			secureBoot := false
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{SecureBoot: &secureBoot},
+				},
+			}
+			vmi.Spec.Domain.LaunchSecurity = &v1.LaunchSecurity{SEV: &v1.SEV{}}
+		})
+
+		It("should reject SEV if the feature gate is not enabled", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.launchSecurity.sev"))
+			Expect(causes[0].Message).To(ContainSubstring("WorkloadEncryptionSEV feature gate is not enabled"))
+		})
+
+		It("should accept SEV with EFI", func() {
+			enableFeatureGate(virtconfig.SEVGate)
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		table.DescribeTable("should reject SEV without EFI with SecureBoot disabled", func(firmware *v1.Firmware) {
+			enableFeatureGate(virtconfig.SEVGate)
+			vmi.Spec.Domain.Firmware = firmware
+			vmi.Spec.Domain.Features = &v1.Features{SMM: &v1.FeatureState{}}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.firmware.bootloader"))
+			Expect(causes[0].Message).To(ContainSubstring("requires EFI with SecureBoot disabled"))
+		},
+			table.Entry("without firmware", nil),
+			table.Entry("with BIOS", &v1.Firmware{Bootloader: &v1.Bootloader{BIOS: &v1.BIOS{}}}),
+			table.Entry("with SecureBoot", &v1.Firmware{Bootloader: &v1.Bootloader{EFI: &v1.EFI{}}}),
+		)
+
+		It("should reject the LiveMigrate eviction strategy without the SEVLiveMigration feature gate", func() {
+			enableFeatureGate(virtconfig.LiveMigrationGate + "," + virtconfig.SEVGate)
+			strategy := v1.EvictionStrategyLiveMigrate
+			vmi.Spec.EvictionStrategy = &strategy
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.evictionStrategy"))
+			Expect(causes[0].Message).To(ContainSubstring("can not be live migrated"))
+		})
+
+		It("should accept the LiveMigrate eviction strategy with the SEVLiveMigration feature gate", func() {
+			enableFeatureGate(virtconfig.LiveMigrationGate + "," + virtconfig.SEVGate + "," + virtconfig.SEVLiveMigrationGate)
+			strategy := v1.EvictionStrategyLiveMigrate
+			vmi.Spec.EvictionStrategy = &strategy
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+	})
+
+	Context("with virtio-fs", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Volumes = []v1.Volume{{
+				Name: "shared",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "claim"},
+				},
+			}}
+			vmi.Spec.Domain.Devices.Filesystems = []v1.Filesystem{{Name: "shared", Virtiofs: &v1.FilesystemVirtiofs{}}}
+		})
+
+		It("should reject filesystems if the feature gate is not enabled", func() {
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.filesystems"))
+			Expect(causes[0].Message).To(ContainSubstring("ExperimentalVirtiofsSupport feature gate is not enabled"))
+		})
+
+		table.DescribeTable("should accept a filesystem sharing", func(volumeSource v1.VolumeSource) {
+			enableFeatureGate(virtconfig.VirtIOFSGate)
+			vmi.Spec.Volumes[0].VolumeSource = volumeSource
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		},
+			table.Entry("a persistentVolumeClaim", v1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "claim"},
+			}),
+			table.Entry("a configMap", v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: k8sv1.LocalObjectReference{Name: "config"}},
+			}),
+			table.Entry("a secret", v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: "secret"},
+			}),
+		)
+
+		table.DescribeTable("should reject", func(mutate func(vmi *v1.VirtualMachineInstance), field string, message string) {
+			enableFeatureGate(virtconfig.VirtIOFSGate)
+			mutate(vmi)
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal(field))
+			Expect(causes[0].Message).To(ContainSubstring(message))
+		},
+			table.Entry("a filesystem without a volume", func(vmi *v1.VirtualMachineInstance) {
+				vmi.Spec.Volumes = nil
+			}, "fake.domain.devices.filesystems[0].name", "'shared' not found"),
+			table.Entry("a filesystem without virtiofs", func(vmi *v1.VirtualMachineInstance) {
+				vmi.Spec.Domain.Devices.Filesystems[0].Virtiofs = nil
+			}, "fake.domain.devices.filesystems[0].virtiofs", "virtio-fs is the only supported filesystem"),
+			table.Entry("a duplicate filesystem", func(vmi *v1.VirtualMachineInstance) {
+				vmi.Spec.Domain.Devices.Filesystems = append(vmi.Spec.Domain.Devices.Filesystems, vmi.Spec.Domain.Devices.Filesystems[0])
+			}, "fake.domain.devices.filesystems[1].name", "duplicate name shared"),
+			table.Entry("a filesystem with an unsupported volume", func(vmi *v1.VirtualMachineInstance) {
+				vmi.Spec.Volumes[0].VolumeSource = v1.VolumeSource{EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")}}
+			}, "fake.domain.devices.filesystems[0].name", "can only be mapped to a ConfigMap, Secret or PersistentVolumeClaim volume"),
+			table.Entry("a filesystem sharing the volume of a disk", func(vmi *v1.VirtualMachineInstance) {
+				vmi.Spec.Domain.Devices.Disks = []v1.Disk{{Name: "shared"}}
+			}, "fake.domain.devices.filesystems[0].name", "already used by a disk"),
+		)
+	})
+
+	Context("with sound", func() {
+		table.DescribeTable("should validate the model", func(model string, valid bool) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Sound = &v1.SoundDevice{Name: "audio", Model: model}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			if valid {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal("fake.domain.devices.sound.model"))
 			}
+		},
+			table.Entry("without a model", "", true),
+			table.Entry("with ich9", "ich9", true),
+			table.Entry("with ac97", "ac97", true),
+			table.Entry("with an unsupported model", "sb16", false),
+		)
+
+		It("should reject a sound device without a name", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Sound = &v1.SoundDevice{}
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-			Expect(len(causes)).To(Equal(1))
-			Expect(causes[0].Field).To(Equal("fake.domain.resources.limits.memory"))
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.sound.name"))
 		})
-		It("should reject specs with inconsistent memory specification", func() {
-			vmi.Spec.Domain.Resources.Limits = k8sv1.ResourceList{
-				k8sv1.ResourceCPU:    resource.MustParse("1"),
-				k8sv1.ResourceMemory: resource.MustParse("8Mi"),
-			}
-			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
-				k8sv1.ResourceCPU:    resource.MustParse("1"),
-				k8sv1.ResourceMemory: resource.MustParse("4Mi"),
-			}
+	})
+
+	Context("with a panic device", func() {
+		It("should accept a panic device with a crash dump claim", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Panic = &v1.PanicDevice{CrashDumpClaimName: "crash-dumps"}
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-			Expect(len(causes)).To(Equal(1))
-			Expect(causes[0].Field).To(Equal("fake.domain.resources.requests.memory"))
+			Expect(causes).To(BeEmpty())
 		})
+
+		table.DescribeTable("should reject a crash dump claim which is also used by a volume", func(volumeSource v1.VolumeSource) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Panic = &v1.PanicDevice{CrashDumpClaimName: "crash-dumps"}
+			vmi.Spec.Volumes = []v1.Volume{{Name: "disk", VolumeSource: volumeSource}}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.devices.panic.crashDumpClaimName"))
+			Expect(causes[0].Message).To(ContainSubstring("is already used by fake.volumes[0]"))
+		},
+			table.Entry("persistentVolumeClaim", v1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "crash-dumps"},
+			}),
+			table.Entry("dataVolume", v1.VolumeSource{
+				DataVolume: &v1.DataVolumeSource{Name: "crash-dumps"},
+			}),
+		)
 	})
 
-	Context("with CPU features", func() {
-		It("should accept valid CPU feature policies", func() {
-			vmi := v1.NewMinimalVMI("testvm")
-			vmi.Spec.Domain.CPU = &v1.CPU{
-				Features: []v1.CPUFeature{
-					{
-						Name: "lahf_lm",
-					},
-				},
+	Context("with a CPU thread policy", func() {
+		table.DescribeTable("should validate", func(policy v1.CPUThreadPolicy, cpu v1.CPU, field string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("64M")
+			cpu.ThreadPolicy = &policy
+			vmi.Spec.Domain.CPU = &cpu
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			if field == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(field))
 			}
+		},
+			table.Entry("and accept full cores", v1.CPUThreadPolicyFullCores,
+				v1.CPU{Cores: 2, Threads: 2, DedicatedCPUPlacement: true}, ""),
+			table.Entry("and accept isolated threads", v1.CPUThreadPolicyIsolate,
+				v1.CPU{Cores: 2, DedicatedCPUPlacement: true}, ""),
+			table.Entry("and reject an unknown policy", v1.CPUThreadPolicy("prefer"),
+				v1.CPU{Cores: 2, DedicatedCPUPlacement: true}, "fake.domain.cpu.threadPolicy"),
+			table.Entry("and reject a policy without dedicated CPUs", v1.CPUThreadPolicyIsolate,
+				v1.CPU{Cores: 2}, "fake.domain.cpu.threadPolicy"),
+			table.Entry("and reject full cores without guest threads", v1.CPUThreadPolicyFullCores,
+				v1.CPU{Cores: 2, DedicatedCPUPlacement: true}, "fake.domain.cpu.threads"),
+			table.Entry("and reject isolated threads with guest threads", v1.CPUThreadPolicyIsolate,
+				v1.CPU{Cores: 2, Threads: 2, DedicatedCPUPlacement: true}, "fake.domain.cpu.threads"),
+		)
 
-			for policy, _ := range validCPUFeaturePolicies {
-				vmi.Spec.Domain.CPU.Features[0].Policy = policy
-				causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-				Expect(len(causes)).To(Equal(0))
-			}
+		It("should require the CPU topology of the guest", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("64M")
+			vmi.Spec.Domain.Resources.Requests[k8sv1.ResourceCPU] = resource.MustParse("2")
+			policy := v1.CPUThreadPolicyIsolate
+			vmi.Spec.Domain.CPU = &v1.CPU{DedicatedCPUPlacement: true, ThreadPolicy: &policy}
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(ContainSubstring("requires the CPU topology of the guest"))
 		})
+	})
 
-		It("should reject invalid CPU feature policy", func() {
-			vmi := v1.NewMinimalVMI("testvm")
-			vmi.Spec.Domain.CPU = &v1.CPU{
-				Features: []v1.CPUFeature{
-					{
-						Name:   "lahf_lm",
-						Policy: "invalid_policy",
-					},
-				},
-			}
+	Context("with topology spread constraints", func() {
+		table.DescribeTable("should validate", func(constraint k8sv1.TopologySpreadConstraint, field string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.TopologySpreadConstraints = []k8sv1.TopologySpreadConstraint{constraint}
 			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
-			Expect(len(causes)).To(Equal(1))
-		})
+			if field == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(field))
+			}
+		},
+			table.Entry("and accept a valid constraint",
+				k8sv1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: k8sv1.DoNotSchedule}, ""),
+			table.Entry("and reject a maxSkew of zero",
+				k8sv1.TopologySpreadConstraint{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: k8sv1.ScheduleAnyway}, "fake.topologySpreadConstraints[0].maxSkew"),
+			table.Entry("and reject an empty topologyKey",
+				k8sv1.TopologySpreadConstraint{MaxSkew: 1, WhenUnsatisfiable: k8sv1.ScheduleAnyway}, "fake.topologySpreadConstraints[0].topologyKey"),
+			table.Entry("and reject an unknown whenUnsatisfiable",
+				k8sv1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: "Sometimes"}, "fake.topologySpreadConstraints[0].whenUnsatisfiable"),
+		)
 	})
 
 	Context("with Disk", func() {
@@ -2362,6 +3745,199 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
 			Expect(len(causes)).To(Equal(0))
 		})
+
+		table.DescribeTable("should validate the claim of golden image volumes", func(claimName string, expectedCauses int) {
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "golden",
+				VolumeSource: v1.VolumeSource{
+					GoldenImage: &v1.GoldenImageVolumeSource{
+						ClaimName: claimName,
+					},
+				},
+			})
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			Expect(len(causes)).To(Equal(expectedCauses))
+			if expectedCauses > 0 {
+				Expect(causes[0].Field).To(Equal("fake[0].goldenImage.claimName"))
+			}
+		},
+			table.Entry("and accept a claim", "golden-pvc", 0),
+			table.Entry("and reject a missing claim", "", 1),
+		)
+
+		table.DescribeTable("should validate ignition volumes", func(source *v1.IgnitionSource, expectedMessage string) {
+			enableFeatureGate(virtconfig.IgnitionGate)
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "ignition",
+				VolumeSource: v1.VolumeSource{
+					Ignition: source,
+				},
+			})
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			if expectedMessage == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+			table.Entry("and accept a secret", &v1.IgnitionSource{SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"}}, ""),
+			table.Entry("and accept a config map", &v1.IgnitionSource{ConfigMapRef: &k8sv1.LocalObjectReference{Name: "ignition-config"}}, ""),
+			table.Entry("and accept inline data", &v1.IgnitionSource{Data: `{"ignition":{"version":"3.0.0"}}`}, ""),
+			table.Entry("and reject a missing source", &v1.IgnitionSource{}, "must have exactly one ignition config source set"),
+			table.Entry("and reject multiple sources", &v1.IgnitionSource{
+				SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"},
+				Data:      `{"ignition":{"version":"3.0.0"}}`,
+			}, "must have exactly one ignition config source set"),
+			table.Entry("and reject a secret without name", &v1.IgnitionSource{SecretRef: &k8sv1.LocalObjectReference{}}, "fake[0].ignition.secretRef.name is a required field"),
+			table.Entry("and reject inline data exceeding the size limit", &v1.IgnitionSource{Data: strings.Repeat("a", ignitionDataMaxLen+1)}, "exceeds 2048 byte limit"),
+		)
+
+		It("should reject ignition volumes if the feature gate is not enabled", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "ignition",
+				VolumeSource: v1.VolumeSource{
+					Ignition: &v1.IgnitionSource{Data: `{"ignition":{"version":"3.0.0"}}`},
+				},
+			})
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(ContainSubstring("ExperimentalIgnitionSupport feature gate is not enabled"))
+		})
+
+		It("should reject more than one ignition volume", func() {
+			enableFeatureGate(virtconfig.IgnitionGate)
+			vmi := v1.NewMinimalVMI("testvmi")
+			for _, name := range []string{"ignition1", "ignition2"} {
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+					Name: name,
+					VolumeSource: v1.VolumeSource{
+						Ignition: &v1.IgnitionSource{Data: `{"ignition":{"version":"3.0.0"}}`},
+					},
+				})
+			}
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal("fake must have max one ignition volume set"))
+		})
+
+		table.DescribeTable("should validate sysprep volumes", func(source *v1.SysprepSource, expectedMessage string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "sysprep",
+				VolumeSource: v1.VolumeSource{
+					Sysprep: source,
+				},
+			})
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			if expectedMessage == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Message).To(ContainSubstring(expectedMessage))
+			}
+		},
+			table.Entry("and accept a config map", &v1.SysprepSource{ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"}}, ""),
+			table.Entry("and accept a secret", &v1.SysprepSource{Secret: &k8sv1.LocalObjectReference{Name: "sysprep-secret"}}, ""),
+			table.Entry("and reject a missing source", &v1.SysprepSource{}, "fake[0].sysprep must have exactly one answer file source set."),
+			table.Entry("and reject multiple sources", &v1.SysprepSource{
+				ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"},
+				Secret:    &k8sv1.LocalObjectReference{Name: "sysprep-secret"},
+			}, "fake[0].sysprep must have exactly one answer file source set."),
+			table.Entry("and reject a config map without name", &v1.SysprepSource{ConfigMap: &k8sv1.LocalObjectReference{}}, "fake[0].sysprep.configMap.name is a required field"),
+		)
+
+		It("should reject sysprep volumes together with cloud-init volumes", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "sysprep",
+				VolumeSource: v1.VolumeSource{
+					Sysprep: &v1.SysprepSource{ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"}},
+				},
+			}, v1.Volume{
+				Name: "cloudinit",
+				VolumeSource: v1.VolumeSource{
+					CloudInitNoCloud: &v1.CloudInitNoCloudSource{UserData: "#cloud-config"},
+				},
+			})
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal("fake must not have a sysprep volume together with a cloud-init volume"))
+		})
+
+		It("should reject more than one sysprep volume", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			for _, name := range []string{"sysprep1", "sysprep2"} {
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+					Name: name,
+					VolumeSource: v1.VolumeSource{
+						Sysprep: &v1.SysprepSource{ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"}},
+					},
+				})
+			}
+
+			causes := validateVolumes(k8sfield.NewPath("fake"), vmi.Spec.Volumes, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal("fake must have max one sysprep volume set"))
+		})
+
+		table.DescribeTable("should only map sysprep volumes to cdroms", func(diskDevice v1.DiskDevice, expectedCauses int) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name:       "sysprep",
+				DiskDevice: diskDevice,
+			})
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "sysprep",
+				VolumeSource: v1.VolumeSource{
+					Sysprep: &v1.SysprepSource{ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"}},
+				},
+			})
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(expectedCauses))
+			if expectedCauses > 0 {
+				Expect(causes[0].Message).To(Equal("fake.domain.devices.disks[0] must be a cdrom to be mapped to a sysprep volume."))
+			}
+		},
+			table.Entry("and accept a cdrom", v1.DiskDevice{CDRom: &v1.CDRomTarget{Bus: "sata"}}, 0),
+			table.Entry("and reject a disk", v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "sata"}}, 1),
+		)
+
+		It("should reject disks referencing an ignition volume", func() {
+			enableFeatureGate(virtconfig.IgnitionGate)
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name: "ignition",
+			})
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name: "ignition",
+				VolumeSource: v1.VolumeSource{
+					Ignition: &v1.IgnitionSource{Data: `{"ignition":{"version":"3.0.0"}}`},
+				},
+			}, v1.Volume{
+				Name: "other",
+				VolumeSource: v1.VolumeSource{
+					EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")},
+				},
+			})
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Message).To(Equal("fake.domain.devices.disks[0] can not be mapped to an ignition volume."))
+		})
 	})
 
 	Context("with bootloader", func() {
@@ -2442,6 +4018,80 @@ var _ = Describe("Validating VMICreate Admitter", func() {
 			Expect(len(causes)).To(Equal(0))
 		})
 
+		It("should accept EFI with an SMM feature without explicit state", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Features = &v1.Features{
+				SMM: &v1.FeatureState{},
+			}
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should accept secureBoot without enrolled keys and a firmware container disk", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			_true := true
+			_false := false
+			vmi.Spec.Domain.Features = &v1.Features{
+				SMM: &v1.FeatureState{
+					Enabled: &_true,
+				},
+			}
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{
+						EnrolledKeys:  &_false,
+						ContainerDisk: &v1.ContainerDiskSource{Image: "ovmf:custom"},
+					},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(BeEmpty())
+		})
+
+		It("should not accept enrolled keys without secureBoot", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			_false := false
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{
+						SecureBoot:   &_false,
+						EnrolledKeys: &_false,
+					},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.firmware.bootloader.efi.enrolledKeys"))
+		})
+
+		It("should not accept a firmware container disk without image", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+
+			_false := false
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{
+						SecureBoot:    &_false,
+						ContainerDisk: &v1.ContainerDiskSource{},
+					},
+				},
+			}
+
+			causes := ValidateVirtualMachineInstanceSpec(k8sfield.NewPath("fake"), &vmi.Spec, config)
+			Expect(causes).To(HaveLen(1))
+			Expect(causes[0].Field).To(Equal("fake.domain.firmware.bootloader.efi.containerDisk.image"))
+		})
+
 		It("should not accept BIOS and EFI together", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
 			vmi.Spec.Subdomain = "testsubdomain"
@@ -2779,3 +4429,7 @@ var _ = Describe("Function getNumberOfPodInterfaces()", func() {
 	})
 
 })
+
+func uint32Ptr(i uint32) *uint32 {
+	return &i
+}
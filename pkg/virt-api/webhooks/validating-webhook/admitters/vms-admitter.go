@@ -26,13 +26,17 @@ import (
 	"strings"
 
 	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	k8svalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/kubecli"
 	cdiclone "kubevirt.io/containerized-data-importer/pkg/clone"
+	"kubevirt.io/kubevirt/pkg/instancetype"
 	webhookutils "kubevirt.io/kubevirt/pkg/util/webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
@@ -44,12 +48,14 @@ type CloneAuthFunc func(pvcNamespace, pvcName, saNamespace, saName string) (bool
 
 type VMsAdmitter struct {
 	ClusterConfig *virtconfig.ClusterConfig
+	Client        kubecli.KubevirtClient
 	cloneAuthFunc CloneAuthFunc
 }
 
 func NewVMsAdmitter(clusterConfig *virtconfig.ClusterConfig, client kubecli.KubevirtClient) *VMsAdmitter {
 	return &VMsAdmitter{
 		ClusterConfig: clusterConfig,
+		Client:        client,
 		cloneAuthFunc: func(pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
 			return cdiclone.CanServiceAccountClonePVC(client, pvcNamespace, pvcName, saNamespace, saName)
 		},
@@ -75,7 +81,12 @@ func (admitter *VMsAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1beta1.Admissi
 		return webhookutils.ToAdmissionResponseError(err)
 	}
 
-	causes := ValidateVirtualMachineSpec(k8sfield.NewPath("spec"), &vm.Spec, admitter.ClusterConfig, accountName)
+	causes := admitter.applyInstancetypeToVm(&vm)
+	if len(causes) > 0 {
+		return webhookutils.ToAdmissionResponse(causes)
+	}
+
+	causes = ValidateVirtualMachineSpec(k8sfield.NewPath("spec"), &vm.Spec, admitter.ClusterConfig, accountName)
 	if len(causes) > 0 {
 		return webhookutils.ToAdmissionResponse(causes)
 	}
@@ -93,11 +104,21 @@ func (admitter *VMsAdmitter) Admit(ar *v1beta1.AdmissionReview) *v1beta1.Admissi
 		return webhookutils.ToAdmissionResponse(causes)
 	}
 
+	causes = validateStateChangeRequestConflicts(ar.Request, &vm)
+	if len(causes) > 0 {
+		return webhookutils.ToAdmissionResponse(causes)
+	}
+
 	causes = validateSnapshotStatus(ar.Request, &vm)
 	if len(causes) > 0 {
 		return webhookutils.ToAdmissionResponse(causes)
 	}
 
+	causes = admitter.validateRollback(ar.Request, &vm)
+	if len(causes) > 0 {
+		return webhookutils.ToAdmissionResponse(causes)
+	}
+
 	reviewResponse := v1beta1.AdmissionResponse{}
 	reviewResponse.Allowed = true
 	return &reviewResponse
@@ -114,6 +135,11 @@ func (admitter *VMsAdmitter) AdmitStatus(ar *v1beta1.AdmissionReview) *v1beta1.A
 		return webhookutils.ToAdmissionResponse(causes)
 	}
 
+	causes = validateStateChangeRequestConflicts(ar.Request, vm)
+	if len(causes) > 0 {
+		return webhookutils.ToAdmissionResponse(causes)
+	}
+
 	causes = validateSnapshotStatus(ar.Request, vm)
 	if len(causes) > 0 {
 		return webhookutils.ToAdmissionResponse(causes)
@@ -124,6 +150,55 @@ func (admitter *VMsAdmitter) AdmitStatus(ar *v1beta1.AdmissionReview) *v1beta1.A
 	return &reviewResponse
 }
 
+// applyInstancetypeToVm expands the instancetype and preference into the template of the VirtualMachine,
+// so that the template is validated like the VirtualMachineInstance which gets started from it
+func (admitter *VMsAdmitter) applyInstancetypeToVm(vm *v1.VirtualMachine) []metav1.StatusCause {
+	if vm.Spec.Instancetype == nil && vm.Spec.Preference == nil {
+		return nil
+	}
+
+	field := k8sfield.NewPath("spec")
+	if !admitter.ClusterConfig.InstancetypeEnabled() {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: "Instancetype feature gate is not enabled in kubevirt-config",
+			Field:   field.String(),
+		}}
+	}
+
+	instancetypeSpec, err := instancetype.FindInstancetypeSpec(admitter.Client, vm)
+	if err != nil {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueNotFound,
+			Message: fmt.Sprintf("Failure to find instancetype: %v", err),
+			Field:   field.Child("instancetype").String(),
+		}}
+	}
+	preferenceSpec, err := instancetype.FindPreferenceSpec(admitter.Client, vm)
+	if err != nil {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueNotFound,
+			Message: fmt.Sprintf("Failure to find preference: %v", err),
+			Field:   field.Child("preference").String(),
+		}}
+	}
+
+	if vm.Spec.Template == nil {
+		return nil
+	}
+
+	var causes []metav1.StatusCause
+	conflicts := instancetype.ApplyToVmi(field.Child("template", "spec"), instancetypeSpec, preferenceSpec, &vm.Spec.Template.Spec)
+	for _, conflict := range conflicts {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("VM field %s conflicts with selected instancetype", conflict.String()),
+			Field:   conflict.String(),
+		})
+	}
+	return causes
+}
+
 func (admitter *VMsAdmitter) authorizeVirtualMachineSpec(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMachine) ([]metav1.StatusCause, error) {
 	var causes []metav1.StatusCause
 
@@ -256,6 +331,97 @@ func ValidateVirtualMachineSpec(field *k8sfield.Path, spec *v1.VirtualMachineSpe
 		}
 	}
 
+	causes = append(causes, validatePlacement(field, spec)...)
+
+	return causes
+}
+
+// validatePlacement makes sure that the template of a VirtualMachine only joins the group of its
+// placement, and that the template does not attract the VirtualMachineInstance to the group it is
+// spread across
+func validatePlacement(field *k8sfield.Path, spec *v1.VirtualMachineSpec) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	labelField := field.Child("template", "metadata", "labels").Key(v1.VirtualMachineGroupLabel)
+	group, labeled := spec.Template.ObjectMeta.Labels[v1.VirtualMachineGroupLabel]
+
+	placement := spec.Placement
+	if placement == nil {
+		if labeled {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueNotSupported,
+				Message: fmt.Sprintf("the %s label is set from the placement of the VirtualMachine", v1.VirtualMachineGroupLabel),
+				Field:   labelField.String(),
+			})
+		}
+		return causes
+	}
+
+	placementField := field.Child("placement")
+	if placement.Group == "" {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueRequired,
+			Message: fmt.Sprintf("%s must not be empty", placementField.Child("group").String()),
+			Field:   placementField.Child("group").String(),
+		})
+	} else if errs := validation.IsValidLabelValue(placement.Group); len(errs) > 0 {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s is not a valid label value: %s", placementField.Child("group").String(), strings.Join(errs, ", ")),
+			Field:   placementField.Child("group").String(),
+		})
+	}
+
+	switch placement.SpreadAcross {
+	case "", v1.PlacementTopologyNode, v1.PlacementTopologyZone:
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s must be one of %s or %s", placementField.Child("spreadAcross").String(), v1.PlacementTopologyNode, v1.PlacementTopologyZone),
+			Field:   placementField.Child("spreadAcross").String(),
+		})
+	}
+
+	switch placement.Policy {
+	case "", v1.PlacementPolicyRequired, v1.PlacementPolicyPreferred:
+	default:
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("%s must be one of %s or %s", placementField.Child("policy").String(), v1.PlacementPolicyRequired, v1.PlacementPolicyPreferred),
+			Field:   placementField.Child("policy").String(),
+		})
+	}
+
+	if labeled && group != placement.Group {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("the VirtualMachine is a member of group %s, its template must not join group %s", placement.Group, group),
+			Field:   labelField.String(),
+		})
+	}
+
+	affinity := spec.Template.Spec.Affinity
+	if affinity == nil || affinity.PodAffinity == nil {
+		return causes
+	}
+	// the members of the group can not be spread and kept together at the same time
+	member := labels.Set{v1.VirtualMachineGroupLabel: placement.Group}
+	podAffinityField := field.Child("template", "spec", "affinity", "podAffinity")
+	for idx, term := range affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if len(term.Namespaces) > 0 || term.LabelSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil || !selector.Matches(member) {
+			continue
+		}
+		termField := podAffinityField.Child("requiredDuringSchedulingIgnoredDuringExecution").Index(idx)
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("%s requires the affinity to group %s, which the placement spreads", termField.String(), placement.Group),
+			Field:   termField.String(),
+		})
+	}
+
 	return causes
 }
 
@@ -325,6 +491,74 @@ func validateStateChangeRequests(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMac
 	return nil
 }
 
+// validateStateChangeRequestConflicts rejects state change requests which contradict each other, as well as
+// new requests while virt-controller has not processed the previous ones yet. Only a stop request may
+// replace pending requests.
+func validateStateChangeRequestConflicts(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMachine) []metav1.StatusCause {
+	requests := vm.Status.StateChangeRequests
+	field := k8sfield.NewPath("status", "stateChangeRequests")
+
+	if !isValidStateChangeRequestSequence(requests) {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Conflicting state change requests %s", stateChangeRequestActions(requests)),
+			Field:   field.String(),
+		}}
+	}
+
+	if ar.Operation != v1beta1.Update {
+		return nil
+	}
+
+	oldVM := &v1.VirtualMachine{}
+	if err := json.Unmarshal(ar.OldObject.Raw, oldVM); err != nil {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeUnexpectedServerResponse,
+			Message: "Could not fetch old VM",
+		}}
+	}
+	oldRequests := oldVM.Status.StateChangeRequests
+
+	// virt-controller removes the requests from the front once it processed them
+	if len(requests) == 0 || len(requests) <= len(oldRequests) && reflect.DeepEqual(requests, oldRequests[len(oldRequests)-len(requests):]) {
+		return nil
+	}
+
+	if len(oldRequests) > 0 && !(len(requests) == 1 && requests[0].Action == v1.StopRequest) {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: fmt.Sprintf("Cannot request %s while %s is still in progress", stateChangeRequestActions(requests), stateChangeRequestActions(oldRequests)),
+			Field:   field.String(),
+		}}
+	}
+
+	return nil
+}
+
+// isValidStateChangeRequestSequence accepts a single request, or a stop followed by a start for a restart
+func isValidStateChangeRequestSequence(requests []v1.VirtualMachineStateChangeRequest) bool {
+	switch len(requests) {
+	case 0:
+		return true
+	case 1:
+		switch requests[0].Action {
+		case v1.StartRequest, v1.StopRequest, v1.RenameRequest:
+			return true
+		}
+	case 2:
+		return requests[0].Action == v1.StopRequest && requests[1].Action == v1.StartRequest
+	}
+	return false
+}
+
+func stateChangeRequestActions(requests []v1.VirtualMachineStateChangeRequest) string {
+	var actions []string
+	for _, request := range requests {
+		actions = append(actions, string(request.Action))
+	}
+	return "[" + strings.Join(actions, ", ") + "]"
+}
+
 func validateSnapshotStatus(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMachine) []metav1.StatusCause {
 	if ar.Operation != v1beta1.Update || vm.Status.SnapshotInProgress == nil {
 		return nil
@@ -349,6 +583,48 @@ func validateSnapshotStatus(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMachine)
 	return nil
 }
 
+// validateRollback rejects rolling the template back to another revision while the
+// VirtualMachineInstance of the VirtualMachine is being migrated
+func (admitter *VMsAdmitter) validateRollback(ar *v1beta1.AdmissionRequest, vm *v1.VirtualMachine) []metav1.StatusCause {
+	revisionName, isRollback := vm.Annotations[v1.RollbackRevisionAnnotation]
+	if ar.Operation != v1beta1.Update || !isRollback {
+		return nil
+	}
+
+	oldVM := &v1.VirtualMachine{}
+	if err := json.Unmarshal(ar.OldObject.Raw, oldVM); err != nil {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeUnexpectedServerResponse,
+			Message: "Could not fetch old VM",
+		}}
+	}
+
+	// the rollback was already admitted
+	if oldVM.Annotations[v1.RollbackRevisionAnnotation] == revisionName {
+		return nil
+	}
+
+	vmi, err := admitter.Client.VirtualMachineInstance(vm.Namespace).Get(vm.Name, &metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeUnexpectedServerResponse,
+			Message: fmt.Sprintf("Could not fetch VMI: %v", err),
+		}}
+	}
+
+	if migration := vmi.Status.MigrationState; migration != nil && !migration.Completed && !migration.Failed {
+		return []metav1.StatusCause{{
+			Type:    metav1.CauseTypeFieldValueNotSupported,
+			Message: fmt.Sprintf("Cannot roll back VM to revision %q while its VMI is migrating", revisionName),
+			Field:   k8sfield.NewPath("metadata", "annotations").Key(v1.RollbackRevisionAnnotation).String(),
+		}}
+	}
+
+	return nil
+}
+
 func getRenameRequest(vm *v1.VirtualMachine) *v1.VirtualMachineStateChangeRequest {
 	for _, req := range vm.Status.StateChangeRequests {
 		if req.Action == v1.RenameRequest {
@@ -25,18 +25,22 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	"k8s.io/api/admission/v1beta1"
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
 	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	"kubevirt.io/kubevirt/pkg/testutils"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
@@ -908,6 +912,54 @@ var _ = Describe("Validating VM Admitter", func() {
 		)
 	})
 
+	Context("with state change requests", func() {
+		start := v1.VirtualMachineStateChangeRequest{Action: v1.StartRequest}
+		stop := v1.VirtualMachineStateChangeRequest{Action: v1.StopRequest, UID: &[]types.UID{"vmi-uid"}[0]}
+
+		admitStatus := func(oldRequests, requests []v1.VirtualMachineStateChangeRequest) *v1beta1.AdmissionResponse {
+			vm := &v1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: k8sv1.NamespaceDefault},
+				Status:     v1.VirtualMachineStatus{StateChangeRequests: oldRequests},
+			}
+			oldObjectBytes, _ := json.Marshal(vm)
+			vm.Status.StateChangeRequests = requests
+			objectBytes, _ := json.Marshal(vm)
+
+			return vmsAdmitter.AdmitStatus(&v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Update,
+					Resource:  webhooks.VirtualMachineGroupVersionResource,
+					OldObject: runtime.RawExtension{
+						Raw: oldObjectBytes,
+					},
+					Object: runtime.RawExtension{
+						Raw: objectBytes,
+					},
+				},
+			})
+		}
+
+		table.DescribeTable("should", func(oldRequests, requests []v1.VirtualMachineStateChangeRequest, allow bool) {
+			resp := admitStatus(oldRequests, requests)
+			Expect(resp.Allowed).To(Equal(allow))
+			if !allow {
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("status.stateChangeRequests"))
+			}
+		},
+			table.Entry("accept a start request", nil, []v1.VirtualMachineStateChangeRequest{start}, true),
+			table.Entry("accept a stop request", nil, []v1.VirtualMachineStateChangeRequest{stop}, true),
+			table.Entry("accept a restart request", nil, []v1.VirtualMachineStateChangeRequest{stop, start}, true),
+			table.Entry("reject a start followed by a stop", nil, []v1.VirtualMachineStateChangeRequest{start, stop}, false),
+			table.Entry("reject two start requests", nil, []v1.VirtualMachineStateChangeRequest{start, start}, false),
+			table.Entry("accept the removal of a processed request", []v1.VirtualMachineStateChangeRequest{stop, start}, []v1.VirtualMachineStateChangeRequest{start}, true),
+			table.Entry("accept the removal of all requests", []v1.VirtualMachineStateChangeRequest{start}, nil, true),
+			table.Entry("accept a stop replacing a pending restart", []v1.VirtualMachineStateChangeRequest{stop, start}, []v1.VirtualMachineStateChangeRequest{stop}, true),
+			table.Entry("reject a restart while a start is pending", []v1.VirtualMachineStateChangeRequest{start}, []v1.VirtualMachineStateChangeRequest{stop, start}, false),
+			table.Entry("reject a start while a stop is pending", []v1.VirtualMachineStateChangeRequest{stop}, []v1.VirtualMachineStateChangeRequest{start}, false),
+		)
+	})
+
 	table.DescribeTable("when snapshot is in progress, should", func(mutateFn func(*v1.VirtualMachine) bool) {
 		vmi := v1.NewMinimalVMI("testvmi")
 		vm := &v1.VirtualMachine{
@@ -960,6 +1012,226 @@ var _ = Describe("Validating VM Admitter", func() {
 			return true
 		}),
 	)
+
+	Context("with instancetype", func() {
+
+		var ctrl *gomock.Controller
+		var instancetypeInterface *kubecli.MockVirtualMachineInstancetypeInterface
+		var vm *v1.VirtualMachine
+
+		admitVM := func() *v1beta1.AdmissionResponse {
+			vmBytes, _ := json.Marshal(vm)
+			return vmsAdmitter.Admit(&v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Resource: webhooks.VirtualMachineGroupVersionResource,
+					Object: runtime.RawExtension{
+						Raw: vmBytes,
+					},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			virtClient := kubecli.NewMockKubevirtClient(ctrl)
+			instancetypeInterface = kubecli.NewMockVirtualMachineInstancetypeInterface(ctrl)
+			virtClient.EXPECT().VirtualMachineInstancetype(gomock.Any()).Return(instancetypeInterface).AnyTimes()
+			vmsAdmitter.Client = virtClient
+
+			instancetypeInterface.EXPECT().Get("small", gomock.Any()).Return(&v1.VirtualMachineInstancetype{
+				ObjectMeta: metav1.ObjectMeta{Name: "small"},
+				Spec: v1.VirtualMachineInstancetypeSpec{
+					CPU:    v1.CPUInstancetype{Guest: 2},
+					Memory: v1.MemoryInstancetype{Guest: resource.MustParse("128Mi")},
+				},
+			}, nil).AnyTimes()
+
+			vm = &v1.VirtualMachine{
+				Spec: v1.VirtualMachineSpec{
+					Running:      &notRunning,
+					Instancetype: &v1.InstancetypeMatcher{Name: "small"},
+					Template: &v1.VirtualMachineInstanceTemplateSpec{
+						Spec: v1.VirtualMachineInstanceSpec{},
+					},
+				},
+			}
+			enableFeatureGate(virtconfig.InstancetypeGate)
+		})
+
+		AfterEach(func() {
+			disableFeatureGates()
+			ctrl.Finish()
+		})
+
+		It("should accept a VM whose memory comes from the instancetype", func() {
+			resp := admitVM()
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should reject a VM if the feature gate is not enabled", func() {
+			disableFeatureGates()
+			resp := admitVM()
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec"))
+		})
+
+		It("should reject a VM referencing a missing instancetype", func() {
+			instancetypeInterface.EXPECT().Get("missing", gomock.Any()).Return(nil, errors.NewNotFound(v1.Resource("virtualmachineinstancetypes"), "missing"))
+			vm.Spec.Instancetype.Name = "missing"
+			resp := admitVM()
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.instancetype"))
+		})
+
+		It("should reject a VM overriding fields of the instancetype", func() {
+			vm.Spec.Template.Spec.Domain.CPU = &v1.CPU{Cores: 4}
+			vm.Spec.Template.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("64Mi"),
+			}
+			resp := admitVM()
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(2))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.template.spec.domain.cpu"))
+			Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.template.spec.domain.resources.requests.memory"))
+		})
+	})
+
+	Context("with rollback", func() {
+
+		var ctrl *gomock.Controller
+		var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+		var vm *v1.VirtualMachine
+
+		admitRollback := func(revisionName string) *v1beta1.AdmissionResponse {
+			oldVMBytes, _ := json.Marshal(vm)
+			vm.Annotations = map[string]string{v1.RollbackRevisionAnnotation: revisionName}
+			vmBytes, _ := json.Marshal(vm)
+			return vmsAdmitter.Admit(&v1beta1.AdmissionReview{
+				Request: &v1beta1.AdmissionRequest{
+					Operation: v1beta1.Update,
+					Resource:  webhooks.VirtualMachineGroupVersionResource,
+					OldObject: runtime.RawExtension{
+						Raw: oldVMBytes,
+					},
+					Object: runtime.RawExtension{
+						Raw: vmBytes,
+					},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			virtClient := kubecli.NewMockKubevirtClient(ctrl)
+			vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+			virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+			vmsAdmitter.Client = virtClient
+
+			vm = &v1.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: k8sv1.NamespaceDefault},
+				Spec: v1.VirtualMachineSpec{
+					Running: &notRunning,
+					Template: &v1.VirtualMachineInstanceTemplateSpec{
+						Spec: v1.NewMinimalVMI("testvmi").Spec,
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		table.DescribeTable("should", func(migrationState *v1.VirtualMachineInstanceMigrationState, allow bool) {
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Status.MigrationState = migrationState
+			vmiInterface.EXPECT().Get("testvm", gomock.Any()).Return(vmi, nil)
+
+			resp := admitRollback("testvm-template-1")
+			Expect(resp.Allowed).To(Equal(allow))
+			if !allow {
+				Expect(resp.Result.Details.Causes).To(HaveLen(1))
+				Expect(resp.Result.Details.Causes[0].Field).To(Equal("metadata.annotations[kubevirt.io/rollback-revision]"))
+			}
+		},
+			table.Entry("accept a rollback if the VMI is not migrating", nil, true),
+			table.Entry("accept a rollback if the migration of the VMI completed", &v1.VirtualMachineInstanceMigrationState{Completed: true}, true),
+			table.Entry("reject a rollback while the VMI is migrating", &v1.VirtualMachineInstanceMigrationState{}, false),
+		)
+
+		It("should accept a rollback if the VM has no VMI", func() {
+			vmiInterface.EXPECT().Get("testvm", gomock.Any()).Return(nil, errors.NewNotFound(v1.Resource("virtualmachineinstances"), "testvm"))
+
+			resp := admitRollback("testvm-template-1")
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("should not check the VMI again once the rollback was admitted", func() {
+			vm.Annotations = map[string]string{v1.RollbackRevisionAnnotation: "testvm-template-1"}
+
+			resp := admitRollback("testvm-template-1")
+			Expect(resp.Allowed).To(BeTrue())
+		})
+	})
+
+	Context("with a placement", func() {
+		groupAffinity := func(group string) *k8sv1.Affinity {
+			return &k8sv1.Affinity{PodAffinity: &k8sv1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []k8sv1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{v1.VirtualMachineGroupLabel: group},
+					},
+					TopologyKey: k8sv1.LabelHostname,
+				}},
+			}}
+		}
+
+		table.DescribeTable("should validate the group membership", func(placement *v1.VirtualMachinePlacement, labels map[string]string, affinity *k8sv1.Affinity, expectedField string) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Labels = labels
+			vmi.Spec.Affinity = affinity
+			spec := &v1.VirtualMachineSpec{
+				Running:   &notRunning,
+				Placement: placement,
+				Template: &v1.VirtualMachineInstanceTemplateSpec{
+					ObjectMeta: vmi.ObjectMeta,
+					Spec:       vmi.Spec,
+				},
+			}
+
+			causes := validatePlacement(k8sfield.NewPath("spec"), spec)
+			if expectedField == "" {
+				Expect(causes).To(BeEmpty())
+			} else {
+				Expect(causes).To(HaveLen(1))
+				Expect(causes[0].Field).To(Equal(expectedField))
+			}
+		},
+			table.Entry("accept a VM without placement", nil, nil, nil, ""),
+			table.Entry("accept a group spread across zones",
+				&v1.VirtualMachinePlacement{Group: "database", SpreadAcross: v1.PlacementTopologyZone, Policy: v1.PlacementPolicyPreferred}, nil, nil, ""),
+			table.Entry("accept a template labeled with the same group",
+				&v1.VirtualMachinePlacement{Group: "database"}, map[string]string{v1.VirtualMachineGroupLabel: "database"}, nil, ""),
+			table.Entry("accept an affinity to another group",
+				&v1.VirtualMachinePlacement{Group: "database"}, nil, groupAffinity("frontend"), ""),
+			table.Entry("reject a group label without placement",
+				nil, map[string]string{v1.VirtualMachineGroupLabel: "database"}, nil, "spec.template.metadata.labels[kubevirt.io/vm-group]"),
+			table.Entry("reject an empty group",
+				&v1.VirtualMachinePlacement{}, nil, nil, "spec.placement.group"),
+			table.Entry("reject a group which is not a label value",
+				&v1.VirtualMachinePlacement{Group: "data base"}, nil, nil, "spec.placement.group"),
+			table.Entry("reject an unknown topology",
+				&v1.VirtualMachinePlacement{Group: "database", SpreadAcross: "Rack"}, nil, nil, "spec.placement.spreadAcross"),
+			table.Entry("reject an unknown policy",
+				&v1.VirtualMachinePlacement{Group: "database", Policy: "Sometimes"}, nil, nil, "spec.placement.policy"),
+			table.Entry("reject a template joining another group",
+				&v1.VirtualMachinePlacement{Group: "database"}, map[string]string{v1.VirtualMachineGroupLabel: "frontend"}, nil, "spec.template.metadata.labels[kubevirt.io/vm-group]"),
+			table.Entry("reject a required affinity to the own group",
+				&v1.VirtualMachinePlacement{Group: "database"}, nil, groupAffinity("database"), "spec.template.spec.affinity.podAffinity.requiredDuringSchedulingIgnoredDuringExecution[0]"),
+		)
+	})
 })
 
 func makeCloneAdmitFunc(expectedSourceNamespace, expectedPVCName, expectedTargetNamespace, expectedServiceAccount string) CloneAuthFunc {
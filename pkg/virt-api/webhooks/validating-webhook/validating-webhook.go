@@ -22,6 +22,8 @@ package validating_webhook
 import (
 	"net/http"
 
+	"k8s.io/client-go/tools/record"
+
 	"kubevirt.io/client-go/kubecli"
 	validating_webhooks "kubevirt.io/kubevirt/pkg/util/webhooks/validating-webhooks"
 	"kubevirt.io/kubevirt/pkg/virt-api/webhooks/validating-webhook/admitters"
@@ -63,3 +65,7 @@ func ServeVMSnapshots(resp http.ResponseWriter, req *http.Request, clusterConfig
 func ServeStatusValidation(resp http.ResponseWriter, req *http.Request) {
 	validating_webhooks.Serve(resp, req, &admitters.StatusAdmitter{})
 }
+
+func ServePodEvictionInterceptor(resp http.ResponseWriter, req *http.Request, clusterConfig *virtconfig.ClusterConfig, virtCli kubecli.KubevirtClient, recorder record.EventRecorder) {
+	validating_webhooks.Serve(resp, req, admitters.NewPodEvictionAdmitter(clusterConfig, virtCli, recorder))
+}
@@ -20,8 +20,13 @@
 package virtconfig
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +34,7 @@ import (
 	k8sv1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/tools/cache"
 
@@ -52,14 +58,29 @@ const (
 	NetworkInterfaceKey               = "default-network-interface"
 	PermitSlirpInterface              = "permitSlirpInterface"
 	PermitBridgeInterfaceOnPodNetwork = "permitBridgeInterfaceOnPodNetwork"
+	IstioAwareMasquerade              = "istioAwareMasquerade"
 	NodeDrainTaintDefaultKey          = "kubevirt.io/drain"
 	SmbiosConfigKey                   = "smbios"
 	SELinuxLauncherTypeKey            = "selinuxLauncherType"
+	SELinuxLauncherTypesKey           = "selinuxLauncherTypes"
 	SupportedGuestAgentVersionsKey    = "supported-guest-agent"
 	OVMFPathKey                       = "ovmfPath"
 	MemBalloonStatsPeriod             = "memBalloonStatsPeriod"
+	PermittedHostDevicesKey           = "permittedHostDevices"
+	KSMConfigurationKey               = "ksmConfiguration"
+	MemBalloonFreePageReportingKey    = "memBalloonFreePageReporting"
+	MultiQueueConfigurationKey        = "multiQueueConfiguration"
+	DefaultNetworkPolicyKey           = "defaultNetworkPolicy"
+	AuditConfigurationKey             = "audit"
+	NodeFencingKey                    = "nodeFencing"
+	SeccompConfigurationKey           = "seccompConfiguration"
+	MetricsConfigurationKey           = "metrics"
+	FilesystemOverheadKey             = "filesystemOverhead"
 )
 
+// selinuxTypeRegex matches the identifiers SELinux types are named with
+var selinuxTypeRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 type ConfigModifiedFn func()
 
 // NewClusterConfig represents the `kubevirt-config` config map. It can be used to live-update
@@ -72,31 +93,21 @@ func NewClusterConfig(configMapInformer cache.SharedIndexInformer,
 	kubeVirtInformer cache.SharedIndexInformer,
 	namespace string) *ClusterConfig {
 
-	defaultConfig := defaultClusterConfig()
+	c := newClusterConfig(configMapInformer.GetStore(), crdInformer.GetStore(), kubeVirtInformer.GetStore(), namespace)
 
-	c := &ClusterConfig{
-		configMapInformer: configMapInformer,
-		crdInformer:       crdInformer,
-		kubeVirtInformer:  kubeVirtInformer,
-		lock:              &sync.Mutex{},
-		namespace:         namespace,
-		lastValidConfig:   defaultConfig,
-		defaultConfig:     defaultConfig,
-	}
-
-	c.configMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	configMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.configAddedDeleted,
 		DeleteFunc: c.configAddedDeleted,
 		UpdateFunc: c.configUpdated,
 	})
 
-	c.crdInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	crdInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.crdAddedDeleted,
 		DeleteFunc: c.crdAddedDeleted,
 		UpdateFunc: c.crdUpdated,
 	})
 
-	c.kubeVirtInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	kubeVirtInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.configAddedDeleted,
 		UpdateFunc: c.configUpdated,
 	})
@@ -104,6 +115,42 @@ func NewClusterConfig(configMapInformer cache.SharedIndexInformer,
 	return c
 }
 
+// NewClusterConfigFromObjects returns the config of objects which were fetched once, e.g. by a
+// client outside of the cluster. The config is not updated when the objects change.
+func NewClusterConfigFromObjects(configMap *k8sv1.ConfigMap,
+	crds []*extv1beta1.CustomResourceDefinition,
+	kubeVirt *v1.KubeVirt,
+	namespace string) *ClusterConfig {
+
+	configMapStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	crdStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	kubeVirtStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if configMap != nil {
+		configMapStore.Add(configMap)
+	}
+	for _, crd := range crds {
+		crdStore.Add(crd)
+	}
+	if kubeVirt != nil {
+		kubeVirtStore.Add(kubeVirt)
+	}
+	return newClusterConfig(configMapStore, crdStore, kubeVirtStore, namespace)
+}
+
+func newClusterConfig(configMapStore cache.Store, crdStore cache.Store, kubeVirtStore cache.Store, namespace string) *ClusterConfig {
+	defaultConfig := defaultClusterConfig()
+
+	return &ClusterConfig{
+		configMapStore:  configMapStore,
+		crdStore:        crdStore,
+		kubeVirtStore:   kubeVirtStore,
+		lock:            &sync.Mutex{},
+		namespace:       namespace,
+		lastValidConfig: defaultConfig,
+		defaultConfig:   defaultConfig,
+	}
+}
+
 func (c *ClusterConfig) configAddedDeleted(obj interface{}) {
 	go c.GetConfig()
 	c.lock.Lock()
@@ -150,12 +197,14 @@ func (c *ClusterConfig) crdUpdated(old, cur interface{}) {
 
 func defaultClusterConfig() *v1.KubeVirtConfiguration {
 	parallelOutboundMigrationsPerNodeDefault := ParallelOutboundMigrationsPerNodeDefault
+	parallelInboundMigrationsPerNodeDefault := ParallelInboundMigrationsPerNodeDefault
 	parallelMigrationsPerClusterDefault := ParallelMigrationsPerClusterDefault
 	bandwithPerMigrationDefault := resource.MustParse(BandwithPerMigrationDefault)
 	nodeDrainTaintDefaultKey := NodeDrainTaintDefaultKey
 	allowAutoConverge := MigrationAllowAutoConverge
 	progressTimeout := MigrationProgressTimeout
 	completionTimeoutPerGiB := MigrationCompletionTimeoutPerGiB
+	retryLimit := MigrationRetryLimitDefault
 	cpuRequestDefault := resource.MustParse(DefaultCPURequest)
 	emulatedMachinesDefault := strings.Split(DefaultEmulatedMachines, ",")
 	nodeSelectorsDefault, _ := parseNodeSelectors(DefaultNodeSelectors)
@@ -178,12 +227,15 @@ func defaultClusterConfig() *v1.KubeVirtConfiguration {
 		MigrationConfiguration: &v1.MigrationConfiguration{
 			ParallelMigrationsPerCluster:      &parallelMigrationsPerClusterDefault,
 			ParallelOutboundMigrationsPerNode: &parallelOutboundMigrationsPerNodeDefault,
+			ParallelInboundMigrationsPerNode:  &parallelInboundMigrationsPerNodeDefault,
 			BandwidthPerMigration:             &bandwithPerMigrationDefault,
 			NodeDrainTaintKey:                 &nodeDrainTaintDefaultKey,
 			ProgressTimeout:                   &progressTimeout,
 			CompletionTimeoutPerGiB:           &completionTimeoutPerGiB,
 			UnsafeMigrationOverride:           DefaultUnsafeMigrationOverride,
 			AllowAutoConverge:                 allowAutoConverge,
+			RetryLimit:                        &retryLimit,
+			DisableTLS:                        MigrationDisableTLSDefault,
 		},
 		MachineType:      DefaultMachineType,
 		CPURequest:       &cpuRequestDefault,
@@ -192,19 +244,21 @@ func defaultClusterConfig() *v1.KubeVirtConfiguration {
 			NetworkInterface:                  defaultNetworkInterface,
 			PermitSlirpInterface:              DefaultPermitSlirpInterface,
 			PermitBridgeInterfaceOnPodNetwork: DefaultPermitBridgeInterfaceOnPodNetwork,
+			IstioAwareMasquerade:              DefaultIstioAwareMasquerade,
 		},
 		SMBIOSConfig:                SmbiosDefaultConfig,
 		SELinuxLauncherType:         DefaultSELinuxLauncherType,
 		SupportedGuestAgentVersions: supportedQEMUGuestAgentVersions,
 		OVMFPath:                    DefaultOVMFPath,
 		MemBalloonStatsPeriod:       DefaultMemBalloonStatsPeriod,
+		MemBalloonFreePageReporting: DefaultMemBalloonFreePageReporting,
 	}
 }
 
 type ClusterConfig struct {
-	configMapInformer                cache.SharedIndexInformer
-	crdInformer                      cache.SharedIndexInformer
-	kubeVirtInformer                 cache.SharedIndexInformer
+	configMapStore                   cache.Store
+	crdStore                         cache.Store
+	kubeVirtStore                    cache.Store
 	namespace                        string
 	lock                             *sync.Mutex
 	lastValidConfig                  *v1.KubeVirtConfiguration
@@ -244,6 +298,143 @@ func setConfigFromConfigMap(config *v1.KubeVirtConfiguration, configMap *k8sv1.C
 		}
 	}
 
+	// set permitted host devices
+	permittedHostDevices := strings.TrimSpace(configMap.Data[PermittedHostDevicesKey])
+	if permittedHostDevices != "" {
+		config.PermittedHostDevices = &v1.PermittedHostDevices{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(permittedHostDevices), 1024).Decode(config.PermittedHostDevices)
+		if err != nil {
+			return fmt.Errorf("failed to parse permitted host devices config: %v", err)
+		}
+	}
+
+	// set the kernel samepage merging options
+	ksmConfiguration := strings.TrimSpace(configMap.Data[KSMConfigurationKey])
+	if ksmConfiguration != "" {
+		config.KSMConfiguration = &v1.KSMConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(ksmConfiguration), 1024).Decode(config.KSMConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to parse KSM config: %v", err)
+		}
+		if threshold := config.KSMConfiguration.MemoryPressureThreshold; threshold != nil && *threshold > 100 {
+			return fmt.Errorf("invalid memoryPressureThreshold in KSM config, %d is above 100 percent", *threshold)
+		}
+	}
+
+	// set the multi-queue auto tuning options
+	multiQueueConfiguration := strings.TrimSpace(configMap.Data[MultiQueueConfigurationKey])
+	if multiQueueConfiguration != "" {
+		config.MultiQueueConfiguration = &v1.MultiQueueConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(multiQueueConfiguration), 1024).Decode(config.MultiQueueConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to parse multi-queue config: %v", err)
+		}
+		if maxQueues := config.MultiQueueConfiguration.MaxQueues; maxQueues != nil && (*maxQueues < 1 || *maxQueues > MaxMultiQueueQueues) {
+			return fmt.Errorf("invalid maxQueues in multi-queue config, %d is not between 1 and %d", *maxQueues, MaxMultiQueueQueues)
+		}
+	}
+
+	// set the network policy for the virt-launcher pods
+	defaultNetworkPolicy := strings.TrimSpace(configMap.Data[DefaultNetworkPolicyKey])
+	if defaultNetworkPolicy != "" {
+		config.DefaultNetworkPolicy = &v1.DefaultNetworkPolicyConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(defaultNetworkPolicy), 1024).Decode(config.DefaultNetworkPolicy)
+		if err != nil {
+			return fmt.Errorf("failed to parse default network policy config: %v", err)
+		}
+		switch mode := config.DefaultNetworkPolicy.Mode; mode {
+		case v1.DefaultNetworkPolicyCreate, v1.DefaultNetworkPolicyValidate:
+		default:
+			return fmt.Errorf("invalid mode in default network policy config: %v", mode)
+		}
+		for _, cidr := range config.DefaultNetworkPolicy.BlockedCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("invalid blocked CIDR in default network policy config: %v", err)
+			}
+		}
+	}
+
+	// set the audit sink of virt-api
+	auditConfiguration := strings.TrimSpace(configMap.Data[AuditConfigurationKey])
+	if auditConfiguration != "" {
+		config.AuditConfiguration = &v1.AuditConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(auditConfiguration), 1024).Decode(config.AuditConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to parse audit config: %v", err)
+		}
+		if webhookURL := config.AuditConfiguration.WebhookURL; webhookURL != "" {
+			u, err := url.Parse(webhookURL)
+			if err != nil {
+				return fmt.Errorf("invalid webhookURL in audit config: %v", err)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("invalid webhookURL in audit config, the scheme has to be http or https")
+			}
+		}
+		if caBundle := config.AuditConfiguration.CABundle; caBundle != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(caBundle)) {
+			return fmt.Errorf("invalid caBundle in audit config, no PEM encoded certificate found")
+		}
+	}
+
+	// set the fencing of vmis on unresponsive nodes
+	nodeFencing := strings.TrimSpace(configMap.Data[NodeFencingKey])
+	if nodeFencing != "" {
+		config.NodeFencing = &v1.NodeFencingConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(nodeFencing), 1024).Decode(config.NodeFencing)
+		if err != nil {
+			return fmt.Errorf("failed to parse node fencing config: %v", err)
+		}
+		if gracePeriod := config.NodeFencing.GracePeriod; gracePeriod != nil && gracePeriod.Duration < 0 {
+			return fmt.Errorf("invalid gracePeriod in node fencing config, it must not be negative")
+		}
+		if confirmation := config.NodeFencing.Confirmation; confirmation != nil {
+			for _, resource := range confirmation.Resources {
+				if resource.Version == "" || resource.Resource == "" {
+					return fmt.Errorf("invalid confirmation resource in node fencing config, version and resource are required")
+				}
+			}
+		}
+	}
+
+	// set the seccomp profile of the virt-launcher pods
+	seccompConfiguration := strings.TrimSpace(configMap.Data[SeccompConfigurationKey])
+	if seccompConfiguration != "" {
+		config.SeccompConfiguration = &v1.SeccompConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(seccompConfiguration), 1024).Decode(config.SeccompConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to parse seccomp config: %v", err)
+		}
+		if err := validateSeccompConfiguration(config.SeccompConfiguration); err != nil {
+			return err
+		}
+	}
+
+	// set the names of the renamed metrics
+	metricsConfiguration := strings.TrimSpace(configMap.Data[MetricsConfigurationKey])
+	if metricsConfiguration != "" {
+		config.MetricsConfiguration = &v1.MetricsConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(metricsConfiguration), 1024).Decode(config.MetricsConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to parse metrics config: %v", err)
+		}
+		if err := validateMetricsConfiguration(config.MetricsConfiguration); err != nil {
+			return err
+		}
+	}
+
+	// set the filesystem overhead of the storage classes
+	filesystemOverhead := strings.TrimSpace(configMap.Data[FilesystemOverheadKey])
+	if filesystemOverhead != "" {
+		config.FilesystemOverhead = &v1.FilesystemOverheadConfiguration{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(filesystemOverhead), 1024).Decode(config.FilesystemOverhead)
+		if err != nil {
+			return fmt.Errorf("failed to parse filesystemOverhead config: %v", err)
+		}
+		if err := validateFilesystemOverhead(config.FilesystemOverhead); err != nil {
+			return err
+		}
+	}
+
 	// set image pull policy
 	policy := strings.TrimSpace(configMap.Data[ImagePullPolicyKey])
 	switch policy {
@@ -272,6 +463,19 @@ func setConfigFromConfigMap(config *v1.KubeVirtConfiguration, configMap *k8sv1.C
 		return fmt.Errorf("invalid debug.useEmulation in config: %v", useEmulation)
 	}
 
+	// set if the memory balloon reports free pages by default
+	freePageReporting := strings.TrimSpace(configMap.Data[MemBalloonFreePageReportingKey])
+	switch freePageReporting {
+	case "":
+		// keep the default
+	case "true":
+		config.MemBalloonFreePageReporting = true
+	case "false":
+		config.MemBalloonFreePageReporting = false
+	default:
+		return fmt.Errorf("invalid memBalloonFreePageReporting in config: %v", freePageReporting)
+	}
+
 	// set machine type
 	if machineType := strings.TrimSpace(configMap.Data[MachineTypeKey]); machineType != "" {
 		config.MachineType = machineType
@@ -343,6 +547,19 @@ func setConfigFromConfigMap(config *v1.KubeVirtConfiguration, configMap *k8sv1.C
 		return fmt.Errorf("invalid value for permitBridgeInterfaceOnPodNetwork in config: %v", permitBridge)
 	}
 
+	// adapt masquerade to istio sidecars
+	istioAware := strings.TrimSpace(configMap.Data[IstioAwareMasquerade])
+	switch istioAware {
+	case "":
+		// keep the default
+	case "false":
+		config.NetworkConfiguration.IstioAwareMasquerade = false
+	case "true":
+		config.NetworkConfiguration.IstioAwareMasquerade = true
+	default:
+		return fmt.Errorf("invalid value for istioAwareMasquerade in config: %v", istioAware)
+	}
+
 	// set default network interface
 	iface := strings.TrimSpace(configMap.Data[NetworkInterfaceKey])
 	switch iface {
@@ -358,6 +575,19 @@ func setConfigFromConfigMap(config *v1.KubeVirtConfiguration, configMap *k8sv1.C
 		config.SELinuxLauncherType = selinuxLauncherType
 	}
 
+	// set the SELinux types of the workload classes
+	selinuxLauncherTypes := strings.TrimSpace(configMap.Data[SELinuxLauncherTypesKey])
+	if selinuxLauncherTypes != "" {
+		config.SELinuxLauncherTypes = map[string]string{}
+		err := yaml.NewYAMLOrJSONDecoder(strings.NewReader(selinuxLauncherTypes), 1024).Decode(&config.SELinuxLauncherTypes)
+		if err != nil {
+			return fmt.Errorf("failed to parse selinuxLauncherTypes: %v", err)
+		}
+		if err := validateSELinuxLauncherTypes(config.SELinuxLauncherTypes); err != nil {
+			return err
+		}
+	}
+
 	if supportedGuestAgentVersions := strings.TrimSpace(configMap.Data[SupportedGuestAgentVersionsKey]); supportedGuestAgentVersions != "" {
 		vals := strings.Split(strings.TrimRight(supportedGuestAgentVersions, ","), ",")
 		for i := range vals {
@@ -398,9 +628,96 @@ func setConfigFromKubeVirt(config *v1.KubeVirtConfiguration, kv *v1.KubeVirt) er
 		return err
 	}
 
+	// the types and the seccomp profile end up in the pod manifests, an invalid one would
+	// break every launcher pod
+	if err := validateSELinuxLauncherTypes(config.SELinuxLauncherTypes); err != nil {
+		return err
+	}
+	if err := validateSeccompConfiguration(config.SeccompConfiguration); err != nil {
+		return err
+	}
+	if err := validateMetricsConfiguration(config.MetricsConfiguration); err != nil {
+		return err
+	}
+	return validateFilesystemOverhead(config.FilesystemOverhead)
+}
+
+func validateSELinuxLauncherTypes(selinuxLauncherTypes map[string]string) error {
+	for class, selinuxType := range selinuxLauncherTypes {
+		if errs := validation.IsDNS1123Label(class); len(errs) > 0 {
+			return fmt.Errorf("invalid workload class %s in selinuxLauncherTypes: %s", class, strings.Join(errs, ", "))
+		}
+		if !selinuxTypeRegex.MatchString(selinuxType) {
+			return fmt.Errorf("invalid SELinux type %s of the workload class %s in selinuxLauncherTypes", selinuxType, class)
+		}
+	}
+	return nil
+}
+
+func validateSeccompConfiguration(seccompConfiguration *v1.SeccompConfiguration) error {
+	if seccompConfiguration == nil {
+		return nil
+	}
+	switch seccompConfiguration.Type {
+	case "", v1.SeccompProfileRuntimeDefault, v1.SeccompProfileKubeVirt:
+		if seccompConfiguration.CustomProfile != "" {
+			return fmt.Errorf("invalid seccomp config, customProfile is only allowed with the %s type", v1.SeccompProfileCustom)
+		}
+	case v1.SeccompProfileCustom:
+		customProfile := seccompConfiguration.CustomProfile
+		if customProfile == "" {
+			return fmt.Errorf("invalid seccomp config, customProfile is required with the %s type", v1.SeccompProfileCustom)
+		}
+		if filepath.IsAbs(customProfile) || strings.HasPrefix(filepath.Clean(customProfile), "..") {
+			return fmt.Errorf("invalid customProfile %s in seccomp config, it has to be relative to the seccomp directory of the kubelet", customProfile)
+		}
+	default:
+		return fmt.Errorf("invalid type in seccomp config: %v", seccompConfiguration.Type)
+	}
 	return nil
 }
 
+func validateMetricsConfiguration(metricsConfiguration *v1.MetricsConfiguration) error {
+	if metricsConfiguration == nil {
+		return nil
+	}
+	switch metricsConfiguration.NamingMode {
+	case "", v1.MetricsNamingLegacy, v1.MetricsNamingCurrent, v1.MetricsNamingBoth:
+		return nil
+	default:
+		return fmt.Errorf("invalid namingMode in metrics config: %v", metricsConfiguration.NamingMode)
+	}
+}
+
+func validateFilesystemOverhead(filesystemOverhead *v1.FilesystemOverheadConfiguration) error {
+	if filesystemOverhead == nil {
+		return nil
+	}
+	if filesystemOverhead.Global != "" {
+		if _, err := parseFilesystemOverhead(filesystemOverhead.Global); err != nil {
+			return fmt.Errorf("invalid global overhead in filesystemOverhead config: %v", err)
+		}
+	}
+	for storageClass, overhead := range filesystemOverhead.StorageClass {
+		if _, err := parseFilesystemOverhead(overhead); err != nil {
+			return fmt.Errorf("invalid overhead of the storage class %s in filesystemOverhead config: %v", storageClass, err)
+		}
+	}
+	return nil
+}
+
+// parseFilesystemOverhead parses an overhead in percent, the filesystem can not take the whole volume
+func parseFilesystemOverhead(overhead string) (float64, error) {
+	percent, err := strconv.ParseFloat(overhead, 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent < 0 || percent >= 100 {
+		return 0, fmt.Errorf("%s is not a percentage between 0 and 100", overhead)
+	}
+	return percent, nil
+}
+
 // getConfig returns the latest valid parsed config map result, or updates it
 // if a newer version is available.
 // XXX Rework this, to happen mostly in informer callbacks.
@@ -415,7 +732,7 @@ func (c *ClusterConfig) GetConfig() (config *v1.KubeVirtConfiguration) {
 	var resourceType string
 	useConfigMap := false
 
-	if obj, exists, err := c.configMapInformer.GetStore().GetByKey(c.namespace + "/" + ConfigMapName); err != nil {
+	if obj, exists, err := c.configMapStore.GetByKey(c.namespace + "/" + ConfigMapName); err != nil {
 		log.DefaultLogger().Reason(err).Errorf("Error loading the cluster config from ConfigMap cache, falling back to last good resource version '%s'", c.lastValidConfigResourceVersion)
 		return c.lastValidConfig
 	} else if !exists {
@@ -461,7 +778,7 @@ func (c *ClusterConfig) GetConfig() (config *v1.KubeVirtConfiguration) {
 }
 
 func (c *ClusterConfig) getConfigFromKubeVirtCR() *v1.KubeVirt {
-	objects := c.kubeVirtInformer.GetStore().List()
+	objects := c.kubeVirtStore.List()
 	var kubeVirtName string
 	for _, obj := range objects {
 		if kv, ok := obj.(*v1.KubeVirt); ok && kv.DeletionTimestamp == nil {
@@ -475,7 +792,7 @@ func (c *ClusterConfig) getConfigFromKubeVirtCR() *v1.KubeVirt {
 		return nil
 	}
 
-	if obj, exists, err := c.kubeVirtInformer.GetStore().GetByKey(c.namespace + "/" + kubeVirtName); err != nil {
+	if obj, exists, err := c.kubeVirtStore.GetByKey(c.namespace + "/" + kubeVirtName); err != nil {
 		log.DefaultLogger().Reason(err).Errorf("Error loading the cluster config from KubeVirt cache, falling back to last good resource version '%s'", c.lastValidConfigResourceVersion)
 		return nil
 	} else if !exists {
@@ -490,7 +807,7 @@ func (c *ClusterConfig) HasDataVolumeAPI() bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	objects := c.crdInformer.GetStore().List()
+	objects := c.crdStore.List()
 	for _, obj := range objects {
 		if crd, ok := obj.(*extv1beta1.CustomResourceDefinition); ok && crd.DeletionTimestamp == nil {
 			if isDataVolumeCrd(crd) {
@@ -3,14 +3,17 @@ package virtconfig_test
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	kubev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/pointer"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -46,6 +49,18 @@ var _ = Describe("ConfigMap", func() {
 		table.Entry("when unset, GetMemBalloonStatsPeriod should return 10", "", 10),
 		table.Entry("when invalid, GetMemBalloonStatsPeriod should return 10", "invalid", 10))
 
+	table.DescribeTable("when memBalloonFreePageReporting", func(value string, result bool) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.MemBalloonFreePageReportingKey: value},
+		})
+
+		Expect(clusterConfig.IsMemBalloonFreePageReportingEnabled()).To(Equal(result))
+	},
+		table.Entry("is true, IsMemBalloonFreePageReportingEnabled should return true", "true", true),
+		table.Entry("is false, IsMemBalloonFreePageReportingEnabled should return false", "false", false),
+		table.Entry("when unset, IsMemBalloonFreePageReportingEnabled should return false", "", false),
+		table.Entry("when invalid, IsMemBalloonFreePageReportingEnabled should return false", "invalid", false))
+
 	table.DescribeTable(" when useEmulation", func(value string, result bool) {
 		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
 			Data: map[string]string{"debug.useEmulation": value},
@@ -82,6 +97,18 @@ var _ = Describe("ConfigMap", func() {
 		table.Entry("when invalid, IsBridgeInterfaceOnPodNetworkEnabled should return the default", "invalid", true),
 	)
 
+	table.DescribeTable(" when istioAwareMasquerade", func(value string, result bool) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.IstioAwareMasquerade: value},
+		})
+		Expect(clusterConfig.IsIstioAwareMasqueradeEnabled()).To(Equal(result))
+	},
+		table.Entry("is true, IsIstioAwareMasqueradeEnabled should return true", "true", true),
+		table.Entry("is false, IsIstioAwareMasqueradeEnabled should return false", "false", false),
+		table.Entry("when unset, IsIstioAwareMasqueradeEnabled should return false", "", false),
+		table.Entry("when invalid, IsIstioAwareMasqueradeEnabled should return the default", "invalid", false),
+	)
+
 	table.DescribeTable(" when imagePullPolicy", func(value string, result kubev1.PullPolicy) {
 		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
 			Data: map[string]string{virtconfig.ImagePullPolicyKey: value},
@@ -200,7 +227,7 @@ var _ = Describe("ConfigMap", func() {
 
 	It("Should return migration config values if specified as json", func() {
 		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
-			Data: map[string]string{virtconfig.MigrationsConfigKey: `{"parallelOutboundMigrationsPerNode" : "10", "parallelMigrationsPerCluster": "20", "bandwidthPerMigration": "110Mi", "progressTimeout" : "5", "completionTimeoutPerGiB": "5", "unsafeMigrationOverride": "true", "allowAutoConverge": "true"}`},
+			Data: map[string]string{virtconfig.MigrationsConfigKey: `{"parallelOutboundMigrationsPerNode" : "10", "parallelMigrationsPerCluster": "20", "bandwidthPerMigration": "110Mi", "progressTimeout" : "5", "completionTimeoutPerGiB": "5", "unsafeMigrationOverride": "true", "allowAutoConverge": "true", "retryLimit": "7", "parallelInboundMigrationsPerNode": "4", "disableTLS": "true"}`},
 		})
 		result := clusterConfig.GetMigrationConfiguration()
 		Expect(*result.ParallelOutboundMigrationsPerNode).To(BeNumerically("==", 10))
@@ -210,6 +237,9 @@ var _ = Describe("ConfigMap", func() {
 		Expect(*result.CompletionTimeoutPerGiB).To(BeNumerically("==", 5))
 		Expect(result.UnsafeMigrationOverride).To(BeTrue())
 		Expect(result.AllowAutoConverge).To(BeTrue())
+		Expect(*result.RetryLimit).To(BeNumerically("==", 7))
+		Expect(*result.ParallelInboundMigrationsPerNode).To(BeNumerically("==", 4))
+		Expect(result.DisableTLS).To(BeTrue())
 	})
 
 	It("Should return migration config values if specified as yaml", func() {
@@ -232,6 +262,7 @@ var _ = Describe("ConfigMap", func() {
 		Expect(*result.ParallelOutboundMigrationsPerNode).To(BeNumerically("==", 10))
 		Expect(*result.ParallelMigrationsPerCluster).To(BeNumerically("==", 5))
 		Expect(result.BandwidthPerMigration.String()).To(Equal("64Mi"))
+		Expect(result.DisableTLS).To(BeFalse())
 	})
 
 	It("Should update the config if a newer version is available", func() {
@@ -328,6 +359,26 @@ var _ = Describe("ConfigMap", func() {
 		table.Entry("when unset, GetSELinuxLauncherType should return the default", virtconfig.DefaultSELinuxLauncherType, virtconfig.DefaultSELinuxLauncherType),
 	)
 
+	table.DescribeTable(" when SELinuxLauncherTypes", func(value string, class string, result string, known bool) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{
+				virtconfig.SELinuxLauncherTypeKey:  "spc_t",
+				virtconfig.SELinuxLauncherTypesKey: value,
+			},
+		})
+		selinuxLauncherType, isKnown := clusterConfig.GetSELinuxLauncherTypeForClass(class)
+		Expect(selinuxLauncherType).To(Equal(result))
+		Expect(isKnown).To(Equal(known))
+	},
+		table.Entry("when set, GetSELinuxLauncherTypeForClass should return the type of the class", `{"vfio": "virt_vfio_t"}`, "vfio", "virt_vfio_t", true),
+		table.Entry("when set, GetSELinuxLauncherTypeForClass should return the type of custom classes", `{"custom": "virt_custom_t"}`, "custom", "virt_custom_t", true),
+		table.Entry("when the class is unset, GetSELinuxLauncherTypeForClass should fall back to the default class", `{"default": "virt_default_t"}`, "sev", "virt_default_t", true),
+		table.Entry("when unset, GetSELinuxLauncherTypeForClass should fall back to SELinuxLauncherType", "", "vfio", "spc_t", true),
+		table.Entry("when unset, GetSELinuxLauncherTypeForClass should report unknown custom classes", "", "custom", "spc_t", false),
+		table.Entry("when the type is invalid, GetSELinuxLauncherTypeForClass should return the default", `{"vfio": "virt-vfio_t"}`, "vfio", virtconfig.DefaultSELinuxLauncherType, true),
+		table.Entry("when the class is invalid, GetSELinuxLauncherTypeForClass should return the default", `{"Not_A_Label": "virt_vfio_t"}`, "vfio", virtconfig.DefaultSELinuxLauncherType, true),
+	)
+
 	table.DescribeTable(" when OVMFPath", func(value string, result string) {
 		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
 			Data: map[string]string{virtconfig.OVMFPathKey: value},
@@ -339,6 +390,183 @@ var _ = Describe("ConfigMap", func() {
 		table.Entry("when unset, GetOVMFPath should return the default", "", virtconfig.DefaultOVMFPath),
 	)
 
+	It("should parse the permitted host devices", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.PermittedHostDevicesKey: `
+pciHostDevices:
+- pciVendorSelector: "10de:1eb8"
+  resourceName: "nvidia.com/TU104GL_Tesla_T4"
+  externalResourceProvider: true
+- pciVendorSelector: "8086:6f54"
+  resourceName: "intel.com/qat"
+`},
+		})
+		Expect(clusterConfig.GetPermittedHostDevices()).To(Equal(&v1.PermittedHostDevices{
+			PciHostDevices: []v1.PciHostDevice{
+				{PCIVendorSelector: "10de:1eb8", ResourceName: "nvidia.com/TU104GL_Tesla_T4", ExternalResourceProvider: true},
+				{PCIVendorSelector: "8086:6f54", ResourceName: "intel.com/qat"},
+			},
+		}))
+	})
+
+	It("should not permit any host devices by default", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{})
+		Expect(clusterConfig.GetPermittedHostDevices()).To(BeNil())
+	})
+
+	It("should parse the KSM configuration", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.KSMConfigurationKey: `memoryPressureThreshold: 70`},
+		})
+		threshold := uint32(70)
+		Expect(clusterConfig.GetKSMConfiguration()).To(Equal(&v1.KSMConfiguration{MemoryPressureThreshold: &threshold}))
+	})
+
+	It("should not manage KSM by default", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{})
+		Expect(clusterConfig.GetKSMConfiguration()).To(BeNil())
+	})
+
+	It("should reject a KSM memory pressure threshold above 100 percent", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.KSMConfigurationKey: `memoryPressureThreshold: 120`},
+		})
+		Expect(clusterConfig.GetKSMConfiguration()).To(BeNil())
+	})
+
+	It("should parse the multi-queue configuration", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.MultiQueueConfigurationKey: "autoTuning: true\nmaxQueues: 4"},
+		})
+		maxQueues := uint32(4)
+		Expect(clusterConfig.GetMultiQueueConfiguration()).To(Equal(&v1.MultiQueueConfiguration{AutoTuning: true, MaxQueues: &maxQueues}))
+	})
+
+	It("should reject more multi-queue queues than supported", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.MultiQueueConfigurationKey: "autoTuning: true\nmaxQueues: 512"},
+		})
+		Expect(clusterConfig.GetMultiQueueConfiguration()).To(BeNil())
+	})
+
+	It("should parse the default network policy configuration", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.DefaultNetworkPolicyKey: "mode: Create\nblockedCIDRs:\n- 169.254.169.254/32\n- fd00:ec2::254/128"},
+		})
+		Expect(clusterConfig.GetDefaultNetworkPolicy()).To(Equal(&v1.DefaultNetworkPolicyConfiguration{
+			Mode:         v1.DefaultNetworkPolicyCreate,
+			BlockedCIDRs: []string{"169.254.169.254/32", "fd00:ec2::254/128"},
+		}))
+	})
+
+	table.DescribeTable("should reject an invalid default network policy configuration", func(value string) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.DefaultNetworkPolicyKey: value},
+		})
+		Expect(clusterConfig.GetDefaultNetworkPolicy()).To(BeNil())
+	},
+		table.Entry("with an unknown mode", "mode: Enforce"),
+		table.Entry("without a mode", "blockedCIDRs:\n- 169.254.169.254/32"),
+		table.Entry("with an invalid CIDR", "mode: Validate\nblockedCIDRs:\n- 169.254.169.254"),
+	)
+
+	It("should parse the audit configuration", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.AuditConfigurationKey: "webhookURL: https://audit.example.com/events"},
+		})
+		Expect(clusterConfig.GetAuditConfiguration()).To(Equal(&v1.AuditConfiguration{
+			WebhookURL: "https://audit.example.com/events",
+		}))
+	})
+
+	table.DescribeTable("should reject an invalid audit configuration", func(value string) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.AuditConfigurationKey: value},
+		})
+		Expect(clusterConfig.GetAuditConfiguration()).To(BeNil())
+	},
+		table.Entry("with an unsupported scheme", "webhookURL: ftp://audit.example.com/events"),
+		table.Entry("with an invalid CA bundle", "webhookURL: https://audit.example.com/events\ncaBundle: invalid"),
+	)
+
+	table.DescribeTable("should parse the node fencing configuration", func(value string, enabled bool, gracePeriod time.Duration) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.NodeFencingKey: value},
+		})
+		nodeFencing := clusterConfig.GetNodeFencing()
+		Expect(nodeFencing.Enabled).To(Equal(enabled))
+		Expect(nodeFencing.GracePeriod.Duration).To(Equal(gracePeriod))
+	},
+		table.Entry("when unset", "", false, virtconfig.DefaultNodeFencingGracePeriod),
+		table.Entry("when enabled", "enabled: true", true, virtconfig.DefaultNodeFencingGracePeriod),
+		table.Entry("with a grace period", "enabled: true\ngracePeriod: 10m", true, 10*time.Minute),
+		table.Entry("with a negative grace period", "enabled: true\ngracePeriod: -1m", false, virtconfig.DefaultNodeFencingGracePeriod),
+	)
+
+	table.DescribeTable("should default the node fencing confirmation rules", func(value string, notReady bool) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.NodeFencingKey: value},
+		})
+		confirmation := clusterConfig.GetNodeFencing().Confirmation
+		Expect(*confirmation.NotReady).To(Equal(notReady))
+	},
+		table.Entry("to the node not being ready", "enabled: true", true),
+		table.Entry("to the chosen rules", "enabled: true\nconfirmation:\n  outOfServiceTaint: true", false),
+		table.Entry("to the chosen rules and the node not being ready", "enabled: true\nconfirmation:\n  outOfServiceTaint: true\n  notReady: true", true),
+	)
+
+	It("should reject confirmation resources without a version", func() {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.NodeFencingKey: "enabled: true\nconfirmation:\n  resources:\n  - resource: fenceagentsremediations"},
+		})
+		Expect(clusterConfig.GetNodeFencing().Enabled).To(BeFalse())
+	})
+
+	table.DescribeTable("should parse the seccomp configuration", func(value string, result *v1.SeccompConfiguration) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.SeccompConfigurationKey: value},
+		})
+		Expect(clusterConfig.GetSeccompConfiguration()).To(Equal(result))
+	},
+		table.Entry("when unset", "", nil),
+		table.Entry("with the RuntimeDefault type", "type: RuntimeDefault", &v1.SeccompConfiguration{Type: v1.SeccompProfileRuntimeDefault}),
+		table.Entry("with the KubeVirt type", "type: KubeVirt", &v1.SeccompConfiguration{Type: v1.SeccompProfileKubeVirt}),
+		table.Entry("with a custom profile", "type: Custom\ncustomProfile: profiles/qemu.json", &v1.SeccompConfiguration{Type: v1.SeccompProfileCustom, CustomProfile: "profiles/qemu.json"}),
+		table.Entry("with an unknown type", "type: Unconfined", nil),
+		table.Entry("with the Custom type without a profile", "type: Custom", nil),
+		table.Entry("with a custom profile outside of the seccomp directory", "type: Custom\ncustomProfile: ../qemu.json", nil),
+		table.Entry("with an absolute custom profile", "type: Custom\ncustomProfile: /etc/qemu.json", nil),
+		table.Entry("with a custom profile and another type", "type: KubeVirt\ncustomProfile: profiles/qemu.json", nil),
+	)
+
+	table.DescribeTable("should parse the naming mode of the metrics", func(value string, result v1.MetricsNamingMode) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.MetricsConfigurationKey: value},
+		})
+		Expect(clusterConfig.GetMetricsNamingMode()).To(Equal(result))
+	},
+		table.Entry("when unset", "", v1.MetricsNamingBoth),
+		table.Entry("with the Legacy mode", "namingMode: Legacy", v1.MetricsNamingLegacy),
+		table.Entry("with the Current mode", "namingMode: Current", v1.MetricsNamingCurrent),
+		table.Entry("with the Both mode", "namingMode: Both", v1.MetricsNamingBoth),
+		table.Entry("with an unknown mode", "namingMode: New", v1.MetricsNamingBoth),
+	)
+
+	table.DescribeTable("should parse the filesystem overhead", func(value string, storageClass *string, result float64) {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{
+			Data: map[string]string{virtconfig.FilesystemOverheadKey: value},
+		})
+		Expect(clusterConfig.GetFilesystemOverhead(storageClass)).To(Equal(result))
+	},
+		table.Entry("when unset", "", nil, float64(0)),
+		table.Entry("with the global overhead", `global: "5.5"`, nil, 5.5),
+		table.Entry("with the global overhead for a storage class without its own", "global: \"5.5\"\nstorageClass:\n  local: \"10\"", pointer.StringPtr("nfs"), 5.5),
+		table.Entry("with the overhead of a storage class", "global: \"5.5\"\nstorageClass:\n  local: \"10\"", pointer.StringPtr("local"), float64(10)),
+		table.Entry("with an invalid global overhead", `global: "five"`, nil, float64(0)),
+		table.Entry("with an overhead of the whole volume", "storageClass:\n  local: \"100\"", pointer.StringPtr("local"), float64(0)),
+		table.Entry("with a negative overhead", "storageClass:\n  local: \"-1\"", pointer.StringPtr("local"), float64(0)),
+	)
+
 	table.DescribeTable("when kubevirt CR holds config", func(value string, result v1.KubeVirtConfiguration) {
 		clusterConfig, _, _, _ := testutils.NewFakeClusterConfigUsingKV(&v1.KubeVirt{
 			ObjectMeta: metav1.ObjectMeta{
@@ -412,4 +640,25 @@ var _ = Describe("ConfigMap", func() {
 		emulation = clusterConfig.IsUseEmulation()
 		Expect(emulation).To(BeFalse())
 	})
+
+	It("should read the config of objects fetched once", func() {
+		configMap := &kubev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: virtconfig.ConfigMapName, Namespace: "kubevirt", ResourceVersion: "1"},
+			Data:       map[string]string{virtconfig.UseEmulationKey: "true"},
+		}
+		dataVolumeCRD := &extv1beta1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "datavolumes.cdi.kubevirt.io"},
+			Spec: extv1beta1.CustomResourceDefinitionSpec{
+				Names: extv1beta1.CustomResourceDefinitionNames{Kind: "DataVolume"},
+			},
+		}
+
+		clusterConfig := virtconfig.NewClusterConfigFromObjects(configMap, []*extv1beta1.CustomResourceDefinition{dataVolumeCRD}, nil, "kubevirt")
+		Expect(clusterConfig.IsUseEmulation()).To(BeTrue())
+		Expect(clusterConfig.HasDataVolumeAPI()).To(BeTrue())
+
+		clusterConfig = virtconfig.NewClusterConfigFromObjects(nil, nil, nil, "kubevirt")
+		Expect(clusterConfig.IsUseEmulation()).To(BeFalse())
+		Expect(clusterConfig.HasDataVolumeAPI()).To(BeFalse())
+	})
 })
@@ -34,6 +34,21 @@ const (
 	QATGate               = "QAT"
 	SnapshotGate          = "Snapshot"
 	HostDiskGate          = "HostDisk"
+	TPMGate               = "VirtualTPM"
+	NUMAGate              = "NUMA"
+	HostDevicesGate       = "HostDevices"
+	VirtIOFSGate          = "ExperimentalVirtiofsSupport"
+	VMExportGate          = "VMExport"
+	IncrementalBackupGate = "IncrementalBackup"
+	SEVGate               = "WorkloadEncryptionSEV"
+	SEVLiveMigrationGate  = "SEVLiveMigration"
+	VMPreemptionGate      = "VMPreemption"
+	InstancetypeGate      = "Instancetype"
+	MacvtapGate           = "Macvtap"
+	PasstGate             = "Passt"
+	NonRootGate           = "NonRoot"
+	// UtilizationSchedulingGate makes virt-handler report the utilization the scheduler extender scores nodes by
+	UtilizationSchedulingGate = "UtilizationScheduling"
 )
 
 func (c *ClusterConfig) isFeatureGateEnabled(featureGate string) bool {
@@ -84,3 +99,59 @@ func (config *ClusterConfig) SnapshotEnabled() bool {
 func (config *ClusterConfig) HostDiskEnabled() bool {
 	return config.isFeatureGateEnabled(HostDiskGate)
 }
+
+func (config *ClusterConfig) TPMEnabled() bool {
+	return config.isFeatureGateEnabled(TPMGate)
+}
+
+func (config *ClusterConfig) NUMAEnabled() bool {
+	return config.isFeatureGateEnabled(NUMAGate)
+}
+
+func (config *ClusterConfig) HostDevicesPassthroughEnabled() bool {
+	return config.isFeatureGateEnabled(HostDevicesGate)
+}
+
+func (config *ClusterConfig) VirtiofsEnabled() bool {
+	return config.isFeatureGateEnabled(VirtIOFSGate)
+}
+
+func (config *ClusterConfig) VMExportEnabled() bool {
+	return config.isFeatureGateEnabled(VMExportGate)
+}
+
+func (config *ClusterConfig) IncrementalBackupEnabled() bool {
+	return config.isFeatureGateEnabled(IncrementalBackupGate)
+}
+
+func (config *ClusterConfig) WorkloadEncryptionSEVEnabled() bool {
+	return config.isFeatureGateEnabled(SEVGate)
+}
+
+func (config *ClusterConfig) SEVLiveMigrationEnabled() bool {
+	return config.isFeatureGateEnabled(SEVLiveMigrationGate)
+}
+
+func (config *ClusterConfig) VMPreemptionEnabled() bool {
+	return config.isFeatureGateEnabled(VMPreemptionGate)
+}
+
+func (config *ClusterConfig) InstancetypeEnabled() bool {
+	return config.isFeatureGateEnabled(InstancetypeGate)
+}
+
+func (config *ClusterConfig) MacvtapEnabled() bool {
+	return config.isFeatureGateEnabled(MacvtapGate)
+}
+
+func (config *ClusterConfig) PasstEnabled() bool {
+	return config.isFeatureGateEnabled(PasstGate)
+}
+
+func (config *ClusterConfig) NonRootEnabled() bool {
+	return config.isFeatureGateEnabled(NonRootGate)
+}
+
+func (config *ClusterConfig) UtilizationSchedulingEnabled() bool {
+	return config.isFeatureGateEnabled(UtilizationSchedulingGate)
+}
@@ -25,20 +25,25 @@ package virtconfig
 
 import (
 	"runtime"
+	"time"
 
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "kubevirt.io/client-go/api/v1"
 )
 
 const (
 	ParallelOutboundMigrationsPerNodeDefault uint32 = 2
+	ParallelInboundMigrationsPerNodeDefault  uint32 = 2
 	ParallelMigrationsPerClusterDefault      uint32 = 5
 	BandwithPerMigrationDefault                     = "64Mi"
 	MigrationAllowAutoConverge               bool   = false
 	MigrationProgressTimeout                 int64  = 150
 	MigrationCompletionTimeoutPerGiB         int64  = 800
+	MigrationRetryLimitDefault               uint32 = 3
+	MigrationDisableTLSDefault               bool   = false
 	DefaultAMD64MachineType                         = "q35"
 	DefaultPPC64LEMachineType                       = "pseries"
 	DefaultCPURequest                               = "100m"
@@ -56,10 +61,17 @@ const (
 	SmbiosConfigDefaultManufacturer                 = "KubeVirt"
 	SmbiosConfigDefaultProduct                      = "None"
 	DefaultPermitBridgeInterfaceOnPodNetwork        = true
+	DefaultIstioAwareMasquerade                     = false
 	DefaultSELinuxLauncherType                      = ""
 	SupportedGuestAgentVersions                     = "3.*,4.*"
 	DefaultOVMFPath                                 = "/usr/share/OVMF"
 	DefaultMemBalloonStatsPeriod                    = 10
+	DefaultMemBalloonFreePageReporting              = false
+	DefaultNetworkPolicyBlockedCIDR                 = "169.254.169.254/32"
+	DefaultKSMMemoryPressureThreshold        uint32 = 80
+	DefaultMultiQueueMaxQueues               uint32 = 8
+	MaxMultiQueueQueues                      uint32 = 256
+	DefaultNodeFencingGracePeriod                   = 5 * time.Minute
 )
 
 // Set default machine type and supported emulated machines based on architecture
@@ -76,6 +88,10 @@ func (c *ClusterConfig) GetMemBalloonStatsPeriod() int {
 	return c.GetConfig().MemBalloonStatsPeriod
 }
 
+func (c *ClusterConfig) IsMemBalloonFreePageReportingEnabled() bool {
+	return c.GetConfig().MemBalloonFreePageReporting
+}
+
 func (c *ClusterConfig) IsUseEmulation() bool {
 	return c.GetConfig().DeveloperConfiguration.UseEmulation
 }
@@ -138,6 +154,10 @@ func (c *ClusterConfig) IsBridgeInterfaceOnPodNetworkEnabled() bool {
 	return c.GetConfig().NetworkConfiguration.PermitBridgeInterfaceOnPodNetwork
 }
 
+func (c *ClusterConfig) IsIstioAwareMasqueradeEnabled() bool {
+	return c.GetConfig().NetworkConfiguration.IstioAwareMasquerade
+}
+
 func (c *ClusterConfig) GetDefaultClusterConfig() *v1.KubeVirtConfiguration {
 	return c.defaultConfig
 }
@@ -146,6 +166,26 @@ func (c *ClusterConfig) GetSELinuxLauncherType() string {
 	return c.GetConfig().SELinuxLauncherType
 }
 
+// GetSELinuxLauncherTypeForClass returns the SELinux type of the virt-launcher pods of a
+// workload class, and if the class is known. Classes without a type of their own, and
+// unknown classes, get the type of the default class, which falls back to the
+// selinuxLauncherType.
+func (c *ClusterConfig) GetSELinuxLauncherTypeForClass(class string) (string, bool) {
+	config := c.GetConfig()
+	if selinuxType, exists := config.SELinuxLauncherTypes[class]; exists {
+		return selinuxType, true
+	}
+	known := false
+	switch class {
+	case v1.SELinuxWorkloadClassDefault, v1.SELinuxWorkloadClassSEV, v1.SELinuxWorkloadClassVFIO:
+		known = true
+	}
+	if selinuxType, exists := config.SELinuxLauncherTypes[v1.SELinuxWorkloadClassDefault]; exists {
+		return selinuxType, known
+	}
+	return config.SELinuxLauncherType, known
+}
+
 func (c *ClusterConfig) GetSupportedAgentVersions() []string {
 	return c.GetConfig().SupportedGuestAgentVersions
 }
@@ -153,3 +193,78 @@ func (c *ClusterConfig) GetSupportedAgentVersions() []string {
 func (c *ClusterConfig) GetOVMFPath() string {
 	return c.GetConfig().OVMFPath
 }
+
+func (c *ClusterConfig) GetPermittedHostDevices() *v1.PermittedHostDevices {
+	return c.GetConfig().PermittedHostDevices
+}
+
+func (c *ClusterConfig) GetKSMConfiguration() *v1.KSMConfiguration {
+	return c.GetConfig().KSMConfiguration
+}
+
+func (c *ClusterConfig) GetMultiQueueConfiguration() *v1.MultiQueueConfiguration {
+	return c.GetConfig().MultiQueueConfiguration
+}
+
+func (c *ClusterConfig) GetDefaultNetworkPolicy() *v1.DefaultNetworkPolicyConfiguration {
+	return c.GetConfig().DefaultNetworkPolicy
+}
+
+func (c *ClusterConfig) GetAuditConfiguration() *v1.AuditConfiguration {
+	return c.GetConfig().AuditConfiguration
+}
+
+func (c *ClusterConfig) GetSeccompConfiguration() *v1.SeccompConfiguration {
+	return c.GetConfig().SeccompConfiguration
+}
+
+// GetMetricsNamingMode returns whether the renamed metrics are exposed under their deprecated
+// names, their new names or both, which is the default
+func (c *ClusterConfig) GetMetricsNamingMode() v1.MetricsNamingMode {
+	if metrics := c.GetConfig().MetricsConfiguration; metrics != nil && metrics.NamingMode != "" {
+		return metrics.NamingMode
+	}
+	return v1.MetricsNamingBoth
+}
+
+// GetFilesystemOverhead returns the overhead, in percent, of the filesystem of the volumes of a
+// storage class, or the global one for an unset storage class or one without its own
+func (c *ClusterConfig) GetFilesystemOverhead(storageClass *string) float64 {
+	filesystemOverhead := c.GetConfig().FilesystemOverhead
+	if filesystemOverhead == nil {
+		return 0
+	}
+	overhead := filesystemOverhead.Global
+	if storageClass != nil {
+		if classOverhead, exists := filesystemOverhead.StorageClass[*storageClass]; exists {
+			overhead = classOverhead
+		}
+	}
+	if overhead == "" {
+		return 0
+	}
+	// the overheads were validated with the config
+	percent, _ := parseFilesystemOverhead(overhead)
+	return percent
+}
+
+// GetNodeFencing returns the fencing configuration of vmis on unresponsive nodes,
+// with the grace period and the confirmation rules defaulted
+func (c *ClusterConfig) GetNodeFencing() *v1.NodeFencingConfiguration {
+	nodeFencing := c.GetConfig().NodeFencing.DeepCopy()
+	if nodeFencing == nil {
+		nodeFencing = &v1.NodeFencingConfiguration{}
+	}
+	if nodeFencing.GracePeriod == nil {
+		nodeFencing.GracePeriod = &metav1.Duration{Duration: DefaultNodeFencingGracePeriod}
+	}
+	if nodeFencing.Confirmation == nil {
+		nodeFencing.Confirmation = &v1.NodeFencingConfirmation{}
+	}
+	if confirmation := nodeFencing.Confirmation; confirmation.NotReady == nil {
+		// the node not being ready confirms it, unless other rules are chosen
+		notReady := !confirmation.OutOfServiceTaint && len(confirmation.Resources) == 0
+		confirmation.NotReady = &notReady
+	}
+	return nodeFencing
+}
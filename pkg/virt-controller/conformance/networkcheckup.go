@@ -0,0 +1,272 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package conformance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// the addresses of the VMIs on a multus network, documented on ConformanceNetworkCheckup
+	networkCheckupSourceAddress = "192.168.100.10"
+	networkCheckupTargetAddress = "192.168.100.20"
+	networkCheckupPrefixLength  = 24
+
+	networkCheckupProbes = 10
+	// networkCheckupReport prefixes the line the source VMI reports its measurements on
+	networkCheckupReport = "conformance-network-checkup:"
+)
+
+var (
+	// the summary of busybox and iputils ping
+	latencyPattern = regexp.MustCompile(`min/avg/max(?:/mdev)? = ([0-9.]+)/([0-9.]+)/([0-9.]+)(?:/[0-9.]+)? ms`)
+	// the receiver summary of iperf3
+	throughputPattern = regexp.MustCompile(`([0-9.]+) ([KMG]?)bits/sec`)
+)
+
+// checkNetworkLatency starts a target VMI, then a source VMI which probes the target
+// and reports the measurements on its serial console
+func checkNetworkLatency(r *Runner, name string) error {
+	checkup := r.run.Spec.NetworkCheckup
+	if checkup == nil {
+		return skip("no network checkup configured")
+	}
+
+	targetName := name + "-target"
+	target := r.newNetworkCheckupVMI(targetName, checkup.TargetNode, networkCheckupTargetAddress, networkCheckupTargetScript(checkup))
+	defer r.deleteVMI(targetName)
+	if err := r.startVMI(target); err != nil {
+		return err
+	}
+
+	targetAddress := networkCheckupTargetAddress
+	if checkup.NetworkName == "" {
+		// on the pod network the target is reached through the address of its pod
+		target, err := r.clientset.VirtualMachineInstance(r.run.Namespace).Get(targetName, &metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(target.Status.Interfaces) == 0 || target.Status.Interfaces[0].IP == "" {
+			return fmt.Errorf("VMI %s has no address on the pod network", targetName)
+		}
+		targetAddress = target.Status.Interfaces[0].IP
+	}
+
+	sourceName := name + "-source"
+	source := r.newNetworkCheckupVMI(sourceName, checkup.SourceNode, networkCheckupSourceAddress, networkCheckupSourceScript(checkup, targetAddress))
+	defer r.deleteVMI(sourceName)
+	if err := r.startVMI(source); err != nil {
+		return err
+	}
+
+	report, err := r.readConsoleLine(sourceName, networkCheckupReport)
+	if err != nil {
+		return err
+	}
+	result, err := parseNetworkCheckupReport(report)
+	if err != nil {
+		return err
+	}
+	r.networkCheckupResult = result
+
+	if checkup.MaxLatency != nil && result.AvgLatency.Duration > checkup.MaxLatency.Duration {
+		return fmt.Errorf("the average latency %s exceeds %s", result.AvgLatency.Duration, checkup.MaxLatency.Duration)
+	}
+	return nil
+}
+
+// newNetworkCheckupVMI returns a VMI on the node and the network of the checkup,
+// which runs the script on boot
+func (r *Runner) newNetworkCheckupVMI(name string, node string, address string, script string) *v1.VirtualMachineInstance {
+	checkup := r.run.Spec.NetworkCheckup
+	vmi := r.newVMI(name)
+	if checkup.Image != "" {
+		vmi.Spec.Volumes[0].ContainerDisk.Image = checkup.Image
+	}
+	if node != "" {
+		vmi.Spec.NodeSelector = map[string]string{k8sv1.LabelHostname: node}
+	}
+
+	if checkup.NetworkName != "" {
+		iface := v1.Interface{Name: "checkup"}
+		if checkup.SRIOV {
+			iface.SRIOV = &v1.InterfaceSRIOV{}
+		} else {
+			iface.Bridge = &v1.InterfaceBridge{}
+		}
+		vmi.Spec.Domain.Devices.Interfaces = append(vmi.Spec.Domain.Devices.Interfaces, iface)
+		vmi.Spec.Networks = append(vmi.Spec.Networks, v1.Network{
+			Name: "checkup",
+			NetworkSource: v1.NetworkSource{
+				Multus: &v1.MultusNetwork{
+					NetworkName: checkup.NetworkName,
+				},
+			},
+		})
+	}
+
+	vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+		Name: "cloudinitdisk",
+		DiskDevice: v1.DiskDevice{
+			Disk: &v1.DiskTarget{
+				Bus: "virtio",
+			},
+		},
+	})
+	vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+		Name: "cloudinitdisk",
+		VolumeSource: v1.VolumeSource{
+			CloudInitNoCloud: &v1.CloudInitNoCloudSource{
+				UserData: script,
+			},
+		},
+	})
+	return vmi
+}
+
+// networkCheckupSetup configures the address of the guest on the multus network,
+// the second interface after the one on the pod network
+func networkCheckupSetup(checkup *v1.ConformanceNetworkCheckup, address string) string {
+	if checkup.NetworkName == "" {
+		return ""
+	}
+	return fmt.Sprintf("ip addr add %s/%d dev eth1\nip link set eth1 up\n", address, networkCheckupPrefixLength)
+}
+
+func networkCheckupTargetScript(checkup *v1.ConformanceNetworkCheckup) string {
+	return "#!/bin/sh\n" +
+		networkCheckupSetup(checkup, networkCheckupTargetAddress) +
+		"if command -v iperf3 >/dev/null; then iperf3 -s -D; fi\n"
+}
+
+// networkCheckupSourceScript waits for the target to answer, measures the latency and,
+// with iperf3, the throughput, and repeats the report on the console until the VMI is
+// deleted, since the runner may connect to the console only after the first report
+func networkCheckupSourceScript(checkup *v1.ConformanceNetworkCheckup, targetAddress string) string {
+	return "#!/bin/sh\n" +
+		networkCheckupSetup(checkup, networkCheckupSourceAddress) +
+		fmt.Sprintf(`measure() {
+  until ping -c 1 -W 1 %[1]s >/dev/null; do sleep 1; done
+  latency=$(ping -c %[2]d %[1]s | tail -n 1)
+  throughput=none
+  if command -v iperf3 >/dev/null; then
+    throughput=$(iperf3 -c %[1]s -t 5 | grep receiver)
+  fi
+  while true; do
+    echo "%[3]s $latency; $throughput" >/dev/console
+    sleep 5
+  done
+}
+measure &
+`, targetAddress, networkCheckupProbes, networkCheckupReport)
+}
+
+// readConsoleLine returns the remainder of the first line on the serial console of
+// the VMI which contains prefix
+func (r *Runner) readConsoleLine(name string, prefix string) (string, error) {
+	stream, err := r.clientset.VirtualMachineInstance(r.run.Namespace).SerialConsole(name, &kubecli.SerialConsoleOptions{ConnectionTimeout: r.timeout})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to the serial console of VMI %s: %v", name, err)
+	}
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	defer inWriter.Close()
+	defer outReader.Close()
+
+	go func() {
+		err := stream.Stream(kubecli.StreamOptions{In: inReader, Out: outWriter})
+		outWriter.CloseWithError(err)
+	}()
+
+	lines := make(chan string, 1)
+	errs := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			if i := strings.Index(scanner.Text(), prefix); i >= 0 {
+				lines <- strings.TrimSpace(scanner.Text()[i+len(prefix):])
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("failed to read the serial console of VMI %s: %v", name, err)
+			return
+		}
+		errs <- fmt.Errorf("the serial console of VMI %s closed before the measurements were reported", name)
+	}()
+
+	select {
+	case line := <-lines:
+		return line, nil
+	case err := <-errs:
+		return "", err
+	case <-time.After(r.timeout):
+		return "", fmt.Errorf("timed out after %s waiting for the measurements of VMI %s", r.timeout, name)
+	}
+}
+
+// parseNetworkCheckupReport parses the report of the source VMI, like
+//
+//	round-trip min/avg/max = 0.303/0.456/0.731 ms; [  5]   0.00-5.00   sec   562 MBytes   943 Mbits/sec   receiver
+func parseNetworkCheckupReport(report string) (*v1.ConformanceNetworkCheckupResult, error) {
+	match := latencyPattern.FindStringSubmatch(report)
+	if match == nil {
+		return nil, fmt.Errorf("the target VMI did not answer: %s", report)
+	}
+	result := &v1.ConformanceNetworkCheckupResult{}
+	for i, latency := range []*metav1.Duration{&result.MinLatency, &result.AvgLatency, &result.MaxLatency} {
+		ms, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the latency: %v", err)
+		}
+		latency.Duration = time.Duration(ms * float64(time.Millisecond))
+	}
+
+	if match := throughputPattern.FindStringSubmatch(report); match != nil {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the throughput: %v", err)
+		}
+		switch match[2] {
+		case "K":
+			value *= 1e3
+		case "M":
+			value *= 1e6
+		case "G":
+			value *= 1e9
+		}
+		throughput := int64(value)
+		result.ThroughputBitsPerSecond = &throughput
+	}
+	return result, nil
+}
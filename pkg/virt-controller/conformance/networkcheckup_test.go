@@ -0,0 +1,203 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package conformance
+
+import (
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+// fakeConsole writes its output, then keeps the connection open until the input is closed
+type fakeConsole struct {
+	output string
+}
+
+func (c *fakeConsole) Stream(options kubecli.StreamOptions) error {
+	if _, err := io.WriteString(options.Out, c.output); err != nil {
+		return err
+	}
+	_, err := io.Copy(ioutil.Discard, options.In)
+	return err
+}
+
+var _ = Describe("Network checkup", func() {
+
+	var ctrl *gomock.Controller
+	var virtClient *kubecli.MockKubevirtClient
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var run *v1.ConformanceRun
+	var created map[string]*v1.VirtualMachineInstance
+
+	const report = "round-trip min/avg/max = 0.303/0.456/0.731 ms; [  5]   0.00-5.00   sec   562 MBytes   943 Mbits/sec   receiver"
+
+	newRunner := func() *Runner {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+		runner := NewRunner(virtClient, clusterConfig, run)
+		runner.pollInterval = time.Millisecond
+		runner.timeout = 100 * time.Millisecond
+		return runner
+	}
+
+	expectCheckup := func(console string) {
+		vmiInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstance, error) {
+			created[vmi.Name] = vmi
+			return vmi, nil
+		}).Times(2)
+		vmiInterface.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(name string, _ *metav1.GetOptions) (*v1.VirtualMachineInstance, error) {
+			vmi := v1.NewMinimalVMIWithNS(run.Namespace, name)
+			vmi.Status.Phase = v1.Running
+			vmi.Status.Interfaces = []v1.VirtualMachineInstanceNetworkInterface{{IP: "10.244.0.20"}}
+			return vmi, nil
+		}).AnyTimes()
+		vmiInterface.EXPECT().SerialConsole("testrun-networklatency-source", gomock.Any()).Return(&fakeConsole{output: console}, nil)
+		vmiInterface.EXPECT().Delete("testrun-networklatency-source", gomock.Any()).Return(nil)
+		vmiInterface.EXPECT().Delete("testrun-networklatency-target", gomock.Any()).Return(nil)
+	}
+
+	exercise := func() v1.ConformanceFeatureResult {
+		var results []v1.ConformanceFeatureResult
+		newRunner().Run(func(result v1.ConformanceFeatureResult) {
+			results = append(results, result)
+		})
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Feature).To(Equal(v1.ConformanceFeatureNetworkLatency))
+		return results[0]
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(gomock.Any()).Return(vmiInterface).AnyTimes()
+		created = map[string]*v1.VirtualMachineInstance{}
+
+		run = &v1.ConformanceRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testrun",
+				Namespace: "default",
+				UID:       "1234",
+			},
+			Spec: v1.ConformanceRunSpec{
+				Features:       []v1.ConformanceFeature{v1.ConformanceFeatureNetworkLatency},
+				NetworkCheckup: &v1.ConformanceNetworkCheckup{},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should report the measurements of the source VMI", func() {
+		expectCheckup("login: \r\nconformance-network-checkup: " + report + "\r\n")
+
+		result := exercise()
+		Expect(result.Result).To(Equal(v1.ConformanceResultPassed), result.Message)
+		Expect(result.NetworkCheckup).ToNot(BeNil())
+		Expect(result.NetworkCheckup.AvgLatency.Duration).To(Equal(456 * time.Microsecond))
+		Expect(*result.NetworkCheckup.ThroughputBitsPerSecond).To(Equal(int64(943e6)))
+
+		// on the pod network the source probes the address of the target pod
+		source := created["testrun-networklatency-source"]
+		Expect(source.Spec.Volumes[1].CloudInitNoCloud.UserData).To(ContainSubstring("ping -c 10 10.244.0.20"))
+		Expect(source.Spec.Networks).To(HaveLen(1))
+	})
+
+	It("should fail if the average latency exceeds the maximum", func() {
+		run.Spec.NetworkCheckup.MaxLatency = &metav1.Duration{Duration: 100 * time.Microsecond}
+		expectCheckup("conformance-network-checkup: " + report + "\r\n")
+
+		result := exercise()
+		Expect(result.Result).To(Equal(v1.ConformanceResultFailed))
+		Expect(result.Message).To(ContainSubstring("exceeds 100µs"))
+		Expect(result.NetworkCheckup).ToNot(BeNil())
+	})
+
+	It("should fail if the source VMI does not report in time", func() {
+		expectCheckup("login: ")
+
+		result := exercise()
+		Expect(result.Result).To(Equal(v1.ConformanceResultFailed))
+		Expect(result.Message).To(ContainSubstring("timed out"))
+		Expect(result.NetworkCheckup).To(BeNil())
+	})
+
+	It("should place the VMIs on the nodes and the multus network of the checkup", func() {
+		run.Spec.NetworkCheckup = &v1.ConformanceNetworkCheckup{
+			NetworkName: "sriov-net",
+			SRIOV:       true,
+			SourceNode:  "node01",
+			TargetNode:  "node02",
+			Image:       "registry:5000/checkup",
+		}
+		expectCheckup("conformance-network-checkup: " + report + "\r\n")
+
+		result := exercise()
+		Expect(result.Result).To(Equal(v1.ConformanceResultPassed), result.Message)
+
+		source := created["testrun-networklatency-source"]
+		target := created["testrun-networklatency-target"]
+		Expect(source.Spec.NodeSelector).To(HaveKeyWithValue(k8sv1.LabelHostname, "node01"))
+		Expect(target.Spec.NodeSelector).To(HaveKeyWithValue(k8sv1.LabelHostname, "node02"))
+		Expect(source.Spec.Volumes[0].ContainerDisk.Image).To(Equal("registry:5000/checkup"))
+		Expect(source.Spec.Networks[1].Multus.NetworkName).To(Equal("sriov-net"))
+		Expect(source.Spec.Domain.Devices.Interfaces[1].SRIOV).ToNot(BeNil())
+		Expect(source.Spec.Volumes[1].CloudInitNoCloud.UserData).To(ContainSubstring("ip addr add 192.168.100.10/24 dev eth1"))
+		Expect(source.Spec.Volumes[1].CloudInitNoCloud.UserData).To(ContainSubstring("ping -c 10 192.168.100.20"))
+		Expect(target.Spec.Volumes[1].CloudInitNoCloud.UserData).To(ContainSubstring("ip addr add 192.168.100.20/24 dev eth1"))
+	})
+
+	table.DescribeTable("should parse the report", func(report string, min, avg, max time.Duration, throughput *int64) {
+		result, err := parseNetworkCheckupReport(report)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.MinLatency.Duration).To(Equal(min))
+		Expect(result.AvgLatency.Duration).To(Equal(avg))
+		Expect(result.MaxLatency.Duration).To(Equal(max))
+		Expect(result.ThroughputBitsPerSecond).To(Equal(throughput))
+	},
+		table.Entry("of busybox ping without iperf3", "round-trip min/avg/max = 0.250/0.500/1.000 ms; none",
+			250*time.Microsecond, 500*time.Microsecond, time.Millisecond, nil),
+		table.Entry("of iputils ping", "rtt min/avg/max/mdev = 1.000/2.000/3.000/0.500 ms; none",
+			time.Millisecond, 2*time.Millisecond, 3*time.Millisecond, nil),
+		table.Entry("with the throughput of iperf3", "round-trip min/avg/max = 0.250/0.500/1.000 ms; [  5]   0.00-5.00   sec  4.50 GBytes  7.73 Gbits/sec   receiver",
+			250*time.Microsecond, 500*time.Microsecond, time.Millisecond, int64Ptr(7730000000)),
+	)
+
+	It("should fail to parse the report if the target did not answer", func() {
+		_, err := parseNetworkCheckupReport("10 packets transmitted, 0 packets received, 100% packet loss; none")
+		Expect(err).To(MatchError(ContainSubstring("did not answer")))
+	})
+})
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,458 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package conformance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	snapshotv1 "kubevirt.io/client-go/apis/snapshot/v1alpha1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// ConformanceRunLabel marks every object created while exercising a ConformanceRun
+	ConformanceRunLabel = "kubevirt.io/conformance-run"
+
+	defaultImage          = "kubevirt/cirros-container-disk-demo"
+	defaultStorageSize    = "1Gi"
+	defaultFeatureTimeout = 5 * time.Minute
+	defaultPollInterval   = 2 * time.Second
+)
+
+// skipError is returned by a check whose preconditions are not met on the cluster
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+func skip(format string, a ...interface{}) error {
+	return &skipError{reason: fmt.Sprintf(format, a...)}
+}
+
+// check exercises a single feature. The objects it creates are named after name.
+type check func(r *Runner, name string) error
+
+var checks = map[v1.ConformanceFeature]check{
+	v1.ConformanceFeatureBoot:           checkBoot,
+	v1.ConformanceFeatureMigrate:        checkMigrate,
+	v1.ConformanceFeatureHotplug:        checkHotplug,
+	v1.ConformanceFeatureSnapshot:       checkSnapshot,
+	v1.ConformanceFeatureSRIOV:          checkSRIOV,
+	v1.ConformanceFeatureNetworkLatency: checkNetworkLatency,
+}
+
+// storageFeatures are exercised again for every storage class to check
+var storageFeatures = map[v1.ConformanceFeature]bool{
+	v1.ConformanceFeatureBoot:    true,
+	v1.ConformanceFeatureMigrate: true,
+	v1.ConformanceFeatureHotplug: true,
+}
+
+// Runner exercises the features requested by a single ConformanceRun
+type Runner struct {
+	clientset     kubecli.KubevirtClient
+	clusterConfig *virtconfig.ClusterConfig
+	run           *v1.ConformanceRun
+	timeout       time.Duration
+	pollInterval  time.Duration
+	// storageClass is the storage class the current feature is exercised with, if any
+	storageClass string
+	// networkCheckupResult are the measurements of the current feature, if any
+	networkCheckupResult *v1.ConformanceNetworkCheckupResult
+}
+
+func NewRunner(clientset kubecli.KubevirtClient, clusterConfig *virtconfig.ClusterConfig, run *v1.ConformanceRun) *Runner {
+	timeout := defaultFeatureTimeout
+	if run.Spec.FeatureTimeout != nil {
+		timeout = run.Spec.FeatureTimeout.Duration
+	}
+	return &Runner{
+		clientset:     clientset,
+		clusterConfig: clusterConfig,
+		run:           run,
+		timeout:       timeout,
+		pollInterval:  defaultPollInterval,
+	}
+}
+
+// Run exercises the requested features one after the other, then the storage
+// features again for every storage class, and hands every result to report as
+// soon as it is known
+func (r *Runner) Run(report func(result v1.ConformanceFeatureResult)) {
+	features := r.run.Spec.Features
+	if len(features) == 0 {
+		features = v1.ConformanceFeatures
+	}
+
+	for _, feature := range features {
+		name := fmt.Sprintf("%s-%s", r.run.Name, strings.ToLower(string(feature)))
+		report(r.exercise(feature, name, ""))
+	}
+
+	for i, storageClass := range r.run.Spec.StorageClasses {
+		for _, feature := range features {
+			if !storageFeatures[feature] {
+				continue
+			}
+			// storage class names may be too long for the names of the objects
+			name := fmt.Sprintf("%s-%s-storage-%d", r.run.Name, strings.ToLower(string(feature)), i)
+			report(r.exercise(feature, name, storageClass))
+		}
+	}
+}
+
+func (r *Runner) exercise(feature v1.ConformanceFeature, name string, storageClass string) v1.ConformanceFeatureResult {
+	start := metav1.Now()
+	result := v1.ConformanceFeatureResult{
+		Feature:        feature,
+		StorageClass:   storageClass,
+		StartTimestamp: &start,
+	}
+
+	var err error
+	if check, exists := checks[feature]; exists {
+		r.storageClass = storageClass
+		r.networkCheckupResult = nil
+		err = check(r, name)
+		result.NetworkCheckup = r.networkCheckupResult
+	} else {
+		err = fmt.Errorf("unknown feature %s", feature)
+	}
+	switch err.(type) {
+	case nil:
+		result.Result = v1.ConformanceResultPassed
+	case *skipError:
+		result.Result = v1.ConformanceResultSkipped
+		result.Message = err.Error()
+	default:
+		result.Result = v1.ConformanceResultFailed
+		result.Message = err.Error()
+	}
+	if storageClass != "" {
+		log.Log.Object(r.run).Infof("Conformance feature %s with storage class %s: %s %s", feature, storageClass, result.Result, result.Message)
+	} else {
+		log.Log.Object(r.run).Infof("Conformance feature %s: %s %s", feature, result.Result, result.Message)
+	}
+
+	now := metav1.Now()
+	result.CompletionTimestamp = &now
+	return result
+}
+
+func checkBoot(r *Runner, name string) error {
+	vmi := r.newVMI(name)
+	defer r.deleteVMI(name)
+	if err := r.importStorage(vmi, k8sv1.ReadWriteOnce); err != nil {
+		return err
+	}
+	return r.startVMI(vmi)
+}
+
+func checkMigrate(r *Runner, name string) error {
+	if !r.clusterConfig.LiveMigrationEnabled() {
+		return skip("the %s feature gate is not enabled", virtconfig.LiveMigrationGate)
+	}
+
+	nodes, err := r.clientset.CoreV1().Nodes().List(metav1.ListOptions{LabelSelector: v1.NodeSchedulable + "=true"})
+	if err != nil {
+		return err
+	}
+	if len(nodes.Items) < 2 {
+		return skip("live migration needs at least two schedulable nodes, found %d", len(nodes.Items))
+	}
+
+	vmi := r.newVMI(name)
+	defer r.deleteVMI(name)
+	// the source and the target pod share the volume
+	if err := r.importStorage(vmi, k8sv1.ReadWriteMany); err != nil {
+		return err
+	}
+	if err := r.startVMI(vmi); err != nil {
+		return err
+	}
+
+	migration := &v1.VirtualMachineInstanceMigration{
+		ObjectMeta: r.newObjectMeta(name),
+		Spec: v1.VirtualMachineInstanceMigrationSpec{
+			VMIName: name,
+		},
+	}
+	if _, err := r.clientset.VirtualMachineInstanceMigration(r.run.Namespace).Create(migration); err != nil {
+		return err
+	}
+	defer r.clientset.VirtualMachineInstanceMigration(r.run.Namespace).Delete(name, &metav1.DeleteOptions{})
+
+	return r.waitFor("migration to succeed", func() (bool, error) {
+		migration, err := r.clientset.VirtualMachineInstanceMigration(r.run.Namespace).Get(name, &metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if migration.Status.Phase == v1.MigrationFailed {
+			return false, fmt.Errorf("migration %s failed", name)
+		}
+		return migration.Status.Phase == v1.MigrationSucceeded, nil
+	})
+}
+
+func checkHotplug(r *Runner, name string) error {
+	return skip("volume hotplug is not supported by this KubeVirt build")
+}
+
+func checkSnapshot(r *Runner, name string) error {
+	if !r.clusterConfig.SnapshotEnabled() {
+		return skip("the %s feature gate is not enabled", virtconfig.SnapshotGate)
+	}
+
+	running := false
+	vm := &v1.VirtualMachine{
+		ObjectMeta: r.newObjectMeta(name),
+		Spec: v1.VirtualMachineSpec{
+			Running: &running,
+			Template: &v1.VirtualMachineInstanceTemplateSpec{
+				Spec: r.newVMI(name).Spec,
+			},
+		},
+	}
+	if _, err := r.clientset.VirtualMachine(r.run.Namespace).Create(vm); err != nil {
+		return err
+	}
+	defer r.clientset.VirtualMachine(r.run.Namespace).Delete(name, &metav1.DeleteOptions{})
+
+	apiGroup := v1.GroupName
+	snapshot := &snapshotv1.VirtualMachineSnapshot{
+		ObjectMeta: r.newObjectMeta(name),
+		Spec: snapshotv1.VirtualMachineSnapshotSpec{
+			Source: k8sv1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VirtualMachine",
+				Name:     name,
+			},
+		},
+	}
+	if _, err := r.clientset.VirtualMachineSnapshot(r.run.Namespace).Create(snapshot); err != nil {
+		return err
+	}
+	defer r.clientset.VirtualMachineSnapshot(r.run.Namespace).Delete(name, &metav1.DeleteOptions{})
+
+	return r.waitFor("snapshot to be ready", func() (bool, error) {
+		snapshot, err := r.clientset.VirtualMachineSnapshot(r.run.Namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if snapshot.Status == nil {
+			return false, nil
+		}
+		if snapshot.Status.Error != nil && snapshot.Status.Error.Message != nil {
+			return false, fmt.Errorf("snapshot %s failed: %s", name, *snapshot.Status.Error.Message)
+		}
+		return snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse, nil
+	})
+}
+
+func checkSRIOV(r *Runner, name string) error {
+	if r.run.Spec.SRIOVNetworkName == "" {
+		return skip("no SR-IOV network configured")
+	}
+
+	vmi := r.newVMI(name)
+	vmi.Spec.Domain.Devices.Interfaces = append(vmi.Spec.Domain.Devices.Interfaces, v1.Interface{
+		Name: "sriov",
+		InterfaceBindingMethod: v1.InterfaceBindingMethod{
+			SRIOV: &v1.InterfaceSRIOV{},
+		},
+	})
+	vmi.Spec.Networks = append(vmi.Spec.Networks, v1.Network{
+		Name: "sriov",
+		NetworkSource: v1.NetworkSource{
+			Multus: &v1.MultusNetwork{
+				NetworkName: r.run.Spec.SRIOVNetworkName,
+			},
+		},
+	})
+	defer r.deleteVMI(name)
+	return r.startVMI(vmi)
+}
+
+func (r *Runner) newObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: r.run.Namespace,
+		Labels: map[string]string{
+			ConformanceRunLabel: r.run.Name,
+		},
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(r.run, v1.ConformanceRunGroupVersionKind),
+		},
+	}
+}
+
+func (r *Runner) image() string {
+	if r.run.Spec.Image != "" {
+		return r.run.Spec.Image
+	}
+	return defaultImage
+}
+
+// newVMI returns a small VMI booting from the configured containerDisk, with a
+// masquerade interface on the pod network so that it stays migratable
+func (r *Runner) newVMI(name string) *v1.VirtualMachineInstance {
+	vmi := v1.NewMinimalVMIWithNS(r.run.Namespace, name)
+	vmi.ObjectMeta = r.newObjectMeta(name)
+	vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+		k8sv1.ResourceMemory: resource.MustParse("128Mi"),
+	}
+	vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+		{
+			Name: "containerdisk",
+			DiskDevice: v1.DiskDevice{
+				Disk: &v1.DiskTarget{
+					Bus: "virtio",
+				},
+			},
+		},
+	}
+	vmi.Spec.Volumes = []v1.Volume{
+		{
+			Name: "containerdisk",
+			VolumeSource: v1.VolumeSource{
+				ContainerDisk: &v1.ContainerDiskSource{
+					Image: r.image(),
+				},
+			},
+		},
+	}
+	vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}
+	vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+	return vmi
+}
+
+// importStorage imports the image into a DataVolume of the storage class under check
+// and replaces the containerDisk of the VMI with it. Without a storage class under
+// check the VMI keeps booting from the containerDisk.
+func (r *Runner) importStorage(vmi *v1.VirtualMachineInstance, accessMode k8sv1.PersistentVolumeAccessMode) error {
+	if r.storageClass == "" {
+		return nil
+	}
+	if !r.clusterConfig.HasDataVolumeAPI() {
+		return skip("the DataVolume API of CDI is not installed")
+	}
+	if _, err := r.clientset.StorageV1().StorageClasses().Get(r.storageClass, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get storage class %s: %v", r.storageClass, err)
+	}
+
+	storageClass := r.storageClass
+	dataVolume := &cdiv1.DataVolume{
+		ObjectMeta: r.newObjectMeta(vmi.Name),
+		Spec: cdiv1.DataVolumeSpec{
+			Source: cdiv1.DataVolumeSource{
+				Registry: &cdiv1.DataVolumeSourceRegistry{
+					URL: "docker://" + r.image(),
+				},
+			},
+			PVC: &k8sv1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				AccessModes:      []k8sv1.PersistentVolumeAccessMode{accessMode},
+				Resources: k8sv1.ResourceRequirements{
+					Requests: k8sv1.ResourceList{
+						k8sv1.ResourceStorage: resource.MustParse(defaultStorageSize),
+					},
+				},
+			},
+		},
+	}
+	if _, err := r.clientset.CdiClient().CdiV1alpha1().DataVolumes(r.run.Namespace).Create(dataVolume); err != nil {
+		return err
+	}
+
+	err := r.waitFor(fmt.Sprintf("the import into DataVolume %s", vmi.Name), func() (bool, error) {
+		dataVolume, err := r.clientset.CdiClient().CdiV1alpha1().DataVolumes(r.run.Namespace).Get(vmi.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if dataVolume.Status.Phase == cdiv1.Failed {
+			return false, fmt.Errorf("the import into DataVolume %s failed", vmi.Name)
+		}
+		return dataVolume.Status.Phase == cdiv1.Succeeded, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	vmi.Spec.Volumes[0].VolumeSource = v1.VolumeSource{
+		DataVolume: &v1.DataVolumeSource{
+			Name: vmi.Name,
+		},
+	}
+	return nil
+}
+
+func (r *Runner) startVMI(vmi *v1.VirtualMachineInstance) error {
+	if _, err := r.clientset.VirtualMachineInstance(r.run.Namespace).Create(vmi); err != nil {
+		return err
+	}
+
+	return r.waitFor(fmt.Sprintf("VMI %s to be running", vmi.Name), func() (bool, error) {
+		vmi, err := r.clientset.VirtualMachineInstance(r.run.Namespace).Get(vmi.Name, &metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if vmi.IsFinal() {
+			return false, fmt.Errorf("VMI %s reached phase %s", vmi.Name, vmi.Status.Phase)
+		}
+		return vmi.IsRunning(), nil
+	})
+}
+
+func (r *Runner) deleteVMI(name string) {
+	err := r.clientset.VirtualMachineInstance(r.run.Namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		log.Log.Object(r.run).Reason(err).Errorf("Failed to delete conformance VMI %s", name)
+	}
+	if r.storageClass == "" {
+		return
+	}
+	err = r.clientset.CdiClient().CdiV1alpha1().DataVolumes(r.run.Namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		log.Log.Object(r.run).Reason(err).Errorf("Failed to delete conformance DataVolume %s", name)
+	}
+}
+
+func (r *Runner) waitFor(what string, condition wait.ConditionFunc) error {
+	err := wait.PollImmediate(r.pollInterval, r.timeout, condition)
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out after %s waiting for %s", r.timeout, what)
+	}
+	return err
+}
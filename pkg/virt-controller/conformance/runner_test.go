@@ -0,0 +1,259 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package conformance
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	cdifake "kubevirt.io/client-go/generated/containerized-data-importer/clientset/versioned/fake"
+	"kubevirt.io/client-go/kubecli"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Conformance runner", func() {
+
+	var ctrl *gomock.Controller
+	var virtClient *kubecli.MockKubevirtClient
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var kubeClient *fake.Clientset
+	var cdiClient *cdifake.Clientset
+	var run *v1.ConformanceRun
+
+	newRunner := func(featureGates string) *Runner {
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: featureGates},
+		})
+		runner := NewRunner(virtClient, clusterConfig, run)
+		runner.pollInterval = time.Millisecond
+		runner.timeout = 100 * time.Millisecond
+		return runner
+	}
+
+	runFeatures := func(runner *Runner) []v1.ConformanceFeatureResult {
+		var results []v1.ConformanceFeatureResult
+		runner.Run(func(result v1.ConformanceFeatureResult) {
+			Expect(result.StartTimestamp).ToNot(BeNil())
+			Expect(result.CompletionTimestamp).ToNot(BeNil())
+			results = append(results, result)
+		})
+		return results
+	}
+
+	newNode := func(name string) *k8sv1.Node {
+		return &k8sv1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{v1.NodeSchedulable: "true"},
+			},
+		}
+	}
+
+	expectVMIToReachPhase := func(phase v1.VirtualMachineInstancePhase) {
+		vmiInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstance, error) {
+			Expect(vmi.Name).To(Equal("testrun-boot"))
+			Expect(vmi.Labels).To(HaveKeyWithValue(ConformanceRunLabel, "testrun"))
+			Expect(vmi.OwnerReferences).To(HaveLen(1))
+			Expect(vmi.OwnerReferences[0].UID).To(Equal(run.UID))
+			Expect(vmi.Spec.Volumes[0].ContainerDisk.Image).To(Equal(defaultImage))
+			return vmi, nil
+		})
+		vmiInterface.EXPECT().Get("testrun-boot", gomock.Any()).DoAndReturn(func(name string, _ *metav1.GetOptions) (*v1.VirtualMachineInstance, error) {
+			vmi := v1.NewMinimalVMIWithNS(run.Namespace, name)
+			vmi.Status.Phase = phase
+			return vmi, nil
+		}).AnyTimes()
+		vmiInterface.EXPECT().Delete("testrun-boot", gomock.Any()).Return(nil)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+		cdiClient = cdifake.NewSimpleClientset()
+		virtClient.EXPECT().VirtualMachineInstance(gomock.Any()).Return(vmiInterface).AnyTimes()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+		virtClient.EXPECT().StorageV1().Return(kubeClient.StorageV1()).AnyTimes()
+		virtClient.EXPECT().CdiClient().Return(cdiClient).AnyTimes()
+
+		run = &v1.ConformanceRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testrun",
+				Namespace: "default",
+				UID:       "1234",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should pass boot if the VMI starts", func() {
+		run.Spec.Features = []v1.ConformanceFeature{v1.ConformanceFeatureBoot}
+		expectVMIToReachPhase(v1.Running)
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Feature).To(Equal(v1.ConformanceFeatureBoot))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultPassed))
+	})
+
+	It("should fail boot if the VMI fails", func() {
+		run.Spec.Features = []v1.ConformanceFeature{v1.ConformanceFeatureBoot}
+		expectVMIToReachPhase(v1.Failed)
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultFailed))
+		Expect(results[0].Message).To(ContainSubstring("reached phase Failed"))
+	})
+
+	It("should fail boot if the VMI does not start in time", func() {
+		run.Spec.Features = []v1.ConformanceFeature{v1.ConformanceFeatureBoot}
+		expectVMIToReachPhase(v1.Scheduling)
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultFailed))
+		Expect(results[0].Message).To(ContainSubstring("timed out"))
+	})
+
+	It("should skip migrate with less than two schedulable nodes", func() {
+		run.Spec.Features = []v1.ConformanceFeature{v1.ConformanceFeatureMigrate}
+		_, err := kubeClient.CoreV1().Nodes().Create(newNode("node01"))
+		Expect(err).ToNot(HaveOccurred())
+
+		results := runFeatures(newRunner(virtconfig.LiveMigrationGate))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultSkipped))
+		Expect(results[0].Message).To(ContainSubstring("at least two schedulable nodes"))
+	})
+
+	table.DescribeTable("should skip", func(feature v1.ConformanceFeature, message string) {
+		run.Spec.Features = []v1.ConformanceFeature{feature}
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Feature).To(Equal(feature))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultSkipped))
+		Expect(results[0].Message).To(ContainSubstring(message))
+	},
+		table.Entry("migrate without the LiveMigration feature gate", v1.ConformanceFeatureMigrate, virtconfig.LiveMigrationGate),
+		table.Entry("hotplug", v1.ConformanceFeatureHotplug, "not supported"),
+		table.Entry("snapshot without the Snapshot feature gate", v1.ConformanceFeatureSnapshot, virtconfig.SnapshotGate),
+		table.Entry("SR-IOV without a network", v1.ConformanceFeatureSRIOV, "no SR-IOV network"),
+		table.Entry("network latency without a network checkup", v1.ConformanceFeatureNetworkLatency, "no network checkup"),
+	)
+
+	It("should fail unknown features", func() {
+		run.Spec.Features = []v1.ConformanceFeature{"Teleport"}
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Result).To(Equal(v1.ConformanceResultFailed))
+		Expect(results[0].Message).To(Equal("unknown feature Teleport"))
+	})
+
+	Context("with storage classes", func() {
+		BeforeEach(func() {
+			run.Spec.Features = []v1.ConformanceFeature{v1.ConformanceFeatureBoot, v1.ConformanceFeatureSnapshot}
+			_, err := kubeClient.StorageV1().StorageClasses().Create(&storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "local"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			// the imports succeed right away
+			cdiClient.PrependReactor("create", "datavolumes", func(action testing.Action) (bool, runtime.Object, error) {
+				dataVolume := action.(testing.CreateAction).GetObject().(*cdiv1.DataVolume)
+				dataVolume.Status.Phase = cdiv1.Succeeded
+				return false, nil, nil
+			})
+		})
+
+		It("should boot from a DataVolume of every storage class", func() {
+			run.Spec.StorageClasses = []string{"local"}
+			expectVMIToReachPhase(v1.Running)
+			vmiInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstance, error) {
+				Expect(vmi.Name).To(Equal("testrun-boot-storage-0"))
+				Expect(vmi.Spec.Volumes[0].DataVolume.Name).To(Equal("testrun-boot-storage-0"))
+
+				dataVolume, err := cdiClient.CdiV1alpha1().DataVolumes(run.Namespace).Get("testrun-boot-storage-0", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*dataVolume.Spec.PVC.StorageClassName).To(Equal("local"))
+				Expect(dataVolume.Spec.PVC.AccessModes).To(ConsistOf(k8sv1.ReadWriteOnce))
+				Expect(dataVolume.Spec.Source.Registry.URL).To(Equal("docker://" + defaultImage))
+				return vmi, nil
+			})
+			vmiInterface.EXPECT().Get("testrun-boot-storage-0", gomock.Any()).DoAndReturn(func(name string, _ *metav1.GetOptions) (*v1.VirtualMachineInstance, error) {
+				vmi := v1.NewMinimalVMIWithNS(run.Namespace, name)
+				vmi.Status.Phase = v1.Running
+				return vmi, nil
+			})
+			vmiInterface.EXPECT().Delete("testrun-boot-storage-0", gomock.Any()).Return(nil)
+
+			results := runFeatures(newRunner(""))
+			Expect(results).To(HaveLen(3))
+			Expect(results[2].Feature).To(Equal(v1.ConformanceFeatureBoot))
+			Expect(results[2].StorageClass).To(Equal("local"))
+			Expect(results[2].Result).To(Equal(v1.ConformanceResultPassed))
+
+			_, err := cdiClient.CdiV1alpha1().DataVolumes(run.Namespace).Get("testrun-boot-storage-0", metav1.GetOptions{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should fail the storage features of a missing storage class", func() {
+			run.Spec.StorageClasses = []string{"missing"}
+			expectVMIToReachPhase(v1.Running)
+			vmiInterface.EXPECT().Delete("testrun-boot-storage-0", gomock.Any()).Return(nil)
+
+			results := runFeatures(newRunner(""))
+			Expect(results).To(HaveLen(3))
+			Expect(results[2].StorageClass).To(Equal("missing"))
+			Expect(results[2].Result).To(Equal(v1.ConformanceResultFailed))
+			Expect(results[2].Message).To(ContainSubstring("failed to get storage class missing"))
+		})
+	})
+
+	It("should exercise all features by default", func() {
+		expectVMIToReachPhase(v1.Running)
+
+		results := runFeatures(newRunner(""))
+		Expect(results).To(HaveLen(len(v1.ConformanceFeatures)))
+		for i, feature := range v1.ConformanceFeatures {
+			Expect(results[i].Feature).To(Equal(feature))
+		}
+	})
+})
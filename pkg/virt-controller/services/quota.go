@@ -0,0 +1,357 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	containerdisk "kubevirt.io/kubevirt/pkg/container-disk"
+	"kubevirt.io/kubevirt/pkg/hooks"
+)
+
+// ResourceQuotaExceededError is returned if the virt-launcher pod of a VMI
+// does not fit into a resource quota of its namespace
+type ResourceQuotaExceededError struct {
+	msg string
+}
+
+func (e ResourceQuotaExceededError) Error() string {
+	return e.msg
+}
+
+// RenderLauncherResources renders the containers of the virt-launcher pod of
+// a VMI with their cpu, memory and hugepages resources only. It allows to
+// account the launcher pod against resource quotas before it is rendered.
+func RenderLauncherResources(vmi *v1.VirtualMachineInstance) (*k8sv1.Pod, error) {
+	computeResources, err := getComputeResources(vmi, getMemoryOverhead(vmi))
+	if err != nil {
+		return nil, err
+	}
+	containers := []k8sv1.Container{{Name: "compute", Resources: computeResources}}
+
+	for _, container := range containerdisk.GenerateContainers(vmi, "container-disks", "virt-bin-share-dir") {
+		containers = append(containers, k8sv1.Container{Name: container.Name, Resources: container.Resources})
+	}
+
+	requestedHookSidecarList, err := hooks.UnmarshalHookSidecarList(vmi)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requestedHookSidecarList {
+		containers = append(containers, k8sv1.Container{
+			Name:      fmt.Sprintf("hook-sidecar-%d", i),
+			Resources: getHookSidecarResources(vmi),
+		})
+	}
+
+	if vmi.Spec.ReadinessProbe != nil {
+		containers = append(containers, k8sv1.Container{Name: "kubevirt-infra", Resources: getInfraResources()})
+	}
+
+	return &k8sv1.Pod{
+		Spec: k8sv1.PodSpec{
+			Containers:        containers,
+			InitContainers:    []k8sv1.Container{{Name: "container-disk-binary", Resources: getInitContainerResources(vmi)}},
+			PriorityClassName: vmi.Spec.PriorityClassName,
+		},
+	}, nil
+}
+
+// CalculateLauncherResources computes the requests and limits of the
+// virt-launcher pod of a VMI, as they are accounted by the scheduler and
+// against resource quotas, and the overhead KubeVirt adds to the cpu and
+// memory requested for the guest.
+func CalculateLauncherResources(vmi *v1.VirtualMachineInstance) (*v1.LauncherResources, error) {
+	pod, err := RenderLauncherResources(vmi)
+	if err != nil {
+		return nil, err
+	}
+	requests, limits := podRequestsAndLimits(pod)
+
+	guestResources, err := getComputeResources(vmi, resource.NewQuantity(0, resource.BinarySI))
+	if err != nil {
+		return nil, err
+	}
+	guestRequests := containerRequests(guestResources)
+
+	overhead := k8sv1.ResourceList{}
+	for _, name := range []k8sv1.ResourceName{k8sv1.ResourceCPU, k8sv1.ResourceMemory} {
+		total, ok := requests[name]
+		if !ok {
+			continue
+		}
+		quantity := total.DeepCopy()
+		quantity.Sub(guestRequests[name])
+		if quantity.Sign() < 0 {
+			quantity = *resource.NewQuantity(0, total.Format)
+		}
+		overhead[name] = quantity
+	}
+
+	return &v1.LauncherResources{
+		Requests: requests,
+		Limits:   limits,
+		Overhead: overhead,
+	}, nil
+}
+
+// CheckResourceQuotas verifies that the virt-launcher pod of a VMI fits into
+// the resource quotas of its namespace. The error of an exceeded quota breaks
+// the requested resources down into the ones of the guest and the overhead
+// of the launcher pod.
+func CheckResourceQuotas(vmi *v1.VirtualMachineInstance, pod *k8sv1.Pod, quotas []*k8sv1.ResourceQuota) error {
+	usage := podQuotaUsage(pod)
+
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Name < quotas[j].Name
+	})
+	for _, quota := range quotas {
+		if !quotaMatchesPod(quota, pod) {
+			continue
+		}
+
+		var exceeded []k8sv1.ResourceName
+		for name, hard := range quota.Status.Hard {
+			requested, ok := usage[name]
+			if !ok {
+				continue
+			}
+			total := quota.Status.Used[name]
+			total = total.DeepCopy()
+			total.Add(requested)
+			if total.Cmp(hard) > 0 {
+				exceeded = append(exceeded, name)
+			}
+		}
+		if len(exceeded) == 0 {
+			continue
+		}
+		sortResourceNames(exceeded)
+
+		used := make([]string, 0, len(exceeded))
+		limited := make([]string, 0, len(exceeded))
+		for _, name := range exceeded {
+			quantity := quota.Status.Used[name]
+			used = append(used, fmt.Sprintf("%s=%s", name, quantity.String()))
+			quantity = quota.Status.Hard[name]
+			limited = append(limited, fmt.Sprintf("%s=%s", name, quantity.String()))
+		}
+		return ResourceQuotaExceededError{
+			msg: fmt.Sprintf("exceeded quota: %s, requested: %s, used: %s, limited: %s",
+				quota.Name, DescribeLauncherResources(vmi, pod, exceeded), strings.Join(used, ","), strings.Join(limited, ",")),
+		}
+	}
+	return nil
+}
+
+// DescribeLauncherResources lists how much of the given quota resources the
+// virt-launcher pod of a VMI uses, split into the resources of the guest and
+// the overhead of the launcher pod, e.g. "requests.memory=1208Mi (guest: 1Gi, overhead: 184Mi)"
+func DescribeLauncherResources(vmi *v1.VirtualMachineInstance, pod *k8sv1.Pod, names []k8sv1.ResourceName) string {
+	usage := podQuotaUsage(pod)
+
+	// the guest resources are the ones of the compute container without the overhead
+	guestUsage := k8sv1.ResourceList{}
+	if guestResources, err := getComputeResources(vmi, resource.NewQuantity(0, resource.BinarySI)); err == nil {
+		guestUsage = podQuotaUsage(&k8sv1.Pod{Spec: k8sv1.PodSpec{Containers: []k8sv1.Container{{Resources: guestResources}}}})
+	}
+
+	descriptions := make([]string, 0, len(names))
+	for _, name := range names {
+		total := usage[name]
+		description := fmt.Sprintf("%s=%s", name, total.String())
+
+		if guest, ok := guestUsage[name]; ok && name != k8sv1.ResourcePods && name != podCountResourceName {
+			if guest.Cmp(total) > 0 {
+				guest = total
+			}
+			overhead := total.DeepCopy()
+			overhead.Sub(guest)
+			description = fmt.Sprintf("%s (guest: %s, overhead: %s)", description, guest.String(), overhead.String())
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, ",")
+}
+
+// LauncherQuotaResources returns the quota resources the virt-launcher pod
+// of a VMI uses for cpu and memory
+func LauncherQuotaResources(pod *k8sv1.Pod) []k8sv1.ResourceName {
+	var names []k8sv1.ResourceName
+	for name := range podQuotaUsage(pod) {
+		switch name {
+		case k8sv1.ResourceRequestsCPU, k8sv1.ResourceRequestsMemory, k8sv1.ResourceLimitsCPU, k8sv1.ResourceLimitsMemory:
+			names = append(names, name)
+		}
+	}
+	sortResourceNames(names)
+	return names
+}
+
+const podCountResourceName = k8sv1.ResourceName("count/pods")
+
+// podQuotaUsage computes the usage of a pod the same way the resource quota
+// admission of Kubernetes does
+func podQuotaUsage(pod *k8sv1.Pod) k8sv1.ResourceList {
+	requests, limits := podRequestsAndLimits(pod)
+
+	usage := k8sv1.ResourceList{
+		k8sv1.ResourcePods:   *resource.NewQuantity(1, resource.DecimalSI),
+		podCountResourceName: *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	for name, quantity := range requests {
+		usage[k8sv1.ResourceName(k8sv1.DefaultResourceRequestsPrefix+string(name))] = quantity
+		if name == k8sv1.ResourceCPU || name == k8sv1.ResourceMemory || name == k8sv1.ResourceEphemeralStorage {
+			usage[name] = quantity
+		}
+	}
+	for name, quantity := range limits {
+		if name == k8sv1.ResourceCPU || name == k8sv1.ResourceMemory || name == k8sv1.ResourceEphemeralStorage {
+			usage[k8sv1.ResourceName("limits."+string(name))] = quantity
+		}
+	}
+	return usage
+}
+
+// podRequestsAndLimits sums up the resources of the containers of a pod, the
+// init containers only count if they need more than the containers together
+func podRequestsAndLimits(pod *k8sv1.Pod) (requests k8sv1.ResourceList, limits k8sv1.ResourceList) {
+	requests = k8sv1.ResourceList{}
+	limits = k8sv1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		containerRequests := containerRequests(container.Resources)
+		addResourceList(requests, containerRequests)
+		addResourceList(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		maxResourceList(requests, containerRequests(container.Resources))
+		maxResourceList(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+// containerRequests returns the requests of a container, which default to
+// the limits if they are not set
+func containerRequests(resources k8sv1.ResourceRequirements) k8sv1.ResourceList {
+	requests := k8sv1.ResourceList{}
+	for name, quantity := range resources.Limits {
+		requests[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range resources.Requests {
+		requests[name] = quantity.DeepCopy()
+	}
+	return requests
+}
+
+func addResourceList(list k8sv1.ResourceList, add k8sv1.ResourceList) {
+	for name, quantity := range add {
+		if value, ok := list[name]; ok {
+			value.Add(quantity)
+			list[name] = value
+		} else {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func maxResourceList(list k8sv1.ResourceList, other k8sv1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := list[name]; !ok || quantity.Cmp(value) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// quotaMatchesPod checks whether the scopes of a resource quota select the pod
+func quotaMatchesPod(quota *k8sv1.ResourceQuota, pod *k8sv1.Pod) bool {
+	for _, scope := range quota.Spec.Scopes {
+		if !scopeMatchesPod(k8sv1.ScopedResourceSelectorRequirement{ScopeName: scope, Operator: k8sv1.ScopeSelectorOpExists}, pod) {
+			return false
+		}
+	}
+	if quota.Spec.ScopeSelector != nil {
+		for _, requirement := range quota.Spec.ScopeSelector.MatchExpressions {
+			if !scopeMatchesPod(requirement, pod) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func scopeMatchesPod(requirement k8sv1.ScopedResourceSelectorRequirement, pod *k8sv1.Pod) bool {
+	switch requirement.ScopeName {
+	case k8sv1.ResourceQuotaScopeTerminating:
+		return pod.Spec.ActiveDeadlineSeconds != nil
+	case k8sv1.ResourceQuotaScopeNotTerminating:
+		return pod.Spec.ActiveDeadlineSeconds == nil
+	case k8sv1.ResourceQuotaScopeBestEffort:
+		return isBestEffort(pod)
+	case k8sv1.ResourceQuotaScopeNotBestEffort:
+		return !isBestEffort(pod)
+	case k8sv1.ResourceQuotaScopePriorityClass:
+		switch requirement.Operator {
+		case k8sv1.ScopeSelectorOpExists:
+			return pod.Spec.PriorityClassName != ""
+		case k8sv1.ScopeSelectorOpDoesNotExist:
+			return pod.Spec.PriorityClassName == ""
+		case k8sv1.ScopeSelectorOpIn:
+			return containsString(requirement.Values, pod.Spec.PriorityClassName)
+		case k8sv1.ScopeSelectorOpNotIn:
+			return !containsString(requirement.Values, pod.Spec.PriorityClassName)
+		}
+	}
+	return false
+}
+
+func isBestEffort(pod *k8sv1.Pod) bool {
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		for _, list := range []k8sv1.ResourceList{container.Resources.Requests, container.Resources.Limits} {
+			if _, ok := list[k8sv1.ResourceCPU]; ok {
+				return false
+			}
+			if _, ok := list[k8sv1.ResourceMemory]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortResourceNames(names []k8sv1.ResourceName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+}
@@ -0,0 +1,209 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package services
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	kubev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/hooks"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("Resource quotas", func() {
+
+	var vmi *v1.VirtualMachineInstance
+
+	newQuota := func(name string, hard kubev1.ResourceList, used kubev1.ResourceList) *kubev1.ResourceQuota {
+		return &kubev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: kubev1.ResourceQuotaStatus{
+				Hard: hard,
+				Used: used,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		vmi = v1.NewMinimalVMI("testvmi")
+		vmi.Spec.Domain.Resources.Requests = kubev1.ResourceList{
+			kubev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+	})
+
+	It("should account the resources of the rendered virt-launcher pod", func() {
+		vmi.Spec.Domain.Resources.Requests[kubev1.ResourceCPU] = resource.MustParse("2")
+		vmi.Spec.Domain.Resources.Limits = kubev1.ResourceList{
+			kubev1.ResourceCPU:    resource.MustParse("2"),
+			kubev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		vmi.Spec.Volumes = []v1.Volume{{
+			Name: "containerdisk",
+			VolumeSource: v1.VolumeSource{
+				ContainerDisk: &v1.ContainerDiskSource{Image: "my-image-1"},
+			},
+		}}
+
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		config, _, _, _ := testutils.NewFakeClusterConfig(&kubev1.ConfigMap{})
+		svc := NewTemplateService("kubevirt/virt-launcher",
+			"/var/run/kubevirt",
+			"/var/lib/kubevirt",
+			"/var/run/kubevirt-ephemeral-disks",
+			"/var/run/kubevirt/container-disks",
+			"pull-secret-1",
+			cache.NewIndexer(cache.DeletionHandlingMetaNamespaceKeyFunc, nil),
+			kubecli.NewMockKubevirtClient(ctrl),
+			config,
+			107,
+		)
+		renderedPod, err := svc.RenderLaunchManifest(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		renderedUsage := podQuotaUsage(renderedPod)
+		usage := podQuotaUsage(pod)
+		Expect(LauncherQuotaResources(pod)).To(HaveLen(4))
+		for _, name := range LauncherQuotaResources(pod) {
+			quantity := usage[name]
+			Expect(quantity.Cmp(renderedUsage[name])).To(BeZero(), string(name))
+		}
+	})
+
+	It("should calculate the resources and the overhead of the virt-launcher pod", func() {
+		vmi.Spec.Domain.Resources.Limits = kubev1.ResourceList{
+			kubev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+
+		resources, err := CalculateLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+		usage := podQuotaUsage(pod)
+		requestedMemory := usage[kubev1.ResourceRequestsMemory]
+		limitedMemory := usage[kubev1.ResourceLimitsMemory]
+
+		memory := resources.Requests[kubev1.ResourceMemory]
+		Expect(memory.Cmp(requestedMemory)).To(BeZero())
+		memory = resources.Limits[kubev1.ResourceMemory]
+		Expect(memory.Cmp(limitedMemory)).To(BeZero())
+
+		overhead := resources.Overhead[kubev1.ResourceMemory]
+		expectedOverhead := requestedMemory.DeepCopy()
+		expectedOverhead.Sub(resource.MustParse("1Gi"))
+		Expect(overhead.Sign()).To(Equal(1))
+		Expect(overhead.Cmp(expectedOverhead)).To(BeZero())
+	})
+
+	It("should fail to calculate the resources of a vmi with invalid hook sidecars", func() {
+		vmi.Annotations = map[string]string{hooks.HookSidecarListAnnotationName: "not json"}
+		_, err := CalculateLauncherResources(vmi)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should accept a virt-launcher pod which fits into the quotas", func() {
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		quota := newQuota("memory",
+			kubev1.ResourceList{kubev1.ResourceRequestsMemory: resource.MustParse("4Gi")},
+			kubev1.ResourceList{kubev1.ResourceRequestsMemory: resource.MustParse("2Gi")},
+		)
+		Expect(CheckResourceQuotas(vmi, pod, []*kubev1.ResourceQuota{quota})).To(Succeed())
+	})
+
+	It("should break the resources down if the overhead exceeds a quota", func() {
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		// the guest memory alone would fit
+		quota := newQuota("memory",
+			kubev1.ResourceList{
+				kubev1.ResourceRequestsMemory: resource.MustParse("4Gi"),
+				kubev1.ResourcePods:           resource.MustParse("10"),
+			},
+			kubev1.ResourceList{
+				kubev1.ResourceRequestsMemory: resource.MustParse("3Gi"),
+				kubev1.ResourcePods:           resource.MustParse("1"),
+			},
+		)
+		err = CheckResourceQuotas(vmi, pod, []*kubev1.ResourceQuota{quota})
+		Expect(err).To(BeAssignableToTypeOf(ResourceQuotaExceededError{}))
+
+		Expect(err.Error()).To(HavePrefix("exceeded quota: memory, requested: requests.memory="))
+		Expect(err.Error()).To(ContainSubstring("(guest: 1Gi, overhead: "))
+		Expect(err.Error()).To(HaveSuffix("used: requests.memory=3Gi, limited: requests.memory=4Gi"))
+	})
+
+	It("should ignore quotas without status", func() {
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		quota := newQuota("memory", nil, nil)
+		quota.Spec.Hard = kubev1.ResourceList{kubev1.ResourceRequestsMemory: resource.MustParse("1Mi")}
+		Expect(CheckResourceQuotas(vmi, pod, []*kubev1.ResourceQuota{quota})).To(Succeed())
+	})
+
+	table.DescribeTable("should only account the virt-launcher pod against quotas with matching scopes", func(scopes []kubev1.ResourceQuotaScope, selector *kubev1.ScopeSelector, matches bool) {
+		vmi.Spec.PriorityClassName = "high"
+		pod, err := RenderLauncherResources(vmi)
+		Expect(err).ToNot(HaveOccurred())
+
+		quota := newQuota("pods", kubev1.ResourceList{kubev1.ResourcePods: resource.MustParse("1")}, kubev1.ResourceList{kubev1.ResourcePods: resource.MustParse("1")})
+		quota.Spec.Scopes = scopes
+		quota.Spec.ScopeSelector = selector
+		if matches {
+			Expect(CheckResourceQuotas(vmi, pod, []*kubev1.ResourceQuota{quota})).ToNot(Succeed())
+		} else {
+			Expect(CheckResourceQuotas(vmi, pod, []*kubev1.ResourceQuota{quota})).To(Succeed())
+		}
+	},
+		table.Entry("without scopes", nil, nil, true),
+		table.Entry("with the NotTerminating and NotBestEffort scopes", []kubev1.ResourceQuotaScope{kubev1.ResourceQuotaScopeNotTerminating, kubev1.ResourceQuotaScopeNotBestEffort}, nil, true),
+		table.Entry("with the Terminating scope", []kubev1.ResourceQuotaScope{kubev1.ResourceQuotaScopeTerminating}, nil, false),
+		table.Entry("with the BestEffort scope", []kubev1.ResourceQuotaScope{kubev1.ResourceQuotaScopeBestEffort}, nil, false),
+		table.Entry("with a matching priority class", nil, &kubev1.ScopeSelector{
+			MatchExpressions: []kubev1.ScopedResourceSelectorRequirement{{
+				ScopeName: kubev1.ResourceQuotaScopePriorityClass,
+				Operator:  kubev1.ScopeSelectorOpIn,
+				Values:    []string{"high"},
+			}},
+		}, true),
+		table.Entry("with another priority class", nil, &kubev1.ScopeSelector{
+			MatchExpressions: []kubev1.ScopedResourceSelectorRequirement{{
+				ScopeName: kubev1.ResourceQuotaScopePriorityClass,
+				Operator:  kubev1.ScopeSelectorOpIn,
+				Values:    []string{"low"},
+			}},
+		}, false),
+	)
+})
@@ -30,6 +30,7 @@ import (
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 
 	hostdisk "kubevirt.io/kubevirt/pkg/host-disk"
@@ -43,6 +44,8 @@ import (
 	"kubevirt.io/kubevirt/pkg/config"
 	containerdisk "kubevirt.io/kubevirt/pkg/container-disk"
 	"kubevirt.io/kubevirt/pkg/hooks"
+	"kubevirt.io/kubevirt/pkg/ignition"
+	"kubevirt.io/kubevirt/pkg/tpm"
 	"kubevirt.io/kubevirt/pkg/util"
 	"kubevirt.io/kubevirt/pkg/util/hardware"
 	"kubevirt.io/kubevirt/pkg/util/net/dns"
@@ -54,6 +57,10 @@ const configMapName = "kubevirt-config"
 const KvmDevice = "devices.kubevirt.io/kvm"
 const TunDevice = "devices.kubevirt.io/tun"
 const VhostNetDevice = "devices.kubevirt.io/vhost-net"
+const SEVDevice = "devices.kubevirt.io/sev"
+
+// seccompLocalhostProfilePrefix prefixes the profiles in the seccomp directory of the kubelet
+const seccompLocalhostProfilePrefix = "localhost/"
 
 const debugLogs = "debugLogs"
 
@@ -62,6 +69,14 @@ const MultusNetworksAnnotation = "k8s.v1.cni.cncf.io/networks"
 const CAP_NET_ADMIN = "NET_ADMIN"
 const CAP_NET_RAW = "NET_RAW"
 const CAP_SYS_NICE = "SYS_NICE"
+const CAP_SYS_ADMIN = "SYS_ADMIN"
+const CAP_CHOWN = "CHOWN"
+const CAP_DAC_OVERRIDE = "DAC_OVERRIDE"
+const CAP_FOWNER = "FOWNER"
+const CAP_SETGID = "SETGID"
+const CAP_SETUID = "SETUID"
+const CAP_MKNOD = "MKNOD"
+const CAP_SETFCAP = "SETFCAP"
 
 // LibvirtStartupDelay is added to custom liveness and readiness probes initial delay value.
 // Libvirt needs roughly 10 seconds to start.
@@ -69,18 +84,24 @@ const LibvirtStartupDelay = 10
 
 //These perfixes for node feature discovery, are used in a NodeSelector on the pod
 //to match a VirtualMachineInstance CPU model(Family) and/or features to nodes that support them.
-const NFD_CPU_MODEL_PREFIX = "feature.node.kubernetes.io/cpu-model-"
-const NFD_CPU_FEATURE_PREFIX = "feature.node.kubernetes.io/cpu-feature-"
-const NFD_KVM_INFO_PREFIX = "feature.node.kubernetes.io/kvm-info-cap-hyperv-"
+const NFD_CPU_MODEL_PREFIX = v1.CPUModelLabel
+const NFD_CPU_FEATURE_PREFIX = v1.CPUFeatureLabel
+const NFD_KVM_INFO_PREFIX = v1.HypervLabel
 
 const MULTUS_RESOURCE_NAME_ANNOTATION = "k8s.v1.cni.cncf.io/resourceName"
 const MULTUS_DEFAULT_NETWORK_CNI_ANNOTATION = "v1.multus-cni.io/default-network"
 
+const OVN_KUBERNETES_CNI_TYPE = "ovn-k8s-cni-overlay"
+
 // Istio list of virtual interfaces whose inbound traffic (from VM) will be treated as outbound traffic in envoy
 const ISTIO_KUBEVIRT_ANNOTATION = "traffic.sidecar.istio.io/kubevirtInterfaces"
 
 const ENV_VAR_LIBVIRT_DEBUG_LOGS = "LIBVIRT_DEBUG_LOGS"
 
+// serial console log rotation defaults, the same as the virtlogd defaults
+const defaultSerialConsoleLogMaxSize = 2 * 1024 * 1024
+const defaultSerialConsoleLogMaxFiles = 3
+
 type TemplateService interface {
 	RenderLaunchManifest(*v1.VirtualMachineInstance) (*k8sv1.Pod, error)
 }
@@ -99,6 +120,9 @@ type templateService struct {
 }
 
 type PvcNotFoundError error
+type PvcNotMultiAttachableError struct {
+	error
+}
 
 func isFeatureStateEnabled(fs *v1.FeatureState) bool {
 	return fs != nil && fs.Enabled != nil && *fs.Enabled
@@ -204,6 +228,26 @@ func CPUFeatureLabelsFromCPUFeatures(vmi *v1.VirtualMachineInstance) []string {
 	return labels
 }
 
+func getSerialConsoleLogArgs(serialConsoleLog *v1.SerialConsoleLog) []string {
+	maxSize := int64(defaultSerialConsoleLogMaxSize)
+	if serialConsoleLog.MaxSize != nil {
+		maxSize = serialConsoleLog.MaxSize.Value()
+	}
+	maxFiles := uint32(defaultSerialConsoleLogMaxFiles)
+	if serialConsoleLog.MaxFiles != nil {
+		maxFiles = *serialConsoleLog.MaxFiles
+	}
+
+	args := []string{
+		"--serial-console-log-max-size", strconv.FormatInt(maxSize, 10),
+		"--serial-console-log-max-files", strconv.FormatUint(uint64(maxFiles), 10),
+	}
+	if serialConsoleLog.VolumeName == "" {
+		args = append(args, "--serial-console-log-stdout")
+	}
+	return args
+}
+
 func SetNodeAffinityForForbiddenFeaturePolicy(vmi *v1.VirtualMachineInstance, pod *k8sv1.Pod) {
 
 	if vmi.Spec.Domain.CPU == nil || vmi.Spec.Domain.CPU.Features == nil {
@@ -326,6 +370,11 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		privileged = true
 	}
 
+	nonRoot := util.IsNonRootVMI(vmi)
+	if nonRoot {
+		userId = util.NonRootUID
+	}
+
 	gracePeriodSeconds := v1.DefaultGracePeriodSeconds
 	if vmi.Spec.TerminationGracePeriodSeconds != nil {
 		gracePeriodSeconds = *vmi.Spec.TerminationGracePeriodSeconds
@@ -348,6 +397,20 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		MountPath: "/var/run/libvirt",
 	})
 
+	if nonRoot {
+		// the directories of the image are only writable by root
+		volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+			Name:      "private",
+			MountPath: util.VirtPrivateDir,
+		})
+		volumes = append(volumes, k8sv1.Volume{
+			Name: "private",
+			VolumeSource: k8sv1.VolumeSource{
+				EmptyDir: &k8sv1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
 	// virt-launcher cmd socket dir
 	volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
 		Name:      "sockets",
@@ -360,7 +423,7 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		},
 	})
 
-	if util.IsSRIOVVmi(vmi) || util.IsGPUVMI(vmi) || util.IsQATVMI(vmi) {
+	if util.IsSRIOVVmi(vmi) || util.IsGPUVMI(vmi) || util.IsQATVMI(vmi) || util.IsHostDevVMI(vmi) {
 		// libvirt needs this volume to access PCI device config;
 		// note that the volume should not be read-only because libvirt
 		// opens the config for writing
@@ -420,6 +483,39 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 				},
 			})
 		}
+		if volume.GoldenImage != nil {
+			logger := log.DefaultLogger()
+			claimName := volume.GoldenImage.ClaimName
+			pvc, exists, isBlock, err := types.IsPVCBlockFromStore(t.persistentVolumeClaimStore, namespace, claimName)
+			if err != nil {
+				logger.Errorf("error getting golden image PVC: %v", claimName)
+				return nil, err
+			} else if !exists {
+				logger.Errorf("didn't find golden image PVC %v", claimName)
+				return nil, PvcNotFoundError(fmt.Errorf("didn't find golden image PVC %v", claimName))
+			} else if !types.IsPVCMultiAttachable(pvc) {
+				// a golden image has to be attachable on all nodes, otherwise the vmis using it
+				// would be bound to the node of the first one
+				return nil, PvcNotMultiAttachableError{fmt.Errorf("golden image PVC %v needs the %s or %s access mode", claimName, k8sv1.ReadWriteMany, k8sv1.ReadOnlyMany)}
+			} else if isBlock {
+				volumeDevices = append(volumeDevices, k8sv1.VolumeDevice{
+					Name:       volume.Name,
+					DevicePath: filepath.Join(string(filepath.Separator), "dev", volume.Name),
+				})
+			} else {
+				volumeMount.ReadOnly = true
+				volumeMounts = append(volumeMounts, volumeMount)
+			}
+			volumes = append(volumes, k8sv1.Volume{
+				Name: volume.Name,
+				VolumeSource: k8sv1.VolumeSource{
+					PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+						ReadOnly:  true,
+					},
+				},
+			})
+		}
 		if volume.ContainerDisk != nil && volume.ContainerDisk.ImagePullSecret != "" {
 			imagePullSecrets = appendUniqueImagePullSecret(imagePullSecrets, k8sv1.LocalObjectReference{
 				Name: volume.ContainerDisk.ImagePullSecret,
@@ -515,6 +611,34 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 			})
 		}
 
+		if volume.Sysprep != nil {
+			// attach the ConfigMap or Secret containing the Sysprep answer file to the pod
+			volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+				Name:      volume.Name,
+				MountPath: filepath.Join(config.SysprepSourceDir, volume.Name),
+				ReadOnly:  true,
+			})
+			if volume.Sysprep.ConfigMap != nil {
+				volumes = append(volumes, k8sv1.Volume{
+					Name: volume.Name,
+					VolumeSource: k8sv1.VolumeSource{
+						ConfigMap: &k8sv1.ConfigMapVolumeSource{
+							LocalObjectReference: *volume.Sysprep.ConfigMap,
+						},
+					},
+				})
+			} else if volume.Sysprep.Secret != nil {
+				volumes = append(volumes, k8sv1.Volume{
+					Name: volume.Name,
+					VolumeSource: k8sv1.VolumeSource{
+						Secret: &k8sv1.SecretVolumeSource{
+							SecretName: volume.Sysprep.Secret.Name,
+						},
+					},
+				})
+			}
+		}
+
 		if volume.ServiceAccount != nil {
 			serviceAccountName = volume.ServiceAccount.ServiceAccountName
 		}
@@ -558,6 +682,42 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 			}
 		}
 
+		if volume.Ignition != nil {
+			if volume.Ignition.SecretRef != nil {
+				// attach the secret containing the Ignition config
+				volumes = append(volumes, k8sv1.Volume{
+					Name: volume.Name,
+					VolumeSource: k8sv1.VolumeSource{
+						Secret: &k8sv1.SecretVolumeSource{
+							SecretName: volume.Ignition.SecretRef.Name,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+					Name:      volume.Name,
+					MountPath: filepath.Join(config.SecretSourceDir, volume.Name, ignition.ConfigKey),
+					SubPath:   ignition.ConfigKey,
+					ReadOnly:  true,
+				})
+			} else if volume.Ignition.ConfigMapRef != nil {
+				// attach the config map containing the Ignition config
+				volumes = append(volumes, k8sv1.Volume{
+					Name: volume.Name,
+					VolumeSource: k8sv1.VolumeSource{
+						ConfigMap: &k8sv1.ConfigMapVolumeSource{
+							LocalObjectReference: *volume.Ignition.ConfigMapRef,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+					Name:      volume.Name,
+					MountPath: filepath.Join(config.ConfigMapSourceDir, volume.Name, ignition.ConfigKey),
+					SubPath:   ignition.ConfigKey,
+					ReadOnly:  true,
+				})
+			}
+		}
+
 		if volume.CloudInitConfigDrive != nil {
 			if volume.CloudInitConfigDrive.UserDataSecretRef != nil {
 				// attach a secret referenced by the user
@@ -598,6 +758,28 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		}
 	}
 
+	if tpm.HasPersistentState(vmi) {
+		// attach the claim holding the TPM state
+		volumes = append(volumes, k8sv1.Volume{
+			Name: tpm.StateVolumeName,
+			VolumeSource: k8sv1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{
+					ClaimName: vmi.Spec.Domain.Devices.TPM.PersistentStateClaimName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
+			Name:      tpm.StateVolumeName,
+			MountPath: tpm.StateSourceDir,
+		})
+	}
+
+	if firmware := containerdisk.GetFirmwareContainerDisk(vmi); firmware != nil && firmware.ImagePullSecret != "" {
+		imagePullSecrets = appendUniqueImagePullSecret(imagePullSecrets, k8sv1.LocalObjectReference{
+			Name: firmware.ImagePullSecret,
+		})
+	}
+
 	if t.imagePullSecret != "" {
 		imagePullSecrets = appendUniqueImagePullSecret(imagePullSecrets, k8sv1.LocalObjectReference{
 			Name: t.imagePullSecret,
@@ -611,42 +793,13 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 	gracePeriodSeconds = gracePeriodSeconds + int64(15)
 	gracePeriodKillAfter := gracePeriodSeconds + int64(15)
 
-	// Get memory overhead
-	memoryOverhead := getMemoryOverhead(vmi)
-
-	// Consider CPU and memory requests and limits for pod scheduling
-	resources := k8sv1.ResourceRequirements{}
-	vmiResources := vmi.Spec.Domain.Resources
-
-	resources.Requests = make(k8sv1.ResourceList)
-	resources.Limits = make(k8sv1.ResourceList)
-
-	// Copy vmi resources requests to a container
-	for key, value := range vmiResources.Requests {
-		resources.Requests[key] = value
-	}
-
-	// Copy vmi resources limits to a container
-	for key, value := range vmiResources.Limits {
-		resources.Limits[key] = value
+	resources, err := getComputeResources(vmi, getMemoryOverhead(vmi))
+	if err != nil {
+		return nil, err
 	}
 
 	// Consider hugepages resource for pod scheduling
 	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Hugepages != nil {
-		hugepageType := k8sv1.ResourceName(k8sv1.ResourceHugePagesPrefix + vmi.Spec.Domain.Memory.Hugepages.PageSize)
-		hugepagesMemReq := vmi.Spec.Domain.Resources.Requests.Memory()
-
-		// If requested, use the guest memory to allocate hugepages
-		if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
-			requests := vmi.Spec.Domain.Resources.Requests.Memory().Value()
-			guest := vmi.Spec.Domain.Memory.Guest.Value()
-			if requests > guest {
-				hugepagesMemReq = vmi.Spec.Domain.Memory.Guest
-			}
-		}
-		resources.Requests[hugepageType] = *hugepagesMemReq
-		resources.Limits[hugepageType] = *hugepagesMemReq
-
 		// Configure hugepages mount on a pod
 		volumeMounts = append(volumeMounts, k8sv1.VolumeMount{
 			Name:      "hugepages",
@@ -660,43 +813,6 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 				},
 			},
 		})
-
-		reqMemDiff := resource.NewScaledQuantity(0, resource.Kilo)
-		limMemDiff := resource.NewScaledQuantity(0, resource.Kilo)
-		// In case the guest memory and the requested memeory are diffrent, add the difference
-		// to the to the overhead
-		if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
-			requests := vmi.Spec.Domain.Resources.Requests.Memory().Value()
-			limits := vmi.Spec.Domain.Resources.Limits.Memory().Value()
-			guest := vmi.Spec.Domain.Memory.Guest.Value()
-			if requests > guest {
-				reqMemDiff.Add(*vmi.Spec.Domain.Resources.Requests.Memory())
-				reqMemDiff.Sub(*vmi.Spec.Domain.Memory.Guest)
-			}
-			if limits > guest {
-				limMemDiff.Add(*vmi.Spec.Domain.Resources.Limits.Memory())
-				limMemDiff.Sub(*vmi.Spec.Domain.Memory.Guest)
-			}
-		}
-		// Set requested memory equals to overhead memory
-		reqMemDiff.Add(*memoryOverhead)
-		resources.Requests[k8sv1.ResourceMemory] = *reqMemDiff
-		if _, ok := resources.Limits[k8sv1.ResourceMemory]; ok {
-			limMemDiff.Add(*memoryOverhead)
-			resources.Limits[k8sv1.ResourceMemory] = *limMemDiff
-		}
-	} else {
-		// Add overhead memory
-		memoryRequest := resources.Requests[k8sv1.ResourceMemory]
-		if !vmi.Spec.Domain.Resources.OvercommitGuestOverhead {
-			memoryRequest.Add(*memoryOverhead)
-		}
-		resources.Requests[k8sv1.ResourceMemory] = memoryRequest
-
-		if memoryLimit, ok := resources.Limits[k8sv1.ResourceMemory]; ok {
-			memoryLimit.Add(*memoryOverhead)
-			resources.Limits[k8sv1.ResourceMemory] = memoryLimit
-		}
 	}
 
 	// Read requested hookSidecars from VMI meta
@@ -723,30 +839,19 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		// schedule only on nodes with a running cpu manager
 		nodeSelector[v1.CPUManager] = "true"
 
-		vcpus := hardware.GetNumberOfVCPUs(vmi.Spec.Domain.CPU)
-
-		if vcpus != 0 {
-			resources.Limits[k8sv1.ResourceCPU] = *resource.NewQuantity(vcpus, resource.BinarySI)
-		} else {
-			if cpuLimit, ok := resources.Limits[k8sv1.ResourceCPU]; ok {
-				resources.Requests[k8sv1.ResourceCPU] = cpuLimit
-			} else if cpuRequest, ok := resources.Requests[k8sv1.ResourceCPU]; ok {
-				resources.Limits[k8sv1.ResourceCPU] = cpuRequest
-			}
+		if vmi.IsRealtimeEnabled() {
+			nodeSelector[v1.RealtimeLabel] = "true"
 		}
-		// allocate 1 more pcpu if IsolateEmulatorThread request
-		if vmi.Spec.Domain.CPU.IsolateEmulatorThread {
-			emulatorThreadCpu := resource.NewQuantity(1, resource.BinarySI)
-			limits := resources.Limits[k8sv1.ResourceCPU]
-			limits.Add(*emulatorThreadCpu)
-			resources.Limits[k8sv1.ResourceCPU] = limits
-			if cpuRequest, ok := resources.Requests[k8sv1.ResourceCPU]; ok {
-				cpuRequest.Add(*emulatorThreadCpu)
-				resources.Requests[k8sv1.ResourceCPU] = cpuRequest
+
+		// match the hyperthreads of the node
+		if policy := vmi.Spec.Domain.CPU.ThreadPolicy; policy != nil {
+			switch *policy {
+			case v1.CPUThreadPolicyFullCores:
+				nodeSelector[v1.CPUThreadsPerCoreLabel] = strconv.Itoa(int(vmi.Spec.Domain.CPU.Threads))
+			case v1.CPUThreadPolicyIsolate:
+				nodeSelector[v1.CPUThreadsPerCoreLabel] = "2"
 			}
 		}
-
-		resources.Limits[k8sv1.ResourceMemory] = *resources.Requests.Memory()
 	}
 
 	lessPVCSpaceToleration := t.clusterConfig.GetLessPVCSpaceToleration()
@@ -779,12 +884,24 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		resources.Limits[key] = val
 	}
 
+	if vmi.Spec.Domain.Devices.SerialConsoleLog != nil {
+		command = append(command, getSerialConsoleLogArgs(vmi.Spec.Domain.Devices.SerialConsoleLog)...)
+	}
+
+	if nonRoot {
+		command = append(command, "--run-as-nonroot")
+	}
+
 	if useEmulation {
 		command = append(command, "--use-emulation")
 	} else {
 		resources.Limits[KvmDevice] = resource.MustParse("1")
 	}
 
+	if util.IsSEVVMI(vmi) {
+		resources.Limits[SEVDevice] = resource.MustParse("1")
+	}
+
 	// Add ports from interfaces to the pod manifest
 	ports := getPortsFromVMI(vmi)
 
@@ -838,6 +955,12 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		}
 	}
 
+	if util.IsHostDevVMI(vmi) {
+		for _, hostDev := range vmi.Spec.Domain.Devices.HostDevices {
+			requestResource(&resources, hostDev.DeviceName)
+		}
+	}
+
 	// VirtualMachineInstance target container
 	compute := k8sv1.Container{
 		Name:            "compute",
@@ -858,13 +981,24 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		ReadinessProbe: defaultReadinessProbe,
 	}
 
+	if nonRoot {
+		// virt-handler prepares the pod network and hands the devices over to
+		// the user of qemu and libvirt, the compute container needs no privileges
+		allowPrivilegeEscalation := false
+		compute.SecurityContext.RunAsNonRoot = &nonRoot
+		compute.SecurityContext.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+		compute.SecurityContext.Capabilities = &k8sv1.Capabilities{
+			Drop: []k8sv1.Capability{"ALL"},
+		}
+	}
+
 	if vmi.Spec.ReadinessProbe != nil {
-		compute.ReadinessProbe = copyProbe(vmi.Spec.ReadinessProbe)
+		compute.ReadinessProbe = copyProbe(vmi, vmi.Spec.ReadinessProbe)
 		compute.ReadinessProbe.InitialDelaySeconds = compute.ReadinessProbe.InitialDelaySeconds + LibvirtStartupDelay
 	}
 
 	if vmi.Spec.LivenessProbe != nil {
-		compute.LivenessProbe = copyProbe(vmi.Spec.LivenessProbe)
+		compute.LivenessProbe = copyProbe(vmi, vmi.Spec.LivenessProbe)
 		compute.LivenessProbe.InitialDelaySeconds = compute.LivenessProbe.InitialDelaySeconds + LibvirtStartupDelay
 	}
 
@@ -947,21 +1081,13 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 	podLabels[v1.CreatedByLabel] = string(vmi.UID)
 
 	for i, requestedHookSidecar := range requestedHookSidecarList {
-		resources := k8sv1.ResourceRequirements{}
-		// add default cpu and memory limits to enable cpu pinning if requested
-		// TODO(vladikr): make the hookSidecar express resources
-		if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
-			resources.Limits = make(k8sv1.ResourceList)
-			resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("200m")
-			resources.Limits[k8sv1.ResourceMemory] = resource.MustParse("64M")
-		}
 		sidecar := k8sv1.Container{
 			Name:            fmt.Sprintf("hook-sidecar-%d", i),
 			Image:           requestedHookSidecar.Image,
 			ImagePullPolicy: requestedHookSidecar.ImagePullPolicy,
 			Command:         requestedHookSidecar.Command,
 			Args:            requestedHookSidecar.Args,
-			Resources:       resources,
+			Resources:       getHookSidecarResources(vmi),
 			VolumeMounts: []k8sv1.VolumeMount{
 				k8sv1.VolumeMount{
 					Name:      "hook-sidecar-sockets",
@@ -984,12 +1110,7 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 			SecurityContext: &k8sv1.SecurityContext{
 				RunAsUser: &userId,
 			},
-			Resources: k8sv1.ResourceRequirements{
-				Limits: map[k8sv1.ResourceName]resource.Quantity{
-					k8sv1.ResourceCPU:    resource.MustParse("1m"),
-					k8sv1.ResourceMemory: resource.MustParse("40Mi"),
-				},
-			},
+			Resources:      getInfraResources(),
 			Command:        []string{"/usr/bin/tail", "-f", "/dev/null"},
 			VolumeDevices:  volumeDevices,
 			VolumeMounts:   volumeMounts,
@@ -1024,7 +1145,10 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 
 	for _, network := range vmi.Spec.Networks {
 		if network.Multus != nil && network.Multus.Default {
-			annotationsList[MULTUS_DEFAULT_NETWORK_CNI_ANNOTATION] = network.Multus.NetworkName
+			annotationsList[MULTUS_DEFAULT_NETWORK_CNI_ANNOTATION], err = getDefaultNetworkAnnotation(vmi, &network)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -1039,22 +1163,6 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		},
 	}
 
-	initContainerResources := k8sv1.ResourceRequirements{}
-	if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
-		initContainerResources.Limits = make(k8sv1.ResourceList)
-		initContainerResources.Limits[k8sv1.ResourceCPU] = resource.MustParse("10m")
-		initContainerResources.Limits[k8sv1.ResourceMemory] = resource.MustParse("40M")
-		initContainerResources.Requests = make(k8sv1.ResourceList)
-		initContainerResources.Requests[k8sv1.ResourceCPU] = resource.MustParse("10m")
-		initContainerResources.Requests[k8sv1.ResourceMemory] = resource.MustParse("40M")
-	} else {
-		initContainerResources.Limits = make(k8sv1.ResourceList)
-		initContainerResources.Limits[k8sv1.ResourceCPU] = resource.MustParse("100m")
-		initContainerResources.Limits[k8sv1.ResourceMemory] = resource.MustParse("40M")
-		initContainerResources.Requests = make(k8sv1.ResourceList)
-		initContainerResources.Requests[k8sv1.ResourceCPU] = resource.MustParse("10m")
-		initContainerResources.Requests[k8sv1.ResourceMemory] = resource.MustParse("1M")
-	}
 	initContainerCommand := []string{"/usr/bin/cp",
 		"/usr/bin/container-disk",
 		"/init/usr/bin/container-disk",
@@ -1066,7 +1174,7 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 			ImagePullPolicy: imagePullPolicy,
 			Command:         initContainerCommand,
 			VolumeMounts:    initContainerVolumeMounts,
-			Resources:       initContainerResources,
+			Resources:       getInitContainerResources(vmi),
 		},
 	}
 
@@ -1100,7 +1208,7 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 	}
 
 	// If an SELinux type was specified, use that--otherwise don't set an SELinux type
-	selinuxType := t.clusterConfig.GetSELinuxLauncherType()
+	selinuxType, _ := t.clusterConfig.GetSELinuxLauncherTypeForClass(SELinuxWorkloadClass(vmi))
 	if selinuxType != "" {
 		pod.Spec.SecurityContext.SELinuxOptions = &k8sv1.SELinuxOptions{Type: selinuxType}
 		// By setting an SELinux option on the virt-launcher pod, we trigger this:
@@ -1122,8 +1230,26 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 		}
 	}
 
+	// virt-handler verifies that the cgroup of the pod supports pinning the vCPUs, which
+	// cgroup v2 nodes without the cpuset controller don't
+	if vmi.IsCPUDedicated() {
+		pod.Spec.ReadinessGates = []k8sv1.PodReadinessGate{
+			{ConditionType: k8sv1.PodConditionType(v1.CgroupReadinessGate)},
+		}
+	}
+
+	// The seccomp profile of the cluster config overrides one requested by the vmi annotations
+	if seccompProfile := seccompProfileAnnotation(t.clusterConfig.GetSeccompConfiguration()); seccompProfile != "" {
+		pod.Annotations[k8sv1.SeccompPodAnnotationKey] = seccompProfile
+	}
+
 	if vmi.Spec.PriorityClassName != "" {
 		pod.Spec.PriorityClassName = vmi.Spec.PriorityClassName
+		if t.clusterConfig.VMPreemptionEnabled() {
+			// virt-controller preempts lower priority vmis gracefully instead of the scheduler
+			preemptNever := k8sv1.PreemptNever
+			pod.Spec.PreemptionPolicy = &preemptNever
+		}
 	}
 
 	if vmi.Spec.Affinity != nil {
@@ -1135,6 +1261,7 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 	}
 
 	pod.Spec.Tolerations = vmi.Spec.Tolerations
+	pod.Spec.TopologySpreadConstraints = vmi.Spec.TopologySpreadConstraints
 
 	pod.Spec.SchedulerName = vmi.Spec.SchedulerName
 
@@ -1150,11 +1277,57 @@ func (t *templateService) RenderLaunchManifest(vmi *v1.VirtualMachineInstance) (
 	return &pod, nil
 }
 
+// seccompProfileAnnotation returns the value of the seccomp annotation of the virt-launcher
+// pods, empty to leave the profile to the container runtime
+func seccompProfileAnnotation(seccompConfiguration *v1.SeccompConfiguration) string {
+	if seccompConfiguration == nil {
+		return ""
+	}
+	switch seccompConfiguration.Type {
+	case v1.SeccompProfileRuntimeDefault:
+		return k8sv1.SeccompProfileRuntimeDefault
+	case v1.SeccompProfileKubeVirt:
+		return seccompLocalhostProfilePrefix + util.KubeVirtSeccompProfile
+	case v1.SeccompProfileCustom:
+		return seccompLocalhostProfilePrefix + seccompConfiguration.CustomProfile
+	}
+	return ""
+}
+
+// SELinuxWorkloadClass returns the workload class which determines the SELinux
+// type of the virt-launcher pod, the class selected by the annotation of the VMI
+// or else the class matching its devices
+func SELinuxWorkloadClass(vmi *v1.VirtualMachineInstance) string {
+	if class, exists := vmi.Annotations[v1.SELinuxWorkloadClassAnnotation]; exists {
+		return class
+	}
+	if util.IsSEVVMI(vmi) {
+		return v1.SELinuxWorkloadClassSEV
+	}
+	if util.IsSRIOVVmi(vmi) || util.IsGPUVMI(vmi) || util.IsHostDevVMI(vmi) {
+		return v1.SELinuxWorkloadClassVFIO
+	}
+	return v1.SELinuxWorkloadClassDefault
+}
+
+// requiresNetAdmin tells whether the pod network has to be rewired for the
+// interfaces of the VMI, passt connects the guest from user space instead
+func requiresNetAdmin(vmi *v1.VirtualMachineInstance) bool {
+	interfaces := vmi.Spec.Domain.Devices.Interfaces
+	if len(interfaces) == 0 {
+		return vmi.Spec.Domain.Devices.AutoattachPodInterface == nil || *vmi.Spec.Domain.Devices.AutoattachPodInterface
+	}
+	for _, iface := range interfaces {
+		if iface.Passt == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func getRequiredCapabilities(vmi *v1.VirtualMachineInstance) []k8sv1.Capability {
 	res := []k8sv1.Capability{}
-	if (len(vmi.Spec.Domain.Devices.Interfaces) > 0) ||
-		(vmi.Spec.Domain.Devices.AutoattachPodInterface == nil) ||
-		(*vmi.Spec.Domain.Devices.AutoattachPodInterface == true) {
+	if requiresNetAdmin(vmi) {
 		res = append(res, CAP_NET_ADMIN)
 		// The DHCP server needs the ability to use raw sockets. This
 		// capability is available by default in some clusters, but not
@@ -1163,6 +1336,11 @@ func getRequiredCapabilities(vmi *v1.VirtualMachineInstance) []k8sv1.Capability
 	}
 	// add a CAP_SYS_NICE capability to allow setting cpu affinity
 	res = append(res, CAP_SYS_NICE)
+	if util.IsVMIVirtiofsEnabled(vmi) {
+		// virtiofsd, which is spawned by libvirt, sets up its own sandbox and
+		// has to preserve the ownership and the permissions of the shared files
+		res = append(res, CAP_SYS_ADMIN, CAP_CHOWN, CAP_DAC_OVERRIDE, CAP_FOWNER, CAP_SETGID, CAP_SETUID, CAP_MKNOD, CAP_SETFCAP)
+	}
 	return res
 }
 
@@ -1194,6 +1372,166 @@ func appendUniqueImagePullSecret(secrets []k8sv1.LocalObjectReference, newsecret
 	return append(secrets, newsecret)
 }
 
+// getComputeResources computes the resources of the compute container of the
+// virt-launcher pod. These are the resources of the VMI plus the memory overhead
+// and, for dedicated CPUs, the CPUs needed for the pinning.
+func getComputeResources(vmi *v1.VirtualMachineInstance, memoryOverhead *resource.Quantity) (k8sv1.ResourceRequirements, error) {
+	// Consider CPU and memory requests and limits for pod scheduling
+	resources := k8sv1.ResourceRequirements{}
+	vmiResources := vmi.Spec.Domain.Resources
+
+	resources.Requests = make(k8sv1.ResourceList)
+	resources.Limits = make(k8sv1.ResourceList)
+
+	// Copy vmi resources requests to a container
+	for key, value := range vmiResources.Requests {
+		resources.Requests[key] = value
+	}
+
+	// Copy vmi resources limits to a container
+	for key, value := range vmiResources.Limits {
+		resources.Limits[key] = value
+	}
+
+	// Consider hugepages resource for pod scheduling
+	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Hugepages != nil {
+		pageSize, err := resource.ParseQuantity(vmi.Spec.Domain.Memory.Hugepages.PageSize)
+		if err != nil {
+			return resources, fmt.Errorf("invalid hugepages size %s: %v", vmi.Spec.Domain.Memory.Hugepages.PageSize, err)
+		}
+		// the node reports the hugepages of every size in the canonical form, e.g. hugepages-2Mi for 2048Ki
+		hugepageType := k8sv1.ResourceName(k8sv1.ResourceHugePagesPrefix + pageSize.String())
+		hugepagesMemReq := vmi.Spec.Domain.Resources.Requests.Memory()
+
+		// If requested, use the guest memory to allocate hugepages
+		if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
+			requests := vmi.Spec.Domain.Resources.Requests.Memory().Value()
+			guest := vmi.Spec.Domain.Memory.Guest.Value()
+			if requests > guest {
+				hugepagesMemReq = vmi.Spec.Domain.Memory.Guest
+			}
+		}
+		resources.Requests[hugepageType] = *hugepagesMemReq
+		resources.Limits[hugepageType] = *hugepagesMemReq
+
+		reqMemDiff := resource.NewScaledQuantity(0, resource.Kilo)
+		limMemDiff := resource.NewScaledQuantity(0, resource.Kilo)
+		// In case the guest memory and the requested memeory are diffrent, add the difference
+		// to the to the overhead
+		if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
+			requests := vmi.Spec.Domain.Resources.Requests.Memory().Value()
+			limits := vmi.Spec.Domain.Resources.Limits.Memory().Value()
+			guest := vmi.Spec.Domain.Memory.Guest.Value()
+			if requests > guest {
+				reqMemDiff.Add(*vmi.Spec.Domain.Resources.Requests.Memory())
+				reqMemDiff.Sub(*vmi.Spec.Domain.Memory.Guest)
+			}
+			if limits > guest {
+				limMemDiff.Add(*vmi.Spec.Domain.Resources.Limits.Memory())
+				limMemDiff.Sub(*vmi.Spec.Domain.Memory.Guest)
+			}
+		}
+		// Set requested memory equals to overhead memory
+		reqMemDiff.Add(*memoryOverhead)
+		resources.Requests[k8sv1.ResourceMemory] = *reqMemDiff
+		if _, ok := resources.Limits[k8sv1.ResourceMemory]; ok {
+			limMemDiff.Add(*memoryOverhead)
+			resources.Limits[k8sv1.ResourceMemory] = *limMemDiff
+		}
+	} else {
+		// Add overhead memory
+		memoryRequest := resources.Requests[k8sv1.ResourceMemory]
+		if !vmi.Spec.Domain.Resources.OvercommitGuestOverhead {
+			memoryRequest.Add(*memoryOverhead)
+		}
+		resources.Requests[k8sv1.ResourceMemory] = memoryRequest
+
+		if memoryLimit, ok := resources.Limits[k8sv1.ResourceMemory]; ok {
+			memoryLimit.Add(*memoryOverhead)
+			resources.Limits[k8sv1.ResourceMemory] = memoryLimit
+		}
+	}
+
+	// Handle CPU pinning
+	if vmi.IsCPUDedicated() {
+		vcpus := hardware.GetNumberOfVCPUs(vmi.Spec.Domain.CPU)
+
+		// allocate the idle siblings of isolated vCPUs
+		if policy := vmi.Spec.Domain.CPU.ThreadPolicy; policy != nil && *policy == v1.CPUThreadPolicyIsolate {
+			vcpus *= 2
+		}
+
+		if vcpus != 0 {
+			resources.Limits[k8sv1.ResourceCPU] = *resource.NewQuantity(vcpus, resource.BinarySI)
+		} else {
+			if cpuLimit, ok := resources.Limits[k8sv1.ResourceCPU]; ok {
+				resources.Requests[k8sv1.ResourceCPU] = cpuLimit
+			} else if cpuRequest, ok := resources.Requests[k8sv1.ResourceCPU]; ok {
+				resources.Limits[k8sv1.ResourceCPU] = cpuRequest
+			}
+		}
+		// allocate 1 more pcpu if the emulator thread is isolated
+		if vmi.GetEmulatorThreadPolicy() == v1.EmulatorThreadPolicyIsolate {
+			emulatorThreadCpu := resource.NewQuantity(1, resource.BinarySI)
+			limits := resources.Limits[k8sv1.ResourceCPU]
+			limits.Add(*emulatorThreadCpu)
+			resources.Limits[k8sv1.ResourceCPU] = limits
+			if cpuRequest, ok := resources.Requests[k8sv1.ResourceCPU]; ok {
+				cpuRequest.Add(*emulatorThreadCpu)
+				resources.Requests[k8sv1.ResourceCPU] = cpuRequest
+			}
+		}
+
+		resources.Limits[k8sv1.ResourceMemory] = *resources.Requests.Memory()
+	}
+
+	return resources, nil
+}
+
+// getHookSidecarResources returns the resources of the hook sidecar containers
+func getHookSidecarResources(vmi *v1.VirtualMachineInstance) k8sv1.ResourceRequirements {
+	resources := k8sv1.ResourceRequirements{}
+	// add default cpu and memory limits to enable cpu pinning if requested
+	// TODO(vladikr): make the hookSidecar express resources
+	if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
+		resources.Limits = make(k8sv1.ResourceList)
+		resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("200m")
+		resources.Limits[k8sv1.ResourceMemory] = resource.MustParse("64M")
+	}
+	return resources
+}
+
+// getInfraResources returns the resources of the kubevirt-infra container
+func getInfraResources() k8sv1.ResourceRequirements {
+	return k8sv1.ResourceRequirements{
+		Limits: map[k8sv1.ResourceName]resource.Quantity{
+			k8sv1.ResourceCPU:    resource.MustParse("1m"),
+			k8sv1.ResourceMemory: resource.MustParse("40Mi"),
+		},
+	}
+}
+
+// getInitContainerResources returns the resources of the container-disk-binary init container
+func getInitContainerResources(vmi *v1.VirtualMachineInstance) k8sv1.ResourceRequirements {
+	resources := k8sv1.ResourceRequirements{}
+	if vmi.IsCPUDedicated() || vmi.WantsToHaveQOSGuaranteed() {
+		resources.Limits = make(k8sv1.ResourceList)
+		resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("10m")
+		resources.Limits[k8sv1.ResourceMemory] = resource.MustParse("40M")
+		resources.Requests = make(k8sv1.ResourceList)
+		resources.Requests[k8sv1.ResourceCPU] = resource.MustParse("10m")
+		resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("40M")
+	} else {
+		resources.Limits = make(k8sv1.ResourceList)
+		resources.Limits[k8sv1.ResourceCPU] = resource.MustParse("100m")
+		resources.Limits[k8sv1.ResourceMemory] = resource.MustParse("40M")
+		resources.Requests = make(k8sv1.ResourceList)
+		resources.Requests[k8sv1.ResourceCPU] = resource.MustParse("10m")
+		resources.Requests[k8sv1.ResourceMemory] = resource.MustParse("1M")
+	}
+	return resources
+}
+
 // getMemoryOverhead computes the estimation of total
 // memory needed for the domain to operate properly.
 // This includes the memory needed for the guest and memory
@@ -1239,7 +1577,7 @@ func getMemoryOverhead(vmi *v1.VirtualMachineInstance) *resource.Quantity {
 	// Additional overhead of 1G for VFIO devices. VFIO requires all guest RAM to be locked
 	// in addition to MMIO memory space to allow DMA. 1G is often the size of reserved MMIO space on x86 systems.
 	// Additial information can be found here: https://www.redhat.com/archives/libvir-list/2015-November/msg00329.html
-	if util.IsSRIOVVmi(vmi) || util.IsGPUVMI(vmi) {
+	if util.IsSRIOVVmi(vmi) || util.IsGPUVMI(vmi) || util.IsHostDevVMI(vmi) {
 		overhead.Add(resource.MustParse("1G"))
 	}
 
@@ -1286,6 +1624,74 @@ func getResourceNameForNetwork(network *networkv1.NetworkAttachmentDefinition) s
 	return "" // meaning the network is not served by resources
 }
 
+// ovnNetworkConfig holds the parts of the OVN-Kubernetes CNI configuration
+// which have to agree with the OVN network binding requested by the VMI.
+type ovnNetworkConfig struct {
+	Type     string `json:"type"`
+	Topology string `json:"topology"`
+	VLANID   *int32 `json:"vlanID,omitempty"`
+}
+
+func validateOVNNetworkAttachmentDefinition(ovn *v1.OVNNetwork, network *networkv1.NetworkAttachmentDefinition) error {
+	name := fmt.Sprintf("%s/%s", network.Namespace, network.Name)
+
+	config := ovnNetworkConfig{}
+	if err := json.Unmarshal([]byte(network.Spec.Config), &config); err != nil {
+		return fmt.Errorf("Failed to parse the config of network attachment definition %s: %v", name, err)
+	}
+	if config.Type != OVN_KUBERNETES_CNI_TYPE {
+		return fmt.Errorf("Network attachment definition %s is of type %q, expected %q", name, config.Type, OVN_KUBERNETES_CNI_TYPE)
+	}
+	if config.Topology != string(ovn.Topology) {
+		return fmt.Errorf("Network attachment definition %s has topology %q, expected %q", name, config.Topology, ovn.Topology)
+	}
+
+	vlanToString := func(vlan *int32) string {
+		if vlan == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%d", *vlan)
+	}
+	if vlanToString(config.VLANID) != vlanToString(ovn.VLAN) {
+		return fmt.Errorf("Network attachment definition %s has VLAN %s, expected %s", name, vlanToString(config.VLANID), vlanToString(ovn.VLAN))
+	}
+	return nil
+}
+
+// spoofCheckConfig holds the MAC anti-spoofing knob of the bridge CNI plugin,
+// either set on the config itself or on one of the plugins of a conflist.
+type spoofCheckConfig struct {
+	MACSpoofCheck bool `json:"macspoofchk,omitempty"`
+	Plugins       []struct {
+		MACSpoofCheck bool `json:"macspoofchk,omitempty"`
+	} `json:"plugins,omitempty"`
+}
+
+// validateAllowedMACAddresses rejects networks whose bridge CNI enforces that
+// the guest only sends from the MAC address of the interface, since this would
+// silently drop the traffic of the additionally allowed MAC addresses. The rules
+// are owned by the CNI plugin on the node. SR-IOV virtual functions keep their
+// spoof checking, the guest adds filters for the allowed MAC addresses itself.
+func validateAllowedMACAddresses(iface *v1.Interface, network *networkv1.NetworkAttachmentDefinition) error {
+	if iface == nil || iface.AllowedAddresses == nil || len(iface.AllowedAddresses.MACAddresses) == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("%s/%s", network.Namespace, network.Name)
+
+	config := spoofCheckConfig{}
+	if err := json.Unmarshal([]byte(network.Spec.Config), &config); err != nil {
+		return fmt.Errorf("Failed to parse the config of network attachment definition %s: %v", name, err)
+	}
+	spoofCheck := config.MACSpoofCheck
+	for _, plugin := range config.Plugins {
+		spoofCheck = spoofCheck || plugin.MACSpoofCheck
+	}
+	if spoofCheck {
+		return fmt.Errorf("Network attachment definition %s enables MAC spoof checking, which drops the traffic of the allowed MAC addresses of interface %s", name, iface.Name)
+	}
+	return nil
+}
+
 func getNamespaceAndNetworkName(vmi *v1.VirtualMachineInstance, fullNetworkName string) (namespace string, networkName string) {
 	if strings.Contains(fullNetworkName, "/") {
 		res := strings.SplitN(fullNetworkName, "/", 2)
@@ -1306,6 +1712,14 @@ func getNetworkToResourceMap(virtClient kubecli.KubevirtClient, vmi *v1.VirtualM
 			if err != nil {
 				return map[string]string{}, fmt.Errorf("Failed to locate network attachment definition %s/%s", namespace, networkName)
 			}
+			if network.Multus.OVN != nil {
+				if err := validateOVNNetworkAttachmentDefinition(network.Multus.OVN, crd); err != nil {
+					return map[string]string{}, err
+				}
+			}
+			if err := validateAllowedMACAddresses(getIfaceByName(vmi, network.Name), crd); err != nil {
+				return map[string]string{}, err
+			}
 			networkToResourceMap[network.Name] = getResourceNameForNetwork(crd)
 		}
 	}
@@ -1322,7 +1736,7 @@ func getIfaceByName(vmi *v1.VirtualMachineInstance, name string) *v1.Interface {
 }
 
 func getCniAnnotations(vmi *v1.VirtualMachineInstance) (cniAnnotations map[string]string, err error) {
-	ifaceListMap := make([]map[string]string, 0)
+	ifaceListMap := make([]map[string]interface{}, 0)
 	cniAnnotations = make(map[string]string, 0)
 
 	next_idx := 0
@@ -1333,7 +1747,7 @@ func getCniAnnotations(vmi *v1.VirtualMachineInstance) (cniAnnotations map[strin
 				continue
 			}
 			namespace, networkName := getNamespaceAndNetworkName(vmi, network.Multus.NetworkName)
-			ifaceMap := map[string]string{
+			ifaceMap := map[string]interface{}{
 				"name":      networkName,
 				"namespace": namespace,
 				"interface": fmt.Sprintf("net%d", next_idx+1),
@@ -1347,6 +1761,9 @@ func getCniAnnotations(vmi *v1.VirtualMachineInstance) (cniAnnotations map[strin
 				// we forbid them in API.
 				ifaceMap["mac"] = iface.MacAddress
 			}
+			if ips := getIPClaim(vmi, network.Name); len(ips) > 0 {
+				ifaceMap["ips"] = ips
+			}
 			next_idx = next_idx + 1
 			ifaceListMap = append(ifaceListMap, ifaceMap)
 		}
@@ -1361,6 +1778,40 @@ func getCniAnnotations(vmi *v1.VirtualMachineInstance) (cniAnnotations map[strin
 	return
 }
 
+// getDefaultNetworkAnnotation selects the multus network which replaces the cluster network of the pod.
+// The claimed IP addresses can only be requested with the JSON form of the annotation.
+func getDefaultNetworkAnnotation(vmi *v1.VirtualMachineInstance, network *v1.Network) (string, error) {
+	ips := getIPClaim(vmi, network.Name)
+	if len(ips) == 0 {
+		return network.Multus.NetworkName, nil
+	}
+	namespace, networkName := getNamespaceAndNetworkName(vmi, network.Multus.NetworkName)
+	annotation, err := json.Marshal([]map[string]interface{}{{
+		"name":      networkName,
+		"namespace": namespace,
+		"ips":       ips,
+	}})
+	if err != nil {
+		return "", fmt.Errorf("Failed to create JSON list from the default network %s", network.Multus.NetworkName)
+	}
+	return string(annotation), nil
+}
+
+// getIPClaim returns the IP addresses claimed by the interface of the network, if it wants
+// persistent IPs
+func getIPClaim(vmi *v1.VirtualMachineInstance, name string) []string {
+	iface := getIfaceByName(vmi, name)
+	if iface == nil || !iface.PersistentIPs {
+		return nil
+	}
+	for _, claim := range vmi.Status.IPClaims {
+		if claim.Name == name {
+			return claim.IPs
+		}
+	}
+	return nil
+}
+
 func NewTemplateService(launcherImage string,
 	virtShareDir string,
 	virtLibDir string,
@@ -1388,19 +1839,66 @@ func NewTemplateService(launcherImage string,
 	return &svc
 }
 
-func copyProbe(probe *v1.Probe) *k8sv1.Probe {
+func copyProbe(vmi *v1.VirtualMachineInstance, probe *v1.Probe) *k8sv1.Probe {
 	if probe == nil {
 		return nil
 	}
-	return &k8sv1.Probe{
+	podProbe := &k8sv1.Probe{
 		InitialDelaySeconds: probe.InitialDelaySeconds,
 		TimeoutSeconds:      probe.TimeoutSeconds,
 		PeriodSeconds:       probe.PeriodSeconds,
 		SuccessThreshold:    probe.SuccessThreshold,
 		FailureThreshold:    probe.FailureThreshold,
-		Handler: k8sv1.Handler{
-			HTTPGet:   probe.HTTPGet,
-			TCPSocket: probe.TCPSocket,
-		},
 	}
+	if probe.HTTPGet != nil {
+		podProbe.Handler.HTTPGet = probe.HTTPGet.DeepCopy()
+		podProbe.Handler.HTTPGet.Port = resolveGuestPort(vmi, probe.HTTPGet.Port)
+	}
+	if probe.TCPSocket != nil {
+		podProbe.Handler.TCPSocket = probe.TCPSocket.DeepCopy()
+		podProbe.Handler.TCPSocket.Port = resolveGuestPort(vmi, probe.TCPSocket.Port)
+	}
+	if probe.GuestAgentExec != nil {
+		podProbe.Handler.Exec = &k8sv1.ExecAction{
+			Command: guestAgentExecProbeCommand(vmi, probe),
+		}
+	}
+	return podProbe
+}
+
+// resolveGuestPort resolves a named probe port to the port declared on the VMI interfaces.
+// The pod IP is either handed over to the guest or forwarded to it, so the probe targets the
+// guest and not the launcher pod.
+func resolveGuestPort(vmi *v1.VirtualMachineInstance, port intstr.IntOrString) intstr.IntOrString {
+	if port.Type != intstr.String {
+		return port
+	}
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		for _, p := range iface.Ports {
+			if p.Name == port.StrVal {
+				return intstr.FromInt(int(p.Port))
+			}
+		}
+	}
+	return port
+}
+
+// guestAgentExecProbeCommand returns the command line of virt-probe, which runs the probe
+// command inside the guest through the guest agent and exits with the guest command's exit code
+func guestAgentExecProbeCommand(vmi *v1.VirtualMachineInstance, probe *v1.Probe) []string {
+	timeoutSeconds := probe.TimeoutSeconds
+	if timeoutSeconds < 1 {
+		timeoutSeconds = 1
+	}
+	command := []string{
+		"/usr/bin/virt-probe",
+		"--namespace", vmi.Namespace,
+		"--name", vmi.Name,
+		"--timeoutSeconds", strconv.Itoa(int(timeoutSeconds)),
+	}
+	if util.IsNonRootVMI(vmi) {
+		command = append(command, "--run-as-nonroot")
+	}
+	command = append(command, "--")
+	return append(command, probe.GuestAgentExec.Command...)
 }
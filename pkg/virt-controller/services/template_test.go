@@ -42,6 +42,7 @@ import (
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/hooks"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/util"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
@@ -256,6 +257,49 @@ var _ = Describe("Template", func() {
 				Expect(pod.Spec.Subdomain).To(BeEmpty())
 			})
 		})
+		Context("with a serial console log", func() {
+			table.DescribeTable("should pass the serial console log settings to virt-launcher", func(serialConsoleLog *v1.SerialConsoleLog, expectedArgs []string) {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: "default", UID: "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{Domain: v1.DomainSpec{
+						Devices: v1.Devices{SerialConsoleLog: serialConsoleLog},
+					}},
+				}
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				command := pod.Spec.Containers[0].Command
+				Expect(command[len(command)-len(expectedArgs):]).To(Equal(expectedArgs))
+			},
+				table.Entry("to the pod logs with the default rotation", &v1.SerialConsoleLog{}, []string{
+					"--serial-console-log-max-size", "2097152",
+					"--serial-console-log-max-files", "3",
+					"--serial-console-log-stdout",
+				}),
+				table.Entry("to a volume with a custom rotation", &v1.SerialConsoleLog{
+					VolumeName: "logs",
+					MaxSize:    resource.NewQuantity(10*1024*1024, resource.BinarySI),
+					MaxFiles:   &[]uint32{5}[0],
+				}, []string{
+					"--serial-console-log-max-size", "10485760",
+					"--serial-console-log-max-files", "5",
+				}),
+			)
+
+			It("should not pass serial console log settings by default", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: "default", UID: "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{Domain: v1.DomainSpec{}},
+				}
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Containers[0].Command).ToNot(ContainElement("--serial-console-log-max-size"))
+				Expect(pod.Spec.Containers[0].Command).ToNot(ContainElement("--serial-console-log-stdout"))
+			})
+		})
 		Context("with SELinux types", func() {
 			It("should run under the SELinux type container_t if none specified", func() {
 				vmi := v1.VirtualMachineInstance{
@@ -332,6 +376,53 @@ var _ = Describe("Template", func() {
 					}
 				}
 			})
+			table.DescribeTable("should run under the SELinux type of the workload class", func(annotations map[string]string, hostDevices []v1.HostDevice, expectedType string) {
+				testutils.UpdateFakeClusterConfig(configMapInformer, &kubev1.ConfigMap{
+					Data: map[string]string{
+						virtconfig.SELinuxLauncherTypeKey:  "spc_t",
+						virtconfig.SELinuxLauncherTypesKey: `{"vfio": "virt_vfio_t", "custom": "virt_custom_t"}`,
+					},
+				})
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: "default", UID: "1234",
+						Annotations: annotations,
+					},
+					Spec: v1.VirtualMachineInstanceSpec{Domain: v1.DomainSpec{
+						Devices: v1.Devices{HostDevices: hostDevices},
+					}},
+				}
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.SecurityContext.SELinuxOptions).ToNot(BeNil())
+				Expect(pod.Spec.SecurityContext.SELinuxOptions.Type).To(Equal(expectedType))
+			},
+				table.Entry("falling back to the cluster wide type", nil, nil, "spc_t"),
+				table.Entry("with host devices", nil, []v1.HostDevice{{Name: "hostdev", DeviceName: "vendor.com/dev"}}, "virt_vfio_t"),
+				table.Entry("with the class annotation", map[string]string{v1.SELinuxWorkloadClassAnnotation: "custom"}, nil, "virt_custom_t"),
+			)
+		})
+		Context("with a seccomp configuration", func() {
+			It("should leave the seccomp profile to the container runtime if none is configured", func() {
+				vmi := v1.NewMinimalVMI("testvmi")
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Annotations).ToNot(HaveKey(kubev1.SeccompPodAnnotationKey))
+			})
+			table.DescribeTable("should run with the configured seccomp profile", func(seccompConfiguration string, expectedProfile string) {
+				testutils.UpdateFakeClusterConfig(configMapInformer, &kubev1.ConfigMap{
+					Data: map[string]string{virtconfig.SeccompConfigurationKey: seccompConfiguration},
+				})
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Annotations = map[string]string{kubev1.SeccompPodAnnotationKey: "unconfined"}
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Annotations).To(HaveKeyWithValue(kubev1.SeccompPodAnnotationKey, expectedProfile))
+			},
+				table.Entry("with the RuntimeDefault type", "type: RuntimeDefault", "runtime/default"),
+				table.Entry("with the KubeVirt type", "type: KubeVirt", "localhost/kubevirt/default.json"),
+				table.Entry("with a custom profile", "type: Custom\ncustomProfile: profiles/qemu.json", "localhost/profiles/qemu.json"),
+			)
 		})
 		Context("with debug log annotation", func() {
 			It("should add the corresponding environment variable", func() {
@@ -406,6 +497,187 @@ var _ = Describe("Template", func() {
 				Expect(cloudInitVolumeMountFound).To(BeTrue(), "could not find cloud init user secret volume mount")
 			})
 		})
+		Context("with ignition volume", func() {
+			table.DescribeTable("should mount the referenced ignition config", func(source *v1.IgnitionSource, mountPath string) {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Volumes: []v1.Volume{
+							{
+								Name:         "ignition",
+								VolumeSource: v1.VolumeSource{Ignition: source},
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				var podVolume *kubev1.Volume
+				for i := range pod.Spec.Volumes {
+					if pod.Spec.Volumes[i].Name == "ignition" {
+						podVolume = &pod.Spec.Volumes[i]
+					}
+				}
+				Expect(podVolume).ToNot(BeNil(), "could not find the ignition volume")
+				if source.SecretRef != nil {
+					Expect(podVolume.Secret.SecretName).To(Equal(source.SecretRef.Name))
+				} else {
+					Expect(podVolume.ConfigMap.Name).To(Equal(source.ConfigMapRef.Name))
+				}
+
+				Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(kubev1.VolumeMount{
+					Name:      "ignition",
+					MountPath: mountPath,
+					SubPath:   "config.ign",
+					ReadOnly:  true,
+				}))
+			},
+				table.Entry("from a secret",
+					&v1.IgnitionSource{SecretRef: &kubev1.LocalObjectReference{Name: "ignition-secret"}},
+					"/var/run/kubevirt-private/secret/ignition/config.ign",
+				),
+				table.Entry("from a config map",
+					&v1.IgnitionSource{ConfigMapRef: &kubev1.LocalObjectReference{Name: "ignition-config"}},
+					"/var/run/kubevirt-private/config-map/ignition/config.ign",
+				),
+			)
+		})
+		Context("with sysprep volume", func() {
+			table.DescribeTable("should mount the referenced answer file", func(source *v1.SysprepSource) {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Volumes: []v1.Volume{
+							{
+								Name:         "sysprep",
+								VolumeSource: v1.VolumeSource{Sysprep: source},
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				var podVolume *kubev1.Volume
+				for i := range pod.Spec.Volumes {
+					if pod.Spec.Volumes[i].Name == "sysprep" {
+						podVolume = &pod.Spec.Volumes[i]
+					}
+				}
+				Expect(podVolume).ToNot(BeNil(), "could not find the sysprep volume")
+				if source.ConfigMap != nil {
+					Expect(podVolume.ConfigMap.Name).To(Equal(source.ConfigMap.Name))
+				} else {
+					Expect(podVolume.Secret.SecretName).To(Equal(source.Secret.Name))
+				}
+
+				Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(kubev1.VolumeMount{
+					Name:      "sysprep",
+					MountPath: "/var/run/kubevirt-private/sysprep/sysprep",
+					ReadOnly:  true,
+				}))
+			},
+				table.Entry("from a config map", &v1.SysprepSource{ConfigMap: &kubev1.LocalObjectReference{Name: "sysprep-config"}}),
+				table.Entry("from a secret", &v1.SysprepSource{Secret: &kubev1.LocalObjectReference{Name: "sysprep-secret"}}),
+			)
+		})
+		Context("with TPM", func() {
+			It("should mount the persistent state claim", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							Devices: v1.Devices{
+								TPM: &v1.TPMDevice{PersistentStateClaimName: "tpm-state"},
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(pod.Spec.Volumes).To(ContainElement(kubev1.Volume{
+					Name: "virt-tpm-state",
+					VolumeSource: kubev1.VolumeSource{
+						PersistentVolumeClaim: &kubev1.PersistentVolumeClaimVolumeSource{ClaimName: "tpm-state"},
+					},
+				}))
+				Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(kubev1.VolumeMount{
+					Name:      "virt-tpm-state",
+					MountPath: "/var/run/kubevirt-private/tpm-state",
+				}))
+			})
+
+			It("should not add a state volume without a claim", func() {
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Spec.Domain.Devices.TPM = &v1.TPMDevice{}
+
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				for _, volume := range pod.Spec.Volumes {
+					Expect(volume.Name).ToNot(Equal("virt-tpm-state"))
+				}
+			})
+		})
+		Context("with virtio-fs", func() {
+			It("should grant the capabilities of virtiofsd to the compute container", func() {
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Spec.Domain.Devices.Filesystems = []v1.Filesystem{{Name: "shared", Virtiofs: &v1.FilesystemVirtiofs{}}}
+
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Containers[0].SecurityContext.Capabilities.Add).To(ContainElement(kubev1.Capability(CAP_SYS_ADMIN)))
+			})
+
+			It("should not grant SYS_ADMIN without filesystems", func() {
+				pod, err := svc.RenderLaunchManifest(v1.NewMinimalVMI("testvmi"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Containers[0].SecurityContext.Capabilities.Add).ToNot(ContainElement(kubev1.Capability(CAP_SYS_ADMIN)))
+			})
+		})
+		Context("with a non-root VMI", func() {
+			It("should run the virt-launcher pod unprivileged", func() {
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Status.RuntimeUser = util.NonRootUID
+
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*pod.Spec.SecurityContext.RunAsUser).To(Equal(int64(util.NonRootUID)))
+				compute := pod.Spec.Containers[0]
+				Expect(*compute.SecurityContext.RunAsUser).To(Equal(int64(util.NonRootUID)))
+				Expect(*compute.SecurityContext.RunAsNonRoot).To(BeTrue())
+				Expect(*compute.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+				Expect(compute.SecurityContext.Capabilities.Add).To(BeEmpty())
+				Expect(compute.SecurityContext.Capabilities.Drop).To(ConsistOf(kubev1.Capability("ALL")))
+				Expect(compute.Command).To(ContainElement("--run-as-nonroot"))
+				Expect(compute.VolumeMounts).To(ContainElement(kubev1.VolumeMount{Name: "private", MountPath: util.VirtPrivateDir}))
+			})
+
+			It("should run the virt-launcher pod as root by default", func() {
+				pod, err := svc.RenderLaunchManifest(v1.NewMinimalVMI("testvmi"))
+				Expect(err).ToNot(HaveOccurred())
+				compute := pod.Spec.Containers[0]
+				Expect(*compute.SecurityContext.RunAsUser).To(BeZero())
+				Expect(compute.SecurityContext.RunAsNonRoot).To(BeNil())
+				Expect(compute.Command).ToNot(ContainElement("--run-as-nonroot"))
+			})
+		})
 		Context("with cloud-init network data secret", func() {
 			It("should add volume with secret referenced by cloud-init network data secret ref", func() {
 				vmi := v1.VirtualMachineInstance{
@@ -554,6 +826,68 @@ var _ = Describe("Template", func() {
 				Expect(ok).To(BeTrue())
 				Expect(value).To(Equal("[{\"interface\":\"net1\",\"name\":\"test1\",\"namespace\":\"default\"}]"))
 			})
+			It("should request the claimed IP addresses of interfaces with persistent IPs", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							Devices: v1.Devices{
+								Interfaces: []v1.Interface{
+									{
+										Name:                   "default",
+										InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}},
+										PersistentIPs:          true,
+									},
+									{
+										Name:                   "test1",
+										InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}},
+										PersistentIPs:          true,
+									},
+									{
+										Name:                   "test2",
+										InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}},
+									},
+								},
+							},
+						},
+						Networks: []v1.Network{
+							{Name: "default",
+								NetworkSource: v1.NetworkSource{
+									Multus: &v1.MultusNetwork{NetworkName: "default", Default: true},
+								}},
+							{Name: "test1",
+								NetworkSource: v1.NetworkSource{
+									Multus: &v1.MultusNetwork{NetworkName: "test1"},
+								}},
+							{Name: "test2",
+								NetworkSource: v1.NetworkSource{
+									Multus: &v1.MultusNetwork{NetworkName: "other-namespace/test1"},
+								}},
+						},
+					},
+					Status: v1.VirtualMachineInstanceStatus{
+						IPClaims: []v1.InterfaceIPClaim{
+							{Name: "default", IPs: []string{"10.0.0.5/24"}},
+							{Name: "test1", IPs: []string{"10.1.0.5/24"}},
+							{Name: "test2", IPs: []string{"10.2.0.5/24"}},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Annotations["v1.multus-cni.io/default-network"]).To(Equal(
+					"[{\"ips\":[\"10.0.0.5/24\"],\"name\":\"default\",\"namespace\":\"default\"}]"))
+				expectedIfaces := ("[" +
+					"{\"interface\":\"net1\",\"ips\":[\"10.1.0.5/24\"],\"name\":\"test1\",\"namespace\":\"default\"}," +
+					"{\"interface\":\"net2\",\"name\":\"test1\",\"namespace\":\"other-namespace\"}" +
+					"]")
+				Expect(pod.Annotations["k8s.v1.cni.cncf.io/networks"]).To(Equal(expectedIfaces))
+			})
 			It("should add MAC address in the pod annotation", func() {
 				vmi := v1.VirtualMachineInstance{
 					ObjectMeta: metav1.ObjectMeta{
@@ -914,7 +1248,64 @@ var _ = Describe("Template", func() {
 				}
 				Expect(found).To(BeTrue(), "Expected compute container to be granted SYS_NICE capability")
 				Expect(pod.Spec.NodeSelector).Should(HaveKeyWithValue(v1.CPUManager, "true"))
+				Expect(pod.Spec.ReadinessGates).To(ConsistOf(kubev1.PodReadinessGate{
+					ConditionType: kubev1.PodConditionType(v1.CgroupReadinessGate),
+				}))
+			})
+			It("should not add the cgroup readiness gate without dedicated CPUs", func() {
+				pod, err := svc.RenderLaunchManifest(v1.NewMinimalVMI("testvmi"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.ReadinessGates).To(BeEmpty())
+			})
+			It("should schedule realtime vCPUs on nodes with a realtime kernel", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							CPU: &v1.CPU{
+								Cores:                 2,
+								DedicatedCPUPlacement: true,
+								Realtime:              &v1.Realtime{Mask: "1"},
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.NodeSelector).Should(HaveKeyWithValue(v1.RealtimeLabel, "true"))
 			})
+			table.DescribeTable("should match the hyperthreads of the node", func(policy v1.CPUThreadPolicy, threads uint32, threadsPerCore string, cpus int64) {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							CPU: &v1.CPU{
+								Cores:                 2,
+								Threads:               threads,
+								DedicatedCPUPlacement: true,
+								ThreadPolicy:          &policy,
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.NodeSelector).Should(HaveKeyWithValue(v1.CPUThreadsPerCoreLabel, threadsPerCore))
+				Expect(pod.Spec.Containers[0].Resources.Limits.Cpu().Value()).To(Equal(cpus))
+			},
+				table.Entry("with full cores", v1.CPUThreadPolicyFullCores, uint32(2), "2", int64(4)),
+				table.Entry("with isolated threads", v1.CPUThreadPolicyIsolate, uint32(1), "2", int64(4)),
+			)
 			It("should allocate 1 more cpu when isolateEmulatorThread requested", func() {
 				vmi := v1.VirtualMachineInstance{
 					ObjectMeta: metav1.ObjectMeta{
@@ -938,6 +1329,54 @@ var _ = Describe("Template", func() {
 				cpu := resource.MustParse("3")
 				Expect(pod.Spec.Containers[0].Resources.Limits.Cpu().Cmp(cpu)).To(BeZero())
 			})
+			It("should allocate 1 more cpu when the isolate emulator thread policy is requested", func() {
+				policy := v1.EmulatorThreadPolicyIsolate
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							CPU: &v1.CPU{
+								Cores:                 2,
+								DedicatedCPUPlacement: true,
+								EmulatorThreadPolicy:  &policy,
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				cpu := resource.MustParse("3")
+				Expect(pod.Spec.Containers[0].Resources.Limits.Cpu().Cmp(cpu)).To(BeZero())
+			})
+			It("should not allocate more cpus when the emulator thread shares the pCPU of vCPU 0", func() {
+				policy := v1.EmulatorThreadPolicyVCPU0
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							CPU: &v1.CPU{
+								Cores:                 2,
+								DedicatedCPUPlacement: true,
+								EmulatorThreadPolicy:  &policy,
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				cpu := resource.MustParse("2")
+				Expect(pod.Spec.Containers[0].Resources.Limits.Cpu().Cmp(cpu)).To(BeZero())
+			})
 			It("should add node affinity to pod", func() {
 				nodeAffinity := kubev1.NodeAffinity{}
 				vmi := v1.VirtualMachineInstance{
@@ -1003,6 +1442,27 @@ var _ = Describe("Template", func() {
 				Expect(pod.Spec.Tolerations).To(BeEquivalentTo([]kubev1.Toleration{{Key: podToleration.Key, TolerationSeconds: &tolerationSeconds}}))
 			})
 
+			It("should add topology spread constraints to pod", func() {
+				constraints := []kubev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       kubev1.LabelZoneFailureDomain,
+						WhenUnsatisfiable: kubev1.ScheduleAnyway,
+						LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+					},
+				}
+				vm := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: "default", UID: "1234"},
+					Spec: v1.VirtualMachineInstanceSpec{
+						TopologySpreadConstraints: constraints,
+						Domain:                    v1.DomainSpec{},
+					},
+				}
+				pod, err := svc.RenderLaunchManifest(&vm)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.TopologySpreadConstraints).To(Equal(constraints))
+			})
+
 			It("should add the scheduler name to the pod", func() {
 				vm := v1.VirtualMachineInstance{
 					ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: "default", UID: "1234"},
@@ -1235,7 +1695,7 @@ var _ = Describe("Template", func() {
 		})
 
 		Context("with hugepages constraints", func() {
-			table.DescribeTable("should add to the template constraints ", func(value string) {
+			table.DescribeTable("should add to the template constraints ", func(value string, resourceName string) {
 				vmi := v1.VirtualMachineInstance{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "testvmi",
@@ -1267,7 +1727,7 @@ var _ = Describe("Template", func() {
 				Expect(pod.Spec.Containers[0].Resources.Requests.Memory().ToDec().ScaledValue(resource.Mega)).To(Equal(int64(162)))
 				Expect(pod.Spec.Containers[0].Resources.Limits.Memory().ToDec().ScaledValue(resource.Mega)).To(Equal(int64(162)))
 
-				hugepageType := kubev1.ResourceName(kubev1.ResourceHugePagesPrefix + value)
+				hugepageType := kubev1.ResourceName(resourceName)
 				hugepagesRequest := pod.Spec.Containers[0].Resources.Requests[hugepageType]
 				hugepagesLimit := pod.Spec.Containers[0].Resources.Limits[hugepageType]
 				Expect(hugepagesRequest.ToDec().ScaledValue(resource.Mega)).To(Equal(int64(64)))
@@ -1280,8 +1740,9 @@ var _ = Describe("Template", func() {
 				Expect(len(pod.Spec.Containers[0].VolumeMounts)).To(Equal(6))
 				Expect(pod.Spec.Containers[0].VolumeMounts[4].MountPath).To(Equal("/dev/hugepages"))
 			},
-				table.Entry("hugepages-2Mi", "2Mi"),
-				table.Entry("hugepages-1Gi", "1Gi"),
+				table.Entry("hugepages-2Mi", "2Mi", "hugepages-2Mi"),
+				table.Entry("hugepages-1Gi", "1Gi", "hugepages-1Gi"),
+				table.Entry("hugepages-2Mi in a non canonical form", "2048Ki", "hugepages-2Mi"),
 			)
 			It("should account for difference between guest and container requested memory ", func() {
 				guestMem := resource.MustParse("64M")
@@ -1450,6 +1911,91 @@ var _ = Describe("Template", func() {
 			})
 		})
 
+		Context("with golden image source", func() {
+			namespace := "testns"
+			volumeName := "golden-volume"
+
+			goldenImageVMI := func(pvcName string) *v1.VirtualMachineInstance {
+				return &v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: namespace, UID: "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Volumes: []v1.Volume{
+							{
+								Name: volumeName,
+								VolumeSource: v1.VolumeSource{
+									GoldenImage: &v1.GoldenImageVolumeSource{ClaimName: pvcName},
+								},
+							},
+						},
+						Domain: v1.DomainSpec{},
+					},
+				}
+			}
+
+			It("should attach a shared filesystem pvc read-only", func() {
+				pvc := kubev1.PersistentVolumeClaim{
+					TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "goldenFile"},
+					Spec: kubev1.PersistentVolumeClaimSpec{
+						AccessModes: []kubev1.PersistentVolumeAccessMode{kubev1.ReadOnlyMany},
+					},
+				}
+				Expect(pvcCache.Add(&pvc)).To(Succeed())
+
+				pod, err := svc.RenderLaunchManifest(goldenImageVMI("goldenFile"))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(pod.Spec.Containers[0].VolumeDevices).To(BeEmpty())
+				Expect(pod.Spec.Containers[0].VolumeMounts[4].Name).To(Equal(volumeName))
+				Expect(pod.Spec.Containers[0].VolumeMounts[4].ReadOnly).To(BeTrue())
+				Expect(pod.Spec.Volumes[1].PersistentVolumeClaim.ClaimName).To(Equal("goldenFile"))
+				Expect(pod.Spec.Volumes[1].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+			})
+
+			It("should attach a shared blockdevice pvc read-only", func() {
+				mode := kubev1.PersistentVolumeBlock
+				pvc := kubev1.PersistentVolumeClaim{
+					TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "goldenDevice"},
+					Spec: kubev1.PersistentVolumeClaimSpec{
+						VolumeMode:  &mode,
+						AccessModes: []kubev1.PersistentVolumeAccessMode{kubev1.ReadWriteMany},
+					},
+				}
+				Expect(pvcCache.Add(&pvc)).To(Succeed())
+
+				pod, err := svc.RenderLaunchManifest(goldenImageVMI("goldenDevice"))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(len(pod.Spec.Containers[0].VolumeDevices)).To(Equal(1))
+				Expect(pod.Spec.Containers[0].VolumeDevices[0].Name).To(Equal(volumeName))
+				Expect(pod.Spec.Volumes[1].PersistentVolumeClaim.ClaimName).To(Equal("goldenDevice"))
+				Expect(pod.Spec.Volumes[1].PersistentVolumeClaim.ReadOnly).To(BeTrue())
+			})
+
+			It("should reject a pvc which can only be attached to one node", func() {
+				pvc := kubev1.PersistentVolumeClaim{
+					TypeMeta:   metav1.TypeMeta{Kind: "PersistentVolumeClaim", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "goldenRWO"},
+					Spec: kubev1.PersistentVolumeClaimSpec{
+						AccessModes: []kubev1.PersistentVolumeAccessMode{kubev1.ReadWriteOnce},
+					},
+				}
+				Expect(pvcCache.Add(&pvc)).To(Succeed())
+
+				_, err := svc.RenderLaunchManifest(goldenImageVMI("goldenRWO"))
+				Expect(err).To(BeAssignableToTypeOf(PvcNotMultiAttachableError{}))
+			})
+
+			It("should result in an error for a non existing pvc", func() {
+				_, err := svc.RenderLaunchManifest(goldenImageVMI("goldenNotExisting"))
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(PvcNotFoundError(errors.New(""))))
+			})
+		})
+
 		Context("with launcher's pull secret", func() {
 			It("should contain launcher's secret in pod spec", func() {
 				vmi := v1.VirtualMachineInstance{
@@ -1529,6 +2075,30 @@ var _ = Describe("Template", func() {
 			})
 		})
 
+		Context("with an EFI firmware container disk", func() {
+			It("should add the firmware container and its pull secret", func() {
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Spec.Domain.Firmware = &v1.Firmware{
+					Bootloader: &v1.Bootloader{
+						EFI: &v1.EFI{
+							ContainerDisk: &v1.ContainerDiskSource{
+								Image:           "ovmf:custom",
+								ImagePullSecret: "firmware-secret",
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Containers).To(HaveLen(2))
+				Expect(pod.Spec.Containers[1].Name).To(Equal("firmware"))
+				Expect(pod.Spec.Containers[1].Image).To(Equal("ovmf:custom"))
+				Expect(pod.Spec.Containers[1].Args).To(Equal([]string{"--copy-path", "/var/run/kubevirt/container-disks/firmware"}))
+				Expect(pod.Spec.ImagePullSecrets).To(ContainElement(kubev1.LocalObjectReference{Name: "firmware-secret"}))
+			})
+		})
+
 		Context("with sriov interface", func() {
 			It("should not run privileged", func() {
 				// For Power we are currently running in privileged mode or libvirt will fail to lock memory
@@ -1803,6 +2373,52 @@ var _ = Describe("Template", func() {
 				Expect(caps.Add).To(Not(ContainElement(kubev1.Capability(CAP_NET_ADMIN))), "Expected compute container not to be granted NET_ADMIN capability")
 				Expect(caps.Add).To(Not(ContainElement(kubev1.Capability(CAP_NET_RAW))), "Expected compute container not to be granted NET_RAW capability")
 			})
+			It("Should not grant NET_ADMIN if all interfaces use passt", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: "default", UID: "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							Devices: v1.Devices{
+								Interfaces: []v1.Interface{{
+									Name:                   "default",
+									InterfaceBindingMethod: v1.InterfaceBindingMethod{Passt: &v1.InterfacePasst{}},
+								}},
+							},
+						},
+						Networks: []v1.Network{*v1.DefaultPodNetwork()},
+					},
+				}
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				caps := pod.Spec.Containers[0].SecurityContext.Capabilities
+
+				Expect(caps.Add).To(Not(ContainElement(kubev1.Capability(CAP_NET_ADMIN))), "Expected compute container not to be granted NET_ADMIN capability")
+				Expect(caps.Add).To(Not(ContainElement(kubev1.Capability(CAP_NET_RAW))), "Expected compute container not to be granted NET_RAW capability")
+			})
+		})
+
+		Context("with SEV", func() {
+			It("Should require the sev device", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "testvmi", Namespace: "default", UID: "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							LaunchSecurity: &v1.LaunchSecurity{SEV: &v1.SEV{}},
+						},
+					},
+				}
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+
+				sev, ok := pod.Spec.Containers[0].Resources.Limits[SEVDevice]
+				Expect(ok).To(BeTrue())
+				Expect(int(sev.Value())).To(Equal(1))
+			})
 		})
 
 		Context("with a configMap volume source", func() {
@@ -1938,6 +2554,43 @@ var _ = Describe("Template", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(pod.Spec.Containers[0].ReadinessProbe).To(Not(BeNil()))
 			})
+
+			It("should resolve named probe ports to the ports declared on the VMI interfaces", func() {
+				vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+					Name:  "default",
+					Ports: []v1.Port{{Name: "http", Port: 8080}},
+					InterfaceBindingMethod: v1.InterfaceBindingMethod{
+						Masquerade: &v1.InterfaceMasquerade{},
+					},
+				}}
+				vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+				vmi.Spec.ReadinessProbe.HTTPGet.Port = intstr.FromString("http")
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Containers[0].ReadinessProbe.Handler.HTTPGet.Port).To(Equal(intstr.FromInt(8080)))
+				Expect(vmi.Spec.ReadinessProbe.HTTPGet.Port).To(Equal(intstr.FromString("http")))
+			})
+
+			It("should run guest agent exec probes through virt-probe", func() {
+				vmi.Spec.ReadinessProbe.Handler = v1.Handler{
+					GuestAgentExec: &v1.GuestAgentExecAction{
+						Command: []string{"cat", "/tmp/ready"},
+					},
+				}
+				pod, err := svc.RenderLaunchManifest(vmi)
+				Expect(err).ToNot(HaveOccurred())
+				readinessProbe := pod.Spec.Containers[0].ReadinessProbe
+				Expect(readinessProbe.Handler.TCPSocket).To(BeNil())
+				Expect(readinessProbe.Handler.HTTPGet).To(BeNil())
+				Expect(readinessProbe.Handler.Exec.Command).To(Equal([]string{
+					"/usr/bin/virt-probe",
+					"--namespace", "default",
+					"--name", "testvmi",
+					"--timeoutSeconds", "3",
+					"--",
+					"cat", "/tmp/ready",
+				}))
+			})
 		})
 
 		Context("with GPU device interface", func() {
@@ -2006,6 +2659,46 @@ var _ = Describe("Template", func() {
 			})
 		})
 
+		Context("with host devices", func() {
+			It("should mount pci related host directories and request the device resources", func() {
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "default",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						Domain: v1.DomainSpec{
+							Devices: v1.Devices{
+								HostDevices: []v1.HostDevice{
+									{
+										Name:       "hostdev1",
+										DeviceName: "vendor.com/dev_name",
+									},
+									{
+										Name:       "hostdev2",
+										DeviceName: "vendor.com/dev_name",
+									},
+								},
+							},
+						},
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(pod.Spec.Containers)).To(Equal(1))
+				// Skip first four mounts that are generic for all launcher pods
+				Expect(pod.Spec.Containers[0].VolumeMounts[4].MountPath).To(Equal("/sys/devices/"))
+				Expect(pod.Spec.Volumes[1].HostPath.Path).To(Equal("/sys/devices/"))
+
+				resources := pod.Spec.Containers[0].Resources
+				val, ok := resources.Requests["vendor.com/dev_name"]
+				Expect(ok).To(Equal(true))
+				Expect(val).To(Equal(*resource.NewQuantity(2, resource.DecimalSI)))
+			})
+		})
+
 		It("should add the lessPVCSpaceToleration argument to the template", func() {
 			expectedToleration := "42"
 			testutils.UpdateFakeClusterConfig(configMapInformer, &kubev1.ConfigMap{
@@ -2041,8 +2734,28 @@ var _ = Describe("Template", func() {
 				pod, err := svc.RenderLaunchManifest(&vmi)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(pod.Spec.PriorityClassName).To(Equal("test"))
+				Expect(pod.Spec.PreemptionPolicy).To(BeNil())
 			})
 
+			It("should leave preemption to virt-controller with the VMPreemption feature gate", func() {
+				testutils.UpdateFakeClusterConfig(configMapInformer, &kubev1.ConfigMap{
+					Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.VMPreemptionGate},
+				})
+				vmi := v1.VirtualMachineInstance{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testvmi",
+						Namespace: "namespace",
+						UID:       "1234",
+					},
+					Spec: v1.VirtualMachineInstanceSpec{
+						PriorityClassName: "test",
+					},
+				}
+
+				pod, err := svc.RenderLaunchManifest(&vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*pod.Spec.PreemptionPolicy).To(Equal(kubev1.PreemptNever))
+			})
 		})
 
 	})
@@ -2109,6 +2822,78 @@ var _ = Describe("getResourceNameForNetwork", func() {
 	})
 })
 
+var _ = Describe("validateOVNNetworkAttachmentDefinition", func() {
+	newNetwork := func(config string) *networkv1.NetworkAttachmentDefinition {
+		return &networkv1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "localnet", Namespace: "default"},
+			Spec:       networkv1.NetworkAttachmentDefinitionSpec{Config: config},
+		}
+	}
+	vlan := func(id int32) *int32 {
+		return &id
+	}
+
+	It("should accept a matching network", func() {
+		network := newNetwork(`{"cniVersion":"0.3.1","name":"localnet","type":"ovn-k8s-cni-overlay","topology":"localnet","vlanID":100}`)
+		Expect(validateOVNNetworkAttachmentDefinition(&v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet, VLAN: vlan(100)}, network)).To(Succeed())
+	})
+
+	table.DescribeTable("should reject", func(config string, ovn *v1.OVNNetwork, message string) {
+		err := validateOVNNetworkAttachmentDefinition(ovn, newNetwork(config))
+		Expect(err).To(MatchError(ContainSubstring(message)))
+	},
+		table.Entry("a network of another type",
+			`{"type":"bridge"}`, &v1.OVNNetwork{Topology: v1.OVNTopologyLayer2},
+			`is of type "bridge", expected "ovn-k8s-cni-overlay"`),
+		table.Entry("a network with another topology",
+			`{"type":"ovn-k8s-cni-overlay","topology":"layer2"}`, &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet},
+			`has topology "layer2", expected "localnet"`),
+		table.Entry("a network with another VLAN",
+			`{"type":"ovn-k8s-cni-overlay","topology":"localnet","vlanID":200}`, &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet, VLAN: vlan(100)},
+			"has VLAN 200, expected 100"),
+		table.Entry("a tagged network when no VLAN is requested",
+			`{"type":"ovn-k8s-cni-overlay","topology":"localnet","vlanID":200}`, &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet},
+			"has VLAN 200, expected none"),
+		table.Entry("an invalid config",
+			`{`, &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet},
+			"Failed to parse the config of network attachment definition default/localnet"),
+	)
+})
+
+var _ = Describe("validateAllowedMACAddresses", func() {
+	newNetwork := func(config string) *networkv1.NetworkAttachmentDefinition {
+		return &networkv1.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "vrrp", Namespace: "default"},
+			Spec:       networkv1.NetworkAttachmentDefinitionSpec{Config: config},
+		}
+	}
+	ifaceWithAllowedMACs := &v1.Interface{
+		Name:             "red",
+		AllowedAddresses: &v1.AllowedAddresses{MACAddresses: []string{"00:00:5e:00:01:0a"}},
+	}
+
+	table.DescribeTable("should accept", func(iface *v1.Interface, config string) {
+		Expect(validateAllowedMACAddresses(iface, newNetwork(config))).To(Succeed())
+	},
+		table.Entry("an interface without allowed MAC addresses",
+			&v1.Interface{Name: "red"}, `{"type":"bridge","macspoofchk":true}`),
+		table.Entry("a bridge network without MAC spoof checking",
+			ifaceWithAllowedMACs, `{"type":"bridge","bridge":"br1"}`),
+		table.Entry("an SR-IOV network with spoof checking turned off",
+			ifaceWithAllowedMACs, `{"type":"sriov","spoofchk":"off"}`),
+		table.Entry("an SR-IOV network with spoof checking, which virt-handler turns off",
+			ifaceWithAllowedMACs, `{"type":"sriov","spoofchk":"on"}`),
+	)
+
+	table.DescribeTable("should reject", func(config string) {
+		err := validateAllowedMACAddresses(ifaceWithAllowedMACs, newNetwork(config))
+		Expect(err).To(MatchError(ContainSubstring("Network attachment definition default/vrrp enables MAC spoof checking")))
+	},
+		table.Entry("a bridge network with MAC spoof checking", `{"type":"bridge","macspoofchk":true}`),
+		table.Entry("a conflist with MAC spoof checking", `{"cniVersion":"0.3.1","plugins":[{"type":"bridge","macspoofchk":true},{"type":"tuning"}]}`),
+	)
+})
+
 var _ = Describe("getNamespaceAndNetworkName", func() {
 	It("should return vmi namespace when namespace is implicit", func() {
 		vmi := &v1.VirtualMachineInstance{ObjectMeta: metav1.ObjectMeta{Name: "testvmi", Namespace: "testns"}}
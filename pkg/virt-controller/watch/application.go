@@ -124,14 +124,32 @@ type VirtControllerApp struct {
 	rsController *VMIReplicaSet
 	rsInformer   cache.SharedIndexInformer
 
-	vmController *VMController
-	vmInformer   cache.SharedIndexInformer
+	vmController             *VMController
+	vmInformer               cache.SharedIndexInformer
+	templateRevisionInformer cache.SharedIndexInformer
 
 	dataVolumeInformer cache.SharedIndexInformer
 
 	migrationController *MigrationController
 	migrationInformer   cache.SharedIndexInformer
 
+	conformanceController  *ConformanceController
+	conformanceRunInformer cache.SharedIndexInformer
+
+	exportController *ExportController
+	vmExportInformer cache.SharedIndexInformer
+
+	memoryDumpController *MemoryDumpController
+
+	migrationRetryController *MigrationRetryController
+
+	preemptionController *PreemptionController
+
+	networkPolicyController *NetworkPolicyController
+	networkPolicyInformer   cache.SharedIndexInformer
+
+	workloadUpdateController *WorkloadUpdateController
+
 	snapshotController        *SnapshotController
 	vmSnapshotInformer        cache.SharedIndexInformer
 	vmSnapshotContentInformer cache.SharedIndexInformer
@@ -170,6 +188,13 @@ type VirtControllerApp struct {
 	launcherSubGid                    int64
 	snapshotControllerThreads         int
 	snapshotControllerResyncPeriod    time.Duration
+	conformanceControllerThreads      int
+	exportControllerThreads           int
+	memoryDumpControllerThreads       int
+	migrationRetryControllerThreads   int
+	preemptionControllerThreads       int
+	networkPolicyControllerThreads    int
+	workloadUpdateControllerThreads   int
 }
 
 var _ service.Service = &VirtControllerApp{}
@@ -180,6 +205,14 @@ func init() {
 
 	prometheus.MustRegister(leaderGauge)
 	prometheus.MustRegister(readyGauge)
+	prometheus.MustRegister(migrationRetriesCounter)
+	prometheus.MustRegister(migrationQueueDepth)
+	prometheus.MustRegister(migrationQueueWaitSeconds)
+	prometheus.MustRegister(outdatedVMIsGauge)
+	prometheus.MustRegister(workloadUpdatesCounter)
+	prometheus.MustRegister(vmiPhaseTransitionFromCreationSeconds)
+	prometheus.MustRegister(vmiPhaseTransitionSeconds)
+	prometheus.MustRegister(vmiMigrationDurationSeconds)
 }
 
 func Execute() {
@@ -237,6 +270,7 @@ func Execute() {
 	app.nodeInformer = app.informerFactory.KubeVirtNode()
 
 	app.vmiCache = app.vmiInformer.GetStore()
+	app.vmiInformer.AddEventHandler(newVMILatencyEventHandler())
 	app.vmiRecorder = app.getNewRecorder(k8sv1.NamespaceAll, "virtualmachine-controller")
 
 	app.rsInformer = app.informerFactory.VMIReplicaSet()
@@ -247,13 +281,20 @@ func Execute() {
 	app.informerFactory.K8SInformerFactory().Policy().V1beta1().PodDisruptionBudgets().Informer()
 
 	app.vmInformer = app.informerFactory.VirtualMachine()
+	app.templateRevisionInformer = app.informerFactory.VirtualMachineTemplateRevision()
 
 	app.migrationInformer = app.informerFactory.VirtualMachineInstanceMigration()
 
+	app.conformanceRunInformer = app.informerFactory.ConformanceRun()
+
+	app.vmExportInformer = app.informerFactory.VirtualMachineExport()
+
 	app.vmSnapshotInformer = app.informerFactory.VirtualMachineSnapshot()
 	app.vmSnapshotContentInformer = app.informerFactory.VirtualMachineSnapshotContent()
 	app.storageClassInformer = app.informerFactory.StorageClass()
 
+	app.networkPolicyInformer = app.informerFactory.NetworkPolicy()
+
 	if app.hasCDI {
 		app.dataVolumeInformer = app.informerFactory.DataVolume()
 		log.Log.Infof("CDI detected, DataVolume integration enabled")
@@ -264,6 +305,9 @@ func Execute() {
 		app.dataVolumeInformer = app.informerFactory.DummyDataVolume()
 		log.Log.Infof("CDI not detected, DataVolume integration disabled")
 	}
+	// the stores stay empty until this instance leads and starts the informers
+	prometheus.MustRegister(newDataVolumeProgressCollector(app.dataVolumeInformer))
+	prometheus.MustRegister(newVMIPhaseTransitionCollector(app.vmiInformer))
 
 	app.initCommon()
 	app.initReplicaSet()
@@ -271,6 +315,13 @@ func Execute() {
 	app.initDisruptionBudgetController()
 	app.initEvacuationController()
 	app.initSnapshotController()
+	app.initConformanceController()
+	app.initExportController()
+	app.initMemoryDumpController()
+	app.initMigrationRetryController()
+	app.initPreemptionController()
+	app.initNetworkPolicyController()
+	app.initWorkloadUpdateController()
 	go app.Run()
 
 	select {
@@ -362,6 +413,13 @@ func (vca *VirtControllerApp) Run() {
 					go vca.vmController.Run(vca.vmControllerThreads, stop)
 					go vca.migrationController.Run(vca.migrationControllerThreads, stop)
 					go vca.snapshotController.Run(vca.snapshotControllerThreads, stop)
+					go vca.conformanceController.Run(vca.conformanceControllerThreads, stop)
+					go vca.exportController.Run(vca.exportControllerThreads, stop)
+					go vca.memoryDumpController.Run(vca.memoryDumpControllerThreads, stop)
+					go vca.migrationRetryController.Run(vca.migrationRetryControllerThreads, stop)
+					go vca.preemptionController.Run(vca.preemptionControllerThreads, stop)
+					go vca.networkPolicyController.Run(vca.networkPolicyControllerThreads, stop)
+					go vca.workloadUpdateController.Run(vca.workloadUpdateControllerThreads, stop)
 					cache.WaitForCacheSync(stop, vca.persistentVolumeClaimInformer.HasSynced)
 					close(vca.readyChan)
 				},
@@ -409,13 +467,13 @@ func (vca *VirtControllerApp) initCommon() {
 
 	vca.vmiController = NewVMIController(vca.templateService, vca.vmiInformer, vca.podInformer, vca.persistentVolumeClaimInformer, vca.vmiRecorder, vca.clientSet, vca.dataVolumeInformer)
 	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "node-controller")
-	vca.nodeController = NewNodeController(vca.clientSet, vca.nodeInformer, vca.vmiInformer, recorder)
-	vca.migrationController = NewMigrationController(vca.templateService, vca.vmiInformer, vca.podInformer, vca.migrationInformer, vca.vmiRecorder, vca.clientSet, vca.clusterConfig)
+	vca.nodeController = NewNodeController(vca.clientSet, vca.nodeInformer, vca.vmiInformer, recorder, vca.clusterConfig)
+	vca.migrationController = NewMigrationController(vca.templateService, vca.vmiInformer, vca.podInformer, vca.migrationInformer, vca.nodeInformer, vca.vmiRecorder, vca.clientSet, vca.clusterConfig)
 }
 
 func (vca *VirtControllerApp) initReplicaSet() {
 	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "virtualmachinereplicaset-controller")
-	vca.rsController = NewVMIReplicaSet(vca.vmiInformer, vca.rsInformer, recorder, vca.clientSet, controller.BurstReplicas)
+	vca.rsController = NewVMIReplicaSet(vca.vmiInformer, vca.rsInformer, vca.nodeInformer, recorder, vca.clientSet, controller.BurstReplicas)
 }
 
 func (vca *VirtControllerApp) initVirtualMachines() {
@@ -426,6 +484,7 @@ func (vca *VirtControllerApp) initVirtualMachines() {
 		vca.vmInformer,
 		vca.dataVolumeInformer,
 		vca.persistentVolumeClaimInformer,
+		vca.templateRevisionInformer,
 		recorder,
 		vca.clientSet)
 }
@@ -468,6 +527,91 @@ func (vca *VirtControllerApp) initSnapshotController() {
 	)
 }
 
+func (vca *VirtControllerApp) initConformanceController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "conformance-controller")
+	vca.conformanceController = NewConformanceController(
+		vca.conformanceRunInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+	)
+}
+
+func (vca *VirtControllerApp) initExportController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "export-controller")
+	vca.exportController = NewExportController(
+		vca.vmExportInformer,
+		vca.vmInformer,
+		vca.vmiInformer,
+		vca.podInformer,
+		vca.persistentVolumeClaimInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+		vca.launcherImage,
+	)
+}
+
+func (vca *VirtControllerApp) initMemoryDumpController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "memory-dump-controller")
+	vca.memoryDumpController = NewMemoryDumpController(
+		vca.vmiInformer,
+		vca.podInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+		vca.launcherImage,
+	)
+}
+
+func (vca *VirtControllerApp) initMigrationRetryController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "migration-retry-controller")
+	vca.migrationRetryController = NewMigrationRetryController(
+		vca.vmiInformer,
+		vca.migrationInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+	)
+}
+
+func (vca *VirtControllerApp) initPreemptionController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "preemption-controller")
+	vca.preemptionController = NewPreemptionController(
+		vca.vmiInformer,
+		vca.podInformer,
+		vca.nodeInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+	)
+}
+
+func (vca *VirtControllerApp) initNetworkPolicyController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "networkpolicy-controller")
+	vca.networkPolicyController = NewNetworkPolicyController(
+		vca.vmiInformer,
+		vca.networkPolicyInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+	)
+}
+
+func (vca *VirtControllerApp) initWorkloadUpdateController() {
+	recorder := vca.getNewRecorder(k8sv1.NamespaceAll, "workload-update-controller")
+	vca.workloadUpdateController = NewWorkloadUpdateController(
+		vca.vmiInformer,
+		vca.podInformer,
+		vca.migrationInformer,
+		vca.kubeVirtInformer,
+		recorder,
+		vca.clientSet,
+		vca.clusterConfig,
+		vca.launcherImage,
+	)
+}
+
 func (vca *VirtControllerApp) leaderProbe(_ *restful.Request, response *restful.Response) {
 	res := map[string]interface{}{}
 
@@ -542,4 +686,25 @@ func (vca *VirtControllerApp) AddFlags() {
 
 	flag.DurationVar(&vca.snapshotControllerResyncPeriod, "snapshot-controller-resync-period", defaultSnapshotControllerResyncPeriod,
 		"Number of goroutines to run for snapshot controller")
+
+	flag.IntVar(&vca.conformanceControllerThreads, "conformance-controller-threads", 1,
+		"Number of goroutines to run for conformance controller")
+
+	flag.IntVar(&vca.exportControllerThreads, "export-controller-threads", 1,
+		"Number of goroutines to run for export controller")
+
+	flag.IntVar(&vca.memoryDumpControllerThreads, "memory-dump-controller-threads", 1,
+		"Number of goroutines to run for memory dump controller")
+
+	flag.IntVar(&vca.migrationRetryControllerThreads, "migration-retry-controller-threads", 1,
+		"Number of goroutines to run for migration retry controller")
+
+	flag.IntVar(&vca.preemptionControllerThreads, "preemption-controller-threads", 1,
+		"Number of goroutines to run for preemption controller")
+
+	flag.IntVar(&vca.networkPolicyControllerThreads, "networkpolicy-controller-threads", 1,
+		"Number of goroutines to run for network policy controller")
+
+	flag.IntVar(&vca.workloadUpdateControllerThreads, "workload-update-controller-threads", 1,
+		"Number of goroutines to run for workload update controller")
 }
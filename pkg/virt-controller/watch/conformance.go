@@ -0,0 +1,289 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"sync"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/client-go/version"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virt-controller/conformance"
+)
+
+const (
+	// FailedConformanceRunReason is added in an event if a ConformanceRun finished with failed features
+	FailedConformanceRunReason = "FailedConformanceRun"
+	// SucceededConformanceRunReason is added in an event if all features of a ConformanceRun passed or were skipped
+	SucceededConformanceRunReason = "SucceededConformanceRun"
+	// InterruptedConformanceRunReason is added in an event if the controller lost track of a running ConformanceRun
+	InterruptedConformanceRunReason = "InterruptedConformanceRun"
+)
+
+// conformanceRunState holds the results of a ConformanceRun which is
+// exercised by this controller instance
+type conformanceRunState struct {
+	uid     types.UID
+	results []virtv1.ConformanceFeatureResult
+	done    bool
+}
+
+type ConformanceController struct {
+	clientset              kubecli.KubevirtClient
+	Queue                  workqueue.RateLimitingInterface
+	conformanceRunInformer cache.SharedIndexInformer
+	recorder               record.EventRecorder
+	clusterConfig          *virtconfig.ClusterConfig
+
+	runsLock *sync.Mutex
+	runs     map[string]*conformanceRunState
+	// runFeatures blocks until all features of the run are exercised
+	runFeatures func(run *virtv1.ConformanceRun, report func(result virtv1.ConformanceFeatureResult))
+}
+
+func NewConformanceController(
+	conformanceRunInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+) *ConformanceController {
+
+	c := &ConformanceController{
+		clientset:              clientset,
+		Queue:                  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		conformanceRunInformer: conformanceRunInformer,
+		recorder:               recorder,
+		clusterConfig:          clusterConfig,
+		runsLock:               &sync.Mutex{},
+		runs:                   map[string]*conformanceRunState{},
+	}
+	c.runFeatures = func(run *virtv1.ConformanceRun, report func(result virtv1.ConformanceFeatureResult)) {
+		conformance.NewRunner(c.clientset, c.clusterConfig, run).Run(report)
+	}
+
+	c.conformanceRunInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addConformanceRun,
+		DeleteFunc: c.deleteConformanceRun,
+		UpdateFunc: c.updateConformanceRun,
+	})
+
+	return c
+}
+
+func (c *ConformanceController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting conformance controller.")
+
+	// Wait for cache sync before we start the conformance controller
+	cache.WaitForCacheSync(stopCh, c.conformanceRunInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping conformance controller.")
+}
+
+func (c *ConformanceController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *ConformanceController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing ConformanceRun %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed ConformanceRun %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *ConformanceController) execute(key string) error {
+	obj, exists, err := c.conformanceRunInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		c.forgetRunIfDone(key, "")
+		return nil
+	}
+	run := obj.(*virtv1.ConformanceRun)
+
+	if run.IsFinal() {
+		c.forgetRunIfDone(key, run.UID)
+		return nil
+	}
+
+	if run.Status.Phase == virtv1.ConformanceRunPhaseUnset {
+		return c.startRun(key, run)
+	}
+
+	return c.updateStatus(key, run)
+}
+
+func (c *ConformanceController) startRun(key string, run *virtv1.ConformanceRun) error {
+	runCopy := run.DeepCopy()
+	now := v1.Now()
+	runCopy.Status.Phase = virtv1.ConformanceRunRunning
+	runCopy.Status.StartTimestamp = &now
+	runCopy.Status.KubeVirtVersion = version.Get().String()
+
+	runCopy, err := c.clientset.ConformanceRun(run.Namespace).UpdateStatus(runCopy)
+	if err != nil {
+		return err
+	}
+
+	state := &conformanceRunState{uid: run.UID}
+	c.runsLock.Lock()
+	c.runs[key] = state
+	c.runsLock.Unlock()
+
+	log.Log.Object(run).Info("Starting conformance run")
+	go func() {
+		c.runFeatures(runCopy, func(result virtv1.ConformanceFeatureResult) {
+			c.runsLock.Lock()
+			state.results = append(state.results, result)
+			c.runsLock.Unlock()
+			c.Queue.Add(key)
+		})
+		c.runsLock.Lock()
+		state.done = true
+		c.runsLock.Unlock()
+		c.Queue.Add(key)
+	}()
+
+	return nil
+}
+
+func (c *ConformanceController) updateStatus(key string, run *virtv1.ConformanceRun) error {
+	runCopy := run.DeepCopy()
+
+	c.runsLock.Lock()
+	state, exists := c.runs[key]
+	tracked := exists && state.uid == run.UID
+	done := tracked && state.done
+	if tracked {
+		runCopy.Status.Results = append([]virtv1.ConformanceFeatureResult{}, state.results...)
+	}
+	c.runsLock.Unlock()
+
+	if !tracked {
+		// The features were exercised by a controller instance which is gone,
+		// the results it did not report yet are lost
+		c.recorder.Event(run, k8sv1.EventTypeWarning, InterruptedConformanceRunReason, "The conformance run was interrupted")
+	}
+
+	runCopy.Status.Passed, runCopy.Status.Failed, runCopy.Status.Skipped = 0, 0, 0
+	for _, result := range runCopy.Status.Results {
+		switch result.Result {
+		case virtv1.ConformanceResultPassed:
+			runCopy.Status.Passed++
+		case virtv1.ConformanceResultFailed:
+			runCopy.Status.Failed++
+		case virtv1.ConformanceResultSkipped:
+			runCopy.Status.Skipped++
+		}
+	}
+
+	if done || !tracked {
+		now := v1.Now()
+		runCopy.Status.CompletionTimestamp = &now
+		if runCopy.Status.Failed > 0 || !tracked {
+			runCopy.Status.Phase = virtv1.ConformanceRunFailed
+		} else {
+			runCopy.Status.Phase = virtv1.ConformanceRunSucceeded
+		}
+	}
+
+	if equality.Semantic.DeepEqual(run.Status, runCopy.Status) {
+		return nil
+	}
+
+	_, err := c.clientset.ConformanceRun(run.Namespace).UpdateStatus(runCopy)
+	if err != nil {
+		return err
+	}
+
+	if runCopy.Status.Phase == virtv1.ConformanceRunFailed && tracked {
+		c.recorder.Eventf(run, k8sv1.EventTypeWarning, FailedConformanceRunReason, "%d of %d features failed", runCopy.Status.Failed, len(runCopy.Status.Results))
+	} else if runCopy.Status.Phase == virtv1.ConformanceRunSucceeded {
+		c.recorder.Eventf(run, k8sv1.EventTypeNormal, SucceededConformanceRunReason, "%d features passed, %d skipped", runCopy.Status.Passed, runCopy.Status.Skipped)
+	}
+	return nil
+}
+
+// forgetRunIfDone drops the results of a run once its runner returned. An
+// empty uid matches any run stored under key.
+func (c *ConformanceController) forgetRunIfDone(key string, uid types.UID) {
+	c.runsLock.Lock()
+	defer c.runsLock.Unlock()
+	state, exists := c.runs[key]
+	if exists && state.done && (uid == "" || state.uid == uid) {
+		delete(c.runs, key)
+	}
+}
+
+func (c *ConformanceController) addConformanceRun(obj interface{}) {
+	c.enqueueConformanceRun(obj)
+}
+
+func (c *ConformanceController) deleteConformanceRun(obj interface{}) {
+	c.enqueueConformanceRun(obj)
+}
+
+func (c *ConformanceController) updateConformanceRun(old, curr interface{}) {
+	c.enqueueConformanceRun(curr)
+}
+
+func (c *ConformanceController) enqueueConformanceRun(obj interface{}) {
+	logger := log.Log
+	run := obj.(*virtv1.ConformanceRun)
+	key, err := controller.KeyFunc(run)
+	if err != nil {
+		logger.Object(run).Reason(err).Error("Failed to extract key from ConformanceRun.")
+	}
+	c.Queue.Add(key)
+}
@@ -0,0 +1,217 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	framework "k8s.io/client-go/tools/cache/testing"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("Conformance watcher", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var runInterface *kubecli.MockConformanceRunInterface
+	var runSource *framework.FakeControllerSource
+	var runInformer cache.SharedIndexInformer
+	var stop chan struct{}
+	var conformanceController *ConformanceController
+	var recorder *record.FakeRecorder
+	var mockQueue *testutils.MockWorkQueue
+	var virtClient *kubecli.MockKubevirtClient
+
+	newConformanceRun := func(name string, phase v1.ConformanceRunPhase) *v1.ConformanceRun {
+		return &v1.ConformanceRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: k8sv1.NamespaceDefault,
+				UID:       "1234",
+			},
+			Status: v1.ConformanceRunStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	newResult := func(feature v1.ConformanceFeature, result v1.ConformanceResult) v1.ConformanceFeatureResult {
+		return v1.ConformanceFeatureResult{
+			Feature: feature,
+			Result:  result,
+		}
+	}
+
+	addConformanceRun := func(run *v1.ConformanceRun) {
+		mockQueue.ExpectAdds(1)
+		runSource.Add(run)
+		mockQueue.Wait()
+	}
+
+	trackRun := func(run *v1.ConformanceRun, done bool, results ...v1.ConformanceFeatureResult) {
+		key, err := controller.KeyFunc(run)
+		Expect(err).ToNot(HaveOccurred())
+		conformanceController.runs[key] = &conformanceRunState{
+			uid:     run.UID,
+			results: results,
+			done:    done,
+		}
+	}
+
+	shouldExpectStatusUpdate := func(check func(status v1.ConformanceRunStatus)) {
+		runInterface.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(run *v1.ConformanceRun) (*v1.ConformanceRun, error) {
+			check(run.Status)
+			return run, nil
+		})
+	}
+
+	BeforeEach(func() {
+		stop = make(chan struct{})
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		runInterface = kubecli.NewMockConformanceRunInterface(ctrl)
+
+		runInformer, runSource = testutils.NewFakeInformerFor(&v1.ConformanceRun{})
+		recorder = record.NewFakeRecorder(100)
+		config, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+
+		conformanceController = NewConformanceController(runInformer, recorder, virtClient, config)
+		conformanceController.runFeatures = func(run *v1.ConformanceRun, report func(result v1.ConformanceFeatureResult)) {
+			Fail("no features should be exercised")
+		}
+		// Wrap our workqueue to have a way to detect when we are done processing updates
+		mockQueue = testutils.NewMockWorkQueue(conformanceController.Queue)
+		conformanceController.Queue = mockQueue
+
+		virtClient.EXPECT().ConformanceRun(k8sv1.NamespaceDefault).Return(runInterface).AnyTimes()
+
+		go runInformer.Run(stop)
+		Expect(cache.WaitForCacheSync(stop, runInformer.HasSynced)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		close(stop)
+		// Ensure that we add checks for expected events to every test
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	It("should start a new run and collect its results", func() {
+		run := newConformanceRun("testrun", v1.ConformanceRunPhaseUnset)
+		addConformanceRun(run)
+
+		shouldExpectStatusUpdate(func(status v1.ConformanceRunStatus) {
+			Expect(status.Phase).To(Equal(v1.ConformanceRunRunning))
+			Expect(status.StartTimestamp).ToNot(BeNil())
+			Expect(status.KubeVirtVersion).ToNot(BeEmpty())
+		})
+		conformanceController.runFeatures = func(run *v1.ConformanceRun, report func(result v1.ConformanceFeatureResult)) {
+			report(newResult(v1.ConformanceFeatureBoot, v1.ConformanceResultPassed))
+			report(newResult(v1.ConformanceFeatureHotplug, v1.ConformanceResultSkipped))
+		}
+
+		// one add per reported result and one once the runner returned
+		mockQueue.ExpectAdds(3)
+		conformanceController.Execute()
+		mockQueue.Wait()
+
+		run.Status.Phase = v1.ConformanceRunRunning
+		mockQueue.ExpectAdds(1)
+		runSource.Modify(run)
+		mockQueue.Wait()
+
+		shouldExpectStatusUpdate(func(status v1.ConformanceRunStatus) {
+			Expect(status.Phase).To(Equal(v1.ConformanceRunSucceeded))
+			Expect(status.CompletionTimestamp).ToNot(BeNil())
+			Expect(status.Results).To(HaveLen(2))
+			Expect(status.Passed).To(Equal(1))
+			Expect(status.Skipped).To(Equal(1))
+			Expect(status.Failed).To(Equal(0))
+		})
+		conformanceController.Execute()
+
+		testutils.ExpectEvent(recorder, SucceededConformanceRunReason)
+	})
+
+	It("should publish the results of a run in progress", func() {
+		run := newConformanceRun("testrun", v1.ConformanceRunRunning)
+		addConformanceRun(run)
+		trackRun(run, false, newResult(v1.ConformanceFeatureBoot, v1.ConformanceResultPassed))
+
+		shouldExpectStatusUpdate(func(status v1.ConformanceRunStatus) {
+			Expect(status.Phase).To(Equal(v1.ConformanceRunRunning))
+			Expect(status.CompletionTimestamp).To(BeNil())
+			Expect(status.Results).To(HaveLen(1))
+			Expect(status.Passed).To(Equal(1))
+		})
+		conformanceController.Execute()
+	})
+
+	It("should fail a run with failed features", func() {
+		run := newConformanceRun("testrun", v1.ConformanceRunRunning)
+		addConformanceRun(run)
+		trackRun(run, true,
+			newResult(v1.ConformanceFeatureBoot, v1.ConformanceResultPassed),
+			newResult(v1.ConformanceFeatureMigrate, v1.ConformanceResultFailed),
+		)
+
+		shouldExpectStatusUpdate(func(status v1.ConformanceRunStatus) {
+			Expect(status.Phase).To(Equal(v1.ConformanceRunFailed))
+			Expect(status.Passed).To(Equal(1))
+			Expect(status.Failed).To(Equal(1))
+		})
+		conformanceController.Execute()
+
+		testutils.ExpectEvent(recorder, FailedConformanceRunReason)
+	})
+
+	It("should fail a running run which is not tracked by this controller", func() {
+		run := newConformanceRun("testrun", v1.ConformanceRunRunning)
+		addConformanceRun(run)
+
+		shouldExpectStatusUpdate(func(status v1.ConformanceRunStatus) {
+			Expect(status.Phase).To(Equal(v1.ConformanceRunFailed))
+			Expect(status.CompletionTimestamp).ToNot(BeNil())
+		})
+		conformanceController.Execute()
+
+		testutils.ExpectEvent(recorder, InterruptedConformanceRunReason)
+	})
+
+	It("should ignore and forget finished runs", func() {
+		run := newConformanceRun("testrun", v1.ConformanceRunSucceeded)
+		addConformanceRun(run)
+		trackRun(run, true)
+
+		conformanceController.Execute()
+
+		Expect(conformanceController.runs).To(BeEmpty())
+	})
+})
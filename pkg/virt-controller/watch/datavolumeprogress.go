@@ -0,0 +1,88 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+var dataVolumeProgressDesc = prometheus.NewDesc(
+	"kubevirt_vm_disk_provisioning_progress",
+	"Progress in percent of the import, clone or upload of a DataVolume owned by a VirtualMachine.",
+	[]string{"namespace", "name", "data_volume", "phase"},
+	nil,
+)
+
+// dataVolumeProgressCollector reports the progress of the DataVolumes of the
+// VMs until they are provisioned, which tells why a VM has not started yet
+type dataVolumeProgressCollector struct {
+	dataVolumeInformer cache.SharedIndexInformer
+}
+
+func newDataVolumeProgressCollector(dataVolumeInformer cache.SharedIndexInformer) *dataVolumeProgressCollector {
+	return &dataVolumeProgressCollector{dataVolumeInformer: dataVolumeInformer}
+}
+
+func (c *dataVolumeProgressCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dataVolumeProgressDesc
+}
+
+func (c *dataVolumeProgressCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, obj := range c.dataVolumeInformer.GetStore().List() {
+		dataVolume := obj.(*cdiv1.DataVolume)
+		if dataVolume.Status.Phase == cdiv1.Succeeded {
+			continue
+		}
+		controllerRef := v1.GetControllerOf(dataVolume)
+		if controllerRef == nil || controllerRef.Kind != virtv1.VirtualMachineGroupVersionKind.Kind {
+			continue
+		}
+
+		mv, err := prometheus.NewConstMetric(
+			dataVolumeProgressDesc, prometheus.GaugeValue,
+			parseDataVolumeProgress(dataVolume.Status.Progress),
+			dataVolume.Namespace, controllerRef.Name, dataVolume.Name, string(dataVolume.Status.Phase),
+		)
+		if err != nil {
+			log.Log.Object(dataVolume).Reason(err).Error("Failed to create the provisioning progress metric")
+			continue
+		}
+		ch <- mv
+	}
+}
+
+// parseDataVolumeProgress parses the progress CDI reports, like 45.50%. A
+// progress which is not known yet, like N/A, counts as 0.
+func parseDataVolumeProgress(progress cdiv1.DataVolumeProgress) float64 {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(string(progress), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
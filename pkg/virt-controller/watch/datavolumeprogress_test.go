@@ -0,0 +1,116 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("DataVolume progress collector", func() {
+	var dataVolumeInformer cache.SharedIndexInformer
+	var collector *dataVolumeProgressCollector
+
+	BeforeEach(func() {
+		dataVolumeInformer, _ = testutils.NewFakeInformerFor(&cdiv1.DataVolume{})
+		collector = newDataVolumeProgressCollector(dataVolumeInformer)
+	})
+
+	addDataVolume := func(name string, phase cdiv1.DataVolumePhase, progress cdiv1.DataVolumeProgress, owner metav1.Object) {
+		dataVolume := &cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: k8sv1.NamespaceDefault,
+			},
+			Status: cdiv1.DataVolumeStatus{
+				Phase:    phase,
+				Progress: progress,
+			},
+		}
+		if owner != nil {
+			dataVolume.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, v1.VirtualMachineGroupVersionKind)}
+		}
+		Expect(dataVolumeInformer.GetStore().Add(dataVolume)).To(Succeed())
+	}
+
+	collect := func() []*io_prometheus_client.Metric {
+		ch := make(chan prometheus.Metric, 10)
+		collector.Collect(ch)
+		close(ch)
+
+		var metrics []*io_prometheus_client.Metric
+		for m := range ch {
+			Expect(m.Desc()).To(Equal(dataVolumeProgressDesc))
+			dto := &io_prometheus_client.Metric{}
+			Expect(m.Write(dto)).To(Succeed())
+			metrics = append(metrics, dto)
+		}
+		return metrics
+	}
+
+	labelsOf := func(metric *io_prometheus_client.Metric) map[string]string {
+		labels := map[string]string{}
+		for _, pair := range metric.Label {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		return labels
+	}
+
+	It("should report the progress of the DataVolumes of a VM", func() {
+		vm := &v1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: k8sv1.NamespaceDefault}}
+		addDataVolume("testdv", cdiv1.ImportInProgress, "45.50%", vm)
+
+		metrics := collect()
+		Expect(metrics).To(HaveLen(1))
+		Expect(metrics[0].GetGauge().GetValue()).To(Equal(45.5))
+		Expect(labelsOf(metrics[0])).To(Equal(map[string]string{
+			"namespace":   k8sv1.NamespaceDefault,
+			"name":        "testvm",
+			"data_volume": "testdv",
+			"phase":       string(cdiv1.ImportInProgress),
+		}))
+	})
+
+	It("should skip provisioned DataVolumes and the ones which are not owned by a VM", func() {
+		vm := &v1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Name: "testvm", Namespace: k8sv1.NamespaceDefault}}
+		addDataVolume("provisioned", cdiv1.Succeeded, "100.0%", vm)
+		addDataVolume("standalone", cdiv1.CloneInProgress, "10.0%", nil)
+
+		Expect(collect()).To(BeEmpty())
+	})
+
+	table.DescribeTable("should parse the progress", func(progress cdiv1.DataVolumeProgress, expected float64) {
+		Expect(parseDataVolumeProgress(progress)).To(Equal(expected))
+	},
+		table.Entry("in percent", cdiv1.DataVolumeProgress("12.34%"), 12.34),
+		table.Entry("when it is not known yet", cdiv1.DataVolumeProgress("N/A"), 0.0),
+		table.Entry("when it is empty", cdiv1.DataVolumeProgress(""), 0.0),
+	)
+})
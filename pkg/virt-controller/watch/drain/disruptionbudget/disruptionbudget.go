@@ -5,9 +5,12 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -18,6 +21,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/controller"
+	migrationutils "kubevirt.io/kubevirt/pkg/util/migrations"
 )
 
 const (
@@ -29,8 +33,39 @@ const (
 	FailedDeletePodDisruptionBudgetReason = "FailedDelete"
 	// SuccessfulDeletePodDisruptionBudgetReason is added in an event if deleting a PodDisruptionBudget succeeded.
 	SuccessfulDeletePodDisruptionBudgetReason = "SuccessfulDelete"
+	// FailedUpdatePodDisruptionBudgetReason is added in an event if updating a PodDisruptionBudget failed.
+	FailedUpdatePodDisruptionBudgetReason = "FailedUpdate"
+	// SuccessfulUpdatePodDisruptionBudgetReason is added in an event if updating a PodDisruptionBudget succeeded.
+	SuccessfulUpdatePodDisruptionBudgetReason = "SuccessfulUpdate"
 )
 
+const (
+	pdbGenerateName = "kubevirt-disruption-budget-"
+
+	// orphanCleanupInterval is how often PodDisruptionBudgets without a VMI are looked for
+	orphanCleanupInterval = 5 * time.Minute
+)
+
+var (
+	orphanedPDBsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_vmi_orphaned_pdbs",
+			Help: "Number of PodDisruptionBudgets created for VMIs which do not exist anymore, found by the last clean-up",
+		},
+	)
+	orphanedPDBsDeletedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmi_orphaned_pdbs_deleted_total",
+			Help: "Number of PodDisruptionBudgets deleted because their VMI did not exist anymore",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(orphanedPDBsGauge)
+	prometheus.MustRegister(orphanedPDBsDeletedCounter)
+}
+
 type DisruptionBudgetController struct {
 	clientset                       kubecli.KubevirtClient
 	Queue                           workqueue.RateLimitingInterface
@@ -258,6 +293,7 @@ func (c *DisruptionBudgetController) Run(threadiness int, stopCh <-chan struct{}
 	for i := 0; i < threadiness; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
+	go wait.Until(c.CleanupOrphanedPodDisruptionBudgets, orphanCleanupInterval, stopCh)
 
 	<-stopCh
 	log.Log.Info("Stopping disruption budget controller.")
@@ -327,25 +363,28 @@ func (c *DisruptionBudgetController) execute(key string) error {
 func (c *DisruptionBudgetController) sync(key string, vmi *virtv1.VirtualMachineInstance, pdb *v1beta1.PodDisruptionBudget) error {
 	delete := false
 	create := false
+	update := false
 	// delete if there is no VMI
 	if vmi == nil && pdb != nil {
 		delete = true
 	} else if vmi != nil {
-		wantsToMigrate := wantsToMigrateOnDrain(vmi)
+		wantsPDB := wantsPodDisruptionBudget(vmi)
 		if vmi.DeletionTimestamp != nil && pdb != nil {
 			// pdb can already be deleted, shutdown already in process
 			delete = true
-		} else if !wantsToMigrate && pdb != nil {
-			// We don't want migrations on evictions, if there is a pdb, remove it
+		} else if !wantsPDB && pdb != nil {
+			// Evictions don't have to be blocked, if there is a pdb, remove it
 			delete = true
-		} else if wantsToMigrate && vmi.DeletionTimestamp == nil && pdb == nil {
-			// No pdb and we want migrations on evictions
+		} else if wantsPDB && vmi.DeletionTimestamp == nil && pdb == nil {
+			// No pdb and evictions have to be blocked
 			create = true
-		} else if wantsToMigrate && pdb != nil {
+		} else if wantsPDB && pdb != nil {
 			if ownerRef := v1.GetControllerOf(pdb); ownerRef != nil && ownerRef.UID != vmi.UID {
 				// The pdb is from an old vmi with a different uid, delete and later create the correct one
 				// The VMI always has a minimum grace period, so normally this should not happen, therefore no optimizations
 				delete = true
+			} else if !hasDesiredSpec(pdb) {
+				update = true
 			}
 		}
 	}
@@ -364,18 +403,26 @@ func (c *DisruptionBudgetController) sync(key string, vmi *virtv1.VirtualMachine
 		}
 		c.recorder.Eventf(vmi, v12.EventTypeNormal, SuccessfulDeletePodDisruptionBudgetReason, "Deleted PodDisruptionBudget %s", pdb.Name)
 		return nil
+	} else if update {
+		patch := fmt.Sprintf(`{"spec":{"minAvailable":%d,"maxUnavailable":null}}`, minAvailable.IntValue())
+		_, err := c.clientset.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace).Patch(pdb.Name, types.MergePatchType, []byte(patch))
+		if err != nil {
+			c.recorder.Eventf(vmi, v12.EventTypeWarning, FailedUpdatePodDisruptionBudgetReason, "Error updating the PodDisruptionBudget %s: %v", pdb.Name, err)
+			return err
+		}
+		c.recorder.Eventf(vmi, v12.EventTypeNormal, SuccessfulUpdatePodDisruptionBudgetReason, "Updated PodDisruptionBudget %s", pdb.Name)
 	} else if create {
-		two := intstr.FromInt(2)
+		minAvailable := minAvailable
 		c.podDisruptionBudgetExpectations.ExpectCreations(key, 1)
 		createdPDB, err := c.clientset.PolicyV1beta1().PodDisruptionBudgets(vmi.Namespace).Create(&v1beta1.PodDisruptionBudget{
 			ObjectMeta: v1.ObjectMeta{
 				OwnerReferences: []v1.OwnerReference{
 					*v1.NewControllerRef(vmi, virtv1.VirtualMachineInstanceGroupVersionKind),
 				},
-				GenerateName: "kubevirt-disruption-budget-",
+				GenerateName: pdbGenerateName,
 			},
 			Spec: v1beta1.PodDisruptionBudgetSpec{
-				MinAvailable: &two,
+				MinAvailable: &minAvailable,
 				Selector: &v1.LabelSelector{
 					MatchLabels: map[string]string{
 						virtv1.CreatedByLabel: string(vmi.UID),
@@ -409,12 +456,61 @@ func (c *DisruptionBudgetController) pdbForVMI(namespace, name string) (*v1beta1
 	return nil, nil
 }
 
-func wantsToMigrateOnDrain(vmi *virtv1.VirtualMachineInstance) bool {
-	if vmi.Spec.EvictionStrategy == nil {
+// CleanupOrphanedPodDisruptionBudgets deletes the PodDisruptionBudgets created
+// for VMIs, which lost their VMI, e.g. because their owner reference was removed
+func (c *DisruptionBudgetController) CleanupOrphanedPodDisruptionBudgets() {
+	orphaned := 0
+	for _, obj := range c.pdbInformer.GetStore().List() {
+		pdb := obj.(*v1beta1.PodDisruptionBudget)
+		if pdb.GenerateName != pdbGenerateName || pdb.DeletionTimestamp != nil || !c.isOrphaned(pdb) {
+			continue
+		}
+		orphaned++
+
+		err := c.clientset.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace).Delete(pdb.Name, &v1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			log.Log.Object(pdb).Reason(err).Error("Failed to delete orphaned PodDisruptionBudget")
+			c.recorder.Eventf(pdb, v12.EventTypeWarning, FailedDeletePodDisruptionBudgetReason, "Error deleting the orphaned PodDisruptionBudget %s: %v", pdb.Name, err)
+			continue
+		}
+		orphanedPDBsDeletedCounter.Inc()
+		c.recorder.Eventf(pdb, v12.EventTypeNormal, SuccessfulDeletePodDisruptionBudgetReason, "Deleted orphaned PodDisruptionBudget %s", pdb.Name)
+	}
+	orphanedPDBsGauge.Set(float64(orphaned))
+}
+
+func (c *DisruptionBudgetController) isOrphaned(pdb *v1beta1.PodDisruptionBudget) bool {
+	controllerRef := v1.GetControllerOf(pdb)
+	if controllerRef == nil || controllerRef.Kind != virtv1.VirtualMachineInstanceGroupVersionKind.Kind {
+		return true
+	}
+	obj, exists, err := c.vmiInformer.GetStore().GetByKey(pdb.Namespace + "/" + controllerRef.Name)
+	if err != nil {
+		return false
+	}
+	return !exists || obj.(*virtv1.VirtualMachineInstance).UID != controllerRef.UID
+}
+
+// minAvailable keeps the launcher pod of the VMI from being disrupted. During
+// a migration the selector covers the target pod as well, which allows one of
+// the two pods to be disrupted.
+var minAvailable = intstr.FromInt(1)
+
+func hasDesiredSpec(pdb *v1beta1.PodDisruptionBudget) bool {
+	return pdb.Spec.MaxUnavailable == nil && pdb.Spec.MinAvailable != nil &&
+		*pdb.Spec.MinAvailable == minAvailable
+}
+
+// wantsPodDisruptionBudget checks whether evictions of the launcher pods have
+// to be blocked, because the eviction strategy moves the VMI away instead.
+// A PodDisruptionBudget is kept while a migration is in progress, so that
+// the migration is not disrupted by a changing migratable condition.
+func wantsPodDisruptionBudget(vmi *virtv1.VirtualMachineInstance) bool {
+	if vmi.IsFinal() {
 		return false
 	}
-	if *vmi.Spec.EvictionStrategy == virtv1.EvictionStrategyLiveMigrate {
+	if migrationutils.MigrateOnEviction(vmi) || migrationutils.VMIEvictionStrategy(vmi) == virtv1.EvictionStrategyExternal {
 		return true
 	}
-	return false
+	return vmi.Status.MigrationState != nil && !vmi.Status.MigrationState.Completed
 }
@@ -73,12 +73,22 @@ var _ = Describe("Disruptionbudget", func() {
 			update, ok := action.(testing.CreateAction)
 			pdb := update.GetObject().(*v1beta1.PodDisruptionBudget)
 			Expect(ok).To(BeTrue())
-			Expect(pdb.Spec.MinAvailable.String()).To(Equal("2"))
+			Expect(pdb.Spec.MinAvailable.String()).To(Equal("1"))
 			Expect(update.GetObject().(*v1beta1.PodDisruptionBudget).Spec.Selector.MatchLabels[v1.CreatedByLabel]).To(Equal(string(uid)))
 			return true, update.GetObject(), nil
 		})
 	}
 
+	shouldExpectPDBPatch := func(pdb *v1beta1.PodDisruptionBudget) {
+		kubeClient.Fake.PrependReactor("patch", "poddisruptionbudgets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			patch, ok := action.(testing.PatchAction)
+			Expect(ok).To(BeTrue())
+			Expect(patch.GetName()).To(Equal(pdb.Name))
+			Expect(string(patch.GetPatch())).To(Equal(`{"spec":{"minAvailable":1,"maxUnavailable":null}}`))
+			return true, nil, nil
+		})
+	}
+
 	BeforeEach(func() {
 		stop = make(chan struct{})
 		ctrl = gomock.NewController(GinkgoT())
@@ -127,6 +137,39 @@ var _ = Describe("Disruptionbudget", func() {
 			controller.Execute()
 			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulDeletePodDisruptionBudgetReason)
 		})
+
+		It("should do nothing, if it can not be migrated with the LiveMigrateIfPossible strategy", func() {
+			vmi := newVirtualMachine("testvm")
+			strategy := v1.EvictionStrategyLiveMigrateIfPossible
+			vmi.Spec.EvictionStrategy = &strategy
+			addVirtualMachine(vmi)
+
+			controller.Execute()
+		})
+
+		It("should keep the pdb while a migration is in progress", func() {
+			vmi := newVirtualMachine("testvm")
+			vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{}
+			addVirtualMachine(vmi)
+			pdb := newPodDisruptionBudget(vmi)
+			pdbFeeder.Add(pdb)
+
+			controller.Execute()
+		})
+	})
+
+	Context("A VirtualMachineInstance given which is moved away by an external controller on evictions", func() {
+
+		It("should add the pdb, if it does not exist", func() {
+			vmi := newVirtualMachine("testvm")
+			strategy := v1.EvictionStrategyExternal
+			vmi.Spec.EvictionStrategy = &strategy
+			addVirtualMachine(vmi)
+
+			shouldExpectPDBCreation(vmi.UID)
+			controller.Execute()
+			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulCreatePodDisruptionBudgetReason)
+		})
 	})
 
 	Context("A VirtualMachineInstance given which wants to live-migrate on evictions", func() {
@@ -261,6 +304,57 @@ var _ = Describe("Disruptionbudget", func() {
 			controller.Execute()
 			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulCreatePodDisruptionBudgetReason)
 		})
+
+		It("should update a pdb which blocks all disruptions during a migration", func() {
+			vmi := newVirtualMachine("testvm")
+			vmi.Spec.EvictionStrategy = newEvictionStrategy()
+			addVirtualMachine(vmi)
+			pdb := newPodDisruptionBudget(vmi)
+			two := intstr.FromInt(2)
+			pdb.Spec.MinAvailable = &two
+			pdbFeeder.Add(pdb)
+
+			shouldExpectPDBPatch(pdb)
+			controller.Execute()
+			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulUpdatePodDisruptionBudgetReason)
+		})
+	})
+
+	Context("Orphaned pdbs", func() {
+
+		It("should be deleted", func() {
+			vmi := newVirtualMachine("testvm")
+			pdb := newPodDisruptionBudget(vmi)
+			pdb.OwnerReferences = nil
+			Expect(pdbInformer.GetStore().Add(pdb)).To(Succeed())
+
+			shouldExpectPDBDeletion(pdb)
+			controller.CleanupOrphanedPodDisruptionBudgets()
+			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulDeletePodDisruptionBudgetReason)
+		})
+
+		It("should be deleted, if their VMI does not exist anymore", func() {
+			pdb := newPodDisruptionBudget(newVirtualMachine("testvm"))
+			Expect(pdbInformer.GetStore().Add(pdb)).To(Succeed())
+
+			shouldExpectPDBDeletion(pdb)
+			controller.CleanupOrphanedPodDisruptionBudgets()
+			testutils.ExpectEvent(recorder, disruptionbudget.SuccessfulDeletePodDisruptionBudgetReason)
+		})
+
+		It("should not include pdbs of existing VMIs or created by others", func() {
+			vmi := newVirtualMachine("testvm")
+			vmi.Spec.EvictionStrategy = newEvictionStrategy()
+			Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+			Expect(pdbInformer.GetStore().Add(newPodDisruptionBudget(vmi))).To(Succeed())
+			other := newPodDisruptionBudget(vmi)
+			other.Name = "other"
+			other.GenerateName = ""
+			other.OwnerReferences = nil
+			Expect(pdbInformer.GetStore().Add(other)).To(Succeed())
+
+			controller.CleanupOrphanedPodDisruptionBudgets()
+		})
 	})
 
 	AfterEach(func() {
@@ -285,6 +379,7 @@ func newPodDisruptionBudget(vmi *v1.VirtualMachineInstance) *v1beta1.PodDisrupti
 			OwnerReferences: []v13.OwnerReference{
 				*v13.NewControllerRef(vmi, v1.VirtualMachineInstanceGroupVersionKind),
 			},
+			Name:         "kubevirt-disruption-budget-testvm",
 			GenerateName: "kubevirt-disruption-budget-",
 			Namespace:    vmi.Namespace,
 		},
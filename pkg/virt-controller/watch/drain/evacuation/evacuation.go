@@ -312,15 +312,16 @@ func (c *EvacuationController) execute(key string) error {
 		Effect: k8sv1.TaintEffectNoSchedule,
 	}
 
-	if !nodeHasTaint(taint, node) {
-		return nil
-	}
-
 	vmis, err := c.listVMIsOnNode(node.Name)
 	if err != nil {
 		return fmt.Errorf("failed to list VMIs on node: %v", err)
 	}
 
+	// Without the drain taint only VMIs whose pod eviction was blocked have to leave the node
+	if !nodeHasTaint(taint, node) {
+		vmis = filterEvacuatingVMIs(vmis, node.Name)
+	}
+
 	migrations, err := migrationutils.ListUnfinishedMigrations(c.migrationInformer)
 
 	if err != nil {
@@ -438,12 +439,15 @@ func (c *EvacuationController) filterRunningNonMigratingVMIs(vmis []*virtv1.Virt
 	for _, vmi := range vmis {
 
 		// does not want to migrate
-		if vmi.Spec.EvictionStrategy == nil || *vmi.Spec.EvictionStrategy != virtv1.EvictionStrategyLiveMigrate {
+		strategy := migrationutils.VMIEvictionStrategy(vmi)
+		if strategy != virtv1.EvictionStrategyLiveMigrate && strategy != virtv1.EvictionStrategyLiveMigrateIfPossible {
 			continue
 		}
-		// can't migrate
-		if !controller.NewVirtualMachineInstanceConditionManager().HasConditionWithStatus(vmi, virtv1.VirtualMachineInstanceIsMigratable, k8sv1.ConditionTrue) {
-			nonMigrateable = append(nonMigrateable, vmi)
+		// can't migrate, only worth a warning if the VMI insists on migrating
+		if !migrationutils.IsMigratable(vmi) {
+			if strategy == virtv1.EvictionStrategyLiveMigrate {
+				nonMigrateable = append(nonMigrateable, vmi)
+			}
 			continue
 		}
 		if exists := lookup[vmi.Namespace+"/"+vmi.Name]; !exists &&
@@ -456,6 +460,18 @@ func (c *EvacuationController) filterRunningNonMigratingVMIs(vmis []*virtv1.Virt
 	return migrateable, nonMigrateable
 }
 
+// filterEvacuatingVMIs returns the VMIs which have to leave the node because
+// an eviction of their pod was blocked
+func filterEvacuatingVMIs(vmis []*virtv1.VirtualMachineInstance, nodeName string) []*virtv1.VirtualMachineInstance {
+	evacuating := []*virtv1.VirtualMachineInstance{}
+	for _, vmi := range vmis {
+		if vmi.Status.EvacuationNodeName == nodeName {
+			evacuating = append(evacuating, vmi)
+		}
+	}
+	return evacuating
+}
+
 func nodeHasTaint(taint *k8sv1.Taint, node *k8sv1.Node) bool {
 	for _, t := range node.Spec.Taints {
 		if t.MatchTaint(taint) {
@@ -117,6 +117,28 @@ var _ = Describe("Evacuation", func() {
 
 			controller.Execute()
 		})
+
+		It("should migrate VMIs whose pod eviction was blocked", func() {
+			node := newNode("testnode")
+			addNode(node)
+
+			vmi := newVirtualMachine("testvm", node.Name)
+			vmi.Spec.EvictionStrategy = newEvictionStrategy()
+			vmi.Status.EvacuationNodeName = node.Name
+			vmiFeeder.Add(vmi)
+
+			vmi1 := newVirtualMachine("testvm1", node.Name)
+			vmi1.Spec.EvictionStrategy = newEvictionStrategy()
+			vmiFeeder.Add(vmi1)
+
+			migrationInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(migration *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+				Expect(migration.Spec.VMIName).To(Equal("testvm"))
+				return &v1.VirtualMachineInstanceMigration{ObjectMeta: v13.ObjectMeta{Name: "something"}}, nil
+			})
+
+			controller.Execute()
+			testutils.ExpectEvent(recorder, evacuation.SuccessfulCreateVirtualMachineInstanceMigrationReason)
+		})
 	})
 
 	Context("node eviction in progress", func() {
@@ -162,6 +184,20 @@ var _ = Describe("Evacuation", func() {
 			)
 		})
 
+		It("should leave non-migratable VMIs alone which only migrate if possible", func() {
+			node := newNode("testnode")
+			node.Spec.Taints = append(node.Spec.Taints, *newTaint())
+			addNode(node)
+
+			strategy := v1.EvictionStrategyLiveMigrateIfPossible
+			vmi := newVirtualMachine("testvm", node.Name)
+			vmi.Spec.EvictionStrategy = &strategy
+			vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{{Type: v1.VirtualMachineInstanceIsMigratable, Status: v12.ConditionFalse}}
+			vmiFeeder.Add(vmi)
+
+			controller.Execute()
+		})
+
 		It("should not evict VMIs if 5 migrations are in progress", func() {
 			node := newNode("testnode")
 			node.Spec.Taints = append(node.Spec.Taints, *newTaint())
@@ -0,0 +1,670 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	"kubevirt.io/kubevirt/pkg/certificates/triple"
+	"kubevirt.io/kubevirt/pkg/certificates/triple/cert"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/util/types"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	exportserver "kubevirt.io/kubevirt/pkg/virt-exportserver"
+)
+
+const (
+	// SuccessfulCreateExportServerReason is added in an event if the export server of a VirtualMachineExport was created
+	SuccessfulCreateExportServerReason = "SuccessfulCreateExportServer"
+	// FailedCreateExportServerReason is added in an event if the export server of a VirtualMachineExport could not be created
+	FailedCreateExportServerReason = "FailedCreateExportServer"
+	// SuccessfulDeleteExportServerReason is added in an event if the export server was deleted because the VirtualMachine started
+	SuccessfulDeleteExportServerReason = "SuccessfulDeleteExportServer"
+)
+
+const (
+	exportServerName    = "virt-exportserver"
+	exportServerPort    = 8443
+	exportCertDir       = "/etc/virt-exportserver/cert"
+	exportTokenDir      = "/etc/virt-exportserver/token"
+	exportScratchDir    = "/scratch"
+	exportVolumesDir    = "/export-volumes"
+	exportCertDuration  = 30 * 24 * time.Hour
+	exportCAKey         = "ca.crt"
+	exportCertKey       = "tls.crt"
+	exportPrivateKeyKey = "tls.key"
+	exportManifestKey   = "manifest.json"
+)
+
+// exportVolume is a volume of the exported VirtualMachine backed by a PVC
+type exportVolume struct {
+	name    string
+	pvc     *k8sv1.PersistentVolumeClaim
+	isBlock bool
+}
+
+type ExportController struct {
+	clientset        kubecli.KubevirtClient
+	Queue            workqueue.RateLimitingInterface
+	vmExportInformer cache.SharedIndexInformer
+	vmInformer       cache.SharedIndexInformer
+	vmiInformer      cache.SharedIndexInformer
+	podInformer      cache.SharedIndexInformer
+	pvcInformer      cache.SharedIndexInformer
+	recorder         record.EventRecorder
+	clusterConfig    *virtconfig.ClusterConfig
+	// the image the export server runs in
+	image string
+}
+
+func NewExportController(
+	vmExportInformer cache.SharedIndexInformer,
+	vmInformer cache.SharedIndexInformer,
+	vmiInformer cache.SharedIndexInformer,
+	podInformer cache.SharedIndexInformer,
+	pvcInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+	image string,
+) *ExportController {
+
+	c := &ExportController{
+		clientset:        clientset,
+		Queue:            workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmExportInformer: vmExportInformer,
+		vmInformer:       vmInformer,
+		vmiInformer:      vmiInformer,
+		podInformer:      podInformer,
+		pvcInformer:      pvcInformer,
+		recorder:         recorder,
+		clusterConfig:    clusterConfig,
+		image:            image,
+	}
+
+	c.vmExportInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMExport,
+		DeleteFunc: c.enqueueVMExport,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMExport(curr) },
+	})
+	sourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMExportsForSource,
+		DeleteFunc: c.enqueueVMExportsForSource,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMExportsForSource(curr) },
+	}
+	c.vmInformer.AddEventHandler(sourceHandler)
+	c.vmiInformer.AddEventHandler(sourceHandler)
+	c.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMExportForPod,
+		DeleteFunc: c.enqueueVMExportForPod,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMExportForPod(curr) },
+	})
+
+	return c
+}
+
+func (c *ExportController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting export controller.")
+
+	// Wait for cache sync before we start the export controller
+	cache.WaitForCacheSync(stopCh, c.vmExportInformer.HasSynced, c.vmInformer.HasSynced, c.vmiInformer.HasSynced, c.podInformer.HasSynced, c.pvcInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping export controller.")
+}
+
+func (c *ExportController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *ExportController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing VirtualMachineExport %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed VirtualMachineExport %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *ExportController) execute(key string) error {
+	obj, exists, err := c.vmExportInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+
+	// The secret, service and pod of the export are garbage collected through their owner reference
+	if !exists {
+		return nil
+	}
+	export := obj.(*virtv1.VirtualMachineExport)
+	if export.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if !c.clusterConfig.VMExportEnabled() {
+		return c.updatePendingStatus(export, "the VMExport feature gate is not enabled")
+	}
+
+	obj, exists, err = c.vmInformer.GetStore().GetByKey(export.Namespace + "/" + export.Spec.Source)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return c.updatePendingStatus(export, fmt.Sprintf("VirtualMachine %s does not exist", export.Spec.Source))
+	}
+	vm := obj.(*virtv1.VirtualMachine)
+
+	obj, exists, err = c.vmiInformer.GetStore().GetByKey(export.Namespace + "/" + export.Spec.Source)
+	if err != nil {
+		return err
+	}
+	if exists && !obj.(*virtv1.VirtualMachineInstance).IsFinal() {
+		// The volumes are only consistent while the VirtualMachine is stopped
+		if err := c.deleteExportServer(export); err != nil {
+			return err
+		}
+		return c.updatePendingStatus(export, fmt.Sprintf("VirtualMachine %s is running", vm.Name))
+	}
+
+	volumes, msg, err := c.exportVolumes(vm)
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		return c.updatePendingStatus(export, msg)
+	}
+
+	secret, err := c.ensureSecret(export, vm, volumes)
+	if err != nil {
+		return err
+	}
+	if err := c.ensureService(export); err != nil {
+		return err
+	}
+	if err := c.ensureScratchPVC(export, volumes); err != nil {
+		return err
+	}
+	pod, err := c.ensurePod(export, volumes)
+	if err != nil {
+		return err
+	}
+
+	exportCopy := export.DeepCopy()
+	exportCopy.Status.ServiceName = exportServerObjectName(export)
+	exportCopy.Status.Cert = string(secret.Data[exportCAKey])
+	baseURL := exportServerBaseURL(export)
+	exportCopy.Status.Manifest = baseURL + exportserver.ManifestPath
+	exportCopy.Status.Volumes = nil
+	for _, volume := range volumes {
+		exportCopy.Status.Volumes = append(exportCopy.Status.Volumes, virtv1.VirtualMachineExportVolume{
+			Name: volume.name,
+			Formats: []virtv1.VirtualMachineExportVolumeFormat{
+				{Format: virtv1.ExportVolumeFormatRaw, Url: baseURL + exportserver.RawPath(volume.name)},
+				{Format: virtv1.ExportVolumeFormatQcow2, Url: baseURL + exportserver.Qcow2Path(volume.name)},
+			},
+		})
+	}
+	if isExportServerReady(pod) {
+		exportCopy.Status.Phase = virtv1.VirtualMachineExportReady
+		exportCopy.Status.Message = ""
+	} else {
+		exportCopy.Status.Phase = virtv1.VirtualMachineExportPending
+		exportCopy.Status.Message = "waiting for the export server to become ready"
+	}
+	return c.updateStatus(export, exportCopy)
+}
+
+func (c *ExportController) updatePendingStatus(export *virtv1.VirtualMachineExport, msg string) error {
+	exportCopy := export.DeepCopy()
+	exportCopy.Status = virtv1.VirtualMachineExportStatus{
+		Phase:   virtv1.VirtualMachineExportPending,
+		Message: msg,
+	}
+	return c.updateStatus(export, exportCopy)
+}
+
+func (c *ExportController) updateStatus(export *virtv1.VirtualMachineExport, exportCopy *virtv1.VirtualMachineExport) error {
+	if equality.Semantic.DeepEqual(export.Status, exportCopy.Status) {
+		return nil
+	}
+	_, err := c.clientset.VirtualMachineExport(export.Namespace).UpdateStatus(exportCopy)
+	return err
+}
+
+// exportVolumes returns the PVC backed volumes of the VirtualMachine, or a
+// message why they can not be exported yet
+func (c *ExportController) exportVolumes(vm *virtv1.VirtualMachine) ([]exportVolume, string, error) {
+	var volumes []exportVolume
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		var claimName string
+		if volume.PersistentVolumeClaim != nil {
+			claimName = volume.PersistentVolumeClaim.ClaimName
+		} else if volume.DataVolume != nil {
+			claimName = volume.DataVolume.Name
+		} else {
+			continue
+		}
+
+		pvc, exists, isBlock, err := types.IsPVCBlockFromStore(c.pvcInformer.GetStore(), vm.Namespace, claimName)
+		if err != nil {
+			return nil, "", err
+		}
+		if !exists {
+			return nil, fmt.Sprintf("PersistentVolumeClaim %s does not exist", claimName), nil
+		}
+		volumes = append(volumes, exportVolume{name: volume.Name, pvc: pvc, isBlock: isBlock})
+	}
+	if len(volumes) == 0 {
+		return nil, fmt.Sprintf("VirtualMachine %s has no volumes backed by PersistentVolumeClaims", vm.Name), nil
+	}
+	return volumes, "", nil
+}
+
+// ensureSecret creates the certificate of the export server and the import
+// manifest once. The manifest stays stable while the export exists.
+func (c *ExportController) ensureSecret(export *virtv1.VirtualMachineExport, vm *virtv1.VirtualMachine, volumes []exportVolume) (*k8sv1.Secret, error) {
+	name := exportServerObjectName(export)
+	secret, err := c.clientset.CoreV1().Secrets(export.Namespace).Get(name, v1.GetOptions{})
+	if err == nil {
+		return secret, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	caKeyPair, err := triple.NewCA("export.kubevirt.io", exportCertDuration)
+	if err != nil {
+		return nil, err
+	}
+	keyPair, err := triple.NewServerKeyPair(caKeyPair, name+"."+export.Namespace+".svc", name, export.Namespace, "cluster.local", nil, nil, exportCertDuration)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(newExportManifest(export, vm, volumes))
+	if err != nil {
+		return nil, err
+	}
+
+	secret = &k8sv1.Secret{
+		ObjectMeta: exportServerObjectMeta(export),
+		Data: map[string][]byte{
+			exportCAKey:         cert.EncodeCertPEM(caKeyPair.Cert),
+			exportCertKey:       cert.EncodeCertPEM(keyPair.Cert),
+			exportPrivateKeyKey: cert.EncodePrivateKeyPEM(keyPair.Key),
+			exportManifestKey:   manifest,
+		},
+	}
+	return c.clientset.CoreV1().Secrets(export.Namespace).Create(secret)
+}
+
+func (c *ExportController) ensureService(export *virtv1.VirtualMachineExport) error {
+	_, err := c.clientset.CoreV1().Services(export.Namespace).Get(exportServerObjectName(export), v1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	service := &k8sv1.Service{
+		ObjectMeta: exportServerObjectMeta(export),
+		Spec: k8sv1.ServiceSpec{
+			Selector: map[string]string{virtv1.VirtualMachineExportLabel: export.Name},
+			Ports: []k8sv1.ServicePort{{
+				Name:       "https",
+				Protocol:   k8sv1.ProtocolTCP,
+				Port:       443,
+				TargetPort: intstr.FromInt(exportServerPort),
+			}},
+		},
+	}
+	_, err = c.clientset.CoreV1().Services(export.Namespace).Create(service)
+	return err
+}
+
+// ensureScratchPVC creates the claim the export server converts the volumes
+// to qcow2 on. It is large enough to hold all of them at once.
+func (c *ExportController) ensureScratchPVC(export *virtv1.VirtualMachineExport, volumes []exportVolume) error {
+	name := exportScratchPVCName(export)
+	obj, exists, err := c.pvcInformer.GetStore().GetByKey(export.Namespace + "/" + name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if obj.(*k8sv1.PersistentVolumeClaim).DeletionTimestamp != nil {
+			// the claim of the last export server still holds its outdated conversions
+			return fmt.Errorf("waiting for the scratch claim %s to be deleted", name)
+		}
+		return nil
+	}
+
+	size := resource.NewQuantity(0, resource.BinarySI)
+	for _, volume := range volumes {
+		size.Add(volume.pvc.Spec.Resources.Requests[k8sv1.ResourceStorage])
+	}
+	// the metadata of a fully allocated qcow2 image takes less than a percent of its size
+	size.Add(*resource.NewQuantity(size.Value()/100, resource.BinarySI))
+
+	volumeMode := k8sv1.PersistentVolumeFilesystem
+	pvc := &k8sv1.PersistentVolumeClaim{
+		ObjectMeta: exportServerObjectMeta(export),
+		Spec: k8sv1.PersistentVolumeClaimSpec{
+			AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce},
+			VolumeMode:  &volumeMode,
+			Resources: k8sv1.ResourceRequirements{
+				Requests: k8sv1.ResourceList{k8sv1.ResourceStorage: *size},
+			},
+		},
+	}
+	pvc.Name = name
+	_, err = c.clientset.CoreV1().PersistentVolumeClaims(export.Namespace).Create(pvc)
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *ExportController) ensurePod(export *virtv1.VirtualMachineExport, volumes []exportVolume) (*k8sv1.Pod, error) {
+	obj, exists, err := c.podInformer.GetStore().GetByKey(export.Namespace + "/" + exportServerObjectName(export))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return obj.(*k8sv1.Pod), nil
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(export.Namespace).Create(c.newExportServerPod(export, volumes))
+	if err != nil {
+		c.recorder.Eventf(export, k8sv1.EventTypeWarning, FailedCreateExportServerReason, "Error creating export server: %v", err)
+		return nil, err
+	}
+	c.recorder.Eventf(export, k8sv1.EventTypeNormal, SuccessfulCreateExportServerReason, "Created export server: %v", pod.Name)
+	return pod, nil
+}
+
+// deleteExportServer deletes the pod of the export server together with its
+// scratch claim, the qcow2 conversions on it are outdated once the
+// VirtualMachine runs
+func (c *ExportController) deleteExportServer(export *virtv1.VirtualMachineExport) error {
+	scratchName := exportScratchPVCName(export)
+	_, exists, err := c.pvcInformer.GetStore().GetByKey(export.Namespace + "/" + scratchName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err = c.clientset.CoreV1().PersistentVolumeClaims(export.Namespace).Delete(scratchName, &v1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	name := exportServerObjectName(export)
+	_, exists, err = c.podInformer.GetStore().GetByKey(export.Namespace + "/" + name)
+	if err != nil || !exists {
+		return err
+	}
+	err = c.clientset.CoreV1().Pods(export.Namespace).Delete(name, &v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	c.recorder.Eventf(export, k8sv1.EventTypeNormal, SuccessfulDeleteExportServerReason, "Deleted export server: %v", name)
+	return nil
+}
+
+// exportServerBaseURL is the in-cluster URL of the export server service
+func exportServerBaseURL(export *virtv1.VirtualMachineExport) string {
+	return fmt.Sprintf("https://%s.%s.svc", exportServerObjectName(export), export.Namespace)
+}
+
+func (c *ExportController) newExportServerPod(export *virtv1.VirtualMachineExport, volumes []exportVolume) *k8sv1.Pod {
+	secretName := exportServerObjectName(export)
+	command := []string{
+		"/usr/bin/virt-exportserver",
+		"--listen", fmt.Sprintf(":%d", exportServerPort),
+		"--cert-file", filepath.Join(exportCertDir, exportCertKey),
+		"--key-file", filepath.Join(exportCertDir, exportPrivateKeyKey),
+		"--manifest-file", filepath.Join(exportCertDir, exportManifestKey),
+		"--base-url", exportServerBaseURL(export),
+		"--token-file", filepath.Join(exportTokenDir, virtv1.VirtualMachineExportTokenKey),
+		"--scratch-dir", exportScratchDir,
+	}
+	podVolumes := []k8sv1.Volume{
+		{Name: "cert", VolumeSource: k8sv1.VolumeSource{Secret: &k8sv1.SecretVolumeSource{SecretName: secretName}}},
+		{Name: "token", VolumeSource: k8sv1.VolumeSource{Secret: &k8sv1.SecretVolumeSource{SecretName: export.Spec.TokenSecretRef}}},
+		{Name: "scratch", VolumeSource: k8sv1.VolumeSource{PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: exportScratchPVCName(export)}}},
+	}
+	container := k8sv1.Container{
+		Name:            exportServerName,
+		Image:           c.image,
+		ImagePullPolicy: c.clusterConfig.GetImagePullPolicy(),
+		Ports: []k8sv1.ContainerPort{{
+			Name:          "https",
+			ContainerPort: exportServerPort,
+			Protocol:      k8sv1.ProtocolTCP,
+		}},
+		ReadinessProbe: &k8sv1.Probe{
+			Handler: k8sv1.Handler{
+				TCPSocket: &k8sv1.TCPSocketAction{Port: intstr.FromInt(exportServerPort)},
+			},
+			PeriodSeconds: 5,
+		},
+		VolumeMounts: []k8sv1.VolumeMount{
+			{Name: "cert", MountPath: exportCertDir, ReadOnly: true},
+			{Name: "token", MountPath: exportTokenDir, ReadOnly: true},
+			{Name: "scratch", MountPath: exportScratchDir},
+		},
+	}
+
+	for _, volume := range volumes {
+		podVolumes = append(podVolumes, k8sv1.Volume{
+			Name: volume.name,
+			VolumeSource: k8sv1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{
+					ClaimName: volume.pvc.Name,
+					ReadOnly:  true,
+				},
+			},
+		})
+		path := filepath.Join(exportVolumesDir, volume.name)
+		if volume.isBlock {
+			container.VolumeDevices = append(container.VolumeDevices, k8sv1.VolumeDevice{Name: volume.name, DevicePath: path})
+		} else {
+			container.VolumeMounts = append(container.VolumeMounts, k8sv1.VolumeMount{Name: volume.name, MountPath: path, ReadOnly: true})
+			path = filepath.Join(path, "disk.img")
+		}
+		command = append(command, "--volume", volume.name+"="+path)
+	}
+	container.Command = command
+
+	pod := &k8sv1.Pod{
+		ObjectMeta: exportServerObjectMeta(export),
+		Spec: k8sv1.PodSpec{
+			Containers: []k8sv1.Container{container},
+			Volumes:    podVolumes,
+		},
+	}
+	pod.Labels[virtv1.AppLabel] = exportServerName
+	return pod
+}
+
+// newExportManifest returns a copy of the VirtualMachine, which imports the
+// exported volumes from the export server through DataVolumes. The import
+// URLs are relative, the export server resolves them when serving the manifest.
+func newExportManifest(export *virtv1.VirtualMachineExport, vm *virtv1.VirtualMachine, volumes []exportVolume) *virtv1.VirtualMachine {
+	manifest := &virtv1.VirtualMachine{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: virtv1.GroupVersion.String(),
+			Kind:       virtv1.VirtualMachineGroupVersionKind.Kind,
+		},
+		ObjectMeta: v1.ObjectMeta{
+			Name:   vm.Name,
+			Labels: vm.Labels,
+		},
+		Spec: *vm.Spec.DeepCopy(),
+	}
+	manifest.Spec.DataVolumeTemplates = nil
+
+	exported := map[string]exportVolume{}
+	for _, volume := range volumes {
+		exported[volume.name] = volume
+	}
+	for i, volume := range manifest.Spec.Template.Spec.Volumes {
+		exportVolume, isExported := exported[volume.Name]
+		if !isExported {
+			continue
+		}
+		pvc := exportVolume.pvc
+		manifest.Spec.Template.Spec.Volumes[i].VolumeSource = virtv1.VolumeSource{
+			DataVolume: &virtv1.DataVolumeSource{Name: pvc.Name},
+		}
+		manifest.Spec.DataVolumeTemplates = append(manifest.Spec.DataVolumeTemplates, cdiv1.DataVolume{
+			ObjectMeta: v1.ObjectMeta{Name: pvc.Name},
+			Spec: cdiv1.DataVolumeSpec{
+				Source: cdiv1.DataVolumeSource{
+					HTTP: &cdiv1.DataVolumeSourceHTTP{
+						URL:           exportserver.RawPath(volume.Name),
+						SecretRef:     export.Spec.TokenSecretRef,
+						CertConfigMap: export.Name,
+					},
+				},
+				PVC: &k8sv1.PersistentVolumeClaimSpec{
+					AccessModes: pvc.Spec.AccessModes,
+					VolumeMode:  pvc.Spec.VolumeMode,
+					Resources: k8sv1.ResourceRequirements{
+						Requests: k8sv1.ResourceList{
+							k8sv1.ResourceStorage: pvc.Spec.Resources.Requests[k8sv1.ResourceStorage],
+						},
+					},
+				},
+			},
+		})
+	}
+	return manifest
+}
+
+func exportServerObjectName(export *virtv1.VirtualMachineExport) string {
+	return "virt-export-" + export.Name
+}
+
+func exportScratchPVCName(export *virtv1.VirtualMachineExport) string {
+	return exportServerObjectName(export) + "-scratch"
+}
+
+func exportServerObjectMeta(export *virtv1.VirtualMachineExport) v1.ObjectMeta {
+	return v1.ObjectMeta{
+		Name:      exportServerObjectName(export),
+		Namespace: export.Namespace,
+		Labels: map[string]string{
+			virtv1.VirtualMachineExportLabel: export.Name,
+		},
+		OwnerReferences: []v1.OwnerReference{
+			*v1.NewControllerRef(export, virtv1.VirtualMachineExportGroupVersionKind),
+		},
+	}
+}
+
+func isExportServerReady(pod *k8sv1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == k8sv1.PodReady {
+			return condition.Status == k8sv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func (c *ExportController) enqueueVMExport(obj interface{}) {
+	logger := log.Log
+	export := obj.(*virtv1.VirtualMachineExport)
+	key, err := controller.KeyFunc(export)
+	if err != nil {
+		logger.Object(export).Reason(err).Error("Failed to extract key from VirtualMachineExport.")
+	}
+	c.Queue.Add(key)
+}
+
+// enqueueVMExportsForSource enqueues the exports of a VirtualMachine whenever
+// the VirtualMachine or its VirtualMachineInstance change
+func (c *ExportController) enqueueVMExportsForSource(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	source, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	exports, err := c.vmExportInformer.GetIndexer().ByIndex(cache.NamespaceIndex, source.GetNamespace())
+	if err != nil {
+		return
+	}
+	for _, obj := range exports {
+		export := obj.(*virtv1.VirtualMachineExport)
+		if export.Spec.Source == source.GetName() {
+			c.enqueueVMExport(export)
+		}
+	}
+}
+
+func (c *ExportController) enqueueVMExportForPod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*k8sv1.Pod)
+	if !ok {
+		return
+	}
+	if name, isExportServer := pod.Labels[virtv1.VirtualMachineExportLabel]; isExportServer {
+		c.Queue.Add(pod.Namespace + "/" + name)
+	}
+}
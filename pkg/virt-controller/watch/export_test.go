@@ -0,0 +1,315 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"encoding/json"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Export controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var exportInterface *kubecli.MockVirtualMachineExportInterface
+	var virtClient *kubecli.MockKubevirtClient
+	var kubeClient *fake.Clientset
+	var exportInformer cache.SharedIndexInformer
+	var vmInformer cache.SharedIndexInformer
+	var vmiInformer cache.SharedIndexInformer
+	var podInformer cache.SharedIndexInformer
+	var pvcInformer cache.SharedIndexInformer
+	var configMapInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var exportController *ExportController
+
+	const key = k8sv1.NamespaceDefault + "/testexport"
+
+	newExport := func() *v1.VirtualMachineExport {
+		return &v1.VirtualMachineExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testexport",
+				Namespace: k8sv1.NamespaceDefault,
+				UID:       "1234",
+			},
+			Spec: v1.VirtualMachineExportSpec{
+				Source:         "testvm",
+				TokenSecretRef: "token",
+			},
+		}
+	}
+
+	newVM := func() *v1.VirtualMachine {
+		vm := &v1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testvm",
+				Namespace: k8sv1.NamespaceDefault,
+			},
+			Spec: v1.VirtualMachineSpec{
+				Template: &v1.VirtualMachineInstanceTemplateSpec{},
+			},
+		}
+		vm.Spec.Template.Spec.Volumes = []v1.Volume{
+			{Name: "rootdisk", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "rootpvc"}}},
+			{Name: "datadisk", VolumeSource: v1.VolumeSource{DataVolume: &v1.DataVolumeSource{Name: "datadv"}}},
+			{Name: "cloudinit", VolumeSource: v1.VolumeSource{CloudInitNoCloud: &v1.CloudInitNoCloudSource{UserData: "#cloud-config"}}},
+		}
+		return vm
+	}
+
+	newPVC := func(name string, volumeMode k8sv1.PersistentVolumeMode) *k8sv1.PersistentVolumeClaim {
+		return &k8sv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: k8sv1.NamespaceDefault,
+			},
+			Spec: k8sv1.PersistentVolumeClaimSpec{
+				AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce},
+				VolumeMode:  &volumeMode,
+				Resources: k8sv1.ResourceRequirements{
+					Requests: k8sv1.ResourceList{k8sv1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}
+	}
+
+	addObjects := func(informer cache.SharedIndexInformer, objs ...interface{}) {
+		for _, obj := range objs {
+			Expect(informer.GetStore().Add(obj)).To(Succeed())
+		}
+	}
+
+	enableFeatureGate := func() {
+		testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.VMExportGate},
+		})
+	}
+
+	expectStatus := func() *v1.VirtualMachineExportStatus {
+		status := &v1.VirtualMachineExportStatus{}
+		exportInterface.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(export *v1.VirtualMachineExport) (*v1.VirtualMachineExport, error) {
+			*status = export.Status
+			return export, nil
+		})
+		return status
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		exportInterface = kubecli.NewMockVirtualMachineExportInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+		virtClient.EXPECT().VirtualMachineExport(k8sv1.NamespaceDefault).Return(exportInterface).AnyTimes()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		exportInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineExport{})
+		vmInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachine{})
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
+		recorder = record.NewFakeRecorder(100)
+		var config *virtconfig.ClusterConfig
+		config, configMapInformer, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+
+		exportController = NewExportController(exportInformer, vmInformer, vmiInformer, podInformer, pvcInformer,
+			recorder, virtClient, config, "virt-launcher")
+		addObjects(exportInformer, newExport())
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should wait for the feature gate", func() {
+		status := expectStatus()
+		Expect(exportController.execute(key)).To(Succeed())
+		Expect(status.Phase).To(Equal(v1.VirtualMachineExportPending))
+		Expect(status.Message).To(ContainSubstring("feature gate"))
+	})
+
+	Context("with the feature gate enabled", func() {
+
+		BeforeEach(func() {
+			enableFeatureGate()
+		})
+
+		It("should wait for the VirtualMachine", func() {
+			status := expectStatus()
+			Expect(exportController.execute(key)).To(Succeed())
+			Expect(status.Message).To(Equal("VirtualMachine testvm does not exist"))
+		})
+
+		It("should wait for the PersistentVolumeClaims", func() {
+			addObjects(vmInformer, newVM())
+			addObjects(pvcInformer, newPVC("rootpvc", k8sv1.PersistentVolumeFilesystem))
+			status := expectStatus()
+			Expect(exportController.execute(key)).To(Succeed())
+			Expect(status.Message).To(Equal("PersistentVolumeClaim datadv does not exist"))
+		})
+
+		It("should delete the export server while the VirtualMachine is running", func() {
+			addObjects(vmInformer, newVM())
+			vmi := v1.NewMinimalVMI("testvm")
+			vmi.Status.Phase = v1.Running
+			addObjects(vmiInformer, vmi)
+			pod := exportController.newExportServerPod(newExport(), nil)
+			addObjects(podInformer, pod)
+			_, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Create(pod)
+			Expect(err).ToNot(HaveOccurred())
+			scratch := newPVC("virt-export-testexport-scratch", k8sv1.PersistentVolumeFilesystem)
+			addObjects(pvcInformer, scratch)
+			_, err = kubeClient.CoreV1().PersistentVolumeClaims(k8sv1.NamespaceDefault).Create(scratch)
+			Expect(err).ToNot(HaveOccurred())
+
+			status := expectStatus()
+			Expect(exportController.execute(key)).To(Succeed())
+			Expect(status.Message).To(Equal("VirtualMachine testvm is running"))
+			pods, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).List(metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods.Items).To(BeEmpty())
+			pvcs, err := kubeClient.CoreV1().PersistentVolumeClaims(k8sv1.NamespaceDefault).List(metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvcs.Items).To(BeEmpty())
+			testutils.ExpectEvent(recorder, SuccessfulDeleteExportServerReason)
+		})
+
+		Context("with a stopped VirtualMachine", func() {
+
+			BeforeEach(func() {
+				addObjects(vmInformer, newVM())
+				addObjects(pvcInformer, newPVC("rootpvc", k8sv1.PersistentVolumeFilesystem), newPVC("datadv", k8sv1.PersistentVolumeBlock))
+			})
+
+			It("should create the export server and link the volumes", func() {
+				status := expectStatus()
+				Expect(exportController.execute(key)).To(Succeed())
+				testutils.ExpectEvent(recorder, SuccessfulCreateExportServerReason)
+
+				Expect(status.Phase).To(Equal(v1.VirtualMachineExportPending))
+				Expect(status.ServiceName).To(Equal("virt-export-testexport"))
+				Expect(status.Cert).To(ContainSubstring("BEGIN CERTIFICATE"))
+				Expect(status.Manifest).To(Equal("https://virt-export-testexport.default.svc/manifest"))
+				Expect(status.Volumes).To(HaveLen(2))
+				Expect(status.Volumes[0].Name).To(Equal("rootdisk"))
+				Expect(status.Volumes[0].Formats).To(ConsistOf(
+					v1.VirtualMachineExportVolumeFormat{Format: v1.ExportVolumeFormatRaw, Url: "https://virt-export-testexport.default.svc/volumes/rootdisk/disk.img"},
+					v1.VirtualMachineExportVolumeFormat{Format: v1.ExportVolumeFormatQcow2, Url: "https://virt-export-testexport.default.svc/volumes/rootdisk/disk.qcow2"},
+				))
+
+				pod, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Get("virt-export-testexport", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.OwnerReferences[0].UID).To(Equal(newExport().UID))
+				container := pod.Spec.Containers[0]
+				Expect(container.Image).To(Equal("virt-launcher"))
+				Expect(container.Command).To(ContainElement("rootdisk=/export-volumes/rootdisk/disk.img"))
+				Expect(container.Command).To(ContainElement("datadisk=/export-volumes/datadisk"))
+				Expect(container.Command).To(ContainElement("https://virt-export-testexport.default.svc"))
+				Expect(container.VolumeDevices).To(ConsistOf(k8sv1.VolumeDevice{Name: "datadisk", DevicePath: "/export-volumes/datadisk"}))
+				Expect(container.VolumeMounts).To(ContainElement(k8sv1.VolumeMount{Name: "rootdisk", MountPath: "/export-volumes/rootdisk", ReadOnly: true}))
+
+				service, err := kubeClient.CoreV1().Services(k8sv1.NamespaceDefault).Get("virt-export-testexport", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(service.Spec.Selector).To(Equal(map[string]string{v1.VirtualMachineExportLabel: "testexport"}))
+			})
+
+			It("should convert the volumes on a scratch claim large enough for all of them", func() {
+				expectStatus()
+				Expect(exportController.execute(key)).To(Succeed())
+
+				scratch, err := kubeClient.CoreV1().PersistentVolumeClaims(k8sv1.NamespaceDefault).Get("virt-export-testexport-scratch", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(scratch.OwnerReferences[0].UID).To(Equal(newExport().UID))
+				size := scratch.Spec.Resources.Requests[k8sv1.ResourceStorage]
+				Expect(size.Value()).To(Equal(int64(2<<30 + (2<<30)/100)))
+
+				pod, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Get("virt-export-testexport", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pod.Spec.Volumes).To(ContainElement(k8sv1.Volume{
+					Name: "scratch",
+					VolumeSource: k8sv1.VolumeSource{
+						PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "virt-export-testexport-scratch"},
+					},
+				}))
+			})
+
+			It("should wait for the scratch claim of the last export server to be deleted", func() {
+				scratch := newPVC("virt-export-testexport-scratch", k8sv1.PersistentVolumeFilesystem)
+				now := metav1.Now()
+				scratch.DeletionTimestamp = &now
+				addObjects(pvcInformer, scratch)
+
+				Expect(exportController.execute(key)).To(MatchError("waiting for the scratch claim virt-export-testexport-scratch to be deleted"))
+				_, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Get("virt-export-testexport", metav1.GetOptions{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should create a manifest importing the volumes", func() {
+				expectStatus()
+				Expect(exportController.execute(key)).To(Succeed())
+
+				secret, err := kubeClient.CoreV1().Secrets(k8sv1.NamespaceDefault).Get("virt-export-testexport", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				manifest := &v1.VirtualMachine{}
+				Expect(json.Unmarshal(secret.Data["manifest.json"], manifest)).To(Succeed())
+
+				Expect(manifest.Name).To(Equal("testvm"))
+				Expect(manifest.Spec.DataVolumeTemplates).To(HaveLen(2))
+				dataVolume := manifest.Spec.DataVolumeTemplates[0]
+				Expect(dataVolume.Name).To(Equal("rootpvc"))
+				Expect(dataVolume.Spec.Source.HTTP.URL).To(Equal("/volumes/rootdisk/disk.img"))
+				Expect(dataVolume.Spec.Source.HTTP.SecretRef).To(Equal("token"))
+				Expect(dataVolume.Spec.Source.HTTP.CertConfigMap).To(Equal("testexport"))
+				Expect(dataVolume.Spec.PVC.Resources.Requests[k8sv1.ResourceStorage]).To(Equal(resource.MustParse("1Gi")))
+
+				volumes := manifest.Spec.Template.Spec.Volumes
+				Expect(volumes[0].DataVolume.Name).To(Equal("rootpvc"))
+				Expect(volumes[1].DataVolume.Name).To(Equal("datadv"))
+				Expect(volumes[2].CloudInitNoCloud).ToNot(BeNil())
+			})
+
+			It("should become ready with the export server", func() {
+				pod := exportController.newExportServerPod(newExport(), nil)
+				pod.Status.Conditions = []k8sv1.PodCondition{{Type: k8sv1.PodReady, Status: k8sv1.ConditionTrue}}
+				addObjects(podInformer, pod)
+
+				status := expectStatus()
+				Expect(exportController.execute(key)).To(Succeed())
+				Expect(status.Phase).To(Equal(v1.VirtualMachineExportReady))
+				Expect(status.Message).To(BeEmpty())
+			})
+		})
+	})
+})
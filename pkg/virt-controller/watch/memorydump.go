@@ -0,0 +1,334 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	containerdisk "kubevirt.io/kubevirt/pkg/container-disk"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// SuccessfulCreateMemoryDumpPodReason is added in an event if the attach pod of a memory dump was created
+	SuccessfulCreateMemoryDumpPodReason = "SuccessfulCreateMemoryDumpPod"
+	// FailedCreateMemoryDumpPodReason is added in an event if the attach pod of a memory dump could not be created
+	FailedCreateMemoryDumpPodReason = "FailedCreateMemoryDumpPod"
+	// SuccessfulDeleteMemoryDumpPodReason is added in an event if the attach pod of a memory dump was deleted
+	SuccessfulDeleteMemoryDumpPodReason = "SuccessfulDeleteMemoryDumpPod"
+	// FailedMemoryDumpReason is added in an event if a memory dump failed
+	FailedMemoryDumpReason = "FailedMemoryDump"
+)
+
+const (
+	memoryDumpPodName         = "virt-memory-dump"
+	memoryDumpClaimVolumeName = "memory-dump"
+	memoryDumpTimeFormat      = "20060102-150405"
+)
+
+// MemoryDumpController mounts the claim of a memory dump on the node of the
+// VMI through an attach pod, virt-handler takes over once the pod runs.
+type MemoryDumpController struct {
+	clientset     kubecli.KubevirtClient
+	Queue         workqueue.RateLimitingInterface
+	vmiInformer   cache.SharedIndexInformer
+	podInformer   cache.SharedIndexInformer
+	recorder      record.EventRecorder
+	clusterConfig *virtconfig.ClusterConfig
+	// the image providing the container-disk binary the attach pod runs
+	image string
+}
+
+func NewMemoryDumpController(
+	vmiInformer cache.SharedIndexInformer,
+	podInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+	image string,
+) *MemoryDumpController {
+
+	c := &MemoryDumpController{
+		clientset:     clientset,
+		Queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:   vmiInformer,
+		podInformer:   podInformer,
+		recorder:      recorder,
+		clusterConfig: clusterConfig,
+		image:         image,
+	}
+
+	c.vmiInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMI,
+		DeleteFunc: c.enqueueVMI,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMI(curr) },
+	})
+	c.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMIForPod,
+		DeleteFunc: c.enqueueVMIForPod,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMIForPod(curr) },
+	})
+
+	return c
+}
+
+func (c *MemoryDumpController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting memory dump controller.")
+
+	// Wait for cache sync before we start the memory dump controller
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.podInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping memory dump controller.")
+}
+
+func (c *MemoryDumpController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *MemoryDumpController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing memory dump of VirtualMachineInstance %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed memory dump of VirtualMachineInstance %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *MemoryDumpController) execute(key string) error {
+	obj, exists, err := c.vmiInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+
+	// The attach pod is garbage collected through its owner reference
+	if !exists {
+		return nil
+	}
+	vmi := obj.(*virtv1.VirtualMachineInstance)
+
+	obj, exists, err = c.podInformer.GetStore().GetByKey(vmi.Namespace + "/" + memoryDumpPodObjectName(vmi))
+	if err != nil {
+		return err
+	}
+	var pod *k8sv1.Pod
+	if exists {
+		pod = obj.(*k8sv1.Pod)
+	}
+
+	dump := vmi.Status.MemoryDump
+	if dump == nil || vmi.IsFinal() || dump.Phase == virtv1.MemoryDumpCompleted || dump.Phase == virtv1.MemoryDumpFailed {
+		return c.deleteAttachPod(vmi, pod)
+	}
+
+	switch dump.Phase {
+	case virtv1.MemoryDumpPending:
+		if pod == nil {
+			return c.createAttachPod(vmi, dump.ClaimName)
+		}
+		if pod.DeletionTimestamp != nil {
+			// wait for the pod of the previous dump to go away
+			return nil
+		}
+		switch pod.Status.Phase {
+		case k8sv1.PodRunning:
+			vmiCopy := vmi.DeepCopy()
+			vmiCopy.Status.MemoryDump.Phase = virtv1.MemoryDumpInProgress
+			vmiCopy.Status.MemoryDump.AttachPodUID = pod.UID
+			vmiCopy.Status.MemoryDump.FileName = memoryDumpFileName(vmi, time.Now())
+			return c.updateStatus(vmi, vmiCopy)
+		case k8sv1.PodSucceeded, k8sv1.PodFailed:
+			return c.failMemoryDump(vmi, fmt.Sprintf("the memory dump attach pod %s stopped", pod.Name))
+		}
+	case virtv1.MemoryDumpInProgress:
+		if pod == nil || pod.UID != dump.AttachPodUID || pod.Status.Phase != k8sv1.PodRunning {
+			return c.failMemoryDump(vmi, "the memory dump attach pod disappeared")
+		}
+	}
+	return nil
+}
+
+func (c *MemoryDumpController) failMemoryDump(vmi *virtv1.VirtualMachineInstance, msg string) error {
+	c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedMemoryDumpReason, "Memory dump failed: %s", msg)
+	vmiCopy := vmi.DeepCopy()
+	vmiCopy.Status.MemoryDump.Phase = virtv1.MemoryDumpFailed
+	vmiCopy.Status.MemoryDump.Message = msg
+	return c.updateStatus(vmi, vmiCopy)
+}
+
+func (c *MemoryDumpController) updateStatus(vmi *virtv1.VirtualMachineInstance, vmiCopy *virtv1.VirtualMachineInstance) error {
+	if equality.Semantic.DeepEqual(vmi.Status, vmiCopy.Status) {
+		return nil
+	}
+	_, err := c.clientset.VirtualMachineInstance(vmi.Namespace).Update(vmiCopy)
+	return err
+}
+
+func (c *MemoryDumpController) createAttachPod(vmi *virtv1.VirtualMachineInstance, claimName string) error {
+	pod, err := c.clientset.CoreV1().Pods(vmi.Namespace).Create(c.newAttachPod(vmi, claimName))
+	if err != nil {
+		c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedCreateMemoryDumpPodReason, "Error creating memory dump attach pod: %v", err)
+		return err
+	}
+	c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulCreateMemoryDumpPodReason, "Created memory dump attach pod: %v", pod.Name)
+	return nil
+}
+
+func (c *MemoryDumpController) deleteAttachPod(vmi *virtv1.VirtualMachineInstance, pod *k8sv1.Pod) error {
+	if pod == nil || pod.DeletionTimestamp != nil {
+		return nil
+	}
+	err := c.clientset.CoreV1().Pods(vmi.Namespace).Delete(pod.Name, &v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulDeleteMemoryDumpPodReason, "Deleted memory dump attach pod: %v", pod.Name)
+	return nil
+}
+
+// newAttachPod returns the pod which mounts the claim of the memory dump on
+// the node of the VMI. It only waits on a socket, which lets virt-handler
+// find its mount namespace.
+func (c *MemoryDumpController) newAttachPod(vmi *virtv1.VirtualMachineInstance, claimName string) *k8sv1.Pod {
+	resources := k8sv1.ResourceList{
+		k8sv1.ResourceCPU:    resource.MustParse("10m"),
+		k8sv1.ResourceMemory: resource.MustParse("40M"),
+	}
+	return &k8sv1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      memoryDumpPodObjectName(vmi),
+			Namespace: vmi.Namespace,
+			Labels: map[string]string{
+				virtv1.AppLabel:        memoryDumpPodName,
+				virtv1.MemoryDumpLabel: vmi.Name,
+			},
+			// Not a controller reference, the VMI controller would take the
+			// pod for the virt-launcher pod otherwise
+			OwnerReferences: []v1.OwnerReference{{
+				APIVersion: virtv1.VirtualMachineInstanceGroupVersionKind.GroupVersion().String(),
+				Kind:       virtv1.VirtualMachineInstanceGroupVersionKind.Kind,
+				Name:       vmi.Name,
+				UID:        vmi.UID,
+			}},
+		},
+		Spec: k8sv1.PodSpec{
+			NodeName:      vmi.Status.NodeName,
+			RestartPolicy: k8sv1.RestartPolicyNever,
+			Containers: []k8sv1.Container{{
+				Name:            memoryDumpPodName,
+				Image:           c.image,
+				ImagePullPolicy: c.clusterConfig.GetImagePullPolicy(),
+				Command:         []string{"/usr/bin/container-disk"},
+				Args:            []string{"--copy-path", containerdisk.MemoryDumpCopyPath},
+				Resources: k8sv1.ResourceRequirements{
+					Limits:   resources,
+					Requests: resources,
+				},
+				VolumeMounts: []k8sv1.VolumeMount{
+					{Name: memoryDumpClaimVolumeName, MountPath: containerdisk.MemoryDumpClaimDir},
+					{Name: containerdisk.MemoryDumpSocketVolumeName, MountPath: containerdisk.MemoryDumpSocketDir},
+				},
+			}},
+			Volumes: []k8sv1.Volume{
+				{
+					Name: memoryDumpClaimVolumeName,
+					VolumeSource: k8sv1.VolumeSource{
+						PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+				{
+					Name:         containerdisk.MemoryDumpSocketVolumeName,
+					VolumeSource: k8sv1.VolumeSource{EmptyDir: &k8sv1.EmptyDirVolumeSource{}},
+				},
+			},
+		},
+	}
+}
+
+func memoryDumpPodObjectName(vmi *virtv1.VirtualMachineInstance) string {
+	return memoryDumpPodName + "-" + vmi.Name
+}
+
+// memoryDumpFileName returns a unique name for every dump, so that several
+// dumps can go to the same claim
+func memoryDumpFileName(vmi *virtv1.VirtualMachineInstance, now time.Time) string {
+	return fmt.Sprintf("%s-memory-dump-%s.memory.dump", vmi.Name, now.UTC().Format(memoryDumpTimeFormat))
+}
+
+func (c *MemoryDumpController) enqueueVMI(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	vmi, ok := obj.(*virtv1.VirtualMachineInstance)
+	if !ok {
+		return
+	}
+	key, err := controller.KeyFunc(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to extract key from VirtualMachineInstance.")
+		return
+	}
+	c.Queue.Add(key)
+}
+
+func (c *MemoryDumpController) enqueueVMIForPod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*k8sv1.Pod)
+	if !ok {
+		return
+	}
+	if name, isAttachPod := pod.Labels[virtv1.MemoryDumpLabel]; isAttachPod {
+		c.Queue.Add(pod.Namespace + "/" + name)
+	}
+}
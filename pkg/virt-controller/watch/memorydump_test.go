@@ -0,0 +1,160 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("Memory dump controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var kubeClient *fake.Clientset
+	var vmiInformer cache.SharedIndexInformer
+	var podInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var memoryDumpController *MemoryDumpController
+
+	const key = k8sv1.NamespaceDefault + "/testvmi"
+
+	newVMI := func(phase v1.MemoryDumpPhase) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.UID = "1234"
+		vmi.Status.Phase = v1.Running
+		vmi.Status.NodeName = "node01"
+		vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{
+			ClaimName: "dump",
+			Phase:     phase,
+		}
+		return vmi
+	}
+
+	newPod := func(vmi *v1.VirtualMachineInstance, phase k8sv1.PodPhase) *k8sv1.Pod {
+		pod := memoryDumpController.newAttachPod(vmi, "dump")
+		pod.UID = "5678"
+		pod.Status.Phase = phase
+		Expect(podInformer.GetStore().Add(pod)).To(Succeed())
+		_, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).Create(pod)
+		Expect(err).ToNot(HaveOccurred())
+		return pod
+	}
+
+	expectUpdate := func() *v1.VirtualMachineInstanceMemoryDumpStatus {
+		status := &v1.VirtualMachineInstanceMemoryDumpStatus{}
+		vmiInterface.EXPECT().Update(gomock.Any()).DoAndReturn(func(vmi *v1.VirtualMachineInstance) (*v1.VirtualMachineInstance, error) {
+			*status = *vmi.Status.MemoryDump
+			return vmi, nil
+		})
+		return status
+	}
+
+	listPods := func() []k8sv1.Pod {
+		pods, err := kubeClient.CoreV1().Pods(k8sv1.NamespaceDefault).List(metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return pods.Items
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		kubeClient = fake.NewSimpleClientset()
+		virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		recorder = record.NewFakeRecorder(100)
+		config, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+
+		memoryDumpController = NewMemoryDumpController(vmiInformer, podInformer, recorder, virtClient, config, "virt-launcher")
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should create the attach pod on the node of the VMI", func() {
+		Expect(vmiInformer.GetStore().Add(newVMI(v1.MemoryDumpPending))).To(Succeed())
+
+		Expect(memoryDumpController.execute(key)).To(Succeed())
+		pods := listPods()
+		Expect(pods).To(HaveLen(1))
+		Expect(pods[0].Name).To(Equal("virt-memory-dump-testvmi"))
+		Expect(pods[0].Spec.NodeName).To(Equal("node01"))
+		Expect(pods[0].Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("dump"))
+		Expect(pods[0].OwnerReferences[0].Controller).To(BeNil())
+		testutils.ExpectEvent(recorder, SuccessfulCreateMemoryDumpPodReason)
+	})
+
+	It("should hand the dump over to virt-handler once the attach pod runs", func() {
+		vmi := newVMI(v1.MemoryDumpPending)
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+		newPod(vmi, k8sv1.PodRunning)
+
+		status := expectUpdate()
+		Expect(memoryDumpController.execute(key)).To(Succeed())
+		Expect(status.Phase).To(Equal(v1.MemoryDumpInProgress))
+		Expect(string(status.AttachPodUID)).To(Equal("5678"))
+		Expect(status.FileName).To(HavePrefix("testvmi-memory-dump-"))
+	})
+
+	It("should fail the dump if the attach pod goes away", func() {
+		vmi := newVMI(v1.MemoryDumpInProgress)
+		vmi.Status.MemoryDump.AttachPodUID = "5678"
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+
+		status := expectUpdate()
+		Expect(memoryDumpController.execute(key)).To(Succeed())
+		Expect(status.Phase).To(Equal(v1.MemoryDumpFailed))
+		testutils.ExpectEvent(recorder, FailedMemoryDumpReason)
+	})
+
+	It("should delete the attach pod once the dump completed", func() {
+		vmi := newVMI(v1.MemoryDumpCompleted)
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+		newPod(vmi, k8sv1.PodRunning)
+
+		Expect(memoryDumpController.execute(key)).To(Succeed())
+		Expect(listPods()).To(BeEmpty())
+		testutils.ExpectEvent(recorder, SuccessfulDeleteMemoryDumpPodReason)
+	})
+
+	It("should name every dump after the VMI and the time it started", func() {
+		now := time.Date(2020, 7, 1, 12, 30, 5, 0, time.UTC)
+		Expect(memoryDumpFileName(newVMI(v1.MemoryDumpPending), now)).To(Equal("testvmi-memory-dump-20200701-123005.memory.dump"))
+	})
+})
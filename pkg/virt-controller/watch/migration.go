@@ -23,9 +23,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	k8sv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -48,6 +51,22 @@ import (
 	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 )
 
+var (
+	migrationQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_migration_queue_depth",
+			Help: "Number of pending migrations waiting for a free migration slot",
+		},
+	)
+	migrationQueueWaitSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_migration_queue_wait_seconds",
+			Help:    "Time pending migrations waited for a free migration slot",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+)
+
 type MigrationController struct {
 	templateService    services.TemplateService
 	clientset          kubecli.KubevirtClient
@@ -55,6 +74,7 @@ type MigrationController struct {
 	vmiInformer        cache.SharedIndexInformer
 	podInformer        cache.SharedIndexInformer
 	migrationInformer  cache.SharedIndexInformer
+	nodeInformer       cache.SharedIndexInformer
 	recorder           record.EventRecorder
 	podExpectations    *controller.UIDTrackingControllerExpectations
 	migrationStartLock *sync.Mutex
@@ -66,6 +86,7 @@ func NewMigrationController(templateService services.TemplateService,
 	vmiInformer cache.SharedIndexInformer,
 	podInformer cache.SharedIndexInformer,
 	migrationInformer cache.SharedIndexInformer,
+	nodeInformer cache.SharedIndexInformer,
 	recorder record.EventRecorder,
 	clientset kubecli.KubevirtClient,
 	clusterConfig *virtconfig.ClusterConfig,
@@ -77,6 +98,7 @@ func NewMigrationController(templateService services.TemplateService,
 		vmiInformer:        vmiInformer,
 		podInformer:        podInformer,
 		migrationInformer:  migrationInformer,
+		nodeInformer:       nodeInformer,
 		recorder:           recorder,
 		clientset:          clientset,
 		podExpectations:    controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
@@ -112,7 +134,7 @@ func (c *MigrationController) Run(threadiness int, stopCh <-chan struct{}) {
 	log.Log.Info("Starting migration controller.")
 
 	// Wait for cache sync before we start the pod controller
-	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.podInformer.HasSynced, c.migrationInformer.HasSynced)
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.podInformer.HasSynced, c.migrationInformer.HasSynced, c.nodeInformer.HasSynced)
 
 	// Start the actual work
 	for i := 0; i < threadiness; i++ {
@@ -323,14 +345,35 @@ func (c *MigrationController) updateStatus(migration *virtv1.VirtualMachineInsta
 				return err
 			}
 
-			if canMigrate {
-				migrationCopy.Status.Phase = virtv1.MigrationPending
-			} else {
+			if !canMigrate {
 				// can not migrate because there is an active migration already
 				// in progress for this VMI.
 				migrationCopy.Status.Phase = virtv1.MigrationFailed
 				c.recorder.Eventf(migration, k8sv1.EventTypeWarning, FailedMigrationReason, "VMI is not eligible for migration because another migration job is in progress.")
 				log.Log.Object(migration).Error("Migration object ont eligible for migration because another job is in progress")
+				break
+			}
+
+			compatible, reason, err := c.hasCPUCompatibleTargetNode(vmi)
+			if err != nil {
+				return err
+			}
+
+			if compatible {
+				migrationCopy.Status.Phase = virtv1.MigrationPending
+			} else {
+				// refuse to create a target pod which can only end up on
+				// a node which can't run the CPU of the VMI
+				migrationCopy.Status.Phase = virtv1.MigrationFailed
+				migrationCopy.Status.Conditions = append(migrationCopy.Status.Conditions, virtv1.VirtualMachineInstanceMigrationCondition{
+					Type:          virtv1.VirtualMachineInstanceMigrationCPUIncompatible,
+					Status:        k8sv1.ConditionTrue,
+					LastProbeTime: v1.Now(),
+					Reason:        NoCPUCompatibleNodeReason,
+					Message:       reason,
+				})
+				c.recorder.Eventf(migration, k8sv1.EventTypeWarning, FailedMigrationReason, "Migration failed: %s", reason)
+				log.Log.Object(migration).Errorf("Migration object not eligible for migration: %s", reason)
 			}
 		case virtv1.MigrationPending:
 			if podExists {
@@ -411,6 +454,12 @@ func (c *MigrationController) createTargetPod(migration *virtv1.VirtualMachineIn
 	// TODO libvirt requires unique host names for each target and source
 	templatePod.Spec.Hostname = ""
 
+	if c.clusterConfig.VMPreemptionEnabled() {
+		// a migration must never squeeze the vmi onto a node by preempting other workloads
+		preemptNever := k8sv1.PreemptNever
+		templatePod.Spec.PreemptionPolicy = &preemptNever
+	}
+
 	key := controller.MigrationKey(migration)
 	c.podExpectations.ExpectCreations(key, 1)
 	pod, err := c.clientset.CoreV1().Pods(vmi.GetNamespace()).Create(templatePod)
@@ -501,30 +550,68 @@ func (c *MigrationController) sync(key string, migration *virtv1.VirtualMachineI
 				return fmt.Errorf("failed to determin the number of running migrations: %v", err)
 			}
 
-			// XXX: Make this configurable, think about limit per node, bandwidth per migration, and so on.
+			waitingMigrations, err := c.findWaitingMigrations(runningMigrations)
+			if err != nil {
+				return fmt.Errorf("failed to determine the waiting migrations: %v", err)
+			}
+			migrationQueueDepth.Set(float64(len(waitingMigrations)))
+
 			if len(runningMigrations) >= int(*c.clusterConfig.GetMigrationConfiguration().ParallelMigrationsPerCluster) {
 				// Let's wait until some migrations are done
 				c.Queue.AddAfter(key, time.Second*5)
 				return nil
 			}
 
-			outboundMigrations, err := c.outboundMigrationsOnNode(vmi.Status.NodeName, runningMigrations)
-
+			canStart, err := c.canStartOnSourceNode(vmi.Status.NodeName, runningMigrations)
 			if err != nil {
 				return err
 			}
-
-			if outboundMigrations >= int(*c.clusterConfig.GetMigrationConfiguration().ParallelOutboundMigrationsPerNode) {
-				// Let's ensure that we only have two outbound migrations per node
-				// XXX: Make this configurebale, thinkg about inbout migration limit, bandwidh per migration, and so on.
+			if !canStart {
+				// Let's wait until some outbound migrations of the node are done
 				c.Queue.AddAfter(key, time.Second*5)
 				return nil
 			}
 
+			// Free slots go to the migrations waiting longest, unless the
+			// source node of such a migration has no slot left for it
+			migratingVMIs := map[string]bool{}
+			for _, running := range runningMigrations {
+				migratingVMIs[running.Namespace+"/"+running.Spec.VMIName] = true
+			}
+			for _, waiting := range waitingMigrations {
+				if !waitsLonger(waiting, migration) {
+					break
+				}
+				vmiKey := waiting.Namespace + "/" + waiting.Spec.VMIName
+				if migratingVMIs[vmiKey] {
+					continue
+				}
+				obj, exists, err := c.vmiInformer.GetStore().GetByKey(vmiKey)
+				if err != nil {
+					return err
+				}
+				if !exists || !obj.(*virtv1.VirtualMachineInstance).IsRunning() {
+					continue
+				}
+				canStart, err := c.canStartOnSourceNode(obj.(*virtv1.VirtualMachineInstance).Status.NodeName, runningMigrations)
+				if err != nil {
+					return err
+				}
+				if canStart {
+					c.enqueueMigration(waiting)
+					c.Queue.AddAfter(key, time.Second*5)
+					return nil
+				}
+			}
+
 			// migration was accepted into the system, now see if we
 			// should create the target pod
 			if vmi.IsRunning() {
-				return c.createTargetPod(migration, vmi)
+				if err := c.createTargetPod(migration, vmi); err != nil {
+					return err
+				}
+				migrationQueueDepth.Set(float64(len(waitingMigrations) - 1))
+				migrationQueueWaitSeconds.Observe(time.Since(migration.CreationTimestamp.Time).Seconds())
 			}
 			return nil
 		}()
@@ -532,6 +619,19 @@ func (c *MigrationController) sync(key string, migration *virtv1.VirtualMachineI
 		// once target pod is scheduled, alert the VMI of the migration by
 		// setting the target and source nodes. This kicks off the preparation stage.
 		if podExists && !podIsDown(pod) {
+			handedOff := vmi.Status.MigrationState != nil && vmi.Status.MigrationState.MigrationUID == migration.UID
+			if !handedOff {
+				inboundMigrations, err := c.inboundMigrationsOnNode(pod.Spec.NodeName)
+				if err != nil {
+					return err
+				}
+				if inboundMigrations >= int(*c.clusterConfig.GetMigrationConfiguration().ParallelInboundMigrationsPerNode) {
+					// Let's wait until some inbound migrations of the target node are done
+					c.Queue.AddAfter(key, time.Second*5)
+					return nil
+				}
+			}
+
 			vmiCopy := vmi.DeepCopy()
 			vmiCopy.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{
 				MigrationUID: migration.UID,
@@ -837,6 +937,38 @@ func (c *MigrationController) deleteVMI(obj interface{}) {
 	}
 }
 
+func (c *MigrationController) canStartOnSourceNode(node string, runningMigrations []*virtv1.VirtualMachineInstanceMigration) (bool, error) {
+	outboundMigrations, err := c.outboundMigrationsOnNode(node, runningMigrations)
+	if err != nil {
+		return false, err
+	}
+	return outboundMigrations < int(*c.clusterConfig.GetMigrationConfiguration().ParallelOutboundMigrationsPerNode), nil
+}
+
+// inboundMigrationsOnNode counts the unfinished migrations which were handed
+// over to the virt-handler of the target node
+func (c *MigrationController) inboundMigrationsOnNode(node string) (int, error) {
+	notFinishedMigrations, err := migrations.ListUnfinishedMigrations(c.migrationInformer)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0
+	for _, migration := range notFinishedMigrations {
+		obj, exists, err := c.vmiInformer.GetStore().GetByKey(migration.Namespace + "/" + migration.Spec.VMIName)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			continue
+		}
+		state := obj.(*virtv1.VirtualMachineInstance).Status.MigrationState
+		if state != nil && state.MigrationUID == migration.UID && state.TargetNode == node && !state.Completed {
+			sum = sum + 1
+		}
+	}
+	return sum, nil
+}
+
 func (c *MigrationController) outboundMigrationsOnNode(node string, runningMigrations []*virtv1.VirtualMachineInstanceMigration) (int, error) {
 	sum := 0
 	for _, migration := range runningMigrations {
@@ -849,6 +981,49 @@ func (c *MigrationController) outboundMigrationsOnNode(node string, runningMigra
 	return sum, nil
 }
 
+// hasCPUCompatibleTargetNode checks the node labeller data to see whether any schedulable node, apart from
+// the current one, provides the CPU model and features the VMI requires. If not, the returned reason explains why.
+func (c *MigrationController) hasCPUCompatibleTargetNode(vmi *virtv1.VirtualMachineInstance) (bool, string, error) {
+	if !c.clusterConfig.CPUNodeDiscoveryEnabled() {
+		// without the node labeller there is nothing to compare against
+		return true, "", nil
+	}
+
+	requiredLabels := services.CPUFeatureLabelsFromCPUFeatures(vmi)
+	if cpuModelLabel, err := services.CPUModelLabelFromCPUModel(vmi); err == nil {
+		if vmi.Spec.Domain.CPU.Model != virtv1.CPUModeHostModel && vmi.Spec.Domain.CPU.Model != virtv1.CPUModeHostPassthrough {
+			requiredLabels = append(requiredLabels, cpuModelLabel)
+		}
+	}
+	if len(requiredLabels) == 0 {
+		return true, "", nil
+	}
+
+	for _, obj := range c.nodeInformer.GetStore().List() {
+		node := obj.(*k8sv1.Node)
+		if node.Name == vmi.Status.NodeName || node.Labels[virtv1.NodeSchedulable] != "true" {
+			continue
+		}
+		compatible := true
+		for _, label := range requiredLabels {
+			if node.Labels[label] != "true" {
+				compatible = false
+				break
+			}
+		}
+		if compatible {
+			return true, "", nil
+		}
+	}
+
+	var required []string
+	for _, label := range requiredLabels {
+		required = append(required, strings.TrimPrefix(label, "feature.node.kubernetes.io/"))
+	}
+	sort.Strings(required)
+	return false, fmt.Sprintf("no schedulable node other than %s provides the required CPU model and features: %s", vmi.Status.NodeName, strings.Join(required, ", ")), nil
+}
+
 // findRunningMigrations calcules how many migrations are running or in flight to be triggered to running
 // Migrations which are in running phase are added alongside with migrations which are still pending but
 // where we already see a target pod.
@@ -884,3 +1059,43 @@ func (c *MigrationController) findRunningMigrations() ([]*virtv1.VirtualMachineI
 	}
 	return runningMigrations, nil
 }
+
+// findWaitingMigrations returns the pending migrations which wait for a free
+// migration slot, the ones waiting longest first
+func (c *MigrationController) findWaitingMigrations(runningMigrations []*virtv1.VirtualMachineInstanceMigration) ([]*virtv1.VirtualMachineInstanceMigration, error) {
+	notFinishedMigrations, err := migrations.ListUnfinishedMigrations(c.migrationInformer)
+	if err != nil {
+		return nil, err
+	}
+
+	running := map[types.UID]bool{}
+	for _, migration := range runningMigrations {
+		running[migration.UID] = true
+	}
+
+	var waitingMigrations []*virtv1.VirtualMachineInstanceMigration
+	for _, migration := range notFinishedMigrations {
+		if running[migration.UID] || migration.DeletionTimestamp != nil {
+			continue
+		}
+		if migration.Status.Phase != virtv1.MigrationPhaseUnset && migration.Status.Phase != virtv1.MigrationPending {
+			continue
+		}
+		waitingMigrations = append(waitingMigrations, migration)
+	}
+	sort.Slice(waitingMigrations, func(i, j int) bool {
+		return waitsLonger(waitingMigrations[i], waitingMigrations[j])
+	})
+	return waitingMigrations, nil
+}
+
+// waitsLonger orders migrations by their creation, names break ties
+func waitsLonger(a *virtv1.VirtualMachineInstanceMigration, b *virtv1.VirtualMachineInstanceMigration) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -21,6 +21,7 @@ package watch
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -42,6 +43,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 	"kubevirt.io/kubevirt/pkg/virt-controller/services"
 )
 
@@ -57,6 +59,7 @@ var _ = Describe("Migration watcher", func() {
 	var vmiInformer cache.SharedIndexInformer
 	var podInformer cache.SharedIndexInformer
 	var migrationInformer cache.SharedIndexInformer
+	var nodeInformer cache.SharedIndexInformer
 	var stop chan struct{}
 	var controller *MigrationController
 	var recorder *record.FakeRecorder
@@ -166,6 +169,7 @@ var _ = Describe("Migration watcher", func() {
 		vmiInformer, vmiSource = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
 		migrationInformer, migrationSource = testutils.NewFakeInformerFor(&v1.VirtualMachineInstanceMigration{})
 		podInformer, podSource = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		nodeInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Node{})
 		recorder = record.NewFakeRecorder(100)
 
 		pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
@@ -176,6 +180,7 @@ var _ = Describe("Migration watcher", func() {
 			vmiInformer,
 			podInformer,
 			migrationInformer,
+			nodeInformer,
 			recorder,
 			virtClient,
 			config,
@@ -220,6 +225,76 @@ var _ = Describe("Migration watcher", func() {
 		mockQueue.Wait()
 	}
 
+	Context("Migration object in unset state", func() {
+		newVMIWithCPU := func() *v1.VirtualMachineInstance {
+			vmi := newVirtualMachine("testvmi", v1.Running)
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Model:    "Haswell",
+				Features: []v1.CPUFeature{{Name: "vmx"}, {Name: "pcid", Policy: "optional"}},
+			}
+			return vmi
+		}
+
+		addNode := func(name string, labels ...string) {
+			node := &k8sv1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{v1.NodeSchedulable: "true"},
+				},
+			}
+			for _, label := range labels {
+				node.Labels[label] = "true"
+			}
+			Expect(nodeInformer.GetStore().Add(node)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			controller.clusterConfig, _, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.CPUNodeDiscoveryGate},
+			})
+		})
+
+		It("should move to pending state if a node provides the CPU of the VMI", func() {
+			vmi := newVMIWithCPU()
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationPhaseUnset)
+			addNode("node01", services.NFD_CPU_MODEL_PREFIX+"Haswell", services.NFD_CPU_FEATURE_PREFIX+"vmx")
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+
+			migrationInterface.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(arg *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+				Expect(arg.Status.Phase).To(Equal(v1.MigrationPending))
+				return arg, nil
+			})
+
+			controller.Execute()
+		})
+
+		It("should fail if no other node provides the CPU of the VMI", func() {
+			vmi := newVMIWithCPU()
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationPhaseUnset)
+			addNode(vmi.Status.NodeName, services.NFD_CPU_MODEL_PREFIX+"Haswell", services.NFD_CPU_FEATURE_PREFIX+"vmx")
+			addNode("node01", services.NFD_CPU_MODEL_PREFIX+"Haswell")
+			addNode("node02", services.NFD_CPU_FEATURE_PREFIX+"vmx")
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+
+			migrationInterface.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(arg *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+				Expect(arg.Status.Phase).To(Equal(v1.MigrationFailed))
+				Expect(arg.Status.Conditions).To(HaveLen(1))
+				Expect(arg.Status.Conditions[0].Type).To(Equal(v1.VirtualMachineInstanceMigrationCPUIncompatible))
+				Expect(arg.Status.Conditions[0].Reason).To(Equal(NoCPUCompatibleNodeReason))
+				Expect(arg.Status.Conditions[0].Message).To(ContainSubstring("cpu-feature-vmx, cpu-model-Haswell"))
+				return arg, nil
+			})
+
+			controller.Execute()
+
+			testutils.ExpectEvent(recorder, FailedMigrationReason)
+		})
+	})
+
 	Context("Migration object in pending state", func() {
 		It("should create target pod", func() {
 			vmi := newVirtualMachine("testvmi", v1.Running)
@@ -234,6 +309,28 @@ var _ = Describe("Migration watcher", func() {
 			testutils.ExpectEvent(recorder, SuccessfulCreatePodReason)
 		})
 
+		It("should create a target pod which never preempts with the VMPreemption feature gate", func() {
+			controller.clusterConfig, _, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.VMPreemptionGate},
+			})
+			vmi := newVirtualMachine("testvmi", v1.Running)
+			vmi.Spec.PriorityClassName = "high"
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationPending)
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+			kubeClient.Fake.PrependReactor("create", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				pod := action.(testing.CreateAction).GetObject().(*k8sv1.Pod)
+				Expect(pod.Spec.PriorityClassName).To(Equal("high"))
+				Expect(*pod.Spec.PreemptionPolicy).To(Equal(k8sv1.PreemptNever))
+				return true, pod, nil
+			})
+
+			controller.Execute()
+
+			testutils.ExpectEvent(recorder, SuccessfulCreatePodReason)
+		})
+
 		It("should create another target pods if only 4 migrations are in progress", func() {
 			// It should create a pod for this one
 			vmi := newVirtualMachine("testvmi", v1.Running)
@@ -363,6 +460,56 @@ var _ = Describe("Migration watcher", func() {
 			controller.Execute()
 		})
 
+		It("should let the migration which waits longest start first", func() {
+			vmi := newVirtualMachine("testvmi", v1.Running)
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationPending)
+			migration.CreationTimestamp = metav1.Now()
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+
+			olderVMI := newVirtualMachine("oldervmi", v1.Running)
+			olderVMI.Status.NodeName = "othernode"
+			olderMigration := newMigration("oldermigration", olderVMI.Name, v1.MigrationPending)
+			olderMigration.CreationTimestamp = metav1.NewTime(migration.CreationTimestamp.Add(-time.Minute))
+
+			addMigration(olderMigration)
+			addVirtualMachineInstance(olderVMI)
+
+			controller.Execute()
+		})
+
+		It("should not wait for older migrations whose node has no free slot", func() {
+			vmi := newVirtualMachine("testvmi", v1.Running)
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationPending)
+			migration.CreationTimestamp = metav1.Now()
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+
+			olderVMI := newVirtualMachine("oldervmi", v1.Running)
+			olderVMI.Status.NodeName = "othernode"
+			olderMigration := newMigration("oldermigration", olderVMI.Name, v1.MigrationPending)
+			olderMigration.CreationTimestamp = metav1.NewTime(migration.CreationTimestamp.Add(-time.Minute))
+
+			addMigration(olderMigration)
+			addVirtualMachineInstance(olderVMI)
+
+			// Ensure that the node of the older migration has no free outbound slot
+			for i := 0; i < 2; i++ {
+				vmi := newVirtualMachine(fmt.Sprintf("testvmi%v", i), v1.Running)
+				vmi.Status.NodeName = "othernode"
+				migration := newMigration(fmt.Sprintf("testmigration%v", i), vmi.Name, v1.MigrationScheduling)
+
+				addMigration(migration)
+				addVirtualMachineInstance(vmi)
+			}
+
+			shouldExpectPodCreation(vmi.UID, migration.UID, 1, 0, 0)
+			controller.Execute()
+			testutils.ExpectEvent(recorder, SuccessfulCreatePodReason)
+		})
+
 		It("should create target pod and not override existing affinity rules", func() {
 			vmi := newVirtualMachine("testvmi", v1.Running)
 			antiAffinityTerm := k8sv1.PodAffinityTerm{
@@ -537,6 +684,33 @@ var _ = Describe("Migration watcher", func() {
 			controller.Execute()
 			testutils.ExpectEvent(recorder, SuccessfulHandOverPodReason)
 		})
+		It("should not hand pod over if the target node has 2 inbound migrations", func() {
+			vmi := newVirtualMachine("testvmi", v1.Running)
+			vmi.Status.NodeName = "node02"
+			migration := newMigration("testmigration", vmi.Name, v1.MigrationScheduled)
+			pod := newTargetPodForVirtualMachine(vmi, migration, k8sv1.PodPending)
+			pod.Spec.NodeName = "node01"
+
+			addMigration(migration)
+			addVirtualMachineInstance(vmi)
+			podFeeder.Add(pod)
+
+			// Ensure that 2 migrations were already handed over to node01
+			for i := 0; i < 2; i++ {
+				vmi := newVirtualMachine(fmt.Sprintf("testvmi%v", i), v1.Running)
+				migration := newMigration(fmt.Sprintf("testmigration%v", i), vmi.Name, v1.MigrationRunning)
+				vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{
+					MigrationUID: migration.UID,
+					TargetNode:   "node01",
+				}
+
+				addMigration(migration)
+				addVirtualMachineInstance(vmi)
+			}
+
+			controller.Execute()
+		})
+
 		It("should hand pod over to target virt-handler with migration config", func() {
 			vmi := newVirtualMachine("testvmi", v1.Running)
 			vmi.Status.NodeName = "node02"
@@ -0,0 +1,363 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// SuccessfulRetryMigrationReason is added in an event if a failed migration was retried
+	SuccessfulRetryMigrationReason = "SuccessfulRetryMigration"
+	// FailedRetryMigrationReason is added in an event if the retry of a failed migration could not be created
+	FailedRetryMigrationReason = "FailedRetryMigration"
+	// MigrationRetriesExhaustedReason is added in an event if a failed migration is not retried anymore
+	MigrationRetriesExhaustedReason = "MigrationRetriesExhausted"
+)
+
+const (
+	migrationRetryBaseBackoff = 10 * time.Second
+	migrationRetryMaxBackoff  = 5 * time.Minute
+	// migrationRetryWindow is how long after its backoff a failed migration is
+	// still retried. Older failures, like the ones found by a controller which
+	// restarted long after them, are left alone. It covers the time a target
+	// pod may stay pending, as such failures only carry their creation time.
+	migrationRetryWindow = 15 * time.Minute
+)
+
+var migrationRetriesCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "kubevirt_migration_retries_total",
+		Help: "Number of migrations created to retry a failed migration",
+	},
+)
+
+// MigrationRetryController creates a new migration for a VMI when its last
+// migration failed, with an exponential backoff between the attempts, until
+// the retry limit of the migration configuration is reached.
+type MigrationRetryController struct {
+	clientset         kubecli.KubevirtClient
+	Queue             workqueue.RateLimitingInterface
+	vmiInformer       cache.SharedIndexInformer
+	migrationInformer cache.SharedIndexInformer
+	recorder          record.EventRecorder
+	clusterConfig     *virtconfig.ClusterConfig
+}
+
+func NewMigrationRetryController(
+	vmiInformer cache.SharedIndexInformer,
+	migrationInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+) *MigrationRetryController {
+
+	c := &MigrationRetryController{
+		clientset:         clientset,
+		Queue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:       vmiInformer,
+		migrationInformer: migrationInformer,
+		recorder:          recorder,
+		clusterConfig:     clusterConfig,
+	}
+
+	c.migrationInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMIForMigration,
+		DeleteFunc: c.enqueueVMIForMigration,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMIForMigration(curr) },
+	})
+
+	return c
+}
+
+func (c *MigrationRetryController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting migration retry controller.")
+
+	// Wait for cache sync before we start the migration retry controller
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.migrationInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping migration retry controller.")
+}
+
+func (c *MigrationRetryController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *MigrationRetryController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing migration retry of VirtualMachineInstance %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed migration retry of VirtualMachineInstance %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *MigrationRetryController) execute(key string) error {
+	obj, exists, err := c.vmiInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	vmi := obj.(*virtv1.VirtualMachineInstance)
+
+	migrations, err := c.listMigrationsMatchingVMI(vmi.Namespace, vmi.Name)
+	if err != nil {
+		return err
+	}
+
+	var latest *virtv1.VirtualMachineInstanceMigration
+	for _, migration := range migrations {
+		if !migration.IsFinal() {
+			// a migration is still on its way
+			return nil
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&migration.CreationTimestamp) {
+			latest = migration
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	if latest.Status.Phase == virtv1.MigrationSucceeded {
+		return c.removeRetriesExhaustedCondition(vmi)
+	}
+
+	if vmi.IsFinal() || vmi.Status.Phase != virtv1.Running || latest.DeletionTimestamp != nil {
+		return nil
+	}
+
+	retries := migrationRetryCount(latest)
+	limit := c.retryLimit()
+	if retries >= limit {
+		if limit == 0 {
+			// retries are disabled
+			return nil
+		}
+		return c.addRetriesExhaustedCondition(vmi, retries)
+	}
+
+	retryAt := migrationFailedAt(vmi, latest).Add(migrationRetryBackoff(retries))
+	if remaining := retryAt.Sub(time.Now()); remaining > 0 {
+		c.Queue.AddAfter(key, remaining)
+		return nil
+	} else if -remaining > migrationRetryWindow {
+		log.Log.Object(vmi).V(4).Infof("not retrying migration %s, it failed too long ago", latest.Name)
+		return nil
+	}
+
+	retry, err := c.clientset.VirtualMachineInstanceMigration(vmi.Namespace).Create(newMigrationRetry(latest, retries+1))
+	if errors.IsAlreadyExists(err) {
+		return nil
+	} else if err != nil {
+		c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedRetryMigrationReason, "Error retrying failed migration %s: %v", latest.Name, err)
+		return err
+	}
+	migrationRetriesCounter.Inc()
+	c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulRetryMigrationReason, "Retrying failed migration %s with migration %s", latest.Name, retry.Name)
+	return nil
+}
+
+func (c *MigrationRetryController) retryLimit() int {
+	limit := c.clusterConfig.GetMigrationConfiguration().RetryLimit
+	if limit == nil {
+		return 0
+	}
+	return int(*limit)
+}
+
+func (c *MigrationRetryController) addRetriesExhaustedCondition(vmi *virtv1.VirtualMachineInstance, retries int) error {
+	conditionManager := controller.NewVirtualMachineInstanceConditionManager()
+	if conditionManager.HasCondition(vmi, virtv1.VirtualMachineInstanceMigrationRetriesExhausted) {
+		return nil
+	}
+	vmiCopy := vmi.DeepCopy()
+	vmiCopy.Status.Conditions = append(vmiCopy.Status.Conditions, virtv1.VirtualMachineInstanceCondition{
+		Type:               virtv1.VirtualMachineInstanceMigrationRetriesExhausted,
+		Status:             k8sv1.ConditionTrue,
+		LastProbeTime:      v1.Now(),
+		LastTransitionTime: v1.Now(),
+		Reason:             MigrationRetriesExhaustedReason,
+		Message:            fmt.Sprintf("Migration failed after %d retries", retries),
+	})
+	if err := c.patchConditions(vmi, vmiCopy); err != nil {
+		return err
+	}
+	c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, MigrationRetriesExhaustedReason, "Migration failed after %d retries, giving up", retries)
+	return nil
+}
+
+func (c *MigrationRetryController) removeRetriesExhaustedCondition(vmi *virtv1.VirtualMachineInstance) error {
+	conditionManager := controller.NewVirtualMachineInstanceConditionManager()
+	if !conditionManager.HasCondition(vmi, virtv1.VirtualMachineInstanceMigrationRetriesExhausted) {
+		return nil
+	}
+	vmiCopy := vmi.DeepCopy()
+	conditionManager.RemoveCondition(vmiCopy, virtv1.VirtualMachineInstanceMigrationRetriesExhausted)
+	if vmiCopy.Status.Conditions == nil {
+		vmiCopy.Status.Conditions = []virtv1.VirtualMachineInstanceCondition{}
+	}
+	return c.patchConditions(vmi, vmiCopy)
+}
+
+func (c *MigrationRetryController) patchConditions(vmi *virtv1.VirtualMachineInstance, vmiCopy *virtv1.VirtualMachineInstance) error {
+	newConditions, err := json.Marshal(vmiCopy.Status.Conditions)
+	if err != nil {
+		return err
+	}
+	oldConditions, err := json.Marshal(vmi.Status.Conditions)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`[{ "op": "test", "path": "/status/conditions", "value": %s }, { "op": "replace", "path": "/status/conditions", "value": %s }]`, string(oldConditions), string(newConditions))
+	_, err = c.clientset.VirtualMachineInstance(vmi.Namespace).Patch(vmi.Name, types.JSONPatchType, []byte(patch))
+	return err
+}
+
+func (c *MigrationRetryController) listMigrationsMatchingVMI(namespace string, name string) ([]*virtv1.VirtualMachineInstanceMigration, error) {
+	objs, err := c.migrationInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, err
+	}
+	migrations := []*virtv1.VirtualMachineInstanceMigration{}
+	for _, obj := range objs {
+		migration := obj.(*virtv1.VirtualMachineInstanceMigration)
+		if migration.Spec.VMIName == name {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations, nil
+}
+
+func (c *MigrationRetryController) enqueueVMIForMigration(obj interface{}) {
+	migration, ok := obj.(*virtv1.VirtualMachineInstanceMigration)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		migration, ok = tombstone.Obj.(*virtv1.VirtualMachineInstanceMigration)
+		if !ok {
+			return
+		}
+	}
+	c.Queue.Add(migration.Namespace + "/" + migration.Spec.VMIName)
+}
+
+// migrationRetryCount returns how many retries preceded the migration
+func migrationRetryCount(migration *virtv1.VirtualMachineInstanceMigration) int {
+	count, err := strconv.Atoi(migration.Annotations[virtv1.MigrationRetryCountAnnotation])
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// migrationFailedAt returns when the migration failed. The source node
+// records when it ended a migration on the VMI; migrations which failed before
+// that only carry the timestamps of their conditions and their creation.
+func migrationFailedAt(vmi *virtv1.VirtualMachineInstance, migration *virtv1.VirtualMachineInstanceMigration) time.Time {
+	if state := vmi.Status.MigrationState; state != nil && state.MigrationUID == migration.UID && state.EndTimestamp != nil {
+		return state.EndTimestamp.Time
+	}
+	failedAt := migration.CreationTimestamp.Time
+	for _, condition := range migration.Status.Conditions {
+		for _, timestamp := range []v1.Time{condition.LastTransitionTime, condition.LastProbeTime} {
+			if timestamp.Time.After(failedAt) {
+				failedAt = timestamp.Time
+			}
+		}
+	}
+	return failedAt
+}
+
+// migrationRetryBackoff doubles the time to wait before the next retry with
+// every retry which already failed
+func migrationRetryBackoff(retries int) time.Duration {
+	backoff := migrationRetryBaseBackoff
+	for i := 0; i < retries && backoff < migrationRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > migrationRetryMaxBackoff {
+		return migrationRetryMaxBackoff
+	}
+	return backoff
+}
+
+// newMigrationRetry returns the migration retrying the failed migration. Its
+// name is derived from the first migration, so that every retry is only
+// created once.
+func newMigrationRetry(failed *virtv1.VirtualMachineInstanceMigration, retry int) *virtv1.VirtualMachineInstanceMigration {
+	first := failed.Name
+	if name, exists := failed.Annotations[virtv1.MigrationRetryOfAnnotation]; exists {
+		first = name
+	}
+	return &virtv1.VirtualMachineInstanceMigration{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-retry-%d", first, retry),
+			Namespace: failed.Namespace,
+			Labels:    failed.Labels,
+			Annotations: map[string]string{
+				virtv1.MigrationRetryOfAnnotation:    first,
+				virtv1.MigrationRetryCountAnnotation: strconv.Itoa(retry),
+			},
+		},
+		Spec: failed.Spec,
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Migration retry controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var migrationInterface *kubecli.MockVirtualMachineInstanceMigrationInterface
+	var vmiInformer cache.SharedIndexInformer
+	var migrationInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var retryController *MigrationRetryController
+	var mockQueue *testutils.MockWorkQueue
+
+	const key = k8sv1.NamespaceDefault + "/testvmi"
+
+	addVMI := func() *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = v1.Running
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+		return vmi
+	}
+
+	addMigration := func(name string, phase v1.VirtualMachineInstanceMigrationPhase, retries string) *v1.VirtualMachineInstanceMigration {
+		migration := &v1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         k8sv1.NamespaceDefault,
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: v1.VirtualMachineInstanceMigrationSpec{
+				VMIName: "testvmi",
+			},
+			Status: v1.VirtualMachineInstanceMigrationStatus{
+				Phase: phase,
+			},
+		}
+		if retries != "" {
+			migration.Annotations = map[string]string{
+				v1.MigrationRetryOfAnnotation:    "testmigration",
+				v1.MigrationRetryCountAnnotation: retries,
+			}
+		}
+		Expect(migrationInformer.GetStore().Add(migration)).To(Succeed())
+		return migration
+	}
+
+	// expectRetryAfter expects no retry to be created yet, and the VMI to be
+	// enqueued again once the backoff passed
+	expectRetryAfter := func(delay time.Duration) {
+		migrationInterface.EXPECT().Create(gomock.Any()).Times(0)
+
+		Expect(retryController.execute(key)).To(Succeed())
+		enqueuedAfter, enqueued := mockQueue.GetAddAfterDelay(key)
+		Expect(enqueued).To(BeTrue())
+		Expect(enqueuedAfter).To(BeNumerically("~", delay, time.Second))
+	}
+
+	// backedOff lets the migration fail a minute ago, longer than the backoff
+	// of the first retries
+	backedOff := func(migration *v1.VirtualMachineInstanceMigration) {
+		migration.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		migrationInterface = kubecli.NewMockVirtualMachineInstanceMigrationInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+		virtClient.EXPECT().VirtualMachineInstanceMigration(k8sv1.NamespaceDefault).Return(migrationInterface).AnyTimes()
+
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		migrationInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstanceMigration{})
+		recorder = record.NewFakeRecorder(100)
+		config, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.MigrationsConfigKey: `{"retryLimit": "2"}`},
+		})
+
+		retryController = NewMigrationRetryController(vmiInformer, migrationInformer, recorder, virtClient, config)
+		mockQueue = testutils.NewMockWorkQueue(retryController.Queue)
+		retryController.Queue = mockQueue
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	It("should wait for the backoff before retrying a failed migration", func() {
+		addVMI()
+		addMigration("testmigration", v1.MigrationFailed, "")
+
+		expectRetryAfter(migrationRetryBaseBackoff)
+	})
+
+	It("should retry a failed migration once the backoff passed", func() {
+		addVMI()
+		backedOff(addMigration("testmigration", v1.MigrationFailed, ""))
+
+		migrationInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(migration *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+			Expect(migration.Name).To(Equal("testmigration-retry-1"))
+			Expect(migration.Spec.VMIName).To(Equal("testvmi"))
+			Expect(migration.Annotations).To(HaveKeyWithValue(v1.MigrationRetryOfAnnotation, "testmigration"))
+			Expect(migration.Annotations).To(HaveKeyWithValue(v1.MigrationRetryCountAnnotation, "1"))
+			return migration, nil
+		})
+
+		Expect(retryController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, SuccessfulRetryMigrationReason)
+	})
+
+	It("should wait for the backoff from the end of the migration on the source node", func() {
+		vmi := addVMI()
+		migration := addMigration("testmigration", v1.MigrationFailed, "")
+		backedOff(migration)
+		vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{
+			MigrationUID: migration.UID,
+			EndTimestamp: &metav1.Time{Time: time.Now()},
+			Failed:       true,
+		}
+
+		expectRetryAfter(migrationRetryBaseBackoff)
+	})
+
+	It("should wait for the backoff from the last condition of the migration", func() {
+		addVMI()
+		migration := addMigration("testmigration", v1.MigrationFailed, "")
+		backedOff(migration)
+		migration.Status.Conditions = []v1.VirtualMachineInstanceMigrationCondition{{
+			Type:          v1.VirtualMachineInstanceMigrationCPUIncompatible,
+			Status:        k8sv1.ConditionTrue,
+			LastProbeTime: metav1.Now(),
+		}}
+
+		expectRetryAfter(migrationRetryBaseBackoff)
+	})
+
+	It("should not retry migrations which failed long ago", func() {
+		addVMI()
+		migration := addMigration("testmigration", v1.MigrationFailed, "")
+		migration.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+
+		Expect(retryController.execute(key)).To(Succeed())
+	})
+
+	It("should count the retries from the first migration", func() {
+		addVMI()
+		backedOff(addMigration("testmigration-retry-1", v1.MigrationFailed, "1"))
+
+		migrationInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(migration *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+			Expect(migration.Name).To(Equal("testmigration-retry-2"))
+			Expect(migration.Annotations).To(HaveKeyWithValue(v1.MigrationRetryCountAnnotation, "2"))
+			return migration, nil
+		})
+
+		Expect(retryController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, SuccessfulRetryMigrationReason)
+	})
+
+	It("should not retry while a migration is in progress", func() {
+		addVMI()
+		backedOff(addMigration("testmigration", v1.MigrationFailed, ""))
+		addMigration("othermigration", v1.MigrationRunning, "")
+
+		Expect(retryController.execute(key)).To(Succeed())
+	})
+
+	It("should mark the VMI once the retries are exhausted", func() {
+		addVMI()
+		backedOff(addMigration("testmigration-retry-2", v1.MigrationFailed, "2"))
+
+		vmiInterface.EXPECT().Patch("testvmi", types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, patch []byte) (*v1.VirtualMachineInstance, error) {
+			Expect(string(patch)).To(ContainSubstring(string(v1.VirtualMachineInstanceMigrationRetriesExhausted)))
+			return nil, nil
+		})
+
+		Expect(retryController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, MigrationRetriesExhaustedReason)
+	})
+
+	It("should clear the mark once a migration succeeded", func() {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = v1.Running
+		vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{{Type: v1.VirtualMachineInstanceMigrationRetriesExhausted, Status: k8sv1.ConditionTrue}}
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+		addMigration("testmigration", v1.MigrationSucceeded, "")
+
+		vmiInterface.EXPECT().Patch("testvmi", types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, patch []byte) (*v1.VirtualMachineInstance, error) {
+			Expect(string(patch)).To(ContainSubstring(`"op": "replace", "path": "/status/conditions", "value": []`))
+			return nil, nil
+		})
+
+		Expect(retryController.execute(key)).To(Succeed())
+	})
+
+	table.DescribeTable("should back off exponentially", func(retries int, backoff time.Duration) {
+		Expect(migrationRetryBackoff(retries)).To(Equal(backoff))
+	},
+		table.Entry("before the first retry", 0, 10*time.Second),
+		table.Entry("before the second retry", 1, 20*time.Second),
+		table.Entry("before the third retry", 2, 40*time.Second),
+		table.Entry("up to the maximum", 10, 5*time.Minute),
+	)
+})
@@ -0,0 +1,314 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"net"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// DefaultNetworkPolicyName is the name of the network policy created in every namespace running VMIs
+	DefaultNetworkPolicyName = "kubevirt-default-egress"
+	// MissingNetworkPolicyReason is added in an event if no network policy restricts the egress traffic of a VMI
+	MissingNetworkPolicyReason = "MissingNetworkPolicy"
+	// FailedNetworkPolicyReason is added in an event if the default network policy could not be created or updated
+	FailedNetworkPolicyReason = "FailedNetworkPolicy"
+)
+
+// NetworkPolicyController keeps a network policy in every namespace running
+// VMIs, which cuts the virt-launcher pods off from the blocked CIDRs, by default
+// the node metadata service, and from the virt-handler pods. In Validate mode
+// it only warns about VMIs in namespaces where no network policy restricts the
+// egress traffic of the virt-launcher pods.
+type NetworkPolicyController struct {
+	clientset             kubecli.KubevirtClient
+	Queue                 workqueue.RateLimitingInterface
+	vmiInformer           cache.SharedIndexInformer
+	networkPolicyInformer cache.SharedIndexInformer
+	recorder              record.EventRecorder
+	clusterConfig         *virtconfig.ClusterConfig
+}
+
+func NewNetworkPolicyController(
+	vmiInformer cache.SharedIndexInformer,
+	networkPolicyInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+) *NetworkPolicyController {
+
+	c := &NetworkPolicyController{
+		clientset:             clientset,
+		Queue:                 workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:           vmiInformer,
+		networkPolicyInformer: networkPolicyInformer,
+		recorder:              recorder,
+		clusterConfig:         clusterConfig,
+	}
+
+	c.vmiInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNamespace,
+		DeleteFunc: c.enqueueNamespace,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueNamespace(curr) },
+	})
+	c.networkPolicyInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNamespace,
+		DeleteFunc: c.enqueueNamespace,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueNamespace(curr) },
+	})
+
+	return c
+}
+
+func (c *NetworkPolicyController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting network policy controller.")
+
+	// Wait for cache sync before we start the network policy controller
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.networkPolicyInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping network policy controller.")
+}
+
+func (c *NetworkPolicyController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *NetworkPolicyController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing network policy for namespace %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed network policy for namespace %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *NetworkPolicyController) execute(namespace string) error {
+	config := c.clusterConfig.GetDefaultNetworkPolicy()
+
+	vmis, err := c.activeVMIs(namespace)
+	if err != nil {
+		return err
+	}
+
+	if config == nil || config.Mode != virtv1.DefaultNetworkPolicyCreate || len(vmis) == 0 {
+		if err := c.deletePolicy(namespace); err != nil {
+			return err
+		}
+	}
+	if config == nil || len(vmis) == 0 {
+		return nil
+	}
+
+	if config.Mode == virtv1.DefaultNetworkPolicyValidate {
+		restricted, err := c.isEgressRestricted(namespace)
+		if err != nil || restricted {
+			return err
+		}
+		for _, vmi := range vmis {
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, MissingNetworkPolicyReason, "No network policy restricts the egress traffic of the VirtualMachineInstance in namespace %s", namespace)
+		}
+		return nil
+	}
+
+	err = c.syncPolicy(namespace, config)
+	if err != nil {
+		for _, vmi := range vmis {
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedNetworkPolicyReason, "Error syncing network policy %s: %v", DefaultNetworkPolicyName, err)
+		}
+	}
+	return err
+}
+
+// syncPolicy creates the default network policy or updates it if the blocked CIDRs changed
+func (c *NetworkPolicyController) syncPolicy(namespace string, config *virtv1.DefaultNetworkPolicyConfiguration) error {
+	desired := newDefaultNetworkPolicy(namespace, config)
+
+	obj, exists, err := c.networkPolicyInformer.GetStore().GetByKey(namespace + "/" + DefaultNetworkPolicyName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Create(desired)
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+
+	policy := obj.(*networkingv1.NetworkPolicy)
+	if equality.Semantic.DeepEqual(policy.Spec, desired.Spec) {
+		return nil
+	}
+	policy = policy.DeepCopy()
+	policy.Spec = desired.Spec
+	_, err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Update(policy)
+	return err
+}
+
+func (c *NetworkPolicyController) deletePolicy(namespace string) error {
+	_, exists, err := c.networkPolicyInformer.GetStore().GetByKey(namespace + "/" + DefaultNetworkPolicyName)
+	if err != nil || !exists {
+		return err
+	}
+	err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Delete(DefaultNetworkPolicyName, &v1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isEgressRestricted checks whether any network policy in the namespace
+// restricts the egress traffic of the virt-launcher pods
+func (c *NetworkPolicyController) isEgressRestricted(namespace string) (bool, error) {
+	objs, err := c.networkPolicyInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return false, err
+	}
+	launcherLabels := labels.Set{virtv1.AppLabel: "virt-launcher"}
+	for _, obj := range objs {
+		policy := obj.(*networkingv1.NetworkPolicy)
+		selector, err := v1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil || !selector.Matches(launcherLabels) {
+			continue
+		}
+		for _, policyType := range policy.Spec.PolicyTypes {
+			if policyType == networkingv1.PolicyTypeEgress {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (c *NetworkPolicyController) activeVMIs(namespace string) ([]*virtv1.VirtualMachineInstance, error) {
+	objs, err := c.vmiInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var vmis []*virtv1.VirtualMachineInstance
+	for _, obj := range objs {
+		vmi := obj.(*virtv1.VirtualMachineInstance)
+		if !vmi.IsFinal() && vmi.DeletionTimestamp == nil {
+			vmis = append(vmis, vmi)
+		}
+	}
+	return vmis, nil
+}
+
+func (c *NetworkPolicyController) enqueueNamespace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	o, ok := obj.(v1.Object)
+	if !ok {
+		return
+	}
+	c.Queue.Add(o.GetNamespace())
+}
+
+// newDefaultNetworkPolicy allows the virt-launcher pods to reach every pod
+// except virt-handler and every address except the blocked CIDRs. Network
+// plugins which match pod IPs against IP blocks only cut virt-handler off if
+// the pod network is among the blocked CIDRs.
+func newDefaultNetworkPolicy(namespace string, config *virtv1.DefaultNetworkPolicyConfiguration) *networkingv1.NetworkPolicy {
+	blockedCIDRs := config.BlockedCIDRs
+	if len(blockedCIDRs) == 0 {
+		blockedCIDRs = []string{virtconfig.DefaultNetworkPolicyBlockedCIDR}
+	}
+	ipv4Block := &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}
+	ipv6Block := &networkingv1.IPBlock{CIDR: "::/0"}
+	for _, cidr := range blockedCIDRs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ip.To4() != nil {
+			ipv4Block.Except = append(ipv4Block.Except, cidr)
+		} else {
+			ipv6Block.Except = append(ipv6Block.Except, cidr)
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      DefaultNetworkPolicyName,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: v1.LabelSelector{
+				MatchLabels: map[string]string{virtv1.AppLabel: "virt-launcher"},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					To: []networkingv1.NetworkPolicyPeer{{IPBlock: ipv4Block}, {IPBlock: ipv6Block}},
+				},
+				{
+					To: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &v1.LabelSelector{},
+						PodSelector: &v1.LabelSelector{
+							MatchExpressions: []v1.LabelSelectorRequirement{{
+								Key:      virtv1.AppLabel,
+								Operator: v1.LabelSelectorOpNotIn,
+								Values:   []string{"virt-handler"},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
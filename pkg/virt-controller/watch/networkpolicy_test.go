@@ -0,0 +1,173 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Network policy controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var kubeClient *fake.Clientset
+	var vmiInformer cache.SharedIndexInformer
+	var networkPolicyInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+
+	const namespace = k8sv1.NamespaceDefault
+
+	newController := func(config string) *NetworkPolicyController {
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		virtClient.EXPECT().NetworkingV1().Return(kubeClient.NetworkingV1()).AnyTimes()
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.DefaultNetworkPolicyKey: config},
+		})
+		return NewNetworkPolicyController(vmiInformer, networkPolicyInformer, recorder, virtClient, clusterConfig)
+	}
+
+	addVMI := func() *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = v1.Running
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+		return vmi
+	}
+
+	addPolicy := func(policy *networkingv1.NetworkPolicy) {
+		_, err := kubeClient.NetworkingV1().NetworkPolicies(namespace).Create(policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(networkPolicyInformer.GetStore().Add(policy)).To(Succeed())
+	}
+
+	getPolicy := func() (*networkingv1.NetworkPolicy, error) {
+		return kubeClient.NetworkingV1().NetworkPolicies(namespace).Get(DefaultNetworkPolicyName, metav1.GetOptions{})
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = fake.NewSimpleClientset()
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		networkPolicyInformer, _ = testutils.NewFakeInformerFor(&networkingv1.NetworkPolicy{})
+		recorder = record.NewFakeRecorder(100)
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	Context("in Create mode", func() {
+		It("should block the node metadata and virt-handler for the launcher pods", func() {
+			addVMI()
+			Expect(newController("mode: Create").execute(namespace)).To(Succeed())
+
+			policy, err := getPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{v1.AppLabel: "virt-launcher"}))
+			Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeEgress))
+			Expect(policy.Spec.Egress).To(HaveLen(2))
+			Expect(policy.Spec.Egress[0].To[0].IPBlock.Except).To(ConsistOf("169.254.169.254/32"))
+			Expect(policy.Spec.Egress[0].To[1].IPBlock.Except).To(BeEmpty())
+			Expect(policy.Spec.Egress[1].To[0].PodSelector.MatchExpressions[0].Values).To(ConsistOf("virt-handler"))
+		})
+
+		It("should split the blocked CIDRs by IP family", func() {
+			addVMI()
+			Expect(newController("mode: Create\nblockedCIDRs:\n- 10.0.0.0/8\n- fd00:ec2::254/128").execute(namespace)).To(Succeed())
+
+			policy, err := getPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Spec.Egress[0].To[0].IPBlock.Except).To(ConsistOf("10.0.0.0/8"))
+			Expect(policy.Spec.Egress[0].To[1].IPBlock.Except).To(ConsistOf("fd00:ec2::254/128"))
+		})
+
+		It("should update a policy with outdated blocked CIDRs", func() {
+			addVMI()
+			addPolicy(newDefaultNetworkPolicy(namespace, &v1.DefaultNetworkPolicyConfiguration{}))
+			Expect(newController("mode: Create\nblockedCIDRs:\n- 10.0.0.0/8").execute(namespace)).To(Succeed())
+
+			policy, err := getPolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Spec.Egress[0].To[0].IPBlock.Except).To(ConsistOf("10.0.0.0/8"))
+		})
+
+		It("should delete the policy once the namespace has no VMIs left", func() {
+			addPolicy(newDefaultNetworkPolicy(namespace, &v1.DefaultNetworkPolicyConfiguration{}))
+			Expect(newController("mode: Create").execute(namespace)).To(Succeed())
+
+			_, err := getPolicy()
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("in Validate mode", func() {
+		It("should warn about VMIs without a network policy restricting their egress traffic", func() {
+			addVMI()
+			Expect(newController("mode: Validate").execute(namespace)).To(Succeed())
+			testutils.ExpectEvent(recorder, MissingNetworkPolicyReason)
+
+			_, err := getPolicy()
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should accept any network policy restricting the egress traffic of the launcher pods", func() {
+			addVMI()
+			addPolicy(&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: namespace},
+				Spec: networkingv1.NetworkPolicySpec{
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+				},
+			})
+			Expect(newController("mode: Validate").execute(namespace)).To(Succeed())
+		})
+
+		It("should delete the policy created in Create mode", func() {
+			addVMI()
+			addPolicy(newDefaultNetworkPolicy(namespace, &v1.DefaultNetworkPolicyConfiguration{}))
+			Expect(newController("mode: Validate").execute(namespace)).To(Succeed())
+
+			_, err := getPolicy()
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("should not create a policy by default", func() {
+		addVMI()
+		Expect(newController("").execute(namespace)).To(Succeed())
+
+		_, err := getPolicy()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
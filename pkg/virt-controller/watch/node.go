@@ -7,6 +7,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,12 +21,19 @@ import (
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/controller"
 	"kubevirt.io/kubevirt/pkg/util/lookup"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 const (
 	// NodeUnresponsiveReason is in various places as reason to indicate that
 	// an action was taken because virt-handler became unresponsive.
 	NodeUnresponsiveReason = "NodeUnresponsive"
+	// NodeFencedReason is used to indicate that a vmi was moved to failed
+	// and its pod was force deleted because its node became unresponsive.
+	NodeFencedReason = "NodeFenced"
+
+	// outOfServiceTaintKey marks nodes which are known to be shut down
+	outOfServiceTaintKey = "node.kubernetes.io/out-of-service"
 )
 
 // NodeController is the main NodeController struct.
@@ -35,18 +43,22 @@ type NodeController struct {
 	nodeInformer     cache.SharedIndexInformer
 	vmiInformer      cache.SharedIndexInformer
 	recorder         record.EventRecorder
+	clusterConfig    *virtconfig.ClusterConfig
+	conditionManager *controller.VirtualMachineInstanceConditionManager
 	heartBeatTimeout time.Duration
 	recheckInterval  time.Duration
 }
 
 // NewNodeController creates a new instance of the NodeController struct.
-func NewNodeController(clientset kubecli.KubevirtClient, nodeInformer cache.SharedIndexInformer, vmiInformer cache.SharedIndexInformer, recorder record.EventRecorder) *NodeController {
+func NewNodeController(clientset kubecli.KubevirtClient, nodeInformer cache.SharedIndexInformer, vmiInformer cache.SharedIndexInformer, recorder record.EventRecorder, clusterConfig *virtconfig.ClusterConfig) *NodeController {
 	c := &NodeController{
 		clientset:        clientset,
 		Queue:            workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 		nodeInformer:     nodeInformer,
 		vmiInformer:      vmiInformer,
 		recorder:         recorder,
+		clusterConfig:    clusterConfig,
+		conditionManager: controller.NewVirtualMachineInstanceConditionManager(),
 		heartBeatTimeout: 5 * time.Minute,
 		recheckInterval:  1 * time.Minute,
 	}
@@ -193,29 +205,52 @@ func (c *NodeController) execute(key string) error {
 			return err
 		}
 
-		vmis = filterStuckVirtualMachinesWithoutPods(vmis, pods)
+		stuckVMIs := filterStuckVirtualMachinesWithoutPods(vmis, pods)
+		unreachableVMIs := filterUnreachableVirtualMachines(vmis, stuckVMIs)
+
+		confirmation, err := c.confirmNodeDead(node)
+		if err != nil {
+			logger.Reason(err).Error("Failed to determine if node should be fenced")
+			return err
+		}
 
 		errs := []string{}
 		// Do sequential updates, we don't want to create update storms in situations where something might already be wrong
-		for _, vmi := range vmis {
+		for _, vmi := range stuckVMIs {
 			c.recorder.Event(vmi, v1.EventTypeNormal, NodeUnresponsiveReason, fmt.Sprintf("virt-handler on node %s is not responsive, marking VMI as failed", vmi.Status.NodeName))
 			logger.V(2).Infof("Moving vmi %s in namespace %s on unresponsive node to failed state", vmi.Name, vmi.Namespace)
-			phasePatch := fmt.Sprintf(`{ "op": "replace", "path": "/status/phase", "value": "%s" }`, virtv1.Failed)
-			operation := "add"
-			if vmi.Status.Reason != "" {
-				operation = "replace"
-			}
-			reasonPatch := fmt.Sprintf(`{ "op": "%s", "path": "/status/reason", "value": "%s" }`, operation, NodeUnresponsiveReason)
-			_, err := c.clientset.VirtualMachineInstance(vmi.Namespace).Patch(vmi.Name, types.JSONPatchType, []byte(fmt.Sprintf("[%s, %s]", phasePatch, reasonPatch)))
-			if err != nil {
+			if err := c.moveVirtualMachineToFailed(vmi, NodeUnresponsiveReason); err != nil {
 				errs = append(errs, fmt.Sprintf("failed to move vmi %s in namespace %s to final state: %v", vmi.Name, vmi.Namespace, err))
 				logger.Reason(err).Errorf("Failed to move vmi %s in namespace %s to final state", vmi.Name, vmi.Namespace)
 			}
 		}
+		for _, vmi := range unreachableVMIs {
+			if confirmation != "" {
+				// The pod is deleted first, otherwise the failed vmi would wait for
+				// the graceful deletion of its pod, which never finishes on a dead node
+				c.recorder.Event(vmi, v1.EventTypeWarning, NodeFencedReason, fmt.Sprintf("node %s is not responsive and %s, fencing VMI", vmi.Status.NodeName, confirmation))
+				logger.V(2).Infof("Fencing vmi %s in namespace %s on unresponsive node", vmi.Name, vmi.Namespace)
+				if err := c.forceDeletePodsOfVirtualMachine(vmi, pods); err != nil {
+					errs = append(errs, fmt.Sprintf("failed to delete the pods of vmi %s in namespace %s: %v", vmi.Name, vmi.Namespace, err))
+					logger.Reason(err).Errorf("Failed to delete the pods of vmi %s in namespace %s", vmi.Name, vmi.Namespace)
+					continue
+				}
+				if err := c.moveVirtualMachineToFailed(vmi, NodeFencedReason); err != nil {
+					errs = append(errs, fmt.Sprintf("failed to move vmi %s in namespace %s to final state: %v", vmi.Name, vmi.Namespace, err))
+					logger.Reason(err).Errorf("Failed to move vmi %s in namespace %s to final state", vmi.Name, vmi.Namespace)
+				}
+			} else if err := c.markVirtualMachineUnresponsive(vmi); err != nil {
+				errs = append(errs, fmt.Sprintf("failed to mark vmi %s in namespace %s as unresponsive: %v", vmi.Name, vmi.Namespace, err))
+				logger.Reason(err).Errorf("Failed to mark vmi %s in namespace %s as unresponsive", vmi.Name, vmi.Namespace)
+			}
+		}
 
 		if len(errs) > 0 {
 			return fmt.Errorf("%v", strings.Join(errs, "; "))
 		}
+	} else if err := c.markVirtualMachinesResponsive(nodeName); err != nil {
+		logger.Reason(err).Error("Failed to mark vmis as responsive")
+		return err
 	}
 	if nodeExists {
 		c.Queue.AddAfter(key, c.recheckInterval)
@@ -223,6 +258,170 @@ func (c *NodeController) execute(key string) error {
 	return nil
 }
 
+func (c *NodeController) moveVirtualMachineToFailed(vmi *virtv1.VirtualMachineInstance, reason string) error {
+	phasePatch := fmt.Sprintf(`{ "op": "replace", "path": "/status/phase", "value": "%s" }`, virtv1.Failed)
+	operation := "add"
+	if vmi.Status.Reason != "" {
+		operation = "replace"
+	}
+	reasonPatch := fmt.Sprintf(`{ "op": "%s", "path": "/status/reason", "value": "%s" }`, operation, reason)
+	_, err := c.clientset.VirtualMachineInstance(vmi.Namespace).Patch(vmi.Name, types.JSONPatchType, []byte(fmt.Sprintf("[%s, %s]", phasePatch, reasonPatch)))
+	return err
+}
+
+func (c *NodeController) forceDeletePodsOfVirtualMachine(vmi *virtv1.VirtualMachineInstance, pods []*v1.Pod) error {
+	gracePeriod := int64(0)
+	for _, pod := range pods {
+		if controllerRef := controller.GetControllerOf(pod); pod.Namespace != vmi.Namespace || !isControlledByVMI(controllerRef) || controllerRef.UID != vmi.UID {
+			continue
+		}
+		err := c.clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// markVirtualMachineUnresponsive reports the state of a vmi as unknown, since
+// virt-handler can't observe it anymore
+func (c *NodeController) markVirtualMachineUnresponsive(vmi *virtv1.VirtualMachineInstance) error {
+	if c.conditionManager.HasConditionWithStatus(vmi, virtv1.VirtualMachineInstanceNodeResponsive, v1.ConditionUnknown) {
+		return nil
+	}
+	vmiCopy := vmi.DeepCopy()
+	c.conditionManager.RemoveCondition(vmiCopy, virtv1.VirtualMachineInstanceNodeResponsive)
+	vmiCopy.Status.Conditions = append(vmiCopy.Status.Conditions, virtv1.VirtualMachineInstanceCondition{
+		Type:               virtv1.VirtualMachineInstanceNodeResponsive,
+		Status:             v1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             virtv1.VirtualMachineInstanceReasonNodeUnresponsive,
+		Message:            fmt.Sprintf("virt-handler on node %s is not responsive", vmi.Status.NodeName),
+	})
+	if err := c.patchVirtualMachineConditions(vmi, vmiCopy); err != nil {
+		return err
+	}
+	c.recorder.Event(vmi, v1.EventTypeWarning, NodeUnresponsiveReason, fmt.Sprintf("virt-handler on node %s is not responsive, the state of the VMI is unknown", vmi.Status.NodeName))
+	return nil
+}
+
+// markVirtualMachinesResponsive clears the unknown state of the vmis on a
+// node, once its virt-handler sends heartbeats again
+func (c *NodeController) markVirtualMachinesResponsive(nodeName string) error {
+	objs, err := c.vmiInformer.GetIndexer().ByIndex("node", nodeName)
+	if err != nil {
+		return err
+	}
+	errs := []string{}
+	for _, obj := range objs {
+		vmi := obj.(*virtv1.VirtualMachineInstance)
+		if !c.conditionManager.HasCondition(vmi, virtv1.VirtualMachineInstanceNodeResponsive) {
+			continue
+		}
+		vmiCopy := vmi.DeepCopy()
+		c.conditionManager.RemoveCondition(vmiCopy, virtv1.VirtualMachineInstanceNodeResponsive)
+		if err := c.patchVirtualMachineConditions(vmi, vmiCopy); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to mark vmi %s in namespace %s as responsive: %v", vmi.Name, vmi.Namespace, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *NodeController) patchVirtualMachineConditions(vmi *virtv1.VirtualMachineInstance, vmiCopy *virtv1.VirtualMachineInstance) error {
+	newConditions, err := json.Marshal(vmiCopy.Status.Conditions)
+	if err != nil {
+		return err
+	}
+	conditionsPatch := fmt.Sprintf(`{ "op": "add", "path": "/status/conditions", "value": %s }`, string(newConditions))
+	if len(vmi.Status.Conditions) > 0 {
+		oldConditions, err := json.Marshal(vmi.Status.Conditions)
+		if err != nil {
+			return err
+		}
+		conditionsPatch = fmt.Sprintf(`{ "op": "test", "path": "/status/conditions", "value": %s }, { "op": "replace", "path": "/status/conditions", "value": %s }`, string(oldConditions), string(newConditions))
+	}
+	_, err = c.clientset.VirtualMachineInstance(vmi.Namespace).Patch(vmi.Name, types.JSONPatchType, []byte(fmt.Sprintf("[%s]", conditionsPatch)))
+	return err
+}
+
+// confirmNodeDead checks whether the vmis of an unresponsive node are fenced,
+// by the confirmation rules of the node fencing configuration. It returns how
+// the node was confirmed to be dead, or an empty string.
+func (c *NodeController) confirmNodeDead(node *v1.Node) (string, error) {
+	nodeFencing := c.clusterConfig.GetNodeFencing()
+	if !nodeFencing.Enabled {
+		return "", nil
+	}
+	if node == nil {
+		return "deleted", nil
+	}
+	confirmation := nodeFencing.Confirmation
+
+	if confirmation.OutOfServiceTaint {
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == outOfServiceTaintKey {
+				return fmt.Sprintf("tainted with %s", outOfServiceTaintKey), nil
+			}
+		}
+	}
+
+	for _, resource := range confirmation.Resources {
+		confirmed, err := c.isNodeConfirmedByResource(node.Name, resource)
+		if err != nil {
+			// the other rules may still confirm the node
+			log.Log.Object(node).Reason(err).Errorf("Failed to list the %s to confirm the node", resource.Resource)
+			continue
+		}
+		if confirmed {
+			return fmt.Sprintf("confirmed by %s", resource.Resource), nil
+		}
+	}
+
+	if !*confirmation.NotReady {
+		return "", nil
+	}
+	// A ready node keeps running the guests, even if virt-handler is down
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+			return "", nil
+		}
+	}
+	lastHeartBeat, err := getLastHeartBeat(node)
+	if err != nil || lastHeartBeat == nil {
+		return "", err
+	}
+	if lastHeartBeat.Add(c.heartBeatTimeout + nodeFencing.GracePeriod.Duration).Before(time.Now()) {
+		return "not ready", nil
+	}
+	return "", nil
+}
+
+// isNodeConfirmedByResource looks for an object of the resource which is
+// named after the node, like the remediation objects of fencing operators
+func (c *NodeController) isNodeConfirmedByResource(nodeName string, resource virtv1.NodeFencingResource) (bool, error) {
+	path := "/api/" + resource.Version
+	if resource.Group != "" {
+		path = "/apis/" + resource.Group + "/" + resource.Version
+	}
+	body, err := c.clientset.RestClient().Get().AbsPath(path, resource.Resource).DoRaw()
+	if err != nil {
+		return false, err
+	}
+	list := metav1.PartialObjectMetadataList{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return false, err
+	}
+	for _, item := range list.Items {
+		if item.Name == nodeName && item.DeletionTimestamp == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (c *NodeController) alivePodsOnNode(nodeName string) ([]*v1.Pod, error) {
 	handlerNodeSelector := fields.ParseSelectorOrDie("spec.nodeName=" + nodeName)
 	list, err := c.clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{
@@ -292,6 +491,23 @@ func filterStuckVirtualMachinesWithoutPods(vmis []*virtv1.VirtualMachineInstance
 	return filtered
 }
 
+// filterUnreachableVirtualMachines returns the scheduled and running vmis
+// which still have a pod, their guests might still run on the node
+func filterUnreachableVirtualMachines(vmis []*virtv1.VirtualMachineInstance, stuckVMIs []*virtv1.VirtualMachineInstance) []*virtv1.VirtualMachineInstance {
+	stuck := map[types.UID]bool{}
+	for _, vmi := range stuckVMIs {
+		stuck[vmi.UID] = true
+	}
+
+	filtered := []*virtv1.VirtualMachineInstance{}
+	for _, vmi := range vmis {
+		if (vmi.IsScheduled() || vmi.IsRunning()) && !stuck[vmi.UID] {
+			filtered = append(filtered, vmi)
+		}
+	}
+	return filtered
+}
+
 func isControlledByVMI(controllerRef *metav1.OwnerReference) bool {
 	return controllerRef != nil && controllerRef.Kind == virtv1.VirtualMachineInstanceGroupVersionKind.Kind
 }
@@ -300,15 +516,24 @@ func isNodeUnresponsive(node *v1.Node, timeout time.Duration) (bool, error) {
 	if node == nil {
 		return true, nil
 	}
-	if lastHeartBeat, exists := node.Annotations[virtv1.VirtHandlerHeartbeat]; exists {
-
-		timestamp := metav1.Time{}
-		if err := json.Unmarshal([]byte(`"`+lastHeartBeat+`"`), &timestamp); err != nil {
-			return false, err
-		}
-		if timestamp.Time.Before(metav1.Now().Add(-timeout)) {
-			return true, nil
-		}
+	lastHeartBeat, err := getLastHeartBeat(node)
+	if err != nil {
+		return false, err
+	}
+	if lastHeartBeat != nil && lastHeartBeat.Time.Before(metav1.Now().Add(-timeout)) {
+		return true, nil
 	}
 	return false, nil
 }
+
+func getLastHeartBeat(node *v1.Node) (*metav1.Time, error) {
+	lastHeartBeat, exists := node.Annotations[virtv1.VirtHandlerHeartbeat]
+	if !exists {
+		return nil, nil
+	}
+	timestamp := metav1.Time{}
+	if err := json.Unmarshal([]byte(`"`+lastHeartBeat+`"`), &timestamp); err != nil {
+		return nil, err
+	}
+	return &timestamp, nil
+}
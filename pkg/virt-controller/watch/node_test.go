@@ -3,6 +3,7 @@ package watch
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 	"github.com/pborman/uuid"
 	k8sv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +27,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 var _ = Describe("Node controller with", func() {
@@ -43,6 +46,8 @@ var _ = Describe("Node controller with", func() {
 	var virtClient *kubecli.MockKubevirtClient
 	var kubeClient *fake.Clientset
 	var vmiFeeder *testutils.VirtualMachineFeeder
+	var clusterConfig *virtconfig.ClusterConfig
+	var configMapInformer cache.SharedIndexInformer
 
 	syncCaches := func(stop chan struct{}) {
 		go nodeInformer.Run(stop)
@@ -57,10 +62,16 @@ var _ = Describe("Node controller with", func() {
 		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
 
 		nodeInformer, nodeSource = testutils.NewFakeInformerFor(&k8sv1.Node{})
-		vmiInformer, vmiSource = testutils.NewFakeInformerFor(&virtv1.VirtualMachineInstance{})
+		vmiInformer, vmiSource = testutils.NewFakeInformerWithIndexersFor(&virtv1.VirtualMachineInstance{}, cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			"node": func(obj interface{}) (strings []string, e error) {
+				return []string{obj.(*virtv1.VirtualMachineInstance).Status.NodeName}, nil
+			},
+		})
 		recorder = record.NewFakeRecorder(100)
+		clusterConfig, configMapInformer, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
 
-		controller = NewNodeController(virtClient, nodeInformer, vmiInformer, recorder)
+		controller = NewNodeController(virtClient, nodeInformer, vmiInformer, recorder, clusterConfig)
 		// Wrap our workqueue to have a way to detect when we are done processing updates
 		mockQueue = testutils.NewMockWorkQueue(controller.Queue)
 		controller.Queue = mockQueue
@@ -337,6 +348,224 @@ var _ = Describe("Node controller with", func() {
 			table.Entry("running state", virtv1.Running),
 			table.Entry("scheduled state", virtv1.Scheduled),
 		)
+		It("should report the state of a vmi which still has a pod as unknown", func() {
+			node := NewUnhealthyNode("testnode")
+			vmi := NewRunningVirtualMachine("vmi", node)
+
+			addNode(node)
+			kubeClient.Fake.PrependReactor("list", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, &k8sv1.PodList{Items: []k8sv1.Pod{*NewHealthyPodForVirtualMachine("whatever", vmi)}}, nil
+			})
+
+			vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+			vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+				Expect(string(data)).To(ContainSubstring(`"op": "add", "path": "/status/conditions"`))
+				Expect(string(data)).To(ContainSubstring(`"type":"NodeResponsive","status":"Unknown"`))
+				Expect(string(data)).To(ContainSubstring(`"reason":"NodeUnresponsive"`))
+				return vmi, nil
+			})
+
+			controller.Execute()
+			testutils.ExpectEvent(recorder, NodeUnresponsiveReason)
+		})
+		It("should not report the state of a vmi as unknown twice", func() {
+			node := NewUnhealthyNode("testnode")
+			vmi := NewRunningVirtualMachine("vmi", node)
+			vmi.Status.Conditions = []virtv1.VirtualMachineInstanceCondition{{
+				Type:   virtv1.VirtualMachineInstanceNodeResponsive,
+				Status: k8sv1.ConditionUnknown,
+			}}
+
+			addNode(node)
+			kubeClient.Fake.PrependReactor("list", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, &k8sv1.PodList{Items: []k8sv1.Pod{*NewHealthyPodForVirtualMachine("whatever", vmi)}}, nil
+			})
+
+			vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+
+			controller.Execute()
+		})
+	})
+
+	Context("node fencing enabled", func() {
+		BeforeEach(func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.NodeFencingKey: "enabled: true"},
+			})
+		})
+
+		expectPodListWith := func(pod *k8sv1.Pod) {
+			kubeClient.Fake.PrependReactor("list", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, &k8sv1.PodList{Items: []k8sv1.Pod{*pod}}, nil
+			})
+		}
+
+		It("should force delete the pod and fail a vmi on a node which is not ready", func() {
+			node := NewUnhealthyNode("testnode")
+			node.Annotations[virtv1.VirtHandlerHeartbeat] = nowAsJSONWithOffset(-20 * time.Minute)
+			node.Status.Conditions = []k8sv1.NodeCondition{{Type: k8sv1.NodeReady, Status: k8sv1.ConditionUnknown}}
+			vmi := NewRunningVirtualMachine("vmi", node)
+			pod := NewHealthyPodForVirtualMachine("whatever", vmi)
+
+			addNode(node)
+			expectPodListWith(pod)
+			deleted := false
+			kubeClient.Fake.PrependReactor("delete", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				Expect(action.(testing.DeleteAction).GetName()).To(Equal(pod.Name))
+				deleted = true
+				return true, nil, nil
+			})
+
+			vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+			vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+				Expect(deleted).To(BeTrue())
+				Expect(string(data)).To(ContainSubstring(`"value": "Failed"`))
+				Expect(string(data)).To(ContainSubstring(`"value": "NodeFenced"`))
+				return vmi, nil
+			})
+
+			controller.Execute()
+			testutils.ExpectEvent(recorder, NodeFencedReason)
+		})
+
+		table.DescribeTable("should only report the state of a vmi as unknown", func(heartBeatOffset time.Duration, nodeReady k8sv1.ConditionStatus) {
+			node := NewUnhealthyNode("testnode")
+			node.Annotations[virtv1.VirtHandlerHeartbeat] = nowAsJSONWithOffset(heartBeatOffset)
+			node.Status.Conditions = []k8sv1.NodeCondition{{Type: k8sv1.NodeReady, Status: nodeReady}}
+			vmi := NewRunningVirtualMachine("vmi", node)
+
+			addNode(node)
+			expectPodListWith(NewHealthyPodForVirtualMachine("whatever", vmi))
+
+			vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+			vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+				Expect(string(data)).To(ContainSubstring(`"type":"NodeResponsive","status":"Unknown"`))
+				return vmi, nil
+			})
+
+			controller.Execute()
+			testutils.ExpectEvent(recorder, NodeUnresponsiveReason)
+		},
+			table.Entry("if the node is still ready", -20*time.Minute, k8sv1.ConditionTrue),
+			table.Entry("within the grace period", -7*time.Minute, k8sv1.ConditionUnknown),
+		)
+
+		Context("with confirmation rules", func() {
+			var server *ghttp.Server
+
+			BeforeEach(func() {
+				server = ghttp.NewServer()
+				client, err := kubecli.GetKubevirtClientFromFlags(server.URL(), "")
+				Expect(err).ToNot(HaveOccurred())
+				virtClient.EXPECT().RestClient().Return(client.RestClient()).AnyTimes()
+
+				testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+					Data: map[string]string{virtconfig.NodeFencingKey: `
+enabled: true
+confirmation:
+  outOfServiceTaint: true
+  resources:
+  - group: fence-agents-remediation.medik8s.io
+    version: v1alpha1
+    resource: fenceagentsremediations
+`},
+				})
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			expectRemediations := func(names ...string) {
+				list := v1.PartialObjectMetadataList{}
+				for _, name := range names {
+					list.Items = append(list.Items, v1.PartialObjectMetadata{ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "medik8s"}})
+				}
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/apis/fence-agents-remediation.medik8s.io/v1alpha1/fenceagentsremediations"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, list),
+				))
+			}
+
+			expectFencing := func(vmi *virtv1.VirtualMachineInstance) {
+				expectPodListWith(NewHealthyPodForVirtualMachine("whatever", vmi))
+				kubeClient.Fake.PrependReactor("delete", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					return true, nil, nil
+				})
+				vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+				vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+					Expect(string(data)).To(ContainSubstring(`"value": "NodeFenced"`))
+					return vmi, nil
+				})
+			}
+
+			It("should fence the vmis of a node tainted out of service", func() {
+				node := NewUnhealthyNode("testnode")
+				node.Status.Conditions = []k8sv1.NodeCondition{{Type: k8sv1.NodeReady, Status: k8sv1.ConditionTrue}}
+				node.Spec.Taints = []k8sv1.Taint{{Key: "node.kubernetes.io/out-of-service", Effect: k8sv1.TaintEffectNoExecute}}
+				vmi := NewRunningVirtualMachine("vmi", node)
+
+				addNode(node)
+				expectFencing(vmi)
+
+				controller.Execute()
+				testutils.ExpectEvent(recorder, NodeFencedReason)
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("should fence the vmis of a node with a remediation object", func() {
+				node := NewUnhealthyNode("testnode")
+				vmi := NewRunningVirtualMachine("vmi", node)
+
+				addNode(node)
+				expectRemediations("othernode", "testnode")
+				expectFencing(vmi)
+
+				controller.Execute()
+				testutils.ExpectEvent(recorder, NodeFencedReason)
+			})
+
+			It("should not fence the vmis of a node which is only not ready", func() {
+				node := NewUnhealthyNode("testnode")
+				node.Annotations[virtv1.VirtHandlerHeartbeat] = nowAsJSONWithOffset(-20 * time.Minute)
+				node.Status.Conditions = []k8sv1.NodeCondition{{Type: k8sv1.NodeReady, Status: k8sv1.ConditionUnknown}}
+				vmi := NewRunningVirtualMachine("vmi", node)
+
+				addNode(node)
+				expectRemediations("othernode")
+				expectPodListWith(NewHealthyPodForVirtualMachine("whatever", vmi))
+				vmiInterface.EXPECT().List(gomock.Any()).Return(&virtv1.VirtualMachineInstanceList{Items: []virtv1.VirtualMachineInstance{*vmi}}, nil)
+				vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+					Expect(string(data)).To(ContainSubstring(`"type":"NodeResponsive","status":"Unknown"`))
+					return vmi, nil
+				})
+
+				controller.Execute()
+				testutils.ExpectEvent(recorder, NodeUnresponsiveReason)
+			})
+		})
+	})
+
+	Context("virt-handler becoming responsive again given", func() {
+		It("should clear the unknown state of the vmis on the node", func() {
+			node := NewHealthyNode("testnode")
+			vmi := NewRunningVirtualMachine("vmi", node)
+			vmi.Status.Conditions = []virtv1.VirtualMachineInstanceCondition{{
+				Type:   virtv1.VirtualMachineInstanceNodeResponsive,
+				Status: k8sv1.ConditionUnknown,
+			}}
+			vmiInformer.GetStore().Add(vmi)
+
+			addNode(node)
+
+			vmiInterface.EXPECT().Patch(vmi.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, data []byte) (*virtv1.VirtualMachineInstance, error) {
+				Expect(string(data)).To(ContainSubstring(`"op": "test", "path": "/status/conditions"`))
+				Expect(string(data)).To(ContainSubstring(`"op": "replace", "path": "/status/conditions", "value": null`))
+				return vmi, nil
+			})
+
+			controller.Execute()
+		})
 	})
 
 	AfterEach(func() {
@@ -0,0 +1,362 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// PreemptedReason is added in an event if a VMI was shut down to make room for a VMI with a higher priority
+	PreemptedReason = "Preempted"
+	// SuccessfulPreemptReason is added in an event if lower priority VMIs were shut down for a VMI
+	SuccessfulPreemptReason = "SuccessfulPreempt"
+	// FailedPreemptReason is added in an event if lower priority VMIs could not be shut down for a VMI
+	FailedPreemptReason = "FailedPreempt"
+)
+
+// preemptionRecheckInterval is how long to wait before checking again whether
+// the preempted VMIs are gone and the launcher pod got scheduled
+const preemptionRecheckInterval = 10 * time.Second
+
+// PreemptionController makes room for the launcher pods of VMIs which can not
+// be scheduled, by gracefully shutting down VMIs with a lower priority on a
+// node. The launcher pods do not preempt other pods on their own while the
+// VMPreemption feature gate is enabled, so that the guests of the lower
+// priority VMIs are shut down instead of their pods being deleted.
+type PreemptionController struct {
+	clientset     kubecli.KubevirtClient
+	Queue         workqueue.RateLimitingInterface
+	vmiInformer   cache.SharedIndexInformer
+	podInformer   cache.SharedIndexInformer
+	nodeInformer  cache.SharedIndexInformer
+	recorder      record.EventRecorder
+	clusterConfig *virtconfig.ClusterConfig
+}
+
+func NewPreemptionController(
+	vmiInformer cache.SharedIndexInformer,
+	podInformer cache.SharedIndexInformer,
+	nodeInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+) *PreemptionController {
+
+	c := &PreemptionController{
+		clientset:     clientset,
+		Queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:   vmiInformer,
+		podInformer:   podInformer,
+		nodeInformer:  nodeInformer,
+		recorder:      recorder,
+		clusterConfig: clusterConfig,
+	}
+
+	c.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueuePod,
+		UpdateFunc: func(old, curr interface{}) { c.enqueuePod(curr) },
+	})
+
+	return c
+}
+
+func (c *PreemptionController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting preemption controller.")
+
+	// Wait for cache sync before we start the preemption controller
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.podInformer.HasSynced, c.nodeInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping preemption controller.")
+}
+
+func (c *PreemptionController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *PreemptionController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing preemption for pod %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed preemption for pod %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *PreemptionController) execute(key string) error {
+	if !c.clusterConfig.VMPreemptionEnabled() {
+		return nil
+	}
+
+	obj, exists, err := c.podInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	pod := obj.(*k8sv1.Pod)
+	if !needsPreemption(pod) {
+		return nil
+	}
+
+	vmi, err := c.vmiForPod(pod)
+	if err != nil || vmi == nil || vmi.IsFinal() || vmi.DeletionTimestamp != nil {
+		return err
+	}
+
+	// wait until the VMIs which were already preempted for this one are gone
+	preemptor := vmi.Namespace + "/" + vmi.Name
+	for _, obj := range c.vmiInformer.GetStore().List() {
+		if obj.(*virtv1.VirtualMachineInstance).Annotations[virtv1.PreemptedByAnnotation] == preemptor {
+			c.Queue.AddAfter(key, preemptionRecheckInterval)
+			return nil
+		}
+	}
+
+	victims, node := c.selectVictims(pod)
+	if len(victims) == 0 {
+		log.Log.Object(vmi).V(4).Infof("No lower priority VMIs to preempt for pod %s", pod.Name)
+		return nil
+	}
+
+	for _, victim := range victims {
+		if err := c.preempt(victim, preemptor); err != nil {
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedPreemptReason, "Error preempting VirtualMachineInstance %s/%s: %v", victim.Namespace, victim.Name, err)
+			return err
+		}
+	}
+	c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulPreemptReason, "Shutting down %d lower priority VirtualMachineInstances on node %s", len(victims), node)
+	c.Queue.AddAfter(key, preemptionRecheckInterval)
+	return nil
+}
+
+// preempt marks the victim as preempted and deletes it, which makes
+// virt-handler shut the guest down within its termination grace period
+func (c *PreemptionController) preempt(victim *virtv1.VirtualMachineInstance, preemptor string) error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, virtv1.PreemptedByAnnotation, preemptor)
+	_, err := c.clientset.VirtualMachineInstance(victim.Namespace).Patch(victim.Name, types.MergePatchType, []byte(patch))
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = c.clientset.VirtualMachineInstance(victim.Namespace).Delete(victim.Name, &v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	c.recorder.Eventf(victim, k8sv1.EventTypeWarning, PreemptedReason, "Shutting down to make room for VirtualMachineInstance %s with a higher priority", preemptor)
+	return nil
+}
+
+// selectVictims returns the fewest running VMIs with a lower priority than the
+// pod, which free on one node at least the resources the pod requests
+func (c *PreemptionController) selectVictims(pod *k8sv1.Pod) ([]*virtv1.VirtualMachineInstance, string) {
+	priority := podPriority(pod)
+
+	candidates := map[string][]*k8sv1.Pod{}
+	for _, obj := range c.podInformer.GetStore().List() {
+		candidate := obj.(*k8sv1.Pod)
+		if candidate.Status.Phase != k8sv1.PodRunning || candidate.DeletionTimestamp != nil ||
+			candidate.Spec.NodeName == "" || podPriority(candidate) >= priority {
+			continue
+		}
+		if _, isMigrationTarget := candidate.Labels[virtv1.MigrationJobLabel]; isMigrationTarget {
+			continue
+		}
+		candidates[candidate.Spec.NodeName] = append(candidates[candidate.Spec.NodeName], candidate)
+	}
+
+	requests := podRequests(pod)
+	var victims []*virtv1.VirtualMachineInstance
+	var victimNode string
+	for nodeName, nodeCandidates := range candidates {
+		if !c.nodeFits(pod, nodeName) {
+			continue
+		}
+
+		// preempt the lowest priorities first
+		sort.SliceStable(nodeCandidates, func(i, j int) bool {
+			return podPriority(nodeCandidates[i]) < podPriority(nodeCandidates[j])
+		})
+		var nodeVictims []*virtv1.VirtualMachineInstance
+		freed := k8sv1.ResourceList{}
+		for _, candidate := range nodeCandidates {
+			if resourcesCover(freed, requests) {
+				break
+			}
+			vmi, err := c.vmiForPod(candidate)
+			if err != nil || vmi == nil || vmi.IsFinal() || vmi.DeletionTimestamp != nil || vmi.Status.MigrationState != nil && !vmi.Status.MigrationState.Completed {
+				continue
+			}
+			nodeVictims = append(nodeVictims, vmi)
+			addResources(freed, podRequests(candidate))
+		}
+		if !resourcesCover(freed, requests) {
+			continue
+		}
+		if victims == nil || len(nodeVictims) < len(victims) || len(nodeVictims) == len(victims) && nodeName < victimNode {
+			victims = nodeVictims
+			victimNode = nodeName
+		}
+	}
+	return victims, victimNode
+}
+
+// nodeFits checks the node selector of the pod and whether the node is schedulable
+func (c *PreemptionController) nodeFits(pod *k8sv1.Pod, nodeName string) bool {
+	obj, exists, err := c.nodeInformer.GetStore().GetByKey(nodeName)
+	if err != nil || !exists {
+		return false
+	}
+	node := obj.(*k8sv1.Node)
+	if node.Spec.Unschedulable {
+		return false
+	}
+	return labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels))
+}
+
+func (c *PreemptionController) vmiForPod(pod *k8sv1.Pod) (*virtv1.VirtualMachineInstance, error) {
+	name, exists := pod.Annotations[virtv1.DomainAnnotation]
+	if !exists {
+		return nil, nil
+	}
+	obj, exists, err := c.vmiInformer.GetStore().GetByKey(pod.Namespace + "/" + name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	vmi := obj.(*virtv1.VirtualMachineInstance)
+	if string(vmi.UID) != pod.Labels[virtv1.CreatedByLabel] {
+		return nil, nil
+	}
+	return vmi, nil
+}
+
+func (c *PreemptionController) enqueuePod(obj interface{}) {
+	pod := obj.(*k8sv1.Pod)
+	if !needsPreemption(pod) {
+		return
+	}
+	key, err := controller.KeyFunc(pod)
+	if err != nil {
+		return
+	}
+	c.Queue.Add(key)
+}
+
+// needsPreemption checks whether the pod is a launcher pod with a priority,
+// which the scheduler could not place. Migration target pods never preempt.
+func needsPreemption(pod *k8sv1.Pod) bool {
+	if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil || pod.Spec.Priority == nil {
+		return false
+	}
+	if _, isMigrationTarget := pod.Labels[virtv1.MigrationJobLabel]; isMigrationTarget {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == k8sv1.PodScheduled && condition.Status == k8sv1.ConditionFalse && condition.Reason == k8sv1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+func podPriority(pod *k8sv1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// podRequests sums up the CPU and memory requests of the containers of the pod
+func podRequests(pod *k8sv1.Pod) k8sv1.ResourceList {
+	requests := k8sv1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	return requests
+}
+
+func addResources(total k8sv1.ResourceList, add k8sv1.ResourceList) {
+	for _, name := range []k8sv1.ResourceName{k8sv1.ResourceCPU, k8sv1.ResourceMemory} {
+		quantity, exists := add[name]
+		if !exists {
+			continue
+		}
+		sum := total[name]
+		if sum.IsZero() {
+			sum = resource.Quantity{Format: quantity.Format}
+		}
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func resourcesCover(freed k8sv1.ResourceList, requests k8sv1.ResourceList) bool {
+	for _, name := range []k8sv1.ResourceName{k8sv1.ResourceCPU, k8sv1.ResourceMemory} {
+		request, exists := requests[name]
+		if !exists {
+			continue
+		}
+		available := freed[name]
+		if available.Cmp(request) < 0 {
+			return false
+		}
+	}
+	return len(freed) > 0
+}
@@ -0,0 +1,221 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("Preemption controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var vmiInformer cache.SharedIndexInformer
+	var podInformer cache.SharedIndexInformer
+	var nodeInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var preemptionController *PreemptionController
+
+	const key = k8sv1.NamespaceDefault + "/virt-launcher-highvmi"
+
+	addNode := func(name string, unschedulable bool) {
+		node := &k8sv1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       k8sv1.NodeSpec{Unschedulable: unschedulable},
+		}
+		Expect(nodeInformer.GetStore().Add(node)).To(Succeed())
+	}
+
+	addVMI := func(name string, priority int32, nodeName string, memory string) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI(name)
+		vmi.UID = types.UID(name)
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+
+		pod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "virt-launcher-" + name,
+				Namespace:   k8sv1.NamespaceDefault,
+				Labels:      map[string]string{v1.AppLabel: "virt-launcher", v1.CreatedByLabel: name},
+				Annotations: map[string]string{v1.DomainAnnotation: name},
+			},
+			Spec: k8sv1.PodSpec{
+				Priority: &priority,
+				NodeName: nodeName,
+				Containers: []k8sv1.Container{{
+					Name: "compute",
+					Resources: k8sv1.ResourceRequirements{
+						Requests: k8sv1.ResourceList{k8sv1.ResourceMemory: resource.MustParse(memory)},
+					},
+				}},
+			},
+		}
+		if nodeName != "" {
+			vmi.Status.Phase = v1.Running
+			pod.Status.Phase = k8sv1.PodRunning
+		} else {
+			vmi.Status.Phase = v1.Scheduling
+			pod.Status.Phase = k8sv1.PodPending
+			pod.Status.Conditions = []k8sv1.PodCondition{{
+				Type:   k8sv1.PodScheduled,
+				Status: k8sv1.ConditionFalse,
+				Reason: k8sv1.PodReasonUnschedulable,
+			}}
+		}
+		Expect(podInformer.GetStore().Add(pod)).To(Succeed())
+		return vmi
+	}
+
+	expectPreemption := func(names ...string) {
+		for _, name := range names {
+			vmiInterface.EXPECT().Patch(name, types.MergePatchType, gomock.Any()).DoAndReturn(func(name string, _ types.PatchType, patch []byte) (*v1.VirtualMachineInstance, error) {
+				Expect(string(patch)).To(ContainSubstring(`"` + v1.PreemptedByAnnotation + `":"default/highvmi"`))
+				return nil, nil
+			})
+			vmiInterface.EXPECT().Delete(name, gomock.Any()).Return(nil)
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(k8sv1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		nodeInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Node{})
+		recorder = record.NewFakeRecorder(100)
+		config, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.VMPreemptionGate},
+		})
+
+		preemptionController = NewPreemptionController(vmiInformer, podInformer, nodeInformer, recorder, virtClient, config)
+		addNode("node01", false)
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	It("should gracefully shut down lower priority VMIs to make room", func() {
+		addVMI("highvmi", 1000, "", "2Gi")
+		addVMI("lowvmi1", 10, "node01", "1Gi")
+		addVMI("lowvmi2", 0, "node01", "1Gi")
+
+		expectPreemption("lowvmi1", "lowvmi2")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, PreemptedReason)
+		testutils.ExpectEvent(recorder, PreemptedReason)
+		testutils.ExpectEvent(recorder, SuccessfulPreemptReason)
+	})
+
+	It("should preempt the lowest priority VMIs first", func() {
+		addVMI("highvmi", 1000, "", "1Gi")
+		addVMI("lowvmi1", 10, "node01", "1Gi")
+		addVMI("lowvmi2", 0, "node01", "1Gi")
+
+		expectPreemption("lowvmi2")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, PreemptedReason)
+		testutils.ExpectEvent(recorder, SuccessfulPreemptReason)
+	})
+
+	It("should pick the node where the fewest VMIs have to be shut down", func() {
+		addNode("node02", false)
+		addVMI("highvmi", 1000, "", "2Gi")
+		addVMI("lowvmi1", 0, "node01", "1Gi")
+		addVMI("lowvmi2", 0, "node01", "1Gi")
+		addVMI("lowvmi3", 0, "node02", "2Gi")
+
+		expectPreemption("lowvmi3")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+		testutils.ExpectEvent(recorder, PreemptedReason)
+		testutils.ExpectEvent(recorder, SuccessfulPreemptReason)
+	})
+
+	It("should not preempt VMIs with the same or a higher priority", func() {
+		addVMI("highvmi", 1000, "", "1Gi")
+		addVMI("othervmi", 1000, "node01", "1Gi")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+
+	It("should not preempt VMIs on unschedulable nodes", func() {
+		addNode("node02", true)
+		addVMI("highvmi", 1000, "", "1Gi")
+		addVMI("lowvmi", 0, "node02", "1Gi")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+
+	It("should not preempt if the VMIs would not free enough resources", func() {
+		addVMI("highvmi", 1000, "", "4Gi")
+		addVMI("lowvmi", 0, "node01", "1Gi")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+
+	It("should wait for the preempted VMIs to be gone", func() {
+		addVMI("highvmi", 1000, "", "1Gi")
+		lowVMI := addVMI("lowvmi", 0, "node01", "1Gi")
+		lowVMI.Annotations = map[string]string{v1.PreemptedByAnnotation: "default/highvmi"}
+		Expect(vmiInformer.GetStore().Update(lowVMI)).To(Succeed())
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+
+	It("should not preempt for migration target pods", func() {
+		addVMI("highvmi", 1000, "", "1Gi")
+		addVMI("lowvmi", 0, "node01", "1Gi")
+		obj, _, _ := podInformer.GetStore().GetByKey(key)
+		pod := obj.(*k8sv1.Pod)
+		pod.Labels[v1.MigrationJobLabel] = "testmigration"
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+
+	It("should not preempt without the VMPreemption feature gate", func() {
+		config, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+		preemptionController.clusterConfig = config
+		addVMI("highvmi", 1000, "", "1Gi")
+		addVMI("lowvmi", 0, "node01", "1Gi")
+
+		Expect(preemptionController.execute(key)).To(Succeed())
+	})
+})
@@ -22,6 +22,7 @@ package watch
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -65,12 +66,16 @@ const (
 	SuccessfulResumedReplicaSetReason = "SuccessfulResumed"
 )
 
-func NewVMIReplicaSet(vmiInformer cache.SharedIndexInformer, vmiRSInformer cache.SharedIndexInformer, recorder record.EventRecorder, clientset kubecli.KubevirtClient, burstReplicas uint) *VMIReplicaSet {
+// zoneLabel is the well-known label of the zone a node is located in
+const zoneLabel = "topology.kubernetes.io/zone"
+
+func NewVMIReplicaSet(vmiInformer cache.SharedIndexInformer, vmiRSInformer cache.SharedIndexInformer, nodeInformer cache.SharedIndexInformer, recorder record.EventRecorder, clientset kubecli.KubevirtClient, burstReplicas uint) *VMIReplicaSet {
 
 	c := &VMIReplicaSet{
 		Queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 		vmiInformer:   vmiInformer,
 		vmiRSInformer: vmiRSInformer,
+		nodeInformer:  nodeInformer,
 		recorder:      recorder,
 		clientset:     clientset,
 		expectations:  controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
@@ -98,6 +103,7 @@ type VMIReplicaSet struct {
 	Queue         workqueue.RateLimitingInterface
 	vmiInformer   cache.SharedIndexInformer
 	vmiRSInformer cache.SharedIndexInformer
+	nodeInformer  cache.SharedIndexInformer
 	recorder      record.EventRecorder
 	expectations  *controller.UIDTrackingControllerExpectations
 	burstReplicas uint
@@ -110,7 +116,7 @@ func (c *VMIReplicaSet) Run(threadiness int, stopCh <-chan struct{}) {
 	log.Log.Info("Starting VirtualMachineInstanceReplicaSet controller.")
 
 	// Wait for cache sync before we start the controller
-	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.vmiRSInformer.HasSynced)
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.vmiRSInformer.HasSynced, c.nodeInformer.HasSynced)
 
 	// Start the actual work
 	for i := 0; i < threadiness; i++ {
@@ -293,14 +299,14 @@ func (c *VMIReplicaSet) scale(rs *virtv1.VirtualMachineInstanceReplicaSet, vmis
 
 	if diff > 0 {
 		log.Log.V(4).Object(rs).Info("Delete excess VM's")
-		// We have to delete VMIs, use a very simple selection strategy for now
+		// We have to delete VMIs, take them from the most crowded zones to keep the replicas spread
 		// TODO: Possible deletion order: not yet running VMIs < migrating VMIs < other
-		deleteCandidates := vmis[0:diff]
+		deleteCandidates := c.selectDeleteCandidates(vmis, diff)
 		c.expectations.ExpectDeletions(rsKey, controller.VirtualMachineKeys(deleteCandidates))
 		for i := 0; i < diff; i++ {
 			go func(idx int) {
 				defer wg.Done()
-				deleteCandidate := vmis[idx]
+				deleteCandidate := deleteCandidates[idx]
 				err := c.clientset.VirtualMachineInstance(rs.ObjectMeta.Namespace).Delete(deleteCandidate.ObjectMeta.Name, &metav1.DeleteOptions{})
 				// Don't log an error if it is already deleted
 				if err != nil {
@@ -330,6 +336,9 @@ func (c *VMIReplicaSet) scale(rs *virtv1.VirtualMachineInstanceReplicaSet, vmis
 				// TODO check if vmi labels exist, and when make sure that they match. For now just override them
 				vmi.ObjectMeta.Labels = rs.Spec.Template.ObjectMeta.Labels
 				vmi.ObjectMeta.OwnerReferences = []metav1.OwnerReference{OwnerRef(rs)}
+				if len(vmi.Spec.TopologySpreadConstraints) == 0 {
+					vmi.Spec.TopologySpreadConstraints = defaultTopologySpreadConstraints(rs)
+				}
 				vmi, err := c.clientset.VirtualMachineInstance(rs.ObjectMeta.Namespace).Create(vmi)
 				if err != nil {
 					c.expectations.CreationObserved(rsKey)
@@ -672,14 +681,19 @@ func (c *VMIReplicaSet) updateStatus(rs *virtv1.VirtualMachineInstanceReplicaSet
 	// check if the label selector changed
 	labelSelectorMatch := labelSelector.String() == rs.Status.LabelSelector
 
+	// check if the replicas moved between zones
+	zones := c.calcZoneStatus(vmis)
+	zonesMatch := reflect.DeepEqual(zones, rs.Status.Zones)
+
 	// in case the replica count matches and the scaleErr and the error condition equal, don't update
-	if statesMatch && errorsMatch && pausedMatch && labelSelectorMatch {
+	if statesMatch && errorsMatch && pausedMatch && labelSelectorMatch && zonesMatch {
 		return nil
 	}
 
 	rs.Status.LabelSelector = labelSelector.String()
 	rs.Status.Replicas = int32(len(vmis))
 	rs.Status.ReadyReplicas = readyReplicas
+	rs.Status.Zones = zones
 
 	// Add/Remove Paused condition
 	c.checkPaused(rs)
@@ -704,6 +718,93 @@ func (c *VMIReplicaSet) updateStatus(rs *virtv1.VirtualMachineInstanceReplicaSet
 	return nil
 }
 
+// getVMIZone returns the zone of the node the VMI is running on, or an empty string if the VMI is not
+// scheduled yet or the node has no zone
+func (c *VMIReplicaSet) getVMIZone(vmi *virtv1.VirtualMachineInstance) string {
+	if vmi.Status.NodeName == "" {
+		return ""
+	}
+	obj, exists, err := c.nodeInformer.GetStore().GetByKey(vmi.Status.NodeName)
+	if err != nil || !exists {
+		return ""
+	}
+	node := obj.(*k8score.Node)
+	if zone, exists := node.Labels[zoneLabel]; exists {
+		return zone
+	}
+	return node.Labels[k8score.LabelZoneFailureDomain]
+}
+
+// calcZoneStatus counts the replicas per zone, sorted by the zone name
+func (c *VMIReplicaSet) calcZoneStatus(vmis []*virtv1.VirtualMachineInstance) []virtv1.VirtualMachineInstanceReplicaSetZoneStatus {
+	var zones []virtv1.VirtualMachineInstanceReplicaSetZoneStatus
+	indexes := map[string]int{}
+	conditionManager := controller.NewVirtualMachineInstanceConditionManager()
+
+	for _, vmi := range vmis {
+		zone := c.getVMIZone(vmi)
+		if zone == "" {
+			continue
+		}
+		idx, exists := indexes[zone]
+		if !exists {
+			idx = len(zones)
+			indexes[zone] = idx
+			zones = append(zones, virtv1.VirtualMachineInstanceReplicaSetZoneStatus{Zone: zone})
+		}
+		zones[idx].Replicas++
+		if conditionManager.HasConditionWithStatus(vmi, virtv1.VirtualMachineInstanceConditionType(k8score.PodReady), k8score.ConditionTrue) {
+			zones[idx].ReadyReplicas++
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Zone < zones[j].Zone
+	})
+	return zones
+}
+
+// selectDeleteCandidates picks the VMIs which get removed on scale down. VMIs without a zone go first,
+// afterwards one VMI after the other is taken from the zone with the most replicas left
+func (c *VMIReplicaSet) selectDeleteCandidates(vmis []*virtv1.VirtualMachineInstance, count int) []*virtv1.VirtualMachineInstance {
+	var zoneNames []string
+	byZone := map[string][]*virtv1.VirtualMachineInstance{}
+	for _, vmi := range vmis {
+		zone := c.getVMIZone(vmi)
+		if _, exists := byZone[zone]; !exists && zone != "" {
+			zoneNames = append(zoneNames, zone)
+		}
+		byZone[zone] = append(byZone[zone], vmi)
+	}
+	sort.Strings(zoneNames)
+
+	candidates := byZone[""][0:min(count, len(byZone[""]))]
+	for len(candidates) < count {
+		largest := zoneNames[0]
+		for _, zone := range zoneNames[1:] {
+			if len(byZone[zone]) > len(byZone[largest]) {
+				largest = zone
+			}
+		}
+		candidates = append(candidates, byZone[largest][0])
+		byZone[largest] = byZone[largest][1:]
+	}
+	return candidates
+}
+
+// defaultTopologySpreadConstraints asks the scheduler to spread the replicas evenly across the zones,
+// without blocking the scheduling if that is not possible
+func defaultTopologySpreadConstraints(rs *virtv1.VirtualMachineInstanceReplicaSet) []k8score.TopologySpreadConstraint {
+	return []k8score.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       zoneLabel,
+			WhenUnsatisfiable: k8score.ScheduleAnyway,
+			LabelSelector:     rs.Spec.Selector.DeepCopy(),
+		},
+	}
+}
+
 func (c *VMIReplicaSet) calcDiff(rs *virtv1.VirtualMachineInstanceReplicaSet, vmis []*virtv1.VirtualMachineInstance) int {
 	// TODO default this on the aggregated api server
 	wantedReplicas := int32(1)
@@ -40,6 +40,7 @@ var _ = Describe("Replicaset", func() {
 		var rsSource *framework.FakeControllerSource
 		var vmiInformer cache.SharedIndexInformer
 		var rsInformer cache.SharedIndexInformer
+		var nodeInformer cache.SharedIndexInformer
 		var stop chan struct{}
 		var controller *VMIReplicaSet
 		var recorder *record.FakeRecorder
@@ -49,7 +50,8 @@ var _ = Describe("Replicaset", func() {
 		syncCaches := func(stop chan struct{}) {
 			go vmiInformer.Run(stop)
 			go rsInformer.Run(stop)
-			Expect(cache.WaitForCacheSync(stop, vmiInformer.HasSynced, rsInformer.HasSynced)).To(BeTrue())
+			go nodeInformer.Run(stop)
+			Expect(cache.WaitForCacheSync(stop, vmiInformer.HasSynced, rsInformer.HasSynced, nodeInformer.HasSynced)).To(BeTrue())
 		}
 
 		BeforeEach(func() {
@@ -61,9 +63,10 @@ var _ = Describe("Replicaset", func() {
 
 			vmiInformer, vmiSource = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
 			rsInformer, rsSource = testutils.NewFakeInformerFor(&v1.VirtualMachineInstanceReplicaSet{})
+			nodeInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Node{})
 			recorder = record.NewFakeRecorder(100)
 
-			controller = NewVMIReplicaSet(vmiInformer, rsInformer, recorder, virtClient, uint(10))
+			controller = NewVMIReplicaSet(vmiInformer, rsInformer, nodeInformer, recorder, virtClient, uint(10))
 			// Wrap our workqueue to have a way to detect when we are done processing updates
 			mockQueue = testutils.NewMockWorkQueue(controller.Queue)
 			controller.Queue = mockQueue
@@ -239,6 +242,83 @@ var _ = Describe("Replicaset", func() {
 			// TODO test for missing 5
 		})
 
+		Context("with nodes in different zones", func() {
+
+			addNode := func(name, zone string) {
+				nodeInformer.GetStore().Add(&k8sv1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{zoneLabel: zone},
+					},
+				})
+			}
+
+			addVMIOnNode := func(rs *v1.VirtualMachineInstanceReplicaSet, name, node string) {
+				vmi := v1.NewMinimalVMI(name)
+				vmi.ObjectMeta.Labels = map[string]string{"test": "test"}
+				vmi.OwnerReferences = []metav1.OwnerReference{OwnerRef(rs)}
+				vmi.Status.NodeName = node
+				vmiFeeder.Add(vmi)
+			}
+
+			BeforeEach(func() {
+				addNode("node-a", "zone-a")
+				addNode("node-b", "zone-b")
+			})
+
+			It("should ask the scheduler to spread new VMIs across zones", func() {
+				rs, vmi := DefaultReplicaSet(1)
+
+				addReplicaSet(rs)
+
+				vmiInterface.EXPECT().Create(gomock.Any()).Do(func(arg interface{}) {
+					constraints := arg.(*v1.VirtualMachineInstance).Spec.TopologySpreadConstraints
+					Expect(constraints).To(HaveLen(1))
+					Expect(constraints[0].TopologyKey).To(Equal(zoneLabel))
+					Expect(constraints[0].WhenUnsatisfiable).To(Equal(k8sv1.ScheduleAnyway))
+					Expect(constraints[0].LabelSelector).To(Equal(rs.Spec.Selector))
+				}).Return(vmi, nil)
+
+				controller.Execute()
+
+				testutils.ExpectEvent(recorder, SuccessfulCreateVirtualMachineReason)
+			})
+
+			It("should delete VMIs from the zone with the most replicas first", func() {
+				rs, _ := DefaultReplicaSet(2)
+
+				addReplicaSet(rs)
+				addVMIOnNode(rs, "testvmi0", "node-a")
+				addVMIOnNode(rs, "testvmi1", "node-b")
+				addVMIOnNode(rs, "testvmi2", "node-b")
+
+				rsInterface.EXPECT().UpdateStatus(gomock.Any()).AnyTimes()
+				vmiInterface.EXPECT().Delete("testvmi1", gomock.Any()).Return(nil)
+
+				controller.Execute()
+
+				testutils.ExpectEvent(recorder, SuccessfulDeleteVirtualMachineReason)
+			})
+
+			It("should report the replicas per zone", func() {
+				rs, _ := DefaultReplicaSet(3)
+
+				addReplicaSet(rs)
+				addVMIOnNode(rs, "testvmi0", "node-b")
+				addVMIOnNode(rs, "testvmi1", "node-a")
+				addVMIOnNode(rs, "testvmi2", "node-b")
+
+				rsInterface.EXPECT().UpdateStatus(gomock.Any()).Do(func(obj interface{}) {
+					Expect(obj.(*v1.VirtualMachineInstanceReplicaSet).Status.Zones).To(Equal([]v1.VirtualMachineInstanceReplicaSetZoneStatus{
+						{Zone: "zone-a", Replicas: 1},
+						{Zone: "zone-b", Replicas: 2},
+					}))
+				})
+
+				controller.Execute()
+			})
+		})
+
 		It("should not delete vmis which are already marked deleted", func() {
 			rs, _ := DefaultReplicaSet(3)
 
@@ -29,6 +29,7 @@ import (
 	k8score "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -44,6 +45,7 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	cdiclone "kubevirt.io/containerized-data-importer/pkg/clone"
 	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/instancetype"
 )
 
 type CloneAuthFunc func(pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error)
@@ -52,19 +54,21 @@ func NewVMController(vmiInformer cache.SharedIndexInformer,
 	vmiVMInformer cache.SharedIndexInformer,
 	dataVolumeInformer cache.SharedIndexInformer,
 	pvcInformer cache.SharedIndexInformer,
+	templateRevisionInformer cache.SharedIndexInformer,
 	recorder record.EventRecorder,
 	clientset kubecli.KubevirtClient) *VMController {
 
 	c := &VMController{
-		Queue:                  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
-		vmiInformer:            vmiInformer,
-		vmiVMInformer:          vmiVMInformer,
-		dataVolumeInformer:     dataVolumeInformer,
-		pvcInformer:            pvcInformer,
-		recorder:               recorder,
-		clientset:              clientset,
-		expectations:           controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
-		dataVolumeExpectations: controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
+		Queue:                    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:              vmiInformer,
+		vmiVMInformer:            vmiVMInformer,
+		dataVolumeInformer:       dataVolumeInformer,
+		pvcInformer:              pvcInformer,
+		templateRevisionInformer: templateRevisionInformer,
+		recorder:                 recorder,
+		clientset:                clientset,
+		expectations:             controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
+		dataVolumeExpectations:   controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
 		cloneAuthFunc: func(pvcNamespace, pvcName, saNamespace, saName string) (bool, string, error) {
 			return cdiclone.CanServiceAccountClonePVC(clientset, pvcNamespace, pvcName, saNamespace, saName)
 		},
@@ -93,17 +97,18 @@ func NewVMController(vmiInformer cache.SharedIndexInformer,
 }
 
 type VMController struct {
-	clientset              kubecli.KubevirtClient
-	Queue                  workqueue.RateLimitingInterface
-	vmiInformer            cache.SharedIndexInformer
-	vmiVMInformer          cache.SharedIndexInformer
-	dataVolumeInformer     cache.SharedIndexInformer
-	pvcInformer            cache.SharedIndexInformer
-	recorder               record.EventRecorder
-	expectations           *controller.UIDTrackingControllerExpectations
-	dataVolumeExpectations *controller.UIDTrackingControllerExpectations
-	cloneAuthFunc          CloneAuthFunc
-	statusUpdater          *status.VMStatusUpdater
+	clientset                kubecli.KubevirtClient
+	Queue                    workqueue.RateLimitingInterface
+	vmiInformer              cache.SharedIndexInformer
+	vmiVMInformer            cache.SharedIndexInformer
+	dataVolumeInformer       cache.SharedIndexInformer
+	pvcInformer              cache.SharedIndexInformer
+	templateRevisionInformer cache.SharedIndexInformer
+	recorder                 record.EventRecorder
+	expectations             *controller.UIDTrackingControllerExpectations
+	dataVolumeExpectations   *controller.UIDTrackingControllerExpectations
+	cloneAuthFunc            CloneAuthFunc
+	statusUpdater            *status.VMStatusUpdater
 }
 
 func (c *VMController) Run(threadiness int, stopCh <-chan struct{}) {
@@ -112,7 +117,7 @@ func (c *VMController) Run(threadiness int, stopCh <-chan struct{}) {
 	log.Log.Info("Starting VirtualMachine controller.")
 
 	// Wait for cache sync before we start the controller
-	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.vmiVMInformer.HasSynced, c.dataVolumeInformer.HasSynced)
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.vmiVMInformer.HasSynced, c.dataVolumeInformer.HasSynced, c.templateRevisionInformer.HasSynced)
 
 	// Start the actual work
 	for i := 0; i < threadiness; i++ {
@@ -236,6 +241,13 @@ func (c *VMController) execute(key string) error {
 		}
 	}
 
+	if vm.ObjectMeta.DeletionTimestamp == nil {
+		if err := c.syncTemplateRevisions(vm); err != nil {
+			logger.Reason(err).Error("Recording the template revision failed.")
+			return err
+		}
+	}
+
 	var createErr error
 
 	// Scale up or down, if all expected creates and deletes were report by the listener
@@ -665,6 +677,12 @@ func (c *VMController) startVMI(vm *virtv1.VirtualMachine) error {
 	// start it
 	vmi := c.setupVMIFromVM(vm)
 
+	if err := c.applyInstancetypeToVmi(vm, vmi); err != nil {
+		log.Log.Object(vm).Reason(err).Error("Failed to apply the instancetype and preference to the VirtualMachineInstance.")
+		c.recorder.Eventf(vm, k8score.EventTypeWarning, FailedCreateVirtualMachineReason, "Error applying instancetype and preference: %v", err)
+		return err
+	}
+
 	c.expectations.ExpectCreations(vmKey, 1)
 	vmi, err = c.clientset.VirtualMachineInstance(vm.ObjectMeta.Namespace).Create(vmi)
 	if err != nil {
@@ -720,15 +738,95 @@ func (c *VMController) setupVMIFromVM(vm *virtv1.VirtualMachine) *virtv1.Virtual
 
 	setupStableFirmwareUUID(vm, vmi)
 
+	// the new VirtualMachineInstance requests the IP addresses claimed by the previous ones
+	for _, claim := range vm.Status.IPClaims {
+		vmi.Status.IPClaims = append(vmi.Status.IPClaims, *claim.DeepCopy())
+	}
+
 	// TODO check if vmi labels exist, and when make sure that they match. For now just override them
 	vmi.ObjectMeta.Labels = vm.Spec.Template.ObjectMeta.Labels
 	vmi.ObjectMeta.OwnerReferences = []v1.OwnerReference{
 		*v1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
 	}
 
+	setupPlacement(vm, vmi)
+
 	return vmi
 }
 
+// setupPlacement labels the VirtualMachineInstance with the group of the VirtualMachine and adds a
+// pod anti-affinity to the other members of the group, which is copied to the virt-launcher pod
+func setupPlacement(vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMachineInstance) {
+	placement := vm.Spec.Placement
+	if placement == nil {
+		return
+	}
+
+	// the labels and the affinity are shared with the VirtualMachine in the cache
+	labels := map[string]string{}
+	for key, value := range vmi.ObjectMeta.Labels {
+		labels[key] = value
+	}
+	labels[virtv1.VirtualMachineGroupLabel] = placement.Group
+	vmi.ObjectMeta.Labels = labels
+
+	topologyKey := k8score.LabelHostname
+	if placement.SpreadAcross == virtv1.PlacementTopologyZone {
+		topologyKey = zoneLabel
+	}
+	term := k8score.PodAffinityTerm{
+		LabelSelector: &v1.LabelSelector{
+			MatchLabels: map[string]string{virtv1.VirtualMachineGroupLabel: placement.Group},
+		},
+		TopologyKey: topologyKey,
+	}
+
+	affinity := &k8score.Affinity{}
+	if vmi.Spec.Affinity != nil {
+		affinity = vmi.Spec.Affinity.DeepCopy()
+	}
+	if affinity.PodAntiAffinity == nil {
+		affinity.PodAntiAffinity = &k8score.PodAntiAffinity{}
+	}
+	antiAffinity := affinity.PodAntiAffinity
+	if placement.Policy == virtv1.PlacementPolicyPreferred {
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			k8score.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+	} else {
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	}
+	vmi.Spec.Affinity = affinity
+}
+
+// applyInstancetypeToVmi pins the VirtualMachine to ControllerRevisions of its instancetype and preference
+// and expands them into the spec of the VirtualMachineInstance
+func (c *VMController) applyInstancetypeToVmi(vm *virtv1.VirtualMachine, vmi *virtv1.VirtualMachineInstance) error {
+	if vm.Spec.Instancetype == nil && vm.Spec.Preference == nil {
+		return nil
+	}
+
+	vm, err := instancetype.StoreControllerRevisions(c.clientset, vm)
+	if err != nil {
+		return err
+	}
+	instancetypeSpec, err := instancetype.FindInstancetypeSpec(c.clientset, vm)
+	if err != nil {
+		return err
+	}
+	preferenceSpec, err := instancetype.FindPreferenceSpec(c.clientset, vm)
+	if err != nil {
+		return err
+	}
+
+	// the spec is shared with the VirtualMachine in the cache
+	vmi.Spec = *vmi.Spec.DeepCopy()
+	conflicts := instancetype.ApplyToVmi(k8sfield.NewPath("spec", "template", "spec"), instancetypeSpec, preferenceSpec, &vmi.Spec)
+	if len(conflicts) > 0 {
+		return fmt.Errorf("VirtualMachine sets fields owned by its instancetype: %s", conflicts.String())
+	}
+	return nil
+}
+
 // no special meaning, randomly generated on my box.
 // TODO: do we want to use another constants? see examples in RFC4122
 const magicUUID = "6a1a24a1-4061-4607-8bf4-a3963d0c5895"
@@ -1179,6 +1277,11 @@ func (c *VMController) updateStatus(vmOrig *virtv1.VirtualMachine, vmi *virtv1.V
 
 	c.syncReadyConditionFromVMI(vm, vmi)
 
+	// keep the IP addresses claimed by the VirtualMachineInstance for the next one
+	if vmi != nil && len(vmi.Status.IPClaims) > 0 {
+		vm.Status.IPClaims = vmi.Status.IPClaims
+	}
+
 	// Add/Remove Failure condition if necessary
 	vmCondManager := controller.NewVirtualMachineConditionManager()
 	errMatch := (createErr != nil) == vmCondManager.HasCondition(vm, virtv1.VirtualMachineFailure)
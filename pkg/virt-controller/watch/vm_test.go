@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-openapi/errors"
@@ -9,10 +10,13 @@ import (
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pborman/uuid"
+	appsv1 "k8s.io/api/apps/v1"
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	framework "k8s.io/client-go/tools/cache/testing"
@@ -34,6 +38,8 @@ var _ = Describe("VirtualMachine", func() {
 		var ctrl *gomock.Controller
 		var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
 		var vmInterface *kubecli.MockVirtualMachineInterface
+		var instancetypeInterface *kubecli.MockVirtualMachineInstancetypeInterface
+		var kubeClient *fake.Clientset
 		var vmiSource *framework.FakeControllerSource
 		var vmSource *framework.FakeControllerSource
 		var vmiInformer cache.SharedIndexInformer
@@ -41,6 +47,8 @@ var _ = Describe("VirtualMachine", func() {
 		var dataVolumeInformer cache.SharedIndexInformer
 		var dataVolumeSource *framework.FakeControllerSource
 		var pvcInformer cache.SharedIndexInformer
+		var templateRevisionInformer cache.SharedIndexInformer
+		var templateRevisionSource *framework.FakeControllerSource
 		var stop chan struct{}
 		var controller *VMController
 		var recorder *record.FakeRecorder
@@ -53,7 +61,8 @@ var _ = Describe("VirtualMachine", func() {
 			go vmiInformer.Run(stop)
 			go vmInformer.Run(stop)
 			go dataVolumeInformer.Run(stop)
-			Expect(cache.WaitForCacheSync(stop, vmiInformer.HasSynced, vmInformer.HasSynced)).To(BeTrue())
+			go templateRevisionInformer.Run(stop)
+			Expect(cache.WaitForCacheSync(stop, vmiInformer.HasSynced, vmInformer.HasSynced, templateRevisionInformer.HasSynced)).To(BeTrue())
 		}
 
 		BeforeEach(func() {
@@ -62,14 +71,17 @@ var _ = Describe("VirtualMachine", func() {
 			virtClient := kubecli.NewMockKubevirtClient(ctrl)
 			vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
 			vmInterface = kubecli.NewMockVirtualMachineInterface(ctrl)
+			instancetypeInterface = kubecli.NewMockVirtualMachineInstancetypeInterface(ctrl)
+			kubeClient = fake.NewSimpleClientset()
 
 			dataVolumeInformer, dataVolumeSource = testutils.NewFakeInformerFor(&cdiv1.DataVolume{})
 			vmiInformer, vmiSource = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
 			vmInformer, vmSource = testutils.NewFakeInformerFor(&v1.VirtualMachine{})
 			pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
+			templateRevisionInformer, templateRevisionSource = testutils.NewFakeInformerFor(&appsv1.ControllerRevision{})
 			recorder = record.NewFakeRecorder(100)
 
-			controller = NewVMController(vmiInformer, vmInformer, dataVolumeInformer, pvcInformer, recorder, virtClient)
+			controller = NewVMController(vmiInformer, vmInformer, dataVolumeInformer, pvcInformer, templateRevisionInformer, recorder, virtClient)
 			// Wrap our workqueue to have a way to detect when we are done processing updates
 			mockQueue = testutils.NewMockWorkQueue(controller.Queue)
 			controller.Queue = mockQueue
@@ -80,6 +92,8 @@ var _ = Describe("VirtualMachine", func() {
 			// Set up mock client
 			virtClient.EXPECT().VirtualMachineInstance(metav1.NamespaceDefault).Return(vmiInterface).AnyTimes()
 			virtClient.EXPECT().VirtualMachine(metav1.NamespaceDefault).Return(vmInterface).AnyTimes()
+			virtClient.EXPECT().VirtualMachineInstancetype(metav1.NamespaceDefault).Return(instancetypeInterface).AnyTimes()
+			virtClient.EXPECT().AppsV1().Return(kubeClient.AppsV1()).AnyTimes()
 
 			cdiClient = cdifake.NewSimpleClientset()
 			virtClient.EXPECT().CdiClient().Return(cdiClient).AnyTimes()
@@ -525,6 +539,158 @@ var _ = Describe("VirtualMachine", func() {
 			)
 		})
 
+		Context("with an instancetype", func() {
+
+			var instancetypeObj *v1.VirtualMachineInstancetype
+
+			BeforeEach(func() {
+				instancetypeObj = &v1.VirtualMachineInstancetype{
+					ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: metav1.NamespaceDefault, Generation: 1},
+					Spec: v1.VirtualMachineInstancetypeSpec{
+						CPU:    v1.CPUInstancetype{Guest: 2},
+						Memory: v1.MemoryInstancetype{Guest: resource.MustParse("128Mi")},
+					},
+				}
+			})
+
+			It("should pin the instancetype and expand it into the VirtualMachineInstance", func() {
+				vm, vmi := DefaultVirtualMachine(true)
+				vm.Spec.Template.Spec.Domain.Resources.Requests = nil
+				vm.Spec.Instancetype = &v1.InstancetypeMatcher{Name: "small"}
+
+				addVirtualMachine(vm)
+
+				instancetypeInterface.EXPECT().Get("small", gomock.Any()).Return(instancetypeObj, nil)
+				vmInterface.EXPECT().Patch(vm.Name, types.MergePatchType, gomock.Any()).DoAndReturn(
+					func(name string, pt types.PatchType, data []byte, subresources ...string) (*v1.VirtualMachine, error) {
+						Expect(string(data)).To(Equal(`{"spec":{"instancetype":{"revisionName":"testvmi-instancetype-small-1"}}}`))
+						patched := vm.DeepCopy()
+						patched.Spec.Instancetype.RevisionName = "testvmi-instancetype-small-1"
+						return patched, nil
+					})
+				vmiInterface.EXPECT().Create(gomock.Any()).Do(func(arg interface{}) {
+					spec := arg.(*v1.VirtualMachineInstance).Spec
+					Expect(spec.Domain.CPU.Sockets).To(Equal(uint32(2)))
+					Expect(spec.Domain.Memory.Guest.String()).To(Equal("128Mi"))
+				}).Return(vmi, nil)
+				vmInterface.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil)
+
+				controller.Execute()
+
+				testutils.ExpectEvent(recorder, SuccessfulCreateVirtualMachineReason)
+				Expect(vm.Spec.Template.Spec.Domain.CPU).To(BeNil())
+				_, err := kubeClient.AppsV1().ControllerRevisions(metav1.NamespaceDefault).Get("testvmi-instancetype-small-1", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not start a VirtualMachine which overrides its instancetype", func() {
+				vm, _ := DefaultVirtualMachine(true)
+				vm.Spec.Instancetype = &v1.InstancetypeMatcher{Name: "small", RevisionName: "testvmi-instancetype-small-1"}
+				data, err := json.Marshal(instancetypeObj)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = kubeClient.AppsV1().ControllerRevisions(metav1.NamespaceDefault).Create(&appsv1.ControllerRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "testvmi-instancetype-small-1", Namespace: metav1.NamespaceDefault},
+					Data:       runtime.RawExtension{Raw: data},
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				addVirtualMachine(vm)
+
+				vmInterface.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil).AnyTimes()
+
+				controller.Execute()
+
+				testutils.ExpectEvent(recorder, FailedCreateVirtualMachineReason)
+			})
+		})
+
+		Context("template revisions", func() {
+
+			addTemplateRevision := func(vm *v1.VirtualMachine, name string, revision int64) {
+				controllerRevision := &appsv1.ControllerRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: vm.Namespace,
+						Labels:    map[string]string{v1.VirtualMachineTemplateRevisionLabel: string(vm.UID)},
+					},
+					Revision: revision,
+				}
+				_, err := kubeClient.AppsV1().ControllerRevisions(vm.Namespace).Create(controllerRevision)
+				Expect(err).ToNot(HaveOccurred())
+				templateRevisionSource.Add(controllerRevision)
+			}
+
+			currentRevisionName := func(vm *v1.VirtualMachine) string {
+				data, err := json.Marshal(vm.Spec.Template)
+				Expect(err).ToNot(HaveOccurred())
+				return templateRevisionName(vm.Name, data)
+			}
+
+			It("should record the template in a ControllerRevision owned by the VirtualMachine", func() {
+				vm, _ := DefaultVirtualMachine(false)
+
+				addVirtualMachine(vm)
+
+				vmInterface.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil)
+
+				controller.Execute()
+
+				revision, err := kubeClient.AppsV1().ControllerRevisions(vm.Namespace).Get(currentRevisionName(vm), metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revision.Revision).To(Equal(int64(1)))
+				Expect(revision.Labels).To(HaveKeyWithValue(v1.VirtualMachineTemplateRevisionLabel, string(vm.UID)))
+				Expect(revision.OwnerReferences[0].UID).To(Equal(vm.UID))
+
+				template := &v1.VirtualMachineInstanceTemplateSpec{}
+				Expect(json.Unmarshal(revision.Data.Raw, template)).To(Succeed())
+				Expect(template).To(Equal(vm.Spec.Template))
+			})
+
+			It("should move a rolled back revision to the top of the history and complete the rollback", func() {
+				vm, _ := DefaultVirtualMachine(false)
+				name := currentRevisionName(vm)
+				vm.Annotations[v1.RollbackRevisionAnnotation] = name
+				addTemplateRevision(vm, name, 1)
+				addTemplateRevision(vm, "testvmi-template-other", 2)
+
+				addVirtualMachine(vm)
+
+				vmInterface.EXPECT().Patch(vm.Name, types.JSONPatchType, gomock.Any()).DoAndReturn(
+					func(name string, pt types.PatchType, data []byte, subresources ...string) (*v1.VirtualMachine, error) {
+						Expect(string(data)).To(ContainSubstring(`"op": "remove", "path": "/metadata/annotations/kubevirt.io~1rollback-revision"`))
+						return vm, nil
+					})
+				vmInterface.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil)
+
+				controller.Execute()
+
+				revision, err := kubeClient.AppsV1().ControllerRevisions(vm.Namespace).Get(name, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revision.Revision).To(Equal(int64(3)))
+			})
+
+			It("should remove the oldest revisions beyond the history limit", func() {
+				vm, _ := DefaultVirtualMachine(false)
+				for i := 1; i <= templateRevisionHistoryLimit; i++ {
+					addTemplateRevision(vm, fmt.Sprintf("testvmi-template-%d", i), int64(i))
+				}
+
+				addVirtualMachine(vm)
+
+				vmInterface.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil)
+
+				controller.Execute()
+
+				revisions, err := kubeClient.AppsV1().ControllerRevisions(vm.Namespace).List(metav1.ListOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(revisions.Items).To(HaveLen(templateRevisionHistoryLimit))
+				_, err = kubeClient.AppsV1().ControllerRevisions(vm.Namespace).Get("testvmi-template-1", metav1.GetOptions{})
+				Expect(err).To(HaveOccurred())
+				_, err = kubeClient.AppsV1().ControllerRevisions(vm.Namespace).Get(currentRevisionName(vm), metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		It("should create missing VirtualMachineInstance", func() {
 			vm, vmi := DefaultVirtualMachine(true)
 
@@ -600,6 +766,28 @@ var _ = Describe("VirtualMachine", func() {
 			controller.Execute()
 		})
 
+		It("should keep the IP claims of the vmi", func() {
+			vm, vmi := DefaultVirtualMachine(true)
+			vmi.Status.IPClaims = []v1.InterfaceIPClaim{{Name: "default", IPs: []string{"10.1.0.5/24"}}}
+
+			addVirtualMachine(vm)
+			vmiFeeder.Add(vmi)
+
+			vmInterface.EXPECT().UpdateStatus(gomock.Any()).Do(func(arg interface{}) {
+				Expect(arg.(*v1.VirtualMachine).Status.IPClaims).To(Equal(vmi.Status.IPClaims))
+			}).Return(nil, nil)
+
+			controller.Execute()
+		})
+
+		It("should hand the IP claims over to a new vmi", func() {
+			vm, _ := DefaultVirtualMachine(true)
+			vm.Status.IPClaims = []v1.InterfaceIPClaim{{Name: "default", IPs: []string{"10.1.0.5/24"}}}
+
+			vmi := controller.setupVMIFromVM(vm)
+			Expect(vmi.Status.IPClaims).To(Equal(vm.Status.IPClaims))
+		})
+
 		It("should have stable firmware UUIDs", func() {
 			vm1, _ := DefaultVirtualMachineWithNames(true, "testvm1", "testvmi1")
 			vmi1 := controller.setupVMIFromVM(vm1)
@@ -624,6 +812,57 @@ var _ = Describe("VirtualMachine", func() {
 			Expect(string(vmi1.Spec.Domain.Firmware.UUID)).To(Equal(uid))
 		})
 
+		Context("with a placement", func() {
+			It("should require the vmi not to share a node with the other members of its group", func() {
+				vm, _ := DefaultVirtualMachine(true)
+				vm.Spec.Placement = &v1.VirtualMachinePlacement{Group: "database"}
+
+				vmi := controller.setupVMIFromVM(vm)
+				Expect(vmi.Labels).To(HaveKeyWithValue(v1.VirtualMachineGroupLabel, "database"))
+				Expect(vm.Spec.Template.ObjectMeta.Labels).ToNot(HaveKey(v1.VirtualMachineGroupLabel))
+				Expect(vmi.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal([]k8sv1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{v1.VirtualMachineGroupLabel: "database"},
+					},
+					TopologyKey: k8sv1.LabelHostname,
+				}}))
+				Expect(vm.Spec.Template.Spec.Affinity).To(BeNil())
+			})
+
+			It("should prefer spreading the group across zones and keep the affinity of the template", func() {
+				vm, _ := DefaultVirtualMachine(true)
+				vm.Spec.Placement = &v1.VirtualMachinePlacement{
+					Group:        "database",
+					SpreadAcross: v1.PlacementTopologyZone,
+					Policy:       v1.PlacementPolicyPreferred,
+				}
+				nodeAffinity := &k8sv1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &k8sv1.NodeSelector{
+						NodeSelectorTerms: []k8sv1.NodeSelectorTerm{{
+							MatchExpressions: []k8sv1.NodeSelectorRequirement{
+								{Key: "disktype", Operator: k8sv1.NodeSelectorOpIn, Values: []string{"ssd"}},
+							},
+						}},
+					},
+				}
+				vm.Spec.Template.Spec.Affinity = &k8sv1.Affinity{NodeAffinity: nodeAffinity}
+
+				vmi := controller.setupVMIFromVM(vm)
+				Expect(vmi.Spec.Affinity.NodeAffinity).To(Equal(nodeAffinity))
+				Expect(vmi.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+				Expect(vmi.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]k8sv1.WeightedPodAffinityTerm{{
+					Weight: 100,
+					PodAffinityTerm: k8sv1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{v1.VirtualMachineGroupLabel: "database"},
+						},
+						TopologyKey: zoneLabel,
+					},
+				}}))
+				Expect(vm.Spec.Template.Spec.Affinity.PodAntiAffinity).To(BeNil())
+			})
+		})
+
 		It("should delete VirtualMachineInstance when stopped", func() {
 			vm, vmi := DefaultVirtualMachine(false)
 
@@ -23,9 +23,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -46,6 +48,9 @@ const (
 	// FailedCreatePodReason is added in an event and in a vmi controller condition
 	// when a pod for a vmi controller failed to be created.
 	FailedCreatePodReason = "FailedCreate"
+	// ExceededQuotaReason is added in an event and in a vmi controller condition
+	// when a pod for a vmi controller can not be created because of a resource quota.
+	ExceededQuotaReason = "ExceededQuota"
 	// SuccessfulCreatePodReason is added in an event when a pod for a vmi controller
 	// is successfully created.
 	SuccessfulCreatePodReason = "SuccessfulCreate"
@@ -89,6 +94,9 @@ const (
 	// FailedPvcNotFoundReason is added in an event
 	// when a PVC for a volume was not found.
 	FailedPvcNotFoundReason = "FailedPvcNotFound"
+	// FailedPvcNotMultiAttachableReason is added in an event
+	// when a PVC for a golden image can not be attached on many nodes.
+	FailedPvcNotMultiAttachableReason = "FailedPvcNotMultiAttachable"
 	// SuccessfulMigrationReason is added when a migration attempt completes successfully
 	SuccessfulMigrationReason = "SuccessfulMigration"
 	// FailedMigrationReason is added when a migration attempt fails
@@ -97,6 +105,8 @@ const (
 	SuccessfulAbortMigrationReason = "SuccessfulAbortMigration"
 	// FailedAbortMigrationReason is added when an attempt to abort migration fails
 	FailedAbortMigrationReason = "FailedAbortMigration"
+	// NoCPUCompatibleNodeReason is set on a migration when no node can run the CPU model and features of the VMI
+	NoCPUCompatibleNodeReason = "NoCPUCompatibleNode"
 	// FailedPVCVolumeSourceMisusedReason is added when PVC volume source is used where Data Volume should be used
 	FailedPVCVolumeSourceMisusedReason = "PVCVolumeSourceMisused"
 )
@@ -327,7 +337,7 @@ func (c *VMIController) updateStatus(vmi *virtv1.VirtualMachineInstance, pod *k8
 			vmiCopy.Status.Phase = virtv1.Failed
 		} else {
 			vmiCopy.Status.Phase = virtv1.Pending
-			if syncErr != nil && syncErr.Reason() == FailedPvcNotFoundReason {
+			if syncErr != nil && (syncErr.Reason() == FailedPvcNotFoundReason || syncErr.Reason() == FailedPvcNotMultiAttachableReason) {
 				condition := virtv1.VirtualMachineInstanceCondition{
 					Type:    virtv1.VirtualMachineInstanceConditionType(k8sv1.PodScheduled),
 					Reason:  k8sv1.PodReasonUnschedulable,
@@ -475,6 +485,7 @@ func (c *VMIController) updateStatus(vmi *virtv1.VirtualMachineInstance, pod *k8
 	}
 
 	conditionManager.CheckFailure(vmiCopy, syncErr, reason)
+	controller.SetVMIPhaseTransitionTimestamp(&vmi.Status, &vmiCopy.Status)
 
 	// If we detect a change on the vmi we update the vmi
 	if !reflect.DeepEqual(vmi.Status, vmiCopy.Status) ||
@@ -489,6 +500,11 @@ func (c *VMIController) updateStatus(vmi *virtv1.VirtualMachineInstance, pod *k8
 	return nil
 }
 
+// isExceededQuotaError checks whether the resource quota admission of Kubernetes refused a pod
+func isExceededQuotaError(err error) bool {
+	return errors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
+}
+
 // isPodReady treats the pod as ready to be handed over to virt-handler, as soon as all pods except
 // the compute pod are ready. That includes kubevirt-infra and sidecars.
 func isPodReady(pod *k8sv1.Pod) bool {
@@ -580,7 +596,9 @@ func (c *VMIController) sync(vmi *virtv1.VirtualMachineInstance, pod *k8sv1.Pod,
 		}
 
 		templatePod, err := c.templateService.RenderLaunchManifest(vmi)
-		if _, ok := err.(services.PvcNotFoundError); ok {
+		if _, ok := err.(services.PvcNotMultiAttachableError); ok {
+			return &syncErrorImpl{fmt.Errorf("failed to render launch manifest: %v", err), FailedPvcNotMultiAttachableReason}
+		} else if _, ok := err.(services.PvcNotFoundError); ok {
 			return &syncErrorImpl{fmt.Errorf("failed to render launch manifest: %v", err), FailedPvcNotFoundReason}
 		} else if err != nil {
 			return &syncErrorImpl{fmt.Errorf("failed to render launch manifest: %v", err), FailedCreatePodReason}
@@ -589,7 +607,13 @@ func (c *VMIController) sync(vmi *virtv1.VirtualMachineInstance, pod *k8sv1.Pod,
 		vmiKey := controller.VirtualMachineKey(vmi)
 		c.podExpectations.ExpectCreations(vmiKey, 1)
 		pod, err := c.clientset.CoreV1().Pods(vmi.GetNamespace()).Create(templatePod)
-		if err != nil {
+		if isExceededQuotaError(err) {
+			// break the resources of the pod down, the guest resources alone may fit into the quota
+			resources := services.DescribeLauncherResources(vmi, templatePod, services.LauncherQuotaResources(templatePod))
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, ExceededQuotaReason, "Error creating pod: %v, the virt-launcher pod requests %s", err, resources)
+			c.podExpectations.CreationObserved(vmiKey)
+			return &syncErrorImpl{fmt.Errorf("failed to create virtual machine pod: %v, the virt-launcher pod requests %s", err, resources), ExceededQuotaReason}
+		} else if err != nil {
 			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedCreatePodReason, "Error creating pod: %v", err)
 			c.podExpectations.CreationObserved(vmiKey)
 			return &syncErrorImpl{fmt.Errorf("failed to create virtual machine pod: %v", err), FailedCreatePodReason}
@@ -27,6 +27,7 @@ import (
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -122,6 +123,9 @@ var _ = Describe("VirtualMachineInstance watcher", func() {
 		vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
 			Expect(arg.(*v1.VirtualMachineInstance).Status.Phase).To(Equal(v1.Scheduling))
 			Expect(arg.(*v1.VirtualMachineInstance).Status.Conditions).To(BeEmpty())
+			transitions := arg.(*v1.VirtualMachineInstance).Status.PhaseTransitionTimestamps
+			Expect(transitions).ToNot(BeEmpty())
+			Expect(transitions[len(transitions)-1].Phase).To(Equal(v1.Scheduling))
 		}).Return(vmi, nil)
 	}
 
@@ -129,6 +133,9 @@ var _ = Describe("VirtualMachineInstance watcher", func() {
 		vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
 			Expect(arg.(*v1.VirtualMachineInstance).Status.Phase).To(Equal(v1.Scheduled))
 			Expect(arg.(*v1.VirtualMachineInstance).Status.Conditions).To(BeEmpty())
+			transitions := arg.(*v1.VirtualMachineInstance).Status.PhaseTransitionTimestamps
+			Expect(transitions).ToNot(BeEmpty())
+			Expect(transitions[len(transitions)-1].Phase).To(Equal(v1.Scheduled))
 		}).Return(vmi, nil)
 	}
 
@@ -488,6 +495,26 @@ var _ = Describe("VirtualMachineInstance watcher", func() {
 
 			testutils.ExpectEvent(recorder, FailedCreatePodReason)
 		})
+		It("should break the resources of the pod down if it exceeds a resource quota", func() {
+			vmi := NewPendingVirtualMachine("testvmi")
+
+			addVirtualMachine(vmi)
+
+			kubeClient.Fake.PrependReactor("create", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, nil, errors.NewForbidden(k8sv1.Resource("pods"), "", fmt.Errorf("exceeded quota: memory, requested: requests.memory=1208392Ki, used: requests.memory=1Gi, limited: requests.memory=2Gi"))
+			})
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				condition := arg.(*v1.VirtualMachineInstance).Status.Conditions[0]
+				Expect(condition.Reason).To(Equal(ExceededQuotaReason))
+				Expect(condition.Message).To(ContainSubstring("the virt-launcher pod requests"))
+				Expect(condition.Message).To(ContainSubstring("overhead: "))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+
+			testutils.ExpectEvent(recorder, ExceededQuotaReason)
+		})
 		It("should back-off if a sync error occurs", func() {
 			vmi := NewPendingVirtualMachine("testvmi")
 
@@ -585,6 +612,44 @@ var _ = Describe("VirtualMachineInstance watcher", func() {
 			Expect(mockQueue.GetRateLimitedEnqueueCount()).To(Equal(2))
 		})
 
+		It("should mark the vmi unschedulable if the golden image can only be attached to one node", func() {
+			vmi := NewPendingVirtualMachine("testvmi")
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "golden",
+					VolumeSource: v1.VolumeSource{
+						GoldenImage: &v1.GoldenImageVolumeSource{
+							ClaimName: "golden-pvc",
+						},
+					},
+				},
+			}
+			pvcInformer.GetStore().Add(&k8sv1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: vmi.Namespace,
+					Name:      "golden-pvc",
+				},
+				Spec: k8sv1.PersistentVolumeClaimSpec{
+					AccessModes: []k8sv1.PersistentVolumeAccessMode{k8sv1.ReadWriteOnce},
+				},
+			})
+			addVirtualMachine(vmi)
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+				getType := func(c v1.VirtualMachineInstanceCondition) string { return string(c.Type) }
+				getReason := func(c v1.VirtualMachineInstanceCondition) string { return c.Reason }
+				Expect(vmi.Status.Conditions).To(ContainElement(
+					And(
+						WithTransform(getType, Equal(string(k8sv1.PodScheduled))),
+						WithTransform(getReason, Equal(k8sv1.PodReasonUnschedulable)),
+					),
+				))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+			Expect(mockQueue.GetRateLimitedEnqueueCount()).To(Equal(1))
+		})
+
 		table.DescribeTable("should move the vmi to scheduling state if a pod exists", func(phase k8sv1.PodPhase, isReady bool) {
 			vmi := NewPendingVirtualMachine("testvmi")
 			pod := NewPodForVirtualMachine(vmi, phase)
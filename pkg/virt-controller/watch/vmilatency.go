@@ -0,0 +1,95 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/cache"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+)
+
+var (
+	// the buckets range from one second to a bit more than an hour
+	vmiLatencyBuckets = prometheus.ExponentialBuckets(1, 2, 13)
+
+	vmiPhaseTransitionFromCreationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmi_phase_transition_time_from_creation_seconds",
+			Help:    "Time from the creation of VMIs until they entered a phase",
+			Buckets: vmiLatencyBuckets,
+		},
+		[]string{"phase"},
+	)
+	vmiPhaseTransitionSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmi_phase_transition_time_seconds",
+			Help:    "Time VMIs spent in a phase until they entered the next one",
+			Buckets: vmiLatencyBuckets,
+		},
+		[]string{"previous_phase", "phase"},
+	)
+	vmiMigrationDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmi_migration_duration_seconds",
+			Help:    "Time from the start of live migrations of VMIs until they completed",
+			Buckets: vmiLatencyBuckets,
+		},
+		[]string{"result"},
+	)
+)
+
+// newVMILatencyEventHandler observes the lifecycle latencies of the VMIs from the
+// timestamps of their status, when an update of a VMI records a new transition
+func newVMILatencyEventHandler() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, curr interface{}) {
+			observeVMILatencies(old.(*virtv1.VirtualMachineInstance), curr.(*virtv1.VirtualMachineInstance))
+		},
+	}
+}
+
+func observeVMILatencies(oldVMI *virtv1.VirtualMachineInstance, newVMI *virtv1.VirtualMachineInstance) {
+	transitions := newVMI.Status.PhaseTransitionTimestamps
+	for i := len(oldVMI.Status.PhaseTransitionTimestamps); i < len(transitions); i++ {
+		transition := transitions[i]
+		vmiPhaseTransitionFromCreationSeconds.WithLabelValues(string(transition.Phase)).Observe(
+			transition.PhaseTransitionTimestamp.Sub(newVMI.CreationTimestamp.Time).Seconds())
+		if i > 0 {
+			previous := transitions[i-1]
+			vmiPhaseTransitionSeconds.WithLabelValues(string(previous.Phase), string(transition.Phase)).Observe(
+				transition.PhaseTransitionTimestamp.Sub(previous.PhaseTransitionTimestamp.Time).Seconds())
+		}
+	}
+
+	migration := newVMI.Status.MigrationState
+	if migration == nil || !migration.Completed || migration.StartTimestamp == nil || migration.EndTimestamp == nil {
+		return
+	}
+	oldMigration := oldVMI.Status.MigrationState
+	if oldMigration != nil && oldMigration.Completed && oldMigration.MigrationUID == migration.MigrationUID {
+		return
+	}
+	result := "succeeded"
+	if migration.Failed {
+		result = "failed"
+	}
+	vmiMigrationDurationSeconds.WithLabelValues(result).Observe(migration.EndTimestamp.Sub(migration.StartTimestamp.Time).Seconds())
+}
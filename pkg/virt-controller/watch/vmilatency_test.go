@@ -0,0 +1,103 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("VMI latency metrics", func() {
+	var created time.Time
+
+	histogramOf := func(observer prometheus.Observer) *io_prometheus_client.Histogram {
+		dto := &io_prometheus_client.Metric{}
+		Expect(observer.(prometheus.Metric).Write(dto)).To(Succeed())
+		return dto.GetHistogram()
+	}
+
+	transition := func(phase virtv1.VirtualMachineInstancePhase, afterCreation time.Duration) virtv1.VirtualMachineInstancePhaseTransitionTimestamp {
+		return virtv1.VirtualMachineInstancePhaseTransitionTimestamp{
+			Phase:                    phase,
+			PhaseTransitionTimestamp: metav1.NewTime(created.Add(afterCreation)),
+		}
+	}
+
+	BeforeEach(func() {
+		created = time.Now().Add(-time.Hour)
+	})
+
+	It("should observe the time until a VMI entered a new phase", func() {
+		oldVMI := virtv1.NewMinimalVMI("testvmi")
+		oldVMI.CreationTimestamp = metav1.NewTime(created)
+		oldVMI.Status.PhaseTransitionTimestamps = []virtv1.VirtualMachineInstancePhaseTransitionTimestamp{
+			transition(virtv1.Pending, 0),
+			transition(virtv1.Scheduling, time.Second),
+			transition(virtv1.Scheduled, 20*time.Second),
+		}
+		newVMI := oldVMI.DeepCopy()
+		newVMI.Status.PhaseTransitionTimestamps = append(newVMI.Status.PhaseTransitionTimestamps, transition(virtv1.Running, 25*time.Second))
+
+		fromCreation := histogramOf(vmiPhaseTransitionFromCreationSeconds.WithLabelValues(string(virtv1.Running)))
+		fromScheduled := histogramOf(vmiPhaseTransitionSeconds.WithLabelValues(string(virtv1.Scheduled), string(virtv1.Running)))
+
+		observeVMILatencies(oldVMI, newVMI)
+
+		updatedFromCreation := histogramOf(vmiPhaseTransitionFromCreationSeconds.WithLabelValues(string(virtv1.Running)))
+		Expect(updatedFromCreation.GetSampleCount()).To(Equal(fromCreation.GetSampleCount() + 1))
+		Expect(updatedFromCreation.GetSampleSum()).To(BeNumerically("~", fromCreation.GetSampleSum()+25, 0.001))
+		updatedFromScheduled := histogramOf(vmiPhaseTransitionSeconds.WithLabelValues(string(virtv1.Scheduled), string(virtv1.Running)))
+		Expect(updatedFromScheduled.GetSampleCount()).To(Equal(fromScheduled.GetSampleCount() + 1))
+		Expect(updatedFromScheduled.GetSampleSum()).To(BeNumerically("~", fromScheduled.GetSampleSum()+5, 0.001))
+
+		By("not observing the same transition again")
+		observeVMILatencies(newVMI, newVMI.DeepCopy())
+		Expect(histogramOf(vmiPhaseTransitionFromCreationSeconds.WithLabelValues(string(virtv1.Running))).GetSampleCount()).To(Equal(updatedFromCreation.GetSampleCount()))
+	})
+
+	It("should observe the duration of a completed migration once", func() {
+		start := metav1.NewTime(created)
+		end := metav1.NewTime(created.Add(42 * time.Second))
+		oldVMI := virtv1.NewMinimalVMI("testvmi")
+		oldVMI.Status.MigrationState = &virtv1.VirtualMachineInstanceMigrationState{
+			MigrationUID:   "1234",
+			StartTimestamp: &start,
+			EndTimestamp:   &end,
+		}
+		newVMI := oldVMI.DeepCopy()
+		newVMI.Status.MigrationState.Completed = true
+
+		succeeded := histogramOf(vmiMigrationDurationSeconds.WithLabelValues("succeeded"))
+
+		observeVMILatencies(oldVMI, newVMI)
+		observeVMILatencies(newVMI, newVMI.DeepCopy())
+
+		updated := histogramOf(vmiMigrationDurationSeconds.WithLabelValues("succeeded"))
+		Expect(updated.GetSampleCount()).To(Equal(succeeded.GetSampleCount() + 1))
+		Expect(updated.GetSampleSum()).To(BeNumerically("~", succeeded.GetSampleSum()+42, 0.001))
+	})
+})
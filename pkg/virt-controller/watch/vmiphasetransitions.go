@@ -0,0 +1,113 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+)
+
+var (
+	vmiPhaseTransitionLabels = []string{"namespace", "name", "phase", "reason"}
+
+	vmiPhaseTransitionTimestampDesc = prometheus.NewDesc(
+		"kubevirt_vmi_phase_transition_timestamp_seconds",
+		"Time the VMI entered a phase, in seconds since the epoch.",
+		vmiPhaseTransitionLabels,
+		nil,
+	)
+	vmiPhaseDurationDesc = prometheus.NewDesc(
+		"kubevirt_vmi_phase_duration_seconds",
+		"Time the VMI spent in a phase, up to now for its current phase.",
+		vmiPhaseTransitionLabels,
+		nil,
+	)
+)
+
+// vmiPhaseTransitionCollector reports the phase transitions of every VMI, to find
+// out where VMIs get stuck
+type vmiPhaseTransitionCollector struct {
+	vmiInformer cache.SharedIndexInformer
+	now         func() time.Time
+}
+
+func newVMIPhaseTransitionCollector(vmiInformer cache.SharedIndexInformer) *vmiPhaseTransitionCollector {
+	return &vmiPhaseTransitionCollector{vmiInformer: vmiInformer, now: time.Now}
+}
+
+func (c *vmiPhaseTransitionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- vmiPhaseTransitionTimestampDesc
+	ch <- vmiPhaseDurationDesc
+}
+
+func (c *vmiPhaseTransitionCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	for _, obj := range c.vmiInformer.GetStore().List() {
+		vmi := obj.(*virtv1.VirtualMachineInstance)
+		transitions := vmi.Status.PhaseTransitionTimestamps
+		for i, transition := range transitions {
+			reason := ""
+			if transition.Phase == virtv1.Failed {
+				reason = vmiFailureReason(vmi)
+			}
+
+			end := now
+			if i+1 < len(transitions) {
+				end = transitions[i+1].PhaseTransitionTimestamp.Time
+			} else if vmi.IsFinal() {
+				// a final phase has no duration
+				end = transition.PhaseTransitionTimestamp.Time
+			}
+
+			labels := []string{vmi.Namespace, vmi.Name, string(transition.Phase), reason}
+			c.send(ch, vmi, vmiPhaseTransitionTimestampDesc, float64(transition.PhaseTransitionTimestamp.Unix()), labels)
+			c.send(ch, vmi, vmiPhaseDurationDesc, end.Sub(transition.PhaseTransitionTimestamp.Time).Seconds(), labels)
+		}
+	}
+}
+
+func (c *vmiPhaseTransitionCollector) send(ch chan<- prometheus.Metric, vmi *virtv1.VirtualMachineInstance, desc *prometheus.Desc, value float64, labels []string) {
+	mv, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to create the phase transition metric")
+		return
+	}
+	ch <- mv
+}
+
+// vmiFailureReason tells why the VMI failed, from the reason of its status or
+// else from the reason of the failed synchronization
+func vmiFailureReason(vmi *virtv1.VirtualMachineInstance) string {
+	if vmi.Status.Reason != "" {
+		return vmi.Status.Reason
+	}
+	cond := controller.NewVirtualMachineInstanceConditionManager().GetCondition(vmi, virtv1.VirtualMachineInstanceSynchronized)
+	if cond != nil && cond.Status == k8sv1.ConditionFalse {
+		return cond.Reason
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("VMI phase transition collector", func() {
+	var vmiInformer cache.SharedIndexInformer
+	var collector *vmiPhaseTransitionCollector
+	var now time.Time
+
+	type sample struct {
+		phase  string
+		reason string
+		value  float64
+	}
+
+	BeforeEach(func() {
+		vmiInformer, _ = testutils.NewFakeInformerFor(&virtv1.VirtualMachineInstance{})
+		collector = newVMIPhaseTransitionCollector(vmiInformer)
+		now = time.Unix(1000, 0)
+		collector.now = func() time.Time { return now }
+	})
+
+	transition := func(phase virtv1.VirtualMachineInstancePhase, seconds int64) virtv1.VirtualMachineInstancePhaseTransitionTimestamp {
+		return virtv1.VirtualMachineInstancePhaseTransitionTimestamp{
+			Phase:                    phase,
+			PhaseTransitionTimestamp: metav1.NewTime(time.Unix(seconds, 0)),
+		}
+	}
+
+	collect := func() (timestamps []sample, durations []sample) {
+		ch := make(chan prometheus.Metric, 20)
+		collector.Collect(ch)
+		close(ch)
+
+		for m := range ch {
+			dto := &io_prometheus_client.Metric{}
+			Expect(m.Write(dto)).To(Succeed())
+			s := sample{value: dto.GetGauge().GetValue()}
+			for _, pair := range dto.Label {
+				switch pair.GetName() {
+				case "phase":
+					s.phase = pair.GetValue()
+				case "reason":
+					s.reason = pair.GetValue()
+				}
+			}
+			if m.Desc() == vmiPhaseTransitionTimestampDesc {
+				timestamps = append(timestamps, s)
+			} else {
+				durations = append(durations, s)
+			}
+		}
+		return
+	}
+
+	It("should report the time spent in each phase, up to now for the current one", func() {
+		vmi := virtv1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = virtv1.Scheduled
+		vmi.Status.PhaseTransitionTimestamps = []virtv1.VirtualMachineInstancePhaseTransitionTimestamp{
+			transition(virtv1.Pending, 100),
+			transition(virtv1.Scheduling, 110),
+			transition(virtv1.Scheduled, 400),
+		}
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+
+		timestamps, durations := collect()
+		Expect(timestamps).To(ConsistOf(
+			sample{phase: "Pending", value: 100},
+			sample{phase: "Scheduling", value: 110},
+			sample{phase: "Scheduled", value: 400},
+		))
+		Expect(durations).To(ConsistOf(
+			sample{phase: "Pending", value: 10},
+			sample{phase: "Scheduling", value: 290},
+			sample{phase: "Scheduled", value: 600},
+		))
+	})
+
+	It("should report the reason of a failure", func() {
+		vmi := virtv1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = virtv1.Failed
+		vmi.Status.Conditions = []virtv1.VirtualMachineInstanceCondition{{
+			Type:   virtv1.VirtualMachineInstanceSynchronized,
+			Status: k8sv1.ConditionFalse,
+			Reason: FailedCreatePodReason,
+		}}
+		vmi.Status.PhaseTransitionTimestamps = []virtv1.VirtualMachineInstancePhaseTransitionTimestamp{
+			transition(virtv1.Pending, 100),
+			transition(virtv1.Failed, 130),
+		}
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+
+		_, durations := collect()
+		Expect(durations).To(ConsistOf(
+			sample{phase: "Pending", value: 30},
+			sample{phase: "Failed", reason: FailedCreatePodReason, value: 0},
+		))
+	})
+})
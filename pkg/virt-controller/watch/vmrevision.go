@@ -0,0 +1,151 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+)
+
+// templateRevisionHistoryLimit is the number of template revisions which are kept for every VirtualMachine
+const templateRevisionHistoryLimit = 10
+
+// templateRevisionName derives the name of the ControllerRevision from the serialized template,
+// so that the same template always ends up in the same revision
+func templateRevisionName(vmName string, data []byte) string {
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	return fmt.Sprintf("%s-template-%x", vmName, hasher.Sum32())
+}
+
+// listTemplateRevisions returns the template revisions of the VirtualMachine, the oldest one first
+func (c *VMController) listTemplateRevisions(vm *virtv1.VirtualMachine) ([]*appsv1.ControllerRevision, error) {
+	objs, err := c.templateRevisionInformer.GetIndexer().ByIndex(cache.NamespaceIndex, vm.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*appsv1.ControllerRevision
+	for _, obj := range objs {
+		revision := obj.(*appsv1.ControllerRevision)
+		if revision.Labels[virtv1.VirtualMachineTemplateRevisionLabel] == string(vm.UID) {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// syncTemplateRevisions records the current template of the VirtualMachine as its latest revision.
+// A template which is already stored in an older revision, e.g. after a rollback, moves that revision
+// to the top of the history instead of creating a new one. The oldest revisions beyond the history
+// limit are removed.
+func (c *VMController) syncTemplateRevisions(vm *virtv1.VirtualMachine) error {
+	data, err := json.Marshal(vm.Spec.Template)
+	if err != nil {
+		return err
+	}
+	name := templateRevisionName(vm.Name, data)
+
+	revisions, err := c.listTemplateRevisions(vm)
+	if err != nil {
+		return err
+	}
+
+	nextRevision := int64(1)
+	if len(revisions) > 0 {
+		nextRevision = revisions[len(revisions)-1].Revision + 1
+	}
+
+	index := -1
+	for i, revision := range revisions {
+		if revision.Name == name {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case index < 0:
+		revision := &appsv1.ControllerRevision{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: vm.Namespace,
+				Labels: map[string]string{
+					virtv1.VirtualMachineTemplateRevisionLabel: string(vm.UID),
+				},
+				OwnerReferences: []v1.OwnerReference{
+					*v1.NewControllerRef(vm, virtv1.VirtualMachineGroupVersionKind),
+				},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: nextRevision,
+		}
+		_, err := c.clientset.AppsV1().ControllerRevisions(vm.Namespace).Create(revision)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		revisions = append(revisions, revision)
+	case index < len(revisions)-1:
+		revision := revisions[index].DeepCopy()
+		revision.Revision = nextRevision
+		revision, err := c.clientset.AppsV1().ControllerRevisions(vm.Namespace).Update(revision)
+		if err != nil {
+			return err
+		}
+		revisions = append(append(revisions[:index], revisions[index+1:]...), revision)
+	}
+
+	for len(revisions) > templateRevisionHistoryLimit {
+		err := c.clientset.AppsV1().ControllerRevisions(vm.Namespace).Delete(revisions[0].Name, &v1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.Log.Object(vm).V(4).Infof("Removed template revision %s", revisions[0].Name)
+		revisions = revisions[1:]
+	}
+
+	// the rolled back template is the latest revision now, which completes the rollback
+	if revisionName, exists := vm.Annotations[virtv1.RollbackRevisionAnnotation]; exists {
+		path := "/metadata/annotations/" + strings.ReplaceAll(virtv1.RollbackRevisionAnnotation, "/", "~1")
+		patch := fmt.Sprintf(`[{ "op": "test", "path": "%s", "value": "%s" }, { "op": "remove", "path": "%s" }]`, path, revisionName, path)
+		if _, err := c.clientset.VirtualMachine(vm.Namespace).Patch(vm.Name, types.JSONPatchType, []byte(patch)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,417 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	virtv1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// SuccessfulWorkloadUpdateMigrationReason is added in an event if an outdated VMI is migrated to a new virt-launcher pod
+	SuccessfulWorkloadUpdateMigrationReason = "SuccessfulWorkloadUpdateMigration"
+	// SuccessfulWorkloadUpdateEvictionReason is added in an event if the outdated virt-launcher pod of a VMI is evicted
+	SuccessfulWorkloadUpdateEvictionReason = "SuccessfulWorkloadUpdateEviction"
+	// FailedWorkloadUpdateReason is added in an event if an outdated VMI could not be migrated or evicted
+	FailedWorkloadUpdateReason = "FailedWorkloadUpdate"
+)
+
+const (
+	defaultBatchEvictionSize     = 10
+	defaultBatchEvictionInterval = time.Minute
+)
+
+// the phases the outdated VMIs are reported in
+const (
+	workloadUpdatePhasePending   = "pending"
+	workloadUpdatePhaseMigrating = "migrating"
+	workloadUpdatePhaseEvicting  = "evicting"
+)
+
+var (
+	outdatedVMIsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_vmi_outdated_count",
+			Help: "Number of VMIs running in outdated virt-launcher pods, by workload update phase",
+		},
+		[]string{"phase"},
+	)
+	workloadUpdatesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_workload_updates_total",
+			Help: "Number of outdated VMIs migrated or evicted to update their virt-launcher pods, by update method",
+		},
+		[]string{"method"},
+	)
+)
+
+// WorkloadUpdateController moves the VMIs out of outdated virt-launcher pods
+// once KubeVirt was updated, according to the workload update strategy of the
+// KubeVirt CR. Live migratable VMIs are migrated, at most as many at once as the
+// cluster allows parallel migrations, and the launcher pods of the other VMIs
+// are evicted in batches.
+type WorkloadUpdateController struct {
+	clientset         kubecli.KubevirtClient
+	Queue             workqueue.RateLimitingInterface
+	vmiInformer       cache.SharedIndexInformer
+	podInformer       cache.SharedIndexInformer
+	migrationInformer cache.SharedIndexInformer
+	kubeVirtInformer  cache.SharedIndexInformer
+	recorder          record.EventRecorder
+	clusterConfig     *virtconfig.ClusterConfig
+	launcherImage     string
+
+	// lastEvictionBatch is when the last batch of launcher pods was evicted
+	lastEvictionBatch     time.Time
+	lastEvictionBatchLock sync.Mutex
+}
+
+func NewWorkloadUpdateController(
+	vmiInformer cache.SharedIndexInformer,
+	podInformer cache.SharedIndexInformer,
+	migrationInformer cache.SharedIndexInformer,
+	kubeVirtInformer cache.SharedIndexInformer,
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	clusterConfig *virtconfig.ClusterConfig,
+	launcherImage string,
+) *WorkloadUpdateController {
+
+	c := &WorkloadUpdateController{
+		clientset:         clientset,
+		Queue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		vmiInformer:       vmiInformer,
+		podInformer:       podInformer,
+		migrationInformer: migrationInformer,
+		kubeVirtInformer:  kubeVirtInformer,
+		recorder:          recorder,
+		clusterConfig:     clusterConfig,
+		launcherImage:     launcherImage,
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueKubeVirt,
+		DeleteFunc: c.enqueueKubeVirt,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueKubeVirt(curr) },
+	}
+	c.vmiInformer.AddEventHandler(handler)
+	c.migrationInformer.AddEventHandler(handler)
+	c.kubeVirtInformer.AddEventHandler(handler)
+
+	return c
+}
+
+func (c *WorkloadUpdateController) Run(threadiness int, stopCh <-chan struct{}) {
+	defer controller.HandlePanic()
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting workload update controller.")
+
+	// Wait for cache sync before we start the workload update controller
+	cache.WaitForCacheSync(stopCh, c.vmiInformer.HasSynced, c.podInformer.HasSynced, c.migrationInformer.HasSynced, c.kubeVirtInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping workload update controller.")
+}
+
+func (c *WorkloadUpdateController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *WorkloadUpdateController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	err := c.execute(key.(string))
+
+	if err != nil {
+		log.Log.Reason(err).Infof("reenqueuing workload update for KubeVirt %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed workload update for KubeVirt %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+// outdatedVMI is a VMI running in an outdated virt-launcher pod
+type outdatedVMI struct {
+	vmi   *virtv1.VirtualMachineInstance
+	pod   *k8sv1.Pod
+	phase string
+}
+
+func (c *WorkloadUpdateController) execute(key string) error {
+	obj, exists, err := c.kubeVirtInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	kv := obj.(*virtv1.KubeVirt)
+
+	outdated, err := c.outdatedVMIs()
+	if err != nil {
+		return err
+	}
+	phases := map[string]int{
+		workloadUpdatePhasePending:   0,
+		workloadUpdatePhaseMigrating: 0,
+		workloadUpdatePhaseEvicting:  0,
+	}
+	for _, o := range outdated {
+		phases[o.phase]++
+	}
+	for phase, count := range phases {
+		outdatedVMIsGauge.WithLabelValues(phase).Set(float64(count))
+	}
+
+	strategy := kv.Spec.WorkloadUpdateStrategy
+	if strategy == nil || kv.DeletionTimestamp != nil || phases[workloadUpdatePhasePending] == 0 {
+		return nil
+	}
+	// the workloads are only updated once the update of KubeVirt itself completed
+	if kv.Status.Phase != virtv1.KubeVirtPhaseDeployed || kv.Status.ObservedDeploymentID != kv.Status.TargetDeploymentID {
+		return nil
+	}
+
+	migrate, evict := false, false
+	for _, method := range strategy.WorkloadUpdateMethods {
+		switch method {
+		case virtv1.WorkloadUpdateMethodLiveMigrate:
+			migrate = true
+		case virtv1.WorkloadUpdateMethodEvict:
+			evict = true
+		}
+	}
+
+	var toMigrate, toEvict []outdatedVMI
+	for _, o := range outdated {
+		if o.phase != workloadUpdatePhasePending {
+			continue
+		}
+		if migrate && isLiveMigratable(o.vmi) {
+			toMigrate = append(toMigrate, o)
+		} else if evict {
+			toEvict = append(toEvict, o)
+		}
+	}
+
+	if err := c.migrate(toMigrate); err != nil {
+		return err
+	}
+	return c.evict(key, strategy, toEvict)
+}
+
+// migrate creates migrations for the outdated VMIs, at most as many as the
+// parallel migrations per cluster allow besides the running update migrations
+func (c *WorkloadUpdateController) migrate(outdated []outdatedVMI) error {
+	if len(outdated) == 0 {
+		return nil
+	}
+	running := 0
+	for _, obj := range c.migrationInformer.GetStore().List() {
+		migration := obj.(*virtv1.VirtualMachineInstanceMigration)
+		if _, isUpdate := migration.Annotations[virtv1.WorkloadUpdateMigrationAnnotation]; isUpdate && !migration.IsFinal() {
+			running++
+		}
+	}
+	limit := int(*c.clusterConfig.GetMigrationConfiguration().ParallelMigrationsPerCluster)
+
+	for i := 0; i < len(outdated) && running < limit; i++ {
+		vmi := outdated[i].vmi
+		migration := &virtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: v1.ObjectMeta{
+				GenerateName: "kubevirt-workload-update-",
+				Annotations: map[string]string{
+					virtv1.WorkloadUpdateMigrationAnnotation: "",
+				},
+			},
+			Spec: virtv1.VirtualMachineInstanceMigrationSpec{
+				VMIName: vmi.Name,
+			},
+		}
+		migration, err := c.clientset.VirtualMachineInstanceMigration(vmi.Namespace).Create(migration)
+		if err != nil {
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedWorkloadUpdateReason, "Error migrating VirtualMachineInstance out of outdated virt-launcher pod: %v", err)
+			return err
+		}
+		running++
+		workloadUpdatesCounter.WithLabelValues(string(virtv1.WorkloadUpdateMethodLiveMigrate)).Inc()
+		c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulWorkloadUpdateMigrationReason, "Migrating VirtualMachineInstance out of outdated virt-launcher pod with migration %s", migration.Name)
+	}
+	return nil
+}
+
+// evict evicts the launcher pods of the outdated VMIs, one batch per batch interval
+func (c *WorkloadUpdateController) evict(key string, strategy *virtv1.KubeVirtWorkloadUpdateStrategy, outdated []outdatedVMI) error {
+	if len(outdated) == 0 {
+		return nil
+	}
+	batchSize := defaultBatchEvictionSize
+	if strategy.BatchEvictionSize != nil {
+		batchSize = int(*strategy.BatchEvictionSize)
+	}
+	interval := defaultBatchEvictionInterval
+	if strategy.BatchEvictionInterval != nil {
+		interval = strategy.BatchEvictionInterval.Duration
+	}
+
+	c.lastEvictionBatchLock.Lock()
+	defer c.lastEvictionBatchLock.Unlock()
+	if remaining := c.lastEvictionBatch.Add(interval).Sub(time.Now()); remaining > 0 {
+		c.Queue.AddAfter(key, remaining)
+		return nil
+	}
+	c.lastEvictionBatch = time.Now()
+
+	for i := 0; i < len(outdated) && i < batchSize; i++ {
+		vmi, pod := outdated[i].vmi, outdated[i].pod
+		err := c.clientset.CoreV1().Pods(pod.Namespace).Evict(&policyv1beta1.Eviction{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		})
+		if errors.IsTooManyRequests(err) {
+			// a disruption budget protects the VMI
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedWorkloadUpdateReason, "Eviction of outdated virt-launcher pod %s was refused: %v", pod.Name, err)
+			continue
+		} else if err != nil && !errors.IsNotFound(err) {
+			c.recorder.Eventf(vmi, k8sv1.EventTypeWarning, FailedWorkloadUpdateReason, "Error evicting outdated virt-launcher pod %s: %v", pod.Name, err)
+			return err
+		}
+		workloadUpdatesCounter.WithLabelValues(string(virtv1.WorkloadUpdateMethodEvict)).Inc()
+		c.recorder.Eventf(vmi, k8sv1.EventTypeNormal, SuccessfulWorkloadUpdateEvictionReason, "Evicted outdated virt-launcher pod %s", pod.Name)
+	}
+	if len(outdated) > batchSize {
+		c.Queue.AddAfter(key, interval)
+	}
+	return nil
+}
+
+// outdatedVMIs returns the running VMIs whose launcher pod doesn't run the
+// current virt-launcher image, sorted by namespace and name
+func (c *WorkloadUpdateController) outdatedVMIs() ([]outdatedVMI, error) {
+	migrating := map[string]bool{}
+	for _, obj := range c.migrationInformer.GetStore().List() {
+		migration := obj.(*virtv1.VirtualMachineInstanceMigration)
+		if !migration.IsFinal() {
+			migrating[migration.Namespace+"/"+migration.Spec.VMIName] = true
+		}
+	}
+
+	var outdated []outdatedVMI
+	for _, obj := range c.vmiInformer.GetStore().List() {
+		vmi := obj.(*virtv1.VirtualMachineInstance)
+		if vmi.Status.Phase != virtv1.Running || vmi.DeletionTimestamp != nil {
+			continue
+		}
+		pod, err := c.launcherPod(vmi)
+		if err != nil {
+			return nil, err
+		}
+		if pod == nil || podLauncherImage(pod) == c.launcherImage {
+			continue
+		}
+
+		o := outdatedVMI{vmi: vmi, pod: pod, phase: workloadUpdatePhasePending}
+		if pod.DeletionTimestamp != nil {
+			o.phase = workloadUpdatePhaseEvicting
+		} else if migrating[vmi.Namespace+"/"+vmi.Name] || vmi.Status.MigrationState != nil && !vmi.Status.MigrationState.Completed {
+			o.phase = workloadUpdatePhaseMigrating
+		}
+		outdated = append(outdated, o)
+	}
+
+	sort.Slice(outdated, func(i, j int) bool {
+		a, b := outdated[i].vmi, outdated[j].vmi
+		return a.Namespace < b.Namespace || a.Namespace == b.Namespace && a.Name < b.Name
+	})
+	return outdated, nil
+}
+
+// launcherPod returns the most recent launcher pod of the VMI on its node
+func (c *WorkloadUpdateController) launcherPod(vmi *virtv1.VirtualMachineInstance) (*k8sv1.Pod, error) {
+	objs, err := c.podInformer.GetIndexer().ByIndex(cache.NamespaceIndex, vmi.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var current *k8sv1.Pod
+	for _, obj := range objs {
+		pod := obj.(*k8sv1.Pod)
+		if !controller.IsControlledBy(pod, vmi) || pod.Spec.NodeName != vmi.Status.NodeName {
+			continue
+		}
+		if current == nil || current.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			current = pod
+		}
+	}
+	return current, nil
+}
+
+func (c *WorkloadUpdateController) enqueueKubeVirt(_ interface{}) {
+	for _, key := range c.kubeVirtInformer.GetStore().ListKeys() {
+		c.Queue.Add(key)
+	}
+}
+
+func podLauncherImage(pod *k8sv1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "compute" {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+func isLiveMigratable(vmi *virtv1.VirtualMachineInstance) bool {
+	for _, condition := range vmi.Status.Conditions {
+		if condition.Type == virtv1.VirtualMachineInstanceIsMigratable {
+			return condition.Status == k8sv1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/testutils"
+)
+
+var _ = Describe("Workload update controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	const (
+		namespace   = k8sv1.NamespaceDefault
+		newImage    = "virt-launcher:new"
+		oldImage    = "virt-launcher:old"
+		kubeVirtKey = "kubevirt/kubevirt"
+	)
+
+	var ctrl *gomock.Controller
+	var migrationInterface *kubecli.MockVirtualMachineInstanceMigrationInterface
+	var kubeClient *fake.Clientset
+	var vmiInformer cache.SharedIndexInformer
+	var podInformer cache.SharedIndexInformer
+	var migrationInformer cache.SharedIndexInformer
+	var kubeVirtInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var controller *WorkloadUpdateController
+	var kv *v1.KubeVirt
+	var evicted []string
+
+	addVMI := func(name string, image string, migratable bool) {
+		vmi := v1.NewMinimalVMI(name)
+		vmi.UID = types.UID(name)
+		vmi.Status.Phase = v1.Running
+		vmi.Status.NodeName = "node01"
+		if migratable {
+			vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{{
+				Type:   v1.VirtualMachineInstanceIsMigratable,
+				Status: k8sv1.ConditionTrue,
+			}}
+		}
+		Expect(vmiInformer.GetStore().Add(vmi)).To(Succeed())
+
+		pod := &k8sv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virt-launcher-" + name,
+				Namespace: namespace,
+				Labels:    map[string]string{v1.CreatedByLabel: name},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(vmi, v1.VirtualMachineInstanceGroupVersionKind),
+				},
+			},
+			Spec: k8sv1.PodSpec{
+				NodeName:   "node01",
+				Containers: []k8sv1.Container{{Name: "compute", Image: image}},
+			},
+		}
+		Expect(podInformer.GetStore().Add(pod)).To(Succeed())
+	}
+
+	expectMigration := func(vmiName string) {
+		migrationInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(migration *v1.VirtualMachineInstanceMigration) (*v1.VirtualMachineInstanceMigration, error) {
+			Expect(migration.Spec.VMIName).To(Equal(vmiName))
+			Expect(migration.Annotations).To(HaveKey(v1.WorkloadUpdateMigrationAnnotation))
+			migration.Name = "kubevirt-workload-update-" + vmiName
+			return migration, nil
+		})
+	}
+
+	setStrategy := func(batchSize int32, methods ...v1.WorkloadUpdateMethod) {
+		kv.Spec.WorkloadUpdateStrategy = &v1.KubeVirtWorkloadUpdateStrategy{
+			WorkloadUpdateMethods: methods,
+			BatchEvictionSize:     &batchSize,
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		migrationInterface = kubecli.NewMockVirtualMachineInstanceMigrationInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstanceMigration(namespace).Return(migrationInterface).AnyTimes()
+		kubeClient = fake.NewSimpleClientset()
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		evicted = nil
+		kubeClient.Fake.PrependReactor("create", "pods", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			create := action.(testing.CreateAction)
+			Expect(create.GetSubresource()).To(Equal("eviction"))
+			name := create.GetObject().(metav1.Object).GetName()
+			if name == "virt-launcher-protected" {
+				return true, nil, errors.NewTooManyRequests("disruption budget", 0)
+			}
+			evicted = append(evicted, name)
+			return true, nil, nil
+		})
+
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		podInformer, _ = testutils.NewFakeInformerFor(&k8sv1.Pod{})
+		migrationInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstanceMigration{})
+		kubeVirtInformer, _ = testutils.NewFakeInformerFor(&v1.KubeVirt{})
+		recorder = record.NewFakeRecorder(100)
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+
+		kv = &v1.KubeVirt{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt"},
+			Status: v1.KubeVirtStatus{
+				Phase:                v1.KubeVirtPhaseDeployed,
+				TargetDeploymentID:   "42",
+				ObservedDeploymentID: "42",
+			},
+		}
+		Expect(kubeVirtInformer.GetStore().Add(kv)).To(Succeed())
+
+		controller = NewWorkloadUpdateController(vmiInformer, podInformer, migrationInformer, kubeVirtInformer, recorder, virtClient, clusterConfig, newImage)
+	})
+
+	AfterEach(func() {
+		Expect(recorder.Events).To(BeEmpty())
+		ctrl.Finish()
+	})
+
+	It("should migrate live migratable VMIs and evict the others", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodLiveMigrate, v1.WorkloadUpdateMethodEvict)
+		addVMI("migratable", oldImage, true)
+		addVMI("nonmigratable", oldImage, false)
+		addVMI("updated", newImage, true)
+		expectMigration("migratable")
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(ConsistOf("virt-launcher-nonmigratable"))
+		testutils.ExpectEvents(recorder, SuccessfulWorkloadUpdateMigrationReason, SuccessfulWorkloadUpdateEvictionReason)
+	})
+
+	It("should only migrate VMIs if eviction is not allowed", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodLiveMigrate)
+		addVMI("migratable", oldImage, true)
+		addVMI("nonmigratable", oldImage, false)
+		expectMigration("migratable")
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(BeEmpty())
+		testutils.ExpectEvent(recorder, SuccessfulWorkloadUpdateMigrationReason)
+	})
+
+	It("should not migrate VMIs which are already migrating", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodLiveMigrate)
+		addVMI("migratable", oldImage, true)
+		Expect(migrationInformer.GetStore().Add(&v1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: namespace},
+			Spec:       v1.VirtualMachineInstanceMigrationSpec{VMIName: "migratable"},
+		})).To(Succeed())
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+	})
+
+	It("should not create more update migrations than allowed in parallel", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodLiveMigrate)
+		for i := 0; i < 5; i++ {
+			Expect(migrationInformer.GetStore().Add(&v1.VirtualMachineInstanceMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf("update-%d", i),
+					Namespace:   namespace,
+					Annotations: map[string]string{v1.WorkloadUpdateMigrationAnnotation: ""},
+				},
+				Spec: v1.VirtualMachineInstanceMigrationSpec{VMIName: fmt.Sprintf("other-%d", i)},
+			})).To(Succeed())
+		}
+		addVMI("migratable", oldImage, true)
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+	})
+
+	It("should evict the launcher pods in batches", func() {
+		setStrategy(2, v1.WorkloadUpdateMethodEvict)
+		addVMI("a", oldImage, false)
+		addVMI("b", oldImage, false)
+		addVMI("c", oldImage, false)
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(Equal([]string{"virt-launcher-a", "virt-launcher-b"}))
+		testutils.ExpectEvents(recorder, SuccessfulWorkloadUpdateEvictionReason, SuccessfulWorkloadUpdateEvictionReason)
+
+		// the next batch waits for the batch interval
+		Expect(podInformer.GetStore().Delete(&k8sv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "virt-launcher-a", Namespace: namespace}})).To(Succeed())
+		Expect(podInformer.GetStore().Delete(&k8sv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "virt-launcher-b", Namespace: namespace}})).To(Succeed())
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(HaveLen(2))
+
+		controller.lastEvictionBatch = time.Now().Add(-defaultBatchEvictionInterval)
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(Equal([]string{"virt-launcher-a", "virt-launcher-b", "virt-launcher-c"}))
+		testutils.ExpectEvent(recorder, SuccessfulWorkloadUpdateEvictionReason)
+	})
+
+	It("should report evictions refused by a disruption budget", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodEvict)
+		addVMI("protected", oldImage, false)
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(BeEmpty())
+		testutils.ExpectEvent(recorder, FailedWorkloadUpdateReason)
+	})
+
+	It("should wait until the update of KubeVirt completed", func() {
+		setStrategy(10, v1.WorkloadUpdateMethodLiveMigrate, v1.WorkloadUpdateMethodEvict)
+		kv.Status.TargetDeploymentID = "43"
+		addVMI("migratable", oldImage, true)
+		addVMI("nonmigratable", oldImage, false)
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(BeEmpty())
+	})
+
+	It("should not update workloads without a workload update strategy", func() {
+		addVMI("migratable", oldImage, true)
+		addVMI("nonmigratable", oldImage, false)
+
+		Expect(controller.execute(kubeVirtKey)).To(Succeed())
+		Expect(evicted).To(BeEmpty())
+	})
+})
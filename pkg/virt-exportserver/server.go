@@ -0,0 +1,316 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package exportserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+)
+
+const (
+	ManifestPath = "/manifest"
+
+	volumesPrefix = "/volumes/"
+	rawImageName  = "disk.img"
+	qcow2Name     = "disk.qcow2"
+)
+
+// RawPath is the path a volume is served from as raw image
+func RawPath(volume string) string {
+	return volumesPrefix + volume + "/" + rawImageName
+}
+
+// Qcow2Path is the path a volume is served from as qcow2 image
+func Qcow2Path(volume string) string {
+	return volumesPrefix + volume + "/" + qcow2Name
+}
+
+// Server serves the raw images of the exported volumes, qcow2 conversions
+// of them and a manifest to import them into another cluster
+type Server struct {
+	token string
+	// the URL the server is reachable at, the manifest import URLs are
+	// resolved against it
+	baseURL string
+	// volume name -> path of the raw image, either a file or a block device
+	volumes map[string]string
+	// A VirtualMachine whose DataVolumeTemplates import the exported volumes
+	// from paths relative to the server
+	manifest   []byte
+	scratchDir string
+
+	lock        sync.Mutex
+	conversions map[string]*sync.Mutex
+	// qcow2 image being written -> the size it will have once converted
+	reservations   map[string]uint64
+	convert        func(source, target string) error
+	measure        func(source string) (uint64, error)
+	availableSpace func(dir string) (uint64, error)
+}
+
+// InsufficientScratchSpaceError is returned if the scratch directory can not
+// hold the qcow2 image of a volume
+type InsufficientScratchSpaceError struct {
+	Required  uint64
+	Available uint64
+}
+
+func (e *InsufficientScratchSpaceError) Error() string {
+	return fmt.Sprintf("the conversion needs %d bytes of scratch space, %d bytes are available", e.Required, e.Available)
+}
+
+func NewServer(token string, baseURL string, volumes map[string]string, manifest []byte, scratchDir string) *Server {
+	return &Server{
+		token:          token,
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		volumes:        volumes,
+		manifest:       manifest,
+		scratchDir:     scratchDir,
+		conversions:    map[string]*sync.Mutex{},
+		reservations:   map[string]uint64{},
+		convert:        convertToQcow2,
+		measure:        measureQcow2,
+		availableSpace: availableSpace,
+	}
+}
+
+func convertToQcow2(source, target string) error {
+	out, err := exec.Command("qemu-img", "convert", "-f", "raw", "-O", "qcow2", source, target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("qemu-img failed: %v: %s", err, string(out))
+	}
+	return nil
+}
+
+// measureQcow2 returns the size of the qcow2 image of a raw image
+func measureQcow2(source string) (uint64, error) {
+	out, err := exec.Command("qemu-img", "measure", "--output=json", "-f", "raw", "-O", "qcow2", source).Output()
+	if err != nil {
+		return 0, fmt.Errorf("qemu-img failed: %v", err)
+	}
+	measurement := struct {
+		Required uint64 `json:"required"`
+	}{}
+	if err := json.Unmarshal(out, &measurement); err != nil {
+		return 0, fmt.Errorf("failed to parse the measurement of qemu-img: %v", err)
+	}
+	return measurement.Required, nil
+}
+
+func availableSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.URL.Path == ManifestPath {
+		s.serveManifest(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, volumesPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, volumesPrefix), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	path, exists := s.volumes[parts[0]]
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch parts[1] {
+	case rawImageName:
+		s.serveFile(w, r, path)
+	case qcow2Name:
+		target, err := s.qcow2Image(parts[0], path)
+		if spaceErr, ok := err.(*InsufficientScratchSpaceError); ok {
+			log.Log.Reason(err).Errorf("failed to convert volume %s to qcow2", parts[0])
+			http.Error(w, spaceErr.Error(), http.StatusInsufficientStorage)
+			return
+		} else if err != nil {
+			log.Log.Reason(err).Errorf("failed to convert volume %s to qcow2", parts[0])
+			http.Error(w, "failed to convert the volume to qcow2", http.StatusInternalServerError)
+			return
+		}
+		s.serveFile(w, r, target)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorized accepts the token in the token header, or as basic auth password
+// as presented by the CDI http importer
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get(v1.VirtualMachineExportTokenHeader)
+	if token == "" {
+		_, token, _ = r.BasicAuth()
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to open %s", path)
+		http.Error(w, "failed to open the volume", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	// ServeContent determines the size by seeking, which also works for block devices
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, filepath.Base(r.URL.Path), time.Time{}, f)
+}
+
+// qcow2Image converts the raw image on first use. Concurrent requests for
+// the same volume wait for the conversion.
+func (s *Server) qcow2Image(volume string, source string) (string, error) {
+	s.lock.Lock()
+	conversion, exists := s.conversions[volume]
+	if !exists {
+		conversion = &sync.Mutex{}
+		s.conversions[volume] = conversion
+	}
+	s.lock.Unlock()
+
+	conversion.Lock()
+	defer conversion.Unlock()
+
+	target := filepath.Join(s.scratchDir, volume+".qcow2")
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+	// a conversion which was interrupted by a restart of the server left its image behind
+	tmp := target + ".tmp"
+	os.Remove(tmp)
+
+	release, err := s.reserveScratchSpace(source, tmp)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	if err := s.convert(source, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return target, os.Rename(tmp, target)
+}
+
+// reserveScratchSpace makes sure the scratch directory can hold the qcow2
+// image of the volume, next to the images other conversions are writing.
+// Those already occupy the part they have written, so only the remainder is
+// subtracted from the available space.
+func (s *Server) reserveScratchSpace(source string, target string) (func(), error) {
+	required, err := s.measure(source)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	available, err := s.availableSpace(s.scratchDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine the available scratch space: %v", err)
+	}
+	free := available
+	for image, size := range s.reservations {
+		pending := size - writtenSize(image, size)
+		if pending >= free {
+			free = 0
+			break
+		}
+		free -= pending
+	}
+	if required > free {
+		return nil, &InsufficientScratchSpaceError{Required: required, Available: free}
+	}
+	s.reservations[target] = required
+	return func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		delete(s.reservations, target)
+	}, nil
+}
+
+// writtenSize returns how much of an image is already written, at most its
+// final size
+func writtenSize(image string, size uint64) uint64 {
+	info, err := os.Stat(image)
+	if err != nil || info.Size() < 0 {
+		return 0
+	}
+	if uint64(info.Size()) > size {
+		return size
+	}
+	return uint64(info.Size())
+}
+
+// serveManifest resolves the relative import URLs against the configured
+// base URL. The Host header is chosen by the client and is never used.
+func (s *Server) serveManifest(w http.ResponseWriter, r *http.Request) {
+	vm := &v1.VirtualMachine{}
+	if err := json.Unmarshal(s.manifest, vm); err != nil {
+		log.Log.Reason(err).Error("failed to parse the manifest")
+		http.Error(w, "failed to parse the manifest", http.StatusInternalServerError)
+		return
+	}
+	for i := range vm.Spec.DataVolumeTemplates {
+		source := vm.Spec.DataVolumeTemplates[i].Spec.Source.HTTP
+		if source != nil && strings.HasPrefix(source.URL, "/") {
+			source.URL = s.baseURL + source.URL
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(vm); err != nil {
+		log.Log.Reason(err).Error("failed to write the manifest")
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package exportserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+var _ = Describe("Export server", func() {
+
+	var tmpDir string
+	var server *Server
+	var conversions int
+
+	request := func(path string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "https://export.example.com"+path, nil)
+		if token != "" {
+			req.Header.Set(v1.VirtualMachineExportTokenHeader, token)
+		}
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "exportserver")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "disk.img"), []byte("rawdata"), 0644)).To(Succeed())
+
+		vm := &v1.VirtualMachine{
+			Spec: v1.VirtualMachineSpec{
+				DataVolumeTemplates: []cdiv1.DataVolume{{
+					Spec: cdiv1.DataVolumeSpec{
+						Source: cdiv1.DataVolumeSource{
+							HTTP: &cdiv1.DataVolumeSourceHTTP{URL: RawPath("disk0")},
+						},
+					},
+				}},
+			},
+		}
+		manifest, err := json.Marshal(vm)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = NewServer("secret", "https://virt-export-test.default.svc/", map[string]string{"disk0": filepath.Join(tmpDir, "disk.img")}, manifest, tmpDir)
+		conversions = 0
+		server.convert = func(source, target string) error {
+			conversions++
+			data, err := ioutil.ReadFile(source)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(target, append([]byte("qcow2:"), data...), 0644)
+		}
+		server.measure = func(source string) (uint64, error) {
+			return 1024, nil
+		}
+		server.availableSpace = func(dir string) (uint64, error) {
+			return 4096, nil
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	table.DescribeTable("should reject requests", func(token string) {
+		Expect(request(RawPath("disk0"), token).Code).To(Equal(http.StatusUnauthorized))
+	},
+		table.Entry("without a token", ""),
+		table.Entry("with a wrong token", "wrong"),
+	)
+
+	It("should accept the token as basic auth password", func() {
+		req := httptest.NewRequest(http.MethodGet, RawPath("disk0"), nil)
+		req.SetBasicAuth("any", "secret")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		Expect(rr.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should serve the raw image", func() {
+		rr := request(RawPath("disk0"), "secret")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(Equal("rawdata"))
+	})
+
+	It("should convert the image to qcow2 only once", func() {
+		for i := 0; i < 2; i++ {
+			rr := request(Qcow2Path("disk0"), "secret")
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Body.String()).To(Equal("qcow2:rawdata"))
+		}
+		Expect(conversions).To(Equal(1))
+	})
+
+	It("should report failed conversions", func() {
+		server.convert = func(source, target string) error {
+			return fmt.Errorf("no space left")
+		}
+		Expect(request(Qcow2Path("disk0"), "secret").Code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("should not convert without enough scratch space", func() {
+		server.measure = func(source string) (uint64, error) {
+			return 8192, nil
+		}
+		rr := request(Qcow2Path("disk0"), "secret")
+		Expect(rr.Code).To(Equal(http.StatusInsufficientStorage))
+		Expect(rr.Body.String()).To(ContainSubstring("needs 8192 bytes of scratch space, 4096 bytes are available"))
+		Expect(conversions).To(BeZero())
+	})
+
+	It("should count the scratch space of running conversions", func() {
+		server.measure = func(source string) (uint64, error) {
+			return 1536, nil
+		}
+		release, err := server.reserveScratchSpace("other", filepath.Join(tmpDir, "a.qcow2.tmp"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = server.reserveScratchSpace("other", filepath.Join(tmpDir, "b.qcow2.tmp"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = server.reserveScratchSpace("other", filepath.Join(tmpDir, "c.qcow2.tmp"))
+		Expect(err).To(MatchError(&InsufficientScratchSpaceError{Required: 1536, Available: 1024}))
+
+		release()
+		Expect(request(Qcow2Path("disk0"), "secret").Code).To(Equal(http.StatusOK))
+		Expect(server.reservations).To(HaveLen(1))
+	})
+
+	It("should not count the written part of running conversions twice", func() {
+		server.measure = func(source string) (uint64, error) {
+			return 2048, nil
+		}
+		available := uint64(4096)
+		server.availableSpace = func(dir string) (uint64, error) {
+			return available, nil
+		}
+		running := filepath.Join(tmpDir, "a.qcow2.tmp")
+		_, err := server.reserveScratchSpace("other", running)
+		Expect(err).ToNot(HaveOccurred())
+
+		// the written part is already missing from the available space
+		Expect(ioutil.WriteFile(running, make([]byte, 1024), 0644)).To(Succeed())
+		available -= 1024
+		_, err = server.reserveScratchSpace("other", filepath.Join(tmpDir, "b.qcow2.tmp"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = server.reserveScratchSpace("other", filepath.Join(tmpDir, "c.qcow2.tmp"))
+		Expect(err).To(MatchError(&InsufficientScratchSpaceError{Required: 2048, Available: 0}))
+	})
+
+	It("should remove the image of an interrupted conversion", func() {
+		Expect(ioutil.WriteFile(filepath.Join(tmpDir, "disk0.qcow2.tmp"), []byte("partial"), 0644)).To(Succeed())
+		server.convert = func(source, target string) error {
+			return fmt.Errorf("qemu-img failed")
+		}
+		Expect(request(Qcow2Path("disk0"), "secret").Code).To(Equal(http.StatusInternalServerError))
+		Expect(filepath.Join(tmpDir, "disk0.qcow2.tmp")).ToNot(BeAnExistingFile())
+	})
+
+	table.DescribeTable("should not find", func(path string) {
+		Expect(request(path, "secret").Code).To(Equal(http.StatusNotFound))
+	},
+		table.Entry("unknown volumes", RawPath("disk1")),
+		table.Entry("unknown formats", "/volumes/disk0/disk.vmdk"),
+		table.Entry("unknown paths", "/other"),
+	)
+
+	It("should resolve the manifest urls against the base url", func() {
+		rr := request(ManifestPath, "secret")
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		vm := &v1.VirtualMachine{}
+		Expect(json.Unmarshal(rr.Body.Bytes(), vm)).To(Succeed())
+		Expect(vm.Spec.DataVolumeTemplates[0].Spec.Source.HTTP.URL).To(Equal("https://virt-export-test.default.svc/volumes/disk0/disk.img"))
+	})
+})
@@ -0,0 +1,326 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"fmt"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+)
+
+const (
+	// SuccessfulBackupReason is added in an event if a backup was started
+	SuccessfulBackupReason = "SuccessfulBackup"
+	// FailedBackupReason is added in an event if a backup could not be started
+	FailedBackupReason = "FailedBackup"
+)
+
+// BackupController starts the backups of the VMIs running on this node in
+// their virt-launcher and stops them again once the backup is deleted.
+type BackupController struct {
+	clientset         kubecli.KubevirtClient
+	Queue             workqueue.RateLimitingInterface
+	host              string
+	vmBackupInformer  cache.SharedIndexInformer
+	vmiSourceInformer cache.SharedIndexInformer
+	recorder          record.EventRecorder
+	clusterConfig     *virtconfig.ClusterConfig
+	// launcherClient connects to the virt-launcher of a VMI, the caller
+	// closes the client
+	launcherClient func(vmi *v1.VirtualMachineInstance) (cmdclient.LauncherClient, error)
+}
+
+func NewBackupController(
+	recorder record.EventRecorder,
+	clientset kubecli.KubevirtClient,
+	host string,
+	vmBackupInformer cache.SharedIndexInformer,
+	vmiSourceInformer cache.SharedIndexInformer,
+	clusterConfig *virtconfig.ClusterConfig,
+) *BackupController {
+
+	c := &BackupController{
+		clientset:         clientset,
+		Queue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		host:              host,
+		vmBackupInformer:  vmBackupInformer,
+		vmiSourceInformer: vmiSourceInformer,
+		recorder:          recorder,
+		clusterConfig:     clusterConfig,
+		launcherClient:    newLauncherClient,
+	}
+
+	c.vmBackupInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMBackup,
+		DeleteFunc: c.enqueueVMBackup,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMBackup(curr) },
+	})
+	c.vmiSourceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueVMBackupsForSource,
+		DeleteFunc: c.enqueueVMBackupsForSource,
+		UpdateFunc: func(old, curr interface{}) { c.enqueueVMBackupsForSource(curr) },
+	})
+
+	return c
+}
+
+func newLauncherClient(vmi *v1.VirtualMachineInstance) (cmdclient.LauncherClient, error) {
+	socketFile, err := cmdclient.FindSocketOnHost(vmi)
+	if err != nil {
+		return nil, err
+	}
+	return cmdclient.NewClient(socketFile)
+}
+
+func (c *BackupController) Run(threadiness int, stopCh chan struct{}) {
+	defer c.Queue.ShutDown()
+	log.Log.Info("Starting backup controller.")
+
+	// The VMI source informer is started by the VirtualMachineController
+	cache.WaitForCacheSync(stopCh, c.vmBackupInformer.HasSynced, c.vmiSourceInformer.HasSynced)
+
+	// Start the actual work
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+	log.Log.Info("Stopping backup controller.")
+}
+
+func (c *BackupController) runWorker() {
+	for c.Execute() {
+	}
+}
+
+func (c *BackupController) Execute() bool {
+	key, quit := c.Queue.Get()
+	if quit {
+		return false
+	}
+	defer c.Queue.Done(key)
+	if err := c.execute(key.(string)); err != nil {
+		log.Log.Reason(err).Infof("re-enqueuing VirtualMachineBackup %v", key)
+		c.Queue.AddRateLimited(key)
+	} else {
+		log.Log.V(4).Infof("processed VirtualMachineBackup %v", key)
+		c.Queue.Forget(key)
+	}
+	return true
+}
+
+func (c *BackupController) execute(key string) error {
+	obj, exists, err := c.vmBackupInformer.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	backup := obj.(*v1.VirtualMachineBackup)
+
+	vmi, err := c.localVMI(backup)
+	if err != nil {
+		return err
+	}
+	ownedByHost := backup.Labels[v1.NodeNameLabel] == c.host
+
+	if backup.DeletionTimestamp != nil {
+		if !ownedByHost || !controller.HasFinalizer(backup, v1.VirtualMachineBackupFinalizer) {
+			return nil
+		}
+		if vmi != nil && vmi.IsRunning() {
+			if err := c.abortBackup(vmi, backup); err != nil {
+				return err
+			}
+		}
+		backupCopy := backup.DeepCopy()
+		controller.RemoveFinalizer(backupCopy, v1.VirtualMachineBackupFinalizer)
+		_, err := c.clientset.VirtualMachineBackup(backup.Namespace).Update(backupCopy)
+		return err
+	}
+
+	switch backup.Status.Phase {
+	case v1.VirtualMachineBackupFailed:
+		return nil
+	case v1.VirtualMachineBackupReady:
+		// The NBD server goes away with the domain
+		if ownedByHost && (vmi == nil || vmi.IsFinal()) {
+			return c.updateFailedStatus(backup, fmt.Sprintf("VirtualMachineInstance %s stopped", backup.Spec.Source))
+		}
+		return nil
+	}
+
+	// Only the handler of the node the VMI runs on starts the backup
+	if vmi == nil {
+		return nil
+	}
+	if !c.clusterConfig.IncrementalBackupEnabled() {
+		return c.updatePendingStatus(backup, "the IncrementalBackup feature gate is not enabled")
+	}
+	if !vmi.IsRunning() {
+		return c.updatePendingStatus(backup, fmt.Sprintf("VirtualMachineInstance %s is not running", vmi.Name))
+	}
+
+	// Keep the backup around until the handler stopped the backup job
+	if !ownedByHost || !controller.HasFinalizer(backup, v1.VirtualMachineBackupFinalizer) {
+		backupCopy := backup.DeepCopy()
+		if backupCopy.Labels == nil {
+			backupCopy.Labels = map[string]string{}
+		}
+		backupCopy.Labels[v1.NodeNameLabel] = c.host
+		controller.AddFinalizer(backupCopy, v1.VirtualMachineBackupFinalizer)
+		_, err := c.clientset.VirtualMachineBackup(backup.Namespace).Update(backupCopy)
+		return err
+	}
+
+	return c.startBackup(vmi, backup)
+}
+
+// localVMI returns the source VMI of the backup if it runs on this node
+func (c *BackupController) localVMI(backup *v1.VirtualMachineBackup) (*v1.VirtualMachineInstance, error) {
+	obj, exists, err := c.vmiSourceInformer.GetStore().GetByKey(backup.Namespace + "/" + backup.Spec.Source)
+	if err != nil || !exists {
+		return nil, err
+	}
+	vmi := obj.(*v1.VirtualMachineInstance)
+	if vmi.Status.NodeName != c.host {
+		return nil, nil
+	}
+	return vmi, nil
+}
+
+func (c *BackupController) startBackup(vmi *v1.VirtualMachineInstance, backup *v1.VirtualMachineBackup) error {
+	client, err := c.launcherClient(vmi)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	volumes, err := client.BackupVirtualMachine(vmi, backupOptions(backup))
+	if err != nil {
+		c.recorder.Eventf(backup, k8sv1.EventTypeWarning, FailedBackupReason, "Failed to start the backup: %v", err)
+		return c.updateFailedStatus(backup, err.Error())
+	}
+	c.recorder.Eventf(backup, k8sv1.EventTypeNormal, SuccessfulBackupReason, "Started the backup of %s", vmi.Name)
+
+	backupCopy := backup.DeepCopy()
+	backupCopy.Status = v1.VirtualMachineBackupStatus{
+		Phase:      v1.VirtualMachineBackupReady,
+		Checkpoint: backup.Name,
+		Volumes:    volumes,
+	}
+	return c.updateStatus(backup, backupCopy)
+}
+
+func (c *BackupController) abortBackup(vmi *v1.VirtualMachineInstance, backup *v1.VirtualMachineBackup) error {
+	client, err := c.launcherClient(vmi)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.AbortVirtualMachineBackup(vmi, backupOptions(backup))
+}
+
+func backupOptions(backup *v1.VirtualMachineBackup) *cmdclient.BackupOptions {
+	options := &cmdclient.BackupOptions{Name: backup.Name}
+	if backup.Spec.Incremental != nil {
+		options.Incremental = *backup.Spec.Incremental
+	}
+	return options
+}
+
+func (c *BackupController) updatePendingStatus(backup *v1.VirtualMachineBackup, msg string) error {
+	backupCopy := backup.DeepCopy()
+	backupCopy.Status = v1.VirtualMachineBackupStatus{
+		Phase:   v1.VirtualMachineBackupPending,
+		Message: msg,
+	}
+	return c.updateStatus(backup, backupCopy)
+}
+
+func (c *BackupController) updateFailedStatus(backup *v1.VirtualMachineBackup, msg string) error {
+	backupCopy := backup.DeepCopy()
+	backupCopy.Status = v1.VirtualMachineBackupStatus{
+		Phase:   v1.VirtualMachineBackupFailed,
+		Message: msg,
+	}
+	return c.updateStatus(backup, backupCopy)
+}
+
+func (c *BackupController) updateStatus(backup *v1.VirtualMachineBackup, backupCopy *v1.VirtualMachineBackup) error {
+	if equality.Semantic.DeepEqual(backup.Status, backupCopy.Status) {
+		return nil
+	}
+	_, err := c.clientset.VirtualMachineBackup(backup.Namespace).UpdateStatus(backupCopy)
+	return err
+}
+
+func (c *BackupController) enqueueVMBackup(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	backup, ok := obj.(*v1.VirtualMachineBackup)
+	if !ok {
+		return
+	}
+	key, err := controller.KeyFunc(backup)
+	if err != nil {
+		log.Log.Object(backup).Reason(err).Error("Failed to extract key from VirtualMachineBackup.")
+		return
+	}
+	c.Queue.Add(key)
+}
+
+// enqueueVMBackupsForSource enqueues the backups of a VMI whenever the VMI
+// changes
+func (c *BackupController) enqueueVMBackupsForSource(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	source, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	backups, err := c.vmBackupInformer.GetIndexer().ByIndex(cache.NamespaceIndex, source.GetNamespace())
+	if err != nil {
+		return
+	}
+	for _, obj := range backups {
+		backup := obj.(*v1.VirtualMachineBackup)
+		if backup.Spec.Source == source.GetName() {
+			c.enqueueVMBackup(backup)
+		}
+	}
+}
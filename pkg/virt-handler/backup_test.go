@@ -0,0 +1,226 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"fmt"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+)
+
+var _ = Describe("Backup controller", func() {
+	log.Log.SetIOWriter(GinkgoWriter)
+
+	var ctrl *gomock.Controller
+	var backupInterface *kubecli.MockVirtualMachineBackupInterface
+	var launcherClient *cmdclient.MockLauncherClient
+	var backupInformer cache.SharedIndexInformer
+	var vmiInformer cache.SharedIndexInformer
+	var configMapInformer cache.SharedIndexInformer
+	var recorder *record.FakeRecorder
+	var backupController *BackupController
+
+	const host = "master"
+	const key = k8sv1.NamespaceDefault + "/testbackup"
+
+	newBackup := func() *v1.VirtualMachineBackup {
+		return &v1.VirtualMachineBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "testbackup",
+				Namespace: k8sv1.NamespaceDefault,
+			},
+			Spec: v1.VirtualMachineBackupSpec{
+				Source: "testvmi",
+			},
+		}
+	}
+
+	newOwnedBackup := func() *v1.VirtualMachineBackup {
+		backup := newBackup()
+		backup.Labels = map[string]string{v1.NodeNameLabel: host}
+		controller.AddFinalizer(backup, v1.VirtualMachineBackupFinalizer)
+		return backup
+	}
+
+	newVMI := func(phase v1.VirtualMachineInstancePhase) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.Phase = phase
+		vmi.Status.NodeName = host
+		return vmi
+	}
+
+	enableFeatureGate := func() {
+		testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.IncrementalBackupGate},
+		})
+	}
+
+	expectStatus := func() *v1.VirtualMachineBackupStatus {
+		status := &v1.VirtualMachineBackupStatus{}
+		backupInterface.EXPECT().UpdateStatus(gomock.Any()).DoAndReturn(func(backup *v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error) {
+			*status = backup.Status
+			return backup, nil
+		})
+		return status
+	}
+
+	expectUpdate := func() *v1.VirtualMachineBackup {
+		updated := &v1.VirtualMachineBackup{}
+		backupInterface.EXPECT().Update(gomock.Any()).DoAndReturn(func(backup *v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error) {
+			*updated = *backup
+			return backup, nil
+		})
+		return updated
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		backupInterface = kubecli.NewMockVirtualMachineBackupInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineBackup(k8sv1.NamespaceDefault).Return(backupInterface).AnyTimes()
+		launcherClient = cmdclient.NewMockLauncherClient(ctrl)
+
+		backupInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineBackup{})
+		vmiInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
+		recorder = record.NewFakeRecorder(100)
+		var config *virtconfig.ClusterConfig
+		config, configMapInformer, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+
+		backupController = NewBackupController(recorder, virtClient, host, backupInformer, vmiInformer, config)
+		backupController.launcherClient = func(vmi *v1.VirtualMachineInstance) (cmdclient.LauncherClient, error) {
+			return launcherClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should ignore backups of VMIs on other nodes", func() {
+		vmi := newVMI(v1.Running)
+		vmi.Status.NodeName = "othernode"
+		backupInformer.GetStore().Add(newBackup())
+		vmiInformer.GetStore().Add(vmi)
+		Expect(backupController.execute(key)).To(Succeed())
+	})
+
+	It("should wait for the feature gate", func() {
+		backupInformer.GetStore().Add(newBackup())
+		vmiInformer.GetStore().Add(newVMI(v1.Running))
+		status := expectStatus()
+		Expect(backupController.execute(key)).To(Succeed())
+		Expect(status.Phase).To(Equal(v1.VirtualMachineBackupPending))
+		Expect(status.Message).To(ContainSubstring("feature gate"))
+	})
+
+	Context("with the feature gate enabled", func() {
+
+		BeforeEach(func() {
+			enableFeatureGate()
+		})
+
+		It("should wait for the VMI to run", func() {
+			backupInformer.GetStore().Add(newBackup())
+			vmiInformer.GetStore().Add(newVMI(v1.Scheduled))
+			status := expectStatus()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(status.Message).To(Equal("VirtualMachineInstance testvmi is not running"))
+		})
+
+		It("should claim the backup before starting it", func() {
+			backupInformer.GetStore().Add(newBackup())
+			vmiInformer.GetStore().Add(newVMI(v1.Running))
+			updated := expectUpdate()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(updated.Labels).To(HaveKeyWithValue(v1.NodeNameLabel, host))
+			Expect(controller.HasFinalizer(updated, v1.VirtualMachineBackupFinalizer)).To(BeTrue())
+		})
+
+		It("should start the backup and report the exported volumes", func() {
+			incremental := "lastbackup"
+			backup := newOwnedBackup()
+			backup.Spec.Incremental = &incremental
+			backupInformer.GetStore().Add(backup)
+			vmiInformer.GetStore().Add(newVMI(v1.Running))
+			volumes := []v1.VirtualMachineBackupVolume{{Name: "rootdisk", ExportName: "vda", DirtyBitmap: "backup-vda"}}
+			launcherClient.EXPECT().BackupVirtualMachine(gomock.Any(), &cmdclient.BackupOptions{Name: "testbackup", Incremental: "lastbackup"}).Return(volumes, nil)
+			launcherClient.EXPECT().Close()
+
+			status := expectStatus()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(status.Phase).To(Equal(v1.VirtualMachineBackupReady))
+			Expect(status.Checkpoint).To(Equal("testbackup"))
+			Expect(status.Volumes).To(Equal(volumes))
+			testutils.ExpectEvent(recorder, SuccessfulBackupReason)
+		})
+
+		It("should fail the backup if the launcher can not start it", func() {
+			backupInformer.GetStore().Add(newOwnedBackup())
+			vmiInformer.GetStore().Add(newVMI(v1.Running))
+			launcherClient.EXPECT().BackupVirtualMachine(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("unsupported"))
+			launcherClient.EXPECT().Close()
+
+			status := expectStatus()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(status.Phase).To(Equal(v1.VirtualMachineBackupFailed))
+			Expect(status.Message).To(Equal("unsupported"))
+			testutils.ExpectEvent(recorder, FailedBackupReason)
+		})
+
+		It("should fail a ready backup once the VMI is gone", func() {
+			backup := newOwnedBackup()
+			backup.Status.Phase = v1.VirtualMachineBackupReady
+			backupInformer.GetStore().Add(backup)
+
+			status := expectStatus()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(status.Phase).To(Equal(v1.VirtualMachineBackupFailed))
+		})
+
+		It("should abort the backup job before releasing a deleted backup", func() {
+			backup := newOwnedBackup()
+			backup.Status.Phase = v1.VirtualMachineBackupReady
+			now := metav1.Now()
+			backup.DeletionTimestamp = &now
+			backupInformer.GetStore().Add(backup)
+			vmiInformer.GetStore().Add(newVMI(v1.Running))
+			launcherClient.EXPECT().AbortVirtualMachineBackup(gomock.Any(), &cmdclient.BackupOptions{Name: "testbackup"}).Return(nil)
+			launcherClient.EXPECT().Close()
+
+			updated := expectUpdate()
+			Expect(backupController.execute(key)).To(Succeed())
+			Expect(controller.HasFinalizer(updated, v1.VirtualMachineBackupFinalizer)).To(BeFalse())
+		})
+	})
+})
@@ -75,10 +75,20 @@ type MigrationOptions struct {
 	AllowAutoConverge       bool
 }
 
+// BackupOptions select the checkpoint an incremental backup is based on
+type BackupOptions struct {
+	// The name of the backup, which is also the name of the checkpoint it records
+	Name string
+	// The checkpoint the backup is incremental to, if any
+	Incremental string
+}
+
 type LauncherClient interface {
 	SyncVirtualMachine(vmi *v1.VirtualMachineInstance, options *cmdv1.VirtualMachineOptions) error
 	PauseVirtualMachine(vmi *v1.VirtualMachineInstance) error
 	UnpauseVirtualMachine(vmi *v1.VirtualMachineInstance) error
+	FreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error
+	UnfreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error
 	SyncMigrationTarget(vmi *v1.VirtualMachineInstance) error
 	ShutdownVirtualMachine(vmi *v1.VirtualMachineInstance) error
 	KillVirtualMachine(vmi *v1.VirtualMachineInstance) error
@@ -88,9 +98,13 @@ type LauncherClient interface {
 	DeleteDomain(vmi *v1.VirtualMachineInstance) error
 	GetDomain() (*api.Domain, bool, error)
 	GetDomainStats() (*stats.DomainStats, bool, error)
+	StreamDomainStats(ctx context.Context, interval time.Duration, handler func(domainStats *stats.DomainStats, exists bool)) error
 	GetGuestInfo() (*v1.VirtualMachineInstanceGuestAgentInfo, error)
 	GetUsers() (v1.VirtualMachineInstanceGuestOSUserList, error)
 	GetFilesystems() (v1.VirtualMachineInstanceFileSystemList, error)
+	BackupVirtualMachine(vmi *v1.VirtualMachineInstance, options *BackupOptions) ([]v1.VirtualMachineBackupVolume, error)
+	AbortVirtualMachineBackup(vmi *v1.VirtualMachineInstance, options *BackupOptions) error
+	MemoryDumpVirtualMachine(vmi *v1.VirtualMachineInstance, fileName string) error
 	Ping() error
 	Close()
 }
@@ -380,6 +394,14 @@ func (c *VirtLauncherClient) UnpauseVirtualMachine(vmi *v1.VirtualMachineInstanc
 	return c.genericSendVMICmd("Unpause", c.v1client.UnpauseVirtualMachine, vmi, &cmdv1.VirtualMachineOptions{})
 }
 
+func (c *VirtLauncherClient) FreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error {
+	return c.genericSendVMICmd("Freeze", c.v1client.FreezeVirtualMachine, vmi, &cmdv1.VirtualMachineOptions{})
+}
+
+func (c *VirtLauncherClient) UnfreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error {
+	return c.genericSendVMICmd("Unfreeze", c.v1client.UnfreezeVirtualMachine, vmi, &cmdv1.VirtualMachineOptions{})
+}
+
 func (c *VirtLauncherClient) ShutdownVirtualMachine(vmi *v1.VirtualMachineInstance) error {
 	return c.genericSendVMICmd("Shutdown", c.v1client.ShutdownVirtualMachine, vmi, &cmdv1.VirtualMachineOptions{})
 }
@@ -463,9 +485,6 @@ func (c *VirtLauncherClient) GetDomain() (*api.Domain, bool, error) {
 }
 
 func (c *VirtLauncherClient) GetDomainStats() (*stats.DomainStats, bool, error) {
-	stats := &stats.DomainStats{}
-	exists := false
-
 	request := &cmdv1.EmptyRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
 	defer cancel()
@@ -477,17 +496,77 @@ func (c *VirtLauncherClient) GetDomainStats() (*stats.DomainStats, bool, error)
 	}
 
 	if err = handleError(err, "GetDomainStats", response); err != nil {
-		return stats, exists, err
+		return &stats.DomainStats{}, false, err
 	}
 
-	if domainStatsRespose.DomainStats != "" {
-		if err := json.Unmarshal([]byte(domainStatsRespose.DomainStats), stats); err != nil {
-			log.Log.Reason(err).Error("error unmarshalling domain")
-			return stats, exists, err
+	return domainStatsFromResponse(domainStatsRespose)
+}
+
+// StreamDomainStats passes the stats of the domain to handler every interval, until ctx is done or the stream breaks.
+// Launchers which do not offer the stream yet are polled instead.
+func (c *VirtLauncherClient) StreamDomainStats(ctx context.Context, interval time.Duration, handler func(domainStats *stats.DomainStats, exists bool)) error {
+	request := &cmdv1.DomainStatsStreamRequest{
+		IntervalSeconds: uint32(interval / time.Second),
+	}
+	stream, err := c.v1client.StreamDomainStats(ctx, request)
+	if err != nil {
+		return handleError(err, "StreamDomainStats", nil)
+	}
+
+	for {
+		domainStatsResponse, err := stream.Recv()
+		if ctx.Err() != nil {
+			return nil
 		}
-		exists = true
+		if status.Code(err) == codes.Unimplemented {
+			log.Log.V(4).Info("launcher does not stream domain stats, polling them instead")
+			return c.pollDomainStats(ctx, interval, handler)
+		}
+
+		var response *cmdv1.Response
+		if domainStatsResponse != nil {
+			response = domainStatsResponse.Response
+		}
+		if err = handleError(err, "StreamDomainStats", response); err != nil {
+			return err
+		}
+
+		domainStats, exists, err := domainStatsFromResponse(domainStatsResponse)
+		if err != nil {
+			return err
+		}
+		handler(domainStats, exists)
 	}
-	return stats, exists, nil
+}
+
+func (c *VirtLauncherClient) pollDomainStats(ctx context.Context, interval time.Duration, handler func(domainStats *stats.DomainStats, exists bool)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		domainStats, exists, err := c.GetDomainStats()
+		if err != nil {
+			return err
+		}
+		handler(domainStats, exists)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func domainStatsFromResponse(domainStatsResponse *cmdv1.DomainStatsResponse) (*stats.DomainStats, bool, error) {
+	stats := &stats.DomainStats{}
+	if domainStatsResponse.DomainStats == "" {
+		return stats, false, nil
+	}
+	if err := json.Unmarshal([]byte(domainStatsResponse.DomainStats), stats); err != nil {
+		log.Log.Reason(err).Error("error unmarshalling domain")
+		return stats, false, err
+	}
+	return stats, true, nil
 }
 
 func (c *VirtLauncherClient) Ping() error {
@@ -590,3 +669,89 @@ func (c *VirtLauncherClient) GetFilesystems() (v1.VirtualMachineInstanceFileSyst
 
 	return filesystemList, nil
 }
+
+func newBackupRequest(vmi *v1.VirtualMachineInstance, options *BackupOptions) (*cmdv1.BackupRequest, error) {
+	vmiJson, err := json.Marshal(vmi)
+	if err != nil {
+		return nil, err
+	}
+
+	optionsJson, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cmdv1.BackupRequest{
+		Vmi: &cmdv1.VMI{
+			VmiJson: vmiJson,
+		},
+		Options: optionsJson,
+	}, nil
+}
+
+// BackupVirtualMachine starts a pull mode backup and returns the exported disks
+func (c *VirtLauncherClient) BackupVirtualMachine(vmi *v1.VirtualMachineInstance, options *BackupOptions) ([]v1.VirtualMachineBackupVolume, error) {
+	request, err := newBackupRequest(vmi, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), longTimeout)
+	defer cancel()
+
+	backupResponse, err := c.v1client.BackupVirtualMachine(ctx, request)
+	var response *cmdv1.Response
+	if backupResponse != nil {
+		response = backupResponse.Response
+	}
+
+	if err = handleError(err, "Backup", response); err != nil {
+		return nil, err
+	}
+
+	volumes := []v1.VirtualMachineBackupVolume{}
+	if backupResponse.GetBackupVolumes() != "" {
+		if err := json.Unmarshal([]byte(backupResponse.GetBackupVolumes()), &volumes); err != nil {
+			log.Log.Reason(err).Error("error unmarshalling backup volumes response")
+			return nil, err
+		}
+	}
+
+	return volumes, nil
+}
+
+// AbortVirtualMachineBackup ends a running backup, the checkpoint it recorded is kept
+func (c *VirtLauncherClient) AbortVirtualMachineBackup(vmi *v1.VirtualMachineInstance, options *BackupOptions) error {
+	request, err := newBackupRequest(vmi, options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+	defer cancel()
+
+	response, err := c.v1client.AbortVirtualMachineBackup(ctx, request)
+	return handleError(err, "AbortBackup", response)
+}
+
+// MemoryDumpVirtualMachine starts dumping the guest memory to the given file
+// of the mounted memory dump claim, it does not wait for the dump to finish
+func (c *VirtLauncherClient) MemoryDumpVirtualMachine(vmi *v1.VirtualMachineInstance, fileName string) error {
+	vmiJson, err := json.Marshal(vmi)
+	if err != nil {
+		return err
+	}
+
+	request := &cmdv1.MemoryDumpRequest{
+		Vmi: &cmdv1.VMI{
+			VmiJson: vmiJson,
+		},
+		FileName: fileName,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+	defer cancel()
+
+	response, err := c.v1client.MemoryDumpVirtualMachine(ctx, request)
+	return handleError(err, "MemoryDump", response)
+}
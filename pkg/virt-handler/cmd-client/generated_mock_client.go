@@ -4,7 +4,10 @@
 package cmdclient
 
 import (
+	time "time"
+
 	gomock "github.com/golang/mock/gomock"
+	context "golang.org/x/net/context"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	v10 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
@@ -63,6 +66,26 @@ func (_mr *_MockLauncherClientRecorder) UnpauseVirtualMachine(arg0 interface{})
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "UnpauseVirtualMachine", arg0)
 }
 
+func (_m *MockLauncherClient) FreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error {
+	ret := _m.ctrl.Call(_m, "FreezeVirtualMachine", vmi)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLauncherClientRecorder) FreezeVirtualMachine(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FreezeVirtualMachine", arg0)
+}
+
+func (_m *MockLauncherClient) UnfreezeVirtualMachine(vmi *v1.VirtualMachineInstance) error {
+	ret := _m.ctrl.Call(_m, "UnfreezeVirtualMachine", vmi)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLauncherClientRecorder) UnfreezeVirtualMachine(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UnfreezeVirtualMachine", arg0)
+}
+
 func (_m *MockLauncherClient) SyncMigrationTarget(vmi *v1.VirtualMachineInstance) error {
 	ret := _m.ctrl.Call(_m, "SyncMigrationTarget", vmi)
 	ret0, _ := ret[0].(error)
@@ -157,6 +180,16 @@ func (_mr *_MockLauncherClientRecorder) GetDomainStats() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetDomainStats")
 }
 
+func (_m *MockLauncherClient) StreamDomainStats(ctx context.Context, interval time.Duration, handler func(*stats.DomainStats, bool)) error {
+	ret := _m.ctrl.Call(_m, "StreamDomainStats", ctx, interval, handler)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLauncherClientRecorder) StreamDomainStats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StreamDomainStats", arg0, arg1, arg2)
+}
+
 func (_m *MockLauncherClient) GetGuestInfo() (*v1.VirtualMachineInstanceGuestAgentInfo, error) {
 	ret := _m.ctrl.Call(_m, "GetGuestInfo")
 	ret0, _ := ret[0].(*v1.VirtualMachineInstanceGuestAgentInfo)
@@ -190,6 +223,37 @@ func (_mr *_MockLauncherClientRecorder) GetFilesystems() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetFilesystems")
 }
 
+func (_m *MockLauncherClient) BackupVirtualMachine(vmi *v1.VirtualMachineInstance, options *BackupOptions) ([]v1.VirtualMachineBackupVolume, error) {
+	ret := _m.ctrl.Call(_m, "BackupVirtualMachine", vmi, options)
+	ret0, _ := ret[0].([]v1.VirtualMachineBackupVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockLauncherClientRecorder) BackupVirtualMachine(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BackupVirtualMachine", arg0, arg1)
+}
+
+func (_m *MockLauncherClient) AbortVirtualMachineBackup(vmi *v1.VirtualMachineInstance, options *BackupOptions) error {
+	ret := _m.ctrl.Call(_m, "AbortVirtualMachineBackup", vmi, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLauncherClientRecorder) AbortVirtualMachineBackup(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AbortVirtualMachineBackup", arg0, arg1)
+}
+
+func (_m *MockLauncherClient) MemoryDumpVirtualMachine(vmi *v1.VirtualMachineInstance, fileName string) error {
+	ret := _m.ctrl.Call(_m, "MemoryDumpVirtualMachine", vmi, fileName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLauncherClientRecorder) MemoryDumpVirtualMachine(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "MemoryDumpVirtualMachine", arg0, arg1)
+}
+
 func (_m *MockLauncherClient) Ping() error {
 	ret := _m.ctrl.Call(_m, "Ping")
 	ret0, _ := ret[0].(error)
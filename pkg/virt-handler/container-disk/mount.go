@@ -30,6 +30,8 @@ type mounter struct {
 type Mounter interface {
 	Mount(vmi *v1.VirtualMachineInstance, verify bool) error
 	Unmount(vmi *v1.VirtualMachineInstance) error
+	MountMemoryDump(vmi *v1.VirtualMachineInstance, attachPodUID types.UID) error
+	UnmountMemoryDump(vmi *v1.VirtualMachineInstance) error
 }
 
 type vmiMountTargetEntry struct {
@@ -190,6 +192,23 @@ func (m *mounter) Mount(vmi *v1.VirtualMachineInstance, verify bool) error {
 		}
 	}
 
+	if containerdisk.GetFirmwareContainerDisk(vmi) != nil {
+		targetDir, err := containerdisk.GetFirmwareTargetPathFromHostView(vmi)
+		if err != nil {
+			return err
+		}
+
+		sock, err := containerdisk.GetFirmwareSocketPathFromHostView(vmi)
+		if err != nil {
+			return err
+		}
+
+		record.MountTargetEntries = append(record.MountTargetEntries, vmiMountTargetEntry{
+			TargetFile: targetDir,
+			SocketFile: sock,
+		})
+	}
+
 	if len(record.MountTargetEntries) > 0 {
 		err := m.setMountTargetRecord(vmi, &record)
 		if err != nil {
@@ -261,6 +280,112 @@ func (m *mounter) Mount(vmi *v1.VirtualMachineInstance, verify bool) error {
 			}
 		}
 	}
+
+	if firmware := containerdisk.GetFirmwareContainerDisk(vmi); firmware != nil {
+		if err := m.mountFirmware(vmi, firmware); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mountFirmware makes the directory with the EFI roms of the firmware container disk visible for the qemu process.
+func (m *mounter) mountFirmware(vmi *v1.VirtualMachineInstance, firmware *v1.ContainerDiskSource) error {
+	targetDir, err := containerdisk.GetFirmwareTargetPathFromHostView(vmi)
+	if err != nil {
+		return err
+	}
+
+	nodeRes := isolation.NodeIsolationResult()
+
+	if isMounted, err := nodeRes.IsMounted(targetDir); err != nil {
+		return fmt.Errorf("failed to determine if %s is already mounted: %v", targetDir, err)
+	} else if isMounted {
+		return nil
+	}
+
+	sock, err := containerdisk.GetFirmwareSocketPathFromHostView(vmi)
+	if err != nil {
+		return err
+	}
+
+	res, err := m.podIsolationDetector.DetectForSocket(vmi, sock)
+	if err != nil {
+		return fmt.Errorf("failed to detect socket for the firmware containerDisk: %v", err)
+	}
+	mountInfo, err := res.MountInfoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to detect root mount info of the firmware containerDisk: %v", err)
+	}
+	nodeMountInfo, err := nodeRes.ParentMountInfoFor(mountInfo)
+	if err != nil {
+		return fmt.Errorf("failed to detect root mount point of the firmware containerDisk on the node: %v", err)
+	}
+	sourceDir, err := containerdisk.GetFirmwareDir(filepath.Join(nodeRes.MountRoot(), nodeMountInfo.Root, nodeMountInfo.MountPoint), firmware.Path)
+	if err != nil {
+		return fmt.Errorf("failed to find the firmware directory in the containerDisk: %v", err)
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create mount point target %v: %v", targetDir, err)
+	}
+
+	out, err := exec.Command("/usr/bin/virt-chroot", "--mount", "/proc/1/ns/mnt", "mount", "-o", "ro,bind", strings.TrimPrefix(sourceDir, nodeRes.MountRoot()), targetDir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to bindmount the firmware containerDisk: %v : %v", string(out), err)
+	}
+	return nil
+}
+
+// MountMemoryDump makes the claim mounted by the memory dump attach pod visible for the qemu process.
+func (m *mounter) MountMemoryDump(vmi *v1.VirtualMachineInstance, attachPodUID types.UID) error {
+	targetDir, err := containerdisk.GetMemoryDumpTargetPathFromHostView(vmi)
+	if err != nil {
+		return err
+	}
+
+	nodeRes := isolation.NodeIsolationResult()
+
+	if isMounted, err := nodeRes.IsMounted(targetDir); err != nil {
+		return fmt.Errorf("failed to determine if %s is already mounted: %v", targetDir, err)
+	} else if isMounted {
+		return nil
+	}
+
+	res, err := m.podIsolationDetector.DetectForSocket(vmi, containerdisk.GetMemoryDumpSocketPathFromHostView(attachPodUID))
+	if err != nil {
+		return fmt.Errorf("failed to detect socket for the memory dump attach pod: %v", err)
+	}
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create mount point target %v: %v", targetDir, err)
+	}
+
+	sourceDir := filepath.Join(res.MountRoot(), containerdisk.MemoryDumpClaimDir)
+	out, err := exec.Command("/usr/bin/virt-chroot", "--mount", "/proc/1/ns/mnt", "mount", "-o", "bind", sourceDir, targetDir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to bindmount the memory dump claim: %v : %v", string(out), err)
+	}
+	return nil
+}
+
+// UnmountMemoryDump unmounts the claim of a memory dump, if it is mounted.
+func (m *mounter) UnmountMemoryDump(vmi *v1.VirtualMachineInstance) error {
+	// nothing was mounted if the container disks volume is gone
+	if _, found, err := containerdisk.GetVolumeMountDirOnHost(vmi); err != nil || !found {
+		return err
+	}
+	targetDir, err := containerdisk.GetMemoryDumpTargetPathFromHostView(vmi)
+	if err != nil {
+		return err
+	}
+
+	if mounted, err := isolation.NodeIsolationResult().IsMounted(targetDir); err != nil {
+		return fmt.Errorf("failed to check mount point for memory dump %v: %v", targetDir, err)
+	} else if mounted {
+		out, err := exec.Command("/usr/bin/virt-chroot", "--mount", "/proc/1/ns/mnt", "umount", targetDir).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to unmount memory dump %v: %v : %v", targetDir, string(out), err)
+		}
+	}
 	return nil
 }
 
@@ -22,9 +22,15 @@ package device_manager
 import (
 	"math"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
+
 	"kubevirt.io/client-go/log"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 const (
@@ -34,25 +40,43 @@ const (
 	TunName      = "tun"
 	VhostNetPath = "/dev/vhost-net"
 	VhostNetName = "vhost-net"
+	SEVPath      = "/dev/sev"
+	SEVName      = "sev"
 )
 
 type DeviceController struct {
-	devicePlugins []GenericDevice
-	host          string
-	maxDevices    int
-	backoff       []time.Duration
+	devicePlugins      []GenericDevice
+	host               string
+	maxDevices         int
+	backoff            []time.Duration
+	clusterConfig      *virtconfig.ClusterConfig
+	refreshInterval    time.Duration
+	hostDevicePlugins  map[string]controlledDevice
+	permittedSelectors *permittedSelectors
+	lock               sync.Mutex
+}
+
+// controlledDevice is a device plugin which is started and stopped
+// depending on the permitted host devices of the cluster config
+type controlledDevice struct {
+	devicePlugin GenericDevice
+	stop         chan struct{}
 }
 
-func NewDeviceController(host string, maxDevices int) *DeviceController {
+func NewDeviceController(host string, maxDevices int, clusterConfig *virtconfig.ClusterConfig) *DeviceController {
 	return &DeviceController{
 		devicePlugins: []GenericDevice{
 			NewGenericDevicePlugin(KVMName, KVMPath, maxDevices, false),
 			NewGenericDevicePlugin(TunName, TunPath, maxDevices, true),
 			NewGenericDevicePlugin(VhostNetName, VhostNetPath, maxDevices, true),
+			NewGenericDevicePlugin(SEVName, SEVPath, maxDevices, true),
 		},
-		host:       host,
-		maxDevices: maxDevices,
-		backoff:    []time.Duration{1 * time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second},
+		host:              host,
+		maxDevices:        maxDevices,
+		backoff:           []time.Duration{1 * time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second},
+		clusterConfig:     clusterConfig,
+		refreshInterval:   1 * time.Minute,
+		hostDevicePlugins: map[string]controlledDevice{},
 	}
 }
 
@@ -95,8 +119,100 @@ func (c *DeviceController) Run(stop chan struct{}) error {
 		go c.startDevicePlugin(dev, stop)
 	}
 
+	go wait.Until(c.refreshPermittedHostDevices, c.refreshInterval, stop)
+
 	<-stop
 
+	c.lock.Lock()
+	for resourceName, dev := range c.hostDevicePlugins {
+		close(dev.stop)
+		delete(c.hostDevicePlugins, resourceName)
+	}
+	c.lock.Unlock()
+
 	logger.Info("Shutting down device plugin controller")
 	return nil
 }
+
+// permittedSelectors holds the resource names of the permitted host devices
+// which are exposed by virt-handler
+type permittedSelectors struct {
+	// pci is keyed by the lower case vendor selectors
+	pci map[string]string
+	// mdev is keyed by the mediated device type names
+	mdev map[string]string
+	// macvtap maps the host NICs exposed for macvtap interfaces to their index
+	macvtap map[string]int
+}
+
+func (c *DeviceController) getPermittedSelectors() *permittedSelectors {
+	selectors := &permittedSelectors{
+		pci:     map[string]string{},
+		mdev:    map[string]string{},
+		macvtap: map[string]int{},
+	}
+	if c.clusterConfig.MacvtapEnabled() {
+		selectors.macvtap = discoverMacvtapLowerDevices()
+	}
+	permittedHostDevices := c.clusterConfig.GetPermittedHostDevices()
+	if permittedHostDevices == nil {
+		return selectors
+	}
+	for _, pciDev := range permittedHostDevices.PciHostDevices {
+		if !pciDev.ExternalResourceProvider {
+			selectors.pci[strings.ToLower(pciDev.PCIVendorSelector)] = pciDev.ResourceName
+		}
+	}
+	for _, mdev := range permittedHostDevices.MediatedDevices {
+		if !mdev.ExternalResourceProvider {
+			selectors.mdev[strings.TrimSpace(mdev.MDEVNameSelector)] = mdev.ResourceName
+		}
+	}
+	return selectors
+}
+
+// refreshPermittedHostDevices (re)starts the PCI, mediated and macvtap device
+// plugins whenever the permitted host devices in the cluster config or the
+// NICs of the host change
+func (c *DeviceController) refreshPermittedHostDevices() {
+	logger := log.DefaultLogger()
+	selectors := c.getPermittedSelectors()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.permittedSelectors != nil && reflect.DeepEqual(selectors, c.permittedSelectors) {
+		return
+	}
+	c.permittedSelectors = selectors
+
+	for resourceName, dev := range c.hostDevicePlugins {
+		logger.Infof("Stopping the %s device plugin", resourceName)
+		close(dev.stop)
+		delete(c.hostDevicePlugins, resourceName)
+	}
+
+	for resourceName, pciDevices := range discoverPermittedHostPCIDevices(selectors.pci) {
+		c.startHostDevicePlugin(resourceName, NewPCIDevicePlugin(pciDevices, resourceName))
+	}
+	for resourceName, mdevs := range discoverPermittedMediatedDevices(selectors.mdev) {
+		if _, exists := c.hostDevicePlugins[resourceName]; exists {
+			logger.Warningf("Resource %s is used for PCI and mediated devices, only the PCI devices are exposed", resourceName)
+			continue
+		}
+		c.startHostDevicePlugin(resourceName, NewMediatedDevicePlugin(mdevs, resourceName))
+	}
+	for lowerDevice, lowerIndex := range selectors.macvtap {
+		devicePlugin := NewMacvtapDevicePlugin(lowerDevice, lowerIndex, c.maxDevices)
+		c.startHostDevicePlugin(devicePlugin.GetDeviceName(), devicePlugin)
+	}
+}
+
+func (c *DeviceController) startHostDevicePlugin(resourceName string, devicePlugin GenericDevice) {
+	dev := controlledDevice{
+		devicePlugin: devicePlugin,
+		stop:         make(chan struct{}),
+	}
+	c.hostDevicePlugins[resourceName] = dev
+	go c.startDevicePlugin(dev.devicePlugin, dev.stop)
+}
@@ -10,6 +10,11 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	k8sv1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 type FakePlugin struct {
@@ -44,6 +49,7 @@ var _ = Describe("Device Controller", func() {
 	var err error
 	var host string
 	var stop chan struct{}
+	clusterConfig, configMapInformer, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
 
 	BeforeEach(func() {
 		workDir, err = ioutil.TempDir("", "kubevirt-test")
@@ -60,7 +66,7 @@ var _ = Describe("Device Controller", func() {
 
 	Context("Basic Tests", func() {
 		It("Should indicate if node has device", func() {
-			deviceController := NewDeviceController(host, 10)
+			deviceController := NewDeviceController(host, 10, clusterConfig)
 			devicePath := path.Join(workDir, "fake-device")
 			res := deviceController.nodeHasDevice(devicePath)
 			Expect(res).To(BeFalse())
@@ -74,6 +80,97 @@ var _ = Describe("Device Controller", func() {
 		})
 	})
 
+	Context("with permitted host devices", func() {
+		var originalPCIBasePath string
+		var originalMdevBasePath string
+
+		hostDevicePluginNames := func(deviceController *DeviceController) []string {
+			deviceController.lock.Lock()
+			defer deviceController.lock.Unlock()
+			names := []string{}
+			for resourceName := range deviceController.hostDevicePlugins {
+				names = append(names, resourceName)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			originalPCIBasePath = pciBasePath
+			pciBasePath = path.Join(workDir, "devices")
+			createFakePCIDevice(workDir, "0000:00:01.0", "0x8086", "0x6f54", vfioDriver, "12")
+			originalMdevBasePath = mdevBasePath
+			mdevBasePath = path.Join(workDir, "mdevs")
+			createFakeMediatedDevice(workDir, "4b20d080-1b54-4048-85b3-a6a62d165c01", "nvidia-222", "GRID T4-1Q", "21")
+		})
+
+		AfterEach(func() {
+			pciBasePath = originalPCIBasePath
+			mdevBasePath = originalMdevBasePath
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{})
+		})
+
+		It("should start and stop device plugins for the permitted devices", func() {
+			deviceController := NewDeviceController(host, 10, clusterConfig)
+			deviceController.devicePlugins = []GenericDevice{}
+			deviceController.refreshInterval = 10 * time.Millisecond
+			go deviceController.Run(stop)
+			Consistently(func() []string {
+				return hostDevicePluginNames(deviceController)
+			}, 100*time.Millisecond).Should(BeEmpty())
+
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.PermittedHostDevicesKey: `
+pciHostDevices:
+- pciVendorSelector: "8086:6F54"
+  resourceName: "intel.com/nvme"
+- pciVendorSelector: "10de:1eb8"
+  resourceName: "nvidia.com/TU104GL_Tesla_T4"
+mediatedDevices:
+- mdevNameSelector: "GRID T4-1Q"
+  resourceName: "nvidia.com/GRID_T4-1Q"
+`},
+			})
+			Eventually(func() []string {
+				return hostDevicePluginNames(deviceController)
+			}).Should(ConsistOf("intel.com/nvme", "nvidia.com/GRID_T4-1Q"))
+
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{})
+			Eventually(func() []string {
+				return hostDevicePluginNames(deviceController)
+			}).Should(BeEmpty())
+		})
+
+		It("should start a macvtap device plugin per host NIC if the feature gate is enabled", func() {
+			originalMacvtapLinks := macvtapLinks
+			defer func() { macvtapLinks = originalMacvtapLinks }()
+			macvtapLinks = &fakeLinkHandler{links: []netlink.Link{
+				&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Index: 2}},
+				&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth1", Index: 3}},
+			}}
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.MacvtapGate},
+			})
+			deviceController := NewDeviceController(host, 10, clusterConfig)
+			deviceController.refreshPermittedHostDevices()
+			Expect(hostDevicePluginNames(deviceController)).To(ConsistOf(
+				"macvtap.network.kubevirt.io/eth0", "macvtap.network.kubevirt.io/eth1"))
+		})
+
+		It("should not start device plugins for externally provided devices", func() {
+			testutils.UpdateFakeClusterConfig(configMapInformer, &k8sv1.ConfigMap{
+				Data: map[string]string{virtconfig.PermittedHostDevicesKey: `
+pciHostDevices:
+- pciVendorSelector: "8086:6f54"
+  resourceName: "intel.com/nvme"
+  externalResourceProvider: true
+`},
+			})
+			deviceController := NewDeviceController(host, 10, clusterConfig)
+			deviceController.refreshPermittedHostDevices()
+			Expect(hostDevicePluginNames(deviceController)).To(BeEmpty())
+		})
+	})
+
 	Context("Multiple Plugins", func() {
 		var devicePath1 string
 		var devicePath2 string
@@ -93,7 +190,7 @@ var _ = Describe("Device Controller", func() {
 
 		It("should restart the device plugin immeidiately without delays", func() {
 			plugin2 = NewFakePlugin("fake-device2", devicePath2)
-			deviceController := NewDeviceController(host, 10)
+			deviceController := NewDeviceController(host, 10, clusterConfig)
 			deviceController.devicePlugins = []GenericDevice{plugin2}
 			deviceController.backoff = []time.Duration{10 * time.Millisecond, 10 * time.Second}
 			go deviceController.Run(stop)
@@ -105,7 +202,7 @@ var _ = Describe("Device Controller", func() {
 		It("should restart the device plugin with delays if it returns errors", func() {
 			plugin2 = NewFakePlugin("fake-device2", devicePath2)
 			plugin2.Error = fmt.Errorf("failing")
-			deviceController := NewDeviceController(host, 10)
+			deviceController := NewDeviceController(host, 10, clusterConfig)
 			deviceController.backoff = []time.Duration{10 * time.Millisecond, 300 * time.Millisecond}
 			deviceController.devicePlugins = []GenericDevice{plugin2}
 			go deviceController.Run(stop)
@@ -116,7 +213,7 @@ var _ = Describe("Device Controller", func() {
 		})
 
 		It("Should not block on other plugins", func() {
-			deviceController := NewDeviceController(host, 10)
+			deviceController := NewDeviceController(host, 10, clusterConfig)
 			deviceController.devicePlugins = []GenericDevice{plugin1, plugin2}
 			go deviceController.Run(stop)
 
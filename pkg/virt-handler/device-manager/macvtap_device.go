@@ -0,0 +1,354 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package device_manager
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util"
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+const (
+	// MacvtapResourcePrefix is the prefix of the resources which expose the
+	// macvtap devices of a host NIC, e.g. macvtap.network.kubevirt.io/eth0
+	MacvtapResourcePrefix = "macvtap.network.kubevirt.io"
+	hostNetNSPath         = "/proc/1/ns/net"
+)
+
+// macvtapLinkHandler wraps the netlink calls on the host network namespace,
+// virt-handler itself runs in the network namespace of its pod
+type macvtapLinkHandler interface {
+	LinkList() ([]netlink.Link, error)
+	LinkByName(name string) (netlink.Link, error)
+	LinkAdd(link netlink.Link) error
+}
+
+type hostNetlinkHandler struct{}
+
+func (h *hostNetlinkHandler) handle() (*netlink.Handle, error) {
+	ns, err := netns.GetFromPath(hostNetNSPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the host network namespace: %v", err)
+	}
+	defer ns.Close()
+	return netlink.NewHandleAt(ns)
+}
+
+func (h *hostNetlinkHandler) LinkList() ([]netlink.Link, error) {
+	handle, err := h.handle()
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Delete()
+	return handle.LinkList()
+}
+
+func (h *hostNetlinkHandler) LinkByName(name string) (netlink.Link, error) {
+	handle, err := h.handle()
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Delete()
+	return handle.LinkByName(name)
+}
+
+func (h *hostNetlinkHandler) LinkAdd(link netlink.Link) error {
+	handle, err := h.handle()
+	if err != nil {
+		return err
+	}
+	defer handle.Delete()
+	return handle.LinkAdd(link)
+}
+
+var macvtapLinks macvtapLinkHandler = &hostNetlinkHandler{}
+
+// MacvtapDevicePlugin exposes macvtap devices on top of a host NIC. The
+// devices are created on allocation, the macvtap CNI plugin moves the device
+// with the allocated ID into the network namespace of the pod.
+type MacvtapDevicePlugin struct {
+	devs         []*pluginapi.Device
+	server       *grpc.Server
+	socketPath   string
+	stop         chan struct{}
+	resourceName string
+	lowerDevice  string
+	done         chan struct{}
+}
+
+func NewMacvtapDevicePlugin(lowerDevice string, lowerIndex int, maxDevices int) *MacvtapDevicePlugin {
+	resourceName := path.Join(MacvtapResourcePrefix, lowerDevice)
+	dpi := &MacvtapDevicePlugin{
+		devs:         []*pluginapi.Device{},
+		socketPath:   SocketPath(strings.Replace(resourceName, "/", "-", -1)),
+		resourceName: resourceName,
+		lowerDevice:  lowerDevice,
+	}
+	for i := 0; i < maxDevices; i++ {
+		// the IDs name the macvtap devices, which are limited to 15 characters
+		dpi.devs = append(dpi.devs, &pluginapi.Device{
+			ID:     fmt.Sprintf("mvtap%dp%d", lowerIndex, i),
+			Health: pluginapi.Healthy,
+		})
+	}
+	return dpi
+}
+
+func (dpi *MacvtapDevicePlugin) GetDevicePath() string {
+	return dpi.lowerDevice
+}
+
+func (dpi *MacvtapDevicePlugin) GetDeviceName() string {
+	return dpi.resourceName
+}
+
+// Start starts the device plugin
+func (dpi *MacvtapDevicePlugin) Start(stop chan struct{}) (err error) {
+	logger := log.DefaultLogger()
+	dpi.stop = stop
+	dpi.done = make(chan struct{})
+
+	err = dpi.cleanup()
+	if err != nil {
+		return err
+	}
+
+	sock, err := net.Listen("unix", dpi.socketPath)
+	if err != nil {
+		return fmt.Errorf("error creating GRPC server socket: %v", err)
+	}
+
+	dpi.server = grpc.NewServer([]grpc.ServerOption{}...)
+	defer dpi.Stop()
+
+	pluginapi.RegisterDevicePluginServer(dpi.server, dpi)
+	err = dpi.Register()
+	if err != nil {
+		return fmt.Errorf("error registering with device plugin manager: %v", err)
+	}
+
+	errChan := make(chan error, 2)
+
+	go func() {
+		errChan <- dpi.server.Serve(sock)
+	}()
+
+	err = waitForGrpcServer(dpi.socketPath, connectionTimeout)
+	if err != nil {
+		return fmt.Errorf("error starting the GRPC server: %v", err)
+	}
+
+	go func() {
+		errChan <- dpi.healthCheck()
+	}()
+
+	logger.Infof("%s device plugin started", dpi.resourceName)
+	err = <-errChan
+
+	return err
+}
+
+// Stop stops the gRPC server
+func (dpi *MacvtapDevicePlugin) Stop() error {
+	defer close(dpi.done)
+	dpi.server.Stop()
+	return dpi.cleanup()
+}
+
+// Register registers the device plugin for the given resourceName with Kubelet.
+func (dpi *MacvtapDevicePlugin) Register() error {
+	conn, err := connect(pluginapi.KubeletSocket, connectionTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := pluginapi.NewRegistrationClient(conn)
+	reqt := &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     path.Base(dpi.socketPath),
+		ResourceName: dpi.resourceName,
+	}
+
+	_, err = client.Register(context.Background(), reqt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dpi *MacvtapDevicePlugin) ListAndWatch(e *pluginapi.Empty, s pluginapi.DevicePlugin_ListAndWatchServer) error {
+	s.Send(&pluginapi.ListAndWatchResponse{Devices: dpi.devs})
+
+	select {
+	case <-dpi.stop:
+	case <-dpi.done:
+	}
+	return nil
+}
+
+// Allocate creates the macvtap devices of the allocated IDs on the host NIC
+// if they do not exist yet and passes their tap devices to the container
+func (dpi *MacvtapDevicePlugin) Allocate(ctx context.Context, r *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	envVar := util.ResourceNameToEnvVar(util.MacvtapResourcePrefix, dpi.resourceName)
+	response := pluginapi.AllocateResponse{}
+
+	for _, request := range r.ContainerRequests {
+		deviceSpecs := []*pluginapi.DeviceSpec{}
+		for _, devID := range request.DevicesIDs {
+			index, err := dpi.ensureMacvtap(devID)
+			if err != nil {
+				return nil, err
+			}
+			tapPath := fmt.Sprintf("/dev/tap%d", index)
+			deviceSpecs = append(deviceSpecs, &pluginapi.DeviceSpec{
+				HostPath:      tapPath,
+				ContainerPath: tapPath,
+				Permissions:   "rw",
+			})
+		}
+
+		response.ContainerResponses = append(response.ContainerResponses, &pluginapi.ContainerAllocateResponse{
+			Envs:    map[string]string{envVar: strings.Join(request.DevicesIDs, ",")},
+			Devices: deviceSpecs,
+		})
+	}
+
+	return &response, nil
+}
+
+// ensureMacvtap returns the interface index of the macvtap device named
+// devID, the device is created in bridge mode on the host NIC if it is missing.
+// Macvtap devices are destroyed together with the network namespace of the pod.
+func (dpi *MacvtapDevicePlugin) ensureMacvtap(devID string) (int, error) {
+	if link, err := macvtapLinks.LinkByName(devID); err == nil {
+		return link.Attrs().Index, nil
+	}
+
+	lower, err := macvtapLinks.LinkByName(dpi.lowerDevice)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the host NIC %s: %v", dpi.lowerDevice, err)
+	}
+	macvtap := &netlink.Macvtap{
+		Macvlan: netlink.Macvlan{
+			LinkAttrs: netlink.LinkAttrs{
+				Name:        devID,
+				ParentIndex: lower.Attrs().Index,
+			},
+			Mode: netlink.MACVLAN_MODE_BRIDGE,
+		},
+	}
+	if err := macvtapLinks.LinkAdd(macvtap); err != nil {
+		return 0, fmt.Errorf("failed to create macvtap device %s on %s: %v", devID, dpi.lowerDevice, err)
+	}
+	link, err := macvtapLinks.LinkByName(devID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the created macvtap device %s: %v", devID, err)
+	}
+	return link.Attrs().Index, nil
+}
+
+func (dpi *MacvtapDevicePlugin) cleanup() error {
+	if err := os.Remove(dpi.socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (dpi *MacvtapDevicePlugin) GetDevicePluginOptions(ctx context.Context, e *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	options := &pluginapi.DevicePluginOptions{
+		PreStartRequired: false,
+	}
+	return options, nil
+}
+
+func (dpi *MacvtapDevicePlugin) PreStartContainer(ctx context.Context, in *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	res := &pluginapi.PreStartContainerResponse{}
+	return res, nil
+}
+
+// healthCheck only watches the socket of the plugin, host NICs which appear
+// or disappear are picked up by the next refresh of the device controller
+func (dpi *MacvtapDevicePlugin) healthCheck() error {
+	logger := log.DefaultLogger()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to creating a fsnotify watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	err = watcher.Add(filepath.Dir(dpi.socketPath))
+	if err != nil {
+		return fmt.Errorf("failed to add the device-plugin kubelet path to the watcher: %v", err)
+	}
+	_, err = os.Stat(dpi.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat the device-plugin socket: %v", err)
+	}
+
+	for {
+		select {
+		case <-dpi.stop:
+			return nil
+		case err := <-watcher.Errors:
+			logger.Reason(err).Errorf("error watching the device plugin directory")
+		case event := <-watcher.Events:
+			if event.Name == dpi.socketPath && event.Op == fsnotify.Remove {
+				logger.Infof("device socket file for device %s was removed, kubelet probably restarted.", dpi.resourceName)
+				return nil
+			}
+		}
+	}
+}
+
+// discoverMacvtapLowerDevices returns the physical NICs of the host which can
+// carry macvtap devices, keyed by name with their interface index
+func discoverMacvtapLowerDevices() map[string]int {
+	logger := log.DefaultLogger()
+	lowerDevices := map[string]int{}
+
+	links, err := macvtapLinks.LinkList()
+	if err != nil {
+		logger.Reason(err).Errorf("failed to discover the host NICs for macvtap devices")
+		return lowerDevices
+	}
+	for _, link := range links {
+		attrs := link.Attrs()
+		if link.Type() != "device" || attrs.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		lowerDevices[attrs.Name] = attrs.Index
+	}
+	return lowerDevices
+}
@@ -0,0 +1,96 @@
+package device_manager
+
+import (
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/net/context"
+
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+// fakeLinkHandler keeps the links of a fake host network namespace
+type fakeLinkHandler struct {
+	links []netlink.Link
+}
+
+func (h *fakeLinkHandler) LinkList() ([]netlink.Link, error) {
+	return h.links, nil
+}
+
+func (h *fakeLinkHandler) LinkByName(name string) (netlink.Link, error) {
+	for _, link := range h.links {
+		if link.Attrs().Name == name {
+			return link, nil
+		}
+	}
+	return nil, fmt.Errorf("link %s not found", name)
+}
+
+func (h *fakeLinkHandler) LinkAdd(link netlink.Link) error {
+	link.Attrs().Index = len(h.links) + 1
+	h.links = append(h.links, link)
+	return nil
+}
+
+var _ = Describe("Macvtap Device", func() {
+	var originalMacvtapLinks macvtapLinkHandler
+	var links *fakeLinkHandler
+
+	BeforeEach(func() {
+		links = &fakeLinkHandler{links: []netlink.Link{
+			&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "lo", Index: 1, Flags: net.FlagLoopback}},
+			&netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "eth0", Index: 2}},
+			&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "cni0", Index: 3}},
+		}}
+		originalMacvtapLinks = macvtapLinks
+		macvtapLinks = links
+	})
+
+	AfterEach(func() {
+		macvtapLinks = originalMacvtapLinks
+	})
+
+	It("should discover the physical NICs of the host", func() {
+		Expect(discoverMacvtapLowerDevices()).To(Equal(map[string]int{"eth0": 2}))
+	})
+
+	It("should create the allocated macvtap devices and pass their tap devices", func() {
+		dpi := NewMacvtapDevicePlugin("eth0", 2, 3)
+		Expect(dpi.GetDeviceName()).To(Equal("macvtap.network.kubevirt.io/eth0"))
+		Expect(dpi.devs).To(HaveLen(3))
+		Expect(dpi.devs[0].ID).To(Equal("mvtap2p0"))
+
+		response, err := dpi.Allocate(context.Background(), &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"mvtap2p1"}},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.ContainerResponses).To(HaveLen(1))
+		Expect(response.ContainerResponses[0].Envs).To(Equal(map[string]string{
+			"MACVTAP_RESOURCE_MACVTAP_NETWORK_KUBEVIRT_IO_ETH0": "mvtap2p1",
+		}))
+		Expect(response.ContainerResponses[0].Devices).To(ConsistOf(
+			&pluginapi.DeviceSpec{HostPath: "/dev/tap4", ContainerPath: "/dev/tap4", Permissions: "rw"},
+		))
+
+		macvtap, err := links.LinkByName("mvtap2p1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(macvtap.Type()).To(Equal("macvtap"))
+		Expect(macvtap.Attrs().ParentIndex).To(Equal(2))
+		Expect(macvtap.(*netlink.Macvtap).Mode).To(Equal(netlink.MACVLAN_MODE_BRIDGE))
+
+		// an existing device is reused
+		_, err = dpi.Allocate(context.Background(), &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"mvtap2p1"}},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(links.links).To(HaveLen(4))
+	})
+})
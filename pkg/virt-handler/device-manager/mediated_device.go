@@ -0,0 +1,97 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package device_manager
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util"
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+var mdevBasePath = "/sys/bus/mdev/devices"
+
+type MDEV struct {
+	uuid       string
+	typeName   string
+	iommuGroup string
+}
+
+// NewMediatedDevicePlugin exposes mediated devices of the host. Like PCI devices they are
+// passed to the container as vfio groups, their UUIDs are listed in a separate environment variable.
+func NewMediatedDevicePlugin(mdevs []*MDEV, resourceName string) *PCIDevicePlugin {
+	dpi := NewPCIDevicePlugin([]*PCIDevice{}, resourceName)
+	dpi.envPrefix = util.MDEVResourcePrefix
+	for _, mdev := range mdevs {
+		dpi.devs = append(dpi.devs, &pluginapi.Device{
+			ID:     mdev.uuid,
+			Health: pluginapi.Healthy,
+		})
+		dpi.iommuGroups[mdev.uuid] = mdev.iommuGroup
+	}
+	return dpi
+}
+
+// discoverPermittedMediatedDevices returns the created mediated devices of the host,
+// grouped by the resource name of their type name selector
+func discoverPermittedMediatedDevices(supportedMdevsMap map[string]string) map[string][]*MDEV {
+	logger := log.DefaultLogger()
+	mdevsMap := make(map[string][]*MDEV)
+
+	entries, err := ioutil.ReadDir(mdevBasePath)
+	if err != nil {
+		logger.Reason(err).Errorf("failed to discover mediated devices")
+		return mdevsMap
+	}
+
+	for _, entry := range entries {
+		mdevPath := filepath.Join(mdevBasePath, entry.Name())
+		typePath, err := filepath.EvalSymlinks(filepath.Join(mdevPath, "mdev_type"))
+		if err != nil {
+			logger.Reason(err).Warningf("failed to read the type of mediated device %s", entry.Name())
+			continue
+		}
+		// the name of a type is optional
+		typeName := filepath.Base(typePath)
+		if content, err := ioutil.ReadFile(filepath.Join(typePath, "name")); err == nil {
+			typeName = strings.TrimSpace(string(content))
+		}
+		resourceName, supported := supportedMdevsMap[typeName]
+		if !supported {
+			continue
+		}
+
+		iommuGroup, err := filepath.EvalSymlinks(filepath.Join(mdevPath, "iommu_group"))
+		if err != nil {
+			logger.Reason(err).Warningf("failed to read the iommu group of mediated device %s", entry.Name())
+			continue
+		}
+
+		mdevsMap[resourceName] = append(mdevsMap[resourceName], &MDEV{
+			uuid:       entry.Name(),
+			typeName:   typeName,
+			iommuGroup: filepath.Base(iommuGroup),
+		})
+	}
+	return mdevsMap
+}
@@ -0,0 +1,88 @@
+package device_manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+func createFakeMediatedDevice(root string, uuid string, typeID string, typeName string, iommuGroup string) {
+	mdevPath := path.Join(root, "mdevs", uuid)
+	Expect(os.MkdirAll(mdevPath, 0755)).To(Succeed())
+
+	typePath := path.Join(root, "mdev_supported_types", typeID)
+	Expect(os.MkdirAll(typePath, 0755)).To(Succeed())
+	if typeName != "" {
+		Expect(ioutil.WriteFile(path.Join(typePath, "name"), []byte(typeName+"\n"), 0644)).To(Succeed())
+	}
+	Expect(os.Symlink(typePath, path.Join(mdevPath, "mdev_type"))).To(Succeed())
+
+	iommuGroupPath := path.Join(root, "iommu_groups", iommuGroup)
+	Expect(os.MkdirAll(iommuGroupPath, 0755)).To(Succeed())
+	Expect(os.Symlink(iommuGroupPath, path.Join(mdevPath, "iommu_group"))).To(Succeed())
+}
+
+var _ = Describe("Mediated Device", func() {
+	var workDir string
+	var err error
+	var originalMdevBasePath string
+
+	BeforeEach(func() {
+		workDir, err = ioutil.TempDir("", "kubevirt-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		originalMdevBasePath = mdevBasePath
+		mdevBasePath = path.Join(workDir, "mdevs")
+	})
+
+	AfterEach(func() {
+		mdevBasePath = originalMdevBasePath
+		os.RemoveAll(workDir)
+	})
+
+	It("should discover the permitted mediated devices by their type name", func() {
+		createFakeMediatedDevice(workDir, "4b20d080-1b54-4048-85b3-a6a62d165c01", "nvidia-222", "GRID T4-1Q", "21")
+		createFakeMediatedDevice(workDir, "1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11", "nvidia-223", "GRID T4-2Q", "22")
+		createFakeMediatedDevice(workDir, "9c3e1f7a-6c1b-4f2e-8d5a-3b7e2a9c4d33", "i915-GVTg_V5_4", "", "23")
+
+		mdevs := discoverPermittedMediatedDevices(map[string]string{
+			"GRID T4-1Q":     "nvidia.com/GRID_T4-1Q",
+			"i915-GVTg_V5_4": "intel.com/gvt",
+		})
+		Expect(mdevs).To(HaveLen(2))
+		Expect(mdevs["nvidia.com/GRID_T4-1Q"]).To(ConsistOf(
+			&MDEV{uuid: "4b20d080-1b54-4048-85b3-a6a62d165c01", typeName: "GRID T4-1Q", iommuGroup: "21"},
+		))
+		Expect(mdevs["intel.com/gvt"]).To(ConsistOf(
+			&MDEV{uuid: "9c3e1f7a-6c1b-4f2e-8d5a-3b7e2a9c4d33", typeName: "i915-GVTg_V5_4", iommuGroup: "23"},
+		))
+	})
+
+	It("should pass the vfio groups and the UUIDs of allocated devices", func() {
+		dpi := NewMediatedDevicePlugin([]*MDEV{
+			{uuid: "4b20d080-1b54-4048-85b3-a6a62d165c01", typeName: "GRID T4-1Q", iommuGroup: "21"},
+		}, "nvidia.com/GRID_T4-1Q")
+		Expect(dpi.devs).To(HaveLen(1))
+
+		response, err := dpi.Allocate(context.Background(), &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"4b20d080-1b54-4048-85b3-a6a62d165c01"}},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.ContainerResponses).To(HaveLen(1))
+		Expect(response.ContainerResponses[0].Envs).To(Equal(map[string]string{
+			"MDEV_PCI_RESOURCE_NVIDIA_COM_GRID_T4-1Q": "4b20d080-1b54-4048-85b3-a6a62d165c01",
+		}))
+		Expect(response.ContainerResponses[0].Devices).To(ConsistOf(
+			formatVFIODeviceSpec("/dev/vfio/vfio"),
+			formatVFIODeviceSpec("/dev/vfio/21"),
+		))
+	})
+})
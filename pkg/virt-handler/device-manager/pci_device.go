@@ -0,0 +1,376 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package device_manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util"
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+const (
+	vfioDevicePath = "/dev/vfio/"
+	vfioMount      = "/dev/vfio/vfio"
+	vfioDriver     = "vfio-pci"
+)
+
+var pciBasePath = "/sys/bus/pci/devices"
+
+type PCIDevice struct {
+	pciID      string
+	pciAddress string
+	iommuGroup string
+}
+
+type deviceHealth struct {
+	devID  string
+	health string
+}
+
+type PCIDevicePlugin struct {
+	devs         []*pluginapi.Device
+	server       *grpc.Server
+	socketPath   string
+	stop         chan struct{}
+	health       chan deviceHealth
+	resourceName string
+	envPrefix    string
+	done         chan struct{}
+	deviceRoot   string
+	// iommuGroups maps the device IDs to the iommu groups of the devices
+	iommuGroups map[string]string
+}
+
+func NewPCIDevicePlugin(pciDevices []*PCIDevice, resourceName string) *PCIDevicePlugin {
+	serverSock := SocketPath(strings.Replace(resourceName, "/", "-", -1))
+	dpi := &PCIDevicePlugin{
+		devs:         []*pluginapi.Device{},
+		socketPath:   serverSock,
+		health:       make(chan deviceHealth),
+		resourceName: resourceName,
+		envPrefix:    util.PCIResourcePrefix,
+		deviceRoot:   util.HostRootMount,
+		iommuGroups:  map[string]string{},
+	}
+	for _, pciDevice := range pciDevices {
+		dpi.devs = append(dpi.devs, &pluginapi.Device{
+			ID:     pciDevice.pciAddress,
+			Health: pluginapi.Healthy,
+		})
+		dpi.iommuGroups[pciDevice.pciAddress] = pciDevice.iommuGroup
+	}
+	return dpi
+}
+
+func (dpi *PCIDevicePlugin) GetDevicePath() string {
+	return vfioDevicePath
+}
+
+func (dpi *PCIDevicePlugin) GetDeviceName() string {
+	return dpi.resourceName
+}
+
+// Start starts the device plugin
+func (dpi *PCIDevicePlugin) Start(stop chan struct{}) (err error) {
+	logger := log.DefaultLogger()
+	dpi.stop = stop
+	dpi.done = make(chan struct{})
+
+	err = dpi.cleanup()
+	if err != nil {
+		return err
+	}
+
+	sock, err := net.Listen("unix", dpi.socketPath)
+	if err != nil {
+		return fmt.Errorf("error creating GRPC server socket: %v", err)
+	}
+
+	dpi.server = grpc.NewServer([]grpc.ServerOption{}...)
+	defer dpi.Stop()
+
+	pluginapi.RegisterDevicePluginServer(dpi.server, dpi)
+	err = dpi.Register()
+	if err != nil {
+		return fmt.Errorf("error registering with device plugin manager: %v", err)
+	}
+
+	errChan := make(chan error, 2)
+
+	go func() {
+		errChan <- dpi.server.Serve(sock)
+	}()
+
+	err = waitForGrpcServer(dpi.socketPath, connectionTimeout)
+	if err != nil {
+		return fmt.Errorf("error starting the GRPC server: %v", err)
+	}
+
+	go func() {
+		errChan <- dpi.healthCheck()
+	}()
+
+	logger.Infof("%s device plugin started", dpi.resourceName)
+	err = <-errChan
+
+	return err
+}
+
+// Stop stops the gRPC server
+func (dpi *PCIDevicePlugin) Stop() error {
+	defer close(dpi.done)
+	dpi.server.Stop()
+	return dpi.cleanup()
+}
+
+// Register registers the device plugin for the given resourceName with Kubelet.
+func (dpi *PCIDevicePlugin) Register() error {
+	conn, err := connect(pluginapi.KubeletSocket, connectionTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := pluginapi.NewRegistrationClient(conn)
+	reqt := &pluginapi.RegisterRequest{
+		Version:      pluginapi.Version,
+		Endpoint:     path.Base(dpi.socketPath),
+		ResourceName: dpi.resourceName,
+	}
+
+	_, err = client.Register(context.Background(), reqt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dpi *PCIDevicePlugin) ListAndWatch(e *pluginapi.Empty, s pluginapi.DevicePlugin_ListAndWatchServer) error {
+	s.Send(&pluginapi.ListAndWatchResponse{Devices: dpi.devs})
+
+	for {
+		select {
+		case devHealth := <-dpi.health:
+			for _, dev := range dpi.devs {
+				if devHealth.devID == dev.ID {
+					dev.Health = devHealth.health
+				}
+			}
+			s.Send(&pluginapi.ListAndWatchResponse{Devices: dpi.devs})
+		case <-dpi.stop:
+			return nil
+		case <-dpi.done:
+			return nil
+		}
+	}
+}
+
+// Allocate passes the vfio group of every allocated device to the container and
+// lists the allocated device IDs in an environment variable for virt-launcher
+func (dpi *PCIDevicePlugin) Allocate(ctx context.Context, r *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	envVar := util.ResourceNameToEnvVar(dpi.envPrefix, dpi.resourceName)
+	response := pluginapi.AllocateResponse{}
+
+	for _, request := range r.ContainerRequests {
+		deviceSpecs := []*pluginapi.DeviceSpec{formatVFIODeviceSpec(vfioMount)}
+		for _, devID := range request.DevicesIDs {
+			iommuGroup, exists := dpi.iommuGroups[devID]
+			if !exists {
+				return nil, fmt.Errorf("unknown device %s of resource %s", devID, dpi.resourceName)
+			}
+			deviceSpecs = append(deviceSpecs, formatVFIODeviceSpec(filepath.Join(vfioDevicePath, iommuGroup)))
+		}
+
+		response.ContainerResponses = append(response.ContainerResponses, &pluginapi.ContainerAllocateResponse{
+			Envs:    map[string]string{envVar: strings.Join(request.DevicesIDs, ",")},
+			Devices: deviceSpecs,
+		})
+	}
+
+	return &response, nil
+}
+
+func formatVFIODeviceSpec(devicePath string) *pluginapi.DeviceSpec {
+	return &pluginapi.DeviceSpec{
+		HostPath:      devicePath,
+		ContainerPath: devicePath,
+		Permissions:   "mrw",
+	}
+}
+
+func (dpi *PCIDevicePlugin) cleanup() error {
+	if err := os.Remove(dpi.socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (dpi *PCIDevicePlugin) GetDevicePluginOptions(ctx context.Context, e *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
+	options := &pluginapi.DevicePluginOptions{
+		PreStartRequired: false,
+	}
+	return options, nil
+}
+
+func (dpi *PCIDevicePlugin) PreStartContainer(ctx context.Context, in *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
+	res := &pluginapi.PreStartContainerResponse{}
+	return res, nil
+}
+
+func (dpi *PCIDevicePlugin) healthCheck() error {
+	logger := log.DefaultLogger()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to creating a fsnotify watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	// This way we don't have to mount /dev from the node
+	devicePath := filepath.Join(dpi.deviceRoot, vfioDevicePath)
+
+	// Start watching the files before we check for their existence to avoid races
+	err = watcher.Add(devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to add the device root path to the watcher: %v", err)
+	}
+
+	iommuToDevices := map[string][]string{}
+	for devID, iommuGroup := range dpi.iommuGroups {
+		groupPath := filepath.Join(devicePath, iommuGroup)
+		iommuToDevices[groupPath] = append(iommuToDevices[groupPath], devID)
+		if _, err = os.Stat(groupPath); err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("could not stat the device: %v", err)
+			}
+			logger.Warningf("device '%s' is not present, the device plugin can't expose it.", groupPath)
+			dpi.health <- deviceHealth{devID: devID, health: pluginapi.Unhealthy}
+		}
+	}
+
+	dirName := filepath.Dir(dpi.socketPath)
+	err = watcher.Add(dirName)
+	if err != nil {
+		return fmt.Errorf("failed to add the device-plugin kubelet path to the watcher: %v", err)
+	}
+	_, err = os.Stat(dpi.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat the device-plugin socket: %v", err)
+	}
+
+	for {
+		select {
+		case <-dpi.stop:
+			return nil
+		case err := <-watcher.Errors:
+			logger.Reason(err).Errorf("error watching devices and device plugin directory")
+		case event := <-watcher.Events:
+			logger.V(4).Infof("health Event: %v", event)
+			if devIDs, monitored := iommuToDevices[event.Name]; monitored {
+				// Health in this case is if the vfio group actually exists
+				health := ""
+				if event.Op == fsnotify.Create {
+					logger.Infof("monitored device %s appeared", event.Name)
+					health = pluginapi.Healthy
+				} else if (event.Op == fsnotify.Remove) || (event.Op == fsnotify.Rename) {
+					logger.Infof("monitored device %s disappeared", event.Name)
+					health = pluginapi.Unhealthy
+				}
+				if health != "" {
+					for _, devID := range devIDs {
+						dpi.health <- deviceHealth{devID: devID, health: health}
+					}
+				}
+			} else if event.Name == dpi.socketPath && event.Op == fsnotify.Remove {
+				logger.Infof("device socket file for device %s was removed, kubelet probably restarted.", dpi.resourceName)
+				return nil
+			}
+		}
+	}
+}
+
+// discoverPermittedHostPCIDevices returns the PCI devices of the host which are bound
+// to the vfio-pci driver, grouped by the resource name of their vendor selector
+func discoverPermittedHostPCIDevices(supportedPCIDeviceMap map[string]string) map[string][]*PCIDevice {
+	logger := log.DefaultLogger()
+	pciDevicesMap := make(map[string][]*PCIDevice)
+
+	entries, err := ioutil.ReadDir(pciBasePath)
+	if err != nil {
+		logger.Reason(err).Errorf("failed to discover host PCI devices")
+		return pciDevicesMap
+	}
+
+	for _, entry := range entries {
+		devicePath := filepath.Join(pciBasePath, entry.Name())
+		pciID, err := readPCIID(devicePath)
+		if err != nil {
+			logger.Reason(err).Warningf("failed to read the vendor of PCI device %s", entry.Name())
+			continue
+		}
+		resourceName, supported := supportedPCIDeviceMap[pciID]
+		if !supported {
+			continue
+		}
+
+		driver, err := filepath.EvalSymlinks(filepath.Join(devicePath, "driver"))
+		if err != nil || filepath.Base(driver) != vfioDriver {
+			logger.Warningf("PCI device %s is permitted but not bound to the %s driver", entry.Name(), vfioDriver)
+			continue
+		}
+		iommuGroup, err := filepath.EvalSymlinks(filepath.Join(devicePath, "iommu_group"))
+		if err != nil {
+			logger.Reason(err).Warningf("failed to read the iommu group of PCI device %s", entry.Name())
+			continue
+		}
+
+		pciDevicesMap[resourceName] = append(pciDevicesMap[resourceName], &PCIDevice{
+			pciID:      pciID,
+			pciAddress: entry.Name(),
+			iommuGroup: filepath.Base(iommuGroup),
+		})
+	}
+	return pciDevicesMap
+}
+
+// readPCIID returns the "vendor:device" id of a PCI device, e.g. "10de:1eb8"
+func readPCIID(devicePath string) (string, error) {
+	ids := []string{}
+	for _, file := range []string{"vendor", "device"} {
+		content, err := ioutil.ReadFile(filepath.Join(devicePath, file))
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+	}
+	return strings.Join(ids, ":"), nil
+}
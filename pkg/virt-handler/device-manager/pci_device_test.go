@@ -0,0 +1,95 @@
+package device_manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+
+	pluginapi "kubevirt.io/kubevirt/pkg/virt-handler/device-manager/deviceplugin/v1beta1"
+)
+
+func createFakePCIDevice(root string, address string, vendor string, device string, driver string, iommuGroup string) {
+	devicePath := path.Join(root, "devices", address)
+	Expect(os.MkdirAll(devicePath, 0755)).To(Succeed())
+	Expect(ioutil.WriteFile(path.Join(devicePath, "vendor"), []byte(vendor+"\n"), 0644)).To(Succeed())
+	Expect(ioutil.WriteFile(path.Join(devicePath, "device"), []byte(device+"\n"), 0644)).To(Succeed())
+
+	driverPath := path.Join(root, "drivers", driver)
+	Expect(os.MkdirAll(driverPath, 0755)).To(Succeed())
+	Expect(os.Symlink(driverPath, path.Join(devicePath, "driver"))).To(Succeed())
+
+	iommuGroupPath := path.Join(root, "iommu_groups", iommuGroup)
+	Expect(os.MkdirAll(iommuGroupPath, 0755)).To(Succeed())
+	Expect(os.Symlink(iommuGroupPath, path.Join(devicePath, "iommu_group"))).To(Succeed())
+}
+
+var _ = Describe("PCI Device", func() {
+	var workDir string
+	var err error
+	var originalPCIBasePath string
+
+	BeforeEach(func() {
+		workDir, err = ioutil.TempDir("", "kubevirt-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		originalPCIBasePath = pciBasePath
+		pciBasePath = path.Join(workDir, "devices")
+	})
+
+	AfterEach(func() {
+		pciBasePath = originalPCIBasePath
+		os.RemoveAll(workDir)
+	})
+
+	It("should discover the permitted devices which are bound to vfio-pci", func() {
+		createFakePCIDevice(workDir, "0000:00:01.0", "0x8086", "0x6f54", vfioDriver, "12")
+		createFakePCIDevice(workDir, "0000:00:02.0", "0x8086", "0x6f54", vfioDriver, "13")
+		createFakePCIDevice(workDir, "0000:00:03.0", "0x8086", "0x6f54", "nvme", "14")
+		createFakePCIDevice(workDir, "0000:00:04.0", "0x10de", "0x1eb8", vfioDriver, "15")
+
+		devices := discoverPermittedHostPCIDevices(map[string]string{"8086:6f54": "intel.com/nvme"})
+		Expect(devices).To(HaveLen(1))
+		Expect(devices["intel.com/nvme"]).To(ConsistOf(
+			&PCIDevice{pciID: "8086:6f54", pciAddress: "0000:00:01.0", iommuGroup: "12"},
+			&PCIDevice{pciID: "8086:6f54", pciAddress: "0000:00:02.0", iommuGroup: "13"},
+		))
+	})
+
+	It("should pass the vfio groups and the PCI addresses of allocated devices", func() {
+		dpi := NewPCIDevicePlugin([]*PCIDevice{
+			{pciID: "8086:6f54", pciAddress: "0000:00:01.0", iommuGroup: "12"},
+			{pciID: "8086:6f54", pciAddress: "0000:00:02.0", iommuGroup: "13"},
+		}, "intel.com/nvme")
+		Expect(dpi.devs).To(HaveLen(2))
+
+		response, err := dpi.Allocate(context.Background(), &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"0000:00:01.0", "0000:00:02.0"}},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.ContainerResponses).To(HaveLen(1))
+		Expect(response.ContainerResponses[0].Envs).To(Equal(map[string]string{
+			"PCI_RESOURCE_INTEL_COM_NVME": "0000:00:01.0,0000:00:02.0",
+		}))
+		Expect(response.ContainerResponses[0].Devices).To(ConsistOf(
+			formatVFIODeviceSpec("/dev/vfio/vfio"),
+			formatVFIODeviceSpec("/dev/vfio/12"),
+			formatVFIODeviceSpec("/dev/vfio/13"),
+		))
+	})
+
+	It("should reject the allocation of unknown devices", func() {
+		dpi := NewPCIDevicePlugin([]*PCIDevice{}, "intel.com/nvme")
+		_, err := dpi.Allocate(context.Background(), &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{
+				{DevicesIDs: []string{"0000:00:01.0"}},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,81 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+// GuestPanickedReason is added in an event if the guest kernel reported a panic
+const GuestPanickedReason = "GuestPanicked"
+
+// crashDumpClaimName returns the claim the memory of the guest is dumped to when it panics
+func crashDumpClaimName(vmi *v1.VirtualMachineInstance) string {
+	if vmi.Spec.Domain.Devices.Panic == nil {
+		return ""
+	}
+	return vmi.Spec.Domain.Devices.Panic.CrashDumpClaimName
+}
+
+// guestPanicked tells if the guest kernel reported a panic, which stopped the domain
+func guestPanicked(domain *api.Domain) bool {
+	if domain == nil {
+		return false
+	}
+	return domain.Status.Status == api.Crashed ||
+		domain.Status.Status == api.Paused && domain.Status.Reason == api.ReasonPausedCrashed
+}
+
+// capturingCrash tells if the memory of a guest which panicked is about to be, or being,
+// dumped to the crash dump claim. The VMI keeps running until the dump ended.
+func capturingCrash(vmi *v1.VirtualMachineInstance, domain *api.Domain) bool {
+	claimName := crashDumpClaimName(vmi)
+	if claimName == "" || !guestPanicked(domain) {
+		return false
+	}
+	if !controller.NewVirtualMachineInstanceConditionManager().HasCondition(vmi, v1.VirtualMachineInstanceCrashed) {
+		return true
+	}
+	dump := vmi.Status.MemoryDump
+	return dump != nil && (dump.Phase == v1.MemoryDumpPending || dump.Phase == v1.MemoryDumpInProgress)
+}
+
+// startCrashDump requests a dump of the memory of a guest, which just panicked, to the crash
+// dump claim. The memory dump controller and processMemoryDump take it from there.
+func (d *VirtualMachineController) startCrashDump(vmi *v1.VirtualMachineInstance, domain *api.Domain) {
+	claimName := crashDumpClaimName(vmi)
+	if claimName == "" || !guestPanicked(domain) ||
+		controller.NewVirtualMachineInstanceConditionManager().HasCondition(vmi, v1.VirtualMachineInstanceCrashed) {
+		return
+	}
+	// a dump which is already on its way captures the crash as well
+	if dump := vmi.Status.MemoryDump; dump != nil && (dump.Phase == v1.MemoryDumpPending || dump.Phase == v1.MemoryDumpInProgress) {
+		return
+	}
+	vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{
+		ClaimName: claimName,
+		Phase:     v1.MemoryDumpPending,
+	}
+	d.recorder.Eventf(vmi, k8sv1.EventTypeWarning, GuestPanickedReason, "The guest kernel panicked, dumping its memory to PersistentVolumeClaim %s.", claimName)
+}
@@ -0,0 +1,105 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+const (
+	// WatchdogFiredReason is added in an event if the watchdog device of the guest fired
+	WatchdogFiredReason = "WatchdogFired"
+	// StoppedAfterWatchdogReason is added in an event if virt-handler stopped a guest paused by its
+	// watchdog, so that it is restarted in a new pod
+	StoppedAfterWatchdogReason = "StoppedAfterWatchdog"
+)
+
+var watchdogEventsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kubevirt_vmi_watchdog_events_total",
+		Help: "The number of times the watchdog device of a VMI on this node fired.",
+	},
+	[]string{"action"},
+)
+
+func init() {
+	prometheus.MustRegister(watchdogEventsTotal)
+}
+
+// watchdogAction returns the action taken when the watchdog device of the VMI fires
+func watchdogAction(vmi *v1.VirtualMachineInstance) v1.WatchdogAction {
+	watchdog := vmi.Spec.Domain.Devices.Watchdog
+	if watchdog == nil || watchdog.I6300ESB == nil || watchdog.I6300ESB.Action == "" {
+		return v1.WatchdogActionReset
+	}
+	return watchdog.I6300ESB.Action
+}
+
+// stoppedByGuestWatchdog tells if the domain was stopped because its watchdog fired with the restart-pod action
+func stoppedByGuestWatchdog(vmi *v1.VirtualMachineInstance, domain *api.Domain) bool {
+	return watchdogAction(vmi) == v1.WatchdogActionRestartPod && domain.Status.Watchdog.Count > 0
+}
+
+// processGuestWatchdog reports the times the watchdog device of the guest fired. With the restart-pod
+// action the guest, which libvirt paused, is stopped, so that its VirtualMachine restarts it in a new pod.
+func (d *VirtualMachineController) processGuestWatchdog(vmi *v1.VirtualMachineInstance, domain *api.Domain) error {
+	if domain == nil {
+		return nil
+	}
+	action := watchdogAction(vmi)
+
+	count := domain.Status.Watchdog.Count
+	d.watchdogEventsLock.Lock()
+	counted := d.watchdogEvents[vmi.UID]
+	if count > counted {
+		d.watchdogEvents[vmi.UID] = count
+	}
+	d.watchdogEventsLock.Unlock()
+	if count > counted {
+		watchdogEventsTotal.WithLabelValues(string(action)).Add(float64(count - counted))
+		d.recorder.Eventf(vmi, k8sv1.EventTypeWarning, WatchdogFiredReason, "The watchdog of the guest expired, the %s action was taken.", action)
+	}
+
+	if action != v1.WatchdogActionRestartPod || domain.Status.Status != api.Paused || domain.Status.Reason != api.ReasonPausedWatchdog {
+		return nil
+	}
+	client, err := d.getLauncherClient(vmi)
+	if err != nil {
+		return err
+	}
+	if err := client.KillVirtualMachine(vmi); err != nil {
+		return fmt.Errorf("failed to stop the guest paused by its watchdog: %v", err)
+	}
+	d.recorder.Event(vmi, k8sv1.EventTypeWarning, StoppedAfterWatchdogReason, "The guest paused by its watchdog was stopped to restart it in a new pod.")
+	return nil
+}
+
+func (d *VirtualMachineController) forgetGuestWatchdog(uid types.UID) {
+	d.watchdogEventsLock.Lock()
+	defer d.watchdogEventsLock.Unlock()
+	delete(d.watchdogEvents, uid)
+}
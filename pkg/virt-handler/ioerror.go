@@ -0,0 +1,187 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+const (
+	// PausedIOErrorReason is added in an event if qemu paused the guest because of an I/O error
+	PausedIOErrorReason = "PausedIOError"
+	// ResumedAfterIOErrorReason is added in an event if virt-handler resumed a guest paused because of an I/O error
+	ResumedAfterIOErrorReason = "ResumedAfterIOError"
+
+	defaultIOErrorRetryInterval = 60 * time.Second
+
+	ioErrorResumeTriggerPVCExpanded = "pvc_expanded"
+	ioErrorResumeTriggerRetry       = "retry"
+)
+
+var (
+	ioErrorPausesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmi_io_error_pauses_total",
+			Help: "The number of times qemu paused a VMI on this node because of an I/O error.",
+		},
+	)
+	ioErrorResumesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmi_io_error_resumes_total",
+			Help: "The number of times virt-handler resumed a VMI on this node which was paused because of an I/O error.",
+		},
+		[]string{"trigger"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(ioErrorPausesTotal, ioErrorResumesTotal)
+}
+
+// ioErrorPause tracks a guest which qemu paused because of an I/O error
+type ioErrorPause struct {
+	// lastAttempt is the time of the pause or of the last attempt to resume the guest
+	lastAttempt time.Time
+	// capacities are the capacities of the PVCs of the VMI at the last attempt, by claim name
+	capacities map[string]resource.Quantity
+}
+
+// processIOErrorPause reports the pauses of the guest caused by I/O errors. If the VMI asks for it,
+// the guest is resumed once one of its PVCs was expanded or after the retry interval.
+func (d *VirtualMachineController) processIOErrorPause(vmi *v1.VirtualMachineInstance, domain *api.Domain) error {
+	if domain == nil || domain.Status.Status != api.Paused || domain.Status.Reason != api.ReasonPausedIOError {
+		d.forgetIOErrorPause(vmi.UID)
+		return nil
+	}
+	// without autoResume, virt-launcher resumes the guest on the next sync
+	autoResume := vmi.Spec.IOErrorRecovery != nil && vmi.Spec.IOErrorRecovery.AutoResume != nil && *vmi.Spec.IOErrorRecovery.AutoResume
+
+	var capacities map[string]resource.Quantity
+	if autoResume {
+		var err error
+		if capacities, err = d.claimCapacities(vmi); err != nil {
+			return err
+		}
+	}
+
+	d.ioErrorPausesLock.Lock()
+	pause, exists := d.ioErrorPauses[vmi.UID]
+	if !exists {
+		pause = &ioErrorPause{lastAttempt: time.Now(), capacities: capacities}
+		d.ioErrorPauses[vmi.UID] = pause
+	}
+	lastAttempt, previousCapacities := pause.lastAttempt, pause.capacities
+	d.ioErrorPausesLock.Unlock()
+
+	if !exists {
+		ioErrorPausesTotal.Inc()
+		d.recorder.Event(vmi, k8sv1.EventTypeWarning, PausedIOErrorReason, "The guest was paused because of an I/O error, its storage may be full or unreachable.")
+	}
+	if !autoResume {
+		return nil
+	}
+
+	retryInterval := defaultIOErrorRetryInterval
+	if vmi.Spec.IOErrorRecovery.RetryIntervalSeconds > 0 {
+		retryInterval = time.Duration(vmi.Spec.IOErrorRecovery.RetryIntervalSeconds) * time.Second
+	}
+
+	trigger := ioErrorResumeTriggerRetry
+	if expanded := expandedClaim(previousCapacities, capacities); expanded != "" {
+		log.Log.Object(vmi).Infof("PersistentVolumeClaim %s was expanded, resuming the guest paused because of an I/O error", expanded)
+		trigger = ioErrorResumeTriggerPVCExpanded
+	} else if wait := retryInterval - time.Since(lastAttempt); wait > 0 {
+		d.Queue.AddAfter(controller.VirtualMachineKey(vmi), wait)
+		return nil
+	}
+
+	client, err := d.getLauncherClient(vmi)
+	if err != nil {
+		return err
+	}
+	if err := client.UnpauseVirtualMachine(vmi); err != nil {
+		return fmt.Errorf("failed to resume the guest paused because of an I/O error: %v", err)
+	}
+	d.ioErrorPausesLock.Lock()
+	if pause, exists := d.ioErrorPauses[vmi.UID]; exists {
+		pause.lastAttempt = time.Now()
+		pause.capacities = capacities
+	}
+	d.ioErrorPausesLock.Unlock()
+
+	ioErrorResumesTotal.WithLabelValues(trigger).Inc()
+	d.recorder.Event(vmi, k8sv1.EventTypeNormal, ResumedAfterIOErrorReason, "The guest paused because of an I/O error was resumed.")
+	// qemu pauses the guest again if the error persists, check again in case that is not noticed
+	d.Queue.AddAfter(controller.VirtualMachineKey(vmi), retryInterval)
+	return nil
+}
+
+func (d *VirtualMachineController) forgetIOErrorPause(uid types.UID) {
+	d.ioErrorPausesLock.Lock()
+	defer d.ioErrorPausesLock.Unlock()
+	delete(d.ioErrorPauses, uid)
+}
+
+// claimCapacities returns the capacities of the PVCs backing the volumes of the VMI, by claim name
+func (d *VirtualMachineController) claimCapacities(vmi *v1.VirtualMachineInstance) (map[string]resource.Quantity, error) {
+	capacities := map[string]resource.Quantity{}
+	for _, volume := range vmi.Spec.Volumes {
+		var claimName string
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claimName = volume.PersistentVolumeClaim.ClaimName
+		case volume.DataVolume != nil:
+			claimName = volume.DataVolume.Name
+		default:
+			continue
+		}
+		obj, exists, err := d.pvcInformer.GetStore().GetByKey(vmi.Namespace + "/" + claimName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the capacity of PersistentVolumeClaim %s: %v", claimName, err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("PersistentVolumeClaim %s does not exist", claimName)
+		}
+		capacities[claimName] = obj.(*k8sv1.PersistentVolumeClaim).Status.Capacity[k8sv1.ResourceStorage]
+	}
+	return capacities, nil
+}
+
+// expandedClaim returns the name of a claim whose capacity grew, or an empty string
+func expandedClaim(before, after map[string]resource.Quantity) string {
+	for claimName, capacity := range after {
+		previous, exists := before[claimName]
+		if exists && capacity.Cmp(previous) > 0 {
+			return claimName
+		}
+	}
+	return ""
+}
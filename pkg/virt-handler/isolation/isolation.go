@@ -153,8 +153,8 @@ func prLimit(pid int, limit uintptr, rlimit *unix.Rlimit) error {
 }
 
 func (s *socketBasedIsolationDetector) AdjustResources(vm *v1.VirtualMachineInstance) error {
-	// only VFIO attached domains require MEMLOCK adjustment
-	if !util.IsSRIOVVmi(vm) && !util.IsGPUVMI(vm) && !util.IsQATVMI(vm) {
+	// only VFIO attached domains and SEV guests, which pin their memory, require MEMLOCK adjustment
+	if !util.IsSRIOVVmi(vm) && !util.IsGPUVMI(vm) && !util.IsQATVMI(vm) && !util.IsHostDevVMI(vm) && !util.IsSEVVMI(vm) {
 		return nil
 	}
 
@@ -440,6 +440,13 @@ func (s *socketBasedIsolationDetector) getSlice(pid int) (controller []string, s
 	}
 	defer cgroups.Close()
 
+	return s.parseSlice(cgroups)
+}
+
+// parseSlice reads the slice of the whitelisted controllers from the cgroup file of a process
+func (s *socketBasedIsolationDetector) parseSlice(cgroups io.Reader) (controller []string, slice string, err error) {
+	var unifiedSlice string
+	isUnified := true
 	scanner := bufio.NewScanner(cgroups)
 	for scanner.Scan() {
 		cgEntry := strings.SplitN(scanner.Text(), ":", 3)
@@ -448,6 +455,12 @@ func (s *socketBasedIsolationDetector) getSlice(pid int) (controller []string, s
 			err = fmt.Errorf("Could not extract slice from cgroup line: %s", scanner.Text())
 			return
 		}
+		// The unified hierarchy of cgroup v2 holds all controllers
+		if cgEntry[0] == "0" && cgEntry[1] == "" {
+			unifiedSlice = cgEntry[2]
+			continue
+		}
+		isUnified = false
 		// Skip not supported cgroup controller
 		if !sliceContains(s.controller, cgEntry[1]) {
 			continue
@@ -469,6 +482,12 @@ func (s *socketBasedIsolationDetector) getSlice(pid int) (controller []string, s
 		return
 	}
 
+	// On cgroup v2 nodes the whitelisted controllers are all part of the unified hierarchy
+	if isUnified && unifiedSlice != "" {
+		slice = unifiedSlice
+		controller = s.controller
+	}
+
 	if slice == "" {
 		err = fmt.Errorf("Could not detect slice of whitelisted controller: %v", s.controller)
 		return
@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -120,3 +121,26 @@ var _ = Describe("getMemlockSize", func() {
 		Expect(int(bytes_)).To(Equal(1264389000))
 	})
 })
+
+var _ = Describe("parseSlice", func() {
+	detector := &socketBasedIsolationDetector{controller: []string{"devices"}}
+
+	It("Should detect the slice of the whitelisted controller on cgroup v1", func() {
+		controller, slice, err := detector.parseSlice(strings.NewReader("5:devices:/kubepods/pod1\n4:memory:/kubepods/pod1\n0::/\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controller).To(Equal([]string{"devices"}))
+		Expect(slice).To(Equal("/kubepods/pod1"))
+	})
+
+	It("Should detect the slice of the unified hierarchy on cgroup v2", func() {
+		controller, slice, err := detector.parseSlice(strings.NewReader("0::/kubepods/pod1\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controller).To(Equal([]string{"devices"}))
+		Expect(slice).To(Equal("/kubepods/pod1"))
+	})
+
+	It("Should not fall back to the unified hierarchy in the hybrid mode", func() {
+		_, _, err := detector.parseSlice(strings.NewReader("4:memory:/kubepods/pod1\n0::/kubepods/pod1\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
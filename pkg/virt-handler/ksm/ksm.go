@@ -0,0 +1,159 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package ksm
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+// hysteresis is how many percent the used memory has to fall below the
+// threshold before KSM is stopped again, so that it does not flap
+const hysteresis = 5
+
+// KSMHandler starts kernel samepage merging on the node when its memory
+// pressure crosses the threshold of the KSM configuration, and stops it
+// again when the pressure is gone
+type KSMHandler struct {
+	clusterConfig *virtconfig.ClusterConfig
+	host          string
+	procPath      string
+	ksmPath       string
+	// started tells if KSM runs because of the handler
+	started bool
+}
+
+func NewKSMHandler(clusterConfig *virtconfig.ClusterConfig, host string) *KSMHandler {
+	return &KSMHandler{
+		clusterConfig: clusterConfig,
+		host:          host,
+		procPath:      "/proc",
+		ksmPath:       hardware.KSMPath,
+	}
+}
+
+// Run checks the memory pressure of the node in the given interval
+func (k *KSMHandler) Run(interval time.Duration, stopCh chan struct{}) {
+	wait.JitterUntil(func() {
+		if err := k.reconcile(); err != nil {
+			log.DefaultLogger().Reason(err).Errorf("Failed to manage KSM on node %s", k.host)
+		}
+	}, interval, 1.2, true, stopCh)
+}
+
+func (k *KSMHandler) reconcile() error {
+	ksmStats, err := hardware.LookupKSMStats(k.ksmPath)
+	if err != nil {
+		return err
+	} else if ksmStats == nil {
+		// the kernel does not support KSM
+		return nil
+	}
+
+	config := k.clusterConfig.GetKSMConfiguration()
+	if config == nil {
+		// leave KSM to the admin of the node, unless the handler started it
+		if k.started && ksmStats.Running {
+			if err := k.setRunning(false); err != nil {
+				return err
+			}
+		}
+		k.started = false
+		return nil
+	}
+
+	threshold := virtconfig.DefaultKSMMemoryPressureThreshold
+	if config.MemoryPressureThreshold != nil {
+		threshold = *config.MemoryPressureThreshold
+	}
+
+	usage, err := k.memoryUsage()
+	if err != nil {
+		return err
+	}
+
+	running := ksmStats.Running
+	if usage >= threshold && !running {
+		log.DefaultLogger().Infof("Memory usage of node %s is at %d%%, starting KSM", k.host, usage)
+		running = true
+	} else if usage+hysteresis < threshold && running {
+		log.DefaultLogger().Infof("Memory usage of node %s is down to %d%%, stopping KSM", k.host, usage)
+		running = false
+	}
+	if running != ksmStats.Running {
+		if err := k.setRunning(running); err != nil {
+			return err
+		}
+	}
+	k.started = running
+	return nil
+}
+
+func (k *KSMHandler) setRunning(running bool) error {
+	if err := hardware.SetKSMRunning(k.ksmPath, running); err != nil {
+		return fmt.Errorf("failed to set the KSM state of node %s: %v", k.host, err)
+	}
+	return nil
+}
+
+// memoryUsage returns the percentage of the memory of the node which is not available for new workloads
+func (k *KSMHandler) memoryUsage() (uint32, error) {
+	file, err := os.Open(filepath.Join(k.procPath, "meminfo"))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var total, available int64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total, err = strconv.ParseInt(fields[1], 10, 64)
+		case "MemAvailable:":
+			available, err = strconv.ParseInt(fields[1], 10, 64)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s in meminfo: %v", fields[0], err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("meminfo does not report the total memory")
+	}
+	return uint32((total - available) * 100 / total), nil
+}
@@ -0,0 +1,16 @@
+package ksm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/log"
+)
+
+func TestKSM(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.Log.SetIOWriter(GinkgoWriter)
+	RunSpecs(t, "KSM Suite")
+}
@@ -0,0 +1,147 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package ksm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+var _ = Describe("KSM handler", func() {
+	var tmpDir string
+
+	writeFile := func(path string, content string) {
+		path = filepath.Join(tmpDir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	setMemoryUsage := func(percent int) {
+		writeFile("proc/meminfo", fmt.Sprintf("MemTotal:       16000000 kB\nMemFree:         1000000 kB\nMemAvailable:   %8d kB\n", (100-percent)*160000))
+	}
+
+	ksmRunning := func() bool {
+		content, err := ioutil.ReadFile(filepath.Join(tmpDir, "ksm", "run"))
+		Expect(err).ToNot(HaveOccurred())
+		return strings.TrimSpace(string(content)) == "1"
+	}
+
+	newHandler := func(ksmConfiguration string) *KSMHandler {
+		data := map[string]string{}
+		if ksmConfiguration != "" {
+			data[virtconfig.KSMConfigurationKey] = ksmConfiguration
+		}
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{Data: data})
+		handler := NewKSMHandler(clusterConfig, "testnode")
+		handler.procPath = filepath.Join(tmpDir, "proc")
+		handler.ksmPath = filepath.Join(tmpDir, "ksm")
+		return handler
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "ksm")
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, name := range []string{"run", "pages_shared", "pages_sharing", "pages_unshared", "full_scans"} {
+			writeFile(filepath.Join("ksm", name), "0\n")
+		}
+		setMemoryUsage(50)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("should start KSM when the memory usage crosses the threshold", func() {
+		handler := newHandler("memoryPressureThreshold: 70")
+
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeFalse())
+
+		setMemoryUsage(75)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+	})
+
+	It("should use the default threshold", func() {
+		handler := newHandler("{}")
+
+		setMemoryUsage(75)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeFalse())
+
+		setMemoryUsage(85)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+	})
+
+	It("should stop KSM only when the memory usage is clearly below the threshold", func() {
+		handler := newHandler("memoryPressureThreshold: 70")
+		setMemoryUsage(75)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+
+		setMemoryUsage(67)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+
+		setMemoryUsage(60)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeFalse())
+	})
+
+	It("should leave KSM alone without a KSM configuration", func() {
+		writeFile("ksm/run", "1\n")
+		handler := newHandler("")
+
+		setMemoryUsage(10)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+	})
+
+	It("should stop KSM it started when the KSM configuration is removed", func() {
+		handler := newHandler("memoryPressureThreshold: 70")
+		setMemoryUsage(75)
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeTrue())
+
+		handler.clusterConfig, _, _, _ = testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{})
+		Expect(handler.reconcile()).To(Succeed())
+		Expect(ksmRunning()).To(BeFalse())
+	})
+
+	It("should do nothing on nodes without KSM", func() {
+		handler := newHandler("memoryPressureThreshold: 70")
+		handler.ksmPath = filepath.Join(tmpDir, "missing")
+		setMemoryUsage(75)
+		Expect(handler.reconcile()).To(Succeed())
+	})
+})
@@ -30,8 +30,11 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"kubevirt.io/client-go/log"
 	"kubevirt.io/kubevirt/pkg/util/net/ip"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 const (
@@ -41,6 +44,18 @@ const (
 
 var migrationPortsRange = []int{LibvirtDirectMigrationPort, LibvirtBlockMigrationPort}
 
+var tlsHandshakeFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kubevirt_migration_proxy_tls_handshake_failures_total",
+		Help: "Number of failed TLS handshakes between migration proxies, by the side of the proxy which observed the failure",
+	},
+	[]string{"side"},
+)
+
+func init() {
+	prometheus.MustRegister(tlsHandshakeFailures)
+}
+
 type ProxyManager interface {
 	StartTargetListener(key string, targetUnixFiles []string) error
 	GetTargetListenerPorts(key string) map[string]int
@@ -57,6 +72,7 @@ type migrationProxyManager struct {
 	managerLock     sync.Mutex
 	serverTLSConfig *tls.Config
 	clientTLSConfig *tls.Config
+	clusterConfig   *virtconfig.ClusterConfig
 }
 
 type migrationProxy struct {
@@ -72,6 +88,8 @@ type migrationProxy struct {
 	listener        net.Listener
 	serverTLSConfig *tls.Config
 	clientTLSConfig *tls.Config
+	// allowUnencrypted permits plain tcp listeners on non-loopback addresses
+	allowUnencrypted bool
 }
 
 func GetMigrationPortsList(isBlockMigration bool) (ports []int) {
@@ -82,15 +100,39 @@ func GetMigrationPortsList(isBlockMigration bool) (ports []int) {
 	return
 }
 
-func NewMigrationProxyManager(serverTLSConfig *tls.Config, clientTLSConfig *tls.Config) ProxyManager {
+func NewMigrationProxyManager(serverTLSConfig *tls.Config, clientTLSConfig *tls.Config, clusterConfig *virtconfig.ClusterConfig) ProxyManager {
 	return &migrationProxyManager{
 		sourceProxies:   make(map[string][]*migrationProxy),
 		targetProxies:   make(map[string][]*migrationProxy),
 		serverTLSConfig: serverTLSConfig,
 		clientTLSConfig: clientTLSConfig,
+		clusterConfig:   clusterConfig,
 	}
 }
 
+// tlsDisabled returns true if the cluster config allows unencrypted migration streams.
+// The setting is read when the proxies of a migration are created, so changing it
+// only affects migrations started afterwards.
+func (m *migrationProxyManager) tlsDisabled() bool {
+	return m.clusterConfig != nil && m.clusterConfig.GetMigrationConfiguration().DisableTLS
+}
+
+func (m *migrationProxyManager) newTargetProxy(tcpBindAddress string, targetUnixFile string) *migrationProxy {
+	if m.tlsDisabled() {
+		proxy := NewTargetProxy(tcpBindAddress, 0, nil, nil, targetUnixFile)
+		proxy.allowUnencrypted = true
+		return proxy
+	}
+	return NewTargetProxy(tcpBindAddress, 0, m.serverTLSConfig, m.clientTLSConfig, targetUnixFile)
+}
+
+func (m *migrationProxyManager) newSourceProxy(unixSocketPath string, tcpTargetAddress string) *migrationProxy {
+	if m.tlsDisabled() {
+		return NewSourceProxy(unixSocketPath, tcpTargetAddress, nil, nil)
+	}
+	return NewSourceProxy(unixSocketPath, tcpTargetAddress, m.serverTLSConfig, m.clientTLSConfig)
+}
+
 func SourceUnixFile(baseDir string, key string) string {
 	return filepath.Join(baseDir, "migrationproxy", key+"-source.sock")
 }
@@ -132,7 +174,7 @@ func (m *migrationProxyManager) StartTargetListener(key string, targetUnixFiles
 	proxiesList := []*migrationProxy{}
 	for _, targetUnixFile := range targetUnixFiles {
 		// 0 means random port is used
-		proxy := NewTargetProxy(zeroAddress, 0, m.serverTLSConfig, m.clientTLSConfig, targetUnixFile)
+		proxy := m.newTargetProxy(zeroAddress, targetUnixFile)
 
 		err := proxy.StartListening()
 		if err != nil {
@@ -254,7 +296,7 @@ func (m *migrationProxyManager) StartSourceListener(key string, targetAddress st
 		filePath := SourceUnixFile(baseDir, proxyKey)
 
 		os.RemoveAll(filePath)
-		proxy := NewSourceProxy(filePath, targetFullAddr, m.serverTLSConfig, m.clientTLSConfig)
+		proxy := m.newSourceProxy(filePath, targetFullAddr)
 
 		err := proxy.StartListening()
 		if err != nil {
@@ -326,7 +368,7 @@ func (m *migrationProxy) createTcpListener() error {
 	laddr := net.JoinHostPort(m.tcpBindAddress, strconv.Itoa(m.tcpBindPort))
 	if m.serverTLSConfig != nil {
 		listener, err = tls.Listen("tcp", laddr, m.serverTLSConfig)
-	} else if ip.IsLoopbackAddress(m.tcpBindAddress) {
+	} else if ip.IsLoopbackAddress(m.tcpBindAddress) || m.allowUnencrypted {
 		listener, err = net.Listen("tcp", laddr)
 	} else {
 		return fmt.Errorf("Unsecured tcp migration proxy listeners are not permitted")
@@ -379,17 +421,29 @@ func handleConnection(fd net.Conn, targetAddress string, targetProtocol string,
 	outBoundErr := make(chan error)
 	inBoundErr := make(chan error)
 
-	var conn net.Conn
-	var err error
-	if targetProtocol == "tcp" && clientTLSConfig != nil {
-		conn, err = tls.Dial(targetProtocol, targetAddress, clientTLSConfig)
-	} else {
-		conn, err = net.Dial(targetProtocol, targetAddress)
+	if tlsConn, ok := fd.(*tls.Conn); ok {
+		if err := tlsConn.Handshake(); err != nil {
+			tlsHandshakeFailures.WithLabelValues("target").Inc()
+			log.Log.Reason(err).Errorf("TLS handshake with source proxy %s failed", fd.RemoteAddr())
+			return
+		}
 	}
+
+	conn, err := net.Dial(targetProtocol, targetAddress)
 	if err != nil {
 		log.Log.Reason(err).Errorf("unable to create outbound leg of proxy to host %s", targetAddress)
 		return
 	}
+	if targetProtocol == "tcp" && clientTLSConfig != nil {
+		tlsConn := tls.Client(conn, clientTLSConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			tlsHandshakeFailures.WithLabelValues("source").Inc()
+			log.Log.Reason(err).Errorf("TLS handshake with target proxy %s failed", targetAddress)
+			conn.Close()
+			return
+		}
+		conn = tlsConn
+	}
 
 	go func() {
 		//from outbound connection to proxy
@@ -28,8 +28,12 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	k8sv1 "k8s.io/api/core/v1"
 
 	"kubevirt.io/kubevirt/pkg/certificates"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 )
 
 var _ = Describe("MigrationProxy", func() {
@@ -151,7 +155,7 @@ var _ = Describe("MigrationProxy", func() {
 
 				Expect(err).ShouldNot(HaveOccurred())
 
-				manager := NewMigrationProxyManager(tlsConfig, tlsConfig)
+				manager := NewMigrationProxyManager(tlsConfig, tlsConfig, nil)
 				manager.StartTargetListener("mykey", []string{libvirtdSock, directSock})
 				destSrcPortMap := manager.GetTargetListenerPorts("mykey")
 				manager.StartSourceListener("mykey", "127.0.0.1", destSrcPortMap, tmpDir)
@@ -197,6 +201,112 @@ var _ = Describe("MigrationProxy", func() {
 					}
 				}
 			})
+
+			It("by creating unencrypted proxies with a manager if TLS is disabled", func() {
+				libvirtdSock := tmpDir + "/libvirtd-sock"
+				libvirtdListener, err := net.Listen("unix", libvirtdSock)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer libvirtdListener.Close()
+
+				clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+					Data: map[string]string{virtconfig.MigrationsConfigKey: `{"disableTLS": "true"}`},
+				})
+				manager := NewMigrationProxyManager(tlsConfig, tlsConfig, clusterConfig)
+				Expect(manager.StartTargetListener("mykey", []string{libvirtdSock})).To(Succeed())
+				defer manager.StopTargetListener("mykey")
+				destSrcPortMap := manager.GetTargetListenerPorts("mykey")
+				Expect(manager.StartSourceListener("mykey", "127.0.0.1", destSrcPortMap, tmpDir)).To(Succeed())
+				defer manager.StopSourceListener("mykey")
+
+				for _, proxy := range manager.(*migrationProxyManager).targetProxies["mykey"] {
+					Expect(proxy.serverTLSConfig).To(BeNil())
+				}
+				for _, proxy := range manager.(*migrationProxyManager).sourceProxies["mykey"] {
+					Expect(proxy.clientTLSConfig).To(BeNil())
+				}
+
+				numBytes := make(chan int)
+				go func() {
+					fd, err := libvirtdListener.Accept()
+					Expect(err).ShouldNot(HaveOccurred())
+
+					var bytes [1024]byte
+					n, err := fd.Read(bytes[0:])
+					Expect(err).ShouldNot(HaveOccurred())
+					numBytes <- n
+				}()
+
+				sourceFiles := manager.GetSourceListenerFiles("mykey")
+				Expect(sourceFiles).To(HaveLen(1))
+				conn, err := net.Dial("unix", sourceFiles[0])
+				Expect(err).ShouldNot(HaveOccurred())
+
+				sentLen, err := conn.Write([]byte("some message"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(<-numBytes).To(Equal(sentLen))
+			})
+		})
+
+		Context("with TLS handshake failures", func() {
+			handshakeFailures := func(side string) float64 {
+				dto := &io_prometheus_client.Metric{}
+				Expect(tlsHandshakeFailures.WithLabelValues(side).Write(dto)).To(Succeed())
+				return dto.GetCounter().GetValue()
+			}
+
+			It("should count them on the target proxy", func() {
+				sourceSock := tmpDir + "/source-sock"
+				libvirtdSock := tmpDir + "/libvirtd-sock"
+
+				targetProxy := NewTargetProxy("127.0.0.1", 12346, tlsConfig, tlsConfig, libvirtdSock)
+				sourceProxy := NewSourceProxy(sourceSock, "127.0.0.1:12346", nil, nil)
+				defer targetProxy.StopListening()
+				defer sourceProxy.StopListening()
+
+				Expect(targetProxy.StartListening()).To(Succeed())
+				Expect(sourceProxy.StartListening()).To(Succeed())
+
+				failures := handshakeFailures("target")
+
+				conn, err := net.Dial("unix", sourceSock)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+				_, err = conn.Write([]byte("some unencrypted message"))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Eventually(func() float64 {
+					return handshakeFailures("target")
+				}).Should(Equal(failures + 1))
+			})
+
+			It("should count them on the source proxy", func() {
+				sourceSock := tmpDir + "/source-sock"
+
+				listener, err := net.Listen("tcp", "127.0.0.1:12346")
+				Expect(err).ShouldNot(HaveOccurred())
+				defer listener.Close()
+				go func() {
+					defer GinkgoRecover()
+					fd, err := listener.Accept()
+					Expect(err).ShouldNot(HaveOccurred())
+					fd.Write([]byte("not a TLS server hello"))
+					fd.Close()
+				}()
+
+				sourceProxy := NewSourceProxy(sourceSock, "127.0.0.1:12346", tlsConfig, tlsConfig)
+				defer sourceProxy.StopListening()
+				Expect(sourceProxy.StartListening()).To(Succeed())
+
+				failures := handshakeFailures("source")
+
+				conn, err := net.Dial("unix", sourceSock)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer conn.Close()
+
+				Eventually(func() float64 {
+					return handshakeFailures("source")
+				}).Should(Equal(failures + 1))
+			})
 		})
 	})
 })
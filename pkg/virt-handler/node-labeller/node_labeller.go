@@ -0,0 +1,335 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package nodelabeller
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const (
+	// DomCapabilitiesPath is where the node-labeller init container of
+	// virt-handler stores the domain capabilities reported by libvirt
+	DomCapabilitiesPath = "/var/lib/kubevirt-node-labeller/virsh_domcapabilities.xml"
+
+	// KVM_CHECK_EXTENSION from linux/kvm.h
+	kvmCheckExtension = 0xAE03
+)
+
+// hypervCapabilities maps the Hyper-V enlightenments, by the name used in
+// their node label, to the KVM capability from linux/kvm.h they depend on
+var hypervCapabilities = map[string]uintptr{
+	"vpindex":         149, // KVM_CAP_HYPERV_VP_INDEX
+	"runtime":         44,  // KVM_CAP_HYPERV
+	"reset":           44,  // KVM_CAP_HYPERV
+	"synic":           123, // KVM_CAP_HYPERV_SYNIC
+	"synictimer":      123, // KVM_CAP_HYPERV_SYNIC
+	"frequencies":     96,  // KVM_CAP_HYPERV_TIME
+	"reenlightenment": 96,  // KVM_CAP_HYPERV_TIME
+	"tlbflush":        155, // KVM_CAP_HYPERV_TLBFLUSH
+	"ipi":             161, // KVM_CAP_HYPERV_SEND_IPI
+}
+
+// cpuFlagAliases maps /proc/cpuinfo flags whose name differs from the
+// name libvirt uses for the CPU feature
+var cpuFlagAliases = map[string]string{
+	"sse4_1":    "sse4.1",
+	"sse4_2":    "sse4.2",
+	"pclmulqdq": "pclmuldq",
+}
+
+type domCapabilities struct {
+	CPU struct {
+		Modes []struct {
+			Name   string `xml:"name,attr"`
+			Models []struct {
+				Name   string `xml:",chardata"`
+				Usable string `xml:"usable,attr"`
+			} `xml:"model"`
+		} `xml:"mode"`
+	} `xml:"cpu"`
+}
+
+// NodeLabeller inspects the CPU, KVM, memory encryption and hugepage
+// capabilities of the host and publishes them as labels on its node
+type NodeLabeller struct {
+	clientset           kubecli.KubevirtClient
+	host                string
+	clusterConfig       *virtconfig.ClusterConfig
+	procPath            string
+	sysPath             string
+	kvmPath             string
+	domCapabilitiesPath string
+	checkKVMExtension   func(capability uintptr) bool
+}
+
+func NewNodeLabeller(clusterConfig *virtconfig.ClusterConfig, clientset kubecli.KubevirtClient, host string) *NodeLabeller {
+	n := &NodeLabeller{
+		clientset:           clientset,
+		host:                host,
+		clusterConfig:       clusterConfig,
+		procPath:            "/proc",
+		sysPath:             "/sys",
+		kvmPath:             "/dev/kvm",
+		domCapabilitiesPath: DomCapabilitiesPath,
+	}
+	n.checkKVMExtension = n.kvmHasExtension
+	return n
+}
+
+// Run labels the node in the given interval while the CPUNodeDiscovery feature gate is enabled
+func (n *NodeLabeller) Run(interval time.Duration, stopCh chan struct{}) {
+	wait.JitterUntil(func() {
+		if !n.clusterConfig.CPUNodeDiscoveryEnabled() {
+			return
+		}
+		if err := n.labelNode(); err != nil {
+			log.DefaultLogger().Reason(err).Errorf("Failed to label node %s", n.host)
+		}
+	}, interval, 1.2, true, stopCh)
+}
+
+func (n *NodeLabeller) labelNode() error {
+	labels, err := n.hostLabels()
+	if err != nil {
+		return err
+	}
+
+	node, err := n.clientset.CoreV1().Nodes().Get(n.host, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// add new labels and remove the ones of capabilities which are gone
+	changes := map[string]interface{}{}
+	for key := range node.Labels {
+		if _, exists := labels[key]; !exists && isNodeLabellerLabel(key) {
+			changes[key] = nil
+		}
+	}
+	for key, value := range labels {
+		if node.Labels[key] != value {
+			changes[key] = value
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": changes,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = n.clientset.CoreV1().Nodes().Patch(n.host, types.StrategicMergePatchType, patch)
+	if err != nil {
+		return err
+	}
+	log.DefaultLogger().V(4).Infof("Updated %d capability labels of node %s", len(changes), n.host)
+	return nil
+}
+
+func isNodeLabellerLabel(key string) bool {
+	return strings.HasPrefix(key, v1.CPUModelLabel) ||
+		strings.HasPrefix(key, v1.CPUFeatureLabel) ||
+		strings.HasPrefix(key, v1.HypervLabel) ||
+		strings.HasPrefix(key, v1.HugepagesLabel) ||
+		key == v1.SEVLabel ||
+		key == v1.SGXLabel ||
+		key == v1.RealtimeLabel ||
+		key == v1.CPUThreadsPerCoreLabel
+}
+
+// hostLabels returns the labels describing the capabilities of the host
+func (n *NodeLabeller) hostLabels() (map[string]string, error) {
+	labels := map[string]string{}
+
+	flags, err := n.cpuFlags()
+	if err != nil {
+		return nil, err
+	}
+	for _, flag := range flags {
+		if alias, exists := cpuFlagAliases[flag]; exists {
+			flag = alias
+		}
+		labels[v1.CPUFeatureLabel+flag] = "true"
+		if flag == "sgx" {
+			labels[v1.SGXLabel] = "true"
+		}
+	}
+
+	models, err := n.cpuModels()
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range models {
+		labels[v1.CPUModelLabel+model] = "true"
+	}
+
+	if _, err := os.Stat(n.kvmPath); err == nil {
+		for enlightenment, capability := range hypervCapabilities {
+			if n.checkKVMExtension(capability) {
+				labels[v1.HypervLabel+enlightenment] = "true"
+			}
+		}
+	}
+
+	if n.sevEnabled() {
+		labels[v1.SEVLabel] = "true"
+	}
+
+	if n.realtimeKernel() {
+		labels[v1.RealtimeLabel] = "true"
+	}
+
+	if threads, err := hardware.ThreadsPerCore(filepath.Join(n.sysPath, "devices", "system", "cpu")); err == nil {
+		labels[v1.CPUThreadsPerCoreLabel] = strconv.Itoa(threads)
+	}
+
+	pageSizes, err := n.hugepageSizes()
+	if err != nil {
+		return nil, err
+	}
+	for _, pageSize := range pageSizes {
+		labels[v1.HugepagesLabel+pageSize] = "true"
+	}
+
+	return labels, nil
+}
+
+// cpuFlags returns the CPU flags the kernel reports for the first CPU
+func (n *NodeLabeller) cpuFlags() ([]string, error) {
+	file, err := os.Open(filepath.Join(n.procPath, "cpuinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == "flags" {
+			return strings.Fields(parts[1]), nil
+		}
+	}
+	return nil, scanner.Err()
+}
+
+// cpuModels returns the CPU models libvirt reports as usable on the host
+func (n *NodeLabeller) cpuModels() ([]string, error) {
+	content, err := ioutil.ReadFile(n.domCapabilitiesPath)
+	if os.IsNotExist(err) {
+		// the init container could not query libvirt, e.g. because KVM is missing
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	capabilities := &domCapabilities{}
+	if err := xml.Unmarshal(content, capabilities); err != nil {
+		return nil, err
+	}
+
+	var models []string
+	for _, mode := range capabilities.CPU.Modes {
+		if mode.Name != "custom" {
+			continue
+		}
+		for _, model := range mode.Models {
+			if model.Usable == "yes" {
+				models = append(models, model.Name)
+			}
+		}
+	}
+	return models, nil
+}
+
+func (n *NodeLabeller) kvmHasExtension(capability uintptr) bool {
+	kvm, err := os.OpenFile(n.kvmPath, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	defer kvm.Close()
+
+	ret, _, errno := syscall.Syscall(syscall.SYS_IOCTL, kvm.Fd(), kvmCheckExtension, capability)
+	return errno == 0 && ret > 0
+}
+
+func (n *NodeLabeller) sevEnabled() bool {
+	content, err := ioutil.ReadFile(filepath.Join(n.sysPath, "module", "kvm_amd", "parameters", "sev"))
+	if err != nil {
+		return false
+	}
+	value := strings.TrimSpace(string(content))
+	return value == "1" || value == "Y"
+}
+
+func (n *NodeLabeller) realtimeKernel() bool {
+	content, err := ioutil.ReadFile(filepath.Join(n.sysPath, "kernel", "realtime"))
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(content)) == "1"
+}
+
+// hugepageSizes returns the hugepage sizes the kernel supports, formatted like the hugepage resources of a node
+func (n *NodeLabeller) hugepageSizes() ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(n.sysPath, "kernel", "mm", "hugepages"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var pageSizes []string
+	for _, entry := range entries {
+		// entries are named like hugepages-2048kB
+		size := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "hugepages-"), "kB")
+		kiB, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			continue
+		}
+		pageSizes = append(pageSizes, resource.NewQuantity(kiB*1024, resource.BinarySI).String())
+	}
+	return pageSizes, nil
+}
@@ -0,0 +1,16 @@
+package nodelabeller_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/log"
+)
+
+func TestNodeLabeller(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.Log.SetIOWriter(GinkgoWriter)
+	RunSpecs(t, "NodeLabeller Suite")
+}
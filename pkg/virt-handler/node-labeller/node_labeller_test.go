@@ -0,0 +1,173 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package nodelabeller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/testutils"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+)
+
+const domCapabilitiesXML = `<domainCapabilities>
+  <cpu>
+    <mode name='host-passthrough' supported='yes'/>
+    <mode name='host-model' supported='yes'>
+      <model fallback='forbid'>Skylake-Client-IBRS</model>
+    </mode>
+    <mode name='custom' supported='yes'>
+      <model usable='yes'>Penryn</model>
+      <model usable='yes'>Haswell</model>
+      <model usable='no'>Icelake-Server</model>
+    </mode>
+  </cpu>
+</domainCapabilities>`
+
+var _ = Describe("Node-labeller", func() {
+	var ctrl *gomock.Controller
+	var kubeClient *fake.Clientset
+	var labeller *NodeLabeller
+	var tmpDir string
+
+	writeFile := func(path string, content string) {
+		path = filepath.Join(tmpDir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	nodeLabels := func() map[string]string {
+		node, err := kubeClient.CoreV1().Nodes().Get("testnode", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return node.Labels
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "nodelabeller")
+		Expect(err).ToNot(HaveOccurred())
+
+		writeFile("proc/cpuinfo", "processor\t: 0\nflags\t\t: fpu vmx sse4_2 sgx\n\nprocessor\t: 1\nflags\t\t: fpu vmx sse4_2 sgx\n")
+		writeFile("sys/module/kvm_amd/parameters/sev", "1\n")
+		writeFile("sys/kernel/realtime", "1\n")
+		writeFile("sys/devices/system/cpu/cpu0/topology/thread_siblings_list", "0,4\n")
+		Expect(os.MkdirAll(filepath.Join(tmpDir, "sys/kernel/mm/hugepages/hugepages-2048kB"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(tmpDir, "sys/kernel/mm/hugepages/hugepages-1048576kB"), 0755)).To(Succeed())
+		writeFile("dev/kvm", "")
+		writeFile("virsh_domcapabilities.xml", domCapabilitiesXML)
+
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient := kubecli.NewMockKubevirtClient(ctrl)
+		kubeClient = fake.NewSimpleClientset(&k8sv1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "testnode",
+				Labels: map[string]string{
+					"kubernetes.io/hostname":            "testnode",
+					v1.CPUModelLabel + "Conroe":         "true",
+					v1.CPUFeatureLabel + "svm":          "true",
+					v1.HugepagesLabel + "2Mi":           "true",
+					"feature.node.kubernetes.io/custom": "true",
+				},
+			},
+		})
+		virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		clusterConfig, _, _, _ := testutils.NewFakeClusterConfig(&k8sv1.ConfigMap{
+			Data: map[string]string{virtconfig.FeatureGatesKey: virtconfig.CPUNodeDiscoveryGate},
+		})
+		labeller = NewNodeLabeller(clusterConfig, virtClient, "testnode")
+		labeller.procPath = filepath.Join(tmpDir, "proc")
+		labeller.sysPath = filepath.Join(tmpDir, "sys")
+		labeller.kvmPath = filepath.Join(tmpDir, "dev/kvm")
+		labeller.domCapabilitiesPath = filepath.Join(tmpDir, "virsh_domcapabilities.xml")
+		labeller.checkKVMExtension = func(capability uintptr) bool {
+			// pretend only the base Hyper-V capability is there
+			return capability == 44
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+		ctrl.Finish()
+	})
+
+	It("should label the node with the capabilities of the host", func() {
+		Expect(labeller.labelNode()).To(Succeed())
+
+		labels := nodeLabels()
+		Expect(labels).To(HaveKeyWithValue(v1.CPUFeatureLabel+"vmx", "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.CPUFeatureLabel+"sse4.2", "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.CPUModelLabel+"Penryn", "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.CPUModelLabel+"Haswell", "true"))
+		Expect(labels).ToNot(HaveKey(v1.CPUModelLabel + "Icelake-Server"))
+		Expect(labels).ToNot(HaveKey(v1.CPUModelLabel + "Skylake-Client-IBRS"))
+		Expect(labels).To(HaveKeyWithValue(v1.HypervLabel+"reset", "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.HypervLabel+"runtime", "true"))
+		Expect(labels).ToNot(HaveKey(v1.HypervLabel + "synic"))
+		Expect(labels).To(HaveKeyWithValue(v1.SEVLabel, "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.SGXLabel, "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.RealtimeLabel, "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.CPUThreadsPerCoreLabel, "2"))
+		Expect(labels).To(HaveKeyWithValue(v1.HugepagesLabel+"2Mi", "true"))
+		Expect(labels).To(HaveKeyWithValue(v1.HugepagesLabel+"1Gi", "true"))
+	})
+
+	It("should remove labels of capabilities the host does not have anymore", func() {
+		Expect(labeller.labelNode()).To(Succeed())
+
+		labels := nodeLabels()
+		Expect(labels).ToNot(HaveKey(v1.CPUModelLabel + "Conroe"))
+		Expect(labels).ToNot(HaveKey(v1.CPUFeatureLabel + "svm"))
+		Expect(labels).To(HaveKeyWithValue("kubernetes.io/hostname", "testnode"))
+		Expect(labels).To(HaveKeyWithValue("feature.node.kubernetes.io/custom", "true"))
+	})
+
+	It("should not label KVM capabilities without KVM", func() {
+		Expect(os.Remove(labeller.kvmPath)).To(Succeed())
+		Expect(os.Remove(labeller.domCapabilitiesPath)).To(Succeed())
+
+		Expect(labeller.labelNode()).To(Succeed())
+
+		for key := range nodeLabels() {
+			Expect(key).ToNot(HavePrefix(v1.HypervLabel))
+			Expect(key).ToNot(HavePrefix(v1.CPUModelLabel))
+		}
+	})
+
+	It("should not patch the node if the labels are up to date", func() {
+		Expect(labeller.labelNode()).To(Succeed())
+		kubeClient.ClearActions()
+
+		Expect(labeller.labelNode()).To(Succeed())
+		for _, action := range kubeClient.Actions() {
+			Expect(action.GetVerb()).To(Equal("get"))
+		}
+	})
+})
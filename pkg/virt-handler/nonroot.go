@@ -0,0 +1,76 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+// nonRootDevices are the devices of the device plugins qemu opens, kubelet
+// creates them owned by root in the launcher pod
+var nonRootDevices = []string{"dev/kvm", "dev/net/tun", "dev/vhost-net", "dev/sev"}
+
+// prepareNonRootDevices hands the devices of the launcher pod over to the user
+// qemu and libvirt run as, a non-root virt-launcher can not do it on its own
+func (d *VirtualMachineController) prepareNonRootDevices(vmi *v1.VirtualMachineInstance) error {
+	res, err := d.podIsolationDetector.Detect(vmi)
+	if err != nil {
+		return fmt.Errorf("failed to detect isolation for launcher pod: %v", err)
+	}
+	return chownNonRootDevices(res.MountRoot(), vmi)
+}
+
+// chownNonRootDevices changes the owner of the devices below the root of the
+// launcher pod. Besides the devices of the device plugins, these are the block
+// volumes, which are mapped to /dev/<volume name>, and the vfio groups of the
+// host devices. Devices which the pod lacks are skipped.
+func chownNonRootDevices(root string, vmi *v1.VirtualMachineInstance) error {
+	devices := append([]string{}, nonRootDevices...)
+	for _, volume := range vmi.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil || volume.DataVolume != nil {
+			devices = append(devices, filepath.Join("dev", volume.Name))
+		}
+	}
+
+	groups, err := filepath.Glob(filepath.Join(root, "dev", "vfio", "*"))
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		// the vfio container is accessible to everyone
+		if filepath.Base(group) != "vfio" {
+			devices = append(devices, filepath.Join("dev", "vfio", filepath.Base(group)))
+		}
+	}
+
+	user := int(vmi.Status.RuntimeUser)
+	for _, device := range devices {
+		// the devices themselves are never symlinks, don't follow one
+		err := os.Lchown(filepath.Join(root, device), user, user)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to hand /%s over to the user %d: %v", device, user, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/util"
+)
+
+var _ = Describe("Non-root devices", func() {
+	var root string
+
+	BeforeEach(func() {
+		if os.Geteuid() != 0 {
+			Skip("changing the owner of files requires root")
+		}
+		var err error
+		root, err = ioutil.TempDir("", "launcher-root")
+		Expect(err).ToNot(HaveOccurred())
+		for _, device := range []string{"dev/kvm", "dev/vfio/vfio", "dev/vfio/12", "dev/disk0", "dev/other"} {
+			Expect(os.MkdirAll(filepath.Join(root, filepath.Dir(device)), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, device), nil, 0600)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	ownerOf := func(device string) uint32 {
+		info, err := os.Stat(filepath.Join(root, device))
+		Expect(err).ToNot(HaveOccurred())
+		return info.Sys().(*syscall.Stat_t).Uid
+	}
+
+	It("should hand the devices of the launcher pod over to the runtime user", func() {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmi.Status.RuntimeUser = util.NonRootUID
+		vmi.Spec.Volumes = []v1.Volume{{
+			Name: "disk0",
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{},
+			},
+		}}
+
+		Expect(chownNonRootDevices(root, vmi)).To(Succeed())
+		Expect(ownerOf("dev/kvm")).To(Equal(uint32(util.NonRootUID)))
+		Expect(ownerOf("dev/vfio/12")).To(Equal(uint32(util.NonRootUID)))
+		Expect(ownerOf("dev/disk0")).To(Equal(uint32(util.NonRootUID)))
+		Expect(ownerOf("dev/vfio/vfio")).To(BeZero())
+		Expect(ownerOf("dev/other")).To(BeZero())
+	})
+})
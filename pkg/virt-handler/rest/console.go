@@ -20,6 +20,7 @@
 package rest
 
 import (
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -44,6 +45,8 @@ type ConsoleHandler struct {
 	vncStopChans         map[types.UID](chan struct{})
 	serialLock           *sync.Mutex
 	vncLock              *sync.Mutex
+	usbredirSlots        map[types.UID]map[int]struct{}
+	usbredirLock         *sync.Mutex
 	vmiInformer          cache.SharedIndexInformer
 }
 
@@ -54,6 +57,8 @@ func NewConsoleHandler(podIsolationDetector isolation.PodIsolationDetector, vmiI
 		vncStopChans:         make(map[types.UID](chan struct{})),
 		serialLock:           &sync.Mutex{},
 		vncLock:              &sync.Mutex{},
+		usbredirSlots:        make(map[types.UID]map[int]struct{}),
+		usbredirLock:         &sync.Mutex{},
 		vmiInformer:          vmiInformer,
 	}
 }
@@ -76,7 +81,7 @@ func (t *ConsoleHandler) VNCHandler(request *restful.Request, response *restful.
 	cleanup := func() {
 		deleteStopChan(uid, stopChn, t.vncLock, t.vncStopChans)
 	}
-	t.stream(vmi, request, response, unixSocketPath, stopChn, cleanup)
+	t.stream(vmi, request, response, unixSocketPath, dialUnixSocket(unixSocketPath), stopChn, cleanup)
 }
 
 func (t *ConsoleHandler) SerialHandler(request *restful.Request, response *restful.Response) {
@@ -97,7 +102,125 @@ func (t *ConsoleHandler) SerialHandler(request *restful.Request, response *restf
 	cleanup := func() {
 		deleteStopChan(uid, stopCh, t.serialLock, t.serialStopChans)
 	}
-	t.stream(vmi, request, response, unixSocketPath, stopCh, cleanup)
+	t.stream(vmi, request, response, unixSocketPath, dialUnixSocket(unixSocketPath), stopCh, cleanup)
+}
+
+// PortForwardHandler connects the websocket to a TCP port of the guest. The
+// port is dialed on the loopback device of the virt-launcher pod, which
+// forwards the declared ports of the masquerade binding to the guest.
+func (t *ConsoleHandler) PortForwardHandler(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, t.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+	port, err := strconv.Atoi(request.PathParameter("port"))
+	if err != nil || port < 1 || port > 65535 {
+		err = fmt.Errorf("invalid port %q", request.PathParameter("port"))
+		log.Log.Object(vmi).Reason(err).Error("Failed to forward a port")
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	result, err := t.podIsolationDetector.Detect(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to detect the network namespace of the VMI")
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	address := net.JoinHostPort("localhost", strconv.Itoa(port))
+	dial := func() (conn net.Conn, err error) {
+		nsErr := result.DoNetNS(func() error {
+			conn, err = net.Dial("tcp", address)
+			return nil
+		})
+		if nsErr != nil {
+			return nil, nsErr
+		}
+		return conn, err
+	}
+	// Every forwarded TCP connection has its own websocket, so unlike the
+	// consoles a new connection must not close the existing ones
+	t.stream(vmi, request, response, address, dial, nil, func() {})
+}
+
+// USBRedirHandler connects the websocket to a free usbredir socket of the
+// VMI. Every socket redirects a single USB device, so unlike the consoles a
+// new connection takes the next free socket instead of closing the existing one.
+func (t *ConsoleHandler) USBRedirHandler(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, t.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+	slot, unixSocketPath, err := t.reserveUSBRedirSlot(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed finding a unix socket for USB redirection")
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	uid := vmi.GetUID()
+	defer t.releaseUSBRedirSlot(uid, slot)
+	t.stream(vmi, request, response, unixSocketPath, dialUnixSocket(unixSocketPath), nil, func() {})
+}
+
+// BackupHandler connects the websocket to the NBD server of the running
+// backup of the VMI. Every NBD client has its own websocket, so a new
+// connection does not close the existing ones.
+func (t *ConsoleHandler) BackupHandler(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, t.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+	unixSocketPath, err := t.getUnixSocketPath(vmi, "virt-backup")
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed finding unix socket for the backup")
+		response.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	t.stream(vmi, request, response, unixSocketPath, dialUnixSocket(unixSocketPath), nil, func() {})
+}
+
+func (t *ConsoleHandler) reserveUSBRedirSlot(vmi *v1.VirtualMachineInstance) (int, string, error) {
+	t.usbredirLock.Lock()
+	defer t.usbredirLock.Unlock()
+	slots, ok := t.usbredirSlots[vmi.GetUID()]
+	if !ok {
+		slots = make(map[int]struct{})
+		t.usbredirSlots[vmi.GetUID()] = slots
+	}
+	for slot := 0; slot < v1.UsbClientPassthroughMaxNumberOf; slot++ {
+		if _, inUse := slots[slot]; inUse {
+			continue
+		}
+		unixSocketPath, err := t.getUnixSocketPath(vmi, fmt.Sprintf("virt-usbredir-%d", slot))
+		if err != nil {
+			return -1, "", err
+		}
+		slots[slot] = struct{}{}
+		return slot, unixSocketPath, nil
+	}
+	return -1, "", fmt.Errorf("all %d USB redirection slots are in use", v1.UsbClientPassthroughMaxNumberOf)
+}
+
+func (t *ConsoleHandler) releaseUSBRedirSlot(uid types.UID, slot int) {
+	t.usbredirLock.Lock()
+	defer t.usbredirLock.Unlock()
+	if slots, ok := t.usbredirSlots[uid]; ok {
+		delete(slots, slot)
+		if len(slots) == 0 {
+			delete(t.usbredirSlots, uid)
+		}
+	}
+}
+
+func dialUnixSocket(unixSocketPath string) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		return net.Dial("unix", unixSocketPath)
+	}
 }
 
 func newStopChan(uid types.UID, lock *sync.Mutex, stopChans map[types.UID](chan struct{})) chan struct{} {
@@ -144,7 +267,7 @@ func (t *ConsoleHandler) getUnixSocketPath(vmi *v1.VirtualMachineInstance, socke
 
 type cleanupOnError func()
 
-func (t *ConsoleHandler) stream(vmi *v1.VirtualMachineInstance, request *restful.Request, response *restful.Response, unixSocketPath string, stopCh chan struct{}, cleanup cleanupOnError) {
+func (t *ConsoleHandler) stream(vmi *v1.VirtualMachineInstance, request *restful.Request, response *restful.Response, target string, dial func() (net.Conn, error), stopCh chan struct{}, cleanup cleanupOnError) {
 	var upgrader = kubecli.NewUpgrader()
 	clientSocket, err := upgrader.Upgrade(response.ResponseWriter, request.Request, nil)
 	if err != nil {
@@ -155,22 +278,22 @@ func (t *ConsoleHandler) stream(vmi *v1.VirtualMachineInstance, request *restful
 	defer clientSocket.Close()
 
 	log.Log.Object(vmi).Infof("Websocket connection upgraded")
-	log.Log.Object(vmi).Infof("Connecting to %s", unixSocketPath)
+	log.Log.Object(vmi).Infof("Connecting to %s", target)
 
-	fd, err := net.Dial("unix", unixSocketPath)
+	fd, err := dial()
 	if err != nil {
-		log.Log.Object(vmi).Reason(err).Errorf("failed to dial unix socket %s", unixSocketPath)
+		log.Log.Object(vmi).Reason(err).Errorf("failed to dial %s", target)
 		response.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	defer fd.Close()
 
-	log.Log.Object(vmi).Infof("Connected to %s", unixSocketPath)
+	log.Log.Object(vmi).Infof("Connected to %s", target)
 
 	errCh := make(chan error)
 	go func() {
 		_, err := kubecli.CopyTo(clientSocket, fd)
-		log.Log.Object(vmi).Reason(err).Error("error encountered reading from unix socket")
+		log.Log.Object(vmi).Reason(err).Errorf("error encountered reading from %s", target)
 		errCh <- err
 	}()
 
@@ -185,7 +308,7 @@ func (t *ConsoleHandler) stream(vmi *v1.VirtualMachineInstance, request *restful
 		break
 	case err := <-errCh:
 		if err != nil && err != io.EOF {
-			log.Log.Object(vmi).Reason(err).Error("Error in proxing websocket and unix socket")
+			log.Log.Object(vmi).Reason(err).Errorf("Error in proxing websocket and %s", target)
 			response.WriteHeader(http.StatusInternalServerError)
 		}
 
@@ -19,14 +19,18 @@
 package rest
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/emicklei/go-restful"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 
+	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
 )
 
 type LifecycleHandler struct {
@@ -97,6 +101,70 @@ func (lh *LifecycleHandler) UnpauseHandler(request *restful.Request, response *r
 	response.WriteHeader(http.StatusAccepted)
 }
 
+func (lh *LifecycleHandler) FreezeHandler(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, lh.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+
+	sockFile, err := cmdclient.FindSocketOnHost(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	client, err := cmdclient.NewClient(sockFile)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	defer client.Close()
+
+	err = client.FreezeVirtualMachine(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to freeze VMI")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusAccepted)
+}
+
+func (lh *LifecycleHandler) ThawHandler(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, lh.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+
+	sockFile, err := cmdclient.FindSocketOnHost(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	client, err := cmdclient.NewClient(sockFile)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	defer client.Close()
+
+	err = client.UnfreezeVirtualMachine(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to thaw VMI")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusAccepted)
+}
+
 func (lh *LifecycleHandler) GetGuestInfo(request *restful.Request, response *restful.Response) {
 	log.Log.Info("Retreiving guestinfo")
 	vmi, code, err := getVMI(request, lh.vmiInformer)
@@ -112,17 +180,21 @@ func (lh *LifecycleHandler) GetGuestInfo(request *restful.Request, response *res
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 	client, err := cmdclient.NewClient(sockFile)
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
+	defer client.Close()
 
 	guestInfo, err := client.GetGuestInfo()
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to get guest info")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 
 	log.Log.Object(vmi).Infof("returning guestinfo :%v", guestInfo)
@@ -143,17 +215,21 @@ func (lh *LifecycleHandler) GetUsers(request *restful.Request, response *restful
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 	client, err := cmdclient.NewClient(sockFile)
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
+	defer client.Close()
 
 	userList, err := client.GetUsers()
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to get user list")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response.WriteEntity(userList)
@@ -173,18 +249,101 @@ func (lh *LifecycleHandler) GetFilesystems(request *restful.Request, response *r
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 	client, err := cmdclient.NewClient(sockFile)
 	if err != nil {
 		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
+	defer client.Close()
 
 	fsList, err := client.GetFilesystems()
 	if err != nil {
-		log.Log.Object(vmi).Reason(err).Error("Failed to get guest info")
+		log.Log.Object(vmi).Reason(err).Error("Failed to get filesystem list")
 		response.WriteError(http.StatusInternalServerError, err)
+		return
 	}
 
 	response.WriteEntity(fsList)
 }
+
+func (lh *LifecycleHandler) GetStats(request *restful.Request, response *restful.Response) {
+	vmi, code, err := getVMI(request, lh.vmiInformer)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to retrieve VMI")
+		response.WriteError(code, err)
+		return
+	}
+
+	sockFile, err := cmdclient.FindSocketOnHost(vmi)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to detect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	client, err := cmdclient.NewClient(sockFile)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to connect cmd client")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	defer client.Close()
+
+	domainStats, exists, err := client.GetDomainStats()
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to get domain stats")
+		response.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	if !exists {
+		response.WriteError(http.StatusNotFound, fmt.Errorf("domain of VMI %s/%s does not exist", vmi.Namespace, vmi.Name))
+		return
+	}
+
+	response.WriteEntity(toVMIStats(domainStats))
+}
+
+// toVMIStats aggregates the domain stats reported by virt-launcher into the
+// per VMI totals exposed through the stats subresource.
+func toVMIStats(domainStats *stats.DomainStats) *v1.VirtualMachineInstanceStats {
+	vmiStats := &v1.VirtualMachineInstanceStats{
+		Timestamp: metav1.NowMicro(),
+		VCPUs:     len(domainStats.Vcpu),
+	}
+
+	if domainStats.Cpu != nil && domainStats.Cpu.TimeSet {
+		vmiStats.CPUTimeNanoseconds = domainStats.Cpu.Time
+	}
+
+	if domainStats.Memory != nil {
+		// libvirt reports memory in KiB
+		if domainStats.Memory.RSSSet {
+			vmiStats.MemoryResidentBytes = domainStats.Memory.RSS * 1024
+		}
+		if domainStats.Memory.AvailableSet {
+			vmiStats.MemoryAvailableBytes = domainStats.Memory.Available * 1024
+		}
+	}
+
+	for _, block := range domainStats.Block {
+		if block.RdBytesSet {
+			vmiStats.StorageReadBytes += block.RdBytes
+		}
+		if block.WrBytesSet {
+			vmiStats.StorageWriteBytes += block.WrBytes
+		}
+	}
+
+	for _, net := range domainStats.Net {
+		if net.RxBytesSet {
+			vmiStats.NetworkRxBytes += net.RxBytes
+		}
+		if net.TxBytesSet {
+			vmiStats.NetworkTxBytes += net.TxBytes
+		}
+	}
+
+	return vmiStats
+}
@@ -0,0 +1,144 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package seccomp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util"
+)
+
+type Action string
+
+const (
+	ActAllow Action = "SCMP_ACT_ALLOW"
+	ActErrno Action = "SCMP_ACT_ERRNO"
+)
+
+// Profile is a seccomp profile in the format the container runtimes read from the
+// seccomp directory of the kubelet
+type Profile struct {
+	DefaultAction Action     `json:"defaultAction"`
+	Syscalls      []*Syscall `json:"syscalls,omitempty"`
+}
+
+type Syscall struct {
+	Names  []string `json:"names"`
+	Action Action   `json:"action"`
+}
+
+// deniedSyscalls administrate the kernel or the host, neither qemu nor libvirt
+// ever call them in the virt-launcher pod
+var deniedSyscalls = []string{
+	"_sysctl",
+	"acct",
+	"add_key",
+	"bpf",
+	"clock_adjtime",
+	"clock_settime",
+	"create_module",
+	"delete_module",
+	"finit_module",
+	"get_kernel_syms",
+	"init_module",
+	"ioperm",
+	"iopl",
+	"kcmp",
+	"kexec_file_load",
+	"kexec_load",
+	"keyctl",
+	"lookup_dcookie",
+	"nfsservctl",
+	"open_by_handle_at",
+	"perf_event_open",
+	"pivot_root",
+	"process_vm_readv",
+	"process_vm_writev",
+	"ptrace",
+	"query_module",
+	"quotactl",
+	"reboot",
+	"request_key",
+	"setdomainname",
+	"sethostname",
+	"settimeofday",
+	"stime",
+	"swapoff",
+	"swapon",
+	"sysfs",
+	"syslog",
+	"uselib",
+	"ustat",
+	"vhangup",
+	"vm86",
+	"vm86old",
+}
+
+// DefaultProfile returns the kubevirt/default profile. Unlike the default profiles of the
+// container runtimes it allows userfaultfd, which qemu needs for post-copy migrations, and
+// the memory policy syscalls qemu uses for NUMA placement
+func DefaultProfile() *Profile {
+	return &Profile{
+		DefaultAction: ActAllow,
+		Syscalls: []*Syscall{
+			{
+				Names:  deniedSyscalls,
+				Action: ActErrno,
+			},
+		},
+	}
+}
+
+// InstallProfile writes the kubevirt/default profile into the given seccomp directory of
+// the kubelet, unless it is already installed
+func InstallProfile(seccompDir string) error {
+	profile, err := json.MarshalIndent(DefaultProfile(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(seccompDir, util.KubeVirtSeccompProfile)
+	installed, err := ioutil.ReadFile(path)
+	if err == nil && bytes.Equal(installed, profile) {
+		return nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read the installed seccomp profile %s: %v", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// the container runtime must never read a partially written profile
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, profile, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	log.Log.Infof("Installed the seccomp profile %s", path)
+	return nil
+}
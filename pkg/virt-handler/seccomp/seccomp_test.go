@@ -0,0 +1,73 @@
+package seccomp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("seccomp", func() {
+
+	var seccompDir string
+	var profilePath string
+
+	BeforeEach(func() {
+		var err error
+		seccompDir, err = ioutil.TempDir("", "kubevirt")
+		Expect(err).ToNot(HaveOccurred())
+		profilePath = filepath.Join(seccompDir, "kubevirt", "default.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(seccompDir)
+	})
+
+	readProfile := func() *Profile {
+		content, err := ioutil.ReadFile(profilePath)
+		Expect(err).ToNot(HaveOccurred())
+		profile := &Profile{}
+		Expect(json.Unmarshal(content, profile)).To(Succeed())
+		return profile
+	}
+
+	It("should install the profile", func() {
+		Expect(InstallProfile(seccompDir)).To(Succeed())
+		Expect(readProfile()).To(Equal(DefaultProfile()))
+	})
+
+	It("should replace a modified profile", func() {
+		Expect(os.MkdirAll(filepath.Dir(profilePath), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(profilePath, []byte(`{"defaultAction":"SCMP_ACT_ERRNO"}`), 0644)).To(Succeed())
+
+		Expect(InstallProfile(seccompDir)).To(Succeed())
+		Expect(readProfile()).To(Equal(DefaultProfile()))
+		_, err := os.Stat(profilePath + ".tmp")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should keep an installed profile", func() {
+		Expect(InstallProfile(seccompDir)).To(Succeed())
+		info, err := os.Stat(profilePath)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(InstallProfile(seccompDir)).To(Succeed())
+		reinstalled, err := os.Stat(profilePath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.SameFile(info, reinstalled)).To(BeTrue())
+	})
+
+	It("should allow the syscalls qemu needs", func() {
+		profile := DefaultProfile()
+		Expect(profile.DefaultAction).To(Equal(ActAllow))
+		for _, syscall := range profile.Syscalls {
+			if syscall.Action != ActAllow {
+				Expect(syscall.Names).ToNot(ContainElement("userfaultfd"))
+				Expect(syscall.Names).ToNot(ContainElement("mbind"))
+			}
+		}
+	})
+})
@@ -0,0 +1,310 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+)
+
+const hostNetNSPath = "/proc/1/ns/net"
+
+var hostPCIDevicesPath = filepath.Join(util.HostRootMount, "sys/bus/pci/devices")
+
+// vfConfig is the configuration of a virtual function before virt-handler
+// trusted it for the allowed MAC addresses of an interface
+type vfConfig struct {
+	PCIAddress string `json:"pciAddress"`
+	SpoofCheck bool   `json:"spoofCheck"`
+	// Trusted tells that the virtual function was trusted successfully
+	Trusted bool `json:"trusted"`
+}
+
+// vfRecord lists the virtual functions of a VMI changed by virt-handler. It is
+// kept on disk, so that they are restored even if virt-handler restarts.
+type vfRecord struct {
+	VFs []vfConfig `json:"vfs"`
+}
+
+// vfSpoofCheckHandler changes the virtual functions passed through to guests,
+// virt-handler itself runs in the network namespace of its pod
+type vfSpoofCheckHandler interface {
+	// Config returns the current configuration of a virtual function
+	Config(pciAddress string) (vfConfig, error)
+	// AllowAdditionalMACs trusts a virtual function and keeps its spoof checking on
+	AllowAdditionalMACs(pciAddress string) error
+	// Restore revokes the trust of a virtual function and restores its spoof checking
+	Restore(config vfConfig) error
+}
+
+type hostVFHandler struct{}
+
+// withVF runs f with a netlink handle in the host network namespace, the
+// physical function of the virtual function and the index of the virtual
+// function on it
+func withVF(pciAddress string, f func(handle *netlink.Handle, pf netlink.Link, vfIndex int) error) error {
+	pfName, vfIndex, err := physicalFunctionOf(pciAddress)
+	if err != nil {
+		return err
+	}
+
+	ns, err := netns.GetFromPath(hostNetNSPath)
+	if err != nil {
+		return fmt.Errorf("failed to open the host network namespace: %v", err)
+	}
+	defer ns.Close()
+	handle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return err
+	}
+	defer handle.Delete()
+
+	pf, err := handle.LinkByName(pfName)
+	if err != nil {
+		return fmt.Errorf("failed to find the physical function %s of %s: %v", pfName, pciAddress, err)
+	}
+	return f(handle, pf, vfIndex)
+}
+
+func (h *hostVFHandler) Config(pciAddress string) (vfConfig, error) {
+	config := vfConfig{PCIAddress: pciAddress}
+	err := withVF(pciAddress, func(_ *netlink.Handle, pf netlink.Link, vfIndex int) error {
+		for _, vf := range pf.Attrs().Vfs {
+			if vf.ID == vfIndex {
+				config.SpoofCheck = vf.Spoofchk
+				return nil
+			}
+		}
+		return fmt.Errorf("physical function %s does not report virtual function %d", pf.Attrs().Name, vfIndex)
+	})
+	return config, err
+}
+
+// AllowAdditionalMACs trusts the virtual function, so that the guest can add
+// filters for the allowed MAC addresses beyond the limit of untrusted virtual
+// functions. The spoof checking stays on, the NIC drops the frames of MAC
+// addresses the virtual function has no filter for.
+func (h *hostVFHandler) AllowAdditionalMACs(pciAddress string) error {
+	return withVF(pciAddress, func(handle *netlink.Handle, pf netlink.Link, vfIndex int) error {
+		if err := handle.LinkSetVfTrust(pf, vfIndex, true); err != nil {
+			return fmt.Errorf("failed to trust virtual function %d of %s: %v", vfIndex, pf.Attrs().Name, err)
+		}
+		if err := handle.LinkSetVfSpoofchk(pf, vfIndex, true); err != nil {
+			return fmt.Errorf("failed to turn spoof checking on for virtual function %d of %s: %v", vfIndex, pf.Attrs().Name, err)
+		}
+		return nil
+	})
+}
+
+// Restore revokes the trust of the virtual function, the trust of a virtual
+// function can not be read, the SR-IOV CNI plugin grants it again if the
+// network asks for it
+func (h *hostVFHandler) Restore(config vfConfig) error {
+	return withVF(config.PCIAddress, func(handle *netlink.Handle, pf netlink.Link, vfIndex int) error {
+		if err := handle.LinkSetVfSpoofchk(pf, vfIndex, config.SpoofCheck); err != nil {
+			return fmt.Errorf("failed to restore the spoof checking of virtual function %d of %s: %v", vfIndex, pf.Attrs().Name, err)
+		}
+		if err := handle.LinkSetVfTrust(pf, vfIndex, false); err != nil {
+			return fmt.Errorf("failed to revoke the trust of virtual function %d of %s: %v", vfIndex, pf.Attrs().Name, err)
+		}
+		return nil
+	})
+}
+
+// physicalFunctionOf returns the netdev name of the physical function of a
+// virtual function and the index of the virtual function on it
+func physicalFunctionOf(pciAddress string) (string, int, error) {
+	physfn := filepath.Join(hostPCIDevicesPath, pciAddress, "physfn")
+	netdevs, err := ioutil.ReadDir(filepath.Join(physfn, "net"))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find the physical function of %s: %v", pciAddress, err)
+	}
+	if len(netdevs) == 0 {
+		return "", 0, fmt.Errorf("the physical function of %s has no network device", pciAddress)
+	}
+
+	virtfns, err := filepath.Glob(filepath.Join(physfn, "virtfn*"))
+	if err != nil {
+		return "", 0, err
+	}
+	for _, virtfn := range virtfns {
+		target, err := os.Readlink(virtfn)
+		if err != nil || filepath.Base(target) != pciAddress {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(virtfn), "virtfn"))
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to parse the virtual function index of %s: %v", pciAddress, err)
+		}
+		return netdevs[0].Name(), index, nil
+	}
+	return "", 0, fmt.Errorf("failed to find %s among the virtual functions of its physical function", pciAddress)
+}
+
+var vfSpoofCheck vfSpoofCheckHandler = &hostVFHandler{}
+
+// processAllowedMACs lets the guest send from the additionally allowed MAC
+// addresses of its SR-IOV interfaces, e.g. the virtual MAC of a VRRP instance.
+// The guest announces the addresses itself once it takes them over. The
+// configuration of a virtual function is recorded before it is changed, so
+// that restoreAllowedMACs can bring it back once the VMI is gone.
+func (d *VirtualMachineController) processAllowedMACs(vmi *v1.VirtualMachineInstance, domain *api.Domain) error {
+	if domain == nil {
+		return nil
+	}
+	var record *vfRecord
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		if iface.SRIOV == nil || iface.AllowedAddresses == nil || len(iface.AllowedAddresses.MACAddresses) == 0 {
+			continue
+		}
+		address := sriovSourceAddress(domain, iface.Name)
+		if address == nil {
+			continue
+		}
+		pciAddress := fmt.Sprintf("%s:%s:%s.%s",
+			strings.TrimPrefix(address.Domain, "0x"),
+			strings.TrimPrefix(address.Bus, "0x"),
+			strings.TrimPrefix(address.Slot, "0x"),
+			strings.TrimPrefix(address.Function, "0x"))
+
+		if record == nil {
+			var err error
+			if record, err = d.getVFRecord(vmi); err != nil {
+				return err
+			}
+		}
+		config := record.find(pciAddress)
+		if config == nil {
+			original, err := vfSpoofCheck.Config(pciAddress)
+			if err != nil {
+				return fmt.Errorf("failed to allow the additional MAC addresses of interface %s: %v", iface.Name, err)
+			}
+			record.VFs = append(record.VFs, original)
+			if err := d.setVFRecord(vmi, record); err != nil {
+				return err
+			}
+			config = &record.VFs[len(record.VFs)-1]
+		}
+		if config.Trusted {
+			continue
+		}
+
+		if err := vfSpoofCheck.AllowAdditionalMACs(pciAddress); err != nil {
+			return fmt.Errorf("failed to allow the additional MAC addresses of interface %s: %v", iface.Name, err)
+		}
+		config.Trusted = true
+		if err := d.setVFRecord(vmi, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreAllowedMACs restores the virtual functions processAllowedMACs changed
+func (d *VirtualMachineController) restoreAllowedMACs(vmi *v1.VirtualMachineInstance) error {
+	// UID is required in order to find the record
+	if string(vmi.GetUID()) == "" {
+		return nil
+	}
+	record, err := d.getVFRecord(vmi)
+	if err != nil {
+		return err
+	}
+	for _, config := range record.VFs {
+		if err := vfSpoofCheck.Restore(config); err != nil {
+			return fmt.Errorf("failed to restore virtual function %s: %v", config.PCIAddress, err)
+		}
+	}
+	if err := os.Remove(d.vfRecordFile(vmi)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *VirtualMachineController) vfRecordFile(vmi *v1.VirtualMachineInstance) string {
+	return filepath.Join(d.vfStateDir, string(vmi.UID))
+}
+
+// getVFRecord returns the virtual functions changed for the VMI, the record is
+// empty if none were changed
+func (d *VirtualMachineController) getVFRecord(vmi *v1.VirtualMachineInstance) (*vfRecord, error) {
+	if string(vmi.UID) == "" {
+		return nil, fmt.Errorf("unable to find the changed virtual functions of a vmi without uid")
+	}
+	record := &vfRecord{}
+	bytes, err := ioutil.ReadFile(d.vfRecordFile(vmi))
+	if os.IsNotExist(err) {
+		return record, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bytes, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (d *VirtualMachineController) setVFRecord(vmi *v1.VirtualMachineInstance, record *vfRecord) error {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.vfStateDir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(d.vfRecordFile(vmi), bytes, 0644)
+}
+
+func (r *vfRecord) find(pciAddress string) *vfConfig {
+	for i := range r.VFs {
+		if r.VFs[i].PCIAddress == pciAddress {
+			return &r.VFs[i]
+		}
+	}
+	return nil
+}
+
+// sriovSourceAddress returns the host PCI address of the virtual function
+// passed through for an SR-IOV interface
+func sriovSourceAddress(domain *api.Domain, name string) *api.Address {
+	for _, hostDev := range domain.Spec.Devices.HostDevices {
+		if hostDev.Alias != nil && hostDev.Alias.Name == name {
+			return hostDev.Source.Address
+		}
+	}
+	for _, iface := range domain.Spec.Devices.Interfaces {
+		if iface.Type == "hostdev" && iface.Alias != nil && iface.Alias.Name == name {
+			return iface.Source.Address
+		}
+	}
+	return nil
+}
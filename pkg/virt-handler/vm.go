@@ -37,6 +37,7 @@ import (
 
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -55,7 +56,9 @@ import (
 	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
 	hostdisk "kubevirt.io/kubevirt/pkg/host-disk"
 	virtutil "kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/util/cgroup"
 	clusterutils "kubevirt.io/kubevirt/pkg/util/cluster"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	pvcutils "kubevirt.io/kubevirt/pkg/util/types"
 	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
 	virtcache "kubevirt.io/kubevirt/pkg/virt-handler/cache"
@@ -68,6 +71,12 @@ import (
 	"kubevirt.io/kubevirt/pkg/watchdog"
 )
 
+// hostNUMANodesPath is where virt-handler reads the NUMA topology of the host
+var hostNUMANodesPath = hardware.NUMANodesPath
+
+// hostCPUPath is where virt-handler reads the SMT topology of the host
+var hostCPUPath = hardware.CPUPath
+
 type launcherClientInfo struct {
 	client             cmdclient.LauncherClient
 	socketFile         string
@@ -85,6 +94,7 @@ func NewController(
 	vmiTargetInformer cache.SharedIndexInformer,
 	domainInformer cache.SharedInformer,
 	gracefulShutdownInformer cache.SharedIndexInformer,
+	pvcInformer cache.SharedIndexInformer,
 	watchdogTimeoutSeconds int,
 	maxDevices int,
 	clusterConfig *virtconfig.ClusterConfig,
@@ -106,11 +116,13 @@ func NewController(
 		vmiTargetInformer:        vmiTargetInformer,
 		domainInformer:           domainInformer,
 		gracefulShutdownInformer: gracefulShutdownInformer,
+		pvcInformer:              pvcInformer,
 		heartBeatInterval:        1 * time.Minute,
 		watchdogTimeoutSeconds:   watchdogTimeoutSeconds,
-		migrationProxy:           migrationproxy.NewMigrationProxyManager(serverTLSConfig, clientTLSConfig),
+		migrationProxy:           migrationproxy.NewMigrationProxyManager(serverTLSConfig, clientTLSConfig, clusterConfig),
 		podIsolationDetector:     podIsolationDetector,
 		containerDiskMounter:     container_disk.NewMounter(podIsolationDetector, virtPrivateDir+"/container-disk-mount-state"),
+		vfStateDir:               virtPrivateDir + "/sriov-vf-state",
 		clusterConfig:            clusterConfig,
 	}
 
@@ -141,10 +153,13 @@ func NewController(
 	c.launcherClients = make(map[types.UID]*launcherClientInfo)
 	c.phase1NetworkSetupCache = make(map[types.UID]int)
 	c.podInterfaceCache = make(map[string]*network.PodCacheInterface)
+	network.IstioAwareMasquerade = clusterConfig.IsIstioAwareMasqueradeEnabled
 
 	c.domainNotifyPipes = make(map[string]string)
+	c.ioErrorPauses = make(map[types.UID]*ioErrorPause)
+	c.watchdogEvents = make(map[types.UID]int)
 
-	c.kvmController = device_manager.NewDeviceController(c.host, maxDevices)
+	c.kvmController = device_manager.NewDeviceController(c.host, maxDevices, clusterConfig)
 
 	return c
 }
@@ -161,6 +176,7 @@ type VirtualMachineController struct {
 	vmiTargetInformer        cache.SharedIndexInformer
 	domainInformer           cache.SharedInformer
 	gracefulShutdownInformer cache.SharedIndexInformer
+	pvcInformer              cache.SharedIndexInformer
 	launcherClients          map[types.UID]*launcherClientInfo
 	launcherClientLock       sync.Mutex
 	heartBeatInterval        time.Duration
@@ -184,6 +200,17 @@ type VirtualMachineController struct {
 	podInterfaceCacheLock sync.Mutex
 
 	domainNotifyPipes map[string]string
+
+	// the guests which qemu paused because of an I/O error
+	ioErrorPauses     map[types.UID]*ioErrorPause
+	ioErrorPausesLock sync.Mutex
+
+	// the times the watchdog of the guests fired which were already counted, by VMI
+	watchdogEvents     map[types.UID]int
+	watchdogEventsLock sync.Mutex
+
+	// the records of the SR-IOV virtual functions changed for the allowed MAC addresses, by VMI
+	vfStateDir string
 }
 
 type virtLauncherCriticalNetworkError struct {
@@ -448,6 +475,46 @@ func domainMigrated(domain *api.Domain) bool {
 	return false
 }
 
+// updateIPClaims claims the addresses the interfaces with persistent IPs got from the IPAM, they
+// are requested again for the migration target pods and after restarts of the VirtualMachine
+// domainFailure returns the reason and message of the DomainFailure condition for the state of the domain,
+// or an empty reason if the state does not need attention
+func domainFailure(domain *api.Domain) (reason string, message string) {
+	if domain == nil {
+		return "", ""
+	}
+	switch {
+	case domain.Status.Status == api.Paused && domain.Status.Reason == api.ReasonPausedIOError:
+		return v1.VirtualMachineInstanceReasonPausedIOError, "The domain was paused because of an I/O error"
+	case domain.Status.Status == api.Crashed,
+		domain.Status.Status == api.Paused && domain.Status.Reason == api.ReasonPausedCrashed:
+		return v1.VirtualMachineInstanceReasonDomainCrashed, "The guest of the domain crashed"
+	}
+	return "", ""
+}
+
+func (d *VirtualMachineController) updateIPClaims(vmi *v1.VirtualMachineInstance) {
+	claimed := map[string]bool{}
+	for _, claim := range vmi.Status.IPClaims {
+		claimed[claim.Name] = true
+	}
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		if !iface.PersistentIPs || claimed[iface.Name] {
+			continue
+		}
+		// there is nothing to claim if the IPAM of the network is disabled
+		if _, err := os.Stat(fmt.Sprintf(virtutil.VMIInterfacepath, vmi.UID, iface.Name)); err != nil {
+			continue
+		}
+		podIface, err := d.getPodInterfacefromFileCache(vmi.UID, iface.Name)
+		if err != nil || len(podIface.PodIPNets) == 0 {
+			continue
+		}
+		vmi.Status.IPClaims = append(vmi.Status.IPClaims, v1.InterfaceIPClaim{Name: iface.Name, IPs: podIface.PodIPNets})
+		log.Log.Object(vmi).Infof("Claimed the addresses %v for interface %s", podIface.PodIPNets, iface.Name)
+	}
+}
+
 func (d *VirtualMachineController) getPodInterfacefromFileCache(uid types.UID, ifaceName string) (*network.PodCacheInterface, error) {
 	ifacepath := fmt.Sprintf(virtutil.VMIInterfacepath, uid, ifaceName)
 
@@ -502,6 +569,16 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 			vmi.Status.GuestOSInfo.KernelVersion = domain.Status.OSInfo.KernelVersion
 			vmi.Status.GuestOSInfo.ID = domain.Status.OSInfo.Id
 		}
+		if domain.Status.Hostname != "" {
+			vmi.Status.GuestHostname = domain.Status.Hostname
+		}
+		if domain.Spec.CPUTune != nil && len(domain.Spec.CPUTune.VCPUPin) > 0 {
+			vcpuPinning := make([]v1.VCPUPin, 0, len(domain.Spec.CPUTune.VCPUPin))
+			for _, pin := range domain.Spec.CPUTune.VCPUPin {
+				vcpuPinning = append(vcpuPinning, v1.VCPUPin{VCPU: uint32(pin.VCPU), CPUSet: pin.CPUSet})
+			}
+			vmi.Status.VCPUPinning = vcpuPinning
+		}
 		// This is needed to be backwards compatible with vmi's which have status interfaces
 		// with the name not being set
 		if len(domain.Spec.Devices.Interfaces) == 0 && len(vmi.Status.Interfaces) == 1 && vmi.Status.Interfaces[0].Name == "" {
@@ -598,6 +675,15 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 					}
 				}
 
+				// Report the VLAN the interface is tagged with, virt-controller made sure
+				// it matches the one of the OVN-Kubernetes localnet network
+				if network := existingNetworksByName[domainInterface.Alias.Name]; network.Multus != nil && network.Multus.OVN != nil && network.Multus.OVN.VLAN != nil {
+					newInterface.VLAN = *network.Multus.OVN.VLAN
+				}
+
+				// Report the additional addresses the guest is allowed to send from
+				newInterface.AllowedAddresses = existingInterfacesSpecByName[domainInterface.Alias.Name].AllowedAddresses.DeepCopy()
+
 				// Update IP info based on information from domain.Status.Interfaces (Qemu guest)
 				// Remove the interface from domainInterfaceStatusByMac to mark it as handled
 				if interfaceStatus, exists := domainInterfaceStatusByMac[interfaceMAC]; exists {
@@ -627,6 +713,8 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 			}
 			vmi.Status.Interfaces = newInterfaces
 		}
+
+		d.updateIPClaims(vmi)
 	}
 
 	// Update migration progress if domain reports anything in the migration metadata.
@@ -652,6 +740,39 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 		}
 	}
 
+	// Update the disk compaction results. The metadata only holds the latest run, so its
+	// reclaimed bytes are added to the total whenever a new run is detected.
+	if domain != nil && domain.Spec.Metadata.KubeVirt.DiskCompaction != nil {
+		compactionMetadata := domain.Spec.Metadata.KubeVirt.DiskCompaction
+		if vmi.Status.DiskCompaction == nil {
+			vmi.Status.DiskCompaction = &v1.DiskCompactionStatus{}
+		}
+		status := vmi.Status.DiskCompaction
+		if compactionMetadata.Timestamp != nil && !compactionMetadata.Timestamp.Equal(status.LastCompactionTimestamp) {
+			status.LastCompactionTimestamp = compactionMetadata.Timestamp
+			status.ReclaimedBytes = compactionMetadata.ReclaimedBytes
+			status.TotalReclaimedBytes += compactionMetadata.ReclaimedBytes
+			status.FailureReason = compactionMetadata.FailureReason
+		}
+	}
+
+	// Dump the memory of a guest which panicked to the crash dump claim of its panic device
+	d.startCrashDump(vmi, domain)
+
+	// Update the result of the memory dump once the dump to the current file ended
+	if dump := vmi.Status.MemoryDump; dump != nil && dump.Phase == v1.MemoryDumpInProgress && memoryDumpEnded(dump, domain) {
+		dumpMetadata := domain.Spec.Metadata.KubeVirt.MemoryDump
+		dump.StartTimestamp = dumpMetadata.StartTimestamp
+		dump.EndTimestamp = dumpMetadata.EndTimestamp
+		if dumpMetadata.Failed {
+			dump.Phase = v1.MemoryDumpFailed
+			dump.Message = dumpMetadata.FailureReason
+		} else {
+			dump.Phase = v1.MemoryDumpCompleted
+			dump.Message = ""
+		}
+	}
+
 	// handle migrations differently than normal status updates.
 	//
 	// When a successful migration is detected, we must transfer ownership of the VMI
@@ -703,10 +824,13 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 			// the target node has seen the domain event.
 			vmi.Labels[v1.NodeNameLabel] = migrationHost
 			vmi.Status.NodeName = migrationHost
+			// the VMI left the node it had to be evacuated from
+			vmi.Status.EvacuationNodeName = ""
 			vmi.Status.MigrationState.Completed = true
 			d.recorder.Event(vmi, k8sv1.EventTypeNormal, v1.Migrated.String(), fmt.Sprintf("The VirtualMachineInstance migrated to node %s.", migrationHost))
 		}
 
+		controller.SetVMIPhaseTransitionTimestamp(&oldStatus, &vmi.Status)
 		if !reflect.DeepEqual(oldStatus, vmi.Status) {
 			_, err = d.clientset.VirtualMachineInstance(vmi.ObjectMeta.Namespace).Update(vmi)
 			if err != nil {
@@ -781,8 +905,23 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 		}
 		vmi.Status.Conditions = append(vmi.Status.Conditions, agentCondition)
 	case !channelConnected:
+		// the agent was connected before, report that it is gone while the guest keeps running
+		if condManager.HasCondition(vmi, v1.VirtualMachineInstanceAgentConnected) && domain != nil && domain.Status.Status == api.Running &&
+			!condManager.HasCondition(vmi, v1.VirtualMachineInstanceAgentUnreachable) {
+			agentCondition := v1.VirtualMachineInstanceCondition{
+				Type:          v1.VirtualMachineInstanceAgentUnreachable,
+				LastProbeTime: v12.Now(),
+				Status:        k8sv1.ConditionTrue,
+				Reason:        v1.VirtualMachineInstanceReasonAgentDisconnected,
+				Message:       "The guest agent disconnected from the channel",
+			}
+			vmi.Status.Conditions = append(vmi.Status.Conditions, agentCondition)
+		}
 		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceAgentConnected)
 	}
+	if channelConnected || domain == nil || domain.Status.Status != api.Running {
+		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceAgentUnreachable)
+	}
 
 	if condManager.HasCondition(vmi, v1.VirtualMachineInstanceAgentConnected) {
 		client, err := d.getLauncherClient(vmi)
@@ -834,11 +973,126 @@ func (d *VirtualMachineController) updateVMIStatus(vmi *v1.VirtualMachineInstanc
 		condManager.RemoveCondition(vmi, v1.VirtualMachineInstancePaused)
 	}
 
+	// Mirror the domain states which need attention, like a storage outage, into a condition
+	if reason, message := domainFailure(domain); reason != "" {
+		condition := condManager.GetCondition(vmi, v1.VirtualMachineInstanceDomainFailure)
+		if condition == nil || condition.Reason != reason {
+			log.Log.Object(vmi).V(3).Infof("Adding domain failure condition with reason %s", reason)
+			condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceDomainFailure)
+			now := metav1.NewTime(time.Now())
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:               v1.VirtualMachineInstanceDomainFailure,
+				Status:             k8sv1.ConditionTrue,
+				LastProbeTime:      now,
+				LastTransitionTime: now,
+				Reason:             reason,
+				Message:            message,
+			})
+		}
+	} else if condManager.HasCondition(vmi, v1.VirtualMachineInstanceDomainFailure) {
+		log.Log.Object(vmi).V(3).Info("Removing domain failure condition")
+		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceDomainFailure)
+	}
+
+	// Keep the watchdog condition once the watchdog fired, the domain only knows about the recent events
+	if domain != nil && domain.Status.Watchdog.Count > 0 {
+		message := fmt.Sprintf("The watchdog of the guest expired %d time(s), the %s action was taken", domain.Status.Watchdog.Count, watchdogAction(vmi))
+		condition := condManager.GetCondition(vmi, v1.VirtualMachineInstanceWatchdogFired)
+		if condition == nil || condition.Message != message {
+			log.Log.Object(vmi).V(3).Info("Updating watchdog condition")
+			condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceWatchdogFired)
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:               v1.VirtualMachineInstanceWatchdogFired,
+				Status:             k8sv1.ConditionTrue,
+				LastProbeTime:      metav1.NewTime(time.Now()),
+				LastTransitionTime: domain.Status.Watchdog.LastTimestamp,
+				Reason:             v1.VirtualMachineInstanceReasonWatchdogExpired,
+				Message:            message,
+			})
+		}
+	}
+
+	// Keep the crashed condition once the guest panicked, the domain is gone soon after
+	if guestPanicked(domain) && !condManager.HasCondition(vmi, v1.VirtualMachineInstanceCrashed) {
+		log.Log.Object(vmi).V(3).Info("Adding crashed condition")
+		message := "The guest kernel panicked"
+		if claimName := crashDumpClaimName(vmi); claimName != "" {
+			message = fmt.Sprintf("%s, its memory is dumped to PersistentVolumeClaim %s", message, claimName)
+		}
+		now := metav1.NewTime(time.Now())
+		vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+			Type:               v1.VirtualMachineInstanceCrashed,
+			Status:             k8sv1.ConditionTrue,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+			Reason:             v1.VirtualMachineInstanceReasonGuestPanicked,
+			Message:            message,
+		})
+	}
+
+	// Update frozen condition in case the guest filesystems were frozen / thawed
+	if domain != nil && domain.Spec.Metadata.KubeVirt.FSFreeze != nil {
+		if !condManager.HasCondition(vmi, v1.VirtualMachineInstanceFrozen) {
+			log.Log.Object(vmi).V(3).Info("Adding frozen condition")
+			now := metav1.NewTime(time.Now())
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:               v1.VirtualMachineInstanceFrozen,
+				Status:             k8sv1.ConditionTrue,
+				LastProbeTime:      now,
+				LastTransitionTime: now,
+				Reason:             "FrozenByUser",
+				Message:            "The guest filesystems were frozen by user",
+			})
+		}
+	} else if condManager.HasCondition(vmi, v1.VirtualMachineInstanceFrozen) {
+		log.Log.Object(vmi).V(3).Info("Removing frozen condition")
+		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceFrozen)
+	}
+
+	// Update the SR-IOV failover condition while VFs are detached from the guest
+	if detached := detachedFailoverInterfaces(vmi, domain); len(detached) > 0 {
+		if !condManager.HasCondition(vmi, v1.VirtualMachineInstanceSRIOVFailover) {
+			log.Log.Object(vmi).V(3).Info("Adding SR-IOV failover condition")
+			now := metav1.NewTime(time.Now())
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:               v1.VirtualMachineInstanceSRIOVFailover,
+				Status:             k8sv1.ConditionTrue,
+				LastProbeTime:      now,
+				LastTransitionTime: now,
+				Reason:             v1.VirtualMachineInstanceReasonVFDetached,
+				Message:            fmt.Sprintf("the traffic of the SR-IOV interfaces %s runs through their failover standby interfaces", strings.Join(detached, ", ")),
+			})
+		}
+	} else if condManager.HasCondition(vmi, v1.VirtualMachineInstanceSRIOVFailover) {
+		log.Log.Object(vmi).V(3).Info("Removing SR-IOV failover condition")
+		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceSRIOVFailover)
+	}
+
+	// Update memory dump condition while a dump is pending or in progress
+	if dump := vmi.Status.MemoryDump; dump != nil && (dump.Phase == v1.MemoryDumpPending || dump.Phase == v1.MemoryDumpInProgress) {
+		if !condManager.HasCondition(vmi, v1.VirtualMachineInstanceMemoryDumpInProgress) {
+			log.Log.Object(vmi).V(3).Info("Adding memory dump condition")
+			now := metav1.NewTime(time.Now())
+			vmi.Status.Conditions = append(vmi.Status.Conditions, v1.VirtualMachineInstanceCondition{
+				Type:               v1.VirtualMachineInstanceMemoryDumpInProgress,
+				Status:             k8sv1.ConditionTrue,
+				LastProbeTime:      now,
+				LastTransitionTime: now,
+				Reason:             "MemoryDumpInProgress",
+				Message:            fmt.Sprintf("dumping the guest memory to PersistentVolumeClaim %s", dump.ClaimName),
+			})
+		}
+	} else if condManager.HasCondition(vmi, v1.VirtualMachineInstanceMemoryDumpInProgress) {
+		log.Log.Object(vmi).V(3).Info("Removing memory dump condition")
+		condManager.RemoveCondition(vmi, v1.VirtualMachineInstanceMemoryDumpInProgress)
+	}
+
 	if _, ok := syncError.(*virtLauncherCriticalNetworkError); ok {
 		log.Log.Errorf("virt-launcher crashed due to a network error. Updating VMI %s status to Failed", vmi.Name)
 		vmi.Status.Phase = v1.Failed
 	}
 	condManager.CheckFailure(vmi, syncError, "Synchronizing with the Domain failed.")
+	controller.SetVMIPhaseTransitionTimestamp(&oldStatus, &vmi.Status)
 
 	if !reflect.DeepEqual(oldStatus, vmi.Status) {
 		_, err = d.clientset.VirtualMachineInstance(vmi.ObjectMeta.Namespace).Update(vmi)
@@ -886,7 +1140,7 @@ func (c *VirtualMachineController) Run(threadiness int, stopCh chan struct{}) {
 	go c.vmiSourceInformer.Run(stopCh)
 	go c.vmiTargetInformer.Run(stopCh)
 	go c.gracefulShutdownInformer.Run(stopCh)
-	cache.WaitForCacheSync(stopCh, c.domainInformer.HasSynced, c.vmiSourceInformer.HasSynced, c.vmiTargetInformer.HasSynced, c.gracefulShutdownInformer.HasSynced)
+	cache.WaitForCacheSync(stopCh, c.domainInformer.HasSynced, c.vmiSourceInformer.HasSynced, c.vmiTargetInformer.HasSynced, c.gracefulShutdownInformer.HasSynced, c.pvcInformer.HasSynced)
 
 	go c.heartBeat(c.heartBeatInterval, stopCh)
 
@@ -1302,6 +1556,18 @@ func (d *VirtualMachineController) defaultExecute(key string,
 	case shouldUpdate:
 		log.Log.Object(vmi).V(3).Info("Processing vmi update")
 		syncErr = d.processVmUpdate(vmi)
+		if syncErr == nil {
+			syncErr = d.processMemoryDump(vmi, domain)
+		}
+		if syncErr == nil {
+			syncErr = d.processIOErrorPause(vmi, domain)
+		}
+		if syncErr == nil {
+			syncErr = d.processGuestWatchdog(vmi, domain)
+		}
+		if syncErr == nil {
+			syncErr = d.processAllowedMACs(vmi, domain)
+		}
 	default:
 		log.Log.Object(vmi).V(3).Info("No update processing required")
 	}
@@ -1436,6 +1702,12 @@ func (d *VirtualMachineController) processVmCleanup(vmi *v1.VirtualMachineInstan
 	d.migrationProxy.StopTargetListener(vmiId)
 	d.migrationProxy.StopSourceListener(vmiId)
 
+	// The claim of a memory dump is mounted below the container disks
+	err = d.containerDiskMounter.UnmountMemoryDump(vmi)
+	if err != nil {
+		return err
+	}
+
 	// Unmount container disks and clean up remaining files
 	err = d.containerDiskMounter.Unmount(vmi)
 	if err != nil {
@@ -1443,6 +1715,13 @@ func (d *VirtualMachineController) processVmCleanup(vmi *v1.VirtualMachineInstan
 	}
 
 	d.clearPodNetworkPhase1(vmi.UID)
+	d.forgetIOErrorPause(vmi.UID)
+	d.forgetGuestWatchdog(vmi.UID)
+
+	err = d.restoreAllowedMACs(vmi)
+	if err != nil {
+		return err
+	}
 
 	// Watch dog file and command client must be the last things removed here
 	err = d.closeLauncherClient(vmi)
@@ -1753,16 +2032,47 @@ func (d *VirtualMachineController) checkNetworkInterfacesForMigration(vmi *v1.Vi
 		if iface.Masquerade == nil && networks[iface.Name].Pod != nil {
 			return fmt.Errorf("cannot migrate VMI which does not use masquerade to connect to the pod network")
 		}
+		if iface.SRIOV != nil && iface.SRIOV.FailoverStandby == "" {
+			return fmt.Errorf("cannot migrate VMI with SR-IOV interface %s without a failover standby", iface.Name)
+		}
 	}
 	return nil
 }
 
+// detachedFailoverInterfaces returns the SR-IOV interfaces with a failover standby
+// whose VF is missing in the running domain, e.g. because it was detached for a migration
+func detachedFailoverInterfaces(vmi *v1.VirtualMachineInstance, domain *api.Domain) []string {
+	if domain == nil || domain.Status.Status != api.Running {
+		return nil
+	}
+	attached := map[string]bool{}
+	for _, iface := range domain.Spec.Devices.Interfaces {
+		if iface.Type == "hostdev" && iface.Alias != nil {
+			attached[iface.Alias.Name] = true
+		}
+	}
+	var detached []string
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		if iface.SRIOV != nil && iface.SRIOV.FailoverStandby != "" && !attached[iface.Name] {
+			detached = append(detached, iface.Name)
+		}
+	}
+	return detached
+}
+
 func (d *VirtualMachineController) checkVolumesForMigration(vmi *v1.VirtualMachineInstance) (blockMigrate bool, err error) {
 	// Check if all VMI volumes can be shared between the source and the destination
 	// of a live migration. blockMigrate will be returned as false, only if all volumes
 	// are shared and the VMI has no local disks
 	// Some combinations of disks makes the VMI no suitable for live migration.
 	// A relevant error will be returned in this case.
+	if vmi.Spec.Domain.Devices.TPM != nil {
+		// the TPM state is kept on the source node and is not transferred
+		return true, fmt.Errorf("cannot migrate VMI with a TPM device")
+	}
+	if virtutil.IsSEVVMI(vmi) && !d.clusterConfig.SEVLiveMigrationEnabled() {
+		return true, fmt.Errorf("cannot migrate VMI with SEV memory encryption")
+	}
 	for _, volume := range vmi.Spec.Volumes {
 		volSrc := volume.VolumeSource
 		if volSrc.PersistentVolumeClaim != nil || volSrc.DataVolume != nil {
@@ -1869,6 +2179,37 @@ func (d *VirtualMachineController) handleMigrationProxy(vmi *v1.VirtualMachineIn
 	return nil
 }
 
+// processMemoryDump mounts the claim of an in progress memory dump into the
+// launcher pod and starts the dump. The claim is unmounted once the dump ended.
+func (d *VirtualMachineController) processMemoryDump(vmi *v1.VirtualMachineInstance, domain *api.Domain) error {
+	dump := vmi.Status.MemoryDump
+	if dump == nil {
+		return nil
+	}
+	if dump.Phase != v1.MemoryDumpInProgress || memoryDumpEnded(dump, domain) {
+		return d.containerDiskMounter.UnmountMemoryDump(vmi)
+	}
+
+	if err := d.containerDiskMounter.MountMemoryDump(vmi, dump.AttachPodUID); err != nil {
+		return err
+	}
+	client, err := d.getLauncherClient(vmi)
+	if err != nil {
+		return err
+	}
+	// virt-launcher ignores the request if the dump to this file already started
+	return client.MemoryDumpVirtualMachine(vmi, dump.FileName)
+}
+
+// memoryDumpEnded checks if the domain finished the dump to the file of the status
+func memoryDumpEnded(dump *v1.VirtualMachineInstanceMemoryDumpStatus, domain *api.Domain) bool {
+	if domain == nil || domain.Spec.Metadata.KubeVirt.MemoryDump == nil {
+		return false
+	}
+	dumpMetadata := domain.Spec.Metadata.KubeVirt.MemoryDump
+	return dumpMetadata.FileName == dump.FileName && dumpMetadata.EndTimestamp != nil
+}
+
 func (d *VirtualMachineController) processVmUpdate(origVMI *v1.VirtualMachineInstance) error {
 	vmi := origVMI.DeepCopy()
 
@@ -1915,6 +2256,18 @@ func (d *VirtualMachineController) processVmUpdate(origVMI *v1.VirtualMachineIns
 
 			}
 
+			if virtutil.IsNonRootVMI(vmi) {
+				if err := d.prepareNonRootDevices(vmi); err != nil {
+					return fmt.Errorf("failed to prepare the devices of the migration target: %v", err)
+				}
+			}
+
+			if vmi.IsCPUDedicated() {
+				if err := d.checkCgroupOperations(vmi); err != nil {
+					return fmt.Errorf("failed to pin the vcpus of the migration target: %v", err)
+				}
+			}
+
 			if err := client.SyncMigrationTarget(vmi); err != nil {
 				return fmt.Errorf("syncing migration target failed: %v", err)
 
@@ -1971,6 +2324,36 @@ func (d *VirtualMachineController) processVmUpdate(origVMI *v1.VirtualMachineIns
 			if err != nil {
 				return fmt.Errorf("failed to adjust resources: %v", err)
 			}
+
+			if virtutil.IsNonRootVMI(vmi) {
+				if err := d.prepareNonRootDevices(vmi); err != nil {
+					return fmt.Errorf("failed to prepare the devices: %v", err)
+				}
+			}
+
+			if vmi.IsCPUDedicated() {
+				if err := d.checkCgroupOperations(vmi); err != nil {
+					return fmt.Errorf("failed to pin the vcpus: %v", err)
+				}
+			}
+
+			if vmi.IsCPUDedicated() && vmi.Spec.Domain.CPU.ThreadPolicy != nil {
+				if _, _, err := d.placeDedicatedCPUs(vmi); err != nil {
+					return fmt.Errorf("failed to place the vcpus on the host cores: %v", err)
+				}
+			}
+
+			if vmi.IsNUMAPassthrough() {
+				if err := d.checkNUMATopology(vmi); err != nil {
+					return fmt.Errorf("failed to place the vmi on the host NUMA topology: %v", err)
+				}
+			}
+
+			if vmi.IsRealtimeEnabled() {
+				if err := d.checkRealtimeKernel(); err != nil {
+					return err
+				}
+			}
 		}
 
 		smbios := d.clusterConfig.GetSMBIOS()
@@ -1997,6 +2380,160 @@ func (d *VirtualMachineController) processVmUpdate(origVMI *v1.VirtualMachineIns
 	return err
 }
 
+// checkRealtimeKernel verifies that the node-labeller found a realtime kernel on the node
+func (d *VirtualMachineController) checkRealtimeKernel() error {
+	node, err := d.clientset.CoreV1().Nodes().Get(d.host, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Labels[v1.RealtimeLabel] != "true" {
+		return fmt.Errorf("the realtime vCPUs require a realtime kernel, but node %s is missing the %s label", d.host, v1.RealtimeLabel)
+	}
+	return nil
+}
+
+// placeDedicatedCPUs splits the cpuset of the launcher pod between the vCPUs
+// and the emulator thread, the same way virt-launcher pins them
+func (d *VirtualMachineController) placeDedicatedCPUs(vmi *v1.VirtualMachineInstance) ([]int, *int, error) {
+	manager, err := d.podCgroupManager(vmi)
+	if err != nil {
+		return nil, nil, err
+	}
+	cpus, err := manager.GetCpuSet()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the pod cpuset: %v", err)
+	}
+	return hardware.DedicatedCPUs(hostCPUPath, vmi, cpus)
+}
+
+// podCgroupManager returns the manager of the cgroup of the launcher pod, as mounted in the pod
+func (d *VirtualMachineController) podCgroupManager(vmi *v1.VirtualMachineInstance) (cgroup.Manager, error) {
+	res, err := d.podIsolationDetector.Detect(vmi)
+	if err != nil {
+		return nil, err
+	}
+	return cgroup.NewManager(filepath.Join(res.MountRoot(), cgroup.Root))
+}
+
+// checkCgroupOperations verifies that the cgroup of the launcher pod supports pinning the
+// vCPUs, and reports the result with the cgroup readiness gate of the pod
+func (d *VirtualMachineController) checkCgroupOperations(vmi *v1.VirtualMachineInstance) error {
+	manager, err := d.podCgroupManager(vmi)
+	if err != nil {
+		return err
+	}
+	_, err = manager.GetCpuSet()
+	if err != nil && !cgroup.IsUnsupported(err) {
+		return fmt.Errorf("failed to get the pod cpuset: %v", err)
+	}
+	if gateErr := d.setCgroupReadinessGate(vmi, err); gateErr != nil {
+		return fmt.Errorf("failed to set the cgroup readiness gate of the pod: %v", gateErr)
+	}
+	return err
+}
+
+// setCgroupReadinessGate sets the cgroup readiness gate of the launcher pod on this node,
+// to false with the unsupported operation if there is one
+func (d *VirtualMachineController) setCgroupReadinessGate(vmi *v1.VirtualMachineInstance, unsupported error) error {
+	condition := k8sv1.PodCondition{
+		Type:   k8sv1.PodConditionType(v1.CgroupReadinessGate),
+		Status: k8sv1.ConditionTrue,
+	}
+	if unsupported != nil {
+		condition.Status = k8sv1.ConditionFalse
+		condition.Reason = "UnsupportedCgroupOperation"
+		condition.Message = unsupported.Error()
+	}
+
+	pods, err := d.clientset.CoreV1().Pods(vmi.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", v1.CreatedByLabel, string(vmi.UID)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != d.host || !hasReadinessGate(&pod, condition.Type) {
+			continue
+		}
+		if existing := podCondition(&pod, condition.Type); existing != nil && existing.Status == condition.Status && existing.Message == condition.Message {
+			continue
+		}
+		condition.LastTransitionTime = metav1.Now()
+		patch, err := json.Marshal(map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []k8sv1.PodCondition{condition},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := d.clientset.CoreV1().Pods(pod.Namespace).Patch(pod.Name, types.StrategicMergePatchType, patch, "status"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasReadinessGate(pod *k8sv1.Pod, conditionType k8sv1.PodConditionType) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+func podCondition(pod *k8sv1.Pod, conditionType k8sv1.PodConditionType) *k8sv1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// checkNUMATopology verifies that the dedicated pCPUs of the launcher pod
+// belong to NUMA nodes of the host and that these nodes have enough free
+// hugepages for the guest NUMA cells
+func (d *VirtualMachineController) checkNUMATopology(vmi *v1.VirtualMachineInstance) error {
+	cpus, _, err := d.placeDedicatedCPUs(vmi)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := hardware.LookupNUMATopology(hostNUMANodesPath)
+	if err != nil {
+		return err
+	}
+	if vmi.Spec.Domain.Memory == nil || vmi.Spec.Domain.Memory.Hugepages == nil {
+		return fmt.Errorf("NUMA passthrough requires hugepages")
+	}
+	pageSize, err := resource.ParseQuantity(vmi.Spec.Domain.Memory.Hugepages.PageSize)
+	if err != nil {
+		return err
+	}
+	// the pod requests hugepages for the guest memory if set, for the requested memory otherwise
+	memory := vmi.Spec.Domain.Resources.Requests.Memory().Value()
+	if vmi.Spec.Domain.Memory.Guest != nil {
+		memory = vmi.Spec.Domain.Memory.Guest.Value()
+	}
+	cells, err := hardware.GetNUMACells(nodes, cpus, memory, pageSize.Value())
+	if err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		free, err := hardware.FreeHugepages(hostNUMANodesPath, cell.HostNode, pageSize.Value())
+		if err != nil {
+			return fmt.Errorf("failed to read the free hugepages of NUMA node %d: %v", cell.HostNode, err)
+		}
+		if needed := cell.Memory / pageSize.Value(); free < needed {
+			return fmt.Errorf("NUMA node %d has %d free hugepages of %s, %d are needed", cell.HostNode, free, vmi.Spec.Domain.Memory.Hugepages.PageSize, needed)
+		}
+	}
+	return nil
+}
+
 func (d *VirtualMachineController) setVmPhaseForStatusReason(domain *api.Domain, vmi *v1.VirtualMachineInstance) error {
 	phase, err := d.calculateVmPhaseForStatusReason(domain, vmi)
 	if err != nil {
@@ -2035,12 +2572,16 @@ func (d *VirtualMachineController) calculateVmPhaseForStatusReason(domain *api.D
 		case api.Shutoff, api.Crashed:
 			switch domain.Status.Reason {
 			case api.ReasonCrashed, api.ReasonPanicked:
+				// the guest is kept until its memory was dumped
+				if capturingCrash(vmi, domain) {
+					return v1.Running, nil
+				}
 				return v1.Failed, nil
 			case api.ReasonDestroyed:
 				// When ACPI is available, the domain was tried to be shutdown,
 				// and destroyed means that the domain was destroyed after the graceperiod expired.
 				// Without ACPI a destroyed domain is ok.
-				if isACPIEnabled(vmi, domain) {
+				if isACPIEnabled(vmi, domain) || stoppedByGuestWatchdog(vmi, domain) {
 					return v1.Failed, nil
 				}
 				return v1.Succeeded, nil
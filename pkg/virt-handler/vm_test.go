@@ -58,6 +58,8 @@ import (
 	"kubevirt.io/client-go/precond"
 	diskutils "kubevirt.io/kubevirt/pkg/ephemeral-disk-utils"
 	"kubevirt.io/kubevirt/pkg/testutils"
+	"kubevirt.io/kubevirt/pkg/util/cgroup"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	virtcache "kubevirt.io/kubevirt/pkg/virt-handler/cache"
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
 	"kubevirt.io/kubevirt/pkg/virt-handler/isolation"
@@ -79,6 +81,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 	var domainSource *framework.FakeControllerSource
 	var domainInformer cache.SharedIndexInformer
 	var gracefulShutdownInformer cache.SharedIndexInformer
+	var pvcInformer cache.SharedIndexInformer
 	var mockQueue *testutils.MockWorkQueue
 	var mockWatchdog *MockWatchdog
 	var mockGracefulShutdown *MockGracefulShutdown
@@ -151,6 +154,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 		vmiTargetInformer, _ = testutils.NewFakeInformerFor(&v1.VirtualMachineInstance{})
 		domainInformer, domainSource = testutils.NewFakeInformerFor(&api.Domain{})
 		gracefulShutdownInformer, _ = testutils.NewFakeInformerFor(&api.Domain{})
+		pvcInformer, _ = testutils.NewFakeInformerFor(&k8sv1.PersistentVolumeClaim{})
 		recorder = record.NewFakeRecorder(100)
 
 		ctrl = gomock.NewController(GinkgoT())
@@ -180,6 +184,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 			vmiTargetInformer,
 			domainInformer,
 			gracefulShutdownInformer,
+			pvcInformer,
 			1,
 			10,
 			config,
@@ -656,7 +661,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 			controller.Execute()
 		})
 
-		It("should remove guest agent condition when there is no channel connected", func() {
+		It("should replace the guest agent condition with an unreachable condition when the channel disconnects", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
 			vmi.UID = vmiTestUUID
 			vmi.ObjectMeta.ResourceVersion = "1"
@@ -694,6 +699,10 @@ var _ = Describe("VirtualMachineInstance", func() {
 					Type:   v1.VirtualMachineInstanceIsMigratable,
 					Status: k8sv1.ConditionTrue,
 				},
+				{
+					Type:   v1.VirtualMachineInstanceAgentUnreachable,
+					Status: k8sv1.ConditionTrue,
+				},
 			}
 
 			vmiFeeder.Add(vmi)
@@ -709,6 +718,89 @@ var _ = Describe("VirtualMachineInstance", func() {
 			controller.Execute()
 		})
 
+		It("should remove the unreachable condition and report the hostname when the guest agent reconnects", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+				{
+					Type:   v1.VirtualMachineInstanceIsMigratable,
+					Status: k8sv1.ConditionTrue,
+				},
+				{
+					Type:   v1.VirtualMachineInstanceAgentUnreachable,
+					Status: k8sv1.ConditionTrue,
+					Reason: v1.VirtualMachineInstanceReasonAgentDisconnected,
+				},
+			}
+			vmi = addActivePods(vmi, podTestUUID, host)
+
+			mockWatchdog.CreateFile(vmi)
+
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			domain.Status.Hostname = "testvmi.example.com"
+			domain.Spec.Devices.Channels = []api.Channel{
+				{
+					Type: "unix",
+					Target: &api.ChannelTarget{
+						Name:  "org.qemu.guest_agent.0",
+						State: "connected",
+					},
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+			client.EXPECT().GetGuestInfo().Return(&v1.VirtualMachineInstanceGuestAgentInfo{}, nil)
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+				Expect(vmi.Status.GuestHostname).To(Equal("testvmi.example.com"))
+				conditionTypes := []v1.VirtualMachineInstanceConditionType{}
+				for _, condition := range vmi.Status.Conditions {
+					conditionTypes = append(conditionTypes, condition.Type)
+				}
+				Expect(conditionTypes).To(ContainElement(v1.VirtualMachineInstanceAgentConnected))
+				Expect(conditionTypes).ToNot(ContainElement(v1.VirtualMachineInstanceAgentUnreachable))
+			})
+
+			controller.Execute()
+		})
+
+		It("should report the pinning of the vCPUs", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi = addActivePods(vmi, podTestUUID, host)
+
+			mockWatchdog.CreateFile(vmi)
+
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			domain.Spec.CPUTune = &api.CPUTune{
+				VCPUPin: []api.CPUTuneVCPUPin{
+					{VCPU: 0, CPUSet: "2"},
+					{VCPU: 1, CPUSet: "6"},
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+				Expect(vmi.Status.VCPUPinning).To(Equal([]v1.VCPUPin{
+					{VCPU: 0, CPUSet: "2"},
+					{VCPU: 1, CPUSet: "6"},
+				}))
+			})
+
+			controller.Execute()
+		})
+
 		It("should add and remove paused condition", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
 			vmi.UID = vmiTestUUID
@@ -765,6 +857,448 @@ var _ = Describe("VirtualMachineInstance", func() {
 			controller.Execute()
 		})
 
+		It("should add and remove the domain failure condition on an I/O error", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi = addActivePods(vmi, podTestUUID, host)
+
+			mockWatchdog.CreateFile(vmi)
+
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+
+			By("pausing the domain because of an I/O error")
+			domain.Status.Status = api.Paused
+			domain.Status.Reason = api.ReasonPausedIOError
+
+			updatedVMI := vmi.DeepCopy()
+			updatedVMI.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+				{
+					Type:   v1.VirtualMachineInstanceIsMigratable,
+					Status: k8sv1.ConditionTrue,
+				},
+				{
+					Type:   v1.VirtualMachineInstanceDomainFailure,
+					Status: k8sv1.ConditionTrue,
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+			vmiInterface.EXPECT().Update(NewVMICondMatcher(*updatedVMI))
+
+			controller.Execute()
+
+			By("resuming the domain")
+			domain.Status.Status = api.Running
+			domain.Status.Reason = ""
+
+			updatedVMI = vmi.DeepCopy()
+			updatedVMI.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+				{
+					Type:   v1.VirtualMachineInstanceIsMigratable,
+					Status: k8sv1.ConditionTrue,
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+			vmiInterface.EXPECT().Update(NewVMICondMatcher(*updatedVMI))
+
+			controller.Execute()
+		})
+
+		Context("with a guest paused because of an I/O error", func() {
+			var vmi *v1.VirtualMachineInstance
+			var domain *api.Domain
+
+			BeforeEach(func() {
+				vmi = v1.NewMinimalVMI("testvmi")
+				vmi.UID = vmiTestUUID
+				vmi.ObjectMeta.ResourceVersion = "1"
+				vmi.Status.Phase = v1.Running
+				vmi.Spec.Volumes = []v1.Volume{
+					{
+						Name: "disk0",
+						VolumeSource: v1.VolumeSource{
+							DataVolume: &v1.DataVolumeSource{Name: "testdv"},
+						},
+					},
+				}
+				vmi = addActivePods(vmi, podTestUUID, host)
+				mockWatchdog.CreateFile(vmi)
+
+				domain = api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+				domain.Status.Status = api.Paused
+				domain.Status.Reason = api.ReasonPausedIOError
+
+				pvc := &k8sv1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Namespace: vmi.Namespace, Name: "testdv"},
+					Status: k8sv1.PersistentVolumeClaimStatus{
+						Capacity: k8sv1.ResourceList{k8sv1.ResourceStorage: resource.MustParse("2Gi")},
+					},
+				}
+				pvcInformer.GetStore().Add(pvc)
+				client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+				vmiInterface.EXPECT().Update(gomock.Any()).AnyTimes()
+			})
+
+			receivedEvents := func() []string {
+				events := []string{}
+				for {
+					select {
+					case event := <-recorder.(*record.FakeRecorder).Events:
+						events = append(events, event)
+					default:
+						return events
+					}
+				}
+			}
+
+			It("should report the pause without resuming the guest if the VMI does not ask for it", func() {
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				controller.Execute()
+
+				Expect(controller.ioErrorPauses).To(HaveKey(vmi.UID))
+				Expect(receivedEvents()).To(ContainElement(ContainSubstring(PausedIOErrorReason)))
+			})
+
+			It("should resume the guest once its PersistentVolumeClaim was expanded", func() {
+				_true := true
+				vmi.Spec.IOErrorRecovery = &v1.IOErrorRecovery{AutoResume: &_true, RetryIntervalSeconds: 3600}
+				controller.ioErrorPauses[vmi.UID] = &ioErrorPause{
+					lastAttempt: time.Now(),
+					capacities:  map[string]resource.Quantity{"testdv": resource.MustParse("1Gi")},
+				}
+
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				client.EXPECT().UnpauseVirtualMachine(gomock.Any()).Return(nil)
+
+				controller.Execute()
+
+				Expect(receivedEvents()).To(ContainElement(ContainSubstring(ResumedAfterIOErrorReason)))
+				Expect(controller.ioErrorPauses[vmi.UID].capacities).To(HaveKeyWithValue("testdv", resource.MustParse("2Gi")))
+			})
+
+			It("should wait for the retry interval if no PersistentVolumeClaim was expanded", func() {
+				_true := true
+				vmi.Spec.IOErrorRecovery = &v1.IOErrorRecovery{AutoResume: &_true, RetryIntervalSeconds: 3600}
+				controller.ioErrorPauses[vmi.UID] = &ioErrorPause{
+					lastAttempt: time.Now(),
+					capacities:  map[string]resource.Quantity{"testdv": resource.MustParse("2Gi")},
+				}
+
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				controller.Execute()
+
+				Expect(receivedEvents()).ToNot(ContainElement(ContainSubstring(ResumedAfterIOErrorReason)))
+			})
+		})
+
+		Context("with a guest watchdog", func() {
+			var vmi *v1.VirtualMachineInstance
+			var domain *api.Domain
+
+			BeforeEach(func() {
+				vmi = v1.NewMinimalVMI("testvmi")
+				vmi.UID = vmiTestUUID
+				vmi.ObjectMeta.ResourceVersion = "1"
+				vmi.Status.Phase = v1.Running
+				vmi.Spec.Domain.Devices.Watchdog = &v1.Watchdog{
+					Name:           "watchdog",
+					WatchdogDevice: v1.WatchdogDevice{I6300ESB: &v1.I6300ESBWatchdog{}},
+				}
+				vmi = addActivePods(vmi, podTestUUID, host)
+				mockWatchdog.CreateFile(vmi)
+
+				domain = api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+				domain.Status.Status = api.Running
+				domain.Status.Watchdog = api.WatchdogEvents{Count: 2, LastTimestamp: metav1.Now()}
+			})
+
+			receivedEvents := func() []string {
+				events := []string{}
+				for {
+					select {
+					case event := <-recorder.(*record.FakeRecorder).Events:
+						events = append(events, event)
+					default:
+						return events
+					}
+				}
+			}
+
+			It("should count the times the watchdog fired and report them in a condition", func() {
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+				vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+					var watchdogCondition *v1.VirtualMachineInstanceCondition
+					for i, condition := range vmi.Status.Conditions {
+						if condition.Type == v1.VirtualMachineInstanceWatchdogFired {
+							watchdogCondition = &vmi.Status.Conditions[i]
+						}
+					}
+					Expect(watchdogCondition).ToNot(BeNil())
+					Expect(watchdogCondition.Status).To(Equal(k8sv1.ConditionTrue))
+					Expect(watchdogCondition.Reason).To(Equal(v1.VirtualMachineInstanceReasonWatchdogExpired))
+					Expect(watchdogCondition.Message).To(Equal("The watchdog of the guest expired 2 time(s), the reset action was taken"))
+				})
+
+				controller.Execute()
+
+				Expect(controller.watchdogEvents).To(HaveKeyWithValue(vmi.UID, 2))
+				Expect(receivedEvents()).To(ContainElement(ContainSubstring(WatchdogFiredReason)))
+			})
+
+			It("should not count the same events again", func() {
+				controller.watchdogEvents[vmi.UID] = 2
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+				vmiInterface.EXPECT().Update(gomock.Any())
+
+				controller.Execute()
+
+				Expect(receivedEvents()).ToNot(ContainElement(ContainSubstring(WatchdogFiredReason)))
+			})
+
+			It("should stop a guest paused by its watchdog with the restart-pod action", func() {
+				vmi.Spec.Domain.Devices.Watchdog.I6300ESB.Action = v1.WatchdogActionRestartPod
+				domain.Status.Status = api.Paused
+				domain.Status.Reason = api.ReasonPausedWatchdog
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+				client.EXPECT().KillVirtualMachine(vmi).Return(nil)
+				vmiInterface.EXPECT().Update(gomock.Any())
+
+				controller.Execute()
+
+				Expect(receivedEvents()).To(ContainElement(ContainSubstring(StoppedAfterWatchdogReason)))
+			})
+
+			table.DescribeTable("should fail a VMI stopped by its watchdog", func(action v1.WatchdogAction, expectedPhase v1.VirtualMachineInstancePhase) {
+				vmi.Spec.Domain.Devices.Watchdog.I6300ESB.Action = action
+				domain.Status.Status = api.Shutoff
+				domain.Status.Reason = api.ReasonDestroyed
+
+				phase, err := controller.calculateVmPhaseForStatusReason(domain, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(phase).To(Equal(expectedPhase))
+			},
+				table.Entry("with the restart-pod action", v1.WatchdogActionRestartPod, v1.Failed),
+				table.Entry("with the poweroff action", v1.WatchdogActionPoweroff, v1.Succeeded),
+			)
+		})
+
+		Context("with a panic device", func() {
+			var vmi *v1.VirtualMachineInstance
+			var domain *api.Domain
+
+			BeforeEach(func() {
+				vmi = v1.NewMinimalVMI("testvmi")
+				vmi.UID = vmiTestUUID
+				vmi.ObjectMeta.ResourceVersion = "1"
+				vmi.Status.Phase = v1.Running
+				vmi.Spec.Domain.Devices.Panic = &v1.PanicDevice{CrashDumpClaimName: "crash-dumps"}
+				vmi = addActivePods(vmi, podTestUUID, host)
+				mockWatchdog.CreateFile(vmi)
+
+				domain = api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+				domain.Status.Status = api.Crashed
+				domain.Status.Reason = api.ReasonPanicked
+			})
+
+			crashedCondition := func(vmi *v1.VirtualMachineInstance) *v1.VirtualMachineInstanceCondition {
+				for i, condition := range vmi.Status.Conditions {
+					if condition.Type == v1.VirtualMachineInstanceCrashed {
+						return &vmi.Status.Conditions[i]
+					}
+				}
+				return nil
+			}
+
+			It("should dump the memory of a guest which panicked to the crash dump claim", func() {
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+				vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+					Expect(vmi.Status.Phase).To(Equal(v1.Running))
+					Expect(vmi.Status.MemoryDump).To(Equal(&v1.VirtualMachineInstanceMemoryDumpStatus{
+						ClaimName: "crash-dumps",
+						Phase:     v1.MemoryDumpPending,
+					}))
+					condition := crashedCondition(vmi)
+					Expect(condition).ToNot(BeNil())
+					Expect(condition.Reason).To(Equal(v1.VirtualMachineInstanceReasonGuestPanicked))
+					Expect(condition.Message).To(Equal("The guest kernel panicked, its memory is dumped to PersistentVolumeClaim crash-dumps"))
+				})
+
+				controller.Execute()
+
+				testutils.ExpectEvent(recorder.(*record.FakeRecorder), GuestPanickedReason)
+			})
+
+			It("should fail the VMI of a guest which panicked without a crash dump claim", func() {
+				vmi.Spec.Domain.Devices.Panic.CrashDumpClaimName = ""
+				vmiFeeder.Add(vmi)
+				domainFeeder.Add(domain)
+
+				vmiInterface.EXPECT().Update(gomock.Any()).Do(func(vmi *v1.VirtualMachineInstance) {
+					Expect(vmi.Status.Phase).To(Equal(v1.Failed))
+					Expect(vmi.Status.MemoryDump).To(BeNil())
+					condition := crashedCondition(vmi)
+					Expect(condition).ToNot(BeNil())
+					Expect(condition.Message).To(Equal("The guest kernel panicked"))
+				})
+
+				controller.Execute()
+			})
+
+			table.DescribeTable("should keep the VMI running while the crash dump is taken", func(dumpPhase v1.MemoryDumpPhase, expectedPhase v1.VirtualMachineInstancePhase) {
+				vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+					{Type: v1.VirtualMachineInstanceCrashed, Status: k8sv1.ConditionTrue},
+				}
+				vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{ClaimName: "crash-dumps", Phase: dumpPhase}
+
+				phase, err := controller.calculateVmPhaseForStatusReason(domain, vmi)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(phase).To(Equal(expectedPhase))
+			},
+				table.Entry("while the dump is pending", v1.MemoryDumpPending, v1.Running),
+				table.Entry("while the dump is in progress", v1.MemoryDumpInProgress, v1.Running),
+				table.Entry("until the dump completed", v1.MemoryDumpCompleted, v1.Failed),
+				table.Entry("until the dump failed", v1.MemoryDumpFailed, v1.Failed),
+			)
+		})
+
+		Context("with allowed MAC addresses on an SR-IOV interface", func() {
+			var vmi *v1.VirtualMachineInstance
+			var domain *api.Domain
+			var vfs *fakeVFHandler
+			var originalVFSpoofCheck vfSpoofCheckHandler
+
+			BeforeEach(func() {
+				vmi = v1.NewMinimalVMI("testvmi")
+				vmi.UID = vmiTestUUID
+				vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+					{
+						Name:                   "vrrp",
+						InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}},
+						AllowedAddresses:       &v1.AllowedAddresses{MACAddresses: []string{"00:00:5e:00:01:0a"}},
+					},
+					{
+						Name:                   "sriov",
+						InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{}},
+					},
+				}
+
+				domain = api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+				domain.Spec.Devices.HostDevices = []api.HostDevice{
+					{
+						Type:   "pci",
+						Source: api.HostDeviceSource{Address: &api.Address{Domain: "0x0000", Bus: "0x81", Slot: "0x11", Function: "0x1"}},
+						Alias:  &api.Alias{Name: "vrrp"},
+					},
+					{
+						Type:   "pci",
+						Source: api.HostDeviceSource{Address: &api.Address{Domain: "0x0000", Bus: "0x81", Slot: "0x11", Function: "0x2"}},
+						Alias:  &api.Alias{Name: "sriov"},
+					},
+				}
+
+				vfs = &fakeVFHandler{}
+				originalVFSpoofCheck = vfSpoofCheck
+				vfSpoofCheck = vfs
+			})
+
+			AfterEach(func() {
+				vfSpoofCheck = originalVFSpoofCheck
+			})
+
+			It("should trust the virtual function of the interface once", func() {
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+				Expect(vfs.allowed).To(Equal([]string{"0000:81:11.1"}))
+			})
+
+			It("should restore the virtual function once the VMI is cleaned up", func() {
+				vfs.spoofCheck = true
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+
+				Expect(controller.restoreAllowedMACs(vmi)).To(Succeed())
+				Expect(vfs.restored).To(Equal([]vfConfig{{PCIAddress: "0000:81:11.1", SpoofCheck: true, Trusted: true}}))
+				Expect(controller.vfRecordFile(vmi)).ToNot(BeAnExistingFile())
+
+				Expect(controller.restoreAllowedMACs(vmi)).To(Succeed())
+				Expect(vfs.restored).To(HaveLen(1))
+			})
+
+			It("should restore the virtual function after virt-handler restarted", func() {
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+
+				restarted := &VirtualMachineController{vfStateDir: controller.vfStateDir}
+				Expect(restarted.restoreAllowedMACs(vmi)).To(Succeed())
+				Expect(vfs.restored).To(Equal([]vfConfig{{PCIAddress: "0000:81:11.1", Trusted: true}}))
+			})
+
+			It("should find the virtual function of a failover interface", func() {
+				vmi.Spec.Domain.Devices.Interfaces[0].SRIOV.FailoverStandby = "default"
+				domain.Spec.Devices.HostDevices = domain.Spec.Devices.HostDevices[1:]
+				domain.Spec.Devices.Interfaces = []api.Interface{
+					{
+						Type:   "hostdev",
+						Source: api.InterfaceSource{Address: &api.Address{Domain: "0x0000", Bus: "0x81", Slot: "0x11", Function: "0x3"}},
+						Alias:  &api.Alias{Name: "vrrp"},
+					},
+				}
+
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+				Expect(vfs.allowed).To(Equal([]string{"0000:81:11.3"}))
+			})
+
+			It("should fail the sync if the virtual function could not be changed and retry it", func() {
+				vfs.err = fmt.Errorf("operation not supported")
+
+				err := controller.processAllowedMACs(vmi, domain)
+				Expect(err).To(MatchError("failed to allow the additional MAC addresses of interface vrrp: operation not supported"))
+
+				vfs.err = nil
+				Expect(controller.processAllowedMACs(vmi, domain)).To(Succeed())
+				Expect(vfs.allowed).To(Equal([]string{"0000:81:11.1"}))
+			})
+		})
+
+		table.DescribeTable("should mirror the domain state into the domain failure reason", func(status api.LifeCycle, reason api.StateChangeReason, expectedReason string) {
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = status
+			domain.Status.Reason = reason
+			failureReason, _ := domainFailure(domain)
+			Expect(failureReason).To(Equal(expectedReason))
+		},
+			table.Entry("with a domain paused by an I/O error", api.Paused, api.ReasonPausedIOError, v1.VirtualMachineInstanceReasonPausedIOError),
+			table.Entry("with a domain paused by a crash", api.Paused, api.ReasonPausedCrashed, v1.VirtualMachineInstanceReasonDomainCrashed),
+			table.Entry("with a crashed domain", api.Crashed, api.ReasonPanicked, v1.VirtualMachineInstanceReasonDomainCrashed),
+			table.Entry("with a domain paused by the user", api.Paused, api.ReasonPausedUser, ""),
+			table.Entry("with a running domain", api.Running, api.ReasonUnknown, ""),
+		)
+
 		It("should move VirtualMachineInstance from Scheduled to Failed if watchdog file is missing", func() {
 			cmdclient.MarkSocketUnresponsive(sockFile)
 			vmi := v1.NewMinimalVMI("testvmi")
@@ -1018,6 +1552,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 			vmi.Status.Phase = v1.Running
 			vmi.Labels = make(map[string]string)
 			vmi.Status.NodeName = host
+			vmi.Status.EvacuationNodeName = host
 			vmi.Labels[v1.MigrationTargetNodeNameLabel] = "othernode"
 			vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{
 				TargetNode:               "othernode",
@@ -1048,6 +1583,7 @@ var _ = Describe("VirtualMachineInstance", func() {
 			vmiUpdated.Status.MigrationState.StartTimestamp = &now
 			vmiUpdated.Status.MigrationState.EndTimestamp = &now
 			vmiUpdated.Status.NodeName = "othernode"
+			vmiUpdated.Status.EvacuationNodeName = ""
 			vmiUpdated.Labels[v1.NodeNameLabel] = "othernode"
 			vmiUpdated.Status.Interfaces = make([]v1.VirtualMachineInstanceNetworkInterface, 0)
 			vmiInterface.EXPECT().Update(vmiUpdated)
@@ -1411,6 +1947,22 @@ var _ = Describe("VirtualMachineInstance", func() {
 			Expect(blockMigrate).To(BeTrue())
 			Expect(err).To(Equal(fmt.Errorf("cannot migrate VMI with non-shared HostDisk")))
 		})
+		It("should not be allowed to live-migrate a VMI with a TPM device", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.Devices.TPM = &v1.TPMDevice{}
+
+			blockMigrate, err := controller.checkVolumesForMigration(vmi)
+			Expect(blockMigrate).To(BeTrue())
+			Expect(err).To(Equal(fmt.Errorf("cannot migrate VMI with a TPM device")))
+		})
+		It("should not be allowed to live-migrate a VMI with SEV memory encryption", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.LaunchSecurity = &v1.LaunchSecurity{SEV: &v1.SEV{}}
+
+			blockMigrate, err := controller.checkVolumesForMigration(vmi)
+			Expect(blockMigrate).To(BeTrue())
+			Expect(err).To(Equal(fmt.Errorf("cannot migrate VMI with SEV memory encryption")))
+		})
 
 		Context("with network configuration", func() {
 			It("should block migration for bridge binding assigned to the pod network", func() {
@@ -1481,9 +2033,173 @@ var _ = Describe("VirtualMachineInstance", func() {
 				err := controller.checkNetworkInterfacesForMigration(vmi)
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			table.DescribeTable("should allow migrating SR-IOV interfaces only with a failover standby", func(standby string, migratable bool) {
+				vmi := v1.NewMinimalVMI("testvmi")
+				vmi.Spec.Networks = []v1.Network{
+					{Name: "sriov", NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "sriov-net"}}},
+				}
+				vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+					{Name: "sriov", InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{FailoverStandby: standby}}},
+				}
+
+				err := controller.checkNetworkInterfacesForMigration(vmi)
+				if migratable {
+					Expect(err).ToNot(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+				table.Entry("with a failover standby", "standby", true),
+				table.Entry("without a failover standby", "", false),
+			)
 		})
 
 	})
+	Context("with NUMA passthrough", func() {
+		var nodesPath string
+		var vmi *v1.VirtualMachineInstance
+
+		addNode := func(id int, cpuList string, freeHugepages int) {
+			dir := filepath.Join(nodesPath, fmt.Sprintf("node%d", id), "hugepages", "hugepages-2048kB")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(nodesPath, fmt.Sprintf("node%d", id), "cpulist"), []byte(cpuList), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "free_hugepages"), []byte(fmt.Sprintf("%d", freeHugepages)), 0644)).To(Succeed())
+		}
+
+		setPodCPUSet := func(cpuList string) {
+			path := filepath.Join(vmiShareDir, hardware.CPUSET_PATH)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte(cpuList+"\n"), 0644)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			var err error
+			nodesPath, err = ioutil.TempDir("", "node")
+			Expect(err).ToNot(HaveOccurred())
+			hostNUMANodesPath = nodesPath
+
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.Spec.Domain.CPU = &v1.CPU{
+				Cores:                 2,
+				DedicatedCPUPlacement: true,
+				NUMA:                  &v1.NUMA{GuestMappingPassthrough: &v1.NUMAGuestMappingPassthrough{}},
+			}
+			vmi.Spec.Domain.Memory = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "2Mi"}}
+			vmi.Spec.Domain.Resources.Requests = k8sv1.ResourceList{
+				k8sv1.ResourceMemory: resource.MustParse("8Mi"),
+			}
+		})
+
+		AfterEach(func() {
+			hostNUMANodesPath = hardware.NUMANodesPath
+			os.RemoveAll(nodesPath)
+		})
+
+		It("should accept a placement with enough free hugepages", func() {
+			addNode(0, "0-3", 2)
+			addNode(1, "4-7", 2)
+			setPodCPUSet("3-4")
+
+			Expect(controller.checkNUMATopology(vmi)).To(Succeed())
+		})
+
+		It("should reject a host NUMA node without enough free hugepages", func() {
+			addNode(0, "0-3", 2)
+			addNode(1, "4-7", 1)
+			setPodCPUSet("3-4")
+
+			Expect(controller.checkNUMATopology(vmi)).To(MatchError("NUMA node 1 has 1 free hugepages of 2Mi, 2 are needed"))
+		})
+
+		It("should reject pCPUs outside of the host NUMA nodes", func() {
+			addNode(0, "0-3", 4)
+			setPodCPUSet("3-4")
+
+			Expect(controller.checkNUMATopology(vmi)).To(MatchError("CPU 4 does not belong to any NUMA node"))
+		})
+
+		It("should not place the isolated emulator thread pCPU", func() {
+			addNode(0, "0-3", 4)
+			setPodCPUSet("2-4")
+			vmi.Spec.Domain.CPU.IsolateEmulatorThread = true
+
+			Expect(controller.checkNUMATopology(vmi)).To(Succeed())
+		})
+	})
+
+	Context("with realtime vCPUs", func() {
+		addNode := func(labels map[string]string) {
+			node := &k8sv1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: host, Labels: labels},
+			}
+			virtClient.EXPECT().CoreV1().Return(fake.NewSimpleClientset(node).CoreV1()).AnyTimes()
+		}
+
+		It("should accept a node with a realtime kernel", func() {
+			addNode(map[string]string{v1.RealtimeLabel: "true"})
+			Expect(controller.checkRealtimeKernel()).To(Succeed())
+		})
+
+		It("should reject a node without a realtime kernel", func() {
+			addNode(nil)
+			Expect(controller.checkRealtimeKernel()).To(MatchError(ContainSubstring("is missing the kubevirt.io/realtime label")))
+		})
+	})
+
+	Context("with the cgroup readiness gate", func() {
+		var vmi *v1.VirtualMachineInstance
+		var kubeClient *fake.Clientset
+
+		newPod := func(name string, nodeName string, gates ...string) *k8sv1.Pod {
+			pod := &k8sv1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: vmi.Namespace,
+					Labels:    map[string]string{v1.CreatedByLabel: string(vmi.UID)},
+				},
+				Spec: k8sv1.PodSpec{NodeName: nodeName},
+			}
+			for _, gate := range gates {
+				pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, k8sv1.PodReadinessGate{ConditionType: k8sv1.PodConditionType(gate)})
+			}
+			return pod
+		}
+
+		gateCondition := func(name string) *k8sv1.PodCondition {
+			pod, err := kubeClient.CoreV1().Pods(vmi.Namespace).Get(name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			return podCondition(pod, k8sv1.PodConditionType(v1.CgroupReadinessGate))
+		}
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			kubeClient = fake.NewSimpleClientset(
+				newPod("launcher", host, v1.CgroupReadinessGate),
+				newPod("target", "othernode", v1.CgroupReadinessGate),
+				newPod("ungated", host),
+			)
+			virtClient.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+		})
+
+		It("should set the readiness gate of the launcher pod on the node", func() {
+			Expect(controller.setCgroupReadinessGate(vmi, nil)).To(Succeed())
+			Expect(gateCondition("launcher").Status).To(Equal(k8sv1.ConditionTrue))
+			Expect(gateCondition("target")).To(BeNil())
+			Expect(gateCondition("ungated")).To(BeNil())
+		})
+
+		It("should report unsupported cgroup operations with the readiness gate", func() {
+			unsupported := &cgroup.UnsupportedError{Operation: "reading the cpuset", Reason: "the cpuset controller is not enabled"}
+			Expect(controller.setCgroupReadinessGate(vmi, unsupported)).To(Succeed())
+			condition := gateCondition("launcher")
+			Expect(condition.Status).To(Equal(k8sv1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("UnsupportedCgroupOperation"))
+			Expect(condition.Message).To(Equal(unsupported.Error()))
+		})
+	})
+
 	Context("When VirtualMachineInstance is connected to a network", func() {
 
 		It("should only report the pod network in status", func() {
@@ -1663,6 +2379,93 @@ var _ = Describe("VirtualMachineInstance", func() {
 			controller.Execute()
 		})
 
+		It("should report the VLAN of interfaces connected to an OVN-Kubernetes localnet network", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Scheduled
+
+			vlan := int32(100)
+			vmi.Spec.Networks = []v1.Network{
+				{
+					Name: "localnet",
+					NetworkSource: v1.NetworkSource{
+						Multus: &v1.MultusNetwork{
+							NetworkName: "localnet",
+							OVN:         &v1.OVNNetwork{Topology: v1.OVNTopologyLocalnet, VLAN: &vlan},
+						},
+					},
+				},
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			domain.Spec.Devices.Interfaces = []api.Interface{
+				{
+					MAC:   &api.MAC{MAC: "1C:CE:C0:01:BE:E7"},
+					Alias: &api.Alias{Name: "localnet"},
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				Expect(len(arg.(*v1.VirtualMachineInstance).Status.Interfaces)).To(Equal(1))
+				Expect(arg.(*v1.VirtualMachineInstance).Status.Interfaces[0].Name).To(Equal("localnet"))
+				Expect(arg.(*v1.VirtualMachineInstance).Status.Interfaces[0].VLAN).To(Equal(vlan))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		})
+
+		It("should report the allowed addresses of an interface", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Scheduled
+
+			allowed := &v1.AllowedAddresses{
+				MACAddresses: []string{"00:00:5e:00:01:0a"},
+			}
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+				{
+					Name:                   "vrrp",
+					InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}},
+					AllowedAddresses:       allowed,
+				},
+			}
+			vmi.Spec.Networks = []v1.Network{
+				{
+					Name: "vrrp",
+					NetworkSource: v1.NetworkSource{
+						Multus: &v1.MultusNetwork{NetworkName: "vrrp"},
+					},
+				},
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			domain.Spec.Devices.Interfaces = []api.Interface{
+				{
+					MAC:   &api.MAC{MAC: "1C:CE:C0:01:BE:E7"},
+					Alias: &api.Alias{Name: "vrrp"},
+				},
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				Expect(len(arg.(*v1.VirtualMachineInstance).Status.Interfaces)).To(Equal(1))
+				Expect(arg.(*v1.VirtualMachineInstance).Status.Interfaces[0].AllowedAddresses).To(Equal(allowed))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		})
+
 		It("should update existing interface with IPs", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
 			vmi.UID = vmiTestUUID
@@ -1742,6 +2545,169 @@ var _ = Describe("VirtualMachineInstance", func() {
 			controller.Execute()
 		})
 
+		It("should accumulate the reclaimed bytes of new disk compactions in VMI status", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			lastCompaction := metav1.NewTime(time.Now().Add(-time.Hour))
+			vmi.Status.DiskCompaction = &v1.DiskCompactionStatus{
+				LastCompactionTimestamp: &lastCompaction,
+				ReclaimedBytes:          100,
+				TotalReclaimedBytes:     300,
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			now := metav1.Now()
+			domain.Spec.Metadata.KubeVirt.DiskCompaction = &api.DiskCompactionMetadata{
+				Timestamp:      &now,
+				ReclaimedBytes: 200,
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				status := arg.(*v1.VirtualMachineInstance).Status.DiskCompaction
+				Expect(status.LastCompactionTimestamp).To(Equal(&now))
+				Expect(status.ReclaimedBytes).To(Equal(int64(200)))
+				Expect(status.TotalReclaimedBytes).To(Equal(int64(500)))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		})
+
+		It("should report the result of a finished memory dump and drop the memory dump condition", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi.Status.MemoryDump = &v1.VirtualMachineInstanceMemoryDumpStatus{
+				ClaimName: "dump",
+				Phase:     v1.MemoryDumpInProgress,
+				FileName:  "testvmi.memory.dump",
+			}
+			vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+				{Type: v1.VirtualMachineInstanceMemoryDumpInProgress, Status: k8sv1.ConditionTrue},
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			start := metav1.NewTime(time.Now().Add(-time.Minute))
+			end := metav1.Now()
+			domain.Spec.Metadata.KubeVirt.MemoryDump = &api.MemoryDumpMetadata{
+				FileName:       "testvmi.memory.dump",
+				StartTimestamp: &start,
+				EndTimestamp:   &end,
+				Completed:      true,
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				updated := arg.(*v1.VirtualMachineInstance)
+				Expect(updated.Status.MemoryDump.Phase).To(Equal(v1.MemoryDumpCompleted))
+				Expect(updated.Status.MemoryDump.StartTimestamp).To(Equal(&start))
+				Expect(updated.Status.MemoryDump.EndTimestamp).To(Equal(&end))
+				for _, condition := range updated.Status.Conditions {
+					Expect(condition.Type).ToNot(Equal(v1.VirtualMachineInstanceMemoryDumpInProgress))
+				}
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		})
+
+		table.DescribeTable("should report the frozen condition from the domain metadata", func(frozen bool) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			if !frozen {
+				vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+					{Type: v1.VirtualMachineInstanceFrozen, Status: k8sv1.ConditionTrue},
+				}
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			if frozen {
+				now := metav1.Now()
+				domain.Spec.Metadata.KubeVirt.FSFreeze = &api.FSFreezeMetadata{Timestamp: &now}
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				updated := arg.(*v1.VirtualMachineInstance)
+				hasFrozenCondition := false
+				for _, condition := range updated.Status.Conditions {
+					if condition.Type == v1.VirtualMachineInstanceFrozen {
+						hasFrozenCondition = true
+					}
+				}
+				Expect(hasFrozenCondition).To(Equal(frozen))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		},
+			table.Entry("adding it while the guest filesystems are frozen", true),
+			table.Entry("removing it once the guest filesystems are thawed", false),
+		)
+
+		table.DescribeTable("should report the SR-IOV failover condition from the domain interfaces", func(vfAttached bool) {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.UID = vmiTestUUID
+			vmi.ObjectMeta.ResourceVersion = "1"
+			vmi.Status.Phase = v1.Running
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+				{Name: "sriov", InterfaceBindingMethod: v1.InterfaceBindingMethod{SRIOV: &v1.InterfaceSRIOV{FailoverStandby: "standby"}}},
+			}
+			if vfAttached {
+				vmi.Status.Conditions = []v1.VirtualMachineInstanceCondition{
+					{Type: v1.VirtualMachineInstanceSRIOVFailover, Status: k8sv1.ConditionTrue},
+				}
+			}
+
+			mockWatchdog.CreateFile(vmi)
+			domain := api.NewMinimalDomainWithUUID("testvmi", vmiTestUUID)
+			domain.Status.Status = api.Running
+			if vfAttached {
+				domain.Spec.Devices.Interfaces = []api.Interface{
+					{Type: "hostdev", Alias: &api.Alias{Name: "sriov"}, Teaming: &api.Teaming{Type: "transient", Persistent: "ua-standby"}},
+				}
+			}
+
+			vmiFeeder.Add(vmi)
+			domainFeeder.Add(domain)
+			client.EXPECT().SyncVirtualMachine(vmi, gomock.Any())
+
+			vmiInterface.EXPECT().Update(gomock.Any()).Do(func(arg interface{}) {
+				updated := arg.(*v1.VirtualMachineInstance)
+				hasFailoverCondition := false
+				for _, condition := range updated.Status.Conditions {
+					if condition.Type == v1.VirtualMachineInstanceSRIOVFailover {
+						hasFailoverCondition = true
+						Expect(condition.Reason).To(Equal(v1.VirtualMachineInstanceReasonVFDetached))
+					}
+				}
+				Expect(hasFailoverCondition).To(Equal(!vfAttached))
+			}).Return(vmi, nil)
+
+			controller.Execute()
+		},
+			table.Entry("adding it while the VF is detached", false),
+			table.Entry("removing it once the VF is attached again", true),
+		)
+
 		It("should add new vmi interfaces for new domain interfaces", func() {
 			vmi := v1.NewMinimalVMI("testvmi")
 			vmi.UID = vmiTestUUID
@@ -2098,3 +3064,30 @@ func addActivePods(vmi *v1.VirtualMachineInstance, podUID types.UID, hostName st
 	}
 	return vmi
 }
+
+type fakeVFHandler struct {
+	spoofCheck bool
+	allowed    []string
+	restored   []vfConfig
+	err        error
+}
+
+func (f *fakeVFHandler) Config(pciAddress string) (vfConfig, error) {
+	return vfConfig{PCIAddress: pciAddress, SpoofCheck: f.spoofCheck}, nil
+}
+
+func (f *fakeVFHandler) AllowAdditionalMACs(pciAddress string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.allowed = append(f.allowed, pciAddress)
+	return nil
+}
+
+func (f *fakeVFHandler) Restore(config vfConfig) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.restored = append(f.restored, config)
+	return nil
+}
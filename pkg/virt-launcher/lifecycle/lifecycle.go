@@ -0,0 +1,108 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// defaultHost reaches the sidecars, which share the network namespace of the launcher pod
+	defaultHost = "localhost"
+)
+
+// Run executes the action of a lifecycle hook and returns an error if it failed or timed out
+func Run(handler *v1.LifecycleHandler) error {
+	timeout := defaultTimeout
+	if handler.TimeoutSeconds > 0 {
+		timeout = time.Duration(handler.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if handler.Exec != nil {
+		return runExec(ctx, handler.Exec)
+	} else if handler.HTTPGet != nil {
+		return runHTTPGet(ctx, handler.HTTPGet)
+	}
+	return fmt.Errorf("no action specified")
+}
+
+func runExec(ctx context.Context, action *k8sv1.ExecAction) error {
+	if len(action.Command) == 0 {
+		return fmt.Errorf("no command specified")
+	}
+	out, err := exec.CommandContext(ctx, action.Command[0], action.Command[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command %q failed: %v, output: %s", action.Command[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func runHTTPGet(ctx context.Context, action *k8sv1.HTTPGetAction) error {
+	scheme := strings.ToLower(string(action.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	host := action.Host
+	if host == "" {
+		host = defaultHost
+	}
+	u := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(action.Port.IntValue())),
+		Path:   action.Path,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range action.HTTPHeaders {
+		if strings.EqualFold(header.Name, "Host") {
+			req.Host = header.Value
+		} else {
+			req.Header.Add(header.Name, header.Value)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s returned %s", u.String(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package lifecycle
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Lifecycle hooks", func() {
+
+	Context("with an exec action", func() {
+		It("should succeed if the command succeeds", func() {
+			err := Run(&v1.LifecycleHandler{Exec: &k8sv1.ExecAction{Command: []string{"true"}}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with the output of a failing command", func() {
+			err := Run(&v1.LifecycleHandler{Exec: &k8sv1.ExecAction{Command: []string{"sh", "-c", "echo draining failed; exit 1"}}})
+			Expect(err).To(MatchError(ContainSubstring("draining failed")))
+		})
+
+		It("should fail if the command does not finish in time", func() {
+			err := Run(&v1.LifecycleHandler{
+				Exec:           &k8sv1.ExecAction{Command: []string{"sleep", "10"}},
+				TimeoutSeconds: 1,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with a http action", func() {
+		var server *httptest.Server
+		var status int
+		var requests []*http.Request
+
+		newHandler := func(path string) *v1.LifecycleHandler {
+			u, err := url.Parse(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			host, port, err := net.SplitHostPort(u.Host)
+			Expect(err).ToNot(HaveOccurred())
+			portNumber, err := strconv.Atoi(port)
+			Expect(err).ToNot(HaveOccurred())
+			return &v1.LifecycleHandler{
+				HTTPGet: &k8sv1.HTTPGetAction{
+					Host:        host,
+					Port:        intstr.FromInt(portNumber),
+					Path:        path,
+					HTTPHeaders: []k8sv1.HTTPHeader{{Name: "X-Hook", Value: "preStop"}},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			requests = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				w.WriteHeader(status)
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send the request with the configured headers", func() {
+			Expect(Run(newHandler("/drain"))).To(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].URL.Path).To(Equal("/drain"))
+			Expect(requests[0].Header.Get("X-Hook")).To(Equal("preStop"))
+		})
+
+		It("should fail on an error status", func() {
+			status = http.StatusInternalServerError
+			err := Run(newHandler("/drain"))
+			Expect(err).To(MatchError(ContainSubstring("500")))
+		})
+	})
+})
@@ -47,9 +47,10 @@ type Notifier struct {
 }
 
 type libvirtEvent struct {
-	Domain     string
-	Event      *libvirt.DomainEventLifecycle
-	AgentEvent *libvirt.DomainEventAgentLifecycle
+	Domain        string
+	Event         *libvirt.DomainEventLifecycle
+	AgentEvent    *libvirt.DomainEventAgentLifecycle
+	WatchdogEvent *libvirt.DomainEventWatchdog
 }
 
 func NewNotifier(virtShareDir string) *Notifier {
@@ -199,7 +200,7 @@ func newWatchEventError(err error) watch.Event {
 }
 
 func eventCallback(c cli.Connection, domain *api.Domain, libvirtEvent libvirtEvent, client *Notifier, events chan watch.Event,
-	interfaceStatus []api.InterfaceStatus, osInfo *api.GuestOSInfo) {
+	interfaceStatus []api.InterfaceStatus, osInfo *api.GuestOSInfo, hostname *string, watchdogEvents *api.WatchdogEvents) {
 	d, err := c.LookupDomainByName(util.DomainFromNamespaceName(domain.ObjectMeta.Namespace, domain.ObjectMeta.Name))
 	if err != nil {
 		if !domainerrors.IsNotFound(err) {
@@ -267,7 +268,13 @@ func eventCallback(c cli.Connection, domain *api.Domain, libvirtEvent libvirtEve
 		if osInfo != nil {
 			domain.Status.OSInfo = *osInfo
 		}
-		if interfaceStatus != nil || osInfo != nil {
+		if hostname != nil {
+			domain.Status.Hostname = *hostname
+		}
+		if watchdogEvents != nil {
+			domain.Status.Watchdog = *watchdogEvents
+		}
+		if interfaceStatus != nil || osInfo != nil || hostname != nil {
 			event := watch.Event{Type: watch.Modified, Object: domain}
 			client.SendDomainEvent(event)
 			events <- event
@@ -314,11 +321,18 @@ func (n *Notifier) StartDomainNotifier(
 	go func() {
 		var interfaceStatuses []api.InterfaceStatus
 		var guestOsInfo *api.GuestOSInfo
+		var hostname *string
+		watchdogEvents := &api.WatchdogEvents{}
 		for {
 			select {
 			case event := <-eventChan:
+				if event.WatchdogEvent != nil {
+					// the domain does not keep track of the watchdog firing, unless it is paused
+					watchdogEvents.Count++
+					watchdogEvents.LastTimestamp = metav1.Now()
+				}
 				domainCache = util.NewDomainFromName(event.Domain, vmiUID)
-				eventCallback(domainConn, domainCache, event, n, deleteNotificationSent, interfaceStatuses, guestOsInfo)
+				eventCallback(domainConn, domainCache, event, n, deleteNotificationSent, interfaceStatuses, guestOsInfo, hostname, watchdogEvents)
 				log.Log.Infof("Domain name event: %v", domainCache.Spec.Name)
 				if event.AgentEvent != nil {
 					if event.AgentEvent.State == libvirt.CONNECT_DOMAIN_EVENT_AGENT_LIFECYCLE_STATE_CONNECTED {
@@ -330,12 +344,16 @@ func (n *Notifier) StartDomainNotifier(
 			case agentUpdate := <-agentStore.AgentUpdated:
 				interfaceStatuses = agentUpdate.DomainInfo.Interfaces
 				guestOsInfo = agentUpdate.DomainInfo.OSInfo
+				if agentUpdate.DomainInfo.Hostname != nil {
+					// keep the last known hostname for the lifecycle events
+					hostname = agentUpdate.DomainInfo.Hostname
+				}
 				if interfaceStatuses != nil {
 					interfaceStatuses = agentpoller.MergeAgentStatusesWithDomainData(domainCache.Spec.Devices.Interfaces, interfaceStatuses)
 				}
 
 				eventCallback(domainConn, domainCache, libvirtEvent{}, n, deleteNotificationSent,
-					interfaceStatuses, guestOsInfo)
+					interfaceStatuses, guestOsInfo, hostname, watchdogEvents)
 			case <-reconnectChan:
 				n.SendDomainEvent(newWatchEventError(fmt.Errorf("Libvirt reconnect, domain %s", domainName)))
 			}
@@ -379,6 +397,52 @@ func (n *Notifier) StartDomainNotifier(
 		return err
 	}
 
+	// hot plugged and unplugged devices, like the VFs of SR-IOV interfaces
+	// with a failover standby, change the domain spec without a lifecycle event
+	deviceEventCallback := func(d *libvirt.Domain, alias string) {
+		log.Log.Infof("Device event for %s received", alias)
+		name, err := d.GetName()
+		if err != nil {
+			log.Log.Reason(err).Info("Could not determine name of libvirt domain in event callback.")
+		}
+		select {
+		case eventChan <- libvirtEvent{Domain: name}:
+		default:
+			log.Log.Infof("Libvirt event channel is full, dropping event.")
+		}
+	}
+	err = domainConn.DomainEventDeviceAddedRegister(func(c *libvirt.Connect, d *libvirt.Domain, event *libvirt.DomainEventDeviceAdded) {
+		deviceEventCallback(d, event.DevAlias)
+	})
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to register event callback with libvirt")
+		return err
+	}
+	err = domainConn.DomainEventDeviceRemovedRegister(func(c *libvirt.Connect, d *libvirt.Domain, event *libvirt.DomainEventDeviceRemoved) {
+		deviceEventCallback(d, event.DevAlias)
+	})
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to register event callback with libvirt")
+		return err
+	}
+
+	err = domainConn.DomainEventWatchdogRegister(func(c *libvirt.Connect, d *libvirt.Domain, event *libvirt.DomainEventWatchdog) {
+		log.Log.Infof("Watchdog event with action %d received", event.Action)
+		name, err := d.GetName()
+		if err != nil {
+			log.Log.Reason(err).Info("Could not determine name of libvirt domain in event callback.")
+		}
+		select {
+		case eventChan <- libvirtEvent{WatchdogEvent: event, Domain: name}:
+		default:
+			log.Log.Infof("Libvirt event channel is full, dropping event.")
+		}
+	})
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to register event callback with libvirt")
+		return err
+	}
+
 	log.Log.Infof("Registered libvirt event notify callback")
 	return nil
 }
@@ -88,6 +88,9 @@ func (s *AsyncAgentStore) Store(key AgentCommand, value interface{}) {
 			domainInfo.OSInfo = &info
 		case GET_INTERFACES:
 			domainInfo.Interfaces = value.([]api.InterfaceStatus)
+		case GET_HOSTNAME:
+			hostname := value.(string)
+			domainInfo.Hostname = &hostname
 		}
 
 		s.AgentUpdated <- AgentUpdatedEvent{
@@ -427,6 +427,20 @@ var _ = Describe("Qemu agent poller", func() {
 				agentStore.Store(GET_OSINFO, fakeInfo)
 				Expect(agentStore.AgentUpdated).ToNot(Receive())
 			})
+
+			It("should fire an event for a new hostname", func() {
+				var agentStore = NewAsyncAgentStore()
+				hostname := "TestHost"
+
+				agentStore.Store(GET_HOSTNAME, hostname)
+				Expect(agentStore.AgentUpdated).To(Receive(Equal(AgentUpdatedEvent{
+					Type:       GET_HOSTNAME,
+					DomainInfo: api.DomainGuestInfo{Hostname: &hostname},
+				})))
+
+				agentStore.Store(GET_HOSTNAME, hostname)
+				Expect(agentStore.AgentUpdated).ToNot(Receive())
+			})
 		})
 	})
 })
@@ -0,0 +1,104 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package api
+
+import (
+	"fmt"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+const (
+	backupModePull    = "pull"
+	backupSocketName  = "virt-backup"
+	dirtyBitmapPrefix = "backup-"
+)
+
+// BackupSocketPath returns the path of the unix socket the NBD server of a
+// backup listens on
+func BackupSocketPath(vmi *v1.VirtualMachineInstance) string {
+	return fmt.Sprintf("/var/run/kubevirt-private/%s/%s", vmi.ObjectMeta.UID, backupSocketName)
+}
+
+// NewDomainBackup returns the pull mode backup of the disks which back volumes
+// of the VirtualMachineInstance, the checkpoint which has to be created with
+// it and the volumes the NBD server exports. Only qcow2 disks can keep track
+// of changed blocks, so incremental backups only include those.
+func NewDomainBackup(vmi *v1.VirtualMachineInstance, spec *DomainSpec, name string, incremental string) (*DomainBackup, *DomainCheckpoint, []v1.VirtualMachineBackupVolume, error) {
+	backup := &DomainBackup{
+		Mode:        backupModePull,
+		Incremental: incremental,
+		Server: &DomainBackupServer{
+			Transport: "unix",
+			Socket:    BackupSocketPath(vmi),
+		},
+	}
+	checkpoint := &DomainCheckpoint{
+		Name: name,
+	}
+
+	backupVolumes := backupVolumeNames(vmi)
+	var volumes []v1.VirtualMachineBackupVolume
+	for _, disk := range spec.Devices.Disks {
+		if disk.Alias == nil || disk.Device != "disk" || disk.Target.Device == "" {
+			continue
+		}
+		dev := disk.Target.Device
+		tracked := disk.Driver != nil && disk.Driver.Type == "qcow2"
+		include := backupVolumes[disk.Alias.Name] && (incremental == "" || tracked)
+
+		backupDisk := DomainBackupDisk{Name: dev, Backup: "no"}
+		if include {
+			backupDisk.Backup = "yes"
+			backupDisk.ExportName = dev
+			volume := v1.VirtualMachineBackupVolume{Name: disk.Alias.Name, ExportName: dev}
+			if incremental != "" {
+				volume.DirtyBitmap = dirtyBitmapPrefix + dev
+			}
+			volumes = append(volumes, volume)
+		}
+		backup.Disks = append(backup.Disks, backupDisk)
+
+		checkpointDisk := DomainCheckpointDisk{Name: dev, Checkpoint: "no"}
+		if include && tracked {
+			checkpointDisk.Checkpoint = "bitmap"
+		}
+		checkpoint.Disks = append(checkpoint.Disks, checkpointDisk)
+	}
+
+	if len(volumes) == 0 {
+		return nil, nil, nil, fmt.Errorf("no disk of %s can be backed up", vmi.Name)
+	}
+	return backup, checkpoint, volumes, nil
+}
+
+// backupVolumeNames returns the volumes which hold guest data worth backing
+// up. Volumes generated from the VMI spec, like cloud-init, are skipped.
+func backupVolumeNames(vmi *v1.VirtualMachineInstance) map[string]bool {
+	names := map[string]bool{}
+	for _, volume := range vmi.Spec.Volumes {
+		source := volume.VolumeSource
+		if source.PersistentVolumeClaim != nil || source.DataVolume != nil || source.HostDisk != nil ||
+			source.ContainerDisk != nil || source.Ephemeral != nil || source.EmptyDisk != nil {
+			names[volume.Name] = true
+		}
+	}
+	return names
+}
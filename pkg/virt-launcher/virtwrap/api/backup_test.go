@@ -0,0 +1,111 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package api
+
+import (
+	"encoding/xml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Backup", func() {
+
+	var vmi *v1.VirtualMachineInstance
+	var spec *DomainSpec
+
+	BeforeEach(func() {
+		vmi = v1.NewMinimalVMI("testvmi")
+		vmi.UID = "1234"
+		vmi.Spec.Volumes = []v1.Volume{
+			{Name: "rootdisk", VolumeSource: v1.VolumeSource{ContainerDisk: &v1.ContainerDiskSource{Image: "fedora"}}},
+			{Name: "datadisk", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			{Name: "cloudinit", VolumeSource: v1.VolumeSource{CloudInitNoCloud: &v1.CloudInitNoCloudSource{UserData: "data"}}},
+		}
+		spec = &DomainSpec{}
+		spec.Devices.Disks = []Disk{
+			{Device: "disk", Target: DiskTarget{Device: "vda"}, Driver: &DiskDriver{Type: "qcow2"}, Alias: &Alias{Name: "rootdisk"}},
+			{Device: "disk", Target: DiskTarget{Device: "vdb"}, Driver: &DiskDriver{Type: "raw"}, Alias: &Alias{Name: "datadisk"}},
+			{Device: "disk", Target: DiskTarget{Device: "vdc"}, Driver: &DiskDriver{Type: "raw"}, Alias: &Alias{Name: "cloudinit"}},
+		}
+	})
+
+	It("should back up all data disks and track changes of qcow2 disks", func() {
+		backup, checkpoint, volumes, err := NewDomainBackup(vmi, spec, "backup1", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(backup.Incremental).To(BeEmpty())
+		Expect(backup.Server.Socket).To(Equal("/var/run/kubevirt-private/1234/virt-backup"))
+		Expect(backup.Disks).To(Equal([]DomainBackupDisk{
+			{Name: "vda", Backup: "yes", ExportName: "vda"},
+			{Name: "vdb", Backup: "yes", ExportName: "vdb"},
+			{Name: "vdc", Backup: "no"},
+		}))
+		Expect(checkpoint.Name).To(Equal("backup1"))
+		Expect(checkpoint.Disks).To(Equal([]DomainCheckpointDisk{
+			{Name: "vda", Checkpoint: "bitmap"},
+			{Name: "vdb", Checkpoint: "no"},
+			{Name: "vdc", Checkpoint: "no"},
+		}))
+		Expect(volumes).To(Equal([]v1.VirtualMachineBackupVolume{
+			{Name: "rootdisk", ExportName: "vda"},
+			{Name: "datadisk", ExportName: "vdb"},
+		}))
+	})
+
+	It("should only include disks with a dirty bitmap in incremental backups", func() {
+		backup, _, volumes, err := NewDomainBackup(vmi, spec, "backup2", "backup1")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(backup.Incremental).To(Equal("backup1"))
+		Expect(backup.Disks[0].Backup).To(Equal("yes"))
+		Expect(backup.Disks[1].Backup).To(Equal("no"))
+		Expect(volumes).To(Equal([]v1.VirtualMachineBackupVolume{
+			{Name: "rootdisk", ExportName: "vda", DirtyBitmap: "backup-vda"},
+		}))
+	})
+
+	It("should fail if there is nothing to back up", func() {
+		spec.Devices.Disks = spec.Devices.Disks[2:]
+		_, _, _, err := NewDomainBackup(vmi, spec, "backup1", "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should marshal the backup and checkpoint definitions", func() {
+		backup, checkpoint, _, err := NewDomainBackup(vmi, spec, "backup2", "backup1")
+		Expect(err).ToNot(HaveOccurred())
+
+		backupXML, err := xml.Marshal(backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(backupXML)).To(Equal(`<domainbackup mode="pull"><incremental>backup1</incremental>` +
+			`<server transport="unix" socket="/var/run/kubevirt-private/1234/virt-backup"></server>` +
+			`<disks><disk name="vda" backup="yes" exportname="vda"></disk><disk name="vdb" backup="no"></disk><disk name="vdc" backup="no"></disk></disks>` +
+			`</domainbackup>`))
+
+		checkpointXML, err := xml.Marshal(checkpoint)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(checkpointXML)).To(Equal(`<domaincheckpoint><name>backup2</name>` +
+			`<disks><disk name="vda" checkpoint="bitmap"></disk><disk name="vdb" checkpoint="no"></disk><disk name="vdc" checkpoint="no"></disk></disks>` +
+			`</domaincheckpoint>`))
+	})
+})
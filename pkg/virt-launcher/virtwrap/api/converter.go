@@ -32,6 +32,7 @@ import (
 
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -45,6 +46,7 @@ import (
 	hostdisk "kubevirt.io/kubevirt/pkg/host-disk"
 	"kubevirt.io/kubevirt/pkg/ignition"
 	"kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	"kubevirt.io/kubevirt/pkg/util/net/dns"
 )
 
@@ -56,6 +58,14 @@ const (
 	EFIVars                = "OVMF_VARS.fd"
 	EFICodeSecureBoot      = "OVMF_CODE.secboot.fd"
 	EFIVarsSecureBoot      = "OVMF_VARS.secboot.fd"
+	VirtiofsdPath          = "/usr/libexec/virtiofsd"
+)
+
+// SEV guest policy bits, see the AMD SEV API specification
+const (
+	SEVPolicyNoDebug        = 1 << 0
+	SEVPolicyNoKeysSharing  = 1 << 1
+	SEVPolicyEncryptedState = 1 << 2
 )
 
 // +k8s:deepcopy-gen=false
@@ -73,9 +83,30 @@ type ConverterContext struct {
 	GpuDevices            []string
 	VgpuDevices           []string
 	QATDevices            []string
+	HostDevices           map[string][]string
+	MediatedDevices       map[string][]string
 	EmulatorThreadCpu     *int
 	OVMFPath              string
 	MemBalloonStatsPeriod uint
+	HostNUMANodes         []hardware.NUMANode
+}
+
+func hasTunedQueues(disk *v1.Disk) bool {
+	return disk.Queues != nil && (disk.Queues.Count != nil || disk.Queues.Size != nil)
+}
+
+func getSCSIQueues(disk *v1.Disk, vcpus uint) uint {
+	if disk.Queues.Count != nil {
+		return uint(*disk.Queues.Count)
+	}
+	return vcpus
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 func Convert_v1_Disk_To_api_Disk(diskDevice *v1.Disk, disk *Disk, devicePerBus map[string]int, numQueues *uint) error {
@@ -124,6 +155,17 @@ func Convert_v1_Disk_To_api_Disk(diskDevice *v1.Disk, disk *Disk, devicePerBus m
 	}
 	if numQueues != nil && disk.Target.Bus == "virtio" {
 		disk.Driver.Queues = numQueues
+		if diskDevice.Queues != nil && diskDevice.Queues.Count != nil {
+			count := uint(*diskDevice.Queues.Count)
+			disk.Driver.Queues = &count
+		}
+		if diskDevice.Queues != nil && diskDevice.Queues.Size != nil {
+			size := uint(*diskDevice.Queues.Size)
+			disk.Driver.QueueSize = &size
+		}
+	}
+	if diskDevice.IOTune != nil {
+		disk.IOTune = Convert_v1_DiskIOTune_To_api_DiskIOTune(diskDevice.IOTune)
 	}
 	disk.Alias = &Alias{Name: diskDevice.Name}
 	if diskDevice.BootOrder != nil {
@@ -133,6 +175,24 @@ func Convert_v1_Disk_To_api_Disk(diskDevice *v1.Disk, disk *Disk, devicePerBus m
 	return nil
 }
 
+// Convert_v1_DiskIOTune_To_api_DiskIOTune maps the unset limits to zero, which libvirt treats as unlimited
+func Convert_v1_DiskIOTune_To_api_DiskIOTune(ioTune *v1.DiskIOTune) *DiskIOTune {
+	value := func(limit *uint64) uint64 {
+		if limit == nil {
+			return 0
+		}
+		return *limit
+	}
+	return &DiskIOTune{
+		TotalBytesSec: value(ioTune.TotalBytesSec),
+		ReadBytesSec:  value(ioTune.ReadBytesSec),
+		WriteBytesSec: value(ioTune.WriteBytesSec),
+		TotalIopsSec:  value(ioTune.TotalIopsSec),
+		ReadIopsSec:   value(ioTune.ReadIopsSec),
+		WriteIopsSec:  value(ioTune.WriteIopsSec),
+	}
+}
+
 func checkDirectIOFlag(path string) bool {
 	// check if fs where disk.img file is located or block device
 	// support direct i/o
@@ -267,6 +327,9 @@ func Convert_v1_Volume_To_api_Disk(source *v1.Volume, disk *Disk, c *ConverterCo
 	if source.Ephemeral != nil {
 		return Convert_v1_EphemeralVolumeSource_To_api_Disk(source.Name, source.Ephemeral, disk, c)
 	}
+	if source.GoldenImage != nil {
+		return Convert_v1_GoldenImageVolumeSource_To_api_Disk(source.Name, disk, c)
+	}
 	if source.EmptyDisk != nil {
 		return Convert_v1_EmptyDiskSource_To_api_Disk(source.Name, source.EmptyDisk, disk, c)
 	}
@@ -279,6 +342,9 @@ func Convert_v1_Volume_To_api_Disk(source *v1.Volume, disk *Disk, c *ConverterCo
 	if source.ServiceAccount != nil {
 		return Convert_v1_Config_To_api_Disk(source.Name, disk, config.ServiceAccount)
 	}
+	if source.Sysprep != nil {
+		return Convert_v1_Config_To_api_Disk(source.Name, disk, config.Sysprep)
+	}
 
 	return fmt.Errorf("disk %s references an unsupported source", disk.Alias.Name)
 }
@@ -296,6 +362,9 @@ func Convert_v1_Config_To_api_Disk(volumeName string, disk *Disk, configType con
 	case config.ServiceAccount:
 		disk.Source.File = config.GetServiceAccountDiskPath()
 		break
+	case config.Sysprep:
+		disk.Source.File = config.GetSysprepDiskPath(volumeName)
+		break
 	default:
 		return fmt.Errorf("Cannot convert config '%s' to disk, unrecognized type", configType)
 	}
@@ -303,10 +372,36 @@ func Convert_v1_Config_To_api_Disk(volumeName string, disk *Disk, configType con
 	return nil
 }
 
+// getVirtiofsSourceDir returns the directory in the virt-launcher pod which
+// holds the content of a volume shared through virtio-fs
+func getVirtiofsSourceDir(volume *v1.Volume, c *ConverterContext) (string, error) {
+	switch {
+	case volume.ConfigMap != nil:
+		return config.GetConfigMapSourcePath(volume.Name), nil
+	case volume.Secret != nil:
+		return config.GetSecretSourcePath(volume.Name), nil
+	case volume.PersistentVolumeClaim != nil:
+		if c.IsBlockPVC[volume.Name] {
+			return "", fmt.Errorf("block mode PersistentVolumeClaim %s can not be shared through virtio-fs", volume.PersistentVolumeClaim.ClaimName)
+		}
+		return filepath.Dir(GetFilesystemVolumePath(volume.Name)), nil
+	}
+	return "", fmt.Errorf("volume %s can not be shared through virtio-fs", volume.Name)
+}
+
 func GetFilesystemVolumePath(volumeName string) string {
 	return filepath.Join(string(filepath.Separator), "var", "run", "kubevirt-private", "vmi-disks", volumeName, "disk.img")
 }
 
+// GetSerialConsoleLogPath returns the file the serial console output is logged to,
+// on the given volume or, if no volume is given, next to the serial console socket.
+func GetSerialConsoleLogPath(vmiUID types.UID, volumeName string) string {
+	if volumeName != "" {
+		return filepath.Join(string(filepath.Separator), "var", "run", "kubevirt-private", "vmi-disks", volumeName, "serial-console.log")
+	}
+	return filepath.Join(string(filepath.Separator), "var", "run", "kubevirt-private", string(vmiUID), "virt-serial0.log")
+}
+
 func GetBlockDeviceVolumePath(volumeName string) string {
 	return filepath.Join(string(filepath.Separator), "dev", volumeName)
 }
@@ -374,6 +469,12 @@ func Convert_v1_IgnitionData_To_api_Disk(disk *Disk, c *ConverterContext) error
 	return nil
 }
 
+// IsCompactableDisk tells whether the disk is a qcow2 overlay owned by the VMI,
+// which shrinks when the unused space of the guest is trimmed
+func IsCompactableDisk(disk *Disk) bool {
+	return disk.Type == "file" && disk.Driver != nil && disk.Driver.Type == "qcow2" && disk.Source.File != ""
+}
+
 func Convert_v1_EmptyDiskSource_To_api_Disk(volumeName string, _ *v1.EmptyDiskSource, disk *Disk, c *ConverterContext) error {
 	if disk.Type == "lun" {
 		return fmt.Errorf("device %s is of type lun. Not compatible with a file based disk", disk.Alias.Name)
@@ -429,6 +530,33 @@ func Convert_v1_EphemeralVolumeSource_To_api_Disk(volumeName string, source *v1.
 	return nil
 }
 
+// Convert_v1_GoldenImageVolumeSource_To_api_Disk builds a local qcow2 overlay on top of the
+// read-only golden image
+func Convert_v1_GoldenImageVolumeSource_To_api_Disk(volumeName string, disk *Disk, c *ConverterContext) error {
+	if disk.Type == "lun" {
+		return fmt.Errorf("device %s is of type lun. Not compatible with a file based disk", disk.Alias.Name)
+	}
+
+	disk.Type = "file"
+	disk.Driver.Type = "qcow2"
+	disk.Source.File = ephemeraldisk.GetFilePath(volumeName)
+
+	backingDisk := &Disk{Driver: &DiskDriver{}}
+	err := Convert_v1_PersistentVolumeClaim_To_api_Disk(volumeName, backingDisk, c)
+	if err != nil {
+		return err
+	}
+
+	disk.BackingStore = &BackingStore{
+		Type: backingDisk.Type,
+		Format: &BackingStoreFormat{
+			Type: backingDisk.Driver.Type,
+		},
+		Source: &backingDisk.Source,
+	}
+	return nil
+}
+
 func Convert_v1_Watchdog_To_api_Watchdog(source *v1.Watchdog, watchdog *Watchdog, _ *ConverterContext) error {
 	watchdog.Alias = &Alias{
 		Name: source.Name,
@@ -436,6 +564,10 @@ func Convert_v1_Watchdog_To_api_Watchdog(source *v1.Watchdog, watchdog *Watchdog
 	if source.I6300ESB != nil {
 		watchdog.Model = "i6300esb"
 		watchdog.Action = string(source.I6300ESB.Action)
+		if source.I6300ESB.Action == v1.WatchdogActionRestartPod {
+			// the guest is kept paused until virt-handler stops it
+			watchdog.Action = "pause"
+		}
 		return nil
 	}
 	return fmt.Errorf("watchdog %s can't be mapped, no watchdog type specified", source.Name)
@@ -599,7 +731,13 @@ func ConvertV1ToAPIBalloning(source *v1.Devices, ballooning *MemBalloon, c *Conv
 		if c.MemBalloonStatsPeriod != 0 {
 			ballooning.Stats = &Stats{Period: c.MemBalloonStatsPeriod}
 		}
-
+		if source != nil && source.MemBalloon != nil && source.MemBalloon.FreePageReporting != nil {
+			if *source.MemBalloon.FreePageReporting {
+				ballooning.FreePageReporting = "on"
+			} else {
+				ballooning.FreePageReporting = "off"
+			}
+		}
 	}
 }
 
@@ -638,6 +776,29 @@ func popSRIOVPCIAddress(networkName string, addrsMap map[string][]string) (strin
 	return "", addrsMap, fmt.Errorf("no more SR-IOV PCI addresses to allocate")
 }
 
+// Convert_v1_InterfaceBandwidth_To_api_BandWidth maps the ingress of the VMI
+// interface to the inbound traffic of the domain interface, both are seen from
+// the guest
+func Convert_v1_InterfaceBandwidth_To_api_BandWidth(bandwidth *v1.InterfaceBandwidth) *BandWidth {
+	convertLimit := func(limit *v1.BandwidthLimit) *BandWidthLimit {
+		if limit == nil {
+			return nil
+		}
+		domainLimit := &BandWidthLimit{Average: limit.Average}
+		if limit.Peak != nil {
+			domainLimit.Peak = *limit.Peak
+		}
+		if limit.Burst != nil {
+			domainLimit.Burst = *limit.Burst
+		}
+		return domainLimit
+	}
+	return &BandWidth{
+		Inbound:  convertLimit(bandwidth.Ingress),
+		Outbound: convertLimit(bandwidth.Egress),
+	}
+}
+
 func getInterfaceType(iface *v1.Interface) string {
 	if iface.Slirp != nil {
 		// Slirp configuration works only with e1000 or rtl8139
@@ -720,21 +881,32 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		}
 
 		if vmi.Spec.Domain.Firmware.Bootloader != nil && vmi.Spec.Domain.Firmware.Bootloader.EFI != nil {
-			if vmi.Spec.Domain.Firmware.Bootloader.EFI.SecureBoot == nil || *vmi.Spec.Domain.Firmware.Bootloader.EFI.SecureBoot {
+			efi := vmi.Spec.Domain.Firmware.Bootloader.EFI
+			ovmfPath := c.OVMFPath
+			if efi.ContainerDisk != nil {
+				ovmfPath = containerdisk.GetFirmwareTargetPathFromLauncherView()
+			}
+
+			if efi.SecureBoot == nil || *efi.SecureBoot {
 				domain.Spec.OS.BootLoader = &Loader{
-					Path:     filepath.Join(c.OVMFPath, EFICodeSecureBoot),
+					Path:     filepath.Join(ovmfPath, EFICodeSecureBoot),
 					ReadOnly: "yes",
 					Secure:   "yes",
 					Type:     "pflash",
 				}
 
+				// Without enrolled keys the guest starts in setup mode
+				varsTemplate := EFIVarsSecureBoot
+				if efi.EnrolledKeys != nil && !*efi.EnrolledKeys {
+					varsTemplate = EFIVars
+				}
 				domain.Spec.OS.NVRam = &NVRam{
 					NVRam:    filepath.Join("/tmp", domain.Spec.Name),
-					Template: filepath.Join(c.OVMFPath, EFIVarsSecureBoot),
+					Template: filepath.Join(ovmfPath, varsTemplate),
 				}
 			} else {
 				domain.Spec.OS.BootLoader = &Loader{
-					Path:     filepath.Join(c.OVMFPath, EFICode),
+					Path:     filepath.Join(ovmfPath, EFICode),
 					ReadOnly: "yes",
 					Secure:   "no",
 					Type:     "pflash",
@@ -742,7 +914,7 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 
 				domain.Spec.OS.NVRam = &NVRam{
 					NVRam:    filepath.Join("/tmp", domain.Spec.Name),
-					Template: filepath.Join(c.OVMFPath, EFIVars),
+					Template: filepath.Join(ovmfPath, EFIVars),
 				}
 			}
 		}
@@ -820,6 +992,13 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		}
 	}
 
+	if vmi.Annotations[v1.KSMDisabledAnnotation] == "true" {
+		if domain.Spec.MemoryBacking == nil {
+			domain.Spec.MemoryBacking = &MemoryBacking{}
+		}
+		domain.Spec.MemoryBacking.NoSharePages = &NoSharePages{}
+	}
+
 	volumeIndices := map[string]int{}
 	volumes := map[string]*v1.Volume{}
 	for i, volume := range vmi.Spec.Volumes {
@@ -832,13 +1011,15 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 	useIOThreads := false
 	threadPoolLimit := 1
 
+	manualIOThreads := isManualIOThreadsPolicy(vmi)
+
 	if vmi.Spec.Domain.IOThreadsPolicy != nil {
 		useIOThreads = true
 
 		if (*vmi.Spec.Domain.IOThreadsPolicy) == v1.IOThreadsPolicyAuto {
 			// When IOThreads policy is set to auto and we've allocated a dedicated
 			// pCPU for the emulator thread, we can place IOThread and Emulator thread in the same pCPU
-			if vmi.IsCPUDedicated() && vmi.Spec.Domain.CPU.IsolateEmulatorThread {
+			if vmi.IsCPUDedicated() && vmi.GetEmulatorThreadPolicy() == v1.EmulatorThreadPolicyIsolate {
 				threadPoolLimit = 1
 			} else {
 				numCPUs := 1
@@ -875,6 +1056,10 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 	}
 
 	ioThreadCount := (autoThreads + dedicatedThreads)
+	if manualIOThreads {
+		// the IOThreads are defined explicitly, disks without an IOThread don't use one
+		ioThreadCount = len(vmi.Spec.Domain.IOThreads)
+	}
 	if ioThreadCount != 0 {
 		if domain.Spec.IOThreads == nil {
 			domain.Spec.IOThreads = &IOThreads{}
@@ -895,19 +1080,32 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 	}
 	if virtioNetMQRequested {
 		numQueues = &vcpus
+		if count := vmi.Spec.Domain.Devices.NetworkInterfaceQueues; count != nil {
+			netQueues := uint(*count)
+			numQueues = &netQueues
+		}
 	}
 	if virtioBlkMQRequested {
 		numBlkQueues = &vcpus
 	}
 
 	devicePerBus := make(map[string]int)
+	var scsiQueues uint
 	for _, disk := range vmi.Spec.Domain.Devices.Disks {
 		newDisk := Disk{}
 
-		err := Convert_v1_Disk_To_api_Disk(&disk, &newDisk, devicePerBus, numBlkQueues)
+		// Tuning the queues of a disk implies multi-queue for it
+		diskQueues := numBlkQueues
+		if disk.Queues != nil {
+			diskQueues = &vcpus
+		}
+		err := Convert_v1_Disk_To_api_Disk(&disk, &newDisk, devicePerBus, diskQueues)
 		if err != nil {
 			return err
 		}
+		if newDisk.Target.Bus == "scsi" && hasTunedQueues(&disk) {
+			scsiQueues = maxUint(scsiQueues, getSCSIQueues(&disk, vcpus))
+		}
 		volume := volumes[disk.Name]
 		if volume == nil {
 			return fmt.Errorf("No matching volume with name %s found", disk.Name)
@@ -916,8 +1114,17 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		if err != nil {
 			return err
 		}
+		// Let the trims of the guest punch holes into the overlay
+		if vmi.Spec.DiskCompaction != nil && IsCompactableDisk(&newDisk) {
+			newDisk.Driver.Discard = "unmap"
+		}
 
-		if useIOThreads {
+		if manualIOThreads {
+			if disk.IOThread != nil {
+				ioThreadId := uint(*disk.IOThread)
+				newDisk.Driver.IOThread = &ioThreadId
+			}
+		} else if useIOThreads {
 			ioThreadId := defaultIOThread
 			dedicatedThread := false
 			if disk.DedicatedIOThread != nil {
@@ -957,6 +1164,20 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		domain.Spec.Devices.Rng = newRng
 	}
 
+	if vmi.Spec.Domain.Devices.TPM != nil {
+		domain.Spec.Devices.TPM = &TPM{
+			Model: "tpm-tis",
+			Backend: TPMBackend{
+				Type:    "emulator",
+				Version: "2.0",
+			},
+		}
+		// ppc64le guests use the TPM interface of the hypervisor
+		if c.Architecture == "ppc64le" {
+			domain.Spec.Devices.TPM.Model = "tpm-spapr"
+		}
+	}
+
 	isUSBDevicePresent := false
 	if vmi.Spec.Domain.Devices.Inputs != nil {
 		inputDevices := make([]Input, 0)
@@ -974,11 +1195,93 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		domain.Spec.Devices.Inputs = inputDevices
 	}
 
+	// Each redirected USB device is connected through its own socket, which
+	// virt-handler hands out to the usbredir connections of the clients
+	if vmi.Spec.Domain.Devices.ClientPassthrough != nil {
+		for i := 0; i < v1.UsbClientPassthroughMaxNumberOf; i++ {
+			domain.Spec.Devices.Redirs = append(domain.Spec.Devices.Redirs, RedirectedDevice{
+				Type: "unix",
+				Bus:  "usb",
+				Source: RedirectedDeviceSource{
+					Mode: "bind",
+					Path: fmt.Sprintf("/var/run/kubevirt-private/%s/virt-usbredir-%d", vmi.ObjectMeta.UID, i),
+				},
+			})
+		}
+		isUSBDevicePresent = true
+	}
+
+	for _, fs := range vmi.Spec.Domain.Devices.Filesystems {
+		if fs.Virtiofs == nil {
+			continue
+		}
+		volume, exists := volumes[fs.Name]
+		if !exists {
+			return fmt.Errorf("No matching volume with name %s found", fs.Name)
+		}
+		sourceDir, err := getVirtiofsSourceDir(volume, c)
+		if err != nil {
+			return err
+		}
+		// libvirt spawns a virtiofsd process for every filesystem
+		domain.Spec.Devices.Filesystems = append(domain.Spec.Devices.Filesystems, FilesystemDevice{
+			Type:       "mount",
+			AccessMode: "passthrough",
+			Source:     &FilesystemSource{Dir: sourceDir},
+			Target:     &FilesystemTarget{Dir: fs.Name},
+			Driver:     &FilesystemDriver{Type: "virtiofs"},
+			Binary:     &FilesystemBinary{Path: VirtiofsdPath, Xattr: "on"},
+		})
+	}
+	if len(domain.Spec.Devices.Filesystems) > 0 {
+		// virtiofsd accesses the guest memory, so it has to be shared
+		if domain.Spec.MemoryBacking == nil {
+			domain.Spec.MemoryBacking = &MemoryBacking{}
+		}
+		domain.Spec.MemoryBacking.Source = &MemoryBackingSource{Type: "memfd"}
+		domain.Spec.MemoryBacking.Access = &MemoryBackingAccess{Mode: "shared"}
+	}
+
+	if sound := vmi.Spec.Domain.Devices.Sound; sound != nil {
+		model := "ich9"
+		if sound.Model == "ac97" {
+			model = "ac97"
+		}
+		domain.Spec.Devices.SoundCards = []SoundCard{
+			{Model: model, Alias: &Alias{Name: sound.Name}},
+		}
+	}
+
+	if vmi.Spec.Domain.Devices.Panic != nil {
+		model := "isa"
+		if c.Architecture == "ppc64le" {
+			model = "pseries"
+		}
+		domain.Spec.Devices.Panic = &PanicDevice{Model: model}
+		// keep a guest which panicked around, so that its memory can be dumped
+		// before virt-handler stops it
+		domain.Spec.OnCrash = "preserve"
+	}
+
+	// Queues of scsi disks can only be set on a virtio-scsi controller, which all scsi disks share.
+	// It gets the most queues requested. Without tuned scsi disks libvirt keeps its default controller.
+	if scsiQueues > 0 {
+		domain.Spec.Devices.Controllers = append(domain.Spec.Devices.Controllers, Controller{
+			Type:   "scsi",
+			Index:  "0",
+			Model:  "virtio-scsi",
+			Driver: &ControllerDriver{Queues: &scsiQueues},
+		})
+	}
+
 	domain.Spec.Devices.Ballooning = &MemBalloon{}
 	ConvertV1ToAPIBalloning(&vmi.Spec.Domain.Devices, domain.Spec.Devices.Ballooning, c)
+	if domain.Spec.Devices.Ballooning.Model != "none" && vmi.Annotations[v1.FreePageReportingDisabledAnnotation] == "true" {
+		domain.Spec.Devices.Ballooning.FreePageReporting = "off"
+	}
 
-	//usb controller is turned on, only when user specify input device with usb bus,
-	//otherwise it is turned off
+	//usb controller is turned on, only when user specify input device with usb bus
+	//or usb redirection, otherwise it is turned off
 	//In ppc64le usb devices like mouse / keyboard are set by default,
 	//so we can't disable the controller otherwise we run into the following error:
 	//"unsupported configuration: USB is disabled for this domain, but USB devices are present in the domain XML"
@@ -1047,7 +1350,14 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 				log.Log.Reason(err).Error("failed to format domain cputune.")
 				return err
 			}
-			if vmi.Spec.Domain.CPU.IsolateEmulatorThread {
+			if vmi.IsNUMAPassthrough() {
+				if err := formatDomainNUMA(vmi, domain, c); err != nil {
+					log.Log.Reason(err).Error("failed to format domain numa topology.")
+					return err
+				}
+			}
+			switch vmi.GetEmulatorThreadPolicy() {
+			case v1.EmulatorThreadPolicyIsolate:
 				if c.EmulatorThreadCpu == nil {
 					err := fmt.Errorf("no CPUs allocated for the emulation thread")
 					log.Log.Reason(err).Error("failed to format emulation thread pin")
@@ -1055,14 +1365,28 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 
 				}
 				appendDomainEmulatorThreadPin(domain, *c.EmulatorThreadCpu)
+			case v1.EmulatorThreadPolicyVCPU0:
+				// share the pCPU of the first vCPU
+				appendDomainEmulatorThreadPin(domain, c.CPUSet[0])
 			}
-			if useIOThreads {
+			if manualIOThreads {
+				if err := formatDomainManualIOThreadPin(vmi, domain, c); err != nil {
+					log.Log.Reason(err).Error("failed to format domain iothread pinning.")
+					return err
+				}
+			} else if useIOThreads {
 				if err := formatDomainIOThreadPin(vmi, domain, c); err != nil {
 					log.Log.Reason(err).Error("failed to format domain iothread pinning.")
 					return err
 				}
 
 			}
+			if vmi.IsRealtimeEnabled() {
+				if err := formatDomainRealtime(vmi, domain, c); err != nil {
+					log.Log.Reason(err).Error("failed to format domain realtime scheduling.")
+					return err
+				}
+			}
 		}
 	}
 
@@ -1095,6 +1419,16 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		}
 	}
 
+	// Append the PCI addresses and mediated devices allocated by the device plugins
+	// for the host devices and for the GPUs which are backed by mediated devices
+	if requests := getHostDeviceRequests(vmi, c); len(requests) > 0 {
+		hostDevices, err := createHostDevices(requests, c.HostDevices, c.MediatedDevices)
+		if err != nil {
+			return err
+		}
+		domain.Spec.Devices.HostDevices = append(domain.Spec.Devices.HostDevices, hostDevices...)
+	}
+
 	if vmi.Spec.Domain.CPU == nil || vmi.Spec.Domain.CPU.Model == "" {
 		domain.Spec.CPU.Mode = v1.CPUModeHostModel
 	}
@@ -1130,6 +1464,16 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 				},
 			},
 		}
+
+		if serialConsoleLog := vmi.Spec.Domain.Devices.SerialConsoleLog; serialConsoleLog != nil {
+			if serialConsoleLog.VolumeName != "" && c.IsBlockPVC[serialConsoleLog.VolumeName] {
+				return fmt.Errorf("serial console log volume %s must not be a block device", serialConsoleLog.VolumeName)
+			}
+			domain.Spec.Devices.Serials[0].Log = &SerialLog{
+				File:   GetSerialConsoleLogPath(vmi.UID, serialConsoleLog.VolumeName),
+				Append: "on",
+			}
+		}
 	}
 
 	if vmi.Spec.Domain.Devices.AutoattachGraphicsDevice == nil || *vmi.Spec.Domain.Devices.AutoattachGraphicsDevice == true {
@@ -1186,6 +1530,13 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		sriovPciAddresses[key] = append([]string{}, value...)
 	}
 
+	failoverStandbys := make(map[string]bool)
+	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
+		if iface.SRIOV != nil && iface.SRIOV.FailoverStandby != "" {
+			failoverStandbys[iface.SRIOV.FailoverStandby] = true
+		}
+	}
+
 	for _, iface := range vmi.Spec.Domain.Devices.Interfaces {
 		net, isExist := networks[iface.Name]
 		if !isExist {
@@ -1204,23 +1555,47 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 				return err
 			}
 
+			sourceAddress := &Address{
+				Type:     "pci",
+				Domain:   "0x" + dbsfFields[0],
+				Bus:      "0x" + dbsfFields[1],
+				Slot:     "0x" + dbsfFields[2],
+				Function: "0x" + dbsfFields[3],
+			}
+			log.Log.Infof("SR-IOV PCI device allocated: %s", pciAddr)
+
+			if iface.SRIOV.FailoverStandby != "" {
+				// the VF gets detached for live migrations, the guest keeps its traffic
+				// flowing through the virtio standby interface in the meantime
+				domainIface := Interface{
+					Type:    "hostdev",
+					Managed: "yes",
+					Source:  InterfaceSource{Address: sourceAddress},
+					Alias:   &Alias{Name: iface.Name},
+					Teaming: &Teaming{Type: "transient", Persistent: UserAliasPrefix + iface.SRIOV.FailoverStandby},
+				}
+				if iface.MacAddress != "" {
+					domainIface.MAC = &MAC{MAC: iface.MacAddress}
+				}
+				if iface.BootOrder != nil {
+					domainIface.BootOrder = &BootOrder{Order: *iface.BootOrder}
+				}
+				domain.Spec.Devices.Interfaces = append(domain.Spec.Devices.Interfaces, domainIface)
+				continue
+			}
+
 			hostDev := HostDevice{
 				Source: HostDeviceSource{
-					Address: &Address{
-						Type:     "pci",
-						Domain:   "0x" + dbsfFields[0],
-						Bus:      "0x" + dbsfFields[1],
-						Slot:     "0x" + dbsfFields[2],
-						Function: "0x" + dbsfFields[3],
-					},
+					Address: sourceAddress,
 				},
 				Type:    "pci",
 				Managed: "yes",
+				// lets virt-handler find the VF of the interface in the domain
+				Alias: &Alias{Name: iface.Name},
 			}
 			if iface.BootOrder != nil {
 				hostDev.BootOrder = &BootOrder{Order: *iface.BootOrder}
 			}
-			log.Log.Infof("SR-IOV PCI device allocated: %s", pciAddr)
 			domain.Spec.Devices.HostDevices = append(domain.Spec.Devices.HostDevices, hostDev)
 		} else {
 			ifaceType := getInterfaceType(&iface)
@@ -1276,6 +1651,38 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 				if iface.BootOrder != nil {
 					domainIface.BootOrder = &BootOrder{Order: *iface.BootOrder}
 				}
+				if iface.Bandwidth != nil {
+					domainIface.BandWidth = Convert_v1_InterfaceBandwidth_To_api_BandWidth(iface.Bandwidth)
+				}
+				if failoverStandbys[iface.Name] {
+					domainIface.Teaming = &Teaming{Type: "persistent"}
+				}
+			} else if iface.Macvtap != nil {
+				// the macvtap device is created on the host NIC by the device plugin and
+				// moved into the pod by the CNI plugin, libvirt only opens its tap device
+				domainIface.Type = "ethernet"
+				domainIface.Target = &InterfaceTarget{
+					Device:  fmt.Sprintf("net%d", cniNetworks[iface.Name]),
+					Managed: "no",
+				}
+				if iface.MacAddress != "" {
+					domainIface.MAC = &MAC{MAC: iface.MacAddress}
+				}
+				if iface.BootOrder != nil {
+					domainIface.BootOrder = &BootOrder{Order: *iface.BootOrder}
+				}
+			} else if iface.Passt != nil {
+				// libvirt spawns passt, which runs unprivileged and hands the
+				// addresses of the pod interface to the guest
+				domainIface.Type = "user"
+				domainIface.Backend = &InterfaceBackend{Type: "passt"}
+				domainIface.PortForward = convertPasstPorts(iface.Ports)
+				if iface.MacAddress != "" {
+					domainIface.MAC = &MAC{MAC: iface.MacAddress}
+				}
+				if iface.BootOrder != nil {
+					domainIface.BootOrder = &BootOrder{Order: *iface.BootOrder}
+				}
 			} else if iface.Slirp != nil {
 				domainIface.Type = "user"
 
@@ -1300,8 +1707,7 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 	}
 
 	// Add Ignition Command Line if present
-	ignitiondata, _ := vmi.Annotations[v1.IgnitionAnnotation]
-	if ignitiondata != "" && strings.Contains(ignitiondata, "ignition") {
+	if ignition.HasIgnitionSource(vmi) {
 		if domain.Spec.QEMUCmd == nil {
 			domain.Spec.QEMUCmd = &Commandline{}
 		}
@@ -1314,6 +1720,11 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 		domain.Spec.QEMUCmd.QEMUArg = append(domain.Spec.QEMUCmd.QEMUArg, Arg{Value: fmt.Sprintf("name=opt/com.coreos/config,file=%s", ignitionpath)})
 	}
 
+	if vmi.Spec.Domain.LaunchSecurity != nil && vmi.Spec.Domain.LaunchSecurity.SEV != nil {
+		domain.Spec.LaunchSecurity = convertSEV(vmi.Spec.Domain.LaunchSecurity.SEV)
+		setVirtioIOMMU(&domain.Spec)
+	}
+
 	if val := vmi.Annotations[v1.PlacePCIDevicesOnRootComplex]; val == "true" {
 		if err := PlacePCIDevicesOnRootComplex(&domain.Spec); err != nil {
 			return err
@@ -1323,6 +1734,65 @@ func Convert_v1_VirtualMachine_To_api_Domain(vmi *v1.VirtualMachineInstance, dom
 	return nil
 }
 
+// convertPasstPorts groups the ports of an interface by protocol, passt only
+// forwards the listed ports of the pod to the guest
+func convertPasstPorts(ports []v1.Port) []InterfacePortForward {
+	var portForwards []InterfacePortForward
+	for _, proto := range []string{"TCP", "UDP"} {
+		var ranges []InterfacePortForwardRange
+		for _, port := range ports {
+			protocol := strings.ToUpper(port.Protocol)
+			if protocol == "" {
+				protocol = "TCP"
+			}
+			if protocol == proto {
+				ranges = append(ranges, InterfacePortForwardRange{Start: uint(port.Port)})
+			}
+		}
+		if len(ranges) > 0 {
+			portForwards = append(portForwards, InterfacePortForward{Proto: strings.ToLower(proto), Ranges: ranges})
+		}
+	}
+	return portForwards
+}
+
+func convertSEV(sev *v1.SEV) *LaunchSecurity {
+	policy := SEVPolicyNoDebug | SEVPolicyNoKeysSharing
+	if sev.Policy != nil && sev.Policy.EncryptedState != nil && *sev.Policy.EncryptedState {
+		policy |= SEVPolicyEncryptedState
+	}
+	return &LaunchSecurity{
+		Type:   "sev",
+		Policy: fmt.Sprintf("0x%04x", policy),
+	}
+}
+
+// setVirtioIOMMU makes the virtio devices use the IOMMU platform, since with
+// encrypted memory they can only access the buffers the guest shares with the host
+func setVirtioIOMMU(domain *DomainSpec) {
+	for i := range domain.Devices.Disks {
+		disk := &domain.Devices.Disks[i]
+		if disk.Target.Bus == "virtio" && disk.Driver != nil {
+			disk.Driver.IOMMU = "on"
+		}
+	}
+	for i := range domain.Devices.Interfaces {
+		iface := &domain.Devices.Interfaces[i]
+		if iface.Model != nil && iface.Model.Type == "virtio" {
+			if iface.Driver == nil {
+				iface.Driver = &InterfaceDriver{}
+			}
+			iface.Driver.IOMMU = "on"
+		}
+	}
+	if domain.Devices.Ballooning != nil && domain.Devices.Ballooning.Model == "virtio" {
+		domain.Devices.Ballooning.Driver = &MemBalloonDriver{IOMMU: "on"}
+	}
+	if domain.Devices.Rng != nil {
+		domain.Devices.Rng.Driver = &RngDriver{IOMMU: "on"}
+	}
+}
+
 func getVirtualMemory(vmi *v1.VirtualMachineInstance) *resource.Quantity {
 	// In case that guest memory is explicitly set, return it
 	if vmi.Spec.Domain.Memory != nil && vmi.Spec.Domain.Memory.Guest != nil {
@@ -1396,6 +1866,52 @@ func formatDomainCPUTune(vmi *v1.VirtualMachineInstance, domain *Domain, c *Conv
 	return nil
 }
 
+// formatDomainNUMA creates a guest NUMA cell for every host NUMA node the vCPUs
+// are pinned to and keeps the memory of each cell on its host NUMA node
+func formatDomainNUMA(vmi *v1.VirtualMachineInstance, domain *Domain, c *ConverterContext) error {
+	if len(c.HostNUMANodes) == 0 {
+		return fmt.Errorf("no host NUMA topology available")
+	}
+	if vmi.Spec.Domain.Memory == nil || vmi.Spec.Domain.Memory.Hugepages == nil {
+		return fmt.Errorf("NUMA passthrough requires hugepages")
+	}
+	pageSize, err := resource.ParseQuantity(vmi.Spec.Domain.Memory.Hugepages.PageSize)
+	if err != nil {
+		return err
+	}
+	vcpus := int(calculateRequestedVCPUs(domain.Spec.CPU.Topology))
+	cells, err := hardware.GetNUMACells(c.HostNUMANodes, c.CPUSet[:vcpus], int64(domain.Spec.Memory.Value), pageSize.Value())
+	if err != nil {
+		return err
+	}
+
+	domain.Spec.CPU.NUMA = &NUMA{}
+	domain.Spec.NUMATune = &NUMATune{
+		Memory: NUMATuneMemory{Mode: "strict"},
+	}
+	var hostNodes []string
+	for idx, cell := range cells {
+		var cellCPUs []string
+		for _, vcpu := range cell.VCPUs {
+			cellCPUs = append(cellCPUs, strconv.Itoa(vcpu))
+		}
+		domain.Spec.CPU.NUMA.Cells = append(domain.Spec.CPU.NUMA.Cells, NUMACell{
+			ID:     strconv.Itoa(idx),
+			CPUs:   strings.Join(cellCPUs, ","),
+			Memory: uint64(cell.Memory),
+			Unit:   "b",
+		})
+		domain.Spec.NUMATune.MemNodes = append(domain.Spec.NUMATune.MemNodes, MemNode{
+			CellID:  uint32(idx),
+			Mode:    "strict",
+			NodeSet: strconv.Itoa(cell.HostNode),
+		})
+		hostNodes = append(hostNodes, strconv.Itoa(cell.HostNode))
+	}
+	domain.Spec.NUMATune.Memory.NodeSet = strings.Join(hostNodes, ",")
+	return nil
+}
+
 func appendDomainEmulatorThreadPin(domain *Domain, allocatedCpu int) {
 	emulatorThread := CPUEmulatorPin{
 		CPUSet: strconv.Itoa(allocatedCpu),
@@ -1414,7 +1930,7 @@ func formatDomainIOThreadPin(vmi *v1.VirtualMachineInstance, domain *Domain, c *
 	iothreads := int(domain.Spec.IOThreads.IOThreads)
 	vcpus := int(calculateRequestedVCPUs(domain.Spec.CPU.Topology))
 
-	if vmi.IsCPUDedicated() && vmi.Spec.Domain.CPU.IsolateEmulatorThread {
+	if vmi.IsCPUDedicated() && vmi.GetEmulatorThreadPolicy() == v1.EmulatorThreadPolicyIsolate {
 		// pin the IOThread on the same pCPU as the emulator thread
 		cpuset := fmt.Sprintf("%d", *c.EmulatorThreadCpu)
 		appendDomainIOThreadPin(domain, uint(1), cpuset)
@@ -1447,6 +1963,118 @@ func formatDomainIOThreadPin(vmi *v1.VirtualMachineInstance, domain *Domain, c *
 	return nil
 }
 
+func isManualIOThreadsPolicy(vmi *v1.VirtualMachineInstance) bool {
+	return vmi.Spec.Domain.IOThreadsPolicy != nil && *vmi.Spec.Domain.IOThreadsPolicy == v1.IOThreadsPolicyManual
+}
+
+// formatDomainManualIOThreadPin pins the explicitly defined IOThreads to the pCPUs of the requested vCPUs
+func formatDomainManualIOThreadPin(vmi *v1.VirtualMachineInstance, domain *Domain, c *ConverterContext) error {
+	for _, ioThread := range vmi.Spec.Domain.IOThreads {
+		if ioThread.CPUs == "" {
+			continue
+		}
+		vcpus, err := hardware.ParseCPUSetLine(ioThread.CPUs)
+		if err != nil {
+			return fmt.Errorf("invalid cpus of iothread %d: %v", ioThread.ID, err)
+		}
+		var pcpus []string
+		for _, vcpu := range vcpus {
+			if vcpu < 0 || vcpu >= len(c.CPUSet) {
+				return fmt.Errorf("iothread %d is pinned to vCPU %d, which has no allocated CPU", ioThread.ID, vcpu)
+			}
+			pcpus = append(pcpus, strconv.Itoa(c.CPUSet[vcpu]))
+		}
+		appendDomainIOThreadPin(domain, uint(ioThread.ID), strings.Join(pcpus, ","))
+	}
+	return nil
+}
+
+// formatDomainRealtime schedules the realtime vCPUs with the fifo policy and
+// moves the emulator thread and the IOThreads off their pCPUs
+func formatDomainRealtime(vmi *v1.VirtualMachineInstance, domain *Domain, c *ConverterContext) error {
+	realtime := vmi.Spec.Domain.CPU.Realtime
+	vcpus := int(calculateRequestedVCPUs(domain.Spec.CPU.Topology))
+
+	realtimeVCPUs := map[int]bool{}
+	if realtime.Mask == "" {
+		for vcpu := 0; vcpu < vcpus; vcpu++ {
+			realtimeVCPUs[vcpu] = true
+		}
+	} else {
+		mask, err := hardware.ParseCPUSetLine(realtime.Mask)
+		if err != nil {
+			return fmt.Errorf("invalid realtime mask: %v", err)
+		}
+		for _, vcpu := range mask {
+			if vcpu < 0 || vcpu >= vcpus {
+				return fmt.Errorf("realtime mask contains vCPU %d, but the VMI has %d vCPUs", vcpu, vcpus)
+			}
+			realtimeVCPUs[vcpu] = true
+		}
+	}
+
+	var sched []string
+	realtimeCPUs := map[int]bool{}
+	var housekeeping []string
+	for vcpu := 0; vcpu < vcpus; vcpu++ {
+		if realtimeVCPUs[vcpu] {
+			sched = append(sched, strconv.Itoa(vcpu))
+			realtimeCPUs[c.CPUSet[vcpu]] = true
+		} else {
+			housekeeping = append(housekeeping, strconv.Itoa(c.CPUSet[vcpu]))
+		}
+	}
+	if vmi.GetEmulatorThreadPolicy() == v1.EmulatorThreadPolicyIsolate {
+		// the isolated pCPU is the best place for everything besides the vCPUs
+		housekeeping = []string{strconv.Itoa(*c.EmulatorThreadCpu)}
+	}
+	if len(housekeeping) == 0 {
+		return fmt.Errorf("realtime vCPUs require an isolated emulator thread or at least one non-realtime vCPU")
+	}
+
+	priority := uint32(1)
+	if realtime.Priority != nil {
+		priority = *realtime.Priority
+	}
+	domain.Spec.CPUTune.VCPUSched = []CPUTuneVCPUSched{{
+		VCPUs:     strings.Join(sched, ","),
+		Scheduler: "fifo",
+		Priority:  priority,
+	}}
+
+	domain.Spec.CPUTune.EmulatorPin = &CPUEmulatorPin{CPUSet: strings.Join(housekeeping, ",")}
+
+	if domain.Spec.IOThreads == nil {
+		return nil
+	}
+	pins := map[uint]string{}
+	for _, pin := range domain.Spec.CPUTune.IOThreadPin {
+		pins[pin.IOThread] = pin.CPUSet
+	}
+	domain.Spec.CPUTune.IOThreadPin = nil
+	for thread := uint(1); thread <= domain.Spec.IOThreads.IOThreads; thread++ {
+		cpuset, pinned := pins[thread]
+		if !pinned || cpusetOverlaps(cpuset, realtimeCPUs) {
+			cpuset = strings.Join(housekeeping, ",")
+		}
+		appendDomainIOThreadPin(domain, thread, cpuset)
+	}
+	return nil
+}
+
+func cpusetOverlaps(cpuset string, cpus map[int]bool) bool {
+	parsed, err := hardware.ParseCPUSetLine(cpuset)
+	if err != nil {
+		return true
+	}
+	for _, cpu := range parsed {
+		if cpus[cpu] {
+			return true
+		}
+	}
+	return false
+}
+
 func createSlirpNetwork(iface v1.Interface, network v1.Network, domain *Domain) error {
 	qemuArg := Arg{Value: fmt.Sprintf("user,id=%s", iface.Name)}
 
@@ -1633,6 +2261,59 @@ func createHostDevicesFromPCIAddresses(pcis []string) ([]HostDevice, error) {
 	return hds, nil
 }
 
+// getHostDeviceRequests returns the host devices of the vmi, and its GPUs
+// for which mediated devices were allocated
+func getHostDeviceRequests(vmi *v1.VirtualMachineInstance, c *ConverterContext) []v1.HostDevice {
+	var requests []v1.HostDevice
+	for _, gpu := range vmi.Spec.Domain.Devices.GPUs {
+		if len(c.MediatedDevices[gpu.DeviceName]) > 0 {
+			requests = append(requests, v1.HostDevice{Name: gpu.Name, DeviceName: gpu.DeviceName})
+		}
+	}
+	return append(requests, vmi.Spec.Domain.Devices.HostDevices...)
+}
+
+// popDeviceID removes the first allocated device of a resource
+func popDeviceID(resourceName string, devices map[string][]string) (string, bool) {
+	if len(devices[resourceName]) == 0 {
+		return "", false
+	}
+	deviceID := devices[resourceName][0]
+	devices[resourceName] = devices[resourceName][1:]
+	return deviceID, true
+}
+
+func copyDeviceMap(devices map[string][]string) map[string][]string {
+	devicesCopy := map[string][]string{}
+	for resourceName, deviceIDs := range devices {
+		devicesCopy[resourceName] = append([]string{}, deviceIDs...)
+	}
+	return devicesCopy
+}
+
+// createHostDevices assigns one of the allocated PCI addresses or mediated devices of its resource to every host device
+func createHostDevices(vmiHostDevices []v1.HostDevice, resourceToAddresses map[string][]string, resourceToMdevs map[string][]string) ([]HostDevice, error) {
+	var hds []HostDevice
+	availableAddresses := copyDeviceMap(resourceToAddresses)
+	availableMdevs := copyDeviceMap(resourceToMdevs)
+	for _, vmiHostDev := range vmiHostDevices {
+		var hostDevs []HostDevice
+		var err error
+		if address, exists := popDeviceID(vmiHostDev.DeviceName, availableAddresses); exists {
+			hostDevs, err = createHostDevicesFromPCIAddresses([]string{address})
+		} else if uuid, exists := popDeviceID(vmiHostDev.DeviceName, availableMdevs); exists {
+			hostDevs, err = createHostDevicesFromMdevUUIDList([]string{uuid})
+		} else {
+			return nil, fmt.Errorf("no device of resource %s is allocated for host device %s", vmiHostDev.DeviceName, vmiHostDev.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure host device %s: %v", vmiHostDev.Name, err)
+		}
+		hds = append(hds, hostDevs...)
+	}
+	return hds, nil
+}
+
 func createHostDevicesFromMdevUUIDList(mdevUuidList []string) ([]HostDevice, error) {
 	var hds []HostDevice
 	for _, mdevUuid := range mdevUuidList {
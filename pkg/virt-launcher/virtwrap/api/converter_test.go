@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -34,7 +35,10 @@ import (
 	k8smeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "kubevirt.io/client-go/api/v1"
+	containerdisk "kubevirt.io/kubevirt/pkg/container-disk"
 	cmdv1 "kubevirt.io/kubevirt/pkg/handler-launcher-com/cmd/v1"
+	"kubevirt.io/kubevirt/pkg/ignition"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 )
 
 var _ = Describe("Converter", func() {
@@ -1366,6 +1370,21 @@ var _ = Describe("Converter", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		table.DescribeTable("should convert the watchdog action", func(action v1.WatchdogAction, expectedAction string) {
+			watchdog := &Watchdog{}
+			source := &v1.Watchdog{
+				Name:           "mywatchdog",
+				WatchdogDevice: v1.WatchdogDevice{I6300ESB: &v1.I6300ESBWatchdog{Action: action}},
+			}
+			Expect(Convert_v1_Watchdog_To_api_Watchdog(source, watchdog, &ConverterContext{})).To(Succeed())
+			Expect(watchdog.Model).To(Equal("i6300esb"))
+			Expect(watchdog.Action).To(Equal(expectedAction))
+		},
+			table.Entry("reset", v1.WatchdogActionReset, "reset"),
+			table.Entry("inject-nmi", v1.WatchdogActionInjectNMI, "inject-nmi"),
+			table.Entry("restart-pod by pausing the guest for virt-handler", v1.WatchdogActionRestartPod, "pause"),
+		)
+
 		It("should convert hugepages", func() {
 			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
 			vmi.Spec.Domain.Memory = &v1.Memory{
@@ -1378,6 +1397,19 @@ var _ = Describe("Converter", func() {
 			Expect(domainSpec.Memory.Unit).To(Equal("b"))
 		})
 
+		It("should prevent KSM from merging the memory of vmis which opt out", func() {
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Annotations = map[string]string{v1.KSMDisabledAnnotation: "true"}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.MemoryBacking.NoSharePages).ToNot(BeNil())
+		})
+
+		It("should allow KSM to merge the memory of vmis by default", func() {
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.MemoryBacking).To(BeNil())
+		})
+
 		It("should use guest memory instead of requested memory if present", func() {
 			guestMemory := resource.MustParse("123Mi")
 			vmi.Spec.Domain.Memory = &v1.Memory{
@@ -1391,6 +1423,27 @@ var _ = Describe("Converter", func() {
 			Expect(domainSpec.Memory.Unit).To(Equal("b"))
 		})
 
+		It("should enable free page reporting of the memory balloon", func() {
+			vmi.Spec.Domain.Devices.MemBalloon = &v1.MemBalloon{FreePageReporting: True()}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Ballooning.FreePageReporting).To(Equal("on"))
+		})
+
+		It("should disable free page reporting of vmis annotated for it", func() {
+			vmi.Spec.Domain.Devices.MemBalloon = &v1.MemBalloon{FreePageReporting: True()}
+			vmi.Annotations = map[string]string{v1.FreePageReportingDisabledAnnotation: "true"}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Ballooning.FreePageReporting).To(Equal("off"))
+		})
+
+		It("should not configure free page reporting without a memory balloon", func() {
+			vmi.Spec.Domain.Devices.AutoattachMemBalloon = False()
+			vmi.Spec.Domain.Devices.MemBalloon = &v1.MemBalloon{FreePageReporting: True()}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Ballooning.Model).To(Equal("none"))
+			Expect(domainSpec.Devices.Ballooning.FreePageReporting).To(BeEmpty())
+		})
+
 		It("should not add RNG when not present", func() {
 			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
 			Expect(domainSpec.Devices.Rng).To(BeNil())
@@ -1402,6 +1455,109 @@ var _ = Describe("Converter", func() {
 			Expect(domainSpec.Devices.Rng).ToNot(BeNil())
 		})
 
+		It("should not add a TPM when not present", func() {
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.TPM).To(BeNil())
+		})
+
+		It("should add an emulated TPM 2.0 when present", func() {
+			vmi.Spec.Domain.Devices.TPM = &v1.TPMDevice{}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.TPM).To(Equal(&TPM{
+				Model:   "tpm-tis",
+				Backend: TPMBackend{Type: "emulator", Version: "2.0"},
+			}))
+		})
+
+		Context("with virtio-fs", func() {
+			BeforeEach(func() {
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes,
+					v1.Volume{Name: "shared-config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: k8sv1.LocalObjectReference{Name: "config"}}}},
+					v1.Volume{Name: "shared-claim", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: "claim"}}},
+				)
+			})
+
+			It("should share the volumes and the guest memory", func() {
+				vmi.Spec.Domain.Devices.Filesystems = []v1.Filesystem{
+					{Name: "shared-config", Virtiofs: &v1.FilesystemVirtiofs{}},
+					{Name: "shared-claim", Virtiofs: &v1.FilesystemVirtiofs{}},
+				}
+				domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+				Expect(domainSpec.Devices.Filesystems).To(Equal([]FilesystemDevice{
+					{
+						Type:       "mount",
+						AccessMode: "passthrough",
+						Source:     &FilesystemSource{Dir: "/var/run/kubevirt-private/config-map/shared-config"},
+						Target:     &FilesystemTarget{Dir: "shared-config"},
+						Driver:     &FilesystemDriver{Type: "virtiofs"},
+						Binary:     &FilesystemBinary{Path: VirtiofsdPath, Xattr: "on"},
+					},
+					{
+						Type:       "mount",
+						AccessMode: "passthrough",
+						Source:     &FilesystemSource{Dir: "/var/run/kubevirt-private/vmi-disks/shared-claim"},
+						Target:     &FilesystemTarget{Dir: "shared-claim"},
+						Driver:     &FilesystemDriver{Type: "virtiofs"},
+						Binary:     &FilesystemBinary{Path: VirtiofsdPath, Xattr: "on"},
+					},
+				}))
+				Expect(domainSpec.MemoryBacking.Source).To(Equal(&MemoryBackingSource{Type: "memfd"}))
+				Expect(domainSpec.MemoryBacking.Access).To(Equal(&MemoryBackingAccess{Mode: "shared"}))
+			})
+
+			It("should reject a block mode claim", func() {
+				vmi.Spec.Domain.Devices.Filesystems = []v1.Filesystem{{Name: "shared-claim", Virtiofs: &v1.FilesystemVirtiofs{}}}
+				c.IsBlockPVC = map[string]bool{"shared-claim": true}
+				domain := &Domain{}
+				Expect(Convert_v1_VirtualMachine_To_api_Domain(vmi, domain, c)).ToNot(Succeed())
+			})
+		})
+
+		table.DescribeTable("should emulate a sound card", func(model string, expectedModel string) {
+			vmi.Spec.Domain.Devices.Sound = &v1.SoundDevice{Name: "audio", Model: model}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.SoundCards).To(Equal([]SoundCard{{Model: expectedModel, Alias: &Alias{Name: "audio"}}}))
+		},
+			table.Entry("defaulting to ich9", "", "ich9"),
+			table.Entry("with ich9", "ich9", "ich9"),
+			table.Entry("with ac97", "ac97", "ac97"),
+		)
+
+		table.DescribeTable("should add a panic device", func(arch string, expectedModel string) {
+			vmi.Spec.Domain.Devices.Panic = &v1.PanicDevice{CrashDumpClaimName: "crash-dumps"}
+			c.Architecture = arch
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Panic).To(Equal(&PanicDevice{Model: expectedModel}))
+			Expect(domainSpec.OnCrash).To(Equal("preserve"))
+		},
+			table.Entry("on amd64", "amd64", "isa"),
+			table.Entry("on ppc64le", "ppc64le", "pseries"),
+		)
+
+		It("should not add a panic device when it is not requested", func() {
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Panic).To(BeNil())
+			Expect(domainSpec.OnCrash).To(BeEmpty())
+		})
+
+		It("should not add redirected devices when client passthrough is not requested", func() {
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Redirs).To(BeEmpty())
+		})
+
+		It("should add usb redirection sockets and a usb controller for client passthrough", func() {
+			vmi.Spec.Domain.Devices.ClientPassthrough = &v1.ClientPassthroughDevices{}
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Redirs).To(HaveLen(v1.UsbClientPassthroughMaxNumberOf))
+			for i, redir := range domainSpec.Devices.Redirs {
+				Expect(redir.Type).To(Equal("unix"))
+				Expect(redir.Bus).To(Equal("usb"))
+				Expect(redir.Source.Mode).To(Equal("bind"))
+				Expect(redir.Source.Path).To(Equal(fmt.Sprintf("/var/run/kubevirt-private/%s/virt-usbredir-%d", vmi.UID, i)))
+			}
+			Expect(domainSpec.Devices.Controllers).To(ContainElement(Controller{Type: "usb", Index: "0", Model: "qemu-xhci"}))
+		})
+
 	})
 	Context("Network convert", func() {
 		var vmi *v1.VirtualMachineInstance
@@ -1613,6 +1769,28 @@ var _ = Describe("Converter", func() {
 			Expect(domain.Spec.Devices.Interfaces[0].BootOrder.Order).To(Equal(uint(bootOrder)))
 			Expect(domain.Spec.Devices.Interfaces[1].BootOrder).To(BeNil())
 		})
+		It("should set the bandwidth limits of an interface", func() {
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			peak := uint32(2048)
+			iface := v1.DefaultBridgeNetworkInterface()
+			iface.Bandwidth = &v1.InterfaceBandwidth{
+				Ingress: &v1.BandwidthLimit{Average: 1024, Peak: &peak},
+				Egress:  &v1.BandwidthLimit{Average: 512},
+			}
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{*iface}
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.Devices.Interfaces).To(HaveLen(1))
+			Expect(domain.Spec.Devices.Interfaces[0].BandWidth).To(Equal(&BandWidth{
+				Inbound:  &BandWidthLimit{Average: 1024, Peak: 2048},
+				Outbound: &BandWidthLimit{Average: 512},
+			}))
+
+			xml, err := xml.Marshal(domain.Spec.Devices.Interfaces[0].BandWidth)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(xml)).To(Equal(`<BandWidth><inbound average="1024" peak="2048"></inbound><outbound average="512"></outbound></BandWidth>`))
+		})
 		It("Should create network configuration for masquerade interface", func() {
 			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
 			name1 := "Name"
@@ -1728,6 +1906,49 @@ var _ = Describe("Converter", func() {
 			table.Entry("and add the serial console if it is set to true", True(), 1),
 			table.Entry("and not add the serial console if it is set to false", False(), 0),
 		)
+
+		table.DescribeTable("should log the serial console", func(volumeName string, expectedLogFile string) {
+			vmi := v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "default",
+					UID:       "1234",
+				},
+				Spec: v1.VirtualMachineInstanceSpec{
+					Domain: v1.DomainSpec{
+						Devices: v1.Devices{
+							SerialConsoleLog: &v1.SerialConsoleLog{VolumeName: volumeName},
+						},
+					},
+				},
+			}
+			domain := vmiToDomain(&vmi, &ConverterContext{UseEmulation: true})
+			Expect(domain.Spec.Devices.Serials).To(HaveLen(1))
+			Expect(domain.Spec.Devices.Serials[0].Log).To(Equal(&SerialLog{File: expectedLogFile, Append: "on"}))
+		},
+			table.Entry("to the pod logs", "", "/var/run/kubevirt-private/1234/virt-serial0.log"),
+			table.Entry("to a volume", "logs", "/var/run/kubevirt-private/vmi-disks/logs/serial-console.log"),
+		)
+
+		It("should refuse to log the serial console to a block volume", func() {
+			vmi := v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "default",
+					UID:       "1234",
+				},
+				Spec: v1.VirtualMachineInstanceSpec{
+					Domain: v1.DomainSpec{
+						Devices: v1.Devices{
+							SerialConsoleLog: &v1.SerialConsoleLog{VolumeName: "logs"},
+						},
+					},
+				},
+			}
+			domain := &Domain{}
+			c := &ConverterContext{UseEmulation: true, IsBlockPVC: map[string]bool{"logs": true}}
+			Expect(Convert_v1_VirtualMachine_To_api_Domain(&vmi, domain, c)).To(MatchError("serial console log volume logs must not be a block device"))
+		})
 	})
 
 	Context("IOThreads", func() {
@@ -1984,7 +2205,195 @@ var _ = Describe("Converter", func() {
 			Expect(*(domain.Spec.Devices.Disks[0].Driver.Queues)).To(Equal(expectedQueues),
 				"expected number of queues to equal number of requested CPUs")
 		})
+
+		It("should honor the queue settings of a virtio disk", func() {
+			count := uint32(4)
+			size := uint32(512)
+			vmi.Spec.Domain.Devices.BlockMultiQueue = nil
+			vmi.Spec.Domain.Devices.Disks[0].Queues = &v1.DiskQueues{Count: &count, Size: &size}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(*domain.Spec.Devices.Disks[0].Driver.Queues).To(Equal(uint(4)))
+			Expect(*domain.Spec.Devices.Disks[0].Driver.QueueSize).To(Equal(uint(512)))
+		})
+
+		It("should default the queue count of a tuned disk to the number of vCPUs", func() {
+			size := uint32(512)
+			vmi.Spec.Domain.Devices.BlockMultiQueue = nil
+			vmi.Spec.Domain.Devices.Disks[0].Queues = &v1.DiskQueues{Size: &size}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(*domain.Spec.Devices.Disks[0].Driver.Queues).To(Equal(uint(2)))
+		})
+
+		It("should set the queues of scsi disks on a virtio-scsi controller", func() {
+			count := uint32(4)
+			vmi.Spec.Domain.Devices.Disks[0].Disk.Bus = "scsi"
+			vmi.Spec.Domain.Devices.Disks[0].Queues = &v1.DiskQueues{Count: &count}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(domain.Spec.Devices.Disks[0].Driver.Queues).To(BeNil())
+			Expect(domain.Spec.Devices.Controllers).To(ContainElement(Controller{
+				Type:   "scsi",
+				Index:  "0",
+				Model:  "virtio-scsi",
+				Driver: &ControllerDriver{Queues: &[]uint{4}[0]},
+			}))
+		})
+
+		It("should set the I/O limits of a disk", func() {
+			readIops := uint64(100)
+			totalBytes := uint64(1 << 20)
+			vmi.Spec.Domain.Devices.Disks[0].IOTune = &v1.DiskIOTune{ReadIopsSec: &readIops, TotalBytesSec: &totalBytes}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(domain.Spec.Devices.Disks[0].IOTune).To(Equal(&DiskIOTune{ReadIopsSec: 100, TotalBytesSec: 1 << 20}))
+		})
+
+		It("should keep the default scsi controller for scsi disks without tuned queues", func() {
+			vmi.Spec.Domain.Devices.Disks[0].Disk.Bus = "scsi"
+			vmi.Spec.Domain.Devices.Disks[0].Queues = &v1.DiskQueues{}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			for _, controller := range domain.Spec.Devices.Controllers {
+				Expect(controller.Type).ToNot(Equal("scsi"))
+			}
+		})
+
+		It("should give the virtio-scsi controller the most queues of the tuned scsi disks", func() {
+			count := uint32(4)
+			vmi.Spec.Domain.Devices.Disks[0].Disk.Bus = "scsi"
+			vmi.Spec.Domain.Devices.Disks[0].Queues = &v1.DiskQueues{Count: &count}
+			vmi.Spec.Domain.Devices.Disks = append(vmi.Spec.Domain.Devices.Disks, v1.Disk{
+				Name:       "scsidisk",
+				DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "scsi"}},
+			})
+			vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+				Name:         "scsidisk",
+				VolumeSource: v1.VolumeSource{EmptyDisk: &v1.EmptyDiskSource{}},
+			})
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(domain.Spec.Devices.Controllers).To(ContainElement(Controller{
+				Type:   "scsi",
+				Index:  "0",
+				Model:  "virtio-scsi",
+				Driver: &ControllerDriver{Queues: &[]uint{4}[0]},
+			}))
+		})
+
+		It("should not add a scsi controller without scsi disks", func() {
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			for _, controller := range domain.Spec.Devices.Controllers {
+				Expect(controller.Type).ToNot(Equal("scsi"))
+			}
+		})
+	})
+	Context("disk compaction", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+				{Name: "overlay"},
+				{Name: "hostdisk"},
+			}
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "overlay",
+					VolumeSource: v1.VolumeSource{
+						EmptyDisk: &v1.EmptyDiskSource{
+							Capacity: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				{
+					Name: "hostdisk",
+					VolumeSource: v1.VolumeSource{
+						HostDisk: &v1.HostDisk{
+							Path:     "/var/run/kubevirt-private/vmi-disks/myvolume/disk.img",
+							Type:     v1.HostDiskExistsOrCreate,
+							Capacity: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			}
+		})
+
+		It("should let the guest discard blocks of the qcow2 overlays", func() {
+			vmi.Spec.DiskCompaction = &v1.DiskCompaction{Interval: k8smeta.Duration{Duration: time.Hour}}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(domain.Spec.Devices.Disks[0].Driver.Discard).To(Equal("unmap"))
+			Expect(domain.Spec.Devices.Disks[1].Driver.Discard).To(BeEmpty())
+		})
+
+		It("should not change the discard mode without compaction", func() {
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			Expect(domain.Spec.Devices.Disks[0].Driver.Discard).To(BeEmpty())
+			Expect(domain.Spec.Devices.Disks[1].Driver.Discard).To(BeEmpty())
+		})
 	})
+
+	Context("golden image", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+				{Name: "golden"},
+			}
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "golden",
+					VolumeSource: v1.VolumeSource{
+						GoldenImage: &v1.GoldenImageVolumeSource{
+							ClaimName: "golden-pvc",
+						},
+					},
+				},
+			}
+		})
+
+		It("should put an overlay on top of a filesystem golden image", func() {
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true, SMBios: &cmdv1.SMBios{}})
+			disk := domain.Spec.Devices.Disks[0]
+			Expect(disk.Type).To(Equal("file"))
+			Expect(disk.Driver.Type).To(Equal("qcow2"))
+			Expect(disk.Source.File).To(Equal("/var/run/libvirt/kubevirt-ephemeral-disk/golden/disk.qcow2"))
+			Expect(disk.BackingStore.Type).To(Equal("file"))
+			Expect(disk.BackingStore.Format.Type).To(Equal("raw"))
+			Expect(disk.BackingStore.Source.File).To(Equal("/var/run/kubevirt-private/vmi-disks/golden/disk.img"))
+		})
+
+		It("should put an overlay on top of a block golden image", func() {
+			c := &ConverterContext{
+				UseEmulation: true,
+				SMBios:       &cmdv1.SMBios{},
+				IsBlockPVC:   map[string]bool{"golden": true},
+			}
+			domain := vmiToDomain(vmi, c)
+			disk := domain.Spec.Devices.Disks[0]
+			Expect(disk.Type).To(Equal("file"))
+			Expect(disk.Source.File).To(Equal("/var/run/libvirt/kubevirt-ephemeral-disk/golden/disk.qcow2"))
+			Expect(disk.BackingStore.Type).To(Equal("block"))
+			Expect(disk.BackingStore.Format.Type).To(Equal("raw"))
+			Expect(disk.BackingStore.Source.Dev).To(Equal("/dev/golden"))
+		})
+	})
+
 	Context("Correctly handle iothreads with dedicated cpus", func() {
 		var vmi *v1.VirtualMachineInstance
 
@@ -2053,7 +2462,223 @@ var _ = Describe("Converter", func() {
 			isExpectedThreadsLayout := reflect.DeepEqual(expectedLayout, domain.Spec.CPUTune.IOThreadPin)
 			Expect(isExpectedThreadsLayout).To(BeTrue())
 		})
+		It("should assign disks and pin iothreads as defined with the manual policy", func() {
+			policy := v1.IOThreadsPolicyManual
+			threadOne := uint32(1)
+			threadTwo := uint32(2)
+			vmi.Spec.Domain.CPU.Cores = 4
+			vmi.Spec.Domain.IOThreadsPolicy = &policy
+			vmi.Spec.Domain.IOThreads = []v1.IOThread{
+				{ID: 1, CPUs: "0-1"},
+				{ID: 2, CPUs: "3"},
+				{ID: 3},
+			}
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+				{Name: "disk1", IOThread: &threadTwo},
+				{Name: "disk2"},
+				{Name: "disk3", IOThread: &threadOne},
+			}
+			for _, disk := range vmi.Spec.Domain.Devices.Disks {
+				vmi.Spec.Volumes = append(vmi.Spec.Volumes, v1.Volume{
+					Name: disk.Name,
+					VolumeSource: v1.VolumeSource{
+						EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")},
+					},
+				})
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			c := &ConverterContext{CPUSet: []int{5, 6, 7, 8}, UseEmulation: true}
+
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.IOThreads.IOThreads).To(Equal(uint(3)))
+			Expect(*domain.Spec.Devices.Disks[0].Driver.IOThread).To(Equal(uint(2)))
+			Expect(domain.Spec.Devices.Disks[1].Driver.IOThread).To(BeNil())
+			Expect(*domain.Spec.Devices.Disks[2].Driver.IOThread).To(Equal(uint(1)))
+			Expect(domain.Spec.CPUTune.IOThreadPin).To(Equal([]CPUTuneIOThreadPin{
+				{IOThread: 1, CPUSet: "5,6"},
+				{IOThread: 2, CPUSet: "8"},
+			}))
+		})
+		It("should fail to pin a manual iothread to a vCPU without allocated CPU", func() {
+			vmi.Spec.Domain.IOThreads = []v1.IOThread{{ID: 1, CPUs: "2"}}
+			c := &ConverterContext{CPUSet: []int{5, 6}, UseEmulation: true}
+			domain := &Domain{}
+			domain.Spec.CPUTune = &CPUTune{}
+
+			err := formatDomainManualIOThreadPin(vmi, domain, c)
+			Expect(err).To(MatchError("iothread 1 is pinned to vCPU 2, which has no allocated CPU"))
+		})
+	})
+	Context("emulator thread placement with dedicated cpus", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "default",
+					UID:       "1234",
+				},
+				Spec: v1.VirtualMachineInstanceSpec{
+					Domain: v1.DomainSpec{
+						CPU: &v1.CPU{Cores: 2, DedicatedCPUPlacement: true},
+						Resources: v1.ResourceRequirements{
+							Requests: k8sv1.ResourceList{
+								k8sv1.ResourceMemory: resource.MustParse("64M"),
+							},
+						},
+					},
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+		})
+
+		table.DescribeTable("should pin the emulator thread", func(policy *v1.EmulatorThreadPolicy, isolate bool, expectedEmulatorPin *CPUEmulatorPin) {
+			vmi.Spec.Domain.CPU.EmulatorThreadPolicy = policy
+			vmi.Spec.Domain.CPU.IsolateEmulatorThread = isolate
+			emulatorThreadCpu := 7
+			c := &ConverterContext{CPUSet: []int{5, 6}, EmulatorThreadCpu: &emulatorThreadCpu, UseEmulation: true}
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.CPUTune.EmulatorPin).To(Equal(expectedEmulatorPin))
+		},
+			table.Entry("on the isolated pCPU with isolateEmulatorThread", nil, true, &CPUEmulatorPin{CPUSet: "7"}),
+			table.Entry("on the isolated pCPU with the isolate policy", emulatorThreadPolicy(v1.EmulatorThreadPolicyIsolate), false, &CPUEmulatorPin{CPUSet: "7"}),
+			table.Entry("on the pCPU of vCPU 0 with the vcpu0 policy", emulatorThreadPolicy(v1.EmulatorThreadPolicyVCPU0), false, &CPUEmulatorPin{CPUSet: "5"}),
+			table.Entry("nowhere with the float policy", emulatorThreadPolicy(v1.EmulatorThreadPolicyFloat), false, nil),
+			table.Entry("nowhere by default", nil, false, nil),
+		)
+	})
+
+	Context("realtime with dedicated cpus", func() {
+		var vmi *v1.VirtualMachineInstance
+		var c *ConverterContext
+
+		BeforeEach(func() {
+			vmi = &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "default",
+					UID:       "1234",
+				},
+				Spec: v1.VirtualMachineInstanceSpec{
+					Domain: v1.DomainSpec{
+						CPU: &v1.CPU{Cores: 4, DedicatedCPUPlacement: true, Realtime: &v1.Realtime{}},
+						Resources: v1.ResourceRequirements{
+							Requests: k8sv1.ResourceList{
+								k8sv1.ResourceMemory: resource.MustParse("64M"),
+							},
+						},
+					},
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			emulatorThreadCpu := 9
+			c = &ConverterContext{CPUSet: []int{5, 6, 7, 8}, EmulatorThreadCpu: &emulatorThreadCpu, UseEmulation: true}
+		})
+
+		It("should schedule all vCPUs with the fifo policy by default", func() {
+			vmi.Spec.Domain.CPU.EmulatorThreadPolicy = emulatorThreadPolicy(v1.EmulatorThreadPolicyIsolate)
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.CPUTune.VCPUSched).To(Equal([]CPUTuneVCPUSched{{VCPUs: "0,1,2,3", Scheduler: "fifo", Priority: 1}}))
+			Expect(domain.Spec.CPUTune.EmulatorPin).To(Equal(&CPUEmulatorPin{CPUSet: "9"}))
+		})
+
+		It("should keep the emulator thread and the IOThreads away from the realtime vCPUs", func() {
+			vmi.Spec.Domain.CPU.Realtime = &v1.Realtime{Mask: "1-3", Priority: uint32Ptr(10)}
+			vmi.Spec.Domain.CPU.EmulatorThreadPolicy = emulatorThreadPolicy(v1.EmulatorThreadPolicyVCPU0)
+			ioThreadsPolicy := v1.IOThreadsPolicyShared
+			vmi.Spec.Domain.IOThreadsPolicy = &ioThreadsPolicy
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{{
+				Name:       "disk0",
+				DiskDevice: v1.DiskDevice{Disk: &v1.DiskTarget{Bus: "virtio"}},
+			}}
+			vmi.Spec.Volumes = []v1.Volume{{
+				Name: "disk0",
+				VolumeSource: v1.VolumeSource{
+					EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")},
+				},
+			}}
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.CPUTune.VCPUSched).To(Equal([]CPUTuneVCPUSched{{VCPUs: "1,2,3", Scheduler: "fifo", Priority: 10}}))
+			Expect(domain.Spec.CPUTune.EmulatorPin).To(Equal(&CPUEmulatorPin{CPUSet: "5"}))
+			Expect(domain.Spec.CPUTune.IOThreadPin).To(Equal([]CPUTuneIOThreadPin{{IOThread: 1, CPUSet: "5"}}))
+		})
+
+		It("should fail if there is no pCPU left for the emulator thread", func() {
+			domain := &Domain{}
+			Expect(Convert_v1_VirtualMachine_To_api_Domain(vmi, domain, c)).To(MatchError(ContainSubstring("non-realtime vCPU")))
+		})
+
+		It("should fail if the mask contains vCPUs the VMI does not have", func() {
+			vmi.Spec.Domain.CPU.Realtime = &v1.Realtime{Mask: "2-4"}
+			domain := &Domain{}
+			Expect(Convert_v1_VirtualMachine_To_api_Domain(vmi, domain, c)).To(MatchError(ContainSubstring("contains vCPU 4")))
+		})
+	})
+
+	Context("NUMA passthrough with dedicated cpus", func() {
+		var vmi *v1.VirtualMachineInstance
+		hostNUMANodes := []hardware.NUMANode{
+			{ID: 0, CPUs: []int{0, 1, 2, 3}},
+			{ID: 1, CPUs: []int{4, 5, 6, 7}},
+		}
+
+		BeforeEach(func() {
+			vmi = &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "default",
+					UID:       "1234",
+				},
+				Spec: v1.VirtualMachineInstanceSpec{
+					Domain: v1.DomainSpec{
+						CPU: &v1.CPU{
+							Cores:                 3,
+							DedicatedCPUPlacement: true,
+							NUMA:                  &v1.NUMA{GuestMappingPassthrough: &v1.NUMAGuestMappingPassthrough{}},
+						},
+						Memory: &v1.Memory{Hugepages: &v1.Hugepages{PageSize: "2Mi"}},
+						Resources: v1.ResourceRequirements{
+							Requests: k8sv1.ResourceList{
+								k8sv1.ResourceMemory: resource.MustParse("6Mi"),
+							},
+						},
+					},
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+		})
+
+		It("should create a guest NUMA cell per host NUMA node", func() {
+			c := &ConverterContext{CPUSet: []int{3, 4, 5}, HostNUMANodes: hostNUMANodes, UseEmulation: true}
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.CPU.NUMA).To(Equal(&NUMA{Cells: []NUMACell{
+				{ID: "0", CPUs: "0", Memory: 4 * 1024 * 1024, Unit: "b"},
+				{ID: "1", CPUs: "1,2", Memory: 2 * 1024 * 1024, Unit: "b"},
+			}}))
+			Expect(domain.Spec.NUMATune).To(Equal(&NUMATune{
+				Memory: NUMATuneMemory{Mode: "strict", NodeSet: "0,1"},
+				MemNodes: []MemNode{
+					{CellID: 0, Mode: "strict", NodeSet: "0"},
+					{CellID: 1, Mode: "strict", NodeSet: "1"},
+				},
+			}))
+		})
+
+		It("should not create a guest NUMA topology without passthrough", func() {
+			vmi.Spec.Domain.CPU.NUMA = nil
+			c := &ConverterContext{CPUSet: []int{3, 4, 5}, HostNUMANodes: hostNUMANodes, UseEmulation: true}
+			domain := vmiToDomain(vmi, c)
+			Expect(domain.Spec.CPU.NUMA).To(BeNil())
+			Expect(domain.Spec.NUMATune).To(BeNil())
+		})
+
+		It("should fail without the host NUMA topology", func() {
+			c := &ConverterContext{CPUSet: []int{3, 4, 5}, UseEmulation: true}
+			Expect(Convert_v1_VirtualMachine_To_api_Domain(vmi, &Domain{}, c)).To(MatchError("no host NUMA topology available"))
+		})
 	})
+
 	Context("virtio-net multi-queue", func() {
 		var vmi *v1.VirtualMachineInstance
 
@@ -2095,6 +2720,13 @@ var _ = Describe("Converter", func() {
 				"expected number of queues to equal number of requested vCPUs")
 		})
 
+		It("should assign the requested number of queues to a device", func() {
+			queues := uint32(3)
+			vmi.Spec.Domain.Devices.NetworkInterfaceQueues = &queues
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true})
+			Expect(*(domain.Spec.Devices.Interfaces[0].Driver.Queues)).To(Equal(uint(3)))
+		})
+
 		It("should not assign queues to a non-virtio devices", func() {
 			vmi.Spec.Domain.Devices.Interfaces[0].Model = "e1000"
 			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true})
@@ -2103,6 +2735,71 @@ var _ = Describe("Converter", func() {
 		})
 	})
 
+	Context("ignition", func() {
+		It("should pass the config of an ignition volume through the firmware configuration", func() {
+			vmi := &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "ignition",
+					VolumeSource: v1.VolumeSource{
+						Ignition: &v1.IgnitionSource{
+							SecretRef: &k8sv1.LocalObjectReference{Name: "ignition-secret"},
+						},
+					},
+				},
+			}
+
+			domain := vmiToDomain(vmi, &ConverterContext{VirtualMachine: vmi, UseEmulation: true})
+			Expect(domain.Spec.Devices.Disks).To(BeEmpty())
+			Expect(domain.Spec.QEMUCmd.QEMUArg).To(Equal([]Arg{
+				{Value: "-fw_cfg"},
+				{Value: fmt.Sprintf("name=opt/com.coreos/config,file=%s/%s", ignition.GetDomainBasePath("testvmi", "mynamespace"), ignition.IgnitionFile)},
+			}))
+		})
+	})
+
+	Context("sysprep", func() {
+		It("should attach the answer file iso as a cdrom", func() {
+			vmi := &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{
+				{
+					Name: "sysprep",
+					DiskDevice: v1.DiskDevice{
+						CDRom: &v1.CDRomTarget{Bus: "sata"},
+					},
+				},
+			}
+			vmi.Spec.Volumes = []v1.Volume{
+				{
+					Name: "sysprep",
+					VolumeSource: v1.VolumeSource{
+						Sysprep: &v1.SysprepSource{
+							ConfigMap: &k8sv1.LocalObjectReference{Name: "sysprep-config"},
+						},
+					},
+				},
+			}
+
+			domain := vmiToDomain(vmi, &ConverterContext{VirtualMachine: vmi, UseEmulation: true})
+			Expect(domain.Spec.Devices.Disks).To(HaveLen(1))
+			Expect(domain.Spec.Devices.Disks[0].Device).To(Equal("cdrom"))
+			Expect(domain.Spec.Devices.Disks[0].Type).To(Equal("file"))
+			Expect(domain.Spec.Devices.Disks[0].Source.File).To(Equal("/var/run/kubevirt-private/sysprep-disks/sysprep.iso"))
+		})
+	})
+
 	Context("sriov", func() {
 		vmi := &v1.VirtualMachineInstance{
 			ObjectMeta: k8smeta.ObjectMeta{
@@ -2169,6 +2866,111 @@ var _ = Describe("Converter", func() {
 			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.Bus).To(Equal("0x81"))
 			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.Slot).To(Equal("0x11"))
 			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.Function).To(Equal("0x2"))
+			Expect(domain.Spec.Devices.HostDevices[0].Alias).To(Equal(&Alias{Name: "sriov"}))
+			Expect(domain.Spec.Devices.HostDevices[1].Alias).To(Equal(&Alias{Name: "sriov2"}))
+		})
+
+		It("should team sriov interfaces with their failover standby", func() {
+			failoverVMI := vmi.DeepCopy()
+			failoverVMI.Spec.Domain.Devices.Interfaces[1].SRIOV.FailoverStandby = "default"
+			failoverVMI.Spec.Domain.Devices.Interfaces[1].MacAddress = "de:ad:00:00:be:ef"
+			c := &ConverterContext{
+				UseEmulation: true,
+				SRIOVDevices: map[string][]string{
+					"sriov":  []string{"0000:81:11.1"},
+					"sriov2": []string{"0000:81:11.2"},
+				},
+			}
+			domain := vmiToDomain(failoverVMI, c)
+
+			Expect(domain.Spec.Devices.HostDevices).To(HaveLen(1))
+			Expect(domain.Spec.Devices.Interfaces).To(HaveLen(2))
+			Expect(domain.Spec.Devices.Interfaces[0].Teaming).To(Equal(&Teaming{Type: "persistent"}))
+			vf := domain.Spec.Devices.Interfaces[1]
+			Expect(vf.Type).To(Equal("hostdev"))
+			Expect(vf.Managed).To(Equal("yes"))
+			Expect(vf.Source.Address.Function).To(Equal("0x1"))
+			Expect(vf.MAC).To(Equal(&MAC{MAC: "de:ad:00:00:be:ef"}))
+			Expect(vf.Teaming).To(Equal(&Teaming{Type: "transient", Persistent: "ua-default"}))
+
+			xmlBytes, err := xml.Marshal(vf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(xmlBytes)).To(ContainSubstring(`<teaming type="transient" persistent="ua-default"></teaming>`))
+		})
+	})
+
+	Context("macvtap", func() {
+		It("should convert macvtap interfaces into ethernet interfaces on the multus interface", func() {
+			vmi := &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Networks = []v1.Network{
+				*v1.DefaultPodNetwork(),
+				{Name: "macvtap", NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "macvtap"}}},
+			}
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{
+				*v1.DefaultBridgeNetworkInterface(),
+				{
+					Name:                   "macvtap",
+					MacAddress:             "de:ad:00:00:be:ef",
+					InterfaceBindingMethod: v1.InterfaceBindingMethod{Macvtap: &v1.InterfaceMacvtap{}},
+				},
+			}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true})
+
+			Expect(domain.Spec.Devices.Interfaces).To(HaveLen(2))
+			macvtap := domain.Spec.Devices.Interfaces[1]
+			Expect(macvtap.Type).To(Equal("ethernet"))
+			Expect(macvtap.Target).To(Equal(&InterfaceTarget{Device: "net1", Managed: "no"}))
+			Expect(macvtap.MAC).To(Equal(&MAC{MAC: "de:ad:00:00:be:ef"}))
+			Expect(macvtap.Model.Type).To(Equal("virtio"))
+
+			xmlBytes, err := xml.Marshal(macvtap)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(xmlBytes)).To(ContainSubstring(`<target dev="net1" managed="no"></target>`))
+		})
+	})
+
+	Context("passt", func() {
+		It("should convert passt interfaces into user interfaces with a passt backend", func() {
+			vmi := &v1.VirtualMachineInstance{
+				ObjectMeta: k8smeta.ObjectMeta{
+					Name:      "testvmi",
+					Namespace: "mynamespace",
+				},
+			}
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Networks = []v1.Network{*v1.DefaultPodNetwork()}
+			vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{
+				Name:                   "default",
+				InterfaceBindingMethod: v1.InterfaceBindingMethod{Passt: &v1.InterfacePasst{}},
+				Ports: []v1.Port{
+					{Port: 80},
+					{Port: 53, Protocol: "UDP"},
+					{Port: 443, Protocol: "TCP"},
+				},
+			}}
+
+			domain := vmiToDomain(vmi, &ConverterContext{UseEmulation: true})
+
+			Expect(domain.Spec.Devices.Interfaces).To(HaveLen(1))
+			passt := domain.Spec.Devices.Interfaces[0]
+			Expect(passt.Type).To(Equal("user"))
+			Expect(passt.Backend).To(Equal(&InterfaceBackend{Type: "passt"}))
+			Expect(passt.PortForward).To(Equal([]InterfacePortForward{
+				{Proto: "tcp", Ranges: []InterfacePortForwardRange{{Start: 80}, {Start: 443}}},
+				{Proto: "udp", Ranges: []InterfacePortForwardRange{{Start: 53}}},
+			}))
+			Expect(domain.Spec.QEMUCmd).To(BeNil())
+
+			xmlBytes, err := xml.Marshal(passt)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(xmlBytes)).To(ContainSubstring(`<backend type="passt"></backend><portForward proto="tcp"><range start="80"></range><range start="443"></range></portForward>`))
 		})
 	})
 
@@ -2254,6 +3056,92 @@ var _ = Describe("Converter", func() {
 				Expect(path.Base(domainSpec.OS.NVRam.Template)).To(Equal(EFIVarsSecureBoot))
 				Expect(domainSpec.OS.NVRam.NVRam).To(Equal("/tmp/mynamespace_testvmi"))
 			})
+
+			It("should use the vars template without enrolled keys if requested", func() {
+				vmi.Spec.Domain.Firmware = &v1.Firmware{
+					Bootloader: &v1.Bootloader{
+						EFI: &v1.EFI{
+							EnrolledKeys: False(),
+						},
+					},
+				}
+				domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+				Expect(domainSpec.OS.BootLoader.Secure).To(Equal("yes"))
+				Expect(path.Base(domainSpec.OS.BootLoader.Path)).To(Equal(EFICodeSecureBoot))
+				Expect(path.Base(domainSpec.OS.NVRam.Template)).To(Equal(EFIVars))
+			})
+
+			It("should load the EFI roms from the firmware container disk", func() {
+				vmi.Spec.Domain.Firmware = &v1.Firmware{
+					Bootloader: &v1.Bootloader{
+						EFI: &v1.EFI{
+							ContainerDisk: &v1.ContainerDiskSource{Image: "ovmf:custom"},
+						},
+					},
+				}
+				domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+				firmwareDir := containerdisk.GetFirmwareTargetPathFromLauncherView()
+				Expect(domainSpec.OS.BootLoader.Path).To(Equal(path.Join(firmwareDir, EFICodeSecureBoot)))
+				Expect(domainSpec.OS.NVRam.Template).To(Equal(path.Join(firmwareDir, EFIVarsSecureBoot)))
+			})
+		})
+	})
+
+	Context("SEV", func() {
+		var vmi *v1.VirtualMachineInstance
+		var c *ConverterContext
+
+		BeforeEach(func() {
+			vmi = v1.NewMinimalVMI("testvmi")
+			v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+			vmi.Spec.Domain.Firmware = &v1.Firmware{
+				Bootloader: &v1.Bootloader{
+					EFI: &v1.EFI{SecureBoot: False()},
+				},
+			}
+			vmi.Spec.Domain.Devices.Disks = []v1.Disk{{
+				Name: "disk0",
+				DiskDevice: v1.DiskDevice{
+					Disk: &v1.DiskTarget{Bus: "virtio"},
+				},
+			}}
+			vmi.Spec.Volumes = []v1.Volume{{
+				Name: "disk0",
+				VolumeSource: v1.VolumeSource{
+					EmptyDisk: &v1.EmptyDiskSource{Capacity: resource.MustParse("1Gi")},
+				},
+			}}
+			vmi.Spec.Domain.Devices.Rng = &v1.Rng{}
+			vmi.Spec.Domain.LaunchSecurity = &v1.LaunchSecurity{SEV: &v1.SEV{}}
+
+			c = &ConverterContext{
+				VirtualMachine: vmi,
+				UseEmulation:   true,
+			}
+		})
+
+		It("should not configure launch security by default", func() {
+			vmi.Spec.Domain.LaunchSecurity = nil
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.LaunchSecurity).To(BeNil())
+			Expect(domainSpec.Devices.Disks[0].Driver.IOMMU).To(BeEmpty())
+		})
+
+		table.DescribeTable("should configure the SEV policy", func(policy *v1.SEVPolicy, expected string) {
+			vmi.Spec.Domain.LaunchSecurity.SEV.Policy = policy
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.LaunchSecurity).To(Equal(&LaunchSecurity{Type: "sev", Policy: expected}))
+		},
+			table.Entry("without policy", nil, "0x0003"),
+			table.Entry("without encrypted state", &v1.SEVPolicy{EncryptedState: False()}, "0x0003"),
+			table.Entry("with encrypted state", &v1.SEVPolicy{EncryptedState: True()}, "0x0007"),
+		)
+
+		It("should let the virtio devices use the IOMMU", func() {
+			domainSpec := vmiToDomainXMLToDomainSpec(vmi, c)
+			Expect(domainSpec.Devices.Disks[0].Driver.IOMMU).To(Equal("on"))
+			Expect(domainSpec.Devices.Ballooning.Driver.IOMMU).To(Equal("on"))
+			Expect(domainSpec.Devices.Rng.Driver.IOMMU).To(Equal("on"))
 		})
 	})
 
@@ -2370,6 +3258,87 @@ var _ = Describe("Converter", func() {
 
 		})
 	})
+
+	Context("host devices", func() {
+		vmi := &v1.VirtualMachineInstance{
+			ObjectMeta: k8smeta.ObjectMeta{
+				Name:      "testvmi",
+				Namespace: "mynamespace",
+				UID:       "1234",
+			},
+			Spec: v1.VirtualMachineInstanceSpec{
+				Domain: v1.DomainSpec{
+					Devices: v1.Devices{
+						HostDevices: []v1.HostDevice{
+							{Name: "nvme1", DeviceName: "vendor.com/nvme"},
+							{Name: "fpga1", DeviceName: "vendor.com/fpga"},
+							{Name: "nvme2", DeviceName: "vendor.com/nvme"},
+						},
+					},
+				},
+			},
+		}
+
+		v1.SetObjectDefaults_VirtualMachineInstance(vmi)
+
+		It("should assign one allocated PCI address to every host device", func() {
+			c := &ConverterContext{
+				UseEmulation: true,
+				HostDevices: map[string][]string{
+					"vendor.com/nvme": {"0000:81:00.0", "0000:82:00.0"},
+					"vendor.com/fpga": {"0000:05:00.1"},
+				},
+			}
+
+			domain := vmiToDomain(vmi, c)
+
+			Expect(domain.Spec.Devices.HostDevices).To(HaveLen(3))
+			for _, hostDev := range domain.Spec.Devices.HostDevices {
+				Expect(hostDev.Type).To(Equal("pci"))
+				Expect(hostDev.Managed).To(Equal("yes"))
+			}
+			Expect(domain.Spec.Devices.HostDevices[0].Source.Address.Bus).To(Equal("0x81"))
+			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.Bus).To(Equal("0x05"))
+			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.Function).To(Equal("0x1"))
+			Expect(domain.Spec.Devices.HostDevices[2].Source.Address.Bus).To(Equal("0x82"))
+		})
+
+		It("should assign mediated devices to host devices and GPUs", func() {
+			vmi := vmi.DeepCopy()
+			vmi.Spec.Domain.Devices.HostDevices = []v1.HostDevice{{Name: "vgpu1", DeviceName: "nvidia.com/GRID_T4-1Q"}}
+			vmi.Spec.Domain.Devices.GPUs = []v1.GPU{{Name: "gpu1", DeviceName: "nvidia.com/GRID_T4-1Q"}}
+			c := &ConverterContext{
+				UseEmulation: true,
+				MediatedDevices: map[string][]string{
+					"nvidia.com/GRID_T4-1Q": {"4b20d080-1b54-4048-85b3-a6a62d165c01", "1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11"},
+				},
+			}
+
+			domain := vmiToDomain(vmi, c)
+
+			Expect(domain.Spec.Devices.HostDevices).To(HaveLen(2))
+			for _, hostDev := range domain.Spec.Devices.HostDevices {
+				Expect(hostDev.Type).To(Equal("mdev"))
+				Expect(hostDev.Model).To(Equal("vfio-pci"))
+			}
+			Expect(domain.Spec.Devices.HostDevices[0].Source.Address.UUID).To(Equal("4b20d080-1b54-4048-85b3-a6a62d165c01"))
+			Expect(domain.Spec.Devices.HostDevices[1].Source.Address.UUID).To(Equal("1f6b7d2c-5e3e-4b2c-9e8a-2f5c0e7a1d11"))
+		})
+
+		It("should fail if not enough PCI addresses are allocated", func() {
+			c := &ConverterContext{
+				UseEmulation: true,
+				HostDevices: map[string][]string{
+					"vendor.com/nvme": {"0000:81:00.0"},
+					"vendor.com/fpga": {"0000:05:00.1"},
+				},
+			}
+
+			domain := &Domain{}
+			err := Convert_v1_VirtualMachine_To_api_Domain(vmi, domain, c)
+			Expect(err).To(MatchError(ContainSubstring("no device of resource vendor.com/nvme is allocated for host device nvme2")))
+		})
+	})
 })
 
 var _ = Describe("popSRIOVPCIAddress", func() {
@@ -2449,3 +3418,11 @@ func False() *bool {
 	b := false
 	return &b
 }
+
+func emulatorThreadPolicy(policy v1.EmulatorThreadPolicy) *v1.EmulatorThreadPolicy {
+	return &policy
+}
+
+func uint32Ptr(i uint32) *uint32 {
+	return &i
+}
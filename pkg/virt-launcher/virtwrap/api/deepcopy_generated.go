@@ -133,6 +133,16 @@ func (in *BackingStoreFormat) DeepCopy() *BackingStoreFormat {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BandWidth) DeepCopyInto(out *BandWidth) {
 	*out = *in
+	if in.Inbound != nil {
+		in, out := &in.Inbound, &out.Inbound
+		*out = new(BandWidthLimit)
+		**out = **in
+	}
+	if in.Outbound != nil {
+		in, out := &in.Outbound, &out.Outbound
+		*out = new(BandWidthLimit)
+		**out = **in
+	}
 	return
 }
 
@@ -146,6 +156,22 @@ func (in *BandWidth) DeepCopy() *BandWidth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandWidthLimit) DeepCopyInto(out *BandWidthLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandWidthLimit.
+func (in *BandWidthLimit) DeepCopy() *BandWidthLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(BandWidthLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Boot) DeepCopyInto(out *Boot) {
 	*out = *in
@@ -212,6 +238,11 @@ func (in *CPU) DeepCopyInto(out *CPU) {
 		*out = new(CPUTopology)
 		**out = **in
 	}
+	if in.NUMA != nil {
+		in, out := &in.NUMA, &out.NUMA
+		*out = new(NUMA)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -291,6 +322,11 @@ func (in *CPUTune) DeepCopyInto(out *CPUTune) {
 		*out = new(CPUEmulatorPin)
 		**out = **in
 	}
+	if in.VCPUSched != nil {
+		in, out := &in.VCPUSched, &out.VCPUSched
+		*out = make([]CPUTuneVCPUSched, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -336,6 +372,22 @@ func (in *CPUTuneVCPUPin) DeepCopy() *CPUTuneVCPUPin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUTuneVCPUSched) DeepCopyInto(out *CPUTuneVCPUSched) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUTuneVCPUSched.
+func (in *CPUTuneVCPUSched) DeepCopy() *CPUTuneVCPUSched {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUTuneVCPUSched)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
@@ -670,6 +722,35 @@ func (in *Devices) DeepCopyInto(out *Devices) {
 		*out = new(Rng)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPM)
+		**out = **in
+	}
+	if in.Redirs != nil {
+		in, out := &in.Redirs, &out.Redirs
+		*out = make([]RedirectedDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]FilesystemDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SoundCards != nil {
+		in, out := &in.SoundCards, &out.SoundCards
+		*out = make([]SoundCard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Panic != nil {
+		in, out := &in.Panic, &out.Panic
+		*out = new(PanicDevice)
+		**out = **in
+	}
 	return
 }
 
@@ -693,6 +774,11 @@ func (in *Disk) DeepCopyInto(out *Disk) {
 		*out = new(DiskDriver)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IOTune != nil {
+		in, out := &in.IOTune, &out.IOTune
+		*out = new(DiskIOTune)
+		**out = **in
+	}
 	if in.ReadOnly != nil {
 		in, out := &in.ReadOnly, &out.ReadOnly
 		*out = new(ReadOnly)
@@ -757,6 +843,26 @@ func (in *DiskAuth) DeepCopy() *DiskAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskCompactionMetadata) DeepCopyInto(out *DiskCompactionMetadata) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskCompactionMetadata.
+func (in *DiskCompactionMetadata) DeepCopy() *DiskCompactionMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskCompactionMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskDriver) DeepCopyInto(out *DiskDriver) {
 	*out = *in
@@ -783,6 +889,22 @@ func (in *DiskDriver) DeepCopy() *DiskDriver {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskIOTune) DeepCopyInto(out *DiskIOTune) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskIOTune.
+func (in *DiskIOTune) DeepCopy() *DiskIOTune {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskIOTune)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskSecret) DeepCopyInto(out *DiskSecret) {
 	*out = *in
@@ -880,6 +1002,103 @@ func (in *Domain) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainBackup) DeepCopyInto(out *DomainBackup) {
+	*out = *in
+	out.XMLName = in.XMLName
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(DomainBackupServer)
+		**out = **in
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DomainBackupDisk, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainBackup.
+func (in *DomainBackup) DeepCopy() *DomainBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainBackupDisk) DeepCopyInto(out *DomainBackupDisk) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainBackupDisk.
+func (in *DomainBackupDisk) DeepCopy() *DomainBackupDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainBackupDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainBackupServer) DeepCopyInto(out *DomainBackupServer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainBackupServer.
+func (in *DomainBackupServer) DeepCopy() *DomainBackupServer {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainBackupServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainCheckpoint) DeepCopyInto(out *DomainCheckpoint) {
+	*out = *in
+	out.XMLName = in.XMLName
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DomainCheckpointDisk, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainCheckpoint.
+func (in *DomainCheckpoint) DeepCopy() *DomainCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainCheckpointDisk) DeepCopyInto(out *DomainCheckpointDisk) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainCheckpointDisk.
+func (in *DomainCheckpointDisk) DeepCopy() *DomainCheckpointDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainCheckpointDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainGuestInfo) DeepCopyInto(out *DomainGuestInfo) {
 	*out = *in
@@ -895,6 +1114,11 @@ func (in *DomainGuestInfo) DeepCopyInto(out *DomainGuestInfo) {
 		*out = new(GuestOSInfo)
 		**out = **in
 	}
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -990,11 +1214,21 @@ func (in *DomainSpec) DeepCopyInto(out *DomainSpec) {
 		*out = new(CPUTune)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NUMATune != nil {
+		in, out := &in.NUMATune, &out.NUMATune
+		*out = new(NUMATune)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.IOThreads != nil {
 		in, out := &in.IOThreads, &out.IOThreads
 		*out = new(IOThreads)
 		**out = **in
 	}
+	if in.LaunchSecurity != nil {
+		in, out := &in.LaunchSecurity, &out.LaunchSecurity
+		*out = new(LaunchSecurity)
+		**out = **in
+	}
 	return
 }
 
@@ -1019,6 +1253,7 @@ func (in *DomainStatus) DeepCopyInto(out *DomainStatus) {
 		}
 	}
 	out.OSInfo = in.OSInfo
+	in.Watchdog.DeepCopyInto(&out.Watchdog)
 	return
 }
 
@@ -1082,6 +1317,26 @@ func (in *Env) DeepCopy() *Env {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FSFreezeMetadata) DeepCopyInto(out *FSFreezeMetadata) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FSFreezeMetadata.
+func (in *FSFreezeMetadata) DeepCopy() *FSFreezeMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(FSFreezeMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeatureEnabled) DeepCopyInto(out *FeatureEnabled) {
 	*out = *in
@@ -1289,6 +1544,106 @@ func (in *Filesystem) DeepCopy() *Filesystem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemBinary) DeepCopyInto(out *FilesystemBinary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemBinary.
+func (in *FilesystemBinary) DeepCopy() *FilesystemBinary {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemBinary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemDevice) DeepCopyInto(out *FilesystemDevice) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(FilesystemSource)
+		**out = **in
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(FilesystemTarget)
+		**out = **in
+	}
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(FilesystemDriver)
+		**out = **in
+	}
+	if in.Binary != nil {
+		in, out := &in.Binary, &out.Binary
+		*out = new(FilesystemBinary)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemDevice.
+func (in *FilesystemDevice) DeepCopy() *FilesystemDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemDriver) DeepCopyInto(out *FilesystemDriver) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemDriver.
+func (in *FilesystemDriver) DeepCopy() *FilesystemDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSource) DeepCopyInto(out *FilesystemSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSource.
+func (in *FilesystemSource) DeepCopy() *FilesystemSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemTarget) DeepCopyInto(out *FilesystemTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemTarget.
+func (in *FilesystemTarget) DeepCopy() *FilesystemTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilterRef) DeepCopyInto(out *FilterRef) {
 	*out = *in
@@ -1397,6 +1752,11 @@ func (in *HostDevice) DeepCopyInto(out *HostDevice) {
 		*out = new(Address)
 		**out = **in
 	}
+	if in.Alias != nil {
+		in, out := &in.Alias, &out.Alias
+		*out = new(Alias)
+		**out = **in
+	}
 	return
 }
 
@@ -1542,7 +1902,7 @@ func (in *Interface) DeepCopyInto(out *Interface) {
 	if in.BandWidth != nil {
 		in, out := &in.BandWidth, &out.BandWidth
 		*out = new(BandWidth)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.BootOrder != nil {
 		in, out := &in.BootOrder, &out.BootOrder
@@ -1569,19 +1929,52 @@ func (in *Interface) DeepCopyInto(out *Interface) {
 		*out = new(InterfaceDriver)
 		(*in).DeepCopyInto(*out)
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interface.
-func (in *Interface) DeepCopy() *Interface {
-	if in == nil {
-		return nil
+	if in.Teaming != nil {
+		in, out := &in.Teaming, &out.Teaming
+		*out = new(Teaming)
+		**out = **in
+	}
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(InterfaceBackend)
+		**out = **in
+	}
+	if in.PortForward != nil {
+		in, out := &in.PortForward, &out.PortForward
+		*out = make([]InterfacePortForward, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interface.
+func (in *Interface) DeepCopy() *Interface {
+	if in == nil {
+		return nil
 	}
 	out := new(Interface)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceBackend) DeepCopyInto(out *InterfaceBackend) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBackend.
+func (in *InterfaceBackend) DeepCopy() *InterfaceBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterfaceDriver) DeepCopyInto(out *InterfaceDriver) {
 	*out = *in
@@ -1603,6 +1996,43 @@ func (in *InterfaceDriver) DeepCopy() *InterfaceDriver {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfacePortForward) DeepCopyInto(out *InterfacePortForward) {
+	*out = *in
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]InterfacePortForwardRange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfacePortForward.
+func (in *InterfacePortForward) DeepCopy() *InterfacePortForward {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfacePortForward)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfacePortForwardRange) DeepCopyInto(out *InterfacePortForwardRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfacePortForwardRange.
+func (in *InterfacePortForwardRange) DeepCopy() *InterfacePortForwardRange {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfacePortForwardRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterfaceSource) DeepCopyInto(out *InterfaceSource) {
 	*out = *in
@@ -1674,6 +2104,21 @@ func (in *KubeVirtMetadata) DeepCopyInto(out *KubeVirtMetadata) {
 		*out = new(MigrationMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskCompaction != nil {
+		in, out := &in.DiskCompaction, &out.DiskCompaction
+		*out = new(DiskCompactionMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryDump != nil {
+		in, out := &in.MemoryDump, &out.MemoryDump
+		*out = new(MemoryDumpMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FSFreeze != nil {
+		in, out := &in.FSFreeze, &out.FSFreeze
+		*out = new(FSFreezeMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1687,6 +2132,22 @@ func (in *KubeVirtMetadata) DeepCopy() *KubeVirtMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchSecurity) DeepCopyInto(out *LaunchSecurity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchSecurity.
+func (in *LaunchSecurity) DeepCopy() *LaunchSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkState) DeepCopyInto(out *LinkState) {
 	*out = *in
@@ -1764,6 +2225,11 @@ func (in *MemBalloon) DeepCopyInto(out *MemBalloon) {
 		*out = new(Address)
 		**out = **in
 	}
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(MemBalloonDriver)
+		**out = **in
+	}
 	return
 }
 
@@ -1777,6 +2243,38 @@ func (in *MemBalloon) DeepCopy() *MemBalloon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemBalloonDriver) DeepCopyInto(out *MemBalloonDriver) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemBalloonDriver.
+func (in *MemBalloonDriver) DeepCopy() *MemBalloonDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(MemBalloonDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemNode) DeepCopyInto(out *MemNode) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemNode.
+func (in *MemNode) DeepCopy() *MemNode {
+	if in == nil {
+		return nil
+	}
+	out := new(MemNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Memory) DeepCopyInto(out *Memory) {
 	*out = *in
@@ -1801,6 +2299,21 @@ func (in *MemoryBacking) DeepCopyInto(out *MemoryBacking) {
 		*out = new(HugePages)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(MemoryBackingSource)
+		**out = **in
+	}
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(MemoryBackingAccess)
+		**out = **in
+	}
+	if in.NoSharePages != nil {
+		in, out := &in.NoSharePages, &out.NoSharePages
+		*out = new(NoSharePages)
+		**out = **in
+	}
 	return
 }
 
@@ -1814,6 +2327,62 @@ func (in *MemoryBacking) DeepCopy() *MemoryBacking {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryBackingAccess) DeepCopyInto(out *MemoryBackingAccess) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryBackingAccess.
+func (in *MemoryBackingAccess) DeepCopy() *MemoryBackingAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryBackingAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryBackingSource) DeepCopyInto(out *MemoryBackingSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryBackingSource.
+func (in *MemoryBackingSource) DeepCopy() *MemoryBackingSource {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryBackingSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryDumpMetadata) DeepCopyInto(out *MemoryDumpMetadata) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTimestamp != nil {
+		in, out := &in.EndTimestamp, &out.EndTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryDumpMetadata.
+func (in *MemoryDumpMetadata) DeepCopy() *MemoryDumpMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryDumpMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -1871,6 +2440,81 @@ func (in *Model) DeepCopy() *Model {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMA) DeepCopyInto(out *NUMA) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]NUMACell, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMA.
+func (in *NUMA) DeepCopy() *NUMA {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMACell) DeepCopyInto(out *NUMACell) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMACell.
+func (in *NUMACell) DeepCopy() *NUMACell {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMACell)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMATune) DeepCopyInto(out *NUMATune) {
+	*out = *in
+	out.Memory = in.Memory
+	if in.MemNodes != nil {
+		in, out := &in.MemNodes, &out.MemNodes
+		*out = make([]MemNode, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMATune.
+func (in *NUMATune) DeepCopy() *NUMATune {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMATune)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMATuneMemory) DeepCopyInto(out *NUMATuneMemory) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMATuneMemory.
+func (in *NUMATuneMemory) DeepCopy() *NUMATuneMemory {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMATuneMemory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NVRam) DeepCopyInto(out *NVRam) {
 	*out = *in
@@ -1887,6 +2531,22 @@ func (in *NVRam) DeepCopy() *NVRam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NoSharePages) DeepCopyInto(out *NoSharePages) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NoSharePages.
+func (in *NoSharePages) DeepCopy() *NoSharePages {
+	if in == nil {
+		return nil
+	}
+	out := new(NoSharePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OS) DeepCopyInto(out *OS) {
 	*out = *in
@@ -1950,6 +2610,22 @@ func (in *OSType) DeepCopy() *OSType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PanicDevice) DeepCopyInto(out *PanicDevice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PanicDevice.
+func (in *PanicDevice) DeepCopy() *PanicDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(PanicDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadOnly) DeepCopyInto(out *ReadOnly) {
 	*out = *in
@@ -1966,6 +2642,39 @@ func (in *ReadOnly) DeepCopy() *ReadOnly {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedirectedDevice) DeepCopyInto(out *RedirectedDevice) {
+	*out = *in
+	out.Source = in.Source
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectedDevice.
+func (in *RedirectedDevice) DeepCopy() *RedirectedDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(RedirectedDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedirectedDeviceSource) DeepCopyInto(out *RedirectedDeviceSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectedDeviceSource.
+func (in *RedirectedDeviceSource) DeepCopy() *RedirectedDeviceSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RedirectedDeviceSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
@@ -1995,6 +2704,11 @@ func (in *Rng) DeepCopyInto(out *Rng) {
 		*out = new(Address)
 		**out = **in
 	}
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(RngDriver)
+		**out = **in
+	}
 	return
 }
 
@@ -2024,6 +2738,22 @@ func (in *RngBackend) DeepCopy() *RngBackend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RngDriver) DeepCopyInto(out *RngDriver) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RngDriver.
+func (in *RngDriver) DeepCopy() *RngDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(RngDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RngRate) DeepCopyInto(out *RngRate) {
 	*out = *in
@@ -2158,6 +2888,27 @@ func (in *SerialTarget) DeepCopy() *SerialTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SoundCard) DeepCopyInto(out *SoundCard) {
+	*out = *in
+	if in.Alias != nil {
+		in, out := &in.Alias, &out.Alias
+		*out = new(Alias)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoundCard.
+func (in *SoundCard) DeepCopy() *SoundCard {
+	if in == nil {
+		return nil
+	}
+	out := new(SoundCard)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Stats) DeepCopyInto(out *Stats) {
 	*out = *in
@@ -2210,6 +2961,55 @@ func (in *SysInfo) DeepCopy() *SysInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPM) DeepCopyInto(out *TPM) {
+	*out = *in
+	out.Backend = in.Backend
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPM.
+func (in *TPM) DeepCopy() *TPM {
+	if in == nil {
+		return nil
+	}
+	out := new(TPM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMBackend) DeepCopyInto(out *TPMBackend) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMBackend.
+func (in *TPMBackend) DeepCopy() *TPMBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Teaming) DeepCopyInto(out *Teaming) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Teaming.
+func (in *Teaming) DeepCopy() *Teaming {
+	if in == nil {
+		return nil
+	}
+	out := new(Teaming)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Timer) DeepCopyInto(out *Timer) {
 	*out = *in
@@ -2368,3 +3168,20 @@ func (in *Watchdog) DeepCopy() *Watchdog {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WatchdogEvents) DeepCopyInto(out *WatchdogEvents) {
+	*out = *in
+	in.LastTimestamp.DeepCopyInto(&out.LastTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WatchdogEvents.
+func (in *WatchdogEvents) DeepCopy() *WatchdogEvents {
+	if in == nil {
+		return nil
+	}
+	out := new(WatchdogEvents)
+	in.DeepCopyInto(out)
+	return out
+}
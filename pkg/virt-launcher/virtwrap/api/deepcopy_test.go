@@ -53,6 +53,8 @@ var _ = Describe("Generated deepcopy functions", func() {
 			&InterfaceSource{},
 			&Model{},
 			&InterfaceTarget{},
+			&InterfaceBackend{},
+			&InterfacePortForward{},
 			&Alias{},
 			&OS{},
 			&OSType{},
@@ -79,6 +81,8 @@ var _ = Describe("Generated deepcopy functions", func() {
 			&RngBackend{},
 			&RngRate{},
 			&Watchdog{},
+			&WatchdogEvents{},
+			&PanicDevice{},
 			&SecretUsage{},
 			&SecretSpec{},
 			&CPU{},
@@ -106,6 +106,14 @@ type DomainStatus struct {
 	Reason     StateChangeReason
 	Interfaces []InterfaceStatus
 	OSInfo     GuestOSInfo
+	Hostname   string
+	Watchdog   WatchdogEvents
+}
+
+// WatchdogEvents counts the times the watchdog device of the guest fired
+type WatchdogEvents struct {
+	Count         int
+	LastTimestamp metav1.Time
 }
 
 type DomainSysInfo struct {
@@ -156,6 +164,7 @@ type User struct {
 type DomainGuestInfo struct {
 	Interfaces []InterfaceStatus
 	OSInfo     *GuestOSInfo
+	Hostname   *string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -169,31 +178,44 @@ type DomainList struct {
 // tagged, and they must correspond to the libvirt domain as described in
 // https://libvirt.org/formatdomain.html.
 type DomainSpec struct {
-	XMLName       xml.Name       `xml:"domain"`
-	Type          string         `xml:"type,attr"`
-	XmlNS         string         `xml:"xmlns:qemu,attr,omitempty"`
-	Name          string         `xml:"name"`
-	UUID          string         `xml:"uuid,omitempty"`
-	Memory        Memory         `xml:"memory"`
-	MemoryBacking *MemoryBacking `xml:"memoryBacking,omitempty"`
-	OS            OS             `xml:"os"`
-	SysInfo       *SysInfo       `xml:"sysinfo,omitempty"`
-	Devices       Devices        `xml:"devices"`
-	Clock         *Clock         `xml:"clock,omitempty"`
-	Resource      *Resource      `xml:"resource,omitempty"`
-	QEMUCmd       *Commandline   `xml:"qemu:commandline,omitempty"`
-	Metadata      Metadata       `xml:"metadata,omitempty"`
-	Features      *Features      `xml:"features,omitempty"`
-	CPU           CPU            `xml:"cpu"`
-	VCPU          *VCPU          `xml:"vcpu"`
-	CPUTune       *CPUTune       `xml:"cputune"`
-	IOThreads     *IOThreads     `xml:"iothreads,omitempty"`
+	XMLName        xml.Name        `xml:"domain"`
+	Type           string          `xml:"type,attr"`
+	XmlNS          string          `xml:"xmlns:qemu,attr,omitempty"`
+	Name           string          `xml:"name"`
+	UUID           string          `xml:"uuid,omitempty"`
+	Memory         Memory          `xml:"memory"`
+	MemoryBacking  *MemoryBacking  `xml:"memoryBacking,omitempty"`
+	OS             OS              `xml:"os"`
+	SysInfo        *SysInfo        `xml:"sysinfo,omitempty"`
+	Devices        Devices         `xml:"devices"`
+	Clock          *Clock          `xml:"clock,omitempty"`
+	Resource       *Resource       `xml:"resource,omitempty"`
+	QEMUCmd        *Commandline    `xml:"qemu:commandline,omitempty"`
+	Metadata       Metadata        `xml:"metadata,omitempty"`
+	Features       *Features       `xml:"features,omitempty"`
+	CPU            CPU             `xml:"cpu"`
+	VCPU           *VCPU           `xml:"vcpu"`
+	CPUTune        *CPUTune        `xml:"cputune"`
+	NUMATune       *NUMATune       `xml:"numatune,omitempty"`
+	IOThreads      *IOThreads      `xml:"iothreads,omitempty"`
+	LaunchSecurity *LaunchSecurity `xml:"launchSecurity,omitempty"`
+	OnCrash        string          `xml:"on_crash,omitempty"`
+}
+
+// LaunchSecurity mirroring libvirt XML under https://libvirt.org/formatdomain.html#launch-security
+type LaunchSecurity struct {
+	Type string `xml:"type,attr"`
+	// Cbitpos and ReducedPhysBits are filled in by libvirt from the host capabilities if omitted
+	Cbitpos         string `xml:"cbitpos,omitempty"`
+	ReducedPhysBits string `xml:"reducedPhysBits,omitempty"`
+	Policy          string `xml:"policy"`
 }
 
 type CPUTune struct {
 	VCPUPin     []CPUTuneVCPUPin     `xml:"vcpupin"`
 	IOThreadPin []CPUTuneIOThreadPin `xml:"iothreadpin,omitempty"`
 	EmulatorPin *CPUEmulatorPin      `xml:"emulatorpin"`
+	VCPUSched   []CPUTuneVCPUSched   `xml:"vcpusched,omitempty"`
 }
 
 type CPUTuneVCPUPin struct {
@@ -201,6 +223,12 @@ type CPUTuneVCPUPin struct {
 	CPUSet string `xml:"cpuset,attr"`
 }
 
+type CPUTuneVCPUSched struct {
+	VCPUs     string `xml:"vcpus,attr"`
+	Scheduler string `xml:"scheduler,attr"`
+	Priority  uint32 `xml:"priority,attr,omitempty"`
+}
+
 type CPUTuneIOThreadPin struct {
 	IOThread uint   `xml:"iothread,attr"`
 	CPUSet   string `xml:"cpuset,attr"`
@@ -220,6 +248,36 @@ type CPU struct {
 	Model    string       `xml:"model,omitempty"`
 	Features []CPUFeature `xml:"feature"`
 	Topology *CPUTopology `xml:"topology"`
+	NUMA     *NUMA        `xml:"numa,omitempty"`
+}
+
+// NUMA mirroring libvirt XML under cpu
+type NUMA struct {
+	Cells []NUMACell `xml:"cell"`
+}
+
+type NUMACell struct {
+	ID     string `xml:"id,attr"`
+	CPUs   string `xml:"cpus,attr"`
+	Memory uint64 `xml:"memory,attr,omitempty"`
+	Unit   string `xml:"unit,attr,omitempty"`
+}
+
+// NUMATune mirroring libvirt XML under https://libvirt.org/formatdomain.html#numa-node-tuning
+type NUMATune struct {
+	Memory   NUMATuneMemory `xml:"memory"`
+	MemNodes []MemNode      `xml:"memnode"`
+}
+
+type NUMATuneMemory struct {
+	Mode    string `xml:"mode,attr"`
+	NodeSet string `xml:"nodeset,attr"`
+}
+
+type MemNode struct {
+	CellID  uint32 `xml:"cellid,attr"`
+	Mode    string `xml:"mode,attr"`
+	NodeSet string `xml:"nodeset,attr"`
 }
 
 type CPUFeature struct {
@@ -281,9 +339,32 @@ type Metadata struct {
 }
 
 type KubeVirtMetadata struct {
-	UID         types.UID            `xml:"uid"`
-	GracePeriod *GracePeriodMetadata `xml:"graceperiod,omitempty"`
-	Migration   *MigrationMetadata   `xml:"migration,omitempty"`
+	UID            types.UID               `xml:"uid"`
+	GracePeriod    *GracePeriodMetadata    `xml:"graceperiod,omitempty"`
+	Migration      *MigrationMetadata      `xml:"migration,omitempty"`
+	DiskCompaction *DiskCompactionMetadata `xml:"diskCompaction,omitempty"`
+	MemoryDump     *MemoryDumpMetadata     `xml:"memoryDump,omitempty"`
+	FSFreeze       *FSFreezeMetadata       `xml:"fsFreeze,omitempty"`
+}
+
+type DiskCompactionMetadata struct {
+	Timestamp      *metav1.Time `xml:"timestamp,omitempty"`
+	ReclaimedBytes int64        `xml:"reclaimedBytes,omitempty"`
+	FailureReason  string       `xml:"failureReason,omitempty"`
+}
+
+// FSFreezeMetadata is only present while the guest filesystems are frozen
+type FSFreezeMetadata struct {
+	Timestamp *metav1.Time `xml:"timestamp,omitempty"`
+}
+
+type MemoryDumpMetadata struct {
+	FileName       string       `xml:"fileName,omitempty"`
+	StartTimestamp *metav1.Time `xml:"startTimestamp,omitempty"`
+	EndTimestamp   *metav1.Time `xml:"endTimestamp,omitempty"`
+	Completed      bool         `xml:"completed,omitempty"`
+	Failed         bool         `xml:"failed,omitempty"`
+	FailureReason  string       `xml:"failureReason,omitempty"`
 }
 
 type MigrationMetadata struct {
@@ -327,7 +408,23 @@ type Memory struct {
 
 // MemoryBacking mirroring libvirt XML under https://libvirt.org/formatdomain.html#elementsMemoryBacking
 type MemoryBacking struct {
-	HugePages *HugePages `xml:"hugepages,omitempty"`
+	HugePages    *HugePages           `xml:"hugepages,omitempty"`
+	Source       *MemoryBackingSource `xml:"source,omitempty"`
+	Access       *MemoryBackingAccess `xml:"access,omitempty"`
+	NoSharePages *NoSharePages        `xml:"nosharepages,omitempty"`
+}
+
+// NoSharePages mirroring libvirt XML under memoryBacking, it prevents KSM from merging the guest memory
+type NoSharePages struct{}
+
+// MemoryBackingSource mirroring libvirt XML under memoryBacking
+type MemoryBackingSource struct {
+	Type string `xml:"type,attr"`
+}
+
+// MemoryBackingAccess mirroring libvirt XML under memoryBacking
+type MemoryBackingAccess struct {
+	Mode string `xml:"mode,attr"`
 }
 
 // HugePages mirroring libvirt XML under memoryBacking
@@ -342,20 +439,25 @@ type HugePage struct {
 }
 
 type Devices struct {
-	Emulator    string       `xml:"emulator,omitempty"`
-	Interfaces  []Interface  `xml:"interface"`
-	Channels    []Channel    `xml:"channel"`
-	HostDevices []HostDevice `xml:"hostdev,omitempty"`
-	Controllers []Controller `xml:"controller,omitempty"`
-	Video       []Video      `xml:"video"`
-	Graphics    []Graphics   `xml:"graphics"`
-	Ballooning  *MemBalloon  `xml:"memballoon,omitempty"`
-	Disks       []Disk       `xml:"disk"`
-	Inputs      []Input      `xml:"input"`
-	Serials     []Serial     `xml:"serial"`
-	Consoles    []Console    `xml:"console"`
-	Watchdog    *Watchdog    `xml:"watchdog,omitempty"`
-	Rng         *Rng         `xml:"rng,omitempty"`
+	Emulator    string             `xml:"emulator,omitempty"`
+	Interfaces  []Interface        `xml:"interface"`
+	Channels    []Channel          `xml:"channel"`
+	HostDevices []HostDevice       `xml:"hostdev,omitempty"`
+	Controllers []Controller       `xml:"controller,omitempty"`
+	Video       []Video            `xml:"video"`
+	Graphics    []Graphics         `xml:"graphics"`
+	Ballooning  *MemBalloon        `xml:"memballoon,omitempty"`
+	Disks       []Disk             `xml:"disk"`
+	Inputs      []Input            `xml:"input"`
+	Serials     []Serial           `xml:"serial"`
+	Consoles    []Console          `xml:"console"`
+	Watchdog    *Watchdog          `xml:"watchdog,omitempty"`
+	Rng         *Rng               `xml:"rng,omitempty"`
+	TPM         *TPM               `xml:"tpm,omitempty"`
+	Redirs      []RedirectedDevice `xml:"redirdev,omitempty"`
+	Filesystems []FilesystemDevice `xml:"filesystem,omitempty"`
+	SoundCards  []SoundCard        `xml:"sound,omitempty"`
+	Panic       *PanicDevice       `xml:"panic,omitempty"`
 }
 
 // Input represents input device, e.g. tablet
@@ -375,6 +477,7 @@ type HostDevice struct {
 	Mode      string           `xml:"mode,attr,omitempty"`
 	Model     string           `xml:"model,attr,omitempty"`
 	Address   *Address         `xml:"address,emitempty"`
+	Alias     *Alias           `xml:"alias,omitempty"`
 }
 
 type HostDeviceSource struct {
@@ -383,6 +486,72 @@ type HostDeviceSource struct {
 
 // END HostDevice -----------------------------
 
+// BEGIN FilesystemDevice -----------------------------
+
+// FilesystemDevice represents a libvirt filesystem element https://libvirt.org/formatdomain.html#filesystems
+type FilesystemDevice struct {
+	Type       string            `xml:"type,attr"`
+	AccessMode string            `xml:"accessmode,attr"`
+	Source     *FilesystemSource `xml:"source,omitempty"`
+	Target     *FilesystemTarget `xml:"target,omitempty"`
+	Driver     *FilesystemDriver `xml:"driver,omitempty"`
+	Binary     *FilesystemBinary `xml:"binary,omitempty"`
+}
+
+type FilesystemSource struct {
+	Dir string `xml:"dir,attr"`
+}
+
+type FilesystemTarget struct {
+	Dir string `xml:"dir,attr"`
+}
+
+type FilesystemDriver struct {
+	Type string `xml:"type,attr"`
+}
+
+type FilesystemBinary struct {
+	Path  string `xml:"path,attr,omitempty"`
+	Xattr string `xml:"xattr,attr,omitempty"`
+}
+
+// END FilesystemDevice -----------------------------
+
+// BEGIN SoundCard -----------------------------
+
+// SoundCard represents a libvirt sound element https://libvirt.org/formatdomain.html#sound-devices
+type SoundCard struct {
+	Model string `xml:"model,attr"`
+	Alias *Alias `xml:"alias,omitempty"`
+}
+
+// END SoundCard -----------------------------
+
+// BEGIN PanicDevice -----------------------------
+
+// PanicDevice represents a libvirt panic element https://libvirt.org/formatdomain.html#panic-device
+type PanicDevice struct {
+	Model string `xml:"model,attr"`
+}
+
+// END PanicDevice -----------------------------
+
+// BEGIN RedirectedDevice -----------------------------
+
+// RedirectedDevice represents a libvirt redirdev element https://libvirt.org/formatdomain.html#redirected-devices
+type RedirectedDevice struct {
+	Type   string                 `xml:"type,attr"`
+	Bus    string                 `xml:"bus,attr"`
+	Source RedirectedDeviceSource `xml:"source"`
+}
+
+type RedirectedDeviceSource struct {
+	Mode string `xml:"mode,attr"`
+	Path string `xml:"path,attr"`
+}
+
+// END RedirectedDevice -----------------------------
+
 // BEGIN Controller -----------------------------
 
 // Controller represens libvirt controller element https://libvirt.org/formatdomain.html#elementsControllers
@@ -400,6 +569,7 @@ type Controller struct {
 // BEGIN ControllerDriver
 type ControllerDriver struct {
 	IOThread *uint `xml:"iothread,attr,omitempty"`
+	Queues   *uint `xml:"queues,attr,omitempty"`
 }
 
 // END ControllerDriver
@@ -414,6 +584,7 @@ type Disk struct {
 	Target       DiskTarget    `xml:"target"`
 	Serial       string        `xml:"serial,omitempty"`
 	Driver       *DiskDriver   `xml:"driver,omitempty"`
+	IOTune       *DiskIOTune   `xml:"iotune,omitempty"`
 	ReadOnly     *ReadOnly     `xml:"readonly,omitempty"`
 	Auth         *DiskAuth     `xml:"auth,omitempty"`
 	Alias        *Alias        `xml:"alias,omitempty"`
@@ -458,6 +629,19 @@ type DiskDriver struct {
 	Type        string `xml:"type,attr"`
 	IOThread    *uint  `xml:"iothread,attr,omitempty"`
 	Queues      *uint  `xml:"queues,attr,omitempty"`
+	QueueSize   *uint  `xml:"queue_size,attr,omitempty"`
+	Discard     string `xml:"discard,attr,omitempty"`
+	IOMMU       string `xml:"iommu,attr,omitempty"`
+}
+
+// DiskIOTune mirroring libvirt XML under disk, a zero value means no limit
+type DiskIOTune struct {
+	TotalBytesSec uint64 `xml:"total_bytes_sec,omitempty"`
+	ReadBytesSec  uint64 `xml:"read_bytes_sec,omitempty"`
+	WriteBytesSec uint64 `xml:"write_bytes_sec,omitempty"`
+	TotalIopsSec  uint64 `xml:"total_iops_sec,omitempty"`
+	ReadIopsSec   uint64 `xml:"read_iops_sec,omitempty"`
+	WriteIopsSec  uint64 `xml:"write_iops_sec,omitempty"`
 }
 
 type DiskSourceHost struct {
@@ -484,6 +668,7 @@ type Serial struct {
 	Target *SerialTarget `xml:"target,omitempty"`
 	Source *SerialSource `xml:"source,omitempty"`
 	Alias  *Alias        `xml:"alias,omitempty"`
+	Log    *SerialLog    `xml:"log,omitempty"`
 }
 
 type SerialTarget struct {
@@ -495,6 +680,11 @@ type SerialSource struct {
 	Path string `xml:"path,attr,omitempty"`
 }
 
+type SerialLog struct {
+	File   string `xml:"file,attr,omitempty"`
+	Append string `xml:"append,attr,omitempty"`
+}
+
 // END Serial -----------------------------
 
 // BEGIN Console -----------------------------
@@ -521,25 +711,52 @@ type ConsoleSource struct {
 // BEGIN Inteface -----------------------------
 
 type Interface struct {
-	Address             *Address         `xml:"address,omitempty"`
-	Type                string           `xml:"type,attr"`
-	TrustGuestRxFilters string           `xml:"trustGuestRxFilters,attr,omitempty"`
-	Source              InterfaceSource  `xml:"source"`
-	Target              *InterfaceTarget `xml:"target,omitempty"`
-	Model               *Model           `xml:"model,omitempty"`
-	MAC                 *MAC             `xml:"mac,omitempty"`
-	MTU                 *MTU             `xml:"mtu,omitempty"`
-	BandWidth           *BandWidth       `xml:"bandwidth,omitempty"`
-	BootOrder           *BootOrder       `xml:"boot,omitempty"`
-	LinkState           *LinkState       `xml:"link,omitempty"`
-	FilterRef           *FilterRef       `xml:"filterref,omitempty"`
-	Alias               *Alias           `xml:"alias,omitempty"`
-	Driver              *InterfaceDriver `xml:"driver,omitempty"`
+	Address             *Address               `xml:"address,omitempty"`
+	Type                string                 `xml:"type,attr"`
+	Managed             string                 `xml:"managed,attr,omitempty"`
+	TrustGuestRxFilters string                 `xml:"trustGuestRxFilters,attr,omitempty"`
+	Source              InterfaceSource        `xml:"source"`
+	Target              *InterfaceTarget       `xml:"target,omitempty"`
+	Model               *Model                 `xml:"model,omitempty"`
+	MAC                 *MAC                   `xml:"mac,omitempty"`
+	MTU                 *MTU                   `xml:"mtu,omitempty"`
+	BandWidth           *BandWidth             `xml:"bandwidth,omitempty"`
+	BootOrder           *BootOrder             `xml:"boot,omitempty"`
+	LinkState           *LinkState             `xml:"link,omitempty"`
+	FilterRef           *FilterRef             `xml:"filterref,omitempty"`
+	Alias               *Alias                 `xml:"alias,omitempty"`
+	Driver              *InterfaceDriver       `xml:"driver,omitempty"`
+	Teaming             *Teaming               `xml:"teaming,omitempty"`
+	Backend             *InterfaceBackend      `xml:"backend,omitempty"`
+	PortForward         []InterfacePortForward `xml:"portForward,omitempty"`
+}
+
+// InterfaceBackend selects the process implementing a user interface, e.g. passt
+type InterfaceBackend struct {
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// InterfacePortForward forwards ports of the pod to the guest through passt
+type InterfacePortForward struct {
+	Proto  string                      `xml:"proto,attr"`
+	Ranges []InterfacePortForwardRange `xml:"range"`
+}
+
+type InterfacePortForwardRange struct {
+	Start uint `xml:"start,attr"`
+}
+
+// Teaming bonds a transient hostdev interface with a persistent virtio
+// interface in the guest, the transient one names the alias of the persistent one
+type Teaming struct {
+	Type       string `xml:"type,attr"`
+	Persistent string `xml:"persistent,attr,omitempty"`
 }
 
 type InterfaceDriver struct {
-	Name   string `xml:"name,attr"`
+	Name   string `xml:"name,attr,omitempty"`
 	Queues *uint  `xml:"queues,attr,omitempty"`
+	IOMMU  string `xml:"iommu,attr,omitempty"`
 }
 
 type LinkState struct {
@@ -547,6 +764,17 @@ type LinkState struct {
 }
 
 type BandWidth struct {
+	// Inbound is the traffic received by the guest
+	Inbound *BandWidthLimit `xml:"inbound,omitempty"`
+	// Outbound is the traffic sent by the guest
+	Outbound *BandWidthLimit `xml:"outbound,omitempty"`
+}
+
+// BandWidthLimit rates are in KiB per second, the burst in KiB
+type BandWidthLimit struct {
+	Average uint32 `xml:"average,attr"`
+	Peak    uint32 `xml:"peak,attr,omitempty"`
+	Burst   uint32 `xml:"burst,attr,omitempty"`
 }
 
 type BootOrder struct {
@@ -578,7 +806,8 @@ type Model struct {
 }
 
 type InterfaceTarget struct {
-	Device string `xml:"dev,attr"`
+	Device  string `xml:"dev,attr"`
+	Managed string `xml:"managed,attr,omitempty"`
 }
 
 type Alias struct {
@@ -764,9 +993,15 @@ type Stats struct {
 }
 
 type MemBalloon struct {
-	Model   string   `xml:"model,attr"`
-	Stats   *Stats   `xml:"stats,omitempty"`
-	Address *Address `xml:"address,emitempty"`
+	Model             string            `xml:"model,attr"`
+	FreePageReporting string            `xml:"freePageReporting,attr,omitempty"`
+	Stats             *Stats            `xml:"stats,omitempty"`
+	Address           *Address          `xml:"address,emitempty"`
+	Driver            *MemBalloonDriver `xml:"driver,omitempty"`
+}
+
+type MemBalloonDriver struct {
+	IOMMU string `xml:"iommu,attr,omitempty"`
 }
 
 type Watchdog struct {
@@ -783,6 +1018,12 @@ type Rng struct {
 	// Backend specifies the source of entropy to be used
 	Backend *RngBackend `xml:"backend,omitempty"`
 	Address *Address    `xml:"address,emitempty"`
+	Driver  *RngDriver  `xml:"driver,omitempty"`
+}
+
+// RngDriver configures the virtio transport of the RNG device
+type RngDriver struct {
+	IOMMU string `xml:"iommu,attr,omitempty"`
 }
 
 // RngRate sets the limiting factor how to read from entropy source
@@ -801,6 +1042,20 @@ type RngBackend struct {
 	Source string `xml:",chardata"`
 }
 
+// TPM represents an emulated TPM device
+type TPM struct {
+	// Model is the interface of the TPM towards the guest, e.g. tpm-tis
+	Model string `xml:"model,attr"`
+	// Backend is the emulator providing the TPM
+	Backend TPMBackend `xml:"backend"`
+}
+
+// TPMBackend is the emulator providing a TPM
+type TPMBackend struct {
+	Type    string `xml:"type,attr"`
+	Version string `xml:"version,attr,omitempty"`
+}
+
 type IOThreads struct {
 	IOThreads uint `xml:",chardata"`
 }
@@ -820,6 +1075,40 @@ type SecretSpec struct {
 	Usage       SecretUsage `xml:"usage,omitempty"`
 }
 
+// DomainBackup describes a pull mode backup job, see
+// https://libvirt.org/formatbackup.html
+type DomainBackup struct {
+	XMLName     xml.Name            `xml:"domainbackup"`
+	Mode        string              `xml:"mode,attr,omitempty"`
+	Incremental string              `xml:"incremental,omitempty"`
+	Server      *DomainBackupServer `xml:"server,omitempty"`
+	Disks       []DomainBackupDisk  `xml:"disks>disk"`
+}
+
+type DomainBackupServer struct {
+	Transport string `xml:"transport,attr,omitempty"`
+	Socket    string `xml:"socket,attr,omitempty"`
+}
+
+type DomainBackupDisk struct {
+	Name       string `xml:"name,attr"`
+	Backup     string `xml:"backup,attr,omitempty"`
+	ExportName string `xml:"exportname,attr,omitempty"`
+}
+
+// DomainCheckpoint describes a checkpoint which tracks the blocks changed
+// after a backup, see https://libvirt.org/formatcheckpoint.html
+type DomainCheckpoint struct {
+	XMLName xml.Name               `xml:"domaincheckpoint"`
+	Name    string                 `xml:"name"`
+	Disks   []DomainCheckpointDisk `xml:"disks>disk"`
+}
+
+type DomainCheckpointDisk struct {
+	Name       string `xml:"name,attr"`
+	Checkpoint string `xml:"checkpoint,attr,omitempty"`
+}
+
 func NewMinimalDomainSpec(vmiName string) *DomainSpec {
 	precond.MustNotBeEmpty(vmiName)
 	domain := &DomainSpec{}
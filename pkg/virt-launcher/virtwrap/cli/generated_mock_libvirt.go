@@ -84,6 +84,36 @@ func (_mr *_MockConnectionRecorder) AgentEventLifecycleRegister(arg0 interface{}
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "AgentEventLifecycleRegister", arg0)
 }
 
+func (_m *MockConnection) DomainEventDeviceAddedRegister(callback libvirt_go.DomainEventDeviceAddedCallback) error {
+	ret := _m.ctrl.Call(_m, "DomainEventDeviceAddedRegister", callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockConnectionRecorder) DomainEventDeviceAddedRegister(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DomainEventDeviceAddedRegister", arg0)
+}
+
+func (_m *MockConnection) DomainEventDeviceRemovedRegister(callback libvirt_go.DomainEventDeviceRemovedCallback) error {
+	ret := _m.ctrl.Call(_m, "DomainEventDeviceRemovedRegister", callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockConnectionRecorder) DomainEventDeviceRemovedRegister(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DomainEventDeviceRemovedRegister", arg0)
+}
+
+func (_m *MockConnection) DomainEventWatchdogRegister(callback libvirt_go.DomainEventWatchdogCallback) error {
+	ret := _m.ctrl.Call(_m, "DomainEventWatchdogRegister", callback)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockConnectionRecorder) DomainEventWatchdogRegister(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DomainEventWatchdogRegister", arg0)
+}
+
 func (_m *MockConnection) ListAllDomains(flags libvirt_go.ConnectListAllDomainsFlags) ([]VirDomain, error) {
 	ret := _m.ctrl.Call(_m, "ListAllDomains", flags)
 	ret0, _ := ret[0].([]VirDomain)
@@ -125,6 +155,17 @@ func (_mr *_MockConnectionRecorder) QemuAgentCommand(arg0, arg1 interface{}) *go
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "QemuAgentCommand", arg0, arg1)
 }
 
+func (_m *MockConnection) QemuMonitorCommand(command string, domainName string) (string, error) {
+	ret := _m.ctrl.Call(_m, "QemuMonitorCommand", command, domainName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConnectionRecorder) QemuMonitorCommand(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "QemuMonitorCommand", arg0, arg1)
+}
+
 func (_m *MockConnection) GetAllDomainStats(statsTypes libvirt_go.DomainStatsTypes, flags libvirt_go.ConnectGetAllDomainStatsFlags) ([]libvirt_go.DomainStats, error) {
 	ret := _m.ctrl.Call(_m, "GetAllDomainStats", statsTypes, flags)
 	ret0, _ := ret[0].([]libvirt_go.DomainStats)
@@ -420,6 +461,67 @@ func (_mr *_MockVirDomainRecorder) AbortJob() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "AbortJob")
 }
 
+func (_m *MockVirDomain) BackupBegin(backupXML string, checkpointXML string, flags libvirt_go.DomainBackupBeginFlags) error {
+	ret := _m.ctrl.Call(_m, "BackupBegin", backupXML, checkpointXML, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) BackupBegin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BackupBegin", arg0, arg1, arg2)
+}
+
+func (_m *MockVirDomain) CoreDumpWithFormat(to string, format libvirt_go.DomainCoreDumpFormat, flags libvirt_go.DomainCoreDumpFlags) error {
+	ret := _m.ctrl.Call(_m, "CoreDumpWithFormat", to, format, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) CoreDumpWithFormat(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CoreDumpWithFormat", arg0, arg1, arg2)
+}
+
+func (_m *MockVirDomain) AttachDeviceFlags(xml string, flags libvirt_go.DomainDeviceModifyFlags) error {
+	ret := _m.ctrl.Call(_m, "AttachDeviceFlags", xml, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) AttachDeviceFlags(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AttachDeviceFlags", arg0, arg1)
+}
+
+func (_m *MockVirDomain) DetachDeviceFlags(xml string, flags libvirt_go.DomainDeviceModifyFlags) error {
+	ret := _m.ctrl.Call(_m, "DetachDeviceFlags", xml, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) DetachDeviceFlags(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DetachDeviceFlags", arg0, arg1)
+}
+
+func (_m *MockVirDomain) GetBlockInfo(disk string, flags uint) (*libvirt_go.DomainBlockInfo, error) {
+	ret := _m.ctrl.Call(_m, "GetBlockInfo", disk, flags)
+	ret0, _ := ret[0].(*libvirt_go.DomainBlockInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirDomainRecorder) GetBlockInfo(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetBlockInfo", arg0, arg1)
+}
+
+func (_m *MockVirDomain) BlockResize(disk string, size uint64, flags libvirt_go.DomainBlockResizeFlags) error {
+	ret := _m.ctrl.Call(_m, "BlockResize", disk, size, flags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirDomainRecorder) BlockResize(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BlockResize", arg0, arg1, arg2)
+}
+
 func (_m *MockVirDomain) Free() error {
 	ret := _m.ctrl.Call(_m, "Free")
 	ret0, _ := ret[0].(error)
@@ -46,10 +46,14 @@ type Connection interface {
 	Close() (int, error)
 	DomainEventLifecycleRegister(callback libvirt.DomainEventLifecycleCallback) error
 	AgentEventLifecycleRegister(callback libvirt.DomainEventAgentLifecycleCallback) error
+	DomainEventDeviceAddedRegister(callback libvirt.DomainEventDeviceAddedCallback) error
+	DomainEventDeviceRemovedRegister(callback libvirt.DomainEventDeviceRemovedCallback) error
+	DomainEventWatchdogRegister(callback libvirt.DomainEventWatchdogCallback) error
 	ListAllDomains(flags libvirt.ConnectListAllDomainsFlags) ([]VirDomain, error)
 	NewStream(flags libvirt.StreamFlags) (Stream, error)
 	SetReconnectChan(reconnect chan bool)
 	QemuAgentCommand(command string, domainName string) (string, error)
+	QemuMonitorCommand(command string, domainName string) (string, error)
 	GetAllDomainStats(statsTypes libvirt.DomainStatsTypes, flags libvirt.ConnectGetAllDomainStatsFlags) ([]libvirt.DomainStats, error)
 	// helper method, not found in libvirt
 	// We add this helper to
@@ -77,8 +81,11 @@ type LibvirtConnection struct {
 	reconnect     chan bool
 	reconnectLock *sync.Mutex
 
-	domainEventCallbacks []libvirt.DomainEventLifecycleCallback
-	agentEventCallbacks  []libvirt.DomainEventAgentLifecycleCallback
+	domainEventCallbacks        []libvirt.DomainEventLifecycleCallback
+	agentEventCallbacks         []libvirt.DomainEventAgentLifecycleCallback
+	deviceAddedEventCallbacks   []libvirt.DomainEventDeviceAddedCallback
+	deviceRemovedEventCallbacks []libvirt.DomainEventDeviceRemovedCallback
+	watchdogEventCallbacks      []libvirt.DomainEventWatchdogCallback
 }
 
 func (s *VirStream) Write(p []byte) (n int, err error) {
@@ -154,6 +161,39 @@ func (l *LibvirtConnection) AgentEventLifecycleRegister(callback libvirt.DomainE
 	return
 }
 
+func (l *LibvirtConnection) DomainEventDeviceAddedRegister(callback libvirt.DomainEventDeviceAddedCallback) (err error) {
+	if err = l.reconnectIfNecessary(); err != nil {
+		return
+	}
+
+	l.deviceAddedEventCallbacks = append(l.deviceAddedEventCallbacks, callback)
+	_, err = l.Connect.DomainEventDeviceAddedRegister(nil, callback)
+	l.checkConnectionLost(err)
+	return
+}
+
+func (l *LibvirtConnection) DomainEventDeviceRemovedRegister(callback libvirt.DomainEventDeviceRemovedCallback) (err error) {
+	if err = l.reconnectIfNecessary(); err != nil {
+		return
+	}
+
+	l.deviceRemovedEventCallbacks = append(l.deviceRemovedEventCallbacks, callback)
+	_, err = l.Connect.DomainEventDeviceRemovedRegister(nil, callback)
+	l.checkConnectionLost(err)
+	return
+}
+
+func (l *LibvirtConnection) DomainEventWatchdogRegister(callback libvirt.DomainEventWatchdogCallback) (err error) {
+	if err = l.reconnectIfNecessary(); err != nil {
+		return
+	}
+
+	l.watchdogEventCallbacks = append(l.watchdogEventCallbacks, callback)
+	_, err = l.Connect.DomainEventWatchdogRegister(nil, callback)
+	l.checkConnectionLost(err)
+	return
+}
+
 func (l *LibvirtConnection) LookupDomainByName(name string) (dom VirDomain, err error) {
 	if err = l.reconnectIfNecessary(); err != nil {
 		return
@@ -206,6 +246,22 @@ func (l *LibvirtConnection) QemuAgentCommand(command string, domainName string)
 	return result, err
 }
 
+// Execute a command on the QEMU monitor, for commands libvirt does not wrap yet
+// command - the QMP command, for example this gets the dirty page rate: {"execute":"query-dirty-rate"}
+// domainName -  the qemu domain name
+func (l *LibvirtConnection) QemuMonitorCommand(command string, domainName string) (string, error) {
+	if err := l.reconnectIfNecessary(); err != nil {
+		return "", err
+	}
+	domain, err := l.Connect.LookupDomainByName(domainName)
+	if err != nil {
+		return "", err
+	}
+	defer domain.Free()
+	result, err := domain.QemuMonitorCommand(command, libvirt.DOMAIN_QEMU_MONITOR_COMMAND_DEFAULT)
+	return result, err
+}
+
 func (l *LibvirtConnection) GetAllDomainStats(statsTypes libvirt.DomainStatsTypes, flags libvirt.ConnectGetAllDomainStatsFlags) ([]libvirt.DomainStats, error) {
 	if err := l.reconnectIfNecessary(); err != nil {
 		return nil, err
@@ -307,6 +363,18 @@ func (l *LibvirtConnection) reconnectIfNecessary() (err error) {
 			log.Log.Info("Re-registered agent callback")
 			_, err = l.Connect.DomainEventAgentLifecycleRegister(nil, callback)
 		}
+		for _, callback := range l.deviceAddedEventCallbacks {
+			log.Log.Info("Re-registered device added callback")
+			_, err = l.Connect.DomainEventDeviceAddedRegister(nil, callback)
+		}
+		for _, callback := range l.deviceRemovedEventCallbacks {
+			log.Log.Info("Re-registered device removed callback")
+			_, err = l.Connect.DomainEventDeviceRemovedRegister(nil, callback)
+		}
+		for _, callback := range l.watchdogEventCallbacks {
+			log.Log.Info("Re-registered watchdog callback")
+			_, err = l.Connect.DomainEventWatchdogRegister(nil, callback)
+		}
 
 		log.Log.Error("Re-registered domain and agent callbacks for new connection")
 
@@ -366,6 +434,12 @@ type VirDomain interface {
 	GetJobInfo() (*libvirt.DomainJobInfo, error)
 	SetTime(secs int64, nsecs uint, flags libvirt.DomainSetTimeFlags) error
 	AbortJob() error
+	BackupBegin(backupXML string, checkpointXML string, flags libvirt.DomainBackupBeginFlags) error
+	CoreDumpWithFormat(to string, format libvirt.DomainCoreDumpFormat, flags libvirt.DomainCoreDumpFlags) error
+	AttachDeviceFlags(xml string, flags libvirt.DomainDeviceModifyFlags) error
+	DetachDeviceFlags(xml string, flags libvirt.DomainDeviceModifyFlags) error
+	GetBlockInfo(disk string, flags uint) (*libvirt.DomainBlockInfo, error)
+	BlockResize(disk string, size uint64, flags libvirt.DomainBlockResizeFlags) error
 	Free() error
 }
 
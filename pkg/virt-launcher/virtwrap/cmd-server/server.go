@@ -38,6 +38,9 @@ import (
 	launcherErrors "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/errors"
 )
 
+// defaultDomainStatsStreamInterval is used when the client does not ask for an interval
+const defaultDomainStatsStreamInterval = 10 * time.Second
+
 type ServerOptions struct {
 	useEmulation bool
 }
@@ -80,6 +83,20 @@ func getMigrationOptionsFromRequest(request *cmdv1.MigrationRequest) (*cmdclient
 	return options, nil
 }
 
+func getBackupOptionsFromRequest(request *cmdv1.BackupRequest) (*cmdclient.BackupOptions, error) {
+
+	if request.Options == nil {
+		return nil, fmt.Errorf("backup options object not present in command server request")
+	}
+
+	var options *cmdclient.BackupOptions
+	if err := json.Unmarshal(request.Options, &options); err != nil {
+		return nil, fmt.Errorf("no valid backup options object present in command server request: %v", err)
+	}
+
+	return options, nil
+}
+
 func getErrorMessage(err error) string {
 	if virErr := launcherErrors.FormatLibvirtError(err); virErr != "" {
 		return virErr
@@ -131,6 +148,87 @@ func (l *Launcher) CancelVirtualMachineMigration(ctx context.Context, request *c
 
 }
 
+// BackupVirtualMachine starts a pull mode backup and returns the volumes it exports
+func (l *Launcher) BackupVirtualMachine(ctx context.Context, request *cmdv1.BackupRequest) (*cmdv1.BackupResponse, error) {
+	response := &cmdv1.BackupResponse{}
+
+	vmi, vmiResponse := getVMIFromRequest(request.Vmi)
+	response.Response = vmiResponse
+	if !vmiResponse.Success {
+		return response, nil
+	}
+
+	options, err := getBackupOptionsFromRequest(request)
+	if err != nil {
+		response.Response.Success = false
+		response.Response.Message = err.Error()
+		return response, nil
+	}
+
+	volumes, err := l.domainManager.BackupVMI(vmi, options)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to start backup")
+		response.Response.Success = false
+		response.Response.Message = getErrorMessage(err)
+		return response, nil
+	}
+
+	if jVolumes, err := json.Marshal(volumes); err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to marshal backup volumes")
+		response.Response.Success = false
+		response.Response.Message = getErrorMessage(err)
+		return response, nil
+	} else {
+		response.BackupVolumes = string(jVolumes)
+	}
+
+	log.Log.Object(vmi).Infof("Started backup %s", options.Name)
+	return response, nil
+}
+
+func (l *Launcher) AbortVirtualMachineBackup(ctx context.Context, request *cmdv1.BackupRequest) (*cmdv1.Response, error) {
+
+	vmi, response := getVMIFromRequest(request.Vmi)
+	if !response.Success {
+		return response, nil
+	}
+
+	options, err := getBackupOptionsFromRequest(request)
+	if err != nil {
+		response.Success = false
+		response.Message = err.Error()
+		return response, nil
+	}
+
+	if err := l.domainManager.AbortVMIBackup(vmi, options); err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to abort backup")
+		response.Success = false
+		response.Message = getErrorMessage(err)
+		return response, nil
+	}
+
+	log.Log.Object(vmi).Infof("Aborted backup %s", options.Name)
+	return response, nil
+}
+
+func (l *Launcher) MemoryDumpVirtualMachine(ctx context.Context, request *cmdv1.MemoryDumpRequest) (*cmdv1.Response, error) {
+
+	vmi, response := getVMIFromRequest(request.Vmi)
+	if !response.Success {
+		return response, nil
+	}
+
+	if err := l.domainManager.MemoryDumpVMI(vmi, request.FileName); err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to start memory dump")
+		response.Success = false
+		response.Message = getErrorMessage(err)
+		return response, nil
+	}
+
+	log.Log.Object(vmi).Infof("Started memory dump to %s", request.FileName)
+	return response, nil
+}
+
 func (l *Launcher) SyncMigrationTarget(ctx context.Context, request *cmdv1.VMIRequest) (*cmdv1.Response, error) {
 
 	vmi, response := getVMIFromRequest(request.Vmi)
@@ -202,6 +300,40 @@ func (l *Launcher) UnpauseVirtualMachine(ctx context.Context, request *cmdv1.VMI
 	return response, nil
 }
 
+func (l *Launcher) FreezeVirtualMachine(ctx context.Context, request *cmdv1.VMIRequest) (*cmdv1.Response, error) {
+	vmi, response := getVMIFromRequest(request.Vmi)
+	if !response.Success {
+		return response, nil
+	}
+
+	if err := l.domainManager.FreezeVMI(vmi); err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to freeze vmi")
+		response.Success = false
+		response.Message = getErrorMessage(err)
+		return response, nil
+	}
+
+	log.Log.Object(vmi).Info("Froze vmi")
+	return response, nil
+}
+
+func (l *Launcher) UnfreezeVirtualMachine(ctx context.Context, request *cmdv1.VMIRequest) (*cmdv1.Response, error) {
+	vmi, response := getVMIFromRequest(request.Vmi)
+	if !response.Success {
+		return response, nil
+	}
+
+	if err := l.domainManager.UnfreezeVMI(vmi); err != nil {
+		log.Log.Object(vmi).Reason(err).Errorf("Failed to unfreeze vmi")
+		response.Success = false
+		response.Message = getErrorMessage(err)
+		return response, nil
+	}
+
+	log.Log.Object(vmi).Info("Unfroze vmi")
+	return response, nil
+}
+
 func (l *Launcher) KillVirtualMachine(ctx context.Context, request *cmdv1.VMIRequest) (*cmdv1.Response, error) {
 
 	vmi, response := getVMIFromRequest(request.Vmi)
@@ -305,6 +437,31 @@ func (l *Launcher) GetDomain(ctx context.Context, request *cmdv1.EmptyRequest) (
 }
 
 func (l *Launcher) GetDomainStats(ctx context.Context, request *cmdv1.EmptyRequest) (*cmdv1.DomainStatsResponse, error) {
+	return l.getDomainStats(), nil
+}
+
+// StreamDomainStats pushes the stats of the domain every interval, until the client goes away
+func (l *Launcher) StreamDomainStats(request *cmdv1.DomainStatsStreamRequest, stream cmdv1.Cmd_StreamDomainStatsServer) error {
+	interval := defaultDomainStatsStreamInterval
+	if request.IntervalSeconds > 0 {
+		interval = time.Duration(request.IntervalSeconds) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(l.getDomainStats()); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *Launcher) getDomainStats() *cmdv1.DomainStatsResponse {
 
 	response := &cmdv1.DomainStatsResponse{
 		Response: &cmdv1.Response{
@@ -316,7 +473,7 @@ func (l *Launcher) GetDomainStats(ctx context.Context, request *cmdv1.EmptyReque
 	if err != nil {
 		response.Response.Success = false
 		response.Response.Message = getErrorMessage(err)
-		return response, nil
+		return response
 	}
 
 	if len(list) > 0 {
@@ -324,13 +481,13 @@ func (l *Launcher) GetDomainStats(ctx context.Context, request *cmdv1.EmptyReque
 			log.Log.Reason(err).Errorf("Failed to marshal domain stats")
 			response.Response.Success = false
 			response.Response.Message = getErrorMessage(err)
-			return response, nil
+			return response
 		} else {
 			response.DomainStats = string(domainStats)
 		}
 	}
 
-	return response, nil
+	return response
 }
 
 // GetGuestInfo collect guest info from the domain
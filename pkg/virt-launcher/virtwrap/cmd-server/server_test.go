@@ -28,6 +28,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -120,6 +121,50 @@ var _ = Describe("Virt remote commands", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should freeze a vmi", func() {
+			vmi := v1.NewVMIReferenceFromName("testvmi")
+			domainManager.EXPECT().FreezeVMI(vmi)
+			err := client.FreezeVirtualMachine(vmi)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should unfreeze a vmi", func() {
+			vmi := v1.NewVMIReferenceFromName("testvmi")
+			domainManager.EXPECT().UnfreezeVMI(vmi)
+			err := client.UnfreezeVirtualMachine(vmi)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should start a backup and return the exported volumes", func() {
+			vmi := v1.NewVMIReferenceFromName("testvmi")
+			options := &cmdclient.BackupOptions{Name: "backup2", Incremental: "backup1"}
+			volumes := []v1.VirtualMachineBackupVolume{
+				{Name: "rootdisk", ExportName: "vda", DirtyBitmap: "backup-vda"},
+			}
+			domainManager.EXPECT().BackupVMI(vmi, options).Return(volumes, nil)
+
+			fetched, err := client.BackupVirtualMachine(vmi, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetched).To(Equal(volumes))
+		})
+
+		It("should abort a backup", func() {
+			vmi := v1.NewVMIReferenceFromName("testvmi")
+			options := &cmdclient.BackupOptions{Name: "backup1"}
+			domainManager.EXPECT().AbortVMIBackup(vmi, options)
+
+			err := client.AbortVirtualMachineBackup(vmi, options)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should start a memory dump", func() {
+			vmi := v1.NewVMIReferenceFromName("testvmi")
+			domainManager.EXPECT().MemoryDumpVMI(vmi, "testvmi.memory.dump")
+
+			err := client.MemoryDumpVirtualMachine(vmi, "testvmi.memory.dump")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("should list domains", func() {
 			var list []*api.Domain
 			list = append(list, api.NewMinimalDomain("testvmi1"))
@@ -180,6 +225,27 @@ var _ = Describe("Virt remote commands", func() {
 			Expect(domStats.UUID).To(Equal(list[0].UUID))
 		})
 
+		It("should stream domain stats", func() {
+			list := []*stats.DomainStats{
+				{Name: "testvmstats1"},
+			}
+			domainManager.EXPECT().GetDomainStats().Return(list, nil).MinTimes(2)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var names []string
+			err := client.StreamDomainStats(ctx, time.Second, func(domStats *stats.DomainStats, exists bool) {
+				Expect(exists).To(BeTrue())
+				names = append(names, domStats.Name)
+				if len(names) == 2 {
+					cancel()
+				}
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"testvmstats1", "testvmstats1"}))
+		})
+
 		It("should return full user list", func() {
 			userList := []v1.VirtualMachineInstanceGuestOSUser{
 				v1.VirtualMachineInstanceGuestOSUser{
@@ -0,0 +1,129 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+const (
+	queryDirtyRateCommand = `{"execute":"query-dirty-rate"}`
+	// QEMU samples the guest memory for a second to estimate the rate
+	calcDirtyRateCommand = `{"execute":"calc-dirty-rate","arguments":{"calc-time":1}}`
+)
+
+// States of a dirty rate measurement of QEMU
+const (
+	dirtyRateStatusMeasuring = "measuring"
+	dirtyRateStatusMeasured  = "measured"
+)
+
+// qmpCommandNotFound is the class of the error QEMU returns for commands it does not know
+const qmpCommandNotFound = "CommandNotFound"
+
+type qmpError struct {
+	Class string `json:"class"`
+	Desc  string `json:"desc"`
+}
+
+type queryDirtyRateReply struct {
+	Return *struct {
+		Status string `json:"status"`
+		// DirtyRate is in MiB per second, only set once the measurement completed
+		DirtyRate *uint64 `json:"dirty-rate"`
+	} `json:"return"`
+	Error *qmpError `json:"error"`
+}
+
+type calcDirtyRateReply struct {
+	Error *qmpError `json:"error"`
+}
+
+// dirtyRateMeter keeps QEMU measuring the rate at which the guest dirties its
+// memory, which tells whether a migration can converge. Each stats sample
+// reports the last measurement and starts the next one.
+type dirtyRateMeter struct {
+	lock sync.Mutex
+	// unsupported is set once QEMU turned out to predate the dirty rate commands
+	unsupported bool
+}
+
+func newDirtyRateMeter() *dirtyRateMeter {
+	return &dirtyRateMeter{}
+}
+
+// observe returns the last dirty rate measured for the domain, if any
+func (m *dirtyRateMeter) observe(virConn cli.Connection, domainName string) (*stats.DomainStatsDirtyRate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.unsupported {
+		return nil, nil
+	}
+
+	query := &queryDirtyRateReply{}
+	if err := qemuMonitorCommand(virConn, domainName, queryDirtyRateCommand, query); err != nil {
+		return nil, err
+	}
+	if query.Error != nil {
+		if query.Error.Class == qmpCommandNotFound {
+			m.unsupported = true
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query the dirty rate: %s", query.Error.Desc)
+	}
+	if query.Return == nil {
+		return nil, fmt.Errorf("failed to query the dirty rate: empty reply")
+	}
+
+	var dirtyRate *stats.DomainStatsDirtyRate
+	if query.Return.Status == dirtyRateStatusMeasured && query.Return.DirtyRate != nil {
+		dirtyRate = &stats.DomainStatsDirtyRate{
+			BytesPerSecond: *query.Return.DirtyRate << 20,
+		}
+	}
+
+	if query.Return.Status != dirtyRateStatusMeasuring {
+		calc := &calcDirtyRateReply{}
+		if err := qemuMonitorCommand(virConn, domainName, calcDirtyRateCommand, calc); err != nil {
+			return dirtyRate, err
+		}
+		if calc.Error != nil {
+			return dirtyRate, fmt.Errorf("failed to measure the dirty rate: %s", calc.Error.Desc)
+		}
+	}
+	return dirtyRate, nil
+}
+
+func qemuMonitorCommand(virConn cli.Connection, domainName string, command string, reply interface{}) error {
+	result, err := virConn.QemuMonitorCommand(command, domainName)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(result), reply); err != nil {
+		return fmt.Errorf("failed to parse the reply to %s: %v", command, err)
+	}
+	return nil
+}
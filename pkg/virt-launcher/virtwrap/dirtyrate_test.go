@@ -0,0 +1,100 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var _ = Describe("Dirty rate", func() {
+	const domainName = "testnamespace_testvmi"
+
+	var ctrl *gomock.Controller
+	var mockConn *cli.MockConnection
+	var meter *dirtyRateMeter
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockConn = cli.NewMockConnection(ctrl)
+		meter = newDirtyRateMeter()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectQuery := func(reply string) {
+		mockConn.EXPECT().QemuMonitorCommand(queryDirtyRateCommand, domainName).Return(reply, nil)
+	}
+
+	expectCalc := func() {
+		mockConn.EXPECT().QemuMonitorCommand(calcDirtyRateCommand, domainName).Return(`{"return":{}}`, nil)
+	}
+
+	It("should start the first measurement", func() {
+		expectQuery(`{"return":{"status":"unstarted","start-time":0,"calc-time":0}}`)
+		expectCalc()
+
+		dirtyRate, err := meter.observe(mockConn, domainName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirtyRate).To(BeNil())
+	})
+
+	It("should report the last measurement and start the next one", func() {
+		expectQuery(`{"return":{"status":"measured","dirty-rate":64,"start-time":1234,"calc-time":1}}`)
+		expectCalc()
+
+		dirtyRate, err := meter.observe(mockConn, domainName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirtyRate).To(Equal(&stats.DomainStatsDirtyRate{BytesPerSecond: 64 << 20}))
+	})
+
+	It("should not start a measurement while one is running", func() {
+		expectQuery(`{"return":{"status":"measuring","start-time":1234,"calc-time":1}}`)
+
+		dirtyRate, err := meter.observe(mockConn, domainName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirtyRate).To(BeNil())
+	})
+
+	It("should stop asking QEMU versions which can't measure the dirty rate", func() {
+		expectQuery(`{"error":{"class":"CommandNotFound","desc":"The command query-dirty-rate has not been found"}}`)
+
+		dirtyRate, err := meter.observe(mockConn, domainName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirtyRate).To(BeNil())
+
+		dirtyRate, err = meter.observe(mockConn, domainName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirtyRate).To(BeNil())
+	})
+
+	It("should fail on other errors of QEMU", func() {
+		expectQuery(`{"error":{"class":"GenericError","desc":"boom"}}`)
+
+		_, err := meter.observe(mockConn, domainName)
+		Expect(err).To(MatchError("failed to query the dirty rate: boom"))
+	})
+})
@@ -0,0 +1,146 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"libvirt.org/libvirt-go"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	hostdisk "kubevirt.io/kubevirt/pkg/host-disk"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+// diskExpandedReason is added in an event if the guest was given the additional space of an expanded PersistentVolumeClaim
+const diskExpandedReason = "DiskExpanded"
+
+// The sizes of the backing storage of the disks, replaced in tests
+var (
+	blockDeviceSize = func(path string) (uint64, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		size, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(size), nil
+	}
+	dirBytesAvailable = func(path string) (uint64, error) {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			return 0, err
+		}
+		return stat.Bavail * uint64(stat.Bsize), nil
+	}
+)
+
+// diskExpansion is a disk which the guest sees larger after its PersistentVolumeClaim was expanded
+type diskExpansion struct {
+	name    string
+	oldSize uint64
+	newSize uint64
+}
+
+// syncExpandedDisks resizes the raw disks of a running domain whose backing storage grew. Block volumes grow
+// with their PersistentVolumeClaim, the images of filesystem volumes are grown up to the capacity of the
+// PersistentVolumeClaim virt-handler passes in the host disk of the volume.
+func syncExpandedDisks(vmi *v1.VirtualMachineInstance, dom cli.VirDomain, spec *api.DomainSpec) ([]diskExpansion, error) {
+	hostDisks := map[string]*v1.HostDisk{}
+	for _, volume := range vmi.Spec.Volumes {
+		if volume.HostDisk != nil && volume.HostDisk.Type == v1.HostDiskExistsOrCreate {
+			hostDisks[volume.Name] = volume.HostDisk
+		}
+	}
+
+	var expansions []diskExpansion
+	for _, disk := range spec.Devices.Disks {
+		if disk.Device != "disk" || disk.Driver == nil || disk.Driver.Type != "raw" || disk.ReadOnly != nil || disk.Alias == nil {
+			continue
+		}
+
+		var size uint64
+		var err error
+		if disk.Source.Dev != "" {
+			size, err = blockDeviceSize(disk.Source.Dev)
+		} else if hostDisk, exists := hostDisks[disk.Alias.Name]; exists && disk.Source.File == hostdisk.GetMountedHostDiskPath(disk.Alias.Name, hostDisk.Path) {
+			size, err = growHostDiskImage(disk.Source.File, hostDisk)
+		} else {
+			continue
+		}
+		if err != nil {
+			return expansions, fmt.Errorf("failed to get the size of the storage of disk %s: %v", disk.Alias.Name, err)
+		}
+
+		info, err := dom.GetBlockInfo(disk.Target.Device, 0)
+		if err != nil {
+			return expansions, fmt.Errorf("failed to get the capacity of disk %s: %v", disk.Alias.Name, err)
+		}
+		if size <= info.Capacity {
+			continue
+		}
+		if err := dom.BlockResize(disk.Target.Device, size, libvirt.DOMAIN_BLOCK_RESIZE_BYTES); err != nil {
+			return expansions, fmt.Errorf("failed to resize disk %s: %v", disk.Alias.Name, err)
+		}
+		log.Log.Object(vmi).Infof("Resized disk %s from %d to %d bytes", disk.Alias.Name, info.Capacity, size)
+		expansions = append(expansions, diskExpansion{name: disk.Alias.Name, oldSize: info.Capacity, newSize: size})
+	}
+	return expansions, nil
+}
+
+// growHostDiskImage grows the image of a host disk to the capacity of its PersistentVolumeClaim, or to the space
+// left on the volume if the storage provisioner gave less, and returns its size
+func growHostDiskImage(path string, hostDisk *v1.HostDisk) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	capacity, _ := hostDisk.Capacity.AsInt64()
+	if capacity <= size {
+		return uint64(size), nil
+	}
+
+	available, err := dirBytesAvailable(filepath.Dir(path))
+	if err != nil {
+		return 0, err
+	}
+	newSize := capacity
+	if uint64(newSize-size) > available {
+		newSize = size + int64(available)
+	}
+	if newSize <= size {
+		return uint64(size), nil
+	}
+	// the image is a sparse file, growing it does not allocate the space
+	if err := os.Truncate(path, newSize); err != nil {
+		return 0, err
+	}
+	return uint64(newSize), nil
+}
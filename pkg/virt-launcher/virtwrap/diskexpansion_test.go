@@ -0,0 +1,158 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	libvirt "libvirt.org/libvirt-go"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+var _ = Describe("Disk expansion", func() {
+	var ctrl *gomock.Controller
+	var mockDomain *cli.MockVirDomain
+	var vmi *v1.VirtualMachineInstance
+	var spec *api.DomainSpec
+	var deviceSize uint64
+	var origBlockDeviceSize func(string) (uint64, error)
+
+	blockDisk := api.Disk{
+		Device: "disk",
+		Type:   "block",
+		Source: api.DiskSource{Dev: "/dev/blockpvc"},
+		Target: api.DiskTarget{Bus: "virtio", Device: "vda"},
+		Driver: &api.DiskDriver{Name: "qemu", Type: "raw"},
+		Alias:  &api.Alias{Name: "blockpvc"},
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockDomain = cli.NewMockVirDomain(ctrl)
+		vmi = v1.NewMinimalVMI("testvmi")
+		spec = api.NewMinimalDomainSpec("testvmi")
+		spec.Devices.Disks = []api.Disk{blockDisk}
+
+		origBlockDeviceSize = blockDeviceSize
+		blockDeviceSize = func(path string) (uint64, error) {
+			Expect(path).To(Equal("/dev/blockpvc"))
+			return deviceSize, nil
+		}
+	})
+
+	AfterEach(func() {
+		blockDeviceSize = origBlockDeviceSize
+		ctrl.Finish()
+	})
+
+	It("should resize a disk whose block volume grew", func() {
+		deviceSize = 2048
+		mockDomain.EXPECT().GetBlockInfo("vda", uint(0)).Return(&libvirt.DomainBlockInfo{Capacity: 1024}, nil)
+		mockDomain.EXPECT().BlockResize("vda", uint64(2048), libvirt.DOMAIN_BLOCK_RESIZE_BYTES).Return(nil)
+
+		expansions, err := syncExpandedDisks(vmi, mockDomain, spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expansions).To(ConsistOf(diskExpansion{name: "blockpvc", oldSize: 1024, newSize: 2048}))
+	})
+
+	It("should not resize a disk whose block volume kept its size", func() {
+		deviceSize = 1024
+		mockDomain.EXPECT().GetBlockInfo("vda", uint(0)).Return(&libvirt.DomainBlockInfo{Capacity: 1024}, nil)
+
+		expansions, err := syncExpandedDisks(vmi, mockDomain, spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expansions).To(BeEmpty())
+	})
+
+	It("should not resize read-only and non-raw disks", func() {
+		readOnly := blockDisk
+		readOnly.ReadOnly = &api.ReadOnly{}
+		qcow2 := blockDisk
+		qcow2.Driver = &api.DiskDriver{Name: "qemu", Type: "qcow2"}
+		spec.Devices.Disks = []api.Disk{readOnly, qcow2}
+
+		expansions, err := syncExpandedDisks(vmi, mockDomain, spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expansions).To(BeEmpty())
+	})
+
+	Context("with the image of a host disk", func() {
+		var tmpDir string
+		var image string
+		var available uint64
+		var origDirBytesAvailable func(string) (uint64, error)
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "diskexpansion")
+			Expect(err).ToNot(HaveOccurred())
+			image = filepath.Join(tmpDir, "disk.img")
+			Expect(ioutil.WriteFile(image, make([]byte, 1024), 0644)).To(Succeed())
+
+			available = 1 << 20
+			origDirBytesAvailable = dirBytesAvailable
+			dirBytesAvailable = func(string) (uint64, error) {
+				return available, nil
+			}
+		})
+
+		AfterEach(func() {
+			dirBytesAvailable = origDirBytesAvailable
+			os.RemoveAll(tmpDir)
+		})
+
+		imageSize := func() int64 {
+			info, err := os.Stat(image)
+			Expect(err).ToNot(HaveOccurred())
+			return info.Size()
+		}
+
+		It("should grow the image to the capacity of the volume", func() {
+			size, err := growHostDiskImage(image, &v1.HostDisk{Capacity: resource.MustParse("4Ki")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(uint64(4096)))
+			Expect(imageSize()).To(Equal(int64(4096)))
+		})
+
+		It("should grow the image only up to the space left on the volume", func() {
+			available = 1024
+			size, err := growHostDiskImage(image, &v1.HostDisk{Capacity: resource.MustParse("4Ki")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(uint64(2048)))
+			Expect(imageSize()).To(Equal(int64(2048)))
+		})
+
+		It("should not shrink the image", func() {
+			size, err := growHostDiskImage(image, &v1.HostDisk{Capacity: resource.MustParse("512")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(Equal(uint64(1024)))
+			Expect(imageSize()).To(Equal(int64(1024)))
+		})
+	})
+})
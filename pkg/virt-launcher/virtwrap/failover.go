@@ -0,0 +1,136 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	"libvirt.org/libvirt-go"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+// The guest has to release the VFs before the migration can start, unplugging
+// a PCI device needs the cooperation of the guest kernel
+var (
+	failoverDetachInterval = 1 * time.Second
+	failoverDetachTimeout  = 60 * time.Second
+)
+
+// failoverVFs returns the hostdev interfaces which are teamed with a virtio
+// standby interface
+func failoverVFs(devices api.Devices) []api.Interface {
+	var vfs []api.Interface
+	for _, iface := range devices.Interfaces {
+		if iface.Type == "hostdev" && iface.Teaming != nil && iface.Teaming.Type == "transient" {
+			vfs = append(vfs, iface)
+		}
+	}
+	return vfs
+}
+
+func interfaceXML(iface api.Interface) (string, error) {
+	var buf bytes.Buffer
+	if err := xml.NewEncoder(&buf).EncodeElement(iface, xml.StartElement{Name: xml.Name{Local: "interface"}}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// detachFailoverVFs unplugs the VFs of the SR-IOV interfaces with a failover
+// standby from the running domain and waits until the guest released them. If
+// the migration fails, the next sync of the domain plugs them back.
+func detachFailoverVFs(vmi *v1.VirtualMachineInstance, dom cli.VirDomain) error {
+	devices, err := getAllDomainDevices(dom)
+	if err != nil {
+		return err
+	}
+	vfs := failoverVFs(devices)
+	if len(vfs) == 0 {
+		return nil
+	}
+
+	for _, vf := range vfs {
+		ifaceXML, err := interfaceXML(vf)
+		if err != nil {
+			return err
+		}
+		if err := dom.DetachDeviceFlags(ifaceXML, libvirt.DOMAIN_DEVICE_MODIFY_LIVE); err != nil {
+			return fmt.Errorf("failed to detach the VF of interface %s: %v", vf.Alias.Name, err)
+		}
+		log.Log.Object(vmi).Infof("Detaching the VF of interface %s for the migration", vf.Alias.Name)
+	}
+
+	err = utilwait.PollImmediate(failoverDetachInterval, failoverDetachTimeout, func() (bool, error) {
+		devices, err := getAllDomainDevices(dom)
+		if err != nil {
+			return false, err
+		}
+		return len(failoverVFs(devices)) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the guest did not release the VFs of the SR-IOV interfaces: %v", err)
+	}
+	return nil
+}
+
+// syncFailoverVFs plugs the VFs of the desired domain which are missing in the
+// running domain, they were detached for a migration. Nothing is attached while
+// the migration is running, the target attaches its own VFs once it took over.
+func syncFailoverVFs(vmi *v1.VirtualMachineInstance, dom cli.VirDomain, spec *api.DomainSpec) error {
+	if vmi.Status.MigrationState != nil && !vmi.Status.MigrationState.Completed {
+		return nil
+	}
+	wanted := failoverVFs(spec.Devices)
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	devices, err := getAllDomainDevices(dom)
+	if err != nil {
+		return err
+	}
+	attached := map[string]bool{}
+	for _, vf := range failoverVFs(devices) {
+		attached[vf.Alias.Name] = true
+	}
+
+	for _, vf := range wanted {
+		if attached[vf.Alias.Name] {
+			continue
+		}
+		ifaceXML, err := interfaceXML(vf)
+		if err != nil {
+			return err
+		}
+		if err := dom.AttachDeviceFlags(ifaceXML, libvirt.DOMAIN_DEVICE_MODIFY_LIVE); err != nil {
+			return fmt.Errorf("failed to attach the VF of interface %s: %v", vf.Alias.Name, err)
+		}
+		log.Log.Object(vmi).Infof("Attached the VF of interface %s", vf.Alias.Name)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	libvirt "libvirt.org/libvirt-go"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+var _ = Describe("SR-IOV failover", func() {
+	var ctrl *gomock.Controller
+	var mockDomain *cli.MockVirDomain
+	var vmi *v1.VirtualMachineInstance
+
+	vf := api.Interface{
+		Type:    "hostdev",
+		Managed: "yes",
+		Source:  api.InterfaceSource{Address: &api.Address{Type: "pci", Domain: "0x0000", Bus: "0x81", Slot: "0x11", Function: "0x1"}},
+		Alias:   &api.Alias{Name: "sriov"},
+		Teaming: &api.Teaming{Type: "transient", Persistent: "ua-standby"},
+	}
+	standby := api.Interface{
+		Type:    "bridge",
+		Alias:   &api.Alias{Name: "standby"},
+		Teaming: &api.Teaming{Type: "persistent"},
+	}
+
+	domainXML := func(ifaces ...api.Interface) string {
+		spec := api.NewMinimalDomainSpec("testvmi")
+		spec.Devices.Interfaces = ifaces
+		data, err := xml.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockDomain = cli.NewMockVirDomain(ctrl)
+		vmi = v1.NewMinimalVMI("testvmi")
+		failoverDetachInterval = 10 * time.Millisecond
+		failoverDetachTimeout = 100 * time.Millisecond
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should detach the VFs and wait until the guest released them", func() {
+		gomock.InOrder(
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(domainXML(standby, vf), nil),
+			mockDomain.EXPECT().DetachDeviceFlags(gomock.Any(), libvirt.DOMAIN_DEVICE_MODIFY_LIVE).Return(nil),
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(domainXML(standby, vf), nil),
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(domainXML(standby), nil),
+		)
+		Expect(detachFailoverVFs(vmi, mockDomain)).To(Succeed())
+	})
+
+	It("should fail the migration if the guest keeps the VFs", func() {
+		mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(domainXML(standby, vf), nil).AnyTimes()
+		mockDomain.EXPECT().DetachDeviceFlags(gomock.Any(), libvirt.DOMAIN_DEVICE_MODIFY_LIVE).Return(nil)
+		Expect(detachFailoverVFs(vmi, mockDomain)).ToNot(Succeed())
+	})
+
+	It("should attach the missing VFs once the migration completed", func() {
+		vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{Completed: true}
+		spec := api.NewMinimalDomainSpec("testvmi")
+		spec.Devices.Interfaces = []api.Interface{standby, vf}
+
+		mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(domainXML(standby), nil)
+		mockDomain.EXPECT().AttachDeviceFlags(gomock.Any(), libvirt.DOMAIN_DEVICE_MODIFY_LIVE).Do(func(ifaceXML string, _ libvirt.DomainDeviceModifyFlags) {
+			Expect(ifaceXML).To(HavePrefix(`<interface type="hostdev" managed="yes">`))
+			Expect(ifaceXML).To(ContainSubstring(`<teaming type="transient" persistent="ua-standby"></teaming>`))
+		}).Return(nil)
+		Expect(syncFailoverVFs(vmi, mockDomain, spec)).To(Succeed())
+	})
+
+	It("should not attach VFs while the migration is running", func() {
+		vmi.Status.MigrationState = &v1.VirtualMachineInstanceMigrationState{}
+		spec := api.NewMinimalDomainSpec("testvmi")
+		spec.Devices.Interfaces = []api.Interface{standby, vf}
+
+		Expect(syncFailoverVFs(vmi, mockDomain, spec)).To(Succeed())
+	})
+})
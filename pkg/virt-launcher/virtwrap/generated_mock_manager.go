@@ -65,6 +65,26 @@ func (_mr *_MockDomainManagerRecorder) UnpauseVMI(arg0 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "UnpauseVMI", arg0)
 }
 
+func (_m *MockDomainManager) FreezeVMI(_param0 *v1.VirtualMachineInstance) error {
+	ret := _m.ctrl.Call(_m, "FreezeVMI", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) FreezeVMI(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FreezeVMI", arg0)
+}
+
+func (_m *MockDomainManager) UnfreezeVMI(_param0 *v1.VirtualMachineInstance) error {
+	ret := _m.ctrl.Call(_m, "UnfreezeVMI", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) UnfreezeVMI(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UnfreezeVMI", arg0)
+}
+
 func (_m *MockDomainManager) KillVMI(_param0 *v1.VirtualMachineInstance) error {
 	ret := _m.ctrl.Call(_m, "KillVMI", _param0)
 	ret0, _ := ret[0].(error)
@@ -157,6 +177,37 @@ func (_mr *_MockDomainManagerRecorder) CancelVMIMigration(arg0 interface{}) *gom
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CancelVMIMigration", arg0)
 }
 
+func (_m *MockDomainManager) BackupVMI(_param0 *v1.VirtualMachineInstance, _param1 *cmd_client.BackupOptions) ([]v1.VirtualMachineBackupVolume, error) {
+	ret := _m.ctrl.Call(_m, "BackupVMI", _param0, _param1)
+	ret0, _ := ret[0].([]v1.VirtualMachineBackupVolume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockDomainManagerRecorder) BackupVMI(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BackupVMI", arg0, arg1)
+}
+
+func (_m *MockDomainManager) AbortVMIBackup(_param0 *v1.VirtualMachineInstance, _param1 *cmd_client.BackupOptions) error {
+	ret := _m.ctrl.Call(_m, "AbortVMIBackup", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) AbortVMIBackup(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AbortVMIBackup", arg0, arg1)
+}
+
+func (_m *MockDomainManager) MemoryDumpVMI(_param0 *v1.VirtualMachineInstance, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "MemoryDumpVMI", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDomainManagerRecorder) MemoryDumpVMI(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "MemoryDumpVMI", arg0, arg1)
+}
+
 func (_m *MockDomainManager) GetGuestInfo() (v1.VirtualMachineInstanceGuestAgentInfo, error) {
 	ret := _m.ctrl.Call(_m, "GetGuestInfo")
 	ret0, _ := ret[0].(v1.VirtualMachineInstanceGuestAgentInfo)
@@ -0,0 +1,97 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+// hugetlbUsageFiles match the files of the hugetlb controller reporting the
+// hugepages usage of the cgroup of the launcher per page size, on cgroup v1
+// and v2
+var hugetlbUsageFiles = []string{
+	"/sys/fs/cgroup/hugetlb/hugetlb.*.usage_in_bytes",
+	"/sys/fs/cgroup/hugetlb.*.current",
+}
+
+// parseHugetlbPageSize parses the page size the kernel uses in the names of the
+// hugetlb controller files, e.g. 2MB or 1GB
+func parseHugetlbPageSize(size string) (uint64, error) {
+	units := map[string]uint64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+	for suffix, unit := range units {
+		if strings.HasSuffix(size, suffix) {
+			value, err := strconv.ParseUint(strings.TrimSuffix(size, suffix), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return value * unit, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hugepages size %s", size)
+}
+
+// getHugepagesStats reports the hugepages charged to the cgroup of the
+// launcher pod per page size, which confirms that the guest memory is backed
+// by them.
+func getHugepagesStats() ([]stats.DomainStatsHugepages, error) {
+	for _, pattern := range hugetlbUsageFiles {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		var hugepagesStats []stats.DomainStatsHugepages
+		for _, file := range files {
+			// skip the reservations, e.g. hugetlb.2MB.rsvd.current
+			parts := strings.Split(filepath.Base(file), ".")
+			if len(parts) != 3 {
+				continue
+			}
+			pageSize, err := parseHugetlbPageSize(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			usage, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+			}
+			hugepagesStats = append(hugepagesStats, stats.DomainStatsHugepages{PageSize: pageSize, Usage: usage})
+		}
+		sort.Slice(hugepagesStats, func(i, j int) bool {
+			return hugepagesStats[i].PageSize < hugepagesStats[j].PageSize
+		})
+		return hugepagesStats, nil
+	}
+	return nil, nil
+}
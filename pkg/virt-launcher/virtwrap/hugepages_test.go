@@ -0,0 +1,87 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var _ = Describe("Hugepages stats", func() {
+	var cgroupDir string
+	var origUsageFiles []string
+
+	BeforeEach(func() {
+		var err error
+		cgroupDir, err = ioutil.TempDir("", "cgroup")
+		Expect(err).ToNot(HaveOccurred())
+		origUsageFiles = hugetlbUsageFiles
+		hugetlbUsageFiles = []string{
+			filepath.Join(cgroupDir, "hugetlb", "hugetlb.*.usage_in_bytes"),
+			filepath.Join(cgroupDir, "hugetlb.*.current"),
+		}
+	})
+
+	AfterEach(func() {
+		hugetlbUsageFiles = origUsageFiles
+		os.RemoveAll(cgroupDir)
+	})
+
+	writeFile := func(name string, content string) {
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(cgroupDir, name)), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(cgroupDir, name), []byte(content), 0644)).To(Succeed())
+	}
+
+	It("should report the hugepages usage of the cgroup v1 hugetlb controller", func() {
+		writeFile("hugetlb/hugetlb.2MB.usage_in_bytes", "67108864\n")
+		writeFile("hugetlb/hugetlb.1GB.usage_in_bytes", "0\n")
+		writeFile("hugetlb/hugetlb.2MB.limit_in_bytes", "67108864\n")
+
+		hugepagesStats, err := getHugepagesStats()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hugepagesStats).To(Equal([]stats.DomainStatsHugepages{
+			{PageSize: 2 << 20, Usage: 64 << 20},
+			{PageSize: 1 << 30, Usage: 0},
+		}))
+	})
+
+	It("should report the hugepages usage of the cgroup v2 hugetlb controller", func() {
+		writeFile("hugetlb.2MB.current", "67108864\n")
+		writeFile("hugetlb.2MB.rsvd.current", "0\n")
+
+		hugepagesStats, err := getHugepagesStats()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hugepagesStats).To(Equal([]stats.DomainStatsHugepages{
+			{PageSize: 2 << 20, Usage: 64 << 20},
+		}))
+	})
+
+	It("should not report anything without the hugetlb controller", func() {
+		hugepagesStats, err := getHugepagesStats()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hugepagesStats).To(BeEmpty())
+	})
+})
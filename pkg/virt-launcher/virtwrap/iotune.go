@@ -0,0 +1,137 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+// throttleThreshold is the share of an I/O limit above which a disk is
+// considered to be throttled
+const throttleThreshold = 0.95
+
+type blockSample struct {
+	time    time.Time
+	rdReqs  uint64
+	wrReqs  uint64
+	rdBytes uint64
+	wrBytes uint64
+}
+
+type ioLimit struct {
+	name  string
+	value uint64
+	// counter returns the counter of a block sample the limit applies to
+	counter func(sample blockSample) uint64
+}
+
+func ioLimits(limits api.DiskIOTune) []ioLimit {
+	return []ioLimit{
+		{stats.IOTuneTotalBytesSec, limits.TotalBytesSec, func(s blockSample) uint64 { return s.rdBytes + s.wrBytes }},
+		{stats.IOTuneReadBytesSec, limits.ReadBytesSec, func(s blockSample) uint64 { return s.rdBytes }},
+		{stats.IOTuneWriteBytesSec, limits.WriteBytesSec, func(s blockSample) uint64 { return s.wrBytes }},
+		{stats.IOTuneTotalIopsSec, limits.TotalIopsSec, func(s blockSample) uint64 { return s.rdReqs + s.wrReqs }},
+		{stats.IOTuneReadIopsSec, limits.ReadIopsSec, func(s blockSample) uint64 { return s.rdReqs }},
+		{stats.IOTuneWriteIopsSec, limits.WriteIopsSec, func(s blockSample) uint64 { return s.wrReqs }},
+	}
+}
+
+// ioThrottleTracker counts how often the disks of the domain hit one of their
+// I/O limits. QEMU does not report the throttled requests, so the I/O rates
+// between two stats samples are compared with the limits instead.
+type ioThrottleTracker struct {
+	lock sync.Mutex
+	// limits, samples and hits are keyed by the target device of the disk
+	limits  map[string]api.DiskIOTune
+	samples map[string]blockSample
+	hits    map[string]map[string]uint64
+}
+
+func newIOThrottleTracker() *ioThrottleTracker {
+	return &ioThrottleTracker{
+		limits:  map[string]api.DiskIOTune{},
+		samples: map[string]blockSample{},
+		hits:    map[string]map[string]uint64{},
+	}
+}
+
+// setLimits records the I/O limits of the disks of the domain
+func (t *ioThrottleTracker) setLimits(disks []api.Disk) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.limits = map[string]api.DiskIOTune{}
+	for _, disk := range disks {
+		if disk.IOTune != nil {
+			t.limits[disk.Target.Device] = *disk.IOTune
+		}
+	}
+}
+
+// observe counts the limits the disks hit since the previous sample and
+// returns the hits of all disks with I/O limits
+func (t *ioThrottleTracker) observe(now time.Time, blocks []stats.DomainStatsBlock) []stats.DomainStatsBlockThrottle {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var throttleStats []stats.DomainStatsBlockThrottle
+	for _, block := range blocks {
+		limits, exists := t.limits[block.Name]
+		if !block.NameSet || !exists {
+			continue
+		}
+
+		sample := blockSample{time: now, rdReqs: block.RdReqs, wrReqs: block.WrReqs, rdBytes: block.RdBytes, wrBytes: block.WrBytes}
+		prev, sampled := t.samples[block.Name]
+		seconds := now.Sub(prev.time).Seconds()
+
+		hits, exists := t.hits[block.Name]
+		if !exists {
+			hits = map[string]uint64{}
+			t.hits[block.Name] = hits
+		}
+		for _, limit := range ioLimits(limits) {
+			if limit.value == 0 {
+				continue
+			}
+			current, previous := limit.counter(sample), limit.counter(prev)
+			// a counter which went backwards was restarted, e.g. by a migration
+			if sampled && seconds > 0 && current >= previous &&
+				float64(current-previous)/seconds >= throttleThreshold*float64(limit.value) {
+				hits[limit.name]++
+			}
+			throttleStats = append(throttleStats, stats.DomainStatsBlockThrottle{Name: block.Name, Limit: limit.name, Hits: hits[limit.name]})
+		}
+		t.samples[block.Name] = sample
+	}
+
+	sort.Slice(throttleStats, func(i, j int) bool {
+		if throttleStats[i].Name != throttleStats[j].Name {
+			return throttleStats[i].Name < throttleStats[j].Name
+		}
+		return throttleStats[i].Limit < throttleStats[j].Limit
+	})
+	return throttleStats
+}
@@ -0,0 +1,75 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var _ = Describe("I/O throttling", func() {
+	var tracker *ioThrottleTracker
+	var start time.Time
+
+	block := func(name string, rdReqs, wrReqs uint64) stats.DomainStatsBlock {
+		return stats.DomainStatsBlock{Name: name, NameSet: true, RdReqs: rdReqs, WrReqs: wrReqs}
+	}
+
+	BeforeEach(func() {
+		tracker = newIOThrottleTracker()
+		tracker.setLimits([]api.Disk{
+			{Target: api.DiskTarget{Device: "vda"}, IOTune: &api.DiskIOTune{TotalIopsSec: 100, WriteIopsSec: 50}},
+			{Target: api.DiskTarget{Device: "vdb"}},
+		})
+		start = time.Now()
+	})
+
+	It("should report the limits of the disks with I/O limits", func() {
+		throttleStats := tracker.observe(start, []stats.DomainStatsBlock{block("vda", 0, 0), block("vdb", 0, 0)})
+		Expect(throttleStats).To(Equal([]stats.DomainStatsBlockThrottle{
+			{Name: "vda", Limit: stats.IOTuneTotalIopsSec},
+			{Name: "vda", Limit: stats.IOTuneWriteIopsSec},
+		}))
+	})
+
+	It("should count the limits which were hit since the previous sample", func() {
+		tracker.observe(start, []stats.DomainStatsBlock{block("vda", 0, 0)})
+		// 60 reads and 40 writes per second
+		tracker.observe(start.Add(10*time.Second), []stats.DomainStatsBlock{block("vda", 600, 400)})
+		// 10 reads and 50 writes per second
+		throttleStats := tracker.observe(start.Add(20*time.Second), []stats.DomainStatsBlock{block("vda", 700, 900)})
+		Expect(throttleStats).To(Equal([]stats.DomainStatsBlockThrottle{
+			{Name: "vda", Limit: stats.IOTuneTotalIopsSec, Hits: 1},
+			{Name: "vda", Limit: stats.IOTuneWriteIopsSec, Hits: 1},
+		}))
+	})
+
+	It("should not count restarted counters as a hit", func() {
+		tracker.observe(start, []stats.DomainStatsBlock{block("vda", 1000, 1000)})
+		throttleStats := tracker.observe(start.Add(time.Second), []stats.DomainStatsBlock{block("vda", 0, 0)})
+		Expect(throttleStats[0].Hits).To(BeZero())
+		Expect(throttleStats[1].Hits).To(BeZero())
+	})
+})
@@ -38,6 +38,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	cmdclient "kubevirt.io/kubevirt/pkg/virt-handler/cmd-client"
@@ -61,8 +62,12 @@ import (
 	"kubevirt.io/kubevirt/pkg/hooks"
 	hostdisk "kubevirt.io/kubevirt/pkg/host-disk"
 	"kubevirt.io/kubevirt/pkg/ignition"
+	"kubevirt.io/kubevirt/pkg/tpm"
+	virtutil "kubevirt.io/kubevirt/pkg/util"
+	"kubevirt.io/kubevirt/pkg/util/hardware"
 	"kubevirt.io/kubevirt/pkg/util/net/ip"
 	migrationproxy "kubevirt.io/kubevirt/pkg/virt-handler/migration-proxy"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/lifecycle"
 	agentpoller "kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/agent-poller"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/api"
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
@@ -77,6 +82,21 @@ const gpuEnvPrefix = "GPU_PASSTHROUGH_DEVICES"
 const vgpuEnvPrefix = "VGPU_PASSTHROUGH_DEVICES"
 const QATEnvPrefix = "QAT"
 
+// guestFstrimCommand trims the unused space of all mounted guest filesystems
+const guestFstrimCommand = `{"execute":"guest-fstrim"}`
+
+// guestFSFreezeCommand and guestFSThawCommand quiesce and resume the I/O of all
+// mounted guest filesystems
+const guestFSFreezeCommand = `{"execute":"guest-fsfreeze-freeze"}`
+const guestFSThawCommand = `{"execute":"guest-fsfreeze-thaw"}`
+
+const (
+	// failedPostStartHookReason is added in an event if the post-start hook of a VirtualMachineInstance failed
+	failedPostStartHookReason = "FailedPostStartHook"
+	// failedPreStopHookReason is added in an event if the pre-stop hook of a VirtualMachineInstance failed
+	failedPreStopHookReason = "FailedPreStopHook"
+)
+
 type contextStore struct {
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -86,6 +106,8 @@ type DomainManager interface {
 	SyncVMI(*v1.VirtualMachineInstance, bool, *cmdv1.VirtualMachineOptions) (*api.DomainSpec, error)
 	PauseVMI(*v1.VirtualMachineInstance) error
 	UnpauseVMI(*v1.VirtualMachineInstance) error
+	FreezeVMI(*v1.VirtualMachineInstance) error
+	UnfreezeVMI(*v1.VirtualMachineInstance) error
 	KillVMI(*v1.VirtualMachineInstance) error
 	DeleteVMI(*v1.VirtualMachineInstance) error
 	SignalShutdownVMI(*v1.VirtualMachineInstance) error
@@ -95,6 +117,9 @@ type DomainManager interface {
 	PrepareMigrationTarget(*v1.VirtualMachineInstance, bool) error
 	GetDomainStats() ([]*stats.DomainStats, error)
 	CancelVMIMigration(*v1.VirtualMachineInstance) error
+	BackupVMI(*v1.VirtualMachineInstance, *cmdclient.BackupOptions) ([]v1.VirtualMachineBackupVolume, error)
+	AbortVMIBackup(*v1.VirtualMachineInstance, *cmdclient.BackupOptions) error
+	MemoryDumpVMI(*v1.VirtualMachineInstance, string) error
 	GetGuestInfo() (v1.VirtualMachineInstanceGuestAgentInfo, error)
 	GetUsers() ([]v1.VirtualMachineInstanceGuestOSUser, error)
 	GetFilesystems() ([]v1.VirtualMachineInstanceFileSystem, error)
@@ -117,6 +142,10 @@ type LibvirtDomainManager struct {
 	cloudInitDataStore     *cloudinit.CloudInitData
 	setGuestTimeContextPtr *contextStore
 	ovmfPath               string
+	diskCompactorStarted   bool
+	ioThrottle             *ioThrottleTracker
+	dirtyRate              *dirtyRateMeter
+	processes              *processAccounting
 }
 
 type migrationDisks struct {
@@ -156,8 +185,11 @@ func NewLibvirtDomainManager(connection cli.Connection, virtShareDir string, not
 		paused: pausedVMIs{
 			paused: make(map[types.UID]bool, 0),
 		},
-		agentData: agentStore,
-		ovmfPath:  ovmfPath,
+		agentData:  agentStore,
+		ovmfPath:   ovmfPath,
+		ioThrottle: newIOThrottleTracker(),
+		dirtyRate:  newDirtyRateMeter(),
+		processes:  newProcessAccounting(),
 	}
 
 	return &manager, nil
@@ -346,7 +378,8 @@ func classifyVolumesForMigration(vmi *v1.VirtualMachineInstance) *migrationDisks
 		}
 		if volSrc.ConfigMap != nil || volSrc.Secret != nil ||
 			volSrc.ServiceAccount != nil || volSrc.CloudInitNoCloud != nil ||
-			volSrc.CloudInitConfigDrive != nil || volSrc.ContainerDisk != nil {
+			volSrc.CloudInitConfigDrive != nil || volSrc.ContainerDisk != nil ||
+			volSrc.Sysprep != nil {
 			disks.generated[volume.Name] = true
 		}
 	}
@@ -436,8 +469,13 @@ func (l *LibvirtDomainManager) asyncMigrate(vmi *v1.VirtualMachineInstance, opti
 			return
 		}
 
-		// For a tunnelled migration, this is always the uri
-		dstURI := fmt.Sprintf("qemu+tcp://%s/system", net.JoinHostPort(loopbackAddress, strconv.Itoa(LibvirtLocalConnectionPort)))
+		// For a tunnelled migration, this is always the uri, the target runs
+		// the session daemon if the libvirt of the vmi runs unprivileged
+		libvirtDriver := "system"
+		if virtutil.IsNonRootVMI(vmi) {
+			libvirtDriver = "session"
+		}
+		dstURI := fmt.Sprintf("qemu+tcp://%s/%s", net.JoinHostPort(loopbackAddress, strconv.Itoa(LibvirtLocalConnectionPort)), libvirtDriver)
 		migrURI := fmt.Sprintf("tcp://%s", ip.NormalizeIPAddress(loopbackAddress))
 
 		domName := api.VMINamespaceKeyFunc(vmi)
@@ -470,6 +508,13 @@ func (l *LibvirtDomainManager) asyncMigrate(vmi *v1.VirtualMachineInstance, opti
 			params.MigrateDisks = copyDisks
 			params.MigrateDisksSet = true
 		}
+		// VFs can't be migrated, the standby interfaces carry the traffic until
+		// the target attached its own VFs
+		if err := detachFailoverVFs(vmi, dom); err != nil {
+			log.Log.Object(vmi).Reason(err).Error("Live migration failed.")
+			l.setMigrationResult(vmi, true, fmt.Sprintf("%v", err), "")
+			return
+		}
 		// start live migration tracking
 		migrationErrorChan := make(chan error, 1)
 		defer close(migrationErrorChan)
@@ -567,6 +612,117 @@ func (l *LibvirtDomainManager) setGuestTime(vmi *v1.VirtualMachineInstance, dom
 	}(ctx, vmi, dom)
 }
 
+// startDiskCompactor compacts the disk overlays in the configured interval, for as long as the domain exists.
+// The caller must hold the domainModifyLock.
+func (l *LibvirtDomainManager) startDiskCompactor(vmi *v1.VirtualMachineInstance) {
+	if l.diskCompactorStarted {
+		return
+	}
+	l.diskCompactorStarted = true
+
+	go func(vmi *v1.VirtualMachineInstance, interval time.Duration) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := l.compactDisks(vmi)
+			if domainerrors.IsNotFound(err) {
+				return
+			} else if err != nil {
+				log.Log.Object(vmi).Reason(err).Error("Compacting the disk overlays failed.")
+			}
+		}
+	}(vmi.DeepCopy(), vmi.Spec.DiskCompaction.Interval.Duration)
+}
+
+// compactDisks lets the guest trim its unused space, which punches holes into the qcow2 overlays,
+// and stores how much the overlays shrank in the domain metadata
+func (l *LibvirtDomainManager) compactDisks(vmi *v1.VirtualMachineInstance) error {
+	domName := api.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+	overlays := []string{}
+	for i := range domainSpec.Devices.Disks {
+		if api.IsCompactableDisk(&domainSpec.Devices.Disks[i]) {
+			overlays = append(overlays, domainSpec.Devices.Disks[i].Source.File)
+		}
+	}
+	if len(overlays) == 0 {
+		return nil
+	}
+
+	allocatedBefore, err := getAllocatedBytes(overlays)
+	if err != nil {
+		return err
+	}
+	failureReason := ""
+	if _, err := l.virConn.QemuAgentCommand(guestFstrimCommand, domName); err != nil {
+		failureReason = fmt.Sprintf("trimming the guest filesystems failed: %v", err)
+	}
+	allocatedAfter, err := getAllocatedBytes(overlays)
+	if err != nil {
+		return err
+	}
+
+	// The guest keeps on writing while it trims, so the overlays can even grow
+	reclaimed := allocatedBefore - allocatedAfter
+	if reclaimed < 0 {
+		reclaimed = 0
+	}
+	log.Log.Object(vmi).Infof("Compacting the disk overlays reclaimed %d bytes.", reclaimed)
+	return l.setDiskCompactionResult(vmi, reclaimed, failureReason)
+}
+
+func (l *LibvirtDomainManager) setDiskCompactionResult(vmi *v1.VirtualMachineInstance, reclaimed int64, failureReason string) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	dom, err := l.virConn.LookupDomainByName(api.VMINamespaceKeyFunc(vmi))
+	if err != nil {
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	domainSpec.Metadata.KubeVirt.DiskCompaction = &api.DiskCompactionMetadata{
+		Timestamp:      &now,
+		ReclaimedBytes: reclaimed,
+		FailureReason:  failureReason,
+	}
+	_, err = l.setDomainSpecWithHooks(vmi, domainSpec)
+	return err
+}
+
+// getAllocatedBytes returns the space the files occupy on the storage. Unlike
+// their size, it goes down when holes are punched into them.
+var getAllocatedBytes = func(files []string) (int64, error) {
+	var allocated int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return 0, err
+		}
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return 0, fmt.Errorf("can't determine the allocated blocks of %s", file)
+		}
+		// the blocks are always counted in units of 512 bytes
+		allocated += stat.Blocks * 512
+	}
+	return allocated, nil
+}
+
 func getVMIEphemeralDisksTotalSize() *resource.Quantity {
 	var baseDir = "/var/run/kubevirt-ephemeral-disks/"
 	totalSize := int64(0)
@@ -739,6 +895,187 @@ func (l *LibvirtDomainManager) asyncMigrationAbort(vmi *v1.VirtualMachineInstanc
 	}(l, vmi)
 }
 
+// BackupVMI starts a pull mode backup of the VMI disks together with a
+// checkpoint named after the backup. The disks are exported by an NBD server
+// until the backup is aborted.
+func (l *LibvirtDomainManager) BackupVMI(vmi *v1.VirtualMachineInstance, options *cmdclient.BackupOptions) ([]v1.VirtualMachineBackupVolume, error) {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	logger := log.Log.Object(vmi)
+
+	domName := api.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		logger.Reason(err).Error("Getting the domain for the backup failed.")
+		return nil, err
+	}
+	defer dom.Free()
+
+	stats, err := dom.GetJobStats(0)
+	if err != nil {
+		logger.Reason(err).Error("Getting the domain job stats failed.")
+		return nil, err
+	}
+	if stats.Type != libvirt.DOMAIN_JOB_NONE {
+		return nil, fmt.Errorf("another job is active on domain %s", domName)
+	}
+
+	domSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return nil, err
+	}
+	backup, checkpoint, volumes, err := api.NewDomainBackup(vmi, domSpec, options.Name, options.Incremental)
+	if err != nil {
+		return nil, err
+	}
+	backupXML, err := xml.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	checkpointXML, err := xml.Marshal(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dom.BackupBegin(string(backupXML), string(checkpointXML), 0)
+	if err != nil {
+		logger.Reason(err).Errorf("Starting backup %s failed.", options.Name)
+		return nil, err
+	}
+	logger.Infof("Started backup %s", options.Name)
+	return volumes, nil
+}
+
+// AbortVMIBackup stops the backup job of the VMI, which shuts the NBD server
+// down. The checkpoint of the backup is kept for later incremental backups.
+func (l *LibvirtDomainManager) AbortVMIBackup(vmi *v1.VirtualMachineInstance, options *cmdclient.BackupOptions) error {
+	logger := log.Log.Object(vmi)
+
+	domName := api.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		if domainerrors.IsNotFound(err) {
+			return nil
+		}
+		logger.Reason(err).Error("Getting the domain for the backup failed.")
+		return err
+	}
+	defer dom.Free()
+
+	stats, err := dom.GetJobStats(0)
+	if err != nil {
+		logger.Reason(err).Error("Getting the domain job stats failed.")
+		return err
+	}
+	if stats.Type == libvirt.DOMAIN_JOB_NONE || stats.Operation != libvirt.DOMAIN_JOB_OPERATION_BACKUP {
+		return nil
+	}
+	if err := dom.AbortJob(); err != nil {
+		logger.Reason(err).Errorf("Aborting backup %s failed.", options.Name)
+		return err
+	}
+	logger.Infof("Aborted backup %s", options.Name)
+	return nil
+}
+
+// MemoryDumpVMI dumps the guest memory of the VMI to a file on the claim
+// virt-handler mounted into the launcher. The dump runs in the background and
+// its progress is kept in the domain metadata. Requesting the dump of the
+// same file again has no effect.
+func (l *LibvirtDomainManager) MemoryDumpVMI(vmi *v1.VirtualMachineInstance, fileName string) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	if fileName == "" || filepath.Base(fileName) != fileName {
+		return fmt.Errorf("invalid memory dump file name %q", fileName)
+	}
+
+	dom, err := l.virConn.LookupDomainByName(api.VMINamespaceKeyFunc(vmi))
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Getting the domain for the memory dump failed.")
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+	if dump := domainSpec.Metadata.KubeVirt.MemoryDump; dump != nil {
+		if dump.FileName == fileName {
+			return nil
+		}
+		if dump.EndTimestamp == nil {
+			return fmt.Errorf("the memory dump to %s is still in progress", dump.FileName)
+		}
+	}
+
+	now := metav1.Now()
+	domainSpec.Metadata.KubeVirt.MemoryDump = &api.MemoryDumpMetadata{
+		FileName:       fileName,
+		StartTimestamp: &now,
+	}
+	if _, err := l.setDomainSpecWithHooks(vmi, domainSpec); err != nil {
+		return err
+	}
+
+	go func(vmi *v1.VirtualMachineInstance) {
+		logger := log.Log.Object(vmi)
+		dumpErr := l.dumpMemory(vmi, filepath.Join(containerdisk.GetMemoryDumpTargetPathFromLauncherView(), fileName))
+		if dumpErr != nil {
+			logger.Reason(dumpErr).Errorf("Dumping the guest memory to %s failed.", fileName)
+		} else {
+			logger.Infof("Dumped the guest memory to %s.", fileName)
+		}
+		if err := l.setMemoryDumpResult(vmi, fileName, dumpErr); err != nil {
+			logger.Reason(err).Error("Storing the memory dump result failed.")
+		}
+	}(vmi.DeepCopy())
+	return nil
+}
+
+// dumpMemory writes the guest memory to an ELF file, like virsh dump --memory-only.
+// It blocks until the dump is written.
+func (l *LibvirtDomainManager) dumpMemory(vmi *v1.VirtualMachineInstance, path string) error {
+	dom, err := l.virConn.LookupDomainByName(api.VMINamespaceKeyFunc(vmi))
+	if err != nil {
+		return err
+	}
+	defer dom.Free()
+	return dom.CoreDumpWithFormat(path, libvirt.DOMAIN_CORE_DUMP_FORMAT_RAW, libvirt.DUMP_MEMORY_ONLY)
+}
+
+func (l *LibvirtDomainManager) setMemoryDumpResult(vmi *v1.VirtualMachineInstance, fileName string, dumpErr error) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	dom, err := l.virConn.LookupDomainByName(api.VMINamespaceKeyFunc(vmi))
+	if err != nil {
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+	dump := domainSpec.Metadata.KubeVirt.MemoryDump
+	if dump == nil || dump.FileName != fileName {
+		return nil
+	}
+	now := metav1.Now()
+	dump.EndTimestamp = &now
+	if dumpErr != nil {
+		dump.Failed = true
+		dump.FailureReason = dumpErr.Error()
+	} else {
+		dump.Completed = true
+	}
+	_, err = l.setDomainSpecWithHooks(vmi, domainSpec)
+	return err
+}
+
 func (l *LibvirtDomainManager) MigrateVMI(vmi *v1.VirtualMachineInstance, options *cmdclient.MigrationOptions) error {
 
 	if vmi.Status.MigrationState == nil {
@@ -787,11 +1124,20 @@ func (l *LibvirtDomainManager) PrepareMigrationTarget(vmi *v1.VirtualMachineInst
 		logger.Reason(err).Error("failed to read pod cpuset.")
 		return fmt.Errorf("failed to read pod cpuset: %v", err)
 	}
-	// reserve the last cpu for the emulator thread
-	if vmi.IsCPUDedicated() && vmi.Spec.Domain.CPU.IsolateEmulatorThread {
-		if len(podCPUSet) > 0 {
-			emulatorThreadCpu = &podCPUSet[len(podCPUSet)]
-			podCPUSet = podCPUSet[:len(podCPUSet)-1]
+	// split the cpus between the vcpus and the emulator thread
+	if vmi.IsCPUDedicated() {
+		podCPUSet, emulatorThreadCpu, err = hardware.DedicatedCPUs(hardware.CPUPath, vmi, podCPUSet)
+		if err != nil {
+			logger.Reason(err).Error("failed to place the dedicated cpus.")
+			return fmt.Errorf("failed to place the dedicated cpus: %v", err)
+		}
+	}
+	var hostNUMANodes []hardware.NUMANode
+	if vmi.IsNUMAPassthrough() {
+		hostNUMANodes, err = hardware.LookupNUMATopology(hardware.NUMANodesPath)
+		if err != nil {
+			logger.Reason(err).Error("failed to read the host NUMA topology.")
+			return fmt.Errorf("failed to read the host NUMA topology: %v", err)
 		}
 	}
 	// Check if PVC volumes are block volumes
@@ -807,6 +1153,14 @@ func (l *LibvirtDomainManager) PrepareMigrationTarget(vmi *v1.VirtualMachineInst
 				return err
 			}
 			isBlockPVCMap[volume.Name] = isBlockPVC
+		} else if volume.VolumeSource.GoldenImage != nil {
+			isBlockPVC, err := isBlockDeviceVolume(volume.Name)
+			if err != nil {
+				logger.Reason(err).Errorf("failed to detect volume mode for Volume %v and golden image PVC %v.",
+					volume.Name, volume.VolumeSource.GoldenImage.ClaimName)
+				return err
+			}
+			isBlockPVCMap[volume.Name] = isBlockPVC
 		} else if volume.VolumeSource.ContainerDisk != nil {
 			image, err := containerdisk.GetDiskTargetPartFromLauncherView(i)
 			if err != nil {
@@ -837,12 +1191,17 @@ func (l *LibvirtDomainManager) PrepareMigrationTarget(vmi *v1.VirtualMachineInst
 		IsBlockPVC:        isBlockPVCMap,
 		IsBlockDV:         isBlockDVMap,
 		DiskType:          diskInfo,
+		SRIOVDevices:      getSRIOVPCIAddresses(vmi.Spec.Domain.Devices.Interfaces),
+		HostDevices:       getAllocatedDeviceIDs(virtutil.PCIResourcePrefix, getHostDeviceResourceNames(vmi.Spec.Domain.Devices.HostDevices)),
+		MediatedDevices:   getAllocatedDeviceIDs(virtutil.MDEVResourcePrefix, getMediatedDeviceResourceNames(vmi)),
 		EmulatorThreadCpu: emulatorThreadCpu,
 		OVMFPath:          l.ovmfPath,
+		HostNUMANodes:     hostNUMANodes,
 	}
 	if err := api.Convert_v1_VirtualMachine_To_api_Domain(vmi, domain, c); err != nil {
 		return fmt.Errorf("conversion failed: %v", err)
 	}
+	l.ioThrottle.setLimits(domain.Spec.Devices.Disks)
 
 	dom, err := l.preStartHook(vmi, domain)
 	if err != nil {
@@ -940,6 +1299,11 @@ func (l *LibvirtDomainManager) preStartHook(vmi *v1.VirtualMachineInstance, doma
 		return nil, err
 	}
 
+	err = ignition.ResolveIgnitionVolume(vmi, config.SecretSourceDir, config.ConfigMapSourceDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// generate cloud-init data
 	cloudInitData, err := cloudinit.ReadCloudInitVolumeDataSource(vmi)
 	if err != nil {
@@ -1009,6 +1373,15 @@ func (l *LibvirtDomainManager) preStartHook(vmi *v1.VirtualMachineInstance, doma
 	if err := config.CreateServiceAccountDisk(vmi); err != nil {
 		return domain, fmt.Errorf("creating service account disk failed: %v", err)
 	}
+	// create Sysprep disks if they exist
+	if err := config.CreateSysprepDisks(vmi); err != nil {
+		return domain, fmt.Errorf("creating sysprep disks failed: %v", err)
+	}
+
+	// link the TPM state to its claim
+	if err := tpm.PrepareStorage(vmi, domain); err != nil {
+		return domain, fmt.Errorf("preparing the TPM storage failed: %v", err)
+	}
 
 	// set drivers cache mode
 	for i := range domain.Spec.Devices.Disks {
@@ -1079,6 +1452,37 @@ func getSRIOVPCIAddresses(ifaces []v1.Interface) map[string][]string {
 	return networkToAddressesMap
 }
 
+// getAllocatedDeviceIDs returns the devices which were allocated by device plugins for the
+// given resources, as listed in the environment variables with the given prefix
+func getAllocatedDeviceIDs(envPrefix string, resourceNames []string) map[string][]string {
+	resourceToDevicesMap := map[string][]string{}
+	for _, resourceName := range resourceNames {
+		varName := virtutil.ResourceNameToEnvVar(envPrefix, resourceName)
+		if deviceIDs, isSet := os.LookupEnv(varName); isSet {
+			resourceToDevicesMap[resourceName] = parseDeviceAddress(deviceIDs)
+		}
+	}
+	return resourceToDevicesMap
+}
+
+func getHostDeviceResourceNames(hostDevices []v1.HostDevice) []string {
+	var resourceNames []string
+	for _, hostDev := range hostDevices {
+		resourceNames = append(resourceNames, hostDev.DeviceName)
+	}
+	return resourceNames
+}
+
+// getMediatedDeviceResourceNames returns the resources of the host devices and GPUs,
+// which both may be backed by mediated devices
+func getMediatedDeviceResourceNames(vmi *v1.VirtualMachineInstance) []string {
+	resourceNames := getHostDeviceResourceNames(vmi.Spec.Domain.Devices.HostDevices)
+	for _, gpu := range vmi.Spec.Domain.Devices.GPUs {
+		resourceNames = append(resourceNames, gpu.DeviceName)
+	}
+	return resourceNames
+}
+
 // This function parses all environment variables with prefix string that is set by a Device Plugin.
 // Device plugin that passes GPU devices by setting these env variables is https://github.com/NVIDIA/kubevirt-gpu-device-plugin
 // It returns address list for devices set in the env variable.
@@ -1111,6 +1515,16 @@ func parseDeviceAddress(addrString string) []string {
 	}
 	return addrs
 }
+// keepPausedOnIOError tells if a guest which qemu paused because of an I/O error is left
+// paused on sync. Once the VMI sets spec.ioErrorRecovery.autoResume, virt-handler decides
+// when to resume the guest, otherwise it is resumed like any guest not paused by the user.
+func keepPausedOnIOError(vmi *v1.VirtualMachineInstance, reason int) bool {
+	if libvirt.DomainPausedReason(reason) != libvirt.DOMAIN_PAUSED_IOERROR {
+		return false
+	}
+	return vmi.Spec.IOErrorRecovery != nil && vmi.Spec.IOErrorRecovery.AutoResume != nil
+}
+
 func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulation bool, options *cmdv1.VirtualMachineOptions) (*api.DomainSpec, error) {
 	l.domainModifyLock.Lock()
 	defer l.domainModifyLock.Unlock()
@@ -1124,11 +1538,20 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 		logger.Reason(err).Error("failed to read pod cpuset.")
 		return nil, err
 	}
-	// reserve the last cpu for the emulator thread
-	if vmi.IsCPUDedicated() && vmi.Spec.Domain.CPU.IsolateEmulatorThread {
-		if len(podCPUSet) > 0 {
-			emulatorThreadCpu = &podCPUSet[len(podCPUSet)-1]
-			podCPUSet = podCPUSet[:len(podCPUSet)-1]
+	// split the cpus between the vcpus and the emulator thread
+	if vmi.IsCPUDedicated() {
+		podCPUSet, emulatorThreadCpu, err = hardware.DedicatedCPUs(hardware.CPUPath, vmi, podCPUSet)
+		if err != nil {
+			logger.Reason(err).Error("failed to place the dedicated cpus.")
+			return nil, fmt.Errorf("failed to place the dedicated cpus: %v", err)
+		}
+	}
+	var hostNUMANodes []hardware.NUMANode
+	if vmi.IsNUMAPassthrough() {
+		hostNUMANodes, err = hardware.LookupNUMATopology(hardware.NUMANodesPath)
+		if err != nil {
+			logger.Reason(err).Error("failed to read the host NUMA topology.")
+			return nil, err
 		}
 	}
 
@@ -1145,6 +1568,14 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 				return nil, err
 			}
 			isBlockPVCMap[volume.Name] = isBlockPVC
+		} else if volume.VolumeSource.GoldenImage != nil {
+			isBlockPVC, err := isBlockDeviceVolume(volume.Name)
+			if err != nil {
+				logger.Reason(err).Errorf("failed to detect volume mode for Volume %v and golden image PVC %v.",
+					volume.Name, volume.VolumeSource.GoldenImage.ClaimName)
+				return nil, err
+			}
+			isBlockPVCMap[volume.Name] = isBlockPVC
 		} else if volume.VolumeSource.ContainerDisk != nil {
 			image, err := containerdisk.GetDiskTargetPartFromLauncherView(i)
 			if err != nil {
@@ -1179,8 +1610,11 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 		GpuDevices:        getEnvAddressListByPrefix(gpuEnvPrefix),
 		VgpuDevices:       getEnvAddressListByPrefix(vgpuEnvPrefix),
 		QATDevices:        getEnvAddressListByPrefix(QATEnvPrefix),
+		HostDevices:       getAllocatedDeviceIDs(virtutil.PCIResourcePrefix, getHostDeviceResourceNames(vmi.Spec.Domain.Devices.HostDevices)),
+		MediatedDevices:   getAllocatedDeviceIDs(virtutil.MDEVResourcePrefix, getMediatedDeviceResourceNames(vmi)),
 		EmulatorThreadCpu: emulatorThreadCpu,
 		OVMFPath:          l.ovmfPath,
+		HostNUMANodes:     hostNUMANodes,
 	}
 	if options != nil {
 		if options.VirtualMachineSMBios != nil {
@@ -1193,6 +1627,7 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 		logger.Error("Conversion failed.")
 		return nil, err
 	}
+	l.ioThrottle.setLimits(domain.Spec.Devices.Disks)
 
 	// Set defaults which are not coming from the cluster
 	api.NewDefaulter(c.Architecture).SetObjectDefaults_Domain(domain)
@@ -1219,7 +1654,7 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 		}
 	}
 	defer dom.Free()
-	domState, _, err := dom.GetState()
+	domState, domReason, err := dom.GetState()
 	if err != nil {
 		logger.Reason(err).Error("Getting the domain state failed.")
 		return nil, err
@@ -1248,8 +1683,13 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 			return nil, err
 		}
 		logger.Info("Domain started.")
-	} else if cli.IsPaused(domState) && !l.paused.contains(vmi.UID) {
-		// TODO: if state change reason indicates a system error, we could try something smarter
+		if vmi.Spec.Lifecycle != nil && vmi.Spec.Lifecycle.PostStart != nil {
+			go l.runLifecycleHook(vmi.DeepCopy(), vmi.Spec.Lifecycle.PostStart, failedPostStartHookReason)
+		}
+	} else if cli.IsPaused(domState) && !l.paused.contains(vmi.UID) && !keepPausedOnIOError(vmi, domReason) &&
+		libvirt.DomainPausedReason(domReason) != libvirt.DOMAIN_PAUSED_CRASHED {
+		// A guest which crashed is kept paused, so that its memory can be dumped.
+		// TODO: if state change reason indicates another system error, we could try something smarter
 		err := dom.Resume()
 		if err != nil {
 			logger.Reason(err).Error("unpausing the VirtualMachineInstance failed.")
@@ -1260,6 +1700,26 @@ func (l *LibvirtDomainManager) SyncVMI(vmi *v1.VirtualMachineInstance, useEmulat
 		// Nothing to do
 	}
 
+	if !newDomain && domState == libvirt.DOMAIN_RUNNING {
+		if err := syncFailoverVFs(vmi, dom, &domain.Spec); err != nil {
+			logger.Reason(err).Error("Attaching the VFs of the SR-IOV interfaces failed.")
+			return nil, err
+		}
+
+		expansions, err := syncExpandedDisks(vmi, dom, &domain.Spec)
+		if err != nil {
+			// the guest keeps working with the old size, try again on the next sync
+			logger.Reason(err).Warning("Resizing the expanded disks failed.")
+		}
+		for _, expansion := range expansions {
+			l.reportDiskExpansion(vmi, expansion)
+		}
+	}
+
+	if vmi.Spec.DiskCompaction != nil {
+		l.startDiskCompactor(vmi)
+	}
+
 	xmlstr, err := dom.GetXMLDesc(0)
 	if err != nil {
 		return nil, err
@@ -1395,6 +1855,89 @@ func (l *LibvirtDomainManager) UnpauseVMI(vmi *v1.VirtualMachineInstance) error
 	return nil
 }
 
+// FreezeVMI quiesces the guest filesystems through the guest agent and records
+// the frozen state in the domain metadata until the filesystems are thawed
+func (l *LibvirtDomainManager) FreezeVMI(vmi *v1.VirtualMachineInstance) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	logger := log.Log.Object(vmi)
+
+	domName := api.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		logger.Reason(err).Error("Getting the domain failed during freeze.")
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+	if domainSpec.Metadata.KubeVirt.FSFreeze != nil {
+		logger.Infof("Filesystems are already frozen for %s", vmi.GetObjectMeta().GetName())
+		return nil
+	}
+
+	if _, err := l.virConn.QemuAgentCommand(guestFSFreezeCommand, domName); err != nil {
+		logger.Reason(err).Error("Freezing the guest filesystems failed.")
+		return err
+	}
+
+	now := metav1.Now()
+	domainSpec.Metadata.KubeVirt.FSFreeze = &api.FSFreezeMetadata{
+		Timestamp: &now,
+	}
+	if _, err := l.setDomainSpecWithHooks(vmi, domainSpec); err != nil {
+		// Nobody would know that the filesystems are frozen, don't leave the guest stuck
+		if _, thawErr := l.virConn.QemuAgentCommand(guestFSThawCommand, domName); thawErr != nil {
+			logger.Reason(thawErr).Error("Thawing the guest filesystems failed.")
+		}
+		return err
+	}
+	logger.Infof("Froze the guest filesystems for %s", vmi.GetObjectMeta().GetName())
+
+	return nil
+}
+
+// UnfreezeVMI thaws the guest filesystems through the guest agent and removes
+// the frozen state from the domain metadata
+func (l *LibvirtDomainManager) UnfreezeVMI(vmi *v1.VirtualMachineInstance) error {
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	logger := log.Log.Object(vmi)
+
+	domName := api.VMINamespaceKeyFunc(vmi)
+	dom, err := l.virConn.LookupDomainByName(domName)
+	if err != nil {
+		logger.Reason(err).Error("Getting the domain failed during thaw.")
+		return err
+	}
+	defer dom.Free()
+
+	domainSpec, err := l.getDomainSpec(dom)
+	if err != nil {
+		return err
+	}
+
+	// Thawing is harmless if the filesystems are not frozen, so always ask the
+	// agent, the guest may have been frozen behind our back
+	if _, err := l.virConn.QemuAgentCommand(guestFSThawCommand, domName); err != nil {
+		logger.Reason(err).Error("Thawing the guest filesystems failed.")
+		return err
+	}
+	logger.Infof("Thawed the guest filesystems for %s", vmi.GetObjectMeta().GetName())
+
+	if domainSpec.Metadata.KubeVirt.FSFreeze == nil {
+		return nil
+	}
+	domainSpec.Metadata.KubeVirt.FSFreeze = nil
+	_, err = l.setDomainSpecWithHooks(vmi, domainSpec)
+	return err
+}
+
 func (l *LibvirtDomainManager) MarkGracefulShutdownVMI(vmi *v1.VirtualMachineInstance) error {
 	l.domainModifyLock.Lock()
 	defer l.domainModifyLock.Unlock()
@@ -1464,7 +2007,17 @@ func (l *LibvirtDomainManager) SignalShutdownVMI(vmi *v1.VirtualMachineInstance)
 			return err
 		}
 
-		if domSpec.Metadata.KubeVirt.GracePeriod.DeletionTimestamp == nil {
+		if domSpec.Metadata.KubeVirt.GracePeriod.DeletionTimestamp == nil && vmi.Spec.Lifecycle != nil && vmi.Spec.Lifecycle.PreStop != nil {
+			// The grace period covers the pre-stop hook, record its start before the hook runs
+			now := metav1.Now()
+			domSpec.Metadata.KubeVirt.GracePeriod.DeletionTimestamp = &now
+			_, err = l.setDomainSpecWithHooks(vmi, domSpec)
+			if err != nil {
+				log.Log.Object(vmi).Reason(err).Error("Unable to update grace period start time on domain xml")
+				return err
+			}
+			go l.preStopAndSignalShutdown(vmi.DeepCopy())
+		} else if domSpec.Metadata.KubeVirt.GracePeriod.DeletionTimestamp == nil {
 			err = dom.ShutdownFlags(libvirt.DOMAIN_SHUTDOWN_ACPI_POWER_BTN)
 			if err != nil {
 				log.Log.Object(vmi).Reason(err).Error("Signalling graceful shutdown failed.")
@@ -1485,6 +2038,57 @@ func (l *LibvirtDomainManager) SignalShutdownVMI(vmi *v1.VirtualMachineInstance)
 	return nil
 }
 
+// preStopAndSignalShutdown runs the pre-stop hook of the VirtualMachineInstance and asks the guest to shut down afterwards
+func (l *LibvirtDomainManager) preStopAndSignalShutdown(vmi *v1.VirtualMachineInstance) {
+	l.runLifecycleHook(vmi, vmi.Spec.Lifecycle.PreStop, failedPreStopHookReason)
+
+	l.domainModifyLock.Lock()
+	defer l.domainModifyLock.Unlock()
+
+	dom, err := l.virConn.LookupDomainByName(api.VMINamespaceKeyFunc(vmi))
+	if err != nil {
+		if !domainerrors.IsNotFound(err) {
+			log.Log.Object(vmi).Reason(err).Error("Getting the domain failed during graceful shutdown.")
+		}
+		return
+	}
+	defer dom.Free()
+
+	err = dom.ShutdownFlags(libvirt.DOMAIN_SHUTDOWN_ACPI_POWER_BTN)
+	if err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Signalling graceful shutdown failed.")
+		return
+	}
+	log.Log.Object(vmi).Infof("Signaled graceful shutdown for %s", vmi.GetObjectMeta().GetName())
+}
+
+// reportDiskExpansion reports the new size of an expanded disk as an event on the VirtualMachineInstance
+func (l *LibvirtDomainManager) reportDiskExpansion(vmi *v1.VirtualMachineInstance, expansion diskExpansion) {
+	if l.notifier == nil {
+		return
+	}
+	message := fmt.Sprintf("Disk %s was resized from %d to %d bytes", expansion.name, expansion.oldSize, expansion.newSize)
+	if err := l.notifier.SendK8sEvent(vmi, k8sv1.EventTypeNormal, diskExpandedReason, message); err != nil {
+		log.Log.Object(vmi).Reason(err).Error("Failed to report the disk expansion.")
+	}
+}
+
+// runLifecycleHook executes a lifecycle hook and reports a failure as an event on the VirtualMachineInstance
+func (l *LibvirtDomainManager) runLifecycleHook(vmi *v1.VirtualMachineInstance, handler *v1.LifecycleHandler, failureReason string) {
+	err := lifecycle.Run(handler)
+	if err == nil {
+		log.Log.Object(vmi).V(3).Info("Lifecycle hook succeeded.")
+		return
+	}
+
+	log.Log.Object(vmi).Reason(err).Error("Lifecycle hook failed.")
+	if l.notifier != nil {
+		if err := l.notifier.SendK8sEvent(vmi, k8sv1.EventTypeWarning, failureReason, err.Error()); err != nil {
+			log.Log.Object(vmi).Reason(err).Error("Failed to report the lifecycle hook failure.")
+		}
+	}
+}
+
 func (l *LibvirtDomainManager) KillVMI(vmi *v1.VirtualMachineInstance) error {
 	domName := api.VMINamespaceKeyFunc(vmi)
 	dom, err := l.virConn.LookupDomainByName(domName)
@@ -1603,7 +2207,54 @@ func (l *LibvirtDomainManager) GetDomainStats() ([]*stats.DomainStats, error) {
 	statsTypes := libvirt.DOMAIN_STATS_BALLOON | libvirt.DOMAIN_STATS_CPU_TOTAL | libvirt.DOMAIN_STATS_VCPU | libvirt.DOMAIN_STATS_INTERFACE | libvirt.DOMAIN_STATS_BLOCK
 	flags := libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING
 
-	return l.virConn.GetDomainStats(statsTypes, flags)
+	domStats, err := l.virConn.GetDomainStats(statsTypes, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	// connection limit drops, bandwidth limit, process, iothread, hugepages, CPU pressure and dirty rate stats are best effort, don't fail the libvirt stats for them
+	connLimitStats, err := network.GetPodConnectionLimitStats()
+	if err != nil {
+		log.Log.Reason(err).Warning("failed to collect connection limit stats")
+	}
+	processStats, err := l.processes.observe()
+	if err != nil {
+		log.Log.Reason(err).Warning("failed to collect process stats")
+	}
+	ioThreadStats, err := getIOThreadStats()
+	if err != nil {
+		log.Log.Reason(err).Warning("failed to collect iothread stats")
+	}
+	hugepagesStats, err := getHugepagesStats()
+	if err != nil {
+		log.Log.Reason(err).Warning("failed to collect hugepages stats")
+	}
+	cpuPressureStats, err := getCPUPressureStats()
+	if err != nil {
+		log.Log.Reason(err).Warning("failed to collect CPU pressure stats")
+	}
+	for _, domStat := range domStats {
+		domStat.ConnLimit = connLimitStats
+		domStat.Processes = processStats
+		domStat.IOThreads = ioThreadStats
+		domStat.Hugepages = hugepagesStats
+		domStat.CPUPressure = cpuPressureStats
+		domStat.BlockThrottle = l.ioThrottle.observe(time.Now(), domStat.Block)
+		if domStat.DirtyRate, err = l.dirtyRate.observe(l.virConn, domStat.Name); err != nil {
+			log.Log.Reason(err).Warning("failed to collect dirty rate stats")
+		}
+
+		var devices []string
+		for _, net := range domStat.Net {
+			if net.NameSet {
+				devices = append(devices, net.Name)
+			}
+		}
+		if domStat.NetThrottle, err = network.GetPodBandwidthLimitStats(devices); err != nil {
+			log.Log.Reason(err).Warning("failed to collect bandwidth limit stats")
+		}
+	}
+	return domStats, nil
 }
 
 func (l *LibvirtDomainManager) buildDevicesMetadata(vmi *v1.VirtualMachineInstance, dom cli.VirDomain) ([]cloudinit.DeviceData, error) {
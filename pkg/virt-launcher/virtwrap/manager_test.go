@@ -214,6 +214,56 @@ var _ = Describe("Manager", func() {
 			Expect(err).To(BeNil())
 			Expect(newspec).ToNot(BeNil())
 		})
+		It("should unpause a VirtualMachineInstance on SyncVMI, which was paused because of an I/O error without I/O error recovery", func() {
+			// Make sure that we always free the domain after use
+			mockDomain.EXPECT().Free()
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			xml, err := xml.Marshal(domainSpec)
+
+			mockConn.EXPECT().LookupDomainByName(testDomainName).Return(mockDomain, nil)
+			mockDomain.EXPECT().GetState().Return(libvirt.DOMAIN_PAUSED, int(libvirt.DOMAIN_PAUSED_IOERROR), nil)
+			mockDomain.EXPECT().Resume().Return(nil)
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(string(xml), nil)
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			newspec, err := manager.SyncVMI(vmi, true, &cmdv1.VirtualMachineOptions{VirtualMachineSMBios: &cmdv1.SMBios{}})
+			Expect(err).To(BeNil())
+			Expect(newspec).ToNot(BeNil())
+		})
+		It("should not unpause a VirtualMachineInstance on SyncVMI, which was paused because of an I/O error and sets autoResume", func() {
+			// Make sure that we always free the domain after use
+			mockDomain.EXPECT().Free()
+			vmi := newVMI(testNamespace, testVmName)
+			_false := false
+			vmi.Spec.IOErrorRecovery = &v1.IOErrorRecovery{AutoResume: &_false}
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			xml, err := xml.Marshal(domainSpec)
+
+			mockConn.EXPECT().LookupDomainByName(testDomainName).Return(mockDomain, nil)
+			mockDomain.EXPECT().GetState().Return(libvirt.DOMAIN_PAUSED, int(libvirt.DOMAIN_PAUSED_IOERROR), nil)
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(string(xml), nil)
+			// no expected call to unpause
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			newspec, err := manager.SyncVMI(vmi, true, &cmdv1.VirtualMachineOptions{VirtualMachineSMBios: &cmdv1.SMBios{}})
+			Expect(err).To(BeNil())
+			Expect(newspec).ToNot(BeNil())
+		})
+		It("should not unpause a VirtualMachineInstance on SyncVMI, which was paused because its guest crashed", func() {
+			// Make sure that we always free the domain after use
+			mockDomain.EXPECT().Free()
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			xml, err := xml.Marshal(domainSpec)
+
+			mockConn.EXPECT().LookupDomainByName(testDomainName).Return(mockDomain, nil)
+			mockDomain.EXPECT().GetState().Return(libvirt.DOMAIN_PAUSED, int(libvirt.DOMAIN_PAUSED_CRASHED), nil)
+			mockDomain.EXPECT().GetXMLDesc(libvirt.DomainXMLFlags(0)).Return(string(xml), nil)
+			// no expected call to unpause
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			newspec, err := manager.SyncVMI(vmi, true, &cmdv1.VirtualMachineOptions{VirtualMachineSMBios: &cmdv1.SMBios{}})
+			Expect(err).To(BeNil())
+			Expect(newspec).ToNot(BeNil())
+		})
 		It("should not unpause a paused VirtualMachineInstance on SyncVMI, which was paused by user", func() {
 			// Make sure that we always free the domain after use
 			mockDomain.EXPECT().Free()
@@ -333,6 +383,163 @@ var _ = Describe("Manager", func() {
 			manager.MarkGracefulShutdownVMI(vmi)
 		})
 	})
+	Context("test disk compaction", func() {
+		var origGetAllocatedBytes func(files []string) (int64, error)
+
+		BeforeEach(func() {
+			origGetAllocatedBytes = getAllocatedBytes
+		})
+
+		AfterEach(func() {
+			getAllocatedBytes = origGetAllocatedBytes
+		})
+
+		It("should trim the guest and store the reclaimed bytes in the metadata", func() {
+			mockDomain.EXPECT().Free().AnyTimes()
+
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			domainSpec.Devices.Disks = []api.Disk{
+				{
+					Type:   "file",
+					Driver: &api.DiskDriver{Type: "qcow2"},
+					Source: api.DiskSource{File: "/var/run/kubevirt-ephemeral-disks/disk-data/disk0/disk.qcow2"},
+				},
+			}
+			oldXML, err := xml.Marshal(domainSpec)
+			Expect(err).To(BeNil())
+
+			allocated := []int64{3000, 1000}
+			getAllocatedBytes = func(files []string) (int64, error) {
+				Expect(files).To(Equal([]string{"/var/run/kubevirt-ephemeral-disks/disk-data/disk0/disk.qcow2"}))
+				bytes := allocated[0]
+				allocated = allocated[1:]
+				return bytes, nil
+			}
+
+			mockDomain.EXPECT().GetState().AnyTimes().Return(libvirt.DOMAIN_RUNNING, 1, nil)
+			mockConn.EXPECT().LookupDomainByName(testDomainName).AnyTimes().Return(mockDomain, nil)
+			mockDomain.EXPECT().GetXMLDesc(gomock.Eq(libvirt.DOMAIN_XML_MIGRATABLE)).AnyTimes().Return(string(oldXML), nil)
+			mockDomain.EXPECT().GetXMLDesc(gomock.Eq(libvirt.DOMAIN_XML_INACTIVE)).AnyTimes().Return(string(oldXML), nil)
+			mockConn.EXPECT().QemuAgentCommand(guestFstrimCommand, testDomainName).Return("{\"return\":{}}", nil)
+			mockConn.EXPECT().DomainDefineXML(gomock.Any()).DoAndReturn(func(xml string) (cli.VirDomain, error) {
+				Expect(xml).To(ContainSubstring("<reclaimedBytes>2000</reclaimedBytes>"))
+				Expect(xml).ToNot(ContainSubstring("<failureReason>"))
+				return mockDomain, nil
+			})
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+
+			Expect(manager.(*LibvirtDomainManager).compactDisks(vmi)).To(Succeed())
+		})
+	})
+	Context("test memory dump", func() {
+		It("should reject file names outside of the memory dump claim", func() {
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			Expect(manager.MemoryDumpVMI(newVMI(testNamespace, testVmName), "../dump")).ToNot(Succeed())
+		})
+
+		It("should record the start and the result of the dump in the metadata", func() {
+			mockDomain.EXPECT().Free().AnyTimes()
+
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			oldXML, err := xml.Marshal(domainSpec)
+			Expect(err).To(BeNil())
+			// the domain returns what was defined last
+			definedXML := string(oldXML)
+			getXML := func(flags libvirt.DomainXMLFlags) (string, error) {
+				return definedXML, nil
+			}
+
+			mockDomain.EXPECT().GetState().AnyTimes().Return(libvirt.DOMAIN_RUNNING, 1, nil)
+			mockConn.EXPECT().LookupDomainByName(testDomainName).AnyTimes().Return(mockDomain, nil)
+			mockDomain.EXPECT().GetXMLDesc(gomock.Eq(libvirt.DOMAIN_XML_MIGRATABLE)).AnyTimes().DoAndReturn(getXML)
+			mockDomain.EXPECT().GetXMLDesc(gomock.Eq(libvirt.DOMAIN_XML_INACTIVE)).AnyTimes().DoAndReturn(getXML)
+			mockDomain.EXPECT().CoreDumpWithFormat("/var/run/kubevirt/container-disks/memory-dump/testvmi.memory.dump", libvirt.DOMAIN_CORE_DUMP_FORMAT_RAW, libvirt.DUMP_MEMORY_ONLY).Return(nil)
+			done := make(chan struct{})
+			gomock.InOrder(
+				mockConn.EXPECT().DomainDefineXML(gomock.Any()).DoAndReturn(func(xml string) (cli.VirDomain, error) {
+					Expect(xml).To(ContainSubstring("<fileName>testvmi.memory.dump</fileName>"))
+					Expect(xml).ToNot(ContainSubstring("<completed>"))
+					definedXML = xml
+					return mockDomain, nil
+				}),
+				mockConn.EXPECT().DomainDefineXML(gomock.Any()).DoAndReturn(func(xml string) (cli.VirDomain, error) {
+					defer close(done)
+					Expect(xml).To(ContainSubstring("<completed>true</completed>"))
+					return mockDomain, nil
+				}),
+			)
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+
+			Expect(manager.MemoryDumpVMI(vmi, "testvmi.memory.dump")).To(Succeed())
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	Context("test fsfreeze", func() {
+		var definedXML string
+
+		BeforeEach(func() {
+			mockDomain.EXPECT().Free().AnyTimes()
+			getXML := func(flags libvirt.DomainXMLFlags) (string, error) {
+				return definedXML, nil
+			}
+			mockConn.EXPECT().LookupDomainByName(testDomainName).AnyTimes().Return(mockDomain, nil)
+			mockDomain.EXPECT().GetXMLDesc(gomock.Any()).AnyTimes().DoAndReturn(getXML)
+		})
+
+		It("should freeze the guest filesystems and record it in the metadata", func() {
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			oldXML, err := xml.Marshal(domainSpec)
+			Expect(err).To(BeNil())
+			definedXML = string(oldXML)
+
+			mockConn.EXPECT().QemuAgentCommand(guestFSFreezeCommand, testDomainName).Return("{\"return\":2}", nil)
+			mockConn.EXPECT().DomainDefineXML(gomock.Any()).DoAndReturn(func(xml string) (cli.VirDomain, error) {
+				Expect(xml).To(ContainSubstring("<fsFreeze>"))
+				return mockDomain, nil
+			})
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+
+			Expect(manager.FreezeVMI(vmi)).To(Succeed())
+		})
+
+		It("should not freeze the guest filesystems twice", func() {
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			now := metav1.Now()
+			domainSpec.Metadata.KubeVirt.FSFreeze = &api.FSFreezeMetadata{Timestamp: &now}
+			oldXML, err := xml.Marshal(domainSpec)
+			Expect(err).To(BeNil())
+			definedXML = string(oldXML)
+
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+
+			Expect(manager.FreezeVMI(vmi)).To(Succeed())
+		})
+
+		It("should thaw the guest filesystems and drop the metadata", func() {
+			vmi := newVMI(testNamespace, testVmName)
+			domainSpec := expectIsolationDetectionForVMI(vmi)
+			now := metav1.Now()
+			domainSpec.Metadata.KubeVirt.FSFreeze = &api.FSFreezeMetadata{Timestamp: &now}
+			oldXML, err := xml.Marshal(domainSpec)
+			Expect(err).To(BeNil())
+			definedXML = string(oldXML)
+
+			mockConn.EXPECT().QemuAgentCommand(guestFSThawCommand, testDomainName).Return("{\"return\":2}", nil)
+			mockConn.EXPECT().DomainDefineXML(gomock.Any()).DoAndReturn(func(xml string) (cli.VirDomain, error) {
+				Expect(xml).ToNot(ContainSubstring("<fsFreeze>"))
+				return mockDomain, nil
+			})
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+
+			Expect(manager.UnfreezeVMI(vmi)).To(Succeed())
+		})
+	})
+
 	Context("test migration monitor", func() {
 		It("migration should be canceled if it's not progressing", func() {
 			migrationErrorChan := make(chan error)
@@ -762,19 +969,90 @@ var _ = Describe("Manager", func() {
 	)
 
 	Context("on successful GetAllDomainStats", func() {
+		BeforeEach(func() {
+			mockConn.EXPECT().QemuMonitorCommand(gomock.Any(), gomock.Any()).Return(`{"return":{"status":"measuring"}}`, nil).AnyTimes()
+		})
+
 		It("should return content", func() {
+			StubOutNetworkForTest()
+			mockConn.EXPECT().GetDomainStats(
+				gomock.Eq(libvirt.DOMAIN_STATS_BALLOON|libvirt.DOMAIN_STATS_CPU_TOTAL|libvirt.DOMAIN_STATS_VCPU|libvirt.DOMAIN_STATS_INTERFACE|libvirt.DOMAIN_STATS_BLOCK),
+				gomock.Eq(libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING),
+			).Return([]*stats.DomainStats{
+				&stats.DomainStats{},
+			}, nil)
+
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			domStats, err := manager.GetDomainStats()
+
+			Expect(err).To(BeNil())
+			Expect(len(domStats)).To(Equal(1))
+		})
+
+		It("should add the connection limit drops", func() {
+			mockConn.EXPECT().GetDomainStats(
+				gomock.Eq(libvirt.DOMAIN_STATS_BALLOON|libvirt.DOMAIN_STATS_CPU_TOTAL|libvirt.DOMAIN_STATS_VCPU|libvirt.DOMAIN_STATS_INTERFACE|libvirt.DOMAIN_STATS_BLOCK),
+				gomock.Eq(libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING),
+			).Return([]*stats.DomainStats{
+				&stats.DomainStats{},
+			}, nil)
+			connLimitStats := []stats.DomainStatsConnLimit{
+				{Name: "default", Reason: network.ConnectionLimitMaxConnections, Dropped: 42},
+			}
+			network.GetPodConnectionLimitStats = func() ([]stats.DomainStatsConnLimit, error) { return connLimitStats, nil }
+			defer StubOutNetworkForTest()
+
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			domStats, err := manager.GetDomainStats()
+
+			Expect(err).To(BeNil())
+			Expect(len(domStats)).To(Equal(1))
+			Expect(domStats[0].ConnLimit).To(Equal(connLimitStats))
+		})
+
+		It("should not fail when the connection limit drops can't be collected", func() {
 			mockConn.EXPECT().GetDomainStats(
 				gomock.Eq(libvirt.DOMAIN_STATS_BALLOON|libvirt.DOMAIN_STATS_CPU_TOTAL|libvirt.DOMAIN_STATS_VCPU|libvirt.DOMAIN_STATS_INTERFACE|libvirt.DOMAIN_STATS_BLOCK),
 				gomock.Eq(libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING),
 			).Return([]*stats.DomainStats{
 				&stats.DomainStats{},
 			}, nil)
+			network.GetPodConnectionLimitStats = func() ([]stats.DomainStatsConnLimit, error) { return nil, fmt.Errorf("no iptables") }
+			defer StubOutNetworkForTest()
 
 			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
 			domStats, err := manager.GetDomainStats()
 
 			Expect(err).To(BeNil())
 			Expect(len(domStats)).To(Equal(1))
+			Expect(domStats[0].ConnLimit).To(BeEmpty())
+		})
+
+		It("should add the bandwidth limit stats of the interfaces", func() {
+			mockConn.EXPECT().GetDomainStats(
+				gomock.Eq(libvirt.DOMAIN_STATS_BALLOON|libvirt.DOMAIN_STATS_CPU_TOTAL|libvirt.DOMAIN_STATS_VCPU|libvirt.DOMAIN_STATS_INTERFACE|libvirt.DOMAIN_STATS_BLOCK),
+				gomock.Eq(libvirt.CONNECT_GET_ALL_DOMAINS_STATS_RUNNING),
+			).Return([]*stats.DomainStats{
+				&stats.DomainStats{
+					Net: []stats.DomainStatsNet{{Name: "vnet0", NameSet: true}, {Name: "vnet1"}},
+				},
+			}, nil)
+			netThrottleStats := []stats.DomainStatsNetThrottle{
+				{Name: "vnet0", Type: stats.NetTypeTx, Throttled: 7},
+			}
+			var devices []string
+			network.GetPodBandwidthLimitStats = func(d []string) ([]stats.DomainStatsNetThrottle, error) {
+				devices = d
+				return netThrottleStats, nil
+			}
+			defer StubOutNetworkForTest()
+
+			manager, _ := NewLibvirtDomainManager(mockConn, "fake", nil, 0, nil, "/usr/share/OVMF")
+			domStats, err := manager.GetDomainStats()
+
+			Expect(err).To(BeNil())
+			Expect(devices).To(Equal([]string{"vnet0"}))
+			Expect(domStats[0].NetThrottle).To(Equal(netThrottleStats))
 		})
 	})
 
@@ -904,6 +1182,8 @@ func newVMI(namespace, name string) *v1.VirtualMachineInstance {
 
 func StubOutNetworkForTest() {
 	network.SetupPodNetworkPhase2 = func(vm *v1.VirtualMachineInstance, domain *api.Domain) error { return nil }
+	network.GetPodConnectionLimitStats = func() ([]stats.DomainStatsConnLimit, error) { return nil, nil }
+	network.GetPodBandwidthLimitStats = func([]string) ([]stats.DomainStatsNetThrottle, error) { return nil, nil }
 }
 
 func addCloudInitDisk(vmi *v1.VirtualMachineInstance, userData string, networkData string) {
@@ -0,0 +1,91 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const allowedAddressesTable = "kubevirt_allowed_addresses"
+
+// createAllowedAddressesRules drops the traffic the guest sends through the
+// bridge from other addresses than the ones of the interface and its allowed
+// addresses. IP addresses are only filtered for the families the allowed
+// addresses list, the guest may send DHCP requests, ARP probes and IPv6
+// duplicate address detection from the unspecified and link local addresses.
+func (b *BridgePodInterface) createAllowedAddressesRules() error {
+	if b.iface.AllowedAddresses == nil {
+		return nil
+	}
+	return Handler.NftablesApplyRuleset(b.allowedAddressesRuleset())
+}
+
+// allowedAddressesRuleset returns the chain of the interface in the bridge
+// table. It sees the frames of every bridge of the pod, but only filters the
+// ones its bridge receives from the tap device of the guest.
+func (b *BridgePodInterface) allowedAddressesRuleset() string {
+	allowed := b.iface.AllowedAddresses
+
+	macs := []string{b.vif.MAC.String()}
+	for _, mac := range allowed.MACAddresses {
+		if hwAddr, err := net.ParseMAC(mac); err == nil {
+			macs = append(macs, hwAddr.String())
+		}
+	}
+	var ipv4, ipv6 []string
+	for _, ip := range allowed.IPAddresses {
+		if strings.Contains(ip, ":") {
+			ipv6 = append(ipv6, ip)
+		} else {
+			ipv4 = append(ipv4, ip)
+		}
+	}
+
+	rules := []string{
+		"type filter hook forward priority 0; policy accept;",
+		fmt.Sprintf("meta ibrname != %q accept", b.bridgeInterfaceName),
+		fmt.Sprintf("iifname %q accept", b.podInterfaceName),
+		fmt.Sprintf("ether saddr != %s drop", nftablesSet(macs)),
+		fmt.Sprintf("arp saddr ether != %s drop", nftablesSet(macs)),
+	}
+	if len(ipv4) > 0 {
+		sources := []string{"0.0.0.0"}
+		if !b.vif.IPAMDisabled {
+			sources = append(sources, b.vif.IP.IP.String())
+		}
+		sources = append(sources, ipv4...)
+		rules = append(rules,
+			fmt.Sprintf("ip saddr != %s drop", nftablesSet(sources)),
+			fmt.Sprintf("arp saddr ip != %s drop", nftablesSet(sources)))
+	}
+	if len(ipv6) > 0 {
+		sources := append([]string{"::", "fe80::/10"}, ipv6...)
+		rules = append(rules, fmt.Sprintf("ip6 saddr != %s drop", nftablesSet(sources)))
+	}
+
+	return fmt.Sprintf("table bridge %s {\n\tchain %s {\n\t\t%s\n\t}\n}\n",
+		allowedAddressesTable, b.podInterfaceName, strings.Join(rules, "\n\t\t"))
+}
+
+func nftablesSet(elements []string) string {
+	return fmt.Sprintf("{ %s }", strings.Join(elements, ", "))
+}
@@ -0,0 +1,115 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Allowed addresses", func() {
+	var mockNetwork *MockNetworkHandler
+	var ctrl *gomock.Controller
+	var bridge *BridgePodInterface
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockNetwork = NewMockNetworkHandler(ctrl)
+		Handler = mockNetwork
+
+		mac, _ := net.ParseMAC("12:34:56:78:9a:bc")
+		ip, _ := netlink.ParseAddr("10.35.0.6/24")
+		bridge = &BridgePodInterface{
+			iface: &v1.Interface{
+				Name:                   "vrrp",
+				InterfaceBindingMethod: v1.InterfaceBindingMethod{Bridge: &v1.InterfaceBridge{}},
+				AllowedAddresses: &v1.AllowedAddresses{
+					MACAddresses: []string{"00:00:5E:00:01:0A"},
+				},
+			},
+			vif:                 &VIF{Name: "net1", MAC: mac, IP: *ip},
+			podInterfaceName:    "net1",
+			bridgeInterfaceName: "k6t-net1",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should not create any rule without allowed addresses", func() {
+		bridge.iface.AllowedAddresses = nil
+		Expect(bridge.createAllowedAddressesRules()).To(Succeed())
+	})
+
+	It("should only filter the MAC addresses without allowed IP addresses", func() {
+		mockNetwork.EXPECT().NftablesApplyRuleset(`table bridge kubevirt_allowed_addresses {
+	chain net1 {
+		type filter hook forward priority 0; policy accept;
+		meta ibrname != "k6t-net1" accept
+		iifname "net1" accept
+		ether saddr != { 12:34:56:78:9a:bc, 00:00:5e:00:01:0a } drop
+		arp saddr ether != { 12:34:56:78:9a:bc, 00:00:5e:00:01:0a } drop
+	}
+}
+`).Return(nil)
+		Expect(bridge.createAllowedAddressesRules()).To(Succeed())
+	})
+
+	It("should filter the IP addresses of the families of the allowed IP addresses", func() {
+		bridge.iface.AllowedAddresses.IPAddresses = []string{"10.35.0.100", "fd00::100"}
+		mockNetwork.EXPECT().NftablesApplyRuleset(`table bridge kubevirt_allowed_addresses {
+	chain net1 {
+		type filter hook forward priority 0; policy accept;
+		meta ibrname != "k6t-net1" accept
+		iifname "net1" accept
+		ether saddr != { 12:34:56:78:9a:bc, 00:00:5e:00:01:0a } drop
+		arp saddr ether != { 12:34:56:78:9a:bc, 00:00:5e:00:01:0a } drop
+		ip saddr != { 0.0.0.0, 10.35.0.6, 10.35.0.100 } drop
+		arp saddr ip != { 0.0.0.0, 10.35.0.6, 10.35.0.100 } drop
+		ip6 saddr != { ::, fe80::/10, fd00::100 } drop
+	}
+}
+`).Return(nil)
+		Expect(bridge.createAllowedAddressesRules()).To(Succeed())
+	})
+
+	It("should only allow the listed IPv4 addresses if the pod interface has none", func() {
+		bridge.vif.IPAMDisabled = true
+		bridge.iface.AllowedAddresses.IPAddresses = []string{"192.168.0.0/24"}
+		mockNetwork.EXPECT().NftablesApplyRuleset(gomock.Any()).Do(func(ruleset string) {
+			Expect(ruleset).To(ContainSubstring("ip saddr != { 0.0.0.0, 192.168.0.0/24 } drop"))
+			Expect(ruleset).ToNot(ContainSubstring("ip6 saddr"))
+		}).Return(nil)
+		Expect(bridge.createAllowedAddressesRules()).To(Succeed())
+	})
+
+	It("should fail if the rules could not be applied", func() {
+		mockNetwork.EXPECT().NftablesApplyRuleset(gomock.Any()).Return(fmt.Errorf("no nft"))
+		Expect(bridge.createAllowedAddressesRules()).To(MatchError("no nft"))
+	})
+})
@@ -0,0 +1,74 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var tcOverlimitsRegex = regexp.MustCompile(`\(dropped \d+, overlimits (\d+)`)
+
+// GetBandwidthLimitStats returns the packets which exceeded the bandwidth
+// limits libvirt applied to the given tap devices. The traffic received by the
+// guest is shaped by the htb class 1:1 of the device, the traffic sent by the
+// guest is policed by a filter of its ingress qdisc. Devices without limits
+// are skipped. It has to be called from within the network namespace of the
+// virt-launcher pod.
+func GetBandwidthLimitStats(devices []string) ([]stats.DomainStatsNetThrottle, error) {
+	var throttleStats []stats.DomainStatsNetThrottle
+	for _, device := range devices {
+		output, err := exec.Command("tc", "-s", "class", "show", "dev", device, "classid", "1:1").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tc classes of %s: %s", device, string(output))
+		}
+		if overlimits, exists := parseTcOverlimits(string(output)); exists {
+			throttleStats = append(throttleStats, stats.DomainStatsNetThrottle{Name: device, Type: stats.NetTypeRx, Throttled: overlimits})
+		}
+
+		output, err = exec.Command("tc", "-s", "filter", "show", "dev", device, "parent", "ffff:").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tc filters of %s: %s", device, string(output))
+		}
+		if overlimits, exists := parseTcOverlimits(string(output)); exists {
+			throttleStats = append(throttleStats, stats.DomainStatsNetThrottle{Name: device, Type: stats.NetTypeTx, Throttled: overlimits})
+		}
+	}
+	return throttleStats, nil
+}
+
+// parseTcOverlimits sums up the overlimits of the statistics in the output
+// of tc -s
+func parseTcOverlimits(output string) (uint64, bool) {
+	var overlimits uint64
+	matches := tcOverlimitsRegex.FindAllStringSubmatch(output, -1)
+	for _, match := range matches {
+		value, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		overlimits += value
+	}
+	return overlimits, len(matches) > 0
+}
@@ -0,0 +1,59 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bandwidth limits", func() {
+	It("should parse the overlimits of the htb class", func() {
+		output := `class htb 1:1 root leaf 2: prio 0 rate 8Mbit ceil 16Mbit burst 256Kb cburst 1600b 
+ Sent 1048576 bytes 712 pkt (dropped 0, overlimits 42 requeues 0) 
+ backlog 0b 0p requeues 0
+ lended: 700 borrowed: 0 giants: 0
+ tokens: 3051 ctokens: 12
+`
+		overlimits, exists := parseTcOverlimits(output)
+		Expect(exists).To(BeTrue())
+		Expect(overlimits).To(Equal(uint64(42)))
+	})
+
+	It("should parse the overlimits of the police filter", func() {
+		output := `filter protocol all pref 49152 u32 chain 0 
+filter protocol all pref 49152 u32 chain 0 fh 800: ht divisor 1 
+filter protocol all pref 49152 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 flowid :1 not_in_hw 
+  match 00000000/00000000 at 0
+ police 0x1 rate 4Mbit burst 1Kb mtu 64Kb action drop overhead 0b 
+	ref 1 bind 1 
+
+ Sent 524288 bytes 360 pkt (dropped 7, overlimits 7 requeues 0) 
+`
+		overlimits, exists := parseTcOverlimits(output)
+		Expect(exists).To(BeTrue())
+		Expect(overlimits).To(Equal(uint64(7)))
+	})
+
+	It("should report nothing for a device without limits", func() {
+		_, exists := parseTcOverlimits("")
+		Expect(exists).To(BeFalse())
+	})
+})
@@ -29,6 +29,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/coreos/go-iptables/iptables"
 
@@ -101,7 +102,10 @@ type NetworkHandler interface {
 	NftablesNewChain(proto iptables.Protocol, table, chain string) error
 	NftablesAppendRule(proto iptables.Protocol, table, chain string, rulespec ...string) error
 	NftablesLoad(fnName string) error
+	NftablesApplyRuleset(ruleset string) error
 	GetNFTIPString(proto iptables.Protocol) string
+	IptablesRuleCounters(proto iptables.Protocol, table, chain string) (map[string]uint64, error)
+	NftablesRuleCounters(proto iptables.Protocol, table, chain string) (map[string]uint64, error)
 }
 
 type NetworkUtilsHandler struct{}
@@ -224,6 +228,64 @@ func (h *NetworkUtilsHandler) NftablesLoad(fnName string) error {
 
 	return nil
 }
+
+// NftablesApplyRuleset adds the tables, chains and rules of the ruleset
+func (h *NetworkUtilsHandler) NftablesApplyRuleset(ruleset string) error {
+	cmd := exec.Command("nft", "-f", "-")
+	cmd.Stdin = strings.NewReader(ruleset)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to apply nftables ruleset error %s", string(output))
+	}
+
+	return nil
+}
+
+// IptablesRuleCounters returns the number of packets which matched the rules of
+// the given chain, keyed by the comment of the rule. Rules without a comment are
+// skipped. A chain which does not exist has no counters.
+func (h *NetworkUtilsHandler) IptablesRuleCounters(proto iptables.Protocol, table, chain string) (map[string]uint64, error) {
+	iptablesObject, err := iptables.NewWithProtocol(proto)
+	if err != nil {
+		return nil, err
+	}
+
+	chains, err := iptablesObject.ListChains(table)
+	if err != nil {
+		return nil, err
+	}
+	counters := map[string]uint64{}
+	if !containsString(chains, chain) {
+		return counters, nil
+	}
+
+	stats, err := iptablesObject.StructuredStats(table, chain)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		if match := iptablesCommentRegex.FindStringSubmatch(stat.Options); match != nil {
+			counters[match[1]] += stat.Packets
+		}
+	}
+	return counters, nil
+}
+
+// NftablesRuleCounters returns the number of packets which matched the rules of
+// the given chain, keyed by the comment of the rule. Rules without a comment or
+// without a counter are skipped. A chain which does not exist has no counters.
+func (h *NetworkUtilsHandler) NftablesRuleCounters(proto iptables.Protocol, table, chain string) (map[string]uint64, error) {
+	output, err := exec.Command("nft", "list", "chain", Handler.GetNFTIPString(proto), table, chain).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "No such file or directory") {
+			return map[string]uint64{}, nil
+		}
+		return nil, fmt.Errorf("failed to list nftables chain %s error %s", chain, string(output))
+	}
+
+	return parseNftablesRuleCounters(string(output)), nil
+}
+
 func (h *NetworkUtilsHandler) GetHostAndGwAddressesFromCIDR(s string) (string, string, error) {
 	ip, ipnet, err := net.ParseCIDR(s)
 	if err != nil {
@@ -344,6 +406,8 @@ func (h *NetworkUtilsHandler) GenerateRandomMac() (net.HardwareAddr, error) {
 // Allow mocking for tests
 var SetupPodNetworkPhase1 = SetupNetworkInterfacesPhase1
 var SetupPodNetworkPhase2 = SetupNetworkInterfacesPhase2
+var GetPodConnectionLimitStats = GetConnectionLimitStats
+var GetPodBandwidthLimitStats = GetBandwidthLimitStats
 var DHCPServer = dhcp.SingleClientDHCPServer
 
 func initHandler() {
@@ -0,0 +1,247 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+const connectionLimitChain = "KUBEVIRT_CONNLIMIT"
+
+// Reasons for which the connection limit rules drop a new connection
+const (
+	ConnectionLimitMaxConnections             = "max-connections"
+	ConnectionLimitMaxNewConnectionsPerSecond = "max-new-connections-per-second"
+)
+
+var (
+	iptablesCommentRegex = regexp.MustCompile(`/\* (\S+) \*/`)
+	nftablesCounterRegex = regexp.MustCompile(`counter packets (\d+) bytes \d+.* comment "([^"]+)"`)
+)
+
+// Every rule dropping connections is tagged with the VMI interface name and
+// the limit it enforces, so that its counter can be reported per interface.
+func connectionLimitComment(ifaceName, reason string) string {
+	return fmt.Sprintf("%s/%s", ifaceName, reason)
+}
+
+func getNftablesFilterName(proto iptables.Protocol) string {
+	if proto == iptables.ProtocolIPv6 {
+		return "ipv6-filter"
+	}
+	return "ipv4-filter"
+}
+
+// createConnectionLimitRules restricts the connections the guest can open through
+// the masquerade bridge. Connections are tracked by the conntrack table of the
+// node, so without a limit a single guest is able to exhaust it.
+func (p *MasqueradePodInterface) createConnectionLimitRules(proto iptables.Protocol) error {
+	if p.iface.ConnectionLimits == nil {
+		return nil
+	}
+	if Handler.HasNatIptables(proto) {
+		return p.createConnectionLimitRulesUsingIptables(proto)
+	}
+	if err := Handler.NftablesLoad(getNftablesFilterName(proto)); err != nil {
+		return err
+	}
+	return p.createConnectionLimitRulesUsingNftables(proto)
+}
+
+func (p *MasqueradePodInterface) createConnectionLimitRulesUsingIptables(proto iptables.Protocol) error {
+	limits := p.iface.ConnectionLimits
+
+	err := Handler.IptablesNewChain(proto, "filter", connectionLimitChain)
+	if err != nil {
+		return err
+	}
+
+	err = Handler.IptablesAppendRule(proto, "filter", "FORWARD",
+		"-i", p.bridgeInterfaceName,
+		"-o", p.podInterfaceName,
+		"-m", "conntrack", "--ctstate", "NEW",
+		"-j", connectionLimitChain)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxConnections != nil {
+		err = Handler.IptablesAppendRule(proto, "filter", connectionLimitChain,
+			"-i", p.bridgeInterfaceName,
+			"-m", "connlimit",
+			"--connlimit-above", strconv.FormatUint(uint64(*limits.MaxConnections), 10),
+			"--connlimit-mask", "0",
+			"-m", "comment", "--comment", connectionLimitComment(p.iface.Name, ConnectionLimitMaxConnections),
+			"-j", "DROP")
+		if err != nil {
+			return err
+		}
+	}
+
+	if limits.MaxNewConnectionsPerSecond != nil {
+		rate := strconv.FormatUint(uint64(*limits.MaxNewConnectionsPerSecond), 10)
+		err = Handler.IptablesAppendRule(proto, "filter", connectionLimitChain,
+			"-i", p.bridgeInterfaceName,
+			"-m", "limit",
+			"--limit", rate+"/second",
+			"--limit-burst", rate,
+			"-j", "RETURN")
+		if err != nil {
+			return err
+		}
+
+		err = Handler.IptablesAppendRule(proto, "filter", connectionLimitChain,
+			"-i", p.bridgeInterfaceName,
+			"-m", "comment", "--comment", connectionLimitComment(p.iface.Name, ConnectionLimitMaxNewConnectionsPerSecond),
+			"-j", "DROP")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *MasqueradePodInterface) createConnectionLimitRulesUsingNftables(proto iptables.Protocol) error {
+	limits := p.iface.ConnectionLimits
+
+	err := Handler.NftablesNewChain(proto, "filter", connectionLimitChain)
+	if err != nil {
+		return err
+	}
+
+	err = Handler.NftablesAppendRule(proto, "filter", "forward",
+		"iifname", p.bridgeInterfaceName,
+		"oifname", p.podInterfaceName,
+		"ct", "state", "new",
+		"counter", "jump", connectionLimitChain)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxConnections != nil {
+		err = Handler.NftablesAppendRule(proto, "filter", connectionLimitChain,
+			"iifname", p.bridgeInterfaceName,
+			"ct", "count", "over", strconv.FormatUint(uint64(*limits.MaxConnections), 10),
+			"counter", "drop",
+			"comment", strconv.Quote(connectionLimitComment(p.iface.Name, ConnectionLimitMaxConnections)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if limits.MaxNewConnectionsPerSecond != nil {
+		rate := strconv.FormatUint(uint64(*limits.MaxNewConnectionsPerSecond), 10)
+		err = Handler.NftablesAppendRule(proto, "filter", connectionLimitChain,
+			"iifname", p.bridgeInterfaceName,
+			"limit", "rate", "over", rate+"/second", "burst", rate, "packets",
+			"counter", "drop",
+			"comment", strconv.Quote(connectionLimitComment(p.iface.Name, ConnectionLimitMaxNewConnectionsPerSecond)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetConnectionLimitStats returns the number of new connections dropped by the
+// connection limits of the VMI interfaces. It has to be called from within the
+// network namespace of the virt-launcher pod.
+func GetConnectionLimitStats() ([]stats.DomainStatsConnLimit, error) {
+	initHandler()
+
+	protocols := []iptables.Protocol{iptables.ProtocolIPv4}
+	if Handler.IsIpv6Enabled() {
+		protocols = append(protocols, iptables.ProtocolIPv6)
+	}
+
+	dropped := map[string]uint64{}
+	for _, proto := range protocols {
+		var counters map[string]uint64
+		var err error
+		if Handler.HasNatIptables(proto) {
+			counters, err = Handler.IptablesRuleCounters(proto, "filter", connectionLimitChain)
+		} else {
+			counters, err = Handler.NftablesRuleCounters(proto, "filter", connectionLimitChain)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for comment, packets := range counters {
+			dropped[comment] += packets
+		}
+	}
+
+	comments := make([]string, 0, len(dropped))
+	for comment := range dropped {
+		comments = append(comments, comment)
+	}
+	sort.Strings(comments)
+
+	var connLimitStats []stats.DomainStatsConnLimit
+	for _, comment := range comments {
+		parts := strings.SplitN(comment, "/", 2)
+		if len(parts) != 2 {
+			log.Log.V(4).Infof("ignoring connection limit rule with unexpected comment %s", comment)
+			continue
+		}
+		connLimitStats = append(connLimitStats, stats.DomainStatsConnLimit{
+			Name:    parts[0],
+			Reason:  parts[1],
+			Dropped: dropped[comment],
+		})
+	}
+	return connLimitStats, nil
+}
+
+func parseNftablesRuleCounters(output string) map[string]uint64 {
+	counters := map[string]uint64{}
+	for _, line := range strings.Split(output, "\n") {
+		match := nftablesCounterRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		packets, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		counters[match[2]] += packets
+	}
+	return counters
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var _ = Describe("Connection limits", func() {
+	var mockNetwork *MockNetworkHandler
+	var ctrl *gomock.Controller
+	var masq *MasqueradePodInterface
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockNetwork = NewMockNetworkHandler(ctrl)
+		Handler = mockNetwork
+
+		maxConnections := uint32(100)
+		maxRate := uint32(10)
+		masq = &MasqueradePodInterface{
+			iface: &v1.Interface{
+				Name:                   "default",
+				InterfaceBindingMethod: v1.InterfaceBindingMethod{Masquerade: &v1.InterfaceMasquerade{}},
+				ConnectionLimits: &v1.ConnectionLimits{
+					MaxConnections:             &maxConnections,
+					MaxNewConnectionsPerSecond: &maxRate,
+				},
+			},
+			podInterfaceName:    "eth0",
+			bridgeInterfaceName: "k6t-eth0",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("createConnectionLimitRules", func() {
+		It("should not create any rule without limits", func() {
+			masq.iface.ConnectionLimits = nil
+			Expect(masq.createConnectionLimitRules(iptables.ProtocolIPv4)).To(Succeed())
+		})
+
+		It("should create the limit rules using iptables", func() {
+			proto := iptables.ProtocolIPv4
+			mockNetwork.EXPECT().HasNatIptables(proto).Return(true)
+			gomock.InOrder(
+				mockNetwork.EXPECT().IptablesNewChain(proto, "filter", "KUBEVIRT_CONNLIMIT").Return(nil),
+				mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "FORWARD",
+					"-i", "k6t-eth0", "-o", "eth0", "-m", "conntrack", "--ctstate", "NEW", "-j", "KUBEVIRT_CONNLIMIT").Return(nil),
+				mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+					"-i", "k6t-eth0", "-m", "connlimit", "--connlimit-above", "100", "--connlimit-mask", "0",
+					"-m", "comment", "--comment", "default/max-connections", "-j", "DROP").Return(nil),
+				mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+					"-i", "k6t-eth0", "-m", "limit", "--limit", "10/second", "--limit-burst", "10", "-j", "RETURN").Return(nil),
+				mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+					"-i", "k6t-eth0", "-m", "comment", "--comment", "default/max-new-connections-per-second", "-j", "DROP").Return(nil),
+			)
+
+			Expect(masq.createConnectionLimitRules(proto)).To(Succeed())
+		})
+
+		It("should create the limit rules using nftables", func() {
+			proto := iptables.ProtocolIPv6
+			mockNetwork.EXPECT().HasNatIptables(proto).Return(false)
+			gomock.InOrder(
+				mockNetwork.EXPECT().NftablesLoad("ipv6-filter").Return(nil),
+				mockNetwork.EXPECT().NftablesNewChain(proto, "filter", "KUBEVIRT_CONNLIMIT").Return(nil),
+				mockNetwork.EXPECT().NftablesAppendRule(proto, "filter", "forward",
+					"iifname", "k6t-eth0", "oifname", "eth0", "ct", "state", "new", "counter", "jump", "KUBEVIRT_CONNLIMIT").Return(nil),
+				mockNetwork.EXPECT().NftablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+					"iifname", "k6t-eth0", "ct", "count", "over", "100", "counter", "drop",
+					"comment", `"default/max-connections"`).Return(nil),
+				mockNetwork.EXPECT().NftablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+					"iifname", "k6t-eth0", "limit", "rate", "over", "10/second", "burst", "10", "packets", "counter", "drop",
+					"comment", `"default/max-new-connections-per-second"`).Return(nil),
+			)
+
+			Expect(masq.createConnectionLimitRules(proto)).To(Succeed())
+		})
+
+		It("should only create the configured limits", func() {
+			proto := iptables.ProtocolIPv4
+			masq.iface.ConnectionLimits.MaxNewConnectionsPerSecond = nil
+			mockNetwork.EXPECT().HasNatIptables(proto).Return(true)
+			mockNetwork.EXPECT().IptablesNewChain(proto, "filter", "KUBEVIRT_CONNLIMIT").Return(nil)
+			mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "FORWARD",
+				"-i", "k6t-eth0", "-o", "eth0", "-m", "conntrack", "--ctstate", "NEW", "-j", "KUBEVIRT_CONNLIMIT").Return(nil)
+			mockNetwork.EXPECT().IptablesAppendRule(proto, "filter", "KUBEVIRT_CONNLIMIT",
+				"-i", "k6t-eth0", "-m", "connlimit", "--connlimit-above", "100", "--connlimit-mask", "0",
+				"-m", "comment", "--comment", "default/max-connections", "-j", "DROP").Return(nil)
+
+			Expect(masq.createConnectionLimitRules(proto)).To(Succeed())
+		})
+
+		It("should fail if the nftables filter table can't be loaded", func() {
+			proto := iptables.ProtocolIPv4
+			mockNetwork.EXPECT().HasNatIptables(proto).Return(false)
+			mockNetwork.EXPECT().NftablesLoad("ipv4-filter").Return(fmt.Errorf("no nftables"))
+
+			Expect(masq.createConnectionLimitRules(proto)).ToNot(Succeed())
+		})
+	})
+
+	Context("GetConnectionLimitStats", func() {
+		It("should sum up the drops of both protocols per interface and reason", func() {
+			mockNetwork.EXPECT().IsIpv6Enabled().Return(true)
+			mockNetwork.EXPECT().HasNatIptables(iptables.ProtocolIPv4).Return(true)
+			mockNetwork.EXPECT().HasNatIptables(iptables.ProtocolIPv6).Return(false)
+			mockNetwork.EXPECT().IptablesRuleCounters(iptables.ProtocolIPv4, "filter", "KUBEVIRT_CONNLIMIT").Return(map[string]uint64{
+				"default/max-connections":                3,
+				"default/max-new-connections-per-second": 5,
+			}, nil)
+			mockNetwork.EXPECT().NftablesRuleCounters(iptables.ProtocolIPv6, "filter", "KUBEVIRT_CONNLIMIT").Return(map[string]uint64{
+				"default/max-connections": 2,
+			}, nil)
+
+			connLimitStats, err := GetConnectionLimitStats()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(connLimitStats).To(Equal([]stats.DomainStatsConnLimit{
+				{Name: "default", Reason: ConnectionLimitMaxConnections, Dropped: 5},
+				{Name: "default", Reason: ConnectionLimitMaxNewConnectionsPerSecond, Dropped: 5},
+			}))
+		})
+
+		It("should return no stats without connection limit rules", func() {
+			mockNetwork.EXPECT().IsIpv6Enabled().Return(false)
+			mockNetwork.EXPECT().HasNatIptables(iptables.ProtocolIPv4).Return(true)
+			mockNetwork.EXPECT().IptablesRuleCounters(iptables.ProtocolIPv4, "filter", "KUBEVIRT_CONNLIMIT").Return(map[string]uint64{}, nil)
+
+			connLimitStats, err := GetConnectionLimitStats()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(connLimitStats).To(BeEmpty())
+		})
+	})
+
+	It("should parse the counters of nftables rules", func() {
+		output := `table ip filter {
+	chain KUBEVIRT_CONNLIMIT {
+		iifname "k6t-eth0" ct count over 100 counter packets 7 bytes 420 drop comment "default/max-connections"
+		iifname "k6t-eth0" limit rate over 10/second burst 10 packets counter packets 12 bytes 720 drop comment "default/max-new-connections-per-second"
+		iifname "k6t-eth0" counter packets 1 bytes 60 accept
+	}
+}
+`
+		Expect(parseNftablesRuleCounters(output)).To(Equal(map[string]uint64{
+			"default/max-connections":                7,
+			"default/max-new-connections-per-second": 12,
+		}))
+	})
+})
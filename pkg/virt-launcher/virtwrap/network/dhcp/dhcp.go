@@ -42,6 +42,7 @@ const (
 	errorSearchDomainNotValid = "Search domain is not valid"
 	errorSearchDomainTooLong  = "Search domains length exceeded allowable size"
 	errorNTPConfiguration     = "Could not parse NTP server as IPv4 address: %s"
+	errorRouteConfiguration   = "Could not parse route to %s via %s as IPv4 route"
 )
 
 // simple domain validation regex. Put it here to avoid compiling each time.
@@ -109,6 +110,24 @@ func prepareDHCPOptions(
 	hostname string,
 	customDHCPOptions *v1.DHCPOptions) (dhcp.Options, error) {
 
+	if customDHCPOptions != nil {
+		if customDHCPOptions.MTU != nil {
+			log.Log.Infof("Setting dhcp option MTU to %d", *customDHCPOptions.MTU)
+			mtu = uint16(*customDHCPOptions.MTU)
+		}
+		if len(customDHCPOptions.SearchDomains) > 0 {
+			log.Log.Infof("Setting dhcp option search domains to %s", customDHCPOptions.SearchDomains)
+			searchDomains = customDHCPOptions.SearchDomains
+		}
+		if len(customDHCPOptions.Routes) > 0 {
+			customRoutes, err := appendCustomRoutes(routes, customDHCPOptions.Routes)
+			if err != nil {
+				return nil, err
+			}
+			routes = &customRoutes
+		}
+	}
+
 	mtuArray := make([]byte, 2)
 	binary.BigEndian.PutUint16(mtuArray, mtu)
 
@@ -271,6 +290,24 @@ func formClasslessRoutes(routes *[]netlink.Route) (formattedRoutes []byte) {
 	return
 }
 
+// appendCustomRoutes returns the routes of the pod interface followed by the
+// custom routes, the routes of the pod interface are left untouched
+func appendCustomRoutes(routes *[]netlink.Route, customRoutes []v1.DHCPRoute) ([]netlink.Route, error) {
+	var allRoutes []netlink.Route
+	if routes != nil {
+		allRoutes = append(allRoutes, *routes...)
+	}
+	for _, customRoute := range customRoutes {
+		_, dst, err := net.ParseCIDR(customRoute.Destination)
+		gw := net.ParseIP(customRoute.Gateway).To4()
+		if err != nil || dst.IP.To4() == nil || gw == nil {
+			return nil, fmt.Errorf(errorRouteConfiguration, customRoute.Destination, customRoute.Gateway)
+		}
+		allRoutes = append(allRoutes, netlink.Route{Dst: dst, Gw: gw})
+	}
+	return allRoutes, nil
+}
+
 func convertSearchDomainsToBytes(searchDomainStrings []string) ([]byte, error) {
 	/*
 	   https://tools.ietf.org/html/rfc3397
@@ -244,5 +244,48 @@ var _ = Describe("DHCP", func() {
 			}))
 			Expect(options[240]).To(Equal([]byte("private.options.kubevirt.io")))
 		})
+
+		It("should override the MTU and the search domains", func() {
+			ip := net.ParseIP("192.168.2.1")
+			mtu := uint32(1400)
+			dhcpOptions := &v1.DHCPOptions{
+				MTU:           &mtu,
+				SearchDomains: []string{"example.com"},
+			}
+
+			options, err := prepareDHCPOptions(ip.DefaultMask(), ip, nil, nil, []string{"default.svc.cluster.local"}, 1500, "myhost", dhcpOptions)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(options[dhcp4.OptionInterfaceMTU]).To(Equal([]byte{0x05, 0x78}))
+			Expect(options[dhcp4.OptionDomainSearch]).To(Equal([]byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}))
+			Expect(options[dhcp4.OptionDomainName]).To(Equal([]byte("example.com")))
+		})
+
+		It("should append the custom routes to the routes of the pod interface", func() {
+			ip := net.ParseIP("192.168.2.1")
+			routes := []netlink.Route{{Gw: net.IPv4(192, 168, 2, 1)}}
+			dhcpOptions := &v1.DHCPOptions{
+				Routes: []v1.DHCPRoute{{Destination: "10.10.0.0/16", Gateway: "192.168.2.254"}},
+			}
+
+			options, err := prepareDHCPOptions(ip.DefaultMask(), ip, nil, &routes, nil, 1500, "myhost", dhcpOptions)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(options[dhcp4.OptionClasslessRouteFormat]).To(Equal([]byte{
+				16, 10, 10, 192, 168, 2, 254,
+				0, 192, 168, 2, 1,
+			}))
+			Expect(routes).To(HaveLen(1))
+		})
+
+		It("should reject custom routes which are not IPv4", func() {
+			ip := net.ParseIP("192.168.2.1")
+			dhcpOptions := &v1.DHCPOptions{
+				Routes: []v1.DHCPRoute{{Destination: "fd10::/64", Gateway: "fd10::1"}},
+			}
+
+			_, err := prepareDHCPOptions(ip.DefaultMask(), ip, nil, nil, nil, 1500, "myhost", dhcpOptions)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
@@ -293,6 +293,16 @@ func (_mr *_MockNetworkHandlerRecorder) NftablesLoad(arg0 interface{}) *gomock.C
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "NftablesLoad", arg0)
 }
 
+func (_m *MockNetworkHandler) NftablesApplyRuleset(ruleset string) error {
+	ret := _m.ctrl.Call(_m, "NftablesApplyRuleset", ruleset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockNetworkHandlerRecorder) NftablesApplyRuleset(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "NftablesApplyRuleset", arg0)
+}
+
 func (_m *MockNetworkHandler) GetNFTIPString(proto iptables.Protocol) string {
 	ret := _m.ctrl.Call(_m, "GetNFTIPString", proto)
 	ret0, _ := ret[0].(string)
@@ -302,3 +312,25 @@ func (_m *MockNetworkHandler) GetNFTIPString(proto iptables.Protocol) string {
 func (_mr *_MockNetworkHandlerRecorder) GetNFTIPString(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetNFTIPString", arg0)
 }
+
+func (_m *MockNetworkHandler) IptablesRuleCounters(proto iptables.Protocol, table string, chain string) (map[string]uint64, error) {
+	ret := _m.ctrl.Call(_m, "IptablesRuleCounters", proto, table, chain)
+	ret0, _ := ret[0].(map[string]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockNetworkHandlerRecorder) IptablesRuleCounters(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "IptablesRuleCounters", arg0, arg1, arg2)
+}
+
+func (_m *MockNetworkHandler) NftablesRuleCounters(proto iptables.Protocol, table string, chain string) (map[string]uint64, error) {
+	ret := _m.ctrl.Call(_m, "NftablesRuleCounters", proto, table, chain)
+	ret0, _ := ret[0].(map[string]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockNetworkHandlerRecorder) NftablesRuleCounters(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "NftablesRuleCounters", arg0, arg1, arg2)
+}
@@ -0,0 +1,105 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+// IstioInjectSidecarAnnotation requests the injection of the Istio proxy into the pod
+const IstioInjectSidecarAnnotation = "sidecar.istio.io/inject"
+
+// istioReservedPorts are the ports the Istio proxy listens on in the pod, the
+// outbound and inbound traffic is redirected to 15001 and 15006 and the health
+// checks are served on 15020 and 15021. They must never be forwarded to the VM.
+var istioReservedPorts = []string{"15000", "15001", "15004", "15006", "15008", "15020", "15021", "15090"}
+
+// IstioAwareMasquerade reports if the masquerade binding adapts its NAT rules to
+// an injected Istio proxy, virt-handler hooks it up to the cluster config
+var IstioAwareMasquerade = func() bool { return false }
+
+func isIstioProxyInjected(vmi *v1.VirtualMachineInstance) bool {
+	return IstioAwareMasquerade() && vmi.Annotations[IstioInjectSidecarAnnotation] == "true"
+}
+
+// getEnvoyLoopbackAddress returns the source address the Istio proxy uses to
+// pass the inbound traffic on to the pod IP
+func getEnvoyLoopbackAddress(proto iptables.Protocol) string {
+	if proto == iptables.ProtocolIPv4 {
+		return "127.0.0.6"
+	} else {
+		return "::6"
+	}
+}
+
+// createIstioNatRulesUsingNftables keeps the traffic to the proxy in the pod
+// and makes the guest reachable for the inbound traffic the proxy passes on
+func (p *MasqueradePodInterface) createIstioNatRulesUsingNftables(proto iptables.Protocol) error {
+	err := Handler.NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+		"tcp", "dport", fmt.Sprintf("{ %s }", strings.Join(istioReservedPorts, ", ")),
+		"counter", "return")
+	if err != nil {
+		return err
+	}
+
+	return Handler.NftablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+		Handler.GetNFTIPString(proto), "saddr", getEnvoyLoopbackAddress(proto),
+		"counter", "snat", "to", p.getGatewayByProtocol(proto))
+}
+
+// createEnvoyDnatRuleUsingNftables forwards the connections the proxy opens to
+// the pod IP to the guest, all of them if port is nil
+func (p *MasqueradePodInterface) createEnvoyDnatRuleUsingNftables(proto iptables.Protocol, port *v1.Port) error {
+	rule := []string{Handler.GetNFTIPString(proto), "saddr", getEnvoyLoopbackAddress(proto)}
+	if port != nil {
+		rule = append(rule, strings.ToLower(port.Protocol), "dport", strconv.Itoa(int(port.Port)))
+	}
+	rule = append(rule, "counter", "dnat", "to", p.getVifIpByProtocol(proto))
+	return Handler.NftablesAppendRule(proto, "nat", "output", rule...)
+}
+
+func (p *MasqueradePodInterface) createIstioNatRulesUsingIptables(proto iptables.Protocol) error {
+	err := Handler.IptablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+		"-p", "tcp",
+		"-m", "multiport", "--dports", strings.Join(istioReservedPorts, ","),
+		"-j", "RETURN")
+	if err != nil {
+		return err
+	}
+
+	return Handler.IptablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+		"--source", getEnvoyLoopbackAddress(proto),
+		"-j", "SNAT", "--to-source", p.getGatewayByProtocol(proto))
+}
+
+func (p *MasqueradePodInterface) createEnvoyDnatRuleUsingIptables(proto iptables.Protocol, port *v1.Port) error {
+	var rule []string
+	if port != nil {
+		rule = append(rule, "-p", strings.ToLower(port.Protocol), "--dport", strconv.Itoa(int(port.Port)))
+	}
+	rule = append(rule, "--source", getEnvoyLoopbackAddress(proto), "-j", "DNAT", "--to-destination", p.getVifIpByProtocol(proto))
+	return Handler.IptablesAppendRule(proto, "nat", "OUTPUT", rule...)
+}
@@ -0,0 +1,145 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package network
+
+import (
+	"net"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Istio aware masquerade", func() {
+	const reservedPorts = "{ 15000, 15001, 15004, 15006, 15008, 15020, 15021, 15090 }"
+	proto := iptables.ProtocolIPv4
+
+	var mockNetwork *MockNetworkHandler
+	var ctrl *gomock.Controller
+	var masq *MasqueradePodInterface
+
+	expectNftablesBaseRules := func() {
+		mockNetwork.EXPECT().NftablesNewChain(proto, "nat", "KUBEVIRT_PREINBOUND").Return(nil)
+		mockNetwork.EXPECT().NftablesNewChain(proto, "nat", "KUBEVIRT_POSTINBOUND").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "postrouting", "ip", "saddr", "10.0.2.2", "counter", "masquerade").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "prerouting", "iifname", "eth0", "counter", "jump", "KUBEVIRT_PREINBOUND").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "postrouting", "oifname", "k6t-eth0", "counter", "jump", "KUBEVIRT_POSTINBOUND").Return(nil)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockNetwork = NewMockNetworkHandler(ctrl)
+		Handler = mockNetwork
+		mockNetwork.EXPECT().GetNFTIPString(proto).Return("ip").AnyTimes()
+
+		vmi := newVMIMasqueradeInterface("testnamespace", "testVmName")
+		vmi.Annotations = map[string]string{IstioInjectSidecarAnnotation: "true"}
+		masq = &MasqueradePodInterface{
+			vmi:                 vmi,
+			iface:               &vmi.Spec.Domain.Devices.Interfaces[0],
+			vif:                 &VIF{IP: netlink.Addr{IPNet: &net.IPNet{IP: net.ParseIP("10.0.2.2")}}},
+			gatewayAddr:         &netlink.Addr{IPNet: &net.IPNet{IP: net.ParseIP("10.0.2.1")}},
+			podInterfaceName:    "eth0",
+			bridgeInterfaceName: "k6t-eth0",
+		}
+		IstioAwareMasquerade = func() bool { return true }
+	})
+
+	AfterEach(func() {
+		IstioAwareMasquerade = func() bool { return false }
+		ctrl.Finish()
+	})
+
+	It("should keep the proxy ports in the pod and pass the proxied traffic to the guest using nftables", func() {
+		expectNftablesBaseRules()
+		gomock.InOrder(
+			mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+				"tcp", "dport", reservedPorts, "counter", "return").Return(nil),
+			mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+				"counter", "dnat", "to", "10.0.2.2").Return(nil),
+		)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+			"ip", "saddr", "127.0.0.6", "counter", "snat", "to", "10.0.2.1").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "output",
+			"ip", "saddr", "127.0.0.6", "counter", "dnat", "to", "10.0.2.2").Return(nil)
+
+		Expect(masq.createNatRulesUsingNftables(proto)).To(Succeed())
+	})
+
+	It("should only pass the proxied traffic of the forwarded ports to the guest using nftables", func() {
+		masq.iface.Ports = []v1.Port{{Name: "http", Port: 80}}
+		expectNftablesBaseRules()
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+			"tcp", "dport", reservedPorts, "counter", "return").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+			"ip", "saddr", "127.0.0.6", "counter", "snat", "to", "10.0.2.1").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+			"tcp", "dport", "80", "ip", "saddr", "127.0.0.1", "counter", "snat", "to", "10.0.2.1").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+			"tcp", "dport", "80", "counter", "dnat", "to", "10.0.2.2").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "output",
+			"ip", "daddr", "127.0.0.1", "tcp", "dport", "80", "counter", "dnat", "to", "10.0.2.2").Return(nil)
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "output",
+			"ip", "saddr", "127.0.0.6", "tcp", "dport", "80", "counter", "dnat", "to", "10.0.2.2").Return(nil)
+
+		Expect(masq.createNatRulesUsingNftables(proto)).To(Succeed())
+	})
+
+	It("should keep the proxy ports in the pod and pass the proxied traffic to the guest using iptables", func() {
+		mockNetwork.EXPECT().IptablesNewChain(proto, "nat", gomock.Any()).Return(nil).Times(2)
+		mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "POSTROUTING", "-s", "10.0.2.2", "-j", "MASQUERADE").Return(nil)
+		mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "PREROUTING", "-i", "eth0", "-j", "KUBEVIRT_PREINBOUND").Return(nil)
+		mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "POSTROUTING", "-o", "k6t-eth0", "-j", "KUBEVIRT_POSTINBOUND").Return(nil)
+		gomock.InOrder(
+			mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+				"-p", "tcp", "-m", "multiport", "--dports", "15000,15001,15004,15006,15008,15020,15021,15090", "-j", "RETURN").Return(nil),
+			mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+				"-j", "DNAT", "--to-destination", "10.0.2.2").Return(nil),
+		)
+		mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "KUBEVIRT_POSTINBOUND",
+			"--source", "127.0.0.6", "-j", "SNAT", "--to-source", "10.0.2.1").Return(nil)
+		mockNetwork.EXPECT().IptablesAppendRule(proto, "nat", "OUTPUT",
+			"--source", "127.0.0.6", "-j", "DNAT", "--to-destination", "10.0.2.2").Return(nil)
+
+		Expect(masq.createNatRulesUsingIptables(proto)).To(Succeed())
+	})
+
+	It("should not touch the rules without an injected proxy", func() {
+		masq.vmi.Annotations = nil
+		expectNftablesBaseRules()
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+			"counter", "dnat", "to", "10.0.2.2").Return(nil)
+
+		Expect(masq.createNatRulesUsingNftables(proto)).To(Succeed())
+	})
+
+	It("should not touch the rules if disabled in the cluster config", func() {
+		IstioAwareMasquerade = func() bool { return false }
+		expectNftablesBaseRules()
+		mockNetwork.EXPECT().NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
+			"counter", "dnat", "to", "10.0.2.2").Return(nil)
+
+		Expect(masq.createNatRulesUsingNftables(proto)).To(Succeed())
+	})
+})
@@ -35,6 +35,7 @@ import (
 	"github.com/coreos/go-iptables/iptables"
 
 	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -100,18 +101,62 @@ func setPodInterfaceCache(iface *v1.Interface, podInterfaceName string, uid stri
 		return nil
 	}
 
+	podIface := PodCacheInterface{Iface: iface}
 	for _, addr := range addrList {
 		if addr.IP.IsGlobalUnicast() {
-			err = writeToCachedFile(PodCacheInterface{Iface: iface, PodIP: addr.IP.String()},
-				util.VMIInterfacepath, uid, iface.Name)
-			if err != nil {
-				log.Log.Reason(err).Errorf("failed to write pod Interface to cache, %s", err.Error())
-				return err
+			if podIface.PodIP == "" {
+				podIface.PodIP = addr.IP.String()
 			}
-			return nil
+			podIface.PodIPNets = append(podIface.PodIPNets, addr.IPNet.String())
 		}
 	}
+	if podIface.PodIP == "" {
+		return nil
+	}
 
+	err = writeToCachedFile(podIface, util.VMIInterfacepath, uid, iface.Name)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to write pod Interface to cache, %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// verifyIPClaim makes sure that the pod interface got the IP addresses claimed by the interface,
+// the VMI must not come up with other addresses than before the migration or restart
+func verifyIPClaim(vmi *v1.VirtualMachineInstance, iface *v1.Interface, podInterfaceName string) error {
+	if !iface.PersistentIPs {
+		return nil
+	}
+	var claimed []string
+	for _, claim := range vmi.Status.IPClaims {
+		if claim.Name == iface.Name {
+			claimed = claim.IPs
+		}
+	}
+	if len(claimed) == 0 {
+		return nil
+	}
+
+	link, err := Handler.LinkByName(podInterfaceName)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to get a link for interface: %s", podInterfaceName)
+		return err
+	}
+	addrList, err := Handler.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to get a address for interface: %s", podInterfaceName)
+		return err
+	}
+	assigned := map[string]bool{}
+	for _, addr := range addrList {
+		assigned[addr.IPNet.String()] = true
+	}
+	for _, ip := range claimed {
+		if !assigned[ip] {
+			return fmt.Errorf("the pod interface %s did not get the address %s claimed by interface %s, the IPAM of the network has to honor requested addresses", podInterfaceName, ip, iface.Name)
+		}
+	}
 	return nil
 }
 
@@ -134,14 +179,19 @@ func (l *PodInterface) PlugPhase1(vmi *v1.VirtualMachineInstance, iface *v1.Inte
 		return err
 	}
 
-	// ignore the driver.loadCachedInterface for slirp and set the Pod interface cache
-	if !isExist || iface.Slirp != nil {
+	// ignore the driver.loadCachedInterface for slirp and passt and set the Pod interface cache
+	if !isExist || iface.Slirp != nil || iface.Passt != nil {
 		err := setPodInterfaceCache(iface, podInterfaceName, string(vmi.ObjectMeta.UID))
 		if err != nil {
 			return err
 		}
 	}
 	if !isExist {
+		if err := verifyIPClaim(vmi, iface, podInterfaceName); err != nil {
+			log.Log.Reason(err).Error("failed to verify the claimed IP addresses")
+			return createCriticalNetworkError(err)
+		}
+
 		err = driver.discoverPodNetworkInterface()
 		if err != nil {
 			return err
@@ -281,6 +331,16 @@ func getPhase2Binding(vmi *v1.VirtualMachineInstance, iface *v1.Interface, netwo
 	if iface.Slirp != nil {
 		return &SlirpPodInterface{vmi: vmi, iface: iface, domain: domain}, nil
 	}
+	if iface.Passt != nil {
+		return &PasstPodInterface{}, nil
+	}
+	if iface.Macvtap != nil {
+		return &MacvtapPodInterface{iface: iface,
+			virtIface:        &api.Interface{},
+			vmi:              vmi,
+			domain:           domain,
+			podInterfaceName: podInterfaceName}, nil
+	}
 	return nil, fmt.Errorf("Not implemented")
 }
 
@@ -403,6 +463,11 @@ func (b *BridgePodInterface) preparePodNetworkInterfaces() error {
 		return err
 	}
 
+	if err := b.createAllowedAddressesRules(); err != nil {
+		log.Log.Reason(err).Errorf("failed to restrict the addresses the guest sends from on interface: %s", b.podInterfaceName)
+		return err
+	}
+
 	b.virtIface.MTU = &api.MTU{Size: strconv.Itoa(b.podNicLink.Attrs().MTU)}
 	b.virtIface.MAC = &api.MAC{MAC: b.vif.MAC.String()}
 
@@ -667,6 +732,11 @@ func (p *MasqueradePodInterface) preparePodNetworkInterfaces() error {
 			log.Log.Reason(err).Errorf("failed to create ipv4 nat rules for vm error: %v", err)
 			return err
 		}
+		err = p.createConnectionLimitRules(iptables.ProtocolIPv4)
+		if err != nil {
+			log.Log.Reason(err).Errorf("failed to create ipv4 connection limit rules for vm error: %v", err)
+			return err
+		}
 	} else {
 		return fmt.Errorf("Couldn't configure ipv4 nat rules")
 	}
@@ -683,6 +753,11 @@ func (p *MasqueradePodInterface) preparePodNetworkInterfaces() error {
 				log.Log.Reason(err).Errorf("failed to create ipv6 nat rules for vm error: %v", err)
 				return err
 			}
+			err = p.createConnectionLimitRules(iptables.ProtocolIPv6)
+			if err != nil {
+				log.Log.Reason(err).Errorf("failed to create ipv6 connection limit rules for vm error: %v", err)
+				return err
+			}
 		} else {
 			return fmt.Errorf("Couldn't configure ipv6 nat rules")
 		}
@@ -829,7 +904,22 @@ func (p *MasqueradePodInterface) createNatRulesUsingIptables(protocol iptables.P
 		return err
 	}
 
+	istioProxyInjected := isIstioProxyInjected(p.vmi)
+	if istioProxyInjected {
+		err = p.createIstioNatRulesUsingIptables(protocol)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(p.iface.Ports) == 0 {
+		if istioProxyInjected {
+			err = p.createEnvoyDnatRuleUsingIptables(protocol, nil)
+			if err != nil {
+				return err
+			}
+		}
+
 		err = Handler.IptablesAppendRule(protocol, "nat", "KUBEVIRT_PREINBOUND",
 			"-j",
 			"DNAT",
@@ -838,7 +928,7 @@ func (p *MasqueradePodInterface) createNatRulesUsingIptables(protocol iptables.P
 		return err
 	}
 
-	for _, port := range p.iface.Ports {
+	for _, port := range p.forwardedPorts() {
 		if port.Protocol == "" {
 			port.Protocol = "tcp"
 		}
@@ -880,11 +970,63 @@ func (p *MasqueradePodInterface) createNatRulesUsingIptables(protocol iptables.P
 		if err != nil {
 			return err
 		}
+
+		if istioProxyInjected {
+			err = p.createEnvoyDnatRuleUsingIptables(protocol, &port)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// forwardedPorts returns the ports declared on the interface together with the
+// ports of TCP and HTTP probes, so that kubelet probes reach the guest and not
+// the launcher pod
+func (p *MasqueradePodInterface) forwardedPorts() []v1.Port {
+	ports := append([]v1.Port{}, p.iface.Ports...)
+	for _, probe := range []*v1.Probe{p.vmi.Spec.ReadinessProbe, p.vmi.Spec.LivenessProbe} {
+		port, ok := getProbePort(probe)
+		if !ok || isPortDeclared(ports, port) {
+			continue
+		}
+		ports = append(ports, v1.Port{Protocol: "TCP", Port: port})
+	}
+	return ports
+}
+
+// getProbePort returns the numeric port a TCP or HTTP probe targets on the pod IP
+func getProbePort(probe *v1.Probe) (int32, bool) {
+	if probe == nil {
+		return 0, false
+	}
+	var port intstr.IntOrString
+	switch {
+	case probe.HTTPGet != nil && probe.HTTPGet.Host == "":
+		port = probe.HTTPGet.Port
+	case probe.TCPSocket != nil && probe.TCPSocket.Host == "":
+		port = probe.TCPSocket.Port
+	default:
+		return 0, false
+	}
+	// named ports can only refer to ports declared on an interface
+	if port.Type != intstr.Int {
+		return 0, false
+	}
+	return port.IntVal, true
+}
+
+func isPortDeclared(ports []v1.Port, port int32) bool {
+	for _, p := range ports {
+		if p.Port == port && (p.Protocol == "" || strings.EqualFold(p.Protocol, "tcp")) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *MasqueradePodInterface) getGatewayByProtocol(proto iptables.Protocol) string {
 	if proto == iptables.ProtocolIPv4 {
 		return p.gatewayAddr.IP.String()
@@ -935,14 +1077,29 @@ func (p *MasqueradePodInterface) createNatRulesUsingNftables(proto iptables.Prot
 		return err
 	}
 
+	istioProxyInjected := isIstioProxyInjected(p.vmi)
+	if istioProxyInjected {
+		err = p.createIstioNatRulesUsingNftables(proto)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(p.iface.Ports) == 0 {
+		if istioProxyInjected {
+			err = p.createEnvoyDnatRuleUsingNftables(proto, nil)
+			if err != nil {
+				return err
+			}
+		}
+
 		err = Handler.NftablesAppendRule(proto, "nat", "KUBEVIRT_PREINBOUND",
 			"counter", "dnat", "to", p.getVifIpByProtocol(proto))
 
 		return err
 	}
 
-	for _, port := range p.iface.Ports {
+	for _, port := range p.forwardedPorts() {
 		if port.Protocol == "" {
 			port.Protocol = "tcp"
 		}
@@ -975,6 +1132,13 @@ func (p *MasqueradePodInterface) createNatRulesUsingNftables(proto iptables.Prot
 		if err != nil {
 			return err
 		}
+
+		if istioProxyInjected {
+			err = p.createEnvoyDnatRuleUsingNftables(proto, &port)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -1042,3 +1206,115 @@ func (b *SlirpPodInterface) setCachedVIF(pid, name string) error {
 func (s *SlirpPodInterface) setCachedInterface(pid, name string) error {
 	return nil
 }
+
+// MacvtapPodInterface hands the macvtap device of the pod over to the guest,
+// the device is created on a host NIC by the macvtap device plugin and moved
+// into the pod by the CNI plugin, so there is nothing to rewire in the pod.
+type MacvtapPodInterface struct {
+	vmi              *v1.VirtualMachineInstance
+	iface            *v1.Interface
+	virtIface        *api.Interface
+	podNicLink       netlink.Link
+	domain           *api.Domain
+	podInterfaceName string
+}
+
+func (m *MacvtapPodInterface) discoverPodNetworkInterface() error {
+	link, err := Handler.LinkByName(m.podInterfaceName)
+	if err != nil {
+		log.Log.Reason(err).Errorf("failed to get a link for interface: %s", m.podInterfaceName)
+		return err
+	}
+	m.podNicLink = link
+	return nil
+}
+
+func (m *MacvtapPodInterface) preparePodNetworkInterfaces() error {
+	// the guest has to use the MAC address of the macvtap device, frames to
+	// other addresses are not delivered to it
+	m.virtIface.MAC = &api.MAC{MAC: m.podNicLink.Attrs().HardwareAddr.String()}
+	m.virtIface.MTU = &api.MTU{Size: strconv.Itoa(m.podNicLink.Attrs().MTU)}
+	return nil
+}
+
+func (m *MacvtapPodInterface) startDHCP(vmi *v1.VirtualMachineInstance) error {
+	// the guest is connected to the L2 network of the host NIC and gets its
+	// addresses from there
+	return nil
+}
+
+func (m *MacvtapPodInterface) decorateConfig() error {
+	ifaces := m.domain.Spec.Devices.Interfaces
+	for i, iface := range ifaces {
+		if iface.Alias.Name == m.iface.Name {
+			ifaces[i].MTU = m.virtIface.MTU
+			ifaces[i].MAC = m.virtIface.MAC
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MacvtapPodInterface) loadCachedInterface(pid, name string) (bool, error) {
+	var ifaceConfig api.Interface
+
+	isExist, err := readFromCachedFile(pid, name, interfaceCacheFile, &ifaceConfig)
+	if err != nil {
+		return false, err
+	}
+
+	if isExist {
+		m.virtIface = &ifaceConfig
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (m *MacvtapPodInterface) setCachedInterface(pid, name string) error {
+	return writeToCachedFile(m.virtIface, interfaceCacheFile, pid, name)
+}
+
+func (m *MacvtapPodInterface) loadCachedVIF(pid, name string) (bool, error) {
+	return true, nil
+}
+
+func (m *MacvtapPodInterface) setCachedVIF(pid, name string) error {
+	return nil
+}
+
+// PasstPodInterface leaves the pod interface untouched, passt is spawned by
+// libvirt and connects the guest to the pod network from user space
+type PasstPodInterface struct{}
+
+func (p *PasstPodInterface) discoverPodNetworkInterface() error {
+	return nil
+}
+
+func (p *PasstPodInterface) preparePodNetworkInterfaces() error {
+	return nil
+}
+
+func (p *PasstPodInterface) startDHCP(vmi *v1.VirtualMachineInstance) error {
+	return nil
+}
+
+func (p *PasstPodInterface) decorateConfig() error {
+	return nil
+}
+
+func (p *PasstPodInterface) loadCachedInterface(pid, name string) (bool, error) {
+	return true, nil
+}
+
+func (p *PasstPodInterface) setCachedInterface(pid, name string) error {
+	return nil
+}
+
+func (p *PasstPodInterface) loadCachedVIF(pid, name string) (bool, error) {
+	return true, nil
+}
+
+func (p *PasstPodInterface) setCachedVIF(pid, name string) error {
+	return nil
+}
@@ -36,6 +36,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/vishvananda/netlink"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/log"
@@ -433,6 +435,34 @@ var _ = Describe("Pod Network", func() {
 				api.NewDefaulter(runtime.GOARCH).SetObjectDefaults_Domain(domain)
 				TestPodInterfaceIPBinding(vm, domain)
 			})
+			It("should forward the ports of TCP and HTTP probes to the guest", func() {
+				vm := newVMIMasqueradeInterface("testnamespace", "testVmName")
+				iface := &vm.Spec.Domain.Devices.Interfaces[0]
+				iface.Ports = []v1.Port{{Name: "http", Port: 80, Protocol: "TCP"}}
+				vm.Spec.ReadinessProbe = &v1.Probe{Handler: v1.Handler{
+					TCPSocket: &k8sv1.TCPSocketAction{Port: intstr.FromInt(8080)},
+				}}
+				vm.Spec.LivenessProbe = &v1.Probe{Handler: v1.Handler{
+					HTTPGet: &k8sv1.HTTPGetAction{Port: intstr.FromString("http")},
+				}}
+
+				driver := &MasqueradePodInterface{vmi: vm, iface: iface}
+				Expect(driver.forwardedPorts()).To(Equal([]v1.Port{
+					{Name: "http", Port: 80, Protocol: "TCP"},
+					{Port: 8080, Protocol: "TCP"},
+				}))
+			})
+			It("should not forward the ports of probes targeting another host", func() {
+				vm := newVMIMasqueradeInterface("testnamespace", "testVmName")
+				iface := &vm.Spec.Domain.Devices.Interfaces[0]
+				iface.Ports = []v1.Port{{Name: "http", Port: 80, Protocol: "TCP"}}
+				vm.Spec.ReadinessProbe = &v1.Probe{Handler: v1.Handler{
+					HTTPGet: &k8sv1.HTTPGetAction{Host: "example.com", Port: intstr.FromInt(8080)},
+				}}
+
+				driver := &MasqueradePodInterface{vmi: vm, iface: iface}
+				Expect(driver.forwardedPorts()).To(Equal(iface.Ports))
+			})
 			It("should define a new VIF bind to a bridge and create a default nat rule using nftables", func() {
 				// forward all the traffic
 				for _, proto := range ipProtocols() {
@@ -541,6 +571,50 @@ var _ = Describe("Pod Network", func() {
 				Expect(domain.Spec.QEMUCmd.QEMUArg[1]).To(Equal(api.Arg{Value: "e1000,netdev=default,id=default"}))
 			})
 		})
+		Context("Passt Plug", func() {
+			It("should leave the interface created by the converter untouched", func() {
+				vmi := newVMI("testnamespace", "testVmName")
+				vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{Name: "default", InterfaceBindingMethod: v1.InterfaceBindingMethod{Passt: &v1.InterfacePasst{}}}}
+				domain := &api.Domain{}
+				domain.Spec.Devices.Interfaces = []api.Interface{{
+					Type:    "user",
+					Backend: &api.InterfaceBackend{Type: "passt"},
+					Alias:   &api.Alias{Name: "default"},
+				}}
+				expectedDomain := domain.DeepCopy()
+
+				driver, err := getPhase2Binding(vmi, &vmi.Spec.Domain.Devices.Interfaces[0], &vmi.Spec.Networks[0], domain, podInterface)
+				Expect(err).ToNot(HaveOccurred())
+				_, ok := driver.(*PasstPodInterface)
+				Expect(ok).To(BeTrue())
+				TestRunPlug(driver)
+				Expect(domain).To(Equal(expectedDomain))
+			})
+		})
+		Context("Macvtap Plug", func() {
+			It("should hand the MAC address and the MTU of the macvtap device to the guest", func() {
+				vmi := newVMI("testnamespace", "testVmName")
+				vmi.Spec.Networks = []v1.Network{{Name: "macvtap", NetworkSource: v1.NetworkSource{Multus: &v1.MultusNetwork{NetworkName: "macvtap"}}}}
+				vmi.Spec.Domain.Devices.Interfaces = []v1.Interface{{Name: "macvtap", InterfaceBindingMethod: v1.InterfaceBindingMethod{Macvtap: &v1.InterfaceMacvtap{}}}}
+				domain := &api.Domain{}
+				domain.Spec.Devices.Interfaces = []api.Interface{{
+					Type:   "ethernet",
+					Target: &api.InterfaceTarget{Device: "net1", Managed: "no"},
+					Alias:  &api.Alias{Name: "macvtap"},
+				}}
+				macvtap := &netlink.Macvtap{Macvlan: netlink.Macvlan{LinkAttrs: netlink.LinkAttrs{Name: "net1", MTU: 9000, HardwareAddr: fakeMac}}}
+				mockNetwork.EXPECT().LinkByName("net1").Return(macvtap, nil)
+
+				driver, err := getPhase2Binding(vmi, &vmi.Spec.Domain.Devices.Interfaces[0], &vmi.Spec.Networks[0], domain, "net1")
+				Expect(err).ToNot(HaveOccurred())
+				_, ok := driver.(*MacvtapPodInterface)
+				Expect(ok).To(BeTrue())
+				TestRunPlug(driver)
+				Expect(domain.Spec.Devices.Interfaces[0].MAC).To(Equal(&api.MAC{MAC: fakeMac.String()}))
+				Expect(domain.Spec.Devices.Interfaces[0].MTU).To(Equal(&api.MTU{Size: "9000"}))
+				Expect(domain.Spec.Devices.Interfaces[0].Target.Device).To(Equal("net1"))
+			})
+		})
 	})
 
 	Context("Masquerade startDHCP", func() {
@@ -734,8 +808,8 @@ var _ = Describe("Pod Network", func() {
 
 	It("should write interface to cache file", func() {
 		uid := "test-1234"
-		address1 := &net.IPNet{IP: net.IPv4(1, 2, 3, 4)}
-		address2 := &net.IPNet{IP: net.IPv4(169, 254, 0, 0)}
+		address1 := &net.IPNet{IP: net.IPv4(1, 2, 3, 4), Mask: net.CIDRMask(24, 32)}
+		address2 := &net.IPNet{IP: net.IPv4(169, 254, 0, 0), Mask: net.CIDRMask(16, 32)}
 		fakeAddr1 := netlink.Addr{IPNet: address1}
 		fakeAddr2 := netlink.Addr{IPNet: address2}
 		addrList := []netlink.Addr{fakeAddr1, fakeAddr2}
@@ -755,6 +829,38 @@ var _ = Describe("Pod Network", func() {
 		err = json.Unmarshal(data, &podData)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(podData.PodIP).To(Equal("1.2.3.4"))
+		Expect(podData.PodIPNets).To(Equal([]string{"1.2.3.4/24"}))
+	})
+
+	Context("with persistent IPs", func() {
+		var vmi *v1.VirtualMachineInstance
+
+		BeforeEach(func() {
+			vmi = newVMIBridgeInterface("testnamespace", "testVmName")
+			vmi.Spec.Domain.Devices.Interfaces[0].PersistentIPs = true
+			vmi.Status.IPClaims = []v1.InterfaceIPClaim{{Name: "default", IPs: []string{"1.2.3.4/24"}}}
+		})
+
+		It("should accept the claimed address on the pod interface", func() {
+			address := netlink.Addr{IPNet: &net.IPNet{IP: net.IPv4(1, 2, 3, 4), Mask: net.CIDRMask(24, 32)}}
+			mockNetwork.EXPECT().LinkByName(podInterface).Return(dummy, nil)
+			mockNetwork.EXPECT().AddrList(dummy, netlink.FAMILY_ALL).Return([]netlink.Addr{address}, nil)
+
+			Expect(verifyIPClaim(vmi, &vmi.Spec.Domain.Devices.Interfaces[0], podInterface)).To(Succeed())
+		})
+
+		It("should reject another address on the pod interface", func() {
+			address := netlink.Addr{IPNet: &net.IPNet{IP: net.IPv4(1, 2, 3, 5), Mask: net.CIDRMask(24, 32)}}
+			mockNetwork.EXPECT().LinkByName(podInterface).Return(dummy, nil)
+			mockNetwork.EXPECT().AddrList(dummy, netlink.FAMILY_ALL).Return([]netlink.Addr{address}, nil)
+
+			Expect(verifyIPClaim(vmi, &vmi.Spec.Domain.Devices.Interfaces[0], podInterface)).ToNot(Succeed())
+		})
+
+		It("should not look at the pod interface without a claim", func() {
+			vmi.Status.IPClaims = nil
+			Expect(verifyIPClaim(vmi, &vmi.Spec.Domain.Devices.Interfaces[0], podInterface)).To(Succeed())
+		})
 	})
 })
 
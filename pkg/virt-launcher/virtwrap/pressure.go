@@ -0,0 +1,50 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"kubevirt.io/kubevirt/pkg/util/cgroup"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+// getCPUPressureStats reports how long the processes of the launcher pod
+// stalled waiting for a CPU. Nothing is reported where the cgroup does not
+// track the pressure, e.g. on cgroup v1.
+func getCPUPressureStats() (*stats.DomainStatsPressure, error) {
+	manager, err := cgroup.NewManager(cgroup.Root)
+	if err != nil {
+		return nil, err
+	}
+	pressure, err := manager.GetCPUPressure()
+	if cgroup.IsUnsupported(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return convertPressure(pressure), nil
+}
+
+func convertPressure(pressure *cgroup.Pressure) *stats.DomainStatsPressure {
+	return &stats.DomainStatsPressure{
+		Some:    float64(pressure.Some) / 1e6,
+		FullSet: pressure.FullSet,
+		Full:    float64(pressure.Full) / 1e6,
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/procfs"
+
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var procMountPoint = procfs.DefaultMountPoint
+
+// ioThreadCommPrefix is the command name qemu gives to the threads of the
+// iothread objects libvirt creates, followed by the IOThread ID
+const ioThreadCommPrefix = "IO iothread"
+
+// isQemu tells whether a process of the launcher pod is qemu by its command
+// name, the kernel truncates the name of qemu-system-x86_64
+func isQemu(comm string) bool {
+	return comm == "qemu-kvm" || strings.HasPrefix(comm, "qemu-system-")
+}
+
+// processType maps the command name of a process in the launcher pod to the
+// part of the VMI it belongs to
+func processType(comm string) string {
+	switch {
+	case isQemu(comm):
+		return stats.ProcessTypeQemu
+	case comm == "libvirtd" || comm == "virtlogd":
+		return stats.ProcessTypeLibvirt
+	case comm == "virt-launcher":
+		return stats.ProcessTypeLauncher
+	case comm == "virtiofsd":
+		return stats.ProcessTypeVirtiofsd
+	case comm == "swtpm":
+		return stats.ProcessTypeSwtpm
+	}
+	return stats.ProcessTypeOther
+}
+
+// processKey identifies a process across samples, the start time tells
+// apart a process which got the PID of an exited one
+type processKey struct {
+	pid       int
+	starttime uint64
+}
+
+type processSample struct {
+	processType    string
+	cpuTime        float64
+	residentMemory uint64
+	readBytes      uint64
+	writeBytes     uint64
+}
+
+// processAccounting sums up the resources consumed by the processes of the
+// launcher pod per process type. The counters a process reached when it was
+// last seen are carried forward once it exited, so that the counters of a
+// type don't go backwards. What a process consumed after it was last seen,
+// and processes which lived shorter than the time between two samples, are
+// not accounted.
+type processAccounting struct {
+	lock sync.Mutex
+	// processes are the processes seen in the previous sample
+	processes map[processKey]processSample
+	// exited are the counters of the processes which exited, per type
+	exited map[string]stats.DomainStatsProcesses
+}
+
+func newProcessAccounting() *processAccounting {
+	return &processAccounting{
+		processes: map[processKey]processSample{},
+		exited:    map[string]stats.DomainStatsProcesses{},
+	}
+}
+
+// observe samples the processes of the launcher pod and returns the resources
+// consumed per process type. Processes which exit while they are being read
+// are skipped.
+func (a *processAccounting) observe() ([]stats.DomainStatsProcesses, error) {
+	fs, err := procfs.NewFS(procMountPoint)
+	if err != nil {
+		return nil, err
+	}
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	processes := make(map[processKey]processSample, len(procs))
+	for _, proc := range procs {
+		procStat, err := proc.Stat()
+		if err != nil {
+			log.Log.V(4).Reason(err).Infof("failed to read the stat of process %d", proc.PID)
+			continue
+		}
+
+		key := processKey{pid: proc.PID, starttime: procStat.Starttime}
+		sample := processSample{
+			processType:    processType(procStat.Comm),
+			cpuTime:        procStat.CPUTime(),
+			residentMemory: uint64(procStat.ResidentMemory()),
+		}
+		if procIO, err := proc.IO(); err == nil {
+			sample.readBytes = procIO.ReadBytes
+			sample.writeBytes = procIO.WriteBytes
+		} else {
+			// keep the I/O of the previous sample, so that the counters don't go backwards
+			log.Log.V(4).Reason(err).Infof("failed to read the I/O of process %d", proc.PID)
+			sample.readBytes = a.processes[key].readBytes
+			sample.writeBytes = a.processes[key].writeBytes
+		}
+		processes[key] = sample
+	}
+
+	for key, sample := range a.processes {
+		if _, running := processes[key]; running {
+			continue
+		}
+		exited := a.exited[sample.processType]
+		exited.CPUTime += sample.cpuTime
+		exited.ReadBytes += sample.readBytes
+		exited.WriteBytes += sample.writeBytes
+		a.exited[sample.processType] = exited
+	}
+	a.processes = processes
+
+	byType := map[string]*stats.DomainStatsProcesses{}
+	for t, exited := range a.exited {
+		processStats := exited
+		processStats.Type = t
+		byType[t] = &processStats
+	}
+	for _, sample := range processes {
+		processStats, exists := byType[sample.processType]
+		if !exists {
+			processStats = &stats.DomainStatsProcesses{Type: sample.processType}
+			byType[sample.processType] = processStats
+		}
+		processStats.CPUTime += sample.cpuTime
+		processStats.ResidentMemory += sample.residentMemory
+		processStats.ReadBytes += sample.readBytes
+		processStats.WriteBytes += sample.writeBytes
+	}
+
+	processStats := make([]stats.DomainStatsProcesses, 0, len(byType))
+	for _, s := range byType {
+		processStats = append(processStats, *s)
+	}
+	sort.Slice(processStats, func(i, j int) bool {
+		return processStats[i].Type < processStats[j].Type
+	})
+	return processStats, nil
+}
+
+// getIOThreadStats reports the CPU time consumed by the IOThreads of the qemu
+// processes of the launcher pod.
+func getIOThreadStats() ([]stats.DomainStatsIOThread, error) {
+	fs, err := procfs.NewFS(procMountPoint)
+	if err != nil {
+		return nil, err
+	}
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	var ioThreadStats []stats.DomainStatsIOThread
+	for _, proc := range procs {
+		procStat, err := proc.Stat()
+		if err != nil || !isQemu(procStat.Comm) {
+			continue
+		}
+
+		taskFS, err := procfs.NewFS(filepath.Join(procMountPoint, fmt.Sprintf("%d", proc.PID), "task"))
+		if err != nil {
+			log.Log.V(4).Reason(err).Infof("failed to read the threads of process %d", proc.PID)
+			continue
+		}
+		threads, err := taskFS.AllProcs()
+		if err != nil {
+			log.Log.V(4).Reason(err).Infof("failed to read the threads of process %d", proc.PID)
+			continue
+		}
+		for _, thread := range threads {
+			threadStat, err := thread.Stat()
+			if err != nil || !strings.HasPrefix(threadStat.Comm, ioThreadCommPrefix) {
+				continue
+			}
+			id, err := strconv.ParseUint(strings.TrimPrefix(threadStat.Comm, ioThreadCommPrefix), 10, 32)
+			if err != nil {
+				continue
+			}
+			ioThreadStats = append(ioThreadStats, stats.DomainStatsIOThread{
+				ID:      uint(id),
+				CPUTime: threadStat.CPUTime(),
+			})
+		}
+	}
+
+	sort.Slice(ioThreadStats, func(i, j int) bool {
+		return ioThreadStats[i].ID < ioThreadStats[j].ID
+	})
+	return ioThreadStats, nil
+}
@@ -0,0 +1,208 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtwrap
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/procfs"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/stats"
+)
+
+var _ = Describe("Process stats", func() {
+	var procDir string
+
+	addStartedProcess := func(pid int, starttime int, comm string, utime, stime, rssPages int) {
+		dir := filepath.Join(procDir, fmt.Sprintf("%d", pid))
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		stat := fmt.Sprintf("%d (%s) S 1 1 1 0 -1 4194560 100 0 0 0 %d %d 0 0 20 0 1 0 %d 1000000 %d\n", pid, comm, utime, stime, starttime, rssPages)
+		Expect(ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644)).To(Succeed())
+	}
+
+	addProcess := func(pid int, comm string, utime, stime, rssPages int) {
+		addStartedProcess(pid, 100, comm, utime, stime, rssPages)
+	}
+
+	addIO := func(pid int, readBytes, writeBytes int) {
+		io := fmt.Sprintf("rchar: 0\nwchar: 0\nsyscr: 0\nsyscw: 0\nread_bytes: %d\nwrite_bytes: %d\ncancelled_write_bytes: 0\n", readBytes, writeBytes)
+		Expect(ioutil.WriteFile(filepath.Join(procDir, fmt.Sprintf("%d", pid), "io"), []byte(io), 0644)).To(Succeed())
+	}
+
+	removeProcess := func(pid int) {
+		Expect(os.RemoveAll(filepath.Join(procDir, fmt.Sprintf("%d", pid)))).To(Succeed())
+	}
+
+	processStatsOf := func(processStats []stats.DomainStatsProcesses, processType string) stats.DomainStatsProcesses {
+		for _, s := range processStats {
+			if s.Type == processType {
+				return s
+			}
+		}
+		Fail("no stats for process type " + processType)
+		return stats.DomainStatsProcesses{}
+	}
+
+	BeforeEach(func() {
+		var err error
+		procDir, err = ioutil.TempDir("", "proc")
+		Expect(err).ToNot(HaveOccurred())
+		procMountPoint = procDir
+	})
+
+	AfterEach(func() {
+		procMountPoint = procfs.DefaultMountPoint
+		os.RemoveAll(procDir)
+	})
+
+	It("should sum up the processes per type", func() {
+		addProcess(1, "virt-launcher", 100, 0, 10)
+		addProcess(20, "libvirtd", 200, 100, 100)
+		addProcess(21, "virtlogd", 10, 0, 10)
+		addProcess(30, "qemu-kvm", 1000, 500, 1000)
+		addIO(30, 2048, 4096)
+		addProcess(40, "sh", 1, 0, 1)
+
+		processStats, err := newProcessAccounting().observe()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processStats).To(HaveLen(4))
+		Expect(processStats[0].Type).To(Equal(stats.ProcessTypeLibvirt))
+		Expect(processStats[0].CPUTime).To(Equal(3.1))
+		Expect(processStats[0].ResidentMemory).To(Equal(uint64(110 * os.Getpagesize())))
+		Expect(processStatsOf(processStats, stats.ProcessTypeQemu)).To(Equal(stats.DomainStatsProcesses{
+			Type:           stats.ProcessTypeQemu,
+			CPUTime:        15,
+			ResidentMemory: uint64(1000 * os.Getpagesize()),
+			ReadBytes:      2048,
+			WriteBytes:     4096,
+		}))
+		Expect(processStatsOf(processStats, stats.ProcessTypeOther).CPUTime).To(Equal(0.01))
+	})
+
+	It("should carry forward the counters of exited processes", func() {
+		accounting := newProcessAccounting()
+		addProcess(30, "qemu-kvm", 1000, 0, 1000)
+		addProcess(50, "virtiofsd", 300, 0, 100)
+		addIO(50, 1000, 2000)
+		addProcess(51, "virtiofsd", 100, 0, 100)
+		_, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+
+		removeProcess(50)
+		addProcess(51, "virtiofsd", 200, 0, 100)
+		processStats, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processStatsOf(processStats, stats.ProcessTypeVirtiofsd)).To(Equal(stats.DomainStatsProcesses{
+			Type:           stats.ProcessTypeVirtiofsd,
+			CPUTime:        5,
+			ResidentMemory: uint64(100 * os.Getpagesize()),
+			ReadBytes:      1000,
+			WriteBytes:     2000,
+		}))
+
+		removeProcess(51)
+		processStats, err = accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processStatsOf(processStats, stats.ProcessTypeVirtiofsd)).To(Equal(stats.DomainStatsProcesses{
+			Type:       stats.ProcessTypeVirtiofsd,
+			CPUTime:    5,
+			ReadBytes:  1000,
+			WriteBytes: 2000,
+		}))
+		Expect(processStatsOf(processStats, stats.ProcessTypeQemu).CPUTime).To(Equal(float64(10)))
+	})
+
+	It("should tell apart a process which reuses the PID of an exited one", func() {
+		accounting := newProcessAccounting()
+		addStartedProcess(60, 100, "swtpm", 500, 0, 10)
+		_, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+
+		addStartedProcess(60, 200, "swtpm", 100, 0, 10)
+		processStats, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processStatsOf(processStats, stats.ProcessTypeSwtpm).CPUTime).To(Equal(float64(6)))
+	})
+
+	It("should keep the I/O of a process which can not be read anymore", func() {
+		accounting := newProcessAccounting()
+		addProcess(30, "qemu-kvm", 1000, 0, 1000)
+		addIO(30, 2048, 4096)
+		_, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.Remove(filepath.Join(procDir, "30", "io"))).To(Succeed())
+		processStats, err := accounting.observe()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(processStatsOf(processStats, stats.ProcessTypeQemu).ReadBytes).To(Equal(uint64(2048)))
+		Expect(processStatsOf(processStats, stats.ProcessTypeQemu).WriteBytes).To(Equal(uint64(4096)))
+	})
+
+	It("should report the cpu time of the qemu iothreads", func() {
+		addProcess(1, "virt-launcher", 100, 0, 10)
+		addProcess(30, "qemu-kvm", 1000, 500, 1000)
+		addThread := func(pid, tid int, comm string, utime, stime int) {
+			dir := filepath.Join(procDir, fmt.Sprintf("%d", pid), "task", fmt.Sprintf("%d", tid))
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			stat := fmt.Sprintf("%d (%s) S 1 1 1 0 -1 4194560 100 0 0 0 %d %d 0 0 20 0 1 0 100 1000000 0\n", tid, comm, utime, stime)
+			Expect(ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644)).To(Succeed())
+		}
+		addThread(30, 30, "qemu-kvm", 100, 100)
+		addThread(30, 32, "IO iothread2", 50, 50)
+		addThread(30, 31, "IO iothread1", 100, 50)
+		addThread(30, 33, "CPU 0/KVM", 500, 0)
+
+		ioThreadStats, err := getIOThreadStats()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioThreadStats).To(Equal([]stats.DomainStatsIOThread{
+			{ID: 1, CPUTime: 1.5},
+			{ID: 2, CPUTime: 1},
+		}))
+	})
+
+	table.DescribeTable("should map the command of a process to its type", func(comm string, expectedType string) {
+		Expect(processType(comm)).To(Equal(expectedType))
+	},
+		table.Entry("for qemu-kvm", "qemu-kvm", stats.ProcessTypeQemu),
+		table.Entry("for qemu-system-x86_64 truncated by the kernel", "qemu-system-x86", stats.ProcessTypeQemu),
+		table.Entry("for libvirtd", "libvirtd", stats.ProcessTypeLibvirt),
+		table.Entry("for virtlogd", "virtlogd", stats.ProcessTypeLibvirt),
+		table.Entry("for virt-launcher", "virt-launcher", stats.ProcessTypeLauncher),
+		table.Entry("for virtiofsd", "virtiofsd", stats.ProcessTypeVirtiofsd),
+		table.Entry("for swtpm", "swtpm", stats.ProcessTypeSwtpm),
+		table.Entry("for anything else", "sh", stats.ProcessTypeOther),
+	)
+
+	table.DescribeTable("should recognize qemu by its command", func(comm string, qemu bool) {
+		Expect(isQemu(comm)).To(Equal(qemu))
+	},
+		table.Entry("for qemu-kvm", "qemu-kvm", true),
+		table.Entry("for qemu-system-x86_64 truncated by the kernel", "qemu-system-x86", true),
+		table.Entry("for libvirtd", "libvirtd", false),
+		table.Entry("for virt-launcher", "virt-launcher", false),
+	)
+})
@@ -58,6 +58,22 @@ type DomainStats struct {
 	Net   []DomainStatsNet
 	Block []DomainStatsBlock
 	// omitted from libvirt-go: Perf
+	// new, see below
+	ConnLimit []DomainStatsConnLimit
+	// new, see below
+	Processes []DomainStatsProcesses
+	// new, see below
+	IOThreads []DomainStatsIOThread
+	// new, see below
+	Hugepages []DomainStatsHugepages
+	// new, see below
+	BlockThrottle []DomainStatsBlockThrottle
+	// new, see below
+	NetThrottle []DomainStatsNetThrottle
+	// new, see below
+	DirtyRate *DomainStatsDirtyRate
+	// new, see below
+	CPUPressure *DomainStatsPressure
 }
 
 type DomainStatsCPU struct {
@@ -99,6 +115,111 @@ type DomainStatsNet struct {
 	TxDrop     uint64
 }
 
+// DomainStatsConnLimit is not part of the libvirt stats; it reports the new
+// connections dropped by the connection limits of a VMI interface.
+type DomainStatsConnLimit struct {
+	// Name of the VMI interface
+	Name string
+	// Reason is the limit which caused the drops
+	Reason  string
+	Dropped uint64
+}
+
+// Directions of the traffic of an interface, seen from the guest
+const (
+	NetTypeRx = "rx"
+	NetTypeTx = "tx"
+)
+
+// DomainStatsNetThrottle is not part of the libvirt stats; it reports the
+// packets which exceeded the bandwidth limits of an interface. Received
+// packets are delayed, sent packets are dropped.
+type DomainStatsNetThrottle struct {
+	// Name of the tap device, like the name of the net stats
+	Name string
+	// Type is the direction of the traffic
+	Type      string
+	Throttled uint64
+}
+
+// Types of the processes in the launcher pod
+const (
+	ProcessTypeQemu      = "qemu"
+	ProcessTypeLibvirt   = "libvirt"
+	ProcessTypeLauncher  = "virt-launcher"
+	ProcessTypeVirtiofsd = "virtiofsd"
+	ProcessTypeSwtpm     = "swtpm"
+	ProcessTypeOther     = "other"
+)
+
+// DomainStatsProcesses is not part of the libvirt stats; it reports the
+// resources consumed by all processes of one type in the launcher pod,
+// including the ones which already exited.
+type DomainStatsProcesses struct {
+	Type string
+	// CPUTime is the user and system time in seconds
+	CPUTime float64
+	// ResidentMemory in bytes, of the running processes only
+	ResidentMemory uint64
+	// ReadBytes and WriteBytes are the storage I/O in bytes
+	ReadBytes  uint64
+	WriteBytes uint64
+}
+
+// DomainStatsIOThread is not part of the libvirt stats; it reports the
+// resources consumed by one IOThread of the qemu process.
+type DomainStatsIOThread struct {
+	ID uint
+	// CPUTime is the user and system time in seconds
+	CPUTime float64
+}
+
+// DomainStatsHugepages is not part of the libvirt stats; it reports the
+// hugepages of one size charged to the cgroup of the launcher pod.
+type DomainStatsHugepages struct {
+	// PageSize in bytes
+	PageSize uint64
+	// Usage in bytes
+	Usage uint64
+}
+
+// I/O limits of a disk, named like in the libvirt iotune element
+const (
+	IOTuneTotalBytesSec = "total_bytes_sec"
+	IOTuneReadBytesSec  = "read_bytes_sec"
+	IOTuneWriteBytesSec = "write_bytes_sec"
+	IOTuneTotalIopsSec  = "total_iops_sec"
+	IOTuneReadIopsSec   = "read_iops_sec"
+	IOTuneWriteIopsSec  = "write_iops_sec"
+)
+
+// DomainStatsBlockThrottle is not part of the libvirt stats; it reports how
+// often a disk was found running at one of its I/O limits.
+type DomainStatsBlockThrottle struct {
+	// Name of the drive, like the name of the block stats
+	Name string
+	// Limit is the I/O limit which was hit
+	Limit string
+	Hits  uint64
+}
+
+// DomainStatsDirtyRate is not part of the libvirt stats; it reports the rate
+// at which the guest dirtied its memory in the last measurement of QEMU.
+type DomainStatsDirtyRate struct {
+	BytesPerSecond uint64
+}
+
+// DomainStatsPressure is not part of the libvirt stats; it reports how long
+// the processes of the launcher pod stalled waiting for a resource.
+type DomainStatsPressure struct {
+	// Some is the time in seconds in which at least one process stalled
+	Some float64
+	// Full is the time in seconds in which all non-idle processes stalled
+	// at once, which older kernels do not report for the CPU
+	FullSet bool
+	Full    float64
+}
+
 type DomainStatsBlock struct {
 	NameSet         bool
 	Name            string
@@ -20,30 +20,14 @@
 package util
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-
-	"kubevirt.io/kubevirt/pkg/util/hardware"
+	"kubevirt.io/kubevirt/pkg/util/cgroup"
 )
 
+// GetPodCPUSet returns the CPUs of the cgroup of the pod, on cgroup v1 and v2
 func GetPodCPUSet() ([]int, error) {
-	var cpuset string
-	file, err := os.Open(hardware.CPUSET_PATH)
+	manager, err := cgroup.NewManager(cgroup.Root)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	if scanner.Scan() {
-		cpuset = scanner.Text()
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-	cpusList, err := hardware.ParseCPUSetLine(cpuset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse cpuset file: %v", err)
-	}
-	return cpusList, nil
+	return manager.GetCpuSet()
 }
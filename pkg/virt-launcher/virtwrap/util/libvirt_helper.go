@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -23,6 +25,15 @@ import (
 	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
 )
 
+const (
+	// The libvirt session daemon of a non-root virt-launcher keeps its
+	// configuration, sockets and logs below the libvirt runtime directory,
+	// which is an emptyDir volume of the pod
+	nonRootLibvirtRuntimeDir = "/var/run/libvirt"
+	libvirtSocketPath        = "/var/run/libvirt/libvirt-sock"
+	systemLibvirtConfigDir   = "/etc/libvirt"
+)
+
 var LifeCycleTranslationMap = map[libvirt.DomainState]api.LifeCycle{
 	libvirt.DOMAIN_NOSTATE:     api.NoState,
 	libvirt.DOMAIN_RUNNING:     api.Running,
@@ -230,12 +241,12 @@ func StartLibvirt(stopChan chan struct{}) {
 	}()
 }
 
-func StartVirtlog(stopChan chan struct{}, domainName string) {
+func StartVirtlog(stopChan chan struct{}, domainName string, nonRoot bool) {
 	go func() {
 		for {
 			var args []string
 			args = append(args, "-f")
-			args = append(args, "/etc/libvirt/virtlogd.conf")
+			args = append(args, filepath.Join(libvirtConfigDir(nonRoot), "virtlogd.conf"))
 			cmd := exec.Command("/usr/sbin/virtlogd", args...)
 
 			exitChan := make(chan struct{})
@@ -247,7 +258,7 @@ func StartVirtlog(stopChan chan struct{}, domainName string) {
 			}
 
 			go func() {
-				logfile := fmt.Sprintf("/var/log/libvirt/qemu/%s.log", domainName)
+				logfile := filepath.Join(qemuLogDir(nonRoot), domainName+".log")
 
 				// It can take a few seconds to the log file to be created
 				for {
@@ -296,6 +307,80 @@ func StartVirtlog(stopChan chan struct{}, domainName string) {
 	}()
 }
 
+// FollowSerialConsoleLog writes the lines virtlogd logs from the serial console
+// to the given logger. The log file is reopened when virtlogd rotates it.
+func FollowSerialConsoleLog(stopChan chan struct{}, logger *log.FilteredLogger, logFile string) {
+	go func() {
+		var file *os.File
+		var reader *bufio.Reader
+		var partialLine string
+		defer func() {
+			if file != nil {
+				file.Close()
+			}
+		}()
+
+		for {
+			if file == nil {
+				f, err := os.Open(logFile)
+				if err == nil {
+					file = f
+					reader = bufio.NewReader(file)
+				} else if !os.IsNotExist(err) {
+					log.Log.Reason(err).Error("failed to open serial console log")
+				}
+			}
+
+			if file != nil {
+				// check for a rotation before draining the file, virtlogd
+				// does not write to the old file anymore once it is renamed
+				rotated := false
+				fileInfo, fileErr := file.Stat()
+				pathInfo, pathErr := os.Stat(logFile)
+				if fileErr != nil || pathErr != nil || !os.SameFile(fileInfo, pathInfo) {
+					rotated = true
+				}
+
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						partialLine += line
+						break
+					}
+					log.LogSerialConsoleLogLine(logger, strings.TrimRight(partialLine+line, "\r\n"))
+					partialLine = ""
+				}
+
+				if rotated {
+					log.LogSerialConsoleLogLine(logger, strings.TrimRight(partialLine, "\r\n"))
+					partialLine = ""
+					file.Close()
+					file = nil
+					continue
+				}
+			}
+
+			select {
+			case <-stopChan:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+}
+
+// ConfigureVirtlogRotation sets when virtlogd rotates the log files it writes,
+// which includes the serial console log.
+func ConfigureVirtlogRotation(maxSize int64, maxBackups int, nonRoot bool) error {
+	virtlogdConf, err := os.OpenFile(filepath.Join(libvirtConfigDir(nonRoot), "virtlogd.conf"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer virtlogdConf.Close()
+	_, err = virtlogdConf.WriteString(fmt.Sprintf("max_size = %d\nmax_backups = %d\n", maxSize, maxBackups))
+	return err
+}
+
 // returns the namespace and name that is encoded in the
 // domain name.
 func SplitVMINamespaceKey(domainName string) (namespace, name string) {
@@ -338,8 +423,63 @@ func NewDomainFromName(name string, vmiUID types.UID) *api.Domain {
 	return domain
 }
 
-func SetupLibvirt() error {
+// LibvirtURI returns the URI of the libvirt daemon of virt-launcher, a non-root
+// virt-launcher runs the session daemon instead of the system daemon
+func LibvirtURI(nonRoot bool) string {
+	if nonRoot {
+		return "qemu+unix:///session?socket=" + libvirtSocketPath
+	}
+	return "qemu:///system"
+}
+
+func libvirtConfigDir(nonRoot bool) string {
+	if nonRoot {
+		return filepath.Join(nonRootLibvirtRuntimeDir, "config", "libvirt")
+	}
+	return systemLibvirtConfigDir
+}
+
+func qemuLogDir(nonRoot bool) string {
+	if nonRoot {
+		return filepath.Join(nonRootLibvirtRuntimeDir, "cache", "libvirt", "qemu", "log")
+	}
+	return "/var/log/libvirt/qemu"
+}
+
+// setupSessionLibvirt points the libvirt session daemon, virtlogd and qemu to
+// directories the unprivileged user can write, and starts them off with the
+// configuration of the system daemon
+func setupSessionLibvirt() error {
+	env := map[string]string{
+		"XDG_RUNTIME_DIR": filepath.Dir(nonRootLibvirtRuntimeDir),
+		"XDG_CONFIG_HOME": filepath.Join(nonRootLibvirtRuntimeDir, "config"),
+		"XDG_CACHE_HOME":  filepath.Join(nonRootLibvirtRuntimeDir, "cache"),
+	}
+	for name, value := range env {
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range []string{libvirtConfigDir(true), qemuLogDir(true)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{"libvirtd.conf", "qemu.conf", "virtlogd.conf"} {
+		conf, err := ioutil.ReadFile(filepath.Join(systemLibvirtConfigDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(libvirtConfigDir(true), name), conf, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func setupKVMDevice() error {
 	// TODO: setting permissions and owners is not part of device plugins.
 	// Configure these manually right now on "/dev/kvm"
 	stats, err := os.Stat("/dev/kvm")
@@ -367,8 +507,20 @@ func SetupLibvirt() error {
 	} else if !os.IsNotExist(err) {
 		return err
 	}
+	return nil
+}
+
+func SetupLibvirt(nonRoot bool) error {
+	if nonRoot {
+		// virt-handler hands /dev/kvm over to the unprivileged user
+		if err := setupSessionLibvirt(); err != nil {
+			return err
+		}
+	} else if err := setupKVMDevice(); err != nil {
+		return err
+	}
 
-	qemuConf, err := os.OpenFile("/etc/libvirt/qemu.conf", os.O_APPEND|os.O_WRONLY, 0644)
+	qemuConf, err := os.OpenFile(filepath.Join(libvirtConfigDir(nonRoot), "qemu.conf"), os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
 	}
@@ -388,7 +540,7 @@ func SetupLibvirt() error {
 	}
 
 	// Let libvirt log to stderr
-	libvirtConf, err := os.OpenFile("/etc/libvirt/libvirtd.conf", os.O_APPEND|os.O_WRONLY, 0644)
+	libvirtConf, err := os.OpenFile(filepath.Join(libvirtConfigDir(nonRoot), "libvirtd.conf"), os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
 	}
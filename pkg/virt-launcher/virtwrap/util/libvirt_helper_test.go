@@ -4,13 +4,18 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	kubevirtlog "kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/util"
 )
 
 const (
@@ -195,4 +200,67 @@ var _ = Describe("LibvirtHelper", func() {
 
 		Expect(loggedLines).To(Equal(expectedLines))
 	})
+
+	Context("following the serial console log", func() {
+		var tmpDir string
+		var logFile string
+		var stopChan chan struct{}
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "serial-console-log")
+			Expect(err).ToNot(HaveOccurred())
+			logFile = filepath.Join(tmpDir, "virt-serial0.log")
+			stopChan = make(chan struct{})
+		})
+
+		AfterEach(func() {
+			close(stopChan)
+			os.RemoveAll(tmpDir)
+		})
+
+		appendToLog := func(data string) {
+			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			Expect(err).ToNot(HaveOccurred())
+			defer f.Close()
+			_, err = f.WriteString(data)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		It("should log the serial console lines across rotations", func() {
+			buffer := &lockedBuffer{}
+			kubevirtlog.InitializeLogging("virt-launcher")
+			klog := kubevirtlog.MakeLogger(log.NewContext(log.NewJSONLogger(buffer)))
+
+			util.FollowSerialConsoleLog(stopChan, klog, logFile)
+
+			appendToLog("Booting from Hard Disk...\r\nWelcome to ")
+			Eventually(buffer.String, 5).Should(ContainSubstring(`"msg":"Booting from Hard Disk..."`))
+			Expect(buffer.String()).ToNot(ContainSubstring("Welcome"))
+
+			appendToLog("Alpine Linux\r\n")
+			Expect(os.Rename(logFile, logFile+".0")).To(Succeed())
+			appendToLog("login:\r\n")
+
+			Eventually(buffer.String, 5).Should(ContainSubstring(`"msg":"login:"`))
+			Expect(buffer.String()).To(ContainSubstring(`"msg":"Welcome to Alpine Linux","subcomponent":"serial-console"`))
+		})
+	})
 })
+
+type lockedBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
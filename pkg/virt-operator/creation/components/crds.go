@@ -208,9 +208,157 @@ func NewVirtualMachineInstanceMigrationCrd() *extv1beta1.CustomResourceDefinitio
 	return crd
 }
 
+func NewConformanceRunCrd() *extv1beta1.CustomResourceDefinition {
+	crd := newBlankCrd()
+
+	crd.ObjectMeta.Name = "conformanceruns." + virtv1.ConformanceRunGroupVersionKind.Group
+	crd.Spec = extv1beta1.CustomResourceDefinitionSpec{
+		Group:    virtv1.ConformanceRunGroupVersionKind.Group,
+		Version:  virtv1.ApiSupportedVersions[0].Name,
+		Versions: virtv1.ApiSupportedVersions,
+		Scope:    "Namespaced",
+
+		Names: extv1beta1.CustomResourceDefinitionNames{
+			Plural:     "conformanceruns",
+			Singular:   "conformancerun",
+			Kind:       virtv1.ConformanceRunGroupVersionKind.Kind,
+			ShortNames: []string{"cfr", "cfrs"},
+		},
+		AdditionalPrinterColumns: []extv1beta1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+			{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+			{Name: "Passed", Type: "integer", JSONPath: ".status.passed"},
+			{Name: "Failed", Type: "integer", JSONPath: ".status.failed"},
+			{Name: "Skipped", Type: "integer", JSONPath: ".status.skipped"},
+		},
+		Subresources: &extv1beta1.CustomResourceSubresources{
+			Status: &extv1beta1.CustomResourceSubresourceStatus{},
+		},
+	}
+
+	return crd
+}
+
+// Used by manifest generation
+// If you change something here, you probably need to change the CSV manifest too,
+// see /manifests/release/kubevirt.VERSION.csv.yaml.in
+func NewVirtualMachineExportCrd() *extv1beta1.CustomResourceDefinition {
+	crd := newBlankCrd()
+
+	crd.ObjectMeta.Name = "virtualmachineexports." + virtv1.VirtualMachineExportGroupVersionKind.Group
+	crd.Spec = extv1beta1.CustomResourceDefinitionSpec{
+		Group:    virtv1.VirtualMachineExportGroupVersionKind.Group,
+		Version:  virtv1.ApiSupportedVersions[0].Name,
+		Versions: virtv1.ApiSupportedVersions,
+		Scope:    "Namespaced",
+
+		Names: extv1beta1.CustomResourceDefinitionNames{
+			Plural:     "virtualmachineexports",
+			Singular:   "virtualmachineexport",
+			Kind:       virtv1.VirtualMachineExportGroupVersionKind.Kind,
+			ShortNames: []string{"vmexport", "vmexports"},
+		},
+		AdditionalPrinterColumns: []extv1beta1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+			{Name: "Source", Type: "string", JSONPath: ".spec.source"},
+			{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+			{Name: "Service", Type: "string", JSONPath: ".status.serviceName"},
+		},
+		Subresources: &extv1beta1.CustomResourceSubresources{
+			Status: &extv1beta1.CustomResourceSubresourceStatus{},
+		},
+	}
+
+	return crd
+}
+
+// Used by manifest generation
+// If you change something here, you probably need to change the CSV manifest too,
+// see /manifests/release/kubevirt.VERSION.csv.yaml.in
+func NewVirtualMachineBackupCrd() *extv1beta1.CustomResourceDefinition {
+	crd := newBlankCrd()
+
+	crd.ObjectMeta.Name = "virtualmachinebackups." + virtv1.VirtualMachineBackupGroupVersionKind.Group
+	crd.Spec = extv1beta1.CustomResourceDefinitionSpec{
+		Group:    virtv1.VirtualMachineBackupGroupVersionKind.Group,
+		Version:  virtv1.ApiSupportedVersions[0].Name,
+		Versions: virtv1.ApiSupportedVersions,
+		Scope:    "Namespaced",
+
+		Names: extv1beta1.CustomResourceDefinitionNames{
+			Plural:     "virtualmachinebackups",
+			Singular:   "virtualmachinebackup",
+			Kind:       virtv1.VirtualMachineBackupGroupVersionKind.Kind,
+			ShortNames: []string{"vmbackup", "vmbackups"},
+		},
+		AdditionalPrinterColumns: []extv1beta1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+			{Name: "Source", Type: "string", JSONPath: ".spec.source"},
+			{Name: "Incremental", Type: "string", JSONPath: ".spec.incremental"},
+			{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+		},
+		Subresources: &extv1beta1.CustomResourceSubresources{
+			Status: &extv1beta1.CustomResourceSubresourceStatus{},
+		},
+	}
+
+	return crd
+}
+
 // Used by manifest generation
 // If you change something here, you probably need to change the CSV manifest too,
 // see /manifests/release/kubevirt.VERSION.csv.yaml.in
+func NewVirtualMachineInstancetypeCrd() *extv1beta1.CustomResourceDefinition {
+	crd := newBlankCrd()
+
+	crd.ObjectMeta.Name = "virtualmachineinstancetypes." + virtv1.VirtualMachineInstancetypeGroupVersionKind.Group
+	crd.Spec = extv1beta1.CustomResourceDefinitionSpec{
+		Group:    virtv1.VirtualMachineInstancetypeGroupVersionKind.Group,
+		Version:  virtv1.ApiSupportedVersions[0].Name,
+		Versions: virtv1.ApiSupportedVersions,
+		Scope:    "Namespaced",
+
+		Names: extv1beta1.CustomResourceDefinitionNames{
+			Plural:     "virtualmachineinstancetypes",
+			Singular:   "virtualmachineinstancetype",
+			Kind:       virtv1.VirtualMachineInstancetypeGroupVersionKind.Kind,
+			ShortNames: []string{"vminstancetype", "vminstancetypes", "vmf", "vmfs"},
+		},
+		AdditionalPrinterColumns: []extv1beta1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+		},
+	}
+
+	return crd
+}
+
+// Used by manifest generation
+// If you change something here, you probably need to change the CSV manifest too,
+// see /manifests/release/kubevirt.VERSION.csv.yaml.in
+func NewVirtualMachinePreferenceCrd() *extv1beta1.CustomResourceDefinition {
+	crd := newBlankCrd()
+
+	crd.ObjectMeta.Name = "virtualmachinepreferences." + virtv1.VirtualMachinePreferenceGroupVersionKind.Group
+	crd.Spec = extv1beta1.CustomResourceDefinitionSpec{
+		Group:    virtv1.VirtualMachinePreferenceGroupVersionKind.Group,
+		Version:  virtv1.ApiSupportedVersions[0].Name,
+		Versions: virtv1.ApiSupportedVersions,
+		Scope:    "Namespaced",
+
+		Names: extv1beta1.CustomResourceDefinitionNames{
+			Plural:     "virtualmachinepreferences",
+			Singular:   "virtualmachinepreference",
+			Kind:       virtv1.VirtualMachinePreferenceGroupVersionKind.Kind,
+			ShortNames: []string{"vmpref", "vmprefs", "vmp", "vmps"},
+		},
+		AdditionalPrinterColumns: []extv1beta1.CustomResourceColumnDefinition{
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+		},
+	}
+
+	return crd
+}
+
 func NewKubeVirtCrd() *extv1beta1.CustomResourceDefinition {
 
 	// we use a different label here, so no newBlankCrd()
@@ -223,6 +223,7 @@ func NewApiServerDeployment(namespace string, repository string, imagePrefix str
 
 	attachCertificateSecret(&deployment.Spec.Template.Spec, VirtApiCertSecretName, "/etc/virt-api/certificates")
 	attachCertificateSecret(&deployment.Spec.Template.Spec, VirtHandlerCertSecretName, "/etc/virt-handler/clientcertificates")
+	attachCertificateSecret(&deployment.Spec.Template.Spec, VirtApiVNCTokenKeySecretName, "/etc/virt-api/vnc-token-key")
 	pod := &deployment.Spec.Template.Spec
 	pod.ServiceAccountName = rbac.ApiServiceAccountName
 	pod.SecurityContext = &corev1.PodSecurityContext{
@@ -338,7 +339,7 @@ func NewControllerDeployment(namespace string, repository string, imagePrefix st
 	return deployment, nil
 }
 
-func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string, version string, pullPolicy corev1.PullPolicy, verbosity string, extraEnv map[string]string) (*appsv1.DaemonSet, error) {
+func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string, version string, launcherVersion string, pullPolicy corev1.PullPolicy, verbosity string, extraEnv map[string]string) (*appsv1.DaemonSet, error) {
 
 	deploymentName := "virt-handler"
 	imageName := fmt.Sprintf("%s%s", imagePrefix, deploymentName)
@@ -400,6 +401,25 @@ func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string
 			ContainerPort: 8443,
 		},
 	}
+	// the health endpoint checks the connection to the apiserver and only
+	// comes up once the handler finished starting, the canary rollout of
+	// virt-handler relies on it
+	container.ReadinessProbe = &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Scheme: corev1.URISchemeHTTPS,
+				Port: intstr.IntOrString{
+					Type:   intstr.Int,
+					IntVal: 8443,
+				},
+				Path: "/healthz",
+			},
+		},
+		InitialDelaySeconds: 15,
+		PeriodSeconds:       20,
+		TimeoutSeconds:      10,
+		FailureThreshold:    3,
+	}
 	container.SecurityContext = &corev1.SecurityContext{
 		Privileged: boolPtr(true),
 	}
@@ -467,6 +487,39 @@ func NewHandlerDaemonSet(namespace string, repository string, imagePrefix string
 		})
 	}
 
+	// The node-labeller of virt-handler learns from libvirt which CPU models the node can run.
+	// Failing to query libvirt, e.g. on nodes without KVM, must not keep virt-handler from starting.
+	launcherVersion = AddVersionSeparatorPrefix(launcherVersion)
+	nodeLabellerVolumeMount := corev1.VolumeMount{
+		Name:      "node-labeller",
+		MountPath: "/var/lib/kubevirt-node-labeller",
+	}
+	pod.InitContainers = []corev1.Container{
+		{
+			Name:            "virt-launcher",
+			Image:           fmt.Sprintf("%s/%s%s%s", repository, imagePrefix, "virt-launcher", launcherVersion),
+			ImagePullPolicy: pullPolicy,
+			Command: []string{
+				"/bin/sh",
+				"-c",
+			},
+			Args: []string{
+				"libvirtd -d; virsh domcapabilities > /var/lib/kubevirt-node-labeller/virsh_domcapabilities.xml || rm -f /var/lib/kubevirt-node-labeller/virsh_domcapabilities.xml",
+			},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: boolPtr(true),
+			},
+			VolumeMounts: []corev1.VolumeMount{nodeLabellerVolumeMount},
+		},
+	}
+	container.VolumeMounts = append(container.VolumeMounts, nodeLabellerVolumeMount)
+	pod.Volumes = append(pod.Volumes, corev1.Volume{
+		Name: "node-labeller",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
 	return daemonset, nil
 
 }
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -27,7 +28,10 @@ const (
 	VirtOperatorCertSecretName      = "kubevirt-operator-certs"
 	VirtApiCertSecretName           = "kubevirt-virt-api-certs"
 	VirtControllerCertSecretName    = "kubevirt-controller-certs"
+	VirtApiVNCTokenKeySecretName    = "kubevirt-virt-api-vnc-token-key"
 	CABundleKey                     = "ca-bundle"
+	VNCTokenKeyBytesValue           = "key"
+	vncTokenKeyLength               = 32
 )
 
 type CertificateCreationCallback func(secret *k8sv1.Secret, caCert *tls.Certificate, duration time.Duration) (cert *x509.Certificate, key *rsa.PrivateKey)
@@ -138,6 +142,43 @@ func PopulateSecretWithCertificate(secret *k8sv1.Secret, caCert *tls.Certificate
 	return nil
 }
 
+// NewVNCTokenKeySecret returns the secret holding the key which virt-api signs VNC tokens with.
+// It is not a certificate, and is populated once by PopulateVNCTokenKeySecret.
+func NewVNCTokenKeySecret(installNamespace string) *k8sv1.Secret {
+	return &k8sv1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VirtApiVNCTokenKeySecretName,
+			Namespace: installNamespace,
+			Labels: map[string]string{
+				v1.ManagedByLabel: v1.ManagedByLabelOperatorValue,
+			},
+		},
+		Type: "Opaque",
+	}
+}
+
+func PopulateVNCTokenKeySecret(secret *k8sv1.Secret) error {
+	key := make([]byte, vncTokenKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	secret.Data = map[string][]byte{
+		VNCTokenKeyBytesValue: key,
+	}
+	return nil
+}
+
+func ValidateVNCTokenKeySecret(secret *k8sv1.Secret) error {
+	if len(secret.Data[VNCTokenKeyBytesValue]) < vncTokenKeyLength {
+		return fmt.Errorf("%s value not found in %s secret\n", VNCTokenKeyBytesValue, secret.Name)
+	}
+	return nil
+}
+
 func NewCACertSecret(operatorNamespace string) *k8sv1.Secret {
 	return &k8sv1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -248,10 +289,10 @@ func NewCertSecrets(installNamespace string, operatorNamespace string) []*k8sv1.
 	return secrets
 }
 
-// nextRotationDeadline returns a value for the threshold at which the
-// current certificate should be rotated, 80% of the expiration of the
-// certificate.
-func NextRotationDeadline(cert *tls.Certificate, ca *tls.Certificate, duration *metav1.Duration) time.Time {
+// NextRotationDeadline returns a value for the threshold at which the
+// current certificate should be rotated, renewBefore ahead of the expiration
+// of the certificate, or at 80% of its lifetime if renewBefore is not set.
+func NextRotationDeadline(cert *tls.Certificate, ca *tls.Certificate, duration *metav1.Duration, renewBefore *metav1.Duration) time.Time {
 
 	if cert == nil {
 		return time.Now()
@@ -274,6 +315,9 @@ func NextRotationDeadline(cert *tls.Certificate, ca *tls.Certificate, duration *
 
 	notAfter := cert.Leaf.NotAfter
 	deadline := notAfter.Add(-time.Duration(float64(duration.Duration) * 0.2))
+	if renewBefore != nil {
+		deadline = notAfter.Add(-renewBefore.Duration)
+	}
 
 	log.DefaultLogger().V(4).Infof("Certificate with common name '%s' expiration is %v, rotation deadline is %v", cert.Leaf.Subject.CommonName, notAfter, deadline)
 	return deadline
@@ -128,7 +128,7 @@ var _ = Describe("Certificate Management", func() {
 			current := NewSelfSignedCert(now, now.Add(1*time.Hour))
 			ca := NewSelfSignedCert(now, now.Add(1*time.Hour))
 			duration := &v1.Duration{Duration: 5 * time.Hour}
-			deadline := NextRotationDeadline(current, ca, duration)
+			deadline := NextRotationDeadline(current, ca, duration, nil)
 			Expect(deadline.Before(time.Now())).To(BeTrue())
 		})
 
@@ -174,12 +174,29 @@ var _ = Describe("Certificate Management", func() {
 			crt, err := LoadCertificates(crtSecret)
 
 			deadline := now.Add(time.Duration(float64(crtDuration.Duration) * 0.8))
-			Expect(NextRotationDeadline(crt, caCrt, crtDuration).Unix()).To(BeNumerically("==", deadline.Unix(), 1))
+			Expect(NextRotationDeadline(crt, caCrt, crtDuration, nil).Unix()).To(BeNumerically("==", deadline.Unix(), 1))
 		},
 			table.Entry("with a long valid CA", 24*time.Hour),
 			table.Entry("with a CA which expires before the certificate rotation", 1*time.Hour),
 		)
 
+		It("should suggest a rotation on the certificate renewBefore ahead of its expiration", func() {
+			now := time.Now()
+			crtDuration := &v1.Duration{Duration: 2 * time.Hour}
+			renewBefore := &v1.Duration{Duration: 90 * time.Minute}
+			caSecret := NewCACertSecret("test")
+			Expect(PopulateSecretWithCertificate(caSecret, nil, &v1.Duration{Duration: 24 * time.Hour})).To(Succeed())
+			caCrt, err := LoadCertificates(caSecret)
+			Expect(err).NotTo(HaveOccurred())
+			crtSecret := NewCertSecrets("test", "test")[0]
+			Expect(PopulateSecretWithCertificate(crtSecret, caCrt, crtDuration)).To(Succeed())
+			crt, err := LoadCertificates(crtSecret)
+			Expect(err).NotTo(HaveOccurred())
+
+			deadline := now.Add(30 * time.Minute)
+			Expect(NextRotationDeadline(crt, caCrt, crtDuration, renewBefore).Unix()).To(BeNumerically("==", deadline.Unix(), 1))
+		})
+
 		table.DescribeTable("should successfully sign with the current CA the certificate for", func(scretName string) {
 			duration := &v1.Duration{Duration: 5 * time.Hour}
 			caSecret := NewCACertSecret("test")
@@ -215,6 +232,19 @@ var _ = Describe("Certificate Management", func() {
 		Expect(secrets[len(secrets)-1].Namespace).To(Equal("operator_namespace"))
 	})
 
+	It("should populate the VNC token key secret with a random key", func() {
+		secret := NewVNCTokenKeySecret("install_namespace")
+		Expect(secret.Namespace).To(Equal("install_namespace"))
+		Expect(ValidateVNCTokenKeySecret(secret)).ToNot(Succeed())
+
+		Expect(PopulateVNCTokenKeySecret(secret)).To(Succeed())
+		Expect(ValidateVNCTokenKeySecret(secret)).To(Succeed())
+
+		otherSecret := NewVNCTokenKeySecret("install_namespace")
+		Expect(PopulateVNCTokenKeySecret(otherSecret)).To(Succeed())
+		Expect(otherSecret.Data[VNCTokenKeyBytesValue]).ToNot(Equal(secret.Data[VNCTokenKeyBytesValue]))
+	})
+
 	It("should create the kubevirt-ca configmap for the right namespace", func() {
 		configMap := NewKubeVirtCAConfigMap("namespace")
 		Expect(configMap.Namespace).To(Equal("namespace"))
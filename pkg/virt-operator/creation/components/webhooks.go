@@ -189,6 +189,7 @@ func NewVirtAPIValidatingWebhookConfiguration(installNamespace string) *v1beta1.
 	migrationUpdatePath := MigrationUpdateValidatePath
 	vmSnapshotValidatePath := VMSnapshotValidatePath
 	statusValidatePath := StatusValidatePath
+	launcherEvictionValidatePath := LauncherEvictionValidatePath
 	failurePolicy := v1beta1.Fail
 
 	return &v1beta1.ValidatingWebhookConfiguration{
@@ -401,6 +402,27 @@ func NewVirtAPIValidatingWebhookConfiguration(installNamespace string) *v1beta1.
 					},
 				},
 			},
+			{
+				Name:          "virt-launcher-eviction-interceptor.kubevirt.io",
+				FailurePolicy: &failurePolicy,
+				Rules: []v1beta1.RuleWithOperations{{
+					Operations: []v1beta1.OperationType{
+						v1beta1.Create,
+					},
+					Rule: v1beta1.Rule{
+						APIGroups:   []string{""},
+						APIVersions: []string{"v1"},
+						Resources:   []string{"pods/eviction"},
+					},
+				}},
+				ClientConfig: v1beta1.WebhookClientConfig{
+					Service: &v1beta1.ServiceReference{
+						Namespace: installNamespace,
+						Name:      VirtApiServiceName,
+						Path:      &launcherEvictionValidatePath,
+					},
+				},
+			},
 		},
 	}
 }
@@ -444,3 +466,5 @@ const KubeVirtOperatorValidatingWebhookName = "virt-operator-validator"
 const VMSnapshotValidatePath = "/virtualmachinesnapshots-validate"
 
 const StatusValidatePath = "/status-validate"
+
+const LauncherEvictionValidatePath = "/launcher-eviction-validate"
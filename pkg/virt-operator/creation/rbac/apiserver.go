@@ -124,6 +124,29 @@ func newApiServerClusterRole() *rbacv1.ClusterRole {
 					"watch", "list",
 				},
 			},
+			{
+				APIGroups: []string{
+					"kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachineinstancetypes",
+					"virtualmachinepreferences",
+				},
+				Verbs: []string{
+					"get",
+				},
+			},
+			{
+				APIGroups: []string{
+					"apps",
+				},
+				Resources: []string{
+					"controllerrevisions",
+				},
+				Verbs: []string{
+					"get",
+				},
+			},
 			{
 				APIGroups: []string{
 					"",
@@ -141,6 +164,18 @@ func newApiServerClusterRole() *rbacv1.ClusterRole {
 				},
 				Resources: []string{
 					"limitranges",
+					"resourcequotas",
+				},
+				Verbs: []string{
+					"watch", "list",
+				},
+			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"nodes",
 				},
 				Verbs: []string{
 					"watch", "list",
@@ -172,6 +207,17 @@ func newApiServerClusterRole() *rbacv1.ClusterRole {
 					"watch",
 				},
 			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"events",
+				},
+				Verbs: []string{
+					"create", "patch",
+				},
+			},
 		},
 	}
 }
@@ -123,8 +123,18 @@ func newAdminClusterRole() *rbacv1.ClusterRole {
 				Resources: []string{
 					"virtualmachineinstances/console",
 					"virtualmachineinstances/vnc",
+					"virtualmachineinstances/vnc-token",
+					"virtualmachineinstances/portforward",
+					"virtualmachineinstances/usbredir",
+					"virtualmachineinstances/backup",
 					"virtualmachineinstances/pause",
 					"virtualmachineinstances/unpause",
+					"virtualmachineinstances/freeze",
+					"virtualmachineinstances/thaw",
+					"virtualmachineinstances/stats",
+					"virtualmachineinstances/guestosinfo",
+					"virtualmachineinstances/userlist",
+					"virtualmachineinstances/filesystemlist",
 				},
 				Verbs: []string{
 					"get",
@@ -138,11 +148,25 @@ func newAdminClusterRole() *rbacv1.ClusterRole {
 					"virtualmachines/start",
 					"virtualmachines/stop",
 					"virtualmachines/restart",
+					"virtualmachines/rollback",
+					"virtualmachineinstances/memorydump",
 				},
 				Verbs: []string{
 					"update",
 				},
 			},
+			{
+				APIGroups: []string{
+					"subresources.kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachines/expand-spec",
+					"expand-spec",
+				},
+				Verbs: []string{
+					"get", "update",
+				},
+			},
 			{
 				APIGroups: []string{
 					"kubevirt.io",
@@ -153,6 +177,11 @@ func newAdminClusterRole() *rbacv1.ClusterRole {
 					"virtualmachineinstancepresets",
 					"virtualmachineinstancereplicasets",
 					"virtualmachineinstancemigrations",
+					"conformanceruns",
+					"virtualmachineexports",
+					"virtualmachinebackups",
+					"virtualmachineinstancetypes",
+					"virtualmachinepreferences",
 				},
 				Verbs: []string{
 					"get", "delete", "create", "update", "patch", "list", "watch", "deletecollection",
@@ -183,8 +212,18 @@ func newEditClusterRole() *rbacv1.ClusterRole {
 				Resources: []string{
 					"virtualmachineinstances/console",
 					"virtualmachineinstances/vnc",
+					"virtualmachineinstances/vnc-token",
+					"virtualmachineinstances/portforward",
+					"virtualmachineinstances/usbredir",
+					"virtualmachineinstances/backup",
 					"virtualmachineinstances/pause",
 					"virtualmachineinstances/unpause",
+					"virtualmachineinstances/freeze",
+					"virtualmachineinstances/thaw",
+					"virtualmachineinstances/stats",
+					"virtualmachineinstances/guestosinfo",
+					"virtualmachineinstances/userlist",
+					"virtualmachineinstances/filesystemlist",
 				},
 				Verbs: []string{
 					"get",
@@ -198,11 +237,25 @@ func newEditClusterRole() *rbacv1.ClusterRole {
 					"virtualmachines/start",
 					"virtualmachines/stop",
 					"virtualmachines/restart",
+					"virtualmachines/rollback",
+					"virtualmachineinstances/memorydump",
 				},
 				Verbs: []string{
 					"update",
 				},
 			},
+			{
+				APIGroups: []string{
+					"subresources.kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachines/expand-spec",
+					"expand-spec",
+				},
+				Verbs: []string{
+					"get", "update",
+				},
+			},
 			{
 				APIGroups: []string{
 					"kubevirt.io",
@@ -213,6 +266,11 @@ func newEditClusterRole() *rbacv1.ClusterRole {
 					"virtualmachineinstancepresets",
 					"virtualmachineinstancereplicasets",
 					"virtualmachineinstancemigrations",
+					"conformanceruns",
+					"virtualmachineexports",
+					"virtualmachinebackups",
+					"virtualmachineinstancetypes",
+					"virtualmachinepreferences",
 				},
 				Verbs: []string{
 					"get", "delete", "create", "update", "patch", "list", "watch",
@@ -236,6 +294,32 @@ func newViewClusterRole() *rbacv1.ClusterRole {
 			},
 		},
 		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{
+					"subresources.kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachineinstances/stats",
+					"virtualmachineinstances/guestosinfo",
+					"virtualmachineinstances/userlist",
+					"virtualmachineinstances/filesystemlist",
+				},
+				Verbs: []string{
+					"get",
+				},
+			},
+			{
+				APIGroups: []string{
+					"subresources.kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachines/expand-spec",
+					"expand-spec",
+				},
+				Verbs: []string{
+					"get", "update",
+				},
+			},
 			{
 				APIGroups: []string{
 					"kubevirt.io",
@@ -246,6 +330,11 @@ func newViewClusterRole() *rbacv1.ClusterRole {
 					"virtualmachineinstancepresets",
 					"virtualmachineinstancereplicasets",
 					"virtualmachineinstancemigrations",
+					"conformanceruns",
+					"virtualmachineexports",
+					"virtualmachinebackups",
+					"virtualmachineinstancetypes",
+					"virtualmachinepreferences",
 				},
 				Verbs: []string{
 					"get", "list", "watch",
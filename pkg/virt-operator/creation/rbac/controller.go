@@ -81,12 +81,23 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"",
 				},
 				Resources: []string{
-					"pods", "configmaps", "endpoints",
+					"pods", "configmaps", "endpoints", "services",
 				},
 				Verbs: []string{
 					"get", "list", "watch", "delete", "update", "create",
 				},
 			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"secrets",
+				},
+				Verbs: []string{
+					"get", "create",
+				},
+			},
 			{
 				APIGroups: []string{
 					"",
@@ -109,6 +120,17 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"update",
 				},
 			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"pods/eviction",
+				},
+				Verbs: []string{
+					"create",
+				},
+			},
 			{
 				APIGroups: []string{
 					"",
@@ -128,7 +150,18 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"persistentvolumeclaims",
 				},
 				Verbs: []string{
-					"get", "list", "watch",
+					"get", "list", "watch", "create", "delete",
+				},
+			},
+			{
+				APIGroups: []string{
+					"apps",
+				},
+				Resources: []string{
+					"controllerrevisions",
+				},
+				Verbs: []string{
+					"create", "get", "list", "watch", "update", "delete",
 				},
 			},
 			{
@@ -153,6 +186,17 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"*",
 				},
 			},
+			{
+				APIGroups: []string{
+					"subresources.kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachineinstances/console",
+				},
+				Verbs: []string{
+					"get",
+				},
+			},
 			{
 				APIGroups: []string{
 					"cdi.kubevirt.io",
@@ -241,6 +285,22 @@ func newControllerClusterRole() *rbacv1.ClusterRole {
 					"watch",
 				},
 			},
+			{
+				APIGroups: []string{
+					"networking.k8s.io",
+				},
+				Resources: []string{
+					"networkpolicies",
+				},
+				Verbs: []string{
+					"get",
+					"list",
+					"watch",
+					"create",
+					"update",
+					"delete",
+				},
+			},
 		},
 	}
 }
@@ -79,6 +79,18 @@ func newHandlerClusterRole() *rbacv1.ClusterRole {
 					"update", "list", "watch",
 				},
 			},
+			{
+				APIGroups: []string{
+					"kubevirt.io",
+				},
+				Resources: []string{
+					"virtualmachinebackups",
+					"virtualmachinebackups/status",
+				},
+				Verbs: []string{
+					"update", "list", "watch",
+				},
+			},
 			{
 				APIGroups: []string{
 					"",
@@ -87,7 +99,30 @@ func newHandlerClusterRole() *rbacv1.ClusterRole {
 					"persistentvolumeclaims",
 				},
 				Verbs: []string{
-					"get",
+					"get", "list", "watch",
+				},
+			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"pods",
+				},
+				Verbs: []string{
+					"list",
+					"watch",
+				},
+			},
+			{
+				APIGroups: []string{
+					"",
+				},
+				Resources: []string{
+					"pods/status",
+				},
+				Verbs: []string{
+					"patch",
 				},
 			},
 			{
@@ -98,6 +133,7 @@ func newHandlerClusterRole() *rbacv1.ClusterRole {
 					"nodes",
 				},
 				Verbs: []string{
+					"get",
 					"patch",
 				},
 			},
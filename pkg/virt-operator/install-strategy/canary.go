@@ -0,0 +1,259 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package installstrategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+const (
+	defaultCanaryVerificationPeriod = 5 * time.Minute
+	defaultCanaryFailureTimeout     = 10 * time.Minute
+	// canaryMaxRestarts is how often the containers of an updated pod may
+	// restart, e.g. after a node reboot, before it counts as crashing even
+	// though it is ready between the crashes
+	canaryMaxRestarts = 2
+)
+
+// syncDaemonSetCanary updates the pods of a daemonset batch by batch instead of
+// leaving the rollout to the daemonset controller. The daemonset is switched to
+// the OnDelete update strategy and the outdated pods are deleted, first on a
+// single canary node and then on doubling batches of nodes, each once the
+// verification period of the previous batch passed without failures. The
+// health of the updated pods is taken from their readiness, which the
+// readiness probe of virt-handler derives from its health endpoint, and from
+// their container restarts. If an updated pod stays unready for longer than
+// the failure timeout, or keeps crashing, the daemonset is rolled back to the
+// previous install strategy.
+func syncDaemonSetCanary(queue workqueue.RateLimitingInterface,
+	kv *v1.KubeVirt,
+	daemonSet *appsv1.DaemonSet,
+	prevStrategy *InstallStrategy,
+	stores util.Stores,
+	clientset kubecli.KubevirtClient,
+	expectations *util.Expectations) error {
+
+	kvkey, err := controller.KeyFunc(kv)
+	if err != nil {
+		return err
+	}
+
+	status := kv.Status.CanaryRollout
+	if status == nil || status.DeploymentID != kv.Status.TargetDeploymentID {
+		status = &v1.KubeVirtCanaryRolloutStatus{
+			DeploymentID: kv.Status.TargetDeploymentID,
+			Phase:        v1.CanaryRolloutProgressing,
+			UpdatedNodes: 1,
+		}
+		kv.Status.CanaryRollout = status
+	}
+
+	switch status.Phase {
+	case v1.CanaryRolloutRolledBack:
+		// the previous version is kept until the KubeVirt CR targets another one
+		return nil
+	case v1.CanaryRolloutSucceeded:
+		return syncDaemonSet(kv, daemonSet, stores, clientset, expectations)
+	}
+
+	canaryDaemonSet := daemonSet.DeepCopy()
+	canaryDaemonSet.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+		Type: appsv1.OnDeleteDaemonSetStrategyType,
+	}
+	err = syncDaemonSet(kv, canaryDaemonSet, stores, clientset, expectations)
+	if err != nil {
+		return err
+	}
+
+	// wait until the updated daemonset is observed before touching its pods
+	obj, exists, _ := stores.DaemonSetCache.Get(daemonSet)
+	if !exists {
+		return nil
+	}
+	cachedDaemonSet := obj.(*appsv1.DaemonSet)
+	if !objectMatchesVersion(&cachedDaemonSet.ObjectMeta, kv.Status.TargetKubeVirtVersion, kv.Status.TargetKubeVirtRegistry, kv.Status.TargetDeploymentID) {
+		return nil
+	}
+
+	var updated, outdated []*corev1.Pod
+	terminating := 0
+	for _, obj := range stores.InfrastructurePodCache.List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !strings.HasPrefix(pod.Name, daemonSet.Name+"-") {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			terminating++
+		} else if util.PodIsUpToDate(pod, kv) {
+			updated = append(updated, pod)
+		} else {
+			outdated = append(outdated, pod)
+		}
+	}
+
+	now := metav1.Now()
+
+	for _, pod := range updated {
+		if restarts := podRestarts(pod); restarts > canaryMaxRestarts {
+			if status.UnhealthySince == nil {
+				status.UnhealthySince = &now
+			}
+			return rollbackDaemonSetCanary(kv, daemonSet, prevStrategy, clientset,
+				fmt.Sprintf("the updated pod %s restarted %d times", pod.Name, restarts))
+		}
+	}
+
+	unhealthy := 0
+	for _, pod := range updated {
+		if !util.PodIsReady(pod) {
+			unhealthy++
+		}
+	}
+	if unhealthy > 0 {
+		failureTimeout := defaultCanaryFailureTimeout
+		if kv.Spec.CanaryRollout.FailureTimeout != nil {
+			failureTimeout = kv.Spec.CanaryRollout.FailureTimeout.Duration
+		}
+		if status.UnhealthySince == nil {
+			status.UnhealthySince = &now
+		}
+		unhealthyFor := now.Sub(status.UnhealthySince.Time)
+		if unhealthyFor >= failureTimeout {
+			return rollbackDaemonSetCanary(kv, daemonSet, prevStrategy, clientset,
+				fmt.Sprintf("the updated pods were unhealthy since %s", status.UnhealthySince.UTC().Format(time.RFC3339)))
+		}
+		status.Message = fmt.Sprintf("Waiting for %d updated %s pods to become ready", unhealthy, daemonSet.Name)
+		queue.AddAfter(kvkey, failureTimeout-unhealthyFor)
+		return nil
+	}
+	status.UnhealthySince = nil
+
+	if terminating > 0 || len(updated)+len(outdated) < int(cachedDaemonSet.Status.DesiredNumberScheduled) {
+		// wait for the daemonset controller to replace the deleted pods
+		return nil
+	}
+
+	if len(outdated) == 0 {
+		status.Phase = v1.CanaryRolloutSucceeded
+		status.Message = fmt.Sprintf("%s was updated on all %d nodes", daemonSet.Name, len(updated))
+		log.Log.Infof("Canary rollout of daemonset %v succeeded", daemonSet.Name)
+		// restore the update strategy of the target install strategy
+		return syncDaemonSet(kv, daemonSet, stores, clientset, expectations)
+	}
+
+	if status.BatchStartTime == nil {
+		status.BatchStartTime = &now
+	} else if int32(len(updated)) >= status.UpdatedNodes {
+		verificationPeriod := defaultCanaryVerificationPeriod
+		if kv.Spec.CanaryRollout.VerificationPeriod != nil {
+			verificationPeriod = kv.Spec.CanaryRollout.VerificationPeriod.Duration
+		}
+		if remaining := verificationPeriod - now.Sub(status.BatchStartTime.Time); remaining > 0 {
+			queue.AddAfter(kvkey, remaining)
+			return nil
+		}
+		// UpdatedNodes counts the nodes of all batches so far, so growing it
+		// to 2n+1 doubles the size of each batch: 1, 2, 4, ... nodes
+		status.UpdatedNodes = 2*status.UpdatedNodes + 1
+		status.BatchStartTime = &now
+	}
+
+	// delete the outdated pods in a stable order, so that a stale pod cache
+	// doesn't cause pods outside of the batch to be deleted
+	sort.Slice(outdated, func(i, j int) bool {
+		return outdated[i].Name < outdated[j].Name
+	})
+	for i := 0; i < int(status.UpdatedNodes)-len(updated) && i < len(outdated); i++ {
+		pod := outdated[i]
+		err := clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete outdated pod %s of daemonset %s: %v", pod.Name, daemonSet.Name, err)
+		}
+		log.Log.V(2).Infof("Deleted outdated pod %v of daemonset %v", pod.Name, daemonSet.Name)
+	}
+	status.Message = fmt.Sprintf("Updating %s on %d of %d nodes", daemonSet.Name, status.UpdatedNodes, len(updated)+len(outdated))
+
+	return nil
+}
+
+// podRestarts sums up the restarts of the containers of a pod
+func podRestarts(pod *corev1.Pod) int32 {
+	restarts := int32(0)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+	return restarts
+}
+
+// rollbackDaemonSetCanary restores the daemonset of the previous install strategy,
+// whose rolling update strategy lets the daemonset controller replace the updated pods
+func rollbackDaemonSetCanary(kv *v1.KubeVirt,
+	daemonSet *appsv1.DaemonSet,
+	prevStrategy *InstallStrategy,
+	clientset kubecli.KubevirtClient,
+	reason string) error {
+
+	status := kv.Status.CanaryRollout
+
+	var prevDaemonSet *appsv1.DaemonSet
+	if prevStrategy != nil {
+		for _, ds := range prevStrategy.daemonSets {
+			if ds.Name == daemonSet.Name {
+				prevDaemonSet = ds.DeepCopy()
+			}
+		}
+	}
+	if prevDaemonSet == nil {
+		// without the previous strategy the rollout can only be halted
+		status.Message = fmt.Sprintf("The updated %s pods are unhealthy, but the previous version is unknown", daemonSet.Name)
+		return nil
+	}
+
+	version := kv.Status.ObservedKubeVirtVersion
+	imageRegistry := kv.Status.ObservedKubeVirtRegistry
+	id := kv.Status.ObservedDeploymentID
+	injectOperatorMetadata(kv, &prevDaemonSet.ObjectMeta, version, imageRegistry, id)
+	injectOperatorMetadata(kv, &prevDaemonSet.Spec.Template.ObjectMeta, version, imageRegistry, id)
+
+	err := patchDaemonSet(kv, prevDaemonSet, clientset)
+	if err != nil {
+		return err
+	}
+
+	status.Phase = v1.CanaryRolloutRolledBack
+	status.Message = fmt.Sprintf("%s was rolled back to version %s, %s", daemonSet.Name, version, reason)
+	log.Log.Warningf("Canary rollout of daemonset %v failed, rolled back to version %v", daemonSet.Name, version)
+	return nil
+}
@@ -0,0 +1,283 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package installstrategy
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+var _ = Describe("Canary rollout", func() {
+
+	const prevVersion = "0.9"
+
+	var ctrl *gomock.Controller
+	var clientset *kubecli.MockKubevirtClient
+	var kubeClient *fake.Clientset
+	var queue workqueue.RateLimitingInterface
+	var stores util.Stores
+	var expectations *util.Expectations
+	var kv *v1.KubeVirt
+	var daemonSet *appsv1.DaemonSet
+	var prevStrategy *InstallStrategy
+	var patches []string
+
+	addPod := func(name string, upToDate bool, ready bool) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "virt-handler-" + name,
+				Namespace:   Namespace,
+				Annotations: map[string]string{},
+			},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Ready: ready}},
+			},
+		}
+		if upToDate {
+			injectOperatorMetadata(kv, &pod.ObjectMeta, Version, Registry, Id)
+		} else {
+			injectOperatorMetadata(kv, &pod.ObjectMeta, prevVersion, Registry, "41")
+		}
+		Expect(stores.InfrastructurePodCache.Add(pod)).To(Succeed())
+		_, err := kubeClient.CoreV1().Pods(Namespace).Create(pod)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	podFromCache := func(name string) *corev1.Pod {
+		obj, exists, err := stores.InfrastructurePodCache.GetByKey(Namespace + "/virt-handler-" + name)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		return obj.(*corev1.Pod)
+	}
+
+	podExists := func(name string) bool {
+		_, err := kubeClient.CoreV1().Pods(Namespace).Get("virt-handler-"+name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	sync := func() {
+		Expect(syncDaemonSetCanary(queue, kv, daemonSet, prevStrategy, stores, clientset, expectations)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubeClient = fake.NewSimpleClientset()
+		clientset = kubecli.NewMockKubevirtClient(ctrl)
+		clientset.EXPECT().AppsV1().Return(kubeClient.AppsV1()).AnyTimes()
+		clientset.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+
+		patches = nil
+		kubeClient.Fake.PrependReactor("patch", "daemonsets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+			patches = append(patches, string(action.(testing.PatchAction).GetPatch()))
+			return true, nil, nil
+		})
+
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		stores = util.Stores{}
+		stores.DaemonSetCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		stores.InfrastructurePodCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		expectations = &util.Expectations{}
+		expectations.DaemonSet = controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("DaemonSet"))
+
+		kv = &v1.KubeVirt{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: Namespace},
+			Spec: v1.KubeVirtSpec{
+				CanaryRollout: &v1.KubeVirtCanaryRollout{},
+			},
+			Status: v1.KubeVirtStatus{
+				TargetKubeVirtVersion:    Version,
+				TargetKubeVirtRegistry:   Registry,
+				TargetDeploymentID:       Id,
+				ObservedKubeVirtVersion:  prevVersion,
+				ObservedKubeVirtRegistry: Registry,
+				ObservedDeploymentID:     "41",
+			},
+		}
+
+		daemonSet = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "virt-handler", Namespace: Namespace},
+			Spec: appsv1.DaemonSetSpec{
+				UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType},
+			},
+		}
+		prevStrategy = &InstallStrategy{daemonSets: []*appsv1.DaemonSet{daemonSet.DeepCopy()}}
+
+		// the daemonset was already updated to the target version
+		cachedDaemonSet := daemonSet.DeepCopy()
+		cachedDaemonSet.Spec.UpdateStrategy.Type = appsv1.OnDeleteDaemonSetStrategyType
+		cachedDaemonSet.Status.DesiredNumberScheduled = 3
+		injectOperatorMetadata(kv, &cachedDaemonSet.ObjectMeta, Version, Registry, Id)
+		Expect(stores.DaemonSetCache.Add(cachedDaemonSet)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		queue.ShutDown()
+		ctrl.Finish()
+	})
+
+	It("should update a single canary node first", func() {
+		addPod("a", false, true)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		sync()
+
+		Expect(podExists("a")).To(BeFalse())
+		Expect(podExists("b")).To(BeTrue())
+		Expect(podExists("c")).To(BeTrue())
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutProgressing))
+		Expect(kv.Status.CanaryRollout.DeploymentID).To(Equal(Id))
+		Expect(kv.Status.CanaryRollout.UpdatedNodes).To(Equal(int32(1)))
+		Expect(kv.Status.CanaryRollout.BatchStartTime).ToNot(BeNil())
+		Expect(patches).To(BeEmpty())
+	})
+
+	It("should update the next batch once the verification period passed", func() {
+		addPod("a", true, true)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		startTime := metav1.Now()
+		kv.Status.CanaryRollout = &v1.KubeVirtCanaryRolloutStatus{
+			DeploymentID:   Id,
+			Phase:          v1.CanaryRolloutProgressing,
+			UpdatedNodes:   1,
+			BatchStartTime: &startTime,
+		}
+
+		sync()
+		Expect(podExists("b")).To(BeTrue())
+		Expect(podExists("c")).To(BeTrue())
+		Expect(kv.Status.CanaryRollout.UpdatedNodes).To(Equal(int32(1)))
+
+		startTime = metav1.NewTime(time.Now().Add(-defaultCanaryVerificationPeriod))
+		kv.Status.CanaryRollout.BatchStartTime = &startTime
+		sync()
+		Expect(podExists("b")).To(BeFalse())
+		Expect(podExists("c")).To(BeFalse())
+		Expect(kv.Status.CanaryRollout.UpdatedNodes).To(Equal(int32(3)))
+	})
+
+	It("should restore the update strategy once all nodes are updated", func() {
+		addPod("a", true, true)
+		addPod("b", true, true)
+		addPod("c", true, true)
+		sync()
+
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutSucceeded))
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0]).To(ContainSubstring("/spec/updateStrategy"))
+		Expect(patches[0]).To(ContainSubstring(string(appsv1.RollingUpdateDaemonSetStrategyType)))
+	})
+
+	It("should wait for unhealthy pods until the failure timeout", func() {
+		addPod("a", true, false)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		sync()
+
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutProgressing))
+		Expect(kv.Status.CanaryRollout.UnhealthySince).ToNot(BeNil())
+		Expect(podExists("b")).To(BeTrue())
+		Expect(patches).To(BeEmpty())
+	})
+
+	It("should roll back right away once an updated pod keeps crashing", func() {
+		addPod("a", true, true)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		pod := podFromCache("a")
+		pod.Status.ContainerStatuses[0].RestartCount = canaryMaxRestarts + 1
+		startTime := metav1.Now()
+		kv.Status.CanaryRollout = &v1.KubeVirtCanaryRolloutStatus{
+			DeploymentID:   Id,
+			Phase:          v1.CanaryRolloutProgressing,
+			UpdatedNodes:   1,
+			BatchStartTime: &startTime,
+		}
+		sync()
+
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutRolledBack))
+		Expect(kv.Status.CanaryRollout.Message).To(ContainSubstring("the updated pod virt-handler-a restarted 3 times"))
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0]).To(ContainSubstring(prevVersion))
+	})
+
+	It("should tolerate a few restarts of the updated pods", func() {
+		addPod("a", true, true)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		podFromCache("a").Status.ContainerStatuses[0].RestartCount = canaryMaxRestarts
+		sync()
+
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutProgressing))
+		Expect(patches).To(BeEmpty())
+	})
+
+	It("should roll back once the updated pods are unhealthy for longer than the failure timeout", func() {
+		addPod("a", true, false)
+		addPod("b", false, true)
+		addPod("c", false, true)
+		unhealthySince := metav1.NewTime(time.Now().Add(-defaultCanaryFailureTimeout))
+		kv.Status.CanaryRollout = &v1.KubeVirtCanaryRolloutStatus{
+			DeploymentID:   Id,
+			Phase:          v1.CanaryRolloutProgressing,
+			UpdatedNodes:   1,
+			UnhealthySince: &unhealthySince,
+		}
+		sync()
+
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutRolledBack))
+		Expect(kv.Status.CanaryRollout.Message).To(ContainSubstring("the updated pods were unhealthy since"))
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0]).To(ContainSubstring(prevVersion))
+		Expect(patches[0]).To(ContainSubstring(string(appsv1.RollingUpdateDaemonSetStrategyType)))
+
+		// the rolled back daemonset is kept until another version is targeted
+		sync()
+		Expect(patches).To(HaveLen(1))
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutRolledBack))
+
+		kv.Status.TargetDeploymentID = "43"
+		sync()
+		Expect(kv.Status.CanaryRollout.Phase).To(Equal(v1.CanaryRolloutProgressing))
+		Expect(kv.Status.CanaryRollout.DeploymentID).To(Equal("43"))
+	})
+})
@@ -44,6 +44,7 @@ import (
 	"k8s.io/kube-aggregator/pkg/apis/apiregistration/v1beta1"
 
 	"github.com/blang/semver"
+	"github.com/prometheus/client_golang/prometheus"
 
 	v1 "kubevirt.io/client-go/api/v1"
 	"kubevirt.io/client-go/kubecli"
@@ -59,6 +60,20 @@ import (
 const Duration7d = time.Hour * 24 * 7
 const Duration1d = time.Hour * 24
 
+var (
+	certificateExpirationGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubevirt_certificate_expiration_timestamp_seconds",
+			Help: "Expiration time of the certificates managed by virt-operator, in seconds since the epoch",
+		},
+		[]string{"secret"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(certificateExpirationGauge)
+}
+
 type APIServiceInterface interface {
 	Get(name string, options metav1.GetOptions) (*v1beta1.APIService, error)
 	Create(*v1beta1.APIService) (*v1beta1.APIService, error)
@@ -192,27 +207,25 @@ func syncDaemonSet(kv *v1.KubeVirt,
 		}
 	} else if !objectMatchesVersion(&cachedDaemonSet.ObjectMeta, imageTag, imageRegistry, id) {
 		// Patch if old version
-		var ops []string
-
-		// Add Labels and Annotations Patches
-		labelAnnotationPatch, err := createLabelsAndAnnotationsPatch(&daemonSet.ObjectMeta)
+		err = patchDaemonSet(kv, daemonSet, clientset)
 		if err != nil {
 			return err
 		}
-		ops = append(ops, labelAnnotationPatch...)
+		log.Log.V(2).Infof("daemonset %v updated", daemonSet.GetName())
 
-		// Add Spec Patch
-		newSpec, err := json.Marshal(daemonSet.Spec)
+	} else if cachedDaemonSet.Spec.UpdateStrategy.Type != daemonSet.Spec.UpdateStrategy.Type {
+		// The canary rollout switches the update strategy of up-to-date daemonsets
+		newStrategy, err := json.Marshal(daemonSet.Spec.UpdateStrategy)
 		if err != nil {
 			return err
 		}
-		ops = append(ops, fmt.Sprintf(`{ "op": "replace", "path": "/spec", "value": %s }`, string(newSpec)))
+		ops := []string{fmt.Sprintf(`{ "op": "replace", "path": "/spec/updateStrategy", "value": %s }`, string(newStrategy))}
 
 		_, err = apps.DaemonSets(kv.Namespace).Patch(daemonSet.Name, types.JSONPatchType, generatePatchBytes(ops))
 		if err != nil {
-			return fmt.Errorf("unable to patch daemonset %+v: %v", daemonSet, err)
+			return fmt.Errorf("unable to patch the update strategy of daemonset %+v: %v", daemonSet, err)
 		}
-		log.Log.V(2).Infof("daemonset %v updated", daemonSet.GetName())
+		log.Log.V(2).Infof("daemonset %v update strategy changed to %v", daemonSet.GetName(), daemonSet.Spec.UpdateStrategy.Type)
 
 	} else {
 		log.Log.V(4).Infof("daemonset %v is up-to-date", daemonSet.GetName())
@@ -220,6 +233,30 @@ func syncDaemonSet(kv *v1.KubeVirt,
 	return nil
 }
 
+func patchDaemonSet(kv *v1.KubeVirt, daemonSet *appsv1.DaemonSet, clientset kubecli.KubevirtClient) error {
+	var ops []string
+
+	// Add Labels and Annotations Patches
+	labelAnnotationPatch, err := createLabelsAndAnnotationsPatch(&daemonSet.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, labelAnnotationPatch...)
+
+	// Add Spec Patch
+	newSpec, err := json.Marshal(daemonSet.Spec)
+	if err != nil {
+		return err
+	}
+	ops = append(ops, fmt.Sprintf(`{ "op": "replace", "path": "/spec", "value": %s }`, string(newSpec)))
+
+	_, err = clientset.AppsV1().DaemonSets(kv.Namespace).Patch(daemonSet.Name, types.JSONPatchType, generatePatchBytes(ops))
+	if err != nil {
+		return fmt.Errorf("unable to patch daemonset %+v: %v", daemonSet, err)
+	}
+	return nil
+}
+
 func syncDeployment(kv *v1.KubeVirt,
 	deployment *appsv1.Deployment,
 	stores util.Stores,
@@ -1706,6 +1743,7 @@ func createOrUpdateCACertificateSecret(
 	clientset kubecli.KubevirtClient,
 	expectations *util.Expectations,
 	duration *metav1.Duration,
+	renewBefore *metav1.Duration,
 ) (caCert *tls.Certificate, err error) {
 
 	for _, secret := range targetStrategy.certificateSecrets {
@@ -1714,7 +1752,7 @@ func createOrUpdateCACertificateSecret(
 		if secret.Name != components.KubeVirtCASecretName {
 			continue
 		}
-		caCert, err := createOrUpdateCertificateSecret(queue, kv, stores, clientset, expectations, nil, secret, duration)
+		caCert, err := createOrUpdateCertificateSecret(queue, kv, stores, clientset, expectations, nil, secret, duration, renewBefore)
 		if err != nil {
 			return nil, err
 		}
@@ -1732,6 +1770,7 @@ func createOrUpdateCertificateSecret(
 	ca *tls.Certificate,
 	secret *corev1.Secret,
 	duration *metav1.Duration,
+	renewBefore *metav1.Duration,
 ) (*tls.Certificate, error) {
 	var cachedSecret *corev1.Secret
 	secret = secret.DeepCopy()
@@ -1773,8 +1812,8 @@ func createOrUpdateCertificateSecret(
 		} else if cachedSecret.Annotations["kubevirt.io/duration"] != duration.String() {
 			rotateCertificate = true
 		} else {
-			rotationTime := components.NextRotationDeadline(crt, ca, duration)
-			// We update the certificate if it has passed 80 percent of its lifetime
+			rotationTime := components.NextRotationDeadline(crt, ca, duration, renewBefore)
+			// We update the certificate if it is about to expire
 			if rotationTime.Before(time.Now()) {
 				rotateCertificate = true
 			}
@@ -1794,8 +1833,9 @@ func createOrUpdateCertificateSecret(
 		log.DefaultLogger().Reason(err).Infof("Failed to load certificate from secret %s.", secret.Name)
 		return nil, err
 	}
+	certificateExpirationGauge.WithLabelValues(secret.Name).Set(float64(crt.Leaf.NotAfter.Unix()))
 	// we need to ensure that we revisit certificates before they expire
-	wakeupDeadline := components.NextRotationDeadline(crt, ca, duration).Sub(time.Now())
+	wakeupDeadline := components.NextRotationDeadline(crt, ca, duration, renewBefore).Sub(time.Now())
 	queue.AddAfter(kvkey, wakeupDeadline)
 
 	injectOperatorMetadata(kv, &secret.ObjectMeta, version, imageRegistry, id)
@@ -1847,6 +1887,7 @@ func createOrUpdateCertificateSecrets(
 	expectations *util.Expectations,
 	caCert *tls.Certificate,
 	duration *metav1.Duration,
+	renewBefore *metav1.Duration,
 ) error {
 
 	for _, secret := range targetStrategy.certificateSecrets {
@@ -1855,8 +1896,12 @@ func createOrUpdateCertificateSecrets(
 		if secret.Name == components.KubeVirtCASecretName {
 			continue
 		}
+		// The VNC token key is no certificate
+		if secret.Name == components.VirtApiVNCTokenKeySecretName {
+			continue
+		}
 
-		_, err := createOrUpdateCertificateSecret(queue, kv, stores, clientset, expectations, caCert, secret, duration)
+		_, err := createOrUpdateCertificateSecret(queue, kv, stores, clientset, expectations, caCert, secret, duration, renewBefore)
 		if err != nil {
 			return err
 		}
@@ -1864,6 +1909,142 @@ func createOrUpdateCertificateSecrets(
 	return nil
 }
 
+// createOrUpdateVNCTokenKeySecret creates the secret with the key virt-api signs VNC tokens with.
+// The key is not rotated, so that the tokens stay valid while virt-api is updated.
+func createOrUpdateVNCTokenKeySecret(
+	kv *v1.KubeVirt,
+	targetStrategy *InstallStrategy,
+	stores util.Stores,
+	clientset kubecli.KubevirtClient,
+	expectations *util.Expectations,
+) error {
+
+	version := kv.Status.TargetKubeVirtVersion
+	imageRegistry := kv.Status.TargetKubeVirtRegistry
+	id := kv.Status.TargetDeploymentID
+
+	kvkey, err := controller.KeyFunc(kv)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range targetStrategy.certificateSecrets {
+
+		// Only work on the VNC token key secret
+		if secret.Name != components.VirtApiVNCTokenKeySecretName {
+			continue
+		}
+		secret = secret.DeepCopy()
+
+		obj, exists, _ := stores.SecretCache.Get(secret)
+		var cachedSecret *corev1.Secret
+		regenerateKey := !exists
+		if exists {
+			cachedSecret = obj.(*corev1.Secret)
+			if err := components.ValidateVNCTokenKeySecret(cachedSecret); err != nil {
+				log.DefaultLogger().Reason(err).Infof("Failed to load the VNC token key from secret %s, will regenerate it.", secret.Name)
+				regenerateKey = true
+			}
+		}
+
+		if regenerateKey {
+			if err := components.PopulateVNCTokenKeySecret(secret); err != nil {
+				return err
+			}
+		} else {
+			secret.Data = cachedSecret.Data
+		}
+
+		injectOperatorMetadata(kv, &secret.ObjectMeta, version, imageRegistry, id)
+		if !exists {
+			expectations.Secrets.RaiseExpectations(kvkey, 1, 0)
+			_, err := clientset.CoreV1().Secrets(secret.Namespace).Create(secret)
+			if err != nil {
+				expectations.Secrets.LowerExpectations(kvkey, 1, 0)
+				return fmt.Errorf("unable to create secret %+v: %v", secret, err)
+			}
+		} else if !objectMatchesVersion(&cachedSecret.ObjectMeta, version, imageRegistry, id) || regenerateKey {
+			var ops []string
+
+			labelAnnotationPatch, err := createLabelsAndAnnotationsPatch(&secret.ObjectMeta)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, labelAnnotationPatch...)
+
+			data, err := json.Marshal(secret.Data)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, fmt.Sprintf(`{ "op": "replace", "path": "/data", "value": %s }`, string(data)))
+
+			_, err = clientset.CoreV1().Secrets(secret.Namespace).Patch(secret.Name, types.JSONPatchType, generatePatchBytes(ops))
+			if err != nil {
+				return fmt.Errorf("unable to patch secret %+v: %v", secret, err)
+			}
+			log.Log.V(2).Infof("secret %v updated", secret.GetName())
+		} else {
+			log.Log.V(4).Infof("secret %v is up-to-date", secret.GetName())
+		}
+	}
+	return nil
+}
+
+// getCertificateRotationConfig returns the lifetime and the renewal time of the
+// CA and of the certificates signed by it, together with the time old CA
+// certificates are kept in the CA bundle. The deprecated intervals are only
+// taken into account if the corresponding new fields are not set.
+func getCertificateRotationConfig(kv *v1.KubeVirt) (caConfig *v1.CertConfig, certConfig *v1.CertConfig, caOverlapTime *metav1.Duration) {
+	caConfig = &v1.CertConfig{Duration: &metav1.Duration{Duration: Duration7d}}
+	certConfig = &v1.CertConfig{Duration: &metav1.Duration{Duration: Duration1d}}
+	caOverlapTime = &metav1.Duration{Duration: Duration1d}
+
+	selfSigned := kv.Spec.CertificateRotationStrategy.SelfSigned
+	if selfSigned == nil {
+		return
+	}
+
+	if selfSigned.CARotateInterval != nil {
+		caConfig.Duration = selfSigned.CARotateInterval
+	}
+	if selfSigned.CAOverlapInterval != nil {
+		caOverlapTime = selfSigned.CAOverlapInterval
+	}
+	if selfSigned.CertRotateInterval != nil {
+		certConfig.Duration = selfSigned.CertRotateInterval
+	}
+
+	if selfSigned.CA != nil {
+		if selfSigned.CA.Duration != nil {
+			caConfig.Duration = selfSigned.CA.Duration
+		}
+		if selfSigned.CA.RenewBefore != nil {
+			caConfig.RenewBefore = selfSigned.CA.RenewBefore
+		}
+	}
+	if selfSigned.Server != nil {
+		if selfSigned.Server.Duration != nil {
+			certConfig.Duration = selfSigned.Server.Duration
+		}
+		if selfSigned.Server.RenewBefore != nil {
+			certConfig.RenewBefore = selfSigned.Server.RenewBefore
+		}
+	}
+
+	// a renewal time beyond the lifetime would rotate the certificates on every sync
+	for name, config := range map[string]*v1.CertConfig{"CA": caConfig, "server": certConfig} {
+		if config.RenewBefore != nil && config.RenewBefore.Duration >= config.Duration.Duration {
+			log.Log.Warningf("Ignoring the %s certificate renewBefore %v, it is not shorter than the duration %v", name, config.RenewBefore.Duration, config.Duration.Duration)
+			config.RenewBefore = nil
+		}
+	}
+	if caConfig.RenewBefore != nil {
+		// the old CA stays valid for renewBefore after the new one was issued
+		caOverlapTime = caConfig.RenewBefore
+	}
+	return
+}
+
 func createOrUpdateService(kv *v1.KubeVirt,
 	targetStrategy *InstallStrategy,
 	stores util.Stores,
@@ -2443,23 +2624,10 @@ func SyncAll(queue workqueue.RateLimitingInterface, kv *v1.KubeVirt, prevStrateg
 		return false, err
 	}
 
-	caDuration := &metav1.Duration{Duration: Duration7d}
-	caOverlapTime := &metav1.Duration{Duration: Duration1d}
-	certDuration := &metav1.Duration{Duration: Duration1d}
-	if kv.Spec.CertificateRotationStrategy.SelfSigned != nil {
-		if kv.Spec.CertificateRotationStrategy.SelfSigned.CARotateInterval != nil {
-			caDuration = kv.Spec.CertificateRotationStrategy.SelfSigned.CARotateInterval
-		}
-		if kv.Spec.CertificateRotationStrategy.SelfSigned.CAOverlapInterval != nil {
-			caOverlapTime = kv.Spec.CertificateRotationStrategy.SelfSigned.CAOverlapInterval
-		}
-		if kv.Spec.CertificateRotationStrategy.SelfSigned.CertRotateInterval != nil {
-			certDuration = kv.Spec.CertificateRotationStrategy.SelfSigned.CertRotateInterval
-		}
-	}
+	caConfig, certConfig, caOverlapTime := getCertificateRotationConfig(kv)
 
 	// create/update CA Certificate secret
-	caCert, err := createOrUpdateCACertificateSecret(queue, kv, targetStrategy, stores, clientset, expectations, caDuration)
+	caCert, err := createOrUpdateCACertificateSecret(queue, kv, targetStrategy, stores, clientset, expectations, caConfig.Duration, caConfig.RenewBefore)
 	if err != nil {
 		return false, err
 	}
@@ -2471,7 +2639,13 @@ func SyncAll(queue workqueue.RateLimitingInterface, kv *v1.KubeVirt, prevStrateg
 	}
 
 	// create/update Certificate secrets
-	err = createOrUpdateCertificateSecrets(queue, kv, targetStrategy, stores, clientset, expectations, caCert, certDuration)
+	err = createOrUpdateCertificateSecrets(queue, kv, targetStrategy, stores, clientset, expectations, caCert, certConfig.Duration, certConfig.RenewBefore)
+	if err != nil {
+		return false, err
+	}
+
+	// create/update the VNC token key secret
+	err = createOrUpdateVNCTokenKeySecret(kv, targetStrategy, stores, clientset, expectations)
 	if err != nil {
 		return false, err
 	}
@@ -2525,6 +2699,10 @@ func SyncAll(queue workqueue.RateLimitingInterface, kv *v1.KubeVirt, prevStrateg
 		}
 	}
 
+	if kv.Spec.CanaryRollout == nil {
+		kv.Status.CanaryRollout = nil
+	}
+
 	if takeUpdatePath {
 		// UPDATE PATH IS
 		// 1. daemonsets - ensures all compute nodes are updated to handle new features
@@ -2534,7 +2712,11 @@ func SyncAll(queue workqueue.RateLimitingInterface, kv *v1.KubeVirt, prevStrateg
 
 		// create/update Daemonsets
 		for _, daemonSet := range targetStrategy.daemonSets {
-			err := syncDaemonSet(kv, daemonSet, stores, clientset, expectations)
+			if kv.Spec.CanaryRollout != nil {
+				err = syncDaemonSetCanary(queue, kv, daemonSet, prevStrategy, stores, clientset, expectations)
+			} else {
+				err = syncDaemonSet(kv, daemonSet, stores, clientset, expectations)
+			}
 			if err != nil {
 				return false, err
 			}
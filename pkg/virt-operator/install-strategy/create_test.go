@@ -3,6 +3,7 @@ package installstrategy
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/golang/mock/gomock"
@@ -276,4 +277,52 @@ var _ = Describe("Create", func() {
 		})
 	})
 
+	Context("on calling getCertificateRotationConfig", func() {
+
+		duration := func(d time.Duration) *v12.Duration {
+			return &v12.Duration{Duration: d}
+		}
+
+		It("should default to a weekly CA and daily certificates", func() {
+			caConfig, certConfig, caOverlapTime := getCertificateRotationConfig(&v1.KubeVirt{})
+			Expect(caConfig.Duration.Duration).To(Equal(Duration7d))
+			Expect(caConfig.RenewBefore).To(BeNil())
+			Expect(certConfig.Duration.Duration).To(Equal(Duration1d))
+			Expect(certConfig.RenewBefore).To(BeNil())
+			Expect(caOverlapTime.Duration).To(Equal(Duration1d))
+		})
+
+		It("should prefer the certificate configs over the deprecated intervals", func() {
+			kv := &v1.KubeVirt{Spec: v1.KubeVirtSpec{CertificateRotationStrategy: v1.KubeVirtCertificateRotateStrategy{
+				SelfSigned: &v1.KubeVirtSelfSignConfiguration{
+					CARotateInterval:   duration(time.Hour),
+					CertRotateInterval: duration(time.Hour),
+					CAOverlapInterval:  duration(time.Hour),
+					CA:                 &v1.CertConfig{Duration: duration(48 * time.Hour), RenewBefore: duration(12 * time.Hour)},
+					Server:             &v1.CertConfig{Duration: duration(24 * time.Hour), RenewBefore: duration(6 * time.Hour)},
+				},
+			}}}
+			caConfig, certConfig, caOverlapTime := getCertificateRotationConfig(kv)
+			Expect(caConfig.Duration.Duration).To(Equal(48 * time.Hour))
+			Expect(caConfig.RenewBefore.Duration).To(Equal(12 * time.Hour))
+			Expect(certConfig.Duration.Duration).To(Equal(24 * time.Hour))
+			Expect(certConfig.RenewBefore.Duration).To(Equal(6 * time.Hour))
+			Expect(caOverlapTime.Duration).To(Equal(12 * time.Hour))
+		})
+
+		It("should ignore a renewBefore which is not shorter than the duration", func() {
+			kv := &v1.KubeVirt{Spec: v1.KubeVirtSpec{CertificateRotationStrategy: v1.KubeVirtCertificateRotateStrategy{
+				SelfSigned: &v1.KubeVirtSelfSignConfiguration{
+					CAOverlapInterval: duration(time.Hour),
+					CA:                &v1.CertConfig{RenewBefore: duration(Duration7d)},
+					Server:            &v1.CertConfig{Duration: duration(time.Hour), RenewBefore: duration(2 * time.Hour)},
+				},
+			}}}
+			caConfig, certConfig, caOverlapTime := getCertificateRotationConfig(kv)
+			Expect(caConfig.RenewBefore).To(BeNil())
+			Expect(certConfig.RenewBefore).To(BeNil())
+			Expect(caOverlapTime.Duration).To(Equal(time.Hour))
+		})
+	})
+
 })
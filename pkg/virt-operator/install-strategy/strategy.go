@@ -227,6 +227,11 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 	strategy.crds = append(strategy.crds, components.NewReplicaSetCrd())
 	strategy.crds = append(strategy.crds, components.NewVirtualMachineCrd())
 	strategy.crds = append(strategy.crds, components.NewVirtualMachineInstanceMigrationCrd())
+	strategy.crds = append(strategy.crds, components.NewConformanceRunCrd())
+	strategy.crds = append(strategy.crds, components.NewVirtualMachineExportCrd())
+	strategy.crds = append(strategy.crds, components.NewVirtualMachineBackupCrd())
+	strategy.crds = append(strategy.crds, components.NewVirtualMachineInstancetypeCrd())
+	strategy.crds = append(strategy.crds, components.NewVirtualMachinePreferenceCrd())
 	strategy.crds = append(strategy.crds, components.NewVirtualMachineSnapshotCrd())
 	strategy.crds = append(strategy.crds, components.NewVirtualMachineSnapshotContentCrd())
 
@@ -294,7 +299,7 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 
 	strategy.configMaps = append(strategy.configMaps, components.NewKubeVirtCAConfigMap(operatorNamespace))
 
-	handler, err := components.NewHandlerDaemonSet(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
+	handler, err := components.NewHandlerDaemonSet(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetLauncherVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
 	if err != nil {
 		return nil, fmt.Errorf("error generating virt-handler deployment %v", err)
 	}
@@ -304,6 +309,7 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 	strategy.apiServices = components.NewVirtAPIAPIServices(config.GetNamespace())
 	strategy.certificateSecrets = components.NewCertSecrets(config.GetNamespace(), operatorNamespace)
 	strategy.certificateSecrets = append(strategy.certificateSecrets, components.NewCACertSecret(operatorNamespace))
+	strategy.certificateSecrets = append(strategy.certificateSecrets, components.NewVNCTokenKeySecret(config.GetNamespace()))
 	strategy.configMaps = append(strategy.configMaps, components.NewKubeVirtCAConfigMap(operatorNamespace))
 
 	return strategy, nil
@@ -1028,6 +1028,11 @@ func (c *KubeVirtController) syncDeployment(kv *v1.KubeVirt) error {
 		return err
 	}
 
+	// the update stops if virt-handler was rolled back by the canary rollout
+	if rollout := kv.Status.CanaryRollout; rollout != nil && rollout.Phase == v1.CanaryRolloutRolledBack {
+		util.UpdateConditionsCanaryRolledBack(kv)
+	}
+
 	// the entire sync can't always occur within a single control loop execution.
 	// when synced==true that means SyncAll() has completed and has nothing left to wait on.
 	if synced {
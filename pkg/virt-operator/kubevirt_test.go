@@ -142,8 +142,8 @@ var _ = Describe("KubeVirt Operator", func() {
 	var totalDeletions int
 	var resourceChanges map[string]map[string]int
 
-	resourceCount := 51
-	patchCount := 32
+	resourceCount := 57
+	patchCount := 38
 	updateCount := 20
 
 	deleteFromCache := true
@@ -674,7 +674,7 @@ var _ = Describe("KubeVirt Operator", func() {
 		injectMetadata(&pod.ObjectMeta, config)
 		addPod(pod)
 
-		handler, _ := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
+		handler, _ := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetLauncherVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
 		pod = &k8sv1.Pod{
 			ObjectMeta: handler.Spec.Template.ObjectMeta,
 			Spec:       handler.Spec.Template.Spec,
@@ -835,6 +835,11 @@ var _ = Describe("KubeVirt Operator", func() {
 		all = append(all, components.NewReplicaSetCrd())
 		all = append(all, components.NewVirtualMachineCrd())
 		all = append(all, components.NewVirtualMachineInstanceMigrationCrd())
+		all = append(all, components.NewConformanceRunCrd())
+		all = append(all, components.NewVirtualMachineExportCrd())
+		all = append(all, components.NewVirtualMachineBackupCrd())
+		all = append(all, components.NewVirtualMachineInstancetypeCrd())
+		all = append(all, components.NewVirtualMachinePreferenceCrd())
 		all = append(all, components.NewVirtualMachineSnapshotCrd())
 		all = append(all, components.NewVirtualMachineSnapshotContentCrd())
 		all = append(all, components.NewPrometheusRuleCR(config.GetNamespace()))
@@ -849,7 +854,7 @@ var _ = Describe("KubeVirt Operator", func() {
 		apiDeploymentPdb := components.NewPodDisruptionBudgetForDeployment(apiDeployment)
 		controller, _ := components.NewControllerDeployment(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetControllerVersion(), config.GetLauncherVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
 		controllerPdb := components.NewPodDisruptionBudgetForDeployment(controller)
-		handler, _ := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
+		handler, _ := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetLauncherVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
 		all = append(all, apiDeployment, apiDeploymentPdb, controller, controllerPdb, handler)
 
 		all = append(all, rbac.GetAllServiceMonitor(NAMESPACE, config.GetMonitorNamespace(), config.GetMonitorServiceAccount())...)
@@ -894,6 +899,10 @@ var _ = Describe("KubeVirt Operator", func() {
 			all = append(all, secret)
 		}
 
+		vncTokenKeySecret := components.NewVNCTokenKeySecret(NAMESPACE)
+		components.PopulateVNCTokenKeySecret(vncTokenKeySecret)
+		all = append(all, vncTokenKeySecret)
+
 		for _, obj := range all {
 			if resource, ok := obj.(runtime.Object); ok {
 				addResource(resource, config)
@@ -1771,7 +1780,7 @@ var _ = Describe("KubeVirt Operator", func() {
 			envVal := rand.String(10)
 			config.PassthroughEnvVars = map[string]string{envKey: envVal}
 
-			handlerDaemonset, err := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
+			handlerDaemonset, err := components.NewHandlerDaemonSet(NAMESPACE, config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetLauncherVersion(), config.GetImagePullPolicy(), config.GetVerbosity(), config.GetExtraEnv())
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(handlerDaemonset.Spec.Template.Spec.Containers[0].Env).To(ContainElement(k8sv1.EnvVar{Name: envKey, Value: envVal}))
@@ -1923,7 +1932,7 @@ var _ = Describe("KubeVirt Operator", func() {
 			Expect(len(controller.stores.ClusterRoleBindingCache.List())).To(Equal(5))
 			Expect(len(controller.stores.RoleCache.List())).To(Equal(3))
 			Expect(len(controller.stores.RoleBindingCache.List())).To(Equal(3))
-			Expect(len(controller.stores.CrdCache.List())).To(Equal(7))
+			Expect(len(controller.stores.CrdCache.List())).To(Equal(12))
 			Expect(len(controller.stores.ServiceCache.List())).To(Equal(3))
 			Expect(len(controller.stores.DeploymentCache.List())).To(Equal(1))
 			Expect(len(controller.stores.DaemonSetCache.List())).To(Equal(0))
@@ -45,6 +45,7 @@ const (
 	ConditionReasonDeploying                = "DeploymentInProgress"
 	ConditionReasonUpdating                 = "UpdateInProgress"
 	ConditionReasonDeleting                 = "DeletionInProgress"
+	ConditionReasonCanaryRolledBack         = "CanaryRolledBack"
 )
 
 func UpdateConditionsDeploying(kv *virtv1.KubeVirt) {
@@ -70,6 +71,12 @@ func UpdateConditionsUpdating(kv *virtv1.KubeVirt) {
 	updateCondition(kv, virtv1.KubeVirtConditionDegraded, k8sv1.ConditionTrue, ConditionReasonUpdating, msg)
 }
 
+func UpdateConditionsCanaryRolledBack(kv *virtv1.KubeVirt) {
+	msg := kv.Status.CanaryRollout.Message
+	updateCondition(kv, virtv1.KubeVirtConditionProgressing, k8sv1.ConditionFalse, ConditionReasonCanaryRolledBack, msg)
+	updateCondition(kv, virtv1.KubeVirtConditionDegraded, k8sv1.ConditionTrue, ConditionReasonCanaryRolledBack, msg)
+}
+
 func UpdateConditionsCreated(kv *virtv1.KubeVirt) {
 	updateCondition(kv, virtv1.KubeVirtConditionCreated, k8sv1.ConditionTrue, ConditionReasonDeploymentCreated, "All resources were created.")
 }
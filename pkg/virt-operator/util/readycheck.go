@@ -59,12 +59,12 @@ func DaemonsetIsReady(kv *v1.KubeVirt, daemonset *appsv1.DaemonSet, stores Store
 				continue
 			}
 
-			if !podIsUpToDate(pod, kv) {
+			if !PodIsUpToDate(pod, kv) {
 				log.Log.Infof("DaemonSet %v waiting for out of date pods to terminate.", daemonset.Name)
 				return false
 			}
 
-			if podIsReady(pod) {
+			if PodIsReady(pod) {
 				podsReady++
 			}
 		}
@@ -105,12 +105,12 @@ func DeploymentIsReady(kv *v1.KubeVirt, deployment *appsv1.Deployment, stores St
 				continue
 			}
 
-			if !podIsUpToDate(pod, kv) {
+			if !PodIsUpToDate(pod, kv) {
 				log.Log.Infof("Deployment %v waiting for out of date pods to terminate.", deployment.Name)
 				return false
 			}
 
-			if podIsReady(pod) {
+			if PodIsReady(pod) {
 				podsReady++
 			}
 		}
@@ -134,7 +134,7 @@ func podHasNamePrefix(pod *k8sv1.Pod, namePrefix string) bool {
 	return false
 }
 
-func podIsUpToDate(pod *k8sv1.Pod, kv *v1.KubeVirt) bool {
+func PodIsUpToDate(pod *k8sv1.Pod, kv *v1.KubeVirt) bool {
 	if pod.Annotations == nil {
 		return false
 	}
@@ -157,7 +157,7 @@ func podIsUpToDate(pod *k8sv1.Pod, kv *v1.KubeVirt) bool {
 	return true
 }
 
-func podIsReady(pod *k8sv1.Pod) bool {
+func PodIsReady(pod *k8sv1.Pod) bool {
 	if pod.Status.Phase != k8sv1.PodRunning {
 		return false
 	}
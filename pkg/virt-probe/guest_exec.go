@@ -0,0 +1,111 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtprobe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+type guestExecCommand struct {
+	Execute   string      `json:"execute"`
+	Arguments interface{} `json:"arguments"`
+}
+
+type guestExecArguments struct {
+	Path          string   `json:"path"`
+	Arg           []string `json:"arg,omitempty"`
+	CaptureOutput bool     `json:"capture-output"`
+}
+
+type guestExecStatusArguments struct {
+	Pid int `json:"pid"`
+}
+
+type guestExecResult struct {
+	Return struct {
+		Pid int `json:"pid"`
+	} `json:"return"`
+}
+
+type guestExecStatusResult struct {
+	Return struct {
+		Exited   bool `json:"exited"`
+		ExitCode int  `json:"exitcode"`
+	} `json:"return"`
+}
+
+// GuestExec runs command inside the guest of domainName through the guest agent
+// and waits until it exited. It returns the exit code of the command.
+func GuestExec(conn cli.Connection, domainName string, command []string, timeout time.Duration, pollInterval time.Duration) (int, error) {
+	if len(command) == 0 {
+		return 0, fmt.Errorf("no command specified")
+	}
+
+	cmd, err := json.Marshal(guestExecCommand{
+		Execute: "guest-exec",
+		Arguments: guestExecArguments{
+			Path:          command[0],
+			Arg:           command[1:],
+			CaptureOutput: true,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	out, err := conn.QemuAgentCommand(string(cmd), domainName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute %q in the guest: %v", command[0], err)
+	}
+	execResult := guestExecResult{}
+	if err := json.Unmarshal([]byte(out), &execResult); err != nil {
+		return 0, fmt.Errorf("failed to parse the guest-exec result: %v", err)
+	}
+
+	statusCmd, err := json.Marshal(guestExecCommand{
+		Execute:   "guest-exec-status",
+		Arguments: guestExecStatusArguments{Pid: execResult.Return.Pid},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		out, err := conn.QemuAgentCommand(string(statusCmd), domainName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get the status of %q in the guest: %v", command[0], err)
+		}
+		statusResult := guestExecStatusResult{}
+		if err := json.Unmarshal([]byte(out), &statusResult); err != nil {
+			return 0, fmt.Errorf("failed to parse the guest-exec-status result: %v", err)
+		}
+		if statusResult.Return.Exited {
+			return statusResult.Return.ExitCode, nil
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("%q did not exit within %v", command[0], timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package virtprobe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/cli"
+)
+
+var _ = Describe("GuestExec", func() {
+
+	const domainName = "default_testvmi"
+	const execCommand = `{"execute":"guest-exec","arguments":{"path":"cat","arg":["/tmp/ready"],"capture-output":true}}`
+	const statusCommand = `{"execute":"guest-exec-status","arguments":{"pid":42}}`
+
+	var ctrl *gomock.Controller
+	var mockConn *cli.MockConnection
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockConn = cli.NewMockConnection(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return the exit code of the guest command", func() {
+		gomock.InOrder(
+			mockConn.EXPECT().QemuAgentCommand(execCommand, domainName).Return(`{"return":{"pid":42}}`, nil),
+			mockConn.EXPECT().QemuAgentCommand(statusCommand, domainName).Return(`{"return":{"exited":false}}`, nil),
+			mockConn.EXPECT().QemuAgentCommand(statusCommand, domainName).Return(`{"return":{"exited":true,"exitcode":3}}`, nil),
+		)
+
+		exitCode, err := GuestExec(mockConn, domainName, []string{"cat", "/tmp/ready"}, time.Second, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exitCode).To(Equal(3))
+	})
+
+	It("should fail if the guest agent is not reachable", func() {
+		mockConn.EXPECT().QemuAgentCommand(execCommand, domainName).Return("", fmt.Errorf("agent not connected"))
+
+		_, err := GuestExec(mockConn, domainName, []string{"cat", "/tmp/ready"}, time.Second, time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("agent not connected")))
+	})
+
+	It("should fail if the guest command does not exit in time", func() {
+		mockConn.EXPECT().QemuAgentCommand(execCommand, domainName).Return(`{"return":{"pid":42}}`, nil)
+		mockConn.EXPECT().QemuAgentCommand(statusCommand, domainName).Return(`{"return":{"exited":false}}`, nil).MinTimes(1)
+
+		_, err := GuestExec(mockConn, domainName, []string{"cat", "/tmp/ready"}, 10*time.Millisecond, time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("did not exit")))
+	})
+})
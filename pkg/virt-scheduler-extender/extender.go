@@ -0,0 +1,146 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package extender
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/log"
+	"kubevirt.io/kubevirt/pkg/util/utilization"
+)
+
+// MaxPriority is the highest score a scheduler extender may give a node
+const MaxPriority = 10
+
+// PrioritizePath is the path of the prioritize verb of the extender
+const PrioritizePath = "/prioritize"
+
+// ExtenderArgs is what the scheduler sends to the extender, the same as in
+// k8s.io/kube-scheduler/extender/v1. Without the node cache of the extender
+// enabled the scheduler sends the nodes, otherwise only their names.
+type ExtenderArgs struct {
+	Pod       *k8sv1.Pod      `json:"pod"`
+	Nodes     *k8sv1.NodeList `json:"nodes,omitempty"`
+	NodeNames *[]string       `json:"nodenames,omitempty"`
+}
+
+// HostPriority is the score of a single node
+type HostPriority struct {
+	Host  string `json:"host"`
+	Score int64  `json:"score"`
+}
+
+// HostPriorityList is the answer of the extender to the prioritize verb
+type HostPriorityList []HostPriority
+
+// Extender scores the nodes for virt-launcher pods by the utilization virt-handler
+// reports on them, so that VMIs land on the nodes whose VMIs wait the least for a
+// CPU and keep the least memory resident, instead of only the ones with the fewest
+// requests
+type Extender struct {
+	nodeStore cache.Store
+	// staleAfter is how old a report may be before it is ignored
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+func NewExtender(nodeStore cache.Store, staleAfter time.Duration) *Extender {
+	return &Extender{
+		nodeStore:  nodeStore,
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+}
+
+func (e *Extender) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the scheduler appends the verb to the URL prefix of the extender
+	if !strings.HasSuffix(r.URL.Path, PrioritizePath) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	args := &ExtenderArgs{}
+	if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(e.Prioritize(args)); err != nil {
+		log.Log.Reason(err).Error("Failed to send the node scores")
+	}
+}
+
+// Prioritize scores the nodes of the arguments. Pods other than virt-launcher pods
+// get the same score on every node, so that only the other priorities decide.
+func (e *Extender) Prioritize(args *ExtenderArgs) HostPriorityList {
+	var names []string
+	if args.NodeNames != nil {
+		names = *args.NodeNames
+	} else if args.Nodes != nil {
+		for _, node := range args.Nodes.Items {
+			names = append(names, node.Name)
+		}
+	}
+
+	isLauncher := args.Pod != nil && args.Pod.Labels[v1.AppLabel] == "virt-launcher"
+	priorities := HostPriorityList{}
+	for _, name := range names {
+		score := int64(0)
+		if isLauncher {
+			score = e.score(name)
+		}
+		priorities = append(priorities, HostPriority{Host: name, Score: score})
+	}
+	return priorities
+}
+
+// score is the share of the node which is not loaded, in the range of the scheduler.
+// Nodes without a recent report are neither preferred nor avoided.
+func (e *Extender) score(name string) int64 {
+	neutral := int64(MaxPriority / 2)
+
+	obj, exists, err := e.nodeStore.GetByKey(name)
+	if err != nil || !exists {
+		return neutral
+	}
+	node := obj.(*k8sv1.Node)
+	nodeUtilization, err := utilization.FromNode(node)
+	if err != nil {
+		log.Log.Reason(err).Warning("Ignoring the utilization of a node")
+		return neutral
+	}
+	if nodeUtilization == nil || e.now().Sub(nodeUtilization.Timestamp.Time) > e.staleAfter {
+		return neutral
+	}
+	return int64(math.Round(MaxPriority * (100 - nodeUtilization.Load(node)) / 100))
+}
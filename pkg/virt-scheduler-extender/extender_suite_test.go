@@ -0,0 +1,16 @@
+package extender_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/log"
+)
+
+func TestExtender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	log.Log.SetIOWriter(GinkgoWriter)
+	RunSpecs(t, "Scheduler Extender Suite")
+}
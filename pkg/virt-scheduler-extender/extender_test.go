@@ -0,0 +1,143 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2021 Red Hat, Inc.
+ *
+ */
+
+package extender
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/kubevirt/pkg/util/utilization"
+)
+
+var _ = Describe("Scheduler extender", func() {
+	var nodeStore cache.Store
+	var extender *Extender
+	var now time.Time
+
+	addNode := func(name string, nodeUtilization *utilization.NodeUtilization) {
+		node := &k8sv1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: k8sv1.NodeStatus{
+				Allocatable: k8sv1.ResourceList{k8sv1.ResourceMemory: resource.MustParse("10Gi")},
+			},
+		}
+		if nodeUtilization != nil {
+			value, err := json.Marshal(nodeUtilization)
+			Expect(err).ToNot(HaveOccurred())
+			node.Annotations = map[string]string{v1.NodeUtilizationAnnotation: string(value)}
+		}
+		Expect(nodeStore.Add(node)).To(Succeed())
+	}
+
+	launcherPod := func() *k8sv1.Pod {
+		return &k8sv1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:   "virt-launcher-testvmi",
+			Labels: map[string]string{v1.AppLabel: "virt-launcher"},
+		}}
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		nodeStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		extender = NewExtender(nodeStore, 5*time.Minute)
+		extender.now = func() time.Time {
+			return now
+		}
+
+		addNode("idle", &utilization.NodeUtilization{Timestamp: metav1.NewTime(now)})
+		addNode("cpu-contended", &utilization.NodeUtilization{CPUPressure: 40, MemoryResidentBytes: 512 * 1024 * 1024, Timestamp: metav1.NewTime(now)})
+		addNode("memory-contended", &utilization.NodeUtilization{CPUPressure: 5, MemoryResidentBytes: 8 * 1024 * 1024 * 1024, Timestamp: metav1.NewTime(now)})
+		addNode("stale", &utilization.NodeUtilization{CPUPressure: 100, Timestamp: metav1.NewTime(now.Add(-time.Hour))})
+		addNode("unreported", nil)
+	})
+
+	nodeNames := []string{"idle", "cpu-contended", "memory-contended", "stale", "unreported", "unknown"}
+
+	It("should score the nodes for VMIs by their CPU pressure or their resident memory", func() {
+		Expect(extender.Prioritize(&ExtenderArgs{Pod: launcherPod(), NodeNames: &nodeNames})).To(Equal(HostPriorityList{
+			{Host: "idle", Score: 10},
+			{Host: "cpu-contended", Score: 6},
+			{Host: "memory-contended", Score: 2},
+			{Host: "stale", Score: 5},
+			{Host: "unreported", Score: 5},
+			{Host: "unknown", Score: 5},
+		}))
+	})
+
+	It("should score all nodes the same for other pods", func() {
+		pod := &k8sv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+		for _, priority := range extender.Prioritize(&ExtenderArgs{Pod: pod, NodeNames: &nodeNames}) {
+			Expect(priority.Score).To(BeZero())
+		}
+	})
+
+	It("should score the nodes sent by the scheduler without a node cache", func() {
+		nodes := &k8sv1.NodeList{Items: []k8sv1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cpu-contended"}},
+		}}
+		Expect(extender.Prioritize(&ExtenderArgs{Pod: launcherPod(), Nodes: nodes})).To(Equal(HostPriorityList{
+			{Host: "cpu-contended", Score: 6},
+		}))
+	})
+
+	It("should serve the prioritize verb", func() {
+		body, err := json.Marshal(&ExtenderArgs{Pod: launcherPod(), NodeNames: &[]string{"idle"}})
+		Expect(err).ToNot(HaveOccurred())
+		recorder := httptest.NewRecorder()
+		extender.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PrioritizePath, bytes.NewReader(body)))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		priorities := HostPriorityList{}
+		Expect(json.NewDecoder(recorder.Body).Decode(&priorities)).To(Succeed())
+		Expect(priorities).To(Equal(HostPriorityList{{Host: "idle", Score: 10}}))
+	})
+
+	It("should serve the prioritize verb below the URL prefix of the extender", func() {
+		body, err := json.Marshal(&ExtenderArgs{Pod: launcherPod(), NodeNames: &[]string{"idle"}})
+		Expect(err).ToNot(HaveOccurred())
+		recorder := httptest.NewRecorder()
+		extender.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/scheduler"+PrioritizePath, bytes.NewReader(body)))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should not serve other verbs", func() {
+		recorder := httptest.NewRecorder()
+		extender.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/filter", bytes.NewReader([]byte("{}"))))
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should reject malformed arguments", func() {
+		recorder := httptest.NewRecorder()
+		extender.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PrioritizePath, bytes.NewReader([]byte("{"))))
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	})
+})
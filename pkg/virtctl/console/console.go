@@ -32,10 +32,12 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/handoff"
 	"kubevirt.io/kubevirt/pkg/virtctl/templates"
 )
 
 var timeout int
+var reconnect bool
 
 func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 	cmd := &cobra.Command{
@@ -50,6 +52,7 @@ func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 	}
 
 	cmd.Flags().IntVar(&timeout, "timeout", 5, "The number of minutes to wait for the virtual machine instance to be ready.")
+	cmd.Flags().BoolVar(&reconnect, "reconnect", true, "Reconnect to the console when the virtual machine instance is live migrated.")
 	cmd.SetUsageTemplate(templates.UsageTemplate())
 	return cmd
 }
@@ -62,7 +65,9 @@ func usage() string {
 	usage := `  # Connect to the console on VirtualMachineInstance 'myvmi':
   {{ProgramName}} console myvmi
   # Configure one minute timeout (default 5 minutes)
-  {{ProgramName}} console --timeout=1 myvmi`
+  {{ProgramName}} console --timeout=1 myvmi
+  # Don't reconnect when 'myvmi' is live migrated to another node:
+  {{ProgramName}} console --reconnect=false myvmi`
 
 	return usage
 }
@@ -104,10 +109,7 @@ func (c *Console) Run(cmd *cobra.Command, args []string) error {
 			return
 		}
 
-		resChan <- con.Stream(kubecli.StreamOptions{
-			In:  stdinReader,
-			Out: stdoutWriter,
-		})
+		resChan <- c.stream(virtCli, namespace, vmi, con, stdinReader, stdoutWriter)
 	}()
 
 	select {
@@ -187,3 +189,40 @@ func (c *Console) Run(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// stream connects the console of the VMI to the given reader and writer. If the
+// connection is lost because the VMI was live migrated, it transparently
+// connects to the console on the migration target.
+func (c *Console) stream(virtCli kubecli.KubevirtClient, namespace string, vmi string, con kubecli.StreamInterface, in io.Reader, out io.Writer) error {
+	var tracker *handoff.Tracker
+	if reconnect {
+		var err error
+		if tracker, err = handoff.NewTracker(virtCli, namespace, vmi); err != nil {
+			return err
+		}
+	}
+
+	for {
+		err := con.Stream(kubecli.StreamOptions{
+			In:  in,
+			Out: out,
+		})
+		if tracker == nil {
+			return err
+		}
+
+		migrated, waitErr := tracker.WaitForTarget()
+		if waitErr != nil || !migrated {
+			return err
+		}
+		fmt.Fprint(os.Stderr, "\r\nThe virtual machine instance was migrated, reconnecting to ", vmi, " console.\r\n")
+
+		con, err = virtCli.VirtualMachineInstance(namespace).SerialConsole(vmi, &kubecli.SerialConsoleOptions{ConnectionTimeout: time.Duration(timeout) * time.Minute})
+		if err != nil {
+			return err
+		}
+		if err = tracker.Connected(); err != nil {
+			return err
+		}
+	}
+}
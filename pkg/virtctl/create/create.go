@@ -0,0 +1,330 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package create
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/api/admission/v1beta1"
+	k8sv1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virt-api/webhooks"
+	"kubevirt.io/kubevirt/pkg/virt-api/webhooks/mutating-webhook/mutators"
+	"kubevirt.io/kubevirt/pkg/virt-api/webhooks/validating-webhook/admitters"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const (
+	COMMAND_CREATE = "create"
+	COMMAND_VM     = "vm"
+
+	DISK_TYPE_DATAVOLUME    = "dv"
+	DISK_TYPE_PVC           = "pvc"
+	DISK_TYPE_CONTAINERDISK = "containerdisk"
+
+	NETWORK_DEFAULT = "default"
+
+	dataVolumeCRDName = "datavolumes.cdi.kubevirt.io"
+)
+
+var (
+	name     string
+	memory   string
+	cpu      uint32
+	disks    []string
+	networks []string
+	running  bool
+	apply    bool
+)
+
+func NewCreateCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Generate the manifests of KubeVirt resources.",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(cmd.OutOrStderr(), cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(NewCreateVMCommand(clientConfig))
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func NewCreateVMCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vm",
+		Short: "Generate the manifest of a virtual machine.",
+		Long: `Generate the manifest of a virtual machine.
+
+The virtual machine gets the defaults and passes the validation of the admission
+webhooks, the configuration of the cluster is taken into account if it can be
+read. Disks are given as TYPE:SOURCE, where TYPE is one of dv (an existing
+DataVolume), pvc or containerdisk (an image). Networks are either default for the
+pod network or the name of a Multus network.`,
+		Example: usage(),
+		Args:    templates.ExactArgs(COMMAND_VM, 0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := CreateVM{clientConfig: clientConfig}
+			return c.Run(cmd)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "The name of the virtual machine.")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&memory, "memory", "1Gi", "The memory of the virtual machine.")
+	cmd.Flags().Uint32Var(&cpu, "cpu", 1, "The number of CPU cores of the virtual machine.")
+	cmd.Flags().StringArrayVar(&disks, "disk", []string{}, "A disk as TYPE:SOURCE, the first one is booted from. Can be given multiple times.")
+	cmd.Flags().StringArrayVar(&networks, "network", []string{}, "A network to connect to, the pod network is used if none is given. Can be given multiple times.")
+	cmd.Flags().BoolVar(&running, "running", false, "Start the virtual machine once it is created.")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Create the virtual machine in the cluster instead of printing its manifest.")
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Print the manifest of a virtual machine booting a DataVolume:\n"
+	usage += "  {{ProgramName}} create vm --name foo --memory 4Gi --cpu 2 --disk dv:ubuntu --network default\n\n"
+	usage += "  # Create a running virtual machine from a container disk with an additional data disk:\n"
+	usage += "  {{ProgramName}} create vm --name foo --disk containerdisk:kubevirt/fedora-cloud-container-disk-demo --disk pvc:data --running --apply"
+	return usage
+}
+
+type CreateVM struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func (o *CreateVM) Run(cmd *cobra.Command) error {
+	vm, err := newVirtualMachine()
+	if err != nil {
+		return err
+	}
+
+	namespace, _, err := o.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+
+	virtClient, err := kubecli.GetKubevirtClientFromClientConfig(o.clientConfig)
+	if err != nil {
+		return fmt.Errorf("cannot obtain KubeVirt client: %v", err)
+	}
+
+	clusterConfig, err := loadClusterConfig(virtClient)
+	if err != nil {
+		if apply {
+			return fmt.Errorf("error reading the KubeVirt configuration of the cluster: %v", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Can't read the KubeVirt configuration of the cluster, using the defaults: %v\n", err)
+		clusterConfig = defaultClusterConfig()
+	}
+
+	if err := admit(vm, clusterConfig, virtClient); err != nil {
+		return err
+	}
+
+	if apply {
+		if _, err := virtClient.VirtualMachine(namespace).Create(vm); err != nil {
+			return fmt.Errorf("error creating VirtualMachine %s: %v", vm.Name, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "VM %s was created\n", vm.Name)
+		return nil
+	}
+
+	manifest, err := yaml.Marshal(vm)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(manifest)
+	return err
+}
+
+func newVirtualMachine() (*v1.VirtualMachine, error) {
+	memoryRequest, err := resource.ParseQuantity(memory)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory %s: %v", memory, err)
+	}
+
+	spec := v1.VirtualMachineInstanceSpec{
+		Domain: v1.DomainSpec{
+			CPU: &v1.CPU{Cores: cpu},
+			Resources: v1.ResourceRequirements{
+				Requests: k8sv1.ResourceList{
+					k8sv1.ResourceMemory: memoryRequest,
+				},
+			},
+		},
+	}
+
+	for i, disk := range disks {
+		volumeSource, err := parseDisk(disk)
+		if err != nil {
+			return nil, err
+		}
+		diskName := fmt.Sprintf("disk%d", i)
+		spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, v1.Disk{
+			Name: diskName,
+			DiskDevice: v1.DiskDevice{
+				Disk: &v1.DiskTarget{Bus: "virtio"},
+			},
+		})
+		spec.Volumes = append(spec.Volumes, v1.Volume{
+			Name:         diskName,
+			VolumeSource: volumeSource,
+		})
+	}
+	if len(spec.Domain.Devices.Disks) > 0 {
+		bootOrder := uint(1)
+		spec.Domain.Devices.Disks[0].BootOrder = &bootOrder
+	}
+
+	for i, network := range networks {
+		if network == NETWORK_DEFAULT {
+			spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, *v1.DefaultMasqueradeNetworkInterface())
+			spec.Networks = append(spec.Networks, *v1.DefaultPodNetwork())
+			continue
+		}
+		networkName := fmt.Sprintf("net%d", i)
+		spec.Domain.Devices.Interfaces = append(spec.Domain.Devices.Interfaces, v1.Interface{
+			Name: networkName,
+			InterfaceBindingMethod: v1.InterfaceBindingMethod{
+				Bridge: &v1.InterfaceBridge{},
+			},
+		})
+		spec.Networks = append(spec.Networks, v1.Network{
+			Name: networkName,
+			NetworkSource: v1.NetworkSource{
+				Multus: &v1.MultusNetwork{NetworkName: network},
+			},
+		})
+	}
+
+	return &v1.VirtualMachine{
+		TypeMeta: k8smetav1.TypeMeta{
+			APIVersion: v1.GroupVersion.String(),
+			Kind:       v1.VirtualMachineGroupVersionKind.Kind,
+		},
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.VirtualMachineSpec{
+			Running: &running,
+			Template: &v1.VirtualMachineInstanceTemplateSpec{
+				ObjectMeta: k8smetav1.ObjectMeta{
+					Labels: map[string]string{v1.VirtualMachineLabel: name},
+				},
+				Spec: spec,
+			},
+		},
+	}, nil
+}
+
+func parseDisk(disk string) (v1.VolumeSource, error) {
+	parts := strings.SplitN(disk, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return v1.VolumeSource{}, fmt.Errorf("invalid disk %s, expected TYPE:SOURCE", disk)
+	}
+
+	switch source := parts[1]; strings.ToLower(parts[0]) {
+	case DISK_TYPE_DATAVOLUME:
+		return v1.VolumeSource{DataVolume: &v1.DataVolumeSource{Name: source}}, nil
+	case DISK_TYPE_PVC:
+		return v1.VolumeSource{PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{ClaimName: source}}, nil
+	case DISK_TYPE_CONTAINERDISK:
+		return v1.VolumeSource{ContainerDisk: &v1.ContainerDiskSource{Image: source}}, nil
+	default:
+		return v1.VolumeSource{}, fmt.Errorf("unsupported disk type %s, must be one of %s, %s or %s", parts[0], DISK_TYPE_DATAVOLUME, DISK_TYPE_PVC, DISK_TYPE_CONTAINERDISK)
+	}
+}
+
+// admit applies the defaults and the validation of the admission webhooks of virt-api
+func admit(vm *v1.VirtualMachine, clusterConfig *virtconfig.ClusterConfig, virtClient kubecli.KubevirtClient) error {
+	mutator := &mutators.VMsMutator{ClusterConfig: clusterConfig}
+	mutator.SetDefaults(vm)
+
+	raw, err := json.Marshal(vm)
+	if err != nil {
+		return err
+	}
+	ar := &v1beta1.AdmissionReview{
+		Request: &v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Resource:  webhooks.VirtualMachineGroupVersionResource,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+
+	resp := admitters.NewVMsAdmitter(clusterConfig, virtClient).Admit(ar)
+	if !resp.Allowed {
+		return fmt.Errorf("the generated VirtualMachine is invalid: %s", resp.Result.Message)
+	}
+	return nil
+}
+
+// loadClusterConfig reads the KubeVirt configuration of the cluster, so that its feature gates and defaults apply
+func loadClusterConfig(virtClient kubecli.KubevirtClient) (*virtconfig.ClusterConfig, error) {
+	kvs, err := virtClient.KubeVirt(k8smetav1.NamespaceAll).List(&k8smetav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs.Items) == 0 {
+		return nil, fmt.Errorf("KubeVirt is not installed")
+	}
+	kv := &kvs.Items[0]
+
+	configMap, err := virtClient.CoreV1().ConfigMaps(kv.Namespace).Get(virtconfig.ConfigMapName, k8smetav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		configMap = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var crds []*extv1beta1.CustomResourceDefinition
+	crd, err := virtClient.ExtensionsClient().ApiextensionsV1beta1().CustomResourceDefinitions().Get(dataVolumeCRDName, k8smetav1.GetOptions{})
+	if err == nil {
+		crds = append(crds, crd)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return virtconfig.NewClusterConfigFromObjects(configMap, crds, kv, kv.Namespace), nil
+}
+
+// defaultClusterConfig returns the default configuration, with the DataVolume API assumed to be present
+func defaultClusterConfig() *virtconfig.ClusterConfig {
+	dataVolumeCRD := &extv1beta1.CustomResourceDefinition{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: dataVolumeCRDName},
+		Spec: extv1beta1.CustomResourceDefinitionSpec{
+			Names: extv1beta1.CustomResourceDefinitionNames{Kind: "DataVolume"},
+		},
+	}
+	return virtconfig.NewClusterConfigFromObjects(nil, []*extv1beta1.CustomResourceDefinition{dataVolumeCRD}, nil, "")
+}
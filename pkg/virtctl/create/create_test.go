@@ -0,0 +1,148 @@
+package create_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	extclientfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/tests"
+)
+
+var _ = Describe("Create", func() {
+
+	var kvInterface *kubecli.MockKubeVirtInterface
+	var vmInterface *kubecli.MockVirtualMachineInterface
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubecli.GetKubevirtClientFromClientConfig = kubecli.GetMockKubevirtClientFromClientConfig
+		kubecli.MockKubevirtClientInstance = kubecli.NewMockKubevirtClient(ctrl)
+		kvInterface = kubecli.NewMockKubeVirtInterface(ctrl)
+		vmInterface = kubecli.NewMockVirtualMachineInterface(ctrl)
+
+		kubecli.MockKubevirtClientInstance.EXPECT().KubeVirt(k8smetav1.NamespaceAll).Return(kvInterface).AnyTimes()
+		kubecli.MockKubevirtClientInstance.EXPECT().VirtualMachine(k8smetav1.NamespaceDefault).Return(vmInterface).AnyTimes()
+		kubecli.MockKubevirtClientInstance.EXPECT().CoreV1().Return(fake.NewSimpleClientset().CoreV1()).AnyTimes()
+		kubecli.MockKubevirtClientInstance.EXPECT().ExtensionsClient().Return(extclientfake.NewSimpleClientset(&extv1beta1.CustomResourceDefinition{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "datavolumes.cdi.kubevirt.io"},
+			Spec: extv1beta1.CustomResourceDefinitionSpec{
+				Names: extv1beta1.CustomResourceDefinitionNames{Kind: "DataVolume"},
+			},
+		})).AnyTimes()
+	})
+
+	createVM := func(args ...string) (*v1.VirtualMachine, error) {
+		var out bytes.Buffer
+		cmd := tests.NewVirtctlCommand(append([]string{"create", "vm"}, args...)...)
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+		vm := &v1.VirtualMachine{}
+		err := yaml.Unmarshal(out.Bytes(), vm)
+		Expect(err).ToNot(HaveOccurred())
+		return vm, nil
+	}
+
+	Context("without access to the KubeVirt configuration", func() {
+
+		BeforeEach(func() {
+			kvInterface.EXPECT().List(gomock.Any()).Return(nil, fmt.Errorf("forbidden")).AnyTimes()
+		})
+
+		It("should print a virtual machine with the defaults applied", func() {
+			vm, err := createVM("--name", "foo", "--memory", "4Gi", "--cpu", "2", "--disk", "dv:ubuntu", "--network", "default")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(vm.Kind).To(Equal("VirtualMachine"))
+			Expect(vm.Name).To(Equal("foo"))
+			Expect(*vm.Spec.Running).To(BeFalse())
+			spec := vm.Spec.Template.Spec
+			Expect(spec.Domain.Machine.Type).To(Equal(virtconfig.DefaultMachineType))
+			Expect(spec.Domain.CPU.Cores).To(Equal(uint32(2)))
+			Expect(spec.Domain.Resources.Requests.Memory().Cmp(resource.MustParse("4Gi"))).To(Equal(0))
+			Expect(spec.Domain.Devices.Disks).To(HaveLen(1))
+			Expect(*spec.Domain.Devices.Disks[0].BootOrder).To(Equal(uint(1)))
+			Expect(spec.Volumes).To(HaveLen(1))
+			Expect(spec.Volumes[0].DataVolume.Name).To(Equal("ubuntu"))
+			Expect(spec.Networks).To(Equal([]v1.Network{*v1.DefaultPodNetwork()}))
+			Expect(spec.Domain.Devices.Interfaces).To(Equal([]v1.Interface{*v1.DefaultMasqueradeNetworkInterface()}))
+		})
+
+		It("should connect to Multus networks", func() {
+			vm, err := createVM("--name", "foo", "--disk", "containerdisk:kubevirt/cirros-container-disk-demo", "--network", "default", "--network", "mynet")
+			Expect(err).ToNot(HaveOccurred())
+
+			spec := vm.Spec.Template.Spec
+			Expect(spec.Volumes[0].ContainerDisk.Image).To(Equal("kubevirt/cirros-container-disk-demo"))
+			Expect(spec.Networks).To(HaveLen(2))
+			Expect(spec.Networks[1].Name).To(Equal("net1"))
+			Expect(spec.Networks[1].Multus.NetworkName).To(Equal("mynet"))
+			Expect(spec.Domain.Devices.Interfaces[1].Name).To(Equal("net1"))
+			Expect(spec.Domain.Devices.Interfaces[1].Bridge).ToNot(BeNil())
+		})
+
+		It("should refuse to apply the virtual machine", func() {
+			_, err := createVM("--name", "foo", "--apply")
+			Expect(err).To(MatchError(ContainSubstring("error reading the KubeVirt configuration of the cluster")))
+		})
+
+		table.DescribeTable("should fail", func(expectedErr string, args ...string) {
+			_, err := createVM(append([]string{"--name", "foo"}, args...)...)
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+			table.Entry("with an invalid memory", "invalid memory", "--memory", "lots"),
+			table.Entry("with a disk without source", "invalid disk", "--disk", "pvc:"),
+			table.Entry("with an unknown disk type", "unsupported disk type", "--disk", "nfs:share"),
+			table.Entry("if the admission rejects the virtual machine", "the generated VirtualMachine is invalid", "--network", "default", "--network", "default"),
+		)
+	})
+
+	Context("with access to the KubeVirt configuration", func() {
+
+		BeforeEach(func() {
+			kv := v1.KubeVirt{
+				ObjectMeta: k8smetav1.ObjectMeta{Name: "kubevirt", Namespace: "kubevirt", ResourceVersion: "1"},
+				Spec: v1.KubeVirtSpec{
+					Configuration: v1.KubeVirtConfiguration{
+						MachineType:      "custom-machine",
+						EmulatedMachines: []string{"custom-*"},
+					},
+				},
+				Status: v1.KubeVirtStatus{Phase: v1.KubeVirtPhaseDeployed},
+			}
+			kvInterface.EXPECT().List(gomock.Any()).Return(&v1.KubeVirtList{Items: []v1.KubeVirt{kv}}, nil).AnyTimes()
+		})
+
+		It("should use the defaults of the cluster", func() {
+			vm, err := createVM("--name", "foo", "--disk", "dv:ubuntu")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vm.Spec.Template.Spec.Domain.Machine.Type).To(Equal("custom-machine"))
+		})
+
+		It("should create the virtual machine", func() {
+			vmInterface.EXPECT().Create(gomock.Any()).DoAndReturn(func(vm *v1.VirtualMachine) (*v1.VirtualMachine, error) {
+				Expect(vm.Name).To(Equal("foo"))
+				Expect(*vm.Spec.Running).To(BeTrue())
+				Expect(vm.Spec.Template.Spec.Domain.Machine.Type).To(Equal("custom-machine"))
+				return vm, nil
+			})
+
+			cmd := tests.NewRepeatableVirtctlCommand("create", "vm", "--name", "foo", "--disk", "pvc:data", "--running", "--apply")
+			Expect(cmd()).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,112 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package handoff
+
+import (
+	"time"
+
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+const (
+	// DefaultInterval is the default interval at which the VMI is polled while waiting for a migration to finish
+	DefaultInterval = 1 * time.Second
+	// DefaultTimeout is the default time to wait for a migration to finish before giving up on reconnecting
+	DefaultTimeout = 5 * time.Minute
+)
+
+// Tracker remembers which live migration a console or VNC connection was
+// established against. When the connection drops, it allows to tell apart a
+// migration which moved the VMI to another node from any other disconnect.
+type Tracker struct {
+	virtCli   kubecli.KubevirtClient
+	namespace string
+	name      string
+
+	Interval time.Duration
+	Timeout  time.Duration
+
+	migrationUID types.UID
+}
+
+// NewTracker creates a Tracker for the given VMI and records its current migration state.
+func NewTracker(virtCli kubecli.KubevirtClient, namespace string, name string) (*Tracker, error) {
+	t := &Tracker{
+		virtCli:   virtCli,
+		namespace: namespace,
+		name:      name,
+		Interval:  DefaultInterval,
+		Timeout:   DefaultTimeout,
+	}
+	if err := t.Connected(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Connected records the migration state of the VMI at the time a new connection was established.
+func (t *Tracker) Connected() error {
+	vmi, err := t.virtCli.VirtualMachineInstance(t.namespace).Get(t.name, &k8smetav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	t.migrationUID = migrationUID(vmi)
+	return nil
+}
+
+// WaitForTarget is called after a connection to the VMI was lost. It returns true
+// if a live migration was started since the connection was established and waits
+// until that migration has finished, so that a new connection reaches the
+// virt-launcher pod the VMI is running in now.
+func (t *Tracker) WaitForTarget() (bool, error) {
+	migrated := false
+	err := wait.PollImmediate(t.Interval, t.Timeout, func() (bool, error) {
+		vmi, err := t.virtCli.VirtualMachineInstance(t.namespace).Get(t.name, &k8smetav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if vmi.IsFinal() || vmi.Status.MigrationState == nil || migrationUID(vmi) == t.migrationUID {
+			// the connection was not lost because of a migration
+			return true, nil
+		}
+		state := vmi.Status.MigrationState
+		if state.Completed || state.Failed {
+			migrated = true
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return migrated, nil
+}
+
+func migrationUID(vmi *v1.VirtualMachineInstance) types.UID {
+	if vmi.Status.MigrationState == nil {
+		return ""
+	}
+	return vmi.Status.MigrationState.MigrationUID
+}
@@ -0,0 +1,101 @@
+package handoff_test
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/handoff"
+)
+
+var _ = Describe("Migration handoff", func() {
+
+	const vmiName = "testvmi"
+	var virtClient *kubecli.MockKubevirtClient
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var ctrl *gomock.Controller
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		virtClient = kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		virtClient.EXPECT().VirtualMachineInstance(k8smetav1.NamespaceDefault).Return(vmiInterface).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newVMI := func(migrationState *v1.VirtualMachineInstanceMigrationState) *v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMI(vmiName)
+		vmi.Status.Phase = v1.Running
+		vmi.Status.MigrationState = migrationState
+		return vmi
+	}
+
+	newTracker := func(vmi *v1.VirtualMachineInstance) *handoff.Tracker {
+		vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(vmi, nil)
+		tracker, err := handoff.NewTracker(virtClient, k8smetav1.NamespaceDefault, vmiName)
+		Expect(err).ToNot(HaveOccurred())
+		tracker.Interval = 10 * time.Millisecond
+		tracker.Timeout = time.Second
+		return tracker
+	}
+
+	It("should not reconnect if no migration happened", func() {
+		tracker := newTracker(newVMI(nil))
+		vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(newVMI(nil), nil)
+
+		migrated, err := tracker.WaitForTarget()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(BeFalse())
+	})
+
+	It("should not reconnect after a migration which finished before connecting", func() {
+		state := &v1.VirtualMachineInstanceMigrationState{MigrationUID: "1", Completed: true}
+		tracker := newTracker(newVMI(state))
+		vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(newVMI(state), nil)
+
+		migrated, err := tracker.WaitForTarget()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(BeFalse())
+	})
+
+	It("should not reconnect if the VMI is gone", func() {
+		tracker := newTracker(newVMI(nil))
+		vmi := newVMI(&v1.VirtualMachineInstanceMigrationState{MigrationUID: "1"})
+		vmi.Status.Phase = v1.Failed
+		vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(vmi, nil)
+
+		migrated, err := tracker.WaitForTarget()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(BeFalse())
+	})
+
+	It("should wait for an ongoing migration to complete", func() {
+		tracker := newTracker(newVMI(nil))
+		gomock.InOrder(
+			vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(newVMI(&v1.VirtualMachineInstanceMigrationState{MigrationUID: "1"}), nil).Times(2),
+			vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(newVMI(&v1.VirtualMachineInstanceMigrationState{MigrationUID: "1", Completed: true}), nil),
+		)
+
+		migrated, err := tracker.WaitForTarget()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(migrated).To(BeTrue())
+	})
+
+	It("should give up if the migration does not finish in time", func() {
+		tracker := newTracker(newVMI(nil))
+		tracker.Timeout = 50 * time.Millisecond
+		vmiInterface.EXPECT().Get(vmiName, gomock.Any()).Return(newVMI(&v1.VirtualMachineInstanceMigrationState{MigrationUID: "1"}), nil).MinTimes(1)
+
+		_, err := tracker.WaitForTarget()
+		Expect(err).To(HaveOccurred())
+	})
+})
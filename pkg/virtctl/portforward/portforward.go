@@ -0,0 +1,252 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const COMMAND_PORTFORWARD = "port-forward"
+
+var (
+	address string
+	stdio   bool
+)
+
+func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward TYPE/NAME [LOCAL_PORT:]REMOTE_PORT...",
+		Short: "Forward local ports to a virtual machine or virtual machine instance.",
+		Long: `Forward local ports to a virtual machine or virtual machine instance.
+
+The TYPE is either vm or vmi, if omitted vmi is assumed. Every accepted local
+connection is tunneled through the KubeVirt API to the given TCP port of the
+guest. The port has to be declared on the masquerade interface of the pod network.`,
+		Example: usage(),
+		Args:    templates.MinimumNArgs(COMMAND_PORTFORWARD, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := PortForward{clientConfig: clientConfig}
+			return c.Run(cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&address, "address", "127.0.0.1", "The local address to listen on.")
+	cmd.Flags().BoolVar(&stdio, "stdio", false, "If present, forward stdin and stdout instead of listening on a local port, e.g. for the ProxyCommand of ssh.")
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Forward the local port 2222 to the SSH port of the virtual machine instance 'testvmi':\n"
+	usage += "  {{ProgramName}} port-forward vmi/testvmi 2222:22\n\n"
+	usage += "  # Forward the local ports 8080 and 8443 to the same ports of the virtual machine 'testvm':\n"
+	usage += "  {{ProgramName}} port-forward vm/testvm 8080 8443\n\n"
+	usage += "  # Forward a random local port to the port 80 of the virtual machine instance 'testvmi':\n"
+	usage += "  {{ProgramName}} port-forward testvmi :80\n\n"
+	usage += "  # Use the port forwarding as a proxy for ssh:\n"
+	usage += "  ssh -o 'ProxyCommand={{ProgramName}} port-forward --stdio vmi/testvmi 22' user@testvmi"
+	return usage
+}
+
+type PortForward struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+type forwardedPort struct {
+	local  int
+	remote int
+}
+
+func (o *PortForward) Run(cmd *cobra.Command, args []string) error {
+	name, err := ParseTarget(args[0])
+	if err != nil {
+		return err
+	}
+	ports, err := parsePorts(args[1:])
+	if err != nil {
+		return err
+	}
+	if stdio && len(ports) != 1 {
+		return fmt.Errorf("--stdio forwards exactly one port, got %d", len(ports))
+	}
+
+	namespace, _, err := o.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+
+	virtClient, err := kubecli.GetKubevirtClientFromClientConfig(o.clientConfig)
+	if err != nil {
+		return fmt.Errorf("Cannot obtain KubeVirt client: %v", err)
+	}
+	vmiClient := virtClient.VirtualMachineInstance(namespace)
+
+	// A virtual machine runs with an instance of the same name
+	vmi, err := vmiClient.Get(name, &k8smetav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Can't access VMI %s: %v", name, err)
+	}
+	if !vmi.IsRunning() {
+		return fmt.Errorf("VMI %s is not running", name)
+	}
+
+	if stdio {
+		return forwardStdio(cmd, vmiClient, name, ports[0].remote)
+	}
+
+	listeners := make([]net.Listener, 0, len(ports))
+	defer func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}()
+	for _, port := range ports {
+		ln, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port.local)))
+		if err != nil {
+			return fmt.Errorf("Can't listen on port %d: %v", port.local, err)
+		}
+		listeners = append(listeners, ln)
+		fmt.Fprintf(cmd.OutOrStdout(), "Forwarding from %s -> %d\n", ln.Addr().String(), port.remote)
+	}
+
+	errChan := make(chan error, len(listeners))
+	for i, ln := range listeners {
+		go func(ln net.Listener, remote int) {
+			errChan <- serve(ln, vmiClient, name, remote)
+		}(ln, ports[i].remote)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	select {
+	case <-interrupt:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// ParseTarget returns the name of the VMI to forward to. Virtual machines
+// are accepted as well, since their instance is named after them.
+func ParseTarget(target string) (string, error) {
+	parts := strings.SplitN(target, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	switch strings.ToLower(parts[0]) {
+	case "vmi", "vmis", "virtualmachineinstance", "virtualmachineinstances",
+		"vm", "vms", "virtualmachine", "virtualmachines":
+	default:
+		return "", fmt.Errorf("unsupported resource type %s, only vm and vmi are supported", parts[0])
+	}
+	if parts[1] == "" {
+		return "", fmt.Errorf("no name given in %s", target)
+	}
+	return parts[1], nil
+}
+
+// parsePorts accepts the port pairs in the form of kubectl port-forward:
+// LOCAL:REMOTE, REMOTE for the same local port and :REMOTE for a random one.
+func parsePorts(specs []string) ([]forwardedPort, error) {
+	ports := make([]forwardedPort, 0, len(specs))
+	for _, spec := range specs {
+		localSpec, remoteSpec := spec, spec
+		if parts := strings.SplitN(spec, ":", 2); len(parts) == 2 {
+			localSpec, remoteSpec = parts[0], parts[1]
+			if localSpec == "" {
+				localSpec = "0"
+			}
+		}
+		local, err := parsePort(localSpec, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local port in %s: %v", spec, err)
+		}
+		remote, err := parsePort(remoteSpec, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote port in %s: %v", spec, err)
+		}
+		ports = append(ports, forwardedPort{local: local, remote: remote})
+	}
+	return ports, nil
+}
+
+func parsePort(spec string, allowZero bool) (int, error) {
+	port, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a number", spec)
+	}
+	if port > 65535 || port < 0 || (port == 0 && !allowZero) {
+		return 0, fmt.Errorf("%d is out of range", port)
+	}
+	return port, nil
+}
+
+func serve(ln net.Listener, vmiClient kubecli.VirtualMachineInstanceInterface, name string, remote int) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return fmt.Errorf("Can't accept connections on %s: %v", ln.Addr().String(), err)
+		}
+		go forward(conn, vmiClient, name, remote)
+	}
+}
+
+// forwardStdio tunnels the standard input and output through a single stream
+func forwardStdio(cmd *cobra.Command, vmiClient kubecli.VirtualMachineInstanceInterface, name string, remote int) error {
+	stream, err := vmiClient.PortForward(name, remote)
+	if err != nil {
+		return fmt.Errorf("Can't forward to port %d of VMI %s: %v", remote, name, err)
+	}
+	if err = stream.Stream(kubecli.StreamOptions{In: cmd.InOrStdin(), Out: cmd.OutOrStdout()}); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// forward tunnels a single local connection through its own stream
+func forward(conn net.Conn, vmiClient kubecli.VirtualMachineInstanceInterface, name string, remote int) {
+	defer conn.Close()
+
+	glog.V(2).Infof("Handling connection from %s for port %d", conn.RemoteAddr().String(), remote)
+	stream, err := vmiClient.PortForward(name, remote)
+	if err != nil {
+		glog.Errorf("Can't forward to port %d of VMI %s: %v", remote, name, err)
+		return
+	}
+	if err = stream.Stream(kubecli.StreamOptions{In: conn, Out: conn}); err != nil && err != io.EOF {
+		glog.Errorf("Error while forwarding to port %d of VMI %s: %v", remote, name, err)
+	}
+}
@@ -0,0 +1,140 @@
+package portforward
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+)
+
+// echoStream sends everything it reads back to the client
+type echoStream struct{}
+
+func (echoStream) Stream(options kubecli.StreamOptions) error {
+	_, err := io.Copy(options.Out, options.In)
+	return err
+}
+
+var _ = Describe("PortForward", func() {
+
+	table.DescribeTable("should parse the target", func(target string, expectedName string) {
+		name, err := ParseTarget(target)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal(expectedName))
+	},
+		table.Entry("without a type", "testvmi", "testvmi"),
+		table.Entry("of a VMI", "vmi/testvmi", "testvmi"),
+		table.Entry("of a VM", "vm/testvm", "testvm"),
+		table.Entry("with the full type name", "virtualmachineinstance/testvmi", "testvmi"),
+	)
+
+	table.DescribeTable("should reject the target", func(target string) {
+		_, err := ParseTarget(target)
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("of another type", "pod/testpod"),
+		table.Entry("without a name", "vmi/"),
+	)
+
+	table.DescribeTable("should parse the ports", func(specs []string, expected []forwardedPort) {
+		ports, err := parsePorts(specs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ports).To(Equal(expected))
+	},
+		table.Entry("with a local and a remote port", []string{"2222:22"}, []forwardedPort{{local: 2222, remote: 22}}),
+		table.Entry("with the same local port", []string{"8080"}, []forwardedPort{{local: 8080, remote: 8080}}),
+		table.Entry("with a random local port", []string{":80"}, []forwardedPort{{local: 0, remote: 80}}),
+		table.Entry("with several ports", []string{"2222:22", "8080"}, []forwardedPort{{local: 2222, remote: 22}, {local: 8080, remote: 8080}}),
+	)
+
+	table.DescribeTable("should reject the ports", func(spec string) {
+		_, err := parsePorts([]string{spec})
+		Expect(err).To(HaveOccurred())
+	},
+		table.Entry("which are not numbers", "ssh"),
+		table.Entry("with a zero remote port", "2222:0"),
+		table.Entry("with an out of range remote port", "2222:70000"),
+		table.Entry("with an out of range local port", "70000:22"),
+	)
+
+	It("should tunnel a connection through its own stream", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		defer ctrl.Finish()
+		vmiInterface := kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		vmiInterface.EXPECT().PortForward("testvmi", 22).Return(echoStream{}, nil)
+
+		client, server := net.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			forward(server, vmiInterface, "testvmi", 22)
+		}()
+
+		_, err := client.Write([]byte("ping"))
+		Expect(err).ToNot(HaveOccurred())
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(client, buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf)).To(Equal("ping"))
+
+		client.Close()
+		Eventually(done).Should(BeClosed())
+	})
+
+	Context("with --stdio", func() {
+		var ctrl *gomock.Controller
+		var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			kubecli.GetKubevirtClientFromClientConfig = kubecli.GetMockKubevirtClientFromClientConfig
+			kubecli.MockKubevirtClientInstance = kubecli.NewMockKubevirtClient(ctrl)
+			vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+			kubecli.MockKubevirtClientInstance.EXPECT().VirtualMachineInstance(gomock.Any()).Return(vmiInterface).AnyTimes()
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+			stdio = false
+		})
+
+		runPortForward := func(in string, args ...string) (string, error) {
+			var out bytes.Buffer
+			cmd := NewCommand(kubecli.DefaultClientConfig(&pflag.FlagSet{}))
+			// The usage template relies on functions registered by the root command
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(append([]string{"--stdio"}, args...))
+			cmd.SetIn(strings.NewReader(in))
+			cmd.SetOut(&out)
+			err := cmd.Execute()
+			return out.String(), err
+		}
+
+		It("should tunnel stdin and stdout", func() {
+			vmi := v1.NewMinimalVMI("testvmi")
+			vmi.Status.Phase = v1.Running
+			vmiInterface.EXPECT().Get("testvmi", &k8smetav1.GetOptions{}).Return(vmi, nil)
+			vmiInterface.EXPECT().PortForward("testvmi", 22).Return(echoStream{}, nil)
+
+			out, err := runPortForward("ping", "vmi/testvmi", "22")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(Equal("ping"))
+		})
+
+		It("should refuse to forward more than one port", func() {
+			_, err := runPortForward("", "vmi/testvmi", "22", "80")
+			Expect(err).To(MatchError("--stdio forwards exactly one port, got 2"))
+		})
+	})
+})
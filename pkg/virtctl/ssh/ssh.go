@@ -0,0 +1,207 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package ssh
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/portforward"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const (
+	COMMAND_SSH = "ssh"
+
+	SSH_BINARY = "ssh"
+)
+
+var (
+	username     string
+	port         int
+	identityFile string
+	knownHosts   string
+	sshOptions   []string
+)
+
+func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh [USER@]TYPE/NAME [COMMAND...]",
+		Short: "Open a SSH connection to a virtual machine or virtual machine instance.",
+		Long: `Open a SSH connection to a virtual machine or virtual machine instance.
+
+The TYPE is either vm or vmi, if omitted vmi is assumed. The local ssh client is
+used, its connection is tunneled to the guest by the port-forward command. The
+host keys of the guest are stored as vmi/NAME.NAMESPACE in the known hosts file,
+so that no Service is required and the keys of different guests can't collide.`,
+		Example: usage(),
+		Args:    templates.MinimumNArgs(COMMAND_SSH, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := SSH{clientConfig: clientConfig}
+			return c.Run(cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&username, "username", "l", "", "The user to log in as, if not given with USER@.")
+	cmd.Flags().IntVarP(&port, "port", "p", 22, "The SSH port of the guest.")
+	cmd.Flags().StringVarP(&identityFile, "identity-file", "i", "", "The private key to authenticate with.")
+	cmd.Flags().StringVar(&knownHosts, "known-hosts", "", "The known hosts file to verify the host keys with, defaults to the one of ssh.")
+	cmd.Flags().StringArrayVarP(&sshOptions, "ssh-option", "o", []string{}, "An additional option passed to ssh, in the format of ssh_config.")
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Connect as 'fedora' to the virtual machine instance 'testvmi':\n"
+	usage += "  {{ProgramName}} ssh fedora@vmi/testvmi\n\n"
+	usage += "  # Run a command on the virtual machine 'testvm' with a dedicated key:\n"
+	usage += "  {{ProgramName}} ssh -i ~/.ssh/testvm fedora@vm/testvm uptime\n\n"
+	usage += "  # Connect without verifying the host key, e.g. for disposable guests:\n"
+	usage += "  {{ProgramName}} ssh -o StrictHostKeyChecking=no --known-hosts /dev/null fedora@testvmi"
+	return usage
+}
+
+type SSH struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+// sshTarget holds everything needed to build the ssh command line
+type sshTarget struct {
+	user       string
+	name       string
+	namespace  string
+	kubeconfig string
+	virtctl    string
+}
+
+func (o *SSH) Run(cmd *cobra.Command, args []string) error {
+	user, target := parseDestination(args[0])
+	if user == "" {
+		user = username
+	}
+	name, err := portforward.ParseTarget(target)
+	if err != nil {
+		return err
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d is out of range", port)
+	}
+
+	namespace, _, err := o.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+
+	virtClient, err := kubecli.GetKubevirtClientFromClientConfig(o.clientConfig)
+	if err != nil {
+		return fmt.Errorf("Cannot obtain KubeVirt client: %v", err)
+	}
+
+	// Fail early with a clear message instead of a broken proxy in ssh
+	vmi, err := virtClient.VirtualMachineInstance(namespace).Get(name, &k8smetav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Can't access VMI %s: %v", name, err)
+	}
+	if !vmi.IsRunning() {
+		return fmt.Errorf("VMI %s is not running", name)
+	}
+
+	virtctl, err := os.Executable()
+	if err != nil {
+		virtctl = os.Args[0]
+	}
+
+	sshArgs := buildSSHArgs(sshTarget{
+		user:       user,
+		name:       name,
+		namespace:  namespace,
+		kubeconfig: o.clientConfig.ConfigAccess().GetExplicitFile(),
+		virtctl:    virtctl,
+	}, args[1:])
+
+	sshCmd := exec.Command(SSH_BINARY, sshArgs...)
+	sshCmd.Stdin = cmd.InOrStdin()
+	sshCmd.Stdout = cmd.OutOrStdout()
+	sshCmd.Stderr = cmd.ErrOrStderr()
+	return sshCmd.Run()
+}
+
+// parseDestination splits an optional user from [USER@]TYPE/NAME
+func parseDestination(destination string) (user string, target string) {
+	if idx := strings.LastIndex(destination, "@"); idx >= 0 {
+		return destination[:idx], destination[idx+1:]
+	}
+	return "", destination
+}
+
+func buildSSHArgs(target sshTarget, command []string) []string {
+	proxyCommand := []string{target.virtctl, "port-forward", "--stdio", "--namespace", target.namespace}
+	if target.kubeconfig != "" {
+		proxyCommand = append(proxyCommand, "--kubeconfig", target.kubeconfig)
+	}
+	proxyCommand = append(proxyCommand, "vmi/"+target.name, strconv.Itoa(port))
+
+	quoted := make([]string, 0, len(proxyCommand))
+	for _, arg := range proxyCommand {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	args := []string{"-o", "ProxyCommand=" + strings.Join(quoted, " ")}
+	if knownHosts != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+knownHosts)
+	}
+	if identityFile != "" {
+		args = append(args, "-i", identityFile)
+	}
+	for _, option := range sshOptions {
+		args = append(args, "-o", option)
+	}
+
+	// The host name is only used to look up the host keys, the connection
+	// itself is established by the proxy command
+	host := fmt.Sprintf("vmi/%s.%s", target.name, target.namespace)
+	if target.user != "" {
+		host = target.user + "@" + host
+	}
+	args = append(args, host)
+	if len(command) > 0 {
+		args = append(args, "--")
+		args = append(args, command...)
+	}
+	return args
+}
+
+// shellQuote protects the arguments of the proxy command, which ssh runs
+// through the shell of the user
+func shellQuote(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@", r))
+	}) < 0 {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
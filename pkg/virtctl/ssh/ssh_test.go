@@ -0,0 +1,77 @@
+package ssh
+
+import (
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSH", func() {
+
+	table.DescribeTable("should parse the destination", func(destination, expectedUser, expectedTarget string) {
+		user, target := parseDestination(destination)
+		Expect(user).To(Equal(expectedUser))
+		Expect(target).To(Equal(expectedTarget))
+	},
+		table.Entry("with a user", "fedora@vmi/testvmi", "fedora", "vmi/testvmi"),
+		table.Entry("without a user", "vm/testvm", "", "vm/testvm"),
+		table.Entry("with an @ in the user", "fedora@example.com@testvmi", "fedora@example.com", "testvmi"),
+	)
+
+	Context("building the ssh arguments", func() {
+		target := sshTarget{
+			user:      "fedora",
+			name:      "testvmi",
+			namespace: "default",
+			virtctl:   "/usr/bin/virtctl",
+		}
+
+		BeforeEach(func() {
+			port = 22
+			identityFile = ""
+			knownHosts = ""
+			sshOptions = []string{}
+		})
+
+		It("should tunnel through the port-forward command", func() {
+			Expect(buildSSHArgs(target, nil)).To(Equal([]string{
+				"-o", "ProxyCommand=/usr/bin/virtctl port-forward --stdio --namespace default vmi/testvmi 22",
+				"fedora@vmi/testvmi.default",
+			}))
+		})
+
+		It("should pass the kubeconfig, port and the options to ssh", func() {
+			port = 2222
+			identityFile = "/home/user/.ssh/id_testvmi"
+			knownHosts = "/dev/null"
+			sshOptions = []string{"StrictHostKeyChecking=no"}
+			withKubeconfig := target
+			withKubeconfig.kubeconfig = "/home/user/my cluster.conf"
+
+			Expect(buildSSHArgs(withKubeconfig, []string{"uptime", "-p"})).To(Equal([]string{
+				"-o", "ProxyCommand=/usr/bin/virtctl port-forward --stdio --namespace default --kubeconfig '/home/user/my cluster.conf' vmi/testvmi 2222",
+				"-o", "UserKnownHostsFile=/dev/null",
+				"-i", "/home/user/.ssh/id_testvmi",
+				"-o", "StrictHostKeyChecking=no",
+				"fedora@vmi/testvmi.default",
+				"--", "uptime", "-p",
+			}))
+		})
+
+		It("should let ssh pick the user if none is given", func() {
+			withoutUser := target
+			withoutUser.user = ""
+			args := buildSSHArgs(withoutUser, nil)
+			Expect(args[len(args)-1]).To(Equal("vmi/testvmi.default"))
+		})
+	})
+
+	table.DescribeTable("should quote for the shell", func(arg, expected string) {
+		Expect(shellQuote(arg)).To(Equal(expected))
+	},
+		table.Entry("nothing for a plain path", "/usr/bin/virtctl", "/usr/bin/virtctl"),
+		table.Entry("an argument with spaces", "my cluster.conf", "'my cluster.conf'"),
+		table.Entry("an argument with a single quote", "it's", `'it'\''s'`),
+		table.Entry("an empty argument", "", "''"),
+	)
+})
@@ -58,3 +58,27 @@ func ExactArgs(nameOfCommand string, n int) cobra.PositionalArgs {
 		return nil
 	}
 }
+
+// MaximumNArgs validate that the number of input parameters does not exceed n
+func MaximumNArgs(nameOfCommand string, n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) > n {
+			fmt.Printf("fatal: Number of input parameters is incorrect, %s accepts at most %d arg(s), received %d\n\n", nameOfCommand, n, len(args))
+			cmd.Help()
+			return errors.New("argument validation failed")
+		}
+		return nil
+	}
+}
+
+// MinimumNArgs validate that at least n input parameters are given
+func MinimumNArgs(nameOfCommand string, n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < n {
+			fmt.Printf("fatal: Number of input parameters is incorrect, %s accepts at least %d arg(s), received %d\n\n", nameOfCommand, n, len(args))
+			cmd.Help()
+			return errors.New("argument validation failed")
+		}
+		return nil
+	}
+}
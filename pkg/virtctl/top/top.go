@@ -0,0 +1,297 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package top
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const (
+	COMMAND_TOP = "top"
+	COMMAND_VMI = "vmi"
+
+	SORT_BY_CPU    = "cpu"
+	SORT_BY_MEMORY = "memory"
+)
+
+var (
+	sortBy        string
+	allNamespaces bool
+	labelSelector string
+	interval      time.Duration
+	noHeaders     bool
+)
+
+func NewTopCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Display resource (CPU/Memory/Storage/Network) usage of virtual machines.",
+		Long: `Display resource (CPU/Memory/Storage/Network) usage of virtual machines.
+
+The stats are sampled twice from the nodes running the virtual machines, CPU usage
+and the storage and network throughput are computed over the sampling interval.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(cmd.OutOrStderr(), cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(NewTopVMICommand(clientConfig))
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func NewTopVMICommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "vmi [NAME]",
+		Aliases: []string{"vmis", "virtualmachineinstance", "virtualmachineinstances"},
+		Short:   "Display resource (CPU/Memory/Storage/Network) usage of virtual machine instances.",
+		Example: usage(),
+		Args:    templates.MaximumNArgs(COMMAND_VMI, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := TopVMI{clientConfig: clientConfig}
+			return c.Run(cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&sortBy, "sort-by", "", "If non-empty, sort the virtual machine instances using the specified field. The field can be either 'cpu' or 'memory'.")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "If present, list the resource usage of the virtual machine instances across all namespaces.")
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "Time between the two samples used to compute the CPU usage and the throughput.")
+	cmd.Flags().BoolVar(&noHeaders, "no-headers", false, "If present, print output without headers.")
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Show the resource usage of all virtual machine instances in the current namespace:\n"
+	usage += "  {{ProgramName}} top vmi\n\n"
+	usage += "  # Show the resource usage of the virtual machine instance 'testvmi':\n"
+	usage += "  {{ProgramName}} top vmi testvmi\n\n"
+	usage += "  # Show the virtual machine instances of all namespaces, the busiest first:\n"
+	usage += "  {{ProgramName}} top vmi -A --sort-by=cpu"
+	return usage
+}
+
+type TopVMI struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+// vmiUsage holds the resource usage of a VMI computed from two stats samples
+type vmiUsage struct {
+	namespace        string
+	name             string
+	cpuMillicores    int64
+	memoryBytes      uint64
+	storageReadRate  uint64
+	storageWriteRate uint64
+	networkRxRate    uint64
+	networkTxRate    uint64
+}
+
+func (o *TopVMI) Run(cmd *cobra.Command, args []string) error {
+	if sortBy != "" && sortBy != SORT_BY_CPU && sortBy != SORT_BY_MEMORY {
+		return fmt.Errorf("--sort-by accepts only %s or %s", SORT_BY_CPU, SORT_BY_MEMORY)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if len(args) == 1 && allNamespaces {
+		return fmt.Errorf("a virtual machine instance name can't be combined with --all-namespaces")
+	}
+
+	namespace, _, err := o.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+	if allNamespaces {
+		namespace = k8smetav1.NamespaceAll
+	}
+
+	virtClient, err := kubecli.GetKubevirtClientFromClientConfig(o.clientConfig)
+	if err != nil {
+		return fmt.Errorf("Cannot obtain KubeVirt client: %v", err)
+	}
+
+	vmis, err := listRunningVMIs(virtClient, namespace, args)
+	if err != nil {
+		return err
+	}
+	if len(vmis) == 0 {
+		fmt.Fprintln(cmd.OutOrStderr(), "No running virtual machine instances found")
+		return nil
+	}
+
+	first, err := sampleStats(virtClient, vmis)
+	if err != nil {
+		return err
+	}
+	time.Sleep(interval)
+	second, err := sampleStats(virtClient, vmis)
+	if err != nil {
+		return err
+	}
+
+	usages := make([]vmiUsage, 0, len(vmis))
+	for i, vmi := range vmis {
+		usages = append(usages, computeUsage(vmi, &first[i], &second[i]))
+	}
+	sortUsages(usages, sortBy)
+
+	return printUsages(cmd.OutOrStdout(), usages, allNamespaces, noHeaders)
+}
+
+func listRunningVMIs(virtClient kubecli.KubevirtClient, namespace string, args []string) ([]v1.VirtualMachineInstance, error) {
+	if len(args) == 1 {
+		vmi, err := virtClient.VirtualMachineInstance(namespace).Get(args[0], &k8smetav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Error getting VirtualMachineInstance %s: %v", args[0], err)
+		}
+		if vmi.Status.Phase != v1.Running {
+			return nil, fmt.Errorf("VirtualMachineInstance %s is not running", args[0])
+		}
+		return []v1.VirtualMachineInstance{*vmi}, nil
+	}
+
+	list, err := virtClient.VirtualMachineInstance(namespace).List(&k8smetav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing VirtualMachineInstances: %v", err)
+	}
+	var vmis []v1.VirtualMachineInstance
+	for _, vmi := range list.Items {
+		if vmi.Status.Phase == v1.Running {
+			vmis = append(vmis, vmi)
+		}
+	}
+	return vmis, nil
+}
+
+func sampleStats(virtClient kubecli.KubevirtClient, vmis []v1.VirtualMachineInstance) ([]v1.VirtualMachineInstanceStats, error) {
+	samples := make([]v1.VirtualMachineInstanceStats, 0, len(vmis))
+	for _, vmi := range vmis {
+		vmiStats, err := virtClient.VirtualMachineInstance(vmi.Namespace).Stats(vmi.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting stats of VirtualMachineInstance %s/%s: %v", vmi.Namespace, vmi.Name, err)
+		}
+		samples = append(samples, vmiStats)
+	}
+	return samples, nil
+}
+
+func computeUsage(vmi v1.VirtualMachineInstance, first, second *v1.VirtualMachineInstanceStats) vmiUsage {
+	u := vmiUsage{
+		namespace:   vmi.Namespace,
+		name:        vmi.Name,
+		memoryBytes: second.MemoryResidentBytes,
+	}
+
+	elapsed := second.Timestamp.Sub(first.Timestamp.Time)
+	if elapsed <= 0 {
+		return u
+	}
+
+	u.cpuMillicores = int64(delta(first.CPUTimeNanoseconds, second.CPUTimeNanoseconds) * 1000 / uint64(elapsed.Nanoseconds()))
+	u.storageReadRate = rate(first.StorageReadBytes, second.StorageReadBytes, elapsed)
+	u.storageWriteRate = rate(first.StorageWriteBytes, second.StorageWriteBytes, elapsed)
+	u.networkRxRate = rate(first.NetworkRxBytes, second.NetworkRxBytes, elapsed)
+	u.networkTxRate = rate(first.NetworkTxBytes, second.NetworkTxBytes, elapsed)
+	return u
+}
+
+// delta tolerates counters which got reset between the two samples, e.g.
+// because the VMI was migrated to another node in the meantime.
+func delta(first, second uint64) uint64 {
+	if second < first {
+		return 0
+	}
+	return second - first
+}
+
+func rate(first, second uint64, elapsed time.Duration) uint64 {
+	return uint64(float64(delta(first, second)) / elapsed.Seconds())
+}
+
+func sortUsages(usages []vmiUsage, sortBy string) {
+	sort.SliceStable(usages, func(i, j int) bool {
+		switch sortBy {
+		case SORT_BY_CPU:
+			if usages[i].cpuMillicores != usages[j].cpuMillicores {
+				return usages[i].cpuMillicores > usages[j].cpuMillicores
+			}
+		case SORT_BY_MEMORY:
+			if usages[i].memoryBytes != usages[j].memoryBytes {
+				return usages[i].memoryBytes > usages[j].memoryBytes
+			}
+		}
+		if usages[i].namespace != usages[j].namespace {
+			return usages[i].namespace < usages[j].namespace
+		}
+		return usages[i].name < usages[j].name
+	})
+}
+
+func printUsages(out io.Writer, usages []vmiUsage, withNamespace bool, noHeaders bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if !noHeaders {
+		if withNamespace {
+			fmt.Fprint(w, "NAMESPACE\t")
+		}
+		fmt.Fprintln(w, "NAME\tCPU(cores)\tMEMORY(bytes)\tSTORAGE READ/WRITE\tNETWORK RX/TX")
+	}
+	for _, u := range usages {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", u.namespace)
+		}
+		fmt.Fprintf(w, "%s\t%dm\t%dMi\t%s/%s\t%s/%s\n",
+			u.name,
+			u.cpuMillicores,
+			u.memoryBytes/(1024*1024),
+			formatRate(u.storageReadRate), formatRate(u.storageWriteRate),
+			formatRate(u.networkRxRate), formatRate(u.networkTxRate))
+	}
+	return w.Flush()
+}
+
+// formatBytes renders the size with the largest binary unit which keeps
+// the value above one.
+func formatBytes(bytes uint64) string {
+	units := []string{"", "Ki", "Mi", "Gi", "Ti"}
+	value := bytes
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%d%s", value, units[unit])
+}
+
+func formatRate(bytesPerSecond uint64) string {
+	return formatBytes(bytesPerSecond) + "/s"
+}
@@ -0,0 +1,132 @@
+package top_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/top"
+	"kubevirt.io/kubevirt/tests"
+)
+
+var _ = Describe("Top", func() {
+
+	var vmiInterface *kubecli.MockVirtualMachineInstanceInterface
+	var ctrl *gomock.Controller
+	var now time.Time
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubecli.GetKubevirtClientFromClientConfig = kubecli.GetMockKubevirtClientFromClientConfig
+		kubecli.MockKubevirtClientInstance = kubecli.NewMockKubevirtClient(ctrl)
+		vmiInterface = kubecli.NewMockVirtualMachineInstanceInterface(ctrl)
+		kubecli.MockKubevirtClientInstance.EXPECT().VirtualMachineInstance(gomock.Any()).Return(vmiInterface).AnyTimes()
+		now = time.Now()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	newRunningVMI := func(namespace, name string) v1.VirtualMachineInstance {
+		vmi := v1.NewMinimalVMIWithNS(namespace, name)
+		vmi.Status.Phase = v1.Running
+		return *vmi
+	}
+
+	// expectStats returns two samples taken one second apart, during which
+	// the VMI consumed the given CPU time and transferred the given bytes
+	expectStats := func(name string, cpu time.Duration, memory uint64, transferred uint64) {
+		first := v1.VirtualMachineInstanceStats{
+			Timestamp:           k8smetav1.NewMicroTime(now),
+			CPUTimeNanoseconds:  uint64(10 * time.Second),
+			MemoryResidentBytes: memory,
+		}
+		second := first
+		second.Timestamp = k8smetav1.NewMicroTime(now.Add(time.Second))
+		second.CPUTimeNanoseconds += uint64(cpu)
+		second.StorageReadBytes = transferred
+		second.StorageWriteBytes = 2 * transferred
+		second.NetworkRxBytes = 3 * transferred
+		second.NetworkTxBytes = 4 * transferred
+		gomock.InOrder(
+			vmiInterface.EXPECT().Stats(name).Return(first, nil),
+			vmiInterface.EXPECT().Stats(name).Return(second, nil),
+		)
+	}
+
+	runTop := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := tests.NewVirtctlCommand(append([]string{top.COMMAND_TOP, top.COMMAND_VMI, "--interval", "1ms"}, args...)...)
+		cmd.SetOut(&out)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	It("should show the usage of a single VMI", func() {
+		vmi := newRunningVMI(k8smetav1.NamespaceDefault, "testvmi")
+		vmiInterface.EXPECT().Get("testvmi", &k8smetav1.GetOptions{}).Return(&vmi, nil)
+		expectStats("testvmi", 250*time.Millisecond, 512*1024*1024, 2048)
+
+		out, err := runTop("testvmi")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(MatchRegexp(`NAME\s+CPU\(cores\)\s+MEMORY\(bytes\)\s+STORAGE READ/WRITE\s+NETWORK RX/TX`))
+		Expect(out).To(MatchRegexp(`testvmi\s+250m\s+512Mi\s+2Ki/s/4Ki/s\s+6Ki/s/8Ki/s`))
+	})
+
+	It("should fail if the VMI is not running", func() {
+		vmi := v1.NewMinimalVMI("testvmi")
+		vmiInterface.EXPECT().Get("testvmi", &k8smetav1.GetOptions{}).Return(vmi, nil)
+
+		_, err := runTop("testvmi")
+		Expect(err).To(MatchError(ContainSubstring("is not running")))
+	})
+
+	It("should list the running VMIs of all namespaces sorted by CPU", func() {
+		stopped := v1.NewMinimalVMIWithNS("ns1", "stopped")
+		list := &v1.VirtualMachineInstanceList{Items: []v1.VirtualMachineInstance{
+			newRunningVMI("ns1", "idle"),
+			newRunningVMI("ns2", "busy"),
+			*stopped,
+		}}
+		vmiInterface.EXPECT().List(&k8smetav1.ListOptions{LabelSelector: "app=test"}).Return(list, nil)
+		expectStats("idle", 10*time.Millisecond, 1024*1024*1024, 0)
+		expectStats("busy", 1500*time.Millisecond, 256*1024*1024, 0)
+
+		out, err := runTop("-A", "-l", "app=test", "--sort-by", "cpu", "--no-headers")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(MatchRegexp(`^ns2\s+busy\s+1500m\s+256Mi.*\nns1\s+idle\s+10m\s+1024Mi`))
+		Expect(out).ToNot(ContainSubstring("stopped"))
+	})
+
+	It("should sort by memory", func() {
+		list := &v1.VirtualMachineInstanceList{Items: []v1.VirtualMachineInstance{
+			newRunningVMI(k8smetav1.NamespaceDefault, "small"),
+			newRunningVMI(k8smetav1.NamespaceDefault, "large"),
+		}}
+		vmiInterface.EXPECT().List(&k8smetav1.ListOptions{}).Return(list, nil)
+		expectStats("small", 0, 128*1024*1024, 0)
+		expectStats("large", 0, 2048*1024*1024, 0)
+
+		out, err := runTop("--sort-by", "memory", "--no-headers")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(MatchRegexp(`^large\s+.*\nsmall\s+`))
+	})
+
+	It("should reject an unknown sort field", func() {
+		_, err := runTop("--sort-by", "disk")
+		Expect(err).To(MatchError(ContainSubstring("--sort-by accepts only cpu or memory")))
+	})
+
+	It("should reject a VMI name together with --all-namespaces", func() {
+		_, err := runTop("-A", "testvmi")
+		Expect(err).To(HaveOccurred())
+	})
+})
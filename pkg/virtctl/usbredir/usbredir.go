@@ -0,0 +1,190 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package usbredir
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const (
+	COMMAND_USBREDIR = "usbredir"
+	LISTEN_TIMEOUT   = 60 * time.Second
+
+	// usbredirect of the usbredir project connects a local USB device to a
+	// usbredir server, see https://gitlab.freedesktop.org/spice/usbredir
+	USBREDIR_CLIENT = "usbredirect"
+)
+
+// usbredirect accepts either vendor:product or bus-device to select a device
+var deviceFormat = regexp.MustCompile(`^([0-9a-fA-F]{4}:[0-9a-fA-F]{4}|[0-9]+-[0-9]+)$`)
+
+func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usbredir (vendor:product|bus-device) (VMI)",
+		Short: "Redirect a local USB device to a virtual machine instance.",
+		Long: `Redirect a local USB device to a virtual machine instance.
+
+The virtual machine instance needs spec.domain.devices.clientPassthrough to
+accept redirected USB devices. The usbredirect binary of the usbredir project
+has to be present in the $PATH.`,
+		Example: usage(),
+		Args:    templates.ExactArgs(COMMAND_USBREDIR, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := USBRedir{clientConfig: clientConfig}
+			return c.Run(cmd, args)
+		},
+	}
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Redirect the local USB device with the vendor id 0951 and the product id 1666 to 'testvmi':\n"
+	usage += "  {{ProgramName}} usbredir 0951:1666 testvmi\n\n"
+	usage += "  # Redirect the local USB device on bus 3 with the device number 4 to 'testvmi':\n"
+	usage += "  {{ProgramName}} usbredir 3-4 testvmi"
+	return usage
+}
+
+type USBRedir struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func (o *USBRedir) Run(cmd *cobra.Command, args []string) error {
+	device, vmi := args[0], args[1]
+	if err := validateDevice(device); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(USBREDIR_CLIENT); err != nil {
+		return fmt.Errorf("could not find the %s binary in $PATH", USBREDIR_CLIENT)
+	}
+
+	namespace, _, err := o.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+
+	virtCli, err := kubecli.GetKubevirtClientFromClientConfig(o.clientConfig)
+	if err != nil {
+		return err
+	}
+
+	// setup connection with VM
+	usbredir, err := virtCli.VirtualMachineInstance(namespace).USBRedir(vmi)
+	if err != nil {
+		return fmt.Errorf("Can't access VMI %s: %s", vmi, err.Error())
+	}
+
+	// The local tcp server is used to proxy the websocket connection to usbredirect
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("Can't listen on a local port: %s", err.Error())
+	}
+	defer ln.Close()
+
+	// usbredirect -> local tcp connection -> VMI
+
+	listenResChan := make(chan error, 1)
+	clientResChan := make(chan error, 1)
+
+	// wait for usbredirect to connect to our local proxy server
+	go func() {
+		listenResChan <- serve(ln.(*net.TCPListener), usbredir)
+	}()
+
+	go func() {
+		port := ln.Addr().(*net.TCPAddr).Port
+		args := usbredirectArgs(device, port)
+		if glog.V(4) {
+			glog.Infof("Executing commandline: '%s %v'", USBREDIR_CLIENT, args)
+		}
+		output, err := exec.Command(USBREDIR_CLIENT, args...).CombinedOutput()
+		if err != nil {
+			glog.Errorf("%s execution failed: %v, output: %v", USBREDIR_CLIENT, err, string(output))
+		} else {
+			glog.V(2).Infof("%s output: %v", USBREDIR_CLIENT, string(output))
+		}
+		clientResChan <- err
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	select {
+	case <-interrupt:
+	case err = <-clientResChan:
+	case err = <-listenResChan:
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error encountered: %s", err.Error())
+	}
+	return nil
+}
+
+// validateDevice rejects devices which usbredirect would not understand
+func validateDevice(device string) error {
+	if !deviceFormat.MatchString(device) {
+		return fmt.Errorf("invalid USB device %s, expected vendor:product or bus-device", device)
+	}
+	return nil
+}
+
+func usbredirectArgs(device string, port int) []string {
+	return []string{"--device", device, "--to", fmt.Sprintf("127.0.0.1:%d", port)}
+}
+
+// serve accepts the connection of usbredirect and tunnels it through the stream
+func serve(ln *net.TCPListener, stream kubecli.StreamInterface) error {
+	start := time.Now()
+	// exit early if spawning usbredirect fails
+	ln.SetDeadline(start.Add(LISTEN_TIMEOUT))
+
+	conn, err := ln.Accept()
+	if err != nil {
+		glog.V(2).Infof("Failed to accept the connection of %s: %s", USBREDIR_CLIENT, err.Error())
+		return err
+	}
+	defer conn.Close()
+
+	glog.V(2).Infof("%s connected in %v", USBREDIR_CLIENT, time.Now().Sub(start))
+
+	// transfer data from/to the VM until either side hangs up
+	if err = stream.Stream(kubecli.StreamOptions{In: conn, Out: conn}); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package usbredir
+
+import (
+	"io"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/kubecli"
+)
+
+// echoStream sends everything it reads back to the client
+type echoStream struct{}
+
+func (echoStream) Stream(options kubecli.StreamOptions) error {
+	_, err := io.Copy(options.Out, options.In)
+	return err
+}
+
+var _ = Describe("USBRedir", func() {
+
+	table.DescribeTable("should accept the device", func(device string) {
+		Expect(validateDevice(device)).To(Succeed())
+	},
+		table.Entry("by vendor and product", "0951:1666"),
+		table.Entry("by vendor and product in upper case", "04F2:B6BE"),
+		table.Entry("by bus and device number", "3-4"),
+	)
+
+	table.DescribeTable("should reject the device", func(device string) {
+		Expect(validateDevice(device)).ToNot(Succeed())
+	},
+		table.Entry("without a product", "0951"),
+		table.Entry("with a non hex vendor", "09x1:1666"),
+		table.Entry("with a missing device number", "3-"),
+		table.Entry("with a path", "/dev/bus/usb/003/004"),
+	)
+
+	It("should point usbredirect to the local port", func() {
+		Expect(usbredirectArgs("0951:1666", 4711)).To(Equal([]string{"--device", "0951:1666", "--to", "127.0.0.1:4711"}))
+	})
+
+	It("should tunnel the connection of usbredirect through the stream", func() {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer ln.Close()
+
+		done := make(chan error)
+		go func() {
+			done <- serve(ln.(*net.TCPListener), echoStream{})
+		}()
+
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+		_, err = conn.Write([]byte("usbredir"))
+		Expect(err).ToNot(HaveOccurred())
+		buf := make([]byte, len("usbredir"))
+		_, err = io.ReadFull(conn, buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf)).To(Equal("usbredir"))
+
+		conn.Close()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
@@ -45,8 +45,8 @@ const (
 )
 
 var (
-	forceRestart bool
-	gracePeriod  int = -1
+	force       bool
+	gracePeriod int = -1
 )
 
 func NewStartCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
@@ -75,6 +75,8 @@ func NewStopCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 			return c.Run(cmd, args)
 		},
 	}
+	cmd.Flags().BoolVar(&force, "force", false, "--force=false: Only used when grace-period=0. If true, immediately remove VMI pod from API and bypass graceful deletion. Note that immediate deletion of some resources may result in inconsistency or data loss and requires confirmation.")
+	cmd.Flags().IntVar(&gracePeriod, "grace-period", -1, "--grace-period=-1: Period of time in seconds given to the VMI to terminate gracefully. Can only be set to 0 when --force is true (force deletion). Currently only setting 0 is supported.")
 	cmd.SetUsageTemplate(templates.UsageTemplate())
 	return cmd
 }
@@ -90,8 +92,8 @@ func NewRestartCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 			return c.Run(cmd, args)
 		},
 	}
-	cmd.Flags().BoolVar(&forceRestart, "force", forceRestart, "--force=false: Only used when grace-period=0. If true, immediately remove VMI pod from API and bypass graceful deletion. Note that immediate deletion of some resources may result in inconsistency or data loss and requires confirmation.")
-	cmd.Flags().IntVar(&gracePeriod, "grace-period", gracePeriod, "--grace-period=-1: Period of time in seconds given to the VMI to terminate gracefully. Can only be set to 0 when --force is true (force deletion). Currently only setting 0 is supported.")
+	cmd.Flags().BoolVar(&force, "force", false, "--force=false: Only used when grace-period=0. If true, immediately remove VMI pod from API and bypass graceful deletion. Note that immediate deletion of some resources may result in inconsistency or data loss and requires confirmation.")
+	cmd.Flags().IntVar(&gracePeriod, "grace-period", -1, "--grace-period=-1: Period of time in seconds given to the VMI to terminate gracefully. Can only be set to 0 when --force is true (force deletion). Currently only setting 0 is supported.")
 	cmd.SetUsageTemplate(templates.UsageTemplate())
 	return cmd
 }
@@ -209,15 +211,28 @@ func (o *Command) Run(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("Error starting VirtualMachine %v", err)
 		}
 	case COMMAND_STOP:
+		if gracePeriod != -1 && force == false {
+			return fmt.Errorf("Can not set gracePeriod without --force=true")
+		}
+		if force {
+			if gracePeriod == -1 {
+				return fmt.Errorf("Can not force stop without gracePeriod")
+			}
+			err = virtClient.VirtualMachine(namespace).ForceStop(vmiName, gracePeriod)
+			if err != nil {
+				return fmt.Errorf("Error stopping VirtualMachine, %v", err)
+			}
+			break
+		}
 		err = virtClient.VirtualMachine(namespace).Stop(vmiName)
 		if err != nil {
 			return fmt.Errorf("Error stopping VirtualMachine %v", err)
 		}
 	case COMMAND_RESTART:
-		if gracePeriod != -1 && forceRestart == false {
+		if gracePeriod != -1 && force == false {
 			return fmt.Errorf("Can not set gracePeriod without --force=true")
 		}
-		if forceRestart {
+		if force {
 			if gracePeriod != -1 {
 				err = virtClient.VirtualMachine(namespace).ForceRestart(vmiName, gracePeriod)
 				if err != nil {
@@ -103,6 +103,21 @@ var _ = Describe("VirtualMachine", func() {
 			Expect(cmd.Execute()).To(BeNil())
 		})
 
+		It("should force stop vm", func() {
+			vm := kubecli.NewMinimalVM(vmName)
+
+			kubecli.MockKubevirtClientInstance.EXPECT().VirtualMachine(k8smetav1.NamespaceDefault).Return(vmInterface).Times(1)
+			vmInterface.EXPECT().ForceStop(vm.Name, 0).Return(nil).Times(1)
+
+			cmd := tests.NewVirtctlCommand("stop", vmName, "--force", "--grace-period=0")
+			Expect(cmd.Execute()).To(BeNil())
+		})
+
+		It("should not force stop vm without a grace period", func() {
+			cmd := tests.NewVirtctlCommand("stop", vmName, "--force")
+			Expect(cmd.Execute()).ToNot(Succeed())
+		})
+
 		Context("Using RunStrategy", func() {
 			It("with spec:runStrategy:running", func() {
 				vm := kubecli.NewMinimalVM(vmName)
@@ -0,0 +1,284 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package vmexport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/templates"
+)
+
+const (
+	COMMAND_VMEXPORT = "vmexport"
+	COMMAND_DOWNLOAD = "download"
+
+	// the port the export server listens on in its pod
+	exportServerPort = 8443
+)
+
+var (
+	volume      string
+	format      string
+	output      string
+	manifest    bool
+	portForward bool
+)
+
+func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vmexport",
+		Short: "Download the volumes of a VirtualMachineExport.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("use one of the subcommands: %s", COMMAND_DOWNLOAD)
+		},
+	}
+	cmd.AddCommand(newDownloadCommand(clientConfig))
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func newDownloadCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download (VMEXPORT)",
+		Short: "Download a volume or the manifest of a VirtualMachineExport.",
+		Long: `Download a volume or the manifest of a VirtualMachineExport.
+
+The export has to be ready. The token is read from the Secret referenced by the
+export. Without --port-forward the export server has to be reachable through its
+service, which usually requires running inside the cluster.`,
+		Example: usage(),
+		Args:    templates.ExactArgs(COMMAND_DOWNLOAD, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d := download{clientConfig: clientConfig}
+			return d.run(args[0])
+		},
+	}
+	cmd.Flags().StringVar(&volume, "volume", "", "The volume to download")
+	cmd.Flags().StringVar(&format, "format", string(v1.ExportVolumeFormatRaw), "The image format to download the volume in, raw or qcow2")
+	cmd.Flags().StringVar(&output, "output", "", "The file to write the volume or the manifest to")
+	cmd.Flags().BoolVar(&manifest, "manifest", false, "Download the VirtualMachine manifest instead of a volume")
+	cmd.Flags().BoolVar(&portForward, "port-forward", false, "Connect to the export server through a port-forward to its pod")
+	cmd.SetUsageTemplate(templates.UsageTemplate())
+	return cmd
+}
+
+func usage() string {
+	usage := "  # Download the volume 'rootdisk' of the export 'myexport' as qcow2 image:\n"
+	usage += "  {{ProgramName}} vmexport download myexport --volume rootdisk --format qcow2 --output disk.qcow2 --port-forward\n\n"
+	usage += "  # Download the manifest which imports the volumes of 'myexport' into another cluster:\n"
+	usage += "  {{ProgramName}} vmexport download myexport --manifest --output vm.json --port-forward"
+	return usage
+}
+
+type download struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func (d *download) run(name string) error {
+	if output == "" {
+		return fmt.Errorf("--output is required")
+	}
+	if manifest == (volume != "") {
+		return fmt.Errorf("either --volume or --manifest is required")
+	}
+
+	namespace, _, err := d.clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+	virtClient, err := kubecli.GetKubevirtClientFromClientConfig(d.clientConfig)
+	if err != nil {
+		return fmt.Errorf("cannot obtain KubeVirt client: %v", err)
+	}
+
+	export, err := virtClient.VirtualMachineExport(namespace).Get(name, &k8smetav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if export.Status.Phase != v1.VirtualMachineExportReady {
+		return fmt.Errorf("VirtualMachineExport %s is not ready: %s", name, export.Status.Message)
+	}
+	link, err := exportLink(export, volume, v1.ExportVolumeFormat(format), manifest)
+	if err != nil {
+		return err
+	}
+
+	secret, err := virtClient.CoreV1().Secrets(namespace).Get(export.Spec.TokenSecretRef, k8smetav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	token, exists := secret.Data[v1.VirtualMachineExportTokenKey]
+	if !exists {
+		return fmt.Errorf("Secret %s has no %s key", secret.Name, v1.VirtualMachineExportTokenKey)
+	}
+
+	client, err := newHTTPClient(export.Status.Cert)
+	if err != nil {
+		return err
+	}
+	if portForward {
+		stopChan := make(chan struct{})
+		defer close(stopChan)
+		localPort, err := d.forwardExportServer(virtClient, export, stopChan)
+		if err != nil {
+			return err
+		}
+		// Keep the service host for the TLS verification, but connect to the forwarded port
+		localAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(localPort)))
+		client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, localAddr)
+		}
+	}
+
+	return downloadTo(client, link, string(token), output)
+}
+
+// exportLink returns the link to the requested volume format or the manifest
+func exportLink(export *v1.VirtualMachineExport, volume string, format v1.ExportVolumeFormat, manifest bool) (string, error) {
+	if manifest {
+		return export.Status.Manifest, nil
+	}
+	for _, exportVolume := range export.Status.Volumes {
+		if exportVolume.Name != volume {
+			continue
+		}
+		for _, exportFormat := range exportVolume.Formats {
+			if exportFormat.Format == format {
+				return exportFormat.Url, nil
+			}
+		}
+		return "", fmt.Errorf("volume %s is not exported as %s", volume, format)
+	}
+	return "", fmt.Errorf("volume %s is not exported by %s", volume, export.Name)
+}
+
+func newHTTPClient(cert string) (*http.Client, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(cert)) {
+		return nil, fmt.Errorf("the VirtualMachineExport has no valid certificate")
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots},
+		},
+	}, nil
+}
+
+func downloadTo(client *http.Client, link string, token string, output string) error {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(v1.VirtualMachineExportTokenHeader, token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("download failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+// forwardExportServer forwards a local port to the export server pod and
+// returns the local port
+func (d *download) forwardExportServer(virtClient kubecli.KubevirtClient, export *v1.VirtualMachineExport, stopChan chan struct{}) (uint16, error) {
+	pods, err := virtClient.CoreV1().Pods(export.Namespace).List(k8smetav1.ListOptions{
+		LabelSelector: v1.VirtualMachineExportLabel + "=" + export.Name,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var pod *k8sv1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == k8sv1.PodRunning {
+			pod = &pods.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return 0, fmt.Errorf("no running export server found for %s", export.Name)
+	}
+
+	config, err := d.clientConfig.ClientConfig()
+	if err != nil {
+		return 0, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return 0, err
+	}
+	portForwardURL := virtClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, portForwardURL)
+
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", exportServerPort)}, stopChan, readyChan, ioutil.Discard, os.Stderr)
+	if err != nil {
+		return 0, err
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+	select {
+	case <-readyChan:
+	case err := <-errChan:
+		return 0, fmt.Errorf("port-forward to %s failed: %v", pod.Name, err)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	return ports[0].Local, nil
+}
@@ -0,0 +1,138 @@
+package vmexport_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/tests"
+)
+
+var _ = Describe("VirtualMachineExport", func() {
+
+	const exportName = "testexport"
+	var exportInterface *kubecli.MockVirtualMachineExportInterface
+	var ctrl *gomock.Controller
+	var server *httptest.Server
+	var export *v1.VirtualMachineExport
+	var tmpDir string
+	var output string
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		kubecli.GetKubevirtClientFromClientConfig = kubecli.GetMockKubevirtClientFromClientConfig
+		kubecli.MockKubevirtClientInstance = kubecli.NewMockKubevirtClient(ctrl)
+		exportInterface = kubecli.NewMockVirtualMachineExportInterface(ctrl)
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(v1.VirtualMachineExportTokenHeader) != "secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(r.URL.Path))
+		}))
+
+		export = kubecli.NewMinimalVirtualMachineExport(exportName)
+		export.Namespace = k8smetav1.NamespaceDefault
+		export.Spec.TokenSecretRef = "token"
+		export.Status = v1.VirtualMachineExportStatus{
+			Phase:    v1.VirtualMachineExportReady,
+			Cert:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+			Manifest: server.URL + "/manifest",
+			Volumes: []v1.VirtualMachineExportVolume{{
+				Name: "disk0",
+				Formats: []v1.VirtualMachineExportVolumeFormat{
+					{Format: v1.ExportVolumeFormatRaw, Url: server.URL + "/volumes/disk0/disk.img"},
+					{Format: v1.ExportVolumeFormatQcow2, Url: server.URL + "/volumes/disk0/disk.qcow2"},
+				},
+			}},
+		}
+
+		kubeclient := fake.NewSimpleClientset(&k8sv1.Secret{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: "token", Namespace: k8smetav1.NamespaceDefault},
+			Data:       map[string][]byte{v1.VirtualMachineExportTokenKey: []byte("secret")},
+		})
+		kubecli.MockKubevirtClientInstance.EXPECT().CoreV1().Return(kubeclient.CoreV1()).AnyTimes()
+		kubecli.MockKubevirtClientInstance.EXPECT().VirtualMachineExport(k8smetav1.NamespaceDefault).Return(exportInterface).AnyTimes()
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "vmexport")
+		Expect(err).ToNot(HaveOccurred())
+		output = filepath.Join(tmpDir, "out")
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(tmpDir)
+		ctrl.Finish()
+	})
+
+	Context("With missing input parameters", func() {
+		It("should fail without a name", func() {
+			cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download")
+			Expect(cmd()).NotTo(Succeed())
+		})
+
+		It("should fail without an output", func() {
+			cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--volume", "disk0")
+			Expect(cmd()).NotTo(Succeed())
+		})
+
+		It("should fail without a volume or the manifest", func() {
+			cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--output", output)
+			Expect(cmd()).NotTo(Succeed())
+		})
+	})
+
+	It("should download the raw image", func() {
+		exportInterface.EXPECT().Get(exportName, gomock.Any()).Return(export, nil)
+
+		cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--volume", "disk0", "--output", output)
+		Expect(cmd()).To(Succeed())
+		Expect(ioutil.ReadFile(output)).To(Equal([]byte("/volumes/disk0/disk.img")))
+	})
+
+	It("should download the qcow2 image", func() {
+		exportInterface.EXPECT().Get(exportName, gomock.Any()).Return(export, nil)
+
+		cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--volume", "disk0", "--format", "qcow2", "--output", output)
+		Expect(cmd()).To(Succeed())
+		Expect(ioutil.ReadFile(output)).To(Equal([]byte("/volumes/disk0/disk.qcow2")))
+	})
+
+	It("should download the manifest", func() {
+		exportInterface.EXPECT().Get(exportName, gomock.Any()).Return(export, nil)
+
+		cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--manifest", "--output", output)
+		Expect(cmd()).To(Succeed())
+		Expect(ioutil.ReadFile(output)).To(Equal([]byte("/manifest")))
+	})
+
+	It("should fail for unknown volumes", func() {
+		exportInterface.EXPECT().Get(exportName, gomock.Any()).Return(export, nil)
+
+		cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--volume", "disk1", "--output", output)
+		Expect(cmd()).To(MatchError(ContainSubstring("volume disk1 is not exported")))
+	})
+
+	It("should fail if the export is not ready", func() {
+		export.Status.Phase = v1.VirtualMachineExportPending
+		export.Status.Message = "VirtualMachine is running"
+		exportInterface.EXPECT().Get(exportName, gomock.Any()).Return(export, nil)
+
+		cmd := tests.NewRepeatableVirtctlCommand("vmexport", "download", exportName, "--volume", "disk0", "--output", output)
+		Expect(cmd()).To(MatchError(ContainSubstring("is not ready")))
+	})
+})
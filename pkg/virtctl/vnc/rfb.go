@@ -0,0 +1,446 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package vnc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/golang/glog"
+
+	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/handoff"
+)
+
+// RFB protocol constants, see https://github.com/rfbproto/rfbproto/blob/master/rfbproto.rst
+const (
+	rfbVersionLength    = 12
+	rfbServerInitLength = 24
+
+	rfbSecurityNone uint8 = 1
+
+	rfbSetPixelFormat           uint8 = 0
+	rfbSetEncodings             uint8 = 2
+	rfbFramebufferUpdateRequest uint8 = 3
+	rfbKeyEvent                 uint8 = 4
+	rfbPointerEvent             uint8 = 5
+	rfbClientCutText            uint8 = 6
+	rfbQEMUClientMessage        uint8 = 255
+
+	rfbQEMUExtendedKeyEvent uint8  = 0
+	rfbQEMUAudio            uint8  = 1
+	rfbQEMUAudioSetFormat   uint16 = 2
+)
+
+// rfbProxy relays the connection of a local VNC viewer to the VNC subresource
+// of a VMI. It keeps track of the RFB handshake the viewer performed, so that
+// the session can be replayed against a new connection once the VMI was live
+// migrated, without the viewer noticing more than a short pause.
+type rfbProxy struct {
+	dial    func() (kubecli.StreamInterface, error)
+	tracker *handoff.Tracker
+
+	viewer io.ReadWriter
+
+	// the messages the viewer sent during the handshake, which are replayed on reconnect
+	clientVersion []byte
+	securityType  uint8
+	clientInit    []byte
+	pixelFormat   []byte
+	encodings     []byte
+
+	width  uint16
+	height uint16
+}
+
+// rfbServerConn wraps a single connection to the VNC server of a VMI
+type rfbServerConn struct {
+	in   *io.PipeWriter
+	out  *io.PipeReader
+	done chan error
+}
+
+func newRFBServerConn(stream kubecli.StreamInterface) *rfbServerConn {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	conn := &rfbServerConn{
+		in:   inWriter,
+		out:  outReader,
+		done: make(chan error, 1),
+	}
+	go func() {
+		err := stream.Stream(kubecli.StreamOptions{
+			In:  inReader,
+			Out: outWriter,
+		})
+		// unblock everyone still reading from or writing to this connection
+		inReader.CloseWithError(io.ErrClosedPipe)
+		outWriter.CloseWithError(io.EOF)
+		conn.done <- err
+	}()
+	return conn
+}
+
+func (c *rfbServerConn) close() {
+	c.in.Close()
+	c.out.Close()
+}
+
+// Run relays the viewer to the given VNC stream until either side closes the connection.
+func (p *rfbProxy) Run(stream kubecli.StreamInterface) error {
+	server := newRFBServerConn(stream)
+	defer func() { server.close() }()
+
+	replayable, err := p.handshake(server)
+	if err != nil {
+		return err
+	}
+	if !replayable || p.tracker == nil {
+		return p.relayRaw(server)
+	}
+
+	messages := make(chan []byte)
+	viewerErr := make(chan error, 1)
+	go func() {
+		defer close(messages)
+		for {
+			msg, err := p.readClientMessage()
+			if err == io.EOF {
+				viewerErr <- nil
+				return
+			} else if err != nil {
+				viewerErr <- err
+				return
+			}
+			messages <- msg
+		}
+	}()
+
+	for {
+		serverErr := make(chan error, 1)
+		go func(out io.Reader) {
+			_, err := io.Copy(p.viewer, out)
+			serverErr <- err
+		}(server.out)
+
+	relay:
+		for {
+			select {
+			case msg, ok := <-messages:
+				if !ok {
+					return <-viewerErr
+				}
+				if _, err := server.in.Write(msg); err != nil {
+					// the message is lost, the viewer gets a full framebuffer update after the reconnect
+					glog.V(3).Infof("Failed to relay message to the VNC server: %v", err)
+				}
+			case err := <-serverErr:
+				glog.V(2).Infof("Connection to the VNC server closed: %v", err)
+				break relay
+			}
+		}
+
+		connErr := <-server.done
+		migrated, err := p.tracker.WaitForTarget()
+		if err != nil || !migrated {
+			return connErr
+		}
+		glog.Infof("The virtual machine instance was migrated, reconnecting")
+
+		stream, err := p.dial()
+		if err != nil {
+			return err
+		}
+		server = newRFBServerConn(stream)
+		if err := p.tracker.Connected(); err != nil {
+			return err
+		}
+		if err := p.replay(server); err != nil {
+			return err
+		}
+	}
+}
+
+// relayRaw copies the connection in both directions without looking at the messages
+func (p *rfbProxy) relayRaw(server *rfbServerConn) error {
+	errChan := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(p.viewer, server.out)
+		errChan <- err
+	}()
+	go func() {
+		_, err := io.Copy(server.in, p.viewer)
+		errChan <- err
+	}()
+	return <-errChan
+}
+
+// handshake relays the initial RFB handshake between the viewer and the server and
+// records the viewer side of it. It returns false if the negotiated session can't be replayed.
+func (p *rfbProxy) handshake(server *rfbServerConn) (bool, error) {
+	if _, err := relay(p.viewer, server.out, rfbVersionLength); err != nil {
+		return false, err
+	}
+	version, err := relay(server.in, p.viewer, rfbVersionLength)
+	if err != nil {
+		return false, err
+	}
+	p.clientVersion = version
+
+	minor, err := protocolMinorVersion(version)
+	if err != nil {
+		return false, err
+	}
+
+	if minor >= 7 {
+		count, err := relay(p.viewer, server.out, 1)
+		if err != nil {
+			return false, err
+		}
+		if _, err := relay(p.viewer, server.out, int(count[0])); err != nil {
+			return false, err
+		}
+		securityType, err := relay(server.in, p.viewer, 1)
+		if err != nil {
+			return false, err
+		}
+		p.securityType = securityType[0]
+		if p.securityType != rfbSecurityNone {
+			return false, nil
+		}
+		if minor >= 8 {
+			if _, err := relay(p.viewer, server.out, 4); err != nil {
+				return false, err
+			}
+		}
+	} else {
+		securityType, err := relay(p.viewer, server.out, 4)
+		if err != nil {
+			return false, err
+		}
+		p.securityType = uint8(binary.BigEndian.Uint32(securityType))
+		if p.securityType != rfbSecurityNone {
+			return false, nil
+		}
+	}
+
+	if p.clientInit, err = relay(server.in, p.viewer, 1); err != nil {
+		return false, err
+	}
+	serverInit, err := relay(p.viewer, server.out, rfbServerInitLength)
+	if err != nil {
+		return false, err
+	}
+	p.width = binary.BigEndian.Uint16(serverInit[0:2])
+	p.height = binary.BigEndian.Uint16(serverInit[2:4])
+	if _, err := relay(p.viewer, server.out, int(binary.BigEndian.Uint32(serverInit[20:24]))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// replay performs the recorded handshake against a new server connection, swallows
+// the responses the viewer has already seen and requests a full framebuffer update.
+func (p *rfbProxy) replay(server *rfbServerConn) error {
+	if _, err := readN(server.out, rfbVersionLength); err != nil {
+		return err
+	}
+	if _, err := server.in.Write(p.clientVersion); err != nil {
+		return err
+	}
+	minor, err := protocolMinorVersion(p.clientVersion)
+	if err != nil {
+		return err
+	}
+	if minor >= 7 {
+		count, err := readN(server.out, 1)
+		if err != nil {
+			return err
+		}
+		types, err := readN(server.out, int(count[0]))
+		if err != nil {
+			return err
+		}
+		if !containsSecurityType(types, p.securityType) {
+			return fmt.Errorf("the VNC server no longer offers security type %d", p.securityType)
+		}
+		if _, err := server.in.Write([]byte{p.securityType}); err != nil {
+			return err
+		}
+		if minor >= 8 {
+			result, err := readN(server.out, 4)
+			if err != nil {
+				return err
+			}
+			if binary.BigEndian.Uint32(result) != 0 {
+				return fmt.Errorf("the VNC server rejected the security handshake")
+			}
+		}
+	} else {
+		securityType, err := readN(server.out, 4)
+		if err != nil {
+			return err
+		}
+		if uint8(binary.BigEndian.Uint32(securityType)) != p.securityType {
+			return fmt.Errorf("the VNC server no longer offers security type %d", p.securityType)
+		}
+	}
+
+	if _, err := server.in.Write(p.clientInit); err != nil {
+		return err
+	}
+	serverInit, err := readN(server.out, rfbServerInitLength)
+	if err != nil {
+		return err
+	}
+	if _, err := readN(server.out, int(binary.BigEndian.Uint32(serverInit[20:24]))); err != nil {
+		return err
+	}
+
+	for _, msg := range [][]byte{p.pixelFormat, p.encodings, p.fullUpdateRequest()} {
+		if msg == nil {
+			continue
+		}
+		if _, err := server.in.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *rfbProxy) fullUpdateRequest() []byte {
+	msg := make([]byte, 10)
+	msg[0] = rfbFramebufferUpdateRequest
+	binary.BigEndian.PutUint16(msg[6:8], p.width)
+	binary.BigEndian.PutUint16(msg[8:10], p.height)
+	return msg
+}
+
+// readClientMessage reads exactly one message sent by the viewer after the handshake
+func (p *rfbProxy) readClientMessage() ([]byte, error) {
+	msgType, err := readN(p.viewer, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var rest []byte
+	switch msgType[0] {
+	case rfbSetPixelFormat:
+		rest, err = readN(p.viewer, 19)
+	case rfbSetEncodings:
+		if rest, err = readN(p.viewer, 3); err == nil {
+			rest, err = readMore(p.viewer, rest, 4*int(binary.BigEndian.Uint16(rest[1:3])))
+		}
+	case rfbFramebufferUpdateRequest:
+		rest, err = readN(p.viewer, 9)
+	case rfbKeyEvent:
+		rest, err = readN(p.viewer, 7)
+	case rfbPointerEvent:
+		rest, err = readN(p.viewer, 5)
+	case rfbClientCutText:
+		if rest, err = readN(p.viewer, 7); err == nil {
+			rest, err = readMore(p.viewer, rest, int(binary.BigEndian.Uint32(rest[3:7])))
+		}
+	case rfbQEMUClientMessage:
+		rest, err = p.readQEMUClientMessage()
+	default:
+		return nil, fmt.Errorf("unsupported RFB client message type %d", msgType[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg := append(msgType, rest...)
+	switch msgType[0] {
+	case rfbSetPixelFormat:
+		p.pixelFormat = msg
+	case rfbSetEncodings:
+		p.encodings = msg
+	}
+	return msg, nil
+}
+
+func (p *rfbProxy) readQEMUClientMessage() ([]byte, error) {
+	subtype, err := readN(p.viewer, 1)
+	if err != nil {
+		return nil, err
+	}
+	switch subtype[0] {
+	case rfbQEMUExtendedKeyEvent:
+		return readMore(p.viewer, subtype, 10)
+	case rfbQEMUAudio:
+		op, err := readMore(p.viewer, subtype, 2)
+		if err != nil {
+			return nil, err
+		}
+		if binary.BigEndian.Uint16(op[1:3]) == rfbQEMUAudioSetFormat {
+			return readMore(p.viewer, op, 6)
+		}
+		return op, nil
+	default:
+		return nil, fmt.Errorf("unsupported QEMU client message subtype %d", subtype[0])
+	}
+}
+
+func protocolMinorVersion(version []byte) (int, error) {
+	var major, minor int
+	if _, err := fmt.Sscanf(string(version), "RFB %03d.%03d\n", &major, &minor); err != nil {
+		return 0, fmt.Errorf("invalid RFB protocol version %q: %v", string(version), err)
+	}
+	return minor, nil
+}
+
+func containsSecurityType(types []byte, securityType uint8) bool {
+	for _, t := range types {
+		if t == securityType {
+			return true
+		}
+	}
+	return false
+}
+
+// relay copies exactly n bytes from src to dst and returns them
+func relay(dst io.Writer, src io.Reader, n int) ([]byte, error) {
+	buf, err := readN(src, n)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dst.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func readN(src io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(src, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func readMore(src io.Reader, buf []byte, n int) ([]byte, error) {
+	more, err := readN(src, n)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, more...), nil
+}
@@ -0,0 +1,173 @@
+package vnc
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"kubevirt.io/client-go/kubecli"
+)
+
+// fakeStream connects a kubecli stream to an in-memory VNC server
+type fakeStream struct {
+	conn net.Conn
+}
+
+func (s *fakeStream) Stream(options kubecli.StreamOptions) error {
+	errChan := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(s.conn, options.In)
+		errChan <- err
+	}()
+	go func() {
+		_, err := io.Copy(options.Out, s.conn)
+		errChan <- err
+	}()
+	err := <-errChan
+	s.conn.Close()
+	return err
+}
+
+func serverInit(width, height uint16, name string) []byte {
+	msg := make([]byte, rfbServerInitLength)
+	binary.BigEndian.PutUint16(msg[0:2], width)
+	binary.BigEndian.PutUint16(msg[2:4], height)
+	binary.BigEndian.PutUint32(msg[20:24], uint32(len(name)))
+	return append(msg, []byte(name)...)
+}
+
+// serveHandshake plays the server side of an RFB 3.8 handshake without authentication
+func serveHandshake(conn net.Conn) {
+	defer GinkgoRecover()
+	_, err := conn.Write([]byte("RFB 003.008\n"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, rfbVersionLength)).To(Equal([]byte("RFB 003.008\n")))
+	_, err = conn.Write([]byte{1, rfbSecurityNone})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, 1)).To(Equal([]byte{rfbSecurityNone}))
+	_, err = conn.Write([]byte{0, 0, 0, 0})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, 1)).To(Equal([]byte{1}))
+	_, err = conn.Write(serverInit(800, 600, "vmi"))
+	Expect(err).ToNot(HaveOccurred())
+}
+
+// viewHandshake plays the viewer side of an RFB 3.8 handshake without authentication
+func viewHandshake(conn net.Conn) {
+	defer GinkgoRecover()
+	Expect(readN(conn, rfbVersionLength)).To(Equal([]byte("RFB 003.008\n")))
+	_, err := conn.Write([]byte("RFB 003.008\n"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, 2)).To(Equal([]byte{1, rfbSecurityNone}))
+	_, err = conn.Write([]byte{rfbSecurityNone})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, 4)).To(Equal([]byte{0, 0, 0, 0}))
+	_, err = conn.Write([]byte{1})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(readN(conn, rfbServerInitLength+3)).To(Equal(serverInit(800, 600, "vmi")))
+}
+
+var _ = Describe("RFB proxy", func() {
+
+	var viewer, viewerProxySide net.Conn
+	var proxy *rfbProxy
+
+	BeforeEach(func() {
+		viewer, viewerProxySide = net.Pipe()
+		proxy = &rfbProxy{viewer: viewerProxySide}
+	})
+
+	AfterEach(func() {
+		viewer.Close()
+		viewerProxySide.Close()
+	})
+
+	It("should relay and record the handshake", func() {
+		server, serverProxySide := net.Pipe()
+		defer server.Close()
+		go serveHandshake(server)
+		go viewHandshake(viewer)
+
+		replayable, err := proxy.handshake(newRFBServerConn(&fakeStream{conn: serverProxySide}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(replayable).To(BeTrue())
+		Expect(proxy.clientVersion).To(Equal([]byte("RFB 003.008\n")))
+		Expect(proxy.securityType).To(Equal(rfbSecurityNone))
+		Expect(proxy.width).To(Equal(uint16(800)))
+		Expect(proxy.height).To(Equal(uint16(600)))
+	})
+
+	It("should not replay sessions which require authentication", func() {
+		server, serverProxySide := net.Pipe()
+		defer server.Close()
+		go func() {
+			defer GinkgoRecover()
+			server.Write([]byte("RFB 003.008\n"))
+			readN(server, rfbVersionLength)
+			server.Write([]byte{1, 2})
+			readN(server, 1)
+		}()
+		go func() {
+			defer GinkgoRecover()
+			readN(viewer, rfbVersionLength)
+			viewer.Write([]byte("RFB 003.008\n"))
+			readN(viewer, 2)
+			viewer.Write([]byte{2})
+		}()
+
+		replayable, err := proxy.handshake(newRFBServerConn(&fakeStream{conn: serverProxySide}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(replayable).To(BeFalse())
+	})
+
+	It("should read complete client messages", func() {
+		encodings := []byte{rfbSetEncodings, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1}
+		cutText := []byte{rfbClientCutText, 0, 0, 0, 0, 0, 0, 3, 'a', 'b', 'c'}
+		keyEvent := []byte{rfbQEMUClientMessage, rfbQEMUExtendedKeyEvent, 0, 1, 0, 0, 0, 30, 0, 0, 0, 30}
+		go func() {
+			defer GinkgoRecover()
+			for _, msg := range [][]byte{encodings, cutText, keyEvent} {
+				_, err := viewer.Write(msg)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}()
+
+		Expect(proxy.readClientMessage()).To(Equal(encodings))
+		Expect(proxy.readClientMessage()).To(Equal(cutText))
+		Expect(proxy.readClientMessage()).To(Equal(keyEvent))
+		Expect(proxy.encodings).To(Equal(encodings))
+	})
+
+	It("should fail on unknown client messages", func() {
+		go viewer.Write([]byte{42})
+
+		_, err := proxy.readClientMessage()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should replay the session against a new server", func() {
+		proxy.clientVersion = []byte("RFB 003.008\n")
+		proxy.securityType = rfbSecurityNone
+		proxy.clientInit = []byte{1}
+		proxy.encodings = []byte{rfbSetEncodings, 0, 0, 1, 0, 0, 0, 0}
+		proxy.width = 800
+		proxy.height = 600
+
+		server, serverProxySide := net.Pipe()
+		defer server.Close()
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			serveHandshake(server)
+			Expect(readN(server, 8)).To(Equal(proxy.encodings))
+			Expect(readN(server, 10)).To(Equal([]byte{rfbFramebufferUpdateRequest, 0, 0, 0, 0, 0, 3, 32, 2, 88}))
+		}()
+
+		Expect(proxy.replay(newRFBServerConn(&fakeStream{conn: serverProxySide}))).To(Succeed())
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -21,7 +21,6 @@ package vnc
 
 import (
 	"fmt"
-	"io"
 	"net"
 	"os"
 	"os/exec"
@@ -35,6 +34,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"kubevirt.io/client-go/kubecli"
+	"kubevirt.io/kubevirt/pkg/virtctl/handoff"
 	"kubevirt.io/kubevirt/pkg/virtctl/templates"
 )
 
@@ -59,6 +59,8 @@ const (
 	TIGER_VNC     = "vncviewer"
 )
 
+var reconnect bool
+
 func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "vnc (VMI)",
@@ -70,6 +72,7 @@ func NewCommand(clientConfig clientcmd.ClientConfig) *cobra.Command {
 			return c.Run(cmd, args)
 		},
 	}
+	cmd.Flags().BoolVar(&reconnect, "reconnect", true, "Reconnect to the VNC server when the virtual machine instance is live migrated.")
 	cmd.SetUsageTemplate(templates.UsageTemplate())
 	return cmd
 }
@@ -107,30 +110,25 @@ func (o *VNC) Run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("Can't listen on unix socket: %s", err.Error())
 	}
+	proxy := &rfbProxy{
+		dial: func() (kubecli.StreamInterface, error) {
+			return virtCli.VirtualMachineInstance(namespace).VNC(vmi)
+		},
+	}
+	if reconnect {
+		if proxy.tracker, err = handoff.NewTracker(virtCli, namespace, vmi); err != nil {
+			return fmt.Errorf("Can't access VMI %s: %s", vmi, err.Error())
+		}
+	}
 	// End of pre-flight checks. Everything looks good, we can start
 	// the goroutines and let the data flow
 
-	//                                       -> pipeInWriter  -> pipeInReader
-	// remote-viewer -> unix sock connection
-	//                                       <- pipeOutReader <- pipeOutWriter
-	pipeInReader, pipeInWriter := io.Pipe()
-	pipeOutReader, pipeOutWriter := io.Pipe()
+	// remote-viewer -> local tcp connection -> rfbProxy -> VMI
 
-	k8ResChan := make(chan error)
 	listenResChan := make(chan error)
 	viewResChan := make(chan error)
 	stopChan := make(chan struct{}, 1)
 	doneChan := make(chan struct{}, 1)
-	writeStop := make(chan error)
-	readStop := make(chan error)
-
-	go func() {
-		// transfer data from/to the VM
-		k8ResChan <- vnc.Stream(kubecli.StreamOptions{
-			In:  pipeInReader,
-			Out: pipeOutWriter,
-		})
-	}()
 
 	// wait for remote-viewer to connect to our local proxy server
 	go func() {
@@ -143,26 +141,15 @@ func (o *VNC) Run(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			glog.V(2).Infof("Failed to accept unix sock connection. %s", err.Error())
 			listenResChan <- err
+			return
 		}
 		defer fd.Close()
 
 		glog.V(2).Infof("remote-viewer connected in %v", time.Now().Sub(start))
 
-		// write to FD <- pipeOutReader
-		go func() {
-			_, err := io.Copy(fd, pipeOutReader)
-			readStop <- err
-		}()
-
-		// read from FD -> pipeInWriter
-		go func() {
-			_, err := io.Copy(pipeInWriter, fd)
-			writeStop <- err
-		}()
-
-		// don't terminate until remote-viewer is done
-		<-doneChan
-		listenResChan <- err
+		// transfer data from/to the VM until either side hangs up
+		proxy.viewer = fd
+		listenResChan <- proxy.Run(vnc)
 	}()
 
 	// execute VNC
@@ -247,9 +234,6 @@ func (o *VNC) Run(cmd *cobra.Command, args []string) error {
 
 	select {
 	case <-stopChan:
-	case err = <-readStop:
-	case err = <-writeStop:
-	case err = <-k8ResChan:
 	case err = <-viewResChan:
 	case err = <-listenResChan:
 	}
@@ -29,6 +29,48 @@ import (
 	v1alpha1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedAddresses) DeepCopyInto(out *AllowedAddresses) {
+	*out = *in
+	if in.MACAddresses != nil {
+		in, out := &in.MACAddresses, &out.MACAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedAddresses.
+func (in *AllowedAddresses) DeepCopy() *AllowedAddresses {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedAddresses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfiguration) DeepCopyInto(out *AuditConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfiguration.
+func (in *AuditConfiguration) DeepCopy() *AuditConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BIOS) DeepCopyInto(out *BIOS) {
 	*out = *in
@@ -45,6 +87,32 @@ func (in *BIOS) DeepCopy() *BIOS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BandwidthLimit) DeepCopyInto(out *BandwidthLimit) {
+	*out = *in
+	if in.Peak != nil {
+		in, out := &in.Peak, &out.Peak
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BandwidthLimit.
+func (in *BandwidthLimit) DeepCopy() *BandwidthLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(BandwidthLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bootloader) DeepCopyInto(out *Bootloader) {
 	*out = *in
@@ -100,6 +168,26 @@ func (in *CPU) DeepCopyInto(out *CPU) {
 		*out = make([]CPUFeature, len(*in))
 		copy(*out, *in)
 	}
+	if in.EmulatorThreadPolicy != nil {
+		in, out := &in.EmulatorThreadPolicy, &out.EmulatorThreadPolicy
+		*out = new(EmulatorThreadPolicy)
+		**out = **in
+	}
+	if in.ThreadPolicy != nil {
+		in, out := &in.ThreadPolicy, &out.ThreadPolicy
+		*out = new(CPUThreadPolicy)
+		**out = **in
+	}
+	if in.NUMA != nil {
+		in, out := &in.NUMA, &out.NUMA
+		*out = new(NUMA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Realtime != nil {
+		in, out := &in.Realtime, &out.Realtime
+		*out = new(Realtime)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -129,6 +217,64 @@ func (in *CPUFeature) DeepCopy() *CPUFeature {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUInstancetype) DeepCopyInto(out *CPUInstancetype) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUInstancetype.
+func (in *CPUInstancetype) DeepCopy() *CPUInstancetype {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUInstancetype)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUPreferences) DeepCopyInto(out *CPUPreferences) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPreferences.
+func (in *CPUPreferences) DeepCopy() *CPUPreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUPreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertConfig) DeepCopyInto(out *CertConfig) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertConfig.
+func (in *CertConfig) DeepCopy() *CertConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chassis) DeepCopyInto(out *Chassis) {
 	*out = *in
@@ -145,6 +291,22 @@ func (in *Chassis) DeepCopy() *Chassis {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientPassthroughDevices) DeepCopyInto(out *ClientPassthroughDevices) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientPassthroughDevices.
+func (in *ClientPassthroughDevices) DeepCopy() *ClientPassthroughDevices {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientPassthroughDevices)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Clock) DeepCopyInto(out *Clock) {
 	*out = *in
@@ -266,6 +428,37 @@ func (in *CloudInitNoCloudSource) DeepCopy() *CloudInitNoCloudSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapabilities) DeepCopyInto(out *ClusterCapabilities) {
+	*out = *in
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmulatedMachines != nil {
+		in, out := &in.EmulatedMachines, &out.EmulatedMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(KubeVirtConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapabilities.
+func (in *ClusterCapabilities) DeepCopy() *ClusterCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapVolumeSource) DeepCopyInto(out *ConfigMapVolumeSource) {
 	*out = *in
@@ -289,135 +482,436 @@ func (in *ConfigMapVolumeSource) DeepCopy() *ConfigMapVolumeSource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerDiskSource) DeepCopyInto(out *ContainerDiskSource) {
+func (in *ConformanceFeatureResult) DeepCopyInto(out *ConformanceFeatureResult) {
 	*out = *in
+	if in.NetworkCheckup != nil {
+		in, out := &in.NetworkCheckup, &out.NetworkCheckup
+		*out = new(ConformanceNetworkCheckupResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerDiskSource.
-func (in *ContainerDiskSource) DeepCopy() *ContainerDiskSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceFeatureResult.
+func (in *ConformanceFeatureResult) DeepCopy() *ConformanceFeatureResult {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerDiskSource)
+	out := new(ConformanceFeatureResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DHCPOptions) DeepCopyInto(out *DHCPOptions) {
+func (in *ConformanceNetworkCheckup) DeepCopyInto(out *ConformanceNetworkCheckup) {
 	*out = *in
-	if in.NTPServers != nil {
-		in, out := &in.NTPServers, &out.NTPServers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.PrivateOptions != nil {
-		in, out := &in.PrivateOptions, &out.PrivateOptions
-		*out = make([]DHCPPrivateOptions, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceNetworkCheckup.
+func (in *ConformanceNetworkCheckup) DeepCopy() *ConformanceNetworkCheckup {
+	if in == nil {
+		return nil
+	}
+	out := new(ConformanceNetworkCheckup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConformanceNetworkCheckupResult) DeepCopyInto(out *ConformanceNetworkCheckupResult) {
+	*out = *in
+	out.MinLatency = in.MinLatency
+	out.AvgLatency = in.AvgLatency
+	out.MaxLatency = in.MaxLatency
+	if in.ThroughputBitsPerSecond != nil {
+		in, out := &in.ThroughputBitsPerSecond, &out.ThroughputBitsPerSecond
+		*out = new(int64)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPOptions.
-func (in *DHCPOptions) DeepCopy() *DHCPOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceNetworkCheckupResult.
+func (in *ConformanceNetworkCheckupResult) DeepCopy() *ConformanceNetworkCheckupResult {
 	if in == nil {
 		return nil
 	}
-	out := new(DHCPOptions)
+	out := new(ConformanceNetworkCheckupResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DHCPPrivateOptions) DeepCopyInto(out *DHCPPrivateOptions) {
+func (in *ConformanceRun) DeepCopyInto(out *ConformanceRun) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPrivateOptions.
-func (in *DHCPPrivateOptions) DeepCopy() *DHCPPrivateOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceRun.
+func (in *ConformanceRun) DeepCopy() *ConformanceRun {
 	if in == nil {
 		return nil
 	}
-	out := new(DHCPPrivateOptions)
+	out := new(ConformanceRun)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConformanceRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
+func (in *ConformanceRunList) DeepCopyInto(out *ConformanceRunList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConformanceRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSource.
-func (in *DataVolumeSource) DeepCopy() *DataVolumeSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceRunList.
+func (in *ConformanceRunList) DeepCopy() *ConformanceRunList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeSource)
+	out := new(ConformanceRunList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConformanceRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeveloperConfiguration) DeepCopyInto(out *DeveloperConfiguration) {
+func (in *ConformanceRunSpec) DeepCopyInto(out *ConformanceRunSpec) {
 	*out = *in
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]ConformanceFeature, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureTimeout != nil {
+		in, out := &in.FeatureTimeout, &out.FeatureTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.NodeSelectors != nil {
-		in, out := &in.NodeSelectors, &out.NodeSelectors
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.NetworkCheckup != nil {
+		in, out := &in.NetworkCheckup, &out.NetworkCheckup
+		*out = new(ConformanceNetworkCheckup)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeveloperConfiguration.
-func (in *DeveloperConfiguration) DeepCopy() *DeveloperConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceRunSpec.
+func (in *ConformanceRunSpec) DeepCopy() *ConformanceRunSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DeveloperConfiguration)
+	out := new(ConformanceRunSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Devices) DeepCopyInto(out *Devices) {
+func (in *ConformanceRunStatus) DeepCopyInto(out *ConformanceRunStatus) {
 	*out = *in
-	if in.Disks != nil {
-		in, out := &in.Disks, &out.Disks
-		*out = make([]Disk, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
 	}
-	if in.Watchdog != nil {
-		in, out := &in.Watchdog, &out.Watchdog
-		*out = new(Watchdog)
-		(*in).DeepCopyInto(*out)
+	if in.CompletionTimestamp != nil {
+		in, out := &in.CompletionTimestamp, &out.CompletionTimestamp
+		*out = (*in).DeepCopy()
 	}
-	if in.Interfaces != nil {
-		in, out := &in.Interfaces, &out.Interfaces
-		*out = make([]Interface, len(*in))
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ConformanceFeatureResult, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make([]Input, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConformanceRunStatus.
+func (in *ConformanceRunStatus) DeepCopy() *ConformanceRunStatus {
+	if in == nil {
+		return nil
 	}
-	if in.AutoattachPodInterface != nil {
+	out := new(ConformanceRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionLimits) DeepCopyInto(out *ConnectionLimits) {
+	*out = *in
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.MaxNewConnectionsPerSecond != nil {
+		in, out := &in.MaxNewConnectionsPerSecond, &out.MaxNewConnectionsPerSecond
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionLimits.
+func (in *ConnectionLimits) DeepCopy() *ConnectionLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerDiskSource) DeepCopyInto(out *ContainerDiskSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerDiskSource.
+func (in *ContainerDiskSource) DeepCopy() *ContainerDiskSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerDiskSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPOptions) DeepCopyInto(out *DHCPOptions) {
+	*out = *in
+	if in.NTPServers != nil {
+		in, out := &in.NTPServers, &out.NTPServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateOptions != nil {
+		in, out := &in.PrivateOptions, &out.PrivateOptions
+		*out = make([]DHCPPrivateOptions, len(*in))
+		copy(*out, *in)
+	}
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]DHCPRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPOptions.
+func (in *DHCPOptions) DeepCopy() *DHCPOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPPrivateOptions) DeepCopyInto(out *DHCPPrivateOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPrivateOptions.
+func (in *DHCPPrivateOptions) DeepCopy() *DHCPPrivateOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPPrivateOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPRoute) DeepCopyInto(out *DHCPRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPRoute.
+func (in *DHCPRoute) DeepCopy() *DHCPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVolumeSource) DeepCopyInto(out *DataVolumeSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeSource.
+func (in *DataVolumeSource) DeepCopy() *DataVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultNetworkPolicyConfiguration) DeepCopyInto(out *DefaultNetworkPolicyConfiguration) {
+	*out = *in
+	if in.BlockedCIDRs != nil {
+		in, out := &in.BlockedCIDRs, &out.BlockedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultNetworkPolicyConfiguration.
+func (in *DefaultNetworkPolicyConfiguration) DeepCopy() *DefaultNetworkPolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultNetworkPolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeveloperConfiguration) DeepCopyInto(out *DeveloperConfiguration) {
+	*out = *in
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelectors != nil {
+		in, out := &in.NodeSelectors, &out.NodeSelectors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeveloperConfiguration.
+func (in *DeveloperConfiguration) DeepCopy() *DeveloperConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DeveloperConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePreferences) DeepCopyInto(out *DevicePreferences) {
+	*out = *in
+	if in.PreferredAutoattachGraphicsDevice != nil {
+		in, out := &in.PreferredAutoattachGraphicsDevice, &out.PreferredAutoattachGraphicsDevice
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePreferences.
+func (in *DevicePreferences) DeepCopy() *DevicePreferences {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePreferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Devices) DeepCopyInto(out *Devices) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]Disk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Watchdog != nil {
+		in, out := &in.Watchdog, &out.Watchdog
+		*out = new(Watchdog)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]Interface, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]Input, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoattachPodInterface != nil {
 		in, out := &in.AutoattachPodInterface, &out.AutoattachPodInterface
 		*out = new(bool)
 		**out = **in
@@ -432,11 +926,21 @@ func (in *Devices) DeepCopyInto(out *Devices) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SerialConsoleLog != nil {
+		in, out := &in.SerialConsoleLog, &out.SerialConsoleLog
+		*out = new(SerialConsoleLog)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AutoattachMemBalloon != nil {
 		in, out := &in.AutoattachMemBalloon, &out.AutoattachMemBalloon
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MemBalloon != nil {
+		in, out := &in.MemBalloon, &out.MemBalloon
+		*out = new(MemBalloon)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Rng != nil {
 		in, out := &in.Rng, &out.Rng
 		*out = new(Rng)
@@ -452,6 +956,11 @@ func (in *Devices) DeepCopyInto(out *Devices) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.NetworkInterfaceQueues != nil {
+		in, out := &in.NetworkInterfaceQueues, &out.NetworkInterfaceQueues
+		*out = new(uint32)
+		**out = **in
+	}
 	if in.GPUs != nil {
 		in, out := &in.GPUs, &out.GPUs
 		*out = make([]GPU, len(*in))
@@ -462,6 +971,38 @@ func (in *Devices) DeepCopyInto(out *Devices) {
 		*out = make([]QAT, len(*in))
 		copy(*out, *in)
 	}
+	if in.HostDevices != nil {
+		in, out := &in.HostDevices, &out.HostDevices
+		*out = make([]HostDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPMDevice)
+		**out = **in
+	}
+	if in.ClientPassthrough != nil {
+		in, out := &in.ClientPassthrough, &out.ClientPassthrough
+		*out = new(ClientPassthroughDevices)
+		**out = **in
+	}
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]Filesystem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sound != nil {
+		in, out := &in.Sound, &out.Sound
+		*out = new(SoundDevice)
+		**out = **in
+	}
+	if in.Panic != nil {
+		in, out := &in.Panic, &out.Panic
+		*out = new(PanicDevice)
+		**out = **in
+	}
 	return
 }
 
@@ -476,544 +1017,2132 @@ func (in *Devices) DeepCopy() *Devices {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Disk) DeepCopyInto(out *Disk) {
+func (in *Disk) DeepCopyInto(out *Disk) {
+	*out = *in
+	in.DiskDevice.DeepCopyInto(&out.DiskDevice)
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
+	if in.DedicatedIOThread != nil {
+		in, out := &in.DedicatedIOThread, &out.DedicatedIOThread
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IOThread != nil {
+		in, out := &in.IOThread, &out.IOThread
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Queues != nil {
+		in, out := &in.Queues, &out.Queues
+		*out = new(DiskQueues)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IOTune != nil {
+		in, out := &in.IOTune, &out.IOTune
+		*out = new(DiskIOTune)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Disk.
+func (in *Disk) DeepCopy() *Disk {
+	if in == nil {
+		return nil
+	}
+	out := new(Disk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskCompaction) DeepCopyInto(out *DiskCompaction) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskCompaction.
+func (in *DiskCompaction) DeepCopy() *DiskCompaction {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskCompaction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskCompactionStatus) DeepCopyInto(out *DiskCompactionStatus) {
+	*out = *in
+	if in.LastCompactionTimestamp != nil {
+		in, out := &in.LastCompactionTimestamp, &out.LastCompactionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskCompactionStatus.
+func (in *DiskCompactionStatus) DeepCopy() *DiskCompactionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskCompactionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskDevice) DeepCopyInto(out *DiskDevice) {
+	*out = *in
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(DiskTarget)
+		**out = **in
+	}
+	if in.LUN != nil {
+		in, out := &in.LUN, &out.LUN
+		*out = new(LunTarget)
+		**out = **in
+	}
+	if in.Floppy != nil {
+		in, out := &in.Floppy, &out.Floppy
+		*out = new(FloppyTarget)
+		**out = **in
+	}
+	if in.CDRom != nil {
+		in, out := &in.CDRom, &out.CDRom
+		*out = new(CDRomTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskDevice.
+func (in *DiskDevice) DeepCopy() *DiskDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskIOTune) DeepCopyInto(out *DiskIOTune) {
+	*out = *in
+	if in.TotalBytesSec != nil {
+		in, out := &in.TotalBytesSec, &out.TotalBytesSec
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.ReadBytesSec != nil {
+		in, out := &in.ReadBytesSec, &out.ReadBytesSec
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.WriteBytesSec != nil {
+		in, out := &in.WriteBytesSec, &out.WriteBytesSec
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.TotalIopsSec != nil {
+		in, out := &in.TotalIopsSec, &out.TotalIopsSec
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.ReadIopsSec != nil {
+		in, out := &in.ReadIopsSec, &out.ReadIopsSec
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.WriteIopsSec != nil {
+		in, out := &in.WriteIopsSec, &out.WriteIopsSec
+		*out = new(uint64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskIOTune.
+func (in *DiskIOTune) DeepCopy() *DiskIOTune {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskIOTune)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskQueues) DeepCopyInto(out *DiskQueues) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskQueues.
+func (in *DiskQueues) DeepCopy() *DiskQueues {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskQueues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskTarget) DeepCopyInto(out *DiskTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskTarget.
+func (in *DiskTarget) DeepCopy() *DiskTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainSpec) DeepCopyInto(out *DomainSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(CPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(Memory)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Machine = in.Machine
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(Firmware)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clock != nil {
+		in, out := &in.Clock, &out.Clock
+		*out = new(Clock)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(Features)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Devices.DeepCopyInto(&out.Devices)
+	if in.IOThreadsPolicy != nil {
+		in, out := &in.IOThreadsPolicy, &out.IOThreadsPolicy
+		*out = new(IOThreadsPolicy)
+		**out = **in
+	}
+	if in.IOThreads != nil {
+		in, out := &in.IOThreads, &out.IOThreads
+		*out = make([]IOThread, len(*in))
+		copy(*out, *in)
+	}
+	if in.Chassis != nil {
+		in, out := &in.Chassis, &out.Chassis
+		*out = new(Chassis)
+		**out = **in
+	}
+	if in.LaunchSecurity != nil {
+		in, out := &in.LaunchSecurity, &out.LaunchSecurity
+		*out = new(LaunchSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainSpec.
+func (in *DomainSpec) DeepCopy() *DomainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EFI) DeepCopyInto(out *EFI) {
+	*out = *in
+	if in.SecureBoot != nil {
+		in, out := &in.SecureBoot, &out.SecureBoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnrolledKeys != nil {
+		in, out := &in.EnrolledKeys, &out.EnrolledKeys
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ContainerDisk != nil {
+		in, out := &in.ContainerDisk, &out.ContainerDisk
+		*out = new(ContainerDiskSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EFI.
+func (in *EFI) DeepCopy() *EFI {
+	if in == nil {
+		return nil
+	}
+	out := new(EFI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmptyDiskSource) DeepCopyInto(out *EmptyDiskSource) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDiskSource.
+func (in *EmptyDiskSource) DeepCopy() *EmptyDiskSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EmptyDiskSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralVolumeSource) DeepCopyInto(out *EphemeralVolumeSource) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralVolumeSource.
+func (in *EphemeralVolumeSource) DeepCopy() *EphemeralVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureAPIC) DeepCopyInto(out *FeatureAPIC) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureAPIC.
+func (in *FeatureAPIC) DeepCopy() *FeatureAPIC {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureAPIC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureHyperv) DeepCopyInto(out *FeatureHyperv) {
+	*out = *in
+	if in.Relaxed != nil {
+		in, out := &in.Relaxed, &out.Relaxed
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VAPIC != nil {
+		in, out := &in.VAPIC, &out.VAPIC
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Spinlocks != nil {
+		in, out := &in.Spinlocks, &out.Spinlocks
+		*out = new(FeatureSpinlocks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VPIndex != nil {
+		in, out := &in.VPIndex, &out.VPIndex
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyNIC != nil {
+		in, out := &in.SyNIC, &out.SyNIC
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyNICTimer != nil {
+		in, out := &in.SyNICTimer, &out.SyNICTimer
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reset != nil {
+		in, out := &in.Reset, &out.Reset
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VendorID != nil {
+		in, out := &in.VendorID, &out.VendorID
+		*out = new(FeatureVendorID)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Frequencies != nil {
+		in, out := &in.Frequencies, &out.Frequencies
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reenlightenment != nil {
+		in, out := &in.Reenlightenment, &out.Reenlightenment
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLBFlush != nil {
+		in, out := &in.TLBFlush, &out.TLBFlush
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPI != nil {
+		in, out := &in.IPI, &out.IPI
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EVMCS != nil {
+		in, out := &in.EVMCS, &out.EVMCS
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureHyperv.
+func (in *FeatureHyperv) DeepCopy() *FeatureHyperv {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureHyperv)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureSpinlocks) DeepCopyInto(out *FeatureSpinlocks) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpinlocks.
+func (in *FeatureSpinlocks) DeepCopy() *FeatureSpinlocks {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureSpinlocks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureState) DeepCopyInto(out *FeatureState) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureState.
+func (in *FeatureState) DeepCopy() *FeatureState {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureVendorID) DeepCopyInto(out *FeatureVendorID) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureVendorID.
+func (in *FeatureVendorID) DeepCopy() *FeatureVendorID {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureVendorID)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Features) DeepCopyInto(out *Features) {
+	*out = *in
+	in.ACPI.DeepCopyInto(&out.ACPI)
+	if in.APIC != nil {
+		in, out := &in.APIC, &out.APIC
+		*out = new(FeatureAPIC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hyperv != nil {
+		in, out := &in.Hyperv, &out.Hyperv
+		*out = new(FeatureHyperv)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SMM != nil {
+		in, out := &in.SMM, &out.SMM
+		*out = new(FeatureState)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Features.
+func (in *Features) DeepCopy() *Features {
+	if in == nil {
+		return nil
+	}
+	out := new(Features)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filesystem) DeepCopyInto(out *Filesystem) {
+	*out = *in
+	if in.Virtiofs != nil {
+		in, out := &in.Virtiofs, &out.Virtiofs
+		*out = new(FilesystemVirtiofs)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filesystem.
+func (in *Filesystem) DeepCopy() *Filesystem {
+	if in == nil {
+		return nil
+	}
+	out := new(Filesystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemOverheadConfiguration) DeepCopyInto(out *FilesystemOverheadConfiguration) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemOverheadConfiguration.
+func (in *FilesystemOverheadConfiguration) DeepCopy() *FilesystemOverheadConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemOverheadConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemVirtiofs) DeepCopyInto(out *FilesystemVirtiofs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemVirtiofs.
+func (in *FilesystemVirtiofs) DeepCopy() *FilesystemVirtiofs {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemVirtiofs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Firmware) DeepCopyInto(out *Firmware) {
+	*out = *in
+	if in.Bootloader != nil {
+		in, out := &in.Bootloader, &out.Bootloader
+		*out = new(Bootloader)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Firmware.
+func (in *Firmware) DeepCopy() *Firmware {
+	if in == nil {
+		return nil
+	}
+	out := new(Firmware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FloppyTarget) DeepCopyInto(out *FloppyTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FloppyTarget.
+func (in *FloppyTarget) DeepCopy() *FloppyTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(FloppyTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPU) DeepCopyInto(out *GPU) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPU.
+func (in *GPU) DeepCopy() *GPU {
+	if in == nil {
+		return nil
+	}
+	out := new(GPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoldenImageVolumeSource) DeepCopyInto(out *GoldenImageVolumeSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoldenImageVolumeSource.
+func (in *GoldenImageVolumeSource) DeepCopy() *GoldenImageVolumeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GoldenImageVolumeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestAgentExecAction) DeepCopyInto(out *GuestAgentExecAction) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestAgentExecAction.
+func (in *GuestAgentExecAction) DeepCopy() *GuestAgentExecAction {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestAgentExecAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HPETTimer) DeepCopyInto(out *HPETTimer) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HPETTimer.
+func (in *HPETTimer) DeepCopy() *HPETTimer {
+	if in == nil {
+		return nil
+	}
+	out := new(HPETTimer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Handler) DeepCopyInto(out *Handler) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCPSocket != nil {
+		in, out := &in.TCPSocket, &out.TCPSocket
+		*out = new(corev1.TCPSocketAction)
+		**out = **in
+	}
+	if in.GuestAgentExec != nil {
+		in, out := &in.GuestAgentExec, &out.GuestAgentExec
+		*out = new(GuestAgentExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Handler.
+func (in *Handler) DeepCopy() *Handler {
+	if in == nil {
+		return nil
+	}
+	out := new(Handler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDevice) DeepCopyInto(out *HostDevice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDevice.
+func (in *HostDevice) DeepCopy() *HostDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(HostDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostDisk) DeepCopyInto(out *HostDisk) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	if in.Shared != nil {
+		in, out := &in.Shared, &out.Shared
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDisk.
+func (in *HostDisk) DeepCopy() *HostDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(HostDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hugepages) DeepCopyInto(out *Hugepages) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hugepages.
+func (in *Hugepages) DeepCopy() *Hugepages {
+	if in == nil {
+		return nil
+	}
+	out := new(Hugepages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HypervTimer) DeepCopyInto(out *HypervTimer) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HypervTimer.
+func (in *HypervTimer) DeepCopy() *HypervTimer {
+	if in == nil {
+		return nil
+	}
+	out := new(HypervTimer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *I6300ESBWatchdog) DeepCopyInto(out *I6300ESBWatchdog) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new I6300ESBWatchdog.
+func (in *I6300ESBWatchdog) DeepCopy() *I6300ESBWatchdog {
+	if in == nil {
+		return nil
+	}
+	out := new(I6300ESBWatchdog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOErrorRecovery) DeepCopyInto(out *IOErrorRecovery) {
+	*out = *in
+	if in.AutoResume != nil {
+		in, out := &in.AutoResume, &out.AutoResume
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOErrorRecovery.
+func (in *IOErrorRecovery) DeepCopy() *IOErrorRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(IOErrorRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOThread) DeepCopyInto(out *IOThread) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOThread.
+func (in *IOThread) DeepCopy() *IOThread {
+	if in == nil {
+		return nil
+	}
+	out := new(IOThread)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnitionSource) DeepCopyInto(out *IgnitionSource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnitionSource.
+func (in *IgnitionSource) DeepCopy() *IgnitionSource {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnitionSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Input) DeepCopyInto(out *Input) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Input.
+func (in *Input) DeepCopy() *Input {
+	if in == nil {
+		return nil
+	}
+	out := new(Input)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancetypeMatcher) DeepCopyInto(out *InstancetypeMatcher) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancetypeMatcher.
+func (in *InstancetypeMatcher) DeepCopy() *InstancetypeMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancetypeMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Interface) DeepCopyInto(out *Interface) {
+	*out = *in
+	in.InterfaceBindingMethod.DeepCopyInto(&out.InterfaceBindingMethod)
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]Port, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootOrder != nil {
+		in, out := &in.BootOrder, &out.BootOrder
+		*out = new(uint)
+		**out = **in
+	}
+	if in.DHCPOptions != nil {
+		in, out := &in.DHCPOptions, &out.DHCPOptions
+		*out = new(DHCPOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionLimits != nil {
+		in, out := &in.ConnectionLimits, &out.ConnectionLimits
+		*out = new(ConnectionLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedAddresses != nil {
+		in, out := &in.AllowedAddresses, &out.AllowedAddresses
+		*out = new(AllowedAddresses)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		*out = new(InterfaceBandwidth)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interface.
+func (in *Interface) DeepCopy() *Interface {
+	if in == nil {
+		return nil
+	}
+	out := new(Interface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceBandwidth) DeepCopyInto(out *InterfaceBandwidth) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(BandwidthLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBandwidth.
+func (in *InterfaceBandwidth) DeepCopy() *InterfaceBandwidth {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceBandwidth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceBindingMethod) DeepCopyInto(out *InterfaceBindingMethod) {
+	*out = *in
+	if in.Bridge != nil {
+		in, out := &in.Bridge, &out.Bridge
+		*out = new(InterfaceBridge)
+		**out = **in
+	}
+	if in.Slirp != nil {
+		in, out := &in.Slirp, &out.Slirp
+		*out = new(InterfaceSlirp)
+		**out = **in
+	}
+	if in.Masquerade != nil {
+		in, out := &in.Masquerade, &out.Masquerade
+		*out = new(InterfaceMasquerade)
+		**out = **in
+	}
+	if in.SRIOV != nil {
+		in, out := &in.SRIOV, &out.SRIOV
+		*out = new(InterfaceSRIOV)
+		**out = **in
+	}
+	if in.Macvtap != nil {
+		in, out := &in.Macvtap, &out.Macvtap
+		*out = new(InterfaceMacvtap)
+		**out = **in
+	}
+	if in.Passt != nil {
+		in, out := &in.Passt, &out.Passt
+		*out = new(InterfacePasst)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBindingMethod.
+func (in *InterfaceBindingMethod) DeepCopy() *InterfaceBindingMethod {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceBindingMethod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceBridge) DeepCopyInto(out *InterfaceBridge) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBridge.
+func (in *InterfaceBridge) DeepCopy() *InterfaceBridge {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceBridge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceIPClaim) DeepCopyInto(out *InterfaceIPClaim) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceIPClaim.
+func (in *InterfaceIPClaim) DeepCopy() *InterfaceIPClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceIPClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceMacvtap) DeepCopyInto(out *InterfaceMacvtap) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceMacvtap.
+func (in *InterfaceMacvtap) DeepCopy() *InterfaceMacvtap {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceMacvtap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceMasquerade) DeepCopyInto(out *InterfaceMasquerade) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceMasquerade.
+func (in *InterfaceMasquerade) DeepCopy() *InterfaceMasquerade {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceMasquerade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfacePasst) DeepCopyInto(out *InterfacePasst) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfacePasst.
+func (in *InterfacePasst) DeepCopy() *InterfacePasst {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfacePasst)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceSRIOV) DeepCopyInto(out *InterfaceSRIOV) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSRIOV.
+func (in *InterfaceSRIOV) DeepCopy() *InterfaceSRIOV {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceSRIOV)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceSlirp) DeepCopyInto(out *InterfaceSlirp) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSlirp.
+func (in *InterfaceSlirp) DeepCopy() *InterfaceSlirp {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceSlirp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KSMConfiguration) DeepCopyInto(out *KSMConfiguration) {
+	*out = *in
+	if in.MemoryPressureThreshold != nil {
+		in, out := &in.MemoryPressureThreshold, &out.MemoryPressureThreshold
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KSMConfiguration.
+func (in *KSMConfiguration) DeepCopy() *KSMConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KSMConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KVMTimer) DeepCopyInto(out *KVMTimer) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KVMTimer.
+func (in *KVMTimer) DeepCopy() *KVMTimer {
+	if in == nil {
+		return nil
+	}
+	out := new(KVMTimer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirt) DeepCopyInto(out *KubeVirt) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirt.
+func (in *KubeVirt) DeepCopy() *KubeVirt {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeVirt) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtCanaryRollout) DeepCopyInto(out *KubeVirtCanaryRollout) {
+	*out = *in
+	if in.VerificationPeriod != nil {
+		in, out := &in.VerificationPeriod, &out.VerificationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FailureTimeout != nil {
+		in, out := &in.FailureTimeout, &out.FailureTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCanaryRollout.
+func (in *KubeVirtCanaryRollout) DeepCopy() *KubeVirtCanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtCanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtCanaryRolloutStatus) DeepCopyInto(out *KubeVirtCanaryRolloutStatus) {
+	*out = *in
+	if in.BatchStartTime != nil {
+		in, out := &in.BatchStartTime, &out.BatchStartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCanaryRolloutStatus.
+func (in *KubeVirtCanaryRolloutStatus) DeepCopy() *KubeVirtCanaryRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtCanaryRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtCertificateRotateStrategy) DeepCopyInto(out *KubeVirtCertificateRotateStrategy) {
+	*out = *in
+	if in.SelfSigned != nil {
+		in, out := &in.SelfSigned, &out.SelfSigned
+		*out = new(KubeVirtSelfSignConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCertificateRotateStrategy.
+func (in *KubeVirtCertificateRotateStrategy) DeepCopy() *KubeVirtCertificateRotateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtCertificateRotateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtCondition) DeepCopyInto(out *KubeVirtCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCondition.
+func (in *KubeVirtCondition) DeepCopy() *KubeVirtCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtConfiguration) DeepCopyInto(out *KubeVirtConfiguration) {
+	*out = *in
+	if in.CPURequest != nil {
+		in, out := &in.CPURequest, &out.CPURequest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DeveloperConfiguration != nil {
+		in, out := &in.DeveloperConfiguration, &out.DeveloperConfiguration
+		*out = new(DeveloperConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmulatedMachines != nil {
+		in, out := &in.EmulatedMachines, &out.EmulatedMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MigrationConfiguration != nil {
+		in, out := &in.MigrationConfiguration, &out.MigrationConfiguration
+		*out = new(MigrationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkConfiguration != nil {
+		in, out := &in.NetworkConfiguration, &out.NetworkConfiguration
+		*out = new(NetworkConfiguration)
+		**out = **in
+	}
+	if in.SELinuxLauncherTypes != nil {
+		in, out := &in.SELinuxLauncherTypes, &out.SELinuxLauncherTypes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SMBIOSConfig != nil {
+		in, out := &in.SMBIOSConfig, &out.SMBIOSConfig
+		*out = new(SMBiosConfiguration)
+		**out = **in
+	}
+	if in.SupportedGuestAgentVersions != nil {
+		in, out := &in.SupportedGuestAgentVersions, &out.SupportedGuestAgentVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PermittedHostDevices != nil {
+		in, out := &in.PermittedHostDevices, &out.PermittedHostDevices
+		*out = new(PermittedHostDevices)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KSMConfiguration != nil {
+		in, out := &in.KSMConfiguration, &out.KSMConfiguration
+		*out = new(KSMConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiQueueConfiguration != nil {
+		in, out := &in.MultiQueueConfiguration, &out.MultiQueueConfiguration
+		*out = new(MultiQueueConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultNetworkPolicy != nil {
+		in, out := &in.DefaultNetworkPolicy, &out.DefaultNetworkPolicy
+		*out = new(DefaultNetworkPolicyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditConfiguration != nil {
+		in, out := &in.AuditConfiguration, &out.AuditConfiguration
+		*out = new(AuditConfiguration)
+		**out = **in
+	}
+	if in.NodeFencing != nil {
+		in, out := &in.NodeFencing, &out.NodeFencing
+		*out = new(NodeFencingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeccompConfiguration != nil {
+		in, out := &in.SeccompConfiguration, &out.SeccompConfiguration
+		*out = new(SeccompConfiguration)
+		**out = **in
+	}
+	if in.MetricsConfiguration != nil {
+		in, out := &in.MetricsConfiguration, &out.MetricsConfiguration
+		*out = new(MetricsConfiguration)
+		**out = **in
+	}
+	if in.FilesystemOverhead != nil {
+		in, out := &in.FilesystemOverhead, &out.FilesystemOverhead
+		*out = new(FilesystemOverheadConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtConfiguration.
+func (in *KubeVirtConfiguration) DeepCopy() *KubeVirtConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtList) DeepCopyInto(out *KubeVirtList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeVirt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtList.
+func (in *KubeVirtList) DeepCopy() *KubeVirtList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeVirtList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtSelfSignConfiguration) DeepCopyInto(out *KubeVirtSelfSignConfiguration) {
+	*out = *in
+	if in.CARotateInterval != nil {
+		in, out := &in.CARotateInterval, &out.CARotateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CertRotateInterval != nil {
+		in, out := &in.CertRotateInterval, &out.CertRotateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CAOverlapInterval != nil {
+		in, out := &in.CAOverlapInterval, &out.CAOverlapInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(CertConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(CertConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtSelfSignConfiguration.
+func (in *KubeVirtSelfSignConfiguration) DeepCopy() *KubeVirtSelfSignConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtSelfSignConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtSpec) DeepCopyInto(out *KubeVirtSpec) {
+	*out = *in
+	in.CertificateRotationStrategy.DeepCopyInto(&out.CertificateRotationStrategy)
+	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.CanaryRollout != nil {
+		in, out := &in.CanaryRollout, &out.CanaryRollout
+		*out = new(KubeVirtCanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadUpdateStrategy != nil {
+		in, out := &in.WorkloadUpdateStrategy, &out.WorkloadUpdateStrategy
+		*out = new(KubeVirtWorkloadUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtSpec.
+func (in *KubeVirtSpec) DeepCopy() *KubeVirtSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtStatus) DeepCopyInto(out *KubeVirtStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KubeVirtCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CanaryRollout != nil {
+		in, out := &in.CanaryRollout, &out.CanaryRollout
+		*out = new(KubeVirtCanaryRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtStatus.
+func (in *KubeVirtStatus) DeepCopy() *KubeVirtStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeVirtWorkloadUpdateStrategy) DeepCopyInto(out *KubeVirtWorkloadUpdateStrategy) {
+	*out = *in
+	if in.WorkloadUpdateMethods != nil {
+		in, out := &in.WorkloadUpdateMethods, &out.WorkloadUpdateMethods
+		*out = make([]WorkloadUpdateMethod, len(*in))
+		copy(*out, *in)
+	}
+	if in.BatchEvictionSize != nil {
+		in, out := &in.BatchEvictionSize, &out.BatchEvictionSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BatchEvictionInterval != nil {
+		in, out := &in.BatchEvictionInterval, &out.BatchEvictionInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtWorkloadUpdateStrategy.
+func (in *KubeVirtWorkloadUpdateStrategy) DeepCopy() *KubeVirtWorkloadUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeVirtWorkloadUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchSecurity) DeepCopyInto(out *LaunchSecurity) {
+	*out = *in
+	if in.SEV != nil {
+		in, out := &in.SEV, &out.SEV
+		*out = new(SEV)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchSecurity.
+func (in *LaunchSecurity) DeepCopy() *LaunchSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LauncherResources) DeepCopyInto(out *LauncherResources) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Overhead != nil {
+		in, out := &in.Overhead, &out.Overhead
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LauncherResources.
+func (in *LauncherResources) DeepCopy() *LauncherResources {
+	if in == nil {
+		return nil
+	}
+	out := new(LauncherResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHandler) DeepCopyInto(out *LifecycleHandler) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(corev1.ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHandler.
+func (in *LifecycleHandler) DeepCopy() *LifecycleHandler {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHandler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LunTarget) DeepCopyInto(out *LunTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LunTarget.
+func (in *LunTarget) DeepCopy() *LunTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(LunTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Machine) DeepCopyInto(out *Machine) {
 	*out = *in
-	in.DiskDevice.DeepCopyInto(&out.DiskDevice)
-	if in.BootOrder != nil {
-		in, out := &in.BootOrder, &out.BootOrder
-		*out = new(uint)
-		**out = **in
-	}
-	if in.DedicatedIOThread != nil {
-		in, out := &in.DedicatedIOThread, &out.DedicatedIOThread
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Disk.
-func (in *Disk) DeepCopy() *Disk {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Machine.
+func (in *Machine) DeepCopy() *Machine {
 	if in == nil {
 		return nil
 	}
-	out := new(Disk)
+	out := new(Machine)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskDevice) DeepCopyInto(out *DiskDevice) {
+func (in *MachinePreferences) DeepCopyInto(out *MachinePreferences) {
 	*out = *in
-	if in.Disk != nil {
-		in, out := &in.Disk, &out.Disk
-		*out = new(DiskTarget)
-		**out = **in
-	}
-	if in.LUN != nil {
-		in, out := &in.LUN, &out.LUN
-		*out = new(LunTarget)
-		**out = **in
-	}
-	if in.Floppy != nil {
-		in, out := &in.Floppy, &out.Floppy
-		*out = new(FloppyTarget)
-		**out = **in
-	}
-	if in.CDRom != nil {
-		in, out := &in.CDRom, &out.CDRom
-		*out = new(CDRomTarget)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskDevice.
-func (in *DiskDevice) DeepCopy() *DiskDevice {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePreferences.
+func (in *MachinePreferences) DeepCopy() *MachinePreferences {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskDevice)
+	out := new(MachinePreferences)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskTarget) DeepCopyInto(out *DiskTarget) {
+func (in *MediatedHostDevice) DeepCopyInto(out *MediatedHostDevice) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskTarget.
-func (in *DiskTarget) DeepCopy() *DiskTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MediatedHostDevice.
+func (in *MediatedHostDevice) DeepCopy() *MediatedHostDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskTarget)
+	out := new(MediatedHostDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DomainSpec) DeepCopyInto(out *DomainSpec) {
+func (in *MemBalloon) DeepCopyInto(out *MemBalloon) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.CPU != nil {
-		in, out := &in.CPU, &out.CPU
-		*out = new(CPU)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Memory != nil {
-		in, out := &in.Memory, &out.Memory
-		*out = new(Memory)
-		(*in).DeepCopyInto(*out)
-	}
-	out.Machine = in.Machine
-	if in.Firmware != nil {
-		in, out := &in.Firmware, &out.Firmware
-		*out = new(Firmware)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Clock != nil {
-		in, out := &in.Clock, &out.Clock
-		*out = new(Clock)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Features != nil {
-		in, out := &in.Features, &out.Features
-		*out = new(Features)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Devices.DeepCopyInto(&out.Devices)
-	if in.IOThreadsPolicy != nil {
-		in, out := &in.IOThreadsPolicy, &out.IOThreadsPolicy
-		*out = new(IOThreadsPolicy)
-		**out = **in
-	}
-	if in.Chassis != nil {
-		in, out := &in.Chassis, &out.Chassis
-		*out = new(Chassis)
+	if in.FreePageReporting != nil {
+		in, out := &in.FreePageReporting, &out.FreePageReporting
+		*out = new(bool)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainSpec.
-func (in *DomainSpec) DeepCopy() *DomainSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemBalloon.
+func (in *MemBalloon) DeepCopy() *MemBalloon {
 	if in == nil {
 		return nil
 	}
-	out := new(DomainSpec)
+	out := new(MemBalloon)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EFI) DeepCopyInto(out *EFI) {
+func (in *Memory) DeepCopyInto(out *Memory) {
 	*out = *in
-	if in.SecureBoot != nil {
-		in, out := &in.SecureBoot, &out.SecureBoot
-		*out = new(bool)
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = new(Hugepages)
 		**out = **in
 	}
+	if in.Guest != nil {
+		in, out := &in.Guest, &out.Guest
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EFI.
-func (in *EFI) DeepCopy() *EFI {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
+func (in *Memory) DeepCopy() *Memory {
 	if in == nil {
 		return nil
 	}
-	out := new(EFI)
+	out := new(Memory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmptyDiskSource) DeepCopyInto(out *EmptyDiskSource) {
+func (in *MemoryDumpOptions) DeepCopyInto(out *MemoryDumpOptions) {
 	*out = *in
-	out.Capacity = in.Capacity.DeepCopy()
+	out.TypeMeta = in.TypeMeta
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmptyDiskSource.
-func (in *EmptyDiskSource) DeepCopy() *EmptyDiskSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryDumpOptions.
+func (in *MemoryDumpOptions) DeepCopy() *MemoryDumpOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(EmptyDiskSource)
+	out := new(MemoryDumpOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EphemeralVolumeSource) DeepCopyInto(out *EphemeralVolumeSource) {
+func (in *MemoryInstancetype) DeepCopyInto(out *MemoryInstancetype) {
 	*out = *in
-	if in.PersistentVolumeClaim != nil {
-		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
-		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+	out.Guest = in.Guest.DeepCopy()
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = new(Hugepages)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralVolumeSource.
-func (in *EphemeralVolumeSource) DeepCopy() *EphemeralVolumeSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryInstancetype.
+func (in *MemoryInstancetype) DeepCopy() *MemoryInstancetype {
 	if in == nil {
 		return nil
 	}
-	out := new(EphemeralVolumeSource)
+	out := new(MemoryInstancetype)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureAPIC) DeepCopyInto(out *FeatureAPIC) {
+func (in *MetricsConfiguration) DeepCopyInto(out *MetricsConfiguration) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureAPIC.
-func (in *FeatureAPIC) DeepCopy() *FeatureAPIC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfiguration.
+func (in *MetricsConfiguration) DeepCopy() *MetricsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureAPIC)
+	out := new(MetricsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureHyperv) DeepCopyInto(out *FeatureHyperv) {
+func (in *MigrationConfiguration) DeepCopyInto(out *MigrationConfiguration) {
 	*out = *in
-	if in.Relaxed != nil {
-		in, out := &in.Relaxed, &out.Relaxed
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VAPIC != nil {
-		in, out := &in.VAPIC, &out.VAPIC
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Spinlocks != nil {
-		in, out := &in.Spinlocks, &out.Spinlocks
-		*out = new(FeatureSpinlocks)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VPIndex != nil {
-		in, out := &in.VPIndex, &out.VPIndex
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Runtime != nil {
-		in, out := &in.Runtime, &out.Runtime
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SyNIC != nil {
-		in, out := &in.SyNIC, &out.SyNIC
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SyNICTimer != nil {
-		in, out := &in.SyNICTimer, &out.SyNICTimer
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.BandwidthPerMigration != nil {
+		in, out := &in.BandwidthPerMigration, &out.BandwidthPerMigration
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.Reset != nil {
-		in, out := &in.Reset, &out.Reset
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.CompletionTimeoutPerGiB != nil {
+		in, out := &in.CompletionTimeoutPerGiB, &out.CompletionTimeoutPerGiB
+		*out = new(int64)
+		**out = **in
 	}
-	if in.VendorID != nil {
-		in, out := &in.VendorID, &out.VendorID
-		*out = new(FeatureVendorID)
-		(*in).DeepCopyInto(*out)
+	if in.NodeDrainTaintKey != nil {
+		in, out := &in.NodeDrainTaintKey, &out.NodeDrainTaintKey
+		*out = new(string)
+		**out = **in
 	}
-	if in.Frequencies != nil {
-		in, out := &in.Frequencies, &out.Frequencies
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.ParallelOutboundMigrationsPerNode != nil {
+		in, out := &in.ParallelOutboundMigrationsPerNode, &out.ParallelOutboundMigrationsPerNode
+		*out = new(uint32)
+		**out = **in
 	}
-	if in.Reenlightenment != nil {
-		in, out := &in.Reenlightenment, &out.Reenlightenment
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.ParallelInboundMigrationsPerNode != nil {
+		in, out := &in.ParallelInboundMigrationsPerNode, &out.ParallelInboundMigrationsPerNode
+		*out = new(uint32)
+		**out = **in
 	}
-	if in.TLBFlush != nil {
-		in, out := &in.TLBFlush, &out.TLBFlush
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.ParallelMigrationsPerCluster != nil {
+		in, out := &in.ParallelMigrationsPerCluster, &out.ParallelMigrationsPerCluster
+		*out = new(uint32)
+		**out = **in
 	}
-	if in.IPI != nil {
-		in, out := &in.IPI, &out.IPI
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.ProgressTimeout != nil {
+		in, out := &in.ProgressTimeout, &out.ProgressTimeout
+		*out = new(int64)
+		**out = **in
 	}
-	if in.EVMCS != nil {
-		in, out := &in.EVMCS, &out.EVMCS
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
+	if in.RetryLimit != nil {
+		in, out := &in.RetryLimit, &out.RetryLimit
+		*out = new(uint32)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureHyperv.
-func (in *FeatureHyperv) DeepCopy() *FeatureHyperv {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationConfiguration.
+func (in *MigrationConfiguration) DeepCopy() *MigrationConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureHyperv)
+	out := new(MigrationConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureSpinlocks) DeepCopyInto(out *FeatureSpinlocks) {
+func (in *MultiQueueConfiguration) DeepCopyInto(out *MultiQueueConfiguration) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Retries != nil {
-		in, out := &in.Retries, &out.Retries
+	if in.MaxQueues != nil {
+		in, out := &in.MaxQueues, &out.MaxQueues
 		*out = new(uint32)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureSpinlocks.
-func (in *FeatureSpinlocks) DeepCopy() *FeatureSpinlocks {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiQueueConfiguration.
+func (in *MultiQueueConfiguration) DeepCopy() *MultiQueueConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureSpinlocks)
+	out := new(MultiQueueConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureState) DeepCopyInto(out *FeatureState) {
+func (in *MultusNetwork) DeepCopyInto(out *MultusNetwork) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	if in.OVN != nil {
+		in, out := &in.OVN, &out.OVN
+		*out = new(OVNNetwork)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureState.
-func (in *FeatureState) DeepCopy() *FeatureState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultusNetwork.
+func (in *MultusNetwork) DeepCopy() *MultusNetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureState)
+	out := new(MultusNetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureVendorID) DeepCopyInto(out *FeatureVendorID) {
+func (in *NUMA) DeepCopyInto(out *NUMA) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
+	if in.GuestMappingPassthrough != nil {
+		in, out := &in.GuestMappingPassthrough, &out.GuestMappingPassthrough
+		*out = new(NUMAGuestMappingPassthrough)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureVendorID.
-func (in *FeatureVendorID) DeepCopy() *FeatureVendorID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMA.
+func (in *NUMA) DeepCopy() *NUMA {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureVendorID)
+	out := new(NUMA)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Features) DeepCopyInto(out *Features) {
+func (in *NUMAGuestMappingPassthrough) DeepCopyInto(out *NUMAGuestMappingPassthrough) {
 	*out = *in
-	in.ACPI.DeepCopyInto(&out.ACPI)
-	if in.APIC != nil {
-		in, out := &in.APIC, &out.APIC
-		*out = new(FeatureAPIC)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Hyperv != nil {
-		in, out := &in.Hyperv, &out.Hyperv
-		*out = new(FeatureHyperv)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SMM != nil {
-		in, out := &in.SMM, &out.SMM
-		*out = new(FeatureState)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Features.
-func (in *Features) DeepCopy() *Features {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMAGuestMappingPassthrough.
+func (in *NUMAGuestMappingPassthrough) DeepCopy() *NUMAGuestMappingPassthrough {
 	if in == nil {
 		return nil
 	}
-	out := new(Features)
+	out := new(NUMAGuestMappingPassthrough)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Firmware) DeepCopyInto(out *Firmware) {
+func (in *Network) DeepCopyInto(out *Network) {
 	*out = *in
-	if in.Bootloader != nil {
-		in, out := &in.Bootloader, &out.Bootloader
-		*out = new(Bootloader)
-		(*in).DeepCopyInto(*out)
-	}
+	in.NetworkSource.DeepCopyInto(&out.NetworkSource)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Firmware.
-func (in *Firmware) DeepCopy() *Firmware {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
+func (in *Network) DeepCopy() *Network {
 	if in == nil {
 		return nil
 	}
-	out := new(Firmware)
+	out := new(Network)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FloppyTarget) DeepCopyInto(out *FloppyTarget) {
+func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FloppyTarget.
-func (in *FloppyTarget) DeepCopy() *FloppyTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
+func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(FloppyTarget)
+	out := new(NetworkConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GPU) DeepCopyInto(out *GPU) {
+func (in *NetworkSource) DeepCopyInto(out *NetworkSource) {
 	*out = *in
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(PodNetwork)
+		**out = **in
+	}
+	if in.Multus != nil {
+		in, out := &in.Multus, &out.Multus
+		*out = new(MultusNetwork)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPU.
-func (in *GPU) DeepCopy() *GPU {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSource.
+func (in *NetworkSource) DeepCopy() *NetworkSource {
 	if in == nil {
 		return nil
 	}
-	out := new(GPU)
+	out := new(NetworkSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HPETTimer) DeepCopyInto(out *HPETTimer) {
+func (in *NodeFencingConfiguration) DeepCopyInto(out *NodeFencingConfiguration) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Confirmation != nil {
+		in, out := &in.Confirmation, &out.Confirmation
+		*out = new(NodeFencingConfirmation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HPETTimer.
-func (in *HPETTimer) DeepCopy() *HPETTimer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFencingConfiguration.
+func (in *NodeFencingConfiguration) DeepCopy() *NodeFencingConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(HPETTimer)
+	out := new(NodeFencingConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Handler) DeepCopyInto(out *Handler) {
+func (in *NodeFencingConfirmation) DeepCopyInto(out *NodeFencingConfirmation) {
 	*out = *in
-	if in.HTTPGet != nil {
-		in, out := &in.HTTPGet, &out.HTTPGet
-		*out = new(corev1.HTTPGetAction)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TCPSocket != nil {
-		in, out := &in.TCPSocket, &out.TCPSocket
-		*out = new(corev1.TCPSocketAction)
+	if in.NotReady != nil {
+		in, out := &in.NotReady, &out.NotReady
+		*out = new(bool)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]NodeFencingResource, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Handler.
-func (in *Handler) DeepCopy() *Handler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFencingConfirmation.
+func (in *NodeFencingConfirmation) DeepCopy() *NodeFencingConfirmation {
 	if in == nil {
 		return nil
 	}
-	out := new(Handler)
+	out := new(NodeFencingConfirmation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostDisk) DeepCopyInto(out *HostDisk) {
+func (in *NodeFencingResource) DeepCopyInto(out *NodeFencingResource) {
 	*out = *in
-	out.Capacity = in.Capacity.DeepCopy()
-	if in.Shared != nil {
-		in, out := &in.Shared, &out.Shared
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostDisk.
-func (in *HostDisk) DeepCopy() *HostDisk {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFencingResource.
+func (in *NodeFencingResource) DeepCopy() *NodeFencingResource {
 	if in == nil {
 		return nil
 	}
-	out := new(HostDisk)
+	out := new(NodeFencingResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Hugepages) DeepCopyInto(out *Hugepages) {
+func (in *OVNNetwork) DeepCopyInto(out *OVNNetwork) {
 	*out = *in
+	if in.VLAN != nil {
+		in, out := &in.VLAN, &out.VLAN
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hugepages.
-func (in *Hugepages) DeepCopy() *Hugepages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNNetwork.
+func (in *OVNNetwork) DeepCopy() *OVNNetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(Hugepages)
+	out := new(OVNNetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HypervTimer) DeepCopyInto(out *HypervTimer) {
+func (in *PITTimer) DeepCopyInto(out *PITTimer) {
 	*out = *in
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
@@ -1023,182 +3152,157 @@ func (in *HypervTimer) DeepCopyInto(out *HypervTimer) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HypervTimer.
-func (in *HypervTimer) DeepCopy() *HypervTimer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITTimer.
+func (in *PITTimer) DeepCopy() *PITTimer {
 	if in == nil {
 		return nil
 	}
-	out := new(HypervTimer)
+	out := new(PITTimer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *I6300ESBWatchdog) DeepCopyInto(out *I6300ESBWatchdog) {
+func (in *PanicDevice) DeepCopyInto(out *PanicDevice) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new I6300ESBWatchdog.
-func (in *I6300ESBWatchdog) DeepCopy() *I6300ESBWatchdog {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PanicDevice.
+func (in *PanicDevice) DeepCopy() *PanicDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(I6300ESBWatchdog)
+	out := new(PanicDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Input) DeepCopyInto(out *Input) {
+func (in *PciHostDevice) DeepCopyInto(out *PciHostDevice) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Input.
-func (in *Input) DeepCopy() *Input {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PciHostDevice.
+func (in *PciHostDevice) DeepCopy() *PciHostDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(Input)
+	out := new(PciHostDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Interface) DeepCopyInto(out *Interface) {
+func (in *PermittedHostDevices) DeepCopyInto(out *PermittedHostDevices) {
 	*out = *in
-	in.InterfaceBindingMethod.DeepCopyInto(&out.InterfaceBindingMethod)
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]Port, len(*in))
+	if in.PciHostDevices != nil {
+		in, out := &in.PciHostDevices, &out.PciHostDevices
+		*out = make([]PciHostDevice, len(*in))
 		copy(*out, *in)
 	}
-	if in.BootOrder != nil {
-		in, out := &in.BootOrder, &out.BootOrder
-		*out = new(uint)
-		**out = **in
-	}
-	if in.DHCPOptions != nil {
-		in, out := &in.DHCPOptions, &out.DHCPOptions
-		*out = new(DHCPOptions)
-		(*in).DeepCopyInto(*out)
+	if in.MediatedDevices != nil {
+		in, out := &in.MediatedDevices, &out.MediatedDevices
+		*out = make([]MediatedHostDevice, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interface.
-func (in *Interface) DeepCopy() *Interface {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PermittedHostDevices.
+func (in *PermittedHostDevices) DeepCopy() *PermittedHostDevices {
 	if in == nil {
 		return nil
 	}
-	out := new(Interface)
+	out := new(PermittedHostDevices)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceBindingMethod) DeepCopyInto(out *InterfaceBindingMethod) {
+func (in *PodNetwork) DeepCopyInto(out *PodNetwork) {
 	*out = *in
-	if in.Bridge != nil {
-		in, out := &in.Bridge, &out.Bridge
-		*out = new(InterfaceBridge)
-		**out = **in
-	}
-	if in.Slirp != nil {
-		in, out := &in.Slirp, &out.Slirp
-		*out = new(InterfaceSlirp)
-		**out = **in
-	}
-	if in.Masquerade != nil {
-		in, out := &in.Masquerade, &out.Masquerade
-		*out = new(InterfaceMasquerade)
-		**out = **in
-	}
-	if in.SRIOV != nil {
-		in, out := &in.SRIOV, &out.SRIOV
-		*out = new(InterfaceSRIOV)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBindingMethod.
-func (in *InterfaceBindingMethod) DeepCopy() *InterfaceBindingMethod {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodNetwork.
+func (in *PodNetwork) DeepCopy() *PodNetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceBindingMethod)
+	out := new(PodNetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceBridge) DeepCopyInto(out *InterfaceBridge) {
+func (in *Port) DeepCopyInto(out *Port) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceBridge.
-func (in *InterfaceBridge) DeepCopy() *InterfaceBridge {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Port.
+func (in *Port) DeepCopy() *Port {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceBridge)
+	out := new(Port)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceMasquerade) DeepCopyInto(out *InterfaceMasquerade) {
+func (in *PreferenceMatcher) DeepCopyInto(out *PreferenceMatcher) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceMasquerade.
-func (in *InterfaceMasquerade) DeepCopy() *InterfaceMasquerade {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferenceMatcher.
+func (in *PreferenceMatcher) DeepCopy() *PreferenceMatcher {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceMasquerade)
+	out := new(PreferenceMatcher)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceSRIOV) DeepCopyInto(out *InterfaceSRIOV) {
+func (in *Probe) DeepCopyInto(out *Probe) {
 	*out = *in
+	in.Handler.DeepCopyInto(&out.Handler)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSRIOV.
-func (in *InterfaceSRIOV) DeepCopy() *InterfaceSRIOV {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceSRIOV)
+	out := new(Probe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InterfaceSlirp) DeepCopyInto(out *InterfaceSlirp) {
+func (in *QAT) DeepCopyInto(out *QAT) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSlirp.
-func (in *InterfaceSlirp) DeepCopy() *InterfaceSlirp {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QAT.
+func (in *QAT) DeepCopy() *QAT {
 	if in == nil {
 		return nil
 	}
-	out := new(InterfaceSlirp)
+	out := new(QAT)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KVMTimer) DeepCopyInto(out *KVMTimer) {
+func (in *RTCTimer) DeepCopyInto(out *RTCTimer) {
 	*out = *in
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
@@ -1208,767 +3312,758 @@ func (in *KVMTimer) DeepCopyInto(out *KVMTimer) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KVMTimer.
-func (in *KVMTimer) DeepCopy() *KVMTimer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RTCTimer.
+func (in *RTCTimer) DeepCopy() *RTCTimer {
 	if in == nil {
 		return nil
 	}
-	out := new(KVMTimer)
+	out := new(RTCTimer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirt) DeepCopyInto(out *KubeVirt) {
+func (in *Realtime) DeepCopyInto(out *Realtime) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(uint32)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirt.
-func (in *KubeVirt) DeepCopy() *KubeVirt {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Realtime.
+func (in *Realtime) DeepCopy() *Realtime {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirt)
+	out := new(Realtime)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeVirt) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtCertificateRotateStrategy) DeepCopyInto(out *KubeVirtCertificateRotateStrategy) {
+func (in *RenameOptions) DeepCopyInto(out *RenameOptions) {
 	*out = *in
-	if in.SelfSigned != nil {
-		in, out := &in.SelfSigned, &out.SelfSigned
-		*out = new(KubeVirtSelfSignConfiguration)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	if in.OldName != nil {
+		in, out := &in.OldName, &out.OldName
+		*out = new(string)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCertificateRotateStrategy.
-func (in *KubeVirtCertificateRotateStrategy) DeepCopy() *KubeVirtCertificateRotateStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenameOptions.
+func (in *RenameOptions) DeepCopy() *RenameOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtCertificateRotateStrategy)
+	out := new(RenameOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtCondition) DeepCopyInto(out *KubeVirtCondition) {
+func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
 	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtCondition.
-func (in *KubeVirtCondition) DeepCopy() *KubeVirtCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirements.
+func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtCondition)
+	out := new(ResourceRequirements)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtConfiguration) DeepCopyInto(out *KubeVirtConfiguration) {
+func (in *RestartOptions) DeepCopyInto(out *RestartOptions) {
 	*out = *in
-	if in.CPURequest != nil {
-		in, out := &in.CPURequest, &out.CPURequest
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.DeveloperConfiguration != nil {
-		in, out := &in.DeveloperConfiguration, &out.DeveloperConfiguration
-		*out = new(DeveloperConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EmulatedMachines != nil {
-		in, out := &in.EmulatedMachines, &out.EmulatedMachines
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.MigrationConfiguration != nil {
-		in, out := &in.MigrationConfiguration, &out.MigrationConfiguration
-		*out = new(MigrationConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NetworkConfiguration != nil {
-		in, out := &in.NetworkConfiguration, &out.NetworkConfiguration
-		*out = new(NetworkConfiguration)
-		**out = **in
-	}
-	if in.SMBIOSConfig != nil {
-		in, out := &in.SMBIOSConfig, &out.SMBIOSConfig
-		*out = new(SMBiosConfiguration)
+	out.TypeMeta = in.TypeMeta
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
 		**out = **in
 	}
-	if in.SupportedGuestAgentVersions != nil {
-		in, out := &in.SupportedGuestAgentVersions, &out.SupportedGuestAgentVersions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtConfiguration.
-func (in *KubeVirtConfiguration) DeepCopy() *KubeVirtConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartOptions.
+func (in *RestartOptions) DeepCopy() *RestartOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtConfiguration)
+	out := new(RestartOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtList) DeepCopyInto(out *KubeVirtList) {
+func (in *Rng) DeepCopyInto(out *Rng) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KubeVirt, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtList.
-func (in *KubeVirtList) DeepCopy() *KubeVirtList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rng.
+func (in *Rng) DeepCopy() *Rng {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtList)
+	out := new(Rng)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeVirtList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtSelfSignConfiguration) DeepCopyInto(out *KubeVirtSelfSignConfiguration) {
+func (in *RollbackOptions) DeepCopyInto(out *RollbackOptions) {
 	*out = *in
-	if in.CARotateInterval != nil {
-		in, out := &in.CARotateInterval, &out.CARotateInterval
-		*out = new(metav1.Duration)
-		**out = **in
-	}
-	if in.CertRotateInterval != nil {
-		in, out := &in.CertRotateInterval, &out.CertRotateInterval
-		*out = new(metav1.Duration)
-		**out = **in
-	}
-	if in.CAOverlapInterval != nil {
-		in, out := &in.CAOverlapInterval, &out.CAOverlapInterval
-		*out = new(metav1.Duration)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtSelfSignConfiguration.
-func (in *KubeVirtSelfSignConfiguration) DeepCopy() *KubeVirtSelfSignConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackOptions.
+func (in *RollbackOptions) DeepCopy() *RollbackOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtSelfSignConfiguration)
+	out := new(RollbackOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtSpec) DeepCopyInto(out *KubeVirtSpec) {
+func (in *SEV) DeepCopyInto(out *SEV) {
 	*out = *in
-	in.CertificateRotationStrategy.DeepCopyInto(&out.CertificateRotationStrategy)
-	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(SEVPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtSpec.
-func (in *KubeVirtSpec) DeepCopy() *KubeVirtSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SEV.
+func (in *SEV) DeepCopy() *SEV {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtSpec)
+	out := new(SEV)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeVirtStatus) DeepCopyInto(out *KubeVirtStatus) {
+func (in *SEVPolicy) DeepCopyInto(out *SEVPolicy) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]KubeVirtCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.EncryptedState != nil {
+		in, out := &in.EncryptedState, &out.EncryptedState
+		*out = new(bool)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeVirtStatus.
-func (in *KubeVirtStatus) DeepCopy() *KubeVirtStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SEVPolicy.
+func (in *SEVPolicy) DeepCopy() *SEVPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeVirtStatus)
+	out := new(SEVPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LunTarget) DeepCopyInto(out *LunTarget) {
+func (in *SMBiosConfiguration) DeepCopyInto(out *SMBiosConfiguration) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LunTarget.
-func (in *LunTarget) DeepCopy() *LunTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMBiosConfiguration.
+func (in *SMBiosConfiguration) DeepCopy() *SMBiosConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LunTarget)
+	out := new(SMBiosConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Machine) DeepCopyInto(out *Machine) {
+func (in *SeccompConfiguration) DeepCopyInto(out *SeccompConfiguration) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Machine.
-func (in *Machine) DeepCopy() *Machine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeccompConfiguration.
+func (in *SeccompConfiguration) DeepCopy() *SeccompConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Machine)
+	out := new(SeccompConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Memory) DeepCopyInto(out *Memory) {
+func (in *SecretVolumeSource) DeepCopyInto(out *SecretVolumeSource) {
 	*out = *in
-	if in.Hugepages != nil {
-		in, out := &in.Hugepages, &out.Hugepages
-		*out = new(Hugepages)
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Guest != nil {
-		in, out := &in.Guest, &out.Guest
-		x := (*in).DeepCopy()
-		*out = &x
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
-func (in *Memory) DeepCopy() *Memory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVolumeSource.
+func (in *SecretVolumeSource) DeepCopy() *SecretVolumeSource {
 	if in == nil {
 		return nil
 	}
-	out := new(Memory)
+	out := new(SecretVolumeSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MigrationConfiguration) DeepCopyInto(out *MigrationConfiguration) {
+func (in *SerialConsoleLog) DeepCopyInto(out *SerialConsoleLog) {
 	*out = *in
-	if in.BandwidthPerMigration != nil {
-		in, out := &in.BandwidthPerMigration, &out.BandwidthPerMigration
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
 		x := (*in).DeepCopy()
 		*out = &x
 	}
-	if in.CompletionTimeoutPerGiB != nil {
-		in, out := &in.CompletionTimeoutPerGiB, &out.CompletionTimeoutPerGiB
-		*out = new(int64)
-		**out = **in
-	}
-	if in.NodeDrainTaintKey != nil {
-		in, out := &in.NodeDrainTaintKey, &out.NodeDrainTaintKey
-		*out = new(string)
-		**out = **in
-	}
-	if in.ParallelOutboundMigrationsPerNode != nil {
-		in, out := &in.ParallelOutboundMigrationsPerNode, &out.ParallelOutboundMigrationsPerNode
-		*out = new(uint32)
-		**out = **in
-	}
-	if in.ParallelMigrationsPerCluster != nil {
-		in, out := &in.ParallelMigrationsPerCluster, &out.ParallelMigrationsPerCluster
+	if in.MaxFiles != nil {
+		in, out := &in.MaxFiles, &out.MaxFiles
 		*out = new(uint32)
 		**out = **in
 	}
-	if in.ProgressTimeout != nil {
-		in, out := &in.ProgressTimeout, &out.ProgressTimeout
-		*out = new(int64)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationConfiguration.
-func (in *MigrationConfiguration) DeepCopy() *MigrationConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SerialConsoleLog.
+func (in *SerialConsoleLog) DeepCopy() *SerialConsoleLog {
 	if in == nil {
 		return nil
 	}
-	out := new(MigrationConfiguration)
+	out := new(SerialConsoleLog)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MultusNetwork) DeepCopyInto(out *MultusNetwork) {
+func (in *ServiceAccountVolumeSource) DeepCopyInto(out *ServiceAccountVolumeSource) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultusNetwork.
-func (in *MultusNetwork) DeepCopy() *MultusNetwork {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountVolumeSource.
+func (in *ServiceAccountVolumeSource) DeepCopy() *ServiceAccountVolumeSource {
 	if in == nil {
 		return nil
 	}
-	out := new(MultusNetwork)
+	out := new(ServiceAccountVolumeSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Network) DeepCopyInto(out *Network) {
+func (in *SoundDevice) DeepCopyInto(out *SoundDevice) {
 	*out = *in
-	in.NetworkSource.DeepCopyInto(&out.NetworkSource)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Network.
-func (in *Network) DeepCopy() *Network {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SoundDevice.
+func (in *SoundDevice) DeepCopy() *SoundDevice {
 	if in == nil {
 		return nil
 	}
-	out := new(Network)
+	out := new(SoundDevice)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfiguration) DeepCopyInto(out *NetworkConfiguration) {
+func (in *StopOptions) DeepCopyInto(out *StopOptions) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfiguration.
-func (in *NetworkConfiguration) DeepCopy() *NetworkConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StopOptions.
+func (in *StopOptions) DeepCopy() *StopOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfiguration)
+	out := new(StopOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkSource) DeepCopyInto(out *NetworkSource) {
+func (in *SysprepSource) DeepCopyInto(out *SysprepSource) {
 	*out = *in
-	if in.Pod != nil {
-		in, out := &in.Pod, &out.Pod
-		*out = new(PodNetwork)
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
-	if in.Multus != nil {
-		in, out := &in.Multus, &out.Multus
-		*out = new(MultusNetwork)
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSource.
-func (in *NetworkSource) DeepCopy() *NetworkSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SysprepSource.
+func (in *SysprepSource) DeepCopy() *SysprepSource {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkSource)
+	out := new(SysprepSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PITTimer) DeepCopyInto(out *PITTimer) {
+func (in *TPMDevice) DeepCopyInto(out *TPMDevice) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMDevice.
+func (in *TPMDevice) DeepCopy() *TPMDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Timer) DeepCopyInto(out *Timer) {
+	*out = *in
+	if in.HPET != nil {
+		in, out := &in.HPET, &out.HPET
+		*out = new(HPETTimer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KVM != nil {
+		in, out := &in.KVM, &out.KVM
+		*out = new(KVMTimer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PIT != nil {
+		in, out := &in.PIT, &out.PIT
+		*out = new(PITTimer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RTC != nil {
+		in, out := &in.RTC, &out.RTC
+		*out = new(RTCTimer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hyperv != nil {
+		in, out := &in.Hyperv, &out.Hyperv
+		*out = new(HypervTimer)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITTimer.
-func (in *PITTimer) DeepCopy() *PITTimer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timer.
+func (in *Timer) DeepCopy() *Timer {
 	if in == nil {
 		return nil
 	}
-	out := new(PITTimer)
+	out := new(Timer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodNetwork) DeepCopyInto(out *PodNetwork) {
+func (in *VCPUPin) DeepCopyInto(out *VCPUPin) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodNetwork.
-func (in *PodNetwork) DeepCopy() *PodNetwork {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCPUPin.
+func (in *VCPUPin) DeepCopy() *VCPUPin {
 	if in == nil {
 		return nil
 	}
-	out := new(PodNetwork)
+	out := new(VCPUPin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Port) DeepCopyInto(out *Port) {
+func (in *VMISelector) DeepCopyInto(out *VMISelector) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Port.
-func (in *Port) DeepCopy() *Port {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMISelector.
+func (in *VMISelector) DeepCopy() *VMISelector {
 	if in == nil {
 		return nil
 	}
-	out := new(Port)
+	out := new(VMISelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Probe) DeepCopyInto(out *Probe) {
+func (in *VNCToken) DeepCopyInto(out *VNCToken) {
 	*out = *in
-	in.Handler.DeepCopyInto(&out.Handler)
+	out.TypeMeta = in.TypeMeta
+	in.ExpirationTimestamp.DeepCopyInto(&out.ExpirationTimestamp)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
-func (in *Probe) DeepCopy() *Probe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VNCToken.
+func (in *VNCToken) DeepCopy() *VNCToken {
 	if in == nil {
 		return nil
 	}
-	out := new(Probe)
+	out := new(VNCToken)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VNCToken) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QAT) DeepCopyInto(out *QAT) {
+func (in *VirtualMachine) DeepCopyInto(out *VirtualMachine) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QAT.
-func (in *QAT) DeepCopy() *QAT {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachine.
+func (in *VirtualMachine) DeepCopy() *VirtualMachine {
 	if in == nil {
 		return nil
 	}
-	out := new(QAT)
+	out := new(VirtualMachine)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachine) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RTCTimer) DeepCopyInto(out *RTCTimer) {
+func (in *VirtualMachineBackup) DeepCopyInto(out *VirtualMachineBackup) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RTCTimer.
-func (in *RTCTimer) DeepCopy() *RTCTimer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackup.
+func (in *VirtualMachineBackup) DeepCopy() *VirtualMachineBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(RTCTimer)
+	out := new(VirtualMachineBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RenameOptions) DeepCopyInto(out *RenameOptions) {
+func (in *VirtualMachineBackupList) DeepCopyInto(out *VirtualMachineBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	if in.OldName != nil {
-		in, out := &in.OldName, &out.OldName
-		*out = new(string)
-		**out = **in
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenameOptions.
-func (in *RenameOptions) DeepCopy() *RenameOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupList.
+func (in *VirtualMachineBackupList) DeepCopy() *VirtualMachineBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(RenameOptions)
+	out := new(VirtualMachineBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
+func (in *VirtualMachineBackupSpec) DeepCopyInto(out *VirtualMachineBackupSpec) {
 	*out = *in
-	if in.Requests != nil {
-		in, out := &in.Requests, &out.Requests
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
+	if in.Incremental != nil {
+		in, out := &in.Incremental, &out.Incremental
+		*out = new(string)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirements.
-func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupSpec.
+func (in *VirtualMachineBackupSpec) DeepCopy() *VirtualMachineBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRequirements)
+	out := new(VirtualMachineBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestartOptions) DeepCopyInto(out *RestartOptions) {
+func (in *VirtualMachineBackupStatus) DeepCopyInto(out *VirtualMachineBackupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	if in.GracePeriodSeconds != nil {
-		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
-		*out = new(int64)
-		**out = **in
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VirtualMachineBackupVolume, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartOptions.
-func (in *RestartOptions) DeepCopy() *RestartOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupStatus.
+func (in *VirtualMachineBackupStatus) DeepCopy() *VirtualMachineBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RestartOptions)
+	out := new(VirtualMachineBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Rng) DeepCopyInto(out *Rng) {
+func (in *VirtualMachineBackupVolume) DeepCopyInto(out *VirtualMachineBackupVolume) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rng.
-func (in *Rng) DeepCopy() *Rng {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineBackupVolume.
+func (in *VirtualMachineBackupVolume) DeepCopy() *VirtualMachineBackupVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(Rng)
+	out := new(VirtualMachineBackupVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SMBiosConfiguration) DeepCopyInto(out *SMBiosConfiguration) {
+func (in *VirtualMachineCondition) DeepCopyInto(out *VirtualMachineCondition) {
 	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMBiosConfiguration.
-func (in *SMBiosConfiguration) DeepCopy() *SMBiosConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCondition.
+func (in *VirtualMachineCondition) DeepCopy() *VirtualMachineCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(SMBiosConfiguration)
+	out := new(VirtualMachineCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretVolumeSource) DeepCopyInto(out *SecretVolumeSource) {
+func (in *VirtualMachineExport) DeepCopyInto(out *VirtualMachineExport) {
 	*out = *in
-	if in.Optional != nil {
-		in, out := &in.Optional, &out.Optional
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretVolumeSource.
-func (in *SecretVolumeSource) DeepCopy() *SecretVolumeSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExport.
+func (in *VirtualMachineExport) DeepCopy() *VirtualMachineExport {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretVolumeSource)
+	out := new(VirtualMachineExport)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceAccountVolumeSource) DeepCopyInto(out *ServiceAccountVolumeSource) {
+func (in *VirtualMachineExportList) DeepCopyInto(out *VirtualMachineExportList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountVolumeSource.
-func (in *ServiceAccountVolumeSource) DeepCopy() *ServiceAccountVolumeSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportList.
+func (in *VirtualMachineExportList) DeepCopy() *VirtualMachineExportList {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceAccountVolumeSource)
+	out := new(VirtualMachineExportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Timer) DeepCopyInto(out *Timer) {
+func (in *VirtualMachineExportSpec) DeepCopyInto(out *VirtualMachineExportSpec) {
 	*out = *in
-	if in.HPET != nil {
-		in, out := &in.HPET, &out.HPET
-		*out = new(HPETTimer)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.KVM != nil {
-		in, out := &in.KVM, &out.KVM
-		*out = new(KVMTimer)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PIT != nil {
-		in, out := &in.PIT, &out.PIT
-		*out = new(PITTimer)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RTC != nil {
-		in, out := &in.RTC, &out.RTC
-		*out = new(RTCTimer)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Hyperv != nil {
-		in, out := &in.Hyperv, &out.Hyperv
-		*out = new(HypervTimer)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timer.
-func (in *Timer) DeepCopy() *Timer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportSpec.
+func (in *VirtualMachineExportSpec) DeepCopy() *VirtualMachineExportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Timer)
+	out := new(VirtualMachineExportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VMISelector) DeepCopyInto(out *VMISelector) {
+func (in *VirtualMachineExportStatus) DeepCopyInto(out *VirtualMachineExportStatus) {
 	*out = *in
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VirtualMachineExportVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMISelector.
-func (in *VMISelector) DeepCopy() *VMISelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportStatus.
+func (in *VirtualMachineExportStatus) DeepCopy() *VirtualMachineExportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VMISelector)
+	out := new(VirtualMachineExportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualMachine) DeepCopyInto(out *VirtualMachine) {
+func (in *VirtualMachineExportVolume) DeepCopyInto(out *VirtualMachineExportVolume) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]VirtualMachineExportVolumeFormat, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachine.
-func (in *VirtualMachine) DeepCopy() *VirtualMachine {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportVolume.
+func (in *VirtualMachineExportVolume) DeepCopy() *VirtualMachineExportVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(VirtualMachine)
+	out := new(VirtualMachineExportVolume)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VirtualMachine) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VirtualMachineCondition) DeepCopyInto(out *VirtualMachineCondition) {
+func (in *VirtualMachineExportVolumeFormat) DeepCopyInto(out *VirtualMachineExportVolumeFormat) {
 	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineCondition.
-func (in *VirtualMachineCondition) DeepCopy() *VirtualMachineCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineExportVolumeFormat.
+func (in *VirtualMachineExportVolumeFormat) DeepCopy() *VirtualMachineExportVolumeFormat {
 	if in == nil {
 		return nil
 	}
-	out := new(VirtualMachineCondition)
+	out := new(VirtualMachineExportVolumeFormat)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2179,7 +4274,33 @@ func (in *VirtualMachineInstanceGuestOSUserList) DeepCopyObject() runtime.Object
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceLifecycle) DeepCopyInto(out *VirtualMachineInstanceLifecycle) {
+	*out = *in
+	if in.PostStart != nil {
+		in, out := &in.PostStart, &out.PostStart
+		*out = new(LifecycleHandler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = new(LifecycleHandler)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceLifecycle.
+func (in *VirtualMachineInstanceLifecycle) DeepCopy() *VirtualMachineInstanceLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceLifecycle)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -2215,6 +4336,30 @@ func (in *VirtualMachineInstanceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceMemoryDumpStatus) DeepCopyInto(out *VirtualMachineInstanceMemoryDumpStatus) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTimestamp != nil {
+		in, out := &in.EndTimestamp, &out.EndTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceMemoryDumpStatus.
+func (in *VirtualMachineInstanceMemoryDumpStatus) DeepCopy() *VirtualMachineInstanceMemoryDumpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceMemoryDumpStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineInstanceMigration) DeepCopyInto(out *VirtualMachineInstanceMigration) {
 	*out = *in
@@ -2372,6 +4517,11 @@ func (in *VirtualMachineInstanceNetworkInterface) DeepCopyInto(out *VirtualMachi
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AllowedAddresses != nil {
+		in, out := &in.AllowedAddresses, &out.AllowedAddresses
+		*out = new(AllowedAddresses)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2385,6 +4535,23 @@ func (in *VirtualMachineInstanceNetworkInterface) DeepCopy() *VirtualMachineInst
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstancePhaseTransitionTimestamp) DeepCopyInto(out *VirtualMachineInstancePhaseTransitionTimestamp) {
+	*out = *in
+	in.PhaseTransitionTimestamp.DeepCopyInto(&out.PhaseTransitionTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstancePhaseTransitionTimestamp.
+func (in *VirtualMachineInstancePhaseTransitionTimestamp) DeepCopy() *VirtualMachineInstancePhaseTransitionTimestamp {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstancePhaseTransitionTimestamp)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineInstancePreset) DeepCopyInto(out *VirtualMachineInstancePreset) {
 	*out = *in
@@ -2587,6 +4754,11 @@ func (in *VirtualMachineInstanceReplicaSetStatus) DeepCopyInto(out *VirtualMachi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]VirtualMachineInstanceReplicaSetZoneStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2600,6 +4772,22 @@ func (in *VirtualMachineInstanceReplicaSetStatus) DeepCopy() *VirtualMachineInst
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceReplicaSetZoneStatus) DeepCopyInto(out *VirtualMachineInstanceReplicaSetZoneStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceReplicaSetZoneStatus.
+func (in *VirtualMachineInstanceReplicaSetZoneStatus) DeepCopy() *VirtualMachineInstanceReplicaSetZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceReplicaSetZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineInstanceSpec) DeepCopyInto(out *VirtualMachineInstanceSpec) {
 	*out = *in
@@ -2623,6 +4811,13 @@ func (in *VirtualMachineInstanceSpec) DeepCopyInto(out *VirtualMachineInstanceSp
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.EvictionStrategy != nil {
 		in, out := &in.EvictionStrategy, &out.EvictionStrategy
 		*out = new(EvictionStrategy)
@@ -2662,6 +4857,21 @@ func (in *VirtualMachineInstanceSpec) DeepCopyInto(out *VirtualMachineInstanceSp
 		*out = new(corev1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskCompaction != nil {
+		in, out := &in.DiskCompaction, &out.DiskCompaction
+		*out = new(DiskCompaction)
+		**out = **in
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(VirtualMachineInstanceLifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IOErrorRecovery != nil {
+		in, out := &in.IOErrorRecovery, &out.IOErrorRecovery
+		*out = new(IOErrorRecovery)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2675,6 +4885,32 @@ func (in *VirtualMachineInstanceSpec) DeepCopy() *VirtualMachineInstanceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstanceStats) DeepCopyInto(out *VirtualMachineInstanceStats) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstanceStats.
+func (in *VirtualMachineInstanceStats) DeepCopy() *VirtualMachineInstanceStats {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstanceStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineInstanceStats) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineInstanceStatus) DeepCopyInto(out *VirtualMachineInstanceStatus) {
 	*out = *in
@@ -2710,6 +4946,35 @@ func (in *VirtualMachineInstanceStatus) DeepCopyInto(out *VirtualMachineInstance
 			(*out)[key] = val
 		}
 	}
+	if in.DiskCompaction != nil {
+		in, out := &in.DiskCompaction, &out.DiskCompaction
+		*out = new(DiskCompactionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryDump != nil {
+		in, out := &in.MemoryDump, &out.MemoryDump
+		*out = new(VirtualMachineInstanceMemoryDumpStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VCPUPinning != nil {
+		in, out := &in.VCPUPinning, &out.VCPUPinning
+		*out = make([]VCPUPin, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPClaims != nil {
+		in, out := &in.IPClaims, &out.IPClaims
+		*out = make([]InterfaceIPClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PhaseTransitionTimestamps != nil {
+		in, out := &in.PhaseTransitionTimestamps, &out.PhaseTransitionTimestamps
+		*out = make([]VirtualMachineInstancePhaseTransitionTimestamp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -2741,6 +5006,84 @@ func (in *VirtualMachineInstanceTemplateSpec) DeepCopy() *VirtualMachineInstance
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstancetype) DeepCopyInto(out *VirtualMachineInstancetype) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstancetype.
+func (in *VirtualMachineInstancetype) DeepCopy() *VirtualMachineInstancetype {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstancetype)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineInstancetype) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstancetypeList) DeepCopyInto(out *VirtualMachineInstancetypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachineInstancetype, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstancetypeList.
+func (in *VirtualMachineInstancetypeList) DeepCopy() *VirtualMachineInstancetypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstancetypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachineInstancetypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineInstancetypeSpec) DeepCopyInto(out *VirtualMachineInstancetypeSpec) {
+	*out = *in
+	out.CPU = in.CPU
+	in.Memory.DeepCopyInto(&out.Memory)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineInstancetypeSpec.
+func (in *VirtualMachineInstancetypeSpec) DeepCopy() *VirtualMachineInstancetypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineInstancetypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineList) DeepCopyInto(out *VirtualMachineList) {
 	*out = *in
@@ -2774,6 +5117,113 @@ func (in *VirtualMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePlacement) DeepCopyInto(out *VirtualMachinePlacement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePlacement.
+func (in *VirtualMachinePlacement) DeepCopy() *VirtualMachinePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreference) DeepCopyInto(out *VirtualMachinePreference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreference.
+func (in *VirtualMachinePreference) DeepCopy() *VirtualMachinePreference {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachinePreference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreferenceList) DeepCopyInto(out *VirtualMachinePreferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VirtualMachinePreference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreferenceList.
+func (in *VirtualMachinePreferenceList) DeepCopy() *VirtualMachinePreferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VirtualMachinePreferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachinePreferenceSpec) DeepCopyInto(out *VirtualMachinePreferenceSpec) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(CPUPreferences)
+		**out = **in
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = new(DevicePreferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Machine != nil {
+		in, out := &in.Machine, &out.Machine
+		*out = new(MachinePreferences)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachinePreferenceSpec.
+func (in *VirtualMachinePreferenceSpec) DeepCopy() *VirtualMachinePreferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachinePreferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineSpec) DeepCopyInto(out *VirtualMachineSpec) {
 	*out = *in
@@ -2799,6 +5249,21 @@ func (in *VirtualMachineSpec) DeepCopyInto(out *VirtualMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Instancetype != nil {
+		in, out := &in.Instancetype, &out.Instancetype
+		*out = new(InstancetypeMatcher)
+		**out = **in
+	}
+	if in.Preference != nil {
+		in, out := &in.Preference, &out.Preference
+		*out = new(PreferenceMatcher)
+		**out = **in
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(VirtualMachinePlacement)
+		**out = **in
+	}
 	return
 }
 
@@ -2862,6 +5327,13 @@ func (in *VirtualMachineStatus) DeepCopyInto(out *VirtualMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IPClaims != nil {
+		in, out := &in.IPClaims, &out.IPClaims
+		*out = make([]InterfaceIPClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -2915,6 +5387,16 @@ func (in *VolumeSource) DeepCopyInto(out *VolumeSource) {
 		*out = new(CloudInitConfigDriveSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ignition != nil {
+		in, out := &in.Ignition, &out.Ignition
+		*out = new(IgnitionSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sysprep != nil {
+		in, out := &in.Sysprep, &out.Sysprep
+		*out = new(SysprepSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ContainerDisk != nil {
 		in, out := &in.ContainerDisk, &out.ContainerDisk
 		*out = new(ContainerDiskSource)
@@ -2925,6 +5407,11 @@ func (in *VolumeSource) DeepCopyInto(out *VolumeSource) {
 		*out = new(EphemeralVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GoldenImage != nil {
+		in, out := &in.GoldenImage, &out.GoldenImage
+		*out = new(GoldenImageVolumeSource)
+		**out = **in
+	}
 	if in.EmptyDisk != nil {
 		in, out := &in.EmptyDisk, &out.EmptyDisk
 		*out = new(EmptyDiskSource)
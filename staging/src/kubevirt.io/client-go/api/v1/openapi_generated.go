@@ -288,26 +288,49 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
 		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                 schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
 		"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                                         schema_apimachinery_pkg_util_intstr_IntOrString(ref),
+		"kubevirt.io/client-go/api/v1.AllowedAddresses":                                           schema_kubevirtio_client_go_api_v1_AllowedAddresses(ref),
+		"kubevirt.io/client-go/api/v1.AuditConfiguration":                                         schema_kubevirtio_client_go_api_v1_AuditConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.BIOS":                                                       schema_kubevirtio_client_go_api_v1_BIOS(ref),
+		"kubevirt.io/client-go/api/v1.BandwidthLimit":                                             schema_kubevirtio_client_go_api_v1_BandwidthLimit(ref),
 		"kubevirt.io/client-go/api/v1.Bootloader":                                                 schema_kubevirtio_client_go_api_v1_Bootloader(ref),
 		"kubevirt.io/client-go/api/v1.CDRomTarget":                                                schema_kubevirtio_client_go_api_v1_CDRomTarget(ref),
 		"kubevirt.io/client-go/api/v1.CPU":                                                        schema_kubevirtio_client_go_api_v1_CPU(ref),
 		"kubevirt.io/client-go/api/v1.CPUFeature":                                                 schema_kubevirtio_client_go_api_v1_CPUFeature(ref),
+		"kubevirt.io/client-go/api/v1.CPUInstancetype":                                            schema_kubevirtio_client_go_api_v1_CPUInstancetype(ref),
+		"kubevirt.io/client-go/api/v1.CPUPreferences":                                             schema_kubevirtio_client_go_api_v1_CPUPreferences(ref),
+		"kubevirt.io/client-go/api/v1.CertConfig":                                                 schema_kubevirtio_client_go_api_v1_CertConfig(ref),
 		"kubevirt.io/client-go/api/v1.Chassis":                                                    schema_kubevirtio_client_go_api_v1_Chassis(ref),
+		"kubevirt.io/client-go/api/v1.ClientPassthroughDevices":                                   schema_kubevirtio_client_go_api_v1_ClientPassthroughDevices(ref),
 		"kubevirt.io/client-go/api/v1.Clock":                                                      schema_kubevirtio_client_go_api_v1_Clock(ref),
 		"kubevirt.io/client-go/api/v1.ClockOffset":                                                schema_kubevirtio_client_go_api_v1_ClockOffset(ref),
 		"kubevirt.io/client-go/api/v1.ClockOffsetUTC":                                             schema_kubevirtio_client_go_api_v1_ClockOffsetUTC(ref),
 		"kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource":                                 schema_kubevirtio_client_go_api_v1_CloudInitConfigDriveSource(ref),
 		"kubevirt.io/client-go/api/v1.CloudInitNoCloudSource":                                     schema_kubevirtio_client_go_api_v1_CloudInitNoCloudSource(ref),
+		"kubevirt.io/client-go/api/v1.ClusterCapabilities":                                        schema_kubevirtio_client_go_api_v1_ClusterCapabilities(ref),
 		"kubevirt.io/client-go/api/v1.ConfigMapVolumeSource":                                      schema_kubevirtio_client_go_api_v1_ConfigMapVolumeSource(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceFeatureResult":                                   schema_kubevirtio_client_go_api_v1_ConformanceFeatureResult(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceNetworkCheckup":                                  schema_kubevirtio_client_go_api_v1_ConformanceNetworkCheckup(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceNetworkCheckupResult":                            schema_kubevirtio_client_go_api_v1_ConformanceNetworkCheckupResult(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceRun":                                             schema_kubevirtio_client_go_api_v1_ConformanceRun(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceRunList":                                         schema_kubevirtio_client_go_api_v1_ConformanceRunList(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceRunSpec":                                         schema_kubevirtio_client_go_api_v1_ConformanceRunSpec(ref),
+		"kubevirt.io/client-go/api/v1.ConformanceRunStatus":                                       schema_kubevirtio_client_go_api_v1_ConformanceRunStatus(ref),
+		"kubevirt.io/client-go/api/v1.ConnectionLimits":                                           schema_kubevirtio_client_go_api_v1_ConnectionLimits(ref),
 		"kubevirt.io/client-go/api/v1.ContainerDiskSource":                                        schema_kubevirtio_client_go_api_v1_ContainerDiskSource(ref),
 		"kubevirt.io/client-go/api/v1.DHCPOptions":                                                schema_kubevirtio_client_go_api_v1_DHCPOptions(ref),
 		"kubevirt.io/client-go/api/v1.DHCPPrivateOptions":                                         schema_kubevirtio_client_go_api_v1_DHCPPrivateOptions(ref),
+		"kubevirt.io/client-go/api/v1.DHCPRoute":                                                  schema_kubevirtio_client_go_api_v1_DHCPRoute(ref),
 		"kubevirt.io/client-go/api/v1.DataVolumeSource":                                           schema_kubevirtio_client_go_api_v1_DataVolumeSource(ref),
+		"kubevirt.io/client-go/api/v1.DefaultNetworkPolicyConfiguration":                          schema_kubevirtio_client_go_api_v1_DefaultNetworkPolicyConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.DeveloperConfiguration":                                     schema_kubevirtio_client_go_api_v1_DeveloperConfiguration(ref),
+		"kubevirt.io/client-go/api/v1.DevicePreferences":                                          schema_kubevirtio_client_go_api_v1_DevicePreferences(ref),
 		"kubevirt.io/client-go/api/v1.Devices":                                                    schema_kubevirtio_client_go_api_v1_Devices(ref),
 		"kubevirt.io/client-go/api/v1.Disk":                                                       schema_kubevirtio_client_go_api_v1_Disk(ref),
+		"kubevirt.io/client-go/api/v1.DiskCompaction":                                             schema_kubevirtio_client_go_api_v1_DiskCompaction(ref),
+		"kubevirt.io/client-go/api/v1.DiskCompactionStatus":                                       schema_kubevirtio_client_go_api_v1_DiskCompactionStatus(ref),
 		"kubevirt.io/client-go/api/v1.DiskDevice":                                                 schema_kubevirtio_client_go_api_v1_DiskDevice(ref),
+		"kubevirt.io/client-go/api/v1.DiskIOTune":                                                 schema_kubevirtio_client_go_api_v1_DiskIOTune(ref),
+		"kubevirt.io/client-go/api/v1.DiskQueues":                                                 schema_kubevirtio_client_go_api_v1_DiskQueues(ref),
 		"kubevirt.io/client-go/api/v1.DiskTarget":                                                 schema_kubevirtio_client_go_api_v1_DiskTarget(ref),
 		"kubevirt.io/client-go/api/v1.DomainSpec":                                                 schema_kubevirtio_client_go_api_v1_DomainSpec(ref),
 		"kubevirt.io/client-go/api/v1.EFI":                                                        schema_kubevirtio_client_go_api_v1_EFI(ref),
@@ -319,23 +342,40 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/client-go/api/v1.FeatureState":                                               schema_kubevirtio_client_go_api_v1_FeatureState(ref),
 		"kubevirt.io/client-go/api/v1.FeatureVendorID":                                            schema_kubevirtio_client_go_api_v1_FeatureVendorID(ref),
 		"kubevirt.io/client-go/api/v1.Features":                                                   schema_kubevirtio_client_go_api_v1_Features(ref),
+		"kubevirt.io/client-go/api/v1.Filesystem":                                                 schema_kubevirtio_client_go_api_v1_Filesystem(ref),
+		"kubevirt.io/client-go/api/v1.FilesystemOverheadConfiguration":                            schema_kubevirtio_client_go_api_v1_FilesystemOverheadConfiguration(ref),
+		"kubevirt.io/client-go/api/v1.FilesystemVirtiofs":                                         schema_kubevirtio_client_go_api_v1_FilesystemVirtiofs(ref),
 		"kubevirt.io/client-go/api/v1.Firmware":                                                   schema_kubevirtio_client_go_api_v1_Firmware(ref),
 		"kubevirt.io/client-go/api/v1.FloppyTarget":                                               schema_kubevirtio_client_go_api_v1_FloppyTarget(ref),
+		"kubevirt.io/client-go/api/v1.GoldenImageVolumeSource":                                    schema_kubevirtio_client_go_api_v1_GoldenImageVolumeSource(ref),
 		"kubevirt.io/client-go/api/v1.GPU":                                                        schema_kubevirtio_client_go_api_v1_GPU(ref),
+		"kubevirt.io/client-go/api/v1.GuestAgentExecAction":                                       schema_kubevirtio_client_go_api_v1_GuestAgentExecAction(ref),
 		"kubevirt.io/client-go/api/v1.HPETTimer":                                                  schema_kubevirtio_client_go_api_v1_HPETTimer(ref),
+		"kubevirt.io/client-go/api/v1.HostDevice":                                                 schema_kubevirtio_client_go_api_v1_HostDevice(ref),
 		"kubevirt.io/client-go/api/v1.HostDisk":                                                   schema_kubevirtio_client_go_api_v1_HostDisk(ref),
 		"kubevirt.io/client-go/api/v1.Hugepages":                                                  schema_kubevirtio_client_go_api_v1_Hugepages(ref),
 		"kubevirt.io/client-go/api/v1.HypervTimer":                                                schema_kubevirtio_client_go_api_v1_HypervTimer(ref),
 		"kubevirt.io/client-go/api/v1.I6300ESBWatchdog":                                           schema_kubevirtio_client_go_api_v1_I6300ESBWatchdog(ref),
+		"kubevirt.io/client-go/api/v1.IOErrorRecovery":                                            schema_kubevirtio_client_go_api_v1_IOErrorRecovery(ref),
+		"kubevirt.io/client-go/api/v1.IOThread":                                                   schema_kubevirtio_client_go_api_v1_IOThread(ref),
+		"kubevirt.io/client-go/api/v1.IgnitionSource":                                             schema_kubevirtio_client_go_api_v1_IgnitionSource(ref),
 		"kubevirt.io/client-go/api/v1.Input":                                                      schema_kubevirtio_client_go_api_v1_Input(ref),
+		"kubevirt.io/client-go/api/v1.InstancetypeMatcher":                                        schema_kubevirtio_client_go_api_v1_InstancetypeMatcher(ref),
 		"kubevirt.io/client-go/api/v1.Interface":                                                  schema_kubevirtio_client_go_api_v1_Interface(ref),
+		"kubevirt.io/client-go/api/v1.InterfaceBandwidth":                                         schema_kubevirtio_client_go_api_v1_InterfaceBandwidth(ref),
 		"kubevirt.io/client-go/api/v1.InterfaceBindingMethod":                                     schema_kubevirtio_client_go_api_v1_InterfaceBindingMethod(ref),
 		"kubevirt.io/client-go/api/v1.InterfaceBridge":                                            schema_kubevirtio_client_go_api_v1_InterfaceBridge(ref),
+		"kubevirt.io/client-go/api/v1.InterfaceIPClaim":                                           schema_kubevirtio_client_go_api_v1_InterfaceIPClaim(ref),
+		"kubevirt.io/client-go/api/v1.InterfaceMacvtap":                                           schema_kubevirtio_client_go_api_v1_InterfaceMacvtap(ref),
 		"kubevirt.io/client-go/api/v1.InterfaceMasquerade":                                        schema_kubevirtio_client_go_api_v1_InterfaceMasquerade(ref),
+		"kubevirt.io/client-go/api/v1.InterfacePasst":                                             schema_kubevirtio_client_go_api_v1_InterfacePasst(ref),
 		"kubevirt.io/client-go/api/v1.InterfaceSRIOV":                                             schema_kubevirtio_client_go_api_v1_InterfaceSRIOV(ref),
 		"kubevirt.io/client-go/api/v1.InterfaceSlirp":                                             schema_kubevirtio_client_go_api_v1_InterfaceSlirp(ref),
+		"kubevirt.io/client-go/api/v1.KSMConfiguration":                                           schema_kubevirtio_client_go_api_v1_KSMConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.KVMTimer":                                                   schema_kubevirtio_client_go_api_v1_KVMTimer(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirt":                                                   schema_kubevirtio_client_go_api_v1_KubeVirt(ref),
+		"kubevirt.io/client-go/api/v1.KubeVirtCanaryRollout":                                      schema_kubevirtio_client_go_api_v1_KubeVirtCanaryRollout(ref),
+		"kubevirt.io/client-go/api/v1.KubeVirtCanaryRolloutStatus":                                schema_kubevirtio_client_go_api_v1_KubeVirtCanaryRolloutStatus(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirtCertificateRotateStrategy":                          schema_kubevirtio_client_go_api_v1_KubeVirtCertificateRotateStrategy(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirtCondition":                                          schema_kubevirtio_client_go_api_v1_KubeVirtCondition(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirtConfiguration":                                      schema_kubevirtio_client_go_api_v1_KubeVirtConfiguration(ref),
@@ -343,29 +383,71 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/client-go/api/v1.KubeVirtSelfSignConfiguration":                              schema_kubevirtio_client_go_api_v1_KubeVirtSelfSignConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirtSpec":                                               schema_kubevirtio_client_go_api_v1_KubeVirtSpec(ref),
 		"kubevirt.io/client-go/api/v1.KubeVirtStatus":                                             schema_kubevirtio_client_go_api_v1_KubeVirtStatus(ref),
+		"kubevirt.io/client-go/api/v1.KubeVirtWorkloadUpdateStrategy":                             schema_kubevirtio_client_go_api_v1_KubeVirtWorkloadUpdateStrategy(ref),
+		"kubevirt.io/client-go/api/v1.LaunchSecurity":                                             schema_kubevirtio_client_go_api_v1_LaunchSecurity(ref),
+		"kubevirt.io/client-go/api/v1.LauncherResources":                                          schema_kubevirtio_client_go_api_v1_LauncherResources(ref),
+		"kubevirt.io/client-go/api/v1.LifecycleHandler":                                           schema_kubevirtio_client_go_api_v1_LifecycleHandler(ref),
 		"kubevirt.io/client-go/api/v1.LunTarget":                                                  schema_kubevirtio_client_go_api_v1_LunTarget(ref),
 		"kubevirt.io/client-go/api/v1.Machine":                                                    schema_kubevirtio_client_go_api_v1_Machine(ref),
+		"kubevirt.io/client-go/api/v1.MachinePreferences":                                         schema_kubevirtio_client_go_api_v1_MachinePreferences(ref),
+		"kubevirt.io/client-go/api/v1.MediatedHostDevice":                                         schema_kubevirtio_client_go_api_v1_MediatedHostDevice(ref),
+		"kubevirt.io/client-go/api/v1.MemBalloon":                                                 schema_kubevirtio_client_go_api_v1_MemBalloon(ref),
 		"kubevirt.io/client-go/api/v1.Memory":                                                     schema_kubevirtio_client_go_api_v1_Memory(ref),
+		"kubevirt.io/client-go/api/v1.MemoryInstancetype":                                         schema_kubevirtio_client_go_api_v1_MemoryInstancetype(ref),
+		"kubevirt.io/client-go/api/v1.MetricsConfiguration":                                       schema_kubevirtio_client_go_api_v1_MetricsConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.MigrationConfiguration":                                     schema_kubevirtio_client_go_api_v1_MigrationConfiguration(ref),
+		"kubevirt.io/client-go/api/v1.MultiQueueConfiguration":                                    schema_kubevirtio_client_go_api_v1_MultiQueueConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.MultusNetwork":                                              schema_kubevirtio_client_go_api_v1_MultusNetwork(ref),
+		"kubevirt.io/client-go/api/v1.NUMA":                                                       schema_kubevirtio_client_go_api_v1_NUMA(ref),
+		"kubevirt.io/client-go/api/v1.NUMAGuestMappingPassthrough":                                schema_kubevirtio_client_go_api_v1_NUMAGuestMappingPassthrough(ref),
 		"kubevirt.io/client-go/api/v1.Network":                                                    schema_kubevirtio_client_go_api_v1_Network(ref),
 		"kubevirt.io/client-go/api/v1.NetworkConfiguration":                                       schema_kubevirtio_client_go_api_v1_NetworkConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.NetworkSource":                                              schema_kubevirtio_client_go_api_v1_NetworkSource(ref),
+		"kubevirt.io/client-go/api/v1.NodeFencingConfiguration":                                   schema_kubevirtio_client_go_api_v1_NodeFencingConfiguration(ref),
+		"kubevirt.io/client-go/api/v1.NodeFencingConfirmation":                                    schema_kubevirtio_client_go_api_v1_NodeFencingConfirmation(ref),
+		"kubevirt.io/client-go/api/v1.NodeFencingResource":                                        schema_kubevirtio_client_go_api_v1_NodeFencingResource(ref),
+		"kubevirt.io/client-go/api/v1.OVNNetwork":                                                 schema_kubevirtio_client_go_api_v1_OVNNetwork(ref),
 		"kubevirt.io/client-go/api/v1.PITTimer":                                                   schema_kubevirtio_client_go_api_v1_PITTimer(ref),
+		"kubevirt.io/client-go/api/v1.PanicDevice":                                                schema_kubevirtio_client_go_api_v1_PanicDevice(ref),
+		"kubevirt.io/client-go/api/v1.PciHostDevice":                                              schema_kubevirtio_client_go_api_v1_PciHostDevice(ref),
+		"kubevirt.io/client-go/api/v1.PermittedHostDevices":                                       schema_kubevirtio_client_go_api_v1_PermittedHostDevices(ref),
 		"kubevirt.io/client-go/api/v1.PodNetwork":                                                 schema_kubevirtio_client_go_api_v1_PodNetwork(ref),
 		"kubevirt.io/client-go/api/v1.Port":                                                       schema_kubevirtio_client_go_api_v1_Port(ref),
+		"kubevirt.io/client-go/api/v1.PreferenceMatcher":                                          schema_kubevirtio_client_go_api_v1_PreferenceMatcher(ref),
 		"kubevirt.io/client-go/api/v1.Probe":                                                      schema_kubevirtio_client_go_api_v1_Probe(ref),
 		"kubevirt.io/client-go/api/v1.QAT":                                                        schema_kubevirtio_client_go_api_v1_QAT(ref),
 		"kubevirt.io/client-go/api/v1.RTCTimer":                                                   schema_kubevirtio_client_go_api_v1_RTCTimer(ref),
+		"kubevirt.io/client-go/api/v1.Realtime":                                                   schema_kubevirtio_client_go_api_v1_Realtime(ref),
 		"kubevirt.io/client-go/api/v1.ResourceRequirements":                                       schema_kubevirtio_client_go_api_v1_ResourceRequirements(ref),
 		"kubevirt.io/client-go/api/v1.RestartOptions":                                             schema_kubevirtio_client_go_api_v1_RestartOptions(ref),
 		"kubevirt.io/client-go/api/v1.Rng":                                                        schema_kubevirtio_client_go_api_v1_Rng(ref),
+		"kubevirt.io/client-go/api/v1.SEV":                                                        schema_kubevirtio_client_go_api_v1_SEV(ref),
+		"kubevirt.io/client-go/api/v1.SEVPolicy":                                                  schema_kubevirtio_client_go_api_v1_SEVPolicy(ref),
 		"kubevirt.io/client-go/api/v1.SMBiosConfiguration":                                        schema_kubevirtio_client_go_api_v1_SMBiosConfiguration(ref),
+		"kubevirt.io/client-go/api/v1.SeccompConfiguration":                                       schema_kubevirtio_client_go_api_v1_SeccompConfiguration(ref),
 		"kubevirt.io/client-go/api/v1.SecretVolumeSource":                                         schema_kubevirtio_client_go_api_v1_SecretVolumeSource(ref),
+		"kubevirt.io/client-go/api/v1.SerialConsoleLog":                                           schema_kubevirtio_client_go_api_v1_SerialConsoleLog(ref),
 		"kubevirt.io/client-go/api/v1.ServiceAccountVolumeSource":                                 schema_kubevirtio_client_go_api_v1_ServiceAccountVolumeSource(ref),
+		"kubevirt.io/client-go/api/v1.SoundDevice":                                                schema_kubevirtio_client_go_api_v1_SoundDevice(ref),
+		"kubevirt.io/client-go/api/v1.StopOptions":                                                schema_kubevirtio_client_go_api_v1_StopOptions(ref),
+		"kubevirt.io/client-go/api/v1.SysprepSource":                                              schema_kubevirtio_client_go_api_v1_SysprepSource(ref),
+		"kubevirt.io/client-go/api/v1.TPMDevice":                                                  schema_kubevirtio_client_go_api_v1_TPMDevice(ref),
 		"kubevirt.io/client-go/api/v1.Timer":                                                      schema_kubevirtio_client_go_api_v1_Timer(ref),
+		"kubevirt.io/client-go/api/v1.VCPUPin":                                                    schema_kubevirtio_client_go_api_v1_VCPUPin(ref),
+		"kubevirt.io/client-go/api/v1.VNCToken":                                                   schema_kubevirtio_client_go_api_v1_VNCToken(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachine":                                             schema_kubevirtio_client_go_api_v1_VirtualMachine(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineBackup":                                       schema_kubevirtio_client_go_api_v1_VirtualMachineBackup(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineBackupList":                                   schema_kubevirtio_client_go_api_v1_VirtualMachineBackupList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineBackupSpec":                                   schema_kubevirtio_client_go_api_v1_VirtualMachineBackupSpec(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineBackupStatus":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineBackupStatus(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineBackupVolume":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineBackupVolume(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineCondition":                                    schema_kubevirtio_client_go_api_v1_VirtualMachineCondition(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExport":                                       schema_kubevirtio_client_go_api_v1_VirtualMachineExport(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExportList":                                   schema_kubevirtio_client_go_api_v1_VirtualMachineExportList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExportSpec":                                   schema_kubevirtio_client_go_api_v1_VirtualMachineExportSpec(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExportStatus":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineExportStatus(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExportVolume":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineExportVolume(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineExportVolumeFormat":                           schema_kubevirtio_client_go_api_v1_VirtualMachineExportVolumeFormat(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstance":                                     schema_kubevirtio_client_go_api_v1_VirtualMachineInstance(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceCondition":                            schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceCondition(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem":                           schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystem(ref),
@@ -375,7 +457,9 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo":                          schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSInfo(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser":                          schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUser(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUserList":                      schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUserList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceLifecycle":                            schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceLifecycle(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceList":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMemoryDumpStatus":                     schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMemoryDumpStatus(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigration":                            schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigration(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationCondition":                   schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationCondition(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationList":                        schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationList(ref),
@@ -383,6 +467,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationState":                       schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationState(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationStatus":                      schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationStatus(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceNetworkInterface":                     schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceNetworkInterface(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstancePhaseTransitionTimestamp":             schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePhaseTransitionTimestamp(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstancePreset":                               schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePreset(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetList":                           schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetList(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetSpec":                           schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetSpec(ref),
@@ -391,10 +476,19 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetList":                       schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetList(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetSpec":                       schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetSpec(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetStatus":                     schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetStatus(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetZoneStatus":                 schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetZoneStatus(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceSpec(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceStats":                                schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceStats(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceStatus":                               schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceStatus(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec":                         schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceTemplateSpec(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstancetype":                                 schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetype(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstancetypeList":                             schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetypeList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachineInstancetypeSpec":                             schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetypeSpec(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineList":                                         schema_kubevirtio_client_go_api_v1_VirtualMachineList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachinePlacement":                                    schema_kubevirtio_client_go_api_v1_VirtualMachinePlacement(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachinePreference":                                   schema_kubevirtio_client_go_api_v1_VirtualMachinePreference(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachinePreferenceList":                               schema_kubevirtio_client_go_api_v1_VirtualMachinePreferenceList(ref),
+		"kubevirt.io/client-go/api/v1.VirtualMachinePreferenceSpec":                               schema_kubevirtio_client_go_api_v1_VirtualMachinePreferenceSpec(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineSpec":                                         schema_kubevirtio_client_go_api_v1_VirtualMachineSpec(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineStateChangeRequest":                           schema_kubevirtio_client_go_api_v1_VirtualMachineStateChangeRequest(ref),
 		"kubevirt.io/client-go/api/v1.VirtualMachineStatus":                                       schema_kubevirtio_client_go_api_v1_VirtualMachineStatus(ref),
@@ -13467,6 +13561,74 @@ func schema_apimachinery_pkg_util_intstr_IntOrString(ref common.ReferenceCallbac
 	}
 }
 
+func schema_kubevirtio_client_go_api_v1_AllowedAddresses(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Additional addresses a guest is allowed to use on an interface.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"macAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Unicast MAC addresses, e.g. the virtual MAC of a VRRP instance. The bridge binding drops the frames the guest sends from any other MAC address than these and the one of the interface. The virtual function of an SR-IOV interface keeps its spoof checking and is trusted, so that the guest can add filters for them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"ipAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IP addresses or CIDRs, e.g. the virtual IP of a VRRP instance. Once an address of a family is listed, the bridge binding drops the packets the guest sends from any other address of that family than these and the one of the interface. Only supported with the bridge binding.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_AuditConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AuditConfiguration holds the options for the audit events virt-api emits for privileged operations on vmis, like console connections or memory dumps",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"webhookURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WebhookURL is the address of a dedicated audit sink. The audit events are posted to it as audit.k8s.io/v1 EventList, like by the audit webhook backend of kube-apiserver",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"caBundle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CABundle is the PEM encoded CA bundle verifying the certificate of the audit sink. Defaults to the system trust store",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_kubevirtio_client_go_api_v1_BIOS(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -13478,6 +13640,41 @@ func schema_kubevirtio_client_go_api_v1_BIOS(ref common.ReferenceCallback) commo
 	}
 }
 
+func schema_kubevirtio_client_go_api_v1_BandwidthLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Rate limit of one direction of the traffic of an interface.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"average": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Average rate in kibibytes per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"peak": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maximum rate in kibibytes per second at which bursts can be received. Only supported for the ingress traffic.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"burst": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Amount of kibibytes which can be transferred in a single burst.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"average"},
+			},
+		},
+	}
+}
+
 func schema_kubevirtio_client_go_api_v1_Bootloader(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -13600,11 +13797,37 @@ func schema_kubevirtio_client_go_api_v1_CPU(ref common.ReferenceCallback) common
 							Format:      "",
 						},
 					},
+					"emulatorThreadPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EmulatorThreadPolicy controls on which pCPUs the emulator thread runs. One of: isolate, vcpu0, float isolate - one more dedicated pCPU is allocated for the emulator thread, same as IsolateEmulatorThread. vcpu0   - the emulator thread is pinned to the pCPU of vCPU 0. float   - the emulator thread may run on all pCPUs of the VMI. isolate and vcpu0 require DedicatedCPUPlacement. Defaults to isolate if IsolateEmulatorThread is set, float otherwise.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"threadPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThreadPolicy controls how the dedicated vCPUs are placed on the hyperthreads of the node. One of: fullCores, isolate fullCores - the vCPUs of a guest core are pinned to the sibling hyperthreads of a host core,\n            so that no other workload shares a core with the VMI. The guest threads per core\n            have to match the threads per core of the node.\nisolate   - every vCPU gets a host core of its own and the sibling hyperthreads stay idle.\n            Twice the pCPUs are allocated, which requires nodes with two threads per core.\nRequires DedicatedCPUPlacement and an explicit CPU topology.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"numa": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NUMA allows specifying settings for the guest NUMA topology.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.NUMA"),
+						},
+					},
+					"realtime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Realtime schedules the vCPUs of the VMI as realtime tasks with the fifo policy. The emulator thread and the IOThreads are kept away from the realtime vCPUs. Requires DedicatedCPUPlacement, hugepages and a node running a realtime kernel.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Realtime"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.CPUFeature"},
+			"kubevirt.io/client-go/api/v1.CPUFeature", "kubevirt.io/client-go/api/v1.NUMA", "kubevirt.io/client-go/api/v1.Realtime"},
 	}
 }
 
@@ -13636,6 +13859,88 @@ func schema_kubevirtio_client_go_api_v1_CPUFeature(ref common.ReferenceCallback)
 	}
 }
 
+func schema_kubevirtio_client_go_api_v1_CPUInstancetype(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CPUInstancetype describes the CPU of a VirtualMachineInstancetype",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"guest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of vCPUs of the guest. How they are arranged in sockets, cores and threads is taken from the preferred CPU topology of the VirtualMachinePreference.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"model": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CPU model of the guest",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dedicatedCpuPlacement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pin the vCPUs to dedicated pCPUs of the node",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"guest"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_CPUPreferences(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CPUPreferences describes the preferred CPU of a VirtualMachinePreference",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"preferredCPUTopology": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the vCPUs of the instancetype are arranged, one of preferSockets, preferCores or preferThreads. Defaults to preferSockets.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_CertConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CertConfig contains the tunables for TLS certificates",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The requested 'duration' (i.e. lifetime) of the Certificate.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"renewBefore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The amount of time before the currently issued certificate's \"notAfter\" time that we will begin to attempt to renew the certificate. Defaults to 20% of the duration.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_kubevirtio_client_go_api_v1_Chassis(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -13679,6 +13984,17 @@ func schema_kubevirtio_client_go_api_v1_Chassis(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_kubevirtio_client_go_api_v1_ClientPassthroughDevices(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represent a subset of client devices that can be accessed by VMI. At the\nmoment only, USB devices using Usbredir's library and tooling. Another fit\nwould be a smartcard with libcacard.\n\nThe struct is currently empty as there is no immediate request for\nuser-facing APIs. This structure simply turns on USB redirection of\nUsbClientPassthroughMaxNumberOf devices.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
 func schema_kubevirtio_client_go_api_v1_Clock(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -13871,856 +14187,4039 @@ func schema_kubevirtio_client_go_api_v1_CloudInitNoCloudSource(ref common.Refere
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_ConfigMapVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ClusterCapabilities(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ConfigMapVolumeSource adapts a ConfigMap into a volume. More info: https://kubernetes.io/docs/concepts/storage/volumes/#configmap",
+				Description: "ClusterCapabilities describes what the cluster supports, so that clients can adapt to it without reading the KubeVirt CR",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"featureGates": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the referent. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names",
+							Description: "FeatureGates are the feature gates enabled on the cluster",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"machineType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MachineType is the machine type of vmis which don't request one",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"optional": {
+					"emulatedMachines": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify whether the ConfigMap or it's keys must be defined",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "EmulatedMachines are the machine types vmis may request, as glob patterns",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
-					"volumeLabel": {
+					"architecture": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The volume label of the resulting disk inside the VMI. Different bootstrapping mechanisms require different values. Typical values are \"cidata\" (cloud-init), \"config-2\" (cloud-init) or \"OEMDRV\" (kickstart).",
+							Description: "Architecture is the architecture the defaults are chosen for",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"configuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configuration is the effective configuration of the cluster",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtConfiguration"),
+						},
+					},
 				},
+				Required: []string{"machineType", "architecture", "configuration"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.KubeVirtConfiguration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_ContainerDiskSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConfigMapVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents a docker image with an embedded disk.",
+				Description: "ConfigMapVolumeSource adapts a ConfigMap into a volume. More info: https://kubernetes.io/docs/concepts/storage/volumes/#configmap",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"image": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Image is the name of the image with the embedded disk.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"imagePullSecret": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ImagePullSecret is the name of the Docker registry secret required to pull the image. The secret must already exist.",
+							Description: "Name of the referent. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"path": {
+					"optional": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Path defines the path to disk file in the container",
-							Type:        []string{"string"},
+							Description: "Specify whether the ConfigMap or it's keys must be defined",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"imagePullPolicy": {
+					"volumeLabel": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if :latest tag is specified, or IfNotPresent otherwise. Cannot be updated. More info: https://kubernetes.io/docs/concepts/containers/images#updating-images",
+							Description: "The volume label of the resulting disk inside the VMI. Different bootstrapping mechanisms require different values. Typical values are \"cidata\" (cloud-init), \"config-2\" (cloud-init) or \"OEMDRV\" (kickstart).",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"image"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DHCPOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceFeatureResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Extra DHCP options to use in the interface.",
+				Description: "ConformanceFeatureResult is the result of exercising a single feature",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"bootFileName": {
+					"feature": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified will pass option 67 to interface's DHCP server",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"tftpServerName": {
+					"result": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified will pass option 66 to interface's DHCP server",
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"storageClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The storage class the feature was exercised with, if any",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"ntpServers": {
+					"networkCheckup": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified will pass the configured NTP server to the VM via DHCP option 042.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
+							Description: "The measurements of the NetworkLatency feature",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ConformanceNetworkCheckupResult"),
 						},
 					},
-					"privateOptions": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified will pass extra DHCP options for private use, range: 224-254",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.DHCPPrivateOptions"),
-									},
-								},
-							},
+							Description: "Why the feature failed or was skipped",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 				},
+				Required: []string{"feature", "result"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.DHCPPrivateOptions"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/client-go/api/v1.ConformanceNetworkCheckupResult"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DHCPPrivateOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceNetworkCheckup(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DHCPExtraOptions defines Extra DHCP options for a VM.",
+				Description: "ConformanceNetworkCheckup places two VMIs on a network to measure the latency and the throughput between them",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"option": {
+					"networkName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Option is an Integer value from 224-254 Required.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "The multus network the VMIs are attached to, like an SR-IOV or a bridge network. The VMIs get the addresses 192.168.100.10/24 and 192.168.100.20/24 on it. Defaults to the pod network.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"value": {
+					"sriov": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Value is a String value for the Option provided Required.",
+							Description: "Whether the multus network is of type SR-IOV, otherwise the VMIs are bridged to it",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"sourceNode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The node of the VMI sending the probes. Defaults to any node.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetNode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The node of the probed VMI. Defaults to any node.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The containerDisk image of the VMIs. It has to provide ping, and iperf3 for the throughput to be measured. Defaults to the image of the run.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"maxLatency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The highest average round-trip time for the NetworkLatency feature to pass. Without it the feature passes whenever the probed VMI answers.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
 				},
-				Required: []string{"option", "value"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DataVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceNetworkCheckupResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "ConformanceNetworkCheckupResult are the measurements between the two VMIs of a network checkup",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"minLatency": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name represents the name of the DataVolume in the same namespace",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"avgLatency": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"maxLatency": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"throughputBitsPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The throughput from the source to the target VMI in bits per second, only measured if the image provides iperf3",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"minLatency", "avgLatency", "maxLatency"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DeveloperConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceRun(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DeveloperConfiguration holds developer options",
+				Description: "ConformanceRun exercises a matrix of VM features on the cluster and reports the results, so that hardware and storage vendors can validate their stack against this KubeVirt build",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"featureGates": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"pvcTolerateLessSpaceUpToPercent": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"memoryOvercommit": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"nodeSelectors": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
+							Ref: ref("kubevirt.io/client-go/api/v1.ConformanceRunSpec"),
 						},
 					},
-					"useEmulation": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("kubevirt.io/client-go/api/v1.ConformanceRunStatus"),
 						},
 					},
 				},
+				Required: []string{"spec"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.ConformanceRunSpec", "kubevirt.io/client-go/api/v1.ConformanceRunStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Devices(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceRunList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "ConformanceRunList is a list of ConformanceRuns",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"disks": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Disks describes disks, cdroms, floppy and luns which are connected to the vmi.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Disk"),
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"watchdog": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Watchdog describes a watchdog device which can be added to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Watchdog"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"interfaces": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Interfaces describe network interfaces which are added to the vmi.",
-							Type:        []string{"array"},
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Interface"),
+										Ref: ref("kubevirt.io/client-go/api/v1.ConformanceRun"),
 									},
 								},
 							},
 						},
 					},
-					"inputs": {
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.ConformanceRun"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_ConformanceRunSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"features": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Inputs describe input devices",
+							Description: "Features to exercise, in the given order. Defaults to all known features.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Input"),
+										Type:   []string{"string"},
+										Format: "",
 									},
 								},
 							},
 						},
 					},
-					"autoattachPodInterface": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether to attach a pod network interface. Defaults to true.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"autoattachGraphicsDevice": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether to attach the default graphics device or not. VNC will not be available if set to false. Defaults to true.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"autoattachSerialConsole": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether to attach the default serial console or not. Serial console access will not be available if set to false. Defaults to true.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"autoattachMemBalloon": {
+					"image": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether to attach the Memory balloon device with default period. Period can be adjusted in virt-config. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "The containerDisk image the test VMIs boot from. Defaults to kubevirt/cirros-container-disk-demo.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"rng": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Whether to have random number generator from host",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Rng"),
-						},
-					},
-					"blockMultiQueue": {
+					"sriovNetworkName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether or not to enable virtio multi-queue for block devices",
-							Type:        []string{"boolean"},
+							Description: "The multus network of type SR-IOV the SRIOV feature attaches to. The SRIOV feature is skipped if not set.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"networkInterfaceMultiqueue": {
+					"featureTimeout": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, virtual network interfaces configured with a virtio bus will also enable the vhost multiqueue feature",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "How long a single feature may take before it is reported as failed. Defaults to 5 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
-					"gpus": {
+					"storageClasses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether to attach a GPU device to the vmi.",
+							Description: "The storage classes to check. The Boot, Migrate and Hotplug features are exercised again for every storage class, with a VMI booting from a DataVolume of the storage class the image is imported into.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.GPU"),
+										Type:   []string{"string"},
+										Format: "",
 									},
 								},
 							},
 						},
 					},
-					"qats": {
+					"networkCheckup": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Whether to assign a QAT vf device to the vmi.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.QAT"),
-									},
-								},
-							},
+							Description: "The two VMIs the NetworkLatency feature measures the latency and the throughput between. The NetworkLatency feature is skipped if not set.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ConformanceNetworkCheckup"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.Disk", "kubevirt.io/client-go/api/v1.GPU", "kubevirt.io/client-go/api/v1.Input", "kubevirt.io/client-go/api/v1.Interface", "kubevirt.io/client-go/api/v1.QAT", "kubevirt.io/client-go/api/v1.Rng", "kubevirt.io/client-go/api/v1.Watchdog"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/client-go/api/v1.ConformanceNetworkCheckup"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Disk(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConformanceRunStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "ConformanceRunStatus is the machine-readable report of a ConformanceRun",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the device name",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"disk": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a disk to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.DiskTarget"),
-						},
-					},
-					"lun": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a LUN to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.LunTarget"),
-						},
-					},
-					"floppy": {
+					"kubeVirtVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a floppy to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FloppyTarget"),
+							Description: "The version of KubeVirt the features were exercised against",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"cdrom": {
+					"startTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a cdrom to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.CDRomTarget"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"bootOrder": {
+					"completionTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "BootOrder is an integer value > 0, used to determine ordering of boot devices. Lower values take precedence. Each disk or interface that has a boot order must have a unique value. Disks without a boot order are not tried if a disk with a boot order exists.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"serial": {
+					"results": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Serial provides the ability to specify a serial number for the disk device.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The result of every feature exercised so far",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.ConformanceFeatureResult"),
+									},
+								},
+							},
 						},
 					},
-					"dedicatedIOThread": {
+					"passed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "dedicatedIOThread indicates this disk should have an exclusive IO Thread. Enabling this implies useIOThreads = true. Defaults to false.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Type:   []string{"integer"},
+							Format: "int32",
 						},
 					},
-					"cache": {
+					"failed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Cache specifies which kvm disk cache mode should be used.",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"integer"},
+							Format: "int32",
 						},
 					},
-					"tag": {
+					"skipped": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, disk address and its tag will be provided to the guest via config drive metadata",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"integer"},
+							Format: "int32",
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"passed", "failed", "skipped"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.CDRomTarget", "kubevirt.io/client-go/api/v1.DiskTarget", "kubevirt.io/client-go/api/v1.FloppyTarget", "kubevirt.io/client-go/api/v1.LunTarget"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubevirt.io/client-go/api/v1.ConformanceFeatureResult"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DiskDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ConnectionLimits(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents the target of a volume to mount. Only one of its members may be specified.",
+				Description: "Limits on the connection tracking entries an interface can use. New connections exceeding one of the limits are dropped.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"disk": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a disk to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.DiskTarget"),
-						},
-					},
-					"lun": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a LUN to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.LunTarget"),
-						},
-					},
-					"floppy": {
+					"maxConnections": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a floppy to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FloppyTarget"),
+							Description: "Maximum number of concurrently tracked connections.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"cdrom": {
+					"maxNewConnectionsPerSecond": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Attach a volume as a cdrom to the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.CDRomTarget"),
+							Description: "Maximum number of new connections per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.CDRomTarget", "kubevirt.io/client-go/api/v1.DiskTarget", "kubevirt.io/client-go/api/v1.FloppyTarget", "kubevirt.io/client-go/api/v1.LunTarget"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DiskTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ContainerDiskSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Represents a docker image with an embedded disk.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"bus": {
+					"image": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Bus indicates the type of disk device to emulate. supported values: virtio, sata, scsi.",
+							Description: "Image is the name of the image with the embedded disk.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"readonly": {
+					"imagePullSecret": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ReadOnly. Defaults to false.",
-							Type:        []string{"boolean"},
+							Description: "ImagePullSecret is the name of the Docker registry secret required to pull the image. The secret must already exist.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"pciAddress": {
+					"path": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the virtual disk will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10",
+							Description: "Path defines the path to disk file in the container",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if :latest tag is specified, or IfNotPresent otherwise. Cannot be updated. More info: https://kubernetes.io/docs/concepts/containers/images#updating-images",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"image"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_DomainSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_DHCPOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Extra DHCP options to use in the interface.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"resources": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Resources describes the Compute Resources required by this vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.ResourceRequirements"),
-						},
-					},
-					"cpu": {
-						SchemaProps: spec.SchemaProps{
-							Description: "CPU allow specified the detailed CPU topology inside the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.CPU"),
-						},
-					},
-					"memory": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Memory allow specifying the VMI memory features.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Memory"),
-						},
-					},
-					"machine": {
+					"bootFileName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Machine type.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Machine"),
+							Description: "If specified will pass option 67 to interface's DHCP server",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"firmware": {
+					"tftpServerName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Firmware.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Firmware"),
+							Description: "If specified will pass option 66 to interface's DHCP server",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"clock": {
+					"ntpServers": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Clock sets the clock and timers of the vmi.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Clock"),
+							Description: "If specified will pass the configured NTP server to the VM via DHCP option 042.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
-					"features": {
+					"privateOptions": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Features like acpi, apic, hyperv, smm.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Features"),
+							Description: "If specified will pass extra DHCP options for private use, range: 224-254",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.DHCPPrivateOptions"),
+									},
+								},
+							},
 						},
 					},
-					"devices": {
+					"mtu": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Devices allows adding disks, network interfaces, and others",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Devices"),
+							Description: "If specified will pass the MTU to the VM via DHCP option 26 instead of the MTU of the pod interface. Must not exceed the MTU of the pod interface.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"ioThreadsPolicy": {
+					"routes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Controls whether or not disks will share IOThreads. Omitting IOThreadsPolicy disables use of IOThreads. One of: shared, auto",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "If specified will pass the routes to the VM via DHCP option 121 in addition to the routes of the pod interface.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.DHCPRoute"),
+									},
+								},
+							},
 						},
 					},
-					"chassis": {
+					"searchDomains": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Chassis specifies the chassis info passed to the domain.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Chassis"),
+							Description: "If specified will pass the search domains to the VM via DHCP option 119 instead of the search domains of the pod.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"devices"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.CPU", "kubevirt.io/client-go/api/v1.Chassis", "kubevirt.io/client-go/api/v1.Clock", "kubevirt.io/client-go/api/v1.Devices", "kubevirt.io/client-go/api/v1.Features", "kubevirt.io/client-go/api/v1.Firmware", "kubevirt.io/client-go/api/v1.Machine", "kubevirt.io/client-go/api/v1.Memory", "kubevirt.io/client-go/api/v1.ResourceRequirements"},
+			"kubevirt.io/client-go/api/v1.DHCPPrivateOptions", "kubevirt.io/client-go/api/v1.DHCPRoute"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_EFI(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_DHCPPrivateOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "If set, EFI will be used instead of BIOS.",
+				Description: "DHCPExtraOptions defines Extra DHCP options for a VM.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"secureBoot": {
+					"option": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If set, SecureBoot will be enabled and the OVMF roms will be swapped for SecureBoot-enabled ones. Requires SMM to be enabled. Defaults to true",
-							Type:        []string{"boolean"},
+							Description: "Option is an Integer value from 224-254 Required.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value is a String value for the Option provided Required.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"option", "value"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_EmptyDiskSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_DHCPRoute(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "EmptyDisk represents a temporary disk which shares the vmis lifecycle.",
+				Description: "A static route passed to the VM via DHCP.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"capacity": {
+					"destination": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Capacity of the sparse disk.",
-							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+							Description: "Destination network in CIDR notation, e.g. 10.10.0.0/16",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"gateway": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPv4 address of the gateway, it has to be reachable from the interface",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"capacity"},
+				Required: []string{"destination", "gateway"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_EphemeralVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_DataVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"persistentVolumeClaim": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PersistentVolumeClaimVolumeSource represents a reference to a PersistentVolumeClaim in the same namespace. Directly attached to the vmi via qemu. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#persistentvolumeclaims",
-							Ref:         ref("k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource"),
+							Description: "Name represents the name of the DataVolume in the same namespace",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FeatureAPIC(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_DefaultNetworkPolicyConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "DefaultNetworkPolicyConfiguration holds the options for the network policy restricting the egress traffic of the virt-launcher pods in every namespace running vmis",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"enabled": {
+					"mode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+							Description: "Mode is Create to let virt-controller create and update the network policy, or Validate to only warn about vmis in namespaces without a network policy restricting the egress traffic of the virt-launcher pods",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"blockedCIDRs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BlockedCIDRs are the destinations outside of the cluster the virt-launcher pods can not reach. Defaults to the node metadata service 169.254.169.254/32",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"mode"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DeveloperConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeveloperConfiguration holds developer options",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"featureGates": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"pvcTolerateLessSpaceUpToPercent": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"memoryOvercommit": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"nodeSelectors": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"useEmulation": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DevicePreferences(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DevicePreferences describes the preferred devices of a VirtualMachinePreference",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"preferredAutoattachGraphicsDevice": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach the default graphics device, if the VirtualMachine does not say",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"preferredDiskBus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The bus of disks which don't set one",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"preferredInterfaceModel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The model of interfaces which don't set one",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Devices(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"disks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disks describes disks, cdroms, floppy and luns which are connected to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Disk"),
+									},
+								},
+							},
+						},
+					},
+					"watchdog": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Watchdog describes a watchdog device which can be added to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Watchdog"),
+						},
+					},
+					"interfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interfaces describe network interfaces which are added to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Interface"),
+									},
+								},
+							},
+						},
+					},
+					"inputs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Inputs describe input devices",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Input"),
+									},
+								},
+							},
+						},
+					},
+					"autoattachPodInterface": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach a pod network interface. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"autoattachGraphicsDevice": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach the default graphics device or not. VNC will not be available if set to false. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"autoattachSerialConsole": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach the default serial console or not. Serial console access will not be available if set to false. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"serialConsoleLog": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the output of the serial console is logged continuously, not only while a console is connected. Requires the serial console.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SerialConsoleLog"),
+						},
+					},
+					"autoattachMemBalloon": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach the Memory balloon device with default period. Period can be adjusted in virt-config. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"memBalloon": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Options of the Memory balloon device, which are ignored if it is not attached.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.MemBalloon"),
+						},
+					},
+					"rng": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to have random number generator from host",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Rng"),
+						},
+					},
+					"blockMultiQueue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether or not to enable virtio multi-queue for block devices",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"networkInterfaceMultiqueue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, virtual network interfaces configured with a virtio bus will also enable the vhost multiqueue feature",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"networkInterfaceQueues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of queues of each virtio network interface, if NetworkInterfaceMultiQueue is enabled. Defaults to the number of vCPUs.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"gpus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach a GPU device to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.GPU"),
+									},
+								},
+							},
+						},
+					},
+					"qats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to assign a QAT vf device to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.QAT"),
+									},
+								},
+							},
+						},
+					},
+					"hostDevices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to assign host devices, which are permitted in the cluster config, to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.HostDevice"),
+									},
+								},
+							},
+						},
+					},
+					"tpm": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach an emulated TPM 2.0 device to the vmi, as required by Windows 11.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.TPMDevice"),
+						},
+					},
+					"clientPassthrough": {
+						SchemaProps: spec.SchemaProps{
+							Description: "To configure and access client devices such as redirecting USB",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ClientPassthroughDevices"),
+						},
+					},
+					"filesystems": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Filesystems describes filesystems which are shared with the vmi through virtio-fs.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Filesystem"),
+									},
+								},
+							},
+						},
+					},
+					"sound": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to emulate a sound device.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SoundDevice"),
+						},
+					},
+					"panic": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to attach a pvpanic device, through which the guest reports a kernel panic.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.PanicDevice"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.ClientPassthroughDevices", "kubevirt.io/client-go/api/v1.Disk", "kubevirt.io/client-go/api/v1.Filesystem", "kubevirt.io/client-go/api/v1.GPU", "kubevirt.io/client-go/api/v1.HostDevice", "kubevirt.io/client-go/api/v1.Input", "kubevirt.io/client-go/api/v1.Interface", "kubevirt.io/client-go/api/v1.MemBalloon", "kubevirt.io/client-go/api/v1.PanicDevice", "kubevirt.io/client-go/api/v1.QAT", "kubevirt.io/client-go/api/v1.Rng", "kubevirt.io/client-go/api/v1.SerialConsoleLog", "kubevirt.io/client-go/api/v1.SoundDevice", "kubevirt.io/client-go/api/v1.TPMDevice", "kubevirt.io/client-go/api/v1.Watchdog"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Disk(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the device name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disk": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a disk to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskTarget"),
+						},
+					},
+					"lun": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a LUN to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.LunTarget"),
+						},
+					},
+					"floppy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a floppy to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FloppyTarget"),
+						},
+					},
+					"cdrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a cdrom to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.CDRomTarget"),
+						},
+					},
+					"bootOrder": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BootOrder is an integer value > 0, used to determine ordering of boot devices. Lower values take precedence. Each disk or interface that has a boot order must have a unique value. Disks without a boot order are not tried if a disk with a boot order exists.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"serial": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Serial provides the ability to specify a serial number for the disk device.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dedicatedIOThread": {
+						SchemaProps: spec.SchemaProps{
+							Description: "dedicatedIOThread indicates this disk should have an exclusive IO Thread. Enabling this implies useIOThreads = true. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ioThread": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IOThread is the ID of the IOThread serving this disk. Only used with the manual IOThreadsPolicy.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"cache": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cache specifies which kvm disk cache mode should be used.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, disk address and its tag will be provided to the guest via config drive metadata",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"queues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Queues tunes the virtio queues of the disk. Only supported with the virtio and scsi buses. The queues of scsi disks are set on the virtio-scsi controller they share.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskQueues"),
+						},
+					},
+					"ioTune": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IOTune limits the I/O operations and the throughput of the disk, so that the VMI can not starve the other users of a shared storage.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskIOTune"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.CDRomTarget", "kubevirt.io/client-go/api/v1.DiskIOTune", "kubevirt.io/client-go/api/v1.DiskQueues", "kubevirt.io/client-go/api/v1.DiskTarget", "kubevirt.io/client-go/api/v1.FloppyTarget", "kubevirt.io/client-go/api/v1.LunTarget"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskCompaction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DiskCompaction configures the periodic compaction of the qcow2 overlays of a VirtualMachineInstance. The guest agent is required to trim the unused space of the guest filesystems.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval between two compactions, at least one hour.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"interval"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskCompactionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastCompactionTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time the last compaction finished.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reclaimedBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bytes of the overlays given back to the storage by the last compaction.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"totalReclaimedBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bytes of the overlays given back to the storage by all compactions of the VirtualMachineInstance.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"failureReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tells why the last compaction failed, empty if it succeeded.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents the target of a volume to mount. Only one of its members may be specified.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"disk": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a disk to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskTarget"),
+						},
+					},
+					"lun": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a LUN to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.LunTarget"),
+						},
+					},
+					"floppy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a floppy to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FloppyTarget"),
+						},
+					},
+					"cdrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attach a volume as a cdrom to the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.CDRomTarget"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.CDRomTarget", "kubevirt.io/client-go/api/v1.DiskTarget", "kubevirt.io/client-go/api/v1.FloppyTarget", "kubevirt.io/client-go/api/v1.LunTarget"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskIOTune(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DiskIOTune limits the I/O of a disk. A total limit can not be combined with the read and write limits of the same kind.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"totalBytesSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TotalBytesSec limits the read and write throughput in bytes per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"readBytesSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadBytesSec limits the read throughput in bytes per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"writeBytesSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WriteBytesSec limits the write throughput in bytes per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"totalIopsSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TotalIopsSec limits the read and write operations per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"readIopsSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadIopsSec limits the read operations per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"writeIopsSec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WriteIopsSec limits the write operations per second.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskQueues(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DiskQueues configures the virtio queues of a disk.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"count": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Count is the number of queues. Defaults to the number of vCPUs.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size is the number of descriptors of each queue, a power of 2 between 2 and 1024. Only supported with the virtio bus. Defaults to the hypervisor default.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DiskTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"bus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bus indicates the type of disk device to emulate. supported values: virtio, sata, scsi.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"readonly": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadOnly. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"pciAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the virtual disk will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_DomainSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources describes the Compute Resources required by this vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ResourceRequirements"),
+						},
+					},
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPU allow specified the detailed CPU topology inside the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.CPU"),
+						},
+					},
+					"memory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Memory allow specifying the VMI memory features.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Memory"),
+						},
+					},
+					"machine": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Machine type.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Machine"),
+						},
+					},
+					"firmware": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Firmware.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Firmware"),
+						},
+					},
+					"clock": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Clock sets the clock and timers of the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Clock"),
+						},
+					},
+					"features": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Features like acpi, apic, hyperv, smm.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Features"),
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Devices allows adding disks, network interfaces, and others",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Devices"),
+						},
+					},
+					"ioThreadsPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Controls whether or not disks will share IOThreads. Omitting IOThreadsPolicy disables use of IOThreads. One of: shared, auto, manual",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ioThreads": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IOThreads explicitly defines the IOThreads of the vmi. Disks are assigned to them with their ioThread field. Requires the manual IOThreadsPolicy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.IOThread"),
+									},
+								},
+							},
+						},
+					},
+					"chassis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Chassis specifies the chassis info passed to the domain.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Chassis"),
+						},
+					},
+					"launchSecurity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LaunchSecurity configures the memory encryption of the vmi.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.LaunchSecurity"),
+						},
+					},
+				},
+				Required: []string{"devices"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.CPU", "kubevirt.io/client-go/api/v1.Chassis", "kubevirt.io/client-go/api/v1.Clock", "kubevirt.io/client-go/api/v1.Devices", "kubevirt.io/client-go/api/v1.Features", "kubevirt.io/client-go/api/v1.Firmware", "kubevirt.io/client-go/api/v1.IOThread", "kubevirt.io/client-go/api/v1.LaunchSecurity", "kubevirt.io/client-go/api/v1.Machine", "kubevirt.io/client-go/api/v1.Memory", "kubevirt.io/client-go/api/v1.ResourceRequirements"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_EFI(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "If set, EFI will be used instead of BIOS.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secureBoot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If set, SecureBoot will be enabled and the OVMF roms will be swapped for SecureBoot-enabled ones. Requires SMM to be enabled. Defaults to true",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"enrolledKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If set to false, the guest starts with an empty SecureBoot key database (setup mode) instead of the keys enrolled in the OVMF vars template, which allows to enroll custom keys. Only applies if SecureBoot is enabled. Defaults to true",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"containerDisk": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If set, the OVMF roms are taken from the given container image instead of the ones shipped with virt-launcher. Path is the directory containing the roms and defaults to /disk.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ContainerDiskSource"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.ContainerDiskSource"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_EmptyDiskSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EmptyDisk represents a temporary disk which shares the vmis lifecycle.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"capacity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Capacity of the sparse disk.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+				},
+				Required: []string{"capacity"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_EphemeralVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"persistentVolumeClaim": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PersistentVolumeClaimVolumeSource represents a reference to a PersistentVolumeClaim in the same namespace. Directly attached to the vmi via qemu. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#persistentvolumeclaims",
+							Ref:         ref("k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FeatureAPIC(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"endOfInterrupt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EndOfInterrupt enables the end of interrupt notification in the guest. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FeatureHyperv(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Hyperv specific features.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"relaxed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Relaxed instructs the guest OS to disable watchdog timeouts. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"vapic": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VAPIC improves the paravirtualized handling of interrupts. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"spinlocks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spinlocks allows to configure the spinlock retry attempts.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureSpinlocks"),
+						},
+					},
+					"vpindex": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VPIndex enables the Virtual Processor Index to help windows identifying virtual processors. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"runtime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Runtime improves the time accounting to improve scheduling in the guest. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"synic": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyNIC enables the Synthetic Interrupt Controller. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"synictimer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyNICTimer enables Synthetic Interrupt Controller Timers, reducing CPU load. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"reset": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reset enables Hyperv reboot/reset for the vmi. Requires synic. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"vendorid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VendorID allows setting the hypervisor vendor id. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureVendorID"),
+						},
+					},
+					"frequencies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Frequencies improves the TSC clock source handling for Hyper-V on KVM. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"reenlightenment": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reenlightenment enables the notifications on TSC frequency changes. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"tlbflush": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLBFlush improves performances in overcommited environments. Requires vpindex. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"ipi": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPI improves performances in overcommited environments. Requires vpindex. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"evmcs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EVMCS Speeds up L2 vmexits, but disables other virtualization features. Requires vapic. Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.FeatureSpinlocks", "kubevirt.io/client-go/api/v1.FeatureState", "kubevirt.io/client-go/api/v1.FeatureVendorID"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FeatureSpinlocks(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"spinlocks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Retries indicates the number of retries. Must be a value greater or equal 4096. Defaults to 4096.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FeatureState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents if a feature is enabled or disabled.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FeatureVendorID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"vendorid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VendorID sets the hypervisor vendor id, visible to the vmi. String up to twelve characters.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Features(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"acpi": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ACPI enables/disables ACPI insidejsondata guest. Defaults to enabled.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+					"apic": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureAPIC"),
+						},
+					},
+					"hyperv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Defaults to the machine type setting.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureHyperv"),
+						},
+					},
+					"smm": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SMM enables/disables System Management Mode. TSEG not yet implemented.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.FeatureAPIC", "kubevirt.io/client-go/api/v1.FeatureHyperv", "kubevirt.io/client-go/api/v1.FeatureState"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Filesystem(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Filesystem shares the content of a volume with the vmi, without exposing it as a block device.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the device name, it must match the name of a ConfigMap, Secret or\nPersistentVolumeClaim volume. It is used as the mount tag in the guest.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"virtiofs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Virtiofs is supported",
+							Ref:         ref("kubevirt.io/client-go/api/v1.FilesystemVirtiofs"),
+						},
+					},
+				},
+				Required: []string{"name", "virtiofs"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.FilesystemVirtiofs"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FilesystemOverheadConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FilesystemOverheadConfiguration holds the share of filesystem volumes their filesystem\ntakes. The storage sizes requested for the DataVolume templates of virtual machines on\nfilesystem volumes are increased by it, so that their disk image fits",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"global": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Global is the overhead of the storage classes without their own, in percent\nlike \"5.5\". DataVolume templates without a storage class get it as well",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"storageClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageClass maps the names of storage classes to their overhead, in percent",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FilesystemVirtiofs(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FilesystemVirtiofs shares the filesystem through a virtiofsd process in the virt-launcher pod.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Firmware(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"uuid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UUID reported by the vmi bios. Defaults to a random generated uid.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bootloader": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Settings to control the bootloader that is used.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Bootloader"),
+						},
+					},
+					"serial": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The system-serial-number in SMBIOS",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.Bootloader"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_FloppyTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"readonly": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadOnly. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"tray": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tray indicates if the tray of the device is open or closed. Allowed values are \"open\" and \"closed\". Defaults to closed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_GoldenImageVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"claimName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClaimName is the name of a PersistentVolumeClaim in the same namespace. The claim must have the ReadWriteMany or the ReadOnlyMany access mode.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"claimName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_GPU(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the GPU device as exposed by a device plugin",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deviceName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name", "deviceName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_GuestAgentExecAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GuestAgentExecAction describes a command executed inside the guest through the qemu-guest-agent",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Command is the command line to execute inside the guest, the first element is the executable. It is not run in a shell, so shell instructions like pipes do not work.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_HPETTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"tickPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\", \"merge\", \"discard\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"present": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_HostDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HostDevice represents a PCI device of the host which is passed through to the vmi.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the host device in the vmi",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deviceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeviceName is the resource name of the host device, as exposed by a device plugin. It must be listed in the permittedHostDevices of the cluster config.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "deviceName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_HostDisk(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents a disk created on the cluster level",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path to HostDisk image located on the cluster",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Contains information if disk.img exists or should be created allowed options are 'Disk' and 'DiskOrCreate'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"capacity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Capacity of the sparse disk",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"shared": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Shared indicate whether the path is shared between nodes",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"path", "type"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Hugepages(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Hugepages allow to use hugepages for the VirtualMachineInstance instead of regular memory.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pageSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PageSize specifies the hugepage size, for x86_64 architecture valid values are 1Gi and 2Mi.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_HypervTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"present": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_I6300ESBWatchdog(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "i6300esb watchdog device.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"action": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The action to take. Valid values are poweroff, reset, shutdown, inject-nmi, restart-pod. Defaults to reset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_IOErrorRecovery(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IOErrorRecovery describes how a VirtualMachineInstance which qemu paused because of an I/O error is resumed.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"autoResume": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, virt-handler resumes the guest as soon as a PersistentVolumeClaim of the VirtualMachineInstance was expanded, or after the retry interval in case the error cleared. qemu pauses the guest again if the error persists. If false, the guest stays paused. If unset, the guest is resumed on the next sync of the VirtualMachineInstance.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"retryIntervalSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds between two attempts to resume the guest. Defaults to 60 seconds.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_IOThread(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IOThread defines an IOThread and its pinning.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the IOThread. The IDs of all IOThreads have to form the range 1 to n.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"cpus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPUs pins the IOThread to the host CPUs of the given vCPUs, e.g. \"0-1,3\". Requires dedicatedCpuPlacement. If omitted, the IOThread is not pinned.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_IgnitionSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents an Ignition config source for Fedora CoreOS and RHCOS guests. The config is passed to the guest through the opt/com.coreos/config firmware configuration entry. More info: https://coreos.github.io/ignition/",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef references a k8s secret that contains the Ignition config under the config.ign key.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"configMapRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapRef references a k8s config map that contains the Ignition config under the config.ign key.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data contains the inline Ignition config.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Input(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"bus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bus indicates the bus of input device to emulate. Supported values: virtio, usb.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type indicated the type of input device. Supported values: tablet.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the device name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "name"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InstancetypeMatcher(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InstancetypeMatcher references a VirtualMachineInstancetype in the namespace of the VirtualMachine",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the VirtualMachineInstancetype",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"revisionName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the ControllerRevision holding the copy of the VirtualMachineInstancetype the VirtualMachine uses. It is filled in when the VirtualMachine is started the first time, so that later changes of the VirtualMachineInstancetype don't affect the VirtualMachine.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Interface(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Logical name of the interface as well as a reference to the associated networks. Must match the Name of a Network.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"model": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interface model. One of: e1000, e1000e, ne2k_pci, pcnet, rtl8139, virtio. Defaults to virtio.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bridge": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceBridge"),
+						},
+					},
+					"slirp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSlirp"),
+						},
+					},
+					"masquerade": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMasquerade"),
+						},
+					},
+					"sriov": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSRIOV"),
+						},
+					},
+					"macvtap": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMacvtap"),
+						},
+					},
+					"passt": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfacePasst"),
+						},
+					},
+					"ports": {
+						SchemaProps: spec.SchemaProps{
+							Description: "List of ports to be forwarded to the virtual machine.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Port"),
+									},
+								},
+							},
+						},
+					},
+					"macAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interface MAC address. For example: de:ad:00:00:be:af or DE-AD-00-00-BE-AF.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bootOrder": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BootOrder is an integer value > 0, used to determine ordering of boot devices. Lower values take precedence. Each interface or disk that has a boot order must have a unique value. Interfaces without a boot order are not tried.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"pciAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the virtual network interface will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dhcpOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified the network interface will pass additional DHCP options to the VMI",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DHCPOptions"),
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the virtual network interface address and its tag will be provided to the guest via config drive",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"connectionLimits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, limits the connections the guest can track on the node through this interface. Only supported with the masquerade binding.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.ConnectionLimits"),
+						},
+					},
+					"allowedAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, addresses besides the ones of the interface the guest is allowed to send traffic from, e.g. the virtual addresses of a VRRP instance failing over between clustered guests. Only supported with the bridge and SR-IOV bindings.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.AllowedAddresses"),
+						},
+					},
+					"bandwidth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, limits the traffic the guest receives and sends through this interface. Only supported with the bridge and masquerade bindings.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.InterfaceBandwidth"),
+						},
+					},
+					"persistentIPs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If true, the IP addresses the interface got first are claimed and requested again for every migration target pod and after restarts of the VirtualMachine. Only supported with the bridge binding on multus networks, the IPAM of the network has to honor requested IP addresses. Guests behind the masquerade binding always keep the address of the VM network CIDR.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.AllowedAddresses", "kubevirt.io/client-go/api/v1.ConnectionLimits", "kubevirt.io/client-go/api/v1.DHCPOptions", "kubevirt.io/client-go/api/v1.InterfaceBandwidth", "kubevirt.io/client-go/api/v1.InterfaceBridge", "kubevirt.io/client-go/api/v1.InterfaceMacvtap", "kubevirt.io/client-go/api/v1.InterfaceMasquerade", "kubevirt.io/client-go/api/v1.InterfacePasst", "kubevirt.io/client-go/api/v1.InterfaceSRIOV", "kubevirt.io/client-go/api/v1.InterfaceSlirp", "kubevirt.io/client-go/api/v1.Port"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceBandwidth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Rate limits on the traffic of an interface. Traffic received by the guest above the limit is queued, traffic sent by the guest above the limit is dropped.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits the traffic received by the guest.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.BandwidthLimit"),
+						},
+					},
+					"egress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits the traffic sent by the guest.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.BandwidthLimit"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.BandwidthLimit"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceBindingMethod(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents the method which will be used to connect the interface to the guest. Only one of its members may be specified.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"bridge": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceBridge"),
+						},
+					},
+					"slirp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSlirp"),
+						},
+					},
+					"masquerade": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMasquerade"),
+						},
+					},
+					"sriov": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSRIOV"),
+						},
+					},
+					"macvtap": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMacvtap"),
+						},
+					},
+					"passt": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.InterfacePasst"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.InterfaceBridge", "kubevirt.io/client-go/api/v1.InterfaceMacvtap", "kubevirt.io/client-go/api/v1.InterfaceMasquerade", "kubevirt.io/client-go/api/v1.InterfacePasst", "kubevirt.io/client-go/api/v1.InterfaceSRIOV", "kubevirt.io/client-go/api/v1.InterfaceSlirp"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceBridge(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceIPClaim(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InterfaceIPClaim are the IP addresses claimed by an interface, they are requested from the IPAM of the network for every pod of the VirtualMachineInstance",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the interface",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ipAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPs claimed by the interface",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "ipAddresses"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceMacvtap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceMasquerade(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfacePasst(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceSRIOV(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"failoverStandby": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailoverStandby is the name of a virtio interface with the bridge binding which carries the traffic of the guest while the VF is detached for a live migration. The guest bonds both interfaces with net_failover, they have to use the same MAC address.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_InterfaceSlirp(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KSMConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KSMConfiguration holds the options for managing kernel samepage merging on the nodes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"memoryPressureThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MemoryPressureThreshold is the percentage of used memory of a node above which virt-handler enables KSM on it. Defaults to 80",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KVMTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"present": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirt(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirt represents the object deploying all KubeVirt resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.KubeVirtSpec", "kubevirt.io/client-go/api/v1.KubeVirtStatus"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtCanaryRollout(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtCanaryRollout holds the options for the canary rollout of virt-handler updates",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"verificationPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VerificationPeriod is how long the virt-handler pods of a batch are observed before the next batch of nodes is updated. Defaults to 5m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"failureTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailureTimeout is how long an updated virt-handler pod may stay unhealthy before virt-handler is rolled back to the previous version. Defaults to 10m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtCanaryRolloutStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtCanaryRolloutStatus reports the progress of the canary rollout of virt-handler. A rolled back virt-handler stays on the previous version until the KubeVirt CR targets another version.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"deploymentID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeploymentID identifies the deployment virt-handler is rolled out for",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"updatedNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpdatedNodes is the number of nodes virt-handler may be updated on in the current batch",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"batchStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BatchStartTime is when the current batch of nodes started to be updated",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"unhealthySince": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UnhealthySince is when an updated virt-handler pod became unhealthy",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"deploymentID", "phase", "updatedNodes"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtCertificateRotateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selfSigned": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtSelfSignConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.KubeVirtSelfSignConfiguration"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtCondition represents a condition of a KubeVirt deployment",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastProbeTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtConfiguration holds all kubevirt configurations",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cpuModel": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"cpuRequest": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"developerConfiguration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.DeveloperConfiguration"),
+						},
+					},
+					"emulatedMachines": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"imagePullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"migrations": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.MigrationConfiguration"),
+						},
+					},
+					"machineType": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.NetworkConfiguration"),
+						},
+					},
+					"ovmfPath": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"selinuxLauncherType": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"selinuxLauncherTypes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"smbios": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.SMBiosConfiguration"),
+						},
+					},
+					"supportedGuestAgentVersions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"memBalloonStatsPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"permittedHostDevices": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.PermittedHostDevices"),
+						},
+					},
+					"ksmConfiguration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.KSMConfiguration"),
+						},
+					},
+					"memBalloonFreePageReporting": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"multiQueueConfiguration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.MultiQueueConfiguration"),
+						},
+					},
+					"defaultNetworkPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.DefaultNetworkPolicyConfiguration"),
+						},
+					},
+					"audit": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.AuditConfiguration"),
+						},
+					},
+					"nodeFencing": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.NodeFencingConfiguration"),
+						},
+					},
+					"seccompConfiguration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.SeccompConfiguration"),
+						},
+					},
+					"metrics": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.MetricsConfiguration"),
+						},
+					},
+					"filesystemOverhead": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.FilesystemOverheadConfiguration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.AuditConfiguration", "kubevirt.io/client-go/api/v1.DefaultNetworkPolicyConfiguration", "kubevirt.io/client-go/api/v1.DeveloperConfiguration", "kubevirt.io/client-go/api/v1.FilesystemOverheadConfiguration", "kubevirt.io/client-go/api/v1.KSMConfiguration", "kubevirt.io/client-go/api/v1.MetricsConfiguration", "kubevirt.io/client-go/api/v1.MigrationConfiguration", "kubevirt.io/client-go/api/v1.MultiQueueConfiguration", "kubevirt.io/client-go/api/v1.NetworkConfiguration", "kubevirt.io/client-go/api/v1.NodeFencingConfiguration", "kubevirt.io/client-go/api/v1.PermittedHostDevices", "kubevirt.io/client-go/api/v1.SMBiosConfiguration", "kubevirt.io/client-go/api/v1.SeccompConfiguration"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtList is a list of KubeVirts",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.KubeVirt"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.KubeVirt"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtSelfSignConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"caRotateInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deprecated. Use CA.Duration instead",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"certRotateInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deprecated. Use Server.Duration instead",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"caOverlapInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deprecated. Use CA.RenewBefore instead",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"ca": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CA configuration CA certs are kept in the CA bundle as long as they are valid",
+							Ref:         ref("kubevirt.io/client-go/api/v1.CertConfig"),
+						},
+					},
+					"server": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Server configuration Certs are rotated and discarded",
+							Ref:         ref("kubevirt.io/client-go/api/v1.CertConfig"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/client-go/api/v1.CertConfig"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"imageTag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image tag to use for the continer images installed. Defaults to the same tag as the operator's container image.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imageRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image registry to pull the container images from Defaults to the same registry the operator's container image is pulled from.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ImagePullPolicy to use.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"monitorNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace Prometheus is deployed in Defaults to openshift-monitor",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"monitorAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the Prometheus service account that needs read-access to KubeVirt endpoints Defaults to prometheus-k8s",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"uninstallStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies if kubevirt can be deleted if workloads are still present. This is mainly a precaution to avoid accidental data loss",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certificateRotateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtCertificateRotateStrategy"),
+						},
+					},
+					"configuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "holds kubevirt configurations. same as the virt-configMap",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtConfiguration"),
+						},
+					},
+					"canaryRollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CanaryRollout rolls updates of virt-handler out to one node first and then to doubling batches of nodes, and rolls virt-handler back if the updated pods stay unhealthy. If not set, virt-handler is updated by a regular rolling update.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtCanaryRollout"),
+						},
+					},
+					"workloadUpdateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkloadUpdateStrategy defines how running VMIs are moved to the new virt-launcher version after KubeVirt was updated. If not set, running VMIs keep their outdated virt-launcher pods until they are restarted.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtWorkloadUpdateStrategy"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.KubeVirtCanaryRollout", "kubevirt.io/client-go/api/v1.KubeVirtCertificateRotateStrategy", "kubevirt.io/client-go/api/v1.KubeVirtConfiguration", "kubevirt.io/client-go/api/v1.KubeVirtWorkloadUpdateStrategy"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtStatus represents information pertaining to a KubeVirt deployment.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtCondition"),
+									},
+								},
+							},
+						},
+					},
+					"operatorVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"targetKubeVirtRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"targetKubeVirtVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"targetDeploymentConfig": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"targetDeploymentID": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedKubeVirtRegistry": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedKubeVirtVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedDeploymentConfig": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedDeploymentID": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"canaryRollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CanaryRollout reports the progress of the canary rollout of virt-handler",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtCanaryRolloutStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.KubeVirtCanaryRolloutStatus", "kubevirt.io/client-go/api/v1.KubeVirtCondition"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_KubeVirtWorkloadUpdateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubeVirtWorkloadUpdateStrategy defines how the VMIs running in outdated virt-launcher pods are updated",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"workloadUpdateMethods": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WorkloadUpdateMethods are the methods used to update the outdated VMIs. Live migratable VMIs are migrated if LiveMigrate is listed, all other VMIs are evicted if Evict is listed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"batchEvictionSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BatchEvictionSize is the number of VMIs evicted per batch. Defaults to 10",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"batchEvictionInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BatchEvictionInterval is the time between two batches of evictions. Defaults to 1m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_LaunchSecurity(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LaunchSecurity configures the memory encryption of the vmi.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"sev": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SEV encrypts the memory of the vmi with AMD Secure Encrypted Virtualization. Requires EFI with SecureBoot disabled.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SEV"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.SEV"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_LauncherResources(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LauncherResources are the resources of the virt-launcher pod of a vmi, as they are accounted by the scheduler and against resource quotas",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"requests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Requests are the resources requested by the pod",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"limits": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Limits are the resource limits of the pod",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+					"overhead": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Overhead is the part of the requests which is not requested for the guest",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"requests"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_LifecycleHandler(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LifecycleHandler defines the action taken by a lifecycle hook. Exactly one of the fields must be specified.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"exec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exec runs a command in the compute container of the virt-launcher pod.",
+							Ref:         ref("k8s.io/api/core/v1.ExecAction"),
+						},
+					},
+					"httpGet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPGet sends a http request. The host defaults to localhost, which reaches the sidecars of the virt-launcher pod.",
+							Ref:         ref("k8s.io/api/core/v1.HTTPGetAction"),
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds after which the hook times out. Defaults to 30 seconds.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ExecAction", "k8s.io/api/core/v1.HTTPGetAction"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_LunTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"bus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bus indicates the type of disk device to emulate. supported values: virtio, sata, scsi.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"readonly": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadOnly. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Machine(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "QEMU machine type is the actual chipset of the VirtualMachineInstance.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MachinePreferences(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MachinePreferences describes the preferred machine of a VirtualMachinePreference",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"preferredMachineType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The machine type, if the VirtualMachine does not set one",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MediatedHostDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MediatedHostDevice represents a mediated device type of the hosts which may be passed through to vmis",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"mdevNameSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MDEVNameSelector selects the mediated devices by the name of their type, e.g. \"GRID T4-1Q\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceName is the name under which the devices are exposed as a node resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"externalResourceProvider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternalResourceProvider indicates that the devices are exposed by an external device plugin instead of virt-handler",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"mdevNameSelector", "resourceName"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MemBalloon(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MemBalloon configures the Memory balloon device",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"freePageReporting": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FreePageReporting lets the guest report the memory pages it does not use, so that they are returned to the host. Defaults to the memBalloonFreePageReporting of the cluster config.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Memory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Memory allows specifying the VirtualMachineInstance memory features.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hugepages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hugepages allow to use hugepages for the VirtualMachineInstance instead of regular memory.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Hugepages"),
+						},
+					},
+					"guest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Guest allows to specifying the amount of memory which is visible inside the Guest OS. The Guest must lie between Requests and Limits from the resources section. Defaults to the requested memory in the resources section if not specified.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity", "kubevirt.io/client-go/api/v1.Hugepages"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MemoryInstancetype(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MemoryInstancetype describes the memory of a VirtualMachineInstancetype",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"guest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The amount of memory visible to the guest",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"hugepages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Back the memory of the guest with hugepages",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Hugepages"),
+						},
+					},
+				},
+				Required: []string{"guest"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity", "kubevirt.io/client-go/api/v1.Hugepages"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MetricsConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MetricsConfiguration holds the options for the metrics the KubeVirt components expose",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namingMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamingMode selects the names of the metrics renamed to follow the naming conventions of Prometheus: Legacy exposes them under their deprecated names, Current under their new names and Both under both names, to migrate dashboards and alerts. Defaults to Both",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MigrationConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MigrationConfiguration holds migration options",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"allowAutoConverge": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"bandwidthPerMigration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+						},
+					},
+					"completionTimeoutPerGiB": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"nodeDrainTaintKey": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"parallelOutboundMigrationsPerNode": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"parallelInboundMigrationsPerNode": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"parallelMigrationsPerCluster": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"progressTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"unsafeMigrationOverride": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"retryLimit": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"disableTLS": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"allowAutoConverge", "unsafeMigrationOverride"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MultiQueueConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MultiQueueConfiguration holds the options for sizing the virtio queues of new vmis",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"autoTuning": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoTuning enables multi-queue on the virtio network interfaces and disks of new vmis which don't configure it, with one queue per vCPU",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"maxQueues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxQueues caps the number of queues set by the auto tuning. Defaults to 8",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_MultusNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents the multus cni network.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"networkName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "References to a NetworkAttachmentDefinition CRD object. Format: <networkName>, <namespace>/<networkName>. If namespace is not specified, VMI namespace is assumed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Select the default network and add it to the multus-cni.io/default-network annotation.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"endOfInterrupt": {
+					"ovn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the referenced NetworkAttachmentDefinition is expected to configure an OVN-Kubernetes secondary network with the given topology and VLAN, and the VMI will not start otherwise.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.OVNNetwork"),
+						},
+					},
+				},
+				Required: []string{"networkName"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.OVNNetwork"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_NUMA(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NUMA allows specifying settings for the guest NUMA topology.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"guestMappingPassthrough": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GuestMappingPassthrough creates one guest NUMA node for every host NUMA node the dedicated pCPUs of the VMI are placed on. The vCPUs and the memory of a guest NUMA node never cross the boundaries of its host NUMA node. Requires DedicatedCPUPlacement and hugepages.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.NUMAGuestMappingPassthrough"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.NUMAGuestMappingPassthrough"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_NUMAGuestMappingPassthrough(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NUMAGuestMappingPassthrough instructs KubeVirt to pass the host NUMA topology of the dedicated pCPUs through to the guest.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Network(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Network represents a network type and a resource that should be connected to the vm.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Network name. Must be a DNS_LABEL and unique within the vm. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "EndOfInterrupt enables the end of interrupt notification in the guest. Defaults to false.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Ref: ref("kubevirt.io/client-go/api/v1.PodNetwork"),
+						},
+					},
+					"multus": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.MultusNetwork"),
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.MultusNetwork", "kubevirt.io/client-go/api/v1.PodNetwork"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FeatureHyperv(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_NetworkConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Hyperv specific features.",
+				Description: "NetworkConfiguration holds network options",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"relaxed": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Relaxed instructs the guest OS to disable watchdog timeouts. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
-						},
-					},
-					"vapic": {
-						SchemaProps: spec.SchemaProps{
-							Description: "VAPIC improves the paravirtualized handling of interrupts. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
-						},
-					},
-					"spinlocks": {
+					"defaultNetworkInterface": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Spinlocks allows to configure the spinlock retry attempts.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureSpinlocks"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"vpindex": {
+					"permitSlirpInterface": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VPIndex enables the Virtual Processor Index to help windows identifying virtual processors. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"runtime": {
+					"permitBridgeInterfaceOnPodNetwork": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Runtime improves the time accounting to improve scheduling in the guest. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"synic": {
+					"istioAwareMasquerade": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SyNIC enables the Synthetic Interrupt Controller. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"synictimer": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_NetworkSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents the source resource that will be connected to the vm. Only one of its members may be specified.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SyNICTimer enables Synthetic Interrupt Controller Timers, reducing CPU load. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Ref: ref("kubevirt.io/client-go/api/v1.PodNetwork"),
 						},
 					},
-					"reset": {
+					"multus": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reset enables Hyperv reboot/reset for the vmi. Requires synic. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Ref: ref("kubevirt.io/client-go/api/v1.MultusNetwork"),
 						},
 					},
-					"vendorid": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.MultusNetwork", "kubevirt.io/client-go/api/v1.PodNetwork"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_NodeFencingConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeFencingConfiguration holds the options for fencing the vmis on nodes whose virt-handler stopped sending heartbeats",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"enabled": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VendorID allows setting the hypervisor vendor id. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureVendorID"),
+							Description: "Enabled fences the vmis on nodes which are unresponsive and confirmed to be dead. Their virt-launcher pods are force deleted and they are moved to the Failed phase, so that their VirtualMachines can start them on other nodes. Only enable it if the confirmed nodes are reliably powered off, the guests could otherwise run twice.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"frequencies": {
+					"gracePeriod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Frequencies improves the TSC clock source handling for Hyper-V on KVM. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "GracePeriod is how long a node has to be unresponsive before its vmis are fenced. Defaults to 5m",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
 						},
 					},
-					"reenlightenment": {
+					"confirmation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reenlightenment enables the notifications on TSC frequency changes. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "Confirmation holds the rules confirming that an unresponsive node is dead before its vmis are fenced. Any of the rules confirms a node. Defaults to the node not being ready",
+							Ref:         ref("kubevirt.io/client-go/api/v1.NodeFencingConfirmation"),
 						},
 					},
-					"tlbflush": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "kubevirt.io/client-go/api/v1.NodeFencingConfirmation"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_NodeFencingConfirmation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeFencingConfirmation holds the rules confirming that an unresponsive node is dead, so that the guests of its vmis can't run twice",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"notReady": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TLBFlush improves performances in overcommited environments. Requires vpindex. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "NotReady confirms nodes whose kubelet is not ready either, once the grace period passed. Defaults to true if no other rule is set",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"ipi": {
+					"outOfServiceTaint": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IPI improves performances in overcommited environments. Requires vpindex. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "OutOfServiceTaint confirms nodes tainted with node.kubernetes.io/out-of-service, which marks nodes known to be shut down. The grace period does not apply",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"evmcs": {
+					"resources": {
 						SchemaProps: spec.SchemaProps{
-							Description: "EVMCS Speeds up L2 vmexits, but disables other virtualization features. Requires vapic. Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "Resources confirm nodes for which an object of one of the resources exists that is named after the node, like the remediation objects of fencing operators. virt-controller has to be allowed to list them. The grace period does not apply",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.NodeFencingResource"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.FeatureSpinlocks", "kubevirt.io/client-go/api/v1.FeatureState", "kubevirt.io/client-go/api/v1.FeatureVendorID"},
+			"kubevirt.io/client-go/api/v1.NodeFencingResource"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FeatureSpinlocks(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_NodeFencingResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "NodeFencingResource is a resource whose objects are named after the nodes they confirm as dead",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"enabled": {
+					"group": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "Group of the resource, empty for the core API group",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"spinlocks": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Retries indicates the number of retries. Must be a value greater or equal 4096. Defaults to 4096.",
-							Type:        []string{"integer"},
-							Format:      "int64",
+							Description: "Version of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resource is the plural name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"version", "resource"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FeatureState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_OVNNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents if a feature is enabled or disabled.",
+				Description: "Represents the binding of an OVN-Kubernetes secondary network.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"enabled": {
+					"topology": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "Topology of the OVN-Kubernetes network. One of localnet, layer2.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"vlan": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VLAN ID the traffic of the network is tagged with on the physical network. Only supported with the localnet topology.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
+				Required: []string{"topology"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FeatureVendorID(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_PITTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"enabled": {
+					"tickPolicy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\", \"discard\".",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"vendorid": {
+					"present": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VendorID sets the hypervisor vendor id, visible to the vmi. String up to twelve characters.",
-							Type:        []string{"string"},
+							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
@@ -14730,94 +18229,110 @@ func schema_kubevirtio_client_go_api_v1_FeatureVendorID(ref common.ReferenceCall
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Features(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_PanicDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "PanicDevice lets the guest report a kernel panic, like a Linux panic or a Windows bug check, to the hypervisor. The VMI fails once its guest panicked.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"acpi": {
+					"crashDumpClaimName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ACPI enables/disables ACPI insidejsondata guest. Defaults to enabled.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "If specified, the memory of a guest which panicked is dumped to this PersistentVolumeClaim before the VMI fails, for offline analysis. The claim must be large enough for the guest memory.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"apic": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_PciHostDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PciHostDevice represents a PCI device of the hosts which may be passed through to vmis",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pciVendorSelector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureAPIC"),
+							Description: "PCIVendorSelector selects the devices by their vendor and device ID, e.g. \"10de:1eb8\"",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"hyperv": {
+					"resourceName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defaults to the machine type setting.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureHyperv"),
+							Description: "ResourceName is the name under which the devices are exposed as a node resource",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"smm": {
+					"externalResourceProvider": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SMM enables/disables System Management Mode. TSEG not yet implemented.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.FeatureState"),
+							Description: "ExternalResourceProvider indicates that the devices are exposed by an external device plugin instead of virt-handler",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"pciVendorSelector", "resourceName"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.FeatureAPIC", "kubevirt.io/client-go/api/v1.FeatureHyperv", "kubevirt.io/client-go/api/v1.FeatureState"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Firmware(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_PermittedHostDevices(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "PermittedHostDevices holds the host devices which may be passed through to vmis",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"uuid": {
-						SchemaProps: spec.SchemaProps{
-							Description: "UUID reported by the vmi bios. Defaults to a random generated uid.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"bootloader": {
+					"pciHostDevices": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Settings to control the bootloader that is used.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Bootloader"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.PciHostDevice"),
+									},
+								},
+							},
 						},
 					},
-					"serial": {
+					"mediatedDevices": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The system-serial-number in SMBIOS",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.MediatedHostDevice"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.Bootloader"},
+			"kubevirt.io/client-go/api/v1.MediatedHostDevice", "kubevirt.io/client-go/api/v1.PciHostDevice"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_FloppyTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_PodNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Represents the stock pod network interface.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"readonly": {
-						SchemaProps: spec.SchemaProps{
-							Description: "ReadOnly. Defaults to false.",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"tray": {
+					"vmNetworkCIDR": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Tray indicates if the tray of the device is open or closed. Allowed values are \"open\" and \"closed\". Defaults to closed.",
+							Description: "CIDR for vm network. Default 10.0.2.0/24 if not specified.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -14828,127 +18343,176 @@ func schema_kubevirtio_client_go_api_v1_FloppyTarget(ref common.ReferenceCallbac
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_GPU(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_Port(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Port repesents a port to expose from the virtual machine. Default protocol TCP. The port field is mandatory",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the GPU device as exposed by a device plugin",
+							Description: "If specified, this must be an IANA_SVC_NAME and unique within the pod. Each named port in a pod must have a unique name. Name for the port that can be referred to by services.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"deviceName": {
+					"protocol": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Protocol for port. Must be UDP or TCP. Defaults to \"TCP\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of port to expose for the virtual machine. This must be a valid port number, 0 < x < 65536.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"name", "deviceName"},
+				Required: []string{"port"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_HPETTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_PreferenceMatcher(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "PreferenceMatcher references a VirtualMachinePreference in the namespace of the VirtualMachine",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"tickPolicy": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\", \"merge\", \"discard\".",
+							Description: "The name of the VirtualMachinePreference",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"present": {
+					"revisionName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "The name of the ControllerRevision holding the copy of the VirtualMachinePreference the VirtualMachine uses. It is filled in when the VirtualMachine is started the first time, so that later changes of the VirtualMachinePreference don't affect the VirtualMachine.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_HostDisk(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_Probe(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents a disk created on the cluster level",
+				Description: "Probe describes a health check to be performed against a VirtualMachineInstance to determine whether it is alive or ready to receive traffic.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"path": {
+					"httpGet": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The path to HostDisk image located on the cluster",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "HTTPGet specifies the http request to perform.",
+							Ref:         ref("k8s.io/api/core/v1.HTTPGetAction"),
 						},
 					},
-					"type": {
+					"tcpSocket": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Contains information if disk.img exists or should be created allowed options are 'Disk' and 'DiskOrCreate'",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
+							Ref:         ref("k8s.io/api/core/v1.TCPSocketAction"),
 						},
 					},
-					"capacity": {
+					"guestAgentExec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Capacity of the sparse disk",
-							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+							Description: "GuestAgentExec specifies a command to run inside the guest through the qemu-guest-agent. The probe succeeds if the command exits with status 0.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.GuestAgentExecAction"),
 						},
 					},
-					"shared": {
+					"initialDelaySeconds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Shared indicate whether the path is shared between nodes",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "Number of seconds after the VirtualMachineInstance has started before liveness probes are initiated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds after which the probe times out. Defaults to 1 second. Minimum value is 1. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"periodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often (in seconds) to perform the probe. Default to 10 seconds. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"successThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minimum consecutive successes for the probe to be considered successful after having failed. Defaults to 1. Must be 1 for liveness. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failureThreshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minimum consecutive failures for the probe to be considered failed after having succeeded. Defaults to 3. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
-				Required: []string{"path", "type"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+			"k8s.io/api/core/v1.HTTPGetAction", "k8s.io/api/core/v1.TCPSocketAction", "kubevirt.io/client-go/api/v1.GuestAgentExecAction"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Hugepages(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_QAT(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Hugepages allow to use hugepages for the VirtualMachineInstance instead of regular memory.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"pageSize": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "PageSize specifies the hugepage size, for x86_64 architecture valid values are 1Gi and 2Mi.",
+							Description: "Name of the QAT device as exposed by a device plugin",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"deviceName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
 				},
+				Required: []string{"name", "deviceName"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_HypervTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_RTCTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"tickPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"present": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
@@ -14956,22 +18520,9 @@ func schema_kubevirtio_client_go_api_v1_HypervTimer(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_I6300ESBWatchdog(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "i6300esb watchdog device.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"action": {
+					"track": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The action to take. Valid values are poweroff, reset, shutdown. Defaults to reset.",
+							Description: "Track the guest or the wall clock.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -14982,220 +18533,158 @@ func schema_kubevirtio_client_go_api_v1_I6300ESBWatchdog(ref common.ReferenceCal
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Input(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_Realtime(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Realtime configures the realtime scheduling of the vCPUs.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"bus": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Bus indicates the bus of input device to emulate. Supported values: virtio, usb.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"type": {
+					"mask": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type indicated the type of input device. Supported values: tablet.",
+							Description: "Mask selects the realtime vCPUs, e.g. \"0-1,3\". Defaults to all vCPUs.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"priority": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the device name",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Priority of the realtime vCPUs, between 1 and 99. Defaults to 1",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"type", "name"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Interface(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ResourceRequirements(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Logical name of the interface as well as a reference to the associated networks. Must match the Name of a Network.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"model": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Interface model. One of: e1000, e1000e, ne2k_pci, pcnet, rtl8139, virtio. Defaults to virtio.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"bridge": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceBridge"),
-						},
-					},
-					"slirp": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSlirp"),
-						},
-					},
-					"masquerade": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMasquerade"),
-						},
-					},
-					"sriov": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSRIOV"),
-						},
-					},
-					"ports": {
+					"requests": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of ports to be forwarded to the virtual machine.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
+							Description: "Requests is a description of the initial vmi resources. Valid resource keys are \"memory\" and \"cpu\".",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Port"),
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
 									},
 								},
 							},
 						},
 					},
-					"macAddress": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Interface MAC address. For example: de:ad:00:00:be:af or DE-AD-00-00-BE-AF.",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"bootOrder": {
-						SchemaProps: spec.SchemaProps{
-							Description: "BootOrder is an integer value > 0, used to determine ordering of boot devices. Lower values take precedence. Each interface or disk that has a boot order must have a unique value. Interfaces without a boot order are not tried.",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"pciAddress": {
-						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the virtual network interface will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"dhcpOptions": {
+					"limits": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified the network interface will pass additional DHCP options to the VMI",
-							Ref:         ref("kubevirt.io/client-go/api/v1.DHCPOptions"),
+							Description: "Limits describes the maximum amount of compute resources allowed. Valid resource keys are \"memory\" and \"cpu\".",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
 						},
 					},
-					"tag": {
+					"overcommitGuestOverhead": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the virtual network interface address and its tag will be provided to the guest via config drive",
-							Type:        []string{"string"},
+							Description: "Don't ask the scheduler to take the guest-management overhead into account. Instead put the overhead only into the container's memory limit. This can lead to crashes if all memory is in use on a node. Defaults to false.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.DHCPOptions", "kubevirt.io/client-go/api/v1.InterfaceBridge", "kubevirt.io/client-go/api/v1.InterfaceMasquerade", "kubevirt.io/client-go/api/v1.InterfaceSRIOV", "kubevirt.io/client-go/api/v1.InterfaceSlirp", "kubevirt.io/client-go/api/v1.Port"},
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_InterfaceBindingMethod(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_RestartOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents the method which will be used to connect the interface to the guest. Only one of its members may be specified.",
+				Description: "RestartOptions may be provided when deleting an API object.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"bridge": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceBridge"),
-						},
-					},
-					"slirp": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSlirp"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"masquerade": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceMasquerade"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"sriov": {
+					"gracePeriodSeconds": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.InterfaceSRIOV"),
+							Description: "The duration in seconds before the object should be force-restared. Value must be non-negative integer. The value zero indicates, restart immediately. If this value is nil, the default grace period for deletion of the corresponding VMI for the specified type will be used to determine on how much time to give the VMI to restart. Defaults to a per object value if not specified. zero means restart immediately. Allowed Values: nil and 0",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.InterfaceBridge", "kubevirt.io/client-go/api/v1.InterfaceMasquerade", "kubevirt.io/client-go/api/v1.InterfaceSRIOV", "kubevirt.io/client-go/api/v1.InterfaceSlirp"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_InterfaceBridge(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-			},
-		},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_InterfaceMasquerade(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-			},
-		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_InterfaceSRIOV(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_Rng(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "Rng represents the random device passed from host",
+				Type:        []string{"object"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_InterfaceSlirp(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_SEV(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "SEV configures AMD Secure Encrypted Virtualization.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policy the guest owner requests from the platform.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SEVPolicy"),
+						},
+					},
+				},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.SEVPolicy"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KVMTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_SEVPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "SEVPolicy defines the guarantees the guest owner requests from the platform.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"present": {
+					"encryptedState": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+							Description: "EncryptedState additionally encrypts the CPU register state of the guest (SEV-ES). Defaults to false",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
@@ -15206,228 +18695,197 @@ func schema_kubevirtio_client_go_api_v1_KVMTimer(ref common.ReferenceCallback) c
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirt(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_SMBiosConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubeVirt represents the object deploying all KubeVirt resources",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"manufacturer": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"apiVersion": {
+					"product": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"metadata": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"spec": {
+					"sku": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtSpec"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"status": {
+					"family": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtStatus"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
-				Required: []string{"spec"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.KubeVirtSpec", "kubevirt.io/client-go/api/v1.KubeVirtStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtCertificateRotateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_SeccompConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "SeccompConfiguration selects the seccomp profile the virt-launcher pods run with",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"selfSigned": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtSelfSignConfiguration"),
+							Description: "Type is RuntimeDefault for the default profile of the container runtime, KubeVirt for the kubevirt/default profile tailored to qemu, which virt-handler installs on every node, or Custom for a profile the admin installed on the nodes. The virt-launcher pods run with the profile the container runtime picks for pods without one if no type is set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"customProfile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CustomProfile is the path of the custom profile, relative to the seccomp directory of the kubelet. Required with the Custom type",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.KubeVirtSelfSignConfiguration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_SecretVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubeVirtCondition represents a condition of a KubeVirt deployment",
+				Description: "SecretVolumeSource adapts a Secret into a volume.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"secretName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Name of the secret in the pod's namespace to use. More info: https://kubernetes.io/docs/concepts/storage/volumes#secret",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"optional": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Specify whether the Secret or it's keys must be defined",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"lastProbeTime": {
+					"volumeLabel": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "The volume label of the resulting disk inside the VMI. Different bootstrapping mechanisms require different values. Typical values are \"cidata\" (cloud-init), \"config-2\" (cloud-init) or \"OEMDRV\" (kickstart).",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastTransitionTime": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_SerialConsoleLog(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SerialConsoleLog configures where the serial console output is logged to and how the log files are rotated.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"volumeName": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Name of a persistentVolumeClaim volume of the vmi the log files are stored on. The volume must not be used by a disk and must have filesystem volume mode. If empty, the output is written to the virt-launcher pod logs.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"reason": {
+					"maxSize": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Size a log file may grow to before it is rotated. Defaults to 2Mi.",
+							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
 						},
 					},
-					"message": {
+					"maxFiles": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Number of rotated log files which are kept in addition to the current one. Defaults to 3.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"type", "status"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_ServiceAccountVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubeVirtConfiguration holds all kubevirt configurations",
+				Description: "ServiceAccountVolumeSource adapts a ServiceAccount into a volume.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"cpuModel": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"cpuRequest": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"developerConfiguration": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.DeveloperConfiguration"),
-						},
-					},
-					"emulatedMachines": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
-						},
-					},
-					"imagePullPolicy": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"migrations": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.MigrationConfiguration"),
-						},
-					},
-					"machineType": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"network": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.NetworkConfiguration"),
-						},
-					},
-					"ovmfPath": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"selinuxLauncherType": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"smbios": {
+					"serviceAccountName": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.SMBiosConfiguration"),
+							Description: "Name of the service account in the pod's namespace to use. More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"supportedGuestAgentVersions": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_SoundDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SoundDevice represents the user's configuration to emulate a sound card in the vmi.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
+							Description: "User's defined name for this sound device",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"memBalloonStatsPeriod": {
+					"model": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int32",
+							Description: "Model of the sound card, either ich9 or ac97.\nDefaults to ich9.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.DeveloperConfiguration", "kubevirt.io/client-go/api/v1.MigrationConfiguration", "kubevirt.io/client-go/api/v1.NetworkConfiguration", "kubevirt.io/client-go/api/v1.SMBiosConfiguration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_StopOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubeVirtList is a list of KubeVirts",
+				Description: "StopOptions may be provided when stopping a VirtualMachine.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -15444,798 +18902,1073 @@ func schema_kubevirtio_client_go_api_v1_KubeVirtList(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"gracePeriodSeconds": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "The duration in seconds before the VirtualMachineInstance is force-stopped. Value must be non-negative integer. The value zero indicates, stop immediately. If this value is nil, the termination grace period of the VirtualMachineInstance is used. Allowed Values: nil and 0",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"items": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_SysprepSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents a Sysprep answer file source for the unattended installation of Windows guests. The answer file is added as a CDROM to the vmi, where Windows Setup looks for it. More info: https://docs.microsoft.com/en-us/windows-hardware/manufacture/desktop/windows-setup-automation-overview",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secret": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.KubeVirt"),
-									},
-								},
-							},
+							Description: "Secret references a k8s secret that contains the answer file under the autounattend.xml or unattend.xml key.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMap references a k8s config map that contains the answer file under the autounattend.xml or unattend.xml key.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.KubeVirt"},
+			"k8s.io/api/core/v1.LocalObjectReference"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtSelfSignConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_TPMDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "TPMDevice represents a TPM 2.0 device, which is emulated by swtpm in the virt-launcher pod.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"caRotateInterval": {
+					"persistentStateClaimName": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "PersistentStateClaimName references a PersistentVolumeClaim in the same namespace, which keeps the state of the TPM across restarts of the vmi. The claim must not be used by a volume. If not set, the state of the TPM is lost when the vmi stops.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"certRotateInterval": {
+				},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_Timer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents all available timers in a vmi.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hpet": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "HPET (High Precision Event Timer) - multiple timers with periodic interrupts.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.HPETTimer"),
 						},
 					},
-					"caOverlapInterval": {
+					"kvm": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+							Description: "KVM \t(KVM clock) - lets guests read the host’s wall clock time (paravirtualized). For linux guests.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.KVMTimer"),
+						},
+					},
+					"pit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PIT (Programmable Interval Timer) - a timer with periodic interrupts.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.PITTimer"),
+						},
+					},
+					"rtc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RTC (Real Time Clock) - a continuously running timer with periodic interrupts.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.RTCTimer"),
+						},
+					},
+					"hyperv": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hyperv (Hypervclock) - lets guests read the host’s wall clock time (paravirtualized). For windows guests.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.HypervTimer"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+			"kubevirt.io/client-go/api/v1.HPETTimer", "kubevirt.io/client-go/api/v1.HypervTimer", "kubevirt.io/client-go/api/v1.KVMTimer", "kubevirt.io/client-go/api/v1.PITTimer", "kubevirt.io/client-go/api/v1.RTCTimer"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VCPUPin(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VCPUPin is the placement of a vCPU on the pCPUs of the node",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"imageTag": {
+					"vcpu": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The image tag to use for the continer images installed. Defaults to the same tag as the operator's container image.",
+							Description: "VCPU is the index of the vCPU",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"cpuset": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPUSet lists the pCPUs the vCPU runs on",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imageRegistry": {
+				},
+				Required: []string{"vcpu", "cpuset"},
+			},
+		},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VNCToken(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VNCToken grants the user who requested it a single connection to the VNC of a VirtualMachineInstance",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The image registry to pull the container images from Defaults to the same registry the operator's container image is pulled from.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"imagePullPolicy": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The ImagePullPolicy to use.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"monitorNamespace": {
+					"token": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The namespace Prometheus is deployed in Defaults to openshift-monitor",
+							Description: "Token to pass in the token query parameter of the vnc subresource",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"monitorAccount": {
+					"expirationTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The name of the Prometheus service account that needs read-access to KubeVirt endpoints Defaults to prometheus-k8s",
+							Description: "Time after which the token is not accepted anymore",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"token", "expirationTimestamp"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachine(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachine handles the VirtualMachines that are not running or are in a stopped state The VirtualMachine contains the template to create the VirtualMachineInstance. It also mirrors the running state of the created VirtualMachineInstance in its status.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"uninstallStrategy": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies if kubevirt can be deleted if workloads are still present. This is mainly a precaution to avoid accidental data loss",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"certificateRotateStrategy": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtCertificateRotateStrategy"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"configuration": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "holds kubevirt configurations. same as the virt-configMap",
-							Ref:         ref("kubevirt.io/client-go/api/v1.KubeVirtConfiguration"),
+							Description: "Spec contains the specification of VirtualMachineInstance created",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status holds the current state of the controller and brief information about its associated VirtualMachineInstance",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineStatus"),
 						},
 					},
 				},
+				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.KubeVirtCertificateRotateStrategy", "kubevirt.io/client-go/api/v1.KubeVirtConfiguration"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineSpec", "kubevirt.io/client-go/api/v1.VirtualMachineStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_KubeVirtStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "KubeVirtStatus represents information pertaining to a KubeVirt deployment.",
+				Description: "VirtualMachineBackup starts a pull mode backup of the disks of a running VirtualMachineInstance. While the backup is ready, the disks are exported over NBD through the backup subresource of the VirtualMachineInstance. Every backup records a checkpoint, which later backups can reference to only export the blocks which changed since then. Deleting the backup ends the export.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"phase": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"conditions": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.KubeVirtCondition"),
-									},
-								},
-							},
-						},
-					},
-					"operatorVersion": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"targetKubeVirtRegistry": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"targetKubeVirtVersion": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"targetDeploymentConfig": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineBackupSpec"),
 						},
 					},
-					"targetDeploymentID": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineBackupStatus"),
 						},
 					},
-					"observedKubeVirtRegistry": {
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineBackupSpec", "kubevirt.io/client-go/api/v1.VirtualMachineBackupStatus"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineBackupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineBackupList is a list of VirtualMachineBackups",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"observedKubeVirtVersion": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"observedDeploymentConfig": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"observedDeploymentID": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineBackup"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.KubeVirtCondition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineBackup"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_LunTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"bus": {
+					"source": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Bus indicates the type of disk device to emulate. supported values: virtio, sata, scsi.",
+							Description: "The name of the VirtualMachineInstance to back up. It has to live in the namespace of the backup.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"readonly": {
+					"incremental": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ReadOnly. Defaults to false.",
-							Type:        []string{"boolean"},
+							Description: "The name of an earlier backup of the same VirtualMachineInstance. If set, the exported disks carry a dirty bitmap with the blocks which changed since the checkpoint of that backup.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"source"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Machine(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineBackupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineBackupStatus describes where the backed up disks can be read from",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "QEMU machine type is the actual chipset of the VirtualMachineInstance.",
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the backup is not ready",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"checkpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The checkpoint recorded by this backup, which later backups can be incremental to",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"volumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The disks exported by the backup",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineBackupVolume"),
+									},
+								},
+							},
+						},
+					},
 				},
-				Required: []string{"type"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.VirtualMachineBackupVolume"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Memory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineBackupVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Memory allows specifying the VirtualMachineInstance memory features.",
+				Description: "VirtualMachineBackupVolume describes how a single disk is exported over NBD",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"hugepages": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Hugepages allow to use hugepages for the VirtualMachineInstance instead of regular memory.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Hugepages"),
+							Description: "The name of the volume in the VirtualMachineInstance",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"guest": {
+					"exportName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Guest allows to specifying the amount of memory which is visible inside the Guest OS. The Guest must lie between Requests and Limits from the resources section. Defaults to the requested memory in the resources section if not specified.",
-							Ref:         ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+							Description: "The NBD export name of the disk",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dirtyBitmap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the dirty bitmap of an incremental backup. It is exposed in the qemu:dirty-bitmap:<name> NBD metadata context.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name", "exportName"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/api/resource.Quantity", "kubevirt.io/client-go/api/v1.Hugepages"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_MigrationConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "MigrationConfiguration holds migration options",
+				Description: "VirtualMachineCondition represents the state of VirtualMachine",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"allowAutoConverge": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"bandwidthPerMigration": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
-						},
-					},
-					"completionTimeoutPerGiB": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"nodeDrainTaintKey": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"parallelOutboundMigrationsPerNode": {
+					"lastProbeTime": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"parallelMigrationsPerCluster": {
+					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"progressTimeout": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"unsafeMigrationOverride": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
 				},
-				Required: []string{"allowAutoConverge", "unsafeMigrationOverride"},
+				Required: []string{"type", "status"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_MultusNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExport(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents the multus cni network.",
+				Description: "VirtualMachineExport exposes the volumes of a stopped VirtualMachine over an authenticated HTTPS endpoint, so that they can be downloaded or imported into another namespace or cluster",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"networkName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "References to a NetworkAttachmentDefinition CRD object. Format: <networkName>, <namespace>/<networkName>. If namespace is not specified, VMI namespace is assumed.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"default": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Select the default network and add it to the multus-cni.io/default-network annotation.",
-							Type:        []string{"boolean"},
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineExportSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineExportStatus"),
+						},
+					},
 				},
-				Required: []string{"networkName"},
+				Required: []string{"spec"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineExportSpec", "kubevirt.io/client-go/api/v1.VirtualMachineExportStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Network(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExportList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Network represents a network type and a resource that should be connected to the vm.",
+				Description: "VirtualMachineExportList is a list of VirtualMachineExports",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Network name. Must be a DNS_LABEL and unique within the vm. More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#names",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"pod": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.PodNetwork"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"multus": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.MultusNetwork"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineExport"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.MultusNetwork", "kubevirt.io/client-go/api/v1.PodNetwork"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineExport"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_NetworkConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExportSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NetworkConfiguration holds network options",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"defaultNetworkInterface": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"permitSlirpInterface": {
+					"source": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "The name of the VirtualMachine to export. It has to live in the namespace of the export.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"permitBridgeInterfaceOnPodNetwork": {
+					"tokenSecretRef": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "The name of a Secret in the namespace of the export. Clients have to present the value of its \"token\" key in the x-kubevirt-export-token header, or as basic auth password.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"source", "tokenSecretRef"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_NetworkSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExportStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents the source resource that will be connected to the vm. Only one of its members may be specified.",
+				Description: "VirtualMachineExportStatus contains the links to the exported volumes",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"pod": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.PodNetwork"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"multus": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.MultusNetwork"),
+							Description: "Why the export is not ready",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The service the volumes are served from",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"cert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The PEM encoded CA certificate the certificate of the export server is signed with",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"manifest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Link to a VirtualMachine manifest, which imports the exported volumes through DataVolumes. The DataVolumes expect the token as secretKey in a Secret named like the tokenSecretRef, and Cert in a ConfigMap named like the export.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"volumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The links to the exported volumes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineExportVolume"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.MultusNetwork", "kubevirt.io/client-go/api/v1.PodNetwork"},
+			"kubevirt.io/client-go/api/v1.VirtualMachineExportVolume"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_PITTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExportVolume(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineExportVolume contains the links to a single exported volume",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"tickPolicy": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\", \"discard\".",
+							Description: "The name of the volume in the VirtualMachine",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"present": {
+					"formats": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineExportVolumeFormat"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.VirtualMachineExportVolumeFormat"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_PodNetwork(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineExportVolumeFormat(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents the stock pod network interface.",
+				Description: "VirtualMachineExportVolumeFormat is the link to a volume in a specific format",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"vmNetworkCIDR": {
+					"format": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CIDR for vm network. Default 10.0.2.0/24 if not specified.",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
+				Required: []string{"format", "url"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Port(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstance(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Port repesents a port to expose from the virtual machine. Default protocol TCP. The port field is mandatory",
+				Description: "VirtualMachineInstance is *the* VirtualMachineInstance Definition. It represents a virtual machine in the runtime environment of kubernetes.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, this must be an IANA_SVC_NAME and unique within the pod. Each named port in a pod must have a unique name. Name for the port that can be referred to by services.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"protocol": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Protocol for port. Must be UDP or TCP. Defaults to \"TCP\".",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"port": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of port to expose for the virtual machine. This must be a valid port number, 0 < x < 65536.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the high level overview of how the VirtualMachineInstance is doing. It contains information available to controllers and users.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceStatus"),
 						},
 					},
 				},
-				Required: []string{"port"},
+				Required: []string{"spec"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Probe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Probe describes a health check to be performed against a VirtualMachineInstance to determine whether it is alive or ready to receive traffic.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"httpGet": {
-						SchemaProps: spec.SchemaProps{
-							Description: "HTTPGet specifies the http request to perform.",
-							Ref:         ref("k8s.io/api/core/v1.HTTPGetAction"),
-						},
-					},
-					"tcpSocket": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TCPSocket specifies an action involving a TCP port. TCP hooks not yet supported",
-							Ref:         ref("k8s.io/api/core/v1.TCPSocketAction"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"initialDelaySeconds": {
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of seconds after the VirtualMachineInstance has started before liveness probes are initiated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"timeoutSeconds": {
+					"lastProbeTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of seconds after which the probe times out. Defaults to 1 second. Minimum value is 1. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"periodSeconds": {
+					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "How often (in seconds) to perform the probe. Default to 10 seconds. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"successThreshold": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum consecutive successes for the probe to be considered successful after having failed. Defaults to 1. Must be 1 for liveness. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"failureThreshold": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum consecutive failures for the probe to be considered failed after having succeeded. Defaults to 3. Minimum value is 1.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
+				Required: []string{"type", "status"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.HTTPGetAction", "k8s.io/api/core/v1.TCPSocketAction"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_QAT(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystem(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineInstanceFileSystem represents guest os disk",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"diskName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the QAT device as exposed by a device plugin",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"deviceName": {
+					"mountPoint": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"fileSystemType": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
+					"usedBytes": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"totalBytes": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
 				},
-				Required: []string{"name", "deviceName"},
+				Required: []string{"diskName", "mountPoint", "fileSystemType", "usedBytes", "totalBytes"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_RTCTimer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystemInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineInstanceFileSystemInfo represents information regarding single guest os filesystem",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"tickPolicy": {
+					"disks": {
 						SchemaProps: spec.SchemaProps{
-							Description: "TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of \"delay\", \"catchup\".",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"),
+									},
+								},
+							},
 						},
 					},
-					"present": {
+				},
+				Required: []string{"disks"},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystemList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineInstanceFileSystemList comprises the list of all filesystems on guest machine",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
-							Type:        []string{"boolean"},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"track": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Track the guest or the wall clock.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"),
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_ResourceRequirements(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestAgentInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineInstanceGuestAgentInfo represents information from the installed guest agent",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"requests": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Requests is a description of the initial vmi resources. Valid resource keys are \"memory\" and \"cpu\".",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"guestAgentVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GAVersion is a version of currently installed guest agent",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hostname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hostname represents FQDN of a guest",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"os": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OS contains the guest operating system information",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo"),
+						},
+					},
+					"timezone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timezone is guest os current timezone",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"limits": {
+					"userList": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Limits describes the maximum amount of compute resources allowed. Valid resource keys are \"memory\" and \"cpu\".",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Description: "UserList is a list of active guest OS users",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"),
 									},
 								},
 							},
 						},
 					},
-					"overcommitGuestOverhead": {
+					"fsInfo": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Don't ask the scheduler to take the guest-management overhead into account. Instead put the overhead only into the container's memory limit. This can lead to crashes if all memory is in use on a node. Defaults to false.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "FSInfo is a guest os filesystem information containing the disk mapping and disk mounts with usage",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystemInfo"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/api/resource.Quantity"},
+			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystemInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_RestartOptions(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "RestartOptions may be provided when deleting an API object.",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "Name of the Guest OS",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"kernelRelease": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Guest OS Kernel Release",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"gracePeriodSeconds": {
+					"version": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The duration in seconds before the object should be force-restared. Value must be non-negative integer. The value zero indicates, restart immediately. If this value is nil, the default grace period for deletion of the corresponding VMI for the specified type will be used to determine on how much time to give the VMI to restart. Defaults to a per object value if not specified. zero means restart immediately. Allowed Values: nil and 0",
-							Type:        []string{"integer"},
-							Format:      "int64",
+							Description: "Guest OS Version",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_Rng(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Rng represents the random device passed from host",
-				Type:        []string{"object"},
-			},
-		},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_SMBiosConfiguration(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"manufacturer": {
+					"prettyName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Guest OS Pretty Name",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"product": {
+					"versionId": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Version ID of the Guest OS",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"version": {
+					"kernelVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Kernel version of the Guest OS",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"sku": {
+					"machine": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Machine type of the Guest OS",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"family": {
+					"id": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Guest OS Id",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
@@ -16244,110 +19977,117 @@ func schema_kubevirtio_client_go_api_v1_SMBiosConfiguration(ref common.Reference
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_SecretVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SecretVolumeSource adapts a Secret into a volume.",
+				Description: "VirtualMachineGuestOSUser is the single user of the guest os",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"secretName": {
+					"userName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the secret in the pod's namespace to use. More info: https://kubernetes.io/docs/concepts/storage/volumes#secret",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"optional": {
+					"domain": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify whether the Secret or it's keys must be defined",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"volumeLabel": {
+					"loginTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The volume label of the resulting disk inside the VMI. Different bootstrapping mechanisms require different values. Typical values are \"cidata\" (cloud-init), \"config-2\" (cloud-init) or \"OEMDRV\" (kickstart).",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"number"},
+							Format: "double",
 						},
 					},
 				},
+				Required: []string{"userName"},
 			},
 		},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_ServiceAccountVolumeSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUserList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ServiceAccountVolumeSource adapts a ServiceAccount into a volume.",
+				Description: "VirtualMachineInstanceGuestOSUserList comprises the list of all active users on guest machine",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"serviceAccountName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the service account in the pod's namespace to use. More info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"),
+									},
+								},
+							},
+						},
+					},
 				},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_Timer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceLifecycle(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents all available timers in a vmi.",
+				Description: "VirtualMachineInstanceLifecycle describes actions virt-launcher takes in response to lifecycle events of the VirtualMachineInstance, similar to the lifecycle hooks of containers.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"hpet": {
-						SchemaProps: spec.SchemaProps{
-							Description: "HPET (High Precision Event Timer) - multiple timers with periodic interrupts.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.HPETTimer"),
-						},
-					},
-					"kvm": {
-						SchemaProps: spec.SchemaProps{
-							Description: "KVM \t(KVM clock) - lets guests read the host’s wall clock time (paravirtualized). For linux guests.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.KVMTimer"),
-						},
-					},
-					"pit": {
-						SchemaProps: spec.SchemaProps{
-							Description: "PIT (Programmable Interval Timer) - a timer with periodic interrupts.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.PITTimer"),
-						},
-					},
-					"rtc": {
+					"postStart": {
 						SchemaProps: spec.SchemaProps{
-							Description: "RTC (Real Time Clock) - a continuously running timer with periodic interrupts.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.RTCTimer"),
+							Description: "PostStart is called after the domain was started. A failing hook is reported as an event, the VirtualMachineInstance keeps running.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.LifecycleHandler"),
 						},
 					},
-					"hyperv": {
+					"preStop": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Hyperv (Hypervclock) - lets guests read the host’s wall clock time (paravirtualized). For windows guests.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.HypervTimer"),
+							Description: "PreStop is called before the guest is asked to shut down gracefully. The termination grace period starts before the hook is called.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.LifecycleHandler"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.HPETTimer", "kubevirt.io/client-go/api/v1.HypervTimer", "kubevirt.io/client-go/api/v1.KVMTimer", "kubevirt.io/client-go/api/v1.PITTimer", "kubevirt.io/client-go/api/v1.RTCTimer"},
+			"kubevirt.io/client-go/api/v1.LifecycleHandler"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachine(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachine handles the VirtualMachines that are not running or are in a stopped state The VirtualMachine contains the template to create the VirtualMachineInstance. It also mirrors the running state of the created VirtualMachineInstance in its status.",
+				Description: "VirtualMachineInstanceList is a list of VirtualMachines",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -16366,73 +20106,85 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachine(ref common.ReferenceCallb
 					},
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Spec contains the specification of VirtualMachineInstance created",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineSpec"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"status": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status holds the current state of the controller and brief information about its associated VirtualMachineInstance",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineStatus"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstance"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"spec"},
+				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineSpec", "kubevirt.io/client-go/api/v1.VirtualMachineStatus"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstance"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMemoryDumpStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineCondition represents the state of VirtualMachine",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"claimName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "The PersistentVolumeClaim the memory of the guest is dumped to.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "The phase of the memory dump.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastProbeTime": {
+					"attachPodUID": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "The UID of the pod which attaches the claim to the node of the VirtualMachineInstance.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastTransitionTime": {
+					"fileName": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "The name of the dump file on the claim.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"reason": {
+					"startTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "The time the memory dump started.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"endTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time the memory dump ended.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 					"message": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Tells why the memory dump failed.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"type", "status"},
+				Required: []string{"claimName"},
 			},
 		},
 		Dependencies: []string{
@@ -16440,11 +20192,11 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineCondition(ref common.Refer
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstance(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstance is *the* VirtualMachineInstance Definition. It represents a virtual machine in the runtime environment of kubernetes.",
+				Description: "VirtualMachineInstanceMigration represents the object tracking a VMI's migration to another host in the cluster",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -16468,14 +20220,12 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstance(ref common.Refere
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"),
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status is the high level overview of how the VirtualMachineInstance is doing. It contains information available to controllers and users.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceStatus"),
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationStatus"),
 						},
 					},
 				},
@@ -16483,11 +20233,11 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstance(ref common.Refere
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceStatus"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
@@ -16536,306 +20286,318 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceCondition(ref comm
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystem(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceFileSystem represents guest os disk",
+				Description: "VirtualMachineInstanceMigrationList is a list of VirtualMachineMigrations",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"diskName": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"mountPoint": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"fileSystemType": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"usedBytes": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int32",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"totalBytes": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int32",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigration"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"diskName", "mountPoint", "fileSystemType", "usedBytes", "totalBytes"},
+				Required: []string{"items"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigration"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystemInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceFileSystemInfo represents information regarding single guest os filesystem",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"disks": {
+					"vmiName": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"),
-									},
-								},
-							},
+							Description: "The name of the VMI to perform the migration on. VMI must exist in the migration objects namespace",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"disks"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceFileSystemList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationState(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceFileSystemList comprises the list of all filesystems on guest machine",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"startTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The time the migration action began",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"apiVersion": {
+					"endTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
+							Description: "The time the migration action ended",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"targetNodeDomainDetected": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Target Node has seen the Domain Start Event",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"targetNodeAddress": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "The address of the target node to use for the migration",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"items": {
+					"targetDirectMigrationNodePorts": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
+							Description: "The list of ports opened for live migration on the destination node",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"),
+										Type:   []string{"integer"},
+										Format: "int32",
 									},
 								},
 							},
 						},
 					},
-				},
-				Required: []string{"items"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystem"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestAgentInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceGuestAgentInfo represents information from the installed guest agent",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"kind": {
+					"targetNode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "The target node that the VMI is moving to",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"targetPod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "The target pod that the VMI is moving to",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"guestAgentVersion": {
+					"sourceNode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "GAVersion is a version of currently installed guest agent",
+							Description: "The source node that the VMI originated on",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"hostname": {
+					"completed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Hostname represents FQDN of a guest",
-							Type:        []string{"string"},
+							Description: "Indicates the migration completed",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"os": {
+					"failed": {
 						SchemaProps: spec.SchemaProps{
-							Description: "OS contains the guest operating system information",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo"),
+							Description: "Indicates that the migration failed",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"timezone": {
+					"abortRequested": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Timezone is guest os current timezone",
+							Description: "Indicates that the migration has been requested to abort",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"abortStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates the final status of the live migration abortion",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"userList": {
+					"migrationUid": {
 						SchemaProps: spec.SchemaProps{
-							Description: "UserList is a list of active guest OS users",
-							Type:        []string{"array"},
+							Description: "The VirtualMachineInstanceMigration object associated with this migration",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineInstanceMigration reprents information pertaining to a VMI's migration.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationCondition"),
 									},
 								},
 							},
 						},
 					},
-					"fsInfo": {
-						SchemaProps: spec.SchemaProps{
-							Description: "FSInfo is a guest os filesystem information containing the disk mapping and disk mounts with usage",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystemInfo"),
-						},
-					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceFileSystemInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"},
+			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationCondition"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceNetworkInterface(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Name of the Guest OS",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"kernelRelease": {
+					"ipAddress": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Guest OS Kernel Release",
+							Description: "IP address of a Virtual Machine interface. It is always the first item of IPs",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"version": {
+					"mac": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Guest OS Version",
+							Description: "Hardware address of a Virtual Machine interface",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"prettyName": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Guest OS Pretty Name",
+							Description: "Name of the interface, corresponds to name of the network assigned to the interface",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"versionId": {
+					"ipAddresses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Version ID of the Guest OS",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "List of all IP addresses of a Virtual Machine interface",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
-					"kernelVersion": {
+					"interfaceName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kernel version of the Guest OS",
+							Description: "The interface name inside the Virtual Machine",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"machine": {
+					"vlan": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Machine type of the Guest OS",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "VLAN ID the traffic of the interface is tagged with, if it is connected to an OVN-Kubernetes localnet network",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"id": {
+					"allowedAddresses": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Guest OS Id",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Additional addresses the guest is allowed to use on the interface",
+							Ref:         ref("kubevirt.io/client-go/api/v1.AllowedAddresses"),
 						},
 					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.AllowedAddresses"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePhaseTransitionTimestamp(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineGuestOSUser is the single user of the guest os",
+				Description: "VirtualMachineInstancePhaseTransitionTimestamp is the time the VirtualMachineInstance entered a phase",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"userName": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"domain": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Phase is the phase the VirtualMachineInstance entered",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"loginTime": {
+					"phaseTransitionTimestamp": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"number"},
-							Format: "double",
+							Description: "PhaseTransitionTimestamp is the time the VirtualMachineInstance entered the phase",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 				},
-				Required: []string{"userName"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUserList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePreset(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceGuestOSUserList comprises the list of all active users on guest machine",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -16853,35 +20615,28 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceGuestOSUserList(re
 					},
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"items": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"),
-									},
-								},
-							},
+							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetSpec"),
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSUser"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetSpec"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceList is a list of VirtualMachines",
+				Description: "VirtualMachineInstancePresetList is a list of VirtualMachinePresets",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -16909,7 +20664,7 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceList(ref common.Re
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstance"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancePreset"),
 									},
 								},
 							},
@@ -16920,15 +20675,42 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceList(ref common.Re
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstance"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancePreset"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceMigration represents the object tracking a VMI's migration to another host in the cluster",
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector is a label query over a set of VMIs. Required.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"domain": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Domain is the same object type as contained in VirtualMachineInstanceSpec",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DomainSpec"),
+						},
+					},
+				},
+				Required: []string{"selector"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "kubevirt.io/client-go/api/v1.DomainSpec"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineInstance is *the* VirtualMachineInstance Definition. It represents a virtual machine in the runtime environment of kubernetes.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -16952,12 +20734,14 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigration(ref comm
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationSpec"),
+							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationStatus"),
+							Description: "Status is the high level overview of how the VirtualMachineInstance is doing. It contains information available to controllers and users.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetStatus"),
 						},
 					},
 				},
@@ -16965,11 +20749,11 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigration(ref comm
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationStatus"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
@@ -17018,11 +20802,11 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationCondition
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceMigrationList is a list of VirtualMachineMigrations",
+				Description: "VMIList is a list of VMIs",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -17050,7 +20834,7 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationList(ref
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigration"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSet"),
 									},
 								},
 							},
@@ -17061,441 +20845,331 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationList(ref
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigration"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"vmiName": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The name of the VMI to perform the migration on. VMI must exist in the migration objects namespace",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-				},
-			},
-		},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSet"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"startTimestamp": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The time the migration action began",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"endTimestamp": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The time the migration action ended",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
-						},
-					},
-					"targetNodeDomainDetected": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The Target Node has seen the Domain Start Event",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"targetNodeAddress": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The address of the target node to use for the migration",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"targetDirectMigrationNodePorts": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The list of ports opened for live migration on the destination node",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"integer"},
-										Format: "int32",
-									},
-								},
-							},
-						},
-					},
-					"targetNode": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The target node that the VMI is moving to",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"targetPod": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The target pod that the VMI is moving to",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"sourceNode": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The source node that the VMI originated on",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"completed": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Indicates the migration completed",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"failed": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Indicates that the migration failed",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"abortRequested": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Indicates that the migration has been requested to abort",
-							Type:        []string{"boolean"},
-							Format:      "",
-						},
-					},
-					"abortStatus": {
+					"replicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Indicates the final status of the live migration abortion",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Number of desired pods. This is a pointer to distinguish between explicit zero and not specified. Defaults to 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"migrationUid": {
+					"selector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The VirtualMachineInstanceMigration object associated with this migration",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Label selector for pods. Existing ReplicaSets whose pods are selected by this will be the ones affected by this deployment.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceMigrationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceMigration reprents information pertaining to a VMI's migration.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"phase": {
+					"template": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Template describes the pods that will be created.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec"),
 						},
 					},
-					"conditions": {
+					"paused": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationCondition"),
-									},
-								},
-							},
+							Description: "Indicates that the replica set is paused.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"selector", "template"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationCondition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceNetworkInterface(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"ipAddress": {
+					"replicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IP address of a Virtual Machine interface. It is always the first item of IPs",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Total number of non-terminated pods targeted by this deployment (their labels match the selector).",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"mac": {
+					"readyReplicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Hardware address of a Virtual Machine interface",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "The number of ready replicas for this replica set.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"name": {
+					"conditions": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the interface, corresponds to name of the network assigned to the interface",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetCondition"),
+									},
+								},
+							},
+						},
+					},
+					"labelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Canonical form of the label selector for HPA which consumes it through the scale subresource.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"ipAddresses": {
+					"zones": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of all IP addresses of a Virtual Machine interface",
+							Description: "Zones reports how the replicas are distributed across the zones of the nodes they are running on.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetZoneStatus"),
 									},
 								},
 							},
 						},
 					},
-					"interfaceName": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The interface name inside the Virtual Machine",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetCondition", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetZoneStatus"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePreset(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetZoneStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineInstanceReplicaSetZoneStatus contains the number of replicas running in a zone",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
+					"zone": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Zone is the value of the zone label of the nodes",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"replicas": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Number of replicas running in the zone",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"spec": {
+					"readyReplicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetSpec"),
+							Description: "Number of ready replicas in the zone",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
+				Required: []string{"zone", "replicas"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancePresetSpec"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstancePresetList is a list of VirtualMachinePresets",
+				Description: "VirtualMachineInstanceSpec is a description of a VirtualMachineInstance.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"priorityClassName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "If specified, indicates the pod's priority. If not specified, the pod priority will be default or zero if there is no default.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"domain": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Specification of the desired behavior of the VirtualMachineInstance on the host.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DomainSpec"),
+						},
+					},
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector is a selector which must be true for the vmi to fit on a node. Selector which must match a node's labels for the vmi to be scheduled on that node. More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"affinity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If affinity is specifies, obey all the affinity rules",
+							Ref:         ref("k8s.io/api/core/v1.Affinity"),
+						},
+					},
+					"schedulerName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the VMI will be dispatched by specified scheduler. If not specified, the VMI will be dispatched by default scheduler.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"tolerations": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "If toleration is specified, obey all the toleration rules.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
 						},
 					},
-					"items": {
+					"topologySpreadConstraints": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "TopologySpreadConstraints describes how a group of VMIs ought to spread across topology domains, like zones. They are passed to the virt-launcher pod as they are.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancePreset"),
+										Ref: ref("k8s.io/api/core/v1.TopologySpreadConstraint"),
 									},
 								},
 							},
 						},
 					},
-				},
-				Required: []string{"items"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancePreset"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancePresetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"selector": {
+					"evictionStrategy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Selector is a label query over a set of VMIs. Required.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+							Description: "EvictionStrategy describes what happens to the VirtualMachineInstance when its pod is evicted, for instance during a node drain. \"LiveMigrate\" blocks the eviction and migrates the VirtualMachineInstance, \"LiveMigrateIfPossible\" does the same but lets the eviction shut it off if it is not migratable, \"External\" blocks the eviction and leaves the evacuation to an external controller, and \"None\" lets the eviction shut it off.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"domain": {
+					"terminationGracePeriodSeconds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Domain is the same object type as contained in VirtualMachineInstanceSpec",
-							Ref:         ref("kubevirt.io/client-go/api/v1.DomainSpec"),
+							Description: "Grace period observed after signalling a VirtualMachineInstance to stop after which the VirtualMachineInstance is force terminated.",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-				},
-				Required: []string{"selector"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "kubevirt.io/client-go/api/v1.DomainSpec"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstance is *the* VirtualMachineInstance Definition. It represents a virtual machine in the runtime environment of kubernetes.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"kind": {
+					"volumes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "List of volumes that can be mounted by disks belonging to the vmi.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Volume"),
+									},
+								},
+							},
 						},
 					},
-					"apiVersion": {
+					"livenessProbe": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Periodic probe of VirtualMachineInstance liveness. VirtualmachineInstances will be stopped if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Probe"),
 						},
 					},
-					"metadata": {
+					"readinessProbe": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Periodic probe of VirtualMachineInstance service readiness. VirtualmachineInstances will be removed from service endpoints if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+							Ref:         ref("kubevirt.io/client-go/api/v1.Probe"),
 						},
 					},
-					"spec": {
+					"hostname": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetSpec"),
+							Description: "Specifies the hostname of the vmi If not specified, the hostname will be set to the name of the vmi, if dhcp or cloud-init is configured properly.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"subdomain": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Status is the high level overview of how the VirtualMachineInstance is doing. It contains information available to controllers and users.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetStatus"),
+							Description: "If specified, the fully qualified vmi hostname will be \"<hostname>.<subdomain>.<pod namespace>.svc.<cluster domain>\". If not specified, the vmi will not have a domainname at all. The DNS entry will resolve to the vmi, no matter if the vmi itself can pick up a hostname.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-				Required: []string{"spec"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetSpec", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetStatus"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"type": {
+					"networks": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "List of networks that can be attached to a vm's virtual interface.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.Network"),
+									},
+								},
+							},
 						},
 					},
-					"status": {
+					"dnsPolicy": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Set DNS policy for the pod. Defaults to \"ClusterFirst\". Valid values are 'ClusterFirstWithHostNet', 'ClusterFirst', 'Default' or 'None'. DNS parameters given in DNSConfig will be merged with the policy selected with DNSPolicy. To have DNS options set along with hostNetwork, you have to specify DNS policy explicitly to 'ClusterFirstWithHostNet'.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastProbeTime": {
+					"dnsConfig": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Specifies the DNS parameters of a pod. Parameters specified here will be merged to the generated DNS configuration based on DNSPolicy.",
+							Ref:         ref("k8s.io/api/core/v1.PodDNSConfig"),
 						},
 					},
-					"lastTransitionTime": {
+					"diskCompaction": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "If specified, the qcow2 overlays of the disks are periodically compacted while the VirtualMachineInstance is running, by trimming the unused space of the guest back to the storage.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskCompaction"),
 						},
 					},
-					"reason": {
+					"lifecycle": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Actions that virt-launcher takes in response to lifecycle events of the VirtualMachineInstance.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceLifecycle"),
 						},
 					},
-					"message": {
+					"ioErrorRecovery": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "How virt-handler recovers the VirtualMachineInstance when qemu paused it because of an I/O error, like a full or unreachable storage.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.IOErrorRecovery"),
 						},
 					},
 				},
-				Required: []string{"type", "status"},
+				Required: []string{"domain"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.PodDNSConfig", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.TopologySpreadConstraint", "kubevirt.io/client-go/api/v1.DiskCompaction", "kubevirt.io/client-go/api/v1.DomainSpec", "kubevirt.io/client-go/api/v1.IOErrorRecovery", "kubevirt.io/client-go/api/v1.Network", "kubevirt.io/client-go/api/v1.Probe", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceLifecycle", "kubevirt.io/client-go/api/v1.Volume"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceStats(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VMIList is a list of VMIs",
+				Description: "VirtualMachineInstanceStats represents a sample of the resource usage of a running VMI",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -17512,143 +21186,167 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetList(ref
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"timestamp": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "Timestamp of the sample",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"),
 						},
 					},
-					"items": {
+					"cpuTimeNanoseconds": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSet"),
-									},
-								},
-							},
+							Description: "CPU time consumed by the domain since it was started",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-				},
-				Required: []string{"items"},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSet"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"replicas": {
+					"vcpus": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Number of desired pods. This is a pointer to distinguish between explicit zero and not specified. Defaults to 1.",
+							Description: "Number of vCPUs of the domain",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
-					"selector": {
+					"memoryResidentBytes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Label selector for pods. Existing ReplicaSets whose pods are selected by this will be the ones affected by this deployment.",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+							Description: "Resident set size of the domain",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"template": {
+					"memoryAvailableBytes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Template describes the pods that will be created.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec"),
+							Description: "Memory available to the guest, as reported by the balloon driver",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"paused": {
+					"storageReadBytes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Indicates that the replica set is paused.",
-							Type:        []string{"boolean"},
-							Format:      "",
+							Description: "Bytes read from all disks since the domain was started",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"storageWriteBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bytes written to all disks since the domain was started",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"networkRxBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bytes received on all interfaces since the domain was started",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"networkTxBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Bytes transmitted on all interfaces since the domain was started",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
-				Required: []string{"selector", "template"},
+				Required: []string{"timestamp", "cpuTimeNanoseconds", "vcpus", "memoryResidentBytes", "memoryAvailableBytes", "storageReadBytes", "storageWriteBytes", "networkRxBytes", "networkTxBytes"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceReplicaSetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachineInstanceStatus represents information about the status of a VirtualMachineInstance. Status may trail the actual state of a system.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"replicas": {
+					"nodeName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Total number of non-terminated pods targeted by this deployment (their labels match the selector).",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "NodeName is the name where the VirtualMachineInstance is currently running.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"readyReplicas": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The number of ready replicas for this replica set.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "A brief CamelCase message indicating details about why the VMI is in this state. e.g. 'NodeUnresponsive'",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Conditions are specific points in VirtualMachineInstance's pod runtime.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetCondition"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceCondition"),
 									},
 								},
 							},
 						},
 					},
-					"labelSelector": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Canonical form of the label selector for HPA which consumes it through the scale subresource.",
+							Description: "Phase is the status of the VirtualMachineInstance in kubernetes world. It is not the VirtualMachineInstance status, but partially correlates to it.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceReplicaSetCondition"},
-	}
-}
-
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceSpec is a description of a VirtualMachineInstance.",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"priorityClassName": {
+					"interfaces": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, indicates the pod's priority. If not specified, the pod priority will be default or zero if there is no default.",
+							Description: "Interfaces represent the details of available network interfaces.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceNetworkInterface"),
+									},
+								},
+							},
+						},
+					},
+					"guestOSInfo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Guest OS Information",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo"),
+						},
+					},
+					"guestHostname": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hostname of the guest as reported by the guest agent",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"domain": {
+					"migrationState": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specification of the desired behavior of the VirtualMachineInstance on the host.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.DomainSpec"),
+							Description: "Represents the status of a live migration",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationState"),
 						},
 					},
-					"nodeSelector": {
+					"migrationMethod": {
 						SchemaProps: spec.SchemaProps{
-							Description: "NodeSelector is a selector which must be true for the vmi to fit on a node. Selector which must match a node's labels for the vmi to be scheduled on that node. More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/",
+							Description: "Represents the method using which the vmi can be migrated: live migration or block migration",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"qosClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The Quality of Service (QOS) classification assigned to the virtual machine instance based on resource requirements See PodQOSClass type for available QOS classes More info: https://git.k8s.io/community/contributors/design-proposals/node/resource-qos.md",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"activePods": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActivePods is a mapping of pod UID to node name. It is possible for multiple pods to be running for a single VMI during migration.",
 							Type:        []string{"object"},
 							AdditionalProperties: &spec.SchemaOrBool{
 								Allows: true,
@@ -17661,229 +21359,319 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceSpec(ref common.Re
 							},
 						},
 					},
-					"affinity": {
+					"diskCompaction": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If affinity is specifies, obey all the affinity rules",
-							Ref:         ref("k8s.io/api/core/v1.Affinity"),
+							Description: "Represents the result of the last compaction of the disk overlays",
+							Ref:         ref("kubevirt.io/client-go/api/v1.DiskCompactionStatus"),
 						},
 					},
-					"schedulerName": {
+					"memoryDump": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the VMI will be dispatched by specified scheduler. If not specified, the VMI will be dispatched by default scheduler.",
+							Description: "Represents the progress of the last memory dump of the guest",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMemoryDumpStatus"),
+						},
+					},
+					"evacuationNodeName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EvacuationNodeName is set to the node the VirtualMachineInstance has to leave after an eviction of its pod was blocked",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"tolerations": {
+					"vcpuPinning": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If toleration is specified, obey all the toleration rules.",
+							Description: "VCPUPinning reports the pCPUs of the node the vCPUs are pinned to, if the CPUs are dedicated",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/api/core/v1.Toleration"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VCPUPin"),
 									},
 								},
 							},
 						},
 					},
-					"evictionStrategy": {
+					"ipClaims": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPClaims are the IP addresses claimed by the interfaces with persistent IPs",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.InterfaceIPClaim"),
+									},
+								},
+							},
+						},
+					},
+					"runtimeUser": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RuntimeUser is the user qemu and libvirt run as in the virt-launcher pod, 0 if they run as root",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"phaseTransitionTimestamps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PhaseTransitionTimestamps record when the VirtualMachineInstance entered each of its phases",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancePhaseTransitionTimestamp"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.DiskCompactionStatus", "kubevirt.io/client-go/api/v1.InterfaceIPClaim", "kubevirt.io/client-go/api/v1.VCPUPin", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceCondition", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMemoryDumpStatus", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationState", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceNetworkInterface", "kubevirt.io/client-go/api/v1.VirtualMachineInstancePhaseTransitionTimestamp"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetype(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineInstancetype describes the CPU and memory of VirtualMachines. VirtualMachines referencing it must not set these fields themselves.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "EvictionStrategy can be set to \"LiveMigrate\" if the VirtualMachineInstance should be migrated instead of shut-off in case of a node drain.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"terminationGracePeriodSeconds": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Grace period observed after signalling a VirtualMachineInstance to stop after which the VirtualMachineInstance is force terminated.",
-							Type:        []string{"integer"},
-							Format:      "int64",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"volumes": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of volumes that can be mounted by disks belonging to the vmi.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Volume"),
-									},
-								},
-							},
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"livenessProbe": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Periodic probe of VirtualMachineInstance liveness. VirtualmachineInstances will be stopped if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Probe"),
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancetypeSpec"),
 						},
 					},
-					"readinessProbe": {
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancetypeSpec"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetypeList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineInstancetypeList is a list of VirtualMachineInstancetypes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Periodic probe of VirtualMachineInstance service readiness. VirtualmachineInstances will be removed from service endpoints if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
-							Ref:         ref("kubevirt.io/client-go/api/v1.Probe"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"hostname": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies the hostname of the vmi If not specified, the hostname will be set to the name of the vmi, if dhcp or cloud-init is configured properly.",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"subdomain": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "If specified, the fully qualified vmi hostname will be \"<hostname>.<subdomain>.<pod namespace>.svc.<cluster domain>\". If not specified, the vmi will not have a domainname at all. The DNS entry will resolve to the vmi, no matter if the vmi itself can pick up a hostname.",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"networks": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of networks that can be attached to a vm's virtual interface.",
-							Type:        []string{"array"},
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.Network"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstancetype"),
 									},
 								},
 							},
 						},
 					},
-					"dnsPolicy": {
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstancetype"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachineInstancetypeSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cpu": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Set DNS policy for the pod. Defaults to \"ClusterFirst\". Valid values are 'ClusterFirstWithHostNet', 'ClusterFirst', 'Default' or 'None'. DNS parameters given in DNSConfig will be merged with the policy selected with DNSPolicy. To have DNS options set along with hostNetwork, you have to specify DNS policy explicitly to 'ClusterFirstWithHostNet'.",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("kubevirt.io/client-go/api/v1.CPUInstancetype"),
 						},
 					},
-					"dnsConfig": {
+					"memory": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specifies the DNS parameters of a pod. Parameters specified here will be merged to the generated DNS configuration based on DNSPolicy.",
-							Ref:         ref("k8s.io/api/core/v1.PodDNSConfig"),
+							Ref: ref("kubevirt.io/client-go/api/v1.MemoryInstancetype"),
 						},
 					},
 				},
-				Required: []string{"domain"},
+				Required: []string{"cpu", "memory"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.PodDNSConfig", "k8s.io/api/core/v1.Toleration", "kubevirt.io/client-go/api/v1.DomainSpec", "kubevirt.io/client-go/api/v1.Network", "kubevirt.io/client-go/api/v1.Probe", "kubevirt.io/client-go/api/v1.Volume"},
+			"kubevirt.io/client-go/api/v1.CPUInstancetype", "kubevirt.io/client-go/api/v1.MemoryInstancetype"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachineList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineInstanceStatus represents information about the status of a VirtualMachineInstance. Status may trail the actual state of a system.",
+				Description: "VirtualMachineList is a list of virtualmachines",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"nodeName": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "NodeName is the name where the VirtualMachineInstance is currently running.",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"reason": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A brief CamelCase message indicating details about why the VMI is in this state. e.g. 'NodeUnresponsive'",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"conditions": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Conditions are specific points in VirtualMachineInstance's pod runtime.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceCondition"),
-									},
-								},
-							},
-						},
-					},
-					"phase": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Phase is the status of the VirtualMachineInstance in kubernetes world. It is not the VirtualMachineInstance status, but partially correlates to it.",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"interfaces": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Interfaces represent the details of available network interfaces.",
-							Type:        []string{"array"},
+							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceNetworkInterface"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachine"),
 									},
 								},
 							},
 						},
 					},
-					"guestOSInfo": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Guest OS Information",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo"),
-						},
-					},
-					"migrationState": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Represents the status of a live migration",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationState"),
-						},
-					},
-					"migrationMethod": {
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachine"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachinePlacement(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachinePlacement makes a VirtualMachine a member of a group, whose VirtualMachineInstances are spread across nodes or zones by pod anti-affinity on their virt-launcher pods",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"group": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Represents the method using which the vmi can be migrated: live migration or block migration",
+							Description: "The name of the group. It has to be a valid label value.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"qosClass": {
+					"spreadAcross": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The Quality of Service (QOS) classification assigned to the virtual machine instance based on resource requirements See PodQOSClass type for available QOS classes More info: https://git.k8s.io/community/contributors/design-proposals/node/resource-qos.md",
+							Description: "SpreadAcross is the topology the members of the group are spread across, Node or Zone. Defaults to Node.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"activePods": {
+					"policy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ActivePods is a mapping of pod UID to node name. It is possible for multiple pods to be running for a single VMI during migration.",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "",
-									},
-								},
-							},
+							Description: "Policy tells if the spread is Required for scheduling or only Preferred. Defaults to Required.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"group"},
 			},
 		},
-		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceCondition", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceGuestOSInfo", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceMigrationState", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceNetworkInterface"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachinePreference(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "VirtualMachinePreference describes preferred values for fields of VirtualMachines. The preferences are only applied to fields the VirtualMachines leave unset.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"metadata": {
 						SchemaProps: spec.SchemaProps{
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
@@ -17891,23 +21679,23 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineInstanceTemplateSpec(ref c
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "VirtualMachineInstance Spec contains the VirtualMachineInstance specification.",
-							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"),
+							Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachinePreferenceSpec"),
 						},
 					},
 				},
+				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceSpec"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubevirt.io/client-go/api/v1.VirtualMachinePreferenceSpec"},
 	}
 }
 
-func schema_kubevirtio_client_go_api_v1_VirtualMachineList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_kubevirtio_client_go_api_v1_VirtualMachinePreferenceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "VirtualMachineList is a list of virtualmachines",
+				Description: "VirtualMachinePreferenceList is a list of VirtualMachinePreferences",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -17935,7 +21723,7 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineList(ref common.ReferenceC
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachine"),
+										Ref: ref("kubevirt.io/client-go/api/v1.VirtualMachinePreference"),
 									},
 								},
 							},
@@ -17946,7 +21734,36 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineList(ref common.ReferenceC
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachine"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubevirt.io/client-go/api/v1.VirtualMachinePreference"},
+	}
+}
+
+func schema_kubevirtio_client_go_api_v1_VirtualMachinePreferenceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.CPUPreferences"),
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.DevicePreferences"),
+						},
+					},
+					"machine": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubevirt.io/client-go/api/v1.MachinePreferences"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubevirt.io/client-go/api/v1.CPUPreferences", "kubevirt.io/client-go/api/v1.DevicePreferences", "kubevirt.io/client-go/api/v1.MachinePreferences"},
 	}
 }
 
@@ -17990,12 +21807,30 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineSpec(ref common.ReferenceC
 							},
 						},
 					},
+					"instancetype": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Instancetype references a VirtualMachineInstancetype, whose CPU and memory are expanded into the VirtualMachineInstance when the VirtualMachine is started",
+							Ref:         ref("kubevirt.io/client-go/api/v1.InstancetypeMatcher"),
+						},
+					},
+					"preference": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Preference references a VirtualMachinePreference, whose preferences fill the fields of the VirtualMachineInstance which the template leaves unset when the VirtualMachine is started",
+							Ref:         ref("kubevirt.io/client-go/api/v1.PreferenceMatcher"),
+						},
+					},
+					"placement": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Placement spreads the VirtualMachineInstance of the VirtualMachine across nodes or zones with the ones of the other VirtualMachines in the same group",
+							Ref:         ref("kubevirt.io/client-go/api/v1.VirtualMachinePlacement"),
+						},
+					},
 				},
 				Required: []string{"template"},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec", "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1.DataVolume"},
+			"kubevirt.io/client-go/api/v1.InstancetypeMatcher", "kubevirt.io/client-go/api/v1.PreferenceMatcher", "kubevirt.io/client-go/api/v1.VirtualMachineInstanceTemplateSpec", "kubevirt.io/client-go/api/v1.VirtualMachinePlacement", "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1.DataVolume"},
 	}
 }
 
@@ -18095,11 +21930,24 @@ func schema_kubevirtio_client_go_api_v1_VirtualMachineStatus(ref common.Referenc
 							},
 						},
 					},
+					"ipClaims": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IPClaims are the IP addresses claimed by the interfaces with persistent IPs, they are kept across restarts of the VirtualMachineInstance",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("kubevirt.io/client-go/api/v1.InterfaceIPClaim"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubevirt.io/client-go/api/v1.VirtualMachineCondition", "kubevirt.io/client-go/api/v1.VirtualMachineStateChangeRequest"},
+			"kubevirt.io/client-go/api/v1.InterfaceIPClaim", "kubevirt.io/client-go/api/v1.VirtualMachineCondition", "kubevirt.io/client-go/api/v1.VirtualMachineStateChangeRequest"},
 	}
 }
 
@@ -18141,6 +21989,18 @@ func schema_kubevirtio_client_go_api_v1_Volume(ref common.ReferenceCallback) com
 							Ref:         ref("kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource"),
 						},
 					},
+					"ignition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ignition represents an Ignition config which is passed to the vmi on first boot. The volume must not be referenced by a disk. A proper Ignition installation is required inside the guest. More info: https://coreos.github.io/ignition/",
+							Ref:         ref("kubevirt.io/client-go/api/v1.IgnitionSource"),
+						},
+					},
+					"sysprep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sysprep represents a Sysprep answer file for the unattended installation of Windows guests. The answer file will be added as a CDROM to the vmi. It can not be used together with cloud-init volumes.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SysprepSource"),
+						},
+					},
 					"containerDisk": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ContainerDisk references a docker image, embedding a qcow or raw disk. More info: https://kubevirt.gitbooks.io/user-guide/registry-disk.html",
@@ -18153,6 +22013,12 @@ func schema_kubevirtio_client_go_api_v1_Volume(ref common.ReferenceCallback) com
 							Ref:         ref("kubevirt.io/client-go/api/v1.EphemeralVolumeSource"),
 						},
 					},
+					"goldenImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GoldenImage references a ReadWriteMany or ReadOnlyMany PersistentVolumeClaim, which is attached read-only as the backing image of a copy-on-write overlay. Many vmis can use the same golden image at the same time, each one with its own overlay.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.GoldenImageVolumeSource"),
+						},
+					},
 					"emptyDisk": {
 						SchemaProps: spec.SchemaProps{
 							Description: "EmptyDisk represents a temporary disk which shares the vmis lifecycle. More info: https://kubevirt.gitbooks.io/user-guide/disks-and-volumes.html",
@@ -18188,7 +22054,7 @@ func schema_kubevirtio_client_go_api_v1_Volume(ref common.ReferenceCallback) com
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource", "kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource", "kubevirt.io/client-go/api/v1.CloudInitNoCloudSource", "kubevirt.io/client-go/api/v1.ConfigMapVolumeSource", "kubevirt.io/client-go/api/v1.ContainerDiskSource", "kubevirt.io/client-go/api/v1.DataVolumeSource", "kubevirt.io/client-go/api/v1.EmptyDiskSource", "kubevirt.io/client-go/api/v1.EphemeralVolumeSource", "kubevirt.io/client-go/api/v1.HostDisk", "kubevirt.io/client-go/api/v1.SecretVolumeSource", "kubevirt.io/client-go/api/v1.ServiceAccountVolumeSource"},
+			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource", "kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource", "kubevirt.io/client-go/api/v1.CloudInitNoCloudSource", "kubevirt.io/client-go/api/v1.ConfigMapVolumeSource", "kubevirt.io/client-go/api/v1.ContainerDiskSource", "kubevirt.io/client-go/api/v1.DataVolumeSource", "kubevirt.io/client-go/api/v1.EmptyDiskSource", "kubevirt.io/client-go/api/v1.EphemeralVolumeSource", "kubevirt.io/client-go/api/v1.GoldenImageVolumeSource", "kubevirt.io/client-go/api/v1.HostDisk", "kubevirt.io/client-go/api/v1.IgnitionSource", "kubevirt.io/client-go/api/v1.SecretVolumeSource", "kubevirt.io/client-go/api/v1.ServiceAccountVolumeSource", "kubevirt.io/client-go/api/v1.SysprepSource"},
 	}
 }
 
@@ -18223,6 +22089,18 @@ func schema_kubevirtio_client_go_api_v1_VolumeSource(ref common.ReferenceCallbac
 							Ref:         ref("kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource"),
 						},
 					},
+					"ignition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ignition represents an Ignition config which is passed to the vmi on first boot. The volume must not be referenced by a disk. A proper Ignition installation is required inside the guest. More info: https://coreos.github.io/ignition/",
+							Ref:         ref("kubevirt.io/client-go/api/v1.IgnitionSource"),
+						},
+					},
+					"sysprep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sysprep represents a Sysprep answer file for the unattended installation of Windows guests. The answer file will be added as a CDROM to the vmi. It can not be used together with cloud-init volumes.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.SysprepSource"),
+						},
+					},
 					"containerDisk": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ContainerDisk references a docker image, embedding a qcow or raw disk. More info: https://kubevirt.gitbooks.io/user-guide/registry-disk.html",
@@ -18235,6 +22113,12 @@ func schema_kubevirtio_client_go_api_v1_VolumeSource(ref common.ReferenceCallbac
 							Ref:         ref("kubevirt.io/client-go/api/v1.EphemeralVolumeSource"),
 						},
 					},
+					"goldenImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GoldenImage references a ReadWriteMany or ReadOnlyMany PersistentVolumeClaim, which is attached read-only as the backing image of a copy-on-write overlay. Many vmis can use the same golden image at the same time, each one with its own overlay.",
+							Ref:         ref("kubevirt.io/client-go/api/v1.GoldenImageVolumeSource"),
+						},
+					},
 					"emptyDisk": {
 						SchemaProps: spec.SchemaProps{
 							Description: "EmptyDisk represents a temporary disk which shares the vmis lifecycle. More info: https://kubevirt.gitbooks.io/user-guide/disks-and-volumes.html",
@@ -18269,7 +22153,7 @@ func schema_kubevirtio_client_go_api_v1_VolumeSource(ref common.ReferenceCallbac
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource", "kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource", "kubevirt.io/client-go/api/v1.CloudInitNoCloudSource", "kubevirt.io/client-go/api/v1.ConfigMapVolumeSource", "kubevirt.io/client-go/api/v1.ContainerDiskSource", "kubevirt.io/client-go/api/v1.DataVolumeSource", "kubevirt.io/client-go/api/v1.EmptyDiskSource", "kubevirt.io/client-go/api/v1.EphemeralVolumeSource", "kubevirt.io/client-go/api/v1.HostDisk", "kubevirt.io/client-go/api/v1.SecretVolumeSource", "kubevirt.io/client-go/api/v1.ServiceAccountVolumeSource"},
+			"k8s.io/api/core/v1.PersistentVolumeClaimVolumeSource", "kubevirt.io/client-go/api/v1.CloudInitConfigDriveSource", "kubevirt.io/client-go/api/v1.CloudInitNoCloudSource", "kubevirt.io/client-go/api/v1.ConfigMapVolumeSource", "kubevirt.io/client-go/api/v1.ContainerDiskSource", "kubevirt.io/client-go/api/v1.DataVolumeSource", "kubevirt.io/client-go/api/v1.EmptyDiskSource", "kubevirt.io/client-go/api/v1.EphemeralVolumeSource", "kubevirt.io/client-go/api/v1.GoldenImageVolumeSource", "kubevirt.io/client-go/api/v1.HostDisk", "kubevirt.io/client-go/api/v1.IgnitionSource", "kubevirt.io/client-go/api/v1.SecretVolumeSource", "kubevirt.io/client-go/api/v1.ServiceAccountVolumeSource", "kubevirt.io/client-go/api/v1.SysprepSource"},
 	}
 }
 
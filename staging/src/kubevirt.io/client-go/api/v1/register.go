@@ -71,6 +71,11 @@ var (
 	VirtualMachineGroupVersionKind                   = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachine"}
 	VirtualMachineInstanceMigrationGroupVersionKind  = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachineInstanceMigration"}
 	KubeVirtGroupVersionKind                         = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "KubeVirt"}
+	ConformanceRunGroupVersionKind                   = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "ConformanceRun"}
+	VirtualMachineExportGroupVersionKind             = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachineExport"}
+	VirtualMachineBackupGroupVersionKind             = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachineBackup"}
+	VirtualMachineInstancetypeGroupVersionKind       = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachineInstancetype"}
+	VirtualMachinePreferenceGroupVersionKind         = schema.GroupVersionKind{Group: GroupName, Version: GroupVersion.Version, Kind: "VirtualMachinePreference"}
 )
 
 var (
@@ -103,6 +108,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 			&VirtualMachineList{},
 			&KubeVirt{},
 			&KubeVirtList{},
+			&ConformanceRun{},
+			&ConformanceRunList{},
+			&VirtualMachineExport{},
+			&VirtualMachineExportList{},
+			&VirtualMachineBackup{},
+			&VirtualMachineBackupList{},
+			&VirtualMachineInstancetype{},
+			&VirtualMachineInstancetypeList{},
+			&VirtualMachinePreference{},
+			&VirtualMachinePreferenceList{},
 		)
 		metav1.AddToGroupVersion(scheme, groupVersion)
 	}
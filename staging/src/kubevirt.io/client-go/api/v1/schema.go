@@ -30,10 +30,26 @@ type IOThreadsPolicy string
 const (
 	IOThreadsPolicyShared  IOThreadsPolicy = "shared"
 	IOThreadsPolicyAuto    IOThreadsPolicy = "auto"
+	IOThreadsPolicyManual  IOThreadsPolicy = "manual"
 	CPUModeHostPassthrough                 = "host-passthrough"
 	CPUModeHostModel                       = "host-model"
 )
 
+type EmulatorThreadPolicy string
+
+const (
+	EmulatorThreadPolicyIsolate EmulatorThreadPolicy = "isolate"
+	EmulatorThreadPolicyVCPU0   EmulatorThreadPolicy = "vcpu0"
+	EmulatorThreadPolicyFloat   EmulatorThreadPolicy = "float"
+)
+
+type CPUThreadPolicy string
+
+const (
+	CPUThreadPolicyFullCores CPUThreadPolicy = "fullCores"
+	CPUThreadPolicyIsolate   CPUThreadPolicy = "isolate"
+)
+
 //go:generate swagger-doc
 //go:generate openapi-gen -i . --output-package=kubevirt.io/client-go/api/v1  --go-header-file ../../../../../../hack/boilerplate/boilerplate.go.txt
 
@@ -150,6 +166,37 @@ type CloudInitConfigDriveSource struct {
 	NetworkData string `json:"networkData,omitempty"`
 }
 
+// Represents an Ignition config source for Fedora CoreOS and RHCOS guests.
+// The config is passed to the guest through the opt/com.coreos/config firmware configuration entry.
+// More info: https://coreos.github.io/ignition/
+//
+// +k8s:openapi-gen=true
+type IgnitionSource struct {
+	// SecretRef references a k8s secret that contains the Ignition config under the config.ign key.
+	// + optional
+	SecretRef *v1.LocalObjectReference `json:"secretRef,omitempty"`
+	// ConfigMapRef references a k8s config map that contains the Ignition config under the config.ign key.
+	// + optional
+	ConfigMapRef *v1.LocalObjectReference `json:"configMapRef,omitempty"`
+	// Data contains the inline Ignition config.
+	// + optional
+	Data string `json:"data,omitempty"`
+}
+
+// Represents a Sysprep answer file source for the unattended installation of Windows guests.
+// The answer file is added as a CDROM to the vmi, where Windows Setup looks for it.
+// More info: https://docs.microsoft.com/en-us/windows-hardware/manufacture/desktop/windows-setup-automation-overview
+//
+// +k8s:openapi-gen=true
+type SysprepSource struct {
+	// Secret references a k8s secret that contains the answer file under the autounattend.xml or unattend.xml key.
+	// + optional
+	Secret *v1.LocalObjectReference `json:"secret,omitempty"`
+	// ConfigMap references a k8s config map that contains the answer file under the autounattend.xml or unattend.xml key.
+	// + optional
+	ConfigMap *v1.LocalObjectReference `json:"configMap,omitempty"`
+}
+
 //
 // +k8s:openapi-gen=true
 type DomainSpec struct {
@@ -177,12 +224,33 @@ type DomainSpec struct {
 	Devices Devices `json:"devices"`
 	// Controls whether or not disks will share IOThreads.
 	// Omitting IOThreadsPolicy disables use of IOThreads.
-	// One of: shared, auto
+	// One of: shared, auto, manual
 	// +optional
 	IOThreadsPolicy *IOThreadsPolicy `json:"ioThreadsPolicy,omitempty"`
+	// IOThreads explicitly defines the IOThreads of the vmi.
+	// Disks are assigned to them with their ioThread field.
+	// Requires the manual IOThreadsPolicy.
+	// +optional
+	IOThreads []IOThread `json:"ioThreads,omitempty"`
 	// Chassis specifies the chassis info passed to the domain.
 	// +optional
 	Chassis *Chassis `json:"chassis,omitempty"`
+	// LaunchSecurity configures the memory encryption of the vmi.
+	// +optional
+	LaunchSecurity *LaunchSecurity `json:"launchSecurity,omitempty"`
+}
+
+// IOThread defines an IOThread and its pinning.
+//
+// +k8s:openapi-gen=true
+type IOThread struct {
+	// ID of the IOThread. The IDs of all IOThreads have to form the range 1 to n.
+	ID uint32 `json:"id"`
+	// CPUs pins the IOThread to the host CPUs of the given vCPUs, e.g. "0-1,3".
+	// Requires dedicatedCpuPlacement.
+	// If omitted, the IOThread is not pinned.
+	// +optional
+	CPUs string `json:"cpus,omitempty"`
 }
 
 // Chassis specifies the chassis info passed to the domain.
@@ -196,6 +264,35 @@ type Chassis struct {
 	Sku          string `json:"sku,omitempty"`
 }
 
+// LaunchSecurity configures the memory encryption of the vmi.
+//
+// +k8s:openapi-gen=true
+type LaunchSecurity struct {
+	// SEV encrypts the memory of the vmi with AMD Secure Encrypted Virtualization.
+	// Requires EFI with SecureBoot disabled.
+	// +optional
+	SEV *SEV `json:"sev,omitempty"`
+}
+
+// SEV configures AMD Secure Encrypted Virtualization.
+//
+// +k8s:openapi-gen=true
+type SEV struct {
+	// Policy the guest owner requests from the platform.
+	// +optional
+	Policy *SEVPolicy `json:"policy,omitempty"`
+}
+
+// SEVPolicy defines the guarantees the guest owner requests from the platform.
+//
+// +k8s:openapi-gen=true
+type SEVPolicy struct {
+	// EncryptedState additionally encrypts the CPU register state of the guest (SEV-ES).
+	// Defaults to false
+	// +optional
+	EncryptedState *bool `json:"encryptedState,omitempty"`
+}
+
 // Represents the firmware blob used to assist in the domain creation process.
 // Used for setting the QEMU BIOS file path for the libvirt domain.
 //
@@ -225,6 +322,18 @@ type EFI struct {
 	// Defaults to true
 	// +optional
 	SecureBoot *bool `json:"secureBoot,omitempty"`
+	// If set to false, the guest starts with an empty SecureBoot key database
+	// (setup mode) instead of the keys enrolled in the OVMF vars template,
+	// which allows to enroll custom keys.
+	// Only applies if SecureBoot is enabled.
+	// Defaults to true
+	// +optional
+	EnrolledKeys *bool `json:"enrolledKeys,omitempty"`
+	// If set, the OVMF roms are taken from the given container image instead of
+	// the ones shipped with virt-launcher. Path is the directory containing the
+	// roms and defaults to /disk.
+	// +optional
+	ContainerDisk *ContainerDiskSource `json:"containerDisk,omitempty"`
 }
 
 //
@@ -275,6 +384,66 @@ type CPU struct {
 	// the emulator thread on it.
 	// +optional
 	IsolateEmulatorThread bool `json:"isolateEmulatorThread,omitempty"`
+	// EmulatorThreadPolicy controls on which pCPUs the emulator thread runs.
+	// One of: isolate, vcpu0, float
+	// isolate - one more dedicated pCPU is allocated for the emulator thread, same as IsolateEmulatorThread.
+	// vcpu0   - the emulator thread is pinned to the pCPU of vCPU 0.
+	// float   - the emulator thread may run on all pCPUs of the VMI.
+	// isolate and vcpu0 require DedicatedCPUPlacement.
+	// Defaults to isolate if IsolateEmulatorThread is set, float otherwise.
+	// +optional
+	EmulatorThreadPolicy *EmulatorThreadPolicy `json:"emulatorThreadPolicy,omitempty"`
+	// ThreadPolicy controls how the dedicated vCPUs are placed on the hyperthreads of the node.
+	// One of: fullCores, isolate
+	// fullCores - the vCPUs of a guest core are pinned to the sibling hyperthreads of a host core,
+	//             so that no other workload shares a core with the VMI. The guest threads per core
+	//             have to match the threads per core of the node.
+	// isolate   - every vCPU gets a host core of its own and the sibling hyperthreads stay idle.
+	//             Twice the pCPUs are allocated, which requires nodes with two threads per core.
+	// Requires DedicatedCPUPlacement and an explicit CPU topology.
+	// +optional
+	ThreadPolicy *CPUThreadPolicy `json:"threadPolicy,omitempty"`
+	// NUMA allows specifying settings for the guest NUMA topology.
+	// +optional
+	NUMA *NUMA `json:"numa,omitempty"`
+	// Realtime schedules the vCPUs of the VMI as realtime tasks with the fifo policy.
+	// The emulator thread and the IOThreads are kept away from the realtime vCPUs.
+	// Requires DedicatedCPUPlacement, hugepages and a node running a realtime kernel.
+	// +optional
+	Realtime *Realtime `json:"realtime,omitempty"`
+}
+
+// Realtime configures the realtime scheduling of the vCPUs.
+//
+// +k8s:openapi-gen=true
+type Realtime struct {
+	// Mask selects the realtime vCPUs, e.g. "0-1,3".
+	// Defaults to all vCPUs.
+	// +optional
+	Mask string `json:"mask,omitempty"`
+	// Priority of the realtime vCPUs, between 1 and 99.
+	// Defaults to 1
+	// +optional
+	Priority *uint32 `json:"priority,omitempty"`
+}
+
+// NUMA allows specifying settings for the guest NUMA topology.
+//
+// +k8s:openapi-gen=true
+type NUMA struct {
+	// GuestMappingPassthrough creates one guest NUMA node for every host NUMA node
+	// the dedicated pCPUs of the VMI are placed on. The vCPUs and the memory of a
+	// guest NUMA node never cross the boundaries of its host NUMA node.
+	// Requires DedicatedCPUPlacement and hugepages.
+	// +optional
+	GuestMappingPassthrough *NUMAGuestMappingPassthrough `json:"guestMappingPassthrough,omitempty"`
+}
+
+// NUMAGuestMappingPassthrough instructs KubeVirt to pass the host NUMA topology of
+// the dedicated pCPUs through to the guest.
+//
+// +k8s:openapi-gen=true
+type NUMAGuestMappingPassthrough struct {
 }
 
 // CPUFeature allows specifying a CPU feature.
@@ -355,11 +524,18 @@ type Devices struct {
 	// Whether to attach the default serial console or not.
 	// Serial console access will not be available if set to false. Defaults to true.
 	AutoattachSerialConsole *bool `json:"autoattachSerialConsole,omitempty"`
+	// If specified, the output of the serial console is logged continuously,
+	// not only while a console is connected. Requires the serial console.
+	// +optional
+	SerialConsoleLog *SerialConsoleLog `json:"serialConsoleLog,omitempty"`
 	// Whether to attach the Memory balloon device with default period.
 	// Period can be adjusted in virt-config.
 	// Defaults to true.
 	// +optional
 	AutoattachMemBalloon *bool `json:"autoattachMemBalloon,omitempty"`
+	// Options of the Memory balloon device, which are ignored if it is not attached.
+	// +optional
+	MemBalloon *MemBalloon `json:"memBalloon,omitempty"`
 	// Whether to have random number generator from host
 	// +optional
 	Rng *Rng `json:"rng,omitempty"`
@@ -369,12 +545,111 @@ type Devices struct {
 	// If specified, virtual network interfaces configured with a virtio bus will also enable the vhost multiqueue feature
 	// +optional
 	NetworkInterfaceMultiQueue *bool `json:"networkInterfaceMultiqueue,omitempty"`
+	// Number of queues of each virtio network interface, if NetworkInterfaceMultiQueue is enabled.
+	// Defaults to the number of vCPUs.
+	// +optional
+	NetworkInterfaceQueues *uint32 `json:"networkInterfaceQueues,omitempty"`
 	//Whether to attach a GPU device to the vmi.
 	// +optional
 	GPUs []GPU `json:"gpus,omitempty"`
 	//Whether to assign a QAT vf device to the vmi.
 	// +optional
 	QATs []QAT `json:"qats,omitempty"`
+	// Whether to assign host devices, which are permitted in the cluster config, to the vmi.
+	// +optional
+	HostDevices []HostDevice `json:"hostDevices,omitempty"`
+	// Whether to attach an emulated TPM 2.0 device to the vmi, as required by Windows 11.
+	// +optional
+	TPM *TPMDevice `json:"tpm,omitempty"`
+	// To configure and access client devices such as redirecting USB
+	// +optional
+	ClientPassthrough *ClientPassthroughDevices `json:"clientPassthrough,omitempty"`
+	// Filesystems describes filesystems which are shared with the vmi through virtio-fs.
+	// +optional
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+	// Whether to emulate a sound device.
+	// +optional
+	Sound *SoundDevice `json:"sound,omitempty"`
+	// Whether to attach a pvpanic device, through which the guest reports a kernel panic.
+	// +optional
+	Panic *PanicDevice `json:"panic,omitempty"`
+}
+
+// Filesystem shares the content of a volume with the vmi, without exposing it as a block device.
+//
+// +k8s:openapi-gen=true
+type Filesystem struct {
+	// Name is the device name, it must match the name of a ConfigMap, Secret or
+	// PersistentVolumeClaim volume. It is used as the mount tag in the guest.
+	Name string `json:"name"`
+	// Virtiofs is supported
+	Virtiofs *FilesystemVirtiofs `json:"virtiofs"`
+}
+
+// FilesystemVirtiofs shares the filesystem through a virtiofsd process in the virt-launcher pod.
+//
+// +k8s:openapi-gen=true
+type FilesystemVirtiofs struct{}
+
+// SoundDevice represents the user's configuration to emulate a sound card in the vmi.
+//
+// +k8s:openapi-gen=true
+type SoundDevice struct {
+	// User's defined name for this sound device
+	Name string `json:"name"`
+	// Model of the sound card, either ich9 or ac97.
+	// Defaults to ich9.
+	// +optional
+	Model string `json:"model,omitempty"`
+}
+
+// PanicDevice lets the guest report a kernel panic, like a Linux panic or a Windows bug check,
+// to the hypervisor. The VMI fails once its guest panicked.
+//
+// +k8s:openapi-gen=true
+type PanicDevice struct {
+	// If specified, the memory of a guest which panicked is dumped to this PersistentVolumeClaim
+	// before the VMI fails, for offline analysis. The claim must be large enough for the guest memory.
+	// +optional
+	CrashDumpClaimName string `json:"crashDumpClaimName,omitempty"`
+}
+
+// Represent a subset of client devices that can be accessed by VMI. At the
+// moment only, USB devices using Usbredir's library and tooling. Another fit
+// would be a smartcard with libcacard.
+//
+// The struct is currently empty as there is no immediate request for
+// user-facing APIs. This structure simply turns on USB redirection of
+// UsbClientPassthroughMaxNumberOf devices.
+//
+// +k8s:openapi-gen=true
+type ClientPassthroughDevices struct {
+}
+
+// UsbClientPassthroughMaxNumberOf is the number of USB devices which can be
+// redirected into a vmi at the same time.
+const UsbClientPassthroughMaxNumberOf = 4
+
+// TPMDevice represents a TPM 2.0 device, which is emulated by swtpm in the virt-launcher pod.
+//
+// +k8s:openapi-gen=true
+type TPMDevice struct {
+	// PersistentStateClaimName references a PersistentVolumeClaim in the same namespace, which
+	// keeps the state of the TPM across restarts of the vmi. The claim must not be used by a volume.
+	// If not set, the state of the TPM is lost when the vmi stops.
+	// +optional
+	PersistentStateClaimName string `json:"persistentStateClaimName,omitempty"`
+}
+
+// HostDevice represents a PCI device of the host which is passed through to the vmi.
+//
+// +k8s:openapi-gen=true
+type HostDevice struct {
+	// Name of the host device in the vmi
+	Name string `json:"name"`
+	// DeviceName is the resource name of the host device, as exposed by a device plugin.
+	// It must be listed in the permittedHostDevices of the cluster config.
+	DeviceName string `json:"deviceName"`
 }
 
 // ---
@@ -428,12 +703,65 @@ type Disk struct {
 	// Defaults to false.
 	// +optional
 	DedicatedIOThread *bool `json:"dedicatedIOThread,omitempty"`
+	// IOThread is the ID of the IOThread serving this disk.
+	// Only used with the manual IOThreadsPolicy.
+	// +optional
+	IOThread *uint32 `json:"ioThread,omitempty"`
 	// Cache specifies which kvm disk cache mode should be used.
 	// +optional
 	Cache DriverCache `json:"cache,omitempty"`
 	// If specified, disk address and its tag will be provided to the guest via config drive metadata
 	// +optional
 	Tag string `json:"tag,omitempty"`
+	// Queues tunes the virtio queues of the disk.
+	// Only supported with the virtio and scsi buses. The queues of scsi disks
+	// are set on the virtio-scsi controller they share.
+	// +optional
+	Queues *DiskQueues `json:"queues,omitempty"`
+	// IOTune limits the I/O operations and the throughput of the disk, so that
+	// the VMI can not starve the other users of a shared storage.
+	// +optional
+	IOTune *DiskIOTune `json:"ioTune,omitempty"`
+}
+
+// DiskQueues configures the virtio queues of a disk.
+//
+// +k8s:openapi-gen=true
+type DiskQueues struct {
+	// Count is the number of queues.
+	// Defaults to the number of vCPUs.
+	// +optional
+	Count *uint32 `json:"count,omitempty"`
+	// Size is the number of descriptors of each queue, a power of 2 between 2 and 1024.
+	// Only supported with the virtio bus.
+	// Defaults to the hypervisor default.
+	// +optional
+	Size *uint32 `json:"size,omitempty"`
+}
+
+// DiskIOTune limits the I/O of a disk. A total limit can not be combined with
+// the read and write limits of the same kind.
+//
+// +k8s:openapi-gen=true
+type DiskIOTune struct {
+	// TotalBytesSec limits the read and write throughput in bytes per second.
+	// +optional
+	TotalBytesSec *uint64 `json:"totalBytesSec,omitempty"`
+	// ReadBytesSec limits the read throughput in bytes per second.
+	// +optional
+	ReadBytesSec *uint64 `json:"readBytesSec,omitempty"`
+	// WriteBytesSec limits the write throughput in bytes per second.
+	// +optional
+	WriteBytesSec *uint64 `json:"writeBytesSec,omitempty"`
+	// TotalIopsSec limits the read and write operations per second.
+	// +optional
+	TotalIopsSec *uint64 `json:"totalIopsSec,omitempty"`
+	// ReadIopsSec limits the read operations per second.
+	// +optional
+	ReadIopsSec *uint64 `json:"readIopsSec,omitempty"`
+	// WriteIopsSec limits the write operations per second.
+	// +optional
+	WriteIopsSec *uint64 `json:"writeIopsSec,omitempty"`
 }
 
 // Represents the target of a volume to mount.
@@ -553,6 +881,15 @@ type VolumeSource struct {
 	// More info: https://cloudinit.readthedocs.io/en/latest/topics/datasources/configdrive.html
 	// +optional
 	CloudInitConfigDrive *CloudInitConfigDriveSource `json:"cloudInitConfigDrive,omitempty"`
+	// Ignition represents an Ignition config which is passed to the vmi on first boot.
+	// The volume must not be referenced by a disk. A proper Ignition installation is required inside the guest.
+	// More info: https://coreos.github.io/ignition/
+	// +optional
+	Ignition *IgnitionSource `json:"ignition,omitempty"`
+	// Sysprep represents a Sysprep answer file for the unattended installation of Windows guests.
+	// The answer file will be added as a CDROM to the vmi. It can not be used together with cloud-init volumes.
+	// +optional
+	Sysprep *SysprepSource `json:"sysprep,omitempty"`
 	// ContainerDisk references a docker image, embedding a qcow or raw disk.
 	// More info: https://kubevirt.gitbooks.io/user-guide/registry-disk.html
 	// +optional
@@ -560,6 +897,11 @@ type VolumeSource struct {
 	// Ephemeral is a special volume source that "wraps" specified source and provides copy-on-write image on top of it.
 	// +optional
 	Ephemeral *EphemeralVolumeSource `json:"ephemeral,omitempty"`
+	// GoldenImage references a ReadWriteMany or ReadOnlyMany PersistentVolumeClaim, which
+	// is attached read-only as the backing image of a copy-on-write overlay. Many vmis can
+	// use the same golden image at the same time, each one with its own overlay.
+	// +optional
+	GoldenImage *GoldenImageVolumeSource `json:"goldenImage,omitempty"`
 	// EmptyDisk represents a temporary disk which shares the vmis lifecycle.
 	// More info: https://kubevirt.gitbooks.io/user-guide/disks-and-volumes.html
 	// +optional
@@ -600,6 +942,14 @@ type EphemeralVolumeSource struct {
 	PersistentVolumeClaim *v1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
 }
 
+//
+// +k8s:openapi-gen=true
+type GoldenImageVolumeSource struct {
+	// ClaimName is the name of a PersistentVolumeClaim in the same namespace.
+	// The claim must have the ReadWriteMany or the ReadOnlyMany access mode.
+	ClaimName string `json:"claimName"`
+}
+
 // EmptyDisk represents a temporary disk which shares the vmis lifecycle.
 //
 // +k8s:openapi-gen=true
@@ -936,6 +1286,11 @@ const (
 	WatchdogActionReset WatchdogAction = "reset"
 	// WatchdogActionShutdown will shutdown the vmi if the watchdog gets triggered.
 	WatchdogActionShutdown WatchdogAction = "shutdown"
+	// WatchdogActionInjectNMI will inject a non-maskable interrupt into the vmi if the watchdog gets triggered.
+	WatchdogActionInjectNMI WatchdogAction = "inject-nmi"
+	// WatchdogActionRestartPod will stop the vmi if the watchdog gets triggered, so that its VirtualMachine
+	// restarts it in a new pod. The guest is paused until virt-handler stops it.
+	WatchdogActionRestartPod WatchdogAction = "restart-pod"
 )
 
 // Named watchdog device.
@@ -963,7 +1318,7 @@ type WatchdogDevice struct {
 //
 // +k8s:openapi-gen=true
 type I6300ESBWatchdog struct {
-	// The action to take. Valid values are poweroff, reset, shutdown.
+	// The action to take. Valid values are poweroff, reset, shutdown, inject-nmi, restart-pod.
 	// Defaults to reset.
 	Action WatchdogAction `json:"action,omitempty"`
 }
@@ -1001,6 +1356,85 @@ type Interface struct {
 	// If specified, the virtual network interface address and its tag will be provided to the guest via config drive
 	// +optional
 	Tag string `json:"tag,omitempty"`
+	// If specified, limits the connections the guest can track on the node through this interface.
+	// Only supported with the masquerade binding.
+	// +optional
+	ConnectionLimits *ConnectionLimits `json:"connectionLimits,omitempty"`
+	// If specified, addresses besides the ones of the interface the guest is allowed
+	// to send traffic from, e.g. the virtual addresses of a VRRP instance failing over
+	// between clustered guests. Only supported with the bridge and SR-IOV bindings.
+	// +optional
+	AllowedAddresses *AllowedAddresses `json:"allowedAddresses,omitempty"`
+	// If specified, limits the traffic the guest receives and sends through this interface.
+	// Only supported with the bridge and masquerade bindings.
+	// +optional
+	Bandwidth *InterfaceBandwidth `json:"bandwidth,omitempty"`
+	// If true, the IP addresses the interface got first are claimed and requested again
+	// for every migration target pod and after restarts of the VirtualMachine.
+	// Only supported with the bridge binding on multus networks, the IPAM of the network
+	// has to honor requested IP addresses. Guests behind the masquerade binding always
+	// keep the address of the VM network CIDR.
+	// +optional
+	PersistentIPs bool `json:"persistentIPs,omitempty"`
+}
+
+// Additional addresses a guest is allowed to use on an interface.
+//
+// +k8s:openapi-gen=true
+type AllowedAddresses struct {
+	// Unicast MAC addresses, e.g. the virtual MAC of a VRRP instance. The bridge
+	// binding drops the frames the guest sends from any other MAC address than these
+	// and the one of the interface. The virtual function of an SR-IOV interface keeps
+	// its spoof checking and is trusted, so that the guest can add filters for them.
+	// +optional
+	MACAddresses []string `json:"macAddresses,omitempty"`
+	// IP addresses or CIDRs, e.g. the virtual IP of a VRRP instance. Once an address
+	// of a family is listed, the bridge binding drops the packets the guest sends from
+	// any other address of that family than these and the one of the interface.
+	// Only supported with the bridge binding.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+}
+
+// Limits on the connection tracking entries an interface can use.
+// New connections exceeding one of the limits are dropped.
+//
+// +k8s:openapi-gen=true
+type ConnectionLimits struct {
+	// Maximum number of concurrently tracked connections.
+	// +optional
+	MaxConnections *uint32 `json:"maxConnections,omitempty"`
+	// Maximum number of new connections per second.
+	// +optional
+	MaxNewConnectionsPerSecond *uint32 `json:"maxNewConnectionsPerSecond,omitempty"`
+}
+
+// Rate limits on the traffic of an interface.
+// Traffic received by the guest above the limit is queued, traffic sent by the guest above the limit is dropped.
+//
+// +k8s:openapi-gen=true
+type InterfaceBandwidth struct {
+	// Limits the traffic received by the guest.
+	// +optional
+	Ingress *BandwidthLimit `json:"ingress,omitempty"`
+	// Limits the traffic sent by the guest.
+	// +optional
+	Egress *BandwidthLimit `json:"egress,omitempty"`
+}
+
+// Rate limit of one direction of the traffic of an interface.
+//
+// +k8s:openapi-gen=true
+type BandwidthLimit struct {
+	// Average rate in kibibytes per second.
+	Average uint32 `json:"average"`
+	// Maximum rate in kibibytes per second at which bursts can be received.
+	// Only supported for the ingress traffic.
+	// +optional
+	Peak *uint32 `json:"peak,omitempty"`
+	// Amount of kibibytes which can be transferred in a single burst.
+	// +optional
+	Burst *uint32 `json:"burst,omitempty"`
 }
 
 // Extra DHCP options to use in the interface.
@@ -1019,6 +1453,26 @@ type DHCPOptions struct {
 	// If specified will pass extra DHCP options for private use, range: 224-254
 	// +optional
 	PrivateOptions []DHCPPrivateOptions `json:"privateOptions,omitempty"`
+	// If specified will pass the MTU to the VM via DHCP option 26 instead of the MTU of the pod interface.
+	// Must not exceed the MTU of the pod interface.
+	// +optional
+	MTU *uint32 `json:"mtu,omitempty"`
+	// If specified will pass the routes to the VM via DHCP option 121 in addition to the routes of the pod interface.
+	// +optional
+	Routes []DHCPRoute `json:"routes,omitempty"`
+	// If specified will pass the search domains to the VM via DHCP option 119 instead of the search domains of the pod.
+	// +optional
+	SearchDomains []string `json:"searchDomains,omitempty"`
+}
+
+// A static route passed to the VM via DHCP.
+//
+// +k8s:openapi-gen=true
+type DHCPRoute struct {
+	// Destination network in CIDR notation, e.g. 10.10.0.0/16
+	Destination string `json:"destination"`
+	// IPv4 address of the gateway, it has to be reachable from the interface
+	Gateway string `json:"gateway"`
 }
 
 // DHCPExtraOptions defines Extra DHCP options for a VM.
@@ -1042,6 +1496,8 @@ type InterfaceBindingMethod struct {
 	Slirp      *InterfaceSlirp      `json:"slirp,omitempty"`
 	Masquerade *InterfaceMasquerade `json:"masquerade,omitempty"`
 	SRIOV      *InterfaceSRIOV      `json:"sriov,omitempty"`
+	Macvtap    *InterfaceMacvtap    `json:"macvtap,omitempty"`
+	Passt      *InterfacePasst      `json:"passt,omitempty"`
 }
 
 //
@@ -1058,7 +1514,21 @@ type InterfaceMasquerade struct{}
 
 //
 // +k8s:openapi-gen=true
-type InterfaceSRIOV struct{}
+type InterfaceMacvtap struct{}
+
+//
+// +k8s:openapi-gen=true
+type InterfacePasst struct{}
+
+//
+// +k8s:openapi-gen=true
+type InterfaceSRIOV struct {
+	// FailoverStandby is the name of a virtio interface with the bridge binding which carries the
+	// traffic of the guest while the VF is detached for a live migration. The guest bonds both
+	// interfaces with net_failover, they have to use the same MAC address.
+	// +optional
+	FailoverStandby string `json:"failoverStandby,omitempty"`
+}
 
 // Port repesents a port to expose from the virtual machine.
 // Default protocol TCP.
@@ -1111,12 +1581,42 @@ type PodNetwork struct {
 	VMNetworkCIDR string `json:"vmNetworkCIDR,omitempty"`
 }
 
+// SerialConsoleLog configures where the serial console output is logged to
+// and how the log files are rotated.
+//
+// +k8s:openapi-gen=true
+type SerialConsoleLog struct {
+	// Name of a persistentVolumeClaim volume of the vmi the log files are stored on.
+	// The volume must not be used by a disk and must have filesystem volume mode.
+	// If empty, the output is written to the virt-launcher pod logs.
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+	// Size a log file may grow to before it is rotated. Defaults to 2Mi.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Number of rotated log files which are kept in addition to the current one.
+	// Defaults to 3.
+	// +optional
+	MaxFiles *uint32 `json:"maxFiles,omitempty"`
+}
+
 // Rng represents the random device passed from host
 //
 // +k8s:openapi-gen=true
 type Rng struct {
 }
 
+// MemBalloon configures the Memory balloon device
+//
+// +k8s:openapi-gen=true
+type MemBalloon struct {
+	// FreePageReporting lets the guest report the memory pages it does not use,
+	// so that they are returned to the host.
+	// Defaults to the memBalloonFreePageReporting of the cluster config.
+	// +optional
+	FreePageReporting *bool `json:"freePageReporting,omitempty"`
+}
+
 // Represents the multus cni network.
 //
 // +k8s:openapi-gen=true
@@ -1129,4 +1629,33 @@ type MultusNetwork struct {
 	// Select the default network and add it to the
 	// multus-cni.io/default-network annotation.
 	Default bool `json:"default,omitempty"`
+
+	// If specified, the referenced NetworkAttachmentDefinition is expected
+	// to configure an OVN-Kubernetes secondary network with the given
+	// topology and VLAN, and the VMI will not start otherwise.
+	// +optional
+	OVN *OVNNetwork `json:"ovn,omitempty"`
+}
+
+// Represents the binding of an OVN-Kubernetes secondary network.
+//
+// +k8s:openapi-gen=true
+type OVNNetwork struct {
+	// Topology of the OVN-Kubernetes network. One of localnet, layer2.
+	Topology OVNTopology `json:"topology"`
+
+	// VLAN ID the traffic of the network is tagged with on the physical
+	// network. Only supported with the localnet topology.
+	// +optional
+	VLAN *int32 `json:"vlan,omitempty"`
 }
+
+// OVNTopology is the topology of an OVN-Kubernetes secondary network.
+type OVNTopology string
+
+const (
+	// The network is connected to a physical network of the nodes
+	OVNTopologyLocalnet OVNTopology = "localnet"
+	// The network is an overlay spanning all the nodes
+	OVNTopologyLayer2 OVNTopology = "layer2"
+)
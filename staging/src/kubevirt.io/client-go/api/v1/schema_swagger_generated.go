@@ -60,6 +60,23 @@ func (CloudInitConfigDriveSource) SwaggerDoc() map[string]string {
 	}
 }
 
+func (IgnitionSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "Represents an Ignition config source for Fedora CoreOS and RHCOS guests.\nThe config is passed to the guest through the opt/com.coreos/config firmware configuration entry.\nMore info: https://coreos.github.io/ignition/\n\n+k8s:openapi-gen=true",
+		"secretRef":    "SecretRef references a k8s secret that contains the Ignition config under the config.ign key.\n+ optional",
+		"configMapRef": "ConfigMapRef references a k8s config map that contains the Ignition config under the config.ign key.\n+ optional",
+		"data":         "Data contains the inline Ignition config.\n+ optional",
+	}
+}
+
+func (SysprepSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "Represents a Sysprep answer file source for the unattended installation of Windows guests.\nThe answer file is added as a CDROM to the vmi, where Windows Setup looks for it.\nMore info: https://docs.microsoft.com/en-us/windows-hardware/manufacture/desktop/windows-setup-automation-overview\n\n+k8s:openapi-gen=true",
+		"secret":    "Secret references a k8s secret that contains the answer file under the autounattend.xml or unattend.xml key.\n+ optional",
+		"configMap": "ConfigMap references a k8s config map that contains the answer file under the autounattend.xml or unattend.xml key.\n+ optional",
+	}
+}
+
 func (DomainSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                "+k8s:openapi-gen=true",
@@ -71,8 +88,18 @@ func (DomainSpec) SwaggerDoc() map[string]string {
 		"clock":           "Clock sets the clock and timers of the vmi.\n+optional",
 		"features":        "Features like acpi, apic, hyperv, smm.\n+optional",
 		"devices":         "Devices allows adding disks, network interfaces, and others",
-		"ioThreadsPolicy": "Controls whether or not disks will share IOThreads.\nOmitting IOThreadsPolicy disables use of IOThreads.\nOne of: shared, auto\n+optional",
+		"ioThreadsPolicy": "Controls whether or not disks will share IOThreads.\nOmitting IOThreadsPolicy disables use of IOThreads.\nOne of: shared, auto, manual\n+optional",
+		"ioThreads":       "IOThreads explicitly defines the IOThreads of the vmi.\nDisks are assigned to them with their ioThread field.\nRequires the manual IOThreadsPolicy.\n+optional",
 		"chassis":         "Chassis specifies the chassis info passed to the domain.\n+optional",
+		"launchSecurity":  "LaunchSecurity configures the memory encryption of the vmi.\n+optional",
+	}
+}
+
+func (IOThread) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":     "IOThread defines an IOThread and its pinning.\n\n+k8s:openapi-gen=true",
+		"id":   "ID of the IOThread. The IDs of all IOThreads have to form the range 1 to n.",
+		"cpus": "CPUs pins the IOThread to the host CPUs of the given vCPUs, e.g. \"0-1,3\".\nRequires dedicatedCpuPlacement.\nIf omitted, the IOThread is not pinned.\n+optional",
 	}
 }
 
@@ -82,6 +109,27 @@ func (Chassis) SwaggerDoc() map[string]string {
 	}
 }
 
+func (LaunchSecurity) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":    "LaunchSecurity configures the memory encryption of the vmi.\n\n+k8s:openapi-gen=true",
+		"sev": "SEV encrypts the memory of the vmi with AMD Secure Encrypted Virtualization.\nRequires EFI with SecureBoot disabled.\n+optional",
+	}
+}
+
+func (SEV) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "SEV configures AMD Secure Encrypted Virtualization.\n\n+k8s:openapi-gen=true",
+		"policy": "Policy the guest owner requests from the platform.\n+optional",
+	}
+}
+
+func (SEVPolicy) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "SEVPolicy defines the guarantees the guest owner requests from the platform.\n\n+k8s:openapi-gen=true",
+		"encryptedState": "EncryptedState additionally encrypts the CPU register state of the guest (SEV-ES).\nDefaults to false\n+optional",
+	}
+}
+
 func (Bootloader) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":     "Represents the firmware blob used to assist in the domain creation process.\nUsed for setting the QEMU BIOS file path for the libvirt domain.\n\n+k8s:openapi-gen=true",
@@ -98,8 +146,10 @@ func (BIOS) SwaggerDoc() map[string]string {
 
 func (EFI) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":           "If set, EFI will be used instead of BIOS.\n\n+k8s:openapi-gen=true",
-		"secureBoot": "If set, SecureBoot will be enabled and the OVMF roms will be swapped for\nSecureBoot-enabled ones.\nRequires SMM to be enabled.\nDefaults to true\n+optional",
+		"":              "If set, EFI will be used instead of BIOS.\n\n+k8s:openapi-gen=true",
+		"secureBoot":    "If set, SecureBoot will be enabled and the OVMF roms will be swapped for\nSecureBoot-enabled ones.\nRequires SMM to be enabled.\nDefaults to true\n+optional",
+		"enrolledKeys":  "If set to false, the guest starts with an empty SecureBoot key database\n(setup mode) instead of the keys enrolled in the OVMF vars template,\nwhich allows to enroll custom keys.\nOnly applies if SecureBoot is enabled.\nDefaults to true\n+optional",
+		"containerDisk": "If set, the OVMF roms are taken from the given container image instead of\nthe ones shipped with virt-launcher. Path is the directory containing the\nroms and defaults to /disk.\n+optional",
 	}
 }
 
@@ -122,6 +172,31 @@ func (CPU) SwaggerDoc() map[string]string {
 		"features":              "Features specifies the CPU features list inside the VMI.\n+optional",
 		"dedicatedCpuPlacement": "DedicatedCPUPlacement requests the scheduler to place the VirtualMachineInstance on a node\nwith enough dedicated pCPUs and pin the vCPUs to it.\n+optional",
 		"isolateEmulatorThread": "IsolateEmulatorThread requests one more dedicated pCPU to be allocated for the VMI to place\nthe emulator thread on it.\n+optional",
+		"emulatorThreadPolicy":  "EmulatorThreadPolicy controls on which pCPUs the emulator thread runs.\nOne of: isolate, vcpu0, float\nisolate - one more dedicated pCPU is allocated for the emulator thread, same as IsolateEmulatorThread.\nvcpu0   - the emulator thread is pinned to the pCPU of vCPU 0.\nfloat   - the emulator thread may run on all pCPUs of the VMI.\nisolate and vcpu0 require DedicatedCPUPlacement.\nDefaults to isolate if IsolateEmulatorThread is set, float otherwise.\n+optional",
+		"threadPolicy":          "ThreadPolicy controls how the dedicated vCPUs are placed on the hyperthreads of the node.\nOne of: fullCores, isolate\nfullCores - the vCPUs of a guest core are pinned to the sibling hyperthreads of a host core,\n            so that no other workload shares a core with the VMI. The guest threads per core\n            have to match the threads per core of the node.\nisolate   - every vCPU gets a host core of its own and the sibling hyperthreads stay idle.\n            Twice the pCPUs are allocated, which requires nodes with two threads per core.\nRequires DedicatedCPUPlacement and an explicit CPU topology.\n+optional",
+		"numa":                  "NUMA allows specifying settings for the guest NUMA topology.\n+optional",
+		"realtime":              "Realtime schedules the vCPUs of the VMI as realtime tasks with the fifo policy.\nThe emulator thread and the IOThreads are kept away from the realtime vCPUs.\nRequires DedicatedCPUPlacement, hugepages and a node running a realtime kernel.\n+optional",
+	}
+}
+
+func (Realtime) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "Realtime configures the realtime scheduling of the vCPUs.\n\n+k8s:openapi-gen=true",
+		"mask":     "Mask selects the realtime vCPUs, e.g. \"0-1,3\".\nDefaults to all vCPUs.\n+optional",
+		"priority": "Priority of the realtime vCPUs, between 1 and 99.\nDefaults to 1\n+optional",
+	}
+}
+
+func (NUMA) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "NUMA allows specifying settings for the guest NUMA topology.\n\n+k8s:openapi-gen=true",
+		"guestMappingPassthrough": "GuestMappingPassthrough creates one guest NUMA node for every host NUMA node\nthe dedicated pCPUs of the VMI are placed on. The vCPUs and the memory of a\nguest NUMA node never cross the boundaries of its host NUMA node.\nRequires DedicatedCPUPlacement and hugepages.\n+optional",
+	}
+}
+
+func (NUMAGuestMappingPassthrough) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "NUMAGuestMappingPassthrough instructs KubeVirt to pass the host NUMA topology of\nthe dedicated pCPUs through to the guest.\n\n+k8s:openapi-gen=true",
 	}
 }
 
@@ -174,12 +249,71 @@ func (Devices) SwaggerDoc() map[string]string {
 		"autoattachPodInterface":     "Whether to attach a pod network interface. Defaults to true.",
 		"autoattachGraphicsDevice":   "Whether to attach the default graphics device or not.\nVNC will not be available if set to false. Defaults to true.",
 		"autoattachSerialConsole":    "Whether to attach the default serial console or not.\nSerial console access will not be available if set to false. Defaults to true.",
+		"serialConsoleLog":           "If specified, the output of the serial console is logged continuously,\nnot only while a console is connected. Requires the serial console.\n+optional",
 		"autoattachMemBalloon":       "Whether to attach the Memory balloon device with default period.\nPeriod can be adjusted in virt-config.\nDefaults to true.\n+optional",
+		"memBalloon":                 "Options of the Memory balloon device, which are ignored if it is not attached.\n+optional",
 		"rng":                        "Whether to have random number generator from host\n+optional",
 		"blockMultiQueue":            "Whether or not to enable virtio multi-queue for block devices\n+optional",
 		"networkInterfaceMultiqueue": "If specified, virtual network interfaces configured with a virtio bus will also enable the vhost multiqueue feature\n+optional",
+		"networkInterfaceQueues":     "Number of queues of each virtio network interface, if NetworkInterfaceMultiQueue is enabled.\nDefaults to the number of vCPUs.\n+optional",
 		"gpus":                       "Whether to attach a GPU device to the vmi.\n+optional",
 		"qats":                       "Whether to assign a QAT vf device to the vmi.\n+optional",
+		"hostDevices":                "Whether to assign host devices, which are permitted in the cluster config, to the vmi.\n+optional",
+		"tpm":                        "Whether to attach an emulated TPM 2.0 device to the vmi, as required by Windows 11.\n+optional",
+		"clientPassthrough":          "To configure and access client devices such as redirecting USB\n+optional",
+		"filesystems":                "Filesystems describes filesystems which are shared with the vmi through virtio-fs.\n+optional",
+		"sound":                      "Whether to emulate a sound device.\n+optional",
+		"panic":                      "Whether to attach a pvpanic device, through which the guest reports a kernel panic.\n+optional",
+	}
+}
+
+func (Filesystem) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "Filesystem shares the content of a volume with the vmi, without exposing it as a block device.\n\n+k8s:openapi-gen=true",
+		"name":     "Name is the device name, it must match the name of a ConfigMap, Secret or\nPersistentVolumeClaim volume. It is used as the mount tag in the guest.",
+		"virtiofs": "Virtiofs is supported",
+	}
+}
+
+func (FilesystemVirtiofs) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "FilesystemVirtiofs shares the filesystem through a virtiofsd process in the virt-launcher pod.\n\n+k8s:openapi-gen=true",
+	}
+}
+
+func (SoundDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "SoundDevice represents the user's configuration to emulate a sound card in the vmi.\n\n+k8s:openapi-gen=true",
+		"name":  "User's defined name for this sound device",
+		"model": "Model of the sound card, either ich9 or ac97.\nDefaults to ich9.\n+optional",
+	}
+}
+
+func (PanicDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "PanicDevice lets the guest report a kernel panic, like a Linux panic or a Windows bug check,\nto the hypervisor. The VMI fails once its guest panicked.\n\n+k8s:openapi-gen=true",
+		"crashDumpClaimName": "If specified, the memory of a guest which panicked is dumped to this PersistentVolumeClaim\nbefore the VMI fails, for offline analysis. The claim must be large enough for the guest memory.\n+optional",
+	}
+}
+
+func (ClientPassthroughDevices) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "Represent a subset of client devices that can be accessed by VMI. At the\nmoment only, USB devices using Usbredir's library and tooling. Another fit\nwould be a smartcard with libcacard.\n\nThe struct is currently empty as there is no immediate request for\nuser-facing APIs. This structure simply turns on USB redirection of\nUsbClientPassthroughMaxNumberOf devices.\n\n+k8s:openapi-gen=true",
+	}
+}
+
+func (TPMDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                         "TPMDevice represents a TPM 2.0 device, which is emulated by swtpm in the virt-launcher pod.\n\n+k8s:openapi-gen=true",
+		"persistentStateClaimName": "PersistentStateClaimName references a PersistentVolumeClaim in the same namespace, which\nkeeps the state of the TPM across restarts of the vmi. The claim must not be used by a volume.\nIf not set, the state of the TPM is lost when the vmi stops.\n+optional",
+	}
+}
+
+func (HostDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "HostDevice represents a PCI device of the host which is passed through to the vmi.\n\n+k8s:openapi-gen=true",
+		"name":       "Name of the host device in the vmi",
+		"deviceName": "DeviceName is the resource name of the host device, as exposed by a device plugin.\nIt must be listed in the permittedHostDevices of the cluster config.",
 	}
 }
 
@@ -212,8 +346,31 @@ func (Disk) SwaggerDoc() map[string]string {
 		"bootOrder":         "BootOrder is an integer value > 0, used to determine ordering of boot devices.\nLower values take precedence.\nEach disk or interface that has a boot order must have a unique value.\nDisks without a boot order are not tried if a disk with a boot order exists.\n+optional",
 		"serial":            "Serial provides the ability to specify a serial number for the disk device.\n+optional",
 		"dedicatedIOThread": "dedicatedIOThread indicates this disk should have an exclusive IO Thread.\nEnabling this implies useIOThreads = true.\nDefaults to false.\n+optional",
+		"ioThread":          "IOThread is the ID of the IOThread serving this disk.\nOnly used with the manual IOThreadsPolicy.\n+optional",
 		"cache":             "Cache specifies which kvm disk cache mode should be used.\n+optional",
 		"tag":               "If specified, disk address and its tag will be provided to the guest via config drive metadata\n+optional",
+		"queues":            "Queues tunes the virtio queues of the disk.\nOnly supported with the virtio and scsi buses. The queues of scsi disks\nare set on the virtio-scsi controller they share.\n+optional",
+		"ioTune":            "IOTune limits the I/O operations and the throughput of the disk, so that\nthe VMI can not starve the other users of a shared storage.\n+optional",
+	}
+}
+
+func (DiskIOTune) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "DiskIOTune limits the I/O of a disk. A total limit can not be combined with\nthe read and write limits of the same kind.\n\n+k8s:openapi-gen=true",
+		"totalBytesSec": "TotalBytesSec limits the read and write throughput in bytes per second.\n+optional",
+		"readBytesSec":  "ReadBytesSec limits the read throughput in bytes per second.\n+optional",
+		"writeBytesSec": "WriteBytesSec limits the write throughput in bytes per second.\n+optional",
+		"totalIopsSec":  "TotalIopsSec limits the read and write operations per second.\n+optional",
+		"readIopsSec":   "ReadIopsSec limits the read operations per second.\n+optional",
+		"writeIopsSec":  "WriteIopsSec limits the write operations per second.\n+optional",
+	}
+}
+
+func (DiskQueues) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":      "DiskQueues configures the virtio queues of a disk.\n\n+k8s:openapi-gen=true",
+		"count": "Count is the number of queues.\nDefaults to the number of vCPUs.\n+optional",
+		"size":  "Size is the number of descriptors of each queue, a power of 2 between 2 and 1024.\nOnly supported with the virtio bus.\nDefaults to the hypervisor default.\n+optional",
 	}
 }
 
@@ -275,8 +432,11 @@ func (VolumeSource) SwaggerDoc() map[string]string {
 		"persistentVolumeClaim": "PersistentVolumeClaimVolumeSource represents a reference to a PersistentVolumeClaim in the same namespace.\nDirectly attached to the vmi via qemu.\nMore info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#persistentvolumeclaims\n+optional",
 		"cloudInitNoCloud":      "CloudInitNoCloud represents a cloud-init NoCloud user-data source.\nThe NoCloud data will be added as a disk to the vmi. A proper cloud-init installation is required inside the guest.\nMore info: http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html\n+optional",
 		"cloudInitConfigDrive":  "CloudInitConfigDrive represents a cloud-init Config Drive user-data source.\nThe Config Drive data will be added as a disk to the vmi. A proper cloud-init installation is required inside the guest.\nMore info: https://cloudinit.readthedocs.io/en/latest/topics/datasources/configdrive.html\n+optional",
+		"ignition":              "Ignition represents an Ignition config which is passed to the vmi on first boot.\nThe volume must not be referenced by a disk. A proper Ignition installation is required inside the guest.\nMore info: https://coreos.github.io/ignition/\n+optional",
+		"sysprep":               "Sysprep represents a Sysprep answer file for the unattended installation of Windows guests.\nThe answer file will be added as a CDROM to the vmi. It can not be used together with cloud-init volumes.\n+optional",
 		"containerDisk":         "ContainerDisk references a docker image, embedding a qcow or raw disk.\nMore info: https://kubevirt.gitbooks.io/user-guide/registry-disk.html\n+optional",
 		"ephemeral":             "Ephemeral is a special volume source that \"wraps\" specified source and provides copy-on-write image on top of it.\n+optional",
+		"goldenImage":           "GoldenImage references a ReadWriteMany or ReadOnlyMany PersistentVolumeClaim, which\nis attached read-only as the backing image of a copy-on-write overlay. Many vmis can\nuse the same golden image at the same time, each one with its own overlay.\n+optional",
 		"emptyDisk":             "EmptyDisk represents a temporary disk which shares the vmis lifecycle.\nMore info: https://kubevirt.gitbooks.io/user-guide/disks-and-volumes.html\n+optional",
 		"dataVolume":            "DataVolume represents the dynamic creation a PVC for this volume as well as\nthe process of populating that PVC with a disk image.\n+optional",
 		"configMap":             "ConfigMapSource represents a reference to a ConfigMap in the same namespace.\nMore info: https://kubernetes.io/docs/tasks/configure-pod-container/configure-pod-configmap/\n+optional",
@@ -299,6 +459,13 @@ func (EphemeralVolumeSource) SwaggerDoc() map[string]string {
 	}
 }
 
+func (GoldenImageVolumeSource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "+k8s:openapi-gen=true",
+		"claimName": "ClaimName is the name of a PersistentVolumeClaim in the same namespace.\nThe claim must have the ReadWriteMany or the ReadOnlyMany access mode.",
+	}
+}
+
 func (EmptyDiskSource) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":         "EmptyDisk represents a temporary disk which shares the vmis lifecycle.\n\n+k8s:openapi-gen=true",
@@ -466,21 +633,58 @@ func (WatchdogDevice) SwaggerDoc() map[string]string {
 func (I6300ESBWatchdog) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":       "i6300esb watchdog device.\n\n+k8s:openapi-gen=true",
-		"action": "The action to take. Valid values are poweroff, reset, shutdown.\nDefaults to reset.",
+		"action": "The action to take. Valid values are poweroff, reset, shutdown, inject-nmi, restart-pod.\nDefaults to reset.",
 	}
 }
 
 func (Interface) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":            "+k8s:openapi-gen=true",
-		"name":        "Logical name of the interface as well as a reference to the associated networks.\nMust match the Name of a Network.",
-		"model":       "Interface model.\nOne of: e1000, e1000e, ne2k_pci, pcnet, rtl8139, virtio.\nDefaults to virtio.",
-		"ports":       "List of ports to be forwarded to the virtual machine.",
-		"macAddress":  "Interface MAC address. For example: de:ad:00:00:be:af or DE-AD-00-00-BE-AF.",
-		"bootOrder":   "BootOrder is an integer value > 0, used to determine ordering of boot devices.\nLower values take precedence.\nEach interface or disk that has a boot order must have a unique value.\nInterfaces without a boot order are not tried.\n+optional",
-		"pciAddress":  "If specified, the virtual network interface will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10\n+optional",
-		"dhcpOptions": "If specified the network interface will pass additional DHCP options to the VMI\n+optional",
-		"tag":         "If specified, the virtual network interface address and its tag will be provided to the guest via config drive\n+optional",
+		"":                 "+k8s:openapi-gen=true",
+		"name":             "Logical name of the interface as well as a reference to the associated networks.\nMust match the Name of a Network.",
+		"model":            "Interface model.\nOne of: e1000, e1000e, ne2k_pci, pcnet, rtl8139, virtio.\nDefaults to virtio.",
+		"ports":            "List of ports to be forwarded to the virtual machine.",
+		"macAddress":       "Interface MAC address. For example: de:ad:00:00:be:af or DE-AD-00-00-BE-AF.",
+		"bootOrder":        "BootOrder is an integer value > 0, used to determine ordering of boot devices.\nLower values take precedence.\nEach interface or disk that has a boot order must have a unique value.\nInterfaces without a boot order are not tried.\n+optional",
+		"pciAddress":       "If specified, the virtual network interface will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10\n+optional",
+		"dhcpOptions":      "If specified the network interface will pass additional DHCP options to the VMI\n+optional",
+		"tag":              "If specified, the virtual network interface address and its tag will be provided to the guest via config drive\n+optional",
+		"connectionLimits": "If specified, limits the connections the guest can track on the node through this interface.\nOnly supported with the masquerade binding.\n+optional",
+		"allowedAddresses": "If specified, addresses besides the ones of the interface the guest is allowed\nto send traffic from, e.g. the virtual addresses of a VRRP instance failing over\nbetween clustered guests. Only supported with the bridge and SR-IOV bindings.\n+optional",
+		"bandwidth":        "If specified, limits the traffic the guest receives and sends through this interface.\nOnly supported with the bridge and masquerade bindings.\n+optional",
+		"persistentIPs":    "If true, the IP addresses the interface got first are claimed and requested again\nfor every migration target pod and after restarts of the VirtualMachine.\nOnly supported with the bridge binding on multus networks, the IPAM of the network\nhas to honor requested IP addresses. Guests behind the masquerade binding always\nkeep the address of the VM network CIDR.\n+optional",
+	}
+}
+
+func (AllowedAddresses) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "Additional addresses a guest is allowed to use on an interface.\n\n+k8s:openapi-gen=true",
+		"macAddresses": "Unicast MAC addresses, e.g. the virtual MAC of a VRRP instance. The bridge\nbinding drops the frames the guest sends from any other MAC address than these\nand the one of the interface. The virtual function of an SR-IOV interface keeps\nits spoof checking and is trusted, so that the guest can add filters for them.\n+optional",
+		"ipAddresses":  "IP addresses or CIDRs, e.g. the virtual IP of a VRRP instance. Once an address\nof a family is listed, the bridge binding drops the packets the guest sends from\nany other address of that family than these and the one of the interface.\nOnly supported with the bridge binding.\n+optional",
+	}
+}
+
+func (ConnectionLimits) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                           "Limits on the connection tracking entries an interface can use.\nNew connections exceeding one of the limits are dropped.\n\n+k8s:openapi-gen=true",
+		"maxConnections":             "Maximum number of concurrently tracked connections.\n+optional",
+		"maxNewConnectionsPerSecond": "Maximum number of new connections per second.\n+optional",
+	}
+}
+
+func (InterfaceBandwidth) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "Rate limits on the traffic of an interface.\nTraffic received by the guest above the limit is queued, traffic sent by the guest above the limit is dropped.\n\n+k8s:openapi-gen=true",
+		"ingress": "Limits the traffic received by the guest.\n+optional",
+		"egress":  "Limits the traffic sent by the guest.\n+optional",
+	}
+}
+
+func (BandwidthLimit) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "Rate limit of one direction of the traffic of an interface.\n\n+k8s:openapi-gen=true",
+		"average": "Average rate in kibibytes per second.",
+		"peak":    "Maximum rate in kibibytes per second at which bursts can be received.\nOnly supported for the ingress traffic.\n+optional",
+		"burst":   "Amount of kibibytes which can be transferred in a single burst.\n+optional",
 	}
 }
 
@@ -491,6 +695,9 @@ func (DHCPOptions) SwaggerDoc() map[string]string {
 		"tftpServerName": "If specified will pass option 66 to interface's DHCP server\n+optional",
 		"ntpServers":     "If specified will pass the configured NTP server to the VM via DHCP option 042.\n+optional",
 		"privateOptions": "If specified will pass extra DHCP options for private use, range: 224-254\n+optional",
+		"mtu":            "If specified will pass the MTU to the VM via DHCP option 26 instead of the MTU of the pod interface.\nMust not exceed the MTU of the pod interface.\n+optional",
+		"routes":         "If specified will pass the routes to the VM via DHCP option 121 in addition to the routes of the pod interface.\n+optional",
+		"searchDomains":  "If specified will pass the search domains to the VM via DHCP option 119 instead of the search domains of the pod.\n+optional",
 	}
 }
 
@@ -502,6 +709,14 @@ func (DHCPPrivateOptions) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DHCPRoute) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "A static route passed to the VM via DHCP.\n\n+k8s:openapi-gen=true",
+		"destination": "Destination network in CIDR notation, e.g. 10.10.0.0/16",
+		"gateway":     "IPv4 address of the gateway, it has to be reachable from the interface",
+	}
+}
+
 func (InterfaceBindingMethod) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "Represents the method which will be used to connect the interface to the guest.\nOnly one of its members may be specified.\n\n+k8s:openapi-gen=true",
@@ -526,12 +741,25 @@ func (InterfaceMasquerade) SwaggerDoc() map[string]string {
 	}
 }
 
-func (InterfaceSRIOV) SwaggerDoc() map[string]string {
+func (InterfaceMacvtap) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "+k8s:openapi-gen=true",
 	}
 }
 
+func (InterfacePasst) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "+k8s:openapi-gen=true",
+	}
+}
+
+func (InterfaceSRIOV) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                "+k8s:openapi-gen=true",
+		"failoverStandby": "FailoverStandby is the name of a virtio interface with the bridge binding which carries the\ntraffic of the guest while the VF is detached for a live migration. The guest bonds both\ninterfaces with net_failover, they have to use the same MAC address.\n+optional",
+	}
+}
+
 func (Port) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":         "Port repesents a port to expose from the virtual machine.\nDefault protocol TCP.\nThe port field is mandatory\n\n+k8s:openapi-gen=true",
@@ -561,16 +789,41 @@ func (PodNetwork) SwaggerDoc() map[string]string {
 	}
 }
 
+func (SerialConsoleLog) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "SerialConsoleLog configures where the serial console output is logged to\nand how the log files are rotated.\n\n+k8s:openapi-gen=true",
+		"volumeName": "Name of a persistentVolumeClaim volume of the vmi the log files are stored on.\nThe volume must not be used by a disk and must have filesystem volume mode.\nIf empty, the output is written to the virt-launcher pod logs.\n+optional",
+		"maxSize":    "Size a log file may grow to before it is rotated. Defaults to 2Mi.\n+optional",
+		"maxFiles":   "Number of rotated log files which are kept in addition to the current one.\nDefaults to 3.\n+optional",
+	}
+}
+
 func (Rng) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "Rng represents the random device passed from host\n\n+k8s:openapi-gen=true",
 	}
 }
 
+func (MemBalloon) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "MemBalloon configures the Memory balloon device\n\n+k8s:openapi-gen=true",
+		"freePageReporting": "FreePageReporting lets the guest report the memory pages it does not use,\nso that they are returned to the host.\nDefaults to the memBalloonFreePageReporting of the cluster config.\n+optional",
+	}
+}
+
 func (MultusNetwork) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":            "Represents the multus cni network.\n\n+k8s:openapi-gen=true",
 		"networkName": "References to a NetworkAttachmentDefinition CRD object. Format:\n<networkName>, <namespace>/<networkName>. If namespace is not\nspecified, VMI namespace is assumed.",
 		"default":     "Select the default network and add it to the\nmultus-cni.io/default-network annotation.",
+		"ovn":         "If specified, the referenced NetworkAttachmentDefinition is expected\nto configure an OVN-Kubernetes secondary network with the given\ntopology and VLAN, and the VMI will not start otherwise.\n+optional",
+	}
+}
+
+func (OVNNetwork) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "Represents the binding of an OVN-Kubernetes secondary network.\n\n+k8s:openapi-gen=true",
+		"topology": "Topology of the OVN-Kubernetes network. One of localnet, layer2.",
+		"vlan":     "VLAN ID the traffic of the network is tagged with on the physical\nnetwork. Only supported with the localnet topology.\n+optional",
 	}
 }
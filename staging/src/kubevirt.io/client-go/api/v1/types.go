@@ -103,9 +103,16 @@ type VirtualMachineInstanceSpec struct {
 	SchedulerName string `json:"schedulerName,omitempty"`
 	// If toleration is specified, obey all the toleration rules.
 	Tolerations []k8sv1.Toleration `json:"tolerations,omitempty"`
+	// TopologySpreadConstraints describes how a group of VMIs ought to spread across topology
+	// domains, like zones. They are passed to the virt-launcher pod as they are.
+	// +optional
+	TopologySpreadConstraints []k8sv1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
-	// EvictionStrategy can be set to "LiveMigrate" if the VirtualMachineInstance should be
-	// migrated instead of shut-off in case of a node drain.
+	// EvictionStrategy describes what happens to the VirtualMachineInstance when its pod is evicted,
+	// for instance during a node drain. "LiveMigrate" blocks the eviction and migrates the
+	// VirtualMachineInstance, "LiveMigrateIfPossible" does the same but lets the eviction shut it off
+	// if it is not migratable, "External" blocks the eviction and leaves the evacuation to an
+	// external controller, and "None" lets the eviction shut it off.
 	//
 	// +optional
 	EvictionStrategy *EvictionStrategy `json:"evictionStrategy,omitempty"`
@@ -150,6 +157,18 @@ type VirtualMachineInstanceSpec struct {
 	// configuration based on DNSPolicy.
 	// +optional
 	DNSConfig *k8sv1.PodDNSConfig `json:"dnsConfig,omitempty" protobuf:"bytes,26,opt,name=dnsConfig"`
+	// If specified, the qcow2 overlays of the disks are periodically compacted while the
+	// VirtualMachineInstance is running, by trimming the unused space of the guest back to the storage.
+	// +optional
+	DiskCompaction *DiskCompaction `json:"diskCompaction,omitempty"`
+	// Actions that virt-launcher takes in response to lifecycle events of the VirtualMachineInstance.
+	// +optional
+	Lifecycle *VirtualMachineInstanceLifecycle `json:"lifecycle,omitempty"`
+
+	// How virt-handler recovers the VirtualMachineInstance when qemu paused it because of an I/O error,
+	// like a full or unreachable storage.
+	// +optional
+	IOErrorRecovery *IOErrorRecovery `json:"ioErrorRecovery,omitempty"`
 }
 
 // VirtualMachineInstanceStatus represents information about the status of a VirtualMachineInstance. Status may trail the actual
@@ -170,6 +189,9 @@ type VirtualMachineInstanceStatus struct {
 	Interfaces []VirtualMachineInstanceNetworkInterface `json:"interfaces,omitempty"`
 	// Guest OS Information
 	GuestOSInfo VirtualMachineInstanceGuestOSInfo `json:"guestOSInfo,omitempty"`
+	// Hostname of the guest as reported by the guest agent
+	// +optional
+	GuestHostname string `json:"guestHostname,omitempty"`
 	// Represents the status of a live migration
 	MigrationState *VirtualMachineInstanceMigrationState `json:"migrationState,omitempty"`
 	// Represents the method using which the vmi can be migrated: live migration or block migration
@@ -183,6 +205,67 @@ type VirtualMachineInstanceStatus struct {
 	// ActivePods is a mapping of pod UID to node name.
 	// It is possible for multiple pods to be running for a single VMI during migration.
 	ActivePods map[types.UID]string `json:"activePods,omitempty"`
+
+	// Represents the result of the last compaction of the disk overlays
+	// +optional
+	DiskCompaction *DiskCompactionStatus `json:"diskCompaction,omitempty"`
+
+	// Represents the progress of the last memory dump of the guest
+	// +optional
+	MemoryDump *VirtualMachineInstanceMemoryDumpStatus `json:"memoryDump,omitempty"`
+
+	// EvacuationNodeName is set to the node the VirtualMachineInstance has to leave after
+	// an eviction of its pod was blocked
+	// +optional
+	EvacuationNodeName string `json:"evacuationNodeName,omitempty"`
+
+	// VCPUPinning reports the pCPUs of the node the vCPUs are pinned to, if the CPUs are dedicated
+	// +optional
+	VCPUPinning []VCPUPin `json:"vcpuPinning,omitempty"`
+
+	// IPClaims are the IP addresses claimed by the interfaces with persistent IPs
+	// +optional
+	IPClaims []InterfaceIPClaim `json:"ipClaims,omitempty"`
+
+	// RuntimeUser is the user qemu and libvirt run as in the virt-launcher pod,
+	// 0 if they run as root
+	// +optional
+	RuntimeUser uint64 `json:"runtimeUser,omitempty"`
+
+	// PhaseTransitionTimestamps record when the VirtualMachineInstance entered each of its phases
+	// +optional
+	PhaseTransitionTimestamps []VirtualMachineInstancePhaseTransitionTimestamp `json:"phaseTransitionTimestamps,omitempty"`
+}
+
+// InterfaceIPClaim are the IP addresses claimed by an interface, they are requested
+// from the IPAM of the network for every pod of the VirtualMachineInstance
+//
+// +k8s:openapi-gen=true
+type InterfaceIPClaim struct {
+	// Name of the interface
+	Name string `json:"name"`
+	// IPs claimed by the interface
+	IPs []string `json:"ipAddresses"`
+}
+
+// VirtualMachineInstancePhaseTransitionTimestamp is the time the VirtualMachineInstance entered a phase
+//
+// +k8s:openapi-gen=true
+type VirtualMachineInstancePhaseTransitionTimestamp struct {
+	// Phase is the phase the VirtualMachineInstance entered
+	Phase VirtualMachineInstancePhase `json:"phase,omitempty"`
+	// PhaseTransitionTimestamp is the time the VirtualMachineInstance entered the phase
+	PhaseTransitionTimestamp metav1.Time `json:"phaseTransitionTimestamp,omitempty"`
+}
+
+// VCPUPin is the placement of a vCPU on the pCPUs of the node
+//
+// +k8s:openapi-gen=true
+type VCPUPin struct {
+	// VCPU is the index of the vCPU
+	VCPU uint32 `json:"vcpu"`
+	// CPUSet lists the pCPUs the vCPU runs on
+	CPUSet string `json:"cpuset"`
 }
 
 func (v *VirtualMachineInstance) IsScheduling() bool {
@@ -214,6 +297,32 @@ func (v *VirtualMachineInstance) IsCPUDedicated() bool {
 	return v.Spec.Domain.CPU != nil && v.Spec.Domain.CPU.DedicatedCPUPlacement
 }
 
+// Checks if the host NUMA topology should be passed through to the guest
+func (v *VirtualMachineInstance) IsNUMAPassthrough() bool {
+	return v.Spec.Domain.CPU != nil && v.Spec.Domain.CPU.NUMA != nil && v.Spec.Domain.CPU.NUMA.GuestMappingPassthrough != nil
+}
+
+// Checks if the vCPUs of the VMI should be scheduled as realtime tasks
+func (v *VirtualMachineInstance) IsRealtimeEnabled() bool {
+	return v.Spec.Domain.CPU != nil && v.Spec.Domain.CPU.Realtime != nil
+}
+
+// GetEmulatorThreadPolicy returns where the emulator thread is placed, taking
+// the older IsolateEmulatorThread flag into account
+func (v *VirtualMachineInstance) GetEmulatorThreadPolicy() EmulatorThreadPolicy {
+	cpu := v.Spec.Domain.CPU
+	if cpu == nil {
+		return EmulatorThreadPolicyFloat
+	}
+	if cpu.EmulatorThreadPolicy != nil {
+		return *cpu.EmulatorThreadPolicy
+	}
+	if cpu.IsolateEmulatorThread {
+		return EmulatorThreadPolicyIsolate
+	}
+	return EmulatorThreadPolicyFloat
+}
+
 // WantsToHaveQOSGuaranteed checks if cpu and memoyr limits and requests are identical on the VMI.
 // This is the indicator that people want a VMI with QOS of guaranteed
 func (v *VirtualMachineInstance) WantsToHaveQOSGuaranteed() bool {
@@ -245,12 +354,56 @@ const (
 	// Reflects whether the QEMU guest agent is connected through the channel
 	VirtualMachineInstanceUnsupportedAgent VirtualMachineInstanceConditionType = "AgentVersionNotSupported"
 
+	// Reflects that the QEMU guest agent disconnected from the channel while the guest is running
+	VirtualMachineInstanceAgentUnreachable VirtualMachineInstanceConditionType = "AgentUnreachable"
+	// Reason means that the QEMU guest agent was connected before it disconnected
+	VirtualMachineInstanceReasonAgentDisconnected = "AgentDisconnected"
+
 	// Indicates whether the VMI is live migratable
 	VirtualMachineInstanceIsMigratable VirtualMachineInstanceConditionType = "LiveMigratable"
 	// Reason means that VMI is not live migratioable because of it's disks collection
 	VirtualMachineInstanceReasonDisksNotMigratable = "DisksNotLiveMigratable"
 	// Reason means that VMI is not live migratioable because of it's network interfaces collection
 	VirtualMachineInstanceReasonInterfaceNotMigratable = "InterfaceNotLiveMigratable"
+
+	// Reflects that the memory of the guest is being dumped to a PersistentVolumeClaim
+	VirtualMachineInstanceMemoryDumpInProgress VirtualMachineInstanceConditionType = "MemoryDumpInProgress"
+
+	// Reflects that the guest filesystems were frozen by the user through the guest agent
+	VirtualMachineInstanceFrozen VirtualMachineInstanceConditionType = "Frozen"
+
+	// Reflects that a failed migration was retried until the retry limit was reached
+	VirtualMachineInstanceMigrationRetriesExhausted VirtualMachineInstanceConditionType = "MigrationRetriesExhausted"
+
+	// Reflects that the VFs of SR-IOV interfaces with a failover standby are detached from the guest,
+	// their traffic runs through the standby interfaces
+	VirtualMachineInstanceSRIOVFailover VirtualMachineInstanceConditionType = "SRIOVFailover"
+	// Reason means that the VFs were detached for a live migration
+	VirtualMachineInstanceReasonVFDetached = "VFDetached"
+
+	// Reflects whether virt-handler on the node of the VirtualMachineInstance sends heartbeats.
+	// It is reported as unknown while the node is unresponsive, since the guest can't be observed
+	VirtualMachineInstanceNodeResponsive VirtualMachineInstanceConditionType = "NodeResponsive"
+	// Reason means that virt-handler on the node of the VirtualMachineInstance stopped sending heartbeats
+	VirtualMachineInstanceReasonNodeUnresponsive = "NodeUnresponsive"
+
+	// Reflects that the domain of the VirtualMachineInstance is in a state which needs attention,
+	// like being paused after an I/O error or having crashed
+	VirtualMachineInstanceDomainFailure VirtualMachineInstanceConditionType = "DomainFailure"
+	// Reason means that the domain was paused because of an I/O error, usually a storage outage
+	VirtualMachineInstanceReasonPausedIOError = "PausedIOError"
+	// Reason means that the guest of the domain crashed
+	VirtualMachineInstanceReasonDomainCrashed = "DomainCrashed"
+
+	// Reflects that the watchdog device of the VirtualMachineInstance fired
+	VirtualMachineInstanceWatchdogFired VirtualMachineInstanceConditionType = "WatchdogFired"
+	// Reason means that the guest did not feed its watchdog device in time
+	VirtualMachineInstanceReasonWatchdogExpired = "WatchdogExpired"
+
+	// Reflects that the guest of the VirtualMachineInstance crashed
+	VirtualMachineInstanceCrashed VirtualMachineInstanceConditionType = "Crashed"
+	// Reason means that the guest kernel reported a panic
+	VirtualMachineInstanceReasonGuestPanicked = "GuestPanicked"
 )
 
 // +k8s:openapi-gen=true
@@ -260,6 +413,9 @@ type VirtualMachineInstanceMigrationConditionType string
 const (
 	// VirtualMachineInstanceMigrationAbortRequested indicates that live migration abort has been requested
 	VirtualMachineInstanceMigrationAbortRequested VirtualMachineInstanceMigrationConditionType = "migrationAbortRequested"
+
+	// VirtualMachineInstanceMigrationCPUIncompatible indicates that no node can host the CPU model and features of the VMI
+	VirtualMachineInstanceMigrationCPUIncompatible VirtualMachineInstanceMigrationConditionType = "cpuIncompatible"
 )
 
 //
@@ -330,6 +486,11 @@ type VirtualMachineInstanceNetworkInterface struct {
 	IPs []string `json:"ipAddresses,omitempty"`
 	// The interface name inside the Virtual Machine
 	InterfaceName string `json:"interfaceName,omitempty"`
+	// VLAN ID the traffic of the interface is tagged with, if it is
+	// connected to an OVN-Kubernetes localnet network
+	VLAN int32 `json:"vlan,omitempty"`
+	// Additional addresses the guest is allowed to use on the interface
+	AllowedAddresses *AllowedAddresses `json:"allowedAddresses,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -352,6 +513,64 @@ type VirtualMachineInstanceGuestOSInfo struct {
 	ID string `json:"id,omitempty"`
 }
 
+// DiskCompaction configures the periodic compaction of the qcow2 overlays of a VirtualMachineInstance.
+// The guest agent is required to trim the unused space of the guest filesystems.
+//
+// +k8s:openapi-gen=true
+type DiskCompaction struct {
+	// Interval between two compactions, at least one hour.
+	Interval metav1.Duration `json:"interval"`
+}
+
+// +k8s:openapi-gen=true
+type DiskCompactionStatus struct {
+	// The time the last compaction finished.
+	LastCompactionTimestamp *metav1.Time `json:"lastCompactionTimestamp,omitempty"`
+	// Bytes of the overlays given back to the storage by the last compaction.
+	ReclaimedBytes int64 `json:"reclaimedBytes,omitempty"`
+	// Bytes of the overlays given back to the storage by all compactions of the VirtualMachineInstance.
+	TotalReclaimedBytes int64 `json:"totalReclaimedBytes,omitempty"`
+	// Tells why the last compaction failed, empty if it succeeded.
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// MemoryDumpPhase is the phase of a memory dump
+//
+// +k8s:openapi-gen=true
+type MemoryDumpPhase string
+
+const (
+	// MemoryDumpPending means the claim is not yet attached to the node of the VMI
+	MemoryDumpPending MemoryDumpPhase = "Pending"
+	// MemoryDumpInProgress means the memory of the guest is being written to the claim
+	MemoryDumpInProgress MemoryDumpPhase = "InProgress"
+	// MemoryDumpCompleted means the memory dump was written to the claim
+	MemoryDumpCompleted MemoryDumpPhase = "Completed"
+	// MemoryDumpFailed means the memory dump could not be written
+	MemoryDumpFailed MemoryDumpPhase = "Failed"
+)
+
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceMemoryDumpStatus struct {
+	// The PersistentVolumeClaim the memory of the guest is dumped to.
+	ClaimName string `json:"claimName"`
+	// The phase of the memory dump.
+	Phase MemoryDumpPhase `json:"phase,omitempty"`
+	// The UID of the pod which attaches the claim to the node of the VirtualMachineInstance.
+	// +optional
+	AttachPodUID types.UID `json:"attachPodUID,omitempty"`
+	// The name of the dump file on the claim.
+	// +optional
+	FileName string `json:"fileName,omitempty"`
+	// The time the memory dump started.
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+	// The time the memory dump ended.
+	EndTimestamp *metav1.Time `json:"endTimestamp,omitempty"`
+	// Tells why the memory dump failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type VirtualMachineInstanceMigrationState struct {
 	// The time the migration action began
@@ -460,6 +679,20 @@ const (
 	// Machine Instance migration job. Needed because with CRDs we can't use field
 	// selectors. Used on VirtualMachineInstance.
 	MigrationTargetNodeNameLabel string = "kubevirt.io/migrationTargetNodeName"
+	// This annotation holds the name of the failed migration which a
+	// migration retries. Used on VirtualMachineInstanceMigration.
+	MigrationRetryOfAnnotation string = "kubevirt.io/migration-retry-of"
+	// This annotation holds the number of the retry a migration is. Used on
+	// VirtualMachineInstanceMigration.
+	MigrationRetryCountAnnotation string = "kubevirt.io/migration-retry-count"
+	// This annotation holds the namespace and name of the virtual machine
+	// instance whose launcher pod preempted the annotated one. Used on
+	// VirtualMachineInstance.
+	PreemptedByAnnotation string = "kubevirt.io/preempted-by"
+	// This annotation marks migrations created to move a virtual machine
+	// instance out of an outdated virt-launcher pod. Used on
+	// VirtualMachineInstanceMigration.
+	WorkloadUpdateMigrationAnnotation string = "kubevirt.io/workload-update-migration"
 	// This label declares whether a particular node is available for
 	// scheduling virtual machine instances on it. Used on Node.
 	NodeSchedulable string = "kubevirt.io/schedulable"
@@ -467,6 +700,55 @@ const (
 	// if a particular node is alive and hence should be available for new
 	// virtual machine instance scheduling. Used on Node.
 	VirtHandlerHeartbeat string = "kubevirt.io/heartbeat"
+	// This annotation is regularly updated by virt-handler with the CPU
+	// pressure and the resident memory of the VMIs on the node, if the
+	// UtilizationScheduling feature gate is enabled. The scheduler extender
+	// scores nodes by it. Used on Node.
+	NodeUtilizationAnnotation string = "kubevirt.io/utilization"
+	// This label prefix, followed by the name of a CPU model, marks the
+	// CPU models a node can run. Used on Node.
+	CPUModelLabel string = "feature.node.kubernetes.io/cpu-model-"
+	// This label prefix, followed by the name of a CPU feature, marks the
+	// CPU features a node provides. Used on Node.
+	CPUFeatureLabel string = "feature.node.kubernetes.io/cpu-feature-"
+	// This label prefix, followed by the name of a Hyper-V enlightenment,
+	// marks the enlightenments KVM supports on a node. Used on Node.
+	HypervLabel string = "feature.node.kubernetes.io/kvm-info-cap-hyperv-"
+	// This label marks nodes which support AMD Secure Encrypted
+	// Virtualization. Used on Node.
+	SEVLabel string = "kubevirt.io/sev"
+	// This label marks nodes which support Intel Software Guard
+	// Extensions. Used on Node.
+	SGXLabel string = "kubevirt.io/sgx"
+	// This label prefix, followed by a page size, marks the hugepage sizes
+	// a node supports. Used on Node.
+	HugepagesLabel string = "hugepages.node.kubevirt.io/"
+	// This label marks nodes which run a realtime kernel. Used on Node.
+	RealtimeLabel string = "kubevirt.io/realtime"
+	// This label holds the number of hyperthreads per core of a node. Used on Node.
+	CPUThreadsPerCoreLabel string = "kubevirt.io/cpu-threads-per-core"
+	// This annotation opts a virtual machine instance out of kernel samepage
+	// merging of its memory. Used on VirtualMachineInstance.
+	KSMDisabledAnnotation string = "kubevirt.io/ksm-disabled"
+	// This annotation disables the free page reporting of the memory balloon,
+	// e.g. for latency sensitive workloads. Used on VirtualMachineInstance.
+	FreePageReportingDisabledAnnotation string = "kubevirt.io/free-page-reporting-disabled"
+	// This annotation selects the workload class of a virtual machine instance,
+	// its virt-launcher pod runs with the SELinux type of the class. Used on
+	// VirtualMachineInstance.
+	SELinuxWorkloadClassAnnotation string = "kubevirt.io/selinux-workload-class"
+	// This annotation holds the storage size requested for a DataVolume
+	// template on a filesystem volume, before the mutating webhook increased
+	// it by the overhead of the filesystem. Used on DataVolume templates.
+	RequestedStorageSizeAnnotation string = "kubevirt.io/requested-storage-size"
+	// This annotation holds the filesystem overhead, in percent, the storage
+	// size requested for a DataVolume template was increased by. Used on
+	// DataVolume templates.
+	FilesystemOverheadAnnotation string = "kubevirt.io/filesystem-overhead"
+	// This pod condition is the readiness gate of virt-launcher pods which pin
+	// the vCPUs of their virtual machine instance. virt-handler sets it once it
+	// verified that the cgroup of the pod supports the pinning. Used on Pod.
+	CgroupReadinessGate string = "kubevirt.io/cgroup-operations-supported"
 	// This label will be set on all resources created by the operator
 	ManagedByLabel              = "app.kubernetes.io/managed-by"
 	ManagedByLabelOperatorValue = "kubevirt-operator"
@@ -491,6 +773,19 @@ const (
 	PlacePCIDevicesOnRootComplex string = "kubevirt.io/placePCIDevicesOnRootComplex"
 
 	VirtualMachineLabel = AppLabel + "/vm"
+	// This label is used to match the VirtualMachineInstances of a VirtualMachine group for their pod anti-affinity.
+	VirtualMachineGroupLabel = AppLabel + "/vm-group"
+	// This label is used to match VirtualMachineExports with the pods and services serving them.
+	VirtualMachineExportLabel = AppLabel + "/export"
+	// This finalizer is used by virt-handler to end the backup job of a deleted VirtualMachineBackup.
+	VirtualMachineBackupFinalizer = "kubevirt.io/backup"
+	// This label is used to match VirtualMachineInstances with the pods attaching the claim of their memory dump.
+	MemoryDumpLabel = AppLabel + "/memory-dump"
+	// This label holds the UID of the VirtualMachine whose template history a ControllerRevision belongs to.
+	VirtualMachineTemplateRevisionLabel = AppLabel + "/vm-template-of"
+	// This annotation holds the name of the ControllerRevision the template of a VirtualMachine is being
+	// rolled back to. It is removed by virt-controller once the template is recorded as the latest revision.
+	RollbackRevisionAnnotation = AppLabel + "/rollback-revision"
 )
 
 func NewVMI(name string, uid types.UID) *VirtualMachineInstance {
@@ -665,6 +960,25 @@ type VirtualMachineInstanceReplicaSetStatus struct {
 
 	// Canonical form of the label selector for HPA which consumes it through the scale subresource.
 	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Zones reports how the replicas are distributed across the zones of the nodes they are running on.
+	// +optional
+	Zones []VirtualMachineInstanceReplicaSetZoneStatus `json:"zones,omitempty"`
+}
+
+// VirtualMachineInstanceReplicaSetZoneStatus contains the number of replicas running in a zone
+//
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceReplicaSetZoneStatus struct {
+	// Zone is the value of the zone label of the nodes
+	Zone string `json:"zone"`
+
+	// Number of replicas running in the zone
+	Replicas int32 `json:"replicas"`
+
+	// Number of ready replicas in the zone
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 }
 
 //
@@ -900,6 +1214,84 @@ type VirtualMachineSpec struct {
 	// dataVolumeTemplates is a list of dataVolumes that the VirtualMachineInstance template can reference.
 	// DataVolumes in this list are dynamically created for the VirtualMachine and are tied to the VirtualMachine's life-cycle.
 	DataVolumeTemplates []cdiv1.DataVolume `json:"dataVolumeTemplates,omitempty"`
+
+	// Instancetype references a VirtualMachineInstancetype, whose CPU and memory are expanded
+	// into the VirtualMachineInstance when the VirtualMachine is started
+	// +optional
+	Instancetype *InstancetypeMatcher `json:"instancetype,omitempty"`
+
+	// Preference references a VirtualMachinePreference, whose preferences fill the fields of the
+	// VirtualMachineInstance which the template leaves unset when the VirtualMachine is started
+	// +optional
+	Preference *PreferenceMatcher `json:"preference,omitempty"`
+
+	// Placement spreads the VirtualMachineInstance of the VirtualMachine across nodes or zones
+	// with the ones of the other VirtualMachines in the same group
+	// +optional
+	Placement *VirtualMachinePlacement `json:"placement,omitempty"`
+}
+
+// VirtualMachinePlacement makes a VirtualMachine a member of a group, whose VirtualMachineInstances
+// are spread across nodes or zones by pod anti-affinity on their virt-launcher pods
+//
+// +k8s:openapi-gen=true
+type VirtualMachinePlacement struct {
+	// The name of the group. It has to be a valid label value.
+	Group string `json:"group"`
+	// SpreadAcross is the topology the members of the group are spread across, Node or Zone.
+	// Defaults to Node.
+	// +optional
+	SpreadAcross PlacementTopology `json:"spreadAcross,omitempty"`
+	// Policy tells if the spread is Required for scheduling or only Preferred.
+	// Defaults to Required.
+	// +optional
+	Policy PlacementPolicy `json:"policy,omitempty"`
+}
+
+// PlacementTopology is the topology the members of a VirtualMachine group are spread across
+//
+// +k8s:openapi-gen=true
+type PlacementTopology string
+
+const (
+	PlacementTopologyNode PlacementTopology = "Node"
+	PlacementTopologyZone PlacementTopology = "Zone"
+)
+
+// PlacementPolicy tells how strictly the members of a VirtualMachine group are spread
+//
+// +k8s:openapi-gen=true
+type PlacementPolicy string
+
+const (
+	PlacementPolicyRequired  PlacementPolicy = "Required"
+	PlacementPolicyPreferred PlacementPolicy = "Preferred"
+)
+
+// InstancetypeMatcher references a VirtualMachineInstancetype in the namespace of the VirtualMachine
+//
+// +k8s:openapi-gen=true
+type InstancetypeMatcher struct {
+	// The name of the VirtualMachineInstancetype
+	Name string `json:"name"`
+	// The name of the ControllerRevision holding the copy of the VirtualMachineInstancetype
+	// the VirtualMachine uses. It is filled in when the VirtualMachine is started the first time,
+	// so that later changes of the VirtualMachineInstancetype don't affect the VirtualMachine.
+	// +optional
+	RevisionName string `json:"revisionName,omitempty"`
+}
+
+// PreferenceMatcher references a VirtualMachinePreference in the namespace of the VirtualMachine
+//
+// +k8s:openapi-gen=true
+type PreferenceMatcher struct {
+	// The name of the VirtualMachinePreference
+	Name string `json:"name"`
+	// The name of the ControllerRevision holding the copy of the VirtualMachinePreference
+	// the VirtualMachine uses. It is filled in when the VirtualMachine is started the first time,
+	// so that later changes of the VirtualMachinePreference don't affect the VirtualMachine.
+	// +optional
+	RevisionName string `json:"revisionName,omitempty"`
 }
 
 // StateChangeRequestType represents the existing state change requests that are possible
@@ -930,6 +1322,9 @@ type VirtualMachineStatus struct {
 	// StateChangeRequests indicates a list of actions that should be taken on a VMI
 	// e.g. stop a specific VMI then start a new one.
 	StateChangeRequests []VirtualMachineStateChangeRequest `json:"stateChangeRequests,omitempty" optional:"true"`
+	// IPClaims are the IP addresses claimed by the interfaces with persistent IPs, they
+	// are kept across restarts of the VirtualMachineInstance
+	IPClaims []InterfaceIPClaim `json:"ipClaims,omitempty" optional:"true"`
 }
 
 // +k8s:openapi-gen=true
@@ -1024,6 +1419,19 @@ type Handler struct {
 	// TODO: implement a realistic TCP lifecycle hook
 	// +optional
 	TCPSocket *k8sv1.TCPSocketAction `json:"tcpSocket,omitempty"`
+	// GuestAgentExec specifies a command to run inside the guest through the qemu-guest-agent.
+	// The probe succeeds if the command exits with status 0.
+	// +optional
+	GuestAgentExec *GuestAgentExecAction `json:"guestAgentExec,omitempty"`
+}
+
+// GuestAgentExecAction describes a command executed inside the guest through the qemu-guest-agent
+//
+// +k8s:openapi-gen=true
+type GuestAgentExecAction struct {
+	// Command is the command line to execute inside the guest, the first element is the executable.
+	// It is not run in a shell, so shell instructions like pipes do not work.
+	Command []string `json:"command,omitempty"`
 }
 
 // Probe describes a health check to be performed against a VirtualMachineInstance to determine whether it is
@@ -1055,6 +1463,56 @@ type Probe struct {
 	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
+// VirtualMachineInstanceLifecycle describes actions virt-launcher takes in response to
+// lifecycle events of the VirtualMachineInstance, similar to the lifecycle hooks of containers.
+//
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceLifecycle struct {
+	// PostStart is called after the domain was started.
+	// A failing hook is reported as an event, the VirtualMachineInstance keeps running.
+	// +optional
+	PostStart *LifecycleHandler `json:"postStart,omitempty"`
+	// PreStop is called before the guest is asked to shut down gracefully.
+	// The termination grace period starts before the hook is called.
+	// +optional
+	PreStop *LifecycleHandler `json:"preStop,omitempty"`
+}
+
+// LifecycleHandler defines the action taken by a lifecycle hook.
+// Exactly one of the fields must be specified.
+//
+// +k8s:openapi-gen=true
+type LifecycleHandler struct {
+	// Exec runs a command in the compute container of the virt-launcher pod.
+	// +optional
+	Exec *k8sv1.ExecAction `json:"exec,omitempty"`
+	// HTTPGet sends a http request. The host defaults to localhost, which
+	// reaches the sidecars of the virt-launcher pod.
+	// +optional
+	HTTPGet *k8sv1.HTTPGetAction `json:"httpGet,omitempty"`
+	// Number of seconds after which the hook times out.
+	// Defaults to 30 seconds.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// IOErrorRecovery describes how a VirtualMachineInstance which qemu paused because of an I/O error is resumed.
+//
+// +k8s:openapi-gen=true
+type IOErrorRecovery struct {
+	// If true, virt-handler resumes the guest as soon as a PersistentVolumeClaim of the
+	// VirtualMachineInstance was expanded, or after the retry interval in case the error cleared.
+	// qemu pauses the guest again if the error persists.
+	// If false, the guest stays paused.
+	// If unset, the guest is resumed on the next sync of the VirtualMachineInstance.
+	// +optional
+	AutoResume *bool `json:"autoResume,omitempty"`
+	// Number of seconds between two attempts to resume the guest.
+	// Defaults to 60 seconds.
+	// +optional
+	RetryIntervalSeconds int32 `json:"retryIntervalSeconds,omitempty"`
+}
+
 // KubeVirt represents the object deploying all KubeVirt resources
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1079,9 +1537,32 @@ type KubeVirtList struct {
 // ---
 // +k8s:openapi-gen=true
 type KubeVirtSelfSignConfiguration struct {
-	CARotateInterval   *metav1.Duration `json:"caRotateInterval,omitempty"`
+	// Deprecated. Use CA.Duration instead
+	CARotateInterval *metav1.Duration `json:"caRotateInterval,omitempty"`
+	// Deprecated. Use Server.Duration instead
 	CertRotateInterval *metav1.Duration `json:"certRotateInterval,omitempty"`
-	CAOverlapInterval  *metav1.Duration `json:"caOverlapInterval,omitempty"`
+	// Deprecated. Use CA.RenewBefore instead
+	CAOverlapInterval *metav1.Duration `json:"caOverlapInterval,omitempty"`
+
+	// CA configuration
+	// CA certs are kept in the CA bundle as long as they are valid
+	CA *CertConfig `json:"ca,omitempty"`
+
+	// Server configuration
+	// Certs are rotated and discarded
+	Server *CertConfig `json:"server,omitempty"`
+}
+
+// CertConfig contains the tunables for TLS certificates
+// +k8s:openapi-gen=true
+type CertConfig struct {
+	// The requested 'duration' (i.e. lifetime) of the Certificate.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The amount of time before the currently issued certificate's "notAfter"
+	// time that we will begin to attempt to renew the certificate.
+	// Defaults to 20% of the duration.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 // ---
@@ -1120,6 +1601,18 @@ type KubeVirtSpec struct {
 	// holds kubevirt configurations.
 	// same as the virt-configMap
 	Configuration KubeVirtConfiguration `json:"configuration,omitempty"`
+
+	// CanaryRollout rolls updates of virt-handler out to one node first and then to
+	// doubling batches of nodes, and rolls virt-handler back if the updated pods stay
+	// unhealthy. If not set, virt-handler is updated by a regular rolling update.
+	// +optional
+	CanaryRollout *KubeVirtCanaryRollout `json:"canaryRollout,omitempty"`
+
+	// WorkloadUpdateStrategy defines how running VMIs are moved to the new
+	// virt-launcher version after KubeVirt was updated. If not set, running VMIs
+	// keep their outdated virt-launcher pods until they are restarted.
+	// +optional
+	WorkloadUpdateStrategy *KubeVirtWorkloadUpdateStrategy `json:"workloadUpdateStrategy,omitempty"`
 }
 
 type KubeVirtUninstallStrategy string
@@ -1129,6 +1622,77 @@ const (
 	KubeVirtUninstallStrategyBlockUninstallIfWorkloadsExist KubeVirtUninstallStrategy = "BlockUninstallIfWorkloadsExist"
 )
 
+// KubeVirtCanaryRollout holds the options for the canary rollout of virt-handler updates
+//
+// +k8s:openapi-gen=true
+type KubeVirtCanaryRollout struct {
+	// VerificationPeriod is how long the virt-handler pods of a batch are observed
+	// before the next batch of nodes is updated. Defaults to 5m
+	// +optional
+	VerificationPeriod *metav1.Duration `json:"verificationPeriod,omitempty"`
+	// FailureTimeout is how long an updated virt-handler pod may stay unhealthy
+	// before virt-handler is rolled back to the previous version. Defaults to 10m
+	// +optional
+	FailureTimeout *metav1.Duration `json:"failureTimeout,omitempty"`
+}
+
+// KubeVirtCanaryRolloutStatus reports the progress of the canary rollout of virt-handler.
+// A rolled back virt-handler stays on the previous version until the KubeVirt CR
+// targets another version.
+//
+// +k8s:openapi-gen=true
+type KubeVirtCanaryRolloutStatus struct {
+	// DeploymentID identifies the deployment virt-handler is rolled out for
+	DeploymentID string                     `json:"deploymentID"`
+	Phase        KubeVirtCanaryRolloutPhase `json:"phase"`
+	// UpdatedNodes is the number of nodes virt-handler may be updated on in the current batch
+	UpdatedNodes int32 `json:"updatedNodes"`
+	// BatchStartTime is when the current batch of nodes started to be updated
+	// +optional
+	// +nullable
+	BatchStartTime *metav1.Time `json:"batchStartTime,omitempty"`
+	// UnhealthySince is when an updated virt-handler pod became unhealthy
+	// +optional
+	// +nullable
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+	Message        string       `json:"message,omitempty"`
+}
+
+type KubeVirtCanaryRolloutPhase string
+
+const (
+	CanaryRolloutProgressing KubeVirtCanaryRolloutPhase = "Progressing"
+	CanaryRolloutSucceeded   KubeVirtCanaryRolloutPhase = "Succeeded"
+	CanaryRolloutRolledBack  KubeVirtCanaryRolloutPhase = "RolledBack"
+)
+
+// KubeVirtWorkloadUpdateStrategy defines how the VMIs running in outdated
+// virt-launcher pods are updated
+//
+// +k8s:openapi-gen=true
+type KubeVirtWorkloadUpdateStrategy struct {
+	// WorkloadUpdateMethods are the methods used to update the outdated VMIs.
+	// Live migratable VMIs are migrated if LiveMigrate is listed, all other VMIs
+	// are evicted if Evict is listed.
+	// +optional
+	WorkloadUpdateMethods []WorkloadUpdateMethod `json:"workloadUpdateMethods,omitempty"`
+	// BatchEvictionSize is the number of VMIs evicted per batch. Defaults to 10
+	// +optional
+	BatchEvictionSize *int32 `json:"batchEvictionSize,omitempty"`
+	// BatchEvictionInterval is the time between two batches of evictions. Defaults to 1m
+	// +optional
+	BatchEvictionInterval *metav1.Duration `json:"batchEvictionInterval,omitempty"`
+}
+
+type WorkloadUpdateMethod string
+
+const (
+	// WorkloadUpdateMethodLiveMigrate migrates the VMI to a new virt-launcher pod
+	WorkloadUpdateMethodLiveMigrate WorkloadUpdateMethod = "LiveMigrate"
+	// WorkloadUpdateMethodEvict evicts the virt-launcher pod, the VMI is restarted if its VM is running
+	WorkloadUpdateMethodEvict WorkloadUpdateMethod = "Evict"
+)
+
 // KubeVirtStatus represents information pertaining to a KubeVirt deployment.
 //
 // +k8s:openapi-gen=true
@@ -1144,6 +1708,8 @@ type KubeVirtStatus struct {
 	ObservedKubeVirtVersion  string              `json:"observedKubeVirtVersion,omitempty" optional:"true"`
 	ObservedDeploymentConfig string              `json:"observedDeploymentConfig,omitempty" optional:"true"`
 	ObservedDeploymentID     string              `json:"observedDeploymentID,omitempty" optional:"true"`
+	// CanaryRollout reports the progress of the canary rollout of virt-handler
+	CanaryRollout *KubeVirtCanaryRolloutStatus `json:"canaryRollout,omitempty" optional:"true"`
 }
 
 // KubeVirtPhase is a label for the phase of a KubeVirt deployment at the current time.
@@ -1200,79 +1766,618 @@ const (
 )
 
 const (
-	EvictionStrategyLiveMigrate EvictionStrategy = "LiveMigrate"
+	EvictionStrategyLiveMigrate           EvictionStrategy = "LiveMigrate"
+	EvictionStrategyLiveMigrateIfPossible EvictionStrategy = "LiveMigrateIfPossible"
+	EvictionStrategyExternal              EvictionStrategy = "External"
+	EvictionStrategyNone                  EvictionStrategy = "None"
 )
 
-// RestartOptions may be provided when deleting an API object.
+// ConformanceRun exercises a matrix of VM features on the cluster and reports the results,
+// so that hardware and storage vendors can validate their stack against this KubeVirt build
 //
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
-type RestartOptions struct {
-	metav1.TypeMeta `json:",inline"`
-
-	// The duration in seconds before the object should be force-restared. Value must be non-negative integer.
-	// The value zero indicates, restart immediately. If this value is nil, the default grace period for deletion of the corresponding VMI for the
-	// specified type will be used to determine on how much time to give the VMI to restart.
-	// Defaults to a per object value if not specified. zero means restart immediately.
-	// Allowed Values: nil and 0
-	// +optional
-	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty" protobuf:"varint,1,opt,name=gracePeriodSeconds"`
+type ConformanceRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ConformanceRunSpec   `json:"spec" valid:"required"`
+	Status            ConformanceRunStatus `json:"status,omitempty"`
 }
 
-// VirtualMachineInstanceGuestAgentInfo represents information from the installed guest agent
+// ConformanceRunList is a list of ConformanceRuns
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
-type VirtualMachineInstanceGuestAgentInfo struct {
+type ConformanceRunList struct {
 	metav1.TypeMeta `json:",inline"`
-	// GAVersion is a version of currently installed guest agent
-	GAVersion string `json:"guestAgentVersion,omitempty"`
-	// Hostname represents FQDN of a guest
-	Hostname string `json:"hostname,omitempty"`
-	// OS contains the guest operating system information
-	OS VirtualMachineInstanceGuestOSInfo `json:"os,omitempty"`
-	// Timezone is guest os current timezone
-	Timezone string `json:"timezone,omitempty"`
-	// UserList is a list of active guest OS users
-	UserList []VirtualMachineInstanceGuestOSUser `json:"userList,omitempty"`
-	// FSInfo is a guest os filesystem information containing the disk mapping and disk mounts with usage
-	FSInfo VirtualMachineInstanceFileSystemInfo `json:"fsInfo,omitempty"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConformanceRun `json:"items"`
 }
 
-// VirtualMachineInstanceGuestOSUserList comprises the list of all active users on guest machine
-//
-// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// ---
 // +k8s:openapi-gen=true
-type VirtualMachineInstanceGuestOSUserList struct {
-	metav1.TypeMeta `json:",inline"`
-	metav1.ListMeta `json:"metadata,omitempty"`
-	Items           []VirtualMachineInstanceGuestOSUser `json:"items"`
+type ConformanceRunSpec struct {
+	// Features to exercise, in the given order.
+	// Defaults to all known features.
+	// +optional
+	Features []ConformanceFeature `json:"features,omitempty"`
+	// The containerDisk image the test VMIs boot from.
+	// Defaults to kubevirt/cirros-container-disk-demo.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// The multus network of type SR-IOV the SRIOV feature attaches to.
+	// The SRIOV feature is skipped if not set.
+	// +optional
+	SRIOVNetworkName string `json:"sriovNetworkName,omitempty"`
+	// How long a single feature may take before it is reported as failed.
+	// Defaults to 5 minutes.
+	// +optional
+	FeatureTimeout *metav1.Duration `json:"featureTimeout,omitempty"`
+	// The storage classes to check. The Boot, Migrate and Hotplug features are
+	// exercised again for every storage class, with a VMI booting from a
+	// DataVolume of the storage class the image is imported into.
+	// +optional
+	StorageClasses []string `json:"storageClasses,omitempty"`
+	// The two VMIs the NetworkLatency feature measures the latency and the
+	// throughput between. The NetworkLatency feature is skipped if not set.
+	// +optional
+	NetworkCheckup *ConformanceNetworkCheckup `json:"networkCheckup,omitempty"`
 }
 
-// VirtualMachineGuestOSUser is the single user of the guest os
+// ConformanceNetworkCheckup places two VMIs on a network to measure the latency
+// and the throughput between them
+//
 // +k8s:openapi-gen=true
-type VirtualMachineInstanceGuestOSUser struct {
-	UserName  string  `json:"userName"`
-	Domain    string  `json:"domain,omitempty"`
-	LoginTime float64 `json:"loginTime,omitempty"`
+type ConformanceNetworkCheckup struct {
+	// The multus network the VMIs are attached to, like an SR-IOV or a bridge network.
+	// The VMIs get the addresses 192.168.100.10/24 and 192.168.100.20/24 on it.
+	// Defaults to the pod network.
+	// +optional
+	NetworkName string `json:"networkName,omitempty"`
+	// Whether the multus network is of type SR-IOV, otherwise the VMIs are bridged to it
+	// +optional
+	SRIOV bool `json:"sriov,omitempty"`
+	// The node of the VMI sending the probes.
+	// Defaults to any node.
+	// +optional
+	SourceNode string `json:"sourceNode,omitempty"`
+	// The node of the probed VMI.
+	// Defaults to any node.
+	// +optional
+	TargetNode string `json:"targetNode,omitempty"`
+	// The containerDisk image of the VMIs. It has to provide ping, and iperf3
+	// for the throughput to be measured.
+	// Defaults to the image of the run.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// The highest average round-trip time for the NetworkLatency feature to pass.
+	// Without it the feature passes whenever the probed VMI answers.
+	// +optional
+	MaxLatency *metav1.Duration `json:"maxLatency,omitempty"`
 }
 
-// VirtualMachineInstanceFileSystemInfo represents information regarding single guest os filesystem
+// ConformanceFeature is a VM feature exercised by a ConformanceRun
+//
 // +k8s:openapi-gen=true
-type VirtualMachineInstanceFileSystemInfo struct {
-	Filesystems []VirtualMachineInstanceFileSystem `json:"disks"`
+type ConformanceFeature string
+
+const (
+	// Boot a VMI from a containerDisk
+	ConformanceFeatureBoot ConformanceFeature = "Boot"
+	// Live migrate a VMI to another node
+	ConformanceFeatureMigrate ConformanceFeature = "Migrate"
+	// Hotplug a volume into a running VMI
+	ConformanceFeatureHotplug ConformanceFeature = "Hotplug"
+	// Snapshot a stopped VM
+	ConformanceFeatureSnapshot ConformanceFeature = "Snapshot"
+	// Boot a VMI with an SR-IOV interface
+	ConformanceFeatureSRIOV ConformanceFeature = "SRIOV"
+	// Measure the latency and the throughput between two VMIs
+	ConformanceFeatureNetworkLatency ConformanceFeature = "NetworkLatency"
+)
+
+// ConformanceFeatures is the matrix exercised by a ConformanceRun without an explicit list of features
+var ConformanceFeatures = []ConformanceFeature{
+	ConformanceFeatureBoot,
+	ConformanceFeatureMigrate,
+	ConformanceFeatureHotplug,
+	ConformanceFeatureSnapshot,
+	ConformanceFeatureSRIOV,
+	ConformanceFeatureNetworkLatency,
 }
 
-// VirtualMachineInstanceFileSystemList comprises the list of all filesystems on guest machine
+// ConformanceRunStatus is the machine-readable report of a ConformanceRun
 //
-// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
-type VirtualMachineInstanceFileSystemList struct {
-	metav1.TypeMeta `json:",inline"`
-	metav1.ListMeta `json:"metadata,omitempty"`
-	Items           []VirtualMachineInstanceFileSystem `json:"items"`
+type ConformanceRunStatus struct {
+	Phase ConformanceRunPhase `json:"phase,omitempty"`
+	// The version of KubeVirt the features were exercised against
+	// +optional
+	KubeVirtVersion string `json:"kubeVirtVersion,omitempty"`
+	// +optional
+	// +nullable
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+	// +optional
+	// +nullable
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+	// The result of every feature exercised so far
+	// +optional
+	Results []ConformanceFeatureResult `json:"results,omitempty"`
+	Passed  int                        `json:"passed"`
+	Failed  int                        `json:"failed"`
+	Skipped int                        `json:"skipped"`
 }
 
-// VirtualMachineInstanceFileSystem represents guest os disk
+// ConformanceRunPhase is a label for the phase of a ConformanceRun at the current time.
+//
+// +k8s:openapi-gen=true
+type ConformanceRunPhase string
+
+// These are the valid ConformanceRun phases
+const (
+	ConformanceRunPhaseUnset ConformanceRunPhase = ""
+	// The features are being exercised
+	ConformanceRunRunning ConformanceRunPhase = "Running"
+	// All features passed or were skipped
+	ConformanceRunSucceeded ConformanceRunPhase = "Succeeded"
+	// At least one feature failed
+	ConformanceRunFailed ConformanceRunPhase = "Failed"
+)
+
+// ConformanceFeatureResult is the result of exercising a single feature
+//
+// +k8s:openapi-gen=true
+type ConformanceFeatureResult struct {
+	Feature ConformanceFeature `json:"feature"`
+	Result  ConformanceResult  `json:"result"`
+	// The storage class the feature was exercised with, if any
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+	// The measurements of the NetworkLatency feature
+	// +optional
+	NetworkCheckup *ConformanceNetworkCheckupResult `json:"networkCheckup,omitempty"`
+	// Why the feature failed or was skipped
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	// +nullable
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+	// +optional
+	// +nullable
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+}
+
+// ConformanceNetworkCheckupResult are the measurements between the two VMIs of a
+// network checkup
+//
+// +k8s:openapi-gen=true
+type ConformanceNetworkCheckupResult struct {
+	MinLatency metav1.Duration `json:"minLatency"`
+	AvgLatency metav1.Duration `json:"avgLatency"`
+	MaxLatency metav1.Duration `json:"maxLatency"`
+	// The throughput from the source to the target VMI in bits per second,
+	// only measured if the image provides iperf3
+	// +optional
+	ThroughputBitsPerSecond *int64 `json:"throughputBitsPerSecond,omitempty"`
+}
+
+// ConformanceResult is the outcome of exercising a feature
+//
+// +k8s:openapi-gen=true
+type ConformanceResult string
+
+const (
+	ConformanceResultPassed  ConformanceResult = "Passed"
+	ConformanceResultFailed  ConformanceResult = "Failed"
+	ConformanceResultSkipped ConformanceResult = "Skipped"
+)
+
+// IsFinal returns true if the run is not exercising features anymore
+func (r *ConformanceRun) IsFinal() bool {
+	return r.Status.Phase == ConformanceRunSucceeded || r.Status.Phase == ConformanceRunFailed
+}
+
+// VirtualMachineExport exposes the volumes of a stopped VirtualMachine over an authenticated
+// HTTPS endpoint, so that they can be downloaded or imported into another namespace or cluster
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualMachineExportSpec   `json:"spec" valid:"required"`
+	Status            VirtualMachineExportStatus `json:"status,omitempty"`
+}
+
+// VirtualMachineExportList is a list of VirtualMachineExports
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineExport `json:"items"`
+}
+
+// ---
+// +k8s:openapi-gen=true
+type VirtualMachineExportSpec struct {
+	// The name of the VirtualMachine to export. It has to live in the namespace of the export.
+	Source string `json:"source"`
+	// The name of a Secret in the namespace of the export. Clients have to present the value
+	// of its "token" key in the x-kubevirt-export-token header, or as basic auth password.
+	TokenSecretRef string `json:"tokenSecretRef"`
+}
+
+// VirtualMachineExportStatus contains the links to the exported volumes
+//
+// +k8s:openapi-gen=true
+type VirtualMachineExportStatus struct {
+	Phase VirtualMachineExportPhase `json:"phase,omitempty"`
+	// Why the export is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The service the volumes are served from
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// The PEM encoded CA certificate the certificate of the export server is signed with
+	// +optional
+	Cert string `json:"cert,omitempty"`
+	// Link to a VirtualMachine manifest, which imports the exported volumes through
+	// DataVolumes. The DataVolumes expect the token as secretKey in a Secret named like
+	// the tokenSecretRef, and Cert in a ConfigMap named like the export.
+	// +optional
+	Manifest string `json:"manifest,omitempty"`
+	// The links to the exported volumes
+	// +optional
+	Volumes []VirtualMachineExportVolume `json:"volumes,omitempty"`
+}
+
+// VirtualMachineExportPhase is a label for the phase of a VirtualMachineExport at the current time.
+//
+// +k8s:openapi-gen=true
+type VirtualMachineExportPhase string
+
+// These are the valid VirtualMachineExport phases
+const (
+	// The export server is not serving the volumes yet
+	VirtualMachineExportPending VirtualMachineExportPhase = "Pending"
+	// The volumes can be downloaded
+	VirtualMachineExportReady VirtualMachineExportPhase = "Ready"
+)
+
+// VirtualMachineExportVolume contains the links to a single exported volume
+//
+// +k8s:openapi-gen=true
+type VirtualMachineExportVolume struct {
+	// The name of the volume in the VirtualMachine
+	Name    string                             `json:"name"`
+	Formats []VirtualMachineExportVolumeFormat `json:"formats,omitempty"`
+}
+
+// VirtualMachineExportVolumeFormat is the link to a volume in a specific format
+//
+// +k8s:openapi-gen=true
+type VirtualMachineExportVolumeFormat struct {
+	Format ExportVolumeFormat `json:"format"`
+	Url    string             `json:"url"`
+}
+
+// ExportVolumeFormat is the image format an exported volume is served in
+//
+// +k8s:openapi-gen=true
+type ExportVolumeFormat string
+
+const (
+	ExportVolumeFormatRaw   ExportVolumeFormat = "raw"
+	ExportVolumeFormatQcow2 ExportVolumeFormat = "qcow2"
+)
+
+const (
+	// The key of the token in the Secret referenced by a VirtualMachineExport
+	VirtualMachineExportTokenKey = "token"
+	// The header clients present the export token in
+	VirtualMachineExportTokenHeader = "x-kubevirt-export-token"
+)
+
+// VirtualMachineBackup starts a pull mode backup of the disks of a running VirtualMachineInstance.
+// While the backup is ready, the disks are exported over NBD through the backup subresource of the
+// VirtualMachineInstance. Every backup records a checkpoint, which later backups can reference to
+// only export the blocks which changed since then. Deleting the backup ends the export.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualMachineBackupSpec   `json:"spec" valid:"required"`
+	Status            VirtualMachineBackupStatus `json:"status,omitempty"`
+}
+
+// VirtualMachineBackupList is a list of VirtualMachineBackups
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineBackup `json:"items"`
+}
+
+// ---
+// +k8s:openapi-gen=true
+type VirtualMachineBackupSpec struct {
+	// The name of the VirtualMachineInstance to back up. It has to live in the namespace of the backup.
+	Source string `json:"source"`
+	// The name of an earlier backup of the same VirtualMachineInstance. If set, the exported disks
+	// carry a dirty bitmap with the blocks which changed since the checkpoint of that backup.
+	// +optional
+	Incremental *string `json:"incremental,omitempty"`
+}
+
+// VirtualMachineBackupStatus describes where the backed up disks can be read from
+//
+// +k8s:openapi-gen=true
+type VirtualMachineBackupStatus struct {
+	Phase VirtualMachineBackupPhase `json:"phase,omitempty"`
+	// Why the backup is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The checkpoint recorded by this backup, which later backups can be incremental to
+	// +optional
+	Checkpoint string `json:"checkpoint,omitempty"`
+	// The disks exported by the backup
+	// +optional
+	Volumes []VirtualMachineBackupVolume `json:"volumes,omitempty"`
+}
+
+// VirtualMachineBackupPhase is a label for the phase of a VirtualMachineBackup at the current time.
+//
+// +k8s:openapi-gen=true
+type VirtualMachineBackupPhase string
+
+// These are the valid VirtualMachineBackup phases
+const (
+	// The backup job was not started yet
+	VirtualMachineBackupPending VirtualMachineBackupPhase = "Pending"
+	// The disks are exported and can be read through the backup subresource
+	VirtualMachineBackupReady VirtualMachineBackupPhase = "Ready"
+	// The backup job could not be started, or ended before the backup was deleted
+	VirtualMachineBackupFailed VirtualMachineBackupPhase = "Failed"
+)
+
+// VirtualMachineBackupVolume describes how a single disk is exported over NBD
+//
+// +k8s:openapi-gen=true
+type VirtualMachineBackupVolume struct {
+	// The name of the volume in the VirtualMachineInstance
+	Name string `json:"name"`
+	// The NBD export name of the disk
+	ExportName string `json:"exportName"`
+	// The name of the dirty bitmap of an incremental backup. It is exposed in the
+	// qemu:dirty-bitmap:<name> NBD metadata context.
+	// +optional
+	DirtyBitmap string `json:"dirtyBitmap,omitempty"`
+}
+
+// VirtualMachineInstancetype describes the CPU and memory of VirtualMachines.
+// VirtualMachines referencing it must not set these fields themselves.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstancetype struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualMachineInstancetypeSpec `json:"spec" valid:"required"`
+}
+
+// VirtualMachineInstancetypeList is a list of VirtualMachineInstancetypes
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstancetypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineInstancetype `json:"items"`
+}
+
+// ---
+// +k8s:openapi-gen=true
+type VirtualMachineInstancetypeSpec struct {
+	CPU    CPUInstancetype    `json:"cpu"`
+	Memory MemoryInstancetype `json:"memory"`
+}
+
+// CPUInstancetype describes the CPU of a VirtualMachineInstancetype
+//
+// +k8s:openapi-gen=true
+type CPUInstancetype struct {
+	// The number of vCPUs of the guest. How they are arranged in sockets, cores and threads
+	// is taken from the preferred CPU topology of the VirtualMachinePreference.
+	Guest uint32 `json:"guest"`
+	// The CPU model of the guest
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Pin the vCPUs to dedicated pCPUs of the node
+	// +optional
+	DedicatedCPUPlacement bool `json:"dedicatedCpuPlacement,omitempty"`
+}
+
+// MemoryInstancetype describes the memory of a VirtualMachineInstancetype
+//
+// +k8s:openapi-gen=true
+type MemoryInstancetype struct {
+	// The amount of memory visible to the guest
+	Guest resource.Quantity `json:"guest"`
+	// Back the memory of the guest with hugepages
+	// +optional
+	Hugepages *Hugepages `json:"hugepages,omitempty"`
+}
+
+// VirtualMachinePreference describes preferred values for fields of VirtualMachines.
+// The preferences are only applied to fields the VirtualMachines leave unset.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachinePreference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              VirtualMachinePreferenceSpec `json:"spec" valid:"required"`
+}
+
+// VirtualMachinePreferenceList is a list of VirtualMachinePreferences
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachinePreferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachinePreference `json:"items"`
+}
+
+// ---
+// +k8s:openapi-gen=true
+type VirtualMachinePreferenceSpec struct {
+	// +optional
+	CPU *CPUPreferences `json:"cpu,omitempty"`
+	// +optional
+	Devices *DevicePreferences `json:"devices,omitempty"`
+	// +optional
+	Machine *MachinePreferences `json:"machine,omitempty"`
+}
+
+// PreferredCPUTopology is how the vCPUs of an instancetype are arranged
+//
+// +k8s:openapi-gen=true
+type PreferredCPUTopology string
+
+const (
+	// Expose every vCPU as a socket, the default
+	PreferSockets PreferredCPUTopology = "preferSockets"
+	// Expose every vCPU as a core of a single socket
+	PreferCores PreferredCPUTopology = "preferCores"
+	// Expose every vCPU as a thread of a single core
+	PreferThreads PreferredCPUTopology = "preferThreads"
+)
+
+// CPUPreferences describes the preferred CPU of a VirtualMachinePreference
+//
+// +k8s:openapi-gen=true
+type CPUPreferences struct {
+	// How the vCPUs of the instancetype are arranged, one of preferSockets, preferCores or preferThreads.
+	// Defaults to preferSockets.
+	// +optional
+	PreferredCPUTopology PreferredCPUTopology `json:"preferredCPUTopology,omitempty"`
+}
+
+// DevicePreferences describes the preferred devices of a VirtualMachinePreference
+//
+// +k8s:openapi-gen=true
+type DevicePreferences struct {
+	// Whether to attach the default graphics device, if the VirtualMachine does not say
+	// +optional
+	PreferredAutoattachGraphicsDevice *bool `json:"preferredAutoattachGraphicsDevice,omitempty"`
+	// The bus of disks which don't set one
+	// +optional
+	PreferredDiskBus string `json:"preferredDiskBus,omitempty"`
+	// The model of interfaces which don't set one
+	// +optional
+	PreferredInterfaceModel string `json:"preferredInterfaceModel,omitempty"`
+}
+
+// MachinePreferences describes the preferred machine of a VirtualMachinePreference
+//
+// +k8s:openapi-gen=true
+type MachinePreferences struct {
+	// The machine type, if the VirtualMachine does not set one
+	// +optional
+	PreferredMachineType string `json:"preferredMachineType,omitempty"`
+}
+
+// RestartOptions may be provided when deleting an API object.
+//
+// +k8s:openapi-gen=true
+type RestartOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The duration in seconds before the object should be force-restared. Value must be non-negative integer.
+	// The value zero indicates, restart immediately. If this value is nil, the default grace period for deletion of the corresponding VMI for the
+	// specified type will be used to determine on how much time to give the VMI to restart.
+	// Defaults to a per object value if not specified. zero means restart immediately.
+	// Allowed Values: nil and 0
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty" protobuf:"varint,1,opt,name=gracePeriodSeconds"`
+}
+
+// StopOptions may be provided when stopping a VirtualMachine.
+//
+// +k8s:openapi-gen=true
+type StopOptions struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The duration in seconds before the VirtualMachineInstance is force-stopped. Value must be non-negative integer.
+	// The value zero indicates, stop immediately. If this value is nil, the termination grace period of the
+	// VirtualMachineInstance is used.
+	// Allowed Values: nil and 0
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+}
+
+// VirtualMachineInstanceGuestAgentInfo represents information from the installed guest agent
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceGuestAgentInfo struct {
+	metav1.TypeMeta `json:",inline"`
+	// GAVersion is a version of currently installed guest agent
+	GAVersion string `json:"guestAgentVersion,omitempty"`
+	// Hostname represents FQDN of a guest
+	Hostname string `json:"hostname,omitempty"`
+	// OS contains the guest operating system information
+	OS VirtualMachineInstanceGuestOSInfo `json:"os,omitempty"`
+	// Timezone is guest os current timezone
+	Timezone string `json:"timezone,omitempty"`
+	// UserList is a list of active guest OS users
+	UserList []VirtualMachineInstanceGuestOSUser `json:"userList,omitempty"`
+	// FSInfo is a guest os filesystem information containing the disk mapping and disk mounts with usage
+	FSInfo VirtualMachineInstanceFileSystemInfo `json:"fsInfo,omitempty"`
+}
+
+// VirtualMachineInstanceGuestOSUserList comprises the list of all active users on guest machine
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceGuestOSUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineInstanceGuestOSUser `json:"items"`
+}
+
+// VirtualMachineGuestOSUser is the single user of the guest os
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceGuestOSUser struct {
+	UserName  string  `json:"userName"`
+	Domain    string  `json:"domain,omitempty"`
+	LoginTime float64 `json:"loginTime,omitempty"`
+}
+
+// VirtualMachineInstanceFileSystemInfo represents information regarding single guest os filesystem
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceFileSystemInfo struct {
+	Filesystems []VirtualMachineInstanceFileSystem `json:"disks"`
+}
+
+// VirtualMachineInstanceFileSystemList comprises the list of all filesystems on guest machine
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceFileSystemList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineInstanceFileSystem `json:"items"`
+}
+
+// VirtualMachineInstanceFileSystem represents guest os disk
 // +k8s:openapi-gen=true
 type VirtualMachineInstanceFileSystem struct {
 	DiskName       string `json:"diskName"`
@@ -1282,6 +2387,44 @@ type VirtualMachineInstanceFileSystem struct {
 	TotalBytes     int    `json:"totalBytes"`
 }
 
+// VirtualMachineInstanceStats represents a sample of the resource usage of a running VMI
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VirtualMachineInstanceStats struct {
+	metav1.TypeMeta `json:",inline"`
+	// Timestamp of the sample
+	Timestamp metav1.MicroTime `json:"timestamp"`
+	// CPU time consumed by the domain since it was started
+	CPUTimeNanoseconds uint64 `json:"cpuTimeNanoseconds"`
+	// Number of vCPUs of the domain
+	VCPUs int `json:"vcpus"`
+	// Resident set size of the domain
+	MemoryResidentBytes uint64 `json:"memoryResidentBytes"`
+	// Memory available to the guest, as reported by the balloon driver
+	MemoryAvailableBytes uint64 `json:"memoryAvailableBytes"`
+	// Bytes read from all disks since the domain was started
+	StorageReadBytes uint64 `json:"storageReadBytes"`
+	// Bytes written to all disks since the domain was started
+	StorageWriteBytes uint64 `json:"storageWriteBytes"`
+	// Bytes received on all interfaces since the domain was started
+	NetworkRxBytes uint64 `json:"networkRxBytes"`
+	// Bytes transmitted on all interfaces since the domain was started
+	NetworkTxBytes uint64 `json:"networkTxBytes"`
+}
+
+// VNCToken grants the user who requested it a single connection to the VNC of a VirtualMachineInstance
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+type VNCToken struct {
+	metav1.TypeMeta `json:",inline"`
+	// Token to pass in the token query parameter of the vnc subresource
+	Token string `json:"token"`
+	// Time after which the token is not accepted anymore
+	ExpirationTimestamp metav1.Time `json:"expirationTimestamp"`
+}
+
 // Options for a rename operation
 type RenameOptions struct {
 	metav1.TypeMeta `json:",inline"`
@@ -1289,22 +2432,287 @@ type RenameOptions struct {
 	OldName         *string `json:"oldName,omitempty"`
 }
 
+// Options for a rollback operation
+type RollbackOptions struct {
+	metav1.TypeMeta `json:",inline"`
+	// The ControllerRevision holding the template to roll back to
+	RevisionName string `json:"revisionName"`
+}
+
+// Options for a memory dump operation
+type MemoryDumpOptions struct {
+	metav1.TypeMeta `json:",inline"`
+	// The PersistentVolumeClaim the memory of the guest is dumped to
+	ClaimName string `json:"claimName"`
+}
+
 // KubeVirtConfiguration holds all kubevirt configurations
 // +k8s:openapi-gen=true
 type KubeVirtConfiguration struct {
-	CPUModel                    string                  `json:"cpuModel,omitempty"`
-	CPURequest                  *resource.Quantity      `json:"cpuRequest,string,omitempty"`
-	DeveloperConfiguration      *DeveloperConfiguration `json:"developerConfiguration,omitempty"`
-	EmulatedMachines            []string                `json:"emulatedMachines,omitempty"`
-	ImagePullPolicy             k8sv1.PullPolicy        `json:"imagePullPolicy,omitempty"`
-	MigrationConfiguration      *MigrationConfiguration `json:"migrations,omitempty"`
-	MachineType                 string                  `json:"machineType,omitempty"`
-	NetworkConfiguration        *NetworkConfiguration   `json:"network,omitempty"`
-	OVMFPath                    string                  `json:"ovmfPath,omitempty"`
-	SELinuxLauncherType         string                  `json:"selinuxLauncherType,omitempty"`
-	SMBIOSConfig                *SMBiosConfiguration    `json:"smbios,omitempty"`
-	SupportedGuestAgentVersions []string                `json:"supportedGuestAgentVersions,omitempty"`
-	MemBalloonStatsPeriod       int                     `json:"memBalloonStatsPeriod,omitempty"`
+	CPUModel                    string                             `json:"cpuModel,omitempty"`
+	CPURequest                  *resource.Quantity                 `json:"cpuRequest,string,omitempty"`
+	DeveloperConfiguration      *DeveloperConfiguration            `json:"developerConfiguration,omitempty"`
+	EmulatedMachines            []string                           `json:"emulatedMachines,omitempty"`
+	ImagePullPolicy             k8sv1.PullPolicy                   `json:"imagePullPolicy,omitempty"`
+	MigrationConfiguration      *MigrationConfiguration            `json:"migrations,omitempty"`
+	MachineType                 string                             `json:"machineType,omitempty"`
+	NetworkConfiguration        *NetworkConfiguration              `json:"network,omitempty"`
+	OVMFPath                    string                             `json:"ovmfPath,omitempty"`
+	SELinuxLauncherType         string                             `json:"selinuxLauncherType,omitempty"`
+	SELinuxLauncherTypes        map[string]string                  `json:"selinuxLauncherTypes,omitempty"`
+	SMBIOSConfig                *SMBiosConfiguration               `json:"smbios,omitempty"`
+	SupportedGuestAgentVersions []string                           `json:"supportedGuestAgentVersions,omitempty"`
+	MemBalloonStatsPeriod       int                                `json:"memBalloonStatsPeriod,omitempty"`
+	PermittedHostDevices        *PermittedHostDevices              `json:"permittedHostDevices,omitempty"`
+	KSMConfiguration            *KSMConfiguration                  `json:"ksmConfiguration,omitempty"`
+	MemBalloonFreePageReporting bool                               `json:"memBalloonFreePageReporting,omitempty"`
+	MultiQueueConfiguration     *MultiQueueConfiguration           `json:"multiQueueConfiguration,omitempty"`
+	DefaultNetworkPolicy        *DefaultNetworkPolicyConfiguration `json:"defaultNetworkPolicy,omitempty"`
+	AuditConfiguration          *AuditConfiguration                `json:"audit,omitempty"`
+	NodeFencing                 *NodeFencingConfiguration          `json:"nodeFencing,omitempty"`
+	SeccompConfiguration        *SeccompConfiguration              `json:"seccompConfiguration,omitempty"`
+	MetricsConfiguration        *MetricsConfiguration              `json:"metrics,omitempty"`
+	FilesystemOverhead          *FilesystemOverheadConfiguration   `json:"filesystemOverhead,omitempty"`
+}
+
+// The workload classes of the SELinux types of the virt-launcher pods. The
+// selinuxLauncherTypes of the KubeVirtConfiguration map them to SELinux types.
+// VMIs with AMD SEV belong to the sev class, VMIs with devices passed through
+// with vfio to the vfio class and all other VMIs to the default class, unless
+// they select a class with the kubevirt.io/selinux-workload-class annotation.
+// Classes without a type fall back to the type of the default class, and that
+// to the selinuxLauncherType.
+const (
+	SELinuxWorkloadClassDefault = "default"
+	SELinuxWorkloadClassSEV     = "sev"
+	SELinuxWorkloadClassVFIO    = "vfio"
+)
+
+// KSMConfiguration holds the options for managing kernel samepage merging on the nodes
+// +k8s:openapi-gen=true
+type KSMConfiguration struct {
+	// MemoryPressureThreshold is the percentage of used memory of a node above
+	// which virt-handler enables KSM on it. Defaults to 80
+	// +optional
+	MemoryPressureThreshold *uint32 `json:"memoryPressureThreshold,omitempty"`
+}
+
+// MultiQueueConfiguration holds the options for sizing the virtio queues of new vmis
+// +k8s:openapi-gen=true
+type MultiQueueConfiguration struct {
+	// AutoTuning enables multi-queue on the virtio network interfaces and disks of new
+	// vmis which don't configure it, with one queue per vCPU
+	// +optional
+	AutoTuning bool `json:"autoTuning,omitempty"`
+	// MaxQueues caps the number of queues set by the auto tuning. Defaults to 8
+	// +optional
+	MaxQueues *uint32 `json:"maxQueues,omitempty"`
+}
+
+// DefaultNetworkPolicyConfiguration holds the options for the network policy restricting
+// the egress traffic of the virt-launcher pods in every namespace running vmis
+// +k8s:openapi-gen=true
+type DefaultNetworkPolicyConfiguration struct {
+	// Mode is Create to let virt-controller create and update the network policy, or
+	// Validate to only warn about vmis in namespaces without a network policy
+	// restricting the egress traffic of the virt-launcher pods
+	Mode DefaultNetworkPolicyMode `json:"mode"`
+	// BlockedCIDRs are the destinations outside of the cluster the virt-launcher
+	// pods can not reach. Defaults to the node metadata service 169.254.169.254/32
+	// +optional
+	BlockedCIDRs []string `json:"blockedCIDRs,omitempty"`
+}
+
+type DefaultNetworkPolicyMode string
+
+const (
+	DefaultNetworkPolicyCreate   DefaultNetworkPolicyMode = "Create"
+	DefaultNetworkPolicyValidate DefaultNetworkPolicyMode = "Validate"
+)
+
+// AuditConfiguration holds the options for the audit events virt-api emits for
+// privileged operations on vmis, like console connections or memory dumps
+// +k8s:openapi-gen=true
+type AuditConfiguration struct {
+	// WebhookURL is the address of a dedicated audit sink. The audit events are posted
+	// to it as audit.k8s.io/v1 EventList, like by the audit webhook backend of kube-apiserver
+	// +optional
+	WebhookURL string `json:"webhookURL,omitempty"`
+	// CABundle is the PEM encoded CA bundle verifying the certificate of the audit sink.
+	// Defaults to the system trust store
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+}
+
+// NodeFencingConfiguration holds the options for fencing the vmis on nodes whose
+// virt-handler stopped sending heartbeats
+// +k8s:openapi-gen=true
+type NodeFencingConfiguration struct {
+	// Enabled fences the vmis on nodes which are unresponsive and confirmed to be dead. Their
+	// virt-launcher pods are force deleted and they are moved to the Failed phase, so that their
+	// VirtualMachines can start them on other nodes. Only enable it if the confirmed nodes are
+	// reliably powered off, the guests could otherwise run twice.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// GracePeriod is how long a node has to be unresponsive before its vmis are fenced.
+	// Defaults to 5m
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	// Confirmation holds the rules confirming that an unresponsive node is dead before its
+	// vmis are fenced. Any of the rules confirms a node. Defaults to the node not being ready
+	// +optional
+	Confirmation *NodeFencingConfirmation `json:"confirmation,omitempty"`
+}
+
+// NodeFencingConfirmation holds the rules confirming that an unresponsive node is dead,
+// so that the guests of its vmis can't run twice
+// +k8s:openapi-gen=true
+type NodeFencingConfirmation struct {
+	// NotReady confirms nodes whose kubelet is not ready either, once the grace period passed.
+	// Defaults to true if no other rule is set
+	// +optional
+	NotReady *bool `json:"notReady,omitempty"`
+	// OutOfServiceTaint confirms nodes tainted with node.kubernetes.io/out-of-service, which
+	// marks nodes known to be shut down. The grace period does not apply
+	// +optional
+	OutOfServiceTaint bool `json:"outOfServiceTaint,omitempty"`
+	// Resources confirm nodes for which an object of one of the resources exists that is named
+	// after the node, like the remediation objects of fencing operators. virt-controller has to
+	// be allowed to list them. The grace period does not apply
+	// +optional
+	Resources []NodeFencingResource `json:"resources,omitempty"`
+}
+
+// NodeFencingResource is a resource whose objects are named after the nodes they confirm as dead
+// +k8s:openapi-gen=true
+type NodeFencingResource struct {
+	// Group of the resource, empty for the core API group
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Version of the resource
+	Version string `json:"version"`
+	// Resource is the plural name of the resource
+	Resource string `json:"resource"`
+}
+
+// SeccompConfiguration selects the seccomp profile the virt-launcher pods run with
+// +k8s:openapi-gen=true
+type SeccompConfiguration struct {
+	// Type is RuntimeDefault for the default profile of the container runtime, KubeVirt for
+	// the kubevirt/default profile tailored to qemu, which virt-handler installs on every node,
+	// or Custom for a profile the admin installed on the nodes. The virt-launcher pods run with
+	// the profile the container runtime picks for pods without one if no type is set
+	// +optional
+	Type SeccompProfileType `json:"type,omitempty"`
+	// CustomProfile is the path of the custom profile, relative to the seccomp directory of
+	// the kubelet. Required with the Custom type
+	// +optional
+	CustomProfile string `json:"customProfile,omitempty"`
+}
+
+type SeccompProfileType string
+
+const (
+	SeccompProfileRuntimeDefault SeccompProfileType = "RuntimeDefault"
+	SeccompProfileKubeVirt       SeccompProfileType = "KubeVirt"
+	SeccompProfileCustom         SeccompProfileType = "Custom"
+)
+
+// MetricsConfiguration holds the options for the metrics the KubeVirt components expose
+// +k8s:openapi-gen=true
+type MetricsConfiguration struct {
+	// NamingMode selects the names of the metrics renamed to follow the naming conventions of
+	// Prometheus: Legacy exposes them under their deprecated names, Current under their new
+	// names and Both under both names, to migrate dashboards and alerts. Defaults to Both
+	// +optional
+	NamingMode MetricsNamingMode `json:"namingMode,omitempty"`
+}
+
+type MetricsNamingMode string
+
+const (
+	MetricsNamingLegacy  MetricsNamingMode = "Legacy"
+	MetricsNamingCurrent MetricsNamingMode = "Current"
+	MetricsNamingBoth    MetricsNamingMode = "Both"
+)
+
+// FilesystemOverheadConfiguration holds the share of filesystem volumes their filesystem
+// takes. The storage sizes requested for the DataVolume templates of virtual machines on
+// filesystem volumes are increased by it, so that their disk image fits
+// +k8s:openapi-gen=true
+type FilesystemOverheadConfiguration struct {
+	// Global is the overhead of the storage classes without their own, in percent
+	// like "5.5". DataVolume templates without a storage class get it as well
+	// +optional
+	Global string `json:"global,omitempty"`
+	// StorageClass maps the names of storage classes to their overhead, in percent
+	// +optional
+	StorageClass map[string]string `json:"storageClass,omitempty"`
+}
+
+// ClusterCapabilities describes what the cluster supports, so that clients can
+// adapt to it without reading the KubeVirt CR
+// +k8s:openapi-gen=true
+type ClusterCapabilities struct {
+	// FeatureGates are the feature gates enabled on the cluster
+	// +optional
+	FeatureGates []string `json:"featureGates,omitempty"`
+	// MachineType is the machine type of vmis which don't request one
+	MachineType string `json:"machineType"`
+	// EmulatedMachines are the machine types vmis may request, as glob patterns
+	// +optional
+	EmulatedMachines []string `json:"emulatedMachines,omitempty"`
+	// Architecture is the architecture the defaults are chosen for
+	Architecture string `json:"architecture"`
+	// Configuration is the effective configuration of the cluster
+	Configuration *KubeVirtConfiguration `json:"configuration"`
+}
+
+// LauncherResources are the resources of the virt-launcher pod of a vmi, as they
+// are accounted by the scheduler and against resource quotas
+// +k8s:openapi-gen=true
+type LauncherResources struct {
+	// Requests are the resources requested by the pod
+	Requests k8sv1.ResourceList `json:"requests"`
+	// Limits are the resource limits of the pod
+	// +optional
+	Limits k8sv1.ResourceList `json:"limits,omitempty"`
+	// Overhead is the part of the requests which is not requested for the guest
+	// +optional
+	Overhead k8sv1.ResourceList `json:"overhead,omitempty"`
+}
+
+// PermittedHostDevices holds the host devices which may be passed through to vmis
+// +k8s:openapi-gen=true
+type PermittedHostDevices struct {
+	PciHostDevices  []PciHostDevice      `json:"pciHostDevices,omitempty"`
+	MediatedDevices []MediatedHostDevice `json:"mediatedDevices,omitempty"`
+}
+
+// PciHostDevice represents a PCI device of the hosts which may be passed through to vmis
+// +k8s:openapi-gen=true
+type PciHostDevice struct {
+	// PCIVendorSelector selects the devices by their vendor and device ID, e.g. "10de:1eb8"
+	PCIVendorSelector string `json:"pciVendorSelector"`
+	// ResourceName is the name under which the devices are exposed as a node resource
+	ResourceName string `json:"resourceName"`
+	// ExternalResourceProvider indicates that the devices are exposed by an external
+	// device plugin instead of virt-handler
+	// +optional
+	ExternalResourceProvider bool `json:"externalResourceProvider,omitempty"`
+}
+
+// MediatedHostDevice represents a mediated device type of the hosts which may be passed through to vmis
+// +k8s:openapi-gen=true
+type MediatedHostDevice struct {
+	// MDEVNameSelector selects the mediated devices by the name of their type, e.g. "GRID T4-1Q"
+	MDEVNameSelector string `json:"mdevNameSelector"`
+	// ResourceName is the name under which the devices are exposed as a node resource
+	ResourceName string `json:"resourceName"`
+	// ExternalResourceProvider indicates that the devices are exposed by an external
+	// device plugin instead of virt-handler
+	// +optional
+	ExternalResourceProvider bool `json:"externalResourceProvider,omitempty"`
 }
 
 // ---
@@ -1325,9 +2733,12 @@ type MigrationConfiguration struct {
 	CompletionTimeoutPerGiB           *int64             `json:"completionTimeoutPerGiB,string,omitempty"`
 	NodeDrainTaintKey                 *string            `json:"nodeDrainTaintKey,omitempty"`
 	ParallelOutboundMigrationsPerNode *uint32            `json:"parallelOutboundMigrationsPerNode,string,omitempty"`
+	ParallelInboundMigrationsPerNode  *uint32            `json:"parallelInboundMigrationsPerNode,string,omitempty"`
 	ParallelMigrationsPerCluster      *uint32            `json:"parallelMigrationsPerCluster,string,omitempty"`
 	ProgressTimeout                   *int64             `json:"progressTimeout,string,omitempty"`
 	UnsafeMigrationOverride           bool               `json:"unsafeMigrationOverride,string"`
+	RetryLimit                        *uint32            `json:"retryLimit,string,omitempty"`
+	DisableTLS                        bool               `json:"disableTLS,string,omitempty"`
 }
 
 // DeveloperConfiguration holds developer options
@@ -1346,4 +2757,5 @@ type NetworkConfiguration struct {
 	NetworkInterface                  string `json:"defaultNetworkInterface,omitempty"`
 	PermitSlirpInterface              bool   `json:"permitSlirpInterface,string,omitempty"`
 	PermitBridgeInterfaceOnPodNetwork bool   `json:"permitBridgeInterfaceOnPodNetwork,string,omitempty"`
+	IstioAwareMasquerade              bool   `json:"istioAwareMasquerade,string,omitempty"`
 }
@@ -25,7 +25,8 @@ func (VirtualMachineInstanceSpec) SwaggerDoc() map[string]string {
 		"affinity":                      "If affinity is specifies, obey all the affinity rules",
 		"schedulerName":                 "If specified, the VMI will be dispatched by specified scheduler.\nIf not specified, the VMI will be dispatched by default scheduler.\n+optional",
 		"tolerations":                   "If toleration is specified, obey all the toleration rules.",
-		"evictionStrategy":              "EvictionStrategy can be set to \"LiveMigrate\" if the VirtualMachineInstance should be\nmigrated instead of shut-off in case of a node drain.\n\n+optional",
+		"topologySpreadConstraints":     "TopologySpreadConstraints describes how a group of VMIs ought to spread across topology\ndomains, like zones. They are passed to the virt-launcher pod as they are.\n+optional",
+		"evictionStrategy":              "EvictionStrategy describes what happens to the VirtualMachineInstance when its pod is evicted,\nfor instance during a node drain. \"LiveMigrate\" blocks the eviction and migrates the\nVirtualMachineInstance, \"LiveMigrateIfPossible\" does the same but lets the eviction shut it off\nif it is not migratable, \"External\" blocks the eviction and leaves the evacuation to an\nexternal controller, and \"None\" lets the eviction shut it off.\n\n+optional",
 		"terminationGracePeriodSeconds": "Grace period observed after signalling a VirtualMachineInstance to stop after which the VirtualMachineInstance is force terminated.",
 		"volumes":                       "List of volumes that can be mounted by disks belonging to the vmi.",
 		"livenessProbe":                 "Periodic probe of VirtualMachineInstance liveness.\nVirtualmachineInstances will be stopped if the probe fails.\nCannot be updated.\nMore info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes\n+optional",
@@ -35,22 +36,57 @@ func (VirtualMachineInstanceSpec) SwaggerDoc() map[string]string {
 		"networks":                      "List of networks that can be attached to a vm's virtual interface.",
 		"dnsPolicy":                     "Set DNS policy for the pod.\nDefaults to \"ClusterFirst\".\nValid values are 'ClusterFirstWithHostNet', 'ClusterFirst', 'Default' or 'None'.\nDNS parameters given in DNSConfig will be merged with the policy selected with DNSPolicy.\nTo have DNS options set along with hostNetwork, you have to specify DNS policy\nexplicitly to 'ClusterFirstWithHostNet'.\n+optional",
 		"dnsConfig":                     "Specifies the DNS parameters of a pod.\nParameters specified here will be merged to the generated DNS\nconfiguration based on DNSPolicy.\n+optional",
+		"diskCompaction":                "If specified, the qcow2 overlays of the disks are periodically compacted while the\nVirtualMachineInstance is running, by trimming the unused space of the guest back to the storage.\n+optional",
+		"lifecycle":                     "Actions that virt-launcher takes in response to lifecycle events of the VirtualMachineInstance.\n+optional",
+		"ioErrorRecovery":               "How virt-handler recovers the VirtualMachineInstance when qemu paused it because of an I/O error,\nlike a full or unreachable storage.\n+optional",
 	}
 }
 
 func (VirtualMachineInstanceStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":                "VirtualMachineInstanceStatus represents information about the status of a VirtualMachineInstance. Status may trail the actual\nstate of a system.\n\n+k8s:openapi-gen=true",
-		"nodeName":        "NodeName is the name where the VirtualMachineInstance is currently running.",
-		"reason":          "A brief CamelCase message indicating details about why the VMI is in this state. e.g. 'NodeUnresponsive'\n+optional",
-		"conditions":      "Conditions are specific points in VirtualMachineInstance's pod runtime.",
-		"phase":           "Phase is the status of the VirtualMachineInstance in kubernetes world. It is not the VirtualMachineInstance status, but partially correlates to it.",
-		"interfaces":      "Interfaces represent the details of available network interfaces.",
-		"guestOSInfo":     "Guest OS Information",
-		"migrationState":  "Represents the status of a live migration",
-		"migrationMethod": "Represents the method using which the vmi can be migrated: live migration or block migration",
-		"qosClass":        "The Quality of Service (QOS) classification assigned to the virtual machine instance based on resource requirements\nSee PodQOSClass type for available QOS classes\nMore info: https://git.k8s.io/community/contributors/design-proposals/node/resource-qos.md\n+optional",
-		"activePods":      "ActivePods is a mapping of pod UID to node name.\nIt is possible for multiple pods to be running for a single VMI during migration.",
+		"":                          "VirtualMachineInstanceStatus represents information about the status of a VirtualMachineInstance. Status may trail the actual\nstate of a system.\n\n+k8s:openapi-gen=true",
+		"nodeName":                  "NodeName is the name where the VirtualMachineInstance is currently running.",
+		"reason":                    "A brief CamelCase message indicating details about why the VMI is in this state. e.g. 'NodeUnresponsive'\n+optional",
+		"conditions":                "Conditions are specific points in VirtualMachineInstance's pod runtime.",
+		"phase":                     "Phase is the status of the VirtualMachineInstance in kubernetes world. It is not the VirtualMachineInstance status, but partially correlates to it.",
+		"interfaces":                "Interfaces represent the details of available network interfaces.",
+		"guestOSInfo":               "Guest OS Information",
+		"guestHostname":             "Hostname of the guest as reported by the guest agent\n+optional",
+		"migrationState":            "Represents the status of a live migration",
+		"migrationMethod":           "Represents the method using which the vmi can be migrated: live migration or block migration",
+		"qosClass":                  "The Quality of Service (QOS) classification assigned to the virtual machine instance based on resource requirements\nSee PodQOSClass type for available QOS classes\nMore info: https://git.k8s.io/community/contributors/design-proposals/node/resource-qos.md\n+optional",
+		"activePods":                "ActivePods is a mapping of pod UID to node name.\nIt is possible for multiple pods to be running for a single VMI during migration.",
+		"diskCompaction":            "Represents the result of the last compaction of the disk overlays\n+optional",
+		"memoryDump":                "Represents the progress of the last memory dump of the guest\n+optional",
+		"evacuationNodeName":        "EvacuationNodeName is set to the node the VirtualMachineInstance has to leave after\nan eviction of its pod was blocked\n+optional",
+		"vcpuPinning":               "VCPUPinning reports the pCPUs of the node the vCPUs are pinned to, if the CPUs are dedicated\n+optional",
+		"ipClaims":                  "IPClaims are the IP addresses claimed by the interfaces with persistent IPs\n+optional",
+		"runtimeUser":               "RuntimeUser is the user qemu and libvirt run as in the virt-launcher pod,\n0 if they run as root\n+optional",
+		"phaseTransitionTimestamps": "PhaseTransitionTimestamps record when the VirtualMachineInstance entered each of its phases\n+optional",
+	}
+}
+
+func (InterfaceIPClaim) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "InterfaceIPClaim are the IP addresses claimed by an interface, they are requested\nfrom the IPAM of the network for every pod of the VirtualMachineInstance\n\n+k8s:openapi-gen=true",
+		"name":        "Name of the interface",
+		"ipAddresses": "IPs claimed by the interface",
+	}
+}
+
+func (VirtualMachineInstancePhaseTransitionTimestamp) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                         "VirtualMachineInstancePhaseTransitionTimestamp is the time the VirtualMachineInstance entered a phase\n\n+k8s:openapi-gen=true",
+		"phase":                    "Phase is the phase the VirtualMachineInstance entered",
+		"phaseTransitionTimestamp": "PhaseTransitionTimestamp is the time the VirtualMachineInstance entered the phase",
+	}
+}
+
+func (VCPUPin) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "VCPUPin is the placement of a vCPU on the pCPUs of the node\n\n+k8s:openapi-gen=true",
+		"vcpu":   "VCPU is the index of the vCPU",
+		"cpuset": "CPUSet lists the pCPUs the vCPU runs on",
 	}
 }
 
@@ -72,12 +108,14 @@ func (VirtualMachineInstanceMigrationCondition) SwaggerDoc() map[string]string {
 
 func (VirtualMachineInstanceNetworkInterface) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":              "+k8s:openapi-gen=true",
-		"ipAddress":     "IP address of a Virtual Machine interface. It is always the first item of\nIPs",
-		"mac":           "Hardware address of a Virtual Machine interface",
-		"name":          "Name of the interface, corresponds to name of the network assigned to the interface",
-		"ipAddresses":   "List of all IP addresses of a Virtual Machine interface",
-		"interfaceName": "The interface name inside the Virtual Machine",
+		"":                 "+k8s:openapi-gen=true",
+		"ipAddress":        "IP address of a Virtual Machine interface. It is always the first item of\nIPs",
+		"mac":              "Hardware address of a Virtual Machine interface",
+		"name":             "Name of the interface, corresponds to name of the network assigned to the interface",
+		"ipAddresses":      "List of all IP addresses of a Virtual Machine interface",
+		"interfaceName":    "The interface name inside the Virtual Machine",
+		"vlan":             "VLAN ID the traffic of the interface is tagged with, if it is\nconnected to an OVN-Kubernetes localnet network",
+		"allowedAddresses": "Additional addresses the guest is allowed to use on the interface",
 	}
 }
 
@@ -95,6 +133,36 @@ func (VirtualMachineInstanceGuestOSInfo) SwaggerDoc() map[string]string {
 	}
 }
 
+func (DiskCompaction) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "DiskCompaction configures the periodic compaction of the qcow2 overlays of a VirtualMachineInstance.\nThe guest agent is required to trim the unused space of the guest filesystems.\n\n+k8s:openapi-gen=true",
+		"interval": "Interval between two compactions, at least one hour.",
+	}
+}
+
+func (DiskCompactionStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "+k8s:openapi-gen=true",
+		"lastCompactionTimestamp": "The time the last compaction finished.",
+		"reclaimedBytes":          "Bytes of the overlays given back to the storage by the last compaction.",
+		"totalReclaimedBytes":     "Bytes of the overlays given back to the storage by all compactions of the VirtualMachineInstance.",
+		"failureReason":           "Tells why the last compaction failed, empty if it succeeded.",
+	}
+}
+
+func (VirtualMachineInstanceMemoryDumpStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "+k8s:openapi-gen=true",
+		"claimName":      "The PersistentVolumeClaim the memory of the guest is dumped to.",
+		"phase":          "The phase of the memory dump.",
+		"attachPodUID":   "The UID of the pod which attaches the claim to the node of the VirtualMachineInstance.\n+optional",
+		"fileName":       "The name of the dump file on the claim.\n+optional",
+		"startTimestamp": "The time the memory dump started.",
+		"endTimestamp":   "The time the memory dump ended.",
+		"message":        "Tells why the memory dump failed.\n+optional",
+	}
+}
+
 func (VirtualMachineInstanceMigrationState) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                               "+k8s:openapi-gen=true",
@@ -150,6 +218,16 @@ func (VirtualMachineInstanceReplicaSetStatus) SwaggerDoc() map[string]string {
 		"replicas":      "Total number of non-terminated pods targeted by this deployment (their labels match the selector).\n+optional",
 		"readyReplicas": "The number of ready replicas for this replica set.\n+optional",
 		"labelSelector": "Canonical form of the label selector for HPA which consumes it through the scale subresource.",
+		"zones":         "Zones reports how the replicas are distributed across the zones of the nodes they are running on.\n+optional",
+	}
+}
+
+func (VirtualMachineInstanceReplicaSetZoneStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "VirtualMachineInstanceReplicaSetZoneStatus contains the number of replicas running in a zone\n\n+k8s:openapi-gen=true",
+		"zone":          "Zone is the value of the zone label of the nodes",
+		"replicas":      "Number of replicas running in the zone",
+		"readyReplicas": "Number of ready replicas in the zone\n+optional",
 	}
 }
 
@@ -236,6 +314,34 @@ func (VirtualMachineSpec) SwaggerDoc() map[string]string {
 		"runStrategy":         "Running state indicates the requested running state of the VirtualMachineInstance\nmutually exclusive with Running",
 		"template":            "Template is the direct specification of VirtualMachineInstance",
 		"dataVolumeTemplates": "dataVolumeTemplates is a list of dataVolumes that the VirtualMachineInstance template can reference.\nDataVolumes in this list are dynamically created for the VirtualMachine and are tied to the VirtualMachine's life-cycle.",
+		"instancetype":        "Instancetype references a VirtualMachineInstancetype, whose CPU and memory are expanded\ninto the VirtualMachineInstance when the VirtualMachine is started\n+optional",
+		"preference":          "Preference references a VirtualMachinePreference, whose preferences fill the fields of the\nVirtualMachineInstance which the template leaves unset when the VirtualMachine is started\n+optional",
+		"placement":           "Placement spreads the VirtualMachineInstance of the VirtualMachine across nodes or zones\nwith the ones of the other VirtualMachines in the same group\n+optional",
+	}
+}
+
+func (VirtualMachinePlacement) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "VirtualMachinePlacement makes a VirtualMachine a member of a group, whose VirtualMachineInstances\nare spread across nodes or zones by pod anti-affinity on their virt-launcher pods\n\n+k8s:openapi-gen=true",
+		"group":        "The name of the group. It has to be a valid label value.",
+		"spreadAcross": "SpreadAcross is the topology the members of the group are spread across, Node or Zone.\nDefaults to Node.\n+optional",
+		"policy":       "Policy tells if the spread is Required for scheduling or only Preferred.\nDefaults to Required.\n+optional",
+	}
+}
+
+func (InstancetypeMatcher) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "InstancetypeMatcher references a VirtualMachineInstancetype in the namespace of the VirtualMachine\n\n+k8s:openapi-gen=true",
+		"name":         "The name of the VirtualMachineInstancetype",
+		"revisionName": "The name of the ControllerRevision holding the copy of the VirtualMachineInstancetype\nthe VirtualMachine uses. It is filled in when the VirtualMachine is started the first time,\nso that later changes of the VirtualMachineInstancetype don't affect the VirtualMachine.\n+optional",
+	}
+}
+
+func (PreferenceMatcher) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "PreferenceMatcher references a VirtualMachinePreference in the namespace of the VirtualMachine\n\n+k8s:openapi-gen=true",
+		"name":         "The name of the VirtualMachinePreference",
+		"revisionName": "The name of the ControllerRevision holding the copy of the VirtualMachinePreference\nthe VirtualMachine uses. It is filled in when the VirtualMachine is started the first time,\nso that later changes of the VirtualMachinePreference don't affect the VirtualMachine.\n+optional",
 	}
 }
 
@@ -247,6 +353,7 @@ func (VirtualMachineStatus) SwaggerDoc() map[string]string {
 		"ready":               "Ready indicates if the virtual machine is running and ready",
 		"conditions":          "Hold the state information of the VirtualMachine and its VirtualMachineInstance",
 		"stateChangeRequests": "StateChangeRequests indicates a list of actions that should be taken on a VMI\ne.g. stop a specific VMI then start a new one.",
+		"ipClaims":            "IPClaims are the IP addresses claimed by the interfaces with persistent IPs, they\nare kept across restarts of the VirtualMachineInstance",
 	}
 }
 
@@ -269,9 +376,17 @@ func (VirtualMachineCondition) SwaggerDoc() map[string]string {
 
 func (Handler) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"":          "Handler defines a specific action that should be taken",
-		"httpGet":   "HTTPGet specifies the http request to perform.\n+optional",
-		"tcpSocket": "TCPSocket specifies an action involving a TCP port.\nTCP hooks not yet supported\n+optional",
+		"":               "Handler defines a specific action that should be taken",
+		"httpGet":        "HTTPGet specifies the http request to perform.\n+optional",
+		"tcpSocket":      "TCPSocket specifies an action involving a TCP port.\nTCP hooks not yet supported\n+optional",
+		"guestAgentExec": "GuestAgentExec specifies a command to run inside the guest through the qemu-guest-agent.\nThe probe succeeds if the command exits with status 0.\n+optional",
+	}
+}
+
+func (GuestAgentExecAction) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":        "GuestAgentExecAction describes a command executed inside the guest through the qemu-guest-agent",
+		"command": "Command is the command line to execute inside the guest, the first element is the executable.\nIt is not run in a shell, so shell instructions like pipes do not work.",
 	}
 }
 
@@ -286,6 +401,31 @@ func (Probe) SwaggerDoc() map[string]string {
 	}
 }
 
+func (VirtualMachineInstanceLifecycle) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "VirtualMachineInstanceLifecycle describes actions virt-launcher takes in response to\nlifecycle events of the VirtualMachineInstance, similar to the lifecycle hooks of containers.\n\n+k8s:openapi-gen=true",
+		"postStart": "PostStart is called after the domain was started.\nA failing hook is reported as an event, the VirtualMachineInstance keeps running.\n+optional",
+		"preStop":   "PreStop is called before the guest is asked to shut down gracefully.\nThe termination grace period starts before the hook is called.\n+optional",
+	}
+}
+
+func (LifecycleHandler) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "LifecycleHandler defines the action taken by a lifecycle hook.\nExactly one of the fields must be specified.\n\n+k8s:openapi-gen=true",
+		"exec":           "Exec runs a command in the compute container of the virt-launcher pod.\n+optional",
+		"httpGet":        "HTTPGet sends a http request. The host defaults to localhost, which\nreaches the sidecars of the virt-launcher pod.\n+optional",
+		"timeoutSeconds": "Number of seconds after which the hook times out.\nDefaults to 30 seconds.\n+optional",
+	}
+}
+
+func (IOErrorRecovery) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "IOErrorRecovery describes how a VirtualMachineInstance which qemu paused because of an I/O error is resumed.\n\n+k8s:openapi-gen=true",
+		"autoResume":           "If true, virt-handler resumes the guest as soon as a PersistentVolumeClaim of the\nVirtualMachineInstance was expanded, or after the retry interval in case the error cleared.\nqemu pauses the guest again if the error persists.\nIf false, the guest stays paused.\nIf unset, the guest is resumed on the next sync of the VirtualMachineInstance.\n+optional",
+		"retryIntervalSeconds": "Number of seconds between two attempts to resume the guest.\nDefaults to 60 seconds.\n+optional",
+	}
+}
+
 func (KubeVirt) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "KubeVirt represents the object deploying all KubeVirt resources\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
@@ -299,7 +439,21 @@ func (KubeVirtList) SwaggerDoc() map[string]string {
 }
 
 func (KubeVirtSelfSignConfiguration) SwaggerDoc() map[string]string {
-	return map[string]string{}
+	return map[string]string{
+		"caRotateInterval":   "Deprecated. Use CA.Duration instead",
+		"certRotateInterval": "Deprecated. Use Server.Duration instead",
+		"caOverlapInterval":  "Deprecated. Use CA.RenewBefore instead",
+		"ca":                 "CA configuration\nCA certs are kept in the CA bundle as long as they are valid",
+		"server":             "Server configuration\nCerts are rotated and discarded",
+	}
+}
+
+func (CertConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "CertConfig contains the tunables for TLS certificates\n+k8s:openapi-gen=true",
+		"duration":    "The requested 'duration' (i.e. lifetime) of the Certificate.",
+		"renewBefore": "The amount of time before the currently issued certificate's \"notAfter\"\ntime that we will begin to attempt to renew the certificate.\nDefaults to 20% of the duration.",
+	}
 }
 
 func (KubeVirtCertificateRotateStrategy) SwaggerDoc() map[string]string {
@@ -308,19 +462,49 @@ func (KubeVirtCertificateRotateStrategy) SwaggerDoc() map[string]string {
 
 func (KubeVirtSpec) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"imageTag":          "The image tag to use for the continer images installed.\nDefaults to the same tag as the operator's container image.",
-		"imageRegistry":     "The image registry to pull the container images from\nDefaults to the same registry the operator's container image is pulled from.",
-		"imagePullPolicy":   "The ImagePullPolicy to use.",
-		"monitorNamespace":  "The namespace Prometheus is deployed in\nDefaults to openshift-monitor",
-		"monitorAccount":    "The name of the Prometheus service account that needs read-access to KubeVirt endpoints\nDefaults to prometheus-k8s",
-		"uninstallStrategy": "Specifies if kubevirt can be deleted if workloads are still present.\nThis is mainly a precaution to avoid accidental data loss",
-		"configuration":     "holds kubevirt configurations.\nsame as the virt-configMap",
+		"imageTag":               "The image tag to use for the continer images installed.\nDefaults to the same tag as the operator's container image.",
+		"imageRegistry":          "The image registry to pull the container images from\nDefaults to the same registry the operator's container image is pulled from.",
+		"imagePullPolicy":        "The ImagePullPolicy to use.",
+		"monitorNamespace":       "The namespace Prometheus is deployed in\nDefaults to openshift-monitor",
+		"monitorAccount":         "The name of the Prometheus service account that needs read-access to KubeVirt endpoints\nDefaults to prometheus-k8s",
+		"uninstallStrategy":      "Specifies if kubevirt can be deleted if workloads are still present.\nThis is mainly a precaution to avoid accidental data loss",
+		"configuration":          "holds kubevirt configurations.\nsame as the virt-configMap",
+		"canaryRollout":          "CanaryRollout rolls updates of virt-handler out to one node first and then to\ndoubling batches of nodes, and rolls virt-handler back if the updated pods stay\nunhealthy. If not set, virt-handler is updated by a regular rolling update.\n+optional",
+		"workloadUpdateStrategy": "WorkloadUpdateStrategy defines how running VMIs are moved to the new\nvirt-launcher version after KubeVirt was updated. If not set, running VMIs\nkeep their outdated virt-launcher pods until they are restarted.\n+optional",
+	}
+}
+
+func (KubeVirtCanaryRollout) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "KubeVirtCanaryRollout holds the options for the canary rollout of virt-handler updates\n\n+k8s:openapi-gen=true",
+		"verificationPeriod": "VerificationPeriod is how long the virt-handler pods of a batch are observed\nbefore the next batch of nodes is updated. Defaults to 5m\n+optional",
+		"failureTimeout":     "FailureTimeout is how long an updated virt-handler pod may stay unhealthy\nbefore virt-handler is rolled back to the previous version. Defaults to 10m\n+optional",
+	}
+}
+
+func (KubeVirtCanaryRolloutStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "KubeVirtCanaryRolloutStatus reports the progress of the canary rollout of virt-handler.\nA rolled back virt-handler stays on the previous version until the KubeVirt CR\ntargets another version.\n\n+k8s:openapi-gen=true",
+		"deploymentID":   "DeploymentID identifies the deployment virt-handler is rolled out for",
+		"updatedNodes":   "UpdatedNodes is the number of nodes virt-handler may be updated on in the current batch",
+		"batchStartTime": "BatchStartTime is when the current batch of nodes started to be updated\n+optional\n+nullable",
+		"unhealthySince": "UnhealthySince is when an updated virt-handler pod became unhealthy\n+optional\n+nullable",
+	}
+}
+
+func (KubeVirtWorkloadUpdateStrategy) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                      "KubeVirtWorkloadUpdateStrategy defines how the VMIs running in outdated\nvirt-launcher pods are updated\n\n+k8s:openapi-gen=true",
+		"workloadUpdateMethods": "WorkloadUpdateMethods are the methods used to update the outdated VMIs.\nLive migratable VMIs are migrated if LiveMigrate is listed, all other VMIs\nare evicted if Evict is listed.\n+optional",
+		"batchEvictionSize":     "BatchEvictionSize is the number of VMIs evicted per batch. Defaults to 10\n+optional",
+		"batchEvictionInterval": "BatchEvictionInterval is the time between two batches of evictions. Defaults to 1m\n+optional",
 	}
 }
 
 func (KubeVirtStatus) SwaggerDoc() map[string]string {
 	return map[string]string{
-		"": "KubeVirtStatus represents information pertaining to a KubeVirt deployment.\n\n+k8s:openapi-gen=true",
+		"":              "KubeVirtStatus represents information pertaining to a KubeVirt deployment.\n\n+k8s:openapi-gen=true",
+		"canaryRollout": "CanaryRollout reports the progress of the canary rollout of virt-handler",
 	}
 }
 
@@ -332,6 +516,225 @@ func (KubeVirtCondition) SwaggerDoc() map[string]string {
 	}
 }
 
+func (ConformanceRun) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "ConformanceRun exercises a matrix of VM features on the cluster and reports the results,\nso that hardware and storage vendors can validate their stack against this KubeVirt build\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (ConformanceRunList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "ConformanceRunList is a list of ConformanceRuns\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (ConformanceRunSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"features":         "Features to exercise, in the given order.\nDefaults to all known features.\n+optional",
+		"image":            "The containerDisk image the test VMIs boot from.\nDefaults to kubevirt/cirros-container-disk-demo.\n+optional",
+		"sriovNetworkName": "The multus network of type SR-IOV the SRIOV feature attaches to.\nThe SRIOV feature is skipped if not set.\n+optional",
+		"featureTimeout":   "How long a single feature may take before it is reported as failed.\nDefaults to 5 minutes.\n+optional",
+		"storageClasses":   "The storage classes to check. The Boot, Migrate and Hotplug features are\nexercised again for every storage class, with a VMI booting from a\nDataVolume of the storage class the image is imported into.\n+optional",
+		"networkCheckup":   "The two VMIs the NetworkLatency feature measures the latency and the\nthroughput between. The NetworkLatency feature is skipped if not set.\n+optional",
+	}
+}
+
+func (ConformanceNetworkCheckup) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "ConformanceNetworkCheckup places two VMIs on a network to measure the latency\nand the throughput between them\n\n+k8s:openapi-gen=true",
+		"networkName": "The multus network the VMIs are attached to, like an SR-IOV or a bridge network.\nThe VMIs get the addresses 192.168.100.10/24 and 192.168.100.20/24 on it.\nDefaults to the pod network.\n+optional",
+		"sriov":       "Whether the multus network is of type SR-IOV, otherwise the VMIs are bridged to it\n+optional",
+		"sourceNode":  "The node of the VMI sending the probes.\nDefaults to any node.\n+optional",
+		"targetNode":  "The node of the probed VMI.\nDefaults to any node.\n+optional",
+		"image":       "The containerDisk image of the VMIs. It has to provide ping, and iperf3\nfor the throughput to be measured.\nDefaults to the image of the run.\n+optional",
+		"maxLatency":  "The highest average round-trip time for the NetworkLatency feature to pass.\nWithout it the feature passes whenever the probed VMI answers.\n+optional",
+	}
+}
+
+func (ConformanceRunStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "ConformanceRunStatus is the machine-readable report of a ConformanceRun\n\n+k8s:openapi-gen=true",
+		"kubeVirtVersion":     "The version of KubeVirt the features were exercised against\n+optional",
+		"startTimestamp":      "+optional\n+nullable",
+		"completionTimestamp": "+optional\n+nullable",
+		"results":             "The result of every feature exercised so far\n+optional",
+	}
+}
+
+func (ConformanceFeatureResult) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "ConformanceFeatureResult is the result of exercising a single feature\n\n+k8s:openapi-gen=true",
+		"storageClass":        "The storage class the feature was exercised with, if any\n+optional",
+		"networkCheckup":      "The measurements of the NetworkLatency feature\n+optional",
+		"message":             "Why the feature failed or was skipped\n+optional",
+		"startTimestamp":      "+optional\n+nullable",
+		"completionTimestamp": "+optional\n+nullable",
+	}
+}
+
+func (ConformanceNetworkCheckupResult) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "ConformanceNetworkCheckupResult are the measurements between the two VMIs of a\nnetwork checkup\n\n+k8s:openapi-gen=true",
+		"throughputBitsPerSecond": "The throughput from the source to the target VMI in bits per second,\nonly measured if the image provides iperf3\n+optional",
+	}
+}
+
+func (VirtualMachineExport) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineExport exposes the volumes of a stopped VirtualMachine over an authenticated\nHTTPS endpoint, so that they can be downloaded or imported into another namespace or cluster\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineExportList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineExportList is a list of VirtualMachineExports\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineExportSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"source":         "The name of the VirtualMachine to export. It has to live in the namespace of the export.",
+		"tokenSecretRef": "The name of a Secret in the namespace of the export. Clients have to present the value\nof its \"token\" key in the x-kubevirt-export-token header, or as basic auth password.",
+	}
+}
+
+func (VirtualMachineExportStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "VirtualMachineExportStatus contains the links to the exported volumes\n\n+k8s:openapi-gen=true",
+		"message":     "Why the export is not ready\n+optional",
+		"serviceName": "The service the volumes are served from\n+optional",
+		"cert":        "The PEM encoded CA certificate the certificate of the export server is signed with\n+optional",
+		"manifest":    "Link to a VirtualMachine manifest, which imports the exported volumes through\nDataVolumes. The DataVolumes expect the token as secretKey in a Secret named like\nthe tokenSecretRef, and Cert in a ConfigMap named like the export.\n+optional",
+		"volumes":     "The links to the exported volumes\n+optional",
+	}
+}
+
+func (VirtualMachineExportVolume) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":     "VirtualMachineExportVolume contains the links to a single exported volume\n\n+k8s:openapi-gen=true",
+		"name": "The name of the volume in the VirtualMachine",
+	}
+}
+
+func (VirtualMachineExportVolumeFormat) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineExportVolumeFormat is the link to a volume in a specific format\n\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineBackup) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineBackup starts a pull mode backup of the disks of a running VirtualMachineInstance.\nWhile the backup is ready, the disks are exported over NBD through the backup subresource of the\nVirtualMachineInstance. Every backup records a checkpoint, which later backups can reference to\nonly export the blocks which changed since then. Deleting the backup ends the export.\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineBackupList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineBackupList is a list of VirtualMachineBackups\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineBackupSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"source":      "The name of the VirtualMachineInstance to back up. It has to live in the namespace of the backup.",
+		"incremental": "The name of an earlier backup of the same VirtualMachineInstance. If set, the exported disks\ncarry a dirty bitmap with the blocks which changed since the checkpoint of that backup.\n+optional",
+	}
+}
+
+func (VirtualMachineBackupStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "VirtualMachineBackupStatus describes where the backed up disks can be read from\n\n+k8s:openapi-gen=true",
+		"message":    "Why the backup is not ready\n+optional",
+		"checkpoint": "The checkpoint recorded by this backup, which later backups can be incremental to\n+optional",
+		"volumes":    "The disks exported by the backup\n+optional",
+	}
+}
+
+func (VirtualMachineBackupVolume) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "VirtualMachineBackupVolume describes how a single disk is exported over NBD\n\n+k8s:openapi-gen=true",
+		"name":        "The name of the volume in the VirtualMachineInstance",
+		"exportName":  "The NBD export name of the disk",
+		"dirtyBitmap": "The name of the dirty bitmap of an incremental backup. It is exposed in the\nqemu:dirty-bitmap:<name> NBD metadata context.\n+optional",
+	}
+}
+
+func (VirtualMachineInstancetype) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineInstancetype describes the CPU and memory of VirtualMachines.\nVirtualMachines referencing it must not set these fields themselves.\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineInstancetypeList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachineInstancetypeList is a list of VirtualMachineInstancetypes\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachineInstancetypeSpec) SwaggerDoc() map[string]string {
+	return map[string]string{}
+}
+
+func (CPUInstancetype) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                      "CPUInstancetype describes the CPU of a VirtualMachineInstancetype\n\n+k8s:openapi-gen=true",
+		"guest":                 "The number of vCPUs of the guest. How they are arranged in sockets, cores and threads\nis taken from the preferred CPU topology of the VirtualMachinePreference.",
+		"model":                 "The CPU model of the guest\n+optional",
+		"dedicatedCpuPlacement": "Pin the vCPUs to dedicated pCPUs of the node\n+optional",
+	}
+}
+
+func (MemoryInstancetype) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "MemoryInstancetype describes the memory of a VirtualMachineInstancetype\n\n+k8s:openapi-gen=true",
+		"guest":     "The amount of memory visible to the guest",
+		"hugepages": "Back the memory of the guest with hugepages\n+optional",
+	}
+}
+
+func (VirtualMachinePreference) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachinePreference describes preferred values for fields of VirtualMachines.\nThe preferences are only applied to fields the VirtualMachines leave unset.\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachinePreferenceList) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "VirtualMachinePreferenceList is a list of VirtualMachinePreferences\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+	}
+}
+
+func (VirtualMachinePreferenceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"cpu":     "+optional",
+		"devices": "+optional",
+		"machine": "+optional",
+	}
+}
+
+func (CPUPreferences) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "CPUPreferences describes the preferred CPU of a VirtualMachinePreference\n\n+k8s:openapi-gen=true",
+		"preferredCPUTopology": "How the vCPUs of the instancetype are arranged, one of preferSockets, preferCores or preferThreads.\nDefaults to preferSockets.\n+optional",
+	}
+}
+
+func (DevicePreferences) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                                  "DevicePreferences describes the preferred devices of a VirtualMachinePreference\n\n+k8s:openapi-gen=true",
+		"preferredAutoattachGraphicsDevice": "Whether to attach the default graphics device, if the VirtualMachine does not say\n+optional",
+		"preferredDiskBus":                  "The bus of disks which don't set one\n+optional",
+		"preferredInterfaceModel":           "The model of interfaces which don't set one\n+optional",
+	}
+}
+
+func (MachinePreferences) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "MachinePreferences describes the preferred machine of a VirtualMachinePreference\n\n+k8s:openapi-gen=true",
+		"preferredMachineType": "The machine type, if the VirtualMachine does not set one\n+optional",
+	}
+}
+
 func (RestartOptions) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                   "RestartOptions may be provided when deleting an API object.\n\n+k8s:openapi-gen=true",
@@ -339,6 +742,13 @@ func (RestartOptions) SwaggerDoc() map[string]string {
 	}
 }
 
+func (StopOptions) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                   "StopOptions may be provided when stopping a VirtualMachine.\n\n+k8s:openapi-gen=true",
+		"gracePeriodSeconds": "The duration in seconds before the VirtualMachineInstance is force-stopped. Value must be non-negative integer.\nThe value zero indicates, stop immediately. If this value is nil, the termination grace period of the\nVirtualMachineInstance is used.\nAllowed Values: nil and 0\n+optional",
+	}
+}
+
 func (VirtualMachineInstanceGuestAgentInfo) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"":                  "VirtualMachineInstanceGuestAgentInfo represents information from the installed guest agent\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
@@ -381,18 +791,180 @@ func (VirtualMachineInstanceFileSystem) SwaggerDoc() map[string]string {
 	}
 }
 
+func (VirtualMachineInstanceStats) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                     "VirtualMachineInstanceStats represents a sample of the resource usage of a running VMI\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+		"timestamp":            "Timestamp of the sample",
+		"cpuTimeNanoseconds":   "CPU time consumed by the domain since it was started",
+		"vcpus":                "Number of vCPUs of the domain",
+		"memoryResidentBytes":  "Resident set size of the domain",
+		"memoryAvailableBytes": "Memory available to the guest, as reported by the balloon driver",
+		"storageReadBytes":     "Bytes read from all disks since the domain was started",
+		"storageWriteBytes":    "Bytes written to all disks since the domain was started",
+		"networkRxBytes":       "Bytes received on all interfaces since the domain was started",
+		"networkTxBytes":       "Bytes transmitted on all interfaces since the domain was started",
+	}
+}
+
+func (VNCToken) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                    "VNCToken grants the user who requested it a single connection to the VNC of a VirtualMachineInstance\n\n+k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object\n+k8s:openapi-gen=true",
+		"token":               "Token to pass in the token query parameter of the vnc subresource",
+		"expirationTimestamp": "Time after which the token is not accepted anymore",
+	}
+}
+
 func (RenameOptions) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "Options for a rename operation",
 	}
 }
 
+func (RollbackOptions) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "Options for a rollback operation",
+		"revisionName": "The ControllerRevision holding the template to roll back to",
+	}
+}
+
+func (MemoryDumpOptions) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":          "Options for a memory dump operation",
+		"claimName": "The PersistentVolumeClaim the memory of the guest is dumped to",
+	}
+}
+
 func (KubeVirtConfiguration) SwaggerDoc() map[string]string {
 	return map[string]string{
 		"": "KubeVirtConfiguration holds all kubevirt configurations\n+k8s:openapi-gen=true",
 	}
 }
 
+func (KSMConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                        "KSMConfiguration holds the options for managing kernel samepage merging on the nodes\n+k8s:openapi-gen=true",
+		"memoryPressureThreshold": "MemoryPressureThreshold is the percentage of used memory of a node above\nwhich virt-handler enables KSM on it. Defaults to 80\n+optional",
+	}
+}
+
+func (MultiQueueConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "MultiQueueConfiguration holds the options for sizing the virtio queues of new vmis\n+k8s:openapi-gen=true",
+		"autoTuning": "AutoTuning enables multi-queue on the virtio network interfaces and disks of new\nvmis which don't configure it, with one queue per vCPU\n+optional",
+		"maxQueues":  "MaxQueues caps the number of queues set by the auto tuning. Defaults to 8\n+optional",
+	}
+}
+
+func (DefaultNetworkPolicyConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "DefaultNetworkPolicyConfiguration holds the options for the network policy restricting\nthe egress traffic of the virt-launcher pods in every namespace running vmis\n+k8s:openapi-gen=true",
+		"mode":         "Mode is Create to let virt-controller create and update the network policy, or\nValidate to only warn about vmis in namespaces without a network policy\nrestricting the egress traffic of the virt-launcher pods",
+		"blockedCIDRs": "BlockedCIDRs are the destinations outside of the cluster the virt-launcher\npods can not reach. Defaults to the node metadata service 169.254.169.254/32\n+optional",
+	}
+}
+
+func (AuditConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "AuditConfiguration holds the options for the audit events virt-api emits for\nprivileged operations on vmis, like console connections or memory dumps\n+k8s:openapi-gen=true",
+		"webhookURL": "WebhookURL is the address of a dedicated audit sink. The audit events are posted\nto it as audit.k8s.io/v1 EventList, like by the audit webhook backend of kube-apiserver\n+optional",
+		"caBundle":   "CABundle is the PEM encoded CA bundle verifying the certificate of the audit sink.\nDefaults to the system trust store\n+optional",
+	}
+}
+
+func (NodeFencingConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "NodeFencingConfiguration holds the options for fencing the vmis on nodes whose\nvirt-handler stopped sending heartbeats\n+k8s:openapi-gen=true",
+		"enabled":      "Enabled fences the vmis on nodes which are unresponsive and confirmed to be dead. Their\nvirt-launcher pods are force deleted and they are moved to the Failed phase, so that their\nVirtualMachines can start them on other nodes. Only enable it if the confirmed nodes are\nreliably powered off, the guests could otherwise run twice.\n+optional",
+		"gracePeriod":  "GracePeriod is how long a node has to be unresponsive before its vmis are fenced.\nDefaults to 5m\n+optional",
+		"confirmation": "Confirmation holds the rules confirming that an unresponsive node is dead before its\nvmis are fenced. Any of the rules confirms a node. Defaults to the node not being ready\n+optional",
+	}
+}
+
+func (NodeFencingConfirmation) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                  "NodeFencingConfirmation holds the rules confirming that an unresponsive node is dead,\nso that the guests of its vmis can't run twice\n+k8s:openapi-gen=true",
+		"notReady":          "NotReady confirms nodes whose kubelet is not ready either, once the grace period passed.\nDefaults to true if no other rule is set\n+optional",
+		"outOfServiceTaint": "OutOfServiceTaint confirms nodes tainted with node.kubernetes.io/out-of-service, which\nmarks nodes known to be shut down. The grace period does not apply\n+optional",
+		"resources":         "Resources confirm nodes for which an object of one of the resources exists that is named\nafter the node, like the remediation objects of fencing operators. virt-controller has to\nbe allowed to list them. The grace period does not apply\n+optional",
+	}
+}
+
+func (NodeFencingResource) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "NodeFencingResource is a resource whose objects are named after the nodes they confirm as dead\n+k8s:openapi-gen=true",
+		"group":    "Group of the resource, empty for the core API group\n+optional",
+		"version":  "Version of the resource",
+		"resource": "Resource is the plural name of the resource",
+	}
+}
+
+func (SeccompConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":              "SeccompConfiguration selects the seccomp profile the virt-launcher pods run with\n+k8s:openapi-gen=true",
+		"type":          "Type is RuntimeDefault for the default profile of the container runtime, KubeVirt for\nthe kubevirt/default profile tailored to qemu, which virt-handler installs on every node,\nor Custom for a profile the admin installed on the nodes. The virt-launcher pods run with\nthe profile the container runtime picks for pods without one if no type is set\n+optional",
+		"customProfile": "CustomProfile is the path of the custom profile, relative to the seccomp directory of\nthe kubelet. Required with the Custom type\n+optional",
+	}
+}
+
+func (MetricsConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":           "MetricsConfiguration holds the options for the metrics the KubeVirt components expose\n+k8s:openapi-gen=true",
+		"namingMode": "NamingMode selects the names of the metrics renamed to follow the naming conventions of\nPrometheus: Legacy exposes them under their deprecated names, Current under their new\nnames and Both under both names, to migrate dashboards and alerts. Defaults to Both\n+optional",
+	}
+}
+
+func (FilesystemOverheadConfiguration) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":             "FilesystemOverheadConfiguration holds the share of filesystem volumes their filesystem\ntakes. The storage sizes requested for the DataVolume templates of virtual machines on\nfilesystem volumes are increased by it, so that their disk image fits\n+k8s:openapi-gen=true",
+		"global":       "Global is the overhead of the storage classes without their own, in percent\nlike \"5.5\". DataVolume templates without a storage class get it as well\n+optional",
+		"storageClass": "StorageClass maps the names of storage classes to their overhead, in percent\n+optional",
+	}
+}
+
+func (ClusterCapabilities) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                 "ClusterCapabilities describes what the cluster supports, so that clients can\nadapt to it without reading the KubeVirt CR\n+k8s:openapi-gen=true",
+		"featureGates":     "FeatureGates are the feature gates enabled on the cluster\n+optional",
+		"machineType":      "MachineType is the machine type of vmis which don't request one",
+		"emulatedMachines": "EmulatedMachines are the machine types vmis may request, as glob patterns\n+optional",
+		"architecture":     "Architecture is the architecture the defaults are chosen for",
+		"configuration":    "Configuration is the effective configuration of the cluster",
+	}
+}
+
+func (LauncherResources) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "LauncherResources are the resources of the virt-launcher pod of a vmi, as they\nare accounted by the scheduler and against resource quotas\n+k8s:openapi-gen=true",
+		"requests": "Requests are the resources requested by the pod",
+		"limits":   "Limits are the resource limits of the pod\n+optional",
+		"overhead": "Overhead is the part of the requests which is not requested for the guest\n+optional",
+	}
+}
+
+func (PermittedHostDevices) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"": "PermittedHostDevices holds the host devices which may be passed through to vmis\n+k8s:openapi-gen=true",
+	}
+}
+
+func (PciHostDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                         "PciHostDevice represents a PCI device of the hosts which may be passed through to vmis\n+k8s:openapi-gen=true",
+		"pciVendorSelector":        "PCIVendorSelector selects the devices by their vendor and device ID, e.g. \"10de:1eb8\"",
+		"resourceName":             "ResourceName is the name under which the devices are exposed as a node resource",
+		"externalResourceProvider": "ExternalResourceProvider indicates that the devices are exposed by an external\ndevice plugin instead of virt-handler\n+optional",
+	}
+}
+
+func (MediatedHostDevice) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                         "MediatedHostDevice represents a mediated device type of the hosts which may be passed through to vmis\n+k8s:openapi-gen=true",
+		"mdevNameSelector":         "MDEVNameSelector selects the mediated devices by the name of their type, e.g. \"GRID T4-1Q\"",
+		"resourceName":             "ResourceName is the name under which the devices are exposed as a node resource",
+		"externalResourceProvider": "ExternalResourceProvider indicates that the devices are exposed by an external\ndevice plugin instead of virt-handler\n+optional",
+	}
+}
+
 func (SMBiosConfiguration) SwaggerDoc() map[string]string {
 	return map[string]string{}
 }
@@ -0,0 +1,160 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+func (k *kubevirt) ConformanceRun(namespace string) ConformanceRunInterface {
+	return &conformanceRun{
+		restClient: k.restClient,
+		namespace:  namespace,
+		resource:   "conformanceruns",
+	}
+}
+
+type conformanceRun struct {
+	restClient *rest.RESTClient
+	namespace  string
+	resource   string
+}
+
+// Create new ConformanceRun in the cluster to specified namespace
+func (o *conformanceRun) Create(run *v1.ConformanceRun) (*v1.ConformanceRun, error) {
+	newRun := &v1.ConformanceRun{}
+	err := o.restClient.Post().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Body(run).
+		Do().
+		Into(newRun)
+
+	newRun.SetGroupVersionKind(v1.ConformanceRunGroupVersionKind)
+
+	return newRun, err
+}
+
+// Get the ConformanceRun from the cluster by its name and namespace
+func (o *conformanceRun) Get(name string, options *k8smetav1.GetOptions) (*v1.ConformanceRun, error) {
+	newRun := &v1.ConformanceRun{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newRun)
+
+	newRun.SetGroupVersionKind(v1.ConformanceRunGroupVersionKind)
+
+	return newRun, err
+}
+
+// Update the ConformanceRun in the cluster in given namespace
+func (o *conformanceRun) Update(run *v1.ConformanceRun) (*v1.ConformanceRun, error) {
+	updatedRun := &v1.ConformanceRun{}
+	err := o.restClient.Put().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(run.Name).
+		Body(run).
+		Do().
+		Into(updatedRun)
+
+	updatedRun.SetGroupVersionKind(v1.ConformanceRunGroupVersionKind)
+
+	return updatedRun, err
+}
+
+// Delete the defined ConformanceRun in the cluster in defined namespace
+func (o *conformanceRun) Delete(name string, options *k8smetav1.DeleteOptions) error {
+	err := o.restClient.Delete().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+
+	return err
+}
+
+// List all ConformanceRuns in given namespace
+func (o *conformanceRun) List(options *k8smetav1.ListOptions) (*v1.ConformanceRunList, error) {
+	newRunList := &v1.ConformanceRunList{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newRunList)
+
+	for _, run := range newRunList.Items {
+		run.SetGroupVersionKind(v1.ConformanceRunGroupVersionKind)
+	}
+
+	return newRunList, err
+}
+
+func (v *conformanceRun) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ConformanceRun, err error) {
+	result = &v1.ConformanceRun{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return result, err
+}
+
+func (v *conformanceRun) PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.ConformanceRun, err error) {
+	result = &v1.ConformanceRun{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource("status").
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
+
+func (v *conformanceRun) UpdateStatus(run *v1.ConformanceRun) (result *v1.ConformanceRun, err error) {
+	result = &v1.ConformanceRun{}
+	err = v.restClient.Put().
+		Name(run.ObjectMeta.Name).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource("status").
+		Body(run).
+		Do().
+		Into(result)
+	result.SetGroupVersionKind(v1.ConformanceRunGroupVersionKind)
+	return
+}
@@ -0,0 +1,145 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Kubevirt ConformanceRun Client", func() {
+
+	var server *ghttp.Server
+	var client KubevirtClient
+	basePath := "/apis/kubevirt.io/v1alpha3/namespaces/default/conformanceruns"
+	runPath := basePath + "/testrun"
+
+	BeforeEach(func() {
+		var err error
+		server = ghttp.NewServer()
+		client, err = GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fetch a ConformanceRun", func() {
+		run := NewMinimalConformanceRun("testrun")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", runPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, run),
+		))
+		fetchedRun, err := client.ConformanceRun(k8sv1.NamespaceDefault).Get("testrun", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedRun).To(Equal(run))
+	})
+
+	It("should detect non existent ConformanceRuns", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", runPath),
+			ghttp.RespondWithJSONEncoded(http.StatusNotFound, errors.NewNotFound(schema.GroupResource{}, "testrun")),
+		))
+		_, err := client.ConformanceRun(k8sv1.NamespaceDefault).Get("testrun", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fetch a ConformanceRun list", func() {
+		run := NewMinimalConformanceRun("testrun")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, NewConformanceRunList(*run)),
+		))
+		fetchedRunList, err := client.ConformanceRun(k8sv1.NamespaceDefault).List(&k8smetav1.ListOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedRunList.Items).To(HaveLen(1))
+		Expect(fetchedRunList.Items[0]).To(Equal(*run))
+	})
+
+	It("should create a ConformanceRun", func() {
+		run := NewMinimalConformanceRun("testrun")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusCreated, run),
+		))
+		createdRun, err := client.ConformanceRun(k8sv1.NamespaceDefault).Create(run)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(createdRun).To(Equal(run))
+	})
+
+	It("should update a ConformanceRun", func() {
+		run := NewMinimalConformanceRun("testrun")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", runPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, run),
+		))
+		updatedRun, err := client.ConformanceRun(k8sv1.NamespaceDefault).Update(run)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedRun).To(Equal(run))
+	})
+
+	It("should patch a ConformanceRun", func() {
+		run := NewMinimalConformanceRun("testrun")
+		run.Spec.Image = "somethingelse"
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PATCH", runPath),
+			ghttp.VerifyBody([]byte("{\"spec\":{\"image\":something}}")),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, run),
+		))
+
+		_, err := client.ConformanceRun(k8sv1.NamespaceDefault).Patch(run.Name, types.MergePatchType,
+			[]byte("{\"spec\":{\"image\":something}}"))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete a ConformanceRun", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("DELETE", runPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.ConformanceRun(k8sv1.NamespaceDefault).Delete("testrun", &k8smetav1.DeleteOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+})
@@ -58,6 +58,7 @@ import (
 	v1alpha16 "kubevirt.io/client-go/generated/kubevirt/clientset/versioned/typed/snapshot/v1alpha1"
 	versioned2 "kubevirt.io/client-go/generated/network-attachment-definition-client/clientset/versioned"
 	versioned3 "kubevirt.io/client-go/generated/prometheus-operator/clientset/versioned"
+	time "time"
 )
 
 // Mock of KubevirtClient interface
@@ -131,6 +132,56 @@ func (_mr *_MockKubevirtClientRecorder) KubeVirt(arg0 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "KubeVirt", arg0)
 }
 
+func (_m *MockKubevirtClient) ConformanceRun(namespace string) ConformanceRunInterface {
+	ret := _m.ctrl.Call(_m, "ConformanceRun", namespace)
+	ret0, _ := ret[0].(ConformanceRunInterface)
+	return ret0
+}
+
+func (_mr *_MockKubevirtClientRecorder) ConformanceRun(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ConformanceRun", arg0)
+}
+
+func (_m *MockKubevirtClient) VirtualMachineExport(namespace string) VirtualMachineExportInterface {
+	ret := _m.ctrl.Call(_m, "VirtualMachineExport", namespace)
+	ret0, _ := ret[0].(VirtualMachineExportInterface)
+	return ret0
+}
+
+func (_mr *_MockKubevirtClientRecorder) VirtualMachineExport(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VirtualMachineExport", arg0)
+}
+
+func (_m *MockKubevirtClient) VirtualMachineBackup(namespace string) VirtualMachineBackupInterface {
+	ret := _m.ctrl.Call(_m, "VirtualMachineBackup", namespace)
+	ret0, _ := ret[0].(VirtualMachineBackupInterface)
+	return ret0
+}
+
+func (_mr *_MockKubevirtClientRecorder) VirtualMachineBackup(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VirtualMachineBackup", arg0)
+}
+
+func (_m *MockKubevirtClient) VirtualMachineInstancetype(namespace string) VirtualMachineInstancetypeInterface {
+	ret := _m.ctrl.Call(_m, "VirtualMachineInstancetype", namespace)
+	ret0, _ := ret[0].(VirtualMachineInstancetypeInterface)
+	return ret0
+}
+
+func (_mr *_MockKubevirtClientRecorder) VirtualMachineInstancetype(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VirtualMachineInstancetype", arg0)
+}
+
+func (_m *MockKubevirtClient) VirtualMachinePreference(namespace string) VirtualMachinePreferenceInterface {
+	ret := _m.ctrl.Call(_m, "VirtualMachinePreference", namespace)
+	ret0, _ := ret[0].(VirtualMachinePreferenceInterface)
+	return ret0
+}
+
+func (_mr *_MockKubevirtClientRecorder) VirtualMachinePreference(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VirtualMachinePreference", arg0)
+}
+
 func (_m *MockKubevirtClient) VirtualMachineInstancePreset(namespace string) VirtualMachineInstancePresetInterface {
 	ret := _m.ctrl.Call(_m, "VirtualMachineInstancePreset", namespace)
 	ret0, _ := ret[0].(VirtualMachineInstancePresetInterface)
@@ -805,6 +856,50 @@ func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) VNC(arg0 interface{}) *
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "VNC", arg0)
 }
 
+func (_m *MockVirtualMachineInstanceInterface) VNCToken(name string, duration time.Duration) (v114.VNCToken, error) {
+	ret := _m.ctrl.Call(_m, "VNCToken", name, duration)
+	ret0, _ := ret[0].(v114.VNCToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) VNCToken(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "VNCToken", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineInstanceInterface) PortForward(name string, port int) (StreamInterface, error) {
+	ret := _m.ctrl.Call(_m, "PortForward", name, port)
+	ret0, _ := ret[0].(StreamInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) PortForward(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PortForward", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineInstanceInterface) USBRedir(name string) (StreamInterface, error) {
+	ret := _m.ctrl.Call(_m, "USBRedir", name)
+	ret0, _ := ret[0].(StreamInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) USBRedir(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "USBRedir", arg0)
+}
+
+func (_m *MockVirtualMachineInstanceInterface) Backup(name string) (StreamInterface, error) {
+	ret := _m.ctrl.Call(_m, "Backup", name)
+	ret0, _ := ret[0].(StreamInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) Backup(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Backup", arg0)
+}
+
 func (_m *MockVirtualMachineInstanceInterface) Pause(name string) error {
 	ret := _m.ctrl.Call(_m, "Pause", name)
 	ret0, _ := ret[0].(error)
@@ -825,6 +920,36 @@ func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) Unpause(arg0 interface{
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Unpause", arg0)
 }
 
+func (_m *MockVirtualMachineInstanceInterface) Freeze(name string) error {
+	ret := _m.ctrl.Call(_m, "Freeze", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) Freeze(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Freeze", arg0)
+}
+
+func (_m *MockVirtualMachineInstanceInterface) Thaw(name string) error {
+	ret := _m.ctrl.Call(_m, "Thaw", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) Thaw(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Thaw", arg0)
+}
+
+func (_m *MockVirtualMachineInstanceInterface) MemoryDump(name string, options *v114.MemoryDumpOptions) error {
+	ret := _m.ctrl.Call(_m, "MemoryDump", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) MemoryDump(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "MemoryDump", arg0, arg1)
+}
+
 func (_m *MockVirtualMachineInstanceInterface) GuestOsInfo(name string) (v114.VirtualMachineInstanceGuestAgentInfo, error) {
 	ret := _m.ctrl.Call(_m, "GuestOsInfo", name)
 	ret0, _ := ret[0].(v114.VirtualMachineInstanceGuestAgentInfo)
@@ -858,6 +983,17 @@ func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) FilesystemList(arg0 int
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FilesystemList", arg0)
 }
 
+func (_m *MockVirtualMachineInstanceInterface) Stats(name string) (v114.VirtualMachineInstanceStats, error) {
+	ret := _m.ctrl.Call(_m, "Stats", name)
+	ret0, _ := ret[0].(v114.VirtualMachineInstanceStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstanceInterfaceRecorder) Stats(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stats", arg0)
+}
+
 // Mock of ReplicaSetInterface interface
 type MockReplicaSetInterface struct {
 	ctrl     *gomock.Controller
@@ -1237,6 +1373,16 @@ func (_mr *_MockVirtualMachineInterfaceRecorder) Stop(arg0 interface{}) *gomock.
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stop", arg0)
 }
 
+func (_m *MockVirtualMachineInterface) ForceStop(name string, graceperiod int) error {
+	ret := _m.ctrl.Call(_m, "ForceStop", name, graceperiod)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInterfaceRecorder) ForceStop(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ForceStop", arg0, arg1)
+}
+
 func (_m *MockVirtualMachineInterface) Migrate(name string) error {
 	ret := _m.ctrl.Call(_m, "Migrate", name)
 	ret0, _ := ret[0].(error)
@@ -1257,6 +1403,16 @@ func (_mr *_MockVirtualMachineInterfaceRecorder) Rename(arg0, arg1 interface{})
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Rename", arg0, arg1)
 }
 
+func (_m *MockVirtualMachineInterface) Rollback(name string, options *v114.RollbackOptions) error {
+	ret := _m.ctrl.Call(_m, "Rollback", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInterfaceRecorder) Rollback(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Rollback", arg0, arg1)
+}
+
 // Mock of VirtualMachineInstanceMigrationInterface interface
 type MockVirtualMachineInstanceMigrationInterface struct {
 	ctrl     *gomock.Controller
@@ -1482,3 +1638,524 @@ func (_m *MockKubeVirtInterface) PatchStatus(name string, pt types.PatchType, da
 func (_mr *_MockKubeVirtInterfaceRecorder) PatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "PatchStatus", arg0, arg1, arg2)
 }
+
+// Mock of ConformanceRunInterface interface
+type MockConformanceRunInterface struct {
+	ctrl     *gomock.Controller
+	recorder *_MockConformanceRunInterfaceRecorder
+}
+
+// Recorder for MockConformanceRunInterface (not exported)
+type _MockConformanceRunInterfaceRecorder struct {
+	mock *MockConformanceRunInterface
+}
+
+func NewMockConformanceRunInterface(ctrl *gomock.Controller) *MockConformanceRunInterface {
+	mock := &MockConformanceRunInterface{ctrl: ctrl}
+	mock.recorder = &_MockConformanceRunInterfaceRecorder{mock}
+	return mock
+}
+
+func (_m *MockConformanceRunInterface) EXPECT() *_MockConformanceRunInterfaceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockConformanceRunInterface) Get(name string, options *v11.GetOptions) (*v114.ConformanceRun, error) {
+	ret := _m.ctrl.Call(_m, "Get", name, options)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1)
+}
+
+func (_m *MockConformanceRunInterface) List(opts *v11.ListOptions) (*v114.ConformanceRunList, error) {
+	ret := _m.ctrl.Call(_m, "List", opts)
+	ret0, _ := ret[0].(*v114.ConformanceRunList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) List(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "List", arg0)
+}
+
+func (_m *MockConformanceRunInterface) Create(instance *v114.ConformanceRun) (*v114.ConformanceRun, error) {
+	ret := _m.ctrl.Call(_m, "Create", instance)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) Create(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Create", arg0)
+}
+
+func (_m *MockConformanceRunInterface) Update(_param0 *v114.ConformanceRun) (*v114.ConformanceRun, error) {
+	ret := _m.ctrl.Call(_m, "Update", _param0)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) Update(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Update", arg0)
+}
+
+func (_m *MockConformanceRunInterface) Delete(name string, options *v11.DeleteOptions) error {
+	ret := _m.ctrl.Call(_m, "Delete", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1)
+}
+
+func (_m *MockConformanceRunInterface) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*v114.ConformanceRun, error) {
+	_s := []interface{}{name, pt, data}
+	for _, _x := range subresources {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Patch", _s...)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) Patch(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	_s := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Patch", _s...)
+}
+
+func (_m *MockConformanceRunInterface) UpdateStatus(_param0 *v114.ConformanceRun) (*v114.ConformanceRun, error) {
+	ret := _m.ctrl.Call(_m, "UpdateStatus", _param0)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) UpdateStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateStatus", arg0)
+}
+
+func (_m *MockConformanceRunInterface) PatchStatus(name string, pt types.PatchType, data []byte) (*v114.ConformanceRun, error) {
+	ret := _m.ctrl.Call(_m, "PatchStatus", name, pt, data)
+	ret0, _ := ret[0].(*v114.ConformanceRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockConformanceRunInterfaceRecorder) PatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PatchStatus", arg0, arg1, arg2)
+}
+
+// Mock of VirtualMachineExportInterface interface
+type MockVirtualMachineExportInterface struct {
+	ctrl     *gomock.Controller
+	recorder *_MockVirtualMachineExportInterfaceRecorder
+}
+
+// Recorder for MockVirtualMachineExportInterface (not exported)
+type _MockVirtualMachineExportInterfaceRecorder struct {
+	mock *MockVirtualMachineExportInterface
+}
+
+func NewMockVirtualMachineExportInterface(ctrl *gomock.Controller) *MockVirtualMachineExportInterface {
+	mock := &MockVirtualMachineExportInterface{ctrl: ctrl}
+	mock.recorder = &_MockVirtualMachineExportInterfaceRecorder{mock}
+	return mock
+}
+
+func (_m *MockVirtualMachineExportInterface) EXPECT() *_MockVirtualMachineExportInterfaceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockVirtualMachineExportInterface) Get(name string, options *v11.GetOptions) (*v114.VirtualMachineExport, error) {
+	ret := _m.ctrl.Call(_m, "Get", name, options)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineExportInterface) List(opts *v11.ListOptions) (*v114.VirtualMachineExportList, error) {
+	ret := _m.ctrl.Call(_m, "List", opts)
+	ret0, _ := ret[0].(*v114.VirtualMachineExportList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) List(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "List", arg0)
+}
+
+func (_m *MockVirtualMachineExportInterface) Create(instance *v114.VirtualMachineExport) (*v114.VirtualMachineExport, error) {
+	ret := _m.ctrl.Call(_m, "Create", instance)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) Create(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Create", arg0)
+}
+
+func (_m *MockVirtualMachineExportInterface) Update(_param0 *v114.VirtualMachineExport) (*v114.VirtualMachineExport, error) {
+	ret := _m.ctrl.Call(_m, "Update", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) Update(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Update", arg0)
+}
+
+func (_m *MockVirtualMachineExportInterface) Delete(name string, options *v11.DeleteOptions) error {
+	ret := _m.ctrl.Call(_m, "Delete", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineExportInterface) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*v114.VirtualMachineExport, error) {
+	_s := []interface{}{name, pt, data}
+	for _, _x := range subresources {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Patch", _s...)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) Patch(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	_s := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Patch", _s...)
+}
+
+func (_m *MockVirtualMachineExportInterface) UpdateStatus(_param0 *v114.VirtualMachineExport) (*v114.VirtualMachineExport, error) {
+	ret := _m.ctrl.Call(_m, "UpdateStatus", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) UpdateStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateStatus", arg0)
+}
+
+func (_m *MockVirtualMachineExportInterface) PatchStatus(name string, pt types.PatchType, data []byte) (*v114.VirtualMachineExport, error) {
+	ret := _m.ctrl.Call(_m, "PatchStatus", name, pt, data)
+	ret0, _ := ret[0].(*v114.VirtualMachineExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineExportInterfaceRecorder) PatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PatchStatus", arg0, arg1, arg2)
+}
+
+// Mock of VirtualMachineBackupInterface interface
+type MockVirtualMachineBackupInterface struct {
+	ctrl     *gomock.Controller
+	recorder *_MockVirtualMachineBackupInterfaceRecorder
+}
+
+// Recorder for MockVirtualMachineBackupInterface (not exported)
+type _MockVirtualMachineBackupInterfaceRecorder struct {
+	mock *MockVirtualMachineBackupInterface
+}
+
+func NewMockVirtualMachineBackupInterface(ctrl *gomock.Controller) *MockVirtualMachineBackupInterface {
+	mock := &MockVirtualMachineBackupInterface{ctrl: ctrl}
+	mock.recorder = &_MockVirtualMachineBackupInterfaceRecorder{mock}
+	return mock
+}
+
+func (_m *MockVirtualMachineBackupInterface) EXPECT() *_MockVirtualMachineBackupInterfaceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockVirtualMachineBackupInterface) Get(name string, options *v11.GetOptions) (*v114.VirtualMachineBackup, error) {
+	ret := _m.ctrl.Call(_m, "Get", name, options)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineBackupInterface) List(opts *v11.ListOptions) (*v114.VirtualMachineBackupList, error) {
+	ret := _m.ctrl.Call(_m, "List", opts)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackupList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) List(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "List", arg0)
+}
+
+func (_m *MockVirtualMachineBackupInterface) Create(instance *v114.VirtualMachineBackup) (*v114.VirtualMachineBackup, error) {
+	ret := _m.ctrl.Call(_m, "Create", instance)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) Create(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Create", arg0)
+}
+
+func (_m *MockVirtualMachineBackupInterface) Update(_param0 *v114.VirtualMachineBackup) (*v114.VirtualMachineBackup, error) {
+	ret := _m.ctrl.Call(_m, "Update", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) Update(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Update", arg0)
+}
+
+func (_m *MockVirtualMachineBackupInterface) Delete(name string, options *v11.DeleteOptions) error {
+	ret := _m.ctrl.Call(_m, "Delete", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineBackupInterface) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*v114.VirtualMachineBackup, error) {
+	_s := []interface{}{name, pt, data}
+	for _, _x := range subresources {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Patch", _s...)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) Patch(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	_s := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Patch", _s...)
+}
+
+func (_m *MockVirtualMachineBackupInterface) UpdateStatus(_param0 *v114.VirtualMachineBackup) (*v114.VirtualMachineBackup, error) {
+	ret := _m.ctrl.Call(_m, "UpdateStatus", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) UpdateStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateStatus", arg0)
+}
+
+func (_m *MockVirtualMachineBackupInterface) PatchStatus(name string, pt types.PatchType, data []byte) (*v114.VirtualMachineBackup, error) {
+	ret := _m.ctrl.Call(_m, "PatchStatus", name, pt, data)
+	ret0, _ := ret[0].(*v114.VirtualMachineBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineBackupInterfaceRecorder) PatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PatchStatus", arg0, arg1, arg2)
+}
+
+// Mock of VirtualMachineInstancetypeInterface interface
+type MockVirtualMachineInstancetypeInterface struct {
+	ctrl     *gomock.Controller
+	recorder *_MockVirtualMachineInstancetypeInterfaceRecorder
+}
+
+// Recorder for MockVirtualMachineInstancetypeInterface (not exported)
+type _MockVirtualMachineInstancetypeInterfaceRecorder struct {
+	mock *MockVirtualMachineInstancetypeInterface
+}
+
+func NewMockVirtualMachineInstancetypeInterface(ctrl *gomock.Controller) *MockVirtualMachineInstancetypeInterface {
+	mock := &MockVirtualMachineInstancetypeInterface{ctrl: ctrl}
+	mock.recorder = &_MockVirtualMachineInstancetypeInterfaceRecorder{mock}
+	return mock
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) EXPECT() *_MockVirtualMachineInstancetypeInterfaceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) Get(name string, options *v11.GetOptions) (*v114.VirtualMachineInstancetype, error) {
+	ret := _m.ctrl.Call(_m, "Get", name, options)
+	ret0, _ := ret[0].(*v114.VirtualMachineInstancetype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) List(opts *v11.ListOptions) (*v114.VirtualMachineInstancetypeList, error) {
+	ret := _m.ctrl.Call(_m, "List", opts)
+	ret0, _ := ret[0].(*v114.VirtualMachineInstancetypeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) List(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "List", arg0)
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) Create(instance *v114.VirtualMachineInstancetype) (*v114.VirtualMachineInstancetype, error) {
+	ret := _m.ctrl.Call(_m, "Create", instance)
+	ret0, _ := ret[0].(*v114.VirtualMachineInstancetype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) Create(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Create", arg0)
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) Update(_param0 *v114.VirtualMachineInstancetype) (*v114.VirtualMachineInstancetype, error) {
+	ret := _m.ctrl.Call(_m, "Update", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachineInstancetype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) Update(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Update", arg0)
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) Delete(name string, options *v11.DeleteOptions) error {
+	ret := _m.ctrl.Call(_m, "Delete", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1)
+}
+
+func (_m *MockVirtualMachineInstancetypeInterface) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*v114.VirtualMachineInstancetype, error) {
+	_s := []interface{}{name, pt, data}
+	for _, _x := range subresources {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Patch", _s...)
+	ret0, _ := ret[0].(*v114.VirtualMachineInstancetype)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachineInstancetypeInterfaceRecorder) Patch(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	_s := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Patch", _s...)
+}
+
+// Mock of VirtualMachinePreferenceInterface interface
+type MockVirtualMachinePreferenceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *_MockVirtualMachinePreferenceInterfaceRecorder
+}
+
+// Recorder for MockVirtualMachinePreferenceInterface (not exported)
+type _MockVirtualMachinePreferenceInterfaceRecorder struct {
+	mock *MockVirtualMachinePreferenceInterface
+}
+
+func NewMockVirtualMachinePreferenceInterface(ctrl *gomock.Controller) *MockVirtualMachinePreferenceInterface {
+	mock := &MockVirtualMachinePreferenceInterface{ctrl: ctrl}
+	mock.recorder = &_MockVirtualMachinePreferenceInterfaceRecorder{mock}
+	return mock
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) EXPECT() *_MockVirtualMachinePreferenceInterfaceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) Get(name string, options *v11.GetOptions) (*v114.VirtualMachinePreference, error) {
+	ret := _m.ctrl.Call(_m, "Get", name, options)
+	ret0, _ := ret[0].(*v114.VirtualMachinePreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1)
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) List(opts *v11.ListOptions) (*v114.VirtualMachinePreferenceList, error) {
+	ret := _m.ctrl.Call(_m, "List", opts)
+	ret0, _ := ret[0].(*v114.VirtualMachinePreferenceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) List(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "List", arg0)
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) Create(instance *v114.VirtualMachinePreference) (*v114.VirtualMachinePreference, error) {
+	ret := _m.ctrl.Call(_m, "Create", instance)
+	ret0, _ := ret[0].(*v114.VirtualMachinePreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) Create(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Create", arg0)
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) Update(_param0 *v114.VirtualMachinePreference) (*v114.VirtualMachinePreference, error) {
+	ret := _m.ctrl.Call(_m, "Update", _param0)
+	ret0, _ := ret[0].(*v114.VirtualMachinePreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) Update(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Update", arg0)
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) Delete(name string, options *v11.DeleteOptions) error {
+	ret := _m.ctrl.Call(_m, "Delete", name, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1)
+}
+
+func (_m *MockVirtualMachinePreferenceInterface) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*v114.VirtualMachinePreference, error) {
+	_s := []interface{}{name, pt, data}
+	for _, _x := range subresources {
+		_s = append(_s, _x)
+	}
+	ret := _m.ctrl.Call(_m, "Patch", _s...)
+	ret0, _ := ret[0].(*v114.VirtualMachinePreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockVirtualMachinePreferenceInterfaceRecorder) Patch(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	_s := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Patch", _s...)
+}
@@ -27,6 +27,7 @@ package kubecli
 
 import (
 	"io"
+	"time"
 
 	secv1 "github.com/openshift/client-go/security/clientset/versioned/typed/security/v1"
 	autov1 "k8s.io/api/autoscaling/v1"
@@ -52,6 +53,11 @@ type KubevirtClient interface {
 	ReplicaSet(namespace string) ReplicaSetInterface
 	VirtualMachine(namespace string) VirtualMachineInterface
 	KubeVirt(namespace string) KubeVirtInterface
+	ConformanceRun(namespace string) ConformanceRunInterface
+	VirtualMachineExport(namespace string) VirtualMachineExportInterface
+	VirtualMachineBackup(namespace string) VirtualMachineBackupInterface
+	VirtualMachineInstancetype(namespace string) VirtualMachineInstancetypeInterface
+	VirtualMachinePreference(namespace string) VirtualMachinePreferenceInterface
 	VirtualMachineInstancePreset(namespace string) VirtualMachineInstancePresetInterface
 	VirtualMachineSnapshot(namespace string) vmsnapshotv1alpha1.VirtualMachineSnapshotInterface
 	VirtualMachineSnapshotContent(namespace string) vmsnapshotv1alpha1.VirtualMachineSnapshotContentInterface
@@ -151,11 +157,19 @@ type VirtualMachineInstanceInterface interface {
 	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineInstance, err error)
 	SerialConsole(name string, options *SerialConsoleOptions) (StreamInterface, error)
 	VNC(name string) (StreamInterface, error)
+	VNCToken(name string, duration time.Duration) (v1.VNCToken, error)
+	PortForward(name string, port int) (StreamInterface, error)
+	USBRedir(name string) (StreamInterface, error)
+	Backup(name string) (StreamInterface, error)
 	Pause(name string) error
 	Unpause(name string) error
+	Freeze(name string) error
+	Thaw(name string) error
+	MemoryDump(name string, options *v1.MemoryDumpOptions) error
 	GuestOsInfo(name string) (v1.VirtualMachineInstanceGuestAgentInfo, error)
 	UserList(name string) (v1.VirtualMachineInstanceGuestOSUserList, error)
 	FilesystemList(name string) (v1.VirtualMachineInstanceFileSystemList, error)
+	Stats(name string) (v1.VirtualMachineInstanceStats, error)
 }
 
 type ReplicaSetInterface interface {
@@ -195,8 +209,10 @@ type VirtualMachineInterface interface {
 	ForceRestart(name string, graceperiod int) error
 	Start(name string) error
 	Stop(name string) error
+	ForceStop(name string, graceperiod int) error
 	Migrate(name string) error
 	Rename(name string, options *v1.RenameOptions) error
+	Rollback(name string, options *v1.RollbackOptions) error
 }
 
 type VirtualMachineInstanceMigrationInterface interface {
@@ -220,3 +236,54 @@ type KubeVirtInterface interface {
 	UpdateStatus(*v1.KubeVirt) (*v1.KubeVirt, error)
 	PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.KubeVirt, err error)
 }
+
+type ConformanceRunInterface interface {
+	Get(name string, options *k8smetav1.GetOptions) (*v1.ConformanceRun, error)
+	List(opts *k8smetav1.ListOptions) (*v1.ConformanceRunList, error)
+	Create(instance *v1.ConformanceRun) (*v1.ConformanceRun, error)
+	Update(*v1.ConformanceRun) (*v1.ConformanceRun, error)
+	Delete(name string, options *k8smetav1.DeleteOptions) error
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ConformanceRun, err error)
+	UpdateStatus(*v1.ConformanceRun) (*v1.ConformanceRun, error)
+	PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.ConformanceRun, err error)
+}
+
+type VirtualMachineExportInterface interface {
+	Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachineExport, error)
+	List(opts *k8smetav1.ListOptions) (*v1.VirtualMachineExportList, error)
+	Create(instance *v1.VirtualMachineExport) (*v1.VirtualMachineExport, error)
+	Update(*v1.VirtualMachineExport) (*v1.VirtualMachineExport, error)
+	Delete(name string, options *k8smetav1.DeleteOptions) error
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineExport, err error)
+	UpdateStatus(*v1.VirtualMachineExport) (*v1.VirtualMachineExport, error)
+	PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.VirtualMachineExport, err error)
+}
+
+type VirtualMachineBackupInterface interface {
+	Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachineBackup, error)
+	List(opts *k8smetav1.ListOptions) (*v1.VirtualMachineBackupList, error)
+	Create(instance *v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error)
+	Update(*v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error)
+	Delete(name string, options *k8smetav1.DeleteOptions) error
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineBackup, err error)
+	UpdateStatus(*v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error)
+	PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.VirtualMachineBackup, err error)
+}
+
+type VirtualMachineInstancetypeInterface interface {
+	Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachineInstancetype, error)
+	List(opts *k8smetav1.ListOptions) (*v1.VirtualMachineInstancetypeList, error)
+	Create(instancetype *v1.VirtualMachineInstancetype) (*v1.VirtualMachineInstancetype, error)
+	Update(*v1.VirtualMachineInstancetype) (*v1.VirtualMachineInstancetype, error)
+	Delete(name string, options *k8smetav1.DeleteOptions) error
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineInstancetype, err error)
+}
+
+type VirtualMachinePreferenceInterface interface {
+	Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachinePreference, error)
+	List(opts *k8smetav1.ListOptions) (*v1.VirtualMachinePreferenceList, error)
+	Create(preference *v1.VirtualMachinePreference) (*v1.VirtualMachinePreference, error)
+	Update(*v1.VirtualMachinePreference) (*v1.VirtualMachinePreference, error)
+	Delete(name string, options *k8smetav1.DeleteOptions) error
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachinePreference, err error)
+}
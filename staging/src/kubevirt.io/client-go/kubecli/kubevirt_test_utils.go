@@ -3,6 +3,7 @@ package kubecli
 import (
 	"errors"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/clientcmd"
 
@@ -71,3 +72,48 @@ func NewVirtualMachineInstancePresetList(rss ...v1.VirtualMachineInstancePreset)
 func NewMinimalVirtualMachineInstancePreset(name string) *v1.VirtualMachineInstancePreset {
 	return &v1.VirtualMachineInstancePreset{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineInstancePreset"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name}}
 }
+
+func NewMinimalConformanceRun(name string) *v1.ConformanceRun {
+	return &v1.ConformanceRun{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "ConformanceRun"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name}}
+}
+
+func NewConformanceRunList(runs ...v1.ConformanceRun) *v1.ConformanceRunList {
+	return &v1.ConformanceRunList{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "ConformanceRunList"}, Items: runs}
+}
+
+func NewMinimalVirtualMachineExport(name string) *v1.VirtualMachineExport {
+	return &v1.VirtualMachineExport{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineExport"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name}}
+}
+
+func NewVirtualMachineExportList(exports ...v1.VirtualMachineExport) *v1.VirtualMachineExportList {
+	return &v1.VirtualMachineExportList{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineExportList"}, Items: exports}
+}
+
+func NewMinimalVirtualMachineBackup(name string) *v1.VirtualMachineBackup {
+	return &v1.VirtualMachineBackup{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineBackup"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name}}
+}
+
+func NewVirtualMachineBackupList(backups ...v1.VirtualMachineBackup) *v1.VirtualMachineBackupList {
+	return &v1.VirtualMachineBackupList{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineBackupList"}, Items: backups}
+}
+
+func NewMinimalVirtualMachineInstancetype(name string) *v1.VirtualMachineInstancetype {
+	return &v1.VirtualMachineInstancetype{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineInstancetype"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name},
+		Spec: v1.VirtualMachineInstancetypeSpec{
+			CPU:    v1.CPUInstancetype{Guest: 1},
+			Memory: v1.MemoryInstancetype{Guest: resource.MustParse("128Mi")},
+		},
+	}
+}
+
+func NewVirtualMachineInstancetypeList(instancetypes ...v1.VirtualMachineInstancetype) *v1.VirtualMachineInstancetypeList {
+	return &v1.VirtualMachineInstancetypeList{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachineInstancetypeList"}, Items: instancetypes}
+}
+
+func NewMinimalVirtualMachinePreference(name string) *v1.VirtualMachinePreference {
+	return &v1.VirtualMachinePreference{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachinePreference"}, ObjectMeta: k8smetav1.ObjectMeta{Name: name}}
+}
+
+func NewVirtualMachinePreferenceList(preferences ...v1.VirtualMachinePreference) *v1.VirtualMachinePreferenceList {
+	return &v1.VirtualMachinePreferenceList{TypeMeta: k8smetav1.TypeMeta{APIVersion: v1.GroupVersion.String(), Kind: "VirtualMachinePreferenceList"}, Items: preferences}
+}
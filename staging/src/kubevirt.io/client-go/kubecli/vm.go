@@ -189,6 +189,16 @@ func (v *vm) Stop(name string) error {
 	return v.restClient.Put().RequestURI(uri).Do().Error()
 }
 
+func (v *vm) ForceStop(name string, graceperiod int) error {
+	data := map[string]int{"gracePeriodSeconds": graceperiod}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("Cannot Marshal to json: %s", err)
+	}
+	uri := fmt.Sprintf(vmSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "stop")
+	return v.restClient.Put().RequestURI(uri).Body(body).Do().Error()
+}
+
 func (v *vm) Migrate(name string) error {
 	uri := fmt.Sprintf(vmSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "migrate")
 	return v.restClient.Put().RequestURI(uri).Do().Error()
@@ -205,3 +215,15 @@ func (v *vm) Rename(name string, options *v1.RenameOptions) error {
 
 	return v.restClient.Put().RequestURI(uri).Body([]byte(optsJson)).Do().Error()
 }
+
+func (v *vm) Rollback(name string, options *v1.RollbackOptions) error {
+	uri := fmt.Sprintf(vmSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "rollback")
+
+	optsJson, err := json.Marshal(options)
+
+	if err != nil {
+		return err
+	}
+
+	return v.restClient.Put().RequestURI(uri).Body([]byte(optsJson)).Do().Error()
+}
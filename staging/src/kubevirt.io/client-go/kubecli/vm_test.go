@@ -176,6 +176,18 @@ var _ = Describe("Kubevirt VirtualMachine Client", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should force stop a VirtualMachine", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", subVMIPath+"/stop"),
+			ghttp.VerifyBody([]byte(`{"gracePeriodSeconds":0}`)),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachine(k8sv1.NamespaceDefault).ForceStop("testvm", 0)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("should migrate a VirtualMachine", func() {
 		server.AppendHandlers(ghttp.CombineHandlers(
 			ghttp.VerifyRequest("PUT", subVMIPath+"/migrate"),
@@ -201,6 +213,20 @@ var _ = Describe("Kubevirt VirtualMachine Client", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should roll back a VM", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", subVMIPath+"/rollback"),
+				ghttp.RespondWith(http.StatusAccepted, nil),
+			),
+		)
+
+		err := client.VirtualMachine(k8sv1.NamespaceDefault).Rollback("testvm", &virtv1.RollbackOptions{RevisionName: "testvm-template-1"})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	AfterEach(func() {
 		server.Close()
 	})
@@ -0,0 +1,160 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+func (k *kubevirt) VirtualMachineBackup(namespace string) VirtualMachineBackupInterface {
+	return &virtualMachineBackup{
+		restClient: k.restClient,
+		namespace:  namespace,
+		resource:   "virtualmachinebackups",
+	}
+}
+
+type virtualMachineBackup struct {
+	restClient *rest.RESTClient
+	namespace  string
+	resource   string
+}
+
+// Create new VirtualMachineBackup in the cluster to specified namespace
+func (o *virtualMachineBackup) Create(backup *v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error) {
+	newBackup := &v1.VirtualMachineBackup{}
+	err := o.restClient.Post().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Body(backup).
+		Do().
+		Into(newBackup)
+
+	newBackup.SetGroupVersionKind(v1.VirtualMachineBackupGroupVersionKind)
+
+	return newBackup, err
+}
+
+// Get the VirtualMachineBackup from the cluster by its name and namespace
+func (o *virtualMachineBackup) Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachineBackup, error) {
+	newBackup := &v1.VirtualMachineBackup{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newBackup)
+
+	newBackup.SetGroupVersionKind(v1.VirtualMachineBackupGroupVersionKind)
+
+	return newBackup, err
+}
+
+// Update the VirtualMachineBackup in the cluster in given namespace
+func (o *virtualMachineBackup) Update(backup *v1.VirtualMachineBackup) (*v1.VirtualMachineBackup, error) {
+	updatedBackup := &v1.VirtualMachineBackup{}
+	err := o.restClient.Put().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(backup.Name).
+		Body(backup).
+		Do().
+		Into(updatedBackup)
+
+	updatedBackup.SetGroupVersionKind(v1.VirtualMachineBackupGroupVersionKind)
+
+	return updatedBackup, err
+}
+
+// Delete the defined VirtualMachineBackup in the cluster in defined namespace
+func (o *virtualMachineBackup) Delete(name string, options *k8smetav1.DeleteOptions) error {
+	err := o.restClient.Delete().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+
+	return err
+}
+
+// List all VirtualMachineBackups in given namespace
+func (o *virtualMachineBackup) List(options *k8smetav1.ListOptions) (*v1.VirtualMachineBackupList, error) {
+	newBackupList := &v1.VirtualMachineBackupList{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newBackupList)
+
+	for _, backup := range newBackupList.Items {
+		backup.SetGroupVersionKind(v1.VirtualMachineBackupGroupVersionKind)
+	}
+
+	return newBackupList, err
+}
+
+func (v *virtualMachineBackup) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineBackup, err error) {
+	result = &v1.VirtualMachineBackup{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return result, err
+}
+
+func (v *virtualMachineBackup) PatchStatus(name string, pt types.PatchType, data []byte) (result *v1.VirtualMachineBackup, err error) {
+	result = &v1.VirtualMachineBackup{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource("status").
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
+
+func (v *virtualMachineBackup) UpdateStatus(backup *v1.VirtualMachineBackup) (result *v1.VirtualMachineBackup, err error) {
+	result = &v1.VirtualMachineBackup{}
+	err = v.restClient.Put().
+		Name(backup.ObjectMeta.Name).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource("status").
+		Body(backup).
+		Do().
+		Into(result)
+	result.SetGroupVersionKind(v1.VirtualMachineBackupGroupVersionKind)
+	return
+}
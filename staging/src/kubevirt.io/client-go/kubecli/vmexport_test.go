@@ -0,0 +1,145 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Kubevirt VirtualMachineExport Client", func() {
+
+	var server *ghttp.Server
+	var client KubevirtClient
+	basePath := "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineexports"
+	exportPath := basePath + "/testexport"
+
+	BeforeEach(func() {
+		var err error
+		server = ghttp.NewServer()
+		client, err = GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fetch a VirtualMachineExport", func() {
+		export := NewMinimalVirtualMachineExport("testexport")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", exportPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, export),
+		))
+		fetchedExport, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Get("testexport", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedExport).To(Equal(export))
+	})
+
+	It("should detect non existent VirtualMachineExports", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", exportPath),
+			ghttp.RespondWithJSONEncoded(http.StatusNotFound, errors.NewNotFound(schema.GroupResource{}, "testexport")),
+		))
+		_, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Get("testexport", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fetch a VirtualMachineExport list", func() {
+		export := NewMinimalVirtualMachineExport("testexport")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, NewVirtualMachineExportList(*export)),
+		))
+		fetchedExportList, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).List(&k8smetav1.ListOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedExportList.Items).To(HaveLen(1))
+		Expect(fetchedExportList.Items[0]).To(Equal(*export))
+	})
+
+	It("should create a VirtualMachineExport", func() {
+		export := NewMinimalVirtualMachineExport("testexport")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusCreated, export),
+		))
+		createdExport, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Create(export)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(createdExport).To(Equal(export))
+	})
+
+	It("should update a VirtualMachineExport", func() {
+		export := NewMinimalVirtualMachineExport("testexport")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", exportPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, export),
+		))
+		updatedExport, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Update(export)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedExport).To(Equal(export))
+	})
+
+	It("should patch a VirtualMachineExport", func() {
+		export := NewMinimalVirtualMachineExport("testexport")
+		export.Spec.Source = "othervm"
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PATCH", exportPath),
+			ghttp.VerifyBody([]byte("{\"spec\":{\"source\":\"othervm\"}}")),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, export),
+		))
+
+		_, err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Patch(export.Name, types.MergePatchType,
+			[]byte("{\"spec\":{\"source\":\"othervm\"}}"))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete a VirtualMachineExport", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("DELETE", exportPath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachineExport(k8sv1.NamespaceDefault).Delete("testexport", &k8smetav1.DeleteOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+})
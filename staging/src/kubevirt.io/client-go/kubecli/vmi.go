@@ -179,6 +179,37 @@ func (v *vmis) VNC(name string) (StreamInterface, error) {
 	return v.asyncSubresourceHelper(name, "vnc")
 }
 
+// VNCToken issues a token granting access to the VNC of the VMI, a duration of
+// zero uses the default lifetime of the token.
+func (v *vmis) VNCToken(name string, duration time.Duration) (v1.VNCToken, error) {
+	token := v1.VNCToken{}
+	uri := fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "vnc-token")
+	req := v.restClient.Get().RequestURI(uri)
+	if duration != 0 {
+		req = req.Param("duration", duration.String())
+	}
+	err := req.Do().Into(&token)
+	return token, err
+}
+
+// PortForward opens a stream to the given TCP port of the guest, each stream
+// carries exactly one TCP connection.
+func (v *vmis) PortForward(name string, port int) (StreamInterface, error) {
+	return v.asyncSubresourceHelper(name, fmt.Sprintf("portforward/%d", port))
+}
+
+// USBRedir opens a stream to a free usbredir socket of the guest, each stream
+// redirects exactly one USB device.
+func (v *vmis) USBRedir(name string) (StreamInterface, error) {
+	return v.asyncSubresourceHelper(name, "usbredir")
+}
+
+// Backup opens a stream to the NBD server exporting the disks of a ready
+// VirtualMachineBackup of the guest.
+func (v *vmis) Backup(name string) (StreamInterface, error) {
+	return v.asyncSubresourceHelper(name, "backup")
+}
+
 type connectionStruct struct {
 	con StreamInterface
 	err error
@@ -316,6 +347,27 @@ func (v *vmis) Unpause(name string) error {
 	return v.restClient.Put().RequestURI(uri).Do().Error()
 }
 
+func (v *vmis) Freeze(name string) error {
+	uri := fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "freeze")
+	return v.restClient.Put().RequestURI(uri).Do().Error()
+}
+
+func (v *vmis) Thaw(name string) error {
+	uri := fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "thaw")
+	return v.restClient.Put().RequestURI(uri).Do().Error()
+}
+
+func (v *vmis) MemoryDump(name string, options *v1.MemoryDumpOptions) error {
+	uri := fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "memorydump")
+
+	optsJson, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	return v.restClient.Put().RequestURI(uri).Body([]byte(optsJson)).Do().Error()
+}
+
 func (v *vmis) Get(name string, options *k8smetav1.GetOptions) (vmi *v1.VirtualMachineInstance, err error) {
 	vmi = &v1.VirtualMachineInstance{}
 	err = v.restClient.Get().
@@ -471,3 +523,10 @@ func (v *vmis) FilesystemList(name string) (v1.VirtualMachineInstanceFileSystemL
 	err := v.restClient.Get().RequestURI(uri).Do().Into(&fsList)
 	return fsList, err
 }
+
+func (v *vmis) Stats(name string) (v1.VirtualMachineInstanceStats, error) {
+	vmiStats := v1.VirtualMachineInstanceStats{}
+	uri := fmt.Sprintf(vmiSubresourceURL, v1.ApiStorageVersion, v.namespace, name, "stats")
+	err := v.restClient.Get().RequestURI(uri).Do().Into(&vmiStats)
+	return vmiStats, err
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	. "github.com/onsi/ginkgo"
@@ -144,6 +145,54 @@ var _ = Describe("Kubevirt VirtualMachineInstance Client", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should allow to connect a port-forward stream to a VM", func() {
+		portForwardPath := "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvm/portforward/22"
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", portForwardPath),
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+			},
+		))
+		_, err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).PortForward("testvm", 22)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should allow to connect a usbredir stream to a VM", func() {
+		usbredirPath := "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvm/usbredir"
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", usbredirPath),
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+			},
+		))
+		_, err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).USBRedir("testvm")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should allow to connect a backup stream to a VM", func() {
+		backupPath := "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvm/backup"
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", backupPath),
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+			},
+		))
+		_, err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).Backup("testvm")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("should handle a failure connecting to the VM", func() {
 		vncPath := "/apis/subresources.kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstances/testvm/vnc"
 
@@ -241,6 +290,53 @@ var _ = Describe("Kubevirt VirtualMachineInstance Client", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should freeze a VirtualMachineInstance", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", subVMPath+"/freeze"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).Freeze("testvm")
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should thaw a VirtualMachineInstance", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", subVMPath+"/thaw"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).Thaw("testvm")
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should dump the memory of a VirtualMachineInstance", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", subVMPath+"/memorydump"),
+			ghttp.VerifyBody([]byte(`{"claimName":"dump"}`)),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).MemoryDump("testvm", &v1.MemoryDumpOptions{ClaimName: "dump"})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fetch a VNC token via subresource", func() {
+		token := v1.VNCToken{Token: "abc.def"}
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", subVMPath+"/vnc-token", "duration=5m0s"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, token),
+		))
+		fetchedToken, err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).VNCToken("testvm", 5*time.Minute)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedToken.Token).To(Equal(token.Token))
+	})
+
 	It("should fetch GuestOSInfo from VirtualMachineInstance via subresource", func() {
 		osInfo := v1.VirtualMachineInstanceGuestAgentInfo{
 			GAVersion: "4.1.1",
@@ -292,6 +388,27 @@ var _ = Describe("Kubevirt VirtualMachineInstance Client", func() {
 		Expect(fetchedInfo).To(Equal(fileSystemList), "fetched info should be the same as passed in")
 	})
 
+	It("should fetch Stats from VirtualMachineInstance via subresource", func() {
+		vmiStats := v1.VirtualMachineInstanceStats{
+			Timestamp:           k8smetav1.NewMicroTime(time.Unix(1600000000, 5000)),
+			CPUTimeNanoseconds:  1000000,
+			VCPUs:               2,
+			MemoryResidentBytes: 1024,
+		}
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", subVMPath+"/stats"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, vmiStats),
+		))
+		fetchedStats, err := client.VirtualMachineInstance(k8sv1.NamespaceDefault).Stats("testvm")
+
+		Expect(err).ToNot(HaveOccurred(), "should fetch stats normally")
+		Expect(fetchedStats.Timestamp.Equal(&vmiStats.Timestamp)).To(BeTrue())
+		Expect(fetchedStats.CPUTimeNanoseconds).To(Equal(vmiStats.CPUTimeNanoseconds))
+		Expect(fetchedStats.VCPUs).To(Equal(vmiStats.VCPUs))
+		Expect(fetchedStats.MemoryResidentBytes).To(Equal(vmiStats.MemoryResidentBytes))
+	})
+
 	AfterEach(func() {
 		server.Close()
 	})
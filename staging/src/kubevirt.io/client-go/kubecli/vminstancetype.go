@@ -0,0 +1,133 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+func (k *kubevirt) VirtualMachineInstancetype(namespace string) VirtualMachineInstancetypeInterface {
+	return &virtualMachineInstancetype{
+		restClient: k.restClient,
+		namespace:  namespace,
+		resource:   "virtualmachineinstancetypes",
+	}
+}
+
+type virtualMachineInstancetype struct {
+	restClient *rest.RESTClient
+	namespace  string
+	resource   string
+}
+
+// Create new VirtualMachineInstancetype in the cluster to specified namespace
+func (o *virtualMachineInstancetype) Create(instancetype *v1.VirtualMachineInstancetype) (*v1.VirtualMachineInstancetype, error) {
+	newInstancetype := &v1.VirtualMachineInstancetype{}
+	err := o.restClient.Post().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Body(instancetype).
+		Do().
+		Into(newInstancetype)
+
+	newInstancetype.SetGroupVersionKind(v1.VirtualMachineInstancetypeGroupVersionKind)
+
+	return newInstancetype, err
+}
+
+// Get the VirtualMachineInstancetype from the cluster by its name and namespace
+func (o *virtualMachineInstancetype) Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachineInstancetype, error) {
+	newInstancetype := &v1.VirtualMachineInstancetype{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newInstancetype)
+
+	newInstancetype.SetGroupVersionKind(v1.VirtualMachineInstancetypeGroupVersionKind)
+
+	return newInstancetype, err
+}
+
+// Update the VirtualMachineInstancetype in the cluster in given namespace
+func (o *virtualMachineInstancetype) Update(instancetype *v1.VirtualMachineInstancetype) (*v1.VirtualMachineInstancetype, error) {
+	updatedInstancetype := &v1.VirtualMachineInstancetype{}
+	err := o.restClient.Put().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(instancetype.Name).
+		Body(instancetype).
+		Do().
+		Into(updatedInstancetype)
+
+	updatedInstancetype.SetGroupVersionKind(v1.VirtualMachineInstancetypeGroupVersionKind)
+
+	return updatedInstancetype, err
+}
+
+// Delete the defined VirtualMachineInstancetype in the cluster in defined namespace
+func (o *virtualMachineInstancetype) Delete(name string, options *k8smetav1.DeleteOptions) error {
+	err := o.restClient.Delete().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+
+	return err
+}
+
+// List all VirtualMachineInstancetypes in given namespace
+func (o *virtualMachineInstancetype) List(options *k8smetav1.ListOptions) (*v1.VirtualMachineInstancetypeList, error) {
+	newInstancetypeList := &v1.VirtualMachineInstancetypeList{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newInstancetypeList)
+
+	for _, instancetype := range newInstancetypeList.Items {
+		instancetype.SetGroupVersionKind(v1.VirtualMachineInstancetypeGroupVersionKind)
+	}
+
+	return newInstancetypeList, err
+}
+
+func (v *virtualMachineInstancetype) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachineInstancetype, err error) {
+	result = &v1.VirtualMachineInstancetype{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return result, err
+}
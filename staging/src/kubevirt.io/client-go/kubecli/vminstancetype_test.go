@@ -0,0 +1,145 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Kubevirt VirtualMachineInstancetype Client", func() {
+
+	var server *ghttp.Server
+	var client KubevirtClient
+	basePath := "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachineinstancetypes"
+	instancetypePath := basePath + "/testinstancetype"
+
+	BeforeEach(func() {
+		var err error
+		server = ghttp.NewServer()
+		client, err = GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fetch a VirtualMachineInstancetype", func() {
+		instancetype := NewMinimalVirtualMachineInstancetype("testinstancetype")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", instancetypePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, instancetype),
+		))
+		fetchedInstancetype, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Get("testinstancetype", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedInstancetype).To(Equal(instancetype))
+	})
+
+	It("should detect non existent VirtualMachineInstancetypes", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", instancetypePath),
+			ghttp.RespondWithJSONEncoded(http.StatusNotFound, errors.NewNotFound(schema.GroupResource{}, "testinstancetype")),
+		))
+		_, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Get("testinstancetype", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fetch a VirtualMachineInstancetype list", func() {
+		instancetype := NewMinimalVirtualMachineInstancetype("testinstancetype")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, NewVirtualMachineInstancetypeList(*instancetype)),
+		))
+		fetchedInstancetypeList, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).List(&k8smetav1.ListOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedInstancetypeList.Items).To(HaveLen(1))
+		Expect(fetchedInstancetypeList.Items[0]).To(Equal(*instancetype))
+	})
+
+	It("should create a VirtualMachineInstancetype", func() {
+		instancetype := NewMinimalVirtualMachineInstancetype("testinstancetype")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusCreated, instancetype),
+		))
+		createdInstancetype, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Create(instancetype)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(createdInstancetype).To(Equal(instancetype))
+	})
+
+	It("should update a VirtualMachineInstancetype", func() {
+		instancetype := NewMinimalVirtualMachineInstancetype("testinstancetype")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", instancetypePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, instancetype),
+		))
+		updatedInstancetype, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Update(instancetype)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedInstancetype).To(Equal(instancetype))
+	})
+
+	It("should patch a VirtualMachineInstancetype", func() {
+		instancetype := NewMinimalVirtualMachineInstancetype("testinstancetype")
+		instancetype.Spec.CPU.Guest = 2
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PATCH", instancetypePath),
+			ghttp.VerifyBody([]byte("{\"spec\":{\"cpu\":{\"guest\":2}}}")),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, instancetype),
+		))
+
+		_, err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Patch(instancetype.Name, types.MergePatchType,
+			[]byte("{\"spec\":{\"cpu\":{\"guest\":2}}}"))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete a VirtualMachineInstancetype", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("DELETE", instancetypePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachineInstancetype(k8sv1.NamespaceDefault).Delete("testinstancetype", &k8smetav1.DeleteOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+})
@@ -0,0 +1,133 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+func (k *kubevirt) VirtualMachinePreference(namespace string) VirtualMachinePreferenceInterface {
+	return &virtualMachinePreference{
+		restClient: k.restClient,
+		namespace:  namespace,
+		resource:   "virtualmachinepreferences",
+	}
+}
+
+type virtualMachinePreference struct {
+	restClient *rest.RESTClient
+	namespace  string
+	resource   string
+}
+
+// Create new VirtualMachinePreference in the cluster to specified namespace
+func (o *virtualMachinePreference) Create(preference *v1.VirtualMachinePreference) (*v1.VirtualMachinePreference, error) {
+	newPreference := &v1.VirtualMachinePreference{}
+	err := o.restClient.Post().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Body(preference).
+		Do().
+		Into(newPreference)
+
+	newPreference.SetGroupVersionKind(v1.VirtualMachinePreferenceGroupVersionKind)
+
+	return newPreference, err
+}
+
+// Get the VirtualMachinePreference from the cluster by its name and namespace
+func (o *virtualMachinePreference) Get(name string, options *k8smetav1.GetOptions) (*v1.VirtualMachinePreference, error) {
+	newPreference := &v1.VirtualMachinePreference{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newPreference)
+
+	newPreference.SetGroupVersionKind(v1.VirtualMachinePreferenceGroupVersionKind)
+
+	return newPreference, err
+}
+
+// Update the VirtualMachinePreference in the cluster in given namespace
+func (o *virtualMachinePreference) Update(preference *v1.VirtualMachinePreference) (*v1.VirtualMachinePreference, error) {
+	updatedPreference := &v1.VirtualMachinePreference{}
+	err := o.restClient.Put().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(preference.Name).
+		Body(preference).
+		Do().
+		Into(updatedPreference)
+
+	updatedPreference.SetGroupVersionKind(v1.VirtualMachinePreferenceGroupVersionKind)
+
+	return updatedPreference, err
+}
+
+// Delete the defined VirtualMachinePreference in the cluster in defined namespace
+func (o *virtualMachinePreference) Delete(name string, options *k8smetav1.DeleteOptions) error {
+	err := o.restClient.Delete().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+
+	return err
+}
+
+// List all VirtualMachinePreferences in given namespace
+func (o *virtualMachinePreference) List(options *k8smetav1.ListOptions) (*v1.VirtualMachinePreferenceList, error) {
+	newPreferenceList := &v1.VirtualMachinePreferenceList{}
+	err := o.restClient.Get().
+		Resource(o.resource).
+		Namespace(o.namespace).
+		VersionedParams(options, scheme.ParameterCodec).
+		Do().
+		Into(newPreferenceList)
+
+	for _, preference := range newPreferenceList.Items {
+		preference.SetGroupVersionKind(v1.VirtualMachinePreferenceGroupVersionKind)
+	}
+
+	return newPreferenceList, err
+}
+
+func (v *virtualMachinePreference) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.VirtualMachinePreference, err error) {
+	result = &v1.VirtualMachinePreference{}
+	err = v.restClient.Patch(pt).
+		Namespace(v.namespace).
+		Resource(v.resource).
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return result, err
+}
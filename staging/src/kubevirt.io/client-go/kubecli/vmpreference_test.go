@@ -0,0 +1,147 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package kubecli
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "kubevirt.io/client-go/api/v1"
+)
+
+var _ = Describe("Kubevirt VirtualMachinePreference Client", func() {
+
+	var server *ghttp.Server
+	var client KubevirtClient
+	basePath := "/apis/kubevirt.io/v1alpha3/namespaces/default/virtualmachinepreferences"
+	preferencePath := basePath + "/testpreference"
+
+	BeforeEach(func() {
+		var err error
+		server = ghttp.NewServer()
+		client, err = GetKubevirtClientFromFlags(server.URL(), "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fetch a VirtualMachinePreference", func() {
+		preference := NewMinimalVirtualMachinePreference("testpreference")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", preferencePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, preference),
+		))
+		fetchedPreference, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Get("testpreference", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedPreference).To(Equal(preference))
+	})
+
+	It("should detect non existent VirtualMachinePreferences", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", preferencePath),
+			ghttp.RespondWithJSONEncoded(http.StatusNotFound, errors.NewNotFound(schema.GroupResource{}, "testpreference")),
+		))
+		_, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Get("testpreference", &k8smetav1.GetOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fetch a VirtualMachinePreference list", func() {
+		preference := NewMinimalVirtualMachinePreference("testpreference")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, NewVirtualMachinePreferenceList(*preference)),
+		))
+		fetchedPreferenceList, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).List(&k8smetav1.ListOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchedPreferenceList.Items).To(HaveLen(1))
+		Expect(fetchedPreferenceList.Items[0]).To(Equal(*preference))
+	})
+
+	It("should create a VirtualMachinePreference", func() {
+		preference := NewMinimalVirtualMachinePreference("testpreference")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", basePath),
+			ghttp.RespondWithJSONEncoded(http.StatusCreated, preference),
+		))
+		createdPreference, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Create(preference)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(createdPreference).To(Equal(preference))
+	})
+
+	It("should update a VirtualMachinePreference", func() {
+		preference := NewMinimalVirtualMachinePreference("testpreference")
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PUT", preferencePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, preference),
+		))
+		updatedPreference, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Update(preference)
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updatedPreference).To(Equal(preference))
+	})
+
+	It("should patch a VirtualMachinePreference", func() {
+		preference := NewMinimalVirtualMachinePreference("testpreference")
+		preference.Spec.Machine = &v1.MachinePreferences{PreferredMachineType: "q35"}
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("PATCH", preferencePath),
+			ghttp.VerifyBody([]byte("{\"spec\":{\"machine\":{\"preferredMachineType\":\"q35\"}}}")),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, preference),
+		))
+
+		_, err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Patch(preference.Name, types.MergePatchType,
+			[]byte("{\"spec\":{\"machine\":{\"preferredMachineType\":\"q35\"}}}"))
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete a VirtualMachinePreference", func() {
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("DELETE", preferencePath),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, nil),
+		))
+		err := client.VirtualMachinePreference(k8sv1.NamespaceDefault).Delete("testpreference", &k8smetav1.DeleteOptions{})
+
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+})
@@ -740,7 +740,7 @@ var _ = Describe("Infrastructure", func() {
 		})
 
 		It("[test_id:4554]should include the vcpu seconds metrics for running VM", func() {
-			metrics := collectMetrics("kubevirt_vmi_vcpu_seconds")
+			metrics := collectMetrics("kubevirt_vmi_vcpu_seconds_total")
 			for _, v := range metrics {
 				fmt.Fprintf(GinkgoWriter, "vcpu seconds was %f", v)
 				Expect(v).To(BeNumerically(">=", float64(0.0)))
@@ -805,7 +805,7 @@ var _ = Describe("Infrastructure", func() {
 			// Every VMI is labeled with kubevirt.io/nodeName, so just creating a VMI should
 			// be enough to its metrics to contain a kubernetes label
 			containK8sLabel := false
-			metrics := collectMetrics("kubevirt_vmi_vcpu_seconds")
+			metrics := collectMetrics("kubevirt_vmi_vcpu_seconds_total")
 			By("Checking collected metrics")
 			keys := getKeysFromMetrics(metrics)
 			for _, key := range keys {
@@ -765,9 +765,14 @@ func AdjustKubeVirtResource() {
 
 	// Rotate very often during the tests to ensure that things are working
 	kv.Spec.CertificateRotationStrategy = v1.KubeVirtCertificateRotateStrategy{SelfSigned: &v1.KubeVirtSelfSignConfiguration{
-		CARotateInterval:   &metav1.Duration{Duration: 10 * time.Minute},
-		CertRotateInterval: &metav1.Duration{Duration: 7 * time.Minute},
-		CAOverlapInterval:  &metav1.Duration{Duration: 4 * time.Minute},
+		CA: &v1.CertConfig{
+			Duration:    &metav1.Duration{Duration: 10 * time.Minute},
+			RenewBefore: &metav1.Duration{Duration: 4 * time.Minute},
+		},
+		Server: &v1.CertConfig{
+			Duration:    &metav1.Duration{Duration: 7 * time.Minute},
+			RenewBefore: &metav1.Duration{Duration: 2 * time.Minute},
+		},
 	}}
 
 	data, err := json.Marshal(kv.Spec)
@@ -0,0 +1,116 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2020 Red Hat, Inc.
+ *
+ */
+
+package tests_test
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/client-go/api/v1"
+	"kubevirt.io/client-go/kubecli"
+	virtconfig "kubevirt.io/kubevirt/pkg/virt-config"
+	"kubevirt.io/kubevirt/pkg/virt-launcher/virtwrap/network"
+	"kubevirt.io/kubevirt/tests"
+	cd "kubevirt.io/kubevirt/tests/containerdisk"
+)
+
+var _ = Describe("[Serial]Istio", func() {
+	const (
+		istioNamespace      = "istio-system"
+		istioInjectionLabel = "istio-injection"
+		vmiPort             = 8080
+		proxyHealthPort     = 15021
+	)
+
+	var err error
+	var virtClient kubecli.KubevirtClient
+	var vmi *v1.VirtualMachineInstance
+
+	setInjectionLabel := func(value string) {
+		namespace, err := virtClient.CoreV1().Namespaces().Get(tests.NamespaceTestDefault, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		if value == "" {
+			delete(namespace.Labels, istioInjectionLabel)
+		} else {
+			if namespace.Labels == nil {
+				namespace.Labels = map[string]string{}
+			}
+			namespace.Labels[istioInjectionLabel] = value
+		}
+		_, err = virtClient.CoreV1().Namespaces().Update(namespace)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	// the clients are not part of the mesh, their proxy would keep them running
+	runUnmeshedJob := func(job *k8sv1.Pod) {
+		job.Annotations = map[string]string{network.IstioInjectSidecarAnnotation: "false"}
+		job, err = virtClient.CoreV1().Pods(tests.NamespaceTestDefault).Create(job)
+		Expect(err).ToNot(HaveOccurred())
+		waitForJobToCompleteWithStatus(&virtClient, job, k8sv1.PodSucceeded, 420)
+	}
+
+	BeforeEach(func() {
+		virtClient, err = kubecli.GetKubevirtClient()
+		tests.PanicOnError(err)
+
+		_, err = virtClient.CoreV1().Namespaces().Get(istioNamespace, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			Skip("Istio is not installed on the cluster")
+		}
+		Expect(err).ToNot(HaveOccurred())
+
+		tests.BeforeTestCleanup()
+		tests.UpdateClusterConfigValueAndWait(virtconfig.IstioAwareMasquerade, "true")
+		setInjectionLabel("enabled")
+
+		vmi = tests.NewRandomVMIWithMasqueradeInterfaceEphemeralDiskAndUserdata(
+			cd.ContainerDiskFor(cd.ContainerDiskCirros), "#!/bin/bash\necho 'hello'\n",
+			[]v1.Port{{Name: "http", Port: vmiPort, Protocol: "TCP"}})
+		vmi.Annotations = map[string]string{network.IstioInjectSidecarAnnotation: "true"}
+		vmi, err = virtClient.VirtualMachineInstance(tests.NamespaceTestDefault).Create(vmi)
+		Expect(err).ToNot(HaveOccurred())
+		vmi = tests.WaitUntilVMIReady(vmi, tests.LoggedInCirrosExpecter)
+	})
+
+	AfterEach(func() {
+		setInjectionLabel("")
+	})
+
+	It("should pass the inbound traffic through the proxy to the VMI", func() {
+		tests.StartTCPServer(vmi, vmiPort)
+
+		By("Starting a pod which connects to the VMI through its proxy")
+		runUnmeshedJob(tests.NewHelloWorldJob(vmi.Status.Interfaces[0].IP, strconv.Itoa(vmiPort)))
+	})
+
+	It("should keep the health port of the proxy in the pod", func() {
+		By("Starting a pod which checks the readiness of the proxy")
+		check := []string{fmt.Sprintf(`set -x; curl --fail http://%s/healthz/ready`,
+			tests.FormatIPForURL(vmi.Status.Interfaces[0].IP)+":"+strconv.Itoa(proxyHealthPort))}
+		runUnmeshedJob(tests.RenderJob("curl", []string{"/bin/bash", "-c"}, check))
+	})
+})